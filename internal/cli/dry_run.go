@@ -0,0 +1,452 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dryRunInput  = "dry_run_fixtures"
+	dryRunOutput = "dry_run_capture"
+)
+
+// Outputs that are kept as they are during a dry run as they neither connect
+// to anything nor write anywhere.
+var dryRunKeptOutputs = map[string]bool{
+	"drop":          true,
+	"reject":        true,
+	"resource":      true,
+	"sync_response": true,
+}
+
+// The fields of outputs that wrap others which each hold a child output.
+// Wrappers that aren't listed hold their child within an output field, such as
+// retry and drop_on.
+var dryRunChildOutputFields = map[string][]string{
+	"circuit_breaker": {"output", "fallback"},
+	"retry_dlq":       {"output", "dlq"},
+	"shadow":          {"output", "shadow"},
+}
+
+func dryRunCli(schema *service.ConfigSchema) *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "fixtures",
+			Aliases:  []string{"f"},
+			Required: true,
+			Usage:    "A YAML or JSON file containing a list of messages to feed into the pipeline, each with a `content` string or `json_content` value and optional `metadata`.",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Value: 30 * time.Second,
+			Usage: "The maximum period of time to wait for all fixtures to be processed.",
+		},
+		envFileFlag,
+	}
+
+	return &cli.Command{
+		Name:  "dry-run",
+		Usage: "Run a config against input fixtures and report what each output would have written",
+		Flags: flags,
+		Description: `
+Replaces the input of a config with a list of fixtures and each output that
+would write somewhere with a capture, runs the pipeline until all fixtures are
+processed, and then prints a JSON report of the messages written to each output,
+identified by their path within the config, along with any fixtures that were
+rejected. Outputs that route messages, such as switch, broker, fallback and
+weighted, are kept so that routing can be validated in CI:
+
+  {{.BinaryName}} dry-run -f ./fixtures.yaml ./config.yaml
+
+Resources such as caches and rate limits are not replaced and are connected to
+as usual.`[1:],
+		Action: func(c *cli.Context) error {
+			if err := applyEnvFileFlag(c); err != nil {
+				return err
+			}
+			if c.Args().Len() != 1 {
+				return errors.New("exactly one config file must be specified with this command")
+			}
+
+			confBytes, err := os.ReadFile(c.Args().First())
+			if err != nil {
+				return fmt.Errorf("failed to read config: %w", err)
+			}
+			fixtureBytes, err := os.ReadFile(c.String("fixtures"))
+			if err != nil {
+				return fmt.Errorf("failed to read fixtures: %w", err)
+			}
+			var fixtures []dryRunFixture
+			if err := yaml.Unmarshal(fixtureBytes, &fixtures); err != nil {
+				return fmt.Errorf("failed to parse fixtures: %w", err)
+			}
+
+			ctx, done := context.WithTimeout(c.Context, c.Duration("timeout"))
+			defer done()
+
+			report, err := runDryRun(ctx, schema.Environment(), confBytes, fixtures)
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(c.App.Writer)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dryRunFixture struct {
+	Content     string         `yaml:"content" json:"content"`
+	JSONContent any            `yaml:"json_content" json:"json_content,omitempty"`
+	Metadata    map[string]any `yaml:"metadata" json:"metadata,omitempty"`
+}
+
+func (f dryRunFixture) message() (*service.Message, error) {
+	msg := service.NewMessage([]byte(f.Content))
+	if f.JSONContent != nil {
+		jBytes, err := json.Marshal(f.JSONContent)
+		if err != nil {
+			return nil, err
+		}
+		msg.SetBytes(jBytes)
+	}
+	for k, v := range f.Metadata {
+		msg.MetaSetMut(k, v)
+	}
+	return msg, nil
+}
+
+type dryRunMessage struct {
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+type dryRunCapture struct {
+	Path     string          `json:"path"`
+	Type     string          `json:"type"`
+	Label    string          `json:"label,omitempty"`
+	Messages []dryRunMessage `json:"messages"`
+}
+
+type dryRunRejection struct {
+	Fixture int    `json:"fixture"`
+	Error   string `json:"error"`
+}
+
+type dryRunReport struct {
+	Fixtures int               `json:"fixtures"`
+	Outputs  []*dryRunCapture  `json:"outputs"`
+	Rejected []dryRunRejection `json:"rejected"`
+
+	mut sync.Mutex
+}
+
+func (r *dryRunReport) capture(path string, msg *service.Message) error {
+	content, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	m := dryRunMessage{Content: string(content), Metadata: map[string]any{}}
+	_ = msg.MetaWalkMut(func(k string, v any) error {
+		m.Metadata[k] = v
+		return nil
+	})
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	for _, c := range r.Outputs {
+		if c.Path == path {
+			c.Messages = append(c.Messages, m)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output path: %v", path)
+}
+
+func (r *dryRunReport) reject(fixture int, err error) {
+	r.mut.Lock()
+	r.Rejected = append(r.Rejected, dryRunRejection{Fixture: fixture, Error: err.Error()})
+	r.mut.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// runDryRun runs a config with its input replaced by fixtures and the outputs
+// that would write somewhere replaced by captures.
+func runDryRun(ctx context.Context, env *service.Environment, confBytes []byte, fixtures []dryRunFixture) (*dryRunReport, error) {
+	var conf map[string]any
+	if err := yaml.Unmarshal(confBytes, &conf); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if conf == nil {
+		conf = map[string]any{}
+	}
+
+	report := &dryRunReport{
+		Fixtures: len(fixtures),
+		Outputs:  []*dryRunCapture{},
+		Rejected: []dryRunRejection{},
+	}
+	rewriteDryRunConfig(conf, report)
+
+	confBytes, err := yaml.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	env = env.Clone()
+	if err := env.RegisterInput(dryRunInput, service.NewConfigSpec(),
+		func(*service.ParsedConfig, *service.Resources) (service.Input, error) {
+			return &dryRunFixtureInput{fixtures: fixtures, report: report}, nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := env.RegisterOutput(dryRunOutput, service.NewConfigSpec().Field(service.NewStringField("path")),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Output, int, error) {
+			path, err := conf.FieldString("path")
+			if err != nil {
+				return nil, 0, err
+			}
+			return &dryRunCaptureOutput{path: path, report: report}, 1, nil
+		}); err != nil {
+		return nil, err
+	}
+
+	builder := env.NewStreamBuilder()
+	if err := builder.SetYAML(string(confBytes)); err != nil {
+		return nil, err
+	}
+	stream, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Run(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, errors.New("timed out waiting for fixtures to be processed")
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// rewriteDryRunConfig replaces the input of a config with fixtures and its
+// outputs with captures, registering each capture with the report. The
+// servers, metrics and tracers of the config are disabled.
+func rewriteDryRunConfig(conf map[string]any, report *dryRunReport) {
+	input := map[string]any{dryRunInput: map[string]any{}}
+	if existing, ok := conf["input"].(map[string]any); ok {
+		for _, k := range []string{"label", "processors"} {
+			if v, exists := existing[k]; exists {
+				input[k] = v
+			}
+		}
+	}
+	conf["input"] = input
+	delete(conf, "input_resources")
+	delete(conf, "buffer")
+
+	if output, ok := conf["output"].(map[string]any); ok {
+		rewriteDryRunOutput("output", output, report)
+	} else {
+		conf["output"] = map[string]any{"drop": map[string]any{}}
+	}
+	if resources, ok := conf["output_resources"].([]any); ok {
+		for i, r := range resources {
+			if output, ok := r.(map[string]any); ok {
+				rewriteDryRunOutput("output_resources."+strconv.Itoa(i), output, report)
+			}
+		}
+	}
+
+	conf["http"] = map[string]any{"enabled": false}
+	conf["logger"] = map[string]any{"level": "none"}
+	conf["metrics"] = map[string]any{"none": map[string]any{}}
+	conf["tracer"] = map[string]any{"none": map[string]any{}}
+	conf["shutdown_timeout"] = "1s"
+}
+
+// rewriteDryRunOutput replaces an output with a capture unless it only routes
+// messages to other outputs, in which case its children are rewritten.
+func rewriteDryRunOutput(path string, output map[string]any, report *dryRunReport) {
+	var typeName string
+	for k := range output {
+		if k == "label" || k == "processors" {
+			continue
+		}
+		if typeName != "" {
+			// Not a valid output, which is left for the config linter to
+			// report.
+			return
+		}
+		typeName = k
+	}
+	if typeName == "" || dryRunKeptOutputs[typeName] {
+		return
+	}
+
+	path += "." + typeName
+	switch conf := output[typeName].(type) {
+	case []any:
+		// The fallback output is a list of outputs.
+		if typeName == "fallback" {
+			rewriteDryRunOutputList(path, conf, report)
+			return
+		}
+	case map[string]any:
+		switch {
+		case typeName == "reject_errored":
+			rewriteDryRunOutput(path, conf, report)
+			return
+		case typeName == "switch":
+			if cases, ok := conf["cases"].([]any); ok {
+				for i, c := range cases {
+					if c, ok := c.(map[string]any); ok {
+						if child, ok := c["output"].(map[string]any); ok {
+							rewriteDryRunOutput(path+".cases."+strconv.Itoa(i)+".output", child, report)
+						}
+					}
+				}
+			}
+			return
+		case typeName == "broker":
+			if children, ok := conf["outputs"].([]any); ok {
+				rewriteDryRunOutputList(path+".outputs", children, report)
+			}
+			return
+		case typeName == "weighted":
+			if children, ok := conf["outputs"].([]any); ok {
+				for i, c := range children {
+					if c, ok := c.(map[string]any); ok {
+						if child, ok := c["output"].(map[string]any); ok {
+							rewriteDryRunOutput(path+".outputs."+strconv.Itoa(i)+".output", child, report)
+						}
+					}
+				}
+			}
+			return
+		default:
+			// Outputs that wrap others, such as retry and shadow.
+			fields, exists := dryRunChildOutputFields[typeName]
+			if !exists {
+				fields = []string{"output"}
+			}
+			var wrapper bool
+			for _, f := range fields {
+				if child, ok := conf[f].(map[string]any); ok {
+					rewriteDryRunOutput(path+"."+f, child, report)
+					wrapper = true
+				}
+			}
+			if wrapper {
+				return
+			}
+		}
+	}
+
+	label, _ := output["label"].(string)
+	report.Outputs = append(report.Outputs, &dryRunCapture{
+		Path:     path,
+		Type:     typeName,
+		Label:    label,
+		Messages: []dryRunMessage{},
+	})
+	delete(output, typeName)
+	output[dryRunOutput] = map[string]any{"path": path}
+}
+
+func rewriteDryRunOutputList(path string, outputs []any, report *dryRunReport) {
+	for i, o := range outputs {
+		if o, ok := o.(map[string]any); ok {
+			rewriteDryRunOutput(path+"."+strconv.Itoa(i), o, report)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// dryRunFixtureInput reads each fixture once, recording those that are
+// rejected rather than redelivering them, and ends once all fixtures are
+// acknowledged.
+type dryRunFixtureInput struct {
+	fixtures []dryRunFixture
+	report   *dryRunReport
+	next     int
+	pending  sync.WaitGroup
+}
+
+func (*dryRunFixtureInput) Connect(context.Context) error {
+	return nil
+}
+
+func (i *dryRunFixtureInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	if i.next >= len(i.fixtures) {
+		done := make(chan struct{})
+		go func() {
+			i.pending.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, service.ErrEndOfInput
+	}
+
+	index := i.next
+	i.next++
+	msg, err := i.fixtures[index].message()
+	if err != nil {
+		return nil, nil, fmt.Errorf("fixture %v: %w", index, err)
+	}
+	i.pending.Add(1)
+	return msg, func(_ context.Context, err error) error {
+		if err != nil {
+			i.report.reject(index, err)
+		}
+		i.pending.Done()
+		return nil
+	}, nil
+}
+
+func (*dryRunFixtureInput) Close(context.Context) error {
+	return nil
+}
+
+type dryRunCaptureOutput struct {
+	path   string
+	report *dryRunReport
+}
+
+func (*dryRunCaptureOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *dryRunCaptureOutput) Write(_ context.Context, msg *service.Message) error {
+	return o.report.capture(o.path, msg)
+}
+
+func (*dryRunCaptureOutput) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func TestDryRunRouting(t *testing.T) {
+	conf := `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ orders ]
+  processors:
+    - mapping: 'meta source = "kafka"'
+
+pipeline:
+  processors:
+    - mapping: |
+        root = this
+        root.total = this.price * this.quantity
+
+output:
+  switch:
+    cases:
+      - check: this.total > 100
+        output:
+          label: big_orders
+          aws_s3:
+            bucket: big
+      - check: this.total < 0
+        output:
+          reject: 'negative total: ${! this.total }'
+      - output:
+          broker:
+            outputs:
+              - resource: small_orders
+              - drop: {}
+
+output_resources:
+  - label: small_orders
+    retry:
+      output:
+        kafka_franz:
+          seed_brokers: [ localhost:9092 ]
+          topic: small
+`
+
+	fixtures := []dryRunFixture{
+		{JSONContent: map[string]any{"price": 50, "quantity": 3}},
+		{Content: `{"price":5,"quantity":2}`, Metadata: map[string]any{"key": "a"}},
+		{JSONContent: map[string]any{"price": -1, "quantity": 1}},
+	}
+
+	report, err := runDryRun(t.Context(), service.GlobalEnvironment(), []byte(conf), fixtures)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Fixtures)
+	require.Len(t, report.Outputs, 2)
+
+	assert.Equal(t, "output.switch.cases.0.output.aws_s3", report.Outputs[0].Path)
+	assert.Equal(t, "aws_s3", report.Outputs[0].Type)
+	assert.Equal(t, "big_orders", report.Outputs[0].Label)
+	assert.Equal(t, []dryRunMessage{
+		{Content: `{"price":50,"quantity":3,"total":150}`, Metadata: map[string]any{"source": "kafka"}},
+	}, report.Outputs[0].Messages)
+
+	assert.Equal(t, "output_resources.0.retry.output.kafka_franz", report.Outputs[1].Path)
+	assert.Equal(t, "kafka_franz", report.Outputs[1].Type)
+	assert.Equal(t, []dryRunMessage{
+		{Content: `{"price":5,"quantity":2,"total":10}`, Metadata: map[string]any{"key": "a", "source": "kafka"}},
+	}, report.Outputs[1].Messages)
+
+	assert.Equal(t, []dryRunRejection{
+		{Fixture: 2, Error: "negative total: -1"},
+	}, report.Rejected)
+}
+
+func TestDryRunNoFixtures(t *testing.T) {
+	report, err := runDryRun(t.Context(), service.GlobalEnvironment(), []byte(`
+output:
+  stdout: {}
+`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Fixtures)
+	require.Len(t, report.Outputs, 1)
+	assert.Equal(t, "output.stdout", report.Outputs[0].Path)
+	assert.Empty(t, report.Outputs[0].Messages)
+	assert.Empty(t, report.Rejected)
+}
+
+func TestDryRunRewritesWrapperChildren(t *testing.T) {
+	tests := []struct {
+		name  string
+		conf  string
+		paths []string
+	}{
+		{
+			name: "shadow",
+			conf: `
+output:
+  shadow:
+    output:
+      kafka_franz:
+        seed_brokers: [ a:9092 ]
+        topic: foo
+    shadow:
+      kafka_franz:
+        seed_brokers: [ b:9092 ]
+        topic: foo
+`,
+			paths: []string{
+				"output.shadow.output.kafka_franz",
+				"output.shadow.shadow.kafka_franz",
+			},
+		},
+		{
+			name: "circuit_breaker",
+			conf: `
+output:
+  circuit_breaker:
+    output:
+      kafka_franz:
+        seed_brokers: [ a:9092 ]
+        topic: foo
+    fallback:
+      aws_s3:
+        bucket: foo
+`,
+			paths: []string{
+				"output.circuit_breaker.output.kafka_franz",
+				"output.circuit_breaker.fallback.aws_s3",
+			},
+		},
+		{
+			name: "retry_dlq",
+			conf: `
+output:
+  retry_dlq:
+    output:
+      kafka_franz:
+        seed_brokers: [ a:9092 ]
+        topic: foo
+    dlq:
+      retry:
+        output:
+          kafka_franz:
+            seed_brokers: [ b:9092 ]
+            topic: foo_dlq
+`,
+			paths: []string{
+				"output.retry_dlq.output.kafka_franz",
+				"output.retry_dlq.dlq.retry.output.kafka_franz",
+			},
+		},
+		{
+			name: "weighted",
+			conf: `
+output:
+  weighted:
+    outputs:
+      - weight: 9
+        output:
+          kafka_franz:
+            seed_brokers: [ a:9092 ]
+            topic: foo
+      - weight: 1
+        output:
+          shadow:
+            output:
+              kafka_franz:
+                seed_brokers: [ b:9092 ]
+                topic: foo
+            shadow:
+              drop: {}
+`,
+			paths: []string{
+				"output.weighted.outputs.0.output.kafka_franz",
+				"output.weighted.outputs.1.output.shadow.output.kafka_franz",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var conf map[string]any
+			require.NoError(t, yaml.Unmarshal([]byte(test.conf), &conf))
+
+			report := &dryRunReport{}
+			rewriteDryRunConfig(conf, report)
+
+			var paths []string
+			for _, c := range report.Outputs {
+				paths = append(paths, c.Path)
+			}
+			assert.Equal(t, test.paths, paths)
+
+			confBytes, err := yaml.Marshal(conf)
+			require.NoError(t, err)
+			assert.NotContains(t, string(confBytes), "seed_brokers")
+			assert.NotContains(t, string(confBytes), "bucket")
+		})
+	}
+}
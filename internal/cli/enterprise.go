@@ -134,6 +134,7 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 						Name:  "rpc-plugins",
 						Usage: "Plugins to load over the RPC interface. This flag should point to manifest files containing the plugin definitions. Globs are also supported.",
 					},
+					profilingAddressFlag,
 				},
 				redpandaFlags(),
 			),
@@ -148,6 +149,12 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 					return err
 				}
 
+				if profilingAddress := c.String(profilingAddressFlag.Name); profilingAddress != "" {
+					if err := serveProfiling(profilingAddress, slog.New(rpLogger)); err != nil {
+						return err
+					}
+				}
+
 				rpcPlugins := c.StringSlice("rpc-plugins")
 				err := rpcplugin.DiscoverAndRegisterPlugins(service.OSFS(), schema.Environment(), rpcPlugins)
 				if err != nil {
@@ -178,6 +185,9 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 		service.CLIOptAddCommand(agentCli(rpMgr)),
 		service.CLIOptAddCommand(mcpServerCli(rpMgr)),
 		service.CLIOptAddCommand(pluginInit()),
+		service.CLIOptAddCommand(dryRunCli(schema)),
+		service.CLIOptAddCommand(mappingCli()),
+		service.CLIOptAddCommand(streamsRemoteCli(schema)),
 	)
 
 	exitCode, err := service.RunCLIToCode(context.Background(), opts...)
@@ -42,7 +42,7 @@ const (
 
 var secretsFlag = &cli.StringSliceFlag{
 	Name:  "secrets",
-	Usage: "Attempt to load secrets from a provided URN. If more than one entry is specified they will be attempted in order until a value is found. Environment variable lookups are specified with the URN `env:`, which by default is the only entry. In order to disable all secret lookups specify a single entry of `none:`.",
+	Usage: "Attempt to load secrets from a provided URN. If more than one entry is specified they will be attempted in order until a value is found. Environment variable lookups are specified with the URN `env:`, which by default is the only entry. Secrets can also be loaded from HashiCorp Vault with `vault://host:port/mount/prefix`, authenticated with the `VAULT_TOKEN` environment variable, and from Kubernetes secrets with `k8s://namespace/prefix`, where a field of a secret is selected with a key of the form `NAME.field`. In order to disable all secret lookups specify a single entry of `none:`.",
 	Value: cli.NewStringSlice("env:"),
 }
 
@@ -0,0 +1,248 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// The maximum size of mappings submitted to the REPL server.
+const mappingMaxBodySize = 1 << 20
+
+var mappingSamplesFlag = &cli.StringFlag{
+	Name:     "samples",
+	Aliases:  []string{"s"},
+	Required: true,
+	Usage:    "A file of sample messages, either a YAML or JSON list of messages in the format of dry-run fixtures or the newline delimited JSON captured from a tap.",
+}
+
+func mappingCli() *cli.Command {
+	return &cli.Command{
+		Name:  "mapping",
+		Usage: "Iterate on Bloblang mappings against captured sample messages",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "check",
+				Usage: "Execute a mapping against samples and report the outcome for each",
+				Flags: []cli.Flag{mappingSamplesFlag},
+				Description: `
+Executes a mapping file against each sample message and prints a JSON report of
+the result for each sample along with a summary of how many samples were
+mapped, deleted or failed. Exits with a status code 1 if the mapping fails for
+any sample:
+
+  {{.BinaryName}} mapping check -s ./samples.ndjson ./mapping.blobl`[1:],
+				Action: func(c *cli.Context) error {
+					if c.Args().Len() != 1 {
+						return errors.New("exactly one mapping file must be specified with this command")
+					}
+					samples, err := readMappingSamples(c.String("samples"))
+					if err != nil {
+						return err
+					}
+					mappingBytes, err := os.ReadFile(c.Args().First())
+					if err != nil {
+						return fmt.Errorf("failed to read mapping: %w", err)
+					}
+					exec, err := bloblang.Parse(string(mappingBytes))
+					if err != nil {
+						return fmt.Errorf("failed to parse mapping: %w", err)
+					}
+
+					report, err := runMapping(exec, samples)
+					if err != nil {
+						return err
+					}
+					enc := json.NewEncoder(c.App.Writer)
+					enc.SetIndent("", "  ")
+					if err := enc.Encode(report); err != nil {
+						return err
+					}
+					if report.Summary.Failed > 0 {
+						return cli.Exit("", 1)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "serve",
+				Usage: "Run an HTTP server for executing mappings against samples",
+				Flags: []cli.Flag{
+					mappingSamplesFlag,
+					&cli.StringFlag{
+						Name:    "address",
+						Aliases: []string{"a"},
+						Value:   "localhost:4198",
+						Usage:   "The address to listen on.",
+					},
+				},
+				Description: `
+Serves the sample messages at GET /samples, and executes the mapping within the
+body of each POST /execute request against all samples, responding with the
+same report as the check subcommand:
+
+  {{.BinaryName}} mapping serve -s ./samples.ndjson
+  curl -d 'root.name = this.name.uppercase()' http://localhost:4198/execute`[1:],
+				Action: func(c *cli.Context) error {
+					samples, err := readMappingSamples(c.String("samples"))
+					if err != nil {
+						return err
+					}
+					listener, err := net.Listen("tcp", c.String("address"))
+					if err != nil {
+						return err
+					}
+					server := &http.Server{Handler: mappingHandler(samples)}
+					go func() {
+						<-c.Context.Done()
+						_ = server.Close()
+					}()
+
+					fmt.Fprintf(c.App.Writer, "Serving %v samples at: http://%v\n", len(samples), listener.Addr())
+					if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						return err
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// readMappingSamples reads sample messages from a list of fixtures, falling
+// back to newline delimited JSON messages such as those captured from a tap.
+func readMappingSamples(path string) ([]dryRunFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read samples: %w", err)
+	}
+
+	var samples []dryRunFixture
+	if err := yaml.Unmarshal(data, &samples); err == nil {
+		return samples, nil
+	}
+
+	samples = nil
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var s dryRunFixture
+		if err := dec.Decode(&s); err != nil {
+			if errors.Is(err, io.EOF) {
+				return samples, nil
+			}
+			return nil, fmt.Errorf("failed to parse sample %v: %w", len(samples), err)
+		}
+		samples = append(samples, s)
+	}
+}
+
+type mappingResult struct {
+	Sample   int            `json:"sample"`
+	Content  *string        `json:"content,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Deleted  bool           `json:"deleted,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+type mappingSummary struct {
+	Samples int `json:"samples"`
+	Mapped  int `json:"mapped"`
+	Deleted int `json:"deleted"`
+	Failed  int `json:"failed"`
+}
+
+type mappingReport struct {
+	Summary mappingSummary  `json:"summary"`
+	Results []mappingResult `json:"results"`
+}
+
+// runMapping executes a mapping against each sample.
+func runMapping(exec *bloblang.Executor, samples []dryRunFixture) (*mappingReport, error) {
+	report := &mappingReport{
+		Summary: mappingSummary{Samples: len(samples)},
+		Results: make([]mappingResult, 0, len(samples)),
+	}
+	for i, s := range samples {
+		msg, err := s.message()
+		if err != nil {
+			return nil, fmt.Errorf("sample %v: %w", i, err)
+		}
+
+		res := mappingResult{Sample: i}
+		switch out, err := msg.BloblangQuery(exec); {
+		case err != nil:
+			res.Error = err.Error()
+			report.Summary.Failed++
+		case out == nil:
+			res.Deleted = true
+			report.Summary.Deleted++
+		default:
+			content, err := out.AsBytes()
+			if err != nil {
+				return nil, fmt.Errorf("sample %v: %w", i, err)
+			}
+			contentStr := string(content)
+			res.Content = &contentStr
+			res.Metadata = map[string]any{}
+			_ = out.MetaWalkMut(func(k string, v any) error {
+				res.Metadata[k] = v
+				return nil
+			})
+			report.Summary.Mapped++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+func mappingHandler(samples []dryRunFixture) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /samples", func(w http.ResponseWriter, _ *http.Request) {
+		writeMappingJSON(w, http.StatusOK, samples)
+	})
+	mux.HandleFunc("POST /execute", func(w http.ResponseWriter, r *http.Request) {
+		mappingBytes, err := io.ReadAll(io.LimitReader(r.Body, mappingMaxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		exec, err := bloblang.Parse(string(mappingBytes))
+		if err != nil {
+			writeMappingJSON(w, http.StatusBadRequest, map[string]string{"parse_error": err.Error()})
+			return
+		}
+		report, err := runMapping(exec, samples)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeMappingJSON(w, http.StatusOK, report)
+	})
+	return mux
+}
+
+func writeMappingJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingSamplesFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	fixturesPath := filepath.Join(dir, "fixtures.yaml")
+	require.NoError(t, os.WriteFile(fixturesPath, []byte(`
+- content: hello
+  metadata:
+    key: a
+- json_content:
+    name: foo
+`), 0o644))
+
+	tapPath := filepath.Join(dir, "tap.ndjson")
+	require.NoError(t, os.WriteFile(tapPath, []byte(`{"tap":"pre_output","timestamp":"2025-01-01T00:00:00Z","metadata":{"key":"a"},"content":"hello"}
+{"tap":"pre_output","timestamp":"2025-01-01T00:00:01Z","metadata":{},"content":"{\"name\":\"foo\"}"}
+`), 0o644))
+
+	for _, path := range []string{fixturesPath, tapPath} {
+		samples, err := readMappingSamples(path)
+		require.NoError(t, err, path)
+		require.Len(t, samples, 2, path)
+
+		msg, err := samples[0].message()
+		require.NoError(t, err)
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(b), path)
+		v, _ := msg.MetaGet("key")
+		assert.Equal(t, "a", v, path)
+
+		msg, err = samples[1].message()
+		require.NoError(t, err)
+		b, err = msg.AsBytes()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"foo"}`, string(b), path)
+	}
+}
+
+func TestMappingServer(t *testing.T) {
+	samples := []dryRunFixture{
+		{JSONContent: map[string]any{"name": "foo"}},
+		{JSONContent: map[string]any{"name": "bar", "skip": true}},
+		{Content: "not json"},
+	}
+	server := httptest.NewServer(mappingHandler(samples))
+	t.Cleanup(server.Close)
+
+	res, err := http.Post(server.URL+"/execute", "text/plain", strings.NewReader(`
+meta upper = this.name.uppercase()
+root = if this.skip.or(false) { deleted() } else { this.assign({"name": @upper}) }
+`))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var report mappingReport
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&report))
+	assert.Equal(t, mappingSummary{Samples: 3, Mapped: 1, Deleted: 1, Failed: 1}, report.Summary)
+	require.Len(t, report.Results, 3)
+	require.NotNil(t, report.Results[0].Content)
+	assert.JSONEq(t, `{"name":"FOO"}`, *report.Results[0].Content)
+	assert.Equal(t, map[string]any{"upper": "FOO"}, report.Results[0].Metadata)
+	assert.True(t, report.Results[1].Deleted)
+	assert.NotEmpty(t, report.Results[2].Error)
+
+	res, err = http.Post(server.URL+"/execute", "text/plain", strings.NewReader(`root = this.`))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
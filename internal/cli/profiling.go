@@ -0,0 +1,185 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/profile"
+)
+
+const (
+	profilingDefaultSeconds = 10
+	profilingMaxSeconds     = 60
+)
+
+var profilingAddressFlag = &cli.StringFlag{
+	Name:  "profiling-address",
+	Usage: "An address on which to serve on demand CPU, heap and goroutine profiles of this process, along with the CPU time consumed by each profile processor. Profiles reveal details of the running process and so this should only be reachable by operators.",
+}
+
+// profilingServer serves profiles of the running process. Only one CPU profile
+// can be captured at a time, and captures are limited in duration.
+type profilingServer struct {
+	capture sync.Mutex
+}
+
+// serveProfiling starts a server on an address that serves profiles of the
+// running process until the process exits.
+func serveProfiling(address string, log *slog.Logger) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on profiling address: %w", err)
+	}
+	s := &profilingServer{}
+	go func() {
+		log.Info("Serving profiles", "address", "http://"+listener.Addr().String()+"/debug/profile")
+		if err := http.Serve(listener, s.handler()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Profiling server error", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *profilingServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/profile/cpu", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := s.captureCPU(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="cpu.pprof"`)
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("GET /debug/profile/components", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := s.captureCPU(w, r)
+		if !ok {
+			return
+		}
+		res, err := componentsCPU(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("GET /debug/profile/heap", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="heap.pprof"`)
+		_ = pprof.Lookup("heap").WriteTo(w, 0)
+	})
+	mux.HandleFunc("GET /debug/profile/goroutine", func(w http.ResponseWriter, r *http.Request) {
+		// Full stack dumps of every goroutine can be enormous and so only the
+		// profile and its aggregated text form are offered.
+		if r.URL.Query().Get("debug") == "1" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_ = pprof.Lookup("goroutine").WriteTo(w, 1)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="goroutine.pprof"`)
+		_ = pprof.Lookup("goroutine").WriteTo(w, 0)
+	})
+	return mux
+}
+
+// captureCPU captures a CPU profile for the number of seconds requested,
+// writing an error response and returning false when a profile can't be
+// captured.
+func (s *profilingServer) captureCPU(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	seconds := profilingDefaultSeconds
+	if secondsStr := r.URL.Query().Get("seconds"); secondsStr != "" {
+		var err error
+		if seconds, err = strconv.Atoi(secondsStr); err != nil || seconds < 1 || seconds > profilingMaxSeconds {
+			http.Error(w, fmt.Sprintf("seconds must be between 1 and %v", profilingMaxSeconds), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+
+	if !s.capture.TryLock() {
+		http.Error(w, "A CPU profile is already being captured", http.StatusConflict)
+		return nil, false
+	}
+	defer s.capture.Unlock()
+
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return nil, false
+	}
+
+	timer := time.NewTimer(time.Duration(seconds) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+	pprof.StopCPUProfile()
+
+	if err := r.Context().Err(); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+type componentsCPUResult struct {
+	DurationNanos     int64            `json:"duration_ns"`
+	TotalCPUNanos     int64            `json:"total_cpu_ns"`
+	UnattributedNanos int64            `json:"unattributed_cpu_ns"`
+	Components        map[string]int64 `json:"components_cpu_ns"`
+}
+
+// componentsCPU sums the CPU time of the samples of a CPU profile by the name
+// of the profile processor that they were executed within.
+func componentsCPU(data []byte) (*componentsCPUResult, error) {
+	p, err := pprofile.ParseData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+
+	valueIndex := -1
+	for i, st := range p.SampleType {
+		if st.Type == "cpu" && st.Unit == "nanoseconds" {
+			valueIndex = i
+		}
+	}
+	if valueIndex < 0 {
+		return nil, errors.New("profile does not contain CPU time")
+	}
+
+	res := &componentsCPUResult{
+		DurationNanos: p.DurationNanos,
+		Components:    map[string]int64{},
+	}
+	for _, sample := range p.Sample {
+		v := sample.Value[valueIndex]
+		res.TotalCPUNanos += v
+		if names := sample.Label[profile.LabelKey]; len(names) > 0 {
+			res.Components[names[0]] += v
+		} else {
+			res.UnattributedNanos += v
+		}
+	}
+	return res, nil
+}
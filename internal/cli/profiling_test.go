@@ -0,0 +1,88 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/profile"
+)
+
+func TestProfilingComponents(t *testing.T) {
+	s := &profilingServer{}
+	server := httptest.NewServer(s.handler())
+	t.Cleanup(server.Close)
+
+	ctx, done := context.WithCancel(t.Context())
+	defer done()
+	go pprof.Do(ctx, pprof.Labels(profile.LabelKey, "busy"), func(ctx context.Context) {
+		var n uint64
+		for ctx.Err() == nil {
+			for i := range 1 << 16 {
+				n += uint64(i)
+			}
+		}
+		_ = n
+	})
+
+	// Only one CPU profile can be captured at a time.
+	conflict := make(chan int)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		res, err := http.Get(server.URL + "/debug/profile/cpu?seconds=1")
+		if err != nil {
+			conflict <- 0
+			return
+		}
+		res.Body.Close()
+		conflict <- res.StatusCode
+	}()
+
+	res, err := http.Get(server.URL + "/debug/profile/components?seconds=1")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var result componentsCPUResult
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+	assert.Positive(t, result.Components["busy"])
+	assert.GreaterOrEqual(t, result.TotalCPUNanos, result.Components["busy"]+result.UnattributedNanos)
+	assert.Equal(t, http.StatusConflict, <-conflict)
+}
+
+func TestProfilingLimits(t *testing.T) {
+	s := &profilingServer{}
+	server := httptest.NewServer(s.handler())
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{
+		"/debug/profile/cpu?seconds=0",
+		"/debug/profile/cpu?seconds=61",
+		"/debug/profile/components?seconds=nope",
+	} {
+		res, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, path)
+	}
+
+	res, err := http.Get(server.URL + "/debug/profile/heap")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
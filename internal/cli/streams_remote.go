@@ -0,0 +1,203 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/public/streams"
+)
+
+func streamsRemoteCli(schema *service.ConfigSchema) *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "source",
+			Aliases:  []string{"s"},
+			Required: true,
+			Usage:    "The location of stream configs, either an S3 prefix (`s3://bucket/prefix`), a GCS prefix (`gs://bucket/prefix`) or the URL of a git repository.",
+		},
+		&cli.StringFlag{
+			Name:  "branch",
+			Value: "main",
+			Usage: "The branch to track when the source is a git repository.",
+		},
+		&cli.StringFlag{
+			Name:  "dir",
+			Usage: "An optional directory containing the stream configs when the source is a git repository.",
+		},
+		&cli.StringSliceFlag{
+			Name:  "verify-key",
+			Usage: "A file containing an ed25519 public key, either PEM encoded or as base64, by which each stream config must be signed. Can be specified multiple times, in which case a signature by any of the keys is accepted.",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-unsigned",
+			Usage: "Run stream configs without verifying their signatures, which is required when no --verify-key is specified.",
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Value: 30 * time.Second,
+			Usage: "The interval at which the source is polled for changes to stream configs.",
+		},
+		&cli.DurationFlag{
+			Name:  "drain-timeout",
+			Value: 30 * time.Second,
+			Usage: "The maximum period to wait for a stream to drain when it's updated, removed or shut down.",
+		},
+		envFileFlag,
+	}
+
+	return &cli.Command{
+		Name:  "streams-remote",
+		Usage: "Run streams mode with stream configs synced from object storage or a git repository",
+		Flags: flags,
+		Description: `
+Runs the stream configs found within an S3 or GCS prefix or a git repository,
+polling the source for changes and restarting only the streams whose configs
+changed. The ID of each stream is the path of its config relative to the
+source without the extension, with path separators replaced by underscores:
+
+  {{.BinaryName}} streams-remote -s s3://configs/streams --verify-key ./key.pub
+  {{.BinaryName}} streams-remote -s https://github.com/foo/configs.git --dir streams --verify-key ./key.pub
+
+Each config must be accompanied by a file with the same path and the suffix
+.sig containing the base64 encoded ed25519 signature by one of the keys given
+with --verify-key of the path of the config relative to the source (or --dir),
+followed by a null byte and the contents of the config. When any config lacks a
+valid signature none of the changes of that poll are applied and the running
+streams are left undisturbed. Signatures don't protect against a config being
+rolled back to an older signed revision of the same path.`[1:],
+		Action: func(c *cli.Context) error {
+			if err := applyEnvFileFlag(c); err != nil {
+				return err
+			}
+
+			ctx, done := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer done()
+
+			src, err := streamsRemoteSource(ctx, c)
+			if err != nil {
+				return err
+			}
+
+			drainTimeout := c.Duration("drain-timeout")
+			mgr := streams.NewManager(
+				streams.OptSetBuilderFunc(schema.Environment().NewStreamBuilder),
+				streams.OptSetDrainTimeout(drainTimeout),
+				streams.OptSetLogger(slog.New(slog.NewTextHandler(c.App.ErrWriter, nil))),
+			)
+			// Watching only stops once the context ends, which is a shutdown.
+			if err := mgr.WatchSource(ctx, src, c.Duration("poll-interval")); err != nil && ctx.Err() == nil {
+				return err
+			}
+
+			stopCtx, stopDone := context.WithTimeout(context.Background(), drainTimeout)
+			defer stopDone()
+			return mgr.StopAll(stopCtx)
+		},
+	}
+}
+
+// streamsRemoteSource creates the source of stream configs described by the
+// flags of the streams-remote subcommand.
+func streamsRemoteSource(ctx context.Context, c *cli.Context) (streams.Source, error) {
+	var keys []ed25519.PublicKey
+	for _, p := range c.StringSlice("verify-key") {
+		key, err := readVerifyKey(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read verify key %v: %w", p, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 && !c.Bool("allow-unsigned") {
+		return nil, errors.New("at least one --verify-key must be specified unless --allow-unsigned is set")
+	}
+
+	rawURL := c.String("source")
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source: %w", err)
+	}
+
+	var src streams.Source
+	switch u.Scheme {
+	case "s3":
+		awsConf, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		src = streams.NewS3Source(s3.NewFromConfig(awsConf), u.Host, u.Path)
+	case "gs":
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCS client: %w", err)
+		}
+		src = streams.NewGCSSource(client, u.Host, u.Path)
+	default:
+		src = streams.NewGitSource(streams.GitSourceConfig{
+			URL:    rawURL,
+			Branch: c.String("branch"),
+			Dir:    c.String("dir"),
+		})
+	}
+
+	if len(keys) > 0 {
+		src = streams.NewVerifiedSource(src, keys...)
+	}
+	return src, nil
+}
+
+// readVerifyKey reads an ed25519 public key from a file, which is either a PEM
+// encoded PKIX key or the base64 encoded raw key.
+func readVerifyKey(path string) (ed25519.PublicKey, error) {
+	keyBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(keyBytes); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an ed25519 public key, got %T", key)
+		}
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(keyBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("expected a PEM or base64 encoded key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected a key of %v bytes, got %v", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/public/streams"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func streamsRemoteRepo(t *testing.T, priv ed25519.PrivateKey, dir string, files map[string]string) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for p, content := range files {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, streams.SignedMessage(p, []byte(content))))
+		p = path.Join(dir, p)
+		for p, content := range map[string]string{p: content, p + ".sig": sig} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repoDir, p)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(repoDir, p), []byte(content), 0o644))
+			_, err := wt.Add(p)
+			require.NoError(t, err)
+		}
+	}
+	_, err = wt.Commit("add streams", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return repoDir
+}
+
+func runStreamsRemote(ctx context.Context, args ...string) (string, error) {
+	var errBuf bytes.Buffer
+	app := &cli.App{
+		Name:      "connect",
+		ErrWriter: &errBuf,
+		Commands: []*cli.Command{
+			streamsRemoteCli(service.GlobalEnvironment().FullConfigSchema("", "")),
+		},
+	}
+	err := app.RunContext(ctx, append([]string{"connect", "streams-remote"}, args...))
+	return errBuf.String(), err
+}
+
+func TestStreamsRemoteGit(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pub")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644))
+
+	outDir := t.TempDir()
+	streamConf := func(name string) string {
+		return fmt.Sprintf(`
+input:
+  generate:
+    count: 1
+    interval: ""
+    mapping: 'root = "%v"'
+output:
+  file:
+    path: %v
+    codec: lines
+`, name, filepath.Join(outDir, name+".txt"))
+	}
+
+	repoDir := streamsRemoteRepo(t, priv, "streams", map[string]string{
+		"foo.yaml": streamConf("foo"),
+		"bar.yaml": streamConf("bar"),
+	})
+
+	ctx, done := context.WithCancel(t.Context())
+	defer done()
+
+	runErr := make(chan error, 1)
+	go func() {
+		_, err := runStreamsRemote(ctx,
+			"--source", repoDir,
+			"--branch", "master",
+			"--dir", "streams",
+			"--verify-key", keyPath,
+			"--poll-interval", "10ms",
+		)
+		runErr <- err
+	}()
+
+	for _, name := range []string{"foo", "bar"} {
+		assert.Eventually(t, func() bool {
+			content, err := os.ReadFile(filepath.Join(outDir, name+".txt"))
+			return err == nil && string(content) == name+"\n"
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	done()
+	require.NoError(t, <-runErr)
+}
+
+func TestStreamsRemoteInvalidSignature(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "key.pub")
+	require.NoError(t, os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(otherPub)), 0o644))
+
+	outPath := filepath.Join(t.TempDir(), "out.txt")
+	repoDir := streamsRemoteRepo(t, priv, "", map[string]string{
+		"foo.yaml": fmt.Sprintf(`
+input:
+  generate:
+    count: 1
+    interval: ""
+    mapping: 'root = "foo"'
+output:
+  file:
+    path: %v
+`, outPath),
+	})
+
+	ctx, done := context.WithTimeout(t.Context(), 500*time.Millisecond)
+	defer done()
+
+	logs, err := runStreamsRemote(ctx,
+		"--source", repoDir,
+		"--branch", "master",
+		"--verify-key", keyPath,
+		"--poll-interval", "10ms",
+	)
+	require.NoError(t, err)
+	assert.Contains(t, logs, "invalid signature")
+	assert.NoFileExists(t, outPath)
+}
+
+func TestStreamsRemoteRequiresVerifyKey(t *testing.T) {
+	_, err := runStreamsRemote(t.Context(), "--source", "s3://foo/bar")
+	require.ErrorContains(t, err, "--verify-key")
+}
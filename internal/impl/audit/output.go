@@ -0,0 +1,206 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const (
+	aoFieldOutput = "output"
+	aoFieldSink   = "sink"
+	aoFieldTarget = "target"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Writes messages to a child output and records an audit event for each write, along with when the output starts and stops, to a sink output resource.").
+		Description(`
+Audit events are JSON objects written to the output resource named by `+"`"+aoFieldSink+"`"+`, which can be any output such as a `+"`file`"+` or `+"`kafka_franz`"+` output, and which can be shared by the audit outputs of a config. Each event has the following fields:
+
+`+"```json"+`
+{
+  "time": "2025-01-01T00:00:00.000000001Z",
+  "event": "write",
+  "component": "orders_sink",
+  "records": 3,
+  "bytes": 512,
+  "targets": { "orders": 2, "refunds": 1 }
+}
+`+"```"+`
+
+The `+"`event`"+` is one of `+"`started`"+`, when the output is first connected, `+"`write`"+`, when a batch is written successfully, `+"`write_failed`"+`, which also includes an `+"`error`"+` field, and `+"`stopped`"+`, when the output is closed. The `+"`component`"+` is the label of this output. Write events count the records and bytes of each batch, and the records written to each `+"`"+aoFieldTarget+"`"+`, such as the topic, bucket or table that messages are routed to.
+
+Audit events are written before the write of a batch is acknowledged. If an event can't be written the write is reported as failed and retried, and so every write is audited at least once, at the cost of data being written again.`).
+		Fields(
+			service.NewOutputField(aoFieldOutput).
+				Description("The child output to write messages to."),
+			service.NewStringField(aoFieldSink).
+				Description("The name of an output resource to write audit events to."),
+			service.NewInterpolatedStringField(aoFieldTarget).
+				Description("An optional identifier of the destination each message is written to, such as a topic, bucket or table, that write events count records by.").
+				Examples(`${! @kafka_topic }`, `orders_table`).
+				Default(""),
+		).
+		Example(
+			"Audit Kafka writes",
+			"Record the records written to each topic within another topic.",
+			`
+output:
+  label: orders_sink
+  audit:
+    sink: audit_log
+    target: ${! @target_topic }
+    output:
+      kafka_franz:
+        seed_brokers: [ localhost:9092 ]
+        topic: ${! @target_topic }
+
+output_resources:
+  - label: audit_log
+    kafka_franz:
+      seed_brokers: [ localhost:9092 ]
+      topic: connect_audit
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("audit", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newOutputFromConfig(conf, mgr)
+			maxInFlight = 64
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+// event is a structured audit record.
+type event struct {
+	Time      time.Time      `json:"time"`
+	Event     string         `json:"event"`
+	Component string         `json:"component"`
+	Records   int            `json:"records,omitempty"`
+	Bytes     int            `json:"bytes,omitempty"`
+	Targets   map[string]int `json:"targets,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+type output struct {
+	mgr       *service.Resources
+	component string
+	output    wrapper.BatchWriter
+	sink      string
+	target    *service.InterpolatedString
+
+	now func() time.Time
+}
+
+func newOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{
+		mgr:       mgr,
+		component: mgr.Label(),
+		now:       time.Now,
+	}
+
+	var err error
+	if o.sink, err = conf.FieldString(aoFieldSink); err != nil {
+		return nil, err
+	}
+	if !mgr.HasOutput(o.sink) {
+		return nil, fmt.Errorf("output resource '%v' was not found", o.sink)
+	}
+	if o.target, err = conf.FieldInterpolatedString(aoFieldTarget); err != nil {
+		return nil, err
+	}
+	if o.output, err = conf.FieldOutput(aoFieldOutput); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *output) emit(ctx context.Context, e event) error {
+	e.Time = o.now()
+	e.Component = o.component
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var writeErr error
+	if err := o.mgr.AccessOutput(ctx, o.sink, func(out *service.ResourceOutput) {
+		writeErr = out.Write(ctx, service.NewMessage(data))
+	}); err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return fmt.Errorf("failed to write audit event: %w", writeErr)
+	}
+	return nil
+}
+
+func (o *output) Connect(ctx context.Context) error {
+	return o.emit(ctx, event{Event: "started"})
+}
+
+func (o *output) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	e := event{Records: len(batch)}
+	for i, msg := range batch {
+		if b, err := msg.AsBytes(); err == nil {
+			e.Bytes += len(b)
+		}
+		target, err := batch.TryInterpolatedString(i, o.target)
+		if err != nil {
+			return fmt.Errorf("failed to interpolate target: %w", err)
+		}
+		if target != "" {
+			if e.Targets == nil {
+				e.Targets = map[string]int{}
+			}
+			e.Targets[target]++
+		}
+	}
+
+	if err := o.output.WriteBatch(ctx, batch); err != nil {
+		e.Event = "write_failed"
+		e.Error = err.Error()
+		if emitErr := o.emit(ctx, e); emitErr != nil {
+			o.mgr.Logger().Errorf("Failed to write audit event: %v", emitErr)
+		}
+		return err
+	}
+
+	e.Event = "write"
+	return o.emit(ctx, e)
+}
+
+func (o *output) Close(ctx context.Context) error {
+	err := o.output.Close(ctx)
+	if emitErr := o.emit(ctx, event{Event: "stopped"}); emitErr != nil {
+		o.mgr.Logger().Errorf("Failed to write audit event: %v", emitErr)
+	}
+	return err
+}
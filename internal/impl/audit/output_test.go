@@ -0,0 +1,127 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+type captureOutput struct {
+	mut    *sync.Mutex
+	events *[]event
+}
+
+func (captureOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (c captureOutput) Write(_ context.Context, msg *service.Message) error {
+	b, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	var e event
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	c.mut.Lock()
+	*c.events = append(*c.events, e)
+	c.mut.Unlock()
+	return nil
+}
+
+func (captureOutput) Close(context.Context) error {
+	return nil
+}
+
+func TestAuditOutput(t *testing.T) {
+	var (
+		mut    sync.Mutex
+		events []event
+	)
+
+	env := service.NewEnvironment()
+	require.NoError(t, env.RegisterOutput("audit_capture", service.NewConfigSpec(),
+		func(*service.ParsedConfig, *service.Resources) (service.Output, int, error) {
+			return captureOutput{mut: &mut, events: &events}, 1, nil
+		}))
+
+	builder := env.NewStreamBuilder()
+	require.NoError(t, builder.SetYAML(`
+input:
+  generate:
+    count: 3
+    interval: ""
+    batch_size: 3
+    mapping: |
+      root.id = counter()
+      meta topic = if counter() % 2 == 0 { "even" } else { "odd" }
+
+output:
+  label: orders_sink
+  audit:
+    sink: audit_log
+    target: ${! @topic }
+    output:
+      drop: {}
+
+output_resources:
+  - label: audit_log
+    audit_capture: {}
+
+logger:
+  level: none
+`))
+	stream, err := builder.Build()
+	require.NoError(t, err)
+	require.NoError(t, stream.Run(t.Context()))
+
+	mut.Lock()
+	defer mut.Unlock()
+
+	require.Len(t, events, 3)
+	for _, e := range events {
+		assert.Equal(t, "orders_sink", e.Component)
+		assert.False(t, e.Time.IsZero())
+	}
+	assert.Equal(t, "started", events[0].Event)
+	assert.Equal(t, "write", events[1].Event)
+	assert.Equal(t, 3, events[1].Records)
+	assert.Positive(t, events[1].Bytes)
+	assert.Len(t, events[1].Targets, 2)
+	assert.Equal(t, 3, events[1].Targets["even"]+events[1].Targets["odd"])
+	assert.Equal(t, "stopped", events[2].Event)
+}
+
+func TestAuditOutputMissingSink(t *testing.T) {
+	pConf, err := outputSpec().ParseYAML(`
+sink: nope
+output:
+  drop: {}
+`, nil)
+	require.NoError(t, err)
+	_, err = newOutputFromConfig(pConf, service.MockResources())
+	require.ErrorContains(t, err, "was not found")
+}
@@ -47,6 +47,10 @@ a binary value using the ` + "`data_key`" + ` field name.`).
 		Description(`A prefix can be specified to allow multiple cache types to share a single DynamoDB table. An optional TTL duration (` + "`ttl`" + `) and field
 (` + "`ttl_key`" + `) can be specified if the backing table has TTL enabled.
 
+DynamoDB deletes expired items in the background, which can take up to a few days. Items with a TTL that has passed are therefore treated as missing by this cache, and the ` + "`add`" + ` operation overwrites them.
+
+The ` + "`add`" + ` operation, which is used for example by the ` + "`dedupe`" + ` processor, is performed with a conditional write, and is therefore safe for sharing deduplication state between multiple instances of a pipeline. Conditional writes are only atomic within a single region, when using a https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/GlobalTables.html[global table^] concurrent writes of a key to different regions are resolved with a last writer wins policy.
+
 Strong read consistency can be enabled using the ` + "`consistent_read`" + ` configuration field.`).
 		Field(service.NewStringField("table").
 			Description("The table to store items in.")).
@@ -240,12 +244,30 @@ func (d *dynamodbCache) get(ctx context.Context, key string) ([]byte, error) {
 	}
 
 	val, ok := res.Item[d.dataKey].(*types.AttributeValueMemberB)
-	if !ok {
+	if !ok || d.itemExpired(res.Item, time.Now()) {
 		return nil, service.ErrKeyNotFound
 	}
 	return val.Value, nil
 }
 
+// itemExpired returns true when an item has a TTL that has already passed.
+// DynamoDB deletes expired items in the background, which can take several
+// days, and therefore they must be ignored explicitly.
+func (d *dynamodbCache) itemExpired(item map[string]types.AttributeValue, now time.Time) bool {
+	if d.ttlKey == nil {
+		return false
+	}
+	ttlVal, ok := item[*d.ttlKey].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(ttlVal.Value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return expiry <= now.Unix()
+}
+
 func (d *dynamodbCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
 	boff := d.boffPool.Get().(backoff.BackOff)
 	defer func() {
@@ -346,12 +368,13 @@ func (d *dynamodbCache) add(ctx context.Context, key string, value []byte, ttl *
 	input := d.putItemInput(key, value, ttl)
 
 	expr, err := expression.NewBuilder().
-		WithCondition(expression.AttributeNotExists(expression.Name(d.hashKey))).
+		WithCondition(d.addCondition(time.Now())).
 		Build()
 	if err != nil {
 		return err
 	}
 	input.ExpressionAttributeNames = expr.Names()
+	input.ExpressionAttributeValues = expr.Values()
 	input.ConditionExpression = expr.Condition()
 
 	if _, err = d.client.PutItem(ctx, input); err != nil {
@@ -364,6 +387,17 @@ func (d *dynamodbCache) add(ctx context.Context, key string, value []byte, ttl *
 	return nil
 }
 
+// addCondition returns the condition under which an item can be added, which
+// is when the key does not exist or when the existing item has expired but is
+// yet to be deleted by DynamoDB.
+func (d *dynamodbCache) addCondition(now time.Time) expression.ConditionBuilder {
+	cond := expression.AttributeNotExists(expression.Name(d.hashKey))
+	if d.ttlKey != nil {
+		cond = cond.Or(expression.Name(*d.ttlKey).LessThanEqual(expression.Value(now.Unix())))
+	}
+	return cond
+}
+
 func (d *dynamodbCache) Delete(ctx context.Context, key string) error {
 	boff := d.boffPool.Get().(backoff.BackOff)
 	defer func() {
@@ -19,6 +19,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -102,3 +104,45 @@ ttl_key: buz
 		})
 	}
 }
+
+func TestDynamoDBCacheItemExpired(t *testing.T) {
+	ttlKey := "expires_at"
+	now := time.Unix(1000, 0)
+
+	withTTL := &dynamodbCache{ttlKey: &ttlKey}
+	withoutTTL := &dynamodbCache{}
+
+	expired := map[string]types.AttributeValue{
+		ttlKey: &types.AttributeValueMemberN{Value: "999"},
+	}
+	live := map[string]types.AttributeValue{
+		ttlKey: &types.AttributeValueMemberN{Value: "1001"},
+	}
+
+	assert.True(t, withTTL.itemExpired(expired, now))
+	assert.False(t, withTTL.itemExpired(live, now))
+	assert.False(t, withTTL.itemExpired(map[string]types.AttributeValue{}, now))
+	assert.False(t, withoutTTL.itemExpired(expired, now))
+}
+
+func TestDynamoDBCacheAddCondition(t *testing.T) {
+	ttlKey := "expires_at"
+	now := time.Unix(1000, 0)
+
+	expr, err := expression.NewBuilder().
+		WithCondition((&dynamodbCache{hashKey: "id"}).addCondition(now)).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, "attribute_not_exists (#0)", *expr.Condition())
+	assert.Equal(t, map[string]string{"#0": "id"}, expr.Names())
+
+	expr, err = expression.NewBuilder().
+		WithCondition((&dynamodbCache{hashKey: "id", ttlKey: &ttlKey}).addCondition(now)).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, "(attribute_not_exists (#0)) OR (#1 <= :0)", *expr.Condition())
+	assert.Equal(t, map[string]string{"#0": "id", "#1": "expires_at"}, expr.Names())
+	assert.Equal(t, map[string]types.AttributeValue{
+		":0": &types.AttributeValueMemberN{Value: "1000"},
+	}, expr.Values())
+}
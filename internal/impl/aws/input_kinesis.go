@@ -41,6 +41,11 @@ const (
 	kiddbFieldWriteCapacityUnits = "write_capacity_units"
 	kiddbFieldBillingMode        = "billing_mode"
 
+	// Kinesis Input Enhanced Fan-Out Fields
+	kiefoFieldEnabled      = "enabled"
+	kiefoFieldConsumerName = "consumer_name"
+	kiefoFieldConsumerARN  = "consumer_arn"
+
 	// Kinesis Input Fields
 	kiFieldDynamoDB         = "dynamodb"
 	kiFieldStreams          = "streams"
@@ -50,6 +55,7 @@ const (
 	kiFieldLeasePeriod      = "lease_period"
 	kiFieldRebalancePeriod  = "rebalance_period"
 	kiFieldStartFromOldest  = "start_from_oldest"
+	kiFieldEnhancedFanOut   = "enhanced_fan_out"
 	kiFieldBatching         = "batching"
 
 	// Kinesis metrics
@@ -66,6 +72,26 @@ type kiConfig struct {
 	LeasePeriod      string
 	RebalancePeriod  string
 	StartFromOldest  bool
+	EnhancedFanOut   kiefoConfig
+}
+
+type kiefoConfig struct {
+	Enabled      bool
+	ConsumerName string
+	ConsumerARN  string
+}
+
+func kinesisInputEnhancedFanOutConfigFromParsed(pConf *service.ParsedConfig) (conf kiefoConfig, err error) {
+	if conf.Enabled, err = pConf.FieldBool(kiefoFieldEnabled); err != nil {
+		return
+	}
+	if conf.ConsumerName, err = pConf.FieldString(kiefoFieldConsumerName); err != nil {
+		return
+	}
+	if conf.ConsumerARN, err = pConf.FieldString(kiefoFieldConsumerARN); err != nil {
+		return
+	}
+	return
 }
 
 func kinesisInputConfigFromParsed(pConf *service.ParsedConfig) (conf kiConfig, err error) {
@@ -95,6 +121,11 @@ func kinesisInputConfigFromParsed(pConf *service.ParsedConfig) (conf kiConfig, e
 	if conf.StartFromOldest, err = pConf.FieldBool(kiFieldStartFromOldest); err != nil {
 		return
 	}
+	if pConf.Contains(kiFieldEnhancedFanOut) {
+		if conf.EnhancedFanOut, err = kinesisInputEnhancedFanOutConfigFromParsed(pConf.Namespace(kiFieldEnhancedFanOut)); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -113,6 +144,16 @@ Redpanda Connect will not store a consumed sequence unless it is acknowledged at
 
 By default messages of a shard can be processed in parallel, up to a limit determined by the field `+"`checkpoint_limit`"+`. However, if strict ordered processing is required then this value must be set to 1 in order to process shard messages in lock-step. When doing so it is recommended that you perform batching at this component for performance as it will not be possible to batch lock-stepped messages at the output level.
 
+When shards are balanced and a stream is resharded the child shards are not consumed until their parent shards that are claimed by a consumer have been fully consumed, which preserves the order of records with the same partition key across the split or merge.
+
+== Enhanced fan-out
+
+By default records are pulled from shards with `+"`GetRecords`"+` calls, where the read throughput of each shard is shared between all consumers of the stream. When `+"`enhanced_fan_out.enabled`"+` is set to `+"`true`"+` records are instead pushed to this input via https://docs.aws.amazon.com/streams/latest/dev/enhanced-consumers.html[enhanced fan-out^] subscriptions, giving the input a dedicated read throughput of up to 2MB per second per shard and lower latency.
+
+Enhanced fan-out requires a consumer registered with the stream. Unless a `+"`consumer_arn`"+` is specified the input registers a consumer with the `+"`consumer_name`"+`, or reuses it when it already exists, and waits for it to become active before consuming. Consumers are not deregistered when the input shuts down. Shards are balanced and checkpointed in the DynamoDB table in the same way as when pulling records, and therefore all inputs sharing a table must also share the consumer.
+
+The permissions `+"`kinesis:RegisterStreamConsumer`"+`, `+"`kinesis:DescribeStreamConsumer`"+` and `+"`kinesis:SubscribeToShard`"+` are required in addition to those needed for pulling records.
+
 == Table schema
 
 It's possible to configure Redpanda Connect to create the DynamoDB table required for coordination if it does not already exist. However, if you wish to create this yourself (recommended) then create a table with a string HASH key `+"`StreamID`"+` and a string RANGE key `+"`ShardID`"+`.
@@ -170,6 +211,21 @@ Use the `+"`batching`"+` fields to configure an optional xref:configuration:batc
 		service.NewBoolField(kiFieldStartFromOldest).
 			Description("Whether to consume from the oldest message when a sequence does not yet exist for the stream.").
 			Default(true),
+		service.NewObjectField(kiFieldEnhancedFanOut,
+			service.NewBoolField(kiefoFieldEnabled).
+				Description("Whether to consume shards with enhanced fan-out subscriptions instead of pulling records.").
+				Default(false),
+			service.NewStringField(kiefoFieldConsumerName).
+				Description("The name of the stream consumer to register and subscribe with. Defaults to the name of the DynamoDB table when empty.").
+				Default(""),
+			service.NewStringField(kiefoFieldConsumerARN).
+				Description("The ARN of an existing stream consumer to subscribe with, in which case no consumer is registered. This field can only be used when consuming a single stream.").
+				Example("arn:aws:kinesis:us-east-1:111122223333:stream/my-stream/consumer/my-consumer:1625176821").
+				Default("").
+				Advanced(),
+		).
+			Description("Configures consuming shards via enhanced fan-out, where records are pushed to this input with a dedicated read throughput per shard.").
+			Version("4.62.0"),
 	).
 		Fields(config.SessionFields()...).
 		Field(service.NewBatchPolicyField(kiFieldBatching))
@@ -200,6 +256,7 @@ type streamInfo struct {
 	explicitShards []string
 	id             string // Either a name or arn, extracted from config and used for balancing shards
 	arn            string
+	consumerARN    string // Set when consuming via enhanced fan-out
 }
 
 type kinesisReader struct {
@@ -233,6 +290,10 @@ type kinesisReader struct {
 	closeOnce  sync.Once
 	closedChan chan struct{}
 
+	// Signalled when a shard is finished in order to promptly discover and
+	// claim its children.
+	reshardChan chan struct{}
+
 	clientShardsMetric *service.MetricGauge
 	shardsStolenMetric *service.MetricCounter
 }
@@ -291,13 +352,14 @@ func newKinesisReaderFromConfig(conf kiConfig, batcher service.BatchPolicy, sess
 	}
 
 	k := kinesisReader{
-		conf:       conf,
-		sess:       sess,
-		ddbSess:    ddbSess,
-		batcher:    batcher,
-		log:        mgr.Logger(),
-		mgr:        mgr,
-		closedChan: make(chan struct{}),
+		conf:        conf,
+		sess:        sess,
+		ddbSess:     ddbSess,
+		batcher:     batcher,
+		log:         mgr.Logger(),
+		mgr:         mgr,
+		closedChan:  make(chan struct{}),
+		reshardChan: make(chan struct{}, 1),
 	}
 	k.ctx, k.done = context.WithCancel(context.Background())
 
@@ -367,6 +429,15 @@ func newKinesisReaderFromConfig(conf kiConfig, batcher service.BatchPolicy, sess
 		return nil, fmt.Errorf("failed to parse rebalance period string: %v", err)
 	}
 
+	if k.conf.EnhancedFanOut.Enabled {
+		if k.conf.EnhancedFanOut.ConsumerARN != "" && len(k.streams) > 1 {
+			return nil, errors.New("an enhanced fan-out consumer_arn can only be specified when consuming a single stream")
+		}
+		if k.conf.EnhancedFanOut.ConsumerName == "" {
+			k.conf.EnhancedFanOut.ConsumerName = k.conf.DynamoDB.Table
+		}
+	}
+
 	// Initialize metrics
 	k.clientShardsMetric = mgr.Metrics().NewGauge(metricShardsPerClient)
 	k.shardsStolenMetric = mgr.Metrics().NewCounter(metricShardsStolen)
@@ -490,7 +561,10 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, info streamInfo, shardID
 	// Stores consumed records that have yet to be added to the batcher.
 	var pending []types.Record
 	var iter string
-	if iter, initErr = k.getIter(info, shardID, startingSequence); initErr != nil {
+	var fanOut *kinesisFanOutSubscription
+	if info.consumerARN != "" {
+		fanOut = k.newFanOutSubscription(info, shardID, startingSequence)
+	} else if iter, initErr = k.getIter(info, shardID, startingSequence); initErr != nil {
 		return initErr
 	}
 
@@ -511,14 +585,24 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, info streamInfo, shardID
 	//    is nil when our current batched message is a zero value (we don't have
 	//    one prepared).
 	// 4. Next commit, is "done" when the next commit is due.
+	//
+	// When consuming via enhanced fan-out records are pushed by a subscription
+	// rather than pulled, and are received from the event channel instead.
 	var nextTimedBatchChan <-chan time.Time
 	var nextPullChan <-chan time.Time = unblockedChan
+	var nextEventChan <-chan kinesisFanOutEvent
 	var nextFlushChan chan<- asyncMessage
 	commitCtx, commitCtxClose := context.WithTimeout(k.ctx, k.commitPeriod)
+	if fanOut != nil {
+		nextPullChan = nil
+	}
 
 	go func() {
 		defer func() {
 			commitCtxClose()
+			if fanOut != nil {
+				fanOut.Close()
+			}
 			recordBatcher.Close(context.Background(), state == awsKinesisConsumerFinished)
 			boff.Reset()
 			k.boffPool.Put(boff)
@@ -530,6 +614,10 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, info streamInfo, shardID
 				if err := k.checkpointer.Delete(k.ctx, info.id, shardID); err != nil {
 					k.log.Errorf("Failed to remove checkpoint for finished stream '%v' shard '%v': %v", info.id, shardID, err)
 				}
+				select {
+				case k.reshardChan <- struct{}{}:
+				default:
+				}
 			case awsKinesisConsumerYielding:
 				reason = " because the shard has been claimed by another client"
 				if err := k.checkpointer.Yield(k.ctx, info.id, shardID, recordBatcher.GetSequence()); err != nil {
@@ -557,9 +645,18 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, info streamInfo, shardID
 			}
 		}
 
+		if fanOut != nil {
+			go fanOut.run()
+		}
+
 		for {
 			var err error
-			if state == awsKinesisConsumerConsuming && len(pending) == 0 && nextPullChan == unblockedChan {
+			if fanOut != nil {
+				nextEventChan = nil
+				if state == awsKinesisConsumerConsuming && len(pending) == 0 {
+					nextEventChan = fanOut.events
+				}
+			} else if state == awsKinesisConsumerConsuming && len(pending) == 0 && nextPullChan == unblockedChan {
 				if pending, iter, err = k.getRecords(info, iter); err != nil {
 					if !awsErrIsTimeout(err) {
 						nextPullChan = time.After(boff.NextBackOff())
@@ -668,6 +765,11 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, info streamInfo, shardID
 				pendingMsg = asyncMessage{}
 			case <-nextPullChan:
 				nextPullChan = unblockedChan
+			case event := <-nextEventChan:
+				pending = event.records
+				if event.finished {
+					state = awsKinesisConsumerFinished
+				}
 			case <-k.ctx.Done():
 				state = awsKinesisConsumerClosing
 				return
@@ -739,6 +841,9 @@ func (k *kinesisReader) runBalancedShards() {
 					}
 				}
 			}
+			for _, shardID := range shardsAwaitingParents(shardsRes.Shards, clientClaims) {
+				delete(unclaimedShards, shardID)
+			}
 
 			// Have a go at grabbing any unclaimed shards
 			if len(unclaimedShards) > 0 {
@@ -818,12 +923,39 @@ func (k *kinesisReader) runBalancedShards() {
 
 		select {
 		case <-time.After(k.rebalancePeriod):
+		case <-k.reshardChan:
 		case <-k.ctx.Done():
 			return
 		}
 	}
 }
 
+// shardsAwaitingParents returns the shards that have a parent shard still
+// claimed by a client, and therefore must not be consumed until the parent has
+// been fully consumed.
+func shardsAwaitingParents(shards []types.Shard, clientClaims map[string][]awsKinesisClientClaim) []string {
+	claimed := map[string]struct{}{}
+	for _, claims := range clientClaims {
+		for _, claim := range claims {
+			claimed[claim.ShardID] = struct{}{}
+		}
+	}
+
+	var awaiting []string
+	for _, s := range shards {
+		for _, parentID := range []*string{s.ParentShardId, s.AdjacentParentShardId} {
+			if parentID == nil {
+				continue
+			}
+			if _, exists := claimed[*parentID]; exists {
+				awaiting = append(awaiting, *s.ShardId)
+				break
+			}
+		}
+	}
+	return awaiting
+}
+
 func (k *kinesisReader) runExplicitShards() {
 	var wg sync.WaitGroup
 	defer func() {
@@ -918,11 +1050,18 @@ func (k *kinesisReader) Connect(ctx context.Context) error {
 
 	k.svc = svc
 	k.checkpointer = checkpointer
-	k.msgChan = make(chan asyncMessage)
 
 	if err = k.waitUntilStreamsExists(ctx); err != nil {
 		return err
 	}
+	if k.conf.EnhancedFanOut.Enabled {
+		for _, info := range k.streams {
+			if err = k.ensureStreamConsumer(ctx, info); err != nil {
+				return err
+			}
+		}
+	}
+	k.msgChan = make(chan asyncMessage)
 
 	if len(k.streams[0].explicitShards) > 0 {
 		go k.runExplicitShards()
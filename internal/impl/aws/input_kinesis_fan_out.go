@@ -0,0 +1,198 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// ensureStreamConsumer obtains the ARN of the enhanced fan-out consumer of a
+// stream, registering the consumer if it does not yet exist and waiting until
+// it is active.
+func (k *kinesisReader) ensureStreamConsumer(ctx context.Context, info *streamInfo) error {
+	if k.conf.EnhancedFanOut.ConsumerARN != "" {
+		info.consumerARN = k.conf.EnhancedFanOut.ConsumerARN
+		return nil
+	}
+
+	name := k.conf.EnhancedFanOut.ConsumerName
+	for {
+		res, err := k.svc.DescribeStreamConsumer(ctx, &kinesis.DescribeStreamConsumerInput{
+			StreamARN:    &info.arn,
+			ConsumerName: &name,
+		})
+		if err != nil {
+			var nfErr *types.ResourceNotFoundException
+			if !errors.As(err, &nfErr) {
+				return fmt.Errorf("failed to describe stream '%v' consumer '%v': %w", info.id, name, err)
+			}
+
+			k.log.Infof("Registering enhanced fan-out consumer '%v' for stream '%v'", name, info.id)
+			if _, err = k.svc.RegisterStreamConsumer(ctx, &kinesis.RegisterStreamConsumerInput{
+				StreamARN:    &info.arn,
+				ConsumerName: &name,
+			}); err != nil {
+				// The consumer might have been registered concurrently by
+				// another client, or it might still be deleting, in which case
+				// we keep waiting.
+				var inUseErr *types.ResourceInUseException
+				if !errors.As(err, &inUseErr) {
+					return fmt.Errorf("failed to register stream '%v' consumer '%v': %w", info.id, name, err)
+				}
+			}
+		} else if desc := res.ConsumerDescription; desc != nil && desc.ConsumerStatus == types.ConsumerStatusActive && desc.ConsumerARN != nil {
+			info.consumerARN = *desc.ConsumerARN
+			return nil
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type kinesisFanOutEvent struct {
+	records  []types.Record
+	finished bool
+}
+
+// kinesisFanOutSubscription consumes a shard via enhanced fan-out, pushing the
+// records of each event into a channel. Subscriptions expire after five
+// minutes and are renewed from the latest continuation sequence.
+type kinesisFanOutSubscription struct {
+	k       *kinesisReader
+	info    streamInfo
+	shardID string
+
+	// The sequence to continue from after the current subscription, only
+	// accessed by the run loop.
+	sequence string
+
+	events chan kinesisFanOutEvent
+
+	ctx    context.Context
+	done   func()
+	closed chan struct{}
+}
+
+func (k *kinesisReader) newFanOutSubscription(info streamInfo, shardID, sequence string) *kinesisFanOutSubscription {
+	s := &kinesisFanOutSubscription{
+		k:        k,
+		info:     info,
+		shardID:  shardID,
+		sequence: sequence,
+		events:   make(chan kinesisFanOutEvent),
+		closed:   make(chan struct{}),
+	}
+	s.ctx, s.done = context.WithCancel(k.ctx)
+	return s
+}
+
+func (s *kinesisFanOutSubscription) run() {
+	defer close(s.closed)
+
+	boff := s.k.boffPool.Get().(backoff.BackOff)
+	defer func() {
+		boff.Reset()
+		s.k.boffPool.Put(boff)
+	}()
+
+	for {
+		finished, err := s.subscribe()
+		if finished || s.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// A subscription to the shard might still be held by a client
+			// that the shard was stolen from, which expires shortly.
+			var inUseErr *types.ResourceInUseException
+			if errors.As(err, &inUseErr) {
+				s.k.log.Debugf("Subscription to stream '%v' shard '%v' is in use, retrying: %v", s.info.id, s.shardID, err)
+			} else if !awsErrIsTimeout(err) {
+				s.k.log.Errorf("Failed to subscribe to stream '%v' shard '%v': %v", s.info.id, s.shardID, err)
+			}
+			select {
+			case <-time.After(boff.NextBackOff()):
+			case <-s.ctx.Done():
+				return
+			}
+			continue
+		}
+		boff.Reset()
+	}
+}
+
+func (s *kinesisFanOutSubscription) subscribe() (finished bool, err error) {
+	pos := &types.StartingPosition{Type: types.ShardIteratorTypeTrimHorizon}
+	if !s.k.conf.StartFromOldest {
+		pos.Type = types.ShardIteratorTypeLatest
+	}
+	if s.sequence != "" {
+		pos.Type = types.ShardIteratorTypeAfterSequenceNumber
+		pos.SequenceNumber = &s.sequence
+	}
+
+	res, err := s.k.svc.SubscribeToShard(s.ctx, &kinesis.SubscribeToShardInput{
+		ConsumerARN:      &s.info.consumerARN,
+		ShardId:          &s.shardID,
+		StartingPosition: pos,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	stream := res.GetStream()
+	defer stream.Close()
+
+	for e := range stream.Events() {
+		event, ok := e.(*types.SubscribeToShardEventStreamMemberSubscribeToShardEvent)
+		if !ok {
+			continue
+		}
+
+		// The continuation sequence is only absent once the end of a closed
+		// shard has been reached.
+		finished = event.Value.ContinuationSequenceNumber == nil
+		if len(event.Value.Records) > 0 || finished {
+			select {
+			case s.events <- kinesisFanOutEvent{records: event.Value.Records, finished: finished}:
+			case <-s.ctx.Done():
+				return false, nil
+			}
+		}
+		if finished {
+			return true, nil
+		}
+		s.sequence = *event.Value.ContinuationSequenceNumber
+	}
+	return false, stream.Err()
+}
+
+// Close stops the subscription and waits for it to exit.
+func (s *kinesisFanOutSubscription) Close() {
+	s.done()
+	<-s.closed
+}
@@ -16,9 +16,14 @@ package aws
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
 func TestStreamIDParser(t *testing.T) {
@@ -78,3 +83,49 @@ func TestStreamIDParser(t *testing.T) {
 		})
 	}
 }
+
+func TestKinesisInputEnhancedFanOutConfig(t *testing.T) {
+	pConf, err := kinesisInputSpec().ParseYAML(`
+streams: [ foo ]
+dynamodb:
+  table: bar
+enhanced_fan_out:
+  enabled: true
+`, nil)
+	require.NoError(t, err)
+
+	r, err := newKinesisReaderFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	assert.True(t, r.conf.EnhancedFanOut.Enabled)
+	assert.Equal(t, "bar", r.conf.EnhancedFanOut.ConsumerName)
+
+	pConf, err = kinesisInputSpec().ParseYAML(`
+streams: [ foo, baz ]
+dynamodb:
+  table: bar
+enhanced_fan_out:
+  enabled: true
+  consumer_arn: arn:aws:kinesis:us-east-1:111122223333:stream/foo/consumer/bar:1625176821
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newKinesisReaderFromParsed(pConf, service.MockResources())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "single stream")
+}
+
+func TestKinesisShardsAwaitingParents(t *testing.T) {
+	shards := []types.Shard{
+		{ShardId: aws.String("parent-a")},
+		{ShardId: aws.String("parent-b")},
+		{ShardId: aws.String("child-a"), ParentShardId: aws.String("parent-a")},
+		{ShardId: aws.String("child-b"), ParentShardId: aws.String("parent-b")},
+		{ShardId: aws.String("merged"), ParentShardId: aws.String("parent-c"), AdjacentParentShardId: aws.String("parent-a")},
+		{ShardId: aws.String("orphan"), ParentShardId: aws.String("gone")},
+	}
+	claims := map[string][]awsKinesisClientClaim{
+		"client-a": {{ShardID: "parent-a", LeaseTimeout: time.Now()}},
+	}
+
+	assert.ElementsMatch(t, []string{"child-a", "merged"}, shardsAwaitingParents(shards, claims))
+}
@@ -16,10 +16,12 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -37,6 +39,8 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service/codec"
 
 	"github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+	"github.com/redpanda-data/connect/v4/internal/resume"
+	"github.com/redpanda-data/connect/v4/internal/shard"
 )
 
 const (
@@ -56,6 +60,20 @@ const (
 	s3iFieldForcePathStyleURLs = "force_path_style_urls"
 	s3iFieldDeleteObjects      = "delete_objects"
 	s3iFieldSQS                = "sqs"
+	s3iFieldPoll               = "poll"
+	s3iFieldShard              = "shard"
+
+	// S3 Input Poll Fields
+	s3iPollFieldEnabled   = "enabled"
+	s3iPollFieldInterval  = "interval"
+	s3iPollFieldCache     = "cache"
+	s3iPollFieldCacheKey  = "cache_key"
+	s3iPollFieldWatermark = "watermark"
+)
+
+const (
+	s3iPollWatermarkKey          = "key"
+	s3iPollWatermarkLastModified = "last_modified"
 )
 
 type s3iSQSConfig struct {
@@ -97,22 +115,55 @@ func s3iSQSConfigFromParsed(pConf *service.ParsedConfig) (conf s3iSQSConfig, err
 	return
 }
 
+type s3iPollConfig struct {
+	Enabled   bool
+	Interval  time.Duration
+	Cache     string
+	CacheKey  string
+	Watermark string
+}
+
+func s3iPollConfigFromParsed(pConf *service.ParsedConfig) (conf s3iPollConfig, err error) {
+	if conf.Enabled, err = pConf.FieldBool(s3iPollFieldEnabled); err != nil || !conf.Enabled {
+		return
+	}
+	if conf.Interval, err = pConf.FieldDuration(s3iPollFieldInterval); err != nil {
+		return
+	}
+	if conf.Cache, err = pConf.FieldString(s3iPollFieldCache); err != nil {
+		return
+	}
+	if conf.CacheKey, err = pConf.FieldString(s3iPollFieldCacheKey); err != nil {
+		return
+	}
+	if conf.Watermark, err = pConf.FieldString(s3iPollFieldWatermark); err != nil {
+		return
+	}
+	return
+}
+
 type s3iConfig struct {
 	Bucket             string
 	Prefix             string
 	ForcePathStyleURLs bool
 	DeleteObjects      bool
 	SQS                s3iSQSConfig
+	Poll               s3iPollConfig
+	Checkpoint         *resume.Store
+	Shard              *shard.Group
 	CodecCtor          codec.DeprecatedFallbackCodec
 }
 
-func s3iConfigFromParsed(pConf *service.ParsedConfig) (conf s3iConfig, err error) {
+func s3iConfigFromParsed(pConf *service.ParsedConfig, res *service.Resources) (conf s3iConfig, err error) {
 	if conf.Bucket, err = pConf.FieldString(s3iFieldBucket); err != nil {
 		return
 	}
 	if conf.Prefix, err = pConf.FieldString(s3iFieldPrefix); err != nil {
 		return
 	}
+	if conf.Checkpoint, err = resume.StoreFromParsed(pConf, res, "aws_s3_checkpoint:"+conf.Bucket+"/"+conf.Prefix); err != nil {
+		return
+	}
 	if conf.CodecCtor, err = codec.DeprecatedCodecFromParsed(pConf); err != nil {
 		return
 	}
@@ -127,6 +178,22 @@ func s3iConfigFromParsed(pConf *service.ParsedConfig) (conf s3iConfig, err error
 			return
 		}
 	}
+	if pConf.Contains(s3iFieldPoll) {
+		if conf.Poll, err = s3iPollConfigFromParsed(pConf.Namespace(s3iFieldPoll)); err != nil {
+			return
+		}
+	}
+	if pConf.Contains(s3iFieldShard) {
+		// Progress is tracked per instance, which would skip the keys that
+		// move to an instance when the members of the group change.
+		if conf.SQS.URL != "" || conf.Poll.Enabled || conf.Checkpoint != nil {
+			err = errors.New("shard can only be used when listing a bucket without sqs.url, poll.enabled or checkpoint")
+			return
+		}
+		if conf.Shard, err = shard.NewGroupFromParsed(pConf.Namespace(s3iFieldShard), res); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -146,6 +213,16 @@ If your notification events are being routed to SQS via an SNS topic then the ev
 
 When using SQS please make sure you have sensible values for `+"`sqs.max_messages`"+` and also the visibility timeout of the queue itself. When Redpanda Connect consumes an S3 object the SQS message that triggered it is not deleted until the S3 object has been sent onwards. This ensures at-least-once crash resiliency, but also means that if the S3 object takes longer to process than the visibility timeout of your queue then the same objects might be processed multiple times.
 
+== Poll for new objects without SQS
+
+For buckets where upload notifications cannot be routed to SQS it's possible to enable `+"`poll.enabled`"+`, in which case the input continuously lists the bucket (and prefix) and consumes objects it has not yet seen. Rather than remembering every key consumed, progress is tracked as a single watermark stored within a xref:components:caches/about.adoc[cache resource] specified with `+"`poll.cache`"+`, and the watermark only advances once all objects before it have been delivered, which allows the input to resume from where it left off after a restart.
+
+With the `+"`key`"+` watermark (the default) objects are consumed in lexicographical order of their keys, and only keys that sort after the watermark are considered, which makes each poll cheap. This suits buckets where new keys always sort after old ones, such as keys that begin with a timestamp or date partition. With the `+"`last_modified`"+` watermark the whole prefix is listed on each poll and objects are consumed in order of their last modified time, which suits arbitrary key layouts at the cost of more expensive listings. Since S3 reports modification times with second precision objects uploaded within the same second as the watermark but sorting before its key may be missed.
+
+== Resume a bucket scan
+
+Without SQS or polling the input lists the bucket (and prefix) once and shuts down after consuming every object. By default a restarted scan starts from the beginning of the bucket, but when a `+"`checkpoint.cache`"+` is configured the key of the latest object for which all prior objects have been delivered is stored within the cache, and a restarted scan resumes after it. Once the scan completes the checkpoint remains, and so restarting the input afterwards only consumes objects with keys that sort after the last object consumed.
+
 == Download large files
 
 When downloading large files it's often necessary to process it in streamed parts in order to avoid loading the entire file in memory at a given time. In order to do this a `+"<<scanner, `scanner`>>"+` can be specified that determines how to break the input into smaller individual messages.
@@ -224,13 +301,45 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 			).
 				Description("Consume SQS messages in order to trigger key downloads.").
 				Optional(),
+			service.NewObjectField(s3iFieldPoll,
+				service.NewBoolField(s3iPollFieldEnabled).
+					Description("Whether polling the bucket for new objects is enabled.").
+					Default(false),
+				service.NewDurationField(s3iPollFieldInterval).
+					Description("The period of time to wait before listing the bucket again once no new objects are found.").
+					Default("1m").
+					Examples("10s", "5m"),
+				service.NewStringField(s3iPollFieldCache).
+					Description("A xref:components:caches/about.adoc[cache resource] for storing the watermark of consumed objects.").
+					Default(""),
+				service.NewStringField(s3iPollFieldCacheKey).
+					Description("The key under which the watermark is stored within the cache. When empty a key is derived from the bucket and prefix.").
+					Default("").
+					Advanced(),
+				service.NewStringAnnotatedEnumField(s3iPollFieldWatermark, map[string]string{
+					s3iPollWatermarkKey:          "Consume objects in lexicographical order of their keys, only listing keys that sort after the watermark.",
+					s3iPollWatermarkLastModified: "Consume objects in order of their last modified time, listing the entire prefix on each poll.",
+				}).
+					Description("The property of objects used to track which objects have been consumed.").
+					Default(s3iPollWatermarkKey),
+			).
+				Description("Continuously poll the bucket for new objects, tracking progress with a watermark stored within a cache resource. This allows consuming new objects without SQS notifications.").
+				Version("4.62.0").
+				Optional(),
+			resume.CheckpointField().
+				Version("4.62.0"),
+			service.NewObjectField(s3iFieldShard, shard.GroupFields()...).
+				Description("Partition the objects of a bucket scan amongst the instances of a group tracked within a cache resource, where each instance only downloads the objects with keys that it owns. While the members of the group are changing an instance waits for them to settle before skipping an object that it doesn't own. The objects owned by an instance that leaves the group while scanning are skipped by the other instances that already listed them, and so a scan should be repeated when instances leave before it completes.").
+				Version("4.62.0").
+				Optional().
+				Advanced(),
 		)
 }
 
 func init() {
 	service.MustRegisterBatchInput("aws_s3", s3InputSpec(),
 		func(pConf *service.ParsedConfig, res *service.Resources) (service.BatchInput, error) {
-			conf, err := s3iConfigFromParsed(pConf)
+			conf, err := s3iConfigFromParsed(pConf, res)
 			if err != nil {
 				return nil, err
 			}
@@ -311,6 +420,7 @@ type staticTargetReader struct {
 	s3         *s3.Client
 	conf       s3iConfig
 	startAfter *string
+	tracker    *resume.Tracker[string]
 }
 
 func newStaticTargetReader(
@@ -318,65 +428,322 @@ func newStaticTargetReader(
 	conf s3iConfig,
 	s3Client *s3.Client,
 ) (*staticTargetReader, error) {
+	staticKeys := staticTargetReader{
+		s3:   s3Client,
+		conf: conf,
+	}
+	if conf.Checkpoint != nil {
+		// Objects are listed in lexicographical order of their keys, and so
+		// the key of the latest acknowledged object marks our progress.
+		lastKey, err := conf.Checkpoint.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if lastKey != nil {
+			staticKeys.startAfter = aws.String(string(lastKey))
+		}
+		staticKeys.tracker = resume.NewTracker(conf.Checkpoint, 1024, func(key string) ([]byte, error) {
+			return []byte(key), nil
+		})
+	}
+	if err := staticKeys.list(ctx); err != nil {
+		return nil, err
+	}
+	return &staticKeys, nil
+}
+
+func (s *staticTargetReader) list(ctx context.Context) error {
 	maxKeys := int32(100)
 	listInput := &s3.ListObjectsV2Input{
-		Bucket:  &conf.Bucket,
-		MaxKeys: &maxKeys,
+		Bucket:     &s.conf.Bucket,
+		MaxKeys:    &maxKeys,
+		StartAfter: s.startAfter,
 	}
-	if conf.Prefix != "" {
-		listInput.Prefix = &conf.Prefix
+	if s.conf.Prefix != "" {
+		listInput.Prefix = &s.conf.Prefix
 	}
-	output, err := s3Client.ListObjectsV2(ctx, listInput)
+	output, err := s.s3.ListObjectsV2(ctx, listInput)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %v", err)
-	}
-	staticKeys := staticTargetReader{
-		s3:   s3Client,
-		conf: conf,
+		return fmt.Errorf("failed to list objects: %v", err)
 	}
 	for _, obj := range output.Contents {
-		ackFn := deleteS3ObjectAckFn(s3Client, conf.Bucket, *obj.Key, conf.DeleteObjects, nil)
-		staticKeys.pending = append(staticKeys.pending, newS3ObjectTarget(*obj.Key, conf.Bucket, time.Time{}, ackFn))
+		s.pending = append(s.pending, newS3ObjectTarget(*obj.Key, s.conf.Bucket, time.Time{}, nil))
 	}
 	if len(output.Contents) > 0 {
-		staticKeys.startAfter = output.Contents[len(output.Contents)-1].Key
+		s.startAfter = output.Contents[len(output.Contents)-1].Key
+	} else {
+		s.startAfter = nil
 	}
-	return &staticKeys, nil
+	return nil
 }
 
 func (s *staticTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
-	maxKeys := int32(100)
-	if len(s.pending) == 0 && s.startAfter != nil {
-		s.pending = nil
-		listInput := &s3.ListObjectsV2Input{
-			Bucket:     &s.conf.Bucket,
-			MaxKeys:    &maxKeys,
-			StartAfter: s.startAfter,
+	var obj *s3ObjectTarget
+	for obj == nil {
+		if len(s.pending) == 0 && s.startAfter != nil {
+			if err := s.list(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if len(s.pending) == 0 {
+			return nil, io.EOF
 		}
-		if s.conf.Prefix != "" {
-			listInput.Prefix = &s.conf.Prefix
+		obj = s.pending[0]
+
+		// Objects owned by other instances are skipped without downloading
+		// them, and as they're never consumed they aren't acknowledged.
+		if s.conf.Shard != nil {
+			owned, err := s.conf.Shard.OwnsSettled(ctx, obj.key)
+			if err != nil {
+				return nil, err
+			}
+			if !owned {
+				s.pending, obj = s.pending[1:], nil
+			}
 		}
-		output, err := s.s3.ListObjectsV2(ctx, listInput)
+	}
+
+	var trackFn service.AckFunc
+	if s.tracker != nil {
+		var err error
+		if trackFn, err = s.tracker.Track(ctx, obj.key); err != nil {
+			return nil, err
+		}
+	}
+	obj.ackFn = deleteS3ObjectAckFn(s.s3, s.conf.Bucket, obj.key, s.conf.DeleteObjects, trackFn)
+
+	s.pending = s.pending[1:]
+	return obj, nil
+}
+
+func (staticTargetReader) Close(context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// s3Watermark marks the position of an object within the order that a
+// pollTargetReader consumes them.
+type s3Watermark struct {
+	Key          string    `json:"key"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// before returns true if the watermark sorts before an object with the given
+// key and last modified time.
+func (w s3Watermark) before(mode, key string, lastModified time.Time) bool {
+	if mode == s3iPollWatermarkLastModified && !lastModified.Equal(w.LastModified) {
+		return lastModified.After(w.LastModified)
+	}
+	return key > w.Key
+}
+
+type pollInFlight struct {
+	mark s3Watermark
+	done bool
+}
+
+type pollTargetReader struct {
+	conf  s3iConfig
+	log   *service.Logger
+	store *resume.Store
+	s3    *s3.Client
+
+	// The position of the latest object listed, which runs ahead of the
+	// watermark committed to the cache.
+	cursor   s3Watermark
+	nextPoll time.Time
+	pending  []*s3ObjectTarget
+
+	ackMut   sync.Mutex
+	inFlight []*pollInFlight
+	retries  []*s3ObjectTarget
+}
+
+func newPollTargetReader(
+	ctx context.Context,
+	conf s3iConfig,
+	log *service.Logger,
+	res *service.Resources,
+	s3Client *s3.Client,
+) (*pollTargetReader, error) {
+	p := &pollTargetReader{
+		conf:  conf,
+		log:   log,
+		store: resume.NewStore(res, conf.Poll.Cache, conf.Poll.CacheKey),
+		s3:    s3Client,
+	}
+
+	mark, err := p.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if mark == nil {
+		return p, nil
+	}
+	if err := json.Unmarshal(mark, &p.cursor); err != nil {
+		return nil, fmt.Errorf("failed to parse watermark: %w", err)
+	}
+	return p, nil
+}
+
+func (p *pollTargetReader) listKeys(ctx context.Context) ([]s3types.Object, bool, error) {
+	listInput := &s3.ListObjectsV2Input{
+		Bucket: &p.conf.Bucket,
+	}
+	if p.conf.Prefix != "" {
+		listInput.Prefix = &p.conf.Prefix
+	}
+	if p.cursor.Key != "" {
+		listInput.StartAfter = &p.cursor.Key
+	}
+	output, err := p.s3.ListObjectsV2(ctx, listInput)
+	if err != nil {
+		return nil, false, err
+	}
+	return output.Contents, !aws.ToBool(output.IsTruncated), nil
+}
+
+func (p *pollTargetReader) listModified(ctx context.Context) ([]s3types.Object, bool, error) {
+	listInput := &s3.ListObjectsV2Input{
+		Bucket: &p.conf.Bucket,
+	}
+	if p.conf.Prefix != "" {
+		listInput.Prefix = &p.conf.Prefix
+	}
+
+	var objects []s3types.Object
+	paginator := s3.NewListObjectsV2Paginator(p.s3, listInput)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list objects: %v", err)
+			return nil, false, err
 		}
 		for _, obj := range output.Contents {
-			ackFn := deleteS3ObjectAckFn(s.s3, s.conf.Bucket, *obj.Key, s.conf.DeleteObjects, nil)
-			s.pending = append(s.pending, newS3ObjectTarget(*obj.Key, s.conf.Bucket, time.Time{}, ackFn))
+			if p.cursor.before(s3iPollWatermarkLastModified, aws.ToString(obj.Key), aws.ToTime(obj.LastModified)) {
+				objects = append(objects, obj)
+			}
 		}
-		if len(output.Contents) > 0 {
-			s.startAfter = output.Contents[len(output.Contents)-1].Key
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		iTime, jTime := aws.ToTime(objects[i].LastModified), aws.ToTime(objects[j].LastModified)
+		if !iTime.Equal(jTime) {
+			return iTime.Before(jTime)
 		}
+		return aws.ToString(objects[i].Key) < aws.ToString(objects[j].Key)
+	})
+	return objects, true, nil
+}
+
+func (p *pollTargetReader) poll(ctx context.Context) error {
+	var objects []s3types.Object
+	var caughtUp bool
+	var err error
+	if p.conf.Poll.Watermark == s3iPollWatermarkLastModified {
+		objects, caughtUp, err = p.listModified(ctx)
+	} else {
+		objects, caughtUp, err = p.listKeys(ctx)
 	}
-	if len(s.pending) == 0 {
-		return nil, io.EOF
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
 	}
-	obj := s.pending[0]
-	s.pending = s.pending[1:]
-	return obj, nil
+	if caughtUp {
+		p.nextPoll = time.Now().Add(p.conf.Poll.Interval)
+	}
+	for _, obj := range objects {
+		p.pending = append(p.pending, p.newTarget(obj))
+	}
+	return nil
 }
 
-func (staticTargetReader) Close(context.Context) error {
+func (p *pollTargetReader) newTarget(obj s3types.Object) *s3ObjectTarget {
+	mark := s3Watermark{
+		Key:          aws.ToString(obj.Key),
+		LastModified: aws.ToTime(obj.LastModified),
+	}
+	p.cursor = mark
+
+	entry := &pollInFlight{mark: mark}
+	p.ackMut.Lock()
+	p.inFlight = append(p.inFlight, entry)
+	p.ackMut.Unlock()
+
+	target := newS3ObjectTarget(mark.Key, p.conf.Bucket, time.Time{}, nil)
+	target.ackFn = deleteS3ObjectAckFn(p.s3, p.conf.Bucket, mark.Key, p.conf.DeleteObjects, func(ctx context.Context, err error) error {
+		return p.ack(ctx, target, entry, err)
+	})
+	return target
+}
+
+// ack marks an object as consumed and commits the watermark of the latest
+// object for which all prior objects have also been consumed. Objects that
+// failed are queued to be attempted again, unless they no longer exist.
+func (p *pollTargetReader) ack(ctx context.Context, target *s3ObjectTarget, entry *pollInFlight, err error) error {
+	p.ackMut.Lock()
+	defer p.ackMut.Unlock()
+
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if !errors.As(err, &noSuchKey) {
+			p.retries = append(p.retries, target)
+			return nil
+		}
+		p.log.Debugf("Skipping object %v as it no longer exists", target.key)
+	}
+
+	entry.done = true
+
+	var commit *s3Watermark
+	for len(p.inFlight) > 0 && p.inFlight[0].done {
+		commit = &p.inFlight[0].mark
+		p.inFlight = p.inFlight[1:]
+	}
+	if commit == nil {
+		return nil
+	}
+
+	mark, err := json.Marshal(commit)
+	if err != nil {
+		return err
+	}
+	return p.store.Save(ctx, mark)
+}
+
+func (p *pollTargetReader) popRetry() *s3ObjectTarget {
+	p.ackMut.Lock()
+	defer p.ackMut.Unlock()
+	if len(p.retries) == 0 {
+		return nil
+	}
+	t := p.retries[0]
+	p.retries = p.retries[1:]
+	return t
+}
+
+func (p *pollTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
+	for {
+		if t := p.popRetry(); t != nil {
+			return t, nil
+		}
+		if len(p.pending) > 0 {
+			t := p.pending[0]
+			p.pending = p.pending[1:]
+			return t, nil
+		}
+		if wait := time.Until(p.nextPoll); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if err := p.poll(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (*pollTargetReader) Close(context.Context) error {
 	return nil
 }
 
@@ -667,6 +1034,7 @@ type awsS3Reader struct {
 	object    *s3PendingObject
 
 	log *service.Logger
+	res *service.Resources
 }
 
 type s3PendingObject struct {
@@ -684,10 +1052,28 @@ func newAmazonS3Reader(conf s3iConfig, awsConf aws.Config, nm *service.Resources
 	if conf.Prefix != "" && conf.SQS.URL != "" {
 		return nil, errors.New("cannot specify both a prefix and sqs.url")
 	}
+	if conf.Checkpoint != nil && (conf.SQS.URL != "" || conf.Poll.Enabled) {
+		return nil, errors.New("checkpoint can only be used when listing a bucket without sqs.url or poll.enabled")
+	}
+	if conf.Poll.Enabled {
+		if conf.SQS.URL != "" {
+			return nil, errors.New("cannot specify both poll.enabled and sqs.url")
+		}
+		if conf.Bucket == "" {
+			return nil, errors.New("a bucket must be specified when polling is enabled")
+		}
+		if !nm.HasCache(conf.Poll.Cache) {
+			return nil, fmt.Errorf("cache resource %q was not found", conf.Poll.Cache)
+		}
+		if conf.Poll.CacheKey == "" {
+			conf.Poll.CacheKey = "aws_s3_watermark:" + conf.Bucket + "/" + conf.Prefix
+		}
+	}
 	s := &awsS3Reader{
 		conf:              conf,
 		awsConf:           awsConf,
 		log:               nm.Logger(),
+		res:               nm,
 		objectScannerCtor: conf.CodecCtor,
 	}
 	if conf.SQS.DelayPeriod != "" {
@@ -703,6 +1089,9 @@ func (a *awsS3Reader) getTargetReader(ctx context.Context) (s3ObjectTargetReader
 	if a.sqs != nil {
 		return newSQSTargetReader(a.conf, a.log, a.s3, a.sqs), nil
 	}
+	if a.conf.Poll.Enabled {
+		return newPollTargetReader(ctx, a.conf, a.log, a.res, a.s3)
+	}
 	return newStaticTargetReader(ctx, a.conf, a.s3)
 }
 
@@ -874,5 +1263,10 @@ func (a *awsS3Reader) Close(ctx context.Context) (err error) {
 		err = a.object.scanner.Close(ctx)
 		a.object = nil
 	}
+	if a.conf.Shard != nil {
+		if serr := a.conf.Shard.Close(ctx); err == nil {
+			err = serr
+		}
+	}
 	return
 }
@@ -0,0 +1,467 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeS3Object struct {
+	body         string
+	lastModified time.Time
+}
+
+// fakeS3Bucket serves a single bucket supporting ListObjectsV2 (without
+// pagination) and GetObject requests.
+type fakeS3Bucket struct {
+	mut     sync.Mutex
+	objects map[string]fakeS3Object
+	lists   []string
+	gets    []string
+}
+
+func (f *fakeS3Bucket) put(key, body string, lastModified time.Time) {
+	f.mut.Lock()
+	f.objects[key] = fakeS3Object{body: body, lastModified: lastModified}
+	f.mut.Unlock()
+}
+
+func (f *fakeS3Bucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/foo/")
+	if r.URL.Query().Get("list-type") == "2" {
+		startAfter := r.URL.Query().Get("start-after")
+		f.lists = append(f.lists, startAfter)
+
+		var keys []string
+		for k := range f.objects {
+			if k > startAfter {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		b.WriteString(`<ListBucketResult><Name>foo</Name><IsTruncated>false</IsTruncated>`)
+		for _, k := range keys {
+			fmt.Fprintf(&b, `<Contents><Key>%v</Key><LastModified>%v</LastModified><Size>%v</Size></Contents>`,
+				k, f.objects[k].lastModified.Format(time.RFC3339), len(f.objects[k].body))
+		}
+		b.WriteString(`</ListBucketResult>`)
+		_, _ = w.Write([]byte(b.String()))
+		return
+	}
+
+	f.gets = append(f.gets, key)
+	obj, exists := f.objects[key]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>nope</Message></Error>`))
+		return
+	}
+	w.Header().Set("Last-Modified", obj.lastModified.Format(http.TimeFormat))
+	_, _ = w.Write([]byte(obj.body))
+}
+
+func newTestS3PollReader(t *testing.T, bucket *fakeS3Bucket, watermark string, res *service.Resources) *awsS3Reader {
+	t.Helper()
+
+	server := httptest.NewServer(bucket)
+	t.Cleanup(server.Close)
+
+	pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+force_path_style_urls: true
+poll:
+  enabled: true
+  interval: 50ms
+  cache: foocache
+  cache_key: watermark
+  watermark: `+watermark+`
+region: us-east-1
+endpoint: `+server.URL+`
+credentials:
+  id: xxx
+  secret: yyy
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := s3iConfigFromParsed(pConf, res)
+	require.NoError(t, err)
+
+	sess, err := GetSession(t.Context(), pConf)
+	require.NoError(t, err)
+
+	r, err := newAmazonS3Reader(conf, sess, res)
+	require.NoError(t, err)
+	require.NoError(t, r.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = r.Close(context.Background())
+	})
+	return r
+}
+
+func readS3Watermark(t *testing.T, res *service.Resources) (mark s3Watermark) {
+	t.Helper()
+
+	var b []byte
+	var getErr error
+	require.NoError(t, res.AccessCache(t.Context(), "foocache", func(c service.Cache) {
+		b, getErr = c.Get(t.Context(), "watermark")
+	}))
+	require.NoError(t, getErr)
+	require.NoError(t, json.Unmarshal(b, &mark))
+	return
+}
+
+func assertS3Watermark(t *testing.T, res *service.Resources, key string) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		return readS3Watermark(t, res).Key == key
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestS3InputPollConfig(t *testing.T) {
+	pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+poll:
+  enabled: true
+  cache: nope
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := s3iConfigFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, conf.Poll.Interval)
+	assert.Equal(t, s3iPollWatermarkKey, conf.Poll.Watermark)
+
+	_, err = newAmazonS3Reader(conf, aws.Config{}, service.MockResources())
+	require.ErrorContains(t, err, `cache resource "nope" was not found`)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("nope"))
+	r, err := newAmazonS3Reader(conf, aws.Config{}, res)
+	require.NoError(t, err)
+	assert.Equal(t, "aws_s3_watermark:foo/", r.conf.Poll.CacheKey)
+}
+
+func TestS3InputPollKeyWatermark(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	bucket := &fakeS3Bucket{objects: map[string]fakeS3Object{}}
+	bucket.put("a", "from a", now)
+	bucket.put("b", "from b", now)
+	bucket.put("c", "from c", now)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+	require.NoError(t, res.AccessCache(t.Context(), "foocache", func(c service.Cache) {
+		require.NoError(t, c.Set(t.Context(), "watermark", []byte(`{"key":"a"}`), nil))
+	}))
+
+	r := newTestS3PollReader(t, bucket, "key", res)
+
+	readNext := func() (string, service.AckFunc) {
+		t.Helper()
+		batch, ackFn, err := r.ReadBatch(t.Context())
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		return string(b), ackFn
+	}
+
+	body, bAck := readNext()
+	assert.Equal(t, "from b", body)
+	body, cAck := readNext()
+	assert.Equal(t, "from c", body)
+
+	// Acking out of order must not advance the watermark past unacked objects.
+	require.NoError(t, cAck(t.Context(), nil))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "a", readS3Watermark(t, res).Key)
+	require.NoError(t, bAck(t.Context(), nil))
+	assertS3Watermark(t, res, "b")
+
+	// Objects are only consumed once fully read, which for c happens when
+	// moving onto d.
+	bucket.put("d", "from d", now)
+	body, dAck := readNext()
+	assert.Equal(t, "from d", body)
+	require.NoError(t, dAck(t.Context(), nil))
+	assertS3Watermark(t, res, "c")
+
+	bucket.mut.Lock()
+	assert.Equal(t, []string{"a", "c"}, bucket.lists)
+	bucket.mut.Unlock()
+}
+
+func TestS3InputPollLastModifiedWatermark(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	bucket := &fakeS3Bucket{objects: map[string]fakeS3Object{}}
+	bucket.put("z", "from z", now.Add(-time.Hour))
+	bucket.put("y", "from y", now.Add(-time.Minute))
+
+	res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+	r := newTestS3PollReader(t, bucket, "last_modified", res)
+
+	var bodies []string
+	for range 2 {
+		batch, ackFn, err := r.ReadBatch(t.Context())
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+		require.NoError(t, ackFn(t.Context(), nil))
+	}
+	assert.Equal(t, []string{"from z", "from y"}, bodies)
+
+	// Objects modified before the watermark are ignored regardless of key.
+	bucket.put("a", "from a", now.Add(-2*time.Hour))
+	bucket.put("x", "from x", now)
+
+	batch, _, err := r.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "from x", string(b))
+
+	assertS3Watermark(t, res, "y")
+}
+
+func TestS3InputCheckpointResume(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	bucket := &fakeS3Bucket{objects: map[string]fakeS3Object{}}
+	bucket.put("a", "from a", now)
+	bucket.put("b", "from b", now)
+	bucket.put("c", "from c", now)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+	require.NoError(t, res.AccessCache(t.Context(), "foocache", func(c service.Cache) {
+		require.NoError(t, c.Set(t.Context(), "aws_s3_checkpoint:foo/", []byte("a"), nil))
+	}))
+
+	server := httptest.NewServer(bucket)
+	t.Cleanup(server.Close)
+
+	pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+force_path_style_urls: true
+checkpoint:
+  cache: foocache
+region: us-east-1
+endpoint: `+server.URL+`
+credentials:
+  id: xxx
+  secret: yyy
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := s3iConfigFromParsed(pConf, res)
+	require.NoError(t, err)
+
+	sess, err := GetSession(t.Context(), pConf)
+	require.NoError(t, err)
+
+	r, err := newAmazonS3Reader(conf, sess, res)
+	require.NoError(t, err)
+	require.NoError(t, r.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = r.Close(context.Background())
+	})
+
+	readCheckpoint := func() string {
+		var b []byte
+		require.NoError(t, res.AccessCache(t.Context(), "foocache", func(c service.Cache) {
+			b, _ = c.Get(t.Context(), "aws_s3_checkpoint:foo/")
+		}))
+		return string(b)
+	}
+
+	// The scan resumes after the stored key.
+	var bodies []string
+	for range 2 {
+		batch, ackFn, err := r.ReadBatch(t.Context())
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+		require.NoError(t, ackFn(t.Context(), nil))
+	}
+	assert.Equal(t, []string{"from b", "from c"}, bodies)
+	assert.Eventually(t, func() bool {
+		return readCheckpoint() == "b"
+	}, time.Second, 5*time.Millisecond)
+
+	_, _, err = r.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+	assert.Eventually(t, func() bool {
+		return readCheckpoint() == "c"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestS3InputCheckpointConfig(t *testing.T) {
+	pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+checkpoint:
+  cache: nope
+`, nil)
+	require.NoError(t, err)
+
+	_, err = s3iConfigFromParsed(pConf, service.MockResources())
+	require.ErrorContains(t, err, "cache resource 'nope' was not found")
+
+	pConf, err = s3InputSpec().ParseYAML(`
+bucket: foo
+poll:
+  enabled: true
+  cache: nope
+checkpoint:
+  cache: nope
+`, nil)
+	require.NoError(t, err)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("nope"))
+	conf, err := s3iConfigFromParsed(pConf, res)
+	require.NoError(t, err)
+	_, err = newAmazonS3Reader(conf, aws.Config{}, res)
+	require.ErrorContains(t, err, "checkpoint can only be used")
+}
+
+func TestS3InputShard(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	bucket := &fakeS3Bucket{objects: map[string]fakeS3Object{}}
+	for i := range 20 {
+		key := fmt.Sprintf("obj%02d", i)
+		bucket.put(key, "from "+key, now)
+	}
+
+	server := httptest.NewServer(bucket)
+	t.Cleanup(server.Close)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("members"))
+
+	newReader := func(identity string) *awsS3Reader {
+		pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+force_path_style_urls: true
+shard:
+  cache: members
+  group: foo
+  identity: `+identity+`
+  heartbeat_interval: 20ms
+  member_ttl: 1s
+region: us-east-1
+endpoint: `+server.URL+`
+credentials:
+  id: xxx
+  secret: yyy
+`, nil)
+		require.NoError(t, err)
+
+		conf, err := s3iConfigFromParsed(pConf, res)
+		require.NoError(t, err)
+
+		sess, err := GetSession(t.Context(), pConf)
+		require.NoError(t, err)
+
+		r, err := newAmazonS3Reader(conf, sess, res)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = r.Close(context.Background())
+		})
+		return r
+	}
+	readers := []*awsS3Reader{newReader("a"), newReader("b")}
+
+	// Wait for both members to observe each other and settle before scanning.
+	for _, r := range readers {
+		require.Eventually(t, func() bool {
+			members, err := r.conf.Shard.Members()
+			if err != nil || len(members) != 2 {
+				return false
+			}
+			_, settled, err := r.conf.Shard.Owns(t.Context(), "")
+			return err == nil && settled
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	readerKeys := make([][]string, len(readers))
+	for i, r := range readers {
+		require.NoError(t, r.Connect(t.Context()))
+		for {
+			batch, ackFn, err := r.ReadBatch(t.Context())
+			if errors.Is(err, service.ErrEndOfInput) {
+				break
+			}
+			require.NoError(t, err)
+			for _, msg := range batch {
+				key, _ := msg.MetaGet("s3_key")
+				readerKeys[i] = append(readerKeys[i], key)
+			}
+			require.NoError(t, ackFn(t.Context(), nil))
+		}
+	}
+
+	// Each object is read by exactly one instance, and only downloaded by it.
+	assert.NotEmpty(t, readerKeys[0])
+	assert.NotEmpty(t, readerKeys[1])
+	all := append(append([]string{}, readerKeys[0]...), readerKeys[1]...)
+	sort.Strings(all)
+	require.Len(t, all, 20)
+	for i, key := range all {
+		assert.Equal(t, fmt.Sprintf("obj%02d", i), key)
+	}
+
+	bucket.mut.Lock()
+	gets := append([]string{}, bucket.gets...)
+	bucket.mut.Unlock()
+	sort.Strings(gets)
+	assert.Equal(t, all, gets)
+}
+
+func TestS3InputShardConfig(t *testing.T) {
+	pConf, err := s3InputSpec().ParseYAML(`
+bucket: foo
+poll:
+  enabled: true
+  cache: members
+shard:
+  cache: members
+  group: foo
+`, nil)
+	require.NoError(t, err)
+
+	_, err = s3iConfigFromParsed(pConf, service.MockResources(service.MockResourcesOptAddCache("members")))
+	require.ErrorContains(t, err, "shard can only be used")
+}
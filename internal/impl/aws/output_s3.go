@@ -28,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/dustin/go-humanize"
 
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
@@ -55,6 +56,11 @@ const (
 	s3oFieldKMSKeyID                = "kms_key_id"
 	s3oFieldServerSideEncryption    = "server_side_encryption"
 	s3oFieldObjectCannedACL         = "object_canned_acl"
+	s3oFieldMultipart               = "multipart"
+	s3oFieldMultipartPartSize       = "part_size"
+	s3oFieldMultipartConcurrency    = "concurrency"
+	s3oFieldMultipartMaxAttempts    = "max_attempts"
+	s3oFieldMultipartLeaveParts     = "leave_parts_on_error"
 	s3oFieldBatching                = "batching"
 )
 
@@ -83,6 +89,10 @@ type s3oConfig struct {
 	ServerSideEncryption    string
 	UsePathStyle            bool
 	ObjectCannedACL         types.ObjectCannedACL
+	PartSize                int64
+	Concurrency             int
+	MaxAttempts             int
+	LeavePartsOnError       bool
 
 	aconf aws.Config
 }
@@ -165,6 +175,34 @@ func s3oConfigFromParsed(pConf *service.ParsedConfig) (conf s3oConfig, err error
 		return
 	}
 
+	mConf := pConf.Namespace(s3oFieldMultipart)
+	var partSizeStr string
+	if partSizeStr, err = mConf.FieldString(s3oFieldMultipartPartSize); err != nil {
+		return
+	}
+	var partSize uint64
+	if partSize, err = humanize.ParseBytes(partSizeStr); err != nil {
+		err = fmt.Errorf("failed to parse %v.%v: %w", s3oFieldMultipart, s3oFieldMultipartPartSize, err)
+		return
+	}
+	if conf.PartSize = int64(partSize); conf.PartSize < manager.MinUploadPartSize {
+		err = fmt.Errorf("%v.%v must be at least %v", s3oFieldMultipart, s3oFieldMultipartPartSize, humanize.IBytes(uint64(manager.MinUploadPartSize)))
+		return
+	}
+	if conf.Concurrency, err = mConf.FieldInt(s3oFieldMultipartConcurrency); err != nil {
+		return
+	}
+	if conf.Concurrency < 1 {
+		err = fmt.Errorf("%v.%v must be greater than zero", s3oFieldMultipart, s3oFieldMultipartConcurrency)
+		return
+	}
+	if conf.MaxAttempts, err = mConf.FieldInt(s3oFieldMultipartMaxAttempts); err != nil {
+		return
+	}
+	if conf.LeavePartsOnError, err = mConf.FieldBool(s3oFieldMultipartLeaveParts); err != nil {
+		return
+	}
+
 	if conf.aconf, err = GetSession(context.TODO(), pConf); err != nil {
 		return
 	}
@@ -292,9 +330,9 @@ output:
 				Default("").
 				Advanced(),
 			service.NewStringEnumField(s3oFieldChecksumAlgorithm,
-				"CRC32", "CRC32C", "SHA1", "SHA256",
+				"CRC32", "CRC32C", "CRC64NVME", "SHA1", "SHA256",
 			).
-				Description("The algorithm used to create the checksum for each object.").
+				Description("The algorithm used to create the checksum for each object. The checksum is calculated while the object is uploaded and sent with the request, as a trailer when using HTTPS, and S3 rejects the object if it does not match the data received. For multipart uploads a checksum is sent for each part.").
 				Default("").
 				Advanced(),
 			service.NewStringField(s3oFieldServerSideEncryption).
@@ -322,6 +360,23 @@ output:
 				Description("The object canned ACL value.").
 				Default(string(types.ObjectCannedACLPrivate)).
 				Advanced(),
+			service.NewObjectField(s3oFieldMultipart,
+				service.NewStringField(s3oFieldMultipartPartSize).
+					Description("The size of the parts that objects are split into. Objects smaller than this are uploaded with a single request, larger objects are uploaded in parts with a multipart upload. The minimum is 5MiB.").
+					Default("5MiB"),
+				service.NewIntField(s3oFieldMultipartConcurrency).
+					Description("The number of parts of an object to upload in parallel.").
+					Default(5),
+				service.NewIntField(s3oFieldMultipartMaxAttempts).
+					Description("The maximum number of attempts to upload each part, or each object uploaded with a single request, before the upload as a whole is abandoned. Failed parts are retried individually without uploading the rest of the object again. Set to zero to use the retry settings of the AWS client.").
+					Default(0),
+				service.NewBoolField(s3oFieldMultipartLeaveParts).
+					Description("Whether to leave the parts that were uploaded successfully in the bucket when a multipart upload fails, rather than aborting the upload. Note that the parts of incomplete uploads are billed until they are removed, for example by a bucket lifecycle rule.").
+					Default(false),
+			).
+				Description("Options for uploading large objects in parts.").
+				Version("4.62.0").
+				Advanced(),
 			service.NewBatchPolicyField(s3oFieldBatching),
 		).
 		Fields(config.SessionFields()...)
@@ -367,7 +422,16 @@ func (a *amazonS3Writer) Connect(context.Context) error {
 	client := s3.NewFromConfig(a.conf.aconf, func(o *s3.Options) {
 		o.UsePathStyle = a.conf.UsePathStyle
 	})
-	a.uploader = manager.NewUploader(client)
+	a.uploader = manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = a.conf.PartSize
+		u.Concurrency = a.conf.Concurrency
+		u.LeavePartsOnError = a.conf.LeavePartsOnError
+		if a.conf.MaxAttempts > 0 {
+			u.ClientOptions = append(u.ClientOptions, func(o *s3.Options) {
+				o.RetryMaxAttempts = a.conf.MaxAttempts
+			})
+		}
+	})
 	return nil
 }
 
@@ -0,0 +1,156 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestS3OutputMultipartConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		conf   string
+		errMsg string
+	}{
+		{
+			name: "defaults",
+			conf: `bucket: foo
+path: bar`,
+		},
+		{
+			name: "part size too small",
+			conf: `bucket: foo
+path: bar
+multipart:
+  part_size: 1MiB`,
+			errMsg: "must be at least 5.0 MiB",
+		},
+		{
+			name: "invalid concurrency",
+			conf: `bucket: foo
+path: bar
+multipart:
+  concurrency: 0`,
+			errMsg: "must be greater than zero",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pConf, err := s3oOutputSpec().ParseYAML(test.conf, nil)
+			require.NoError(t, err)
+
+			conf, err := s3oConfigFromParsed(pConf)
+			if test.errMsg != "" {
+				require.ErrorContains(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(5*1024*1024), conf.PartSize)
+			assert.Equal(t, 5, conf.Concurrency)
+		})
+	}
+}
+
+func TestS3OutputMultipartPartRetry(t *testing.T) {
+	var mut sync.Mutex
+	partAttempts := map[string]int{}
+	var checksums []string
+	var completed bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		q := r.URL.Query()
+
+		mut.Lock()
+		defer mut.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><Bucket>foo</Bucket><Key>bar</Key><UploadId>upload1</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == http.MethodPut && q.Has("partNumber"):
+			part := q.Get("partNumber")
+			partAttempts[part]++
+			// Over plain HTTP the checksum is sent as a header rather than a
+			// trailer of the request.
+			checksum := r.Header.Get("X-Amz-Checksum-Crc32c")
+			if checksum == "" {
+				checksum = r.Header.Get("X-Amz-Trailer")
+			}
+			checksums = append(checksums, checksum)
+			if part == "2" && partAttempts[part] == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`<Error><Code>InternalError</Code><Message>nope</Message></Error>`))
+				return
+			}
+			w.Header().Set("ETag", `"etag`+part+`"`)
+		case r.Method == http.MethodPost && q.Has("uploadId"):
+			completed = true
+			_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><Bucket>foo</Bucket><Key>bar</Key><ETag>"final"</ETag></CompleteMultipartUploadResult>`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	pConf, err := s3oOutputSpec().ParseYAML(`
+bucket: foo
+path: bar
+force_path_style_urls: true
+checksum_algorithm: CRC32C
+timeout: 30s
+multipart:
+  part_size: 5MiB
+  concurrency: 1
+  max_attempts: 2
+region: us-east-1
+endpoint: `+server.URL+`
+credentials:
+  id: xxx
+  secret: yyy
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := s3oConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	w, err := newAmazonS3Writer(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, w.Connect(t.Context()))
+
+	body := bytes.Repeat([]byte("x"), 11*1024*1024)
+	require.NoError(t, w.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage(body),
+	}))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.True(t, completed)
+	assert.Equal(t, map[string]int{"1": 1, "2": 2, "3": 1}, partAttempts)
+	require.Len(t, checksums, 4)
+	for _, checksum := range checksums {
+		assert.NotEmpty(t, checksum)
+	}
+}
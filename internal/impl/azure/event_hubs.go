@@ -0,0 +1,131 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Common fields for Event Hubs components
+	ehFieldConnectionString = "connection_string"
+	ehFieldNamespace        = "namespace"
+	ehFieldEventHub         = "event_hub"
+)
+
+func eventHubsConnectionFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(ehFieldConnectionString).
+			Description("A connection string for the Event Hubs namespace or event hub. This field is required if `" + ehFieldNamespace + "` is not set.").
+			Example("Endpoint=sb://foo.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=bar").
+			Secret().
+			Default(""),
+		service.NewStringField(ehFieldNamespace).
+			Description("The fully qualified Event Hubs namespace to connect to via https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/sdk/azidentity#DefaultAzureCredential[DefaultAzureCredential^]. This field is ignored if `" + ehFieldConnectionString + "` is set.").
+			Example("foo.servicebus.windows.net").
+			Default(""),
+		service.NewStringField(ehFieldEventHub).
+			Description("The name of the event hub. This field is optional when the `" + ehFieldConnectionString + "` contains an `EntityPath`.").
+			Default(""),
+	}
+}
+
+type ehConnConfig struct {
+	ConnectionString string
+	Namespace        string
+	EventHub         string
+}
+
+func ehConnConfigFromParsed(pConf *service.ParsedConfig) (conf ehConnConfig, err error) {
+	if conf.ConnectionString, err = pConf.FieldString(ehFieldConnectionString); err != nil {
+		return
+	}
+	if conf.Namespace, err = pConf.FieldString(ehFieldNamespace); err != nil {
+		return
+	}
+	if conf.EventHub, err = pConf.FieldString(ehFieldEventHub); err != nil {
+		return
+	}
+	if conf.ConnectionString == "" && conf.Namespace == "" {
+		err = fmt.Errorf("either a %v or a %v must be specified", ehFieldConnectionString, ehFieldNamespace)
+		return
+	}
+	if conf.ConnectionString == "" && conf.EventHub == "" {
+		err = fmt.Errorf("an %v must be specified when connecting via a %v", ehFieldEventHub, ehFieldNamespace)
+	}
+	return
+}
+
+func (c ehConnConfig) consumerClient(consumerGroup string) (*azeventhubs.ConsumerClient, error) {
+	if c.ConnectionString != "" {
+		return azeventhubs.NewConsumerClientFromConnectionString(c.ConnectionString, c.EventHub, consumerGroup, nil)
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting default Azure credentials: %w", err)
+	}
+	return azeventhubs.NewConsumerClient(c.Namespace, c.EventHub, consumerGroup, cred, nil)
+}
+
+func (c ehConnConfig) producerClient() (*azeventhubs.ProducerClient, error) {
+	if c.ConnectionString != "" {
+		return azeventhubs.NewProducerClientFromConnectionString(c.ConnectionString, c.EventHub, nil)
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting default Azure credentials: %w", err)
+	}
+	return azeventhubs.NewProducerClient(c.Namespace, c.EventHub, cred, nil)
+}
+
+// eventHubsEventToMessage converts a received event into a message carrying
+// the event properties as metadata.
+func eventHubsEventToMessage(partitionID string, event *azeventhubs.ReceivedEventData) *service.Message {
+	msg := service.NewMessage(event.Body)
+	msg.MetaSetMut("event_hubs_partition_id", partitionID)
+	msg.MetaSetMut("event_hubs_sequence_number", event.SequenceNumber)
+	msg.MetaSetMut("event_hubs_offset", event.Offset)
+	if event.EnqueuedTime != nil {
+		msg.MetaSetMut("event_hubs_enqueued_time", event.EnqueuedTime.Format(time.RFC3339Nano))
+	}
+	if event.PartitionKey != nil {
+		msg.MetaSetMut("event_hubs_partition_key", *event.PartitionKey)
+	}
+	if event.ContentType != nil {
+		msg.MetaSetMut("event_hubs_content_type", *event.ContentType)
+	}
+	if event.MessageID != nil {
+		msg.MetaSetMut("event_hubs_message_id", *event.MessageID)
+	}
+	if id, ok := event.CorrelationID.(string); ok {
+		msg.MetaSetMut("event_hubs_correlation_id", id)
+	}
+	for k, v := range event.Properties {
+		msg.MetaSetMut(k, v)
+	}
+	return msg
+}
+
+func isEventHubsOwnershipLost(err error) bool {
+	var ehErr *azeventhubs.Error
+	return errors.As(err, &ehErr) && ehErr.Code == azeventhubs.ErrorCodeOwnershipLost
+}
@@ -0,0 +1,445 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2/checkpoints"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Jeffail/checkpoint"
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Event Hubs Input Fields
+	ehiFieldConsumerGroup   = "consumer_group"
+	ehiFieldCheckpointStore = "checkpoint_store"
+	ehiFieldContainer       = "container"
+	ehiFieldStartPosition   = "start_position"
+	ehiFieldLoadBalancing   = "load_balancing"
+	ehiFieldStrategy        = "strategy"
+	ehiFieldUpdateInterval  = "update_interval"
+	ehiFieldPartitionExpiry = "partition_expiration"
+	ehiFieldMaxBatchSize    = "max_batch_size"
+	ehiFieldMaxWaitTime     = "max_wait_time"
+	ehiFieldPrefetch        = "prefetch"
+	ehiFieldCheckpointLimit = "checkpoint_limit"
+)
+
+type ehiConfig struct {
+	Conn            ehConnConfig
+	ConsumerGroup   string
+	Container       *container.Client
+	StartPosition   azeventhubs.StartPosition
+	Strategy        azeventhubs.ProcessorStrategy
+	UpdateInterval  time.Duration
+	PartitionExpiry time.Duration
+	MaxBatchSize    int
+	MaxWaitTime     time.Duration
+	Prefetch        int
+	CheckpointLimit int
+}
+
+func ehiCheckpointStoreFromParsed(pConf *service.ParsedConfig) (*container.Client, error) {
+	containerName, err := pConf.FieldString(ehiFieldContainer)
+	if err != nil {
+		return nil, err
+	}
+	containerStr, err := service.NewInterpolatedString(containerName)
+	if err != nil {
+		return nil, err
+	}
+	client, containerSASToken, err := blobStorageClientFromParsed(pConf, containerStr)
+	if err != nil {
+		return nil, err
+	}
+	if containerSASToken {
+		// if using a container SAS token, the container is already implicit
+		containerName = ""
+	}
+	return client.ServiceClient().NewContainerClient(containerName), nil
+}
+
+func ehiConfigFromParsed(pConf *service.ParsedConfig) (conf ehiConfig, err error) {
+	if conf.Conn, err = ehConnConfigFromParsed(pConf); err != nil {
+		return
+	}
+	if conf.ConsumerGroup, err = pConf.FieldString(ehiFieldConsumerGroup); err != nil {
+		return
+	}
+	if conf.Container, err = ehiCheckpointStoreFromParsed(pConf.Namespace(ehiFieldCheckpointStore)); err != nil {
+		return
+	}
+
+	var startPosition string
+	if startPosition, err = pConf.FieldString(ehiFieldStartPosition); err != nil {
+		return
+	}
+	useLatest := startPosition == "latest"
+	useEarliest := !useLatest
+	conf.StartPosition = azeventhubs.StartPosition{
+		Earliest: &useEarliest,
+		Latest:   &useLatest,
+	}
+
+	lbConf := pConf.Namespace(ehiFieldLoadBalancing)
+	var strategy string
+	if strategy, err = lbConf.FieldString(ehiFieldStrategy); err != nil {
+		return
+	}
+	conf.Strategy = azeventhubs.ProcessorStrategy(strategy)
+	if conf.UpdateInterval, err = lbConf.FieldDuration(ehiFieldUpdateInterval); err != nil {
+		return
+	}
+	if conf.PartitionExpiry, err = lbConf.FieldDuration(ehiFieldPartitionExpiry); err != nil {
+		return
+	}
+
+	if conf.MaxBatchSize, err = pConf.FieldInt(ehiFieldMaxBatchSize); err != nil {
+		return
+	}
+	if conf.MaxBatchSize < 1 {
+		err = fmt.Errorf("%v must be greater than zero", ehiFieldMaxBatchSize)
+		return
+	}
+	if conf.MaxWaitTime, err = pConf.FieldDuration(ehiFieldMaxWaitTime); err != nil {
+		return
+	}
+	if conf.Prefetch, err = pConf.FieldInt(ehiFieldPrefetch); err != nil {
+		return
+	}
+	if conf.CheckpointLimit, err = pConf.FieldInt(ehiFieldCheckpointLimit); err != nil {
+		return
+	}
+	return
+}
+
+func ehiSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("Services", "Azure").
+		Beta().
+		Version("4.62.0").
+		Summary(`Consumes events from an Azure Event Hub using the native AMQP protocol, balancing partitions between consumers and checkpointing progress in Azure Blob Storage.`).
+		Description(`
+Partitions of the event hub are distributed between all consumers sharing the same consumer group and checkpoint store, where ownership of each partition is claimed by writing to blobs within the `+"`checkpoint_store.container`"+`. When consumers join or leave the group partitions are rebalanced according to the `+"`load_balancing.strategy`"+`.
+
+The checkpoint of a partition is only updated once all events up to it have been delivered, and new consumers resume from the latest checkpoint of each partition they claim. Partitions without a checkpoint are consumed from the `+"`start_position`"+`.
+
+This input is compatible with checkpoints written by the official Event Hubs SDKs, and can therefore take over from existing consumers of the same consumer group.
+
+== Replaying captured events
+
+Events that were archived with https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-capture-overview[Event Hubs Capture^] can be replayed by reading the capture files with an input such as `+"`azure_blob_storage`"+` combined with the `+"`azure_event_hubs_capture`"+` scanner, which yields messages with the same contents and metadata as this input.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+- event_hubs_partition_id
+- event_hubs_sequence_number
+- event_hubs_offset
+- event_hubs_enqueued_time
+- event_hubs_partition_key
+- event_hubs_content_type
+- event_hubs_message_id
+- event_hubs_correlation_id
+- All application properties of the event
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(eventHubsConnectionFields()...).
+		Fields(
+			service.NewStringField(ehiFieldConsumerGroup).
+				Description("The consumer group to consume as.").
+				Default(azeventhubs.DefaultConsumerGroup),
+			service.NewObjectField(ehiFieldCheckpointStore,
+				service.NewStringField(ehiFieldContainer).
+					Description("The name of the container in which checkpoints and partition ownership are stored."),
+				service.NewStringField(bscFieldStorageAccount).
+					Description("The storage account to access. This field is ignored if `"+bscFieldStorageConnectionString+"` is set.").
+					Default(""),
+				service.NewStringField(bscFieldStorageAccessKey).
+					Description("The storage account access key. This field is ignored if `"+bscFieldStorageConnectionString+"` is set.").
+					Secret().
+					Default(""),
+				service.NewStringField(bscFieldStorageSASToken).
+					Description("The storage account SAS token. This field is ignored if `"+bscFieldStorageConnectionString+"` or `"+bscFieldStorageAccessKey+"` are set.").
+					Secret().
+					Default(""),
+				service.NewStringField(bscFieldStorageConnectionString).
+					Description("A storage account connection string. This field is required if `"+bscFieldStorageAccount+"` is not set.").
+					Secret().
+					Default(""),
+			).Description("An Azure Blob Storage container used for storing checkpoints and partition ownership."),
+			service.NewStringAnnotatedEnumField(ehiFieldStartPosition, map[string]string{
+				"earliest": "Consume from the earliest event retained by the partition.",
+				"latest":   "Consume only events enqueued after the partition was claimed.",
+			}).
+				Description("Where to begin consuming partitions that have no checkpoint.").
+				Default("earliest"),
+			service.NewObjectField(ehiFieldLoadBalancing,
+				service.NewStringAnnotatedEnumField(ehiFieldStrategy, map[string]string{
+					string(azeventhubs.ProcessorStrategyBalanced): "Claim a single partition during each update interval until all consumers own an equal share, minimising partition swapping.",
+					string(azeventhubs.ProcessorStrategyGreedy):   "Claim as many partitions as needed to reach a fair share during each update interval, which speeds up startup at the cost of more partition swapping.",
+				}).
+					Description("The strategy used for distributing partitions between consumers.").
+					Default(string(azeventhubs.ProcessorStrategyBalanced)),
+				service.NewDurationField(ehiFieldUpdateInterval).
+					Description("How often to attempt claiming partitions and renewing ownership.").
+					Default("10s"),
+				service.NewDurationField(ehiFieldPartitionExpiry).
+					Description("The period of time after which a partition whose ownership was not renewed is considered unowned.").
+					Default("1m"),
+			).
+				Description("Controls how partitions are distributed between consumers.").
+				Advanced(),
+			service.NewIntField(ehiFieldMaxBatchSize).
+				Description("The maximum number of events to consume from a partition in a single batch.").
+				Default(100),
+			service.NewDurationField(ehiFieldMaxWaitTime).
+				Description("The maximum period of time to wait for a batch of events to fill before it is flushed.").
+				Default("1s"),
+			service.NewIntField(ehiFieldPrefetch).
+				Description("The number of events each partition attempts to buffer ahead of consumption. Set to a negative value in order to disable prefetching.").
+				Default(300).
+				Advanced(),
+			service.NewIntField(ehiFieldCheckpointLimit).
+				Description("The maximum number of events of a given partition that can be processed at a given time. Increasing this limit enables parallel processing of events within a partition, but checkpoints are still only updated in order.").
+				Default(1024).
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		LintRule(`root = if this.connection_string.or("") == "" && this.namespace.or("") == "" { [ "either a connection_string or a namespace must be specified" ] }`)
+}
+
+func init() {
+	service.MustRegisterBatchInput("azure_event_hubs", ehiSpec(),
+		func(pConf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			conf, err := ehiConfigFromParsed(pConf)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatchedToggled(pConf, newEventHubsInput(conf, mgr))
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type ehiAsyncBatch struct {
+	batch service.MessageBatch
+	ackFn service.AckFunc
+}
+
+type eventHubsInput struct {
+	conf ehiConfig
+	log  *service.Logger
+
+	connMut sync.Mutex
+	msgChan chan ehiAsyncBatch
+
+	shutSig *shutdown.Signaller
+}
+
+func newEventHubsInput(conf ehiConfig, mgr *service.Resources) *eventHubsInput {
+	return &eventHubsInput{
+		conf:    conf,
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+}
+
+func (e *eventHubsInput) Connect(ctx context.Context) error {
+	e.connMut.Lock()
+	defer e.connMut.Unlock()
+	if e.msgChan != nil {
+		return nil
+	}
+
+	consumer, err := e.conf.Conn.consumerClient(e.conf.ConsumerGroup)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer client: %w", err)
+	}
+	store, err := checkpoints.NewBlobStore(e.conf.Container, nil)
+	if err != nil {
+		_ = consumer.Close(ctx)
+		return fmt.Errorf("failed to create checkpoint store: %w", err)
+	}
+	processor, err := azeventhubs.NewProcessor(consumer, store, &azeventhubs.ProcessorOptions{
+		LoadBalancingStrategy:       e.conf.Strategy,
+		UpdateInterval:              e.conf.UpdateInterval,
+		PartitionExpirationDuration: e.conf.PartitionExpiry,
+		StartPositions: azeventhubs.StartPositions{
+			Default: e.conf.StartPosition,
+		},
+		Prefetch: int32(e.conf.Prefetch),
+	})
+	if err != nil {
+		_ = consumer.Close(ctx)
+		return fmt.Errorf("failed to create processor: %w", err)
+	}
+
+	msgChan := make(chan ehiAsyncBatch)
+	runCtx, cancel := e.shutSig.SoftStopCtx(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			partition := processor.NextPartitionClient(runCtx)
+			if partition == nil {
+				return
+			}
+			e.log.Debugf("Claimed ownership of partition %v", partition.PartitionID())
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e.consumePartition(runCtx, partition, msgChan)
+			}()
+		}
+	}()
+
+	go func() {
+		if err := processor.Run(runCtx); err != nil && runCtx.Err() == nil {
+			e.log.Errorf("Event Hubs processor stopped: %v", err)
+		}
+		cancel()
+		wg.Wait()
+		_ = consumer.Close(context.Background())
+
+		e.connMut.Lock()
+		close(msgChan)
+		e.msgChan = nil
+		e.connMut.Unlock()
+
+		if e.shutSig.IsSoftStopSignalled() {
+			e.shutSig.TriggerHasStopped()
+		}
+	}()
+
+	e.msgChan = msgChan
+	return nil
+}
+
+func (e *eventHubsInput) consumePartition(ctx context.Context, partition *azeventhubs.ProcessorPartitionClient, msgChan chan<- ehiAsyncBatch) {
+	defer func() {
+		_ = partition.Close(context.Background())
+	}()
+
+	partitionID := partition.PartitionID()
+	checkpointer := checkpoint.NewCapped[*azeventhubs.ReceivedEventData](int64(e.conf.CheckpointLimit))
+
+	// Serialises checkpoint updates so that an older checkpoint never
+	// overwrites a newer one.
+	var commitMut sync.Mutex
+
+	for {
+		receiveCtx, done := context.WithTimeout(ctx, e.conf.MaxWaitTime)
+		events, err := partition.ReceiveEvents(receiveCtx, e.conf.MaxBatchSize, nil)
+		done()
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			if ctx.Err() != nil {
+				return
+			}
+			if isEventHubsOwnershipLost(err) {
+				e.log.Debugf("Lost ownership of partition %v", partitionID)
+				return
+			}
+			// Closing the partition client allows the processor to claim it
+			// again during its next update.
+			e.log.Errorf("Failed to receive events from partition %v: %v", partitionID, err)
+			return
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		batch := make(service.MessageBatch, len(events))
+		for i, event := range events {
+			batch[i] = eventHubsEventToMessage(partitionID, event)
+		}
+
+		release, err := checkpointer.Track(ctx, events[len(events)-1], int64(len(events)))
+		if err != nil {
+			return
+		}
+
+		select {
+		case msgChan <- ehiAsyncBatch{
+			batch: batch,
+			ackFn: func(ctx context.Context, _ error) error {
+				commitMut.Lock()
+				defer commitMut.Unlock()
+
+				highest := release()
+				if highest == nil {
+					return nil
+				}
+				if err := partition.UpdateCheckpoint(ctx, *highest, nil); err != nil {
+					return fmt.Errorf("failed to update checkpoint of partition %v: %w", partitionID, err)
+				}
+				return nil
+			},
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *eventHubsInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	e.connMut.Lock()
+	msgChan := e.msgChan
+	e.connMut.Unlock()
+	if msgChan == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case b, open := <-msgChan:
+		if !open {
+			return nil, nil, service.ErrNotConnected
+		}
+		return b.batch, b.ackFn, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (e *eventHubsInput) Close(ctx context.Context) error {
+	go func() {
+		e.shutSig.TriggerSoftStop()
+		e.connMut.Lock()
+		if e.msgChan == nil {
+			// Indicates that we were never connected, so indicate shutdown is
+			// complete.
+			e.shutSig.TriggerHasStopped()
+		}
+		e.connMut.Unlock()
+	}()
+	select {
+	case <-e.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Event Hubs Output Fields
+	ehoFieldPartitionKey = "partition_key"
+	ehoFieldPartitionID  = "partition_id"
+	ehoFieldMessageID    = "message_id"
+	ehoFieldContentType  = "content_type"
+	ehoFieldMetadata     = "metadata"
+	ehoFieldBatching     = "batching"
+)
+
+type ehoConfig struct {
+	Conn         ehConnConfig
+	PartitionKey *service.InterpolatedString
+	PartitionID  *service.InterpolatedString
+	MessageID    *service.InterpolatedString
+	ContentType  *service.InterpolatedString
+	MetaFilter   *service.MetadataExcludeFilter
+}
+
+func ehoConfigFromParsed(pConf *service.ParsedConfig) (conf ehoConfig, err error) {
+	if conf.Conn, err = ehConnConfigFromParsed(pConf); err != nil {
+		return
+	}
+	if conf.PartitionKey, err = pConf.FieldInterpolatedString(ehoFieldPartitionKey); err != nil {
+		return
+	}
+	if conf.PartitionID, err = pConf.FieldInterpolatedString(ehoFieldPartitionID); err != nil {
+		return
+	}
+	if conf.MessageID, err = pConf.FieldInterpolatedString(ehoFieldMessageID); err != nil {
+		return
+	}
+	if conf.ContentType, err = pConf.FieldInterpolatedString(ehoFieldContentType); err != nil {
+		return
+	}
+	if conf.MetaFilter, err = pConf.FieldMetadataExcludeFilter(ehoFieldMetadata); err != nil {
+		return
+	}
+	return
+}
+
+func ehoSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("Services", "Azure").
+		Beta().
+		Version("4.62.0").
+		Summary(`Sends messages to an Azure Event Hub using the native AMQP protocol.`).
+		Description(`
+Messages of a batch that share the same partition key and partition ID are sent together as Event Hubs batches, which are split automatically when they exceed the maximum size allowed by the event hub.
+
+When a `+"`partition_id`"+` resolves to a non-empty value messages are sent directly to that partition and the `+"`partition_key`"+` is ignored. Otherwise messages with a `+"`partition_key`"+` are assigned to partitions by Event Hubs according to a hash of the key, and messages without either are distributed across partitions.
+
+Metadata of messages that passes the `+"`metadata`"+` filter is sent as application properties of the events.`+service.OutputPerformanceDocs(true, true)).
+		Fields(eventHubsConnectionFields()...).
+		Fields(
+			service.NewInterpolatedStringField(ehoFieldPartitionKey).
+				Description("An optional key used for assigning events to partitions. Events with the same key are always written to the same partition.").
+				Example(`${! @kafka_key }`).
+				Default(""),
+			service.NewInterpolatedStringField(ehoFieldPartitionID).
+				Description("An optional ID of the partition to write events to.").
+				Example(`${! @event_hubs_partition_id }`).
+				Default("").
+				Advanced(),
+			service.NewInterpolatedStringField(ehoFieldMessageID).
+				Description("An optional message ID to set on events.").
+				Default("").
+				Advanced(),
+			service.NewInterpolatedStringField(ehoFieldContentType).
+				Description("An optional content type to set on events.").
+				Example("application/json").
+				Default("").
+				Advanced(),
+			service.NewMetadataExcludeFilterField(ehoFieldMetadata).
+				Description("Specify criteria for which metadata values are sent as application properties of events."),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ehoFieldBatching),
+		).
+		LintRule(`root = if this.connection_string.or("") == "" && this.namespace.or("") == "" { [ "either a connection_string or a namespace must be specified" ] }`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("azure_event_hubs", ehoSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batcher service.BatchPolicy, mif int, err error) {
+			var pConf ehoConfig
+			if pConf, err = ehoConfigFromParsed(conf); err != nil {
+				return
+			}
+			if batcher, err = conf.FieldBatchPolicy(ehoFieldBatching); err != nil {
+				return
+			}
+			if mif, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out = newEventHubsOutput(pConf, mgr.Logger())
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type eventHubsOutput struct {
+	conf ehoConfig
+	log  *service.Logger
+
+	connMut  sync.RWMutex
+	producer *azeventhubs.ProducerClient
+}
+
+func newEventHubsOutput(conf ehoConfig, log *service.Logger) *eventHubsOutput {
+	return &eventHubsOutput{
+		conf: conf,
+		log:  log,
+	}
+}
+
+func (e *eventHubsOutput) Connect(ctx context.Context) error {
+	e.connMut.Lock()
+	defer e.connMut.Unlock()
+	if e.producer != nil {
+		return nil
+	}
+
+	producer, err := e.conf.Conn.producerClient()
+	if err != nil {
+		return fmt.Errorf("failed to create producer client: %w", err)
+	}
+	if _, err := producer.GetEventHubProperties(ctx, nil); err != nil {
+		_ = producer.Close(ctx)
+		return fmt.Errorf("failed to obtain event hub properties: %w", err)
+	}
+	e.producer = producer
+	return nil
+}
+
+// ehoGroup is a group of messages from a batch that are sent to the same
+// partition.
+type ehoGroup struct {
+	partitionKey string
+	partitionID  string
+	indexes      []int
+}
+
+func (g *ehoGroup) batchOptions() *azeventhubs.EventDataBatchOptions {
+	opts := &azeventhubs.EventDataBatchOptions{}
+	if g.partitionID != "" {
+		opts.PartitionID = &g.partitionID
+	} else if g.partitionKey != "" {
+		opts.PartitionKey = &g.partitionKey
+	}
+	return opts
+}
+
+func (e *eventHubsOutput) eventFromMessage(batch service.MessageBatch, i int) (*azeventhubs.EventData, error) {
+	body, err := batch[i].AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	event := &azeventhubs.EventData{Body: body}
+
+	messageID, err := batch.TryInterpolatedString(i, e.conf.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("message id interpolation error: %w", err)
+	}
+	if messageID != "" {
+		event.MessageID = &messageID
+	}
+	contentType, err := batch.TryInterpolatedString(i, e.conf.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("content type interpolation error: %w", err)
+	}
+	if contentType != "" {
+		event.ContentType = &contentType
+	}
+
+	_ = e.conf.MetaFilter.Walk(batch[i], func(k, v string) error {
+		if event.Properties == nil {
+			event.Properties = map[string]any{}
+		}
+		event.Properties[k] = v
+		return nil
+	})
+	return event, nil
+}
+
+func (e *eventHubsOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	e.connMut.RLock()
+	producer := e.producer
+	e.connMut.RUnlock()
+	if producer == nil {
+		return service.ErrNotConnected
+	}
+
+	var batchErr *service.BatchError
+	fail := func(i int, err error) {
+		if batchErr == nil {
+			batchErr = service.NewBatchError(batch, err)
+		}
+		batchErr.Failed(i, err)
+	}
+
+	events := make([]*azeventhubs.EventData, len(batch))
+	var groups []*ehoGroup
+	groupsByPartition := map[[2]string]*ehoGroup{}
+	for i := range batch {
+		partitionKey, err := batch.TryInterpolatedString(i, e.conf.PartitionKey)
+		if err != nil {
+			fail(i, fmt.Errorf("partition key interpolation error: %w", err))
+			continue
+		}
+		partitionID, err := batch.TryInterpolatedString(i, e.conf.PartitionID)
+		if err != nil {
+			fail(i, fmt.Errorf("partition id interpolation error: %w", err))
+			continue
+		}
+		if events[i], err = e.eventFromMessage(batch, i); err != nil {
+			fail(i, err)
+			continue
+		}
+		if partitionID != "" {
+			partitionKey = ""
+		}
+
+		groupKey := [2]string{partitionKey, partitionID}
+		group, exists := groupsByPartition[groupKey]
+		if !exists {
+			group = &ehoGroup{partitionKey: partitionKey, partitionID: partitionID}
+			groupsByPartition[groupKey] = group
+			groups = append(groups, group)
+		}
+		group.indexes = append(group.indexes, i)
+	}
+
+	for _, group := range groups {
+		e.sendGroup(ctx, producer, group, events, fail)
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
+}
+
+func (*eventHubsOutput) sendGroup(
+	ctx context.Context,
+	producer *azeventhubs.ProducerClient,
+	group *ehoGroup,
+	events []*azeventhubs.EventData,
+	fail func(int, error),
+) {
+	var current *azeventhubs.EventDataBatch
+	var currentIndexes []int
+
+	send := func() {
+		if current == nil || current.NumEvents() == 0 {
+			return
+		}
+		if err := producer.SendEventDataBatch(ctx, current, nil); err != nil {
+			for _, i := range currentIndexes {
+				fail(i, err)
+			}
+		}
+		current, currentIndexes = nil, nil
+	}
+
+	for n, i := range group.indexes {
+		if current == nil {
+			var err error
+			if current, err = producer.NewEventDataBatch(ctx, group.batchOptions()); err != nil {
+				for _, j := range group.indexes[n:] {
+					fail(j, err)
+				}
+				return
+			}
+		}
+
+		err := current.AddEventData(events[i], nil)
+		if errors.Is(err, azeventhubs.ErrEventDataTooLarge) && current.NumEvents() > 0 {
+			send()
+			if current, err = producer.NewEventDataBatch(ctx, group.batchOptions()); err != nil {
+				for _, j := range group.indexes[n:] {
+					fail(j, err)
+				}
+				return
+			}
+			err = current.AddEventData(events[i], nil)
+		}
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		currentIndexes = append(currentIndexes, i)
+	}
+	send()
+}
+
+func (e *eventHubsOutput) Close(ctx context.Context) error {
+	e.connMut.Lock()
+	defer e.connMut.Unlock()
+	if e.producer == nil {
+		return nil
+	}
+	err := e.producer.Close(ctx)
+	e.producer = nil
+	return err
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// The format of the EnqueuedTimeUtc field of captured events.
+const ehCaptureTimeLayout = "1/2/2006 3:04:05 PM"
+
+func ehCaptureScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Summary("Consume events archived by Azure Event Hubs Capture.").
+		Description(`
+Decodes the Avro files written by https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-capture-overview[Event Hubs Capture^], yielding a message for each captured event containing the original event body. This makes it possible to replay captured events by combining this scanner with inputs such as `+"`azure_blob_storage`"+`.
+
+Each message has the same metadata as messages consumed by the `+"`azure_event_hubs`"+` input where it is available within the capture:
+
+- event_hubs_sequence_number
+- event_hubs_offset
+- event_hubs_enqueued_time
+- event_hubs_partition_key
+- All application properties of the event
+`).
+		Example("Replay captured events", "Consume all events captured from a given event hub.", `
+input:
+  azure_blob_storage:
+    storage_connection_string: "${AZURE_STORAGE_CONNECTION_STRING}"
+    container: capture
+    prefix: my-namespace/my-event-hub/
+    scanner:
+      azure_event_hubs_capture: {}
+`)
+}
+
+func init() {
+	service.MustRegisterBatchScannerCreator("azure_event_hubs_capture", ehCaptureScannerSpec(),
+		func(*service.ParsedConfig, *service.Resources) (service.BatchScannerCreator, error) {
+			return &ehCaptureScannerCreator{}, nil
+		})
+}
+
+type ehCaptureScannerCreator struct{}
+
+func (*ehCaptureScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, _ *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	ocf, err := goavro.NewOCFReader(bufio.NewReader(rdr))
+	if err != nil {
+		return nil, err
+	}
+	return service.AutoAggregateBatchScannerAcks(&ehCaptureScanner{
+		r:   rdr,
+		ocf: ocf,
+	}, aFn), nil
+}
+
+func (*ehCaptureScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type ehCaptureScanner struct {
+	r   io.ReadCloser
+	ocf *goavro.OCFReader
+}
+
+func (c *ehCaptureScanner) NextBatch(context.Context) (service.MessageBatch, error) {
+	if c.r == nil {
+		return nil, io.EOF
+	}
+
+	if !c.ocf.Scan() {
+		if err := c.ocf.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan capture file: %w", err)
+		}
+		return nil, io.EOF
+	}
+
+	datum, err := c.ocf.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read captured event: %w", err)
+	}
+	record, ok := datum.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected captured event to be a record, got %T", datum)
+	}
+	return service.MessageBatch{ehCaptureRecordToMessage(record)}, nil
+}
+
+func (c *ehCaptureScanner) Close(context.Context) error {
+	if c.r == nil {
+		return nil
+	}
+	return c.r.Close()
+}
+
+// ehUnwrapUnion extracts the value of an Avro union as decoded by goavro, where
+// non-null values are wrapped within a single key map named after their type.
+func ehUnwrapUnion(v any) any {
+	if m, ok := v.(map[string]any); ok && len(m) == 1 {
+		for _, inner := range m {
+			return inner
+		}
+	}
+	return v
+}
+
+func ehCaptureRecordToMessage(record map[string]any) *service.Message {
+	body, _ := ehUnwrapUnion(record["Body"]).([]byte)
+	msg := service.NewMessage(body)
+
+	if seq, ok := record["SequenceNumber"].(int64); ok {
+		msg.MetaSetMut("event_hubs_sequence_number", seq)
+	}
+	if offset, ok := record["Offset"].(string); ok {
+		msg.MetaSetMut("event_hubs_offset", offset)
+	}
+	if enqueued, ok := record["EnqueuedTimeUtc"].(string); ok {
+		if t, err := time.Parse(ehCaptureTimeLayout, enqueued); err == nil {
+			enqueued = t.Format(time.RFC3339Nano)
+		}
+		msg.MetaSetMut("event_hubs_enqueued_time", enqueued)
+	}
+	if sysProps, ok := record["SystemProperties"].(map[string]any); ok {
+		if key, ok := ehUnwrapUnion(sysProps["x-opt-partition-key"]).(string); ok {
+			msg.MetaSetMut("event_hubs_partition_key", key)
+		}
+	}
+	if props, ok := record["Properties"].(map[string]any); ok {
+		for k, v := range props {
+			if v = ehUnwrapUnion(v); v != nil {
+				msg.MetaSetMut(k, v)
+			}
+		}
+	}
+	return msg
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// The schema of files written by Event Hubs Capture.
+const ehCaptureTestSchema = `{
+  "type": "record",
+  "name": "EventData",
+  "namespace": "Microsoft.ServiceBus.Messaging",
+  "fields": [
+    {"name": "SequenceNumber", "type": "long"},
+    {"name": "Offset", "type": "string"},
+    {"name": "EnqueuedTimeUtc", "type": "string"},
+    {"name": "SystemProperties", "type": {"type": "map", "values": ["long", "double", "string", "bytes"]}},
+    {"name": "Properties", "type": {"type": "map", "values": ["long", "double", "string", "bytes", "null"]}},
+    {"name": "Body", "type": ["null", "bytes"]}
+  ]
+}`
+
+func TestEventHubsCaptureScanner(t *testing.T) {
+	var buf bytes.Buffer
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:      &buf,
+		Schema: ehCaptureTestSchema,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ocf.Append([]map[string]any{
+		{
+			"SequenceNumber":  int64(10),
+			"Offset":          "4294967296",
+			"EnqueuedTimeUtc": "2/6/2024 7:29:58 PM",
+			"SystemProperties": map[string]any{
+				"x-opt-partition-key": goavro.Union("string", "foo"),
+			},
+			"Properties": map[string]any{
+				"source": goavro.Union("string", "sensor"),
+				"count":  goavro.Union("long", int64(3)),
+				"empty":  nil,
+			},
+			"Body": goavro.Union("bytes", []byte(`{"id":1}`)),
+		},
+		{
+			"SequenceNumber":   int64(11),
+			"Offset":           "4294967400",
+			"EnqueuedTimeUtc":  "2/6/2024 7:30:01 PM",
+			"SystemProperties": map[string]any{},
+			"Properties":       map[string]any{},
+			"Body":             nil,
+		},
+	}))
+
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  azure_event_hubs_capture: {}
+`, nil)
+	require.NoError(t, err)
+
+	rdr, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	var acked bool
+	strm, err := rdr.Create(io.NopCloser(&buf), func(context.Context, error) error {
+		acked = true
+		return nil
+	}, service.NewScannerSourceDetails())
+	require.NoError(t, err)
+
+	batch, aFn, err := strm.NextBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	mBytes, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(mBytes))
+
+	meta := map[string]any{}
+	require.NoError(t, batch[0].MetaWalkMut(func(k string, v any) error {
+		meta[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]any{
+		"event_hubs_sequence_number": int64(10),
+		"event_hubs_offset":          "4294967296",
+		"event_hubs_enqueued_time":   "2024-02-06T19:29:58Z",
+		"event_hubs_partition_key":   "foo",
+		"source":                     "sensor",
+		"count":                      int64(3),
+	}, meta)
+	require.NoError(t, aFn(t.Context(), nil))
+
+	batch, aFn, err = strm.NextBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	mBytes, err = batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Empty(t, mBytes)
+	require.NoError(t, aFn(t.Context(), nil))
+
+	_, _, err = strm.NextBatch(t.Context())
+	require.Equal(t, io.EOF, err)
+
+	require.NoError(t, strm.Close(t.Context()))
+	assert.True(t, acked)
+}
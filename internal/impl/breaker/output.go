@@ -0,0 +1,562 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const (
+	cbFieldOutput           = "output"
+	cbFieldFallback         = "fallback"
+	cbFieldWindow           = "window"
+	cbFieldMinRequests      = "min_requests"
+	cbFieldErrorThreshold   = "error_threshold"
+	cbFieldLatencyThreshold = "latency_threshold"
+	cbFieldOpenDuration     = "open_duration"
+	cbFieldHalfOpenProbes   = "half_open_probes"
+	cbFieldKey              = "key"
+	cbFieldMaxKeys          = "max_keys"
+
+	// cbOtherLabel is the state gauge label of the circuit breaker shared by
+	// keys beyond the maximum number of keys.
+	cbOtherLabel = "_other"
+)
+
+func circuitBreakerOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Monitors the errors and latency of writes to a child output, and stops writing to it for a period once it's failing, routing messages to a fallback output or holding them back until the child output recovers.").
+		Description(`
+The circuit breaker starts closed, in which state every batch is written to the child `+"`"+cbFieldOutput+"`"+`. The outcome of each write within the last `+"`"+cbFieldWindow+"`"+` is recorded, where writes that fail or take longer than `+"`"+cbFieldLatencyThreshold+"`"+` count as failures. Once at least `+"`"+cbFieldMinRequests+"`"+` writes are recorded and the proportion of failures reaches `+"`"+cbFieldErrorThreshold+"`"+`, the circuit breaker opens.
+
+While open, batches are written to the `+"`"+cbFieldFallback+"`"+` output when one is configured, and otherwise writes are blocked, which applies back pressure to the input until the child output recovers. After `+"`"+cbFieldOpenDuration+"`"+` the circuit breaker becomes half-open, in which state single batches are written to the child output as probes while other batches continue to be treated as though the circuit breaker is open. A failed probe opens the circuit breaker again, and once `+"`"+cbFieldHalfOpenProbes+"`"+` consecutive probes succeed the circuit breaker closes.
+
+Failed writes to the child output are rejected, and so they're retried upstream, where they'll be routed to the fallback output if the circuit breaker has opened in the meantime.
+
+== Keys
+
+When a `+"`"+cbFieldKey+"`"+` is configured each distinct key has a circuit breaker of its own, such as each host of an `+"`http_client`"+` output with a dynamic `+"`url`"+`, which means a failing host doesn't affect writes to the others. Batches with messages of multiple keys are split and written to the child output for each key concurrently, and only the messages of the keys that failed are rejected. Without a `+"`"+cbFieldFallback+"`"+` the messages of keys with an open circuit breaker are held back whilst those of other keys are written, although the batch is only acknowledged once all of its messages are written. Circuit breakers of keys that haven't been written to within the `+"`"+cbFieldWindow+"`"+` are removed while closed.
+
+At most `+"`"+cbFieldMaxKeys+"`"+` keys have a circuit breaker of their own at a time, and messages of any further keys share a single circuit breaker, labelled `+"`"+cbOtherLabel+"`"+` within the state gauge, until the circuit breakers of other keys are removed.
+
+== Metrics
+
+The state of the circuit breaker is exposed as the gauge `+"`circuit_breaker_state`"+`, which is `+"`0`"+` when closed, `+"`1`"+` when half-open and `+"`2`"+` when open. When a `+"`"+cbFieldKey+"`"+` is configured the gauge has the label `+"`key`"+`.`).
+		Fields(
+			service.NewOutputField(cbFieldOutput).
+				Description("The child output to write messages to while the circuit breaker is closed."),
+			service.NewOutputField(cbFieldFallback).
+				Description("An optional output to write messages to while the circuit breaker is open. When not set, writes are blocked while the circuit breaker is open.").
+				Optional(),
+			service.NewDurationField(cbFieldWindow).
+				Description("The period of time over which the outcomes of writes are recorded.").
+				Default("30s"),
+			service.NewIntField(cbFieldMinRequests).
+				Description("The minimum number of writes recorded within the window before the circuit breaker can open.").
+				Default(10),
+			service.NewFloatField(cbFieldErrorThreshold).
+				Description("The proportion of failed writes within the window, between 0 and 1, at which the circuit breaker opens.").
+				Default(0.5),
+			service.NewDurationField(cbFieldLatencyThreshold).
+				Description("The duration after which a successful write counts as a failure. Set to `0s` in order to ignore latency.").
+				Default("0s"),
+			service.NewDurationField(cbFieldOpenDuration).
+				Description("The period of time the circuit breaker remains open before probing the child output.").
+				Default("30s"),
+			service.NewIntField(cbFieldHalfOpenProbes).
+				Description("The number of consecutive successful probes required to close the circuit breaker.").
+				Default(1).
+				Advanced(),
+			service.NewInterpolatedStringField(cbFieldKey).
+				Description("An optional key that messages are grouped by, where each key has a circuit breaker of its own.").
+				Example("${! @host }").
+				Optional().
+				Advanced(),
+			service.NewIntField(cbFieldMaxKeys).
+				Description("The maximum number of keys that have a circuit breaker of their own at a time when a `"+cbFieldKey+"` is configured.").
+				Default(100).
+				Advanced(),
+		).
+		Example("Fall back to a backup cluster", "Write messages to a backup Kafka cluster while the primary cluster is failing for the majority of writes.", `
+output:
+  circuit_breaker:
+    error_threshold: 0.5
+    latency_threshold: 10s
+    open_duration: 1m
+    output:
+      kafka_franz:
+        seed_brokers: [ primary:9092 ]
+        topic: events
+    fallback:
+      kafka_franz:
+        seed_brokers: [ backup:9092 ]
+        topic: events
+`).
+		Example("Per host HTTP requests with OAuth2 refresh tokens", "Send messages to the host within their metadata with a circuit breaker for each host, holding back the messages of a failing host while the others continue to be written to. The access token is obtained and refreshed shortly before it expires by an `oauth2_token` processor.", `
+output:
+  processors:
+    - oauth2_token:
+        token_url: https://auth.example.com/oauth/token
+        grant_type: refresh_token
+        client_id: ${CLIENT_ID}
+        client_secret: ${CLIENT_SECRET}
+        refresh_token: ${REFRESH_TOKEN}
+  circuit_breaker:
+    key: ${! @host }
+    min_requests: 5
+    open_duration: 1m
+    output:
+      http_client:
+        url: https://${! @host }/ingest
+        verb: POST
+        headers:
+          Authorization: Bearer ${! @oauth2_access_token }
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"circuit_breaker", circuitBreakerOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newCircuitBreakerOutputFromConfig(conf, mgr)
+			maxInFlight = 64
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateHalfOpen
+	stateOpen
+)
+
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+type circuitBreakerOutput struct {
+	log        *service.Logger
+	stateGauge *service.MetricGauge
+
+	output   wrapper.BatchWriter
+	fallback wrapper.BatchWriter
+	key      *service.InterpolatedString
+
+	window           time.Duration
+	minRequests      int
+	errorThreshold   float64
+	latencyThreshold time.Duration
+	openDuration     time.Duration
+	halfOpenProbes   int
+	clock            func() time.Time
+
+	breakersMut sync.Mutex
+	breakers    map[string]*breaker
+	lastPrune   time.Time
+	other       *breaker
+	maxKeys     int
+}
+
+func newCircuitBreakerOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*circuitBreakerOutput, error) {
+	o := &circuitBreakerOutput{
+		log:      mgr.Logger(),
+		clock:    time.Now,
+		breakers: map[string]*breaker{},
+	}
+
+	var err error
+	if o.window, err = conf.FieldDuration(cbFieldWindow); err != nil {
+		return nil, err
+	}
+	if o.minRequests, err = conf.FieldInt(cbFieldMinRequests); err != nil {
+		return nil, err
+	}
+	if o.errorThreshold, err = conf.FieldFloat(cbFieldErrorThreshold); err != nil {
+		return nil, err
+	}
+	if o.errorThreshold <= 0 || o.errorThreshold > 1 {
+		return nil, errors.New("error_threshold must be greater than zero and at most one")
+	}
+	if o.latencyThreshold, err = conf.FieldDuration(cbFieldLatencyThreshold); err != nil {
+		return nil, err
+	}
+	if o.openDuration, err = conf.FieldDuration(cbFieldOpenDuration); err != nil {
+		return nil, err
+	}
+	if o.halfOpenProbes, err = conf.FieldInt(cbFieldHalfOpenProbes); err != nil {
+		return nil, err
+	}
+	if o.halfOpenProbes <= 0 {
+		return nil, errors.New("half_open_probes must be greater than zero")
+	}
+	if conf.Contains(cbFieldKey) {
+		if o.key, err = conf.FieldInterpolatedString(cbFieldKey); err != nil {
+			return nil, err
+		}
+		if o.maxKeys, err = conf.FieldInt(cbFieldMaxKeys); err != nil {
+			return nil, err
+		}
+		if o.maxKeys <= 0 {
+			return nil, errors.New("max_keys must be greater than zero")
+		}
+		o.stateGauge = mgr.Metrics().NewGauge("circuit_breaker_state", "key")
+	} else {
+		o.stateGauge = mgr.Metrics().NewGauge("circuit_breaker_state")
+		o.breakerFor("")
+	}
+	if o.output, err = conf.FieldOutput(cbFieldOutput); err != nil {
+		return nil, err
+	}
+	if conf.Contains(cbFieldFallback) {
+		if o.fallback, err = conf.FieldOutput(cbFieldFallback); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// breakerFor returns the circuit breaker of a key, creating it when it doesn't
+// exist yet. Closed circuit breakers of keys that haven't been written to
+// within the window are removed at most once per window, and keys beyond the
+// maximum number of keys share a circuit breaker.
+func (o *circuitBreakerOutput) breakerFor(key string) *breaker {
+	o.breakersMut.Lock()
+	defer o.breakersMut.Unlock()
+
+	now := o.clock()
+	if o.key != nil && now.Sub(o.lastPrune) >= o.window {
+		o.lastPrune = now
+		for k, b := range o.breakers {
+			if k != key && b.idle(now) {
+				delete(o.breakers, k)
+			}
+		}
+		if o.other != nil && o.other.idle(now) {
+			o.other = nil
+		}
+	}
+
+	if b, exists := o.breakers[key]; exists {
+		return b
+	}
+	if o.key == nil || len(o.breakers) < o.maxKeys {
+		b := o.newBreaker(key)
+		o.breakers[key] = b
+		return b
+	}
+	if o.other == nil {
+		o.log.Warnf("Reached the maximum of %v keys, key %q and any further keys share a circuit breaker", o.maxKeys, key)
+		o.other = o.newBreaker(cbOtherLabel)
+	}
+	return o.other
+}
+
+func (o *circuitBreakerOutput) newBreaker(key string) *breaker {
+	b := &breaker{o: o, log: o.log, changed: make(chan struct{})}
+	if o.key != nil {
+		b.log = o.log.With("key", key)
+		b.labels = []string{key}
+	}
+	o.stateGauge.Set(int64(stateClosed), b.labels...)
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+// breaker is the state of a circuit breaker of a single key.
+type breaker struct {
+	o      *circuitBreakerOutput
+	log    *service.Logger
+	labels []string
+
+	mut            sync.Mutex
+	state          breakerState
+	outcomes       []outcome
+	lastWrite      time.Time
+	openedAt       time.Time
+	probing        bool
+	probeSuccesses int
+
+	// changed is closed and replaced whenever the state changes in order to
+	// wake up blocked writes.
+	changed chan struct{}
+}
+
+// idle returns whether the breaker is closed and hasn't been written to within
+// the window.
+func (b *breaker) idle(now time.Time) bool {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.state == stateClosed && !b.probing && now.Sub(b.lastWrite) > b.o.window
+}
+
+// setState changes the state of the breaker, the lock must be held.
+func (b *breaker) setState(state breakerState, now time.Time) {
+	if b.state == state {
+		return
+	}
+	switch state {
+	case stateOpen:
+		b.log.Warnf("Circuit breaker opened, pausing writes to the child output for %v", b.o.openDuration)
+		b.openedAt = now
+	case stateHalfOpen:
+		b.log.Infof("Circuit breaker half-open, probing the child output")
+		b.probeSuccesses = 0
+	case stateClosed:
+		b.log.Infof("Circuit breaker closed")
+		b.outcomes = nil
+	}
+	b.state = state
+	b.o.stateGauge.Set(int64(state), b.labels...)
+
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// acquire determines whether a batch can be written to the child output, and
+// whether the write is a probe. When the batch can't be written the time at
+// which the state of the breaker may change is returned.
+func (b *breaker) acquire(now time.Time) (allowed, probe bool, retryAt time.Time, changed <-chan struct{}) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.lastWrite = now
+	if b.state == stateOpen && !now.Before(b.openedAt.Add(b.o.openDuration)) {
+		b.setState(stateHalfOpen, now)
+	}
+	switch b.state {
+	case stateClosed:
+		return true, false, time.Time{}, b.changed
+	case stateHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return true, true, time.Time{}, b.changed
+		}
+		return false, false, time.Time{}, b.changed
+	}
+	return false, false, b.openedAt.Add(b.o.openDuration), b.changed
+}
+
+// record stores the outcome of a write to the child output.
+func (b *breaker) record(now time.Time, probe, failed bool) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if probe {
+		b.probing = false
+		if b.state != stateHalfOpen {
+			return
+		}
+		if failed {
+			b.setState(stateOpen, now)
+			return
+		}
+		if b.probeSuccesses++; b.probeSuccesses >= b.o.halfOpenProbes {
+			b.setState(stateClosed, now)
+			return
+		}
+		// Wake up a blocked write in order to send the next probe.
+		close(b.changed)
+		b.changed = make(chan struct{})
+		return
+	}
+	if b.state != stateClosed {
+		return
+	}
+
+	b.outcomes = append(b.outcomes, outcome{at: now, failed: failed})
+	cutoff := now.Add(-b.o.window)
+	i := 0
+	for i < len(b.outcomes) && b.outcomes[i].at.Before(cutoff) {
+		i++
+	}
+	b.outcomes = b.outcomes[i:]
+
+	if len(b.outcomes) < b.o.minRequests {
+		return
+	}
+	failures := 0
+	for _, oc := range b.outcomes {
+		if oc.failed {
+			failures++
+		}
+	}
+	if float64(failures)/float64(len(b.outcomes)) >= b.o.errorThreshold {
+		b.setState(stateOpen, now)
+	}
+}
+
+// releaseProbe abandons a probe without recording its outcome.
+func (b *breaker) releaseProbe() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.probing = false
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+//------------------------------------------------------------------------------
+
+func (o *circuitBreakerOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *circuitBreakerOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	if o.key == nil {
+		return o.write(ctx, o.breakerFor(""), batch)
+	}
+
+	var keys []string
+	groups := map[string][]int{}
+	for i := range batch {
+		key, err := batch.TryInterpolatedString(i, o.key)
+		if err != nil {
+			return fmt.Errorf("key interpolation error: %w", err)
+		}
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	if len(keys) == 1 {
+		return o.write(ctx, o.breakerFor(keys[0]), batch)
+	}
+
+	// Each key is written concurrently so that a key whose breaker blocks
+	// writes doesn't hold back the others, and only the messages of keys that
+	// failed are rejected so that the others aren't written again.
+	var wg sync.WaitGroup
+	groupIndexes := make([]*service.Indexer, len(keys))
+	errs := make([]error, len(keys))
+	for ki, key := range keys {
+		indexes := groups[key]
+		group := make(service.MessageBatch, len(indexes))
+		for j, i := range indexes {
+			group[j] = batch[i]
+		}
+		groupIndexes[ki] = group.Index()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[ki] = o.write(ctx, o.breakerFor(key), group)
+		}()
+	}
+	wg.Wait()
+
+	var batchErr *service.BatchError
+	for ki, err := range errs {
+		if err == nil {
+			continue
+		}
+		if batchErr == nil {
+			batchErr = service.NewBatchError(batch, err)
+		}
+		indexes := groups[keys[ki]]
+		var groupErr *service.BatchError
+		if errors.As(err, &groupErr) {
+			groupErr.WalkMessagesIndexedBy(groupIndexes[ki], func(j int, _ *service.Message, err error) bool {
+				if err != nil {
+					batchErr.Failed(indexes[j], err)
+				}
+				return true
+			})
+			continue
+		}
+		for _, i := range indexes {
+			batchErr.Failed(i, err)
+		}
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
+}
+
+// write writes a batch to the child output when the breaker allows it, and
+// otherwise to the fallback output or once the breaker allows it.
+func (o *circuitBreakerOutput) write(ctx context.Context, b *breaker, batch service.MessageBatch) error {
+	for {
+		allowed, probe, retryAt, changed := b.acquire(o.clock())
+		if allowed {
+			return o.writeChild(ctx, b, batch, probe)
+		}
+		if o.fallback != nil {
+			return o.fallback.WriteBatch(ctx, batch)
+		}
+
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if !retryAt.IsZero() {
+			timer = time.NewTimer(retryAt.Sub(o.clock()))
+			timerC = timer.C
+		}
+		select {
+		case <-changed:
+		case <-timerC:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (o *circuitBreakerOutput) writeChild(ctx context.Context, b *breaker, batch service.MessageBatch, probe bool) error {
+	start := o.clock()
+	err := o.output.WriteBatch(ctx, batch)
+	end := o.clock()
+
+	if err != nil && ctx.Err() != nil {
+		// Writes abandoned due to shutdown say nothing about the child output.
+		if probe {
+			b.releaseProbe()
+		}
+		return err
+	}
+
+	failed := err != nil || (o.latencyThreshold > 0 && end.Sub(start) > o.latencyThreshold)
+	b.record(end, probe, failed)
+	return err
+}
+
+func (o *circuitBreakerOutput) Close(ctx context.Context) error {
+	err := o.output.Close(ctx)
+	if o.fallback != nil {
+		err = errors.Join(err, o.fallback.Close(ctx))
+	}
+	return err
+}
@@ -0,0 +1,403 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeWriter struct {
+	mut     sync.Mutex
+	err     error
+	delay   func()
+	written int
+}
+
+func (f *fakeWriter) setErr(err error) {
+	f.mut.Lock()
+	f.err = err
+	f.mut.Unlock()
+}
+
+func (f *fakeWriter) count() int {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.written
+}
+
+func (f *fakeWriter) WriteBatch(context.Context, service.MessageBatch) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.delay != nil {
+		f.delay()
+	}
+	if f.err != nil {
+		return f.err
+	}
+	f.written++
+	return nil
+}
+
+func (*fakeWriter) Close(context.Context) error {
+	return nil
+}
+
+type testClock struct {
+	mut sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mut.Lock()
+	c.now = c.now.Add(d)
+	c.mut.Unlock()
+}
+
+func newTestOutput(t *testing.T, conf string, out, fallback *fakeWriter) (*circuitBreakerOutput, *testClock) {
+	t.Helper()
+
+	// The child outputs are replaced with fakes, and so the configured outputs
+	// are only parsed.
+	pConf, err := circuitBreakerOutputConfig().ParseYAML(conf+`
+output:
+  drop: {}
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newCircuitBreakerOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	o.output = out
+	if fallback != nil {
+		o.fallback = fallback
+	}
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	o.clock = clock.Now
+	return o, clock
+}
+
+func stateOf(o *circuitBreakerOutput, key string) breakerState {
+	b := o.breakerFor(key)
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.state
+}
+
+func writeBatch(t *testing.T, o *circuitBreakerOutput) error {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), time.Second*5)
+	defer done()
+	return o.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("hello"))})
+}
+
+func TestCircuitBreakerFallback(t *testing.T) {
+	out, fallback := &fakeWriter{}, &fakeWriter{}
+	o, clock := newTestOutput(t, `
+min_requests: 4
+error_threshold: 0.5
+open_duration: 10s
+half_open_probes: 2
+`, out, fallback)
+
+	// Failures below the minimum number of requests don't open the breaker.
+	out.setErr(errors.New("nope"))
+	for range 3 {
+		require.Error(t, writeBatch(t, o))
+	}
+	assert.Equal(t, stateClosed, stateOf(o, ""))
+
+	require.Error(t, writeBatch(t, o))
+	assert.Equal(t, stateOpen, stateOf(o, ""))
+
+	// While open, writes are routed to the fallback output.
+	out.setErr(nil)
+	require.NoError(t, writeBatch(t, o))
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, 0, out.count())
+	assert.Equal(t, 2, fallback.count())
+
+	// A failed probe opens the breaker again.
+	clock.Add(10 * time.Second)
+	out.setErr(errors.New("still nope"))
+	require.Error(t, writeBatch(t, o))
+	assert.Equal(t, stateOpen, stateOf(o, ""))
+
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, 3, fallback.count())
+
+	// Consecutive successful probes close the breaker.
+	clock.Add(10 * time.Second)
+	out.setErr(nil)
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, stateHalfOpen, stateOf(o, ""))
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, stateClosed, stateOf(o, ""))
+
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, 3, out.count())
+	assert.Equal(t, 3, fallback.count())
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	out := &fakeWriter{}
+	o, clock := newTestOutput(t, `
+window: 10s
+min_requests: 2
+error_threshold: 0.5
+`, out, nil)
+
+	out.setErr(errors.New("nope"))
+	require.Error(t, writeBatch(t, o))
+
+	// Outcomes older than the window are forgotten.
+	clock.Add(11 * time.Second)
+	out.setErr(nil)
+	require.NoError(t, writeBatch(t, o))
+	require.NoError(t, writeBatch(t, o))
+
+	out.setErr(errors.New("nope"))
+	require.Error(t, writeBatch(t, o))
+	assert.Equal(t, stateClosed, stateOf(o, ""))
+
+	require.Error(t, writeBatch(t, o))
+	assert.Equal(t, stateOpen, stateOf(o, ""))
+}
+
+func TestCircuitBreakerLatency(t *testing.T) {
+	out := &fakeWriter{}
+	o, clock := newTestOutput(t, `
+min_requests: 2
+error_threshold: 1
+latency_threshold: 1s
+`, out, nil)
+
+	out.delay = func() { clock.Add(2 * time.Second) }
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, stateClosed, stateOf(o, ""))
+	require.NoError(t, writeBatch(t, o))
+	assert.Equal(t, stateOpen, stateOf(o, ""))
+	assert.Equal(t, 2, out.count())
+}
+
+func TestCircuitBreakerBlocksWithoutFallback(t *testing.T) {
+	out := &fakeWriter{}
+	o, _ := newTestOutput(t, `
+min_requests: 1
+open_duration: 100ms
+`, out, nil)
+	o.clock = time.Now
+
+	out.setErr(errors.New("nope"))
+	require.Error(t, writeBatch(t, o))
+	assert.Equal(t, stateOpen, stateOf(o, ""))
+
+	// Writes are blocked until the breaker becomes half-open, at which point
+	// the write is attempted as a probe.
+	out.setErr(nil)
+	start := time.Now()
+	require.NoError(t, writeBatch(t, o))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, stateClosed, stateOf(o, ""))
+	assert.Equal(t, 1, out.count())
+
+	// Writes abandoned while blocked return the context error.
+	out.setErr(errors.New("nope"))
+	require.Error(t, writeBatch(t, o))
+
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer done()
+	err := o.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("hello"))})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// hostWriter fails batches containing messages of a failing host.
+type hostWriter struct {
+	mut     sync.Mutex
+	failing string
+	written []string
+}
+
+func (h *hostWriter) WriteBatch(_ context.Context, b service.MessageBatch) error {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	for _, msg := range b {
+		if host, _ := msg.MetaGet("host"); host == h.failing {
+			return errors.New("nope")
+		}
+	}
+	for _, msg := range b {
+		content, _ := msg.AsBytes()
+		h.written = append(h.written, string(content))
+	}
+	return nil
+}
+
+func (*hostWriter) Close(context.Context) error {
+	return nil
+}
+
+func TestCircuitBreakerKeys(t *testing.T) {
+	fallback := &fakeWriter{}
+	o, clock := newTestOutput(t, `
+min_requests: 2
+window: 10s
+key: ${! @host }
+`, &fakeWriter{}, fallback)
+	out := &hostWriter{failing: "bad"}
+	o.output = out
+
+	newBatch := func() (service.MessageBatch, *service.Indexer) {
+		var b service.MessageBatch
+		for _, m := range []struct{ content, host string }{
+			{"a", "good"},
+			{"b", "bad"},
+			{"c", "good"},
+		} {
+			msg := service.NewMessage([]byte(m.content))
+			msg.MetaSetMut("host", m.host)
+			b = append(b, msg)
+		}
+		return b, b.Index()
+	}
+
+	for range 2 {
+		b, indexer := newBatch()
+		err := o.WriteBatch(t.Context(), b)
+
+		var batchErr *service.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		var failed []int
+		batchErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, err error) bool {
+			if err != nil {
+				failed = append(failed, i)
+			}
+			return true
+		})
+		assert.Equal(t, []int{1}, failed)
+	}
+	assert.Equal(t, stateOpen, stateOf(o, "bad"))
+	assert.Equal(t, stateClosed, stateOf(o, "good"))
+
+	// Messages of the failing host are routed to the fallback output while
+	// the other hosts continue to be written to.
+	b, _ := newBatch()
+	require.NoError(t, o.WriteBatch(t.Context(), b))
+	assert.Equal(t, 1, fallback.count())
+	assert.Equal(t, []string{"a", "c", "a", "c", "a", "c"}, out.written)
+
+	// Closed breakers of keys that aren't written to within the window are
+	// removed, whereas open breakers are kept.
+	clock.Add(11 * time.Second)
+	assert.Equal(t, stateClosed, stateOf(o, "other"))
+	assert.Len(t, o.breakers, 2)
+	assert.Contains(t, o.breakers, "bad")
+}
+
+func TestCircuitBreakerKeysBlockIndependently(t *testing.T) {
+	o, _ := newTestOutput(t, `
+min_requests: 1
+open_duration: 500ms
+key: ${! @host }
+`, &fakeWriter{}, nil)
+	o.clock = time.Now
+	out := &hostWriter{failing: "bad"}
+	o.output = out
+
+	msg := func(content, host string) *service.Message {
+		m := service.NewMessage([]byte(content))
+		m.MetaSetMut("host", host)
+		return m
+	}
+
+	require.Error(t, o.WriteBatch(t.Context(), service.MessageBatch{msg("x", "bad")}))
+	assert.Equal(t, stateOpen, stateOf(o, "bad"))
+
+	// The message of the healthy host is written whilst the message of the
+	// host with an open breaker is held back.
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- o.WriteBatch(t.Context(), service.MessageBatch{msg("a", "bad"), msg("b", "good")})
+	}()
+	assert.Eventually(t, func() bool {
+		out.mut.Lock()
+		defer out.mut.Unlock()
+		return slices.Equal(out.written, []string{"b"})
+	}, time.Second, time.Millisecond)
+	select {
+	case err := <-writeErr:
+		t.Fatalf("write returned before the breaker allowed it: %v", err)
+	default:
+	}
+
+	// Once the host recovers the held back message is written as a probe.
+	out.mut.Lock()
+	out.failing = ""
+	out.mut.Unlock()
+	require.NoError(t, <-writeErr)
+	assert.Equal(t, stateClosed, stateOf(o, "bad"))
+	assert.Equal(t, []string{"b", "a"}, out.written)
+}
+
+func TestCircuitBreakerMaxKeys(t *testing.T) {
+	o, clock := newTestOutput(t, `
+window: 10s
+key: ${! @host }
+max_keys: 2
+`, &fakeWriter{}, nil)
+
+	a, b := o.breakerFor("a"), o.breakerFor(cbOtherLabel)
+	assert.Equal(t, []string{"a"}, a.labels)
+	assert.Equal(t, []string{cbOtherLabel}, b.labels)
+
+	// Keys beyond the maximum share a breaker, which isn't the breaker of a
+	// key that matches its label.
+	c, d := o.breakerFor("c"), o.breakerFor("d")
+	assert.Same(t, c, d)
+	assert.NotSame(t, b, c)
+	assert.Same(t, b, o.breakerFor(cbOtherLabel))
+	assert.Equal(t, []string{cbOtherLabel}, c.labels)
+
+	// Removing idle breakers frees up room for further keys.
+	clock.Add(11 * time.Second)
+	assert.Same(t, b, o.breakerFor(cbOtherLabel))
+	assert.NotContains(t, o.breakers, "a")
+	assert.Nil(t, o.other)
+
+	e := o.breakerFor("e")
+	assert.Equal(t, []string{"e"}, e.labels)
+	assert.Same(t, e, o.breakerFor("e"))
+	assert.Same(t, o.other, o.breakerFor("a"))
+}
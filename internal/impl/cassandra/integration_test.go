@@ -155,4 +155,62 @@ output:
 			}),
 		)
 	})
+
+	for _, test := range []struct {
+		name    string
+		options string
+	}{
+		{
+			name: "with partition batching",
+			options: `
+    logged_batch: false
+    partition_batching: true
+    idempotent: true`,
+		},
+		{
+			name: "with if not exists",
+			options: `
+    if_not_exists: true`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			template := `
+output:
+  cassandra:
+    addresses:
+      - localhost:$PORT
+    query: 'INSERT INTO testspace.table$ID (id, content) VALUES (?, ?)'
+    args_mapping: 'root = [ this.id, this.content ]'` + test.options + `
+`
+			queryGetFn := func(_ context.Context, testID, messageID string) (string, []string, error) {
+				var resID int
+				var resContent string
+				if err := session.Query(
+					fmt.Sprintf("select id, content from testspace.table%v where id = ?;", testID), messageID,
+				).Scan(&resID, &resContent); err != nil {
+					return "", nil, err
+				}
+				return fmt.Sprintf(`{"content":"%v","id":%v}`, resContent, resID), nil, nil
+			}
+			suite := integration.StreamTests(
+				integration.StreamTestOutputOnlySendSequential(10, queryGetFn),
+				integration.StreamTestOutputOnlySendBatch(10, queryGetFn),
+			)
+			suite.Run(
+				t, template,
+				integration.StreamTestOptPort(resource.GetPort("9042/tcp")),
+				integration.StreamTestOptSleepAfterInput(time.Second*10),
+				integration.StreamTestOptSleepAfterOutput(time.Second*10),
+				integration.StreamTestOptPreTest(func(t testing.TB, _ context.Context, vars *integration.StreamTestConfigVars) {
+					vars.ID = strings.ReplaceAll(vars.ID, "-", "")
+					require.NoError(t, session.Query(
+						fmt.Sprintf(
+							"CREATE TABLE testspace.table%v (id int primary key, content text);",
+							vars.ID,
+						),
+					).Exec())
+				}),
+			)
+		})
+	}
 }
@@ -17,9 +17,12 @@ package cassandra
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +38,11 @@ const (
 	coFieldConsistency = "consistency"
 	coFieldLoggedBatch = "logged_batch"
 	coFieldBatching    = "batching"
+
+	coFieldPartitionBatching = "partition_batching"
+	coFieldIfNotExists       = "if_not_exists"
+	coFieldSerialConsistency = "serial_consistency"
+	coFieldIdempotent        = "idempotent"
 )
 
 func outputSpec() *service.ConfigSpec {
@@ -44,7 +52,15 @@ func outputSpec() *service.ConfigSpec {
 		Description(`
 Query arguments can be set using a bloblang array for the fields using the `+"`args_mapping`"+` field.
 
-When populating timestamp columns the value must either be a string in ISO 8601 format (2006-01-02T15:04:05Z07:00), or an integer representing unix time in seconds.`+service.OutputPerformanceDocs(true, true)).
+When populating timestamp columns the value must either be a string in ISO 8601 format (2006-01-02T15:04:05Z07:00), or an integer representing unix time in seconds.
+
+== Partition batching
+
+Batches spanning many partitions are coordinated by a single node, which forwards each write to the replicas of its partition. When `+"`"+coFieldPartitionBatching+"`"+` is enabled each batch of messages is instead split into a batch per partition key, and these batches are executed in parallel with each routed directly to a replica of its partition. When the batch of one partition fails only the messages of that partition are retried.
+
+== Lightweight transactions
+
+When `+"`"+coFieldIfNotExists+"`"+` is enabled `+"`IF NOT EXISTS`"+` is added to the insert query, and rows are only inserted when a row with the same primary key does not already exist. Messages of rows that already exist are considered delivered. Lightweight transactions can't be batched across partitions and therefore each message is written with its own query.`+service.OutputPerformanceDocs(true, true)).
 		Example(
 			"Basic Inserts",
 			"If we were to create a table with some basic columns with `CREATE TABLE foo.bar (id int primary key, content text, created_at timestamp);`, and were processing JSON documents of the form `{\"id\":\"342354354\",\"content\":\"hello world\",\"timestamp\":1605219406}` using logged batches, we could populate our table with the following config:",
@@ -97,6 +113,26 @@ output:
 				Description("If enabled the driver will perform a logged batch. Disabling this prompts unlogged batches to be used instead, which are less efficient but necessary for alternative storages that do not support logged batches.").
 				Advanced().
 				Default(true),
+			service.NewBoolField(coFieldPartitionBatching).
+				Description("Whether to split each batch of messages into a batch per partition key, which are executed in parallel and routed to a replica of their partition. This is most efficient when combined with unlogged batches by disabling `"+coFieldLoggedBatch+"`.").
+				Version("4.62.0").
+				Advanced().
+				Default(false),
+			service.NewBoolField(coFieldIfNotExists).
+				Description("Whether to write rows as lightweight transactions that are only applied when a row with the same primary key does not already exist, by adding `IF NOT EXISTS` to the query, which must be an `INSERT` query.").
+				Version("4.62.0").
+				Advanced().
+				Default(false),
+			service.NewStringEnumField(coFieldSerialConsistency, "SERIAL", "LOCAL_SERIAL").
+				Description("The consistency level of the Paxos phase of lightweight transactions.").
+				Version("4.62.0").
+				Advanced().
+				Default("SERIAL"),
+			service.NewBoolField(coFieldIdempotent).
+				Description("Whether the query is idempotent, meaning it can be executed multiple times without changing its result. The driver only retries idempotent queries, so enabling this allows failed queries to be retried according to `"+cFieldMaxRetries+"` and `"+cFieldBackoff+"`. Queries that increment counters or append to collections are not idempotent.").
+				Version("4.62.0").
+				Advanced().
+				Default(false),
 			service.NewOutputMaxInFlightField(),
 			service.NewBatchPolicyField(coFieldBatching),
 		)
@@ -120,11 +156,15 @@ func init() {
 type cassandraWriter struct {
 	log *service.Logger
 
-	query       string
-	clientConf  clientConf
-	argsMapping *bloblang.Executor
-	batchType   gocql.BatchType
-	consistency gocql.Consistency
+	query             string
+	clientConf        clientConf
+	argsMapping       *bloblang.Executor
+	batchType         gocql.BatchType
+	consistency       gocql.Consistency
+	partitionBatching bool
+	ifNotExists       bool
+	serialConsistency gocql.SerialConsistency
+	idempotent        bool
 
 	session  *gocql.Session
 	connLock sync.RWMutex
@@ -162,9 +202,52 @@ func newCassandraWriter(conf *service.ParsedConfig, mgr *service.Resources) (c *
 		return nil, fmt.Errorf("parsing consistency: %w", err)
 	}
 
+	if c.partitionBatching, err = conf.FieldBool(coFieldPartitionBatching); err != nil {
+		return
+	}
+
+	if c.ifNotExists, err = conf.FieldBool(coFieldIfNotExists); err != nil {
+		return
+	}
+	if c.ifNotExists {
+		if c.query, err = withIfNotExists(c.query); err != nil {
+			return
+		}
+	}
+
+	var serialConsistencyStr string
+	if serialConsistencyStr, err = conf.FieldString(coFieldSerialConsistency); err != nil {
+		return
+	}
+	if err = c.serialConsistency.UnmarshalText([]byte(serialConsistencyStr)); err != nil {
+		return nil, fmt.Errorf("parsing serial consistency: %w", err)
+	}
+
+	if c.idempotent, err = conf.FieldBool(coFieldIdempotent); err != nil {
+		return
+	}
 	return
 }
 
+var usingClauseRegex = regexp.MustCompile(`(?i)\s+USING\s+(TTL|TIMESTAMP)\b`)
+
+// withIfNotExists adds an IF NOT EXISTS clause to an insert query, placing it
+// before any USING clause.
+func withIfNotExists(query string) (string, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	upper := strings.ToUpper(query)
+	if !strings.HasPrefix(upper, "INSERT") {
+		return "", errors.New("if_not_exists requires an INSERT query")
+	}
+	if strings.Contains(upper, "IF NOT EXISTS") {
+		return query, nil
+	}
+	if loc := usingClauseRegex.FindStringIndex(query); loc != nil {
+		return query[:loc[0]] + " IF NOT EXISTS" + query[loc[0]:], nil
+	}
+	return query + " IF NOT EXISTS", nil
+}
+
 func (c *cassandraWriter) Connect(context.Context) error {
 	c.connLock.Lock()
 	defer c.connLock.Unlock()
@@ -187,7 +270,7 @@ func (c *cassandraWriter) Connect(context.Context) error {
 	return nil
 }
 
-func (c *cassandraWriter) WriteBatch(_ context.Context, batch service.MessageBatch) error {
+func (c *cassandraWriter) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
 	c.connLock.RLock()
 	session := c.session
 	c.connLock.RUnlock()
@@ -196,40 +279,125 @@ func (c *cassandraWriter) WriteBatch(_ context.Context, batch service.MessageBat
 		return service.ErrNotConnected
 	}
 
-	if len(batch) == 1 {
-		return c.writeRow(session, batch)
-	}
-	return c.writeBatch(session, batch)
-}
-
-func (c *cassandraWriter) writeRow(session *gocql.Session, b service.MessageBatch) error {
 	var argsExec *service.MessageBatchBloblangExecutor
 	if c.argsMapping != nil {
-		argsExec = b.BloblangExecutor(c.argsMapping)
+		argsExec = batch.BloblangExecutor(c.argsMapping)
 	}
-	values, err := c.mapArgs(0, argsExec)
+	values := make([][]any, len(batch))
+	for i := range batch {
+		var err error
+		if values[i], err = c.mapArgs(i, argsExec); err != nil {
+			return fmt.Errorf("parsing args for part: %d: %w", i, err)
+		}
+	}
+
+	groups, err := c.groupMessages(session, values)
 	if err != nil {
-		return fmt.Errorf("parsing args: %w", err)
+		return err
 	}
-	return session.Query(c.query, values...).Exec()
+	if len(groups) == 1 {
+		return c.writeGroup(ctx, session, values, groups[0])
+	}
+
+	// Groups are written in parallel, and only the messages of failed groups
+	// are retried.
+	var batchErr *service.BatchError
+	var errMut sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.writeGroup(ctx, session, values, group)
+			if err == nil {
+				return
+			}
+			errMut.Lock()
+			defer errMut.Unlock()
+			if batchErr == nil {
+				batchErr = service.NewBatchError(batch, err)
+			}
+			for _, i := range group {
+				batchErr.Failed(i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
 }
 
-func (c *cassandraWriter) writeBatch(session *gocql.Session, b service.MessageBatch) error {
-	batch := session.NewBatch(c.batchType)
+// groupMessages returns the indexes of messages that should be written
+// together, where each message is written individually for lightweight
+// transactions and messages are grouped by partition when partition batching
+// is enabled.
+func (c *cassandraWriter) groupMessages(session *gocql.Session, values [][]any) ([][]int, error) {
+	if c.ifNotExists {
+		groups := make([][]int, len(values))
+		for i := range values {
+			groups[i] = []int{i}
+		}
+		return groups, nil
+	}
 
-	var argsExec *service.MessageBatchBloblangExecutor
-	if c.argsMapping != nil {
-		argsExec = b.BloblangExecutor(c.argsMapping)
+	if !c.partitionBatching || len(values) == 1 {
+		group := make([]int, len(values))
+		for i := range values {
+			group[i] = i
+		}
+		return [][]int{group}, nil
 	}
 
-	for i := range b {
-		values, err := c.mapArgs(i, argsExec)
+	var groups [][]int
+	groupIndexes := map[string]int{}
+	for i, v := range values {
+		q := session.Query(c.query, v...)
+		key, err := q.GetRoutingKey()
+		q.Release()
 		if err != nil {
-			return fmt.Errorf("parsing args for part: %d: %w", i, err)
+			return nil, fmt.Errorf("calculating partition key for part: %d: %w", i, err)
+		}
+		if gi, exists := groupIndexes[string(key)]; exists {
+			groups[gi] = append(groups[gi], i)
+		} else {
+			groupIndexes[string(key)] = len(groups)
+			groups = append(groups, []int{i})
+		}
+	}
+	return groups, nil
+}
+
+func (c *cassandraWriter) writeGroup(ctx context.Context, session *gocql.Session, values [][]any, group []int) error {
+	if len(group) == 1 {
+		q := session.Query(c.query, values[group[0]]...).
+			WithContext(ctx).
+			Idempotent(c.idempotent)
+		defer q.Release()
+		if !c.ifNotExists {
+			return q.Exec()
+		}
+
+		applied, err := q.SerialConsistency(c.serialConsistency).MapScanCAS(map[string]any{})
+		if err != nil {
+			return err
 		}
-		batch.Query(c.query, values...)
+		if !applied {
+			c.log.Debug("Skipping row that already exists")
+		}
+		return nil
 	}
 
+	batch := session.NewBatch(c.batchType).WithContext(ctx)
+	for _, i := range group {
+		batch.Entries = append(batch.Entries, gocql.BatchEntry{
+			Stmt:       c.query,
+			Args:       values[i],
+			Idempotent: c.idempotent,
+		})
+	}
 	return session.ExecuteBatch(batch)
 }
 
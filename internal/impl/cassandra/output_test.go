@@ -0,0 +1,87 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestWithIfNotExists(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{
+			query:    "INSERT INTO foo.bar (id, content) VALUES (?, ?)",
+			expected: "INSERT INTO foo.bar (id, content) VALUES (?, ?) IF NOT EXISTS",
+		},
+		{
+			query:    "insert into foo.bar (id, content) values (?, ?);\n",
+			expected: "insert into foo.bar (id, content) values (?, ?) IF NOT EXISTS",
+		},
+		{
+			query:    "INSERT INTO foo.bar (id, content) VALUES (?, ?) USING TTL 86400",
+			expected: "INSERT INTO foo.bar (id, content) VALUES (?, ?) IF NOT EXISTS USING TTL 86400",
+		},
+		{
+			query:    "INSERT INTO foo.bar JSON ? IF NOT EXISTS",
+			expected: "INSERT INTO foo.bar JSON ? IF NOT EXISTS",
+		},
+		{
+			query: "UPDATE foo.bar SET content = ? WHERE id = ?",
+			err:   "if_not_exists requires an INSERT query",
+		},
+	} {
+		t.Run(test.query, func(t *testing.T) {
+			query, err := withIfNotExists(test.query)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, query)
+		})
+	}
+}
+
+func TestOutputConfig(t *testing.T) {
+	conf, err := outputSpec().ParseYAML(`
+addresses: [ localhost:9042 ]
+query: INSERT INTO foo.bar (id, content) VALUES (?, ?)
+args_mapping: 'root = [ this.id, this.content ]'
+logged_batch: false
+partition_batching: true
+if_not_exists: true
+serial_consistency: LOCAL_SERIAL
+idempotent: true
+`, nil)
+	require.NoError(t, err)
+
+	w, err := newCassandraWriter(conf, service.MockResources())
+	require.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO foo.bar (id, content) VALUES (?, ?) IF NOT EXISTS", w.query)
+	assert.Equal(t, gocql.UnloggedBatch, w.batchType)
+	assert.Equal(t, gocql.LocalSerial, w.serialConsistency)
+	assert.True(t, w.partitionBatching)
+	assert.True(t, w.idempotent)
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"errors"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func init() {
+	cborParseSpec := bloblang.NewPluginSpec().
+		Category("Parsing").
+		Version("4.62.0").
+		Description("Parses a https://cbor.io/[CBOR^] data item into a structured document. Tags are discarded.").
+		Example("",
+			`root = this.encoded.decode("hex").parse_cbor()`,
+			[2]string{
+				`{"encoded":"a263666f6f636261726474656d701864"}`,
+				`{"foo":"bar","temp":100}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"parse_cbor", cborParseSpec,
+		func(*bloblang.ParsedParams) (bloblang.Method, error) {
+			return func(v any) (any, error) {
+				b, err := bloblang.ValueAsBytes(v)
+				if err != nil {
+					return nil, err
+				}
+				res, _, err := decodeCBOR(b)
+				return res, err
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	cborFormatSpec := bloblang.NewPluginSpec().
+		Category("Parsing").
+		Version("4.62.0").
+		Description("Formats data as a https://cbor.io/[CBOR^] data item in bytes format.").
+		Param(bloblang.NewInt64Param("tag").
+			Description("An optional tag number to wrap the data item in.").
+			Optional()).
+		Example("",
+			`root.encoded = this.format_cbor().encode("hex")`,
+			[2]string{
+				`{"foo":"bar","temp":100}`,
+				`{"encoded":"a263666f6f636261726474656d701864"}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"format_cbor", cborFormatSpec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			tagArg, err := args.GetOptionalInt64("tag")
+			if err != nil {
+				return nil, err
+			}
+			var tag *uint64
+			if tagArg != nil {
+				if *tagArg < 0 {
+					return nil, errors.New("tag must not be negative")
+				}
+				t := uint64(*tagArg)
+				tag = &t
+			}
+			return func(v any) (any, error) {
+				return encodeCBOR(v, tag)
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	decMode cbor.DecMode
+	encMode cbor.EncMode
+)
+
+func init() {
+	var err error
+	if decMode, err = (cbor.DecOptions{
+		BigIntDec: cbor.BigIntDecodePointer,
+	}).DecMode(); err != nil {
+		panic(err)
+	}
+
+	encOpts := cbor.CoreDetEncOptions()
+	encOpts.Time = cbor.TimeRFC3339Nano
+	encOpts.TimeTag = cbor.EncTagRequired
+	if encMode, err = encOpts.EncMode(); err != nil {
+		panic(err)
+	}
+}
+
+// decodeCBOR decodes a CBOR data item into a structured value. When the data
+// item is tagged with a tag that is not natively understood (such as a
+// timestamp or a big number) the number of the tag is returned along with the
+// decoded content. Tags of nested data items are discarded.
+func decodeCBOR(b []byte) (v any, tag *uint64, err error) {
+	var raw any
+	if err := decMode.Unmarshal(b, &raw); err != nil {
+		return nil, nil, err
+	}
+	if t, ok := raw.(cbor.Tag); ok {
+		tag = &t.Number
+		raw = t.Content
+	}
+	if v, err = normalise(raw); err != nil {
+		return nil, nil, err
+	}
+	return v, tag, nil
+}
+
+func normalise(v any) (any, error) {
+	switch t := v.(type) {
+	case uint64:
+		if t <= math.MaxInt64 {
+			return int64(t), nil
+		}
+		return json.Number(fmt.Sprintf("%d", t)), nil
+	case *big.Int:
+		return json.Number(t.String()), nil
+	case big.Int:
+		return json.Number(t.String()), nil
+	case cbor.Tag:
+		return normalise(t.Content)
+	case cbor.RawTag:
+		var content any
+		if err := decMode.Unmarshal(t.Content, &content); err != nil {
+			return nil, err
+		}
+		return normalise(content)
+	case []any:
+		for i, e := range t {
+			var err error
+			if t[i], err = normalise(e); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case map[any]any:
+		obj := make(map[string]any, len(t))
+		for k, e := range t {
+			var err error
+			if obj[fmt.Sprint(k)], err = normalise(e); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+	return v, nil
+}
+
+// encodeCBOR encodes a structured value as a deterministic CBOR data item,
+// optionally wrapped in a tag.
+func encodeCBOR(v any, tag *uint64) ([]byte, error) {
+	v, err := denormalise(v)
+	if err != nil {
+		return nil, err
+	}
+	if tag != nil {
+		v = cbor.Tag{Number: *tag, Content: v}
+	}
+	return encMode.Marshal(v)
+}
+
+func denormalise(v any) (any, error) {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		if i, ok := new(big.Int).SetString(string(t), 10); ok {
+			return i, nil
+		}
+		return t.Float64()
+	case []any:
+		arr := make([]any, len(t))
+		for i, e := range t {
+			var err error
+			if arr[i], err = denormalise(e); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case map[string]any:
+		obj := make(map[string]any, len(t))
+		for k, e := range t {
+			var err error
+			if obj[k], err = denormalise(e); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	cpFieldOperator = "operator"
+
+	tagMetaKey = "cbor_tag"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Summary("Converts messages to or from the https://cbor.io/[CBOR^] format.").
+		Description(`
+When the ` + "`to_json`" + ` operator decodes a data item wrapped in a tag that has no natural JSON representation, such as a vendor specific tag, the number of the tag is added to the message as the metadata field ` + "`" + tagMetaKey + "`" + ` and the content of the tag becomes the message. Tags of nested data items are discarded. Conversely, the ` + "`from_json`" + ` operator wraps the encoded data item in the tag found within the metadata field ` + "`" + tagMetaKey + "`" + ` when present, and therefore tags survive a round trip through both operators.
+
+== Type mapping
+
+Timestamps (tags 0 and 1) are converted into RFC 3339 strings, big numbers (tags 2 and 3) and integers that do not fit within 64 bits into JSON numbers without a loss of precision, byte strings into bytes, and map keys that are not strings into their string representation. When converting from JSON, maps are encoded with sorted keys and numbers with their smallest representation, following the core deterministic encoding requirements of RFC 8949.
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(cpFieldOperator, map[string]string{
+				"to_json":   "Convert CBOR messages to JSON format",
+				"from_json": "Convert JSON messages to CBOR format",
+			}).Description("The operation to perform on messages."),
+		).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"cbor", processorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			return newProcessorFromConfig(conf)
+		})
+}
+
+type cborOperator func(m *service.Message) error
+
+func metaTag(m *service.Message) (*uint64, error) {
+	v, exists := m.MetaGetMut(tagMetaKey)
+	if !exists {
+		return nil, nil
+	}
+	var tag uint64
+	switch t := v.(type) {
+	case uint64:
+		tag = t
+	case int64:
+		if t < 0 {
+			return nil, fmt.Errorf("metadata field %v must not be negative", tagMetaKey)
+		}
+		tag = uint64(t)
+	case string:
+		var err error
+		if tag, err = strconv.ParseUint(t, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata field %v: %w", tagMetaKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("metadata field %v must be an integer, got %T", tagMetaKey, v)
+	}
+	return &tag, nil
+}
+
+func strToCBOROperator(opStr string) (cborOperator, error) {
+	switch opStr {
+	case "to_json":
+		return func(m *service.Message) error {
+			mBytes, err := m.AsBytes()
+			if err != nil {
+				return err
+			}
+
+			v, tag, err := decodeCBOR(mBytes)
+			if err != nil {
+				return fmt.Errorf("failed to convert CBOR document to JSON: %v", err)
+			}
+
+			m.MetaDelete(tagMetaKey)
+			if tag != nil {
+				m.MetaSetMut(tagMetaKey, int64(*tag))
+			}
+			m.SetStructuredMut(v)
+			return nil
+		}, nil
+	case "from_json":
+		return func(m *service.Message) error {
+			jObj, err := m.AsStructured()
+			if err != nil {
+				return fmt.Errorf("failed to parse message as JSON: %v", err)
+			}
+
+			tag, err := metaTag(m)
+			if err != nil {
+				return err
+			}
+
+			b, err := encodeCBOR(jObj, tag)
+			if err != nil {
+				return fmt.Errorf("failed to convert JSON to CBOR: %v", err)
+			}
+
+			m.SetBytes(b)
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("operator not recognised: %v", opStr)
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	operator cborOperator
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig) (*processor, error) {
+	operatorStr, err := conf.FieldString(cpFieldOperator)
+	if err != nil {
+		return nil, err
+	}
+	return newProcessor(operatorStr)
+}
+
+func newProcessor(operatorStr string) (*processor, error) {
+	operator, err := strToCBOROperator(operatorStr)
+	if err != nil {
+		return nil, err
+	}
+	return &processor{
+		operator: operator,
+	}, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	if err := p.operator(msg); err != nil {
+		return nil, err
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (*processor) Close(context.Context) error {
+	return nil
+}
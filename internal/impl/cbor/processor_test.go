@@ -0,0 +1,155 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestCBORToJSON(t *testing.T) {
+	type testCase struct {
+		name           string
+		hexInput       string
+		expectedOutput any
+		expectedTag    any
+	}
+
+	tests := []testCase{
+		{
+			name:     "basic",
+			hexInput: "a563666f6f636261726474656d701864656172726179820102656279746573426869666e65737465648101",
+			expectedOutput: map[string]any{
+				"foo":    "bar",
+				"temp":   int64(100),
+				"array":  []any{int64(1), int64(2)},
+				"bytes":  []byte("hi"),
+				"nested": []any{int64(1)},
+			},
+		},
+		{
+			name:           "integer keys",
+			hexInput:       "a2011864026162",
+			expectedOutput: map[string]any{"1": int64(100), "2": "b"},
+		},
+		{
+			name:           "big numbers",
+			hexInput:       "821bffffffffffffffffc249010000000000000000",
+			expectedOutput: []any{json.Number("18446744073709551615"), json.Number("18446744073709551616")},
+		},
+		{
+			name:           "timestamp",
+			hexInput:       "c11a65937f25",
+			expectedOutput: time.Unix(1704165157, 0).UTC(),
+		},
+		{
+			name:           "vendor tag",
+			hexInput:       "d99c40a16269640a",
+			expectedOutput: map[string]any{"id": int64(10)},
+			expectedTag:    int64(40000),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proc, err := newProcessor("to_json")
+			require.NoError(t, err)
+
+			inputBytes, err := hex.DecodeString(test.hexInput)
+			require.NoError(t, err)
+
+			msgs, err := proc.Process(t.Context(), service.NewMessage(inputBytes))
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+
+			act, err := msgs[0].AsStructured()
+			require.NoError(t, err)
+			if ts, ok := act.(time.Time); ok {
+				act = ts.UTC()
+			}
+			assert.Equal(t, test.expectedOutput, act)
+
+			tag, exists := msgs[0].MetaGetMut(tagMetaKey)
+			if test.expectedTag == nil {
+				assert.False(t, exists)
+			} else {
+				assert.Equal(t, test.expectedTag, tag)
+			}
+		})
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	fromJSON, err := newProcessor("from_json")
+	require.NoError(t, err)
+
+	toJSON, err := newProcessor("to_json")
+	require.NoError(t, err)
+
+	input := service.NewMessage([]byte(`{"temp":100,"id":"foo","big":18446744073709551616,"ratio":0.5,"tags":["a",null,true]}`))
+	input.MetaSetMut(tagMetaKey, "40000")
+
+	msgs, err := fromJSON.Process(t.Context(), input)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	msgs, err = toJSON.Process(t.Context(), msgs[0])
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	act, err := msgs[0].AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"temp":100,"id":"foo","big":18446744073709551616,"ratio":0.5,"tags":["a",null,true]}`, string(act))
+
+	tag, exists := msgs[0].MetaGetMut(tagMetaKey)
+	require.True(t, exists)
+	assert.Equal(t, int64(40000), tag)
+}
+
+func TestCBORFromJSONDeterministic(t *testing.T) {
+	proc, err := newProcessor("from_json")
+	require.NoError(t, err)
+
+	msgs, err := proc.Process(t.Context(), service.NewMessage([]byte(`{"temp":100,"foo":"bar"}`)))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	b, err := msgs[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "a263666f6f636261726474656d701864", hex.EncodeToString(b))
+}
+
+func TestCBORBloblang(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.format_cbor(tag: 100).parse_cbor()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(map[string]any{"foo": "bar", "n": int64(5)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"foo": "bar", "n": int64(5)}, res)
+
+	exec, err = bloblang.Parse(`root = content().parse_cbor()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte{0xa2, 0x01})
+	require.Error(t, err)
+}
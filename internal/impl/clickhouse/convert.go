@@ -0,0 +1,263 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// convertValue converts a value of a structured message into the Go type that
+// a column of the table scans into, which is derived from the type of the
+// column in the table DDL. Values that cannot be converted are returned as is
+// so that the driver has a chance to convert them itself.
+func convertValue(v any, typ reflect.Type) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		elem, err := convertValue(v, typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		ev := reflect.ValueOf(elem)
+		if !ev.Type().AssignableTo(typ.Elem()) {
+			return elem, nil
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(ev)
+		return ptr.Interface(), nil
+	}
+
+	if typ == timeType {
+		return toTime(v)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		switch t := v.(type) {
+		case bool:
+			return t, nil
+		case string:
+			return strconv.ParseBool(t)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt(v)
+		if err != nil {
+			f, ferr := toFloat(v)
+			if ferr != nil {
+				return nil, err
+			}
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("value %v is not an integer", f)
+			}
+			i = int64(f)
+		}
+		res := reflect.New(typ).Elem()
+		if res.OverflowInt(i) {
+			return nil, fmt.Errorf("value %v overflows %v", i, typ)
+		}
+		res.SetInt(i)
+		return res.Interface(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := toUint(v)
+		if err != nil {
+			return nil, err
+		}
+		res := reflect.New(typ).Elem()
+		if res.OverflowUint(u) {
+			return nil, fmt.Errorf("value %v overflows %v", u, typ)
+		}
+		res.SetUint(u)
+		return res.Interface(), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		res := reflect.New(typ).Elem()
+		res.SetFloat(f)
+		return res.Interface(), nil
+	case reflect.String:
+		switch t := v.(type) {
+		case string:
+			return t, nil
+		case []byte:
+			return string(t), nil
+		case json.Number:
+			return t.String(), nil
+		default:
+			b, err := json.Marshal(t)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		}
+	case reflect.Slice:
+		arr, ok := v.([]any)
+		if !ok {
+			break
+		}
+		res := reflect.MakeSlice(typ, len(arr), len(arr))
+		for i, e := range arr {
+			ce, err := convertValue(e, typ.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("element %v: %w", i, err)
+			}
+			if ce == nil {
+				continue
+			}
+			ev := reflect.ValueOf(ce)
+			if !ev.Type().AssignableTo(typ.Elem()) {
+				return v, nil
+			}
+			res.Index(i).Set(ev)
+		}
+		return res.Interface(), nil
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+		res := reflect.MakeMapWithSize(typ, len(obj))
+		for k, e := range obj {
+			ck, err := convertValue(k, typ.Key())
+			if err != nil {
+				return nil, fmt.Errorf("key %v: %w", k, err)
+			}
+			ce, err := convertValue(e, typ.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("value of key %v: %w", k, err)
+			}
+			kv, ev := reflect.ValueOf(ck), reflect.Zero(typ.Elem())
+			if ce != nil {
+				ev = reflect.ValueOf(ce)
+			}
+			if !kv.Type().AssignableTo(typ.Key()) || !ev.Type().AssignableTo(typ.Elem()) {
+				return v, nil
+			}
+			res.SetMapIndex(kv, ev)
+		}
+		return res.Interface(), nil
+	case reflect.Struct:
+		// Types such as decimals are represented by structs, which the driver
+		// accepts in their string form.
+		switch t := v.(type) {
+		case json.Number:
+			return t.String(), nil
+		case float64:
+			return strconv.FormatFloat(t, 'f', -1, 64), nil
+		case int64:
+			return strconv.FormatInt(t, 10), nil
+		case int:
+			return strconv.Itoa(t), nil
+		}
+	}
+	return v, nil
+}
+
+func toInt(v any) (int64, error) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case uint64:
+		if t > math.MaxInt64 {
+			return 0, fmt.Errorf("value %v overflows int64", t)
+		}
+		return int64(t), nil
+	case json.Number:
+		return t.Int64()
+	case string:
+		return strconv.ParseInt(t, 10, 64)
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("expected an integer, got %T", v)
+}
+
+func toUint(v any) (uint64, error) {
+	switch t := v.(type) {
+	case uint64:
+		return t, nil
+	case json.Number:
+		if u, err := strconv.ParseUint(t.String(), 10, 64); err == nil {
+			return u, nil
+		}
+	case string:
+		return strconv.ParseUint(t, 10, 64)
+	}
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 || f != math.Trunc(f) {
+		return 0, fmt.Errorf("value %v is not an unsigned integer", f)
+	}
+	return uint64(f), nil
+}
+
+func toFloat(v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", v)
+}
+
+// toTime converts RFC 3339 strings and numeric unix timestamps in seconds to
+// times.
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(time.RFC3339Nano, t)
+	}
+	f, err := toFloat(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a timestamp, got %T", v)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
@@ -12,19 +12,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	cohere "github.com/cohere-ai/cohere-go/v2"
 
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 
+	"github.com/redpanda-data/connect/v4/internal/impl/text/langdetect"
 	"github.com/redpanda-data/connect/v4/internal/license"
 )
 
 const (
-	oepFieldTextMapping = "text_mapping"
-	oepFieldInputType   = "input_type"
-	oepFieldDimensions  = "dimensions"
+	oepFieldTextMapping  = "text_mapping"
+	oepFieldImageMapping = "image_mapping"
+	oepFieldMaxImageSize = "max_image_size"
+	oepFieldInputType    = "input_type"
+	oepFieldDimensions   = "dimensions"
+	oepFieldDetectLang   = "detect_language"
+	oepFieldLangModels   = "language_models"
 )
 
 func init() {
@@ -42,10 +49,15 @@ func embeddingProcessorConfig() *service.ConfigSpec {
 		Description(`
 This processor sends text strings to the Cohere API, which generates vector embeddings. By default, the processor submits the entire payload of each message as a string, unless you use the `+"`"+oepFieldTextMapping+"`"+` configuration field to customize it.
 
+When `+"`"+oepFieldImageMapping+"`"+` is set the processor also submits an image, which can be provided as raw bytes, a base64 data URI or an HTTP(S) URL to download the image from. Raw bytes and downloaded images are base64 encoded automatically. If both a text and an image are provided they are embedded together as a single multimodal input, which requires a multimodal model such as `+"`embed-v4.0`"+`. When only `+"`"+oepFieldImageMapping+"`"+` is set the payload of the message is not submitted as text.
+
+When `+"`"+oepFieldDetectLang+"`"+` is enabled, or `+"`"+oepFieldLangModels+"`"+` is set, the language of the text is detected locally and added to the resulting message as the metadata field `+"`language`"+` in the form of an ISO 639-1 code, or `+"`und`"+` when the language cannot be determined. This allows mixed-language corpora to be embedded by the model variant best suited to each language.
+
 To learn more about vector embeddings, see the https://docs.cohere.com/docs/embeddings[Cohere API documentation^].`).
 		Version("4.37.0").
 		Fields(
 			baseConfigFieldsWithModels(
+				"embed-v4.0",
 				"embed-english-v3.0",
 				"embed-english-light-v3.0",
 				"embed-multilingual-v3.0",
@@ -56,6 +68,17 @@ To learn more about vector embeddings, see the https://docs.cohere.com/docs/embe
 			service.NewBloblangField(oepFieldTextMapping).
 				Description("The text you want to generate a vector embedding for. By default, the processor submits the entire payload as a string.").
 				Optional(),
+			service.NewBloblangField(oepFieldImageMapping).
+				Description("An optional image you want to generate a vector embedding for. The mapping can result in raw image bytes, a base64 encoded data URI or an HTTP(S) URL of an image. Supported image types are JPEG, PNG, GIF and WEBP.").
+				Example(`root = content()`).
+				Example(`root = this.image_url`).
+				Version("4.62.0").
+				Optional(),
+			service.NewIntField(oepFieldMaxImageSize).
+				Description("The maximum size in bytes of an image. Images larger than this are rejected before being sent to the Cohere API.").
+				Default(5*1024*1024).
+				Version("4.62.0").
+				Advanced(),
 			service.NewStringAnnotatedEnumField(oepFieldInputType, map[string]string{
 				"search_document": "Used for embeddings stored in a vector database for search use-cases.",
 				"search_query":    "Used for embeddings of search queries run against a vector DB to find relevant documents.",
@@ -67,6 +90,17 @@ To learn more about vector embeddings, see the https://docs.cohere.com/docs/embe
 			service.NewIntField(oepFieldDimensions).
 				Optional().
 				Description("The number of dimensions of the output embedding. This is only available for embed-v4 and newer models. Possible values are 256, 512, 1024, and 1536."),
+			service.NewBoolField(oepFieldDetectLang).
+				Description("Whether to detect the language of the text and add it to the resulting message as the metadata field `language`.").
+				Default(false).
+				Version("4.62.0").
+				Advanced(),
+			service.NewStringMapField(oepFieldLangModels).
+				Description("An optional map of ISO 639-1 language codes to the model used for texts detected to be in that language. Texts in any other language, or whose language cannot be determined, are embedded with `model`. Setting this field enables language detection.").
+				Example(map[string]any{"en": "embed-english-v3.0", "fr": "embed-multilingual-v3.0"}).
+				Version("4.62.0").
+				Optional().
+				Advanced(),
 		).
 		Example(
 			"Store embedding vectors in Qdrant",
@@ -106,6 +140,17 @@ func makeEmbeddingsProcessor(conf *service.ParsedConfig, mgr *service.Resources)
 			return nil, err
 		}
 	}
+	var img *bloblang.Executor
+	if conf.Contains(oepFieldImageMapping) {
+		img, err = conf.FieldBloblang(oepFieldImageMapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+	maxImageSize, err := conf.FieldInt(oepFieldMaxImageSize)
+	if err != nil {
+		return nil, err
+	}
 	var et cohere.EmbedInputType
 	v, err := conf.FieldString(oepFieldInputType)
 	if err != nil {
@@ -127,15 +172,90 @@ func makeEmbeddingsProcessor(conf *service.ParsedConfig, mgr *service.Resources)
 		}
 		dims = &dimensions
 	}
-	return &embeddingsProcessor{b, t, et, dims}, nil
+	detectLang, err := conf.FieldBool(oepFieldDetectLang)
+	if err != nil {
+		return nil, err
+	}
+	var langModels map[string]string
+	if conf.Contains(oepFieldLangModels) {
+		if langModels, err = conf.FieldStringMap(oepFieldLangModels); err != nil {
+			return nil, err
+		}
+		detectLang = true
+	}
+	return &embeddingsProcessor{
+		baseProcessor: b,
+		text:          t,
+		image:         img,
+		images: &imageLoader{
+			client:  &http.Client{Timeout: 30 * time.Second},
+			maxSize: int64(maxImageSize),
+		},
+		inputType:  et,
+		dimensions: dims,
+		detectLang: detectLang,
+		langModels: langModels,
+	}, nil
 }
 
 type embeddingsProcessor struct {
 	*baseProcessor
 
 	text       *bloblang.Executor
+	image      *bloblang.Executor
+	images     *imageLoader
 	inputType  cohere.EmbedInputType
 	dimensions *int
+	detectLang bool
+	langModels map[string]string
+}
+
+// routeText detects the language of a text when enabled and updates the
+// model of the request according to the configured language models.
+func (p *embeddingsProcessor) routeText(body *cohere.V2EmbedRequest, text string) string {
+	if !p.detectLang {
+		return ""
+	}
+	lang := langdetect.Detect(text).Language
+	if model, ok := p.langModels[lang]; ok {
+		body.Model = model
+	}
+	return lang
+}
+
+func (p *embeddingsProcessor) textInput(msg *service.Message) (string, error) {
+	if p.text == nil {
+		b, err := msg.AsBytes()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	s, err := msg.BloblangQuery(p.text)
+	if err != nil {
+		return "", fmt.Errorf("%s execution error: %w", oepFieldTextMapping, err)
+	}
+	r, err := s.AsBytes()
+	if err != nil {
+		return "", fmt.Errorf("%s extraction error: %w", oepFieldTextMapping, err)
+	}
+	return string(r), nil
+}
+
+func (p *embeddingsProcessor) imageInput(ctx context.Context, msg *service.Message) (string, error) {
+	s, err := msg.BloblangQuery(p.image)
+	if err != nil {
+		return "", fmt.Errorf("%s execution error: %w", oepFieldImageMapping, err)
+	}
+	v, err := s.AsBytes()
+	if err != nil {
+		return "", fmt.Errorf("%s extraction error: %w", oepFieldImageMapping, err)
+	}
+	uri, err := p.images.toDataURI(ctx, v)
+	if err != nil {
+		return "", fmt.Errorf("%s error: %w", oepFieldImageMapping, err)
+	}
+	return uri, nil
 }
 
 func (p *embeddingsProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
@@ -144,22 +264,32 @@ func (p *embeddingsProcessor) Process(ctx context.Context, msg *service.Message)
 	body.InputType = p.inputType
 	body.OutputDimension = p.dimensions
 	body.EmbeddingTypes = []cohere.EmbeddingType{cohere.EmbeddingTypeFloat}
-	if p.text != nil {
-		s, err := msg.BloblangQuery(p.text)
+	var lang string
+	if p.image == nil {
+		text, err := p.textInput(msg)
 		if err != nil {
-			return nil, fmt.Errorf("%s execution error: %w", oepFieldTextMapping, err)
-		}
-		r, err := s.AsBytes()
-		if err != nil {
-			return nil, fmt.Errorf("%s extraction error: %w", oepFieldTextMapping, err)
+			return nil, err
 		}
-		body.Texts = append(body.Texts, string(r))
+		lang = p.routeText(&body, text)
+		body.Texts = append(body.Texts, text)
 	} else {
-		b, err := msg.AsBytes()
+		uri, err := p.imageInput(ctx, msg)
 		if err != nil {
 			return nil, err
 		}
-		body.Texts = append(body.Texts, string(b))
+		var content []*cohere.EmbedContent
+		if p.text != nil {
+			text, err := p.textInput(msg)
+			if err != nil {
+				return nil, err
+			}
+			lang = p.routeText(&body, text)
+			content = append(content, &cohere.EmbedContent{Text: &cohere.EmbedText{Text: &text}})
+		}
+		content = append(content, &cohere.EmbedContent{
+			ImageUrl: &cohere.EmbedImage{ImageUrl: &cohere.EmbedImageUrl{Url: uri}},
+		})
+		body.Inputs = append(body.Inputs, &cohere.EmbedInput{Content: content})
 	}
 	resp, err := p.client.Embed(ctx, &body)
 	if err != nil {
@@ -178,5 +308,8 @@ func (p *embeddingsProcessor) Process(ctx context.Context, msg *service.Message)
 	}
 	msg = msg.Copy()
 	msg.SetStructuredMut(data)
+	if lang != "" {
+		msg.MetaSetMut("language", lang)
+	}
 	return service.MessageBatch{msg}, nil
 }
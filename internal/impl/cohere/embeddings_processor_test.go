@@ -0,0 +1,200 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cohere
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01")
+
+func embedServer(t *testing.T, requests chan<- map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/image.png" {
+			_, _ = w.Write(testPNG)
+			return
+		}
+		require.Equal(t, "/v2/embed", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests <- body
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"foo","embeddings":{"float":[[0.5,0.25]]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestEmbeddingsProcessor(t *testing.T, yamlStr string) service.Processor {
+	t.Helper()
+	conf, err := embeddingProcessorConfig().ParseYAML(yamlStr, nil)
+	require.NoError(t, err)
+
+	resources := service.MockResources()
+	license.InjectTestService(resources)
+	proc, err := makeEmbeddingsProcessor(conf, resources)
+	require.NoError(t, err)
+	return proc
+}
+
+func TestCohereEmbeddingsImageInputs(t *testing.T) {
+	requests := make(chan map[string]any, 1)
+	srv := embedServer(t, requests)
+	pngURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG)
+
+	tests := []struct {
+		name     string
+		mappings string
+		input    []byte
+		expected any
+	}{
+		{
+			name:     "raw bytes",
+			mappings: `image_mapping: root = content()`,
+			input:    testPNG,
+			expected: []any{
+				map[string]any{"content": []any{
+					map[string]any{"type": "image_url", "image_url": map[string]any{"url": pngURI}},
+				}},
+			},
+		},
+		{
+			name:     "url with text",
+			mappings: "text_mapping: root = this.caption\nimage_mapping: root = this.url",
+			input:    fmt.Appendf(nil, `{"caption":"a tiny image","url":"%s/image.png"}`, srv.URL),
+			expected: []any{
+				map[string]any{"content": []any{
+					map[string]any{"type": "text", "text": "a tiny image"},
+					map[string]any{"type": "image_url", "image_url": map[string]any{"url": pngURI}},
+				}},
+			},
+		},
+		{
+			name:     "data uri",
+			mappings: `image_mapping: root = this.uri`,
+			input:    fmt.Appendf(nil, `{"uri":"%s"}`, pngURI),
+			expected: []any{
+				map[string]any{"content": []any{
+					map[string]any{"type": "image_url", "image_url": map[string]any{"url": pngURI}},
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proc := newTestEmbeddingsProcessor(t, fmt.Sprintf(`
+base_url: %s
+api_key: test-key
+model: embed-v4.0
+%s
+`, srv.URL, test.mappings))
+
+			batch, err := proc.Process(t.Context(), service.NewMessage(test.input))
+			require.NoError(t, err)
+			require.Len(t, batch, 1)
+
+			body := <-requests
+			assert.Equal(t, test.expected, body["inputs"])
+			assert.Nil(t, body["texts"])
+
+			v, err := batch[0].AsStructured()
+			require.NoError(t, err)
+			assert.Equal(t, []any{0.5, 0.25}, v)
+		})
+	}
+}
+
+func TestCohereEmbeddingsImageValidation(t *testing.T) {
+	srv := embedServer(t, make(chan map[string]any, 1))
+
+	tests := []struct {
+		name        string
+		config      string
+		input       []byte
+		expectedErr string
+	}{
+		{
+			name:        "too large",
+			config:      "max_image_size: 8",
+			input:       testPNG,
+			expectedErr: "exceeds the maximum of 8 bytes",
+		},
+		{
+			name:        "unsupported type",
+			input:       []byte("definitely not an image"),
+			expectedErr: "unsupported image type",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proc := newTestEmbeddingsProcessor(t, fmt.Sprintf(`
+base_url: %s
+api_key: test-key
+model: embed-v4.0
+image_mapping: root = content()
+%s
+`, srv.URL, test.config))
+
+			_, err := proc.Process(t.Context(), service.NewMessage(test.input))
+			require.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestCohereEmbeddingsLanguageRouting(t *testing.T) {
+	requests := make(chan map[string]any, 1)
+	srv := embedServer(t, requests)
+
+	proc := newTestEmbeddingsProcessor(t, fmt.Sprintf(`
+base_url: %s
+api_key: test-key
+model: embed-multilingual-v3.0
+language_models:
+  en: embed-english-v3.0
+`, srv.URL))
+
+	tests := []struct {
+		input         string
+		expectedModel string
+		expectedLang  string
+	}{
+		{"The weather is nice and the sun is out.", "embed-english-v3.0", "en"},
+		{"Le temps est beau et le soleil est là.", "embed-multilingual-v3.0", "fr"},
+		{"12345", "embed-multilingual-v3.0", "und"},
+	}
+
+	for _, test := range tests {
+		batch, err := proc.Process(t.Context(), service.NewMessage([]byte(test.input)))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		body := <-requests
+		assert.Equal(t, test.expectedModel, body["model"], test.input)
+
+		lang, ok := batch[0].MetaGet("language")
+		require.True(t, ok)
+		assert.Equal(t, test.expectedLang, lang, test.input)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var supportedImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// imageLoader converts image inputs of various forms into data URIs that can
+// be sent to the Cohere API.
+type imageLoader struct {
+	client  *http.Client
+	maxSize int64
+}
+
+// toDataURI accepts either raw image bytes, a data URI or an http(s) URL
+// pointing to an image and returns a data URI containing the image.
+func (l *imageLoader) toDataURI(ctx context.Context, v []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(v, []byte("data:")):
+		return l.validateDataURI(string(v))
+	case bytes.HasPrefix(v, []byte("http://")), bytes.HasPrefix(v, []byte("https://")):
+		raw, err := l.fetch(ctx, string(bytes.TrimSpace(v)))
+		if err != nil {
+			return "", err
+		}
+		return l.encode(raw)
+	}
+	return l.encode(v)
+}
+
+func (l *imageLoader) encode(raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return "", errors.New("image is empty")
+	}
+	if l.maxSize > 0 && int64(len(raw)) > l.maxSize {
+		return "", fmt.Errorf("image size of %d bytes exceeds the maximum of %d bytes", len(raw), l.maxSize)
+	}
+	mimeType := http.DetectContentType(raw)
+	if !isSupportedImageType(mimeType) {
+		return "", fmt.Errorf("unsupported image type %q, expected one of %v", mimeType, supportedImageTypes)
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func (l *imageLoader) validateDataURI(uri string) (string, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return "", errors.New("image data URIs must be base64 encoded")
+	}
+	mimeType := strings.TrimSuffix(header, ";base64")
+	if !isSupportedImageType(mimeType) {
+		return "", fmt.Errorf("unsupported image type %q, expected one of %v", mimeType, supportedImageTypes)
+	}
+	if l.maxSize > 0 && int64(base64.StdEncoding.DecodedLen(len(data))) > l.maxSize+2 {
+		return "", fmt.Errorf("image size exceeds the maximum of %d bytes", l.maxSize)
+	}
+	return uri, nil
+}
+
+func (l *imageLoader) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching image: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("fetching image: unexpected status code %d", res.StatusCode)
+	}
+	body := io.Reader(res.Body)
+	if l.maxSize > 0 {
+		// Read one extra byte so that oversized images can be detected.
+		body = io.LimitReader(body, l.maxSize+1)
+	}
+	return io.ReadAll(body)
+}
+
+func isSupportedImageType(mimeType string) bool {
+	for _, t := range supportedImageTypes {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
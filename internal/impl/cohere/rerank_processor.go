@@ -10,6 +10,7 @@ package cohere
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"strconv"
@@ -27,6 +28,7 @@ const (
 	crpFieldQuery     = "query"
 	crpFieldTopN      = "top_n"
 	crpFieldMaxTokens = "max_tokens_per_doc"
+	crpFieldDedupe    = "deduplicate"
 )
 
 func init() {
@@ -60,6 +62,7 @@ The output of this processor is an array of objects, each containing a "document
 			service.NewBloblangField(crpFieldDocuments).Description("A list of texts that will be compared to the query. For optimal performance Cohere recommends against sending more than 1000 documents in a single request. NOTE: structured data should be formatted as YAML for best performance."),
 			service.NewInterpolatedStringField(crpFieldTopN).Default("0").Description("The number of documents to return, if 0 all documents are returned."),
 			service.NewIntField(crpFieldMaxTokens).Default(4096).Description("Long documents will be automatically truncated to the specified number of tokens."),
+			service.NewBoolField(crpFieldDedupe).Default(true).Advanced().Description("Whether to detect duplicate documents and only send unique documents to the API. Results are expanded back to every original index afterwards, with duplicates sharing the same relevance score, which reduces the tokens billed for candidate sets produced by overlapping retrievers.").Version("4.62.0"),
 		).
 		Example(
 			"Rerank some documents based on a query",
@@ -108,7 +111,11 @@ func makeRerankProcessor(conf *service.ParsedConfig, mgr *service.Resources) (se
 	if err != nil {
 		return nil, err
 	}
-	return &rerankProcessor{b, q, d, t, m}, nil
+	dd, err := conf.FieldBool(crpFieldDedupe)
+	if err != nil {
+		return nil, err
+	}
+	return &rerankProcessor{b, q, d, t, m, dd}, nil
 }
 
 type rerankProcessor struct {
@@ -118,6 +125,24 @@ type rerankProcessor struct {
 	documents *bloblang.Executor
 	topN      *service.InterpolatedString
 	maxTokens int
+	dedupe    bool
+}
+
+// dedupeDocuments returns the unique set of documents along with, for each
+// unique document, the indexes of the original documents it represents.
+func dedupeDocuments(docs []string) (unique []string, groups [][]int) {
+	seen := make(map[[sha256.Size]byte]int, len(docs))
+	for i, d := range docs {
+		h := sha256.Sum256([]byte(d))
+		if j, exists := seen[h]; exists {
+			groups[j] = append(groups[j], i)
+			continue
+		}
+		seen[h] = len(unique)
+		unique = append(unique, d)
+		groups = append(groups, []int{i})
+	}
+	return
 }
 
 func (p *rerankProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
@@ -156,23 +181,38 @@ func (p *rerankProcessor) Process(ctx context.Context, msg *service.Message) (se
 	if topNVal > 0 {
 		req.TopN = &topNVal
 	}
-	for _, d := range docs {
-		req.Documents = append(req.Documents, bloblang.ValueToString(d))
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = bloblang.ValueToString(d)
+	}
+	groups := make([][]int, len(texts))
+	if p.dedupe {
+		texts, groups = dedupeDocuments(texts)
+	} else {
+		for i := range groups {
+			groups[i] = []int{i}
+		}
 	}
+	req.Documents = texts
 	resp, err := p.client.Rerank(ctx, &req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to rerank documents: %w", err)
 	}
 	rerankedResults := []any{}
 	for _, result := range resp.Results {
-		if result.Index < 0 || result.Index >= len(docs) {
-			return nil, fmt.Errorf("invalid API response: out of range index %d for documents array of length %d", result.Index, len(docs))
+		if result.Index < 0 || result.Index >= len(texts) {
+			return nil, fmt.Errorf("invalid API response: out of range index %d for documents array of length %d", result.Index, len(texts))
 		}
-		rerankedResults = append(rerankedResults, map[string]any{
-			"document":        docs[result.Index],
-			"relevance_score": result.RelevanceScore,
-			"index":           result.Index, // Index within original documents list.
-		})
+		for _, i := range groups[result.Index] {
+			rerankedResults = append(rerankedResults, map[string]any{
+				"document":        docs[i],
+				"relevance_score": result.RelevanceScore,
+				"index":           i, // Index within original documents list.
+			})
+		}
+	}
+	if topNVal > 0 && len(rerankedResults) > topNVal {
+		rerankedResults = rerankedResults[:topNVal]
 	}
 	msg = msg.Copy()
 	msg.SetStructured(rerankedResults)
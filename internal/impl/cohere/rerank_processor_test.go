@@ -515,3 +515,46 @@ top_n: %s
 		})
 	}
 }
+
+func TestCohereRerankProcessorDeduplication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Documents []string `json:"documents"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"foo", "bar", "baz"}, req.Documents)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.5},{"index":2,"relevance_score":0.1}]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	conf, err := rerankProcessorConfig().ParseYAML(fmt.Sprintf(`
+base_url: %s
+api_key: test-key
+model: rerank-v3.5
+query: "${!this.query}"
+documents: "root = this.docs"
+top_n: 4
+`, server.URL), nil)
+	require.NoError(t, err)
+
+	resources := service.MockResources()
+	license.InjectTestService(resources)
+	proc, err := makeRerankProcessor(conf, resources)
+	require.NoError(t, err)
+
+	msgs, err := proc.Process(t.Context(), service.NewMessage([]byte(`{"query":"test","docs":["foo","bar","foo","baz","bar"]}`)))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	result, err := msgs[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"document": "bar", "relevance_score": 0.9, "index": 1},
+		map[string]any{"document": "bar", "relevance_score": 0.9, "index": 4},
+		map[string]any{"document": "foo", "relevance_score": 0.5, "index": 0},
+		map[string]any{"document": "foo", "relevance_score": 0.5, "index": 2},
+	}, result)
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// codecOptions are the settings shared by all algorithms, not all of which
+// apply to every algorithm.
+type codecOptions struct {
+	level              int
+	zstdDictionary     []byte
+	maxDecompressedLen int
+}
+
+// codec compresses and decompresses either whole payloads or streams.
+type codec interface {
+	compress(b []byte) ([]byte, error)
+	decompress(b []byte) ([]byte, error)
+	newReader(r io.Reader) (io.ReadCloser, error)
+	close()
+}
+
+func newCodec(algorithm string, opts codecOptions) (codec, error) {
+	if algorithm != "zstd" && len(opts.zstdDictionary) > 0 {
+		return nil, fmt.Errorf("a zstd dictionary cannot be used with the %v algorithm", algorithm)
+	}
+	switch algorithm {
+	case "zstd":
+		return newZstdCodec(opts)
+	case "snappy":
+		return snappyBlockCodec{}, nil
+	case "snappy_framed":
+		return snappyFramedCodec{}, nil
+	case "lz4_block":
+		return lz4BlockCodec{level: opts.level, maxDecompressedLen: opts.maxDecompressedLen}, nil
+	case "lz4_frame":
+		return lz4FrameCodec{level: opts.level}, nil
+	}
+	return nil, fmt.Errorf("compression algorithm not recognised: %v", algorithm)
+}
+
+//------------------------------------------------------------------------------
+
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	dOpts   []zstd.DOption
+}
+
+func newZstdCodec(opts codecOptions) (*zstdCodec, error) {
+	eOpts := []zstd.EOption{}
+	if opts.level > 0 {
+		eOpts = append(eOpts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(opts.level)))
+	}
+	var dOpts []zstd.DOption
+	if len(opts.zstdDictionary) > 0 {
+		eOpts = append(eOpts, zstd.WithEncoderDict(opts.zstdDictionary))
+		dOpts = append(dOpts, zstd.WithDecoderDicts(opts.zstdDictionary))
+	}
+
+	encoder, err := zstd.NewWriter(nil, eOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+
+	// The maximum length only applies to whole payloads, streams are never
+	// buffered in their entirety.
+	allOpts := dOpts
+	if opts.maxDecompressedLen > 0 {
+		allOpts = append(allOpts, zstd.WithDecoderMaxMemory(uint64(opts.maxDecompressedLen)))
+	}
+	decoder, err := zstd.NewReader(nil, allOpts...)
+	if err != nil {
+		encoder.Close()
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	return &zstdCodec{encoder: encoder, decoder: decoder, dOpts: dOpts}, nil
+}
+
+func (z *zstdCodec) compress(b []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(b, nil), nil
+}
+
+func (z *zstdCodec) decompress(b []byte) ([]byte, error) {
+	return z.decoder.DecodeAll(b, nil)
+}
+
+func (z *zstdCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	// Stream decoders are not safe for concurrent use and therefore each
+	// stream gets its own, with a single goroutine as the data is already
+	// being consumed sequentially.
+	dec, err := zstd.NewReader(r, append([]zstd.DOption{zstd.WithDecoderConcurrency(1)}, z.dOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+func (z *zstdCodec) close() {
+	_ = z.encoder.Close()
+	z.decoder.Close()
+}
+
+//------------------------------------------------------------------------------
+
+type snappyBlockCodec struct{}
+
+func (snappyBlockCodec) compress(b []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, b), nil
+}
+
+func (snappyBlockCodec) decompress(b []byte) ([]byte, error) {
+	return s2.Decode(nil, b)
+}
+
+func (snappyBlockCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = s2.Decode(nil, b); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (snappyBlockCodec) close() {}
+
+//------------------------------------------------------------------------------
+
+type snappyFramedCodec struct{}
+
+func (snappyFramedCodec) compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := s2.NewWriter(&buf, s2.WriterSnappyCompat(), s2.WriterConcurrency(1))
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (snappyFramedCodec) decompress(b []byte) ([]byte, error) {
+	return io.ReadAll(s2.NewReader(bytes.NewReader(b)))
+}
+
+func (snappyFramedCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(s2.NewReader(r)), nil
+}
+
+func (snappyFramedCodec) close() {}
+
+//------------------------------------------------------------------------------
+
+func lz4Level(level int) lz4.CompressionLevel {
+	// Matches the levels of the lz4 algorithm of the compress processor.
+	return lz4.CompressionLevel(1 << (8 + level))
+}
+
+type lz4BlockCodec struct {
+	level              int
+	maxDecompressedLen int
+}
+
+func (l lz4BlockCodec) compress(b []byte) ([]byte, error) {
+	dst := make([]byte, lz4.CompressBlockBound(len(b)))
+
+	var n int
+	var err error
+	if l.level > 0 {
+		c := lz4.CompressorHC{Level: lz4Level(l.level)}
+		n, err = c.CompressBlock(b, dst)
+	} else {
+		var c lz4.Compressor
+		n, err = c.CompressBlock(b, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// The lz4 block format does not record the length of the decompressed data,
+// and therefore the destination buffer grows until the block fits. A block
+// that does not fit within the maximum length is indistinguishable from a
+// corrupted one.
+func (l lz4BlockCodec) decompress(b []byte) ([]byte, error) {
+	size := max(len(b)*4, 1024)
+	for {
+		if size > l.maxDecompressedLen {
+			size = l.maxDecompressedLen
+		}
+		dst := make([]byte, size)
+		n, err := lz4.UncompressBlock(b, dst)
+		if err == nil {
+			return dst[:n], nil
+		}
+		if !errors.Is(err, lz4.ErrInvalidSourceShortBuffer) {
+			return nil, err
+		}
+		if size >= l.maxDecompressedLen {
+			return nil, fmt.Errorf("block is either corrupted or decompresses to more than %v bytes", l.maxDecompressedLen)
+		}
+		size *= 2
+	}
+}
+
+func (l lz4BlockCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = l.decompress(b); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (lz4BlockCodec) close() {}
+
+//------------------------------------------------------------------------------
+
+type lz4FrameCodec struct {
+	level int
+}
+
+func (l lz4FrameCodec) compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := lz4.NewWriter(&buf)
+	if l.level > 0 {
+		if err := w.Apply(lz4.CompressionLevelOption(lz4Level(l.level))); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (lz4FrameCodec) decompress(b []byte) ([]byte, error) {
+	return io.ReadAll(lz4.NewReader(bytes.NewReader(b)))
+}
+
+func (lz4FrameCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(lz4.NewReader(r)), nil
+}
+
+func (lz4FrameCodec) close() {}
@@ -0,0 +1,142 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPayload() []byte {
+	var buf bytes.Buffer
+	for i := range 1000 {
+		fmt.Fprintf(&buf, `{"id":%v,"type":"click","page":"/products/%v"}`+"\n", i, i%17)
+	}
+	return buf.Bytes()
+}
+
+func testDictionary(t *testing.T) []byte {
+	t.Helper()
+
+	var samples [][]byte
+	for i := range 500 {
+		samples = append(samples, fmt.Appendf(nil, `{"id":%v,"type":"click","page":"/products/%v","user":"u%v"}`, i, i%13, i%29))
+	}
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       1234,
+		Contents: samples,
+		History:  bytes.Join(samples[:50], nil),
+		Offsets:  [3]int{1, 4, 8},
+	})
+	require.NoError(t, err)
+	return dict
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	payload := testPayload()
+
+	for _, alg := range []string{"zstd", "snappy", "snappy_framed", "lz4_block", "lz4_frame"} {
+		for _, level := range []int{0, 3} {
+			t.Run(fmt.Sprintf("%v_%v", alg, level), func(t *testing.T) {
+				c, err := newCodec(alg, codecOptions{level: level, maxDecompressedLen: 1 << 20})
+				require.NoError(t, err)
+				t.Cleanup(c.close)
+
+				compressed, err := c.compress(payload)
+				require.NoError(t, err)
+				assert.Less(t, len(compressed), len(payload))
+
+				decompressed, err := c.decompress(compressed)
+				require.NoError(t, err)
+				assert.Equal(t, payload, decompressed)
+
+				rdr, err := c.newReader(bytes.NewReader(compressed))
+				require.NoError(t, err)
+
+				decompressed, err = io.ReadAll(rdr)
+				require.NoError(t, err)
+				require.NoError(t, rdr.Close())
+				assert.Equal(t, payload, decompressed)
+			})
+		}
+	}
+}
+
+func TestCodecFormatsDiffer(t *testing.T) {
+	payload := testPayload()
+
+	framed, err := newCodec("snappy_framed", codecOptions{})
+	require.NoError(t, err)
+
+	block, err := newCodec("snappy", codecOptions{})
+	require.NoError(t, err)
+
+	compressed, err := framed.compress(payload)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(compressed, []byte("\xff\x06\x00\x00sNaPpY")))
+
+	_, err = block.decompress(compressed)
+	require.Error(t, err)
+}
+
+func TestCodecZstdDictionary(t *testing.T) {
+	dict := testDictionary(t)
+	payload := []byte(`{"id":42,"type":"click","page":"/products/3","user":"u13"}`)
+
+	withDict, err := newCodec("zstd", codecOptions{zstdDictionary: dict, maxDecompressedLen: 1 << 20})
+	require.NoError(t, err)
+	t.Cleanup(withDict.close)
+
+	withoutDict, err := newCodec("zstd", codecOptions{maxDecompressedLen: 1 << 20})
+	require.NoError(t, err)
+	t.Cleanup(withoutDict.close)
+
+	compressedWithDict, err := withDict.compress(payload)
+	require.NoError(t, err)
+
+	compressedWithoutDict, err := withoutDict.compress(payload)
+	require.NoError(t, err)
+	assert.Less(t, len(compressedWithDict), len(compressedWithoutDict))
+
+	decompressed, err := withDict.decompress(compressedWithDict)
+	require.NoError(t, err)
+	assert.Equal(t, payload, decompressed)
+
+	_, err = withoutDict.decompress(compressedWithDict)
+	require.Error(t, err)
+
+	_, err = newCodec("lz4_frame", codecOptions{zstdDictionary: dict})
+	require.ErrorContains(t, err, "cannot be used")
+}
+
+func TestCodecLZ4BlockMaxLength(t *testing.T) {
+	payload := []byte(strings.Repeat("a", 1<<16))
+
+	c, err := newCodec("lz4_block", codecOptions{maxDecompressedLen: 1 << 12})
+	require.NoError(t, err)
+
+	compressed, err := c.compress(payload)
+	require.NoError(t, err)
+
+	_, err = c.decompress(compressed)
+	require.ErrorContains(t, err, "more than 4096 bytes")
+}
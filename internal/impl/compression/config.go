@@ -0,0 +1,77 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	cFieldAlgorithm          = "algorithm"
+	cFieldLevel              = "level"
+	cFieldZstdDictionary     = "zstd_dictionary"
+	cFieldMaxDecompressedLen = "max_decompressed_bytes"
+)
+
+func algorithmField() *service.ConfigField {
+	return service.NewStringAnnotatedEnumField(cFieldAlgorithm, map[string]string{
+		"zstd":          "Zstandard frames, optionally compressed with a dictionary.",
+		"snappy":        "The raw snappy block format, without framing.",
+		"snappy_framed": "The snappy framing format, as used by `.sz` files and most snappy streaming tools.",
+		"lz4_block":     "The raw lz4 block format, without framing or a header containing the decompressed length.",
+		"lz4_frame":     "The lz4 frame format, as used by `.lz4` files.",
+	}).Description("The compression algorithm and format.")
+}
+
+func zstdDictionaryField() *service.ConfigField {
+	return service.NewStringField(cFieldZstdDictionary).
+		Description("The path of a zstd dictionary file, such as one created with `zstd --train`, used when compressing and decompressing with the `zstd` algorithm. Dictionaries greatly improve the compression ratio of small messages that share a common structure.").
+		Optional().
+		Example("./dictionaries/events.zdict")
+}
+
+func maxDecompressedLenField() *service.ConfigField {
+	return service.NewIntField(cFieldMaxDecompressedLen).
+		Description("The maximum number of bytes a payload may decompress to, protecting against decompression bombs. This limit applies to whole payloads, and since the `lz4_block` format does not record the decompressed length its buffer grows up to this limit.").
+		Advanced().
+		Default(64 * 1024 * 1024)
+}
+
+func codecFromParsed(conf *service.ParsedConfig, mgr *service.Resources, withLevel bool) (codec, error) {
+	algorithm, err := conf.FieldString(cFieldAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts codecOptions
+	if withLevel {
+		if opts.level, err = conf.FieldInt(cFieldLevel); err != nil {
+			return nil, err
+		}
+	}
+	if opts.maxDecompressedLen, err = conf.FieldInt(cFieldMaxDecompressedLen); err != nil {
+		return nil, err
+	}
+	if conf.Contains(cFieldZstdDictionary) {
+		path, err := conf.FieldString(cFieldZstdDictionary)
+		if err != nil {
+			return nil, err
+		}
+		if opts.zstdDictionary, err = service.ReadFile(mgr.FS(), path); err != nil {
+			return nil, err
+		}
+	}
+	return newCodec(algorithm, opts)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	cpFieldOperator = "operator"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing", "Utility").
+		Summary("Compresses or decompresses messages with algorithms and formats beyond those of the `compress` and `decompress` processors.").
+		Description(`
+This processor supports zstd compression with dictionaries, and both the raw block and framed variants of snappy and lz4, which are often mixed up between systems. Payloads that are too large to hold in memory once decompressed should instead be consumed by an input with the `+"`decompression`"+` scanner, which decompresses streams without buffering them.
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(cpFieldOperator, map[string]string{
+				"compress":   "Compress messages.",
+				"decompress": "Decompress messages.",
+			}).Description("Whether to compress or decompress messages."),
+			algorithmField(),
+			service.NewIntField(cFieldLevel).
+				Description("The level of compression to use, where `0` selects the default of the algorithm. Snappy does not support levels.").
+				Advanced().
+				Default(0),
+			zstdDictionaryField(),
+			maxDecompressedLenField(),
+		).
+		Example("Zstd dictionaries", "Compress small JSON events with a dictionary trained on a sample of them.", `
+pipeline:
+  processors:
+    - compression:
+        operator: compress
+        algorithm: zstd
+        zstd_dictionary: ./events.zdict
+`).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"compression", processorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newProcessorFromConfig(conf, mgr)
+		})
+}
+
+type processor struct {
+	codec      codec
+	decompress bool
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	operator, err := conf.FieldString(cpFieldOperator)
+	if err != nil {
+		return nil, err
+	}
+	if operator != "compress" && operator != "decompress" {
+		return nil, fmt.Errorf("operator not recognised: %v", operator)
+	}
+	c, err := codecFromParsed(conf, mgr, true)
+	if err != nil {
+		return nil, err
+	}
+	return &processor{codec: c, decompress: operator == "decompress"}, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	if p.decompress {
+		if b, err = p.codec.decompress(mBytes); err != nil {
+			return nil, fmt.Errorf("failed to decompress message: %w", err)
+		}
+	} else if b, err = p.codec.compress(mBytes); err != nil {
+		return nil, fmt.Errorf("failed to compress message: %w", err)
+	}
+
+	msg.SetBytes(b)
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *processor) Close(context.Context) error {
+	p.codec.close()
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func TestProcessorRoundTrip(t *testing.T) {
+	dictPath := filepath.Join(t.TempDir(), "events.zdict")
+	require.NoError(t, os.WriteFile(dictPath, testDictionary(t), 0o644))
+
+	newProc := func(operator string) *processor {
+		conf, err := processorConfig().ParseYAML(`
+operator: `+operator+`
+algorithm: zstd
+zstd_dictionary: `+dictPath+`
+`, nil)
+		require.NoError(t, err)
+
+		proc, err := newProcessorFromConfig(conf, service.MockResources())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = proc.Close(t.Context()) })
+		return proc
+	}
+
+	payload := []byte(`{"id":42,"type":"click","page":"/products/3","user":"u13"}`)
+
+	msgs, err := newProc("compress").Process(t.Context(), service.NewMessage(payload))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	compressed, err := msgs[0].AsBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, payload, compressed)
+
+	msgs, err = newProc("decompress").Process(t.Context(), msgs[0])
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	decompressed, err := msgs[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, payload, decompressed)
+}
+
+func TestProcessorDecompressError(t *testing.T) {
+	conf, err := processorConfig().ParseYAML(`
+operator: decompress
+algorithm: snappy_framed
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newProcessorFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte("not compressed")))
+	require.ErrorContains(t, err, "failed to decompress message")
+}
+
+func scanAll(t *testing.T, conf string, rdr io.ReadCloser) (lines []string) {
+	t.Helper()
+
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	creator, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	strm, err := creator.Create(rdr, func(context.Context, error) error {
+		return nil
+	}, service.NewScannerSourceDetails())
+	require.NoError(t, err)
+
+	for {
+		batch, aFn, err := strm.NextBatch(t.Context())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, aFn(t.Context(), nil))
+
+		for _, msg := range batch {
+			mBytes, err := msg.AsBytes()
+			require.NoError(t, err)
+			lines = append(lines, string(mBytes))
+		}
+	}
+	require.NoError(t, strm.Close(t.Context()))
+	require.NoError(t, creator.Close(t.Context()))
+	return
+}
+
+func TestDecompressionScanner(t *testing.T) {
+	payload := testPayload()
+	expected := strings.Split(strings.TrimSpace(string(payload)), "\n")
+
+	for _, alg := range []string{"zstd", "snappy", "snappy_framed", "lz4_block", "lz4_frame"} {
+		t.Run(alg, func(t *testing.T) {
+			c, err := newCodec(alg, codecOptions{})
+			require.NoError(t, err)
+			t.Cleanup(c.close)
+
+			compressed, err := c.compress(payload)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, scanAll(t, `
+test:
+  decompression:
+    algorithm: `+alg+`
+    into:
+      lines: {}
+`, io.NopCloser(bytes.NewReader(compressed))))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"context"
+	"io"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dsFieldChild = "into"
+)
+
+func decompressionScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Summary("Decompresses a stream of bytes before feeding it into a child scanner, with algorithms and formats beyond those of the `decompress` scanner.").
+		Description(`
+The `+"`zstd`"+`, `+"`snappy_framed`"+` and `+"`lz4_frame`"+` algorithms decompress the stream as it is consumed, and therefore files of many gigabytes can be processed without holding them in memory. The raw block formats `+"`snappy`"+` and `+"`lz4_block`"+` cannot be decompressed incrementally and are buffered in their entirety.
+`).
+		Fields(
+			algorithmField(),
+			zstdDictionaryField(),
+			maxDecompressedLenField(),
+			service.NewScannerField(dsFieldChild).
+				Description("The child scanner to feed the decompressed stream into.").
+				Default(map[string]any{"to_the_end": map[string]any{}}),
+		).
+		Example("Zstd compressed lines", "Consume large zstd compressed log files compressed with a dictionary line by line.", `
+input:
+  file:
+    paths: [ ./logs/*.log.zst ]
+    scanner:
+      decompression:
+        algorithm: zstd
+        zstd_dictionary: ./logs.zdict
+        into:
+          lines: {}
+`).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterBatchScannerCreator("decompression", decompressionScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return decompressionScannerFromParsed(conf, mgr)
+		})
+}
+
+func decompressionScannerFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*decompressionScannerCreator, error) {
+	c, err := codecFromParsed(conf, mgr, false)
+	if err != nil {
+		return nil, err
+	}
+	child, err := conf.FieldScanner(dsFieldChild)
+	if err != nil {
+		c.close()
+		return nil, err
+	}
+	return &decompressionScannerCreator{codec: c, child: child}, nil
+}
+
+type decompressionScannerCreator struct {
+	codec codec
+	child *service.OwnedScannerCreator
+}
+
+func (c *decompressionScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	dRdr, err := c.codec.newReader(rdr)
+	if err != nil {
+		_ = rdr.Close()
+		return nil, err
+	}
+	return c.child.Create(&combinedReadCloser{ReadCloser: dRdr, source: rdr}, aFn, details)
+}
+
+func (c *decompressionScannerCreator) Close(ctx context.Context) error {
+	c.codec.close()
+	return c.child.Close(ctx)
+}
+
+// combinedReadCloser closes both the decompressing reader and its source.
+type combinedReadCloser struct {
+	io.ReadCloser
+	source io.Closer
+}
+
+func (c *combinedReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	if sErr := c.source.Close(); err == nil {
+		err = sErr
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+const ff3Rounds = 8
+
+// ff3Cipher implements the FF3-1 format-preserving encryption mode of NIST SP
+// 800-38G Revision 1, operating on numerals in the range [0, radix).
+type ff3Cipher struct {
+	block  cipher.Block
+	radix  int
+	minLen int
+	maxLen int
+}
+
+func newFF3Cipher(key []byte, radix int) (*ff3Cipher, error) {
+	if radix < 2 || radix > 1<<16 {
+		return nil, fmt.Errorf("radix must be between 2 and 65536, got %v", radix)
+	}
+
+	// The key is used in reverse byte order by the specification.
+	block, err := aes.NewCipher(reversed(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ff3Cipher{
+		block:  block,
+		radix:  radix,
+		minLen: int(math.Ceil(math.Log(1000000) / math.Log(float64(radix)))),
+		maxLen: 2 * int(math.Floor(96/math.Log2(float64(radix)))),
+	}, nil
+}
+
+func reversed[T any](s []T) []T {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
+
+// splitTweak derives the left and right 32-bit tweak halves of FF3 from the
+// 56-bit tweak of FF3-1.
+func splitTweak(tweak []byte) (tl, tr [4]byte, err error) {
+	if len(tweak) != 7 {
+		return tl, tr, fmt.Errorf("tweak must be 7 bytes, got %v", len(tweak))
+	}
+	tl = [4]byte{tweak[0], tweak[1], tweak[2], tweak[3] & 0xf0}
+	tr = [4]byte{tweak[4], tweak[5], tweak[6], tweak[3] << 4}
+	return tl, tr, nil
+}
+
+// num returns the number represented by numerals in reverse order, that is
+// NUM_radix(REV(X)).
+func (c *ff3Cipher) num(x []uint16) *big.Int {
+	n := new(big.Int)
+	r := big.NewInt(int64(c.radix))
+	for i := len(x) - 1; i >= 0; i-- {
+		n.Mul(n, r)
+		n.Add(n, big.NewInt(int64(x[i])))
+	}
+	return n
+}
+
+// str returns the m numerals of a number in reverse order, that is
+// REV(STR^m_radix(n)).
+func (c *ff3Cipher) str(n *big.Int, m int) []uint16 {
+	x := make([]uint16, m)
+	r := big.NewInt(int64(c.radix))
+	n = new(big.Int).Set(n)
+	mod := new(big.Int)
+	for i := range m {
+		n.DivMod(n, r, mod)
+		x[i] = uint16(mod.Int64())
+	}
+	return x
+}
+
+func (c *ff3Cipher) round(i int, w [4]byte, x []uint16) *big.Int {
+	var p [16]byte
+	copy(p[:4], w[:])
+	p[3] ^= byte(i)
+	c.num(x).FillBytes(p[4:])
+
+	slices.Reverse(p[:])
+	var s [16]byte
+	c.block.Encrypt(s[:], p[:])
+	slices.Reverse(s[:])
+	return new(big.Int).SetBytes(s[:])
+}
+
+func (c *ff3Cipher) checkLen(n int) error {
+	if n < c.minLen || n > c.maxLen {
+		return fmt.Errorf("value must contain between %v and %v characters of the alphabet, got %v", c.minLen, c.maxLen, n)
+	}
+	return nil
+}
+
+func (c *ff3Cipher) encrypt(tweak []byte, x []uint16) ([]uint16, error) {
+	tl, tr, err := splitTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	return c.apply(tl, tr, x, true)
+}
+
+func (c *ff3Cipher) decrypt(tweak []byte, x []uint16) ([]uint16, error) {
+	tl, tr, err := splitTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	return c.apply(tl, tr, x, false)
+}
+
+// apply runs the eight Feistel rounds of FF3 with the given tweak halves.
+func (c *ff3Cipher) apply(tl, tr [4]byte, x []uint16, encrypt bool) ([]uint16, error) {
+	if err := c.checkLen(len(x)); err != nil {
+		return nil, err
+	}
+
+	u := (len(x) + 1) / 2
+	v := len(x) - u
+	a, b := slices.Clone(x[:u]), slices.Clone(x[u:])
+
+	r := big.NewInt(int64(c.radix))
+	for j := range ff3Rounds {
+		i := j
+		if !encrypt {
+			i = ff3Rounds - 1 - j
+		}
+		m, w := u, tr
+		if i%2 == 1 {
+			m, w = v, tl
+		}
+		modulus := new(big.Int).Exp(r, big.NewInt(int64(m)), nil)
+
+		if encrypt {
+			y := c.round(i, w, b)
+			y.Add(y, c.num(a)).Mod(y, modulus)
+			a, b = b, c.str(y, m)
+		} else {
+			y := c.round(i, w, a)
+			n := c.num(b)
+			n.Sub(n, y).Mod(n, modulus)
+			a, b = c.str(n, m), a
+		}
+	}
+	return append(a, b...), nil
+}
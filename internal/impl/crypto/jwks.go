@@ -0,0 +1,135 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// jwksMinRefreshInterval limits how often a key set is fetched again due to
+// tokens signed with an unknown key ID, which usually indicates that the keys
+// have been rotated.
+const jwksMinRefreshInterval = 10 * time.Second
+
+// jwksCache fetches a JSON Web Key Set from a URL and caches its keys.
+type jwksCache struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+	nowFn  func() time.Time
+
+	mut       sync.Mutex
+	keys      []jose.JSONWebKey
+	fetchedAt time.Time
+}
+
+func newJWKSCache(url string, ttl time.Duration) *jwksCache {
+	return &jwksCache{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+		nowFn:  time.Now,
+	}
+}
+
+func (c *jwksCache) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("request returned status %v", res.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return fmt.Errorf("failed to parse key set: %w", err)
+	}
+
+	c.keys = c.keys[:0]
+	for _, k := range set.Keys {
+		// Keys intended for encryption only are ignored.
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		c.keys = append(c.keys, k.Public())
+	}
+	c.fetchedAt = c.nowFn()
+	return nil
+}
+
+func (c *jwksCache) find(kid string) (jose.JSONWebKey, bool) {
+	if kid == "" {
+		// Tokens without a key ID can only be matched to a key set containing
+		// a single key.
+		if len(c.keys) == 1 {
+			return c.keys[0], true
+		}
+		return jose.JSONWebKey{}, false
+	}
+	for _, k := range c.keys {
+		if k.KeyID == kid {
+			return k, true
+		}
+	}
+	return jose.JSONWebKey{}, false
+}
+
+// key returns the key with the given key ID, fetching the key set when the
+// cached keys have expired or do not contain the key.
+func (c *jwksCache) key(ctx context.Context, kid string) (jose.JSONWebKey, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	sinceFetch := c.nowFn().Sub(c.fetchedAt)
+	if c.fetchedAt.IsZero() || sinceFetch >= c.ttl {
+		if err := c.fetch(ctx); err != nil {
+			return jose.JSONWebKey{}, fmt.Errorf("failed to fetch JWKS from %v: %w", c.url, err)
+		}
+	} else if _, ok := c.find(kid); !ok && sinceFetch >= jwksMinRefreshInterval {
+		if err := c.fetch(ctx); err != nil {
+			return jose.JSONWebKey{}, fmt.Errorf("failed to fetch JWKS from %v: %w", c.url, err)
+		}
+	}
+
+	if k, ok := c.find(kid); ok {
+		return k, nil
+	}
+	if kid == "" {
+		return jose.JSONWebKey{}, errors.New("token has no key ID and the key set does not contain exactly one key")
+	}
+	return jose.JSONWebKey{}, fmt.Errorf("key ID %v not found in key set", kid)
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// asymmetricJWTAlgorithms are the algorithms supported by the parse_jwt and
+// sign_jwt methods.
+var asymmetricJWTAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+func parsePublicKeyPEM(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key must be PEM encoded")
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func parsePrivateKeyPEM(data string) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key must be PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func optionalStringParam(args *bloblang.ParsedParams, name string) (string, error) {
+	v, err := args.GetOptionalString(name)
+	if err != nil || v == nil {
+		return "", err
+	}
+	return *v, nil
+}
+
+func parseJWTMethod(args *bloblang.ParsedParams) (bloblang.Method, error) {
+	jwksURL, err := optionalStringParam(args, "jwks_url")
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPEM, err := optionalStringParam(args, "public_key")
+	if err != nil {
+		return nil, err
+	}
+	if (jwksURL == "") == (publicKeyPEM == "") {
+		return nil, errors.New("exactly one of jwks_url or public_key must be provided")
+	}
+
+	parserOpts := []jwt.ParserOption{jwt.WithValidMethods(asymmetricJWTAlgorithms)}
+	if audience, err := optionalStringParam(args, "audience"); err != nil {
+		return nil, err
+	} else if audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(audience))
+	}
+	if issuer, err := optionalStringParam(args, "issuer"); err != nil {
+		return nil, err
+	} else if issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(issuer))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	var keyFunc jwt.Keyfunc
+	if publicKeyPEM != "" {
+		publicKey, err := parsePublicKeyPEM(publicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public_key: %w", err)
+		}
+		keyFunc = func(*jwt.Token) (any, error) {
+			return publicKey, nil
+		}
+	} else {
+		ttlStr, err := args.GetString("jwks_cache_ttl")
+		if err != nil {
+			return nil, err
+		}
+		ttl, err := time.ParseDuration(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwks_cache_ttl: %w", err)
+		}
+		cache := newJWKSCache(jwksURL, ttl)
+		keyFunc = func(tok *jwt.Token) (any, error) {
+			kid, _ := tok.Header["kid"].(string)
+			key, err := cache.key(context.Background(), kid)
+			if err != nil {
+				return nil, err
+			}
+			if key.Algorithm != "" && key.Algorithm != tok.Method.Alg() {
+				return nil, fmt.Errorf("%w: key %v is for %v, token is signed with %v", errJWTIncorrectMethod, key.KeyID, key.Algorithm, tok.Method.Alg())
+			}
+			return key.Key, nil
+		}
+	}
+
+	return bloblang.StringMethod(func(encoded string) (any, error) {
+		var claims jwt.MapClaims
+		if _, err := parser.ParseWithClaims(encoded, &claims, keyFunc); err != nil {
+			return nil, fmt.Errorf("failed to parse JWT string: %w", err)
+		}
+		return map[string]any(claims), nil
+	}), nil
+}
+
+func signJWTMethod(args *bloblang.ParsedParams) (bloblang.Method, error) {
+	alg, err := args.GetString("algorithm")
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(asymmetricJWTAlgorithms, alg) {
+		return nil, fmt.Errorf("unsupported algorithm: %v", alg)
+	}
+	method := jwt.GetSigningMethod(alg)
+
+	keyPEM, err := args.GetString("private_key")
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private_key: %w", err)
+	}
+
+	kid, err := optionalStringParam(args, "kid")
+	if err != nil {
+		return nil, err
+	}
+
+	return bloblang.ObjectMethod(func(obj map[string]any) (any, error) {
+		token := jwt.NewWithClaims(method, jwt.MapClaims(obj))
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign token: %w", err)
+		}
+		return signed, nil
+	}), nil
+}
+
+func init() {
+	parseSpec := bloblang.NewPluginSpec().
+		Category("JSON Web Tokens").
+		Impure().
+		Description(`Parses and verifies a JWT string signed with one of the algorithms RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 or EdDSA, and returns its claims. The expiry and not before claims are validated when present.
+
+The verifying key is either a PEM encoded `+"`public_key`"+`, or is selected by the key ID (`+"`kid`"+`) header of the token from the JSON Web Key Set served at `+"`jwks_url`"+`. The key set is cached for `+"`jwks_cache_ttl`"+`, and is fetched again sooner when a token refers to an unknown key ID, which allows signing keys to be rotated without interruption.`).
+		Param(bloblang.NewStringParam("jwks_url").
+			Description("The URL of a JSON Web Key Set to verify tokens with.").
+			Optional()).
+		Param(bloblang.NewStringParam("public_key").
+			Description("A PEM encoded public key or certificate to verify tokens with.").
+			Optional()).
+		Param(bloblang.NewStringParam("audience").
+			Description("When set, the `aud` claim of tokens must contain this audience.").
+			Optional()).
+		Param(bloblang.NewStringParam("issuer").
+			Description("When set, the `iss` claim of tokens must equal this issuer.").
+			Optional()).
+		Param(bloblang.NewStringParam("jwks_cache_ttl").
+			Description("The period of time keys fetched from `jwks_url` are cached for.").
+			Default("1h")).
+		ExampleNotTested("Verify bearer tokens of HTTP requests against the keys of an identity provider.",
+			`root.claims = @Authorization.trim_prefix("Bearer ").parse_jwt(jwks_url: "https://auth.example.com/.well-known/jwks.json", audience: "my-api")`).
+		Version("4.62.0")
+
+	if err := bloblang.RegisterMethodV2("parse_jwt", parseSpec, parseJWTMethod); err != nil {
+		panic(err)
+	}
+
+	signSpec := bloblang.NewPluginSpec().
+		Category("JSON Web Tokens").
+		Description("Signs an object representing JSON Web Token (JWT) claims with a PEM encoded private key, using one of the algorithms RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 or EdDSA.").
+		Param(bloblang.NewStringParam("algorithm").
+			Description("The signing algorithm.")).
+		Param(bloblang.NewStringParam("private_key").
+			Description("The PEM encoded private key to sign the token with.")).
+		Param(bloblang.NewStringParam("kid").
+			Description("An optional key ID to add to the header of the token, allowing verifiers to select the key from a key set.").
+			Optional()).
+		ExampleNotTested("",
+			`root.token = this.claims.sign_jwt(algorithm: "EdDSA", private_key: env("JWT_PRIVATE_KEY"), kid: "2025-01")`).
+		Version("4.62.0")
+
+	if err := bloblang.RegisterMethodV2("sign_jwt", signSpec, signJWTMethod); err != nil {
+		panic(err)
+	}
+}
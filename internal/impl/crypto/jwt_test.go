@@ -0,0 +1,210 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+type testJWTKey struct {
+	kid     string
+	alg     string
+	private any
+	public  any
+}
+
+func (k testJWTKey) privatePEM(t *testing.T) string {
+	t.Helper()
+	b, err := x509.MarshalPKCS8PrivateKey(k.private)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}))
+}
+
+func (k testJWTKey) publicPEM(t *testing.T) string {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(k.public)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func testJWTKeys(t *testing.T) []testJWTKey {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return []testJWTKey{
+		{kid: "rsa", alg: "RS256", private: rsaKey, public: &rsaKey.PublicKey},
+		{kid: "ec", alg: "ES256", private: ecKey, public: &ecKey.PublicKey},
+		{kid: "ed", alg: "EdDSA", private: edPrivate, public: edPublic},
+	}
+}
+
+type testJWKSServer struct {
+	mut     sync.Mutex
+	keys    []testJWTKey
+	fetches atomic.Int32
+	*httptest.Server
+}
+
+func newTestJWKSServer(t *testing.T, keys ...testJWTKey) *testJWKSServer {
+	t.Helper()
+
+	s := &testJWKSServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.fetches.Add(1)
+
+		s.mut.Lock()
+		defer s.mut.Unlock()
+
+		var set jose.JSONWebKeySet
+		for _, k := range s.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: k.public, KeyID: k.kid, Algorithm: k.alg, Use: "sig"})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func execMapping(t *testing.T, mapping string, input any) (any, error) {
+	t.Helper()
+
+	exec, err := bloblang.Parse(mapping)
+	require.NoError(t, err)
+	return exec.Query(input)
+}
+
+func signTestJWT(t *testing.T, key testJWTKey, claims map[string]any) string {
+	t.Helper()
+
+	res, err := execMapping(t, fmt.Sprintf(`root = this.sign_jwt(algorithm: %q, private_key: """%v""", kid: %q)`, key.alg, key.privatePEM(t), key.kid), claims)
+	require.NoError(t, err)
+	return res.(string)
+}
+
+func TestJWTSignParsePublicKey(t *testing.T) {
+	for _, key := range testJWTKeys(t) {
+		t.Run(key.alg, func(t *testing.T) {
+			token := signTestJWT(t, key, map[string]any{"sub": "user1"})
+
+			res, err := execMapping(t, fmt.Sprintf(`root = this.parse_jwt(public_key: """%v""")`, key.publicPEM(t)), token)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"sub": "user1"}, res)
+		})
+	}
+}
+
+func TestJWTParseJWKS(t *testing.T) {
+	keys := testJWTKeys(t)
+	server := newTestJWKSServer(t, keys...)
+
+	exec, err := bloblang.Parse(fmt.Sprintf(`root = this.parse_jwt(jwks_url: %q, audience: "api", issuer: "https://auth.example.com")`, server.URL))
+	require.NoError(t, err)
+
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	for _, key := range keys {
+		token := signTestJWT(t, key, map[string]any{"sub": key.kid, "aud": "api", "iss": "https://auth.example.com", "exp": exp})
+
+		res, err := exec.Query(token)
+		require.NoError(t, err, key.alg)
+		assert.Equal(t, key.kid, res.(map[string]any)["sub"])
+	}
+	assert.Equal(t, int32(1), server.fetches.Load(), "keys should be cached")
+
+	// Invalid claims
+	_, err = exec.Query(signTestJWT(t, keys[0], map[string]any{"aud": "other", "iss": "https://auth.example.com"}))
+	require.ErrorContains(t, err, "aud")
+
+	_, err = exec.Query(signTestJWT(t, keys[0], map[string]any{"aud": "api", "iss": "https://auth.example.com", "exp": float64(time.Now().Add(-time.Hour).Unix())}))
+	require.ErrorContains(t, err, "expired")
+
+	// A key of the set used with a different algorithm.
+	mismatched := keys[0]
+	mismatched.alg = "PS256"
+	_, err = exec.Query(signTestJWT(t, mismatched, map[string]any{"aud": "api", "iss": "https://auth.example.com"}))
+	require.ErrorContains(t, err, "incorrect signing method")
+}
+
+func TestJWKSCacheRotation(t *testing.T) {
+	keys := testJWTKeys(t)
+	server := newTestJWKSServer(t, keys[0])
+
+	now := time.Now()
+	cache := newJWKSCache(server.URL, time.Hour)
+	cache.nowFn = func() time.Time { return now }
+
+	_, err := cache.key(t.Context(), "rsa")
+	require.NoError(t, err)
+
+	// Unknown key IDs don't refresh the cache immediately.
+	server.mut.Lock()
+	server.keys = append(server.keys, keys[1])
+	server.mut.Unlock()
+
+	_, err = cache.key(t.Context(), "ec")
+	require.ErrorContains(t, err, "not found")
+	assert.Equal(t, int32(1), server.fetches.Load())
+
+	now = now.Add(jwksMinRefreshInterval)
+	k, err := cache.key(t.Context(), "ec")
+	require.NoError(t, err)
+	assert.Equal(t, "ES256", k.Algorithm)
+	assert.Equal(t, int32(2), server.fetches.Load())
+
+	// Known keys refresh once the TTL has passed.
+	now = now.Add(time.Hour)
+	_, err = cache.key(t.Context(), "rsa")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), server.fetches.Load())
+
+	// Tokens without a key ID require a key set with a single key.
+	_, err = cache.key(t.Context(), "")
+	require.ErrorContains(t, err, "exactly one key")
+}
+
+func TestJWTParamErrors(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.parse_jwt()`)
+	require.ErrorContains(t, err, "exactly one of jwks_url or public_key")
+
+	_, err = bloblang.Parse(`root = this.sign_jwt(algorithm: "HS256", private_key: "foo")`)
+	require.ErrorContains(t, err, "unsupported algorithm")
+}
@@ -0,0 +1,349 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Jeffail/gabs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	tpFieldOperator      = "operator"
+	tpFieldFields        = "fields"
+	tpFieldFieldPath     = "path"
+	tpFieldFieldMethod   = "method"
+	tpFieldFieldAlphabet = "alphabet"
+	tpFieldFF3Key        = "ff3_1_key"
+	tpFieldFF3Tweak      = "ff3_1_tweak"
+	tpFieldHMACKey       = "hmac_key"
+)
+
+func tokenizeProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Summary("Replaces fields of structured messages with tokens of the same length and character set, using either FF3-1 format-preserving encryption or HMAC tokenization.").
+		Description(`
+Only the characters of a field that belong to its alphabet are replaced, and all other characters remain in place, and therefore a credit card number such as `+"`4111-1111-1111-1111`"+` results in a token of the same `+"`dddd-dddd-dddd-dddd`"+` form that still passes schema validation downstream.
+
+== Methods
+
+The `+"`ff3_1`"+` method encrypts fields with the https://csrc.nist.gov/pubs/sp/800/38/g/r1/ipd[FF3-1^] mode of AES, and can be reversed with the `+"`detokenize`"+` operator given the same key and tweak. FF3-1 requires values to contain at least enough characters of the alphabet to represent a million combinations, which is six for digits.
+
+The `+"`hmac`"+` method derives tokens from an HMAC-SHA256 of the value, which are consistent for equal values and therefore remain joinable, but can not be reversed. Fields tokenized with HMAC are left unchanged by the `+"`detokenize`"+` operator.
+
+== Paths
+
+Paths are dot separated, where a segment of `+"`*`"+` matches every element of an array or every value of an object. Fields that do not exist or are null are skipped, and all other fields must be strings.
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(tpFieldOperator, map[string]string{
+				"tokenize":   "Replace field values with tokens.",
+				"detokenize": "Restore field values from tokens created with the `ff3_1` method.",
+			}).Description("Whether to tokenize or detokenize fields.").
+				Default("tokenize"),
+			service.NewObjectListField(tpFieldFields,
+				service.NewStringField(tpFieldFieldPath).
+					Description("The dot separated path of the field.").
+					Example("card.number").
+					Example("payments.*.account"),
+				service.NewStringEnumField(tpFieldFieldMethod, "ff3_1", "hmac").
+					Description("The method used to tokenize the field.").
+					Default("ff3_1"),
+				service.NewStringField(tpFieldFieldAlphabet).
+					Description("The characters of the field to tokenize, in a fixed order. Characters outside of the alphabet are preserved.").
+					Default("0123456789").
+					Example("0123456789abcdefghijklmnopqrstuvwxyz"),
+			).Description("The fields to tokenize."),
+			service.NewStringField(tpFieldFF3Key).
+				Description("A hex encoded AES key of 128, 192 or 256 bits, required by fields with the `ff3_1` method.").
+				Secret().
+				Optional(),
+			service.NewStringField(tpFieldFF3Tweak).
+				Description("A hex encoded tweak of 56 bits used by fields with the `ff3_1` method.").
+				Advanced().
+				Default("00000000000000"),
+			service.NewStringField(tpFieldHMACKey).
+				Description("The key used by fields with the `hmac` method.").
+				Secret().
+				Optional(),
+		).
+		Example("PCI card numbers", "Encrypt card numbers while keeping their format, and replace the card holder with a consistent token.", `
+pipeline:
+  processors:
+    - tokenize:
+        fields:
+          - path: card.number
+          - path: card.holder
+            method: hmac
+            alphabet: abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ
+        ff3_1_key: ${FPE_KEY}
+        hmac_key: ${TOKEN_KEY}
+`).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"tokenize", tokenizeProcessorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			return newTokenizeProcessorFromConfig(conf)
+		})
+}
+
+// tokenizer replaces the numerals of a value, where numerals are indexes into
+// an alphabet.
+type tokenizer func(x []uint16) ([]uint16, error)
+
+type tokenizeField struct {
+	pathStr  string
+	path     []string
+	alphabet []rune
+	indexes  map[rune]uint16
+	apply    tokenizer
+}
+
+type tokenizeProcessor struct {
+	fields []tokenizeField
+}
+
+func newTokenizeProcessorFromConfig(conf *service.ParsedConfig) (*tokenizeProcessor, error) {
+	operator, err := conf.FieldString(tpFieldOperator)
+	if err != nil {
+		return nil, err
+	}
+	detokenize := operator == "detokenize"
+
+	tweak, err := conf.FieldString(tpFieldFF3Tweak)
+	if err != nil {
+		return nil, err
+	}
+	tweakBytes, err := hex.DecodeString(tweak)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %v: %w", tpFieldFF3Tweak, err)
+	}
+	if len(tweakBytes) != 7 {
+		return nil, fmt.Errorf("%v must be 7 bytes, got %v", tpFieldFF3Tweak, len(tweakBytes))
+	}
+
+	var ff3Key []byte
+	if conf.Contains(tpFieldFF3Key) {
+		keyStr, err := conf.FieldString(tpFieldFF3Key)
+		if err != nil {
+			return nil, err
+		}
+		if ff3Key, err = hex.DecodeString(keyStr); err != nil {
+			return nil, fmt.Errorf("failed to decode %v: %w", tpFieldFF3Key, err)
+		}
+	}
+
+	var hmacKey []byte
+	if conf.Contains(tpFieldHMACKey) {
+		keyStr, err := conf.FieldString(tpFieldHMACKey)
+		if err != nil {
+			return nil, err
+		}
+		hmacKey = []byte(keyStr)
+	}
+
+	fieldConfs, err := conf.FieldObjectList(tpFieldFields)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tokenizeProcessor{}
+	for i, fConf := range fieldConfs {
+		path, err := fConf.FieldString(tpFieldFieldPath)
+		if err != nil {
+			return nil, err
+		}
+		method, err := fConf.FieldString(tpFieldFieldMethod)
+		if err != nil {
+			return nil, err
+		}
+		alphabet, err := fConf.FieldString(tpFieldFieldAlphabet)
+		if err != nil {
+			return nil, err
+		}
+
+		f := tokenizeField{
+			pathStr:  path,
+			path:     gabs.DotPathToSlice(path),
+			alphabet: []rune(alphabet),
+			indexes:  map[rune]uint16{},
+		}
+		if len(f.alphabet) > 1<<16 {
+			return nil, fmt.Errorf("field %v: alphabet must not exceed 65536 characters", i)
+		}
+		for j, r := range f.alphabet {
+			if _, exists := f.indexes[r]; exists {
+				return nil, fmt.Errorf("field %v: alphabet contains the character %q more than once", i, r)
+			}
+			f.indexes[r] = uint16(j)
+		}
+		if len(f.alphabet) < 2 {
+			return nil, fmt.Errorf("field %v: alphabet must contain at least two characters", i)
+		}
+
+		switch method {
+		case "ff3_1":
+			if ff3Key == nil {
+				return nil, fmt.Errorf("field %v: %v is required by the ff3_1 method", i, tpFieldFF3Key)
+			}
+			c, err := newFF3Cipher(ff3Key, len(f.alphabet))
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", i, err)
+			}
+			if detokenize {
+				f.apply = func(x []uint16) ([]uint16, error) { return c.decrypt(tweakBytes, x) }
+			} else {
+				f.apply = func(x []uint16) ([]uint16, error) { return c.encrypt(tweakBytes, x) }
+			}
+		case "hmac":
+			if hmacKey == nil {
+				return nil, fmt.Errorf("field %v: %v is required by the hmac method", i, tpFieldHMACKey)
+			}
+			if detokenize {
+				continue
+			}
+			radix := len(f.alphabet)
+			f.apply = func(x []uint16) ([]uint16, error) { return hmacToken(hmacKey, radix, x), nil }
+		default:
+			return nil, fmt.Errorf("field %v: method not recognised: %v", i, method)
+		}
+		p.fields = append(p.fields, f)
+	}
+	return p, nil
+}
+
+// hmacToken derives numerals from HMAC-SHA256 digests of the value in counter
+// mode, using rejection sampling so that every numeral is equally likely.
+func hmacToken(key []byte, radix int, x []uint16) []uint16 {
+	value := make([]byte, 0, len(x)*2)
+	for _, n := range x {
+		value = binary.BigEndian.AppendUint16(value, n)
+	}
+
+	limit := (1 << 16) - (1<<16)%radix
+	token := make([]uint16, 0, len(x))
+	for counter := uint32(0); len(token) < len(x); counter++ {
+		mac := hmac.New(sha256.New, key)
+		_ = binary.Write(mac, binary.BigEndian, counter)
+		_, _ = mac.Write(value)
+		sum := mac.Sum(nil)
+		for i := 0; i+1 < len(sum) && len(token) < len(x); i += 2 {
+			if v := int(binary.BigEndian.Uint16(sum[i:])); v < limit {
+				token = append(token, uint16(v%radix))
+			}
+		}
+	}
+	return token
+}
+
+func (f *tokenizeField) tokenize(s string) (string, error) {
+	runes := []rune(s)
+
+	var positions []int
+	var x []uint16
+	for i, r := range runes {
+		if n, ok := f.indexes[r]; ok {
+			positions = append(positions, i)
+			x = append(x, n)
+		}
+	}
+
+	y, err := f.apply(x)
+	if err != nil {
+		return "", err
+	}
+	for i, pos := range positions {
+		runes[pos] = f.alphabet[y[i]]
+	}
+	return string(runes), nil
+}
+
+func (f *tokenizeField) walk(v any, path []string) (any, error) {
+	if len(path) == 0 {
+		switch t := v.(type) {
+		case nil:
+			return nil, nil
+		case string:
+			return f.tokenize(t)
+		}
+		return nil, fmt.Errorf("expected a string value, got %T", v)
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		if path[0] == "*" {
+			for k, e := range t {
+				var err error
+				if t[k], err = f.walk(e, path[1:]); err != nil {
+					return nil, err
+				}
+			}
+			return t, nil
+		}
+		e, exists := t[path[0]]
+		if !exists {
+			return t, nil
+		}
+		var err error
+		if t[path[0]], err = f.walk(e, path[1:]); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case []any:
+		if path[0] != "*" {
+			return t, nil
+		}
+		for i, e := range t {
+			var err error
+			if t[i], err = f.walk(e, path[1:]); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	}
+	return v, nil
+}
+
+func (p *tokenizeProcessor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	v, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as JSON: %w", err)
+	}
+
+	for _, f := range p.fields {
+		if v, err = f.walk(v, f.path); err != nil {
+			return nil, fmt.Errorf("failed to tokenize %v: %w", f.pathStr, err)
+		}
+	}
+
+	msg.SetStructuredMut(v)
+	return service.MessageBatch{msg}, nil
+}
+
+func (*tokenizeProcessor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTokenizeTestProc(t *testing.T, conf string) *tokenizeProcessor {
+	t.Helper()
+
+	pConf, err := tokenizeProcessorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	proc, err := newTokenizeProcessorFromConfig(pConf)
+	require.NoError(t, err)
+	return proc
+}
+
+func processStructured(t *testing.T, proc service.Processor, v any) any {
+	t.Helper()
+
+	msg := service.NewMessage(nil)
+	msg.SetStructured(v)
+
+	batch, err := proc.Process(t.Context(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	res, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	return res
+}
+
+const tokenizeTestFields = `
+fields:
+  - path: card.number
+  - path: payments.*.iban
+    alphabet: 0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ
+  - path: card.holder
+    method: hmac
+    alphabet: abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ
+ff3_1_key: 2DE79D232DF5585D68CE47882AE256D6
+ff3_1_tweak: CBD09280979564
+hmac_key: tokensecret
+`
+
+func TestTokenizeRoundTrip(t *testing.T) {
+	input := func() map[string]any {
+		return map[string]any{
+			"card": map[string]any{
+				"number": "4111-1111-1111-1111",
+				"holder": "Jane Doe",
+			},
+			"payments": []any{
+				map[string]any{"iban": "GB82WEST12345698765432"},
+				map[string]any{"iban": nil},
+				map[string]any{"amount": 10},
+			},
+			"other": "4111-1111-1111-1111",
+		}
+	}
+
+	tokenized := processStructured(t, newTokenizeTestProc(t, tokenizeTestFields), input()).(map[string]any)
+
+	card := tokenized["card"].(map[string]any)
+	assert.Regexp(t, regexp.MustCompile(`^\d{4}-\d{4}-\d{4}-\d{4}$`), card["number"])
+	assert.NotEqual(t, "4111-1111-1111-1111", card["number"])
+	assert.Regexp(t, regexp.MustCompile(`^[a-zA-Z]{4} [a-zA-Z]{3}$`), card["holder"])
+	assert.NotEqual(t, "Jane Doe", card["holder"])
+	assert.Equal(t, "4111-1111-1111-1111", tokenized["other"])
+
+	iban := tokenized["payments"].([]any)[0].(map[string]any)["iban"]
+	assert.Regexp(t, regexp.MustCompile(`^[0-9A-Z]{22}$`), iban)
+	assert.NotEqual(t, "GB82WEST12345698765432", iban)
+
+	// Tokens are deterministic.
+	assert.Equal(t, tokenized, processStructured(t, newTokenizeTestProc(t, tokenizeTestFields), input()))
+
+	detokenized := processStructured(t, newTokenizeTestProc(t, tokenizeTestFields+"operator: detokenize\n"), tokenized).(map[string]any)
+
+	expected := input()
+	// HMAC tokens can not be reversed.
+	expected["card"].(map[string]any)["holder"] = card["holder"]
+	assert.Equal(t, expected, detokenized)
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	proc := newTokenizeTestProc(t, tokenizeTestFields)
+
+	msg := service.NewMessage([]byte(`{"card":{"number":"411"}}`))
+	_, err := proc.Process(t.Context(), msg)
+	require.ErrorContains(t, err, "failed to tokenize card.number")
+
+	msg = service.NewMessage([]byte(`{"card":{"number":4111111111111111}}`))
+	_, err = proc.Process(t.Context(), msg)
+	require.ErrorContains(t, err, "expected a string value")
+
+	pConf, err := tokenizeProcessorConfig().ParseYAML(`
+fields:
+  - path: card.number
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newTokenizeProcessorFromConfig(pConf)
+	require.ErrorContains(t, err, "ff3_1_key is required")
+
+	pConf, err = tokenizeProcessorConfig().ParseYAML(`
+fields:
+  - path: card.number
+    alphabet: "0120"
+ff3_1_key: 2DE79D232DF5585D68CE47882AE256D6
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newTokenizeProcessorFromConfig(pConf)
+	require.ErrorContains(t, err, "more than once")
+}
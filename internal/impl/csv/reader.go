@@ -0,0 +1,182 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dialect describes the delimiter and quoting rules of a CSV document.
+type dialect struct {
+	delimiter rune
+	quote     rune
+	// escape is a character that escapes the following character within a
+	// field, or zero when quotes are only escaped by doubling them.
+	escape     rune
+	lazyQuotes bool
+}
+
+// malformedError is returned for records that cannot be parsed, reading can
+// continue with the record that follows.
+type malformedError struct {
+	line int
+	err  error
+}
+
+func (m *malformedError) Error() string {
+	return fmt.Sprintf("record on line %v: %v", m.line, m.err)
+}
+
+func (m *malformedError) Unwrap() error {
+	return m.err
+}
+
+// recordReader reads CSV records one at a time, where unlike encoding/csv the
+// quote and escape characters are configurable and a malformed record does not
+// prevent reading the records that follow it.
+type recordReader struct {
+	r    *bufio.Reader
+	d    dialect
+	line int
+}
+
+func newRecordReader(r io.Reader, d dialect) *recordReader {
+	return &recordReader{r: bufio.NewReader(r), d: d}
+}
+
+func (r *recordReader) readLine() (string, error) {
+	line, err := r.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	r.line++
+	return line, nil
+}
+
+func trimLineEnding(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+type parseState int
+
+const (
+	stateFieldStart parseState = iota
+	stateUnquoted
+	stateQuoted
+	stateAfterQuote
+)
+
+// read returns the fields of the next record along with its raw text, empty
+// lines are skipped. Errors of type *malformedError describe records that
+// could not be parsed.
+func (r *recordReader) read() (fields []string, raw string, err error) {
+	var line string
+	for line == "" {
+		if line, err = r.readLine(); err != nil {
+			return nil, "", err
+		}
+		line = trimLineEnding(line)
+	}
+	startLine := r.line
+
+	var rawBuilder strings.Builder
+	rawBuilder.WriteString(line)
+
+	var field strings.Builder
+	state := stateFieldStart
+	for {
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			c := runes[i]
+			switch state {
+			case stateFieldStart:
+				if c == r.d.quote {
+					state = stateQuoted
+					continue
+				}
+				state = stateUnquoted
+				fallthrough
+			case stateUnquoted:
+				switch {
+				case c == r.d.delimiter:
+					fields = append(fields, field.String())
+					field.Reset()
+					state = stateFieldStart
+				case r.d.escape != 0 && c == r.d.escape && i+1 < len(runes):
+					i++
+					field.WriteRune(runes[i])
+				case c == r.d.quote && !r.d.lazyQuotes:
+					return nil, rawBuilder.String(), &malformedError{line: startLine, err: fmt.Errorf("bare %c in non-quoted field", r.d.quote)}
+				default:
+					field.WriteRune(c)
+				}
+			case stateQuoted:
+				switch {
+				case r.d.escape != 0 && r.d.escape != r.d.quote && c == r.d.escape && i+1 < len(runes):
+					i++
+					field.WriteRune(runes[i])
+				case c == r.d.quote:
+					if i+1 < len(runes) && runes[i+1] == r.d.quote {
+						i++
+						field.WriteRune(c)
+					} else {
+						state = stateAfterQuote
+					}
+				default:
+					field.WriteRune(c)
+				}
+			case stateAfterQuote:
+				switch {
+				case c == r.d.delimiter:
+					fields = append(fields, field.String())
+					field.Reset()
+					state = stateFieldStart
+				case r.d.lazyQuotes:
+					field.WriteRune(r.d.quote)
+					field.WriteRune(c)
+					state = stateQuoted
+				default:
+					return nil, rawBuilder.String(), &malformedError{line: startLine, err: fmt.Errorf("extraneous or missing %c in quoted field", r.d.quote)}
+				}
+			}
+		}
+
+		if state != stateQuoted {
+			break
+		}
+
+		// The quoted field continues on the next line.
+		next, err := r.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, rawBuilder.String(), &malformedError{line: startLine, err: fmt.Errorf("missing closing %c of quoted field", r.d.quote)}
+			}
+			return nil, "", err
+		}
+		next = trimLineEnding(next)
+		field.WriteRune('\n')
+		rawBuilder.WriteRune('\n')
+		rawBuilder.WriteString(next)
+		line = next
+	}
+
+	fields = append(fields, field.String())
+	return fields, rawBuilder.String(), nil
+}
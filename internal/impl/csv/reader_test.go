@@ -0,0 +1,108 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReader(t *testing.T) {
+	type record struct {
+		fields    []string
+		raw       string
+		malformed string
+	}
+
+	tests := []struct {
+		name    string
+		dialect dialect
+		input   string
+		records []record
+	}{
+		{
+			name:    "basic",
+			dialect: dialect{delimiter: ',', quote: '"'},
+			input:   "a,b,c\r\n1,,3\n\n4,5,6",
+			records: []record{
+				{fields: []string{"a", "b", "c"}, raw: "a,b,c"},
+				{fields: []string{"1", "", "3"}, raw: "1,,3"},
+				{fields: []string{"4", "5", "6"}, raw: "4,5,6"},
+			},
+		},
+		{
+			name:    "quoted fields",
+			dialect: dialect{delimiter: ',', quote: '"'},
+			input:   "\"a,b\",\"say \"\"hi\"\"\",\"multi\nline\"\nx,y,z\n",
+			records: []record{
+				{fields: []string{"a,b", `say "hi"`, "multi\nline"}, raw: "\"a,b\",\"say \"\"hi\"\"\",\"multi\nline\""},
+				{fields: []string{"x", "y", "z"}, raw: "x,y,z"},
+			},
+		},
+		{
+			name:    "custom dialect",
+			dialect: dialect{delimiter: ';', quote: '\'', escape: '\\'},
+			input:   "'it\\'s';a\\;b;'x''y'\n",
+			records: []record{
+				{fields: []string{"it's", "a;b", "x'y"}, raw: "'it\\'s';a\\;b;'x''y'"},
+			},
+		},
+		{
+			name:    "malformed rows are recovered from",
+			dialect: dialect{delimiter: ',', quote: '"'},
+			input:   "a,b\"c\n\"d\"e,f\ng,h\n\"unterminated",
+			records: []record{
+				{raw: `a,b"c`, malformed: `bare " in non-quoted field`},
+				{raw: `"d"e,f`, malformed: `extraneous or missing " in quoted field`},
+				{fields: []string{"g", "h"}, raw: "g,h"},
+				{raw: `"unterminated`, malformed: `missing closing " of quoted field`},
+			},
+		},
+		{
+			name:    "lazy quotes",
+			dialect: dialect{delimiter: ',', quote: '"', lazyQuotes: true},
+			input:   "a,b\"c\n\"d\"e\",f\n",
+			records: []record{
+				{fields: []string{"a", `b"c`}, raw: `a,b"c`},
+				{fields: []string{`d"e`, "f"}, raw: `"d"e",f`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rr := newRecordReader(strings.NewReader(test.input), test.dialect)
+			for i, exp := range test.records {
+				fields, raw, err := rr.read()
+				if exp.malformed != "" {
+					var mErr *malformedError
+					require.True(t, errors.As(err, &mErr), "record %v: %v", i, err)
+					assert.Contains(t, err.Error(), exp.malformed, i)
+				} else {
+					require.NoError(t, err, i)
+					assert.Equal(t, exp.fields, fields, i)
+				}
+				assert.Equal(t, exp.raw, raw, i)
+			}
+			_, _, err := rr.read()
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	tcsvFieldDelimiter       = "delimiter"
+	tcsvFieldQuote           = "quote"
+	tcsvFieldEscape          = "escape"
+	tcsvFieldLazyQuotes      = "lazy_quotes"
+	tcsvFieldParseHeaderRow  = "parse_header_row"
+	tcsvFieldColumnTypes     = "column_types"
+	tcsvFieldInferTypes      = "infer_types"
+	tcsvFieldInferSampleSize = "infer_sample_size"
+	tcsvFieldTimestampLayout = "timestamp_layout"
+	tcsvFieldOnMalformed     = "on_malformed"
+
+	tcsvOnMalformedFail = "fail"
+	tcsvOnMalformedFlag = "flag"
+	tcsvOnMalformedSkip = "skip"
+)
+
+func typedCSVScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Summary("Consume comma-separated values row by row, with values coerced to types that are either configured per column or inferred from the data.").
+		Description(`
+This scanner is an alternative to the `+"xref:components:scanners/csv.adoc[`csv` scanner]"+` for when values should be emitted as numbers, booleans and timestamps rather than strings, or when the quoting rules of the data differ from RFC 4180.
+
+== Column types
+
+The type of each column is determined by the field `+"`"+tcsvFieldColumnTypes+"`"+`, which maps column names to one of the types `+"`string`, `int`, `float`, `bool`, `timestamp` or `timestamp:<layout>`"+`, where a layout is a https://pkg.go.dev/time#pkg-constants[Go time layout^] and otherwise the field `+"`"+tcsvFieldTimestampLayout+"`"+` is used. When a header row is not parsed columns are named by their index, beginning at `+"`0`"+`. Empty values of columns that are not strings are emitted as `+"`null`"+`.
+
+When `+"`"+tcsvFieldInferTypes+"`"+` is `+"`true`"+` the types of all remaining columns are inferred from the first rows of the data, where each column is given the first of the types `+"`int`, `float`, `bool` and `timestamp`"+` that all of its sampled values can be coerced to, and is otherwise a string.
+
+== Malformed rows
+
+Rows are malformed when they cannot be parsed, when the number of fields differs from the header row, or when a value cannot be coerced to the type of its column. Unlike the `+"`csv`"+` scanner, a malformed row does not prevent the rows that follow it from being parsed. The field `+"`"+tcsvFieldOnMalformed+"`"+` determines whether malformed rows stop consumption, are skipped, or are emitted as messages containing the raw row that are flagged with an error, which can be routed to a dead letter queue with xref:configuration:error_handling.adoc[error handling] patterns.
+
+== Metadata
+
+This scanner adds the following metadata to each message:
+
+- `+"`csv_row`"+` The index of each row, beginning at 0.
+`).
+		Fields(
+			service.NewStringField(tcsvFieldDelimiter).
+				Description("The character that separates fields.").
+				Default(","),
+			service.NewStringField(tcsvFieldQuote).
+				Description("The character that quotes fields.").
+				Default(`"`).
+				Advanced(),
+			service.NewStringField(tcsvFieldEscape).
+				Description("An optional character that escapes the character following it within a field, such as `\\`. Quotes within quoted fields can always be escaped by doubling them.").
+				Optional().
+				Advanced(),
+			service.NewBoolField(tcsvFieldLazyQuotes).
+				Description("If set to `true`, a quote may appear in an unquoted field and a non-doubled quote may appear in a quoted field.").
+				Default(false).
+				Advanced(),
+			service.NewBoolField(tcsvFieldParseHeaderRow).
+				Description("Whether to reference the first row as a header row. If set to true the output structure for messages will be an object where field keys are determined by the header row. Otherwise, each message will consist of an array of values from the corresponding CSV row.").
+				Default(true),
+			service.NewStringMapField(tcsvFieldColumnTypes).
+				Description("A map of column names to the type their values are coerced to.").
+				Example(map[string]any{
+					"id":         "int",
+					"price":      "float",
+					"in_stock":   "bool",
+					"created_at": "timestamp:2006-01-02 15:04:05",
+				}).
+				Optional(),
+			service.NewBoolField(tcsvFieldInferTypes).
+				Description("Whether to infer the types of columns that are not listed in `"+tcsvFieldColumnTypes+"` from the first rows of the data.").
+				Default(false),
+			service.NewIntField(tcsvFieldInferSampleSize).
+				Description("The number of rows sampled when inferring the types of columns.").
+				Default(100).
+				Advanced(),
+			service.NewStringField(tcsvFieldTimestampLayout).
+				Description("The https://pkg.go.dev/time#pkg-constants[Go time layout^] of timestamp columns that do not specify one.").
+				Default(time.RFC3339).
+				Advanced(),
+			service.NewStringAnnotatedEnumField(tcsvFieldOnMalformed, map[string]string{
+				tcsvOnMalformedFail: "Stop consuming the data with an error.",
+				tcsvOnMalformedFlag: "Emit a message containing the raw row flagged with an error.",
+				tcsvOnMalformedSkip: "Skip the row.",
+			}).
+				Description("The action to take on malformed rows.").
+				Default(tcsvOnMalformedFail),
+		).
+		Example("Route malformed rows", "Consume typed rows from CSV files in S3 and send rows that are malformed to a dead letter topic.", `
+input:
+  aws_s3:
+    bucket: my-bucket
+    prefix: exports/
+    scanner:
+      typed_csv:
+        infer_types: true
+        column_types:
+          created_at: timestamp:2006-01-02
+        on_malformed: flag
+
+output:
+  switch:
+    cases:
+      - check: errored()
+        output:
+          kafka_franz:
+            seed_brokers: [ localhost:9092 ]
+            topic: exports_dlq
+      - output:
+          kafka_franz:
+            seed_brokers: [ localhost:9092 ]
+            topic: exports
+`)
+}
+
+func init() {
+	service.MustRegisterBatchScannerCreator("typed_csv", typedCSVScannerSpec(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.BatchScannerCreator, error) {
+			return typedCSVScannerFromParsed(conf)
+		})
+}
+
+func singleRuneField(conf *service.ParsedConfig, name string) (rune, error) {
+	s, err := conf.FieldString(name)
+	if err != nil {
+		return 0, err
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("field %v must be a single character, got %q", name, s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r, nil
+}
+
+func typedCSVScannerFromParsed(conf *service.ParsedConfig) (c *typedCSVScannerCreator, err error) {
+	c = &typedCSVScannerCreator{}
+	if c.dialect.delimiter, err = singleRuneField(conf, tcsvFieldDelimiter); err != nil {
+		return nil, err
+	}
+	if c.dialect.quote, err = singleRuneField(conf, tcsvFieldQuote); err != nil {
+		return nil, err
+	}
+	if conf.Contains(tcsvFieldEscape) {
+		if c.dialect.escape, err = singleRuneField(conf, tcsvFieldEscape); err != nil {
+			return nil, err
+		}
+	}
+	if c.dialect.delimiter == c.dialect.quote || c.dialect.delimiter == c.dialect.escape {
+		return nil, fmt.Errorf("field %v must differ from the quote and escape characters", tcsvFieldDelimiter)
+	}
+	if c.dialect.lazyQuotes, err = conf.FieldBool(tcsvFieldLazyQuotes); err != nil {
+		return nil, err
+	}
+	if c.parseHeaderRow, err = conf.FieldBool(tcsvFieldParseHeaderRow); err != nil {
+		return nil, err
+	}
+	if c.timestampLayout, err = conf.FieldString(tcsvFieldTimestampLayout); err != nil {
+		return nil, err
+	}
+
+	columnTypes, err := conf.FieldStringMap(tcsvFieldColumnTypes)
+	if err != nil {
+		return nil, err
+	}
+	c.columnTypes = make(map[string]columnType, len(columnTypes))
+	for k, v := range columnTypes {
+		if c.columnTypes[k], err = parseColumnType(v, c.timestampLayout); err != nil {
+			return nil, fmt.Errorf("column %v: %w", k, err)
+		}
+	}
+
+	if c.inferTypes, err = conf.FieldBool(tcsvFieldInferTypes); err != nil {
+		return nil, err
+	}
+	if c.inferSampleSize, err = conf.FieldInt(tcsvFieldInferSampleSize); err != nil {
+		return nil, err
+	}
+	if c.inferSampleSize < 1 {
+		return nil, fmt.Errorf("field %v must be greater than zero", tcsvFieldInferSampleSize)
+	}
+	if c.onMalformed, err = conf.FieldString(tcsvFieldOnMalformed); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type typedCSVScannerCreator struct {
+	dialect         dialect
+	parseHeaderRow  bool
+	columnTypes     map[string]columnType
+	inferTypes      bool
+	inferSampleSize int
+	timestampLayout string
+	onMalformed     string
+}
+
+// csvRecord is a record read ahead of being emitted.
+type csvRecord struct {
+	fields []string
+	raw    string
+	err    error
+}
+
+func (c *typedCSVScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, _ *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	s := &typedCSVScanner{
+		r:           rdr,
+		rr:          newRecordReader(rdr, c.dialect),
+		onMalformed: c.onMalformed,
+	}
+
+	if c.parseHeaderRow {
+		headers, _, err := s.rr.read()
+		if err != nil {
+			_ = rdr.Close()
+			if errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to read header row: %w", err)
+		}
+		s.headers = headers
+		for k := range c.columnTypes {
+			if !slices.Contains(headers, k) {
+				_ = rdr.Close()
+				return nil, fmt.Errorf("column %v does not exist in the header row", k)
+			}
+		}
+	}
+
+	if c.inferTypes {
+		for range c.inferSampleSize {
+			fields, raw, err := s.rr.read()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				var mErr *malformedError
+				if !errors.As(err, &mErr) {
+					_ = rdr.Close()
+					return nil, err
+				}
+			}
+			s.buffered = append(s.buffered, csvRecord{fields: fields, raw: raw, err: err})
+		}
+	}
+
+	s.columnTypes = c.columnTypes
+	if c.inferTypes {
+		s.inferred = map[int]columnType{}
+		width := len(s.headers)
+		for _, rec := range s.buffered {
+			width = max(width, len(rec.fields))
+		}
+		for i := range width {
+			if _, exists := c.columnTypes[s.columnName(i)]; exists {
+				continue
+			}
+			var values []string
+			for _, rec := range s.buffered {
+				if rec.err == nil && i < len(rec.fields) {
+					values = append(values, rec.fields[i])
+				}
+			}
+			s.inferred[i] = inferColumnType(values, c.timestampLayout)
+		}
+	}
+
+	return service.AutoAggregateBatchScannerAcks(s, aFn), nil
+}
+
+func (*typedCSVScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type typedCSVScanner struct {
+	r  io.ReadCloser
+	rr *recordReader
+
+	headers     []string
+	columnTypes map[string]columnType
+	inferred    map[int]columnType
+	onMalformed string
+
+	buffered []csvRecord
+	row      int
+}
+
+func (s *typedCSVScanner) columnName(i int) string {
+	if i < len(s.headers) {
+		return s.headers[i]
+	}
+	return strconv.Itoa(i)
+}
+
+func (s *typedCSVScanner) columnType(i int) columnType {
+	if t, exists := s.columnTypes[s.columnName(i)]; exists {
+		return t
+	}
+	return s.inferred[i]
+}
+
+func (s *typedCSVScanner) next() (fields []string, raw string, err error) {
+	if len(s.buffered) > 0 {
+		rec := s.buffered[0]
+		s.buffered = s.buffered[1:]
+		return rec.fields, rec.raw, rec.err
+	}
+	return s.rr.read()
+}
+
+// structured converts the fields of a record into an object when a header row
+// was parsed, or an array otherwise.
+func (s *typedCSVScanner) structured(fields []string) (any, error) {
+	if s.headers != nil && len(fields) != len(s.headers) {
+		return nil, fmt.Errorf("wrong number of fields, expected %v, got %v", len(s.headers), len(fields))
+	}
+
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		v, err := s.columnType(i).coerce(f)
+		if err != nil {
+			return nil, fmt.Errorf("column %v: %w", s.columnName(i), err)
+		}
+		values[i] = v
+	}
+
+	if s.headers == nil {
+		return values, nil
+	}
+	obj := make(map[string]any, len(values))
+	for i, v := range values {
+		obj[s.headers[i]] = v
+	}
+	return obj, nil
+}
+
+func (s *typedCSVScanner) NextBatch(context.Context) (service.MessageBatch, error) {
+	for {
+		fields, raw, err := s.next()
+		if err != nil {
+			var mErr *malformedError
+			if !errors.As(err, &mErr) {
+				return nil, err
+			}
+		}
+
+		var v any
+		if err == nil {
+			if v, err = s.structured(fields); err != nil {
+				err = fmt.Errorf("row %v: %w", s.row, err)
+			}
+		}
+
+		row := s.row
+		s.row++
+
+		if err != nil {
+			switch s.onMalformed {
+			case tcsvOnMalformedSkip:
+				continue
+			case tcsvOnMalformedFlag:
+				msg := service.NewMessage([]byte(raw))
+				msg.MetaSetMut("csv_row", row)
+				msg.SetError(err)
+				return service.MessageBatch{msg}, nil
+			default:
+				return nil, err
+			}
+		}
+
+		msg := service.NewMessage(nil)
+		msg.MetaSetMut("csv_row", row)
+		msg.SetStructuredMut(v)
+		return service.MessageBatch{msg}, nil
+	}
+}
+
+func (s *typedCSVScanner) Close(context.Context) error {
+	return s.r.Close()
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type scannedMessage struct {
+	content string
+	row     int
+	err     string
+}
+
+func scanTypedCSV(t *testing.T, conf, input string) (msgs []scannedMessage, err error) {
+	t.Helper()
+
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	creator, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	strm, err := creator.Create(io.NopCloser(strings.NewReader(input)), func(context.Context, error) error {
+		return nil
+	}, service.NewScannerSourceDetails())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		require.NoError(t, strm.Close(t.Context()))
+	}()
+
+	for {
+		batch, aFn, err := strm.NextBatch(t.Context())
+		if errors.Is(err, io.EOF) {
+			return msgs, nil
+		}
+		if err != nil {
+			return msgs, err
+		}
+		require.NoError(t, aFn(t.Context(), nil))
+
+		for _, m := range batch {
+			mBytes, err := m.AsBytes()
+			require.NoError(t, err)
+
+			row, _ := m.MetaGetMut("csv_row")
+			sm := scannedMessage{content: string(mBytes), row: row.(int)}
+			if mErr := m.GetError(); mErr != nil {
+				sm.err = mErr.Error()
+			}
+			msgs = append(msgs, sm)
+		}
+	}
+}
+
+func TestTypedCSVColumnTypes(t *testing.T) {
+	msgs, err := scanTypedCSV(t, `
+test:
+  typed_csv:
+    column_types:
+      id: int
+      price: float
+      in_stock: bool
+      created_at: timestamp:2006-01-02
+      stamp: timestamp
+`, `id,name,price,in_stock,created_at,stamp
+1,foo,1.5,true,2024-01-02,2024-01-02T03:04:05Z
+2,bar,,false,2024-02-03,
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []scannedMessage{
+		{content: `{"created_at":"2024-01-02T00:00:00Z","id":1,"in_stock":true,"name":"foo","price":1.5,"stamp":"2024-01-02T03:04:05Z"}`, row: 0},
+		{content: `{"created_at":"2024-02-03T00:00:00Z","id":2,"in_stock":false,"name":"bar","price":null,"stamp":null}`, row: 1},
+	}, msgs)
+}
+
+func TestTypedCSVInferTypes(t *testing.T) {
+	msgs, err := scanTypedCSV(t, `
+test:
+  typed_csv:
+    infer_types: true
+    infer_sample_size: 2
+    column_types:
+      code: string
+`, `code,count,ratio,flag,when,name
+01,1,1,true,2024-01-02T03:04:05Z,foo
+02,,2.5,false,,bar
+03,3,4,true,2024-01-02T03:04:05Z,10
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []scannedMessage{
+		{content: `{"code":"01","count":1,"flag":true,"name":"foo","ratio":1,"when":"2024-01-02T03:04:05Z"}`, row: 0},
+		{content: `{"code":"02","count":null,"flag":false,"name":"bar","ratio":2.5,"when":null}`, row: 1},
+		{content: `{"code":"03","count":3,"flag":true,"name":"10","ratio":4,"when":"2024-01-02T03:04:05Z"}`, row: 2},
+	}, msgs)
+}
+
+func TestTypedCSVNoHeader(t *testing.T) {
+	msgs, err := scanTypedCSV(t, `
+test:
+  typed_csv:
+    parse_header_row: false
+    delimiter: "|"
+    column_types:
+      "1": int
+`, "a|1\nb|2\n")
+	require.NoError(t, err)
+	assert.Equal(t, []scannedMessage{
+		{content: `["a",1]`, row: 0},
+		{content: `["b",2]`, row: 1},
+	}, msgs)
+}
+
+func TestTypedCSVMalformed(t *testing.T) {
+	input := `id,name
+1,foo
+x,bar
+3
+4,"baz
+5,qux
+`
+	msgs, err := scanTypedCSV(t, `
+test:
+  typed_csv:
+    column_types:
+      id: int
+    on_malformed: flag
+`, input)
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+	assert.Equal(t, scannedMessage{content: `{"id":1,"name":"foo"}`, row: 0}, msgs[0])
+	assert.Equal(t, "x,bar", msgs[1].content)
+	assert.Contains(t, msgs[1].err, "row 1: column id")
+	assert.Equal(t, "3", msgs[2].content)
+	assert.Contains(t, msgs[2].err, "wrong number of fields, expected 2, got 1")
+	assert.Equal(t, "4,\"baz\n5,qux", msgs[3].content)
+	assert.Contains(t, msgs[3].err, "missing closing")
+
+	msgs, err = scanTypedCSV(t, `
+test:
+  typed_csv:
+    column_types:
+      id: int
+    on_malformed: skip
+`, "id,name\nx,foo\n2,bar\n")
+	require.NoError(t, err)
+	assert.Equal(t, []scannedMessage{
+		{content: `{"id":2,"name":"bar"}`, row: 1},
+	}, msgs)
+
+	msgs, err = scanTypedCSV(t, `
+test:
+  typed_csv:
+    column_types:
+      id: int
+`, "id,name\n1,foo\nx,bar\n")
+	require.ErrorContains(t, err, "row 1: column id")
+	assert.Len(t, msgs, 1)
+}
+
+func TestTypedCSVConfigErrors(t *testing.T) {
+	_, err := scanTypedCSV(t, `
+test:
+  typed_csv:
+    column_types:
+      nope: int
+`, "id,name\n")
+	require.ErrorContains(t, err, "column nope does not exist in the header row")
+
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	for _, conf := range []string{
+		`
+test:
+  typed_csv:
+    delimiter: "::"
+`,
+		`
+test:
+  typed_csv:
+    column_types:
+      id: integer
+`,
+	} {
+		pConf, err := confSpec.ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = pConf.FieldScanner("test")
+		require.Error(t, err, conf)
+	}
+}
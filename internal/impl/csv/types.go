@@ -0,0 +1,109 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindTimestamp
+)
+
+// columnType describes how the values of a column are coerced.
+type columnType struct {
+	kind   columnKind
+	layout string
+}
+
+// parseColumnType parses a type of the form int, float, bool, string,
+// timestamp or timestamp:<layout>.
+func parseColumnType(s, defaultLayout string) (columnType, error) {
+	name, layout, hasLayout := strings.Cut(s, ":")
+	switch name {
+	case "string":
+		return columnType{kind: kindString}, nil
+	case "int":
+		return columnType{kind: kindInt}, nil
+	case "float":
+		return columnType{kind: kindFloat}, nil
+	case "bool":
+		return columnType{kind: kindBool}, nil
+	case "timestamp":
+		if !hasLayout {
+			layout = defaultLayout
+		}
+		return columnType{kind: kindTimestamp, layout: layout}, nil
+	}
+	return columnType{}, fmt.Errorf("unrecognised column type: %v", s)
+}
+
+// coerce converts a value to the type of the column, where empty values of
+// columns that are not strings are null.
+func (c columnType) coerce(v string) (any, error) {
+	if c.kind == kindString {
+		return v, nil
+	}
+	if v == "" {
+		return nil, nil
+	}
+	switch c.kind {
+	case kindInt:
+		return strconv.ParseInt(v, 10, 64)
+	case kindFloat:
+		return strconv.ParseFloat(v, 64)
+	case kindBool:
+		return strconv.ParseBool(v)
+	case kindTimestamp:
+		return time.Parse(c.layout, v)
+	}
+	return v, nil
+}
+
+// inferColumnType returns the narrowest type that all of the given values can
+// be coerced to, in the order int, float, bool, timestamp and then string.
+func inferColumnType(values []string, layout string) columnType {
+	for _, t := range []columnType{
+		{kind: kindInt},
+		{kind: kindFloat},
+		{kind: kindBool},
+		{kind: kindTimestamp, layout: layout},
+	} {
+		fits, nonEmpty := true, false
+		for _, v := range values {
+			if v == "" {
+				continue
+			}
+			nonEmpty = true
+			if _, err := t.coerce(v); err != nil {
+				fits = false
+				break
+			}
+		}
+		if fits && nonEmpty {
+			return t
+		}
+	}
+	return columnType{kind: kindString}
+}
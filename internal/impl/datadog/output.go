@@ -0,0 +1,528 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ddoFieldAPIKey      = "api_key"
+	ddoFieldSite        = "site"
+	ddoFieldURL         = "url"
+	ddoFieldAPI         = "api"
+	ddoFieldService     = "service"
+	ddoFieldSource      = "source"
+	ddoFieldHostname    = "hostname"
+	ddoFieldTagsMapping = "tags_mapping"
+	ddoFieldCompression = "compression"
+	ddoFieldTimeout     = "timeout"
+	ddoFieldBackoff     = "backoff"
+	ddoFieldTLS         = "tls"
+	ddoFieldBatching    = "batching"
+
+	// The limits of each request to the logs intake API.
+	logsMaxEntries      = 1000
+	logsMaxPayloadBytes = 5 * 1000 * 1000
+)
+
+func outputSpec() *service.ConfigSpec {
+	retryDefaults := backoff.NewExponentialBackOff()
+	retryDefaults.InitialInterval = time.Second
+	retryDefaults.MaxInterval = time.Minute
+	retryDefaults.MaxElapsedTime = time.Minute * 5
+
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Sends messages to Datadog as logs or events.").
+		Description(`
+With the `+"`logs`"+` API messages are sent to the https://docs.datadoghq.com/api/latest/logs/#send-logs[logs intake API^], where each batch is sent with as few requests as the limits of the API allow. Messages that are objects are sent as structured logs, where the `+"`message`"+` field is the log message and all other fields are attributes, and all other messages are sent as the message of a log.
+
+With the `+"`events`"+` API each message is sent to the https://docs.datadoghq.com/api/latest/events/#post-an-event[events API^] as an event, and messages must be objects with at least a `+"`title`"+` and `+"`text`"+`.
+
+Requests that are rate limited or fail with a server error are attempted again according to the `+"`backoff`"+` field, waiting at least as long as the period requested by Datadog.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringField(ddoFieldAPIKey).
+				Description("A Datadog API key.").
+				Secret(),
+			service.NewStringField(ddoFieldSite).
+				Description("The Datadog site to send to.").
+				Examples("datadoghq.com", "datadoghq.eu", "us3.datadoghq.com", "us5.datadoghq.com", "ap1.datadoghq.com").
+				Default("datadoghq.com"),
+			service.NewURLField(ddoFieldURL).
+				Description("An optional URL to send requests to instead of the endpoint of the site, such as a proxy.").
+				Optional().
+				Advanced(),
+			service.NewStringEnumField(ddoFieldAPI, "logs", "events").
+				Description("The API to send messages to.").
+				Default("logs"),
+			service.NewInterpolatedStringField(ddoFieldService).
+				Description("An optional name of the service that produced each log, which is not supported by the events API.").
+				Example("checkout").
+				Example(`${! @kafka_topic }`).
+				Optional(),
+			service.NewInterpolatedStringField(ddoFieldSource).
+				Description("An optional source of each log, which is the technology the log originated from, or the source type name of each event.").
+				Example("nginx").
+				Optional(),
+			service.NewInterpolatedStringField(ddoFieldHostname).
+				Description("An optional name of the host that produced each log or event.").
+				Example(`${! this.host }`).
+				Optional(),
+			service.NewBloblangField(ddoFieldTagsMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to the tags of each log or event, either as an array of tags or an object of tag keys and values.").
+				Example(`root = [ "env:prod", "team:" + @team ]`).
+				Example(`root = { "env": "prod", "region": this.region }`).
+				Optional(),
+			service.NewStringEnumField(ddoFieldCompression, "none", "gzip", "deflate").
+				Description("The compression of requests to the logs API. Requests to the events API are not compressed.").
+				Default("gzip").
+				Advanced(),
+			service.NewDurationField(ddoFieldTimeout).
+				Description("The maximum period of time to wait for each request.").
+				Default("30s").
+				Advanced(),
+			service.NewBackOffField(ddoFieldBackoff, false, retryDefaults).
+				Description("The backoff applied between attempts of requests that were rate limited or failed with a server error, after which the batch is failed.").
+				Advanced(),
+			service.NewTLSToggledField(ddoFieldTLS).
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ddoFieldBatching),
+		).
+		Example("Kafka logs", "Sends JSON logs consumed from Kafka topics to Datadog, with the topic as the service.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ checkout_logs, payments_logs ]
+    consumer_group: datadog
+
+output:
+  datadog:
+    api_key: ${DD_API_KEY}
+    site: datadoghq.eu
+    service: ${! @kafka_topic.trim_suffix("_logs") }
+    source: go
+    tags_mapping: 'root = { "env": "prod", "partition": @kafka_partition }'
+    batching:
+      count: 500
+      period: 1s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"datadog", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ddoFieldBatching); err != nil {
+				return
+			}
+			out, err = newOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+type output struct {
+	apiKey      string
+	url         string
+	events      bool
+	service     *service.InterpolatedString
+	source      *service.InterpolatedString
+	hostname    *service.InterpolatedString
+	tagsMapping *bloblang.Executor
+	compression string
+	backoff     *backoff.ExponentialBackOff
+
+	client *http.Client
+	log    *service.Logger
+	nowFn  func() time.Time
+}
+
+func newOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{
+		log:   mgr.Logger(),
+		nowFn: time.Now,
+	}
+
+	var err error
+	if o.apiKey, err = conf.FieldString(ddoFieldAPIKey); err != nil {
+		return nil, err
+	}
+	api, err := conf.FieldString(ddoFieldAPI)
+	if err != nil {
+		return nil, err
+	}
+	o.events = api == "events"
+
+	if conf.Contains(ddoFieldURL) {
+		u, err := conf.FieldURL(ddoFieldURL)
+		if err != nil {
+			return nil, err
+		}
+		o.url = u.String()
+	} else {
+		site, err := conf.FieldString(ddoFieldSite)
+		if err != nil {
+			return nil, err
+		}
+		if o.events {
+			o.url = "https://api." + site + "/api/v1/events"
+		} else {
+			o.url = "https://http-intake.logs." + site + "/api/v2/logs"
+		}
+	}
+
+	for name, target := range map[string]**service.InterpolatedString{
+		ddoFieldService:  &o.service,
+		ddoFieldSource:   &o.source,
+		ddoFieldHostname: &o.hostname,
+	} {
+		if conf.Contains(name) {
+			if *target, err = conf.FieldInterpolatedString(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if conf.Contains(ddoFieldTagsMapping) {
+		if o.tagsMapping, err = conf.FieldBloblang(ddoFieldTagsMapping); err != nil {
+			return nil, err
+		}
+	}
+	if o.compression, err = conf.FieldString(ddoFieldCompression); err != nil {
+		return nil, err
+	}
+	if o.backoff, err = conf.FieldBackOff(ddoFieldBackoff); err != nil {
+		return nil, err
+	}
+
+	timeout, err := conf.FieldDuration(ddoFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(ddoFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{Transport: transport, Timeout: timeout}
+	return o, nil
+}
+
+func (*output) Connect(context.Context) error {
+	return nil
+}
+
+func (o *output) tags(exec *service.MessageBatchBloblangExecutor, i int) ([]string, error) {
+	if exec == nil {
+		return nil, nil
+	}
+	res, err := exec.Query(i)
+	if err != nil {
+		return nil, fmt.Errorf("tags mapping failed: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	v, err := res.AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("tags mapping failed: %w", err)
+	}
+
+	var tags []string
+	switch t := v.(type) {
+	case []any:
+		for _, e := range t {
+			if e != nil {
+				tags = append(tags, bloblang.ValueToString(e))
+			}
+		}
+	case map[string]any:
+		for k, e := range t {
+			if e != nil {
+				tags = append(tags, k+":"+bloblang.ValueToString(e))
+			}
+		}
+		// Sort for deterministic payloads.
+		slices.Sort(tags)
+	default:
+		return nil, fmt.Errorf("tags mapping returned an unsupported result: %T", v)
+	}
+	return tags, nil
+}
+
+// messageObject returns the fields of a log or event, which are the fields of
+// the message when it is an object.
+func messageObject(msg *service.Message) (map[string]any, bool) {
+	if v, err := msg.AsStructuredMut(); err == nil {
+		if obj, ok := v.(map[string]any); ok {
+			return obj, true
+		}
+	}
+	b, _ := msg.AsBytes()
+	return map[string]any{"message": string(b)}, false
+}
+
+func (o *output) entries(batch service.MessageBatch) ([]map[string]any, error) {
+	var tagsExec *service.MessageBatchBloblangExecutor
+	if o.tagsMapping != nil {
+		tagsExec = batch.BloblangExecutor(o.tagsMapping)
+	}
+	var serviceExec, sourceExec, hostnameExec *service.MessageBatchInterpolationExecutor
+	if o.service != nil {
+		serviceExec = batch.InterpolationExecutor(o.service)
+	}
+	if o.source != nil {
+		sourceExec = batch.InterpolationExecutor(o.source)
+	}
+	if o.hostname != nil {
+		hostnameExec = batch.InterpolationExecutor(o.hostname)
+	}
+
+	entries := make([]map[string]any, 0, len(batch))
+	for i, msg := range batch {
+		obj, isObj := messageObject(msg)
+		if o.events && !isObj {
+			return nil, fmt.Errorf("message %v: events must be objects", i)
+		}
+
+		sourceKey, hostKey := "ddsource", "hostname"
+		if o.events {
+			sourceKey, hostKey = "source_type_name", "host"
+		}
+		setField := func(key string, exec *service.MessageBatchInterpolationExecutor) error {
+			if exec == nil {
+				return nil
+			}
+			s, err := exec.TryString(i)
+			if err != nil {
+				return fmt.Errorf("message %v: %v interpolation error: %w", i, key, err)
+			}
+			if s != "" {
+				obj[key] = s
+			}
+			return nil
+		}
+		if !o.events {
+			if err := setField("service", serviceExec); err != nil {
+				return nil, err
+			}
+		}
+		if err := setField(sourceKey, sourceExec); err != nil {
+			return nil, err
+		}
+		if err := setField(hostKey, hostnameExec); err != nil {
+			return nil, err
+		}
+
+		tags, err := o.tags(tagsExec, i)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if len(tags) > 0 {
+			if o.events {
+				obj["tags"] = tags
+			} else {
+				obj["ddtags"] = strings.Join(tags, ",")
+			}
+		}
+		entries = append(entries, obj)
+	}
+	return entries, nil
+}
+
+func (o *output) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	entries, err := o.entries(batch)
+	if err != nil {
+		return err
+	}
+
+	if o.events {
+		for i, e := range entries {
+			payload, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+			if err := o.sendWithRetries(ctx, payload, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Split batches into requests within the limits of the logs intake API.
+	var chunk [][]byte
+	var chunkBytes int
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		payload := append([]byte{'['}, bytes.Join(chunk, []byte{','})...)
+		payload = append(payload, ']')
+		chunk, chunkBytes = nil, 0
+		return o.sendWithRetries(ctx, payload, true)
+	}
+	for i, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("message %v: %w", i, err)
+		}
+		if len(chunk) == logsMaxEntries || chunkBytes+len(b)+2 > logsMaxPayloadBytes {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		chunk = append(chunk, b)
+		chunkBytes += len(b) + 1
+	}
+	return flush()
+}
+
+// retryableError is a failed request that may succeed when attempted again,
+// optionally after a delay requested by Datadog.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *retryableError) Error() string {
+	return r.err.Error()
+}
+
+func (r *retryableError) Unwrap() error {
+	return r.err
+}
+
+func (o *output) sendWithRetries(ctx context.Context, payload []byte, compress bool) error {
+	body := payload
+	var encoding string
+	if compress && o.compression != "none" {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		if o.compression == "gzip" {
+			w, encoding = gzip.NewWriter(&buf), "gzip"
+		} else {
+			w, encoding = zlib.NewWriter(&buf), "deflate"
+		}
+		if _, err := w.Write(payload); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	boff := *o.backoff
+	boff.Reset()
+	for {
+		err := o.send(ctx, body, encoding)
+		var rErr *retryableError
+		if !errors.As(err, &rErr) {
+			return err
+		}
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		wait = max(wait, rErr.retryAfter)
+		o.log.Debugf("Retrying request in %v: %v", wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (o *output) send(ctx context.Context, body []byte, encoding string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", o.apiKey)
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &retryableError{err: err}
+	}
+	defer res.Body.Close()
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+	err = fmt.Errorf("request failed with status %v: %s", res.StatusCode, bytes.TrimSpace(resBody))
+	if res.StatusCode == http.StatusRequestTimeout ||
+		res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode >= 500 {
+		return &retryableError{err: err, retryAfter: retryAfter(res.Header, o.nowFn())}
+	}
+	return err
+}
+
+// retryAfter returns the period that Datadog requested to wait before
+// requests are attempted again, either with a Retry-After header or the
+// rate limit reset header of its APIs.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return t.Sub(now)
+		}
+	}
+	if secs, err := strconv.Atoi(header.Get("X-RateLimit-Reset")); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+func (o *output) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, conf string) *output {
+	t.Helper()
+
+	pConf, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOutputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOutputLogs(t *testing.T) {
+	var mut sync.Mutex
+	var requests [][]map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/logs", r.URL.Path)
+		assert.Equal(t, "foo", r.Header.Get("DD-API-KEY"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+
+		gr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		var entries []map[string]any
+		require.NoError(t, json.NewDecoder(gr).Decode(&entries))
+
+		mut.Lock()
+		requests = append(requests, entries)
+		mut.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+api_key: foo
+url: `+srv.URL+`/api/v2/logs
+service: ${! @topic }
+source: go
+hostname: web-1
+tags_mapping: 'root = { "env": "prod", "partition": @partition, "missing": null }'
+`)
+
+	msgA := service.NewMessage([]byte(`{"message":"order placed","order_id":"abc"}`))
+	msgA.MetaSetMut("topic", "checkout")
+	msgA.MetaSetMut("partition", 3)
+	msgB := service.NewMessage([]byte(`plain log line`))
+	msgB.MetaSetMut("topic", "payments")
+	msgB.MetaSetMut("partition", 1)
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{msgA, msgB}))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, [][]map[string]any{{
+		{
+			"message":  "order placed",
+			"order_id": "abc",
+			"service":  "checkout",
+			"ddsource": "go",
+			"hostname": "web-1",
+			"ddtags":   "env:prod,partition:3",
+		},
+		{
+			"message":  "plain log line",
+			"service":  "payments",
+			"ddsource": "go",
+			"hostname": "web-1",
+			"ddtags":   "env:prod,partition:1",
+		},
+	}}, requests)
+}
+
+func TestOutputLogsSplitsRequests(t *testing.T) {
+	var mut sync.Mutex
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entries []map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&entries))
+		mut.Lock()
+		sizes = append(sizes, len(entries))
+		mut.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+api_key: foo
+url: `+srv.URL+`
+compression: none
+`)
+
+	var batch service.MessageBatch
+	for i := range 2500 {
+		batch = append(batch, service.NewMessage([]byte("log "+strconv.Itoa(i))))
+	}
+	require.NoError(t, o.WriteBatch(t.Context(), batch))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, []int{1000, 1000, 500}, sizes)
+}
+
+func TestOutputRetriesRateLimits(t *testing.T) {
+	var mut sync.Mutex
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("X-RateLimit-Reset", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+api_key: foo
+url: `+srv.URL+`
+backoff:
+  initial_interval: 1ms
+  max_interval: 1ms
+`)
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`hello`))}))
+
+	mut.Lock()
+	assert.Equal(t, 3, attempts)
+	mut.Unlock()
+
+	badSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["Forbidden"]}`))
+	}))
+	defer badSrv.Close()
+
+	o = testOutput(t, `
+api_key: foo
+url: `+badSrv.URL+`
+`)
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`hello`))})
+	require.ErrorContains(t, err, `request failed with status 403: {"errors":["Forbidden"]}`)
+}
+
+func TestOutputEvents(t *testing.T) {
+	var mut sync.Mutex
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Content-Encoding"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mut.Lock()
+		events = append(events, string(b))
+		mut.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+api_key: foo
+url: `+srv.URL+`
+api: events
+service: ignored
+source: kafka
+hostname: ${! this.host | "" }
+tags_mapping: 'root = [ "env:prod" ]'
+`)
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"title":"Deploy","text":"v1.2.3 deployed","host":"web-1"}`)),
+		service.NewMessage([]byte(`{"title":"Rollback","text":"v1.2.2 restored","alert_type":"warning"}`)),
+	}))
+
+	mut.Lock()
+	require.Len(t, events, 2)
+	assert.JSONEq(t, `{"title":"Deploy","text":"v1.2.3 deployed","host":"web-1","source_type_name":"kafka","tags":["env:prod"]}`, events[0])
+	assert.JSONEq(t, `{"title":"Rollback","text":"v1.2.2 restored","alert_type":"warning","source_type_name":"kafka","tags":["env:prod"]}`, events[1])
+	mut.Unlock()
+
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`not an object`))})
+	require.ErrorContains(t, err, "events must be objects")
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dupFieldDeleteHandling  = "delete_handling"
+	dupFieldSoftDeleteField = "soft_delete_field"
+	dupFieldDropTombstones  = "drop_tombstones"
+	dupFieldAddFields       = "add_fields"
+	dupFieldAddFieldsPrefix = "add_fields_prefix"
+
+	deleteHandlingDrop       = "drop"
+	deleteHandlingSoftDelete = "soft_delete"
+	deleteHandlingTombstone  = "tombstone"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Version("4.62.0").
+		Summary("Flattens Debezium change event envelopes into the state of the changed record.").
+		Description(`
+Debezium change events wrap the changed record in an envelope with the fields `+"`before`, `after`, `source`, `op` and `ts_ms`"+`. This processor replaces each envelope with the state of the record after the change, similar to the Debezium `+"`ExtractNewRecordState`"+` transformation, so that CDC pipelines do not need to unwrap envelopes with Bloblang.
+
+Envelopes serialized with their schema (`+"`{\"schema\":{...},\"payload\":{...}}`"+`) are also supported, in which case the schema is discarded.
+
+Create (`+"`c`"+`), read (`+"`r`"+`) and update (`+"`u`"+`) events are replaced with the `+"`after`"+` state of the record. Delete (`+"`d`"+`) events are handled according to the field `+"`"+dupFieldDeleteHandling+"`"+`. Truncate (`+"`t`"+`) and message (`+"`m`"+`) events have no record state and are dropped.
+
+Messages with an empty or `+"`null`"+` body are the tombstones that Debezium emits after each delete for log compacted topics, and are handled according to the field `+"`"+dupFieldDropTombstones+"`"+`.
+
+== Metadata
+
+This processor adds the following metadata fields to each message:
+
+- debezium_op
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(dupFieldDeleteHandling, map[string]string{
+				deleteHandlingDrop:       "Drop delete events, so that only the state after inserts and updates is emitted.",
+				deleteHandlingSoftDelete: "Emit the `before` state of deleted records, with the field `" + dupFieldSoftDeleteField + "` added to all records as a marker of whether it has been deleted.",
+				deleteHandlingTombstone:  "Replace delete events with an empty message, which deletes the key of the record from log compacted topics.",
+			}).
+				Description("How delete events are handled.").
+				Default(deleteHandlingDrop),
+			service.NewStringField(dupFieldSoftDeleteField).
+				Description("The name of the field added to records marking whether the record has been deleted when `"+dupFieldDeleteHandling+"` is `"+deleteHandlingSoftDelete+"`.").
+				Default("__deleted").
+				Advanced(),
+			service.NewBoolField(dupFieldDropTombstones).
+				Description("Whether to drop tombstone messages, which have an empty or `null` body. When `false` tombstones are passed through unchanged.").
+				Default(true),
+			service.NewStringListField(dupFieldAddFields).
+				Description("A list of envelope fields to add to each record, where fields of the `source` block can be referenced without the `source.` prefix unless the name is ambiguous. Each field is added with the name of its path with dots replaced by underscores, prefixed by `"+dupFieldAddFieldsPrefix+"`.").
+				Example([]string{"op", "table", "source.ts_ms"}).
+				Default([]any{}),
+			service.NewStringField(dupFieldAddFieldsPrefix).
+				Description("The prefix of the names of fields added with `"+dupFieldAddFields+"`.").
+				Default("__").
+				Advanced(),
+		).
+		Example("Mirror tables with soft deletes", "Flatten the change events of a table into its rows, keeping deleted rows with a deletion marker along with the table they belong to.", `
+pipeline:
+  processors:
+    - debezium_unwrap:
+        delete_handling: soft_delete
+        add_fields: [ op, table ]
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"debezium_unwrap", processorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			return newUnwrapProcessorFromConfig(conf)
+		})
+}
+
+type unwrapProcessor struct {
+	deleteHandling  string
+	softDeleteField string
+	dropTombstones  bool
+	addFields       []string
+	addFieldsPrefix string
+}
+
+func newUnwrapProcessorFromConfig(conf *service.ParsedConfig) (*unwrapProcessor, error) {
+	p := &unwrapProcessor{}
+
+	var err error
+	if p.deleteHandling, err = conf.FieldString(dupFieldDeleteHandling); err != nil {
+		return nil, err
+	}
+	if p.softDeleteField, err = conf.FieldString(dupFieldSoftDeleteField); err != nil {
+		return nil, err
+	}
+	if p.dropTombstones, err = conf.FieldBool(dupFieldDropTombstones); err != nil {
+		return nil, err
+	}
+	if p.addFields, err = conf.FieldStringList(dupFieldAddFields); err != nil {
+		return nil, err
+	}
+	if p.addFieldsPrefix, err = conf.FieldString(dupFieldAddFieldsPrefix); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func isTombstone(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) == 0 || bytes.Equal(b, []byte("null"))
+}
+
+func (p *unwrapProcessor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	if isTombstone(mBytes) {
+		if p.dropTombstones {
+			return nil, nil
+		}
+		return service.MessageBatch{msg}, nil
+	}
+
+	structured, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse change event: %w", err)
+	}
+	envelope, ok := structured.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected change event to be an object, got %T", structured)
+	}
+	if payload, hasPayload := envelope["payload"]; hasPayload {
+		if _, hasSchema := envelope["schema"]; hasSchema {
+			if payload == nil {
+				if p.dropTombstones {
+					return nil, nil
+				}
+				return service.MessageBatch{msg}, nil
+			}
+			if envelope, ok = payload.(map[string]any); !ok {
+				return nil, fmt.Errorf("expected change event payload to be an object, got %T", payload)
+			}
+		}
+	}
+
+	op, ok := envelope["op"].(string)
+	if !ok {
+		return nil, errors.New("message is not a Debezium change event: missing field op")
+	}
+
+	var record map[string]any
+	switch op {
+	case "c", "r", "u":
+		if record, ok = envelope["after"].(map[string]any); !ok {
+			return nil, fmt.Errorf("expected field after of %v event to be an object, got %T", op, envelope["after"])
+		}
+	case "d":
+		switch p.deleteHandling {
+		case deleteHandlingDrop:
+			return nil, nil
+		case deleteHandlingTombstone:
+			msg.SetBytes(nil)
+			msg.MetaSetMut("debezium_op", op)
+			return service.MessageBatch{msg}, nil
+		}
+		// The before state is null when the source does not capture it, in
+		// which case only the marker can be emitted.
+		if record, ok = envelope["before"].(map[string]any); !ok {
+			record = map[string]any{}
+		}
+	case "t", "m":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown change event op: %v", op)
+	}
+
+	if p.deleteHandling == deleteHandlingSoftDelete {
+		record[p.softDeleteField] = op == "d"
+	}
+	for _, path := range p.addFields {
+		if v, exists := envelopeField(envelope, path); exists {
+			record[p.addFieldsPrefix+strings.ReplaceAll(path, ".", "_")] = v
+		}
+	}
+
+	msg.SetStructuredMut(record)
+	msg.MetaSetMut("debezium_op", op)
+	return service.MessageBatch{msg}, nil
+}
+
+// envelopeField resolves a dot separated path within an envelope, falling
+// back to the source block when the path isn't found at the top level.
+func envelopeField(envelope map[string]any, path string) (any, bool) {
+	if v, exists := getPath(envelope, path); exists {
+		return v, true
+	}
+	if source, ok := envelope["source"].(map[string]any); ok {
+		return getPath(source, path)
+	}
+	return nil, false
+}
+
+func getPath(obj map[string]any, path string) (any, bool) {
+	var current any = obj
+	for seg := range strings.SplitSeq(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func (*unwrapProcessor) Close(context.Context) error {
+	return nil
+}
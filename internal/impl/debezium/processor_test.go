@@ -0,0 +1,112 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	createEvent   = `{"before":null,"after":{"id":1,"name":"foo"},"source":{"connector":"mysql","db":"inventory","table":"users","ts_ms":1700000000000},"op":"c","ts_ms":1700000000001}`
+	updateEvent   = `{"before":{"id":1,"name":"foo"},"after":{"id":1,"name":"bar"},"source":{"connector":"mysql","db":"inventory","table":"users","ts_ms":1700000000000},"op":"u","ts_ms":1700000000001}`
+	deleteEvent   = `{"before":{"id":1,"name":"bar"},"after":null,"source":{"connector":"mysql","db":"inventory","table":"users","ts_ms":1700000000000},"op":"d","ts_ms":1700000000001}`
+	truncateEvent = `{"source":{"connector":"postgresql","table":"users"},"op":"t","ts_ms":1700000000001}`
+	schemaEvent   = `{"schema":{"type":"struct","fields":[]},"payload":{"before":null,"after":{"id":2},"source":{"table":"users"},"op":"r","ts_ms":1700000000001}}`
+)
+
+func testProcessor(t *testing.T, conf string) *unwrapProcessor {
+	t.Helper()
+
+	pConf, err := processorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	proc, err := newUnwrapProcessorFromConfig(pConf)
+	require.NoError(t, err)
+	return proc
+}
+
+func processAll(t *testing.T, proc *unwrapProcessor, inputs ...string) (outputs []string, ops []string) {
+	t.Helper()
+
+	for _, in := range inputs {
+		batch, err := proc.Process(t.Context(), service.NewMessage([]byte(in)))
+		require.NoError(t, err)
+		for _, msg := range batch {
+			b, err := msg.AsBytes()
+			require.NoError(t, err)
+			outputs = append(outputs, string(b))
+			op, _ := msg.MetaGet("debezium_op")
+			ops = append(ops, op)
+		}
+	}
+	return
+}
+
+func TestUnwrapAfterStateOnly(t *testing.T) {
+	proc := testProcessor(t, ``)
+
+	outputs, ops := processAll(t, proc, createEvent, updateEvent, deleteEvent, "", "null", truncateEvent, schemaEvent)
+	assert.Equal(t, []string{
+		`{"id":1,"name":"foo"}`,
+		`{"id":1,"name":"bar"}`,
+		`{"id":2}`,
+	}, outputs)
+	assert.Equal(t, []string{"c", "u", "r"}, ops)
+}
+
+func TestUnwrapSoftDelete(t *testing.T) {
+	proc := testProcessor(t, `
+delete_handling: soft_delete
+add_fields: [ op, table, source.ts_ms, missing ]
+`)
+
+	outputs, ops := processAll(t, proc, createEvent, deleteEvent)
+	assert.Equal(t, []string{
+		`{"__deleted":false,"__op":"c","__source_ts_ms":1700000000000,"__table":"users","id":1,"name":"foo"}`,
+		`{"__deleted":true,"__op":"d","__source_ts_ms":1700000000000,"__table":"users","id":1,"name":"bar"}`,
+	}, outputs)
+	assert.Equal(t, []string{"c", "d"}, ops)
+}
+
+func TestUnwrapTombstones(t *testing.T) {
+	proc := testProcessor(t, `
+delete_handling: tombstone
+drop_tombstones: false
+`)
+
+	outputs, ops := processAll(t, proc, updateEvent, deleteEvent, "")
+	assert.Equal(t, []string{`{"id":1,"name":"bar"}`, ``, ``}, outputs)
+	assert.Equal(t, []string{"u", "d", ""}, ops)
+}
+
+func TestUnwrapErrors(t *testing.T) {
+	proc := testProcessor(t, ``)
+
+	for _, in := range []string{
+		`not json`,
+		`[1,2,3]`,
+		`{"id":1}`,
+		`{"op":"x","after":{}}`,
+		`{"op":"c","after":null}`,
+	} {
+		_, err := proc.Process(t.Context(), service.NewMessage([]byte(in)))
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// bloomFilter is a fixed size bloom filter sized for a number of items at a
+// target false positive rate.
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64
+	hashes uint64
+}
+
+func newBloomFilter(capacity int, fpRate float64) *bloomFilter {
+	n := math.Max(float64(capacity), 1)
+	m := math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(math.Round(m/n*math.Ln2), 1)
+	return &bloomFilter{
+		bits:   make([]uint64, (uint64(m)+63)/64),
+		m:      uint64(m),
+		hashes: uint64(k),
+	}
+}
+
+// locations derives the bit positions of a key with double hashing.
+func (b *bloomFilter) locations(h uint64, fn func(i uint64) bool) bool {
+	h1, h2 := h, (h>>32)|1
+	for i := range b.hashes {
+		if !fn((h1 + i*h2) % b.m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bloomFilter) add(h uint64) {
+	b.locations(h, func(i uint64) bool {
+		b.bits[i/64] |= 1 << (i % 64)
+		return true
+	})
+}
+
+func (b *bloomFilter) test(h uint64) bool {
+	return b.locations(h, func(i uint64) bool {
+		return b.bits[i/64]&(1<<(i%64)) != 0
+	})
+}
+
+func hashKey(key string) uint64 {
+	return xxhash.Sum64String(key)
+}
+
+// rotatingBloomFilter keeps two generations of bloom filters so that memory
+// remains bounded for unbounded streams of keys. Once the current generation
+// reaches its capacity it becomes the previous generation and the oldest keys
+// are forgotten.
+type rotatingBloomFilter struct {
+	capacity int
+	fpRate   float64
+
+	current  *bloomFilter
+	previous *bloomFilter
+	added    int
+}
+
+func newRotatingBloomFilter(capacity int, fpRate float64) *rotatingBloomFilter {
+	return &rotatingBloomFilter{
+		capacity: capacity,
+		fpRate:   fpRate,
+		current:  newBloomFilter(capacity, fpRate),
+	}
+}
+
+// TestAndAdd returns whether a key might have been added previously, and adds
+// it to the filter when it definitely has not.
+func (r *rotatingBloomFilter) TestAndAdd(key string) bool {
+	h := hashKey(key)
+	if r.current.test(h) || (r.previous != nil && r.previous.test(h)) {
+		return true
+	}
+	if r.added >= r.capacity {
+		r.previous, r.current = r.current, newBloomFilter(r.capacity, r.fpRate)
+		r.added = 0
+	}
+	r.current.add(h)
+	r.added++
+	return false
+}
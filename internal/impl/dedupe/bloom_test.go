@@ -0,0 +1,61 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingBloomFilter(t *testing.T) {
+	f := newRotatingBloomFilter(1000, 0.01)
+
+	var negatives int
+	for i := range 1000 {
+		if !f.TestAndAdd("key" + strconv.Itoa(i)) {
+			negatives++
+		}
+	}
+	assert.Greater(t, negatives, 950)
+
+	for i := range 1000 {
+		assert.True(t, f.TestAndAdd("key"+strconv.Itoa(i)), i)
+	}
+
+	var falsePositives int
+	for i := 1000; i < 2000; i++ {
+		if f.TestAndAdd("key" + strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 50)
+
+	// The first generation is retained after a single rotation.
+	assert.True(t, f.TestAndAdd("key0"))
+
+	// But forgotten after another.
+	for i := 2000; i < 3100; i++ {
+		f.TestAndAdd("key" + strconv.Itoa(i))
+	}
+	var remembered int
+	for i := range 1000 {
+		if f.current.test(hashKey("key"+strconv.Itoa(i))) || f.previous.test(hashKey("key"+strconv.Itoa(i))) {
+			remembered++
+		}
+	}
+	assert.Less(t, remembered, 100)
+}
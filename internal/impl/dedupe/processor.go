@@ -0,0 +1,268 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	bdFieldCache             = "cache"
+	bdFieldKey               = "key"
+	bdFieldDropOnErr         = "drop_on_err"
+	bdFieldFilter            = "filter"
+	bdFieldCapacity          = "capacity"
+	bdFieldFalsePositiveRate = "false_positive_rate"
+	bdFieldTrustNegatives    = "trust_negatives"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.62.0").
+		Summary("Deduplicates messages with an in-memory bloom filter in front of a cache resource.").
+		Description(`
+This processor behaves like the `+"xref:components:processors/dedupe.adoc[`dedupe` processor]"+`, where the key of each message is added to a cache with the `+"`add`"+` operation and messages with keys that already exist are dropped. In addition the keys seen by this processor are tracked with a bloom filter, which can tell without contacting the cache whether a key has definitely not been seen before.
+
+By default the filter is only used for observing the stream, and each message is still checked against the cache with the `+"`add`"+` operation, so that deduplication remains exact when the cache is shared by multiple instances of a pipeline. When `+"`"+bdFieldTrustNegatives+"`"+` is enabled keys that are missing from the filter are considered unique without a conditional write, and are stored in the cache with the `+"`set`"+` operation. This avoids conditional writes for the majority of keys, which are costly with caches such as `+"`aws_dynamodb`"+` or `+"`sql`"+`, but is only correct when every instance sharing the cache consumes a distinct set of keys, for example when consuming Kafka partitions keyed by the deduplication key.
+
+Keys that the filter reports as possibly seen are always confirmed with the cache, and therefore false positives of the filter never result in dropped messages.
+
+== Filter capacity
+
+The filter is sized to hold `+"`"+bdFieldFilter+"."+bdFieldCapacity+"`"+` keys with the given false positive rate. Once that many keys have been added a new filter is started and the previous one is kept for lookups, so that memory usage stays bounded while the most recent keys are still remembered. The memory used by the two filters is roughly `+"`2 * capacity * -ln(false_positive_rate) / ln(2)^2`"+` bits, which is around 2.3MB for a million keys at a rate of 0.01.
+
+== Metrics
+
+This processor emits the following metrics:
+
+- `+"`dedupe_filter_negatives`"+`: The number of keys that were missing from the filter.
+- `+"`dedupe_filter_positives`"+`: The number of keys that the filter reported as possibly seen.
+- `+"`dedupe_filter_false_positives`"+`: The number of keys that the filter reported as possibly seen but were missing from the cache.
+
+The observed false positive rate of the filter is `+"`dedupe_filter_false_positives / (dedupe_filter_false_positives + dedupe_filter_negatives)`"+`. Keys that expired from the cache but are still remembered by the filter are also counted as false positives.
+
+== Delivery guarantees
+
+The same caveats as the `+"`dedupe`"+` processor apply, as keys are stored in the cache before messages leave the pipeline.`).
+		Fields(
+			service.NewStringField(bdFieldCache).
+				Description("The xref:components:caches/about.adoc[`cache` resource] to target with this processor."),
+			service.NewInterpolatedStringField(bdFieldKey).
+				Description("An interpolated string yielding the key to deduplicate by for each message.").
+				Examples(`${! meta("kafka_key") }`, `${! content().hash("xxhash64") }`),
+			service.NewBoolField(bdFieldDropOnErr).
+				Description("Whether messages should be dropped when the cache returns a general error such as a network issue.").
+				Default(true),
+			service.NewObjectField(bdFieldFilter,
+				service.NewIntField(bdFieldCapacity).
+					Description("The number of keys held by each generation of the filter.").
+					Default(1000000),
+				service.NewFloatField(bdFieldFalsePositiveRate).
+					Description("The target false positive rate of the filter when it is at capacity.").
+					Default(0.01),
+			).
+				Description("The configuration of the bloom filter.").
+				Advanced(),
+			service.NewBoolField(bdFieldTrustNegatives).
+				Description("Whether keys missing from the filter are considered unique without a conditional write to the cache. Only enable this when instances of the pipeline sharing the cache consume distinct keys.").
+				Default(false).
+				Advanced(),
+		).
+		Example("Deduplicate partitioned Kafka records", "Deduplicate records by their key, where each instance of the pipeline consumes distinct partitions and therefore unique keys are written to Redis without conditional writes.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: dedupe
+
+pipeline:
+  processors:
+    - bloom_dedupe:
+        cache: keys
+        key: ${! @kafka_key }
+        trust_negatives: true
+
+cache_resources:
+  - label: keys
+    redis:
+      url: redis://localhost:6379
+      default_ttl: 24h
+`)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor(
+		"bloom_dedupe", processorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newBloomDedupeFromConfig(conf, mgr)
+		})
+}
+
+type bloomDedupe struct {
+	log *service.Logger
+	res *service.Resources
+
+	cacheName      string
+	key            *service.InterpolatedString
+	dropOnErr      bool
+	trustNegatives bool
+
+	mut    sync.Mutex
+	filter *rotatingBloomFilter
+	// Keys that are being written to the cache after a trusted filter
+	// negative, which are certain duplicates when seen again meanwhile.
+	inFlight map[string]struct{}
+
+	mNegatives      *service.MetricCounter
+	mPositives      *service.MetricCounter
+	mFalsePositives *service.MetricCounter
+}
+
+func newBloomDedupeFromConfig(conf *service.ParsedConfig, res *service.Resources) (*bloomDedupe, error) {
+	d := &bloomDedupe{
+		log:             res.Logger(),
+		res:             res,
+		inFlight:        map[string]struct{}{},
+		mNegatives:      res.Metrics().NewCounter("dedupe_filter_negatives"),
+		mPositives:      res.Metrics().NewCounter("dedupe_filter_positives"),
+		mFalsePositives: res.Metrics().NewCounter("dedupe_filter_false_positives"),
+	}
+
+	var err error
+	if d.cacheName, err = conf.FieldString(bdFieldCache); err != nil {
+		return nil, err
+	}
+	if !res.HasCache(d.cacheName) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", d.cacheName)
+	}
+	if d.key, err = conf.FieldInterpolatedString(bdFieldKey); err != nil {
+		return nil, err
+	}
+	if d.dropOnErr, err = conf.FieldBool(bdFieldDropOnErr); err != nil {
+		return nil, err
+	}
+	if d.trustNegatives, err = conf.FieldBool(bdFieldTrustNegatives); err != nil {
+		return nil, err
+	}
+
+	fConf := conf.Namespace(bdFieldFilter)
+	capacity, err := fConf.FieldInt(bdFieldCapacity)
+	if err != nil {
+		return nil, err
+	}
+	if capacity <= 0 {
+		return nil, errors.New("filter capacity must be greater than zero")
+	}
+	fpRate, err := fConf.FieldFloat(bdFieldFalsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		return nil, errors.New("filter false positive rate must be between zero and one")
+	}
+	d.filter = newRotatingBloomFilter(capacity, fpRate)
+	return d, nil
+}
+
+// checkFilter tests a key against the filter, returning whether it might have
+// been seen and whether it is a certain duplicate of a key in flight.
+func (d *bloomDedupe) checkFilter(key string) (positive, inFlight bool) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if positive = d.filter.TestAndAdd(key); positive {
+		_, inFlight = d.inFlight[key]
+	} else if d.trustNegatives {
+		d.inFlight[key] = struct{}{}
+	}
+	return
+}
+
+func (d *bloomDedupe) doneInFlight(key string) {
+	d.mut.Lock()
+	delete(d.inFlight, key)
+	d.mut.Unlock()
+}
+
+func (d *bloomDedupe) cacheOp(ctx context.Context, key string, add bool) error {
+	var err error
+	if cerr := d.res.AccessCache(ctx, d.cacheName, func(c service.Cache) {
+		if add {
+			err = c.Add(ctx, key, []byte{'t'}, nil)
+		} else {
+			err = c.Set(ctx, key, []byte{'t'}, nil)
+		}
+	}); cerr != nil {
+		err = cerr
+	}
+	return err
+}
+
+func (d *bloomDedupe) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	newBatch := make(service.MessageBatch, 0, len(batch))
+	for i, msg := range batch {
+		key, err := batch.TryInterpolatedString(i, d.key)
+		if err != nil {
+			msg.SetError(fmt.Errorf("key interpolation error: %w", err))
+			newBatch = append(newBatch, msg)
+			continue
+		}
+
+		positive, inFlight := d.checkFilter(key)
+		if positive {
+			d.mPositives.Incr(1)
+			if inFlight {
+				continue
+			}
+		} else {
+			d.mNegatives.Incr(1)
+		}
+
+		trusted := !positive && d.trustNegatives
+		err = d.cacheOp(ctx, key, !trusted)
+		if trusted {
+			d.doneInFlight(key)
+		}
+		if err != nil {
+			if errors.Is(err, service.ErrKeyAlreadyExists) {
+				continue
+			}
+			d.log.Errorf("Cache error: %v", err)
+			if d.dropOnErr {
+				continue
+			}
+			msg.SetError(err)
+		} else if positive {
+			d.mFalsePositives.Incr(1)
+		}
+		newBatch = append(newBatch, msg)
+	}
+
+	if len(newBatch) == 0 {
+		return nil, nil
+	}
+	return []service.MessageBatch{newBatch}, nil
+}
+
+func (*bloomDedupe) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testBloomDedupe(t *testing.T, conf string, res *service.Resources) *bloomDedupe {
+	t.Helper()
+
+	pConf, err := processorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	proc, err := newBloomDedupeFromConfig(pConf, res)
+	require.NoError(t, err)
+	return proc
+}
+
+func processKeys(t *testing.T, proc *bloomDedupe, keys ...string) (outputs []string) {
+	t.Helper()
+
+	var batch service.MessageBatch
+	for _, k := range keys {
+		batch = append(batch, service.NewMessage([]byte(k)))
+	}
+	batches, err := proc.ProcessBatch(t.Context(), batch)
+	require.NoError(t, err)
+	for _, b := range batches {
+		for _, msg := range b {
+			mBytes, err := msg.AsBytes()
+			require.NoError(t, err)
+			outputs = append(outputs, string(mBytes))
+		}
+	}
+	return
+}
+
+func TestBloomDedupe(t *testing.T) {
+	for _, trust := range []string{"false", "true"} {
+		t.Run("trust_negatives "+trust, func(t *testing.T) {
+			res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+			proc := testBloomDedupe(t, `
+cache: foocache
+key: ${! content() }
+trust_negatives: `+trust+`
+`, res)
+
+			assert.Equal(t, []string{"a", "b", "c"}, processKeys(t, proc, "a", "b", "a", "c", "b"))
+			assert.Equal(t, []string{"d"}, processKeys(t, proc, "a", "d", "c"))
+
+			// A second processor sharing the cache, but not the filter, also
+			// drops the keys of the first.
+			other := testBloomDedupe(t, `
+cache: foocache
+key: ${! content() }
+`, res)
+			assert.Equal(t, []string{"e"}, processKeys(t, other, "a", "e", "d"))
+		})
+	}
+}
+
+func TestBloomDedupeFalsePositive(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+	proc := testBloomDedupe(t, `
+cache: foocache
+key: ${! content() }
+`, res)
+
+	assert.Equal(t, []string{"a"}, processKeys(t, proc, "a"))
+
+	// Keys that are remembered by the filter but missing from the cache are
+	// passed through.
+	require.NoError(t, res.AccessCache(t.Context(), "foocache", func(c service.Cache) {
+		require.NoError(t, c.Delete(t.Context(), "a"))
+	}))
+	assert.Equal(t, []string{"a"}, processKeys(t, proc, "a", "a"))
+}
+
+func TestBloomDedupeConfigErrors(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+
+	for name, test := range map[string]struct {
+		conf        string
+		errContains string
+	}{
+		"missing cache": {
+			conf: `
+cache: barcache
+key: ${! content() }
+`,
+			errContains: "cache resource 'barcache' was not found",
+		},
+		"bad capacity": {
+			conf: `
+cache: foocache
+key: ${! content() }
+filter:
+  capacity: 0
+`,
+			errContains: "capacity must be greater than zero",
+		},
+		"bad false positive rate": {
+			conf: `
+cache: foocache
+key: ${! content() }
+filter:
+  false_positive_rate: 1.5
+`,
+			errContains: "false positive rate must be between zero and one",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pConf, err := processorConfig().ParseYAML(test.conf, nil)
+			require.NoError(t, err)
+
+			_, err = newBloomDedupeFromConfig(pConf, res)
+			require.ErrorContains(t, err, test.errContains)
+		})
+	}
+}
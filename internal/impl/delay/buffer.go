@@ -0,0 +1,361 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delay
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	duFieldTimestamp = "timestamp"
+	duFieldPath      = "path"
+	duFieldBatchSize = "batch_size"
+)
+
+func delayUntilBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.62.0").
+		Summary("Holds each message until a point in time computed for it, optionally storing pending messages on disk so that they survive restarts.").
+		Description(`
+The `+"`"+duFieldTimestamp+"`"+` mapping is executed on each message written to this buffer and must return the time at which the message is emitted, either as a timestamp, a string in RFC 3339 format, or a number of seconds since the unix epoch. Messages are emitted in the order of their timestamps, and messages with a timestamp in the past, or for which the mapping fails, are emitted immediately.
+
+This makes it possible to schedule messages for delivery at a later time, such as retrying failed deliveries with a delay that grows with each attempt. Batches written to this buffer are split into individual messages, which are emitted in batches of up to `+"`"+duFieldBatchSize+"`"+` messages. When a batch is rejected downstream its messages return to the buffer and are emitted again immediately.
+
+== Delivery guarantees
+
+When a `+"`"+duFieldPath+"`"+` is set each message is written to a file within the directory and synced to disk before it's acknowledged at the input level, and the file is only removed once the message is acknowledged downstream. Pending messages are loaded from the directory when the buffer is created, and therefore survive restarts and crashes. When the input is exhausted the buffer stops without waiting for pending messages, which are emitted the next time the buffer runs.
+
+When a `+"`"+duFieldPath+"`"+` isn't set pending messages are only held in memory and are lost when the service shuts down unexpectedly. In this case, when the input is exhausted the buffer waits for all pending messages to be emitted before stopping.`).
+		Fields(
+			service.NewBloblangField(duFieldTimestamp).
+				Description("A mapping that returns the time at which a message is emitted.").
+				Examples(
+					`root = now().ts_add_iso8601("PT5M")`,
+					`root = meta("retry_at")`,
+					`root = timestamp_unix() + (2.pow(meta("attempt").number().or(0)) * 10)`,
+				),
+			service.NewStringField(duFieldPath).
+				Description("The path of a directory in which to store pending messages. When empty, pending messages are only held in memory.").
+				Example("/var/lib/connect/delayed").
+				Default(""),
+			service.NewIntField(duFieldBatchSize).
+				Description("The maximum number of messages to emit within a batch.").
+				Default(1),
+		).
+		Example("Retry with exponential delays", "Failed deliveries are written to a topic with an attempt counter, and are delivered again after a delay which doubles with each attempt.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ retries ]
+    consumer_group: retrier
+
+buffer:
+  delay_until:
+    path: /var/lib/connect/retries
+    timestamp: |
+      root = meta("kafka_timestamp_unix").number() + (2.pow(meta("attempt").number().or(0)) * 30)
+
+output:
+  http_client:
+    url: http://localhost:8080/webhook
+    verb: POST
+`)
+}
+
+func init() {
+	service.MustRegisterBatchBuffer(
+		"delay_until", delayUntilBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newDelayUntilBufferFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type duEntry struct {
+	msg  *service.Message
+	at   time.Time
+	seq  uint64
+	file string
+}
+
+type entryHeap []*duEntry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool {
+	if !h[i].at.Equal(h[j].at) {
+		return h[i].at.Before(h[j].at)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h entryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *entryHeap) Push(x any)   { *h = append(*h, x.(*duEntry)) }
+
+func (h *entryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+type delayUntilBuffer struct {
+	log *service.Logger
+
+	timestamp *bloblang.Executor
+	store     *fileStore
+	batchSize int
+	clock     func() time.Time
+
+	mut        sync.Mutex
+	pending    entryHeap
+	nextSeq    uint64
+	endOfInput bool
+	closed     bool
+
+	// changed is closed and replaced whenever the state of the buffer changes
+	// in order to wake up blocked reads.
+	changed chan struct{}
+}
+
+func newDelayUntilBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*delayUntilBuffer, error) {
+	timestamp, err := conf.FieldBloblang(duFieldTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	path, err := conf.FieldString(duFieldPath)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := conf.FieldInt(duFieldBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		return nil, errors.New("batch_size must be greater than zero")
+	}
+	return newDelayUntilBuffer(mgr.Logger(), timestamp, path, batchSize)
+}
+
+func newDelayUntilBuffer(log *service.Logger, timestamp *bloblang.Executor, path string, batchSize int) (*delayUntilBuffer, error) {
+	b := &delayUntilBuffer{
+		log:       log,
+		timestamp: timestamp,
+		batchSize: batchSize,
+		clock:     time.Now,
+		changed:   make(chan struct{}),
+	}
+	if path == "" {
+		return b, nil
+	}
+
+	var err error
+	if b.store, err = openFileStore(path); err != nil {
+		return nil, err
+	}
+	stored, err := b.store.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending messages: %w", err)
+	}
+	for _, e := range stored {
+		b.pending = append(b.pending, e)
+		if e.seq >= b.nextSeq {
+			b.nextSeq = e.seq + 1
+		}
+	}
+	heap.Init(&b.pending)
+	if len(stored) > 0 {
+		b.log.Infof("Loaded %v pending messages from %v", len(stored), path)
+	}
+	return b, nil
+}
+
+// notify wakes up blocked reads, the lock must be held.
+func (b *delayUntilBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *delayUntilBuffer) messageTime(msg *service.Message, now time.Time) time.Time {
+	res, err := msg.BloblangQuery(b.timestamp)
+	if err == nil && res != nil {
+		// Mappings that return a string result in raw contents, which aren't
+		// valid structured data.
+		var v any
+		if v, err = res.AsStructured(); err != nil {
+			var raw []byte
+			raw, err = res.AsBytes()
+			v = string(raw)
+		}
+		var t time.Time
+		if err == nil {
+			if t, err = bloblang.ValueAsTimestamp(v); err == nil {
+				return t
+			}
+		}
+	}
+	if err == nil {
+		err = errors.New("mapping returned no value")
+	}
+	b.log.Debugf("Failed to compute timestamp of message, emitting it immediately: %v", err)
+	return now
+}
+
+func (b *delayUntilBuffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	now := b.clock()
+
+	b.mut.Lock()
+	if b.closed {
+		b.mut.Unlock()
+		return service.ErrEndOfBuffer
+	}
+	entries := make([]*duEntry, len(batch))
+	for i, msg := range batch {
+		entries[i] = &duEntry{
+			msg: msg,
+			at:  b.messageTime(msg, now),
+			seq: b.nextSeq,
+		}
+		b.nextSeq++
+	}
+	b.mut.Unlock()
+
+	if b.store != nil {
+		if err := b.store.save(entries); err != nil {
+			return fmt.Errorf("failed to store messages: %w", err)
+		}
+	}
+
+	b.mut.Lock()
+	if b.closed {
+		b.mut.Unlock()
+		return service.ErrEndOfBuffer
+	}
+	for _, e := range entries {
+		heap.Push(&b.pending, e)
+	}
+	b.notify()
+	b.mut.Unlock()
+
+	return aFn(ctx, nil)
+}
+
+func (b *delayUntilBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	b.mut.Lock()
+	for {
+		if b.closed {
+			b.mut.Unlock()
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if len(b.pending) == 0 && b.endOfInput {
+			b.mut.Unlock()
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if len(b.pending) > 0 && b.store != nil && b.endOfInput {
+			// Pending messages are stored and are emitted once the buffer runs
+			// again.
+			b.mut.Unlock()
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if len(b.pending) > 0 {
+			wait := b.pending[0].at.Sub(b.clock())
+			if wait <= 0 {
+				break
+			}
+			timer = time.NewTimer(wait)
+			timerC = timer.C
+		}
+
+		changed := b.changed
+		b.mut.Unlock()
+
+		select {
+		case <-changed:
+		case <-timerC:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, nil, ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		b.mut.Lock()
+	}
+
+	now := b.clock()
+	var entries []*duEntry
+	for len(entries) < b.batchSize && len(b.pending) > 0 && !b.pending[0].at.After(now) {
+		entries = append(entries, heap.Pop(&b.pending).(*duEntry))
+	}
+	b.mut.Unlock()
+
+	batch := make(service.MessageBatch, len(entries))
+	for i, e := range entries {
+		batch[i] = e.msg.Copy()
+	}
+	return batch, func(_ context.Context, err error) error {
+		if err == nil {
+			if b.store != nil {
+				return b.store.remove(entries)
+			}
+			return nil
+		}
+
+		b.mut.Lock()
+		defer b.mut.Unlock()
+		if b.closed {
+			return nil
+		}
+		for _, e := range entries {
+			heap.Push(&b.pending, e)
+		}
+		b.notify()
+		return nil
+	}, nil
+}
+
+func (b *delayUntilBuffer) EndOfInput() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.endOfInput = true
+	b.notify()
+}
+
+func (b *delayUntilBuffer) Close(context.Context) error {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.closed = true
+	b.notify()
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestBuffer(t *testing.T, conf string) *delayUntilBuffer {
+	t.Helper()
+
+	pConf, err := delayUntilBufferConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	b, err := newDelayUntilBufferFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+	return b
+}
+
+func writeDocs(t *testing.T, b *delayUntilBuffer, docs ...string) {
+	t.Helper()
+
+	var batch service.MessageBatch
+	for _, d := range docs {
+		batch = append(batch, service.NewMessage([]byte(d)))
+	}
+	require.NoError(t, b.WriteBatch(t.Context(), batch, func(context.Context, error) error {
+		return nil
+	}))
+}
+
+func readDocs(t *testing.T, b *delayUntilBuffer) ([]string, service.AckFunc) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), time.Second*5)
+	defer done()
+
+	batch, aFn, err := b.ReadBatch(ctx)
+	require.NoError(t, err)
+
+	docs := make([]string, len(batch))
+	for i, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		docs[i] = string(b)
+	}
+	return docs, aFn
+}
+
+func TestDelayUntilBufferOrdering(t *testing.T) {
+	b := newTestBuffer(t, `
+timestamp: root = this.at
+batch_size: 5
+`)
+
+	now := time.Now()
+	at := func(id string, d time.Duration) string {
+		return fmt.Sprintf(`{"id":%q,"at":%q}`, id, now.Add(d).Format(time.RFC3339Nano))
+	}
+
+	writeDocs(t, b, at("a", 300*time.Millisecond), at("b", -time.Hour), `{"id":"c","at":"nope"}`)
+	writeDocs(t, b, at("d", 100*time.Millisecond))
+
+	docs, aFn := readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{at("b", -time.Hour), `{"id":"c","at":"nope"}`}, docs)
+
+	docs, aFn = readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{at("d", 100*time.Millisecond)}, docs)
+	assert.GreaterOrEqual(t, time.Since(now), 100*time.Millisecond)
+
+	b.EndOfInput()
+
+	// Without a path the buffer waits for pending messages before ending.
+	docs, aFn = readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{at("a", 300*time.Millisecond)}, docs)
+	assert.GreaterOrEqual(t, time.Since(now), 300*time.Millisecond)
+
+	_, _, err := b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestDelayUntilBufferNack(t *testing.T) {
+	b := newTestBuffer(t, `
+timestamp: root = 0
+batch_size: 2
+`)
+
+	writeDocs(t, b, "a", "b", "c")
+
+	docs, aFn := readDocs(t, b)
+	assert.Equal(t, []string{"a", "b"}, docs)
+	require.NoError(t, aFn(t.Context(), errors.New("nope")))
+
+	docs, aFn = readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{"a", "b"}, docs)
+
+	docs, aFn = readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{"c"}, docs)
+}
+
+func TestDelayUntilBufferPersistence(t *testing.T) {
+	dir := t.TempDir()
+	conf := fmt.Sprintf(`
+timestamp: root = meta("at").number()
+path: %v
+batch_size: 5
+`, dir)
+
+	b := newTestBuffer(t, conf)
+
+	future := time.Now().Add(time.Hour).Unix()
+	var batch service.MessageBatch
+	for i, at := range []int64{future, 0, future, 0} {
+		msg := service.NewMessage(fmt.Appendf(nil, "doc%v", i))
+		msg.MetaSetMut("at", at)
+		batch = append(batch, msg)
+	}
+	require.NoError(t, b.WriteBatch(t.Context(), batch, func(context.Context, error) error {
+		return nil
+	}))
+
+	docs, aFn := readDocs(t, b)
+	assert.Equal(t, []string{"doc1", "doc3"}, docs)
+
+	// Delivered messages are removed, and so only the pending messages remain.
+	require.NoError(t, aFn(t.Context(), nil))
+	files, err := filepath.Glob(filepath.Join(dir, "*"+messageExt))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// With a path the buffer ends without waiting for pending messages.
+	b.EndOfInput()
+	_, _, err = b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+	require.NoError(t, b.Close(t.Context()))
+
+	// A write interrupted by a crash leaves a temporary file behind.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000009"+messageExt+tmpExt), []byte("torn"), 0o644))
+
+	b = newTestBuffer(t, conf)
+	b.clock = func() time.Time {
+		return time.Unix(future, 0)
+	}
+	require.Len(t, b.pending, 2)
+	assert.Equal(t, uint64(3), b.nextSeq)
+
+	docs, aFn = readDocs(t, b)
+	require.NoError(t, aFn(t.Context(), nil))
+	assert.Equal(t, []string{"doc0", "doc2"}, docs)
+
+	msgs, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
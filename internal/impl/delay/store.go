@@ -0,0 +1,184 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delay
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	messageExt = ".msg"
+	tmpExt     = ".tmp"
+)
+
+// storedMessage is the encoded form of a pending message.
+type storedMessage struct {
+	At      int64          `msgpack:"at"`
+	Seq     uint64         `msgpack:"seq"`
+	Meta    map[string]any `msgpack:"meta"`
+	Content []byte         `msgpack:"content"`
+}
+
+// fileStore stores each pending message as a file within a directory, which
+// is written to a temporary file and renamed so that a crash never leaves a
+// partially written message behind.
+type fileStore struct {
+	dir string
+}
+
+func openFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) messagePath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, messageExt))
+}
+
+// save writes the messages of entries to disk, and sets the file of each
+// entry.
+func (s *fileStore) save(entries []*duEntry) error {
+	for _, e := range entries {
+		stored := storedMessage{
+			At:   e.at.UnixNano(),
+			Seq:  e.seq,
+			Meta: map[string]any{},
+		}
+		_ = e.msg.MetaWalkMut(func(key string, value any) error {
+			stored.Meta[key] = value
+			return nil
+		})
+		var err error
+		if stored.Content, err = e.msg.AsBytes(); err != nil {
+			return err
+		}
+		data, err := msgpack.Marshal(stored)
+		if err != nil {
+			return err
+		}
+
+		path := s.messagePath(e.seq)
+		if err := writeFileSync(path+tmpExt, data); err != nil {
+			return err
+		}
+		if err := os.Rename(path+tmpExt, path); err != nil {
+			return err
+		}
+		e.file = path
+	}
+	return syncDir(s.dir)
+}
+
+// remove deletes the files of entries that have been delivered.
+func (s *fileStore) remove(entries []*duEntry) error {
+	for _, e := range entries {
+		if e.file == "" {
+			continue
+		}
+		if err := os.Remove(e.file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load reads all messages of the directory, removing temporary files left by
+// writes that were interrupted.
+func (s *fileStore) load() ([]*duEntry, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*duEntry
+	for _, de := range dirEntries {
+		name := de.Name()
+		path := filepath.Join(s.dir, name)
+		if de.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(name, tmpExt) {
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, messageExt) {
+			continue
+		}
+		if _, err := strconv.ParseUint(strings.TrimSuffix(name, messageExt), 10, 64); err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var stored storedMessage
+		if err := msgpack.Unmarshal(data, &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode %v: %w", name, err)
+		}
+
+		msg := service.NewMessage(stored.Content)
+		for k, v := range stored.Meta {
+			msg.MetaSetMut(k, v)
+		}
+		entries = append(entries, &duEntry{
+			msg:  msg,
+			at:   time.Unix(0, stored.At),
+			seq:  stored.Seq,
+			file: path,
+		})
+	}
+	return entries, nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// encodeDataFile encodes rows as a Parquet data file of a table with the given
+// schema, partition columns are omitted from data files as their values are
+// recorded in the transaction log instead.
+func encodeDataFile(schema *dataType, partitionColumns []string, rows []map[string]any) ([]byte, error) {
+	fields, err := schema.arrowFields(partitionColumns)
+	if err != nil {
+		return nil, err
+	}
+	arrowSchema := arrow.NewSchema(fields, nil)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		normalised := make(map[string]any, len(schema.Fields))
+		for _, f := range schema.Fields {
+			if v, exists := row[f.Name]; exists {
+				normalised[f.Name] = normaliseValue(v, f.Type)
+			}
+		}
+		if err := enc.Encode(normalised); err != nil {
+			return nil, err
+		}
+	}
+
+	rdr := array.NewJSONReader(&buf, arrowSchema, array.WithChunk(-1))
+	defer rdr.Release()
+	if !rdr.Next() {
+		if err := rdr.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("no rows to write")
+	}
+	rec := rdr.Record()
+
+	var out bytes.Buffer
+	w, err := pqarrow.NewFileWriter(arrowSchema, &out,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Write(rec); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"gocloud.dev/blob"
+)
+
+const (
+	logDir = "_delta_log/"
+
+	// The highest writer protocol version supported, which covers appends to
+	// tables with column invariants and append only tables.
+	maxWriterVersion = 2
+)
+
+type protocolAction struct {
+	MinReaderVersion int `json:"minReaderVersion"`
+	MinWriterVersion int `json:"minWriterVersion"`
+}
+
+type formatSpec struct {
+	Provider string            `json:"provider"`
+	Options  map[string]string `json:"options"`
+}
+
+type metadataAction struct {
+	ID               string            `json:"id"`
+	Format           formatSpec        `json:"format"`
+	SchemaString     string            `json:"schemaString"`
+	PartitionColumns []string          `json:"partitionColumns"`
+	Configuration    map[string]string `json:"configuration"`
+	CreatedTime      int64             `json:"createdTime"`
+}
+
+type addAction struct {
+	Path             string             `json:"path"`
+	PartitionValues  map[string]*string `json:"partitionValues"`
+	Size             int64              `json:"size"`
+	ModificationTime int64              `json:"modificationTime"`
+	DataChange       bool               `json:"dataChange"`
+	Stats            string             `json:"stats,omitempty"`
+}
+
+type commitInfoAction struct {
+	Timestamp           int64             `json:"timestamp"`
+	Operation           string            `json:"operation"`
+	OperationParameters map[string]string `json:"operationParameters"`
+	IsBlindAppend       bool              `json:"isBlindAppend"`
+	EngineInfo          string            `json:"engineInfo"`
+}
+
+// action is a single line of a commit file, where exactly one of the fields
+// is set.
+type action struct {
+	CommitInfo *commitInfoAction `json:"commitInfo,omitempty"`
+	Protocol   *protocolAction   `json:"protocol,omitempty"`
+	MetaData   *metadataAction   `json:"metaData,omitempty"`
+	Add        *addAction        `json:"add,omitempty"`
+}
+
+// snapshot is the state of a table at a version that is relevant for
+// appending to it.
+type snapshot struct {
+	version  int64
+	protocol *protocolAction
+	metadata *metadataAction
+	schema   *dataType
+}
+
+func commitKey(version int64) string {
+	return fmt.Sprintf("%v%020d.json", logDir, version)
+}
+
+var (
+	commitFileRegexp     = regexp.MustCompile(`^(\d{20})\.json$`)
+	checkpointFileRegexp = regexp.MustCompile(`^(\d{20})\.checkpoint(\.\d{10}\.\d{10})?\.parquet$`)
+)
+
+// logFiles lists the commit and checkpoint files of the transaction log of a
+// table, keyed by their version.
+func logFiles(ctx context.Context, bucket *blob.Bucket) (commits map[int64]string, checkpoints map[int64][]string, err error) {
+	commits, checkpoints = map[int64]string{}, map[int64][]string{}
+
+	iter := bucket.List(&blob.ListOptions{Prefix: logDir})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name := path.Base(obj.Key)
+		if m := commitFileRegexp.FindStringSubmatch(name); m != nil {
+			v, _ := strconv.ParseInt(m[1], 10, 64)
+			commits[v] = obj.Key
+		} else if m := checkpointFileRegexp.FindStringSubmatch(name); m != nil {
+			v, _ := strconv.ParseInt(m[1], 10, 64)
+			checkpoints[v] = append(checkpoints[v], obj.Key)
+		}
+	}
+	return commits, checkpoints, nil
+}
+
+// loadSnapshot reads the latest protocol and metadata of a table from its
+// transaction log, returning a snapshot with a version of -1 when the table
+// does not exist yet.
+func loadSnapshot(ctx context.Context, bucket *blob.Bucket) (*snapshot, error) {
+	commits, checkpoints, err := logFiles(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transaction log: %w", err)
+	}
+
+	snap := &snapshot{version: -1}
+	for v := range commits {
+		snap.version = max(snap.version, v)
+	}
+	if snap.version < 0 {
+		return snap, nil
+	}
+
+	// Walk back through the log until both the latest protocol and metadata
+	// have been found, a checkpoint contains the full state of the table as of
+	// its version.
+	for v := snap.version; v >= 0 && (snap.protocol == nil || snap.metadata == nil); v-- {
+		if parts, exists := checkpoints[v]; exists {
+			if err := readCheckpoint(ctx, bucket, parts, snap); err != nil {
+				return nil, fmt.Errorf("failed to read checkpoint %v: %w", v, err)
+			}
+			break
+		}
+		key, exists := commits[v]
+		if !exists {
+			return nil, fmt.Errorf("transaction log is missing version %v", v)
+		}
+		if err := readCommit(ctx, bucket, key, snap); err != nil {
+			return nil, fmt.Errorf("failed to read version %v: %w", v, err)
+		}
+	}
+	if snap.protocol == nil || snap.metadata == nil {
+		return nil, errors.New("transaction log does not contain the protocol and metadata of the table")
+	}
+
+	snap.schema = &dataType{}
+	if err := json.Unmarshal([]byte(snap.metadata.SchemaString), snap.schema); err != nil {
+		return nil, fmt.Errorf("failed to parse table schema: %w", err)
+	}
+	return snap, nil
+}
+
+func readCommit(ctx context.Context, bucket *blob.Bucket, key string, snap *snapshot) error {
+	data, err := bucket.ReadAll(ctx, key)
+	if err != nil {
+		return err
+	}
+	return applyActions(bytes.NewReader(data), snap)
+}
+
+// applyActions sets the protocol and metadata of a snapshot from newline
+// delimited actions, unless they have already been set from a later version.
+func applyActions(r io.Reader, snap *snapshot) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var a struct {
+			Protocol *protocolAction `json:"protocol"`
+			MetaData *struct {
+				ID               string   `json:"id"`
+				SchemaString     string   `json:"schemaString"`
+				PartitionColumns []string `json:"partitionColumns"`
+			} `json:"metaData"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			return err
+		}
+		if a.Protocol != nil && snap.protocol == nil {
+			snap.protocol = a.Protocol
+		}
+		if a.MetaData != nil && snap.metadata == nil {
+			snap.metadata = &metadataAction{
+				ID:               a.MetaData.ID,
+				SchemaString:     a.MetaData.SchemaString,
+				PartitionColumns: a.MetaData.PartitionColumns,
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// readCheckpoint reads the protocol and metadata from the parts of a Parquet
+// checkpoint by converting its rows into actions.
+func readCheckpoint(ctx context.Context, bucket *blob.Bucket, parts []string, snap *snapshot) error {
+	slices.Sort(parts)
+	for _, key := range parts {
+		data, err := bucket.ReadAll(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		tbl, err := pqarrow.ReadTable(ctx, bytes.NewReader(data), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		tr := array.NewTableReader(tbl, -1)
+		for tr.Next() {
+			if err = array.RecordToJSON(tr.Record(), &buf); err != nil {
+				break
+			}
+		}
+		tr.Release()
+		tbl.Release()
+		if err != nil {
+			return err
+		}
+		if err := applyActions(&buf, snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeActions serialises actions as the content of a commit file.
+func encodeActions(actions []action) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, a := range actions {
+		if err := enc.Encode(a); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
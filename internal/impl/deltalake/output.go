@@ -0,0 +1,426 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dloFieldPath             = "path"
+	dloFieldPartitionColumns = "partition_columns"
+	dloFieldPartitionName    = "name"
+	dloFieldPartitionValue   = "value"
+	dloFieldMaxInFlight      = "max_in_flight"
+	dloFieldBatching         = "batching"
+
+	commitAttempts = 10
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Appends messages to a Delta Lake table.").
+		Description(`
+Each message must be a JSON object, which is written as a row of the table where object fields are matched to the columns of the table by name, fields that do not match a column are ignored. Each batch of messages is written as Parquet data files to the location of the table, which are then added to the table by committing a new version to its transaction log.
+
+The table is located by a URL where the scheme selects the storage of the table: `+"`s3://bucket/path`"+` for AWS S3, `+"`gs://bucket/path`"+` for Google Cloud Storage, `+"`azblob://container/path`"+` for Azure Blob Storage and `+"`file:///path`"+` for the local filesystem. Credentials are obtained from the environment in the standard way of each cloud provider, and query parameters of the URL can be used to configure the storage, e.g. `+"`s3://bucket/path?region=us-east-1`"+`.
+
+If the table does not exist it is created from the first batch of messages, with nullable columns for the fields of the messages where the type of each column is inferred from the first non-null value of the field. Existing tables must only require writer protocol version 2 or lower.
+
+== Partitioning
+
+When `+"`"+dloFieldPartitionColumns+"`"+` are configured each message is written to the partition identified by the values resolved for it, using Hive style partition directories such as `+"`dt=2025-01-01/`"+`. The partition columns must match the partition columns of an existing table, and are created as string columns for new tables.
+
+== Concurrency
+
+Versions are committed to the transaction log with conditional writes, so that when another writer commits the same version first the commit is retried with the next version. Data files of a batch are not committed when another writer has changed the schema or partitioning of the table in the meantime, in which case the batch is rejected and retried with the new schema.
+`).
+		Fields(
+			service.NewStringField(dloFieldPath).
+				Description("The URL of the table.").
+				Example("s3://bucket/tables/events").
+				Example("gs://bucket/tables/events").
+				Example("azblob://container/tables/events").
+				Example("file:///tmp/tables/events"),
+			service.NewObjectListField(dloFieldPartitionColumns,
+				service.NewStringField(dloFieldPartitionName).
+					Description("The name of the partition column."),
+				service.NewInterpolatedStringField(dloFieldPartitionValue).
+					Description("The value of the partition column for each message, an empty string results in a null value."),
+			).
+				Description("The partition columns of the table, in order.").
+				Example([]any{
+					map[string]any{"name": "dt", "value": `${! @kafka_timestamp_unix.ts_format("2006-01-02") }`},
+				}).
+				Default([]any{}),
+			service.NewOutputMaxInFlightField().Default(64),
+			service.NewBatchPolicyField(dloFieldBatching),
+		).
+		Example("Partitioned by date", "Write events from a Kafka topic to a Delta table in S3, partitioned by the date of each event.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: delta
+
+output:
+  delta_lake:
+    path: s3://lakehouse/tables/events?region=us-east-1
+    partition_columns:
+      - name: dt
+        value: '${! this.timestamp.ts_parse("2006-01-02T15:04:05Z07:00").ts_format("2006-01-02") }'
+    batching:
+      count: 10000
+      period: 30s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("delta_lake", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(dloFieldBatching); err != nil {
+				return
+			}
+			out, err = newDeltaLakeOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+type partitionColumn struct {
+	name  string
+	value *service.InterpolatedString
+}
+
+type deltaLakeOutput struct {
+	log *service.Logger
+
+	path       string
+	partitions []partitionColumn
+
+	// Guards the storage and the latest known snapshot of the table, commits
+	// are made while holding the lock so that concurrent batches of this
+	// output do not conflict with each other.
+	snapMut sync.Mutex
+	storage *tableStorage
+	snap    *snapshot
+}
+
+func newDeltaLakeOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*deltaLakeOutput, error) {
+	o := &deltaLakeOutput{log: mgr.Logger()}
+
+	var err error
+	if o.path, err = conf.FieldString(dloFieldPath); err != nil {
+		return nil, err
+	}
+
+	partConfs, err := conf.FieldObjectList(dloFieldPartitionColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, pc := range partConfs {
+		var col partitionColumn
+		if col.name, err = pc.FieldString(dloFieldPartitionName); err != nil {
+			return nil, err
+		}
+		if col.value, err = pc.FieldInterpolatedString(dloFieldPartitionValue); err != nil {
+			return nil, err
+		}
+		o.partitions = append(o.partitions, col)
+	}
+	return o, nil
+}
+
+func (o *deltaLakeOutput) partitionNames() []string {
+	names := make([]string, len(o.partitions))
+	for i, p := range o.partitions {
+		names[i] = p.name
+	}
+	return names
+}
+
+// checkSnapshot returns an error if the output is unable to append to the
+// table as of a snapshot.
+func (o *deltaLakeOutput) checkSnapshot(snap *snapshot) error {
+	if snap.protocol.MinWriterVersion > maxWriterVersion {
+		return fmt.Errorf("table requires writer protocol version %v, which is not supported", snap.protocol.MinWriterVersion)
+	}
+	if names := o.partitionNames(); !slices.Equal(names, snap.metadata.PartitionColumns) {
+		return fmt.Errorf("configured partition columns %v do not match the partition columns %v of the table", names, snap.metadata.PartitionColumns)
+	}
+	if _, err := snap.schema.arrowFields(nil); err != nil {
+		return fmt.Errorf("table schema is not supported: %w", err)
+	}
+	return nil
+}
+
+func (o *deltaLakeOutput) Connect(ctx context.Context) error {
+	o.snapMut.Lock()
+	defer o.snapMut.Unlock()
+
+	if o.storage != nil {
+		return nil
+	}
+
+	storage, err := openTableStorage(ctx, o.path)
+	if err != nil {
+		return fmt.Errorf("failed to open table storage: %w", err)
+	}
+	snap, err := loadSnapshot(ctx, storage.bucket)
+	if err != nil {
+		_ = storage.Close()
+		return err
+	}
+	if snap.version >= 0 {
+		if err := o.checkSnapshot(snap); err != nil {
+			_ = storage.Close()
+			return err
+		}
+	}
+
+	o.storage, o.snap = storage, snap
+	return nil
+}
+
+// tableSchema returns the current schema of the table, creating the metadata
+// of a new table from the rows when the table does not exist yet.
+func (o *deltaLakeOutput) tableSchema(rows []map[string]any) (*tableStorage, *metadataAction, *dataType, error) {
+	o.snapMut.Lock()
+	defer o.snapMut.Unlock()
+
+	if o.storage == nil {
+		return nil, nil, nil, service.ErrNotConnected
+	}
+	if o.snap.metadata == nil {
+		schema := inferSchema(rows, o.partitionNames())
+		o.snap.protocol = &protocolAction{MinReaderVersion: 1, MinWriterVersion: 2}
+		o.snap.metadata = &metadataAction{
+			ID:               uuid.NewString(),
+			Format:           formatSpec{Provider: "parquet", Options: map[string]string{}},
+			SchemaString:     schema.String(),
+			PartitionColumns: o.partitionNames(),
+			Configuration:    map[string]string{},
+			CreatedTime:      time.Now().UnixMilli(),
+		}
+		o.snap.schema = schema
+	}
+	return o.storage, o.snap.metadata, o.snap.schema, nil
+}
+
+func (o *deltaLakeOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	rows := make([]map[string]any, len(batch))
+	values := make([][]*string, len(batch))
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return fmt.Errorf("failed to parse message as JSON: %w", err)
+		}
+		row, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected message to be a JSON object, got %T", v)
+		}
+		rows[i] = row
+
+		values[i] = make([]*string, len(o.partitions))
+		for j, p := range o.partitions {
+			s, err := batch.TryInterpolatedString(i, p.value)
+			if err != nil {
+				return fmt.Errorf("failed to resolve value of partition column %v: %w", p.name, err)
+			}
+			if s != "" {
+				values[i][j] = &s
+			}
+		}
+	}
+
+	storage, metadata, schema, err := o.tableSchema(rows)
+	if err != nil {
+		return err
+	}
+
+	adds, err := o.writeDataFiles(ctx, storage, schema, rows, values)
+	if err != nil {
+		return err
+	}
+	return o.commit(ctx, metadata, adds)
+}
+
+// writeDataFiles writes a data file for each partition of the rows and
+// returns the actions that add them to the table.
+func (o *deltaLakeOutput) writeDataFiles(ctx context.Context, storage *tableStorage, schema *dataType, rows []map[string]any, values [][]*string) ([]action, error) {
+	var dirs []string
+	partitions := map[string][]int{}
+	for i := range rows {
+		dir := partitionDir(o.partitionNames(), values[i])
+		if _, exists := partitions[dir]; !exists {
+			dirs = append(dirs, dir)
+		}
+		partitions[dir] = append(partitions[dir], i)
+	}
+
+	var adds []action
+	for _, dir := range dirs {
+		indexes := partitions[dir]
+		partRows := make([]map[string]any, len(indexes))
+		for i, idx := range indexes {
+			partRows[i] = rows[idx]
+		}
+
+		data, err := encodeDataFile(schema, o.partitionNames(), partRows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode data file: %w", err)
+		}
+
+		key := dir + "part-00000-" + uuid.NewString() + "-c000.snappy.parquet"
+		if err := storage.bucket.WriteAll(ctx, key, data, nil); err != nil {
+			return nil, fmt.Errorf("failed to write data file %v: %w", key, err)
+		}
+
+		partValues := map[string]*string{}
+		for j, p := range o.partitions {
+			partValues[p.name] = values[indexes[0]][j]
+		}
+		stats, _ := json.Marshal(map[string]any{"numRecords": len(partRows)})
+		adds = append(adds, action{Add: &addAction{
+			Path:             (&url.URL{Path: key}).EscapedPath(),
+			PartitionValues:  partValues,
+			Size:             int64(len(data)),
+			ModificationTime: time.Now().UnixMilli(),
+			DataChange:       true,
+			Stats:            string(stats),
+		}})
+	}
+	return adds, nil
+}
+
+// commit commits a new version of the table containing the add actions,
+// retrying with the next version when another writer has committed first.
+// The commit is abandoned when the metadata of the table has changed since
+// the data files were written.
+func (o *deltaLakeOutput) commit(ctx context.Context, metadata *metadataAction, adds []action) error {
+	o.snapMut.Lock()
+	defer o.snapMut.Unlock()
+
+	if o.storage == nil {
+		return service.ErrNotConnected
+	}
+
+	for range commitAttempts {
+		if o.snap.metadata.SchemaString != metadata.SchemaString ||
+			!slices.Equal(o.snap.metadata.PartitionColumns, metadata.PartitionColumns) {
+			return errors.New("table metadata was changed by another writer")
+		}
+
+		version := o.snap.version + 1
+		actions := []action{{CommitInfo: &commitInfoAction{
+			Timestamp:           time.Now().UnixMilli(),
+			Operation:           "WRITE",
+			OperationParameters: map[string]string{"mode": "Append"},
+			IsBlindAppend:       true,
+			EngineInfo:          "Redpanda Connect",
+		}}}
+		if version == 0 {
+			actions = append(actions, action{Protocol: o.snap.protocol}, action{MetaData: o.snap.metadata})
+		}
+		actions = append(actions, adds...)
+
+		data, err := encodeActions(actions)
+		if err != nil {
+			return err
+		}
+
+		err = o.storage.putIfAbsent(ctx, commitKey(version), data)
+		if err == nil {
+			o.snap.version = version
+			return nil
+		}
+		if !errors.Is(err, errVersionExists) {
+			return fmt.Errorf("failed to commit version %v: %w", version, err)
+		}
+
+		o.log.Debugf("Version %v was committed by another writer, retrying", version)
+		snap, err := loadSnapshot(ctx, o.storage.bucket)
+		if err != nil {
+			return err
+		}
+		if err := o.checkSnapshot(snap); err != nil {
+			return err
+		}
+		o.snap = snap
+	}
+	return fmt.Errorf("failed to commit after %v attempts due to concurrent writers", commitAttempts)
+}
+
+func (o *deltaLakeOutput) Close(context.Context) error {
+	o.snapMut.Lock()
+	defer o.snapMut.Unlock()
+
+	if o.storage == nil {
+		return nil
+	}
+	err := o.storage.Close()
+	o.storage = nil
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// partitionDir returns the Hive style directory of a partition, which is
+// empty for unpartitioned tables.
+func partitionDir(names []string, values []*string) string {
+	var dir strings.Builder
+	for i, name := range names {
+		dir.WriteString(escapePartitionValue(name))
+		dir.WriteByte('=')
+		if values[i] == nil {
+			dir.WriteString("__HIVE_DEFAULT_PARTITION__")
+		} else {
+			dir.WriteString(escapePartitionValue(*values[i]))
+		}
+		dir.WriteByte('/')
+	}
+	return dir.String()
+}
+
+// escapePartitionValue escapes characters that are not allowed within the
+// path segments of partition directories, in the same way as Hive.
+func escapePartitionValue(v string) string {
+	var b strings.Builder
+	for _, c := range []byte(v) {
+		if c < 0x20 || c == 0x7f || strings.IndexByte("\"#%'*/:=?\\{[]^", c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestOutput(t *testing.T, conf string) *deltaLakeOutput {
+	t.Helper()
+
+	parsed, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	out, err := newDeltaLakeOutputFromConfig(parsed, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = out.Close(context.Background())
+	})
+	return out
+}
+
+func readLog(t *testing.T, dir string, version int64) []action {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(commitKey(version))))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var actions []action
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a action
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &a))
+		actions = append(actions, a)
+	}
+	require.NoError(t, scanner.Err())
+	return actions
+}
+
+func readDataFile(t *testing.T, dir string, add *addAction) (columns []string, rows int64) {
+	t.Helper()
+
+	p, err := url.PathUnescape(add.Path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+	require.NoError(t, err)
+
+	tbl, err := pqarrow.ReadTable(t.Context(), bytes.NewReader(data), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	defer tbl.Release()
+
+	for _, f := range tbl.Schema().Fields() {
+		columns = append(columns, f.Name)
+	}
+	return columns, tbl.NumRows()
+}
+
+func TestDeltaLakeOutputCreateAndAppend(t *testing.T) {
+	dir := t.TempDir()
+	out := newTestOutput(t, `
+path: file://`+dir+`
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"name":"foo","ts":"2025-01-01T10:00:00Z","tags":["a"],"empty":null}`)),
+		service.NewMessage([]byte(`{"id":2,"name":"bar","nested":{"score":1.5}}`)),
+	}))
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":3,"unknown":true}`)),
+	}))
+
+	first := readLog(t, dir, 0)
+	require.Len(t, first, 4)
+	require.NotNil(t, first[0].CommitInfo)
+	assert.Equal(t, 2, first[1].Protocol.MinWriterVersion)
+	assert.Equal(t, `{"type":"struct","fields":[`+
+		`{"name":"id","type":"long","nullable":true,"metadata":{}},`+
+		`{"name":"name","type":"string","nullable":true,"metadata":{}},`+
+		`{"name":"tags","type":{"type":"array","elementType":"string","containsNull":true},"nullable":true,"metadata":{}},`+
+		`{"name":"ts","type":"timestamp","nullable":true,"metadata":{}},`+
+		`{"name":"nested","type":{"type":"struct","fields":[{"name":"score","type":"double","nullable":true,"metadata":{}}]},"nullable":true,"metadata":{}}`+
+		`]}`, first[2].MetaData.SchemaString)
+	require.NotNil(t, first[3].Add)
+	assert.Equal(t, `{"numRecords":2}`, first[3].Add.Stats)
+
+	columns, rows := readDataFile(t, dir, first[3].Add)
+	assert.Equal(t, []string{"id", "name", "tags", "ts", "nested"}, columns)
+	assert.Equal(t, int64(2), rows)
+
+	second := readLog(t, dir, 1)
+	require.Len(t, second, 2)
+	require.NotNil(t, second[1].Add)
+	_, rows = readDataFile(t, dir, second[1].Add)
+	assert.Equal(t, int64(1), rows)
+}
+
+func TestDeltaLakeOutputPartitioned(t *testing.T) {
+	dir := t.TempDir()
+	out := newTestOutput(t, `
+path: file://`+dir+`
+partition_columns:
+  - name: category
+    value: '${! this.category.or("") }'
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"category":"a/b"}`)),
+		service.NewMessage([]byte(`{"id":2,"category":"c"}`)),
+		service.NewMessage([]byte(`{"id":3,"category":"a/b"}`)),
+		service.NewMessage([]byte(`{"id":4}`)),
+	}))
+
+	actions := readLog(t, dir, 0)
+	assert.Equal(t, []string{"category"}, actions[2].MetaData.PartitionColumns)
+
+	adds := map[string]*addAction{}
+	for _, a := range actions[3:] {
+		require.NotNil(t, a.Add)
+		if v := a.Add.PartitionValues["category"]; v != nil {
+			adds[*v] = a.Add
+		} else {
+			adds[""] = a.Add
+		}
+	}
+	require.Len(t, adds, 3)
+	assert.Regexp(t, `^category=a%252Fb/part-`, adds["a/b"].Path)
+	assert.Regexp(t, `^category=__HIVE_DEFAULT_PARTITION__/part-`, adds[""].Path)
+
+	columns, rows := readDataFile(t, dir, adds["a/b"])
+	assert.Equal(t, []string{"id"}, columns)
+	assert.Equal(t, int64(2), rows)
+}
+
+func TestDeltaLakeOutputConcurrentCommit(t *testing.T) {
+	dir := t.TempDir()
+	out := newTestOutput(t, `
+path: file://`+dir+`
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+	}))
+
+	// Another writer commits the next version.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(commitKey(1))), []byte(`{"commitInfo":{"operation":"WRITE"}}`+"\n"), 0o644))
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":2}`)),
+	}))
+
+	actions := readLog(t, dir, 2)
+	require.Len(t, actions, 2)
+	assert.NotNil(t, actions[1].Add)
+}
+
+func TestDeltaLakeOutputExistingTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		conf     string
+		errMsg   string
+	}{
+		{
+			name:     "unsupported writer version",
+			protocol: `{"protocol":{"minReaderVersion":3,"minWriterVersion":7}}`,
+			errMsg:   "writer protocol version 7",
+		},
+		{
+			name:     "mismatched partition columns",
+			protocol: `{"protocol":{"minReaderVersion":1,"minWriterVersion":2}}`,
+			conf: `
+partition_columns:
+  - name: dt
+    value: foo
+`,
+			errMsg: "do not match",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			logPath := filepath.Join(dir, filepath.FromSlash(commitKey(0)))
+			require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0o755))
+			require.NoError(t, os.WriteFile(logPath, []byte(test.protocol+"\n"+
+				`{"metaData":{"id":"foo","schemaString":"{\"type\":\"struct\",\"fields\":[{\"name\":\"id\",\"type\":\"long\",\"nullable\":true,\"metadata\":{}}]}","partitionColumns":[]}}`+"\n"), 0o644))
+
+			parsed, err := outputSpec().ParseYAML(`
+path: file://`+dir+test.conf, nil)
+			require.NoError(t, err)
+
+			out, err := newDeltaLakeOutputFromConfig(parsed, service.MockResources())
+			require.NoError(t, err)
+			require.ErrorContains(t, out.Connect(t.Context()), test.errMsg)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// dataType is a type of the Delta table schema, which is serialised either as
+// the name of a primitive type or as an object describing a nested type.
+type dataType struct {
+	Name string
+
+	// Set when Name is "struct".
+	Fields []structField
+	// Set when Name is "array".
+	ElementType  *dataType
+	ContainsNull bool
+	// Set when Name is "map".
+	KeyType           *dataType
+	ValueType         *dataType
+	ValueContainsNull bool
+}
+
+type structField struct {
+	Name     string         `json:"name"`
+	Type     *dataType      `json:"type"`
+	Nullable bool           `json:"nullable"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+type nestedType struct {
+	Type              string        `json:"type"`
+	Fields            []structField `json:"fields,omitempty"`
+	ElementType       *dataType     `json:"elementType,omitempty"`
+	ContainsNull      *bool         `json:"containsNull,omitempty"`
+	KeyType           *dataType     `json:"keyType,omitempty"`
+	ValueType         *dataType     `json:"valueType,omitempty"`
+	ValueContainsNull *bool         `json:"valueContainsNull,omitempty"`
+}
+
+func (t *dataType) MarshalJSON() ([]byte, error) {
+	switch t.Name {
+	case "struct":
+		fields := t.Fields
+		if fields == nil {
+			fields = []structField{}
+		}
+		return json.Marshal(nestedType{Type: t.Name, Fields: fields})
+	case "array":
+		return json.Marshal(nestedType{Type: t.Name, ElementType: t.ElementType, ContainsNull: &t.ContainsNull})
+	case "map":
+		return json.Marshal(nestedType{Type: t.Name, KeyType: t.KeyType, ValueType: t.ValueType, ValueContainsNull: &t.ValueContainsNull})
+	}
+	return json.Marshal(t.Name)
+}
+
+func (t *dataType) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return json.Unmarshal(data, &t.Name)
+	}
+
+	var n nestedType
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*t = dataType{
+		Name:        n.Type,
+		Fields:      n.Fields,
+		ElementType: n.ElementType,
+		KeyType:     n.KeyType,
+		ValueType:   n.ValueType,
+	}
+	if n.ContainsNull != nil {
+		t.ContainsNull = *n.ContainsNull
+	}
+	if n.ValueContainsNull != nil {
+		t.ValueContainsNull = *n.ValueContainsNull
+	}
+	return nil
+}
+
+func (t *dataType) String() string {
+	b, _ := t.MarshalJSON()
+	return string(b)
+}
+
+// field returns the top level field of a struct type with the given name.
+func (t *dataType) field(name string) (structField, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+// arrowType returns the Arrow type used to write values of the type to data
+// files.
+func (t *dataType) arrowType() (arrow.DataType, error) {
+	switch t.Name {
+	case "boolean":
+		return arrow.FixedWidthTypes.Boolean, nil
+	case "byte":
+		return arrow.PrimitiveTypes.Int8, nil
+	case "short":
+		return arrow.PrimitiveTypes.Int16, nil
+	case "integer":
+		return arrow.PrimitiveTypes.Int32, nil
+	case "long":
+		return arrow.PrimitiveTypes.Int64, nil
+	case "float":
+		return arrow.PrimitiveTypes.Float32, nil
+	case "double":
+		return arrow.PrimitiveTypes.Float64, nil
+	case "string":
+		return arrow.BinaryTypes.String, nil
+	case "binary":
+		return arrow.BinaryTypes.Binary, nil
+	case "date":
+		return arrow.FixedWidthTypes.Date32, nil
+	case "timestamp":
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+	case "struct":
+		fields, err := t.arrowFields(nil)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.StructOf(fields...), nil
+	case "array":
+		elem, err := t.ElementType.arrowType()
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOfField(arrow.Field{Name: "element", Type: elem, Nullable: t.ContainsNull}), nil
+	case "map":
+		key, err := t.KeyType.arrowType()
+		if err != nil {
+			return nil, err
+		}
+		value, err := t.ValueType.arrowType()
+		if err != nil {
+			return nil, err
+		}
+		m := arrow.MapOf(key, value)
+		m.SetItemNullable(t.ValueContainsNull)
+		return m, nil
+	}
+
+	var precision, scale int32
+	if _, err := fmt.Sscanf(t.Name, "decimal(%d,%d)", &precision, &scale); err == nil {
+		return &arrow.Decimal128Type{Precision: precision, Scale: scale}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t.Name)
+}
+
+// arrowFields returns the Arrow fields of a struct type, skipping the fields
+// named in exclude.
+func (t *dataType) arrowFields(exclude []string) ([]arrow.Field, error) {
+	fields := make([]arrow.Field, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		if slices.Contains(exclude, f.Name) {
+			continue
+		}
+		typ, err := f.Type.arrowType()
+		if err != nil {
+			return nil, fmt.Errorf("column %v: %w", f.Name, err)
+		}
+		fields = append(fields, arrow.Field{Name: f.Name, Type: typ, Nullable: f.Nullable})
+	}
+	return fields, nil
+}
+
+// normaliseValue returns a value of a structured message in the form expected
+// by the Arrow JSON reader for the given type, which represents maps as lists
+// of key and value pairs. Objects and arrays are copied rather than modified.
+func normaliseValue(v any, t *dataType) any {
+	switch t.Name {
+	case "struct":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		res := make(map[string]any, len(t.Fields))
+		for _, f := range t.Fields {
+			if fv, exists := obj[f.Name]; exists {
+				res[f.Name] = normaliseValue(fv, f.Type)
+			}
+		}
+		return res
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		res := make([]any, len(arr))
+		for i, e := range arr {
+			res[i] = normaliseValue(e, t.ElementType)
+		}
+		return res
+	case "map":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		entries := make([]any, 0, len(keys))
+		for _, k := range keys {
+			entries = append(entries, map[string]any{
+				"key":   k,
+				"value": normaliseValue(obj[k], t.ValueType),
+			})
+		}
+		return entries
+	}
+	return v
+}
+
+// inferType returns the type of a value of a structured message, or false if
+// a type cannot be inferred from the value.
+func inferType(v any) (*dataType, bool) {
+	switch t := v.(type) {
+	case bool:
+		return &dataType{Name: "boolean"}, true
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return &dataType{Name: "long"}, true
+		}
+		return &dataType{Name: "double"}, true
+	case int, int32, int64, uint, uint32, uint64:
+		return &dataType{Name: "long"}, true
+	case float32, float64:
+		return &dataType{Name: "double"}, true
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return &dataType{Name: "timestamp"}, true
+		}
+		return &dataType{Name: "string"}, true
+	case time.Time:
+		return &dataType{Name: "timestamp"}, true
+	case []byte:
+		return &dataType{Name: "binary"}, true
+	case map[string]any:
+		st := &dataType{Name: "struct"}
+		inferFields(st, t)
+		if len(st.Fields) == 0 {
+			return nil, false
+		}
+		return st, true
+	case []any:
+		for _, e := range t {
+			if e == nil {
+				continue
+			}
+			elem, ok := inferType(e)
+			if !ok {
+				return nil, false
+			}
+			return &dataType{Name: "array", ElementType: elem, ContainsNull: true}, true
+		}
+	}
+	return nil, false
+}
+
+// inferFields adds nullable fields to a struct type for the fields of an
+// object that are not yet present in it. Fields with null values are skipped
+// as their type cannot be inferred.
+func inferFields(st *dataType, obj map[string]any) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		if _, exists := st.field(k); exists {
+			continue
+		}
+		if typ, ok := inferType(obj[k]); ok {
+			st.Fields = append(st.Fields, structField{Name: k, Type: typ, Nullable: true, Metadata: map[string]any{}})
+		}
+	}
+}
+
+// inferSchema returns the schema of a new table from the rows written to it,
+// where partition columns are always of the string type.
+func inferSchema(rows []map[string]any, partitionColumns []string) *dataType {
+	schema := &dataType{Name: "struct"}
+	for _, col := range partitionColumns {
+		schema.Fields = append(schema.Fields, structField{Name: col, Type: &dataType{Name: "string"}, Nullable: true, Metadata: map[string]any{}})
+	}
+	for _, row := range rows {
+		inferFields(schema, row)
+	}
+	return schema
+}
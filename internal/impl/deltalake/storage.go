@@ -0,0 +1,174 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	azblobblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	// Register the supported storage schemes.
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// errVersionExists is returned when committing a version of the table that
+// has already been committed by another writer.
+var errVersionExists = errors.New("version already exists")
+
+// tableStorage provides access to the files of a table, which are keyed by
+// their path relative to the root of the table.
+type tableStorage struct {
+	bucket *blob.Bucket
+
+	// The directory of the table when stored on the local filesystem, where
+	// commits are made atomic by hard linking the log files into place.
+	localDir string
+}
+
+// openTableStorage opens the storage of a table from a URL such as
+// s3://bucket/path/to/table, where query parameters of the URL configure the
+// bucket.
+func openTableStorage(ctx context.Context, tableURL string) (*tableStorage, error) {
+	u, err := url.Parse(tableURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse table URL: %w", err)
+	}
+
+	if u.Scheme == "file" {
+		dir := filepath.FromSlash(u.Path)
+		q := u.Query()
+		q.Set("create_dir", "true")
+		bucket, err := blob.OpenBucket(ctx, (&url.URL{Scheme: "file", Path: u.Path, RawQuery: q.Encode()}).String())
+		if err != nil {
+			return nil, err
+		}
+		return &tableStorage{bucket: bucket, localDir: dir}, nil
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	bucketURL := url.URL{Scheme: u.Scheme, Host: u.Host, RawQuery: u.RawQuery}
+	bucket, err := blob.OpenBucket(ctx, bucketURL.String())
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" {
+		bucket = blob.PrefixedBucket(bucket, prefix+"/")
+	}
+	return &tableStorage{bucket: bucket}, nil
+}
+
+// putIfAbsent writes a file only if it does not already exist, returning
+// errVersionExists otherwise.
+func (s *tableStorage) putIfAbsent(ctx context.Context, key string, data []byte) error {
+	if s.localDir != "" {
+		return s.linkIfAbsent(key, data)
+	}
+
+	err := s.bucket.WriteAll(ctx, key, data, &blob.WriterOptions{
+		BeforeWrite: func(as func(any) bool) error {
+			var s3Input *s3.PutObjectInput
+			if as(&s3Input) {
+				s3Input.IfNoneMatch = aws.String("*")
+				return nil
+			}
+			var gcsObject **storage.ObjectHandle
+			if as(&gcsObject) {
+				*gcsObject = (*gcsObject).If(storage.Conditions{DoesNotExist: true})
+				return nil
+			}
+			var azureOpts *blockblob.UploadStreamOptions
+			if as(&azureOpts) {
+				etag := azcore.ETagAny
+				azureOpts.AccessConditions = &azblobblob.AccessConditions{
+					ModifiedAccessConditions: &azblobblob.ModifiedAccessConditions{IfNoneMatch: &etag},
+				}
+				return nil
+			}
+			return errors.New("the storage does not support conditional writes")
+		},
+	})
+	if err != nil && s.isConditionFailed(err) {
+		return errVersionExists
+	}
+	return err
+}
+
+func (s *tableStorage) linkIfAbsent(key string, data []byte) error {
+	path := filepath.Join(s.localDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return errVersionExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *tableStorage) isConditionFailed(err error) bool {
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		return true
+	}
+	var apiErr smithy.APIError
+	if s.bucket.ErrorAs(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return true
+		}
+	}
+	var respErr *azcore.ResponseError
+	if s.bucket.ErrorAs(err, &respErr) {
+		return respErr.StatusCode == http.StatusConflict || respErr.StatusCode == http.StatusPreconditionFailed
+	}
+	return false
+}
+
+func (s *tableStorage) Close() error {
+	return s.bucket.Close()
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const (
+	rdFieldOutput      = "output"
+	rdFieldDLQ         = "dlq"
+	rdFieldMaxAttempts = "max_attempts"
+	rdFieldAttemptKey  = "attempt_metadata_key"
+	rdFieldErrorsKey   = "errors_metadata_key"
+	rdFieldBackoff     = "backoff"
+)
+
+func retryDLQOutputConfig() *service.ConfigSpec {
+	retryDefaults := backoff.NewExponentialBackOff()
+	retryDefaults.InitialInterval = time.Millisecond * 500
+	retryDefaults.MaxInterval = time.Second * 30
+	retryDefaults.MaxElapsedTime = 0
+
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Attempts to write messages to a child output a limited number of times for each message, and routes messages that exhaust their attempts to a dead letter queue output along with the errors of each attempt.").
+		Description(`
+Each message written to this output is attempted with the child `+"`"+rdFieldOutput+"`"+`, and messages that fail are attempted again after a `+"`"+rdFieldBackoff+"`"+` period. When the child output reports which messages of a batch failed only those messages are attempted again.
+
+The number of attempts made for a message is stored in the metadata key `+"`"+rdFieldAttemptKey+"`"+` before each attempt. When a message already carries this key, for example because it was consumed from a retry topic, the count continues from its value. Messages are given up on once the count reaches `+"`"+rdFieldMaxAttempts+"`"+`, which is evaluated for each message and therefore may differ between messages.
+
+Messages that exhaust their attempts are written to the `+"`"+rdFieldDLQ+"`"+` output with the error of each attempt stored as an array of strings in the metadata key `+"`"+rdFieldErrorsKey+"`"+`, which also extends any errors that the message already carries. A batch is only acknowledged once all of its messages are delivered either to the child output or the dead letter queue, and when the dead letter queue output fails only the messages that couldn't be written to it are rejected.`).
+		Fields(
+			service.NewOutputField(rdFieldOutput).
+				Description("The child output to write messages to."),
+			service.NewOutputField(rdFieldDLQ).
+				Description("The output to write messages to once they have exhausted their attempts."),
+			service.NewInterpolatedStringField(rdFieldMaxAttempts).
+				Description("The maximum number of attempts for each message, which must resolve to an integer greater than zero.").
+				Examples(`5`, `${! meta("max_attempts").or(3) }`).
+				Default("3"),
+			service.NewStringField(rdFieldAttemptKey).
+				Description("The metadata key holding the number of attempts made for a message.").
+				Default("retry_attempt").
+				Advanced(),
+			service.NewStringField(rdFieldErrorsKey).
+				Description("The metadata key holding the errors of each attempt of messages written to the dead letter queue.").
+				Default("retry_errors").
+				Advanced(),
+			service.NewBackOffField(rdFieldBackoff, false, retryDefaults).
+				Description("The backoff applied between attempts.").
+				Advanced(),
+		).
+		Example("Dead letter topic", "Attempt to deliver events to an HTTP endpoint five times, after which they're written to a Kafka topic along with their errors.", `
+output:
+  retry_dlq:
+    max_attempts: 5
+    output:
+      http_client:
+        url: http://localhost:8080/events
+        verb: POST
+    dlq:
+      kafka_franz:
+        seed_brokers: [ localhost:9092 ]
+        topic: events_dlq
+        metadata:
+          include_patterns: [ retry_.* ]
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"retry_dlq", retryDLQOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newRetryDLQOutputFromConfig(conf, mgr)
+			maxInFlight = 1
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type retryDLQOutput struct {
+	log *service.Logger
+
+	output      wrapper.BatchWriter
+	dlq         wrapper.BatchWriter
+	maxAttempts *service.InterpolatedString
+	attemptKey  string
+	errorsKey   string
+	backoff     *backoff.ExponentialBackOff
+}
+
+func newRetryDLQOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*retryDLQOutput, error) {
+	o := &retryDLQOutput{log: mgr.Logger()}
+
+	var err error
+	if o.maxAttempts, err = conf.FieldInterpolatedString(rdFieldMaxAttempts); err != nil {
+		return nil, err
+	}
+	if o.attemptKey, err = conf.FieldString(rdFieldAttemptKey); err != nil {
+		return nil, err
+	}
+	if o.errorsKey, err = conf.FieldString(rdFieldErrorsKey); err != nil {
+		return nil, err
+	}
+	if o.backoff, err = conf.FieldBackOff(rdFieldBackoff); err != nil {
+		return nil, err
+	}
+	if o.output, err = conf.FieldOutput(rdFieldOutput); err != nil {
+		return nil, err
+	}
+	if o.dlq, err = conf.FieldOutput(rdFieldDLQ); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// rdMessage is a message of a batch that hasn't been delivered yet.
+type rdMessage struct {
+	index       int
+	msg         *service.Message
+	attempts    int64
+	maxAttempts int64
+	errs        []any
+}
+
+func (o *retryDLQOutput) newMessage(index int, msg *service.Message) *rdMessage {
+	m := &rdMessage{index: index, msg: msg.Copy(), maxAttempts: 1}
+
+	if v, exists := msg.MetaGetMut(o.attemptKey); exists {
+		if n, err := bloblang.ValueAsInt64(v); err == nil && n > 0 {
+			m.attempts = n
+		}
+	}
+	if v, exists := msg.MetaGetMut(o.errorsKey); exists {
+		if errs, ok := v.([]any); ok {
+			m.errs = append(m.errs, errs...)
+		}
+	}
+
+	maxStr, err := o.maxAttempts.TryString(msg)
+	if err == nil {
+		var n int64
+		if n, err = strconv.ParseInt(maxStr, 10, 64); err == nil && n > 0 {
+			m.maxAttempts = n
+		} else if err == nil {
+			err = fmt.Errorf("max attempts must be greater than zero, got %v", n)
+		}
+	}
+	if err != nil {
+		o.log.Errorf("Failed to resolve max attempts of message, making a single attempt: %v", err)
+	}
+	return m
+}
+
+func (o *retryDLQOutput) Connect(context.Context) error {
+	return nil
+}
+
+// failures returns the errors of the messages of a batch that failed to be
+// written.
+func failures(batch service.MessageBatch, indexer *service.Indexer, err error) map[int]error {
+	failed := map[int]error{}
+
+	var bErr *service.BatchError
+	if errors.As(err, &bErr) && bErr.IndexedErrors() > 0 {
+		bErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, mErr error) bool {
+			if mErr != nil && i >= 0 {
+				failed[i] = mErr
+			}
+			return true
+		})
+		return failed
+	}
+
+	for i := range batch {
+		failed[i] = err
+	}
+	return failed
+}
+
+func (o *retryDLQOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	pending := make([]*rdMessage, len(batch))
+	for i, msg := range batch {
+		pending[i] = o.newMessage(i, msg)
+	}
+
+	boff := *o.backoff
+	boff.Reset()
+
+	var exhausted []*rdMessage
+	for len(pending) > 0 {
+		attemptBatch := make(service.MessageBatch, len(pending))
+		for i, m := range pending {
+			m.attempts++
+			m.msg.MetaSetMut(o.attemptKey, m.attempts)
+			attemptBatch[i] = m.msg
+		}
+		indexer := attemptBatch.Index()
+
+		err := o.output.WriteBatch(ctx, attemptBatch)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		failed := failures(attemptBatch, indexer, err)
+		var retry []*rdMessage
+		for i, m := range pending {
+			mErr, isFailed := failed[i]
+			if !isFailed {
+				continue
+			}
+			m.errs = append(m.errs, mErr.Error())
+			if m.attempts >= m.maxAttempts {
+				exhausted = append(exhausted, m)
+			} else {
+				retry = append(retry, m)
+			}
+		}
+		if pending = retry; len(pending) == 0 {
+			break
+		}
+
+		o.log.Warnf("Failed to write %v messages, attempting them again: %v", len(pending), err)
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			wait = boff.MaxInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if len(exhausted) == 0 {
+		return nil
+	}
+
+	dlqBatch := make(service.MessageBatch, len(exhausted))
+	for i, m := range exhausted {
+		m.msg.MetaSetMut(o.errorsKey, m.errs)
+		dlqBatch[i] = m.msg
+	}
+	dlqIndexer := dlqBatch.Index()
+	o.log.Debugf("Writing %v messages that exhausted their attempts to the dead letter queue", len(dlqBatch))
+	err := o.dlq.WriteBatch(ctx, dlqBatch)
+	if err == nil {
+		return nil
+	}
+
+	// Only the messages that didn't make it to the dead letter queue are
+	// rejected, as the remaining messages of the batch were delivered.
+	bErr := service.NewBatchError(batch, fmt.Errorf("failed to write to dead letter queue: %w", err))
+	for i, mErr := range failures(dlqBatch, dlqIndexer, err) {
+		bErr.Failed(exhausted[i].index, fmt.Errorf("failed to write to dead letter queue: %w", mErr))
+	}
+	return bErr
+}
+
+func (o *retryDLQOutput) Close(ctx context.Context) error {
+	return errors.Join(o.output.Close(ctx), o.dlq.Close(ctx))
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+type fakeWriter struct {
+	mut     sync.Mutex
+	writeFn func(b service.MessageBatch) error
+	written []service.MessageBatch
+}
+
+func (f *fakeWriter) WriteBatch(_ context.Context, b service.MessageBatch) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var err error
+	if f.writeFn != nil {
+		err = f.writeFn(b)
+	}
+	if err == nil {
+		f.written = append(f.written, b.Copy())
+	}
+	return err
+}
+
+func (*fakeWriter) Close(context.Context) error {
+	return nil
+}
+
+func newTestOutput(t *testing.T, maxAttempts string, out, dlq wrapper.BatchWriter) *retryDLQOutput {
+	t.Helper()
+
+	maxAttemptsStr, err := service.NewInterpolatedString(maxAttempts)
+	require.NoError(t, err)
+
+	boff := backoff.NewExponentialBackOff()
+	boff.InitialInterval = time.Millisecond
+	boff.MaxInterval = time.Millisecond
+
+	return &retryDLQOutput{
+		log:         service.MockResources().Logger(),
+		output:      out,
+		dlq:         dlq,
+		maxAttempts: maxAttemptsStr,
+		attemptKey:  "retry_attempt",
+		errorsKey:   "retry_errors",
+		backoff:     boff,
+	}
+}
+
+func TestRetryDLQOutputPartialFailures(t *testing.T) {
+	attempts := map[string]int{}
+	var delivered []string
+	out := &fakeWriter{writeFn: func(b service.MessageBatch) error {
+		var bErr *service.BatchError
+		for i, msg := range b {
+			content, _ := msg.AsBytes()
+			attempts[string(content)]++
+
+			// Message "b" fails twice and message "c" always fails.
+			if (string(content) == "b" && attempts["b"] <= 2) || string(content) == "c" {
+				if bErr == nil {
+					bErr = service.NewBatchError(b, errors.New("batch failed"))
+				}
+				bErr.Failed(i, fmt.Errorf("attempt %v of %s failed", attempts[string(content)], content))
+				continue
+			}
+			attempt, _ := msg.MetaGetMut("retry_attempt")
+			delivered = append(delivered, fmt.Sprintf("%s:%v", content, attempt))
+		}
+		if bErr != nil {
+			return bErr
+		}
+		return nil
+	}}
+	dlq := &fakeWriter{}
+
+	o := newTestOutput(t, "3", out, dlq)
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte("a")),
+		service.NewMessage([]byte("b")),
+		service.NewMessage([]byte("c")),
+	}))
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 3, "c": 3}, attempts)
+
+	assert.Equal(t, []string{"a:1", "b:3"}, delivered)
+
+	require.Len(t, dlq.written, 1)
+	require.Len(t, dlq.written[0], 1)
+	msg := dlq.written[0][0]
+	content, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "c", string(content))
+
+	attempt, _ := msg.MetaGetMut("retry_attempt")
+	assert.Equal(t, int64(3), attempt)
+	errs, _ := msg.MetaGetMut("retry_errors")
+	assert.Equal(t, []any{
+		"attempt 1 of c failed",
+		"attempt 2 of c failed",
+		"attempt 3 of c failed",
+	}, errs)
+}
+
+func TestRetryDLQOutputPerMessageAttempts(t *testing.T) {
+	out := &fakeWriter{writeFn: func(service.MessageBatch) error {
+		return errors.New("nope")
+	}}
+	dlq := &fakeWriter{}
+
+	o := newTestOutput(t, `${! meta("max").or(1) }`, out, dlq)
+
+	first := service.NewMessage([]byte("first"))
+	first.MetaSetMut("max", 2)
+
+	// Messages that were already attempted continue their count and errors.
+	second := service.NewMessage([]byte("second"))
+	second.MetaSetMut("max", 4)
+	second.MetaSetMut("retry_attempt", 2)
+	second.MetaSetMut("retry_errors", []any{"earlier"})
+
+	third := service.NewMessage([]byte("third"))
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{first, second, third}))
+	assert.Empty(t, out.written)
+
+	results := map[string][]any{}
+	for _, b := range dlq.written {
+		for _, msg := range b {
+			content, _ := msg.AsBytes()
+			attempt, _ := msg.MetaGetMut("retry_attempt")
+			errs, _ := msg.MetaGetMut("retry_errors")
+			results[string(content)] = []any{attempt, errs}
+		}
+	}
+	assert.Equal(t, map[string][]any{
+		"first":  {int64(2), []any{"nope", "nope"}},
+		"second": {int64(4), []any{"earlier", "nope", "nope"}},
+		"third":  {int64(1), []any{"nope"}},
+	}, results)
+}
+
+func TestRetryDLQOutputDLQFailure(t *testing.T) {
+	out := &fakeWriter{writeFn: func(b service.MessageBatch) error {
+		// Messages "b" and "d" always fail.
+		var bErr *service.BatchError
+		for i, msg := range b {
+			if content, _ := msg.AsBytes(); string(content) == "b" || string(content) == "d" {
+				if bErr == nil {
+					bErr = service.NewBatchError(b, errors.New("nope"))
+				}
+				bErr.Failed(i, errors.New("nope"))
+			}
+		}
+		if bErr != nil {
+			return bErr
+		}
+		return nil
+	}}
+	dlq := &fakeWriter{writeFn: func(service.MessageBatch) error {
+		return errors.New("dlq unavailable")
+	}}
+
+	o := newTestOutput(t, "2", out, dlq)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("a")),
+		service.NewMessage([]byte("b")),
+		service.NewMessage([]byte("c")),
+		service.NewMessage([]byte("d")),
+	}
+	indexer := batch.Index()
+
+	err := o.WriteBatch(t.Context(), batch)
+	require.ErrorContains(t, err, "dlq unavailable")
+
+	var bErr *service.BatchError
+	require.ErrorAs(t, err, &bErr)
+	var failed []int
+	bErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{1, 3}, failed)
+}
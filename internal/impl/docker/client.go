@@ -0,0 +1,85 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dcFieldHost    = "host"
+	dcFieldFilters = "filters"
+)
+
+func clientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(dcFieldHost).
+			Description("The address of the Docker daemon. When empty the `DOCKER_HOST` environment variable is used, and otherwise the default socket of the platform.").
+			Examples("unix:///var/run/docker.sock", "tcp://10.0.0.5:2375").
+			Default(""),
+	}
+}
+
+// dockerAPI is the subset of the Docker client used by the inputs of this
+// package.
+type dockerAPI interface {
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+	ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
+	ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error)
+	ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
+	Close() error
+}
+
+func newClientFromParsed(conf *service.ParsedConfig) (func() (dockerAPI, error), error) {
+	host, err := conf.FieldString(dcFieldHost)
+	if err != nil {
+		return nil, err
+	}
+	return func() (dockerAPI, error) {
+		opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+		if host != "" {
+			opts = append(opts, client.WithHost(host))
+		}
+		return client.NewClientWithOpts(opts...)
+	}, nil
+}
+
+// filtersFromParsed parses a map of filter names to the values that are
+// matched by them.
+func filtersFromParsed(conf *service.ParsedConfig) (filters.Args, error) {
+	args := filters.NewArgs()
+	m, err := conf.FieldAnyMap(dcFieldFilters)
+	if err != nil {
+		return args, err
+	}
+	for k, v := range m {
+		values, err := v.FieldStringList()
+		if err != nil {
+			return args, err
+		}
+		for _, value := range values {
+			args.Add(k, value)
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	deFieldSince = "since"
+)
+
+func eventsInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Consumes the events stream of a Docker daemon.").
+		Description(`
+Each event reported by the daemon, such as containers being created, started and stopped, or images being pulled, is consumed as a message containing the event as JSON.
+
+When the connection to the daemon is lost the input reconnects and resumes from the time of the latest event consumed, and so events are not missed while the daemon keeps them.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- docker_event_type
+- docker_event_action
+- docker_event_scope
+- docker_actor_id
+- docker_actor_attributes
+`+"```"+`
+
+The actor attributes are an object containing the attributes of the container, image or other object of the event, which for containers includes their name, image and labels.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewAnyMapField(dcFieldFilters).
+				Description("An optional map of https://docs.docker.com/reference/cli/docker/system/events/#filter[filters^] to the values that events must match, where events must match at least one value of every filter.").
+				Example(map[string]any{
+					"type":  []any{"container"},
+					"event": []any{"start", "die", "oom"},
+				}).
+				Example(map[string]any{
+					"label": []any{"com.example.team=payments"},
+				}).
+				Default(map[string]any{}),
+			service.NewStringField(deFieldSince).
+				Description("An optional time to consume past events from, as a unix timestamp, an RFC 3339 date or a duration relative to now. When empty only new events are consumed.").
+				Examples("1h", "2024-01-02T15:04:05Z").
+				Default(""),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Container lifecycle", "Consume the lifecycle events of containers into Kafka, keyed by the container name.", `
+input:
+  docker_events:
+    filters:
+      type: [ container ]
+      event: [ create, start, stop, die, oom ]
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: docker_events
+    key: ${! @docker_actor_attributes.name }
+`)
+}
+
+func init() {
+	service.MustRegisterInput("docker_events", eventsInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newEventsInputFromParsed(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type eventsInput struct {
+	newFn   func() (dockerAPI, error)
+	filters filters.Args
+	since   string
+
+	log *service.Logger
+
+	mut      sync.Mutex
+	client   dockerAPI
+	cancel   context.CancelFunc
+	msgChan  <-chan events.Message
+	errChan  <-chan error
+	lastTime int64
+}
+
+func newEventsInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*eventsInput, error) {
+	i := &eventsInput{log: mgr.Logger()}
+
+	var err error
+	if i.newFn, err = newClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.filters, err = filtersFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.since, err = conf.FieldString(deFieldSince); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (i *eventsInput) Connect(context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil {
+		return nil
+	}
+
+	client, err := i.newFn()
+	if err != nil {
+		return err
+	}
+
+	// Resume from the latest event consumed when reconnecting. Events at the
+	// same time as the latest event are delivered again.
+	since := i.since
+	if i.lastTime > 0 {
+		since = formatUnixNano(i.lastTime)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.msgChan, i.errChan = client.Events(ctx, events.ListOptions{
+		Since:   since,
+		Filters: i.filters,
+	})
+	i.client, i.cancel = client, cancel
+	return nil
+}
+
+// formatUnixNano formats a unix timestamp in nanoseconds in the seconds with
+// a fraction format accepted by the daemon.
+func formatUnixNano(t int64) string {
+	return fmt.Sprintf("%d.%09d", t/1e9, t%1e9)
+}
+
+func (i *eventsInput) disconnect() {
+	if i.client == nil {
+		return
+	}
+	i.cancel()
+	_ = i.client.Close()
+	i.client, i.cancel = nil, nil
+}
+
+func (i *eventsInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	i.mut.Lock()
+	msgChan, errChan := i.msgChan, i.errChan
+	connected := i.client != nil
+	i.mut.Unlock()
+	if !connected {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case event := <-msgChan:
+		msg, err := newEventMessage(event)
+		if err != nil {
+			return nil, nil, err
+		}
+		i.mut.Lock()
+		if event.TimeNano > i.lastTime {
+			i.lastTime = event.TimeNano
+		}
+		i.mut.Unlock()
+		return msg, func(context.Context, error) error { return nil }, nil
+	case err := <-errChan:
+		i.log.Errorf("Events stream failed: %v", err)
+		i.mut.Lock()
+		i.disconnect()
+		i.mut.Unlock()
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func newEventMessage(event events.Message) (*service.Message, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	msg := service.NewMessage(b)
+	msg.MetaSetMut("docker_event_type", string(event.Type))
+	msg.MetaSetMut("docker_event_action", string(event.Action))
+	msg.MetaSetMut("docker_event_scope", event.Scope)
+	msg.MetaSetMut("docker_actor_id", event.Actor.ID)
+
+	attributes := make(map[string]any, len(event.Actor.Attributes))
+	for k, v := range event.Actor.Attributes {
+		attributes[k] = v
+	}
+	msg.MetaSetMut("docker_actor_attributes", attributes)
+	return msg, nil
+}
+
+func (i *eventsInput) Close(context.Context) error {
+	i.mut.Lock()
+	i.disconnect()
+	i.mut.Unlock()
+	return nil
+}
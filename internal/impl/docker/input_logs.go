@@ -0,0 +1,481 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dlFieldContainers = "containers"
+	dlFieldStdout     = "stdout"
+	dlFieldStderr     = "stderr"
+	dlFieldSince      = "since"
+)
+
+func logsInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Follows the logs of Docker containers, consuming each line as a message.").
+		Description(`
+Follows the logs of the running containers that match the `+"`containers` and `filters`"+` fields, as well as the containers started afterwards, which makes it possible to ship the logs of a host without running a logging agent. Only containers with a logging driver that supports reading logs, such as the default `+"`json-file`"+` driver, can be followed.
+
+The logs of containers without a TTY are multiplexed by the daemon, and each line is consumed with the stream it was written to. Lines written by containers with a TTY are all consumed as `+"`stdout`"+`.
+
+Containers that are running when the input connects are followed from the `+"`since`"+` time, and containers started afterwards are followed from when they were started.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- docker_container_id
+- docker_container_name
+- docker_container_image
+- docker_container_labels
+- docker_log_stream
+- docker_log_timestamp
+`+"```"+`
+
+The container labels are an object of the labels of the container, the log stream is either `+"`stdout` or `stderr`"+`, and the timestamp is the RFC 3339 time at which the line was written.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewStringListField(dlFieldContainers).
+				Description("An optional list of names or IDs of the containers to follow. When empty all containers that match the `filters` are followed.").
+				Example([]string{"checkout", "payments"}).
+				Default([]string{}),
+			service.NewAnyMapField(dcFieldFilters).
+				Description("An optional map of https://docs.docker.com/reference/cli/docker/container/ls/#filter[filters^] to the values that containers must match, where containers must match at least one value of every filter.").
+				Example(map[string]any{
+					"label": []any{"logging=enabled"},
+				}).
+				Example(map[string]any{
+					"ancestor": []any{"nginx"},
+				}).
+				Default(map[string]any{}),
+			service.NewBoolField(dlFieldStdout).
+				Description("Whether to consume the lines written to stdout.").
+				Default(true),
+			service.NewBoolField(dlFieldStderr).
+				Description("Whether to consume the lines written to stderr.").
+				Default(true),
+			service.NewStringField(dlFieldSince).
+				Description("An optional time to consume the logs of the containers running when the input connects from, as a unix timestamp, an RFC 3339 date or a duration relative to now. When empty only new lines are consumed.").
+				Examples("10m", "2024-01-02T15:04:05Z").
+				Default(""),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Ship labelled containers", "Ship the logs of containers with a `logging=enabled` label to Kafka, keyed by the container name.", `
+input:
+  docker_logs:
+    filters:
+      label: [ logging=enabled ]
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: container_logs
+    key: ${! @docker_container_name }
+`)
+}
+
+func init() {
+	service.MustRegisterInput("docker_logs", logsInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newLogsInputFromParsed(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type logsInput struct {
+	newFn      func() (dockerAPI, error)
+	containers []string
+	filters    filters.Args
+	stdout     bool
+	stderr     bool
+	since      string
+
+	log *service.Logger
+
+	mut       sync.Mutex
+	client    dockerAPI
+	shutSig   *shutdown.Signaller
+	msgChan   chan *service.Message
+	errChan   chan error
+	following map[string]struct{}
+	resumeAt  time.Time
+}
+
+func newLogsInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*logsInput, error) {
+	i := &logsInput{log: mgr.Logger()}
+
+	var err error
+	if i.newFn, err = newClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.containers, err = conf.FieldStringList(dlFieldContainers); err != nil {
+		return nil, err
+	}
+	if i.filters, err = filtersFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.stdout, err = conf.FieldBool(dlFieldStdout); err != nil {
+		return nil, err
+	}
+	if i.stderr, err = conf.FieldBool(dlFieldStderr); err != nil {
+		return nil, err
+	}
+	if !i.stdout && !i.stderr {
+		return nil, errors.New("at least one of stdout and stderr must be enabled")
+	}
+	if i.since, err = conf.FieldString(dlFieldSince); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (i *logsInput) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil {
+		return nil
+	}
+
+	client, err := i.newFn()
+	if err != nil {
+		return err
+	}
+
+	// The logs of containers that are running when reconnecting are followed
+	// from when the previous connection was lost.
+	since := i.since
+	if !i.resumeAt.IsZero() {
+		since = i.resumeAt.Format(time.RFC3339Nano)
+	} else if since == "" {
+		since = time.Now().Format(time.RFC3339Nano)
+	}
+
+	// Subscribe to starting containers before listing the running ones so
+	// that containers starting in between aren't missed.
+	shutSig := shutdown.NewSignaller()
+	watchCtx, watchDone := shutSig.SoftStopCtx(context.Background())
+	eventChan, eventErrChan := client.Events(watchCtx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("event", string(events.ActionStart)),
+		),
+	})
+
+	running, err := client.ContainerList(ctx, container.ListOptions{Filters: i.filters})
+	if err != nil {
+		watchDone()
+		shutSig.TriggerHardStop()
+		_ = client.Close()
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	i.client = client
+	i.shutSig = shutSig
+	i.msgChan = make(chan *service.Message)
+	i.errChan = make(chan error, 1)
+	i.following = map[string]struct{}{}
+
+	for _, c := range running {
+		if i.matchesContainers(c.ID, c.Names) {
+			i.follow(client, shutSig, c.ID, since)
+		}
+	}
+
+	go func() {
+		defer watchDone()
+		for {
+			select {
+			case event := <-eventChan:
+				i.containerStarted(watchCtx, client, shutSig, event)
+			case err := <-eventErrChan:
+				if watchCtx.Err() == nil {
+					select {
+					case i.errChan <- err:
+					default:
+					}
+				}
+				return
+			case <-watchCtx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// matchesContainers returns whether a container is one of the configured
+// containers, or true when none are configured.
+func (i *logsInput) matchesContainers(id string, names []string) bool {
+	if len(i.containers) == 0 {
+		return true
+	}
+	for _, c := range i.containers {
+		if len(c) >= 12 && strings.HasPrefix(id, c) {
+			return true
+		}
+		for _, name := range names {
+			if strings.TrimPrefix(name, "/") == strings.TrimPrefix(c, "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (i *logsInput) containerStarted(ctx context.Context, client dockerAPI, shutSig *shutdown.Signaller, event events.Message) {
+	// Check that the container matches the filters by listing it.
+	args := i.filters.Clone()
+	args.Add("id", event.Actor.ID)
+	matched, err := client.ContainerList(ctx, container.ListOptions{Filters: args})
+	if err != nil {
+		if ctx.Err() == nil {
+			i.log.Errorf("Failed to list started container %v: %v", event.Actor.ID, err)
+		}
+		return
+	}
+	for _, c := range matched {
+		if c.ID == event.Actor.ID && i.matchesContainers(c.ID, c.Names) {
+			i.mut.Lock()
+			if i.shutSig == shutSig {
+				i.follow(client, shutSig, c.ID, time.Unix(0, event.TimeNano).Format(time.RFC3339Nano))
+			}
+			i.mut.Unlock()
+		}
+	}
+}
+
+// follow starts following the logs of a container unless they're already
+// being followed. Must be called with the mutex held.
+func (i *logsInput) follow(client dockerAPI, shutSig *shutdown.Signaller, id, since string) {
+	if _, exists := i.following[id]; exists {
+		return
+	}
+	following := i.following
+	following[id] = struct{}{}
+	msgChan := i.msgChan
+
+	go func() {
+		defer func() {
+			i.mut.Lock()
+			delete(following, id)
+			i.mut.Unlock()
+		}()
+
+		ctx, done := shutSig.SoftStopCtx(context.Background())
+		defer done()
+
+		if err := i.followLogs(ctx, client, id, since, msgChan); err != nil && ctx.Err() == nil {
+			i.log.Errorf("Failed to follow logs of container %v: %v", id, err)
+		}
+	}()
+}
+
+func (i *logsInput) followLogs(ctx context.Context, client dockerAPI, id, since string, msgChan chan<- *service.Message) error {
+	info, err := client.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimPrefix(info.Name, "/")
+	var image string
+	labels := map[string]any{}
+	tty := false
+	if info.Config != nil {
+		image = info.Config.Image
+		tty = info.Config.Tty
+		for k, v := range info.Config.Labels {
+			labels[k] = v
+		}
+	}
+
+	r, err := client.ContainerLogs(ctx, id, container.LogsOptions{
+		ShowStdout: i.stdout,
+		ShowStderr: i.stderr,
+		Since:      since,
+		Timestamps: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	i.log.Debugf("Following logs of container %v", name)
+	return readLogLines(r, !tty, func(stream string, line []byte) error {
+		msg := service.NewMessage(nil)
+		if ts, rest, found := bytes.Cut(line, []byte(" ")); found {
+			if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+				msg.MetaSetMut("docker_log_timestamp", t.Format(time.RFC3339Nano))
+				line = rest
+			}
+		}
+		msg.SetBytes(line)
+		msg.MetaSetMut("docker_container_id", id)
+		msg.MetaSetMut("docker_container_name", name)
+		msg.MetaSetMut("docker_container_image", image)
+		msg.MetaSetMut("docker_container_labels", labels)
+		msg.MetaSetMut("docker_log_stream", stream)
+
+		select {
+		case msgChan <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	})
+}
+
+// readLogLines reads the lines of a log stream until it ends. When
+// multiplexed the stream consists of frames with a header that identifies
+// whether the payload was written to stdout or stderr, otherwise all lines
+// were written to stdout.
+func readLogLines(r io.Reader, multiplexed bool, fn func(stream string, line []byte) error) error {
+	if !multiplexed {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(line) > 0 {
+				if fErr := fn("stdout", bytes.TrimSuffix(line, []byte("\n"))); fErr != nil {
+					return fErr
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var pending [3][]byte
+	streams := [3]string{"stdin", "stdout", "stderr"}
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		stream := int(header[0])
+		if stream == 3 {
+			return fmt.Errorf("daemon error: %s", payload)
+		}
+		if stream > 2 {
+			return fmt.Errorf("unrecognised stream %v", stream)
+		}
+
+		pending[stream] = append(pending[stream], payload...)
+		for {
+			idx := bytes.IndexByte(pending[stream], '\n')
+			if idx < 0 {
+				break
+			}
+			line := pending[stream][:idx]
+			pending[stream] = pending[stream][idx+1:]
+			if err := fn(streams[stream], line); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Flush lines that weren't terminated before the stream ended.
+	for stream, line := range pending {
+		if len(line) > 0 {
+			if err := fn(streams[stream], line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (i *logsInput) disconnect() {
+	if i.client == nil {
+		return
+	}
+	i.shutSig.TriggerHardStop()
+	_ = i.client.Close()
+	i.client, i.shutSig = nil, nil
+	i.resumeAt = time.Now()
+}
+
+func (i *logsInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	i.mut.Lock()
+	msgChan, errChan := i.msgChan, i.errChan
+	connected := i.client != nil
+	i.mut.Unlock()
+	if !connected {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case msg := <-msgChan:
+		return msg, func(context.Context, error) error { return nil }, nil
+	case err := <-errChan:
+		i.log.Errorf("Events stream failed: %v", err)
+		i.mut.Lock()
+		i.disconnect()
+		i.mut.Unlock()
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (i *logsInput) Close(context.Context) error {
+	i.mut.Lock()
+	i.disconnect()
+	i.mut.Unlock()
+	return nil
+}
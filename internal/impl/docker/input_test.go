@@ -0,0 +1,295 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeDocker struct {
+	mut        sync.Mutex
+	eventOpts  []events.ListOptions
+	logOpts    map[string]container.LogsOptions
+	containers map[string]container.InspectResponse
+	logs       map[string][]byte
+
+	eventChan chan events.Message
+	errChan   chan error
+}
+
+func newFakeDocker() *fakeDocker {
+	return &fakeDocker{
+		logOpts:    map[string]container.LogsOptions{},
+		containers: map[string]container.InspectResponse{},
+		logs:       map[string][]byte{},
+		eventChan:  make(chan events.Message),
+		errChan:    make(chan error, 1),
+	}
+}
+
+func (f *fakeDocker) addContainer(id, name string, tty bool, labels map[string]string, logs []byte) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.containers[id] = container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{ID: id, Name: "/" + name},
+		Config:            &container.Config{Image: "img-" + name, Tty: tty, Labels: labels},
+	}
+	f.logs[id] = logs
+}
+
+func (f *fakeDocker) Events(_ context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.eventOpts = append(f.eventOpts, options)
+	return f.eventChan, f.errChan
+}
+
+func (f *fakeDocker) ContainerList(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	var summaries []container.Summary
+	for id, c := range f.containers {
+		if options.Filters.Contains("id") && !options.Filters.ExactMatch("id", id) {
+			continue
+		}
+		summaries = append(summaries, container.Summary{ID: id, Names: []string{c.Name}})
+	}
+	return summaries, nil
+}
+
+func (f *fakeDocker) ContainerInspect(_ context.Context, id string) (container.InspectResponse, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	c, exists := f.containers[id]
+	if !exists {
+		return c, errors.New("not found")
+	}
+	return c, nil
+}
+
+func (f *fakeDocker) ContainerLogs(_ context.Context, id string, options container.LogsOptions) (io.ReadCloser, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.logOpts[id] = options
+	return io.NopCloser(bytes.NewReader(f.logs[id])), nil
+}
+
+func (*fakeDocker) Close() error {
+	return nil
+}
+
+func frame(stream byte, payload string) []byte {
+	b := make([]byte, 8, 8+len(payload))
+	b[0] = stream
+	binary.BigEndian.PutUint32(b[4:], uint32(len(payload)))
+	return append(b, payload...)
+}
+
+type logLine struct {
+	stream string
+	line   string
+}
+
+func TestReadLogLines(t *testing.T) {
+	readAll := func(r io.Reader, multiplexed bool) ([]logLine, error) {
+		var lines []logLine
+		err := readLogLines(r, multiplexed, func(stream string, line []byte) error {
+			lines = append(lines, logLine{stream, string(line)})
+			return nil
+		})
+		return lines, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(frame(1, "first\nsec"))
+	buf.Write(frame(2, "oops\n"))
+	buf.Write(frame(1, "ond\nthird"))
+	lines, err := readAll(&buf, true)
+	require.NoError(t, err)
+	assert.Equal(t, []logLine{
+		{"stdout", "first"},
+		{"stderr", "oops"},
+		{"stdout", "second"},
+		{"stdout", "third"},
+	}, lines)
+
+	lines, err = readAll(bytes.NewReader(frame(3, "daemon broke")), true)
+	require.ErrorContains(t, err, "daemon broke")
+	assert.Empty(t, lines)
+
+	lines, err = readAll(bytes.NewReader([]byte("foo\nbar")), false)
+	require.NoError(t, err)
+	assert.Equal(t, []logLine{{"stdout", "foo"}, {"stdout", "bar"}}, lines)
+}
+
+func TestEventsInput(t *testing.T) {
+	pConf, err := eventsInputSpec().ParseYAML(`
+filters:
+  type: [ container ]
+  event: [ start, die ]
+`, nil)
+	require.NoError(t, err)
+
+	i, err := newEventsInputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	fake := newFakeDocker()
+	i.newFn = func() (dockerAPI, error) { return fake, nil }
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*10)
+	defer cancel()
+
+	require.NoError(t, i.Connect(ctx))
+	go func() {
+		fake.eventChan <- events.Message{
+			Type:     events.ContainerEventType,
+			Action:   events.ActionStart,
+			Scope:    "local",
+			Actor:    events.Actor{ID: "abc", Attributes: map[string]string{"name": "web"}},
+			TimeNano: 1700000000123456789,
+		}
+	}()
+
+	msg, ackFn, err := i.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, nil))
+
+	for k, v := range map[string]any{
+		"docker_event_type":       "container",
+		"docker_event_action":     "start",
+		"docker_event_scope":      "local",
+		"docker_actor_id":         "abc",
+		"docker_actor_attributes": map[string]any{"name": "web"},
+	} {
+		actual, _ := msg.MetaGetMut(k)
+		assert.Equal(t, v, actual, k)
+	}
+
+	assert.Equal(t, []string{"container"}, fake.eventOpts[0].Filters.Get("type"))
+	assert.ElementsMatch(t, []string{"start", "die"}, fake.eventOpts[0].Filters.Get("event"))
+	assert.Empty(t, fake.eventOpts[0].Since)
+
+	// Reconnecting resumes from the latest event.
+	fake.errChan <- errors.New("connection lost")
+	_, _, err = i.Read(ctx)
+	require.ErrorIs(t, err, service.ErrNotConnected)
+
+	require.NoError(t, i.Connect(ctx))
+	assert.Equal(t, "1700000000.123456789", fake.eventOpts[1].Since)
+	require.NoError(t, i.Close(ctx))
+}
+
+func TestLogsInput(t *testing.T) {
+	pConf, err := logsInputSpec().ParseYAML(`
+containers: [ web, worker ]
+since: 10m
+`, nil)
+	require.NoError(t, err)
+
+	i, err := newLogsInputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	logs.Write(frame(1, "2024-01-02T15:04:05.000000001Z hello world\n"))
+	logs.Write(frame(2, "2024-01-02T15:04:06Z failed\n"))
+
+	fake := newFakeDocker()
+	fake.addContainer("aaaaaaaaaaaaaaaa", "web", false, map[string]string{"team": "payments"}, logs.Bytes())
+	fake.addContainer("bbbbbbbbbbbbbbbb", "db", true, nil, []byte("ignored\n"))
+	i.newFn = func() (dockerAPI, error) { return fake, nil }
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*10)
+	defer cancel()
+
+	require.NoError(t, i.Connect(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, i.Close(context.Background()))
+	})
+
+	readMsg := func() *service.Message {
+		t.Helper()
+		msg, ackFn, err := i.Read(ctx)
+		require.NoError(t, err)
+		require.NoError(t, ackFn(ctx, nil))
+		return msg
+	}
+	metaOf := func(msg *service.Message, k string) any {
+		v, _ := msg.MetaGetMut(k)
+		return v
+	}
+
+	msg := readMsg()
+	mBytes, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(mBytes))
+	assert.Equal(t, "aaaaaaaaaaaaaaaa", metaOf(msg, "docker_container_id"))
+	assert.Equal(t, "web", metaOf(msg, "docker_container_name"))
+	assert.Equal(t, "img-web", metaOf(msg, "docker_container_image"))
+	assert.Equal(t, map[string]any{"team": "payments"}, metaOf(msg, "docker_container_labels"))
+	assert.Equal(t, "stdout", metaOf(msg, "docker_log_stream"))
+	assert.Equal(t, "2024-01-02T15:04:05.000000001Z", metaOf(msg, "docker_log_timestamp"))
+
+	msg = readMsg()
+	mBytes, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "failed", string(mBytes))
+	assert.Equal(t, "stderr", metaOf(msg, "docker_log_stream"))
+
+	fake.mut.Lock()
+	opts := fake.logOpts["aaaaaaaaaaaaaaaa"]
+	_, followedDB := fake.logOpts["bbbbbbbbbbbbbbbb"]
+	fake.mut.Unlock()
+	assert.Equal(t, "10m", opts.Since)
+	assert.True(t, opts.Follow)
+	assert.True(t, opts.Timestamps)
+	assert.False(t, followedDB)
+
+	// Containers started afterwards are followed from when they started.
+	fake.addContainer("cccccccccccccccc", "worker", true, nil, []byte("2024-01-02T15:04:07Z working\n"))
+	fake.eventChan <- events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionStart,
+		Actor:    events.Actor{ID: "cccccccccccccccc"},
+		TimeNano: time.Date(2024, 1, 2, 15, 4, 7, 0, time.UTC).UnixNano(),
+	}
+
+	msg = readMsg()
+	mBytes, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "working", string(mBytes))
+	assert.Equal(t, "worker", metaOf(msg, "docker_container_name"))
+	assert.Equal(t, "stdout", metaOf(msg, "docker_log_stream"))
+
+	fake.mut.Lock()
+	opts = fake.logOpts["cccccccccccccccc"]
+	fake.mut.Unlock()
+	since, err := time.Parse(time.RFC3339Nano, opts.Since)
+	require.NoError(t, err)
+	assert.True(t, since.Equal(time.Date(2024, 1, 2, 15, 4, 7, 0, time.UTC)))
+}
@@ -0,0 +1,424 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build x_benthos_extra
+
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/marcboeker/go-duckdb/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ddoFieldPath            = "path"
+	ddoFieldMotherDuckToken = "motherduck_token"
+	ddoFieldTable           = "table"
+	ddoFieldCreateTable     = "create_table"
+	ddoFieldInitStatement   = "init_statement"
+	ddoFieldMaxInFlight     = "max_in_flight"
+	ddoFieldBatching        = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Appends messages as rows to a table of a local DuckDB database file or a MotherDuck database.").
+		Description(`
+Each message must be a JSON object, where the fields of the object are inserted into the columns of the table with the same name. Fields that do not have a corresponding column are ignored, and columns that do not have a corresponding field are set to null. Each batch of messages is inserted within a single transaction.
+
+This output requires Redpanda Connect to be built with CGO enabled and the `+"`x_benthos_extra`"+` build tag, as DuckDB is an embedded database.
+
+== Table creation
+
+When `+"`"+ddoFieldCreateTable+"`"+` is enabled and the table does not exist it is created from the fields of the first batch written to it. The types of columns are inferred from the values of the fields, where strings become `+"`VARCHAR`"+`, whole numbers `+"`BIGINT`"+`, other numbers `+"`DOUBLE`"+`, booleans `+"`BOOLEAN`"+`, timestamps `+"`TIMESTAMPTZ`"+`, bytes `+"`BLOB`"+`, and objects and arrays `+"`JSON`"+`. Fields that are null in every message of the batch become `+"`VARCHAR`"+` columns.
+
+Columns are not added to existing tables, so fields that appear in later batches are ignored unless the table is altered, for example with an `+"`"+ddoFieldInitStatement+"`"+`.
+
+== MotherDuck
+
+Databases hosted by https://motherduck.com/[MotherDuck^] can be written to by setting the `+"`"+ddoFieldPath+"`"+` to `+"`md:<database>`"+` and providing a `+"`"+ddoFieldMotherDuckToken+"`"+`.
+`).
+		Fields(
+			service.NewStringField(ddoFieldPath).
+				Description("The path of the DuckDB database file to write to, which is created if it does not exist, or `md:<database>` for a MotherDuck database.").
+				Example("./analytics.duckdb").
+				Example("md:my_db"),
+			service.NewStringField(ddoFieldMotherDuckToken).
+				Description("A MotherDuck access token used when the `path` refers to a MotherDuck database.").
+				Secret().
+				Optional(),
+			service.NewStringField(ddoFieldTable).
+				Description("The table to append rows to.").
+				Example("events"),
+			service.NewBoolField(ddoFieldCreateTable).
+				Description("Whether to create the table from the fields of the first batch when it does not exist.").
+				Default(true),
+			service.NewStringField(ddoFieldInitStatement).
+				Description("An optional SQL statement to execute when the database is opened, before any rows are inserted.").
+				Example(`CREATE TABLE IF NOT EXISTS events (id BIGINT, name VARCHAR, ts TIMESTAMPTZ);`).
+				Optional().
+				Advanced(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField(ddoFieldBatching),
+		).
+		Example("Lightweight analytics sink", "Append the messages of a Kafka topic to a local DuckDB file in batches, creating the table from the first batch.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ clicks ]
+    consumer_group: duckdb
+
+output:
+  duckdb:
+    path: ./clicks.duckdb
+    table: clicks
+    batching:
+      count: 10000
+      period: 5s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("duckdb", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ddoFieldBatching); err != nil {
+				return
+			}
+			out, err = newDuckDBOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+type duckDBOutput struct {
+	log *service.Logger
+
+	dsn           string
+	table         string
+	createTable   bool
+	initStatement string
+
+	dbMut   sync.RWMutex
+	db      *sql.DB
+	columns []tableColumn
+}
+
+// tableColumn is a column of the table and its type.
+type tableColumn struct {
+	name string
+	typ  string
+}
+
+func newDuckDBOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*duckDBOutput, error) {
+	o := &duckDBOutput{log: mgr.Logger()}
+
+	var err error
+	if o.dsn, err = conf.FieldString(ddoFieldPath); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ddoFieldMotherDuckToken) {
+		token, err := conf.FieldString(ddoFieldMotherDuckToken)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			sep := "?"
+			if strings.Contains(o.dsn, "?") {
+				sep = "&"
+			}
+			o.dsn += sep + "motherduck_token=" + url.QueryEscape(token)
+		}
+	}
+	if o.table, err = conf.FieldString(ddoFieldTable); err != nil {
+		return nil, err
+	}
+	if o.table == "" {
+		return nil, errors.New("table must not be empty")
+	}
+	if o.createTable, err = conf.FieldBool(ddoFieldCreateTable); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ddoFieldInitStatement) {
+		if o.initStatement, err = conf.FieldString(ddoFieldInitStatement); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *duckDBOutput) Connect(ctx context.Context) error {
+	o.dbMut.Lock()
+	defer o.dbMut.Unlock()
+
+	if o.db != nil {
+		return nil
+	}
+
+	db, err := sql.Open("duckdb", o.dsn)
+	if err != nil {
+		return err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return err
+	}
+	if o.initStatement != "" {
+		if _, err := db.ExecContext(ctx, o.initStatement); err != nil {
+			_ = db.Close()
+			return fmt.Errorf("failed to execute init statement: %w", err)
+		}
+	}
+	o.db = db
+	return nil
+}
+
+// tableColumns returns the columns of the table, or nil if it doesn't exist.
+func (o *duckDBOutput) tableColumns(ctx context.Context) ([]tableColumn, error) {
+	rows, err := o.db.QueryContext(ctx, `SELECT column_name, data_type FROM information_schema.columns WHERE table_name = ? AND table_schema = current_schema() ORDER BY ordinal_position`, o.table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []tableColumn
+	for rows.Next() {
+		var c tableColumn
+		if err := rows.Scan(&c.name, &c.typ); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// resolveColumns returns the columns of the table, creating it from the rows
+// when it doesn't exist.
+func (o *duckDBOutput) resolveColumns(ctx context.Context, rows []map[string]any) ([]tableColumn, error) {
+	o.dbMut.RLock()
+	columns := o.columns
+	o.dbMut.RUnlock()
+	if columns != nil {
+		return columns, nil
+	}
+
+	o.dbMut.Lock()
+	defer o.dbMut.Unlock()
+	if o.columns != nil {
+		return o.columns, nil
+	}
+
+	columns, err := o.tableColumns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table: %w", err)
+	}
+	if len(columns) == 0 {
+		if !o.createTable {
+			return nil, fmt.Errorf("table %v does not exist", o.table)
+		}
+		stmt, created := createTableStatement(o.table, rows)
+		o.log.Infof("Creating table %v with columns: %v", o.table, strings.Join(created, ", "))
+		if _, err := o.db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to create table: %w", err)
+		}
+		if columns, err = o.tableColumns(ctx); err != nil {
+			return nil, fmt.Errorf("failed to describe table: %w", err)
+		}
+	}
+	o.columns = columns
+	return columns, nil
+}
+
+func (o *duckDBOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	o.dbMut.RLock()
+	db := o.db
+	o.dbMut.RUnlock()
+	if db == nil {
+		return service.ErrNotConnected
+	}
+
+	rows := make([]map[string]any, len(batch))
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return fmt.Errorf("failed to parse message as JSON: %w", err)
+		}
+		row, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected message to be a JSON object, got %T", v)
+		}
+		rows[i] = row
+	}
+
+	columns, err := o.resolveColumns(ctx, rows)
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdentifier(c.name)
+		placeholders[i] = "?"
+		if c.typ == "JSON" {
+			// The driver only binds strings to JSON parameters, and so JSON
+			// values are bound as strings in order to also allow nulls.
+			placeholders[i] = "CAST(? AS VARCHAR)"
+		}
+	}
+	query := "INSERT INTO " + quoteIdentifier(o.table) + " (" + strings.Join(quoted, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	args := make([]any, len(columns))
+	for i, row := range rows {
+		for j, c := range columns {
+			if args[j], err = argValue(row[c.name]); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("message %v: column %v: %w", i, c.name, err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("message %v: %w", i, err)
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (o *duckDBOutput) Close(context.Context) error {
+	o.dbMut.Lock()
+	defer o.dbMut.Unlock()
+
+	if o.db == nil {
+		return nil
+	}
+	err := o.db.Close()
+	o.db = nil
+	o.columns = nil
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// createTableStatement returns a statement creating a table with a column for
+// each field of the rows, typed by the first non-null value of the field.
+func createTableStatement(table string, rows []map[string]any) (string, []string) {
+	types := map[string]string{}
+	for _, row := range rows {
+		for k, v := range row {
+			if t := columnType(v); t != "" && types[k] == "" {
+				types[k] = t
+			} else if _, exists := types[k]; !exists {
+				types[k] = ""
+			}
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for k := range types {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	defs := make([]string, len(names))
+	for i, name := range names {
+		t := types[name]
+		if t == "" {
+			t = "VARCHAR"
+		}
+		defs[i] = quoteIdentifier(name) + " " + t
+	}
+	return "CREATE TABLE IF NOT EXISTS " + quoteIdentifier(table) + " (" + strings.Join(defs, ", ") + ")", defs
+}
+
+// columnType returns the DuckDB type of a column inferred from a value, or an
+// empty string for null values.
+func columnType(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return "VARCHAR"
+	case bool:
+		return "BOOLEAN"
+	case int, int32, int64, uint32:
+		return "BIGINT"
+	case uint64:
+		return "UBIGINT"
+	case float32, float64:
+		return "DOUBLE"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "BIGINT"
+		}
+		return "DOUBLE"
+	case time.Time:
+		return "TIMESTAMPTZ"
+	case []byte:
+		return "BLOB"
+	}
+	return "JSON"
+}
+
+// argValue converts the value of a field into an argument of the insert,
+// where objects and arrays are encoded as JSON.
+func argValue(v any) (any, error) {
+	switch t := v.(type) {
+	case nil, string, bool, int, int32, int64, uint32, uint64, float32, float64, time.Time, []byte:
+		return t, nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build x_benthos_extra
+
+package duckdb
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestOutput(t *testing.T, conf string) *duckDBOutput {
+	t.Helper()
+
+	parsed, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	out, err := newDuckDBOutputFromConfig(parsed, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = out.Close(t.Context())
+	})
+	return out
+}
+
+func TestDuckDBCreateTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.duckdb")
+	out := newTestOutput(t, `
+path: `+path+`
+table: events
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"name":"foo","score":1.5,"tags":["a"],"extra":null}`)),
+		service.NewMessage([]byte(`{"id":2,"name":"bar","active":true}`)),
+	}))
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":3,"unknown":"ignored"}`)),
+	}))
+
+	rows, err := out.db.QueryContext(t.Context(), `SELECT column_name, data_type FROM information_schema.columns WHERE table_name = 'events' ORDER BY ordinal_position`)
+	require.NoError(t, err)
+	columns := map[string]string{}
+	for rows.Next() {
+		var name, typ string
+		require.NoError(t, rows.Scan(&name, &typ))
+		columns[name] = typ
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, map[string]string{
+		"active": "BOOLEAN",
+		"extra":  "VARCHAR",
+		"id":     "BIGINT",
+		"name":   "VARCHAR",
+		"score":  "DOUBLE",
+		"tags":   "JSON",
+	}, columns)
+
+	rows, err = out.db.QueryContext(t.Context(), `SELECT id, name, CAST(tags AS VARCHAR), active FROM events ORDER BY id`)
+	require.NoError(t, err)
+	var results [][]any
+	for rows.Next() {
+		var id int64
+		var name, tags *string
+		var active *bool
+		require.NoError(t, rows.Scan(&id, &name, &tags, &active))
+		results = append(results, []any{id, name, tags, active})
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, results, 3)
+	assert.Equal(t, "foo", *results[0][1].(*string))
+	assert.Equal(t, `["a"]`, *results[0][2].(*string))
+	assert.True(t, *results[1][3].(*bool))
+	assert.Nil(t, results[2][1].(*string))
+}
+
+func TestDuckDBMissingTable(t *testing.T) {
+	out := newTestOutput(t, `
+path: `+filepath.Join(t.TempDir(), "test.duckdb")+`
+table: events
+create_table: false
+`)
+
+	err := out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+	})
+	require.ErrorContains(t, err, "does not exist")
+}
+
+func TestDuckDBInitStatement(t *testing.T) {
+	out := newTestOutput(t, `
+path: `+filepath.Join(t.TempDir(), "test.duckdb")+`
+table: events
+init_statement: CREATE TABLE events (id INTEGER, ts TIMESTAMPTZ)
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"ts":"2025-01-01T10:00:00Z"}`)),
+	}))
+
+	var count int
+	require.NoError(t, out.db.QueryRowContext(t.Context(), `SELECT COUNT(*) FROM events WHERE ts = TIMESTAMPTZ '2025-01-01 10:00:00+00'`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestArgValue(t *testing.T) {
+	for _, test := range []struct {
+		input  any
+		output any
+	}{
+		{input: json.Number("5"), output: int64(5)},
+		{input: json.Number("1.5"), output: 1.5},
+		{input: map[string]any{"a": "b"}, output: `{"a":"b"}`},
+		{input: "foo", output: "foo"},
+		{input: nil, output: nil},
+	} {
+		res, err := argValue(test.input)
+		require.NoError(t, err)
+		assert.Equal(t, test.output, res)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kcFieldURLs         = "urls"
+	kcFieldFlavor       = "flavor"
+	kcFieldTLS          = "tls"
+	kcFieldAuth         = "basic_auth"
+	kcFieldAuthEnabled  = "enabled"
+	kcFieldAuthUsername = "username"
+	kcFieldAuthPassword = "password"
+	kcFieldAPIKey       = "api_key"
+
+	flavorElasticsearch = "elasticsearch"
+	flavorOpenSearch    = "opensearch"
+)
+
+// errTooManyRequests is returned when the cluster rejects a request with a
+// 429 status code, indicating that the request should be retried after
+// backing off.
+var errTooManyRequests = errors.New("cluster responded with 429 too many requests")
+
+func clientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringListField(kcFieldURLs).
+			Description("A list of URLs to connect to. If an item of the list contains commas it will be expanded into multiple URLs.").
+			Example([]string{"http://localhost:9200"}),
+		service.NewStringAnnotatedEnumField(kcFieldFlavor, map[string]string{
+			flavorElasticsearch: "Use the Elasticsearch `dense_vector` field type and top level `knn` search option.",
+			flavorOpenSearch:    "Use the OpenSearch k-NN plugin `knn_vector` field type and `knn` query clause.",
+		}).
+			Description("The flavor of the cluster, which determines the shape of kNN queries.").
+			Default(flavorElasticsearch),
+		service.NewTLSToggledField(kcFieldTLS),
+		service.NewObjectField(kcFieldAuth,
+			service.NewBoolField(kcFieldAuthEnabled).
+				Description("Whether to use basic authentication in requests.").
+				Default(false),
+			service.NewStringField(kcFieldAuthUsername).
+				Description("A username to authenticate as.").
+				Default(""),
+			service.NewStringField(kcFieldAuthPassword).
+				Description("A password to authenticate with.").
+				Default("").Secret(),
+		).Description("Allows you to specify basic authentication.").
+			Advanced().
+			Optional(),
+		service.NewStringField(kcFieldAPIKey).
+			Description("An optional API key to authenticate with, sent using the `ApiKey` authorization scheme. Takes precedence over basic authentication.").
+			Default("").
+			Secret().
+			Advanced(),
+	}
+}
+
+type knnClient struct {
+	urls     []string
+	flavor   string
+	username string
+	password string
+	apiKey   string
+	client   *http.Client
+
+	next atomic.Uint64
+}
+
+func knnClientFromParsed(pConf *service.ParsedConfig) (*knnClient, error) {
+	c := &knnClient{client: &http.Client{}}
+
+	urlStrs, err := pConf.FieldStringList(kcFieldURLs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range urlStrs {
+		for urlStr := range strings.SplitSeq(u, ",") {
+			if urlStr != "" {
+				c.urls = append(c.urls, strings.TrimSuffix(urlStr, "/"))
+			}
+		}
+	}
+	if len(c.urls) == 0 {
+		return nil, errors.New("at least one url must be specified")
+	}
+
+	if c.flavor, err = pConf.FieldString(kcFieldFlavor); err != nil {
+		return nil, err
+	}
+
+	authConf := pConf.Namespace(kcFieldAuth)
+	if enabled, _ := authConf.FieldBool(kcFieldAuthEnabled); enabled {
+		if c.username, err = authConf.FieldString(kcFieldAuthUsername); err != nil {
+			return nil, err
+		}
+		if c.password, err = authConf.FieldString(kcFieldAuthPassword); err != nil {
+			return nil, err
+		}
+	}
+	if c.apiKey, err = pConf.FieldString(kcFieldAPIKey); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := pConf.FieldTLSToggled(kcFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		c.client.Transport = &http.Transport{
+			TLSClientConfig: tlsConf,
+		}
+	}
+	return c, nil
+}
+
+// do performs a request against the next URL in a round robin fashion and
+// returns the body of the response. A 429 response results in
+// errTooManyRequests being returned.
+func (c *knnClient) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	base := c.urls[c.next.Add(1)%uint64(len(c.urls))]
+	req, err := http.NewRequestWithContext(ctx, method, base+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		return nil, errTooManyRequests
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("request failed with status %v: %s", res.StatusCode, resBody)
+	}
+	return resBody, nil
+}
+
+// bulkResponse is the subset of a _bulk response that we care about.
+type bulkResponse struct {
+	Errors bool                          `json:"errors"`
+	Items  []map[string]bulkResponseItem `json:"items"`
+}
+
+type bulkResponseItem struct {
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+func (c *knnClient) bulk(ctx context.Context, body []byte) (*bulkResponse, error) {
+	resBytes, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body)
+	if err != nil {
+		return nil, err
+	}
+	var res bulkResponse
+	if err := json.Unmarshal(resBytes, &res); err != nil {
+		return nil, fmt.Errorf("parsing bulk response: %w", err)
+	}
+	return &res, nil
+}
+
+func (c *knnClient) search(ctx context.Context, index string, query any) ([]byte, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, http.MethodPost, "/"+index+"/_search", "application/json", body)
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	koFieldIndex         = "index"
+	koFieldID            = "id"
+	koFieldVectorField   = "vector_field"
+	koFieldVectorMapping = "vector_mapping"
+	koFieldDimensions    = "dimensions"
+	koFieldBackoff       = "backoff"
+	koFieldBatching      = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	retriesDefaults := backoff.NewExponentialBackOff()
+	retriesDefaults.InitialInterval = 500 * time.Millisecond
+	retriesDefaults.MaxInterval = 10 * time.Second
+	retriesDefaults.MaxElapsedTime = time.Minute
+
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("AI", "Services").
+		Summary("Indexes documents containing dense vectors into an Elasticsearch or OpenSearch kNN index.").
+		Description(`
+Each message is indexed as a document, where the message body (which must be a JSON object) forms the document source and the result of `+"`vector_mapping`"+` is added to the document under the field `+"`vector_field`"+`. The target index must already have a mapping for `+"`vector_field`"+` of type `+"`dense_vector`"+` (Elasticsearch) or `+"`knn_vector`"+` (OpenSearch).
+
+Documents are written using the bulk API. When the cluster responds with a 429 status code, either for the whole request or for individual items within it, the affected documents are retried according to the `+"`backoff`"+` policy before the batch is considered failed.`+service.OutputPerformanceDocs(true, true)).
+		Fields(clientFields()...).
+		Fields(
+			service.NewInterpolatedStringField(koFieldIndex).
+				Description("The index to place documents."),
+			service.NewInterpolatedStringField(koFieldID).
+				Description("The ID for indexed documents. Interpolation should be used in order to create a unique ID for each message. When empty the cluster generates an ID.").
+				Example(`${! @id }`).
+				Default(""),
+			service.NewStringField(koFieldVectorField).
+				Description("The document field the vector is written to.").
+				Default("embedding"),
+			service.NewBloblangField(koFieldVectorMapping).
+				Description("A mapping that extracts the vector from the message, which must result in an array of numbers.").
+				Example(`root = this.embedding`).
+				Example(`root = [1.2, 0.5, 0.76]`),
+			service.NewIntField(koFieldDimensions).
+				Description("When greater than zero, vectors that do not contain exactly this number of dimensions are rejected before being sent.").
+				Default(0).
+				Advanced(),
+			service.NewBackOffField(koFieldBackoff, false, retriesDefaults).
+				Description("Determines how documents rejected with a 429 status code are retried.").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(koFieldBatching),
+		).
+		Example("Indexing embeddings", "Compute embeddings with Ollama and index them alongside the original text.", `
+pipeline:
+  processors:
+    - branch:
+        request_map: root = this.text
+        processors:
+          - ollama_embeddings:
+              model: nomic-embed-text
+        result_map: root.embedding = this
+
+output:
+  elasticsearch_knn:
+    urls: [ http://localhost:9200 ]
+    index: documents
+    id: ${! this.id }
+    vector_field: embedding
+    vector_mapping: root = this.embedding
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("elasticsearch_knn", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(koFieldBatching); err != nil {
+				return
+			}
+			out, err = newKNNOutput(conf, mgr)
+			return
+		})
+}
+
+type knnOutput struct {
+	log    *service.Logger
+	client *knnClient
+
+	index         *service.InterpolatedString
+	id            *service.InterpolatedString
+	vectorField   string
+	vectorMapping *bloblang.Executor
+	dimensions    int
+	backoff       *backoff.ExponentialBackOff
+}
+
+func newKNNOutput(conf *service.ParsedConfig, mgr *service.Resources) (*knnOutput, error) {
+	o := &knnOutput{log: mgr.Logger()}
+
+	var err error
+	if o.client, err = knnClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if o.index, err = conf.FieldInterpolatedString(koFieldIndex); err != nil {
+		return nil, err
+	}
+	if o.id, err = conf.FieldInterpolatedString(koFieldID); err != nil {
+		return nil, err
+	}
+	if o.vectorField, err = conf.FieldString(koFieldVectorField); err != nil {
+		return nil, err
+	}
+	if o.vectorMapping, err = conf.FieldBloblang(koFieldVectorMapping); err != nil {
+		return nil, err
+	}
+	if o.dimensions, err = conf.FieldInt(koFieldDimensions); err != nil {
+		return nil, err
+	}
+	if o.backoff, err = conf.FieldBackOff(koFieldBackoff); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (*knnOutput) Connect(context.Context) error {
+	return nil
+}
+
+type bulkItem struct {
+	action []byte
+	source []byte
+}
+
+func (o *knnOutput) buildItems(batch service.MessageBatch) ([]bulkItem, error) {
+	indexExec := batch.InterpolationExecutor(o.index)
+	idExec := batch.InterpolationExecutor(o.id)
+	vecExec := batch.BloblangExecutor(o.vectorMapping)
+
+	items := make([]bulkItem, len(batch))
+	for i, msg := range batch {
+		index, err := indexExec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("interpolating %s: %w", koFieldIndex, err)
+		}
+		id, err := idExec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("interpolating %s: %w", koFieldID, err)
+		}
+		rawVec, err := vecExec.Query(i)
+		if err != nil {
+			return nil, fmt.Errorf("executing %s: %w", koFieldVectorMapping, err)
+		}
+		vecAny, err := rawVec.AsStructured()
+		if err != nil {
+			return nil, fmt.Errorf("extracting %s result: %w", koFieldVectorMapping, err)
+		}
+		vec, err := asVector(vecAny)
+		if err != nil {
+			return nil, err
+		}
+		if o.dimensions > 0 && len(vec) != o.dimensions {
+			return nil, fmt.Errorf("vector has %v dimensions, expected %v", len(vec), o.dimensions)
+		}
+
+		docAny, err := msg.AsStructured()
+		if err != nil {
+			return nil, fmt.Errorf("parsing message as JSON: %w", err)
+		}
+		doc, ok := docAny.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected message to be a JSON object, got %T", docAny)
+		}
+		source := make(map[string]any, len(doc)+1)
+		for k, v := range doc {
+			source[k] = v
+		}
+		source[o.vectorField] = vec
+
+		meta := map[string]any{"_index": index}
+		if id != "" {
+			meta["_id"] = id
+		}
+		if items[i].action, err = json.Marshal(map[string]any{"index": meta}); err != nil {
+			return nil, err
+		}
+		if items[i].source, err = json.Marshal(source); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+func asVector(v any) ([]float64, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected %s to result in an array, got %T", koFieldVectorMapping, v)
+	}
+	vec := make([]float64, len(arr))
+	for i, e := range arr {
+		f, err := bloblang.ValueAsFloat64(e)
+		if err != nil {
+			return nil, fmt.Errorf("vector element %v: %w", i, err)
+		}
+		vec[i] = f
+	}
+	return vec, nil
+}
+
+func (o *knnOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	items, err := o.buildItems(batch)
+	if err != nil {
+		return err
+	}
+
+	boff := *o.backoff
+	boff.Reset()
+
+	pending := make([]int, len(items))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	var batchErr *service.BatchError
+	for {
+		var body bytes.Buffer
+		for _, i := range pending {
+			body.Write(items[i].action)
+			body.WriteByte('\n')
+			body.Write(items[i].source)
+			body.WriteByte('\n')
+		}
+
+		var retry []int
+		res, err := o.client.bulk(ctx, body.Bytes())
+		switch {
+		case errors.Is(err, errTooManyRequests):
+			retry = pending
+		case err != nil:
+			return fmt.Errorf("sending bulk request: %w", err)
+		default:
+			for j, item := range res.Items {
+				if j >= len(pending) {
+					break
+				}
+				for _, resItem := range item {
+					if resItem.Status == 429 {
+						retry = append(retry, pending[j])
+						continue
+					}
+					if len(resItem.Error) > 0 {
+						itemErr := fmt.Errorf("indexing document: %s", resItem.Error)
+						if batchErr == nil {
+							batchErr = service.NewBatchError(batch, itemErr)
+						}
+						batchErr.Failed(pending[j], itemErr)
+					}
+				}
+			}
+		}
+
+		if len(retry) == 0 {
+			break
+		}
+
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			for _, i := range retry {
+				if batchErr == nil {
+					batchErr = service.NewBatchError(batch, errTooManyRequests)
+				}
+				batchErr.Failed(i, errTooManyRequests)
+			}
+			break
+		}
+		o.log.Debugf("Retrying %v documents rejected with 429 after %v", len(retry), wait)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		pending = retry
+	}
+
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
+}
+
+func (*knnOutput) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, yamlStr string) *knnOutput {
+	t.Helper()
+
+	conf, err := outputSpec().ParseYAML(yamlStr, nil)
+	require.NoError(t, err)
+
+	out, err := newKNNOutput(conf, service.MockResources())
+	require.NoError(t, err)
+	return out
+}
+
+func TestKNNOutputRetriesTooManyRequests(t *testing.T) {
+	var mut sync.Mutex
+	var calls int
+	var received []map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+		calls++
+
+		var lines []map[string]any
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case 2:
+			// Reject the second document only.
+			require.Len(t, lines, 4)
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`))
+			return
+		}
+		received = append(received, lines...)
+		_, _ = w.Write([]byte(`{"errors":false,"items":[{"index":{"status":201}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := testOutput(t, `
+urls: [ `+srv.URL+` ]
+index: foo
+id: ${! this.id }
+vector_mapping: root = this.vec
+dimensions: 2
+backoff:
+  initial_interval: 1ms
+  max_interval: 1ms
+`)
+
+	err := out.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"a","vec":[1,2],"text":"hello"}`)),
+		service.NewMessage([]byte(`{"id":"b","vec":[3,4],"text":"world"}`)),
+	})
+	require.NoError(t, err)
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, 3, calls)
+	require.Len(t, received, 2)
+	assert.Equal(t, map[string]any{"index": map[string]any{"_index": "foo", "_id": "b"}}, received[0])
+	assert.Equal(t, map[string]any{
+		"id":        "b",
+		"vec":       []any{3.0, 4.0},
+		"text":      "world",
+		"embedding": []any{3.0, 4.0},
+	}, received[1])
+}
+
+func TestKNNOutputItemErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}},{"index":{"status":201}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := testOutput(t, `
+urls: [ `+srv.URL+` ]
+index: foo
+vector_mapping: root = this.vec
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"vec":[1,2]}`)),
+		service.NewMessage([]byte(`{"vec":[3,4]}`)),
+	}
+	err := out.WriteBatch(context.Background(), batch)
+	require.Error(t, err)
+
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.IndexedErrors())
+	batchErr.WalkMessages(func(i int, _ *service.Message, err error) bool {
+		if i == 0 {
+			assert.ErrorContains(t, err, "mapper_parsing_exception")
+		} else {
+			assert.NoError(t, err)
+		}
+		return true
+	})
+}
+
+func TestKNNOutputDimensionMismatch(t *testing.T) {
+	out := testOutput(t, `
+urls: [ http://localhost:9200 ]
+index: foo
+vector_mapping: root = this.vec
+dimensions: 3
+`)
+
+	err := out.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"vec":[1,2]}`)),
+	})
+	require.ErrorContains(t, err, "vector has 2 dimensions, expected 3")
+}
+
+func TestBuildSearchQuery(t *testing.T) {
+	params := searchParams{
+		flavor:      flavorElasticsearch,
+		vectorField: "embedding",
+		textFields:  []string{"body"},
+		k:           5,
+		candidates:  50,
+		vectorBoost: 0.5,
+		textBoost:   2,
+	}
+	filter := map[string]any{"term": map[string]any{"lang": "en"}}
+
+	esQuery, err := json.Marshal(buildSearchQuery(params, []float64{0.1, 0.2}, "hello", filter))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "size": 5,
+  "knn": {"field":"embedding","query_vector":[0.1,0.2],"k":5,"num_candidates":50,"boost":0.5,"filter":{"term":{"lang":"en"}}},
+  "query": {"bool":{"must":[{"multi_match":{"query":"hello","fields":["body"],"boost":2}}],"filter":{"term":{"lang":"en"}}}}
+}`, string(esQuery))
+
+	esVectorOnly, err := json.Marshal(buildSearchQuery(params, []float64{0.1, 0.2}, "", nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "size": 5,
+  "knn": {"field":"embedding","query_vector":[0.1,0.2],"k":5,"num_candidates":50,"boost":0.5}
+}`, string(esVectorOnly))
+
+	params.flavor = flavorOpenSearch
+	osQuery, err := json.Marshal(buildSearchQuery(params, []float64{0.1, 0.2}, "hello", filter))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "size": 5,
+  "query": {"bool":{
+    "should":[
+      {"knn":{"embedding":{"vector":[0.1,0.2],"k":5,"boost":0.5}}},
+      {"multi_match":{"query":"hello","fields":["body"],"boost":2}}
+    ],
+    "filter":{"term":{"lang":"en"}}
+  }}
+}`, string(osQuery))
+}
+
+func TestSearchProcessor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/docs/_search", r.URL.Path)
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r.Body)
+		assert.Contains(t, buf.String(), `"query_vector":[1,2]`)
+		_, _ = w.Write([]byte(`{"hits":{"hits":[{"_index":"docs","_id":"x","_score":1.5,"_source":{"body":"hi"}}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	conf, err := processorSpec().ParseYAML(`
+urls: [ `+srv.URL+` ]
+index: docs
+vector_mapping: root = this.vec
+text_mapping: root = this.q
+text_fields: [ body ]
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newSearchProcessor(conf)
+	require.NoError(t, err)
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte(`{"vec":[1,2],"q":"hi"}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	res, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{
+		"id":     "x",
+		"index":  "docs",
+		"score":  1.5,
+		"source": map[string]any{"body": "hi"},
+	}}, res)
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kpFieldIndex         = "index"
+	kpFieldVectorField   = "vector_field"
+	kpFieldVectorMapping = "vector_mapping"
+	kpFieldTextMapping   = "text_mapping"
+	kpFieldTextFields    = "text_fields"
+	kpFieldFilter        = "filter"
+	kpFieldK             = "k"
+	kpFieldCandidates    = "num_candidates"
+	kpFieldVectorBoost   = "vector_boost"
+	kpFieldTextBoost     = "text_boost"
+	kpFieldSourceFields  = "source_fields"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("AI", "Services").
+		Summary("Executes a kNN or hybrid (BM25 + vector) search against an Elasticsearch or OpenSearch index.").
+		Description(`
+The search vector is extracted from each message using `+"`vector_mapping`"+`. When a `+"`text_mapping`"+` is also provided the vector search is combined with a full text `+"`multi_match`"+` query over `+"`text_fields`"+`, and the relevance scores of both are summed after applying `+"`vector_boost`"+` and `+"`text_boost`"+` respectively.
+
+The message is replaced with an array of hits, each an object containing the fields `+"`id`, `index`, `score` and `source`"+`.`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewInterpolatedStringField(kpFieldIndex).
+				Description("The index to search."),
+			service.NewStringField(kpFieldVectorField).
+				Description("The document field containing vectors.").
+				Default("embedding"),
+			service.NewBloblangField(kpFieldVectorMapping).
+				Description("A mapping that extracts the search vector from the message, which must result in an array of numbers.").
+				Example(`root = this.embedding`),
+			service.NewBloblangField(kpFieldTextMapping).
+				Description("An optional mapping that extracts a text query from the message, enabling hybrid search.").
+				Example(`root = this.question`).
+				Optional(),
+			service.NewStringListField(kpFieldTextFields).
+				Description("The document fields the text query is matched against.").
+				Example([]string{"title^2", "body"}).
+				Default([]any{}),
+			service.NewBloblangField(kpFieldFilter).
+				Description("An optional mapping that results in a query DSL object used to filter documents before scoring.").
+				Example(`root.term.category = this.category`).
+				Optional(),
+			service.NewIntField(kpFieldK).
+				Description("The number of nearest neighbours, and therefore hits, to return.").
+				Default(10),
+			service.NewIntField(kpFieldCandidates).
+				Description("The number of candidates considered per shard. Only used by the `elasticsearch` flavor.").
+				Default(100).
+				Advanced(),
+			service.NewFloatField(kpFieldVectorBoost).
+				Description("A boost applied to the vector similarity score.").
+				Default(1.0).
+				Advanced(),
+			service.NewFloatField(kpFieldTextBoost).
+				Description("A boost applied to the text relevance score.").
+				Default(1.0).
+				Advanced(),
+			service.NewStringListField(kpFieldSourceFields).
+				Description("An optional list of source fields to return for each hit. When empty the full source is returned.").
+				Default([]any{}).
+				Advanced(),
+		).
+		Example("Hybrid search", "Combine a question embedding with a keyword match to retrieve context for a RAG pipeline.", `
+pipeline:
+  processors:
+    - branch:
+        request_map: root = this.question
+        processors:
+          - ollama_embeddings:
+              model: nomic-embed-text
+        result_map: root.embedding = this
+    - branch:
+        processors:
+          - elasticsearch_hybrid_search:
+              urls: [ http://localhost:9200 ]
+              index: documents
+              vector_mapping: root = this.embedding
+              text_mapping: root = this.question
+              text_fields: [ body ]
+              k: 5
+        result_map: root.context = this
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor("elasticsearch_hybrid_search", processorSpec(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			return newSearchProcessor(conf)
+		})
+}
+
+type searchProcessor struct {
+	client *knnClient
+
+	index         *service.InterpolatedString
+	vectorMapping *bloblang.Executor
+	textMapping   *bloblang.Executor
+	filter        *bloblang.Executor
+	params        searchParams
+}
+
+type searchParams struct {
+	flavor       string
+	vectorField  string
+	textFields   []string
+	k            int
+	candidates   int
+	vectorBoost  float64
+	textBoost    float64
+	sourceFields []string
+}
+
+func newSearchProcessor(conf *service.ParsedConfig) (*searchProcessor, error) {
+	p := &searchProcessor{}
+
+	var err error
+	if p.client, err = knnClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	p.params.flavor = p.client.flavor
+	if p.index, err = conf.FieldInterpolatedString(kpFieldIndex); err != nil {
+		return nil, err
+	}
+	if p.params.vectorField, err = conf.FieldString(kpFieldVectorField); err != nil {
+		return nil, err
+	}
+	if p.vectorMapping, err = conf.FieldBloblang(kpFieldVectorMapping); err != nil {
+		return nil, err
+	}
+	if conf.Contains(kpFieldTextMapping) {
+		if p.textMapping, err = conf.FieldBloblang(kpFieldTextMapping); err != nil {
+			return nil, err
+		}
+	}
+	if p.params.textFields, err = conf.FieldStringList(kpFieldTextFields); err != nil {
+		return nil, err
+	}
+	if p.textMapping != nil && len(p.params.textFields) == 0 {
+		return nil, fmt.Errorf("field %s must be set when %s is specified", kpFieldTextFields, kpFieldTextMapping)
+	}
+	if conf.Contains(kpFieldFilter) {
+		if p.filter, err = conf.FieldBloblang(kpFieldFilter); err != nil {
+			return nil, err
+		}
+	}
+	if p.params.k, err = conf.FieldInt(kpFieldK); err != nil {
+		return nil, err
+	}
+	if p.params.candidates, err = conf.FieldInt(kpFieldCandidates); err != nil {
+		return nil, err
+	}
+	if p.params.vectorBoost, err = conf.FieldFloat(kpFieldVectorBoost); err != nil {
+		return nil, err
+	}
+	if p.params.textBoost, err = conf.FieldFloat(kpFieldTextBoost); err != nil {
+		return nil, err
+	}
+	if p.params.sourceFields, err = conf.FieldStringList(kpFieldSourceFields); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// buildSearchQuery constructs the body of a search request for the configured
+// flavor. An empty text disables the full text component of the query.
+func buildSearchQuery(params searchParams, vector []float64, text string, filter any) map[string]any {
+	query := map[string]any{"size": params.k}
+	if len(params.sourceFields) > 0 {
+		query["_source"] = params.sourceFields
+	}
+
+	var textClause map[string]any
+	if text != "" {
+		textClause = map[string]any{
+			"multi_match": map[string]any{
+				"query":  text,
+				"fields": params.textFields,
+				"boost":  params.textBoost,
+			},
+		}
+	}
+
+	if params.flavor == flavorOpenSearch {
+		knnClause := map[string]any{
+			"knn": map[string]any{
+				params.vectorField: map[string]any{
+					"vector": vector,
+					"k":      params.k,
+					"boost":  params.vectorBoost,
+				},
+			},
+		}
+		should := []any{knnClause}
+		if textClause != nil {
+			should = append(should, textClause)
+		}
+		boolQuery := map[string]any{"should": should}
+		if filter != nil {
+			boolQuery["filter"] = filter
+		}
+		query["query"] = map[string]any{"bool": boolQuery}
+		return query
+	}
+
+	knnSection := map[string]any{
+		"field":          params.vectorField,
+		"query_vector":   vector,
+		"k":              params.k,
+		"num_candidates": params.candidates,
+		"boost":          params.vectorBoost,
+	}
+	if filter != nil {
+		knnSection["filter"] = filter
+	}
+	query["knn"] = knnSection
+	if textClause != nil {
+		boolQuery := map[string]any{"must": []any{textClause}}
+		if filter != nil {
+			boolQuery["filter"] = filter
+		}
+		query["query"] = map[string]any{"bool": boolQuery}
+	}
+	return query
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Index  string          `json:"_index"`
+			ID     string          `json:"_id"`
+			Score  float64         `json:"_score"`
+			Source json.RawMessage `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (p *searchProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	index, err := p.index.TryString(msg)
+	if err != nil {
+		return nil, fmt.Errorf("interpolating %s: %w", kpFieldIndex, err)
+	}
+
+	rawVec, err := msg.BloblangQuery(p.vectorMapping)
+	if err != nil {
+		return nil, fmt.Errorf("executing %s: %w", kpFieldVectorMapping, err)
+	}
+	vecAny, err := rawVec.AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("extracting %s result: %w", kpFieldVectorMapping, err)
+	}
+	vec, err := asVector(vecAny)
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	if p.textMapping != nil {
+		rawText, err := msg.BloblangQuery(p.textMapping)
+		if err != nil {
+			return nil, fmt.Errorf("executing %s: %w", kpFieldTextMapping, err)
+		}
+		textBytes, err := rawText.AsBytes()
+		if err != nil {
+			return nil, fmt.Errorf("extracting %s result: %w", kpFieldTextMapping, err)
+		}
+		text = string(textBytes)
+	}
+
+	var filter any
+	if p.filter != nil {
+		rawFilter, err := msg.BloblangQuery(p.filter)
+		if err != nil {
+			return nil, fmt.Errorf("executing %s: %w", kpFieldFilter, err)
+		}
+		if filter, err = rawFilter.AsStructured(); err != nil {
+			return nil, fmt.Errorf("extracting %s result: %w", kpFieldFilter, err)
+		}
+	}
+
+	resBytes, err := p.client.search(ctx, index, buildSearchQuery(p.params, vec, text, filter))
+	if err != nil {
+		return nil, fmt.Errorf("executing search: %w", err)
+	}
+	var res searchResponse
+	if err := json.Unmarshal(resBytes, &res); err != nil {
+		return nil, fmt.Errorf("parsing search response: %w", err)
+	}
+
+	hits := make([]any, 0, len(res.Hits.Hits))
+	for _, h := range res.Hits.Hits {
+		var source any
+		if len(h.Source) > 0 {
+			if err := json.Unmarshal(h.Source, &source); err != nil {
+				return nil, fmt.Errorf("parsing hit source: %w", err)
+			}
+		}
+		hits = append(hits, map[string]any{
+			"id":     h.ID,
+			"index":  h.Index,
+			"score":  h.Score,
+			"source": source,
+		})
+	}
+
+	msg = msg.Copy()
+	msg.SetStructuredMut(hits)
+	return service.MessageBatch{msg}, nil
+}
+
+func (*searchProcessor) Close(context.Context) error {
+	return nil
+}
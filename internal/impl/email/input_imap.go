@@ -0,0 +1,570 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	iiFieldAddress          = "address"
+	iiFieldStartTLS         = "start_tls"
+	iiFieldUsername         = "username"
+	iiFieldPassword         = "password"
+	iiFieldMailbox          = "mailbox"
+	iiFieldSearch           = "search"
+	iiFieldIdle             = "idle"
+	iiFieldPollInterval     = "poll_interval"
+	iiFieldSplitAttachments = "split_attachments"
+	iiFieldAfterRead        = "after_read"
+	iiFieldMoveTo           = "move_to"
+	iiFieldTLS              = "tls"
+)
+
+func imapInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Consumes emails from an IMAP mailbox.").
+		Description(`
+Emails matching `+"`"+iiFieldSearch+"`"+` are read from the mailbox in order of their UID. Once all emails have been read the input waits for new ones, either by listening for mailbox updates with the IMAP `+"`IDLE`"+` command or by searching the mailbox every `+"`"+iiFieldPollInterval+"`"+`.
+
+Each email is parsed and emitted as a batch, where the first message is a JSON document of the form:
+
+`+"```json"+`
+{
+  "message_id": "<id@example.com>",
+  "subject": "Monthly report",
+  "date": "2024-01-01T00:00:00Z",
+  "from": [ "\"Alice\" <alice@example.com>" ],
+  "to": [ "<bob@example.com>" ],
+  "cc": [],
+  "reply_to": [],
+  "text": "The plain text body",
+  "html": "<p>The HTML body</p>",
+  "attachments": [
+    { "filename": "report.pdf", "content_type": "application/pdf", "size": 5120 }
+  ]
+}
+`+"```"+`
+
+When `+"`"+iiFieldSplitAttachments+"`"+` is enabled each attachment follows as a separate message containing its raw contents, which allows attachments to be routed and processed independently of the email.
+
+Once a batch has been acknowledged the action `+"`"+iiFieldAfterRead+"`"+` is applied to the email, which either marks it as read, moves it to the mailbox `+"`"+iiFieldMoveTo+"`"+` or deletes it. Emails are read without marking them as read, and since the UID of the last email read is only held in memory a restart of the pipeline reads all emails matching `+"`"+iiFieldSearch+"`"+` again, and so it is recommended to combine the `+"`unseen`"+` search with an action other than `+"`none`"+`.
+
+Emails that cannot be parsed are emitted as a single message containing the raw email, flagged with an error that can be caught using xref:configuration:error_handling.adoc[error handling methods].
+
+When `+"`"+iiFieldTLS+"`"+` is enabled the connection is encrypted from the start, as with port 993, unless `+"`"+iiFieldStartTLS+"`"+` is set, in which case a plain connection is upgraded with the `+"`STARTTLS`"+` command, as with port 143.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- imap_mailbox
+- imap_uid
+- imap_message_id
+- imap_attachment_filename (attachments only)
+- imap_attachment_content_type (attachments only)
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(
+			service.NewStringField(iiFieldAddress).
+				Description("The address of the IMAP server.").
+				Example("imap.example.com:993"),
+			service.NewBoolField(iiFieldStartTLS).
+				Description("Whether to upgrade a plain connection with the `STARTTLS` command when `tls` is enabled.").
+				Default(false).
+				Advanced(),
+			service.NewStringField(iiFieldUsername).
+				Description("The user to authenticate as."),
+			service.NewStringField(iiFieldPassword).
+				Description("The password of the user.").
+				Secret(),
+			service.NewStringField(iiFieldMailbox).
+				Description("The mailbox to consume emails from.").
+				Default("INBOX"),
+			service.NewStringEnumField(iiFieldSearch, "unseen", "all").
+				Description("Which emails of the mailbox to consume.").
+				Default("unseen"),
+			service.NewBoolField(iiFieldIdle).
+				Description("Whether to wait for new emails with the `IDLE` command, falling back to polling when the server does not support it.").
+				Default(true).
+				Advanced(),
+			service.NewDurationField(iiFieldPollInterval).
+				Description("The interval at which the mailbox is searched for new emails when idling is disabled or unsupported.").
+				Default("1m").
+				Advanced(),
+			service.NewBoolField(iiFieldSplitAttachments).
+				Description("Whether to emit the attachments of each email as separate messages following the email.").
+				Default(true),
+			service.NewStringAnnotatedEnumField(iiFieldAfterRead, map[string]string{
+				"none":      "Leave the email unchanged.",
+				"mark_read": "Add the `\\Seen` flag to the email.",
+				"move":      "Move the email to the mailbox `" + iiFieldMoveTo + "`.",
+				"delete":    "Delete the email from the mailbox.",
+			}).
+				Description("The action applied to each email once it has been processed.").
+				Default("mark_read"),
+			service.NewStringField(iiFieldMoveTo).
+				Description("The mailbox that emails are moved to with the `move` action.").
+				Example("Processed").
+				Default(""),
+			service.NewTLSToggledField(iiFieldTLS),
+		).
+		Example(
+			"Process invoice attachments",
+			"Consume unread emails and write their PDF attachments to a bucket, archiving the emails once they have been processed.",
+			`
+input:
+  imap:
+    address: imap.example.com:993
+    username: invoices@example.com
+    password: ${IMAP_PASSWORD}
+    tls:
+      enabled: true
+    after_read: move
+    move_to: Archive
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if @imap_attachment_content_type != "application/pdf" { deleted() }
+
+output:
+  aws_s3:
+    bucket: invoices
+    path: ${! @imap_uid }/${! @imap_attachment_filename }
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchInput("imap", imapInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			i, err := newIMAPInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatched(i), nil
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type imapInput struct {
+	log *service.Logger
+
+	address          string
+	startTLS         bool
+	tlsConf          *tls.Config
+	username         string
+	password         string
+	mailbox          string
+	unseenOnly       bool
+	idle             bool
+	pollInterval     time.Duration
+	splitAttachments bool
+	afterRead        string
+	moveTo           string
+
+	mut         sync.Mutex
+	client      *client.Client
+	updates     chan client.Update
+	uidValidity uint32
+	lastUID     uint32
+	pendingUIDs []uint32
+
+	// Acknowledged UIDs are collected separately as acknowledgements arrive
+	// while reads hold the connection.
+	ackMut    sync.Mutex
+	ackedUIDs []uint32
+	wake      chan struct{}
+}
+
+func newIMAPInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*imapInput, error) {
+	i := &imapInput{
+		log:  mgr.Logger(),
+		wake: make(chan struct{}, 1),
+	}
+
+	var err error
+	if i.address, err = conf.FieldString(iiFieldAddress); err != nil {
+		return nil, err
+	}
+	if i.startTLS, err = conf.FieldBool(iiFieldStartTLS); err != nil {
+		return nil, err
+	}
+	if i.username, err = conf.FieldString(iiFieldUsername); err != nil {
+		return nil, err
+	}
+	if i.password, err = conf.FieldString(iiFieldPassword); err != nil {
+		return nil, err
+	}
+	if i.mailbox, err = conf.FieldString(iiFieldMailbox); err != nil {
+		return nil, err
+	}
+	search, err := conf.FieldString(iiFieldSearch)
+	if err != nil {
+		return nil, err
+	}
+	i.unseenOnly = search == "unseen"
+	if i.idle, err = conf.FieldBool(iiFieldIdle); err != nil {
+		return nil, err
+	}
+	if i.pollInterval, err = conf.FieldDuration(iiFieldPollInterval); err != nil {
+		return nil, err
+	}
+	if i.splitAttachments, err = conf.FieldBool(iiFieldSplitAttachments); err != nil {
+		return nil, err
+	}
+	if i.afterRead, err = conf.FieldString(iiFieldAfterRead); err != nil {
+		return nil, err
+	}
+	if i.moveTo, err = conf.FieldString(iiFieldMoveTo); err != nil {
+		return nil, err
+	}
+	if i.afterRead == "move" && i.moveTo == "" {
+		return nil, fmt.Errorf("field %v is required by the move action", iiFieldMoveTo)
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(iiFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		i.tlsConf = tlsConf
+	}
+	return i, nil
+}
+
+func (i *imapInput) Connect(context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil {
+		return nil
+	}
+
+	var c *client.Client
+	var err error
+	if i.tlsConf != nil && !i.startTLS {
+		c, err = client.DialTLS(i.address, i.tlsConf)
+	} else {
+		c, err = client.Dial(i.address)
+	}
+	if err != nil {
+		return err
+	}
+	if i.tlsConf != nil && i.startTLS {
+		if err := c.StartTLS(i.tlsConf); err != nil {
+			_ = c.Logout()
+			return fmt.Errorf("failed to upgrade connection: %w", err)
+		}
+	}
+	if err := c.Login(i.username, i.password); err != nil {
+		_ = c.Logout()
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+	status, err := c.Select(i.mailbox, false)
+	if err != nil {
+		_ = c.Logout()
+		return fmt.Errorf("failed to select mailbox %v: %w", i.mailbox, err)
+	}
+
+	// Messages are only identified by UIDs while the UID validity of the
+	// mailbox remains the same.
+	if status.UidValidity != i.uidValidity {
+		i.uidValidity = status.UidValidity
+		i.lastUID = 0
+		i.ackMut.Lock()
+		i.ackedUIDs = nil
+		i.ackMut.Unlock()
+	}
+	i.pendingUIDs = nil
+
+	// Updates are delivered synchronously by the client and must therefore be
+	// consumed at all times, they only serve to interrupt idling.
+	updates := make(chan client.Update, 1)
+	c.Updates = updates
+	go func() {
+		for range updates {
+			i.notify()
+		}
+	}()
+
+	i.client = c
+	i.updates = updates
+	return nil
+}
+
+func (i *imapInput) notify() {
+	select {
+	case i.wake <- struct{}{}:
+	default:
+	}
+}
+
+// applyAcked applies the after read action to acknowledged emails.
+func (i *imapInput) applyAcked() error {
+	i.ackMut.Lock()
+	uids := i.ackedUIDs
+	i.ackedUIDs = nil
+	i.ackMut.Unlock()
+
+	if len(uids) == 0 || i.afterRead == "none" {
+		return nil
+	}
+
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+
+	var err error
+	switch i.afterRead {
+	case "mark_read":
+		err = i.client.UidStore(seqSet, imap.FormatFlagsOp(imap.AddFlags, true), []any{imap.SeenFlag}, nil)
+	case "move":
+		err = i.client.UidMove(seqSet, i.moveTo)
+	case "delete":
+		if err = i.client.UidStore(seqSet, imap.FormatFlagsOp(imap.AddFlags, true), []any{imap.DeletedFlag}, nil); err == nil {
+			err = i.client.Expunge(nil)
+		}
+	}
+	if err != nil {
+		// Retain the emails so that the action is attempted again.
+		i.ackMut.Lock()
+		i.ackedUIDs = append(uids, i.ackedUIDs...)
+		i.ackMut.Unlock()
+		return fmt.Errorf("failed to apply %v action: %w", i.afterRead, err)
+	}
+	return nil
+}
+
+func (i *imapInput) search() ([]uint32, error) {
+	criteria := imap.NewSearchCriteria()
+	criteria.Uid = new(imap.SeqSet)
+	criteria.Uid.AddRange(i.lastUID+1, 0)
+	if i.unseenOnly {
+		criteria.WithoutFlags = []string{imap.SeenFlag}
+	}
+
+	uids, err := i.client.UidSearch(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	// A range ending with * always matches the email with the highest UID,
+	// even when it precedes the start of the range.
+	filtered := uids[:0]
+	for _, uid := range uids {
+		if uid > i.lastUID {
+			filtered = append(filtered, uid)
+		}
+	}
+	return filtered, nil
+}
+
+// wait blocks until the mailbox changes, an email is acknowledged or the poll
+// interval elapses.
+func (i *imapInput) wait(ctx context.Context) error {
+	timer := time.NewTimer(i.pollInterval)
+	defer timer.Stop()
+
+	if !i.idle {
+		select {
+		case <-i.wake:
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- i.client.Idle(stop, &client.IdleOptions{PollInterval: i.pollInterval})
+	}()
+
+	select {
+	case <-i.wake:
+	case <-timer.C:
+	case <-ctx.Done():
+	case err := <-done:
+		return err
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (i *imapInput) fetch(uid uint32) (service.MessageBatch, error) {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+	section := &imap.BodySectionName{Peek: true}
+
+	msgs := make(chan *imap.Message, 1)
+	if err := i.client.UidFetch(seqSet, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, msgs); err != nil {
+		return nil, err
+	}
+	msg := <-msgs
+	if msg == nil {
+		// The email has been removed since it was found.
+		return nil, nil
+	}
+	body := msg.GetBody(section)
+	if body == nil {
+		return nil, fmt.Errorf("server did not return the body of email %v", uid)
+	}
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	newPart := func(messageID string) *service.Message {
+		part := service.NewMessage(nil)
+		part.MetaSetMut("imap_mailbox", i.mailbox)
+		part.MetaSetMut("imap_uid", int64(uid))
+		part.MetaSetMut("imap_message_id", messageID)
+		return part
+	}
+
+	parsed, err := parseEmail(bytes.NewReader(raw))
+	if err != nil {
+		// Malformed emails are emitted as is and flagged with an error so
+		// that they can be routed rather than blocking the mailbox.
+		msg := newPart("")
+		msg.SetBytes(raw)
+		msg.SetError(fmt.Errorf("failed to parse email %v: %w", uid, err))
+		return service.MessageBatch{msg}, nil
+	}
+	messageID, _ := parsed.fields["message_id"].(string)
+
+	emailMsg := newPart(messageID)
+	emailMsg.SetStructuredMut(parsed.fields)
+	batch := service.MessageBatch{emailMsg}
+	if i.splitAttachments {
+		for _, a := range parsed.attachments {
+			part := newPart(messageID)
+			part.SetBytes(a.data)
+			part.MetaSetMut("imap_attachment_filename", a.filename)
+			part.MetaSetMut("imap_attachment_content_type", a.contentType)
+			batch = append(batch, part)
+		}
+	}
+	return batch, nil
+}
+
+func (i *imapInput) disconnect() {
+	if i.client == nil {
+		return
+	}
+	_ = i.client.Logout()
+	<-i.client.LoggedOut()
+	close(i.updates)
+	i.client = nil
+	i.updates = nil
+}
+
+func (i *imapInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	for {
+		if i.client == nil {
+			return nil, nil, service.ErrNotConnected
+		}
+		if err := i.applyAcked(); err != nil {
+			i.log.Errorf("%v", err)
+		}
+
+		if len(i.pendingUIDs) == 0 {
+			uids, err := i.search()
+			if err != nil {
+				i.log.Errorf("Failed to search mailbox: %v", err)
+				i.disconnect()
+				return nil, nil, service.ErrNotConnected
+			}
+			if len(uids) == 0 {
+				if err := i.wait(ctx); err != nil {
+					if ctx.Err() != nil {
+						return nil, nil, ctx.Err()
+					}
+					i.log.Errorf("Failed to wait for emails: %v", err)
+					i.disconnect()
+					return nil, nil, service.ErrNotConnected
+				}
+				continue
+			}
+			i.pendingUIDs = uids
+		}
+
+		uid := i.pendingUIDs[0]
+		batch, err := i.fetch(uid)
+		if err != nil {
+			i.log.Errorf("Failed to fetch email: %v", err)
+			i.disconnect()
+			return nil, nil, service.ErrNotConnected
+		}
+		i.pendingUIDs = i.pendingUIDs[1:]
+		i.lastUID = uid
+		if batch == nil {
+			continue
+		}
+
+		return batch, func(_ context.Context, err error) error {
+			if err != nil {
+				return nil
+			}
+			i.ackMut.Lock()
+			i.ackedUIDs = append(i.ackedUIDs, uid)
+			i.ackMut.Unlock()
+			i.notify()
+			return nil
+		}, nil
+	}
+}
+
+func (i *imapInput) Close(context.Context) error {
+	// Interrupt a read waiting for emails so that the connection is released.
+	i.notify()
+
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil {
+		if err := i.applyAcked(); err != nil {
+			i.log.Errorf("%v", err)
+		}
+	}
+	i.disconnect()
+	return nil
+}
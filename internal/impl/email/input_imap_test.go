@@ -0,0 +1,197 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-imap/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const testMultipartEmail = "From: Alice <alice@example.com>\r\n" +
+	"To: bob@example.com\r\n" +
+	"Subject: Report\r\n" +
+	"Date: Mon, 01 Jan 2024 10:00:00 +0000\r\n" +
+	"Message-ID: <report@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"See attached.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/csv\r\n" +
+	"Content-Disposition: attachment; filename=report.csv\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"YSxiCjEsMgo=\r\n" +
+	"--b1--\r\n"
+
+func TestParseEmail(t *testing.T) {
+	parsed, err := parseEmail(strings.NewReader(testMultipartEmail))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"message_id": "report@example.com",
+		"subject":    "Report",
+		"date":       "2024-01-01T10:00:00Z",
+		"from":       []any{`"Alice" <alice@example.com>`},
+		"to":         []any{"<bob@example.com>"},
+		"cc":         []any{},
+		"reply_to":   []any{},
+		"text":       "See attached.",
+		"html":       "",
+		"attachments": []any{
+			map[string]any{"filename": "report.csv", "content_type": "text/csv", "size": int64(8)},
+		},
+	}, parsed.fields)
+	require.Len(t, parsed.attachments, 1)
+	assert.Equal(t, "a,b\n1,2\n", string(parsed.attachments[0].data))
+}
+
+func startIMAPServer(t *testing.T) (string, *client.Client) {
+	t.Helper()
+
+	srv := server.New(memory.New())
+	srv.AllowInsecureAuth = true
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	c, err := client.Dial(l.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, c.Login("username", "password"))
+	t.Cleanup(func() { _ = c.Logout() })
+	return l.Addr().String(), c
+}
+
+func mailboxFlags(t *testing.T, c *client.Client, mailbox string) map[uint32][]string {
+	t.Helper()
+
+	_, err := c.Select(mailbox, true)
+	require.NoError(t, err)
+
+	seqSet, _ := imap.ParseSeqSet("1:*")
+	msgs := make(chan *imap.Message, 10)
+	require.NoError(t, c.UidFetch(seqSet, []imap.FetchItem{imap.FetchUid, imap.FetchFlags}, msgs))
+
+	flags := map[uint32][]string{}
+	for m := range msgs {
+		flags[m.Uid] = m.Flags
+	}
+	return flags
+}
+
+func testIMAPInput(t *testing.T, addr, extra string) *imapInput {
+	t.Helper()
+
+	conf, err := imapInputSpec().ParseYAML(`
+address: `+addr+`
+username: username
+password: password
+poll_interval: 50ms
+`+extra, nil)
+	require.NoError(t, err)
+
+	in, err := newIMAPInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(t.Context()))
+	return in
+}
+
+func TestIMAPInputMarkRead(t *testing.T) {
+	addr, c := startIMAPServer(t)
+	require.NoError(t, c.Append("INBOX", nil, time.Now(), strings.NewReader(testMultipartEmail)))
+
+	in := testIMAPInput(t, addr, "")
+
+	batch, ackFn, err := in.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, "Report", v.(map[string]any)["subject"])
+
+	b, err := batch[1].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(b))
+	filename, _ := batch[1].MetaGet("imap_attachment_filename")
+	assert.Equal(t, "report.csv", filename)
+	uid, _ := batch[1].MetaGetMut("imap_uid")
+	assert.Equal(t, int64(7), uid)
+
+	require.NoError(t, ackFn(t.Context(), nil))
+	require.NoError(t, in.Close(t.Context()))
+
+	assert.Contains(t, mailboxFlags(t, c, "INBOX")[7], imap.SeenFlag)
+}
+
+func TestIMAPInputWaitsForNewEmails(t *testing.T) {
+	addr, c := startIMAPServer(t)
+
+	in := testIMAPInput(t, addr, `
+search: all
+after_read: delete
+split_attachments: false
+`)
+	t.Cleanup(func() { _ = in.Close(t.Context()) })
+
+	appended := make(chan error, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		appended <- c.Append("INBOX", nil, time.Now(), strings.NewReader(testMultipartEmail))
+	}()
+
+	// The mailbox starts with a single email that has already been read,
+	// which is rejected and must therefore be retained.
+	batch, ackFn, err := in.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	require.NoError(t, ackFn(t.Context(), errors.New("rejected")))
+
+	batch, ackFn, err = in.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, "Report", v.(map[string]any)["subject"])
+	require.NoError(t, ackFn(t.Context(), nil))
+	require.NoError(t, <-appended)
+
+	require.Eventually(t, func() bool {
+		in.mut.Lock()
+		defer in.mut.Unlock()
+		return in.applyAcked() == nil
+	}, time.Second, 10*time.Millisecond)
+
+	flags := mailboxFlags(t, c, "INBOX")
+	assert.Contains(t, flags, uint32(6))
+	assert.NotContains(t, flags, uint32(7))
+}
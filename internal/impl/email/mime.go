@@ -0,0 +1,129 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message"
+	_ "github.com/emersion/go-message/charset" // Decode non UTF-8 charsets
+	"github.com/emersion/go-message/mail"
+)
+
+// attachment is a part of an email with a content disposition of attachment.
+type attachment struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// parsedEmail is the structured form of a MIME message.
+type parsedEmail struct {
+	fields      map[string]any
+	attachments []attachment
+}
+
+func addressList(h *mail.Header, key string) []any {
+	addrs, err := h.AddressList(key)
+	if err != nil || len(addrs) == 0 {
+		// Fall back to the raw value so that malformed headers are not lost.
+		if v := h.Get(key); v != "" {
+			return []any{v}
+		}
+		return []any{}
+	}
+	list := make([]any, 0, len(addrs))
+	for _, a := range addrs {
+		list = append(list, a.String())
+	}
+	return list
+}
+
+// parseEmail parses a MIME message into its headers, text and HTML bodies
+// and attachments.
+func parseEmail(r io.Reader) (*parsedEmail, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil && !message.IsUnknownCharset(err) {
+		return nil, err
+	}
+	defer mr.Close()
+
+	subject, _ := mr.Header.Subject()
+	messageID, _ := mr.Header.MessageID()
+	fields := map[string]any{
+		"message_id": messageID,
+		"subject":    subject,
+		"from":       addressList(&mr.Header, "From"),
+		"to":         addressList(&mr.Header, "To"),
+		"cc":         addressList(&mr.Header, "Cc"),
+		"reply_to":   addressList(&mr.Header, "Reply-To"),
+	}
+	if date, err := mr.Header.Date(); err == nil {
+		fields["date"] = date.UTC().Format(time.RFC3339)
+	}
+
+	var text, html strings.Builder
+	p := &parsedEmail{fields: fields}
+	attachmentInfo := []any{}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !message.IsUnknownCharset(err) {
+			return nil, fmt.Errorf("failed to read part: %w", err)
+		}
+
+		data, err := io.ReadAll(part.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read part: %w", err)
+		}
+
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, params, _ := h.ContentType()
+			switch contentType {
+			case "text/html":
+				html.Write(data)
+				continue
+			case "", "text/plain":
+				text.Write(data)
+				continue
+			}
+			// Other inline parts, such as images, are treated as attachments.
+			p.attachments = append(p.attachments, attachment{filename: params["name"], contentType: contentType, data: data})
+		case *mail.AttachmentHeader:
+			contentType, _, _ := h.ContentType()
+			filename, _ := h.Filename()
+			p.attachments = append(p.attachments, attachment{filename: filename, contentType: contentType, data: data})
+		}
+	}
+
+	for _, a := range p.attachments {
+		attachmentInfo = append(attachmentInfo, map[string]any{
+			"filename":     a.filename,
+			"content_type": a.contentType,
+			"size":         int64(len(a.data)),
+		})
+	}
+	fields["text"] = text.String()
+	fields["html"] = html.String()
+	fields["attachments"] = attachmentInfo
+	return p, nil
+}
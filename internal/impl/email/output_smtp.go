@@ -0,0 +1,539 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/emersion/go-message/mail"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	soFieldAddress            = "address"
+	soFieldStartTLS           = "start_tls"
+	soFieldUsername           = "username"
+	soFieldPassword           = "password"
+	soFieldHelo               = "helo"
+	soFieldFrom               = "from"
+	soFieldTo                 = "to"
+	soFieldCc                 = "cc"
+	soFieldBcc                = "bcc"
+	soFieldSubject            = "subject"
+	soFieldBody               = "body"
+	soFieldContentType        = "content_type"
+	soFieldAttachments        = "attachments"
+	soFieldAttachmentFilename = "filename"
+	soFieldAttachmentType     = "content_type"
+	soFieldTimeout            = "timeout"
+	soFieldTLS                = "tls"
+	soFieldBatching           = "batching"
+)
+
+func smtpOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Sends messages as emails through an SMTP server.").
+		Description(`
+By default each message is sent as an email, where the recipients, subject and body are resolved from the message with xref:configuration:interpolation.adoc#bloblang-queries[function interpolation]. Recipients are lists of comma separated addresses, and recipients in `+"`"+soFieldBcc+"`"+` receive the email without being listed in its headers.
+
+When `+"`"+soFieldAttachments+"."+soFieldAttachmentFilename+"`"+` is set each batch is instead sent as a single email, where the first message of the batch determines the recipients, subject and body, and each following message is attached to the email with its raw contents. This allows reports to be assembled from a number of messages with a xref:configuration:batching.adoc#batch-policy[batching policy].
+
+When `+"`"+soFieldTLS+"`"+` is enabled the connection is encrypted from the start, as with port 465, unless `+"`"+soFieldStartTLS+"`"+` is set, in which case a plain connection is upgraded with the `+"`STARTTLS`"+` command, as with port 587. Credentials are only sent over encrypted connections, or to a server on the local host.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringField(soFieldAddress).
+				Description("The address of the SMTP server.").
+				Example("smtp.example.com:587"),
+			service.NewBoolField(soFieldStartTLS).
+				Description("Whether to upgrade a plain connection with the `STARTTLS` command when `tls` is enabled.").
+				Default(false).
+				Advanced(),
+			service.NewStringField(soFieldUsername).
+				Description("An optional user to authenticate as with the `PLAIN` mechanism.").
+				Default(""),
+			service.NewStringField(soFieldPassword).
+				Description("The password of the user.").
+				Default("").
+				Secret(),
+			service.NewStringField(soFieldHelo).
+				Description("The host name sent to the server when greeting it.").
+				Default("localhost").
+				Advanced(),
+			service.NewInterpolatedStringField(soFieldFrom).
+				Description("The sender of emails.").
+				Example(`"Alerts" <alerts@example.com>`),
+			service.NewInterpolatedStringField(soFieldTo).
+				Description("A comma separated list of recipients.").
+				Example("oncall@example.com, ${! @team }@example.com"),
+			service.NewInterpolatedStringField(soFieldCc).
+				Description("An optional comma separated list of recipients to send copies to.").
+				Default(""),
+			service.NewInterpolatedStringField(soFieldBcc).
+				Description("An optional comma separated list of recipients to send blind copies to.").
+				Default("").
+				Advanced(),
+			service.NewInterpolatedStringField(soFieldSubject).
+				Description("The subject of emails.").
+				Example(`Alert: ${! this.service } is ${! this.status }`),
+			service.NewInterpolatedStringField(soFieldBody).
+				Description("The body of emails.").
+				Example(`${! this.description }`).
+				Default("${! content() }"),
+			service.NewStringEnumField(soFieldContentType, "text/plain", "text/html").
+				Description("The content type of the body.").
+				Default("text/plain"),
+			service.NewObjectField(soFieldAttachments,
+				service.NewInterpolatedStringField(soFieldAttachmentFilename).
+					Description("The file name of each attachment. When set each batch is sent as a single email with attachments.").
+					Example(`${! @path.filepath_split().index(-1) }`).
+					Example(`part-${! batch_index() }.json`).
+					Optional(),
+				service.NewInterpolatedStringField(soFieldAttachmentType).
+					Description("The content type of each attachment.").
+					Example(`${! @content_type }`).
+					Default("application/octet-stream"),
+			).Description("Determines how messages of a batch are attached to a single email.").Advanced(),
+			service.NewDurationField(soFieldTimeout).
+				Description("The maximum period to wait for the server when connecting and sending each batch.").
+				Default("30s").
+				Advanced(),
+			service.NewTLSToggledField(soFieldTLS),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(soFieldBatching),
+		).
+		Example("Alerting", "Send an email for each alert, with the recipients depending on the team that owns the alerting service.", `
+output:
+  smtp:
+    address: smtp.example.com:587
+    username: alerts@example.com
+    password: ${SMTP_PASSWORD}
+    start_tls: true
+    tls:
+      enabled: true
+    from: '"Alerts" <alerts@example.com>'
+    to: ${! this.team }@example.com
+    subject: '[${! this.severity.uppercase() }] ${! this.title }'
+    body: ${! this.description }
+`).
+		Example("Daily reports", "Send the reports written over a day as attachments of a single email.", `
+output:
+  smtp:
+    address: smtp.example.com:465
+    username: reports@example.com
+    password: ${SMTP_PASSWORD}
+    tls:
+      enabled: true
+    from: reports@example.com
+    to: management@example.com
+    subject: Daily reports ${! now().ts_format("2006-01-02") }
+    body: The reports of the day are attached.
+    attachments:
+      filename: ${! @report_name }.csv
+      content_type: text/csv
+    batching:
+      period: 24h
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("smtp", smtpOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(soFieldBatching); err != nil {
+				return
+			}
+			out, err = newSMTPOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type smtpOutput struct {
+	log *service.Logger
+
+	address     string
+	startTLS    bool
+	tlsConf     *tls.Config
+	username    string
+	password    string
+	helo        string
+	from        *service.InterpolatedString
+	to          *service.InterpolatedString
+	cc          *service.InterpolatedString
+	bcc         *service.InterpolatedString
+	subject     *service.InterpolatedString
+	body        *service.InterpolatedString
+	contentType string
+	filename    *service.InterpolatedString
+	fileType    *service.InterpolatedString
+	timeout     time.Duration
+	nowFn       func() time.Time
+}
+
+func newSMTPOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*smtpOutput, error) {
+	o := &smtpOutput{log: mgr.Logger(), nowFn: time.Now}
+
+	var err error
+	if o.address, err = conf.FieldString(soFieldAddress); err != nil {
+		return nil, err
+	}
+	if o.startTLS, err = conf.FieldBool(soFieldStartTLS); err != nil {
+		return nil, err
+	}
+	if o.username, err = conf.FieldString(soFieldUsername); err != nil {
+		return nil, err
+	}
+	if o.password, err = conf.FieldString(soFieldPassword); err != nil {
+		return nil, err
+	}
+	if o.helo, err = conf.FieldString(soFieldHelo); err != nil {
+		return nil, err
+	}
+	if o.from, err = conf.FieldInterpolatedString(soFieldFrom); err != nil {
+		return nil, err
+	}
+	if o.to, err = conf.FieldInterpolatedString(soFieldTo); err != nil {
+		return nil, err
+	}
+	if o.cc, err = conf.FieldInterpolatedString(soFieldCc); err != nil {
+		return nil, err
+	}
+	if o.bcc, err = conf.FieldInterpolatedString(soFieldBcc); err != nil {
+		return nil, err
+	}
+	if o.subject, err = conf.FieldInterpolatedString(soFieldSubject); err != nil {
+		return nil, err
+	}
+	if o.body, err = conf.FieldInterpolatedString(soFieldBody); err != nil {
+		return nil, err
+	}
+	if o.contentType, err = conf.FieldString(soFieldContentType); err != nil {
+		return nil, err
+	}
+
+	attConf := conf.Namespace(soFieldAttachments)
+	if attConf.Contains(soFieldAttachmentFilename) {
+		if o.filename, err = attConf.FieldInterpolatedString(soFieldAttachmentFilename); err != nil {
+			return nil, err
+		}
+	}
+	if o.fileType, err = attConf.FieldInterpolatedString(soFieldAttachmentType); err != nil {
+		return nil, err
+	}
+	if o.timeout, err = conf.FieldDuration(soFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(soFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		o.tlsConf = tlsConf
+		if o.tlsConf.ServerName == "" {
+			o.tlsConf = o.tlsConf.Clone()
+			o.tlsConf.ServerName, _, _ = net.SplitHostPort(o.address)
+		}
+	}
+	return o, nil
+}
+
+func (*smtpOutput) Connect(context.Context) error {
+	return nil
+}
+
+// envelope is an email ready to be sent.
+type envelope struct {
+	from       string
+	recipients []string
+	data       []byte
+}
+
+func parseAddresses(field string, v string) ([]*mail.Address, error) {
+	if v == "" {
+		return nil, nil
+	}
+	addrs, err := mail.ParseAddressList(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v addresses: %w", field, err)
+	}
+	return addrs, nil
+}
+
+// compose builds an email from the first message of a batch and attaches the
+// remaining messages, if any.
+func (o *smtpOutput) compose(batch service.MessageBatch) (*envelope, error) {
+	interp := func(field string, s *service.InterpolatedString) (string, error) {
+		v, err := batch.TryInterpolatedString(0, s)
+		if err != nil {
+			return "", fmt.Errorf("failed to interpolate %v: %w", field, err)
+		}
+		return v, nil
+	}
+
+	var h mail.Header
+	h.SetDate(o.nowFn())
+	if err := h.GenerateMessageID(); err != nil {
+		return nil, err
+	}
+
+	fromStr, err := interp(soFieldFrom, o.from)
+	if err != nil {
+		return nil, err
+	}
+	from, err := mail.ParseAddress(fromStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v address: %w", soFieldFrom, err)
+	}
+	h.SetAddressList("From", []*mail.Address{from})
+
+	var recipients []string
+	for _, f := range []struct {
+		field  string
+		header string
+		s      *service.InterpolatedString
+	}{
+		{field: soFieldTo, header: "To", s: o.to},
+		{field: soFieldCc, header: "Cc", s: o.cc},
+		{field: soFieldBcc, s: o.bcc},
+	} {
+		v, err := interp(f.field, f.s)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := parseAddresses(f.field, v)
+		if err != nil {
+			return nil, err
+		}
+		if f.header != "" && len(addrs) > 0 {
+			h.SetAddressList(f.header, addrs)
+		}
+		for _, a := range addrs {
+			recipients = append(recipients, a.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("email has no recipients")
+	}
+
+	subject, err := interp(soFieldSubject, o.subject)
+	if err != nil {
+		return nil, err
+	}
+	h.SetSubject(subject)
+
+	body, err := interp(soFieldBody, o.body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	var bodyHeader mail.InlineHeader
+	bodyHeader.SetContentType(o.contentType, map[string]string{"charset": "utf-8"})
+
+	if o.filename == nil || len(batch) == 1 {
+		h.SetContentType(o.contentType, map[string]string{"charset": "utf-8"})
+		w, err := mail.CreateSingleInlineWriter(&buf, h)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return &envelope{from: from.Address, recipients: recipients, data: buf.Bytes()}, nil
+	}
+
+	mw, err := mail.CreateWriter(&buf, h)
+	if err != nil {
+		return nil, err
+	}
+	bw, err := mw.CreateSingleInline(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(bw, body); err != nil {
+		return nil, err
+	}
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(batch); i++ {
+		filename, err := batch.TryInterpolatedString(i, o.filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate attachment filename: %w", err)
+		}
+		contentType, err := batch.TryInterpolatedString(i, o.fileType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate attachment content type: %w", err)
+		}
+		data, err := batch[i].AsBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		var ah mail.AttachmentHeader
+		ah.SetContentType(contentType, nil)
+		ah.SetFilename(filename)
+		aw, err := mw.CreateAttachment(ah)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := aw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := aw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return &envelope{from: from.Address, recipients: recipients, data: buf.Bytes()}, nil
+}
+
+func (o *smtpOutput) dial(ctx context.Context) (*smtp.Client, error) {
+	dialer := &net.Dialer{Timeout: o.timeout}
+
+	var conn net.Conn
+	var err error
+	if o.tlsConf != nil && !o.startTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: o.tlsConf}).DialContext(ctx, "tcp", o.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", o.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(o.timeout))
+	}
+
+	host, _, _ := net.SplitHostPort(o.address)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := c.Hello(o.helo); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if o.tlsConf != nil && o.startTLS {
+		if err := c.StartTLS(o.tlsConf); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to upgrade connection: %w", err)
+		}
+	}
+	if o.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", o.username, o.password, host)); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func send(c *smtp.Client, e *envelope) error {
+	if err := c.Mail(e.from); err != nil {
+		return err
+	}
+	for _, r := range e.recipients {
+		if err := c.Rcpt(r); err != nil {
+			return fmt.Errorf("recipient %v was rejected: %w", r, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(e.data); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (o *smtpOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	var envelopes []*envelope
+	if o.filename != nil {
+		e, err := o.compose(batch)
+		if err != nil {
+			return err
+		}
+		envelopes = append(envelopes, e)
+	} else {
+		for i := range batch {
+			e, err := o.compose(batch[i : i+1])
+			if err != nil {
+				return err
+			}
+			envelopes = append(envelopes, e)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	c, err := o.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	for i, e := range envelopes {
+		if i > 0 {
+			err = c.Reset()
+		}
+		if err == nil {
+			err = send(c, e)
+		}
+		if err == nil {
+			continue
+		}
+		if o.filename != nil || len(envelopes) == 1 {
+			return err
+		}
+		// Emails that have already been sent are not sent again.
+		bErr := service.NewBatchError(batch, err)
+		for j := i; j < len(batch); j++ {
+			bErr.Failed(j, err)
+		}
+		return bErr
+	}
+	return c.Quit()
+}
+
+func (*smtpOutput) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type receivedEmail struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// fakeSMTPServer accepts emails without authentication, rejecting recipients
+// of the domain reject.example.com.
+type fakeSMTPServer struct {
+	l net.Listener
+
+	mut    sync.Mutex
+	emails []receivedEmail
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{l: l}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+
+	var current receivedEmail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "MAIL":
+			current = receivedEmail{from: strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")}
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			rcpt := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if strings.HasSuffix(rcpt, "@reject.example.com") {
+				_ = tp.PrintfLine("550 No such user")
+				continue
+			}
+			current.recipients = append(current.recipients, rcpt)
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 Go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.data = string(data)
+			s.mut.Lock()
+			s.emails = append(s.emails, current)
+			s.mut.Unlock()
+			_ = tp.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) received() []receivedEmail {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.emails
+}
+
+func testSMTPOutput(t *testing.T, conf string) *smtpOutput {
+	t.Helper()
+
+	pConf, err := smtpOutputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+	o, err := newSMTPOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(t.Context()))
+	return o
+}
+
+func parseReceived(t *testing.T, e receivedEmail) *parsedEmail {
+	t.Helper()
+
+	parsed, err := parseEmail(strings.NewReader(e.data))
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestSMTPOutputEmailPerMessage(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+
+	o := testSMTPOutput(t, `
+address: `+srv.l.Addr().String()+`
+from: '"Alerts" <alerts@example.com>'
+to: ${! this.team }@example.com
+bcc: audit@example.com
+subject: 'Alert: ${! this.title }'
+body: ${! this.description }
+`)
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"team":"db","title":"Disk full","description":"The disk is full."}`)),
+		service.NewMessage([]byte(`{"team":"web","title":"Latency","description":"Requests are slow."}`)),
+	}))
+
+	emails := srv.received()
+	require.Len(t, emails, 2)
+
+	assert.Equal(t, "alerts@example.com", emails[0].from)
+	assert.Equal(t, []string{"db@example.com", "audit@example.com"}, emails[0].recipients)
+	assert.NotContains(t, emails[0].data, "audit@example.com")
+
+	parsed := parseReceived(t, emails[0])
+	assert.Equal(t, "Alert: Disk full", parsed.fields["subject"])
+	assert.Equal(t, []any{`"Alerts" <alerts@example.com>`}, parsed.fields["from"])
+	assert.Equal(t, "The disk is full.", strings.TrimSpace(parsed.fields["text"].(string)))
+
+	parsed = parseReceived(t, emails[1])
+	assert.Equal(t, "Alert: Latency", parsed.fields["subject"])
+	assert.Equal(t, []any{"<web@example.com>"}, parsed.fields["to"])
+}
+
+func TestSMTPOutputAttachments(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+
+	o := testSMTPOutput(t, `
+address: `+srv.l.Addr().String()+`
+from: reports@example.com
+to: a@example.com, b@example.com
+subject: Reports
+body: <p>${! content() }</p>
+content_type: text/html
+attachments:
+  filename: ${! @name }.csv
+  content_type: text/csv
+`)
+
+	batch := service.MessageBatch{service.NewMessage([]byte("See the attached reports."))}
+	for _, name := range []string{"sales", "costs"} {
+		msg := service.NewMessage([]byte(name + ",1\n"))
+		msg.MetaSetMut("name", name)
+		batch = append(batch, msg)
+	}
+	require.NoError(t, o.WriteBatch(t.Context(), batch))
+
+	emails := srv.received()
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails[0].recipients)
+
+	parsed := parseReceived(t, emails[0])
+	assert.Equal(t, "<p>See the attached reports.</p>", parsed.fields["html"])
+	require.Len(t, parsed.attachments, 2)
+	assert.Equal(t, attachment{filename: "sales.csv", contentType: "text/csv", data: []byte("sales,1\n")}, parsed.attachments[0])
+	assert.Equal(t, attachment{filename: "costs.csv", contentType: "text/csv", data: []byte("costs,1\n")}, parsed.attachments[1])
+}
+
+func TestSMTPOutputPartialFailure(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+
+	o := testSMTPOutput(t, `
+address: `+srv.l.Addr().String()+`
+from: alerts@example.com
+to: ${! content() }
+subject: Alert
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("ok@example.com")),
+		service.NewMessage([]byte("nobody@reject.example.com")),
+		service.NewMessage([]byte("later@example.com")),
+	}
+	index := batch.Index()
+	err := o.WriteBatch(t.Context(), batch)
+
+	var bErr *service.BatchError
+	require.ErrorAs(t, err, &bErr)
+	assert.Equal(t, 2, bErr.IndexedErrors())
+
+	var failed []string
+	bErr.WalkMessagesIndexedBy(index, func(_ int, m *service.Message, err error) bool {
+		if err != nil {
+			b, _ := m.AsBytes()
+			failed = append(failed, string(b))
+		}
+		return true
+	})
+	assert.Equal(t, []string{"nobody@reject.example.com", "later@example.com"}, failed)
+
+	emails := srv.received()
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"ok@example.com"}, emails[0].recipients)
+}
+
+func TestSMTPOutputInvalidAddress(t *testing.T) {
+	o := testSMTPOutput(t, `
+address: 127.0.0.1:0
+from: not an address
+to: a@example.com
+subject: Alert
+`)
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage(nil)})
+	require.ErrorContains(t, err, "failed to parse from address")
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// envelopeMagic prefixes every envelope, where the last byte is the version of
+// the format.
+var envelopeMagic = []byte("RPE\x01")
+
+const (
+	dataKeyLen = 32
+	nonceLen   = 12
+)
+
+// envelope is an encrypted payload along with the data key it was encrypted
+// with, itself encrypted by a KMS key.
+//
+// The binary layout is the magic bytes, the uvarint prefixed key ID, the
+// uvarint prefixed wrapped data key, the nonce and then the AES-256-GCM
+// ciphertext. Everything before the nonce is authenticated as additional data,
+// and therefore the key ID and wrapped key cannot be swapped.
+type envelope struct {
+	keyID      string
+	wrappedKey []byte
+	nonce      []byte
+	ciphertext []byte
+}
+
+func (e *envelope) header() []byte {
+	b := bytes.Clone(envelopeMagic)
+	b = binary.AppendUvarint(b, uint64(len(e.keyID)))
+	b = append(b, e.keyID...)
+	b = binary.AppendUvarint(b, uint64(len(e.wrappedKey)))
+	return append(b, e.wrappedKey...)
+}
+
+func (e *envelope) marshal() []byte {
+	b := e.header()
+	b = append(b, e.nonce...)
+	return append(b, e.ciphertext...)
+}
+
+func readPrefixed(b []byte) (field, rest []byte, err error) {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < l {
+		return nil, nil, errors.New("truncated envelope")
+	}
+	return b[n : n+int(l)], b[n+int(l):], nil
+}
+
+func unmarshalEnvelope(b []byte) (*envelope, []byte, error) {
+	if !bytes.HasPrefix(b, envelopeMagic) {
+		return nil, nil, errors.New("payload is not an encryption envelope")
+	}
+	rest := b[len(envelopeMagic):]
+
+	keyID, rest, err := readPrefixed(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	wrappedKey, rest, err := readPrefixed(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) < nonceLen {
+		return nil, nil, errors.New("truncated envelope")
+	}
+
+	header := b[:len(b)-len(rest)]
+	return &envelope{
+		keyID:      string(keyID),
+		wrappedKey: wrappedKey,
+		nonce:      rest[:nonceLen],
+		ciphertext: rest[nonceLen:],
+	}, header, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeyLen {
+		return nil, fmt.Errorf("expected a data key of %v bytes, got %v", dataKeyLen, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts a payload with a data key and returns the marshalled envelope.
+func seal(aead cipher.AEAD, keyID string, wrappedKey, plaintext []byte) ([]byte, error) {
+	e := &envelope{
+		keyID:      keyID,
+		wrappedKey: wrappedKey,
+		nonce:      make([]byte, aead.NonceSize()),
+	}
+	if _, err := rand.Read(e.nonce); err != nil {
+		return nil, err
+	}
+	e.ciphertext = aead.Seal(nil, e.nonce, plaintext, e.header())
+	return e.marshal(), nil
+}
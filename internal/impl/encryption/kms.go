@@ -0,0 +1,140 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	awsconfig "github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+)
+
+const (
+	kmsField               = "kms"
+	kmsFieldAWS            = "aws_kms"
+	kmsFieldGCP            = "gcp_kms"
+	kmsFieldAzure          = "azure_key_vault"
+	kmsFieldVault          = "vault_transit"
+	kmsFieldKeyID          = "key_id"
+	kmsFieldKeyName        = "key_name"
+	kmsFieldKeyVersion     = "key_version"
+	kmsFieldCredentialJSON = "credentials_json"
+	kmsFieldEndpoint       = "endpoint"
+	kmsFieldVaultURL       = "vault_url"
+	kmsFieldAlgorithm      = "algorithm"
+	kmsFieldURL            = "url"
+	kmsFieldToken          = "token"
+	kmsFieldMount          = "mount"
+	kmsFieldNamespace      = "namespace"
+	kmsFieldTLS            = "tls"
+)
+
+// keyWrapper encrypts and decrypts data keys with a key held by a KMS.
+type keyWrapper interface {
+	// wrapKey encrypts a data key and returns it along with the ID of the KMS
+	// key, including its version, that encrypted it.
+	wrapKey(ctx context.Context, dataKey []byte) (wrapped []byte, keyID string, err error)
+
+	// unwrapKey decrypts a data key that was encrypted by wrapKey.
+	unwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error)
+}
+
+func kmsConfigField() *service.ConfigField {
+	return service.NewObjectField(kmsField,
+		service.NewObjectField(kmsFieldAWS,
+			append([]*service.ConfigField{
+				service.NewStringField(kmsFieldKeyID).
+					Description("The ID, ARN or alias of the KMS key.").
+					Example("alias/my-key").
+					Example("arn:aws:kms:us-east-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			}, awsconfig.SessionFields()...)...,
+		).Description("Wrap data keys with an https://aws.amazon.com/kms/[AWS KMS^] key.").Optional(),
+		service.NewObjectField(kmsFieldGCP,
+			service.NewStringField(kmsFieldKeyName).
+				Description("The resource name of the Cloud KMS crypto key.").
+				Example("projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key"),
+			service.NewStringField(kmsFieldCredentialJSON).
+				Description("An optional field to set Google Service Account Credentials json.").
+				Default("").
+				Secret(),
+			service.NewURLField(kmsFieldEndpoint).
+				Description("The endpoint of the Cloud KMS API.").
+				Advanced().
+				Default("https://cloudkms.googleapis.com"),
+		).Description("Wrap data keys with a https://cloud.google.com/kms[Google Cloud KMS^] key.").Optional(),
+		service.NewObjectField(kmsFieldAzure,
+			service.NewURLField(kmsFieldVaultURL).
+				Description("The URL of the key vault, credentials are obtained via https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/sdk/azidentity#DefaultAzureCredential[DefaultAzureCredential^].").
+				Example("https://my-vault.vault.azure.net/"),
+			service.NewStringField(kmsFieldKeyName).
+				Description("The name of the key."),
+			service.NewStringField(kmsFieldKeyVersion).
+				Description("The version of the key used for wrapping data keys, the latest version is used when empty. Data keys are always unwrapped with the version that wrapped them.").
+				Default(""),
+			service.NewStringEnumField(kmsFieldAlgorithm, "RSA-OAEP-256", "RSA-OAEP", "A256KW").
+				Description("The key wrapping algorithm, `A256KW` requires a Managed HSM.").
+				Advanced().
+				Default("RSA-OAEP-256"),
+		).Description("Wrap data keys with an https://azure.microsoft.com/products/key-vault[Azure Key Vault^] key.").Optional(),
+		service.NewObjectField(kmsFieldVault,
+			service.NewURLField(kmsFieldURL).
+				Description("The address of the Vault server.").
+				Example("https://vault.example.com:8200"),
+			service.NewStringField(kmsFieldToken).
+				Description("The token used to authenticate with Vault.").
+				Secret(),
+			service.NewStringField(kmsFieldMount).
+				Description("The path at which the transit secrets engine is mounted.").
+				Default("transit"),
+			service.NewStringField(kmsFieldKeyName).
+				Description("The name of the transit key."),
+			service.NewStringField(kmsFieldNamespace).
+				Description("An optional Vault Enterprise namespace.").
+				Advanced().
+				Default(""),
+			service.NewTLSToggledField(kmsFieldTLS),
+		).Description("Wrap data keys with a https://developer.hashicorp.com/vault/docs/secrets/transit[Vault transit^] key.").Optional(),
+	).Description("The key management service holding the key that encrypts data keys, exactly one must be configured.")
+}
+
+func keyWrapperFromParsed(conf *service.ParsedConfig) (keyWrapper, error) {
+	kConf := conf.Namespace(kmsField)
+
+	var configured []string
+	for _, name := range []string{kmsFieldAWS, kmsFieldGCP, kmsFieldAzure, kmsFieldVault} {
+		if kConf.Contains(name) {
+			configured = append(configured, name)
+		}
+	}
+	if len(configured) != 1 {
+		return nil, fmt.Errorf("exactly one of %v, %v, %v or %v must be configured within %v", kmsFieldAWS, kmsFieldGCP, kmsFieldAzure, kmsFieldVault, kmsField)
+	}
+
+	pConf := kConf.Namespace(configured[0])
+	switch configured[0] {
+	case kmsFieldAWS:
+		return newAWSKeyWrapper(pConf)
+	case kmsFieldGCP:
+		return newGCPKeyWrapper(pConf)
+	case kmsFieldAzure:
+		return newAzureKeyWrapper(pConf)
+	case kmsFieldVault:
+		return newVaultKeyWrapper(pConf)
+	}
+	return nil, errors.New("unreachable")
+}
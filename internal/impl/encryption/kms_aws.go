@@ -0,0 +1,72 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	rpaws "github.com/redpanda-data/connect/v4/internal/impl/aws"
+)
+
+type awsKeyWrapper struct {
+	client *kms.Client
+	keyID  string
+}
+
+func newAWSKeyWrapper(conf *service.ParsedConfig) (*awsKeyWrapper, error) {
+	keyID, err := conf.FieldString(kmsFieldKeyID)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := rpaws.GetSession(context.Background(), conf)
+	if err != nil {
+		return nil, err
+	}
+	return &awsKeyWrapper{client: kms.NewFromConfig(sess), keyID: keyID}, nil
+}
+
+func (a *awsKeyWrapper) wrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	out, err := a.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     &a.keyID,
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return out.CiphertextBlob, aws.ToString(out.KeyId), nil
+}
+
+func (a *awsKeyWrapper) unwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	if keyID == "" {
+		keyID = a.keyID
+	}
+	out, err := a.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          &keyID,
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Plaintext == nil {
+		return nil, errors.New("empty data key returned by KMS")
+	}
+	return out.Plaintext, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type azureKeyWrapper struct {
+	client     *azkeys.Client
+	keyName    string
+	keyVersion string
+	algorithm  azkeys.EncryptionAlgorithm
+}
+
+func newAzureKeyWrapper(conf *service.ParsedConfig) (*azureKeyWrapper, error) {
+	vaultURL, err := conf.FieldString(kmsFieldVaultURL)
+	if err != nil {
+		return nil, err
+	}
+	w := &azureKeyWrapper{}
+	if w.keyName, err = conf.FieldString(kmsFieldKeyName); err != nil {
+		return nil, err
+	}
+	if w.keyVersion, err = conf.FieldString(kmsFieldKeyVersion); err != nil {
+		return nil, err
+	}
+	algorithm, err := conf.FieldString(kmsFieldAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	w.algorithm = azkeys.EncryptionAlgorithm(algorithm)
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain default credentials: %w", err)
+	}
+	if w.client, err = azkeys.NewClient(vaultURL, cred, nil); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (a *azureKeyWrapper) wrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	res, err := a.client.WrapKey(ctx, a.keyName, a.keyVersion, azkeys.KeyOperationParameters{
+		Algorithm: &a.algorithm,
+		Value:     dataKey,
+	}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	var keyID string
+	if res.KID != nil {
+		keyID = string(*res.KID)
+	}
+	return res.Result, keyID, nil
+}
+
+func (a *azureKeyWrapper) unwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	version := a.keyVersion
+	if keyID != "" {
+		kid := azkeys.ID(keyID)
+		version = kid.Version()
+	}
+	res, err := a.client.UnwrapKey(ctx, a.keyName, version, azkeys.KeyOperationParameters{
+		Algorithm: &a.algorithm,
+		Value:     wrapped,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Result, nil
+}
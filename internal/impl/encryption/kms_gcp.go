@@ -0,0 +1,128 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const gcpKMSScope = "https://www.googleapis.com/auth/cloudkms"
+
+// gcpKeyWrapper calls the Cloud KMS REST API directly, which avoids pulling
+// in the gRPC client for two calls.
+type gcpKeyWrapper struct {
+	client   *http.Client
+	endpoint string
+	keyName  string
+}
+
+func newGCPKeyWrapper(conf *service.ParsedConfig) (*gcpKeyWrapper, error) {
+	keyName, err := conf.FieldString(kmsFieldKeyName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := conf.FieldString(kmsFieldEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	credsJSON, err := conf.FieldString(kmsFieldCredentialJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	var client *http.Client
+	if credsJSON != "" {
+		creds, err := google.CredentialsFromJSON(ctx, []byte(credsJSON), gcpKMSScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse credentials: %w", err)
+		}
+		client = oauth2.NewClient(ctx, creds.TokenSource)
+	} else if client, err = google.DefaultClient(ctx, gcpKMSScope); err != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %w", err)
+	}
+	return newGCPKeyWrapperFromClient(client, endpoint, keyName), nil
+}
+
+func newGCPKeyWrapperFromClient(client *http.Client, endpoint, keyName string) *gcpKeyWrapper {
+	return &gcpKeyWrapper{
+		client:   client,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		keyName:  keyName,
+	}
+}
+
+func (g *gcpKeyWrapper) call(ctx context.Context, method string, reqBody, resBody any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint+"/v1/"+g.keyName+":"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("cloud KMS %v request failed with status %v: %s", method, res.StatusCode, bytes.TrimSpace(resBytes))
+	}
+	return json.Unmarshal(resBytes, resBody)
+}
+
+func (g *gcpKeyWrapper) wrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	var res struct {
+		Name       string `json:"name"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := g.call(ctx, "encrypt", map[string]any{"plaintext": dataKey}, &res); err != nil {
+		return nil, "", err
+	}
+	return res.Ciphertext, res.Name, nil
+}
+
+// The ciphertext of Cloud KMS identifies the key version that produced it, and
+// therefore decryption always targets the crypto key itself.
+func (g *gcpKeyWrapper) unwrapKey(ctx context.Context, wrapped []byte, _ string) ([]byte, error) {
+	var res struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	if err := g.call(ctx, "decrypt", map[string]any{"ciphertext": wrapped}, &res); err != nil {
+		return nil, err
+	}
+	return res.Plaintext, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type vaultKeyWrapper struct {
+	client    *http.Client
+	url       string
+	token     string
+	mount     string
+	keyName   string
+	namespace string
+}
+
+func newVaultKeyWrapper(conf *service.ParsedConfig) (*vaultKeyWrapper, error) {
+	w := &vaultKeyWrapper{}
+
+	var err error
+	if w.url, err = conf.FieldString(kmsFieldURL); err != nil {
+		return nil, err
+	}
+	w.url = strings.TrimSuffix(w.url, "/")
+	if w.token, err = conf.FieldString(kmsFieldToken); err != nil {
+		return nil, err
+	}
+	if w.mount, err = conf.FieldString(kmsFieldMount); err != nil {
+		return nil, err
+	}
+	w.mount = strings.Trim(w.mount, "/")
+	if w.keyName, err = conf.FieldString(kmsFieldKeyName); err != nil {
+		return nil, err
+	}
+	if w.namespace, err = conf.FieldString(kmsFieldNamespace); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(kmsFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	w.client = &http.Client{}
+	if tlsEnabled {
+		w.client.Transport = &http.Transport{TLSClientConfig: tlsConf}
+	}
+	return w, nil
+}
+
+func (v *vaultKeyWrapper) call(ctx context.Context, op string, reqBody, resData any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%v/v1/%v/%v/%v", v.url, v.mount, op, v.keyName), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit %v request failed with status %v: %s", op, res.StatusCode, bytes.TrimSpace(resBytes))
+	}
+	return json.Unmarshal(resBytes, &struct {
+		Data any `json:"data"`
+	}{Data: resData})
+}
+
+// Transit ciphertexts are strings of the form vault:v<version>:<base64>, which
+// are stored as is so that the key version is retained.
+func (v *vaultKeyWrapper) wrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	var data struct {
+		Ciphertext string `json:"ciphertext"`
+		KeyVersion int    `json:"key_version"`
+	}
+	if err := v.call(ctx, "encrypt", map[string]any{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &data); err != nil {
+		return nil, "", err
+	}
+	return []byte(data.Ciphertext), fmt.Sprintf("%v:v%v", v.keyName, data.KeyVersion), nil
+}
+
+func (v *vaultKeyWrapper) unwrapKey(ctx context.Context, wrapped []byte, _ string) ([]byte, error) {
+	var data struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := v.call(ctx, "decrypt", map[string]any{
+		"ciphertext": string(wrapped),
+	}, &data); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(data.Plaintext)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dpFieldCacheSize = "data_key_cache_size"
+)
+
+func decryptProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Summary("Decrypts messages encrypted by the `encrypt` processor.").
+		Description(`
+The data key of each envelope is decrypted with the configured key management service and cached, and therefore the KMS is only called once for each data key rather than once for each message. The ID of the KMS key that encrypted the data key is added to messages as the metadata field `+"`"+keyIDMetaKey+"`"+`.
+`).
+		Fields(
+			kmsConfigField(),
+			service.NewIntField(dpFieldCacheSize).
+				Description("The maximum number of decrypted data keys to cache.").
+				Advanced().
+				Default(1024),
+		).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"decrypt", decryptProcessorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			wrapper, err := keyWrapperFromParsed(conf)
+			if err != nil {
+				return nil, err
+			}
+			cacheSize, err := conf.FieldInt(dpFieldCacheSize)
+			if err != nil {
+				return nil, err
+			}
+			return newDecryptProcessor(wrapper, cacheSize)
+		})
+}
+
+type decryptProcessor struct {
+	wrapper keyWrapper
+	keys    *lru.Cache[string, cipher.AEAD]
+}
+
+func newDecryptProcessor(wrapper keyWrapper, cacheSize int) (*decryptProcessor, error) {
+	keys, err := lru.New[string, cipher.AEAD](cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptProcessor{wrapper: wrapper, keys: keys}, nil
+}
+
+func (d *decryptProcessor) aead(ctx context.Context, e *envelope) (cipher.AEAD, error) {
+	cacheKey := e.keyID + "\x00" + string(e.wrappedKey)
+	if aead, ok := d.keys.Get(cacheKey); ok {
+		return aead, nil
+	}
+
+	plainKey, err := d.wrapper.unwrapKey(ctx, e.wrappedKey, e.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	d.keys.Add(cacheKey, aead)
+	return aead, nil
+}
+
+func (d *decryptProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	e, header, err := unmarshalEnvelope(mBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := d.aead(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, e.nonce, e.ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %w", err)
+	}
+
+	msg.SetBytes(plaintext)
+	msg.MetaSetMut(keyIDMetaKey, e.keyID)
+	return service.MessageBatch{msg}, nil
+}
+
+func (*decryptProcessor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	epFieldRotationPeriod = "data_key_rotation_period"
+	epFieldMaxMessages    = "data_key_max_messages"
+
+	keyIDMetaKey = "encryption_key_id"
+)
+
+func encryptProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Summary("Encrypts messages with envelope encryption, where payloads are encrypted with AES-256-GCM data keys that are themselves encrypted by a key management service.").
+		Description(`
+Each message is replaced with an envelope containing the encrypted payload, the ID of the KMS key that encrypted the data key, and the encrypted data key itself, and can therefore be decrypted by the `+"`decrypt`"+` processor without any further context. The ID of the KMS key, including its version where the service reports one, is also added to messages as the metadata field `+"`"+keyIDMetaKey+"`"+`.
+
+== Key rotation
+
+A data key is reused for many messages in order to avoid calling the KMS for every message, and is rotated once it has been in use for `+"`"+epFieldRotationPeriod+"`"+` or has encrypted `+"`"+epFieldMaxMessages+"`"+` messages. New data keys are always encrypted with the current version of the KMS key, and therefore rotating the KMS key within the service takes effect with the next data key. Messages encrypted with older versions remain decryptable for as long as the service retains those versions.
+`).
+		Fields(
+			kmsConfigField(),
+			service.NewDurationField(epFieldRotationPeriod).
+				Description("The maximum period of time a data key is used for before a new one is generated.").
+				Default("1h"),
+			service.NewIntField(epFieldMaxMessages).
+				Description("The maximum number of messages encrypted with a data key before a new one is generated, which must remain far below 2^32 due to the random nonces of AES-GCM.").
+				Advanced().
+				Default(1000000),
+		).
+		Example("Vault transit", "Encrypt payloads with data keys wrapped by a Vault transit key.", `
+pipeline:
+  processors:
+    - encrypt:
+        kms:
+          vault_transit:
+            url: https://vault.example.com:8200
+            token: ${VAULT_TOKEN}
+            key_name: payloads
+`).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"encrypt", encryptProcessorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			wrapper, err := keyWrapperFromParsed(conf)
+			if err != nil {
+				return nil, err
+			}
+			rotationPeriod, err := conf.FieldDuration(epFieldRotationPeriod)
+			if err != nil {
+				return nil, err
+			}
+			maxMessages, err := conf.FieldInt(epFieldMaxMessages)
+			if err != nil {
+				return nil, err
+			}
+			return newEncryptProcessor(wrapper, rotationPeriod, maxMessages), nil
+		})
+}
+
+type dataKey struct {
+	aead       cipher.AEAD
+	wrappedKey []byte
+	keyID      string
+	expiresAt  time.Time
+	remaining  int
+}
+
+type encryptProcessor struct {
+	wrapper        keyWrapper
+	rotationPeriod time.Duration
+	maxMessages    int
+
+	mut     sync.Mutex
+	current *dataKey
+	nowFn   func() time.Time
+}
+
+func newEncryptProcessor(wrapper keyWrapper, rotationPeriod time.Duration, maxMessages int) *encryptProcessor {
+	return &encryptProcessor{
+		wrapper:        wrapper,
+		rotationPeriod: rotationPeriod,
+		maxMessages:    maxMessages,
+		nowFn:          time.Now,
+	}
+}
+
+// dataKey returns the current data key, generating a new one when the current
+// key has expired or been used for the maximum number of messages.
+func (e *encryptProcessor) dataKey(ctx context.Context) (*dataKey, error) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	if e.current != nil && e.current.remaining > 0 && e.nowFn().Before(e.current.expiresAt) {
+		e.current.remaining--
+		return e.current, nil
+	}
+
+	plainKey := make([]byte, dataKeyLen)
+	if _, err := rand.Read(plainKey); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, keyID, err := e.wrapper.wrapKey(ctx, plainKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data key: %w", err)
+	}
+
+	e.current = &dataKey{
+		aead:       aead,
+		wrappedKey: wrapped,
+		keyID:      keyID,
+		expiresAt:  e.nowFn().Add(e.rotationPeriod),
+		remaining:  e.maxMessages - 1,
+	}
+	return e.current, nil
+}
+
+func (e *encryptProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := e.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := seal(key.aead, key.keyID, key.wrappedKey, mBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	msg.SetBytes(b)
+	msg.MetaSetMut(keyIDMetaKey, key.keyID)
+	return service.MessageBatch{msg}, nil
+}
+
+func (*encryptProcessor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// xorKeyWrapper is a fake KMS that wraps keys by XORing them with a version
+// specific byte.
+type xorKeyWrapper struct {
+	mut     sync.Mutex
+	version int
+	wraps   int
+	unwraps int
+}
+
+func (x *xorKeyWrapper) wrapKey(_ context.Context, dataKey []byte) ([]byte, string, error) {
+	x.mut.Lock()
+	defer x.mut.Unlock()
+	x.wraps++
+
+	wrapped := make([]byte, len(dataKey))
+	for i, b := range dataKey {
+		wrapped[i] = b ^ byte(x.version)
+	}
+	return wrapped, fmt.Sprintf("test:v%v", x.version), nil
+}
+
+func (x *xorKeyWrapper) unwrapKey(_ context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	x.mut.Lock()
+	defer x.mut.Unlock()
+	x.unwraps++
+
+	var version int
+	if _, err := fmt.Sscanf(keyID, "test:v%d", &version); err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(wrapped))
+	for i, b := range wrapped {
+		plain[i] = b ^ byte(version)
+	}
+	return plain, nil
+}
+
+func processOne(t *testing.T, proc service.Processor, msg *service.Message) *service.Message {
+	t.Helper()
+
+	batch, err := proc.Process(t.Context(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	return batch[0]
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	wrapper := &xorKeyWrapper{version: 1}
+
+	enc := newEncryptProcessor(wrapper, time.Hour, 1000)
+	dec, err := newDecryptProcessor(wrapper, 10)
+	require.NoError(t, err)
+
+	var encrypted []*service.Message
+	for i := range 5 {
+		msg := processOne(t, enc, service.NewMessage(fmt.Appendf(nil, "hello world %v", i)))
+
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "hello world")
+
+		keyID, _ := msg.MetaGet(keyIDMetaKey)
+		assert.Equal(t, "test:v1", keyID)
+
+		encrypted = append(encrypted, msg)
+	}
+	assert.Equal(t, 1, wrapper.wraps)
+
+	for i, msg := range encrypted {
+		b, err := processOne(t, dec, msg.Copy()).AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("hello world %v", i), string(b))
+	}
+	assert.Equal(t, 1, wrapper.unwraps)
+}
+
+func TestEncryptDataKeyRotation(t *testing.T) {
+	wrapper := &xorKeyWrapper{version: 1}
+
+	now := time.Unix(0, 0)
+	enc := newEncryptProcessor(wrapper, time.Minute, 2)
+	enc.nowFn = func() time.Time { return now }
+
+	dec, err := newDecryptProcessor(wrapper, 10)
+	require.NoError(t, err)
+
+	var encrypted []*service.Message
+	encrypt := func() {
+		encrypted = append(encrypted, processOne(t, enc, service.NewMessage([]byte("foo"))))
+	}
+
+	// Rotated after the maximum number of messages.
+	encrypt()
+	encrypt()
+	assert.Equal(t, 1, wrapper.wraps)
+	encrypt()
+	assert.Equal(t, 2, wrapper.wraps)
+
+	// Rotated after the rotation period, with the new version of the KMS key.
+	wrapper.version = 2
+	now = now.Add(2 * time.Minute)
+	encrypt()
+	assert.Equal(t, 3, wrapper.wraps)
+
+	keyID, _ := encrypted[3].MetaGet(keyIDMetaKey)
+	assert.Equal(t, "test:v2", keyID)
+
+	for _, msg := range encrypted {
+		b, err := processOne(t, dec, msg).AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "foo", string(b))
+	}
+	assert.Equal(t, 3, wrapper.unwraps)
+}
+
+func TestDecryptTampered(t *testing.T) {
+	wrapper := &xorKeyWrapper{version: 1}
+
+	enc := newEncryptProcessor(wrapper, time.Hour, 1000)
+	dec, err := newDecryptProcessor(wrapper, 10)
+	require.NoError(t, err)
+
+	b, err := processOne(t, enc, service.NewMessage([]byte("foo"))).AsBytes()
+	require.NoError(t, err)
+
+	// Claim the data key was wrapped by a different key version.
+	tampered := strings.Replace(string(b), "test:v1", "test:v3", 1)
+	_, err = dec.Process(t.Context(), service.NewMessage([]byte(tampered)))
+	require.Error(t, err)
+
+	b[len(b)-1] ^= 0xff
+	_, err = dec.Process(t.Context(), service.NewMessage(b))
+	require.ErrorContains(t, err, "failed to decrypt message")
+
+	_, err = dec.Process(t.Context(), service.NewMessage([]byte("foo")))
+	require.ErrorContains(t, err, "not an encryption envelope")
+
+	_, err = dec.Process(t.Context(), service.NewMessage(b[:8]))
+	require.ErrorContains(t, err, "truncated")
+}
+
+func TestVaultTransit(t *testing.T) {
+	const transitKey = "AAECAwQFBgcICQoLDA0ODw=="
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/payloads":
+			_, _ = fmt.Fprintf(w, `{"data":{"ciphertext":"vault:v3:%v%v","key_version":3}}`, transitKey, req["plaintext"])
+		case "/v1/transit/decrypt/payloads":
+			plaintext, ok := strings.CutPrefix(req["ciphertext"], "vault:v3:"+transitKey)
+			if !ok {
+				http.Error(w, `{"errors":["invalid ciphertext"]}`, http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data":{"plaintext":%q}}`, plaintext)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	newProc := func(name string, spec *service.ConfigSpec, token string) service.Processor {
+		conf, err := spec.ParseYAML(`
+kms:
+  vault_transit:
+    url: `+ts.URL+`
+    token: `+token+`
+    key_name: payloads
+`, nil)
+		require.NoError(t, err)
+
+		wrapper, err := keyWrapperFromParsed(conf)
+		require.NoError(t, err)
+
+		if name == "encrypt" {
+			return newEncryptProcessor(wrapper, time.Hour, 10)
+		}
+		dec, err := newDecryptProcessor(wrapper, 10)
+		require.NoError(t, err)
+		return dec
+	}
+
+	msg := processOne(t, newProc("encrypt", encryptProcessorConfig(), "s.token"), service.NewMessage([]byte("hello world")))
+
+	keyID, _ := msg.MetaGet(keyIDMetaKey)
+	assert.Equal(t, "payloads:v3", keyID)
+
+	b, err := processOne(t, newProc("decrypt", decryptProcessorConfig(), "s.token"), msg.Copy()).AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	_, err = newProc("decrypt", decryptProcessorConfig(), "s.wrong").Process(t.Context(), msg)
+	require.ErrorContains(t, err, "status 403")
+}
+
+func TestGCPKMS(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string][]byte
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch r.URL.Path {
+		case "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:encrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"name":       "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/2",
+				"ciphertext": append([]byte("wrapped:"), req["plaintext"]...),
+			})
+		case "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:decrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"plaintext": req["ciphertext"][len("wrapped:"):],
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	wrapper := newGCPKeyWrapperFromClient(ts.Client(), ts.URL+"/", "projects/p/locations/global/keyRings/r/cryptoKeys/k")
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, keyID, err := wrapper.wrapKey(t.Context(), dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/2", keyID)
+	assert.Equal(t, "wrapped:"+string(dataKey), string(wrapped))
+
+	unwrapped, err := wrapper.unwrapKey(t.Context(), wrapped, keyID)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+}
+
+func TestKMSConfigExactlyOne(t *testing.T) {
+	conf, err := encryptProcessorConfig().ParseYAML(`
+kms: {}
+`, nil)
+	require.NoError(t, err)
+
+	_, err = keyWrapperFromParsed(conf)
+	require.ErrorContains(t, err, "exactly one of")
+}
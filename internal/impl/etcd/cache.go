@@ -0,0 +1,262 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ecFieldEndpoints   = "endpoints"
+	ecFieldPrefix      = "prefix"
+	ecFieldDefaultTTL  = "default_ttl"
+	ecFieldUsername    = "username"
+	ecFieldPassword    = "password"
+	ecFieldTLS         = "tls"
+	ecFieldDialTimeout = "dial_timeout"
+)
+
+func etcdCacheConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Summary(`Use an etcd cluster as a cache.`).
+		Description(`
+Items are stored as etcd keys, optionally prefixed in order to allow multiple caches to share a cluster under different namespaces.
+
+== TTLs
+
+Items with a TTL are attached to an etcd https://etcd.io/docs/latest/learning/api/#lease-api[lease^] that is granted with the TTL when the item is written, and etcd removes the item once the lease expires. Lease TTLs have a granularity of one second and are rounded up to the next second. Items without a TTL never expire.
+
+== Compare and swap
+
+The `+"`add`"+` operation, which is used for example by the `+"`dedupe`"+` processor, is performed within a transaction that only writes the item when the key does not exist at that revision. This guarantees that only one of several concurrent writers of a key succeeds, even across separate processes sharing the cluster.`).
+		Fields(
+			service.NewStringListField(ecFieldEndpoints).
+				Description("A list of etcd endpoints to connect to.").
+				Example([]string{"localhost:2379"}).
+				Example([]string{"https://etcd-0.etcd:2379", "https://etcd-1.etcd:2379"}),
+			service.NewStringField(ecFieldPrefix).
+				Description("An optional string to prefix item keys with in order to prevent collisions with similar services.").
+				Example("/redpanda-connect/dedupe/").
+				Optional(),
+			service.NewDurationField(ecFieldDefaultTTL).
+				Description("An optional default TTL to set for items, calculated from the moment the item is cached.").
+				Example("60s").
+				Optional(),
+			service.NewStringField(ecFieldUsername).
+				Description("An optional username to authenticate with.").
+				Default("").
+				Advanced(),
+			service.NewStringField(ecFieldPassword).
+				Description("An optional password to authenticate with.").
+				Default("").
+				Secret().
+				Advanced(),
+			service.NewTLSToggledField(ecFieldTLS),
+			service.NewDurationField(ecFieldDialTimeout).
+				Description("The timeout for establishing a connection to the cluster.").
+				Default("5s").
+				Advanced(),
+		).
+		Example("Deduplication", "Deduplicate messages across instances of a pipeline that share an etcd cluster.", `
+pipeline:
+  processors:
+    - dedupe:
+        cache: etcd
+        key: ${! @id }
+
+cache_resources:
+  - label: etcd
+    etcd:
+      endpoints: [ localhost:2379 ]
+      prefix: /dedupe/
+      default_ttl: 1h
+`)
+}
+
+func init() {
+	service.MustRegisterCache(
+		"etcd", etcdCacheConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Cache, error) {
+			return newEtcdCacheFromConfig(conf)
+		})
+}
+
+func newEtcdCacheFromConfig(conf *service.ParsedConfig) (*etcdCache, error) {
+	inEndpoints, err := conf.FieldStringList(ecFieldEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []string
+	for _, e := range inEndpoints {
+		for splitEndpoint := range strings.SplitSeq(e, ",") {
+			if trimmed := strings.TrimSpace(splitEndpoint); trimmed != "" {
+				endpoints = append(endpoints, trimmed)
+			}
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint must be specified")
+	}
+
+	var prefix string
+	if conf.Contains(ecFieldPrefix) {
+		if prefix, err = conf.FieldString(ecFieldPrefix); err != nil {
+			return nil, err
+		}
+	}
+
+	var defaultTTL *time.Duration
+	if conf.Contains(ecFieldDefaultTTL) {
+		ttl, err := conf.FieldDuration(ecFieldDefaultTTL)
+		if err != nil {
+			return nil, err
+		}
+		defaultTTL = &ttl
+	}
+
+	clientConf := clientv3.Config{
+		Endpoints: endpoints,
+		Logger:    zap.NewNop(),
+	}
+	if clientConf.Username, err = conf.FieldString(ecFieldUsername); err != nil {
+		return nil, err
+	}
+	if clientConf.Password, err = conf.FieldString(ecFieldPassword); err != nil {
+		return nil, err
+	}
+	if clientConf.DialTimeout, err = conf.FieldDuration(ecFieldDialTimeout); err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(ecFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		clientConf.TLS = tlsConf
+	}
+
+	client, err := clientv3.New(clientConf)
+	if err != nil {
+		return nil, err
+	}
+	return newEtcdCache(client, prefix, defaultTTL), nil
+}
+
+//------------------------------------------------------------------------------
+
+type etcdCache struct {
+	client     *clientv3.Client
+	prefix     string
+	defaultTTL *time.Duration
+}
+
+func newEtcdCache(client *clientv3.Client, prefix string, defaultTTL *time.Duration) *etcdCache {
+	return &etcdCache{
+		client:     client,
+		prefix:     prefix,
+		defaultTTL: defaultTTL,
+	}
+}
+
+// leaseTTLSeconds converts a TTL into the number of seconds of a lease, which
+// is rounded up as leases have a granularity of one second.
+func leaseTTLSeconds(ttl time.Duration) int64 {
+	secs := int64((ttl + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
+
+// grantLease grants a lease for an item when it has a TTL, otherwise
+// clientv3.NoLease is returned.
+func (e *etcdCache) grantLease(ctx context.Context, ttl *time.Duration) (clientv3.LeaseID, error) {
+	if ttl == nil {
+		ttl = e.defaultTTL
+	}
+	if ttl == nil {
+		return clientv3.NoLease, nil
+	}
+	lease, err := e.client.Grant(ctx, leaseTTLSeconds(*ttl))
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	return lease.ID, nil
+}
+
+func (e *etcdCache) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := e.client.Get(ctx, e.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Kvs) == 0 {
+		return nil, service.ErrKeyNotFound
+	}
+	return res.Kvs[0].Value, nil
+}
+
+func (e *etcdCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	leaseID, err := e.grantLease(ctx, ttl)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, e.prefix+key, string(value), clientv3.WithLease(leaseID))
+	return err
+}
+
+// Add writes the item within a transaction that only succeeds when the key
+// does not already exist.
+func (e *etcdCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	leaseID, err := e.grantLease(ctx, ttl)
+	if err != nil {
+		return err
+	}
+
+	k := e.prefix + key
+	res, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(k), "=", 0)).
+		Then(clientv3.OpPut(k, string(value), clientv3.WithLease(leaseID))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !res.Succeeded {
+		if leaseID != clientv3.NoLease {
+			// The lease granted for the item is unused.
+			_, _ = e.client.Revoke(ctx, leaseID)
+		}
+		return service.ErrKeyAlreadyExists
+	}
+	return nil
+}
+
+func (e *etcdCache) Delete(ctx context.Context, key string) error {
+	_, err := e.client.Delete(ctx, e.prefix+key)
+	return err
+}
+
+func (e *etcdCache) Close(context.Context) error {
+	return e.client.Close()
+}
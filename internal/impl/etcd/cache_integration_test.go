@@ -0,0 +1,119 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/redpanda-data/benthos/v4/public/service/integration"
+)
+
+func TestIntegrationEtcdCache(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "quay.io/coreos/etcd",
+		Tag:        "v3.6.5",
+		Cmd: []string{
+			"etcd",
+			"--listen-client-urls=http://0.0.0.0:2379",
+			"--advertise-client-urls=http://0.0.0.0:2379",
+		},
+		ExposedPorts: []string{"2379/tcp"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	endpoint := fmt.Sprintf("localhost:%v", resource.GetPort("2379/tcp"))
+
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		client, cErr := clientv3.New(clientv3.Config{
+			Endpoints:   []string{endpoint},
+			DialTimeout: time.Second,
+		})
+		if cErr != nil {
+			return cErr
+		}
+		defer client.Close()
+
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		_, cErr = client.Put(ctx, "testkey", "testvalue")
+		return cErr
+	}))
+
+	template := `
+cache_resources:
+  - label: testcache
+    etcd:
+      endpoints: [ localhost:$PORT ]
+      prefix: $ID
+`
+	suite := integration.CacheTests(
+		integration.CacheTestOpenClose(),
+		integration.CacheTestMissingKey(),
+		integration.CacheTestDoubleAdd(),
+		integration.CacheTestDelete(),
+		integration.CacheTestGetAndSet(50),
+	)
+	suite.Run(
+		t, template,
+		integration.CacheTestOptPort(resource.GetPort("2379/tcp")),
+	)
+
+	t.Run("ttl", func(t *testing.T) {
+		pConf, err := etcdCacheConfig().ParseYAML(fmt.Sprintf(`
+endpoints: [ %v ]
+prefix: ttl-
+`, endpoint), nil)
+		require.NoError(t, err)
+
+		cache, err := newEtcdCacheFromConfig(pConf)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = cache.Close(context.Background())
+		})
+
+		ttl := time.Second
+		require.NoError(t, cache.Set(t.Context(), "foo", []byte("bar"), &ttl))
+
+		v, err := cache.Get(t.Context(), "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "bar", string(v))
+
+		assert.Eventually(t, func() bool {
+			_, err := cache.Get(t.Context(), "foo")
+			return err == service.ErrKeyNotFound
+		}, time.Second*10, time.Millisecond*100)
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseTTLSeconds(t *testing.T) {
+	for _, test := range []struct {
+		ttl  time.Duration
+		secs int64
+	}{
+		{ttl: 0, secs: 1},
+		{ttl: time.Millisecond, secs: 1},
+		{ttl: time.Second, secs: 1},
+		{ttl: time.Second + time.Millisecond, secs: 2},
+		{ttl: time.Hour, secs: 3600},
+	} {
+		assert.Equal(t, test.secs, leaseTTLSeconds(test.ttl), test.ttl.String())
+	}
+}
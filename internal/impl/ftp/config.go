@@ -0,0 +1,214 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"sync"
+
+	"github.com/jlaffaye/ftp"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	fFieldAddress             = "address"
+	fFieldConnectionTimeout   = "connection_timeout"
+	fFieldCredentials         = "credentials"
+	fFieldCredentialsUsername = "username"
+	fFieldCredentialsPassword = "password"
+	fFieldExplicitTLS         = "explicit_tls"
+	fFieldDisableEPSV         = "disable_epsv"
+	fFieldMaxConnections      = "max_connections"
+	fFieldTLS                 = "tls"
+)
+
+func connectionFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(fFieldAddress).
+			Description("The address of the server to connect to.").
+			Example("ftp.example.com:21"),
+		service.NewDurationField(fFieldConnectionTimeout).
+			Description("The connection timeout to use when connecting to the target server.").
+			Default("30s").
+			Advanced(),
+		service.NewObjectField(fFieldCredentials,
+			service.NewStringField(fFieldCredentialsUsername).Description("The username to authenticate with the FTP server.").Default("anonymous"),
+			service.NewStringField(fFieldCredentialsPassword).Description("The password for the specified username to connect to the FTP server.").Secret().Default(""),
+		).Description("The credentials to use to log into the target server."),
+		service.NewTLSToggledField(fFieldTLS),
+		service.NewBoolField(fFieldExplicitTLS).
+			Description("Whether to upgrade a plain connection with the `AUTH TLS` command when `tls` is enabled, as with port 21, rather than encrypting the connection from the start, as with port 990.").
+			Default(true).
+			Advanced(),
+		service.NewBoolField(fFieldDisableEPSV).
+			Description("Whether to disable extended passive mode, which some servers and firewalls do not support.").
+			Default(false).
+			Advanced(),
+		service.NewIntField(fFieldMaxConnections).
+			Description("The maximum number of connections to the server. Each connection supports a single transfer at a time.").
+			Default(5).
+			Advanced(),
+	}
+}
+
+// connPool holds connections to an FTP server, as each connection can only
+// serve one command or transfer at a time.
+type connPool struct {
+	address  string
+	username string
+	password string
+	options  []ftp.DialOption
+
+	sem  chan struct{}
+	mut  sync.Mutex
+	idle []*ftp.ServerConn
+}
+
+func connPoolFromParsed(conf *service.ParsedConfig) (*connPool, error) {
+	p := &connPool{}
+
+	var err error
+	if p.address, err = conf.FieldString(fFieldAddress); err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(fFieldConnectionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	p.options = append(p.options, ftp.DialWithTimeout(timeout))
+
+	credsConf := conf.Namespace(fFieldCredentials)
+	if p.username, err = credsConf.FieldString(fFieldCredentialsUsername); err != nil {
+		return nil, err
+	}
+	if p.password, err = credsConf.FieldString(fFieldCredentialsPassword); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(fFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		explicit, err := conf.FieldBool(fFieldExplicitTLS)
+		if err != nil {
+			return nil, err
+		}
+		if explicit {
+			p.options = append(p.options, ftp.DialWithExplicitTLS(tlsConf))
+		} else {
+			p.options = append(p.options, ftp.DialWithTLS(tlsConf))
+		}
+	}
+
+	disableEPSV, err := conf.FieldBool(fFieldDisableEPSV)
+	if err != nil {
+		return nil, err
+	}
+	p.options = append(p.options, ftp.DialWithDisabledEPSV(disableEPSV))
+
+	maxConns, err := conf.FieldInt(fFieldMaxConnections)
+	if err != nil {
+		return nil, err
+	}
+	if maxConns < 1 {
+		return nil, fmt.Errorf("field %v must be at least 1", fFieldMaxConnections)
+	}
+	p.sem = make(chan struct{}, maxConns)
+	return p, nil
+}
+
+// acquire returns an idle connection or dials a new one, blocking until fewer
+// than the maximum number of connections are in use.
+func (p *connPool) acquire(ctx context.Context) (*ftp.ServerConn, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mut.Lock()
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mut.Unlock()
+		return c, nil
+	}
+	p.mut.Unlock()
+
+	c, err := p.dial(ctx)
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+	return c, nil
+}
+
+func (p *connPool) dial(ctx context.Context) (*ftp.ServerConn, error) {
+	c, err := ftp.Dial(p.address, append(p.options, ftp.DialWithContext(ctx))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to FTP server: %w", err)
+	}
+	if err := c.Login(p.username, p.password); err != nil {
+		_ = c.Quit()
+		return nil, fmt.Errorf("failed to log into FTP server: %w", err)
+	}
+	return c, nil
+}
+
+// release returns a connection to the pool, unless the error of the last
+// operation indicates that the connection is broken, in which case it is
+// closed.
+func (p *connPool) release(c *ftp.ServerConn, err error) {
+	if isConnError(err) {
+		_ = c.Quit()
+	} else {
+		p.mut.Lock()
+		p.idle = append(p.idle, c)
+		p.mut.Unlock()
+	}
+	<-p.sem
+}
+
+// close closes all idle connections.
+func (p *connPool) close() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	for _, c := range p.idle {
+		_ = c.Quit()
+	}
+	p.idle = nil
+}
+
+// isConnError returns whether an error is caused by the connection rather
+// than being a response of the server, such as a missing file.
+func isConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var tpErr *textproto.Error
+	return !errors.As(err, &tpErr)
+}
+
+// isNotFound returns whether an error is a response of the server indicating
+// that a file is unavailable.
+func isNotFound(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code == ftp.StatusFileUnavailable
+}
@@ -0,0 +1,225 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ftp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ftpserver "goftp.io/server/v2"
+	"goftp.io/server/v2/driver/file"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+// appendDriver works around the test server truncating files on APPE unless
+// it is preceded by a REST command.
+type appendDriver struct {
+	ftpserver.Driver
+}
+
+func (d appendDriver) PutFile(ctx *ftpserver.Context, destPath string, data io.Reader, offset int64) (int64, error) {
+	if ctx.Cmd == "APPE" && offset < 0 {
+		offset = 0
+	}
+	return d.Driver.PutFile(ctx, destPath, data, offset)
+}
+
+func startFTPServer(t *testing.T) (root, address string) {
+	t.Helper()
+
+	root = t.TempDir()
+	driver, err := file.NewDriver(root)
+	require.NoError(t, err)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv, err := ftpserver.NewServer(&ftpserver.Options{
+		Driver:   appendDriver{driver},
+		Auth:     &ftpserver.SimpleAuth{Name: "user", Password: "pass"},
+		Perm:     ftpserver.NewSimplePerm("user", "user"),
+		Hostname: "127.0.0.1",
+		PublicIP: "127.0.0.1",
+		Port:     l.Addr().(*net.TCPAddr).Port,
+		Logger:   &ftpserver.DiscardLogger{},
+	})
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return root, l.Addr().String()
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for p, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+}
+
+func TestGlob(t *testing.T) {
+	root, address := startFTPServer(t)
+	writeFiles(t, root, map[string]string{
+		"in/a.csv":        "a",
+		"in/b.csv":        "b",
+		"in/c.txt":        "c",
+		"in/x/d.csv":      "d",
+		"in/y/e.csv":      "e",
+		"other/f.csv":     "f",
+		"in/nested/g.csv": "g",
+	})
+
+	pConf, err := service.NewConfigSpec().Fields(connectionFields()...).ParseYAML(`
+address: `+address+`
+credentials:
+  username: user
+  password: pass
+`, nil)
+	require.NoError(t, err)
+	pool, err := connPoolFromParsed(pConf)
+	require.NoError(t, err)
+	t.Cleanup(pool.close)
+
+	c, err := pool.acquire(t.Context())
+	require.NoError(t, err)
+	defer pool.release(c, nil)
+
+	for _, test := range []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "/in/*.csv", expected: []string{"/in/a.csv", "/in/b.csv"}},
+		{pattern: "/in/?/*.csv", expected: []string{"/in/x/d.csv", "/in/y/e.csv"}},
+		{pattern: "/*/*.csv", expected: []string{"/in/a.csv", "/in/b.csv", "/other/f.csv"}},
+		{pattern: "/in/c.txt", expected: []string{"/in/c.txt"}},
+		{pattern: "/in/missing.txt"},
+		{pattern: "/missing/*.csv"},
+	} {
+		t.Run(test.pattern, func(t *testing.T) {
+			files, err := glob(c, test.pattern)
+			require.NoError(t, err)
+
+			var paths []string
+			for _, f := range files {
+				paths = append(paths, f.path)
+			}
+			sort.Strings(paths)
+			assert.Equal(t, test.expected, paths)
+		})
+	}
+}
+
+func TestInputMoveOnFinish(t *testing.T) {
+	root, address := startFTPServer(t)
+	writeFiles(t, root, map[string]string{
+		"in/a.txt":      "a1\na2\n",
+		"in/b.txt":      "b1\n",
+		"archive/.keep": "",
+	})
+
+	pConf, err := ftpInputSpec().ParseYAML(`
+address: `+address+`
+credentials:
+  username: user
+  password: pass
+paths: [ /in/*.txt ]
+scanner:
+  lines: {}
+move_on_finish: /archive
+`, nil)
+	require.NoError(t, err)
+
+	r, err := newFTPReaderFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, r.Connect(t.Context()))
+
+	var lines []string
+	for {
+		batch, ackFn, err := r.ReadBatch(t.Context())
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+		require.NoError(t, err)
+		for _, msg := range batch {
+			b, err := msg.AsBytes()
+			require.NoError(t, err)
+			p, _ := msg.MetaGet("ftp_path")
+			lines = append(lines, p+":"+string(b))
+		}
+		require.NoError(t, ackFn(t.Context(), nil))
+	}
+	require.NoError(t, r.Close(t.Context()))
+
+	assert.Equal(t, []string{"/in/a.txt:a1", "/in/a.txt:a2", "/in/b.txt:b1"}, lines)
+
+	remaining, err := filepath.Glob(filepath.Join(root, "in", "*"))
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	archived, err := os.ReadFile(filepath.Join(root, "archive", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a1\na2\n", string(archived))
+}
+
+func TestOutputCodecs(t *testing.T) {
+	root, address := startFTPServer(t)
+
+	for _, test := range []struct {
+		codec    string
+		expected string
+	}{
+		{codec: "all-bytes", expected: "bar"},
+		{codec: "lines", expected: "foo\nbar\n"},
+	} {
+		t.Run(test.codec, func(t *testing.T) {
+			pConf, err := ftpOutputSpec().ParseYAML(`
+address: `+address+`
+credentials:
+  username: user
+  password: pass
+path: /out/${! @dir }/data.txt
+codec: `+test.codec+`
+`, nil)
+			require.NoError(t, err)
+
+			w, err := newWriterFromParsed(pConf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, w.Connect(t.Context()))
+
+			for _, content := range []string{"foo", "bar"} {
+				msg := service.NewMessage([]byte(content))
+				msg.MetaSetMut("dir", test.codec)
+				require.NoError(t, w.Write(t.Context(), msg))
+			}
+			require.NoError(t, w.Close(t.Context()))
+
+			b, err := os.ReadFile(filepath.Join(root, "out", test.codec, "data.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(b))
+		})
+	}
+}
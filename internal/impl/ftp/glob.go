@@ -0,0 +1,118 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ftp
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+type fileInfo struct {
+	path    string
+	modTime time.Time
+}
+
+func hasMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// glob returns the files matching a pattern, where directories are listed
+// level by level as FTP servers do not expand patterns themselves.
+func glob(c *ftp.ServerConn, pattern string) ([]fileInfo, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	dirPart, file := path.Split(pattern)
+	dir := path.Clean(dirPart)
+
+	dirs := []string{dir}
+	if hasMeta(dir) {
+		// Directory levels containing patterns are expanded first.
+		matches, err := globDirs(c, dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs = matches
+	}
+
+	var files []fileInfo
+	for _, d := range dirs {
+		entries, err := c.List(d)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type != ftp.EntryTypeFile {
+				continue
+			}
+			name := path.Base(e.Name)
+			if ok, _ := path.Match(file, name); !ok {
+				continue
+			}
+			p := name
+			if dirPart != "" {
+				p = path.Join(d, name)
+			}
+			files = append(files, fileInfo{path: p, modTime: e.Time})
+		}
+	}
+	return files, nil
+}
+
+// globDirs returns the directories matching a pattern.
+func globDirs(c *ftp.ServerConn, pattern string) ([]string, error) {
+	parent, name := path.Split(pattern)
+	parent = path.Clean(parent)
+
+	parents := []string{parent}
+	if hasMeta(parent) {
+		var err error
+		if parents, err = globDirs(c, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	var dirs []string
+	for _, p := range parents {
+		if !hasMeta(name) {
+			dirs = append(dirs, path.Join(p, name))
+			continue
+		}
+		entries, err := c.List(p)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			n := path.Base(e.Name)
+			if e.Type != ftp.EntryTypeFolder || n == "." || n == ".." {
+				continue
+			}
+			if ok, _ := path.Match(name, n); ok {
+				dirs = append(dirs, path.Join(p, n))
+			}
+		}
+	}
+	return dirs, nil
+}
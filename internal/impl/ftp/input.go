@@ -0,0 +1,547 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/redpanda-data/benthos/v4/public/service/codec"
+)
+
+const (
+	fiFieldPaths               = "paths"
+	fiFieldDeleteOnFinish      = "delete_on_finish"
+	fiFieldMoveOnFinish        = "move_on_finish"
+	fiFieldWatcher             = "watcher"
+	fiFieldWatcherEnabled      = "enabled"
+	fiFieldWatcherMinimumAge   = "minimum_age"
+	fiFieldWatcherPollInterval = "poll_interval"
+	fiFieldWatcherCache        = "cache"
+)
+
+func ftpInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.62.0").
+		Summary(`Consumes files from an FTP or FTPS server.`).
+		Description(`
+FTPS is enabled with the `+"`tls`"+` field. Since each connection to an FTP server can only transfer a single file at a time, files are read and removed or moved once processed over a pool of up to `+"`"+fFieldMaxConnections+"`"+` connections.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+- ftp_path
+- ftp_mod_time
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(connectionFields()...).
+		Fields(
+			service.NewStringListField(fiFieldPaths).
+				Description("A list of paths to consume sequentially. Glob patterns are supported."),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Fields(codec.DeprecatedCodecFields("to_the_end")...).
+		Fields(
+			service.NewBoolField(fiFieldDeleteOnFinish).
+				Description("Whether to delete files from the server once they are processed.").
+				Advanced().
+				Default(false),
+			service.NewStringField(fiFieldMoveOnFinish).
+				Description("An optional directory to move files to once they are processed, which must exist on the server.").
+				Example("/processed").
+				Advanced().
+				Default(""),
+			service.NewObjectField(fiFieldWatcher,
+				service.NewBoolField(fiFieldWatcherEnabled).
+					Description("Whether file watching is enabled.").
+					Default(false),
+				service.NewDurationField(fiFieldWatcherMinimumAge).
+					Description("The minimum period of time since a file was last updated before attempting to consume it. Increasing this period decreases the likelihood that a file will be consumed whilst it is still being written to.").
+					Default("1s").
+					Examples("10s", "1m", "10m"),
+				service.NewDurationField(fiFieldWatcherPollInterval).
+					Description("The interval between each attempt to scan the target paths for new files.").
+					Default("1s").
+					Examples("100ms", "1s"),
+				service.NewStringField(fiFieldWatcherCache).
+					Description("A xref:components:caches/about.adoc[cache resource] for storing the paths of files already consumed.").
+					Default(""),
+			).Description("A mode whereby the input will periodically scan the target paths for new files and consume them, when all files are consumed the input will continue polling for new files."),
+		).
+		LintRule(`root = if this.delete_on_finish.or(false) && this.move_on_finish.or("") != "" { "delete_on_finish and move_on_finish can't be set simultaneously" }`).
+		Example("Drain a partner drop box", "Consume CSV files uploaded to an FTPS server, moving each file to an archive directory once its rows have been processed.", `
+input:
+  ftp:
+    address: ftp.partner.example.com:21
+    credentials:
+      username: acme
+      password: ${FTP_PASSWORD}
+    tls:
+      enabled: true
+    paths: [ /outgoing/*.csv ]
+    scanner:
+      csv: {}
+    move_on_finish: /archive
+`)
+}
+
+func init() {
+	service.MustRegisterBatchInput("ftp", ftpInputSpec(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+		r, err := newFTPReaderFromParsed(conf, mgr)
+		if err != nil {
+			return nil, err
+		}
+		return service.AutoRetryNacksBatchedToggled(conf, r)
+	})
+}
+
+//------------------------------------------------------------------------------
+
+type ftpReader struct {
+	log *service.Logger
+	mgr *service.Resources
+
+	pool           *connPool
+	paths          []string
+	scannerCtor    codec.DeprecatedFallbackCodec
+	deleteOnFinish bool
+	moveOnFinish   string
+
+	watcherEnabled      bool
+	watcherCache        string
+	watcherPollInterval time.Duration
+	watcherMinAge       time.Duration
+
+	stateLock       sync.Mutex
+	connected       bool
+	scanner         codec.DeprecatedFallbackStream
+	currentFileInfo fileInfo
+	pathProvider    pathProvider
+}
+
+func newFTPReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (f *ftpReader, err error) {
+	f = &ftpReader{
+		log: mgr.Logger(),
+		mgr: mgr,
+	}
+
+	if f.pool, err = connPoolFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if f.paths, err = conf.FieldStringList(fiFieldPaths); err != nil {
+		return nil, err
+	}
+	if f.scannerCtor, err = codec.DeprecatedCodecFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if f.deleteOnFinish, err = conf.FieldBool(fiFieldDeleteOnFinish); err != nil {
+		return nil, err
+	}
+	if f.moveOnFinish, err = conf.FieldString(fiFieldMoveOnFinish); err != nil {
+		return nil, err
+	}
+	if f.deleteOnFinish && f.moveOnFinish != "" {
+		return nil, fmt.Errorf("fields %v and %v cannot be set simultaneously", fiFieldDeleteOnFinish, fiFieldMoveOnFinish)
+	}
+
+	wConf := conf.Namespace(fiFieldWatcher)
+	if f.watcherEnabled, _ = wConf.FieldBool(fiFieldWatcherEnabled); f.watcherEnabled {
+		if f.watcherCache, err = wConf.FieldString(fiFieldWatcherCache); err != nil {
+			return nil, err
+		}
+		if f.watcherPollInterval, err = wConf.FieldDuration(fiFieldWatcherPollInterval); err != nil {
+			return nil, err
+		}
+		if f.watcherMinAge, err = wConf.FieldDuration(fiFieldWatcherMinimumAge); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(f.watcherCache) {
+			return nil, fmt.Errorf("cache resource %q was not found", f.watcherCache)
+		}
+	}
+	return f, nil
+}
+
+func (f *ftpReader) Connect(ctx context.Context) error {
+	f.stateLock.Lock()
+	defer f.stateLock.Unlock()
+
+	if f.connected {
+		return nil
+	}
+
+	// Verify that the server is reachable with the configured credentials.
+	c, err := f.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if f.watcherEnabled {
+		f.pool.release(c, nil)
+		if f.pathProvider == nil {
+			f.pathProvider = &watcherPathProvider{
+				pool:         f.pool,
+				mgr:          f.mgr,
+				cacheName:    f.watcherCache,
+				pollInterval: f.watcherPollInterval,
+				minAge:       f.watcherMinAge,
+				targetPaths:  f.paths,
+			}
+		}
+		f.connected = true
+		return nil
+	}
+
+	if f.pathProvider == nil {
+		spp := &staticPathProvider{}
+		for _, p := range f.paths {
+			files, err := glob(c, p)
+			if err != nil {
+				if isConnError(err) {
+					f.pool.release(c, err)
+					return err
+				}
+				f.log.Warnf("Failed to scan files from path %v: %s", p, err)
+				continue
+			}
+			spp.files = append(spp.files, files...)
+		}
+		f.pathProvider = spp
+	}
+	f.pool.release(c, nil)
+	f.connected = true
+	return nil
+}
+
+func (f *ftpReader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		scanner, err := f.initScanner(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		parts, codecAckFn, err := scanner.NextBatch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			f.closeScanner(ctx)
+			if errors.Is(err, io.EOF) {
+				continue
+			}
+			if isConnError(err) {
+				f.stateLock.Lock()
+				f.connected = false
+				f.stateLock.Unlock()
+				return nil, nil, service.ErrNotConnected
+			}
+			return nil, nil, err
+		}
+
+		f.stateLock.Lock()
+		info := f.currentFileInfo
+		f.stateLock.Unlock()
+		for _, part := range parts {
+			part.MetaSetMut("ftp_path", info.path)
+			part.MetaSetMut("ftp_mod_time", info.modTime)
+		}
+		return parts, codecAckFn, nil
+	}
+}
+
+func (f *ftpReader) closeScanner(ctx context.Context) {
+	f.stateLock.Lock()
+	scanner := f.scanner
+	f.scanner = nil
+	f.stateLock.Unlock()
+
+	if scanner != nil {
+		if err := scanner.Close(ctx); err != nil {
+			f.log.With("error", err).Error("Failed to close scanner")
+		}
+	}
+}
+
+// ftpFile is a file being retrieved, which holds a connection of the pool
+// until it is closed.
+type ftpFile struct {
+	res  *ftp.Response
+	pool *connPool
+	conn *ftp.ServerConn
+	err  error
+}
+
+func (o *ftpFile) Read(p []byte) (int, error) {
+	n, err := o.res.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		o.err = err
+	}
+	return n, err
+}
+
+func (o *ftpFile) Close() error {
+	if o.res == nil {
+		return nil
+	}
+	err := o.res.Close()
+	o.res = nil // Prevent double close
+
+	if o.err == nil {
+		o.err = err
+	}
+	o.pool.release(o.conn, o.err)
+	return err
+}
+
+func (f *ftpReader) initScanner(ctx context.Context) (codec.DeprecatedFallbackStream, error) {
+	f.stateLock.Lock()
+	scanner := f.scanner
+	connected := f.connected
+	f.stateLock.Unlock()
+	if scanner != nil {
+		return scanner, nil
+	}
+	if !connected {
+		return nil, service.ErrNotConnected
+	}
+
+	for {
+		info, ok, err := f.pathProvider.Next(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("finding next file path: %w", err)
+		}
+		if !ok {
+			return nil, service.ErrEndOfInput
+		}
+
+		c, err := f.pool.acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire FTP connection: %w", err)
+		}
+
+		res, err := c.Retr(info.path)
+		if err != nil {
+			f.pool.release(c, err)
+			f.log.With("path", info.path, "err", err.Error()).Warn("Failed to open previously identified file")
+
+			ackErr := err
+			if isNotFound(err) {
+				// If we failed to open the file because it no longer exists then we
+				// can "ack" the path as we're done with it. Otherwise we "nack" it
+				// with the error as we'll want to reprocess it again later.
+				ackErr = nil
+			}
+			if err := f.pathProvider.Ack(ctx, info.path, ackErr); err != nil {
+				f.log.With("error", err).Warnf("Failed to acknowledge path: %s", info.path)
+			}
+			if isConnError(err) {
+				f.stateLock.Lock()
+				f.connected = false
+				f.stateLock.Unlock()
+				return nil, service.ErrNotConnected
+			}
+			continue
+		}
+
+		file := &ftpFile{res: res, pool: f.pool, conn: c}
+		details := service.NewScannerSourceDetails()
+		details.SetName(info.path)
+		scanner, err := f.scannerCtor.Create(file, f.newCodecAckFn(info.path), details)
+		if err != nil {
+			if err := file.Close(); err != nil {
+				f.log.Errorf("Failed to close file %q: %s", info.path, err)
+			}
+			return nil, fmt.Errorf("failed to create scanner: %w", err)
+		}
+
+		f.stateLock.Lock()
+		f.scanner = scanner
+		f.currentFileInfo = info
+		f.stateLock.Unlock()
+		return scanner, nil
+	}
+}
+
+func (f *ftpReader) newCodecAckFn(filePath string) service.AckFunc {
+	return func(ctx context.Context, aErr error) error {
+		if err := f.pathProvider.Ack(ctx, filePath, aErr); err != nil {
+			f.log.With("error", err).Warnf("Failed to acknowledge path: %s", filePath)
+		}
+		if aErr != nil || (!f.deleteOnFinish && f.moveOnFinish == "") {
+			return nil
+		}
+
+		c, err := f.pool.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		if f.deleteOnFinish {
+			if err = c.Delete(filePath); err != nil {
+				err = fmt.Errorf("failed to remove file %q: %w", filePath, err)
+			}
+		} else {
+			target := path.Join(f.moveOnFinish, path.Base(filePath))
+			if err = c.Rename(filePath, target); err != nil {
+				err = fmt.Errorf("failed to move file %q to %q: %w", filePath, target, err)
+			}
+		}
+		f.pool.release(c, err)
+		return err
+	}
+}
+
+func (f *ftpReader) Close(ctx context.Context) error {
+	f.closeScanner(ctx)
+
+	f.stateLock.Lock()
+	defer f.stateLock.Unlock()
+	f.connected = false
+	f.pool.close()
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type pathProvider interface {
+	Next(context.Context) (fileInfo, bool, error)
+	Ack(context.Context, string, error) error
+}
+
+type staticPathProvider struct {
+	files []fileInfo
+}
+
+func (s *staticPathProvider) Next(context.Context) (fileInfo, bool, error) {
+	if len(s.files) == 0 {
+		return fileInfo{}, false, nil
+	}
+	info := s.files[0]
+	s.files = s.files[1:]
+	return info, true, nil
+}
+
+func (*staticPathProvider) Ack(context.Context, string, error) error {
+	return nil
+}
+
+type watcherPathProvider struct {
+	pool         *connPool
+	mgr          *service.Resources
+	cacheName    string
+	pollInterval time.Duration
+	minAge       time.Duration
+	targetPaths  []string
+
+	files        []fileInfo
+	nextPoll     time.Time
+	followUpPoll bool
+}
+
+func (w *watcherPathProvider) Next(ctx context.Context) (fileInfo, bool, error) {
+	for {
+		if len(w.files) > 0 {
+			info := w.files[0]
+			w.files = w.files[1:]
+			return info, true, nil
+		}
+
+		if waitFor := time.Until(w.nextPoll); w.nextPoll.IsZero() || waitFor > 0 {
+			select {
+			case <-time.After(waitFor):
+			case <-ctx.Done():
+				return fileInfo{}, false, ctx.Err()
+			}
+		}
+		w.nextPoll = time.Now().Add(w.pollInterval)
+
+		if err := w.findNewPaths(ctx); err != nil {
+			return fileInfo{}, false, fmt.Errorf("expanding new paths: %w", err)
+		}
+		w.followUpPoll = true
+	}
+}
+
+func (w *watcherPathProvider) findNewPaths(ctx context.Context) error {
+	if cerr := w.mgr.AccessCache(ctx, w.cacheName, func(cache service.Cache) {
+		c, err := w.pool.acquire(ctx)
+		if err != nil {
+			w.mgr.Logger().With("error", err).Warn("Failed to acquire FTP connection")
+			return
+		}
+
+		var connErr error
+		defer func() { w.pool.release(c, connErr) }()
+
+		for _, p := range w.targetPaths {
+			if ctx.Err() != nil {
+				return
+			}
+
+			files, err := glob(c, p)
+			if err != nil {
+				w.mgr.Logger().With("error", err, "path", p).Warn("Failed to scan files from path")
+				if isConnError(err) {
+					connErr = err
+					return
+				}
+				continue
+			}
+
+			for _, info := range files {
+				if time.Since(info.modTime) < w.minAge {
+					continue
+				}
+
+				// We process it if the marker is a pending symbol (!) and we're
+				// polling for the first time, or if the path isn't found in the
+				// cache.
+				if v, err := cache.Get(ctx, info.path); errors.Is(err, service.ErrKeyNotFound) || (!w.followUpPoll && string(v) == "!") {
+					w.files = append(w.files, info)
+					if err = cache.Set(ctx, info.path, []byte("!"), nil); err != nil {
+						// Mark the file target as pending so that we do not reprocess it
+						w.mgr.Logger().With("error", err, "path", info.path).Warn("Failed to mark path as pending")
+					}
+				}
+			}
+		}
+	}); cerr != nil {
+		return fmt.Errorf("error obtaining cache: %v", cerr)
+	}
+	return nil
+}
+
+func (w *watcherPathProvider) Ack(ctx context.Context, name string, err error) (outErr error) {
+	if cerr := w.mgr.AccessCache(ctx, w.cacheName, func(cache service.Cache) {
+		if err == nil {
+			outErr = cache.Set(ctx, name, []byte("@"), nil)
+		} else {
+			_ = cache.Delete(ctx, name)
+		}
+	}); cerr != nil {
+		outErr = cerr
+	}
+	return
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ftp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/jlaffaye/ftp"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	foFieldPath  = "path"
+	foFieldCodec = "codec"
+)
+
+func ftpOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.62.0").
+		Summary(`Writes files to an FTP or FTPS server.`).
+		Description(`In order to have a different path for each object you should use function interpolations described xref:configuration:interpolation.adoc#bloblang-queries[here]. Directories of the path that do not exist are created.
+
+FTPS is enabled with the `+"`tls`"+` field.`+service.OutputPerformanceDocs(true, false)).
+		Fields(connectionFields()...).
+		Fields(
+			service.NewInterpolatedStringField(foFieldPath).
+				Description("The file to save the messages to on the server."),
+			service.NewStringAnnotatedEnumField(foFieldCodec, map[string]string{
+				"all-bytes": "Only applicable to file based outputs. Writes each message to a file in full, if the file already exists the old content is deleted.",
+				"append":    "Append each message to the output stream without any delimiter or special encoding.",
+				"lines":     "Append each message to the output stream followed by a line break.",
+				"delim:x":   "Append each message to the output stream followed by a custom delimiter.",
+			}).
+				Description("The way in which the bytes of messages should be written out into the output data stream. It's possible to write lines using a custom delimiter with the `delim:x` codec, where x is the character sequence custom delimiter.").
+				LintRule("").
+				Examples("lines", "delim:\t", "delim:foobar").
+				Default("all-bytes"),
+			service.NewOutputMaxInFlightField(),
+		)
+}
+
+func init() {
+	service.MustRegisterOutput(
+		"ftp", ftpOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.Output, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out, err = newWriterFromParsed(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type ftpWriter struct {
+	log *service.Logger
+
+	pool       *connPool
+	path       *service.InterpolatedString
+	suffixFn   codecSuffixFn
+	appendMode bool
+
+	// Directories that have been created, as creating them is otherwise
+	// attempted for each message.
+	dirs sync.Map
+}
+
+func newWriterFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (f *ftpWriter, err error) {
+	f = &ftpWriter{
+		log: mgr.Logger(),
+	}
+
+	var codecStr string
+	if codecStr, err = conf.FieldString(foFieldCodec); err != nil {
+		return
+	}
+	if f.suffixFn, f.appendMode, err = codecGetWriter(codecStr); err != nil {
+		return nil, err
+	}
+	if f.pool, err = connPoolFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if f.path, err = conf.FieldInterpolatedString(foFieldPath); err != nil {
+		return
+	}
+	return f, nil
+}
+
+func (f *ftpWriter) Connect(ctx context.Context) error {
+	c, err := f.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	f.pool.release(c, nil)
+	return nil
+}
+
+// makeDirs creates the parent directories of a path, ignoring errors for
+// directories that already exist.
+func (f *ftpWriter) makeDirs(c *ftp.ServerConn, filePath string) {
+	dir := path.Dir(filePath)
+	if dir == "." || dir == "/" {
+		return
+	}
+	if _, exists := f.dirs.Load(dir); exists {
+		return
+	}
+	var current string
+	if strings.HasPrefix(dir, "/") {
+		current = "/"
+	}
+	for _, segment := range strings.Split(strings.Trim(dir, "/"), "/") {
+		current = path.Join(current, segment)
+		_ = c.MakeDir(current)
+	}
+	f.dirs.Store(dir, struct{}{})
+}
+
+func (f *ftpWriter) Write(ctx context.Context, msg *service.Message) (wErr error) {
+	filePath, err := f.path.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("path interpolation error: %w", err)
+	}
+
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	data := mBytes
+	if suffix, addSuffix := f.suffixFn(mBytes); addSuffix {
+		data = append(append(make([]byte, 0, len(mBytes)+len(suffix)), mBytes...), suffix...)
+	}
+
+	c, err := f.pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.pool.release(c, wErr)
+		if isConnError(wErr) {
+			wErr = service.ErrNotConnected
+		}
+	}()
+
+	f.makeDirs(c, filePath)
+	if f.appendMode {
+		if err := c.Append(filePath, bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("failed to append to remote file: %w", err)
+		}
+		return nil
+	}
+	if err := c.Stor(filePath, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to write remote file: %w", err)
+	}
+	return nil
+}
+
+func (f *ftpWriter) Close(context.Context) error {
+	f.pool.close()
+	return nil
+}
@@ -38,6 +38,7 @@ import (
 const (
 	hsiFieldPath                    = "path"
 	hsiFieldRateLimit               = "rate_limit"
+	hsiFieldOpenAPISpec             = "openapi_spec"
 	hsiFieldResponse                = "sync_response"
 	hsiFieldResponseStatus          = "status"
 	hsiFieldResponseHeaders         = "headers"
@@ -45,9 +46,10 @@ const (
 )
 
 type hsiConfig struct {
-	Path      string
-	RateLimit string
-	Response  hsiResponseConfig
+	Path        string
+	RateLimit   string
+	OpenAPISpec string
+	Response    hsiResponseConfig
 
 	// Set via environment variables
 	Address string
@@ -83,6 +85,9 @@ func hsiConfigFromParsed(pConf *service.ParsedConfig) (conf hsiConfig, err error
 	if conf.RateLimit, err = pConf.FieldString(hsiFieldRateLimit); err != nil {
 		return
 	}
+	if conf.OpenAPISpec, err = pConf.FieldString(hsiFieldOpenAPISpec); err != nil {
+		return
+	}
 	if conf.Response, err = hsiResponseConfigFromParsed(pConf.Namespace(hsiFieldResponse)); err != nil {
 		return
 	}
@@ -134,6 +139,12 @@ The field `+"`rate_limit`"+` allows you to specify an optional `+"xref:component
 
 When the rate limit is breached HTTP requests will have a 429 response returned with a Retry-After header.
 
+== Request validation
+
+When the field `+"`openapi_spec`"+` is set to an OpenAPI 3 document each request is validated against it before being consumed. Requests to paths that are not described by the document are rejected with a 404 response, requests with a method that the matched path does not support are rejected with a 405 response, and requests with a content type that the operation does not accept are rejected with a 415 response. Required parameters are checked and parameter values as well as JSON request bodies are validated against their schemas, with failures resulting in a 400 response.
+
+Rejections are returned as https://www.rfc-editor.org/rfc/rfc9457[problem details^] with the content type `+"`application/problem+json`"+` and an `+"`errors`"+` array describing each individual failure. Paths are matched against the request path after removing the path of the first entry of `+"`servers`"+`, if any. Only local references (`+"`#/...`"+`) within the document are supported.
+
 == Responses
 
 It's possible to return a response for each message received using xref:guides:sync_responses.adoc[synchronous responses]. When doing so you can customize headers with the `+"`sync_response` field `headers`"+`, which can also use xref:configuration:interpolation.adoc#bloblang-queries[function interpolation] in the value based on the response message contents.
@@ -147,9 +158,10 @@ This input adds the following metadata fields to each message:
 - http_server_request_path
 - http_server_verb
 - http_server_remote_ip
+- http_server_operation_id (when `+"`openapi_spec`"+` is set and the operation has an operationId)
 - All headers (only first values are taken)
 - All query parameters
-- All path parameters
+- All path parameters (including those of the matched OpenAPI path)
 - All cookies
 `+"```"+`
 
@@ -161,6 +173,11 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 			service.NewStringField(hsiFieldRateLimit).
 				Description("An optional xref:components:rate_limits/about.adoc[rate limit] to throttle requests by.").
 				Default(""),
+			service.NewStringField(hsiFieldOpenAPISpec).
+				Description("An optional path to an OpenAPI 3 document, in either YAML or JSON format, that requests are validated against.").
+				Example("./api/openapi.yaml").
+				Default("").
+				Version("4.62.0"),
 			service.NewObjectField(hsiFieldResponse,
 				service.NewInterpolatedStringField(hsiFieldResponseStatus).
 					Description("Specify the status code to return with synchronous responses. This is a string value, which allows you to customize it based on resulting payloads and their metadata.").
@@ -204,6 +221,7 @@ type Input struct {
 	server *http.Server
 
 	rpJWTValidator *gateway.RPJWTMiddleware
+	openAPI        *openAPIValidator
 
 	batches chan batchAndAck
 
@@ -238,6 +256,16 @@ func InputFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (*Inpu
 		}
 	}
 
+	if h.conf.OpenAPISpec != "" {
+		specBytes, err := service.ReadFile(mgr.FS(), h.conf.OpenAPISpec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+		}
+		if h.openAPI, err = newOpenAPIValidator(specBytes); err != nil {
+			return nil, err
+		}
+	}
+
 	return &h, nil
 }
 
@@ -405,6 +433,16 @@ func (ri *Input) deliverHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var match *openAPIMatch
+	if ri.openAPI != nil {
+		var problem *problemDetails
+		if match, problem = ri.openAPI.validate(r); problem != nil {
+			ri.log.With("status", problem.Status, "detail", problem.Detail).Debug("Request rejected by OpenAPI validation")
+			problem.write(w)
+			return
+		}
+	}
+
 	batch, err := extractBatchFromRequest(r)
 	if err != nil {
 		http.Error(w, "Bad request", http.StatusBadRequest)
@@ -412,6 +450,17 @@ func (ri *Input) deliverHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if match != nil {
+		for _, p := range batch {
+			if match.operationID != "" {
+				p.MetaSetMut("http_server_operation_id", match.operationID)
+			}
+			for k, v := range match.pathParams {
+				p.MetaSetMut(k, v)
+			}
+		}
+	}
+
 	batch, store := batch.WithSyncResponseStore()
 
 	ri.log.With("batch_size", len(batch), "path", ri.conf.Path).Trace("Consumed messages from POST")
@@ -0,0 +1,548 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIValidator validates HTTP requests against the paths, operations,
+// parameters and request bodies of an OpenAPI 3 document.
+type openAPIValidator struct {
+	basePath string
+	routes   []*openAPIRoute
+}
+
+type openAPIRoute struct {
+	template   string
+	segments   []string
+	params     int
+	operations map[string]*openAPIOperation
+}
+
+type openAPIOperation struct {
+	id           string
+	params       []*openAPIParam
+	bodyRequired bool
+	// Media types accepted by the operation mapped to their (optional)
+	// schema. A nil map means the operation does not declare a body.
+	bodyContent map[string]*gojsonschema.Schema
+}
+
+type openAPIParam struct {
+	name      string
+	in        string
+	required  bool
+	valueType string
+	itemsType string
+	schema    *gojsonschema.Schema
+}
+
+type openAPIMatch struct {
+	operationID string
+	pathParams  map[string]string
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func newOpenAPIValidator(specBytes []byte) (*openAPIValidator, error) {
+	var raw any
+	if err := yaml.Unmarshal(specBytes, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	doc, ok := normaliseYAML(raw).(map[string]any)
+	if !ok {
+		return nil, errors.New("OpenAPI document must be an object")
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, only version 3 documents are supported", version)
+	}
+
+	v := &openAPIValidator{}
+	if servers, _ := doc["servers"].([]any); len(servers) > 0 {
+		if server, _ := servers[0].(map[string]any); server != nil {
+			serverURL, _ := server["url"].(string)
+			if u, err := url.Parse(serverURL); err == nil {
+				v.basePath = strings.TrimSuffix(u.Path, "/")
+			}
+		}
+	}
+
+	// Schemas are compiled within a root document containing the component
+	// schemas so that local references resolve.
+	components, _ := doc["components"].(map[string]any)
+	compile := func(schema any) (*gojsonschema.Schema, error) {
+		root := map[string]any{
+			"$ref":     "#/x-schema",
+			"x-schema": convertNullable(schema),
+		}
+		if schemas, ok := components["schemas"]; ok {
+			root["components"] = map[string]any{"schemas": convertNullable(schemas)}
+		}
+		b, err := json.Marshal(root)
+		if err != nil {
+			return nil, err
+		}
+		return gojsonschema.NewSchema(gojsonschema.NewBytesLoader(b))
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	for template, item := range paths {
+		pathItem, _ := resolveOpenAPIRef(doc, item).(map[string]any)
+		if pathItem == nil {
+			continue
+		}
+		route := &openAPIRoute{
+			template:   template,
+			segments:   strings.Split(strings.Trim(template, "/"), "/"),
+			operations: map[string]*openAPIOperation{},
+		}
+		for _, s := range route.segments {
+			if isPathParam(s) {
+				route.params++
+			}
+		}
+
+		sharedParams, _ := pathItem["parameters"].([]any)
+		for _, method := range openAPIMethods {
+			opObj, _ := pathItem[method].(map[string]any)
+			if opObj == nil {
+				continue
+			}
+			op, err := parseOpenAPIOperation(doc, opObj, sharedParams, compile)
+			if err != nil {
+				return nil, fmt.Errorf("%v %v: %w", strings.ToUpper(method), template, err)
+			}
+			route.operations[strings.ToUpper(method)] = op
+		}
+		v.routes = append(v.routes, route)
+	}
+
+	// Prefer concrete paths over templated ones, as required by the spec.
+	slices.SortStableFunc(v.routes, func(a, b *openAPIRoute) int {
+		if a.params != b.params {
+			return a.params - b.params
+		}
+		return strings.Compare(a.template, b.template)
+	})
+	return v, nil
+}
+
+func parseOpenAPIOperation(
+	doc, opObj map[string]any,
+	sharedParams []any,
+	compile func(any) (*gojsonschema.Schema, error),
+) (*openAPIOperation, error) {
+	op := &openAPIOperation{}
+	op.id, _ = opObj["operationId"].(string)
+
+	opParams, _ := opObj["parameters"].([]any)
+	for _, p := range append(slices.Clone(sharedParams), opParams...) {
+		pObj, _ := resolveOpenAPIRef(doc, p).(map[string]any)
+		if pObj == nil {
+			continue
+		}
+		param := &openAPIParam{}
+		param.name, _ = pObj["name"].(string)
+		param.in, _ = pObj["in"].(string)
+		param.required, _ = pObj["required"].(bool)
+		if param.in == "path" {
+			param.required = true
+		}
+		if schemaObj, ok := pObj["schema"]; ok {
+			resolved, _ := resolveOpenAPIRef(doc, schemaObj).(map[string]any)
+			param.valueType, _ = resolved["type"].(string)
+			if items, _ := resolveOpenAPIRef(doc, resolved["items"]).(map[string]any); items != nil {
+				param.itemsType, _ = items["type"].(string)
+			}
+			var err error
+			if param.schema, err = compile(schemaObj); err != nil {
+				return nil, fmt.Errorf("parameter %v: %w", param.name, err)
+			}
+		}
+
+		// Operation level parameters override path level ones.
+		op.params = slices.DeleteFunc(op.params, func(existing *openAPIParam) bool {
+			return existing.name == param.name && existing.in == param.in
+		})
+		op.params = append(op.params, param)
+	}
+
+	body, _ := resolveOpenAPIRef(doc, opObj["requestBody"]).(map[string]any)
+	if body == nil {
+		return op, nil
+	}
+	op.bodyRequired, _ = body["required"].(bool)
+	op.bodyContent = map[string]*gojsonschema.Schema{}
+	content, _ := body["content"].(map[string]any)
+	for mediaType, mObj := range content {
+		var schema *gojsonschema.Schema
+		if m, _ := mObj.(map[string]any); m != nil {
+			if schemaObj, ok := m["schema"]; ok && isJSONMediaType(mediaType) {
+				var err error
+				if schema, err = compile(schemaObj); err != nil {
+					return nil, fmt.Errorf("request body %v: %w", mediaType, err)
+				}
+			}
+		}
+		op.bodyContent[strings.ToLower(mediaType)] = schema
+	}
+	return op, nil
+}
+
+//------------------------------------------------------------------------------
+
+type problemError struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// problemDetails is an RFC 9457 problem details response body.
+type problemDetails struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+	Errors []problemError `json:"errors,omitempty"`
+
+	allow []string
+}
+
+func newProblem(status int, detail string, errs ...problemError) *problemDetails {
+	return &problemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Errors: errs,
+	}
+}
+
+func (p *problemDetails) write(w http.ResponseWriter) {
+	if len(p.allow) > 0 {
+		w.Header().Set("Allow", strings.Join(p.allow, ", "))
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// validate checks a request against the document, returning the matched
+// operation or a problem describing why the request was rejected. The request
+// body is buffered so that it can be read again by the caller.
+func (v *openAPIValidator) validate(r *http.Request) (*openAPIMatch, *problemDetails) {
+	reqPath := r.URL.Path
+	if v.basePath != "" {
+		trimmed, ok := strings.CutPrefix(reqPath, v.basePath)
+		if !ok {
+			return nil, newProblem(http.StatusNotFound, fmt.Sprintf("path %v is not described by the OpenAPI document", r.URL.Path))
+		}
+		reqPath = trimmed
+	}
+	reqSegments := strings.Split(strings.Trim(reqPath, "/"), "/")
+
+	var route *openAPIRoute
+	var pathParams map[string]string
+	for _, candidate := range v.routes {
+		if pathParams = candidate.match(reqSegments); pathParams != nil {
+			route = candidate
+			break
+		}
+	}
+	if route == nil {
+		return nil, newProblem(http.StatusNotFound, fmt.Sprintf("path %v is not described by the OpenAPI document", r.URL.Path))
+	}
+
+	op, exists := route.operations[r.Method]
+	if !exists {
+		p := newProblem(http.StatusMethodNotAllowed, fmt.Sprintf("method %v is not allowed for path %v", r.Method, route.template))
+		for method := range route.operations {
+			p.allow = append(p.allow, method)
+		}
+		slices.Sort(p.allow)
+		return nil, p
+	}
+
+	var errs []problemError
+	for _, param := range op.params {
+		errs = append(errs, param.validate(r, pathParams)...)
+	}
+
+	bodyErrs, problem := op.validateBody(r)
+	if problem != nil {
+		return nil, problem
+	}
+	errs = append(errs, bodyErrs...)
+
+	if len(errs) > 0 {
+		return nil, newProblem(http.StatusBadRequest, "the request does not conform to the OpenAPI document", errs...)
+	}
+	return &openAPIMatch{operationID: op.id, pathParams: pathParams}, nil
+}
+
+func (r *openAPIRoute) match(reqSegments []string) map[string]string {
+	if len(reqSegments) != len(r.segments) {
+		return nil
+	}
+	params := map[string]string{}
+	for i, s := range r.segments {
+		if isPathParam(s) {
+			if reqSegments[i] == "" {
+				return nil
+			}
+			params[s[1:len(s)-1]] = reqSegments[i]
+		} else if s != reqSegments[i] {
+			return nil
+		}
+	}
+	return params
+}
+
+func (p *openAPIParam) validate(r *http.Request, pathParams map[string]string) []problemError {
+	location := p.in + "." + p.name
+
+	var values []string
+	switch p.in {
+	case "path":
+		if v, ok := pathParams[p.name]; ok {
+			values = []string{v}
+		}
+	case "query":
+		values = r.URL.Query()[p.name]
+	case "header":
+		values = r.Header.Values(p.name)
+	case "cookie":
+		if c, err := r.Cookie(p.name); err == nil {
+			values = []string{c.Value}
+		}
+	}
+	if len(values) == 0 {
+		if p.required {
+			return []problemError{{Location: location, Message: "parameter is required"}}
+		}
+		return nil
+	}
+	if p.schema == nil {
+		return nil
+	}
+
+	var value any
+	var err error
+	if p.valueType == "array" {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := make([]any, len(values))
+		for i, v := range values {
+			if items[i], err = coerceParam(p.itemsType, v); err != nil {
+				break
+			}
+		}
+		value = items
+	} else {
+		value, err = coerceParam(p.valueType, values[0])
+	}
+	if err != nil {
+		return []problemError{{Location: location, Message: err.Error()}}
+	}
+	return schemaErrors(p.schema, location, value)
+}
+
+func coerceParam(valueType, v string) (any, error) {
+	switch valueType {
+	case "integer":
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not an integer", v)
+		}
+		return i, nil
+	case "number":
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a number", v)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a boolean", v)
+		}
+		return b, nil
+	}
+	return v, nil
+}
+
+func (op *openAPIOperation) validateBody(r *http.Request) ([]problemError, *problemDetails) {
+	if op.bodyContent == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		if op.bodyRequired {
+			return []problemError{{Location: "body", Message: "request body is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, newProblem(http.StatusUnsupportedMediaType, fmt.Sprintf("failed to parse content type: %v", err))
+	}
+
+	schema, exists := op.bodyContent[mediaType]
+	if !exists {
+		if mainType, _, ok := strings.Cut(mediaType, "/"); ok {
+			schema, exists = op.bodyContent[mainType+"/*"]
+		}
+	}
+	if !exists {
+		schema, exists = op.bodyContent["*/*"]
+	}
+	if !exists {
+		return nil, newProblem(http.StatusUnsupportedMediaType, fmt.Sprintf("content type %v is not accepted by this operation", mediaType))
+	}
+	if schema == nil || !isJSONMediaType(mediaType) {
+		return nil, nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []problemError{{Location: "body", Message: fmt.Sprintf("malformed JSON: %v", err)}}, nil
+	}
+	return schemaErrors(schema, "body", value), nil
+}
+
+func schemaErrors(schema *gojsonschema.Schema, location string, value any) []problemError {
+	res, err := schema.Validate(gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return []problemError{{Location: location, Message: err.Error()}}
+	}
+	var errs []problemError
+	for _, e := range res.Errors() {
+		loc := location
+		if field := e.Field(); field != gojsonschema.STRING_CONTEXT_ROOT {
+			loc += "." + field
+		}
+		errs = append(errs, problemError{Location: loc, Message: e.Description()})
+	}
+	return errs
+}
+
+//------------------------------------------------------------------------------
+
+func isPathParam(segment string) bool {
+	return len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// resolveOpenAPIRef follows local references (#/...) of a node within the
+// document.
+func resolveOpenAPIRef(doc map[string]any, node any) any {
+	for range 32 {
+		obj, _ := node.(map[string]any)
+		ref, _ := obj["$ref"].(string)
+		if ref == "" {
+			return node
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return nil
+		}
+		var current any = doc
+		for _, key := range strings.Split(pointer, "/") {
+			key = strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+			m, _ := current.(map[string]any)
+			if current = m[key]; current == nil {
+				return nil
+			}
+		}
+		node = current
+	}
+	return nil
+}
+
+// convertNullable rewrites the OpenAPI 3.0 nullable keyword into its JSON
+// schema equivalent.
+func convertNullable(node any) any {
+	switch t := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			out[k] = convertNullable(v)
+		}
+		if nullable, isBool := out["nullable"].(bool); isBool {
+			if typeStr, ok := out["type"].(string); ok && nullable {
+				out["type"] = []any{typeStr, "null"}
+			}
+			if enum, ok := out["enum"].([]any); ok && nullable {
+				out["enum"] = append(enum, nil)
+			}
+			delete(out, "nullable")
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, v := range t {
+			out[i] = convertNullable(v)
+		}
+		return out
+	}
+	return node
+}
+
+// normaliseYAML converts any maps with non-string keys (such as response
+// status codes) into maps with string keys.
+func normaliseYAML(node any) any {
+	switch t := node.(type) {
+	case map[string]any:
+		for k, v := range t {
+			t[k] = normaliseYAML(v)
+		}
+		return t
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			out[fmt.Sprint(k)] = normaliseYAML(v)
+		}
+		return out
+	case []any:
+		for i, v := range t {
+			t[i] = normaliseYAML(v)
+		}
+		return t
+	}
+	return node
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package gateway_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/gateway"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+  - url: https://example.com/v1
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        200:
+          description: ok
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      parameters:
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+      responses:
+        200:
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [ name ]
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+`
+
+func TestHTTPOpenAPIValidation(t *testing.T) {
+	t.Setenv("REDPANDA_CLOUD_GATEWAY_ADDRESS", "0.0.0.0:1234")
+
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(testOpenAPISpec), 0o644))
+
+	pConf, err := gateway.InputSpec().ParseYAML(`
+openapi_spec: `+specPath+`
+`, nil)
+	require.NoError(t, err)
+
+	h, err := gateway.InputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	require.NoError(t, h.RegisterCustomMux(router))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedErrors []string
+		expectedMeta   map[string]string
+	}{
+		{
+			name:           "valid body",
+			method:         http.MethodPost,
+			path:           "/v1/pets",
+			contentType:    "application/json",
+			body:           `{"name":"fido","tag":null}`,
+			expectedStatus: http.StatusOK,
+			expectedMeta:   map[string]string{"http_server_operation_id": "createPet"},
+		},
+		{
+			name:           "valid path parameter",
+			method:         http.MethodGet,
+			path:           "/v1/pets/12?verbose=true",
+			expectedStatus: http.StatusOK,
+			expectedMeta: map[string]string{
+				"http_server_operation_id": "getPet",
+				"petId":                    "12",
+			},
+		},
+		{
+			name:           "invalid body",
+			method:         http.MethodPost,
+			path:           "/v1/pets",
+			contentType:    "application/json",
+			body:           `{"tag":5}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"body", "body.tag"},
+		},
+		{
+			name:           "missing body",
+			method:         http.MethodPost,
+			path:           "/v1/pets",
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"body"},
+		},
+		{
+			name:           "invalid parameters",
+			method:         http.MethodGet,
+			path:           "/v1/pets/abc?verbose=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"path.petId", "query.verbose"},
+		},
+		{
+			name:           "unsupported content type",
+			method:         http.MethodPost,
+			path:           "/v1/pets",
+			contentType:    "text/plain",
+			body:           `fido`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodDelete,
+			path:           "/v1/pets/12",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/v1/owners",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tCtx, done := context.WithTimeout(t.Context(), time.Second*30)
+			defer done()
+
+			metaChan := make(chan map[string]string, 1)
+			if test.expectedStatus == http.StatusOK {
+				go func() {
+					batch, aFn, err := h.ReadBatch(tCtx)
+					require.NoError(t, err)
+					require.Len(t, batch, 1)
+
+					meta := map[string]string{}
+					for k := range test.expectedMeta {
+						meta[k], _ = batch[0].MetaGet(k)
+					}
+					metaChan <- meta
+					require.NoError(t, aFn(tCtx, nil))
+				}()
+			}
+
+			req, err := http.NewRequestWithContext(tCtx, test.method, server.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, test.expectedStatus, res.StatusCode)
+
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, test.expectedMeta, <-metaChan)
+				return
+			}
+
+			assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+			var problem struct {
+				Status int `json:"status"`
+				Errors []struct {
+					Location string `json:"location"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+			assert.Equal(t, test.expectedStatus, problem.Status)
+
+			var locations []string
+			for _, e := range problem.Errors {
+				locations = append(locations, e.Location)
+			}
+			assert.ElementsMatch(t, test.expectedErrors, locations)
+		})
+	}
+}
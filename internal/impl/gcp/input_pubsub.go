@@ -38,9 +38,11 @@ const (
 	pbiFieldMaxOutstandingMessages = "max_outstanding_messages"
 	pbiFieldMaxOutstandingBytes    = "max_outstanding_bytes"
 	pbiFieldSync                   = "sync"
+	pbiFieldPreserveKeyOrder       = "preserve_key_order"
 	pbiFieldCreateSub              = "create_subscription"
 	pbiFieldCreateSubEnabled       = "enabled"
 	pbiFieldCreateSubTopicID       = "topic"
+	pbiFieldCreateSubOrdering      = "enable_message_ordering"
 )
 
 type pbiConfig struct {
@@ -51,8 +53,10 @@ type pbiConfig struct {
 	MaxOutstandingMessages int
 	MaxOutstandingBytes    int
 	Sync                   bool
+	PreserveKeyOrder       bool
 	CreateEnabled          bool
 	CreateTopicID          string
+	CreateOrdering         bool
 }
 
 func pbiConfigFromParsed(pConf *service.ParsedConfig) (conf pbiConfig, err error) {
@@ -77,6 +81,9 @@ func pbiConfigFromParsed(pConf *service.ParsedConfig) (conf pbiConfig, err error
 	if conf.Sync, err = pConf.FieldBool(pbiFieldSync); err != nil {
 		return
 	}
+	if conf.PreserveKeyOrder, err = pConf.FieldBool(pbiFieldPreserveKeyOrder); err != nil {
+		return
+	}
 	if pConf.Contains(pbiFieldCreateSub) {
 		createConf := pConf.Namespace(pbiFieldCreateSub)
 		if conf.CreateEnabled, err = createConf.FieldBool(pbiFieldCreateSubEnabled); err != nil {
@@ -85,6 +92,9 @@ func pbiConfigFromParsed(pConf *service.ParsedConfig) (conf pbiConfig, err error
 		if conf.CreateTopicID, err = createConf.FieldString(pbiFieldCreateSubTopicID); err != nil {
 			return
 		}
+		if conf.CreateOrdering, err = createConf.FieldBool(pbiFieldCreateSubOrdering); err != nil {
+			return
+		}
 	}
 	return
 }
@@ -108,6 +118,10 @@ This input adds the following metadata fields to each message:
 - All message attributes
 
 You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+== Ordering keys
+
+When consuming from a subscription with https://cloud.google.com/pubsub/docs/ordering[message ordering^] enabled, messages that share an ordering key are received in the order they were published. However, messages are processed and acknowledged concurrently, and a message that is retried can therefore be delivered after messages with the same key that were published later. Setting `+"`preserve_key_order`"+` to `+"`true`"+` waits for each message with an ordering key to be acknowledged before the next message with the same key is consumed, which preserves the order of each key throughout the pipeline at the cost of limiting each key to a single message in flight. Messages without an ordering key are unaffected.
 `).
 		Fields(
 			service.NewStringField(pbiFieldProjectID).
@@ -126,6 +140,11 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 			service.NewBoolField(pbiFieldSync).
 				Description("Enable synchronous pull mode.").
 				Default(false),
+			service.NewBoolField(pbiFieldPreserveKeyOrder).
+				Description("Whether to wait for each message with an ordering key to be acknowledged before consuming the next message with the same key.").
+				Version("4.62.0").
+				Advanced().
+				Default(false),
 			service.NewIntField(pbiFieldMaxOutstandingMessages).
 				Description("The maximum number of outstanding pending messages to be consumed at a given time.").
 				Default(1000), // pubsub.DefaultReceiveSettings.MaxOutstandingMessages)
@@ -138,6 +157,10 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 				service.NewStringField(pbiFieldCreateSubTopicID).
 					Description("Defines the topic that the subscription should be vinculated to.").
 					Default(""),
+				service.NewBoolField(pbiFieldCreateSubOrdering).
+					Description("Whether to enable message ordering on the created subscription.").
+					Version("4.62.0").
+					Default(false),
 			).
 				Description("Allows you to configure the input subscription and creates if it doesn't exist.").
 				Advanced(),
@@ -173,7 +196,10 @@ func createSubscription(conf pbiConfig, client *pubsub.Client, log *service.Logg
 	}
 
 	log.Infof("Creating subscription '%v' on topic '%v'\n", conf.SubscriptionID, conf.CreateTopicID)
-	_, err = client.CreateSubscription(context.Background(), conf.SubscriptionID, pubsub.SubscriptionConfig{Topic: client.Topic(conf.CreateTopicID)})
+	_, err = client.CreateSubscription(context.Background(), conf.SubscriptionID, pubsub.SubscriptionConfig{
+		Topic:                 client.Topic(conf.CreateTopicID),
+		EnableMessageOrdering: conf.CreateOrdering,
+	})
 	if err != nil {
 		log.Errorf("Error creating subscription %v", err)
 	}
@@ -187,6 +213,10 @@ type gcpPubSubReader struct {
 	closeFunc    context.CancelFunc
 	subMut       sync.Mutex
 
+	// Signals the completion of messages with an ordering key, which the
+	// receive callback waits on when preserving key order.
+	orderedAcks sync.Map
+
 	client *pubsub.Client
 
 	log *service.Logger
@@ -257,12 +287,28 @@ func (c *gcpPubSubReader) Connect(context.Context) error {
 
 	go func() {
 		rerr := sub.Receive(subCtx, func(ctx context.Context, m *pubsub.Message) {
+			// Messages with the same ordering key are passed to this callback
+			// one at a time, therefore blocking until the message is acked
+			// prevents later messages of the key from being consumed.
+			var acked chan struct{}
+			if c.conf.PreserveKeyOrder && m != nil && m.OrderingKey != "" {
+				acked = make(chan struct{})
+				c.orderedAcks.Store(m, acked)
+			}
 			select {
 			case msgsChan <- m:
 			case <-ctx.Done():
 				if m != nil {
+					c.orderedAcks.Delete(m)
 					m.Nack()
 				}
+				return
+			}
+			if acked != nil {
+				select {
+				case <-acked:
+				case <-ctx.Done():
+				}
 			}
 		})
 		if rerr != nil && rerr != context.Canceled {
@@ -325,6 +371,9 @@ func (c *gcpPubSubReader) Read(ctx context.Context) (*service.Message, service.A
 		} else {
 			gmsg.Ack()
 		}
+		if acked, exists := c.orderedAcks.LoadAndDelete(gmsg); exists {
+			close(acked.(chan struct{}))
+		}
 		return nil
 	}, nil
 }
@@ -56,7 +56,11 @@ Or delete all keys with:
 pipeline:
   processors:
     - mapping: meta = deleted()
-`+"```"+``).
+`+"```"+`
+
+== Ordering keys
+
+When an `+"`ordering_key`"+` is configured message ordering is enabled on the topic, and messages with the same ordering key are published in the order they are written. If publishing a message fails then Pub/Sub rejects all later messages with the same ordering key, in which case the failed messages are retried and publishing of the key is resumed. In order to preserve the order of keys across batches `+"`max_in_flight`"+` should be set to `+"`1`"+`, otherwise batches are published in parallel.`).
 		Fields(
 			service.NewStringField("project").Description("The project ID of the topic to publish to."),
 			service.NewStringField("credentials_json").
@@ -71,7 +75,7 @@ pipeline:
 				Description("An optional endpoint to override the default of `pubsub.googleapis.com:443`. This can be used to connect to a region specific pubsub endpoint. For a list of valid values, see https://cloud.google.com/pubsub/docs/reference/service_apis_overview#list_of_regional_endpoints[this document^]."),
 			service.NewInterpolatedStringField("ordering_key").
 				Optional().
-				Description("The ordering key to use for publishing messages.").
+				Description("The ordering key to use for publishing messages. Messages with an empty ordering key are published without ordering.").
 				Advanced(),
 			service.NewIntField("max_in_flight").Default(64).Description("The maximum number of messages to have in flight at a given time. Increasing this may improve throughput."),
 			service.NewIntField("count_threshold").
@@ -265,7 +269,7 @@ func (out *pubsubOutput) WriteBatch(ctx context.Context, batch service.MessageBa
 
 	for i, msg := range batch {
 		i := i
-		res, err := out.writeMessage(ctx, topics, msg)
+		topic, orderingKey, res, err := out.writeMessage(ctx, topics, msg)
 		if err != nil {
 			batchErrFailed(i, err)
 			continue
@@ -274,6 +278,12 @@ func (out *pubsubOutput) WriteBatch(ctx context.Context, batch service.MessageBa
 		p.Go(func(ctx context.Context) (*serverResult, error) {
 			_, err := res.Get(ctx)
 			if err != nil {
+				if orderingKey != "" {
+					// Publishing of an ordering key is paused after a failure
+					// until explicitly resumed, which allows the failed
+					// messages to be retried in order.
+					topic.ResumePublish(orderingKey)
+				}
 				return &serverResult{batchIndex: i, err: err}, nil
 			}
 			return nil, nil
@@ -316,10 +326,10 @@ func (out *pubsubOutput) Close(_ context.Context) error {
 	return err
 }
 
-func (out *pubsubOutput) writeMessage(ctx context.Context, cachedTopics map[string]pubsubTopic, msg *service.Message) (publishResult, error) {
+func (out *pubsubOutput) writeMessage(ctx context.Context, cachedTopics map[string]pubsubTopic, msg *service.Message) (pubsubTopic, string, publishResult, error) {
 	topicName, err := out.topicQ.TryString(msg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve topic name: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to resolve topic name: %w", err)
 	}
 
 	topic, found := cachedTopics[topicName]
@@ -327,7 +337,7 @@ func (out *pubsubOutput) writeMessage(ctx context.Context, cachedTopics map[stri
 	if !found {
 		t, err := out.getTopic(ctx, topicName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get topic: %s: %w", topicName, err)
+			return nil, "", nil, fmt.Errorf("failed to get topic: %s: %w", topicName, err)
 		}
 
 		cachedTopics[topicName] = t
@@ -349,22 +359,22 @@ func (out *pubsubOutput) writeMessage(ctx context.Context, cachedTopics map[stri
 		attr[key] = value
 		return nil
 	}); err != nil {
-		return nil, fmt.Errorf("failed to build message attributes: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to build message attributes: %w", err)
 	}
 
 	var orderingKey string
 	if out.orderingKeyQ != nil {
 		if orderingKey, err = out.orderingKeyQ.TryString(msg); err != nil {
-			return nil, fmt.Errorf("failed to build ordering key: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to build ordering key: %w", err)
 		}
 	}
 
 	data, err := msg.AsBytes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get bytes from message: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to get bytes from message: %w", err)
 	}
 
-	return topic.Publish(ctx, &pubsub.Message{
+	return topic, orderingKey, topic.Publish(ctx, &pubsub.Message{
 		Data:        data,
 		Attributes:  attr,
 		OrderingKey: orderingKey,
@@ -298,6 +298,68 @@ func TestPubSubOutput_PublishErrors(t *testing.T) {
 	require.ElementsMatch(t, []string{"simulated foo error", "simulated bar error"}, errs)
 }
 
+func TestPubSubOutput_ResumeOrderingKeyOnError(t *testing.T) {
+	ctx := t.Context()
+
+	conf, err := newPubSubOutputConfig().ParseYAML(`
+    project: sample-project
+    topic: test
+    ordering_key: '${! content().string().split("_").index(0) }'
+    `,
+		nil,
+	)
+	require.NoError(t, err, "bad output config")
+
+	client := &mockPubSubClient{}
+
+	fooTopic := &mockTopic{}
+	fooTopic.On("Exists").Return(true, nil).Once()
+	fooTopic.On("EnableOrdering").Return().Once()
+	fooTopic.On("ResumePublish", "foo").Return().Once()
+	fooTopic.On("Stop").Return().Once()
+
+	fooResA := &mockPublishResult{}
+	fooResA.On("Get").Return("", errors.New("simulated foo error")).Once()
+	fooTopic.On("Publish", "foo_a", mock.Anything).Return(fooResA).Once()
+
+	barRes := &mockPublishResult{}
+	barRes.On("Get").Return("bar", nil).Once()
+	fooTopic.On("Publish", "bar_a", mock.Anything).Return(barRes).Once()
+
+	client.On("Topic", "test").Return(fooTopic).Once()
+	client.On("Close").Return(nil).Once()
+
+	out, err := newPubSubOutput(conf)
+	require.NoError(t, err, "failed to create output")
+	out.client = client
+	t.Cleanup(func() {
+		err = out.Close(ctx)
+		require.NoError(t, err, "closing output failed")
+
+		mock.AssertExpectationsForObjects(
+			t,
+			client,
+			fooTopic,
+			fooResA, barRes,
+		)
+	})
+
+	err = out.Connect(ctx)
+	require.NoError(t, err, "connect failed")
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("foo_a")),
+		service.NewMessage([]byte("bar_a")),
+	}
+
+	err = out.WriteBatch(ctx, batch)
+	require.Error(t, err, "did not get expected publish error")
+
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr, "error is not a batch error")
+	require.Equal(t, 1, batchErr.IndexedErrors(), "did not receive expected number of batch errors")
+}
+
 func TestPubSubOutput_ValidateTopic(t *testing.T) {
 	ctx := t.Context()
 
@@ -14,6 +14,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -54,6 +55,53 @@ func commonFields() []*service.ConfigField {
 	}
 }
 
+func exportMimeTypesField(name string) *service.ConfigField {
+	return service.NewStringMapField(name).
+		Default(map[string]string{
+			// Bias towards textual formats for exports because they are easier to work with in Connect.
+			"application/vnd.google-apps.document":     "text/markdown",
+			"application/vnd.google-apps.spreadsheet":  "text/csv",
+			"application/vnd.google-apps.presentation": "application/pdf",
+			"application/vnd.google-apps.drawing":      "image/png",
+			"application/vnd.google-apps.script":       "application/vnd.google-apps.script+json",
+		}).
+		Description("A map of Google Drive MIME types to their export formats. The key is the MIME type, and the value is the export format. See https://developers.google.com/workspace/drive/api/guides/ref-export-formats[^Google Drive API Documentation] for a list of supported export types").
+		Example(map[string]string{
+			"application/vnd.google-apps.document":     "application/pdf",
+			"application/vnd.google-apps.spreadsheet":  "application/pdf",
+			"application/vnd.google-apps.presentation": "application/pdf",
+			"application/vnd.google-apps.drawing":      "application/pdf",
+		}).
+		Example(map[string]string{
+			"application/vnd.google-apps.document":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			"application/vnd.google-apps.spreadsheet":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			"application/vnd.google-apps.presentation": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+			"application/vnd.google-apps.drawing":      "image/svg+xml",
+		}).
+		Advanced()
+}
+
+func exportMimeTypesFromParsed(conf *service.ParsedConfig, name string) (map[string]string, error) {
+	mimeTypes, err := conf.FieldStringMap(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for mimeType, exportMimeType := range mimeTypes {
+		formats, ok := googleMimeToFormat[mimeType]
+		if !ok {
+			return nil, fmt.Errorf("export is only valid for Google App file types, got: %v", mimeType)
+		}
+		ok = slices.ContainsFunc(formats.ExportTypes, func(et exportType) bool {
+			return et.MimeType == exportMimeType
+		})
+		if !ok {
+			return nil, fmt.Errorf("export mime type %v is not supported for mime type %v", exportMimeType, mimeType)
+		}
+	}
+	return mimeTypes, nil
+}
+
 type baseProcessor[Service any] struct {
 	credentialsJSON string
 
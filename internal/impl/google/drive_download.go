@@ -14,7 +14,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"slices"
 
 	"google.golang.org/api/drive/v3"
 
@@ -50,29 +49,7 @@ Can download a file from Google Drive based on a file ID.
 				Description("The file ID of the file to download."),
 			service.NewInterpolatedStringField(driveDownloadFieldMimeType).
 				Description("The mime type of the file in drive."),
-			service.NewStringMapField(driveDownloadFieldExportMimeTypes).
-				Default(map[string]string{
-					// Bias towards textual formats for exports because they are easier to work with in Connect.
-					"application/vnd.google-apps.document":     "text/markdown",
-					"application/vnd.google-apps.spreadsheet":  "text/csv",
-					"application/vnd.google-apps.presentation": "application/pdf",
-					"application/vnd.google-apps.drawing":      "image/png",
-					"application/vnd.google-apps.script":       "application/vnd.google-apps.script+json",
-				}).
-				Description("A map of Google Drive MIME types to their export formats. The key is the MIME type, and the value is the export format. See https://developers.google.com/workspace/drive/api/guides/ref-export-formats[^Google Drive API Documentation] for a list of supported export types").
-				Example(map[string]string{
-					"application/vnd.google-apps.document":     "application/pdf",
-					"application/vnd.google-apps.spreadsheet":  "application/pdf",
-					"application/vnd.google-apps.presentation": "application/pdf",
-					"application/vnd.google-apps.drawing":      "application/pdf",
-				}).
-				Example(map[string]string{
-					"application/vnd.google-apps.document":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-					"application/vnd.google-apps.spreadsheet":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
-					"application/vnd.google-apps.presentation": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
-					"application/vnd.google-apps.drawing":      "image/svg+xml",
-				}).
-				Advanced(),
+			exportMimeTypesField(driveDownloadFieldExportMimeTypes),
 		).
 		Example("Download files from Google Drive", "This examples downloads all the files from Google Drive", `
 pipeline:
@@ -109,24 +86,11 @@ func newGoogleDriveDownloadProcessor(conf *service.ParsedConfig, mgr *service.Re
 		return nil, err
 	}
 
-	mimeTypes, err := conf.FieldStringMap(driveDownloadFieldExportMimeTypes)
+	mimeTypes, err := exportMimeTypesFromParsed(conf, driveDownloadFieldExportMimeTypes)
 	if err != nil {
 		return nil, err
 	}
 
-	for mimeType, exportMimeType := range mimeTypes {
-		formats, ok := googleMimeToFormat[mimeType]
-		if !ok {
-			return nil, fmt.Errorf("export is only valid for Google App file types, got: %v", mimeType)
-		}
-		ok = slices.ContainsFunc(formats.ExportTypes, func(et exportType) bool {
-			return et.MimeType == exportMimeType
-		})
-		if !ok {
-			return nil, fmt.Errorf("export mime type %v is not supported for mime type %v", exportMimeType, mimeType)
-		}
-	}
-
 	return &googleDriveDownloadProcessor{
 		baseProcessor:   base,
 		fileID:          fileID,
@@ -164,7 +128,7 @@ func (g *googleDriveDownloadProcessor) Process(ctx context.Context, msg *service
 }
 
 func downloadFile(ctx context.Context, srv *drive.Service, fileID string) ([]byte, error) {
-	resp, err := srv.Files.Get(fileID).Context(ctx).Download()
+	resp, err := srv.Files.Get(fileID).SupportsAllDrives(true).Context(ctx).Download()
 	if err != nil {
 		return nil, fmt.Errorf("unable to download file: %v", err)
 	}
@@ -0,0 +1,488 @@
+/*
+ * Copyright 2025 Redpanda Data, Inc.
+ *
+ * Licensed as a Redpanda Enterprise file under the Redpanda Community
+ * License (the "License"); you may not use this file except in compliance with
+ * the License. You may obtain a copy of the License at
+ *
+ * https://github.com/redpanda-data/redpanda/blob/master/licenses/rcl.md
+ */
+
+package google
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/checkpoint"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+const (
+	driveInputFieldDriveID         = "drive_id"
+	driveInputFieldMimeTypes       = "mime_types"
+	driveInputFieldExportMimeTypes = "export_mime_types"
+	driveInputFieldInitialSync     = "initial_sync"
+	driveInputFieldIncludeDeleted  = "include_deleted"
+	driveInputFieldPollInterval    = "poll_interval"
+	driveInputFieldCache           = "cache"
+	driveInputFieldCacheKey        = "cache_key"
+
+	driveFolderMimeType   = "application/vnd.google-apps.folder"
+	driveShortcutMimeType = "application/vnd.google-apps.shortcut"
+	driveFileFields       = "id,name,mimeType,size,createdTime,modifiedTime,webViewLink,md5Checksum,version,owners(emailAddress),parents,trashed"
+)
+
+func init() {
+	service.MustRegisterInput(
+		"google_drive",
+		driveInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			r, err := newGoogleDriveInput(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, r)
+		},
+	)
+}
+
+func driveInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("Unstructured").
+		Version("4.62.0").
+		Summary("Consumes files from Google Drive, continuously syncing files as they are created, updated or deleted.").
+		Description(`
+Each file is emitted as a message containing its contents, where native Google Workspace files such as documents and spreadsheets are exported using the formats configured in `+"`"+driveInputFieldExportMimeTypes+"`"+`. Folders and shortcuts are skipped. Files that cannot be downloaded are emitted without contents and flagged with an error, which can be handled with xref:configuration:error_handling.adoc[error handling methods].
+
+By default all existing files are consumed when the input first starts, after which the https://developers.google.com/workspace/drive/api/guides/manage-changes[^changes API] is polled for changes. The change token of the latest fully acknowledged page of changes is stored in a `+"`"+driveInputFieldCache+"`"+` resource, and subsequent runs resume syncing from that token. Without a cache every run begins from scratch.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+- google_drive_file_id
+- google_drive_name
+- google_drive_mime_type
+- google_drive_export_mime_type
+- google_drive_size
+- google_drive_created_time
+- google_drive_modified_time
+- google_drive_web_view_link
+- google_drive_md5_checksum
+- google_drive_version
+- google_drive_owners
+- google_drive_parents
+- google_drive_deleted
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+`+authDescription("https://www.googleapis.com/auth/drive.readonly")).
+		Fields(commonFields()...).
+		Fields(
+			service.NewStringField(driveInputFieldDriveID).
+				Description("The ID of a shared drive to sync. If left unset then all files accessible by the credentials are synced.").
+				Optional(),
+			service.NewStringListField(driveInputFieldMimeTypes).
+				Description("An optional list of MIME types to restrict the files consumed to, matched against the MIME type of each file within Google Drive.").
+				Example([]string{"application/pdf", "application/vnd.google-apps.document"}).
+				Default([]string{}),
+			exportMimeTypesField(driveInputFieldExportMimeTypes),
+			service.NewBoolField(driveInputFieldInitialSync).
+				Description("Whether to consume all existing files before syncing changes when no change token has been stored in the cache. When disabled only changes made after the input first starts are consumed.").
+				Default(true),
+			service.NewBoolField(driveInputFieldIncludeDeleted).
+				Description("Whether to emit an empty message with the metadata field `google_drive_deleted` set to `true` for each file that is deleted or trashed, which is useful for removing documents from downstream stores.").
+				Default(false),
+			service.NewDurationField(driveInputFieldPollInterval).
+				Description("The interval between each poll for changes once all pending changes have been consumed.").
+				Default("1m"),
+			service.NewStringField(driveInputFieldCache).
+				Description("A xref:components:caches/about.adoc[cache resource] for storing the change token of the latest consumed changes.").
+				Default(""),
+			service.NewStringField(driveInputFieldCacheKey).
+				Description("The key identifier used when storing the change token.").
+				Default("google_drive_change_token").
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Sync a shared drive", "Consumes every document in a shared drive as Markdown or PDF followed by any subsequent changes, storing the change token in Redis so that restarts resume where they left off.", `
+input:
+  google_drive:
+    drive_id: 0ABcDeFgHiJkLmNoPqR
+    mime_types:
+      - application/pdf
+      - application/vnd.google-apps.document
+    include_deleted: true
+    cache: drive_tokens
+
+cache_resources:
+  - label: drive_tokens
+    redis:
+      url: redis://localhost:6379
+`)
+}
+
+// driveChange is a file that was listed, created, updated or deleted.
+type driveChange struct {
+	fileID  string
+	file    *drive.File
+	removed bool
+}
+
+type googleDriveInput struct {
+	*baseProcessor[drive.Service]
+	mgr *service.Resources
+	log *service.Logger
+
+	driveID         string
+	mimeTypes       []string
+	exportMimeTypes map[string]string
+	initialSync     bool
+	includeDeleted  bool
+	pollInterval    time.Duration
+	cache           string
+	cacheKey        string
+
+	checkpointer *checkpoint.Capped[string]
+
+	mut       sync.Mutex
+	connected bool
+	// The change token from which changes are consumed once the initial
+	// listing of files is complete, and whether that listing is in progress.
+	startToken string
+	listing    bool
+	// The token of the page currently being consumed, the token of the next
+	// page and the token to checkpoint once the current page is consumed.
+	pageToken string
+	nextToken string
+	boundary  string
+	caughtUp  bool
+	nextPoll  time.Time
+	pending   []driveChange
+}
+
+func newGoogleDriveInput(conf *service.ParsedConfig, mgr *service.Resources) (*googleDriveInput, error) {
+	if err := license.CheckRunningEnterprise(mgr); err != nil {
+		return nil, err
+	}
+	base, err := newBaseDriveProcessor(conf)
+	if err != nil {
+		return nil, err
+	}
+	g := &googleDriveInput{
+		baseProcessor: base,
+		mgr:           mgr,
+		log:           mgr.Logger(),
+		checkpointer:  checkpoint.NewCapped[string](1024),
+	}
+	if conf.Contains(driveInputFieldDriveID) {
+		if g.driveID, err = conf.FieldString(driveInputFieldDriveID); err != nil {
+			return nil, err
+		}
+	}
+	if g.mimeTypes, err = conf.FieldStringList(driveInputFieldMimeTypes); err != nil {
+		return nil, err
+	}
+	if g.exportMimeTypes, err = exportMimeTypesFromParsed(conf, driveInputFieldExportMimeTypes); err != nil {
+		return nil, err
+	}
+	if g.initialSync, err = conf.FieldBool(driveInputFieldInitialSync); err != nil {
+		return nil, err
+	}
+	if g.includeDeleted, err = conf.FieldBool(driveInputFieldIncludeDeleted); err != nil {
+		return nil, err
+	}
+	if g.pollInterval, err = conf.FieldDuration(driveInputFieldPollInterval); err != nil {
+		return nil, err
+	}
+	if g.cache, err = conf.FieldString(driveInputFieldCache); err != nil {
+		return nil, err
+	}
+	if g.cacheKey, err = conf.FieldString(driveInputFieldCacheKey); err != nil {
+		return nil, err
+	}
+	if g.cache != "" && !mgr.HasCache(g.cache) {
+		return nil, fmt.Errorf("cache resource %q was not found", g.cache)
+	}
+	return g, nil
+}
+
+func (g *googleDriveInput) Connect(ctx context.Context) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.connected {
+		return nil
+	}
+
+	client, err := g.getDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	var token string
+	if g.cache != "" {
+		var cacheErr error
+		if err := g.mgr.AccessCache(ctx, g.cache, func(c service.Cache) {
+			var b []byte
+			if b, cacheErr = c.Get(ctx, g.cacheKey); errors.Is(cacheErr, service.ErrKeyNotFound) {
+				cacheErr = nil
+			}
+			token = string(b)
+		}); err != nil {
+			return err
+		}
+		if cacheErr != nil {
+			return fmt.Errorf("failed to obtain stored change token: %w", cacheErr)
+		}
+	}
+
+	if token == "" {
+		call := client.Changes.GetStartPageToken().SupportsAllDrives(true).Context(ctx)
+		if g.driveID != "" {
+			call = call.DriveId(g.driveID)
+		}
+		res, err := call.Do()
+		if err != nil {
+			return fmt.Errorf("failed to obtain start page token: %w", err)
+		}
+		g.startToken = res.StartPageToken
+		g.listing = g.initialSync
+	} else {
+		g.startToken = token
+		g.listing = false
+	}
+	g.nextToken = ""
+	if !g.listing {
+		g.nextToken = g.startToken
+	}
+	g.pageToken, g.boundary, g.caughtUp = "", "", false
+	g.pending = nil
+	g.connected = true
+	return nil
+}
+
+func (g *googleDriveInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if !g.connected {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	for {
+		for len(g.pending) > 0 {
+			change := g.pending[0]
+			g.pending = g.pending[1:]
+			if change.removed && !g.includeDeleted {
+				continue
+			}
+
+			msg := g.changeToMessage(ctx, change)
+			release, err := g.checkpointer.Track(ctx, g.pageToken, 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			return msg, func(ctx context.Context, _ error) error {
+				return g.storeToken(ctx, release())
+			}, nil
+		}
+
+		if err := g.fetchPage(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// fetchPage obtains the next page of files or changes, waiting for the poll
+// interval when all changes have been consumed.
+func (g *googleDriveInput) fetchPage(ctx context.Context) error {
+	if g.boundary != "" {
+		if err := g.checkpoint(ctx, g.boundary); err != nil {
+			return err
+		}
+		g.boundary = ""
+	}
+
+	client, err := g.getDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if g.listing {
+		call := client.Files.List().
+			Context(ctx).
+			PageSize(100).
+			Q("trashed = false").
+			Fields("nextPageToken", googleapi.Field("files("+driveFileFields+")"))
+		if g.driveID != "" {
+			call = call.Corpora("drive").DriveId(g.driveID).SupportsAllDrives(true).IncludeItemsFromAllDrives(true)
+		}
+		if g.nextToken != "" {
+			call = call.PageToken(g.nextToken)
+		}
+		res, err := call.Do()
+		if err != nil {
+			return fmt.Errorf("failed to list files: %w", err)
+		}
+
+		// Tokens of file listings cannot be used to resume syncing and so
+		// nothing is stored until the listing is complete.
+		g.pageToken = ""
+		g.pending = g.pending[:0]
+		for _, f := range res.Files {
+			if g.keepFile(f) {
+				g.pending = append(g.pending, driveChange{fileID: f.Id, file: f})
+			}
+		}
+		if g.nextToken = res.NextPageToken; g.nextToken == "" {
+			g.listing = false
+			g.nextToken = g.startToken
+			g.boundary = g.startToken
+		}
+		return nil
+	}
+
+	if g.caughtUp {
+		select {
+		case <-time.After(time.Until(g.nextPoll)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	call := client.Changes.List(g.nextToken).
+		Context(ctx).
+		PageSize(100).
+		IncludeRemoved(true).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Fields("nextPageToken", "newStartPageToken", googleapi.Field("changes(fileId,removed,file("+driveFileFields+"))"))
+	if g.driveID != "" {
+		call = call.DriveId(g.driveID)
+	}
+	res, err := call.Do()
+	if err != nil {
+		return fmt.Errorf("failed to list changes: %w", err)
+	}
+
+	g.pageToken = g.nextToken
+	g.pending = g.pending[:0]
+	for _, c := range res.Changes {
+		if c.Removed || (c.File != nil && c.File.Trashed) {
+			g.pending = append(g.pending, driveChange{fileID: c.FileId, file: c.File, removed: true})
+		} else if c.File != nil && g.keepFile(c.File) {
+			g.pending = append(g.pending, driveChange{fileID: c.FileId, file: c.File})
+		}
+	}
+
+	if g.caughtUp = res.NextPageToken == ""; g.caughtUp {
+		g.nextToken = res.NewStartPageToken
+		g.nextPoll = time.Now().Add(g.pollInterval)
+	} else {
+		g.nextToken = res.NextPageToken
+	}
+	g.boundary = g.nextToken
+	return nil
+}
+
+func (g *googleDriveInput) keepFile(f *drive.File) bool {
+	if f.MimeType == driveFolderMimeType || f.MimeType == driveShortcutMimeType {
+		return false
+	}
+	return len(g.mimeTypes) == 0 || slices.Contains(g.mimeTypes, f.MimeType)
+}
+
+// checkpoint marks a token as reached once all messages emitted before it are
+// acknowledged.
+func (g *googleDriveInput) checkpoint(ctx context.Context, token string) error {
+	release, err := g.checkpointer.Track(ctx, token, 1)
+	if err != nil {
+		return err
+	}
+	return g.storeToken(ctx, release())
+}
+
+func (g *googleDriveInput) storeToken(ctx context.Context, token *string) error {
+	if token == nil || *token == "" || g.cache == "" {
+		return nil
+	}
+	var setErr error
+	if err := g.mgr.AccessCache(ctx, g.cache, func(c service.Cache) {
+		setErr = c.Set(ctx, g.cacheKey, []byte(*token), nil)
+	}); err != nil {
+		return err
+	}
+	return setErr
+}
+
+func (g *googleDriveInput) changeToMessage(ctx context.Context, change driveChange) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.MetaSetMut("google_drive_file_id", change.fileID)
+	msg.MetaSetMut("google_drive_deleted", change.removed)
+
+	f := change.file
+	if f == nil {
+		return msg
+	}
+	msg.MetaSetMut("google_drive_name", f.Name)
+	msg.MetaSetMut("google_drive_mime_type", f.MimeType)
+	msg.MetaSetMut("google_drive_size", f.Size)
+	msg.MetaSetMut("google_drive_created_time", f.CreatedTime)
+	msg.MetaSetMut("google_drive_modified_time", f.ModifiedTime)
+	msg.MetaSetMut("google_drive_web_view_link", f.WebViewLink)
+	msg.MetaSetMut("google_drive_md5_checksum", f.Md5Checksum)
+	msg.MetaSetMut("google_drive_version", f.Version)
+	owners := make([]any, 0, len(f.Owners))
+	for _, o := range f.Owners {
+		owners = append(owners, o.EmailAddress)
+	}
+	msg.MetaSetMut("google_drive_owners", owners)
+	parents := make([]any, 0, len(f.Parents))
+	for _, p := range f.Parents {
+		parents = append(parents, p)
+	}
+	msg.MetaSetMut("google_drive_parents", parents)
+	if change.removed {
+		return msg
+	}
+
+	client, err := g.getDriveService(ctx)
+	if err != nil {
+		msg.SetError(err)
+		return msg
+	}
+
+	var b []byte
+	if exportMimeType, ok := g.exportMimeTypes[f.MimeType]; ok {
+		msg.MetaSetMut("google_drive_export_mime_type", exportMimeType)
+		b, err = exportFile(ctx, client, f.Id, exportMimeType)
+	} else if strings.HasPrefix(f.MimeType, "application/vnd.google-apps.") {
+		err = fmt.Errorf("no export format is configured for mime type %v", f.MimeType)
+	} else {
+		b, err = downloadFile(ctx, client, f.Id)
+	}
+	if err != nil {
+		g.log.With("error", err, "file_id", f.Id).Warn("Failed to download file")
+		msg.SetError(fmt.Errorf("failed to download file %v: %w", f.Id, err))
+		return msg
+	}
+	msg.SetBytes(b)
+	return msg
+}
+
+func (g *googleDriveInput) Close(context.Context) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	g.connected = false
+	return nil
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2025 Redpanda Data, Inc.
+ *
+ * Licensed as a Redpanda Enterprise file under the Redpanda Community
+ * License (the "License"); you may not use this file except in compliance with
+ * the License. You may obtain a copy of the License at
+ *
+ * https://github.com/redpanda-data/redpanda/blob/master/licenses/rcl.md
+ */
+
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/license"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func TestDriveInputSync(t *testing.T) {
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /changes/startPageToken", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"startPageToken": "10"})
+	})
+	mux.HandleFunc("GET /files", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "" {
+			writeJSON(w, map[string]any{
+				"nextPageToken": "p2",
+				"files": []any{
+					map[string]any{"id": "a", "name": "a.pdf", "mimeType": "application/pdf", "size": "9", "owners": []any{map[string]any{"emailAddress": "foo@example.com"}}},
+					map[string]any{"id": "dir", "name": "dir", "mimeType": driveFolderMimeType},
+					map[string]any{"id": "doc", "name": "doc", "mimeType": "application/vnd.google-apps.document"},
+				},
+			})
+			return
+		}
+		writeJSON(w, map[string]any{
+			"files": []any{
+				map[string]any{"id": "b", "name": "b.txt", "mimeType": "text/plain"},
+			},
+		})
+	})
+	mux.HandleFunc("GET /changes", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.URL.Query().Get("pageToken"))
+		writeJSON(w, map[string]any{
+			"newStartPageToken": "11",
+			"changes": []any{
+				map[string]any{"fileId": "c", "removed": true},
+				map[string]any{"fileId": "b", "file": map[string]any{"id": "b", "name": "b.txt", "mimeType": "text/plain"}},
+			},
+		})
+	})
+	contents := map[string]string{"a": "A content", "b": "B content"}
+	mux.HandleFunc("GET /files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "media", r.URL.Query().Get("alt"))
+		_, _ = w.Write([]byte(contents[r.PathValue("id")]))
+	})
+	mux.HandleFunc("GET /files/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "doc", r.PathValue("id"))
+		assert.Equal(t, "text/markdown", r.URL.Query().Get("mimeType"))
+		_, _ = w.Write([]byte("# Doc"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	conf, err := driveInputConfig().ParseYAML(`
+include_deleted: true
+poll_interval: 1h
+cache: tokens
+`, nil)
+	require.NoError(t, err)
+
+	resBuilder := service.NewResourceBuilder()
+	require.NoError(t, resBuilder.AddCacheYAML(`
+label: tokens
+memory: {}
+`))
+	mgr, stop, err := resBuilder.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stop(t.Context()) })
+	license.InjectTestService(mgr)
+
+	in, err := newGoogleDriveInput(conf, mgr)
+	require.NoError(t, err)
+	in.service, err = drive.NewService(t.Context(), option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(t.Context()))
+
+	storedToken := func() string {
+		var b []byte
+		require.NoError(t, mgr.AccessCache(t.Context(), "tokens", func(c service.Cache) {
+			b, _ = c.Get(t.Context(), in.cacheKey)
+		}))
+		return string(b)
+	}
+
+	type read struct {
+		id      string
+		content string
+		deleted bool
+		ackFn   service.AckFunc
+	}
+	readNext := func() read {
+		t.Helper()
+		msg, ackFn, err := in.Read(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, msg.GetError())
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		id, _ := msg.MetaGetMut("google_drive_file_id")
+		deleted, _ := msg.MetaGetMut("google_drive_deleted")
+		return read{id: id.(string), content: string(b), deleted: deleted.(bool), ackFn: ackFn}
+	}
+
+	a := readNext()
+	assert.Equal(t, "a", a.id)
+	assert.Equal(t, "A content", a.content)
+
+	doc := readNext()
+	assert.Equal(t, "doc", doc.id)
+	assert.Equal(t, "# Doc", doc.content)
+
+	b := readNext()
+	assert.Equal(t, "b", b.id)
+	assert.Equal(t, "B content", b.content)
+
+	require.NoError(t, a.ackFn(t.Context(), nil))
+	require.NoError(t, doc.ackFn(t.Context(), nil))
+
+	// The start token is only stored once every listed file is acknowledged.
+	c := readNext()
+	assert.Equal(t, "c", c.id)
+	assert.True(t, c.deleted)
+	assert.Empty(t, c.content)
+	assert.Empty(t, storedToken())
+
+	require.NoError(t, b.ackFn(t.Context(), nil))
+	assert.Equal(t, "10", storedToken())
+
+	b2 := readNext()
+	assert.Equal(t, "b", b2.id)
+	assert.False(t, b2.deleted)
+	require.NoError(t, c.ackFn(t.Context(), nil))
+	require.NoError(t, b2.ackFn(t.Context(), nil))
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Millisecond*50)
+	defer cancel()
+	_, _, err = in.Read(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "11", storedToken())
+
+	require.NoError(t, in.Close(t.Context()))
+}
+
+func TestDriveInputMetadata(t *testing.T) {
+	in := &googleDriveInput{}
+	msg := in.changeToMessage(t.Context(), driveChange{
+		fileID:  "a",
+		removed: true,
+		file: &drive.File{
+			Id:       "a",
+			Name:     "a.pdf",
+			MimeType: "application/pdf",
+			Size:     9,
+			Owners:   []*drive.User{{EmailAddress: "foo@example.com"}},
+			Parents:  []string{"root"},
+		},
+	})
+
+	var meta = map[string]any{}
+	require.NoError(t, msg.MetaWalkMut(func(k string, v any) error {
+		meta[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]any{
+		"google_drive_file_id":       "a",
+		"google_drive_deleted":       true,
+		"google_drive_name":          "a.pdf",
+		"google_drive_mime_type":     "application/pdf",
+		"google_drive_size":          int64(9),
+		"google_drive_created_time":  "",
+		"google_drive_modified_time": "",
+		"google_drive_web_view_link": "",
+		"google_drive_md5_checksum":  "",
+		"google_drive_version":       int64(0),
+		"google_drive_owners":        []any{"foo@example.com"},
+		"google_drive_parents":       []any{"root"},
+	}, meta)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	fieldImportPaths   = "import_paths"
+	fieldFormat        = "format"
+	fieldUseProtoNames = "use_proto_names"
+)
+
+func importPathsField() *service.ConfigField {
+	return service.NewStringListField(fieldImportPaths).
+		Description("A list of directories containing .proto files, including all definitions required by the served or called methods. Each directory listed will be walked with all found .proto files imported.").
+		Default([]string{})
+}
+
+func formatFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringAnnotatedEnumField(fieldFormat, map[string]string{
+			"json":     "Messages are JSON documents following the https://protobuf.dev/programming-guides/json/[protobuf JSON mapping^].",
+			"protobuf": "Messages are binary encoded protobuf messages.",
+		}).
+			Description("The format of message contents.").
+			Default("json"),
+		service.NewBoolField(fieldUseProtoNames).
+			Description("Whether JSON documents use the field names of the .proto files rather than their lower camel case equivalents.").
+			Default(false).
+			Advanced(),
+	}
+}
+
+// messageCodec converts between message contents and protobuf messages.
+type messageCodec struct {
+	format        string
+	useProtoNames bool
+	types         *protoregistry.Types
+}
+
+func messageCodecFromParsed(conf *service.ParsedConfig, types *protoregistry.Types) (c messageCodec, err error) {
+	if c.format, err = conf.FieldString(fieldFormat); err != nil {
+		return
+	}
+	if c.useProtoNames, err = conf.FieldBool(fieldUseProtoNames); err != nil {
+		return
+	}
+	if types == nil {
+		types = protoregistry.GlobalTypes
+	}
+	c.types = types
+	return
+}
+
+func (c messageCodec) marshal(m proto.Message) ([]byte, error) {
+	if c.format == "protobuf" {
+		return proto.Marshal(m)
+	}
+	return protojson.MarshalOptions{
+		Resolver:      c.types,
+		UseProtoNames: c.useProtoNames,
+	}.Marshal(m)
+}
+
+func (c messageCodec) unmarshal(b []byte, m proto.Message) error {
+	if c.format == "protobuf" {
+		return proto.Unmarshal(b, m)
+	}
+	return protojson.UnmarshalOptions{Resolver: c.types}.Unmarshal(b, m)
+}
+
+// splitMethod parses a method of the form package.Service/Method, with an
+// optional leading slash.
+func splitMethod(method string) (service, name string, err error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok || service == "" || name == "" {
+		return "", "", fmt.Errorf("method %q must be of the form package.Service/Method", method)
+	}
+	return service, name, nil
+}
+
+func findMethod(sd protoreflect.ServiceDescriptor, name string) (protoreflect.MethodDescriptor, error) {
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("method %v not found in service %v", name, sd.FullName())
+	}
+	if md.IsStreamingClient() {
+		return nil, fmt.Errorf("method %v/%v is client streaming, which is not supported", sd.FullName(), name)
+	}
+	return md, nil
+}
+
+// metadataToMessage adds the (first) values of gRPC metadata to a message,
+// skipping pseudo headers.
+func metadataToMessage(md metadata.MD, msg *service.Message) {
+	for k, v := range md {
+		if len(v) > 0 && !strings.HasPrefix(k, ":") {
+			msg.MetaSetMut(k, v[0])
+		}
+	}
+}
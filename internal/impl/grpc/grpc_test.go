@@ -0,0 +1,194 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const testProto = `
+syntax = "proto3";
+package testing;
+
+message EchoRequest {
+  string text = 1;
+  int32 times = 2;
+}
+
+message EchoResponse {
+  string text = 1;
+}
+
+service Echo {
+  rpc Say(EchoRequest) returns (EchoResponse);
+  rpc Repeat(EchoRequest) returns (stream EchoResponse);
+  rpc Collect(stream EchoRequest) returns (EchoResponse);
+}
+`
+
+// echoServer consumes requests from a server input and responds to each of
+// them by echoing the text of the request, repeated for streaming methods.
+func echoServer(t *testing.T, importPath string) *serverInput {
+	t.Helper()
+
+	conf, err := serverInputSpec().ParseYAML(fmt.Sprintf(`
+address: 127.0.0.1:0
+import_paths: [ %v ]
+response_metadata:
+  include_prefixes: [ x- ]
+`, importPath), nil)
+	require.NoError(t, err)
+
+	in, err := newServerInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(t.Context()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, in.Close(context.Background()))
+	})
+
+	go func() {
+		for {
+			msg, ackFn, err := in.Read(ctx)
+			if err != nil {
+				return
+			}
+
+			var req struct {
+				Text  string `json:"text"`
+				Times int    `json:"times"`
+			}
+			b, _ := msg.AsBytes()
+			if err := json.Unmarshal(b, &req); err != nil || req.Text == "fail" {
+				_ = ackFn(ctx, errors.New("echo rejected"))
+				continue
+			}
+
+			method, _ := msg.MetaGet("grpc_method")
+			caller, _ := msg.MetaGet("x-caller")
+			var responses service.MessageBatch
+			for range max(req.Times, 1) {
+				res := msg.Copy()
+				res.SetStructured(map[string]any{"text": fmt.Sprintf("%v:%v:%v", method, caller, req.Text)})
+				res.MetaSetMut("x-served-by", "echo")
+				responses = append(responses, res)
+			}
+			_ = responses.AddSyncResponse()
+			_ = ackFn(ctx, nil)
+		}
+	}()
+	return in
+}
+
+func callEcho(t *testing.T, in *serverInput, extraConf, payload string) []string {
+	t.Helper()
+
+	conf, err := clientOutputSpec().ParseYAML(fmt.Sprintf(`
+address: %v
+propagate_response: true
+metadata:
+  x-caller: ${! @caller }
+%v
+`, in.listener.Addr().String(), extraConf), nil)
+	require.NoError(t, err)
+
+	out, err := newClientOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() { _ = out.Close(context.Background()) })
+
+	msg := service.NewMessage([]byte(payload))
+	msg.MetaSetMut("caller", "tester")
+	batch, store := service.MessageBatch{msg}.WithSyncResponseStore()
+	require.NoError(t, out.Write(t.Context(), batch[0]))
+
+	var results []string
+	for _, resBatch := range store.Read() {
+		for _, res := range resBatch {
+			b, err := res.AsBytes()
+			require.NoError(t, err)
+			results = append(results, string(b))
+
+			servedBy, _ := res.MetaGet("x-served-by")
+			assert.Equal(t, "echo", servedBy)
+		}
+	}
+	return results
+}
+
+func TestGRPCServerAndClient(t *testing.T) {
+	importPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(importPath, "echo.proto"), []byte(testProto), 0o644))
+
+	in := echoServer(t, importPath)
+
+	t.Run("unary with reflection", func(t *testing.T) {
+		assert.Equal(t, []string{`{"text":"Say:tester:hello"}`},
+			callEcho(t, in, `method: testing.Echo/Say`, `{"text":"hello"}`))
+	})
+
+	t.Run("server streaming with import paths", func(t *testing.T) {
+		assert.Equal(t, []string{
+			`{"text":"Repeat:tester:hi"}`,
+			`{"text":"Repeat:tester:hi"}`,
+			`{"text":"Repeat:tester:hi"}`,
+		}, callEcho(t, in, fmt.Sprintf("method: /testing.Echo/Repeat\nimport_paths: [ %v ]", importPath), `{"text":"hi","times":3}`))
+	})
+
+	t.Run("client streaming is rejected", func(t *testing.T) {
+		conf, err := clientOutputSpec().ParseYAML(fmt.Sprintf(`
+address: %v
+method: testing.Echo/Collect
+`, in.listener.Addr().String()), nil)
+		require.NoError(t, err)
+
+		out, err := newClientOutputFromConfig(conf, service.MockResources())
+		require.NoError(t, err)
+
+		ctx, done := context.WithTimeout(t.Context(), time.Second*10)
+		defer done()
+		require.ErrorContains(t, out.Connect(ctx), "client streaming")
+	})
+
+	t.Run("rejected messages", func(t *testing.T) {
+		conf, err := clientOutputSpec().ParseYAML(fmt.Sprintf(`
+address: %v
+method: testing.Echo/Say
+`, in.listener.Addr().String()), nil)
+		require.NoError(t, err)
+
+		out, err := newClientOutputFromConfig(conf, service.MockResources())
+		require.NoError(t, err)
+		require.NoError(t, out.Connect(t.Context()))
+		t.Cleanup(func() { _ = out.Close(context.Background()) })
+
+		err = out.Write(t.Context(), service.NewMessage([]byte(`{"text":"fail"}`)))
+		require.ErrorContains(t, err, "echo rejected")
+	})
+}
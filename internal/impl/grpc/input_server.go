@@ -0,0 +1,428 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Jeffail/shutdown"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/protobuf"
+)
+
+const (
+	gsiFieldAddress          = "address"
+	gsiFieldServices         = "services"
+	gsiFieldReflection       = "reflection"
+	gsiFieldCertFile         = "cert_file"
+	gsiFieldKeyFile          = "key_file"
+	gsiFieldResponseMetadata = "response_metadata"
+)
+
+func serverInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Runs a gRPC server that consumes the requests of methods described by protobuf definitions.").
+		Description(`
+Each request received is consumed as a message, and the methods served are those of the services defined within the .proto files found in `+"`"+fieldImportPaths+"`"+`, optionally limited to the services listed in `+"`"+gsiFieldServices+"`"+`. When `+"`"+gsiFieldReflection+"`"+` is enabled the server also implements the https://github.com/grpc/grpc/blob/master/doc/server-reflection.md[gRPC server reflection protocol^], allowing clients to discover the served methods and their descriptors.
+
+== Responses
+
+Responses are returned from xref:guides:sync_responses.adoc[synchronous responses], where the contents of each response message are converted into the output type of the method. Unary methods return the first response message, or an empty message when there is no response, and server streaming methods send each response message to the stream. Client and bidirectional streaming methods are not supported.
+
+The metadata of the first response message selected by `+"`"+gsiFieldResponseMetadata+"`"+` is returned to the client as response headers. When a message is rejected by the pipeline the call fails with the status code `+"`UNAVAILABLE`"+`.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- grpc_service
+- grpc_method
+- All request metadata (only first values are taken)
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(
+			service.NewStringField(gsiFieldAddress).
+				Description("The address to listen on.").
+				Default("0.0.0.0:50051"),
+			importPathsField(),
+			service.NewStringListField(gsiFieldServices).
+				Description("An optional list of fully qualified service names to serve. When empty all services found within `import_paths` are served.").
+				Example([]string{"helloworld.Greeter"}).
+				Default([]string{}),
+			service.NewBoolField(gsiFieldReflection).
+				Description("Whether to serve the gRPC server reflection service.").
+				Default(true),
+		).
+		Fields(formatFields()...).
+		Fields(
+			service.NewStringField(gsiFieldCertFile).
+				Description("An optional certificate file for enabling TLS.").
+				Default("").
+				Advanced(),
+			service.NewStringField(gsiFieldKeyFile).
+				Description("An optional key file for enabling TLS.").
+				Default("").
+				Advanced(),
+			service.NewMetadataFilterField(gsiFieldResponseMetadata).
+				Description("Specify criteria for which metadata values of responses are returned as response headers.").
+				Advanced(),
+		).
+		Example(
+			"Greeter",
+			"Consume the requests of a greeter service and reply to each of them.",
+			`
+input:
+  grpc_server:
+    address: 0.0.0.0:50051
+    import_paths: [ ./protos ]
+    services: [ helloworld.Greeter ]
+
+  processors:
+    - mapping: 'root.message = "Hello " + this.name'
+    - sync_response: {}
+
+output:
+  drop: {}
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterInput("grpc_server", serverInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			return newServerInputFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type serverRequest struct {
+	msg *service.Message
+	res chan error
+}
+
+// serviceInfo advertises the served services to the reflection service, as
+// they're handled dynamically and therefore unknown to the gRPC server.
+type serviceInfo map[string]grpc.ServiceInfo
+
+func (s serviceInfo) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return s
+}
+
+type serverInput struct {
+	log *service.Logger
+
+	address          string
+	reflection       bool
+	certFile         string
+	keyFile          string
+	responseMetadata *service.MetadataFilter
+	codec            messageCodec
+
+	files    *protoregistry.Files
+	methods  map[string]protoreflect.MethodDescriptor
+	services serviceInfo
+
+	requests chan serverRequest
+	shutSig  *shutdown.Signaller
+
+	mut      sync.Mutex
+	server   *grpc.Server
+	listener net.Listener
+}
+
+func newServerInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*serverInput, error) {
+	s := &serverInput{
+		log:      mgr.Logger(),
+		methods:  map[string]protoreflect.MethodDescriptor{},
+		services: serviceInfo{},
+		requests: make(chan serverRequest),
+		shutSig:  shutdown.NewSignaller(),
+	}
+
+	var err error
+	if s.address, err = conf.FieldString(gsiFieldAddress); err != nil {
+		return nil, err
+	}
+	if s.reflection, err = conf.FieldBool(gsiFieldReflection); err != nil {
+		return nil, err
+	}
+	if s.certFile, err = conf.FieldString(gsiFieldCertFile); err != nil {
+		return nil, err
+	}
+	if s.keyFile, err = conf.FieldString(gsiFieldKeyFile); err != nil {
+		return nil, err
+	}
+	if (s.certFile == "") != (s.keyFile == "") {
+		return nil, errors.New("both a cert_file and key_file must be specified for TLS")
+	}
+	if s.responseMetadata, err = conf.FieldMetadataFilter(gsiFieldResponseMetadata); err != nil {
+		return nil, err
+	}
+
+	importPaths, err := conf.FieldStringList(fieldImportPaths)
+	if err != nil {
+		return nil, err
+	}
+	if len(importPaths) == 0 {
+		return nil, errors.New("at least one import path must be specified")
+	}
+	var types *protoregistry.Types
+	if s.files, types, err = protobuf.RegistriesFromImportPaths(mgr.FS(), importPaths); err != nil {
+		return nil, err
+	}
+	if s.codec, err = messageCodecFromParsed(conf, types); err != nil {
+		return nil, err
+	}
+
+	serviceNames, err := conf.FieldStringList(gsiFieldServices)
+	if err != nil {
+		return nil, err
+	}
+	var services []protoreflect.ServiceDescriptor
+	if len(serviceNames) == 0 {
+		s.files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			for i := 0; i < fd.Services().Len(); i++ {
+				services = append(services, fd.Services().Get(i))
+			}
+			return true
+		})
+	}
+	for _, name := range serviceNames {
+		d, err := s.files.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to find service '%v' definition within '%v'", name, importPaths)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("descriptor '%v' is not a service", name)
+		}
+		services = append(services, sd)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services were found within '%v'", importPaths)
+	}
+
+	for _, sd := range services {
+		info := grpc.ServiceInfo{Metadata: sd.ParentFile().Path()}
+		for i := 0; i < sd.Methods().Len(); i++ {
+			md := sd.Methods().Get(i)
+			s.methods[fmt.Sprintf("/%v/%v", sd.FullName(), md.Name())] = md
+			info.Methods = append(info.Methods, grpc.MethodInfo{
+				Name:           string(md.Name()),
+				IsClientStream: md.IsStreamingClient(),
+				IsServerStream: md.IsStreamingServer(),
+			})
+		}
+		s.services[string(sd.FullName())] = info
+	}
+	return s, nil
+}
+
+func (s *serverInput) Connect(context.Context) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.server != nil {
+		return nil
+	}
+
+	opts := []grpc.ServerOption{grpc.UnknownServiceHandler(s.handleStream)}
+	if s.certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.certFile, s.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(opts...)
+	if s.reflection {
+		reflectionOpts := reflection.ServerOptions{
+			Services:           s.services,
+			DescriptorResolver: s.files,
+		}
+		reflectionv1.RegisterServerReflectionServer(server, reflection.NewServerV1(reflectionOpts))
+		reflectionv1alpha.RegisterServerReflectionServer(server, reflection.NewServer(reflectionOpts))
+	}
+
+	s.server = server
+	s.listener = listener
+	go func() {
+		s.log.Infof("Receiving gRPC requests at: %v", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.log.Errorf("gRPC server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *serverInput) handleStream(_ any, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	md, exists := s.methods[fullMethod]
+	if !exists {
+		return status.Errorf(codes.Unimplemented, "method %v is not implemented", fullMethod)
+	}
+	if md.IsStreamingClient() {
+		return status.Errorf(codes.Unimplemented, "client streaming method %v is not supported", fullMethod)
+	}
+
+	req := dynamicpb.NewMessage(md.Input())
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	data, err := s.codec.marshal(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode request: %v", err)
+	}
+
+	msg := service.NewMessage(data)
+	if incoming, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		metadataToMessage(incoming, msg)
+	}
+	msg.MetaSetMut("grpc_service", string(md.Parent().FullName()))
+	msg.MetaSetMut("grpc_method", string(md.Name()))
+
+	batch, store := service.MessageBatch{msg}.WithSyncResponseStore()
+	resChan := make(chan error, 1)
+
+	select {
+	case s.requests <- serverRequest{msg: batch[0], res: resChan}:
+	case <-stream.Context().Done():
+		return status.FromContextError(stream.Context().Err()).Err()
+	case <-s.shutSig.SoftStopChan():
+		return status.Error(codes.Unavailable, "server closing")
+	}
+
+	select {
+	case err := <-resChan:
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	case <-stream.Context().Done():
+		return status.FromContextError(stream.Context().Err()).Err()
+	case <-s.shutSig.HardStopChan():
+		return status.Error(codes.Unavailable, "server closing")
+	}
+
+	var responses service.MessageBatch
+	for _, b := range store.Read() {
+		responses = append(responses, b...)
+	}
+
+	if len(responses) > 0 {
+		header := metadata.MD{}
+		_ = s.responseMetadata.Walk(responses[0], func(k, v string) error {
+			header.Append(k, v)
+			return nil
+		})
+		if len(header) > 0 {
+			if err := stream.SetHeader(header); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !md.IsStreamingServer() {
+		if len(responses) > 1 {
+			responses = responses[:1]
+		} else if len(responses) == 0 {
+			return stream.SendMsg(dynamicpb.NewMessage(md.Output()))
+		}
+	}
+	for _, res := range responses {
+		resBytes, err := res.AsBytes()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read response: %v", err)
+		}
+		out := dynamicpb.NewMessage(md.Output())
+		if err := s.codec.unmarshal(resBytes, out); err != nil {
+			return status.Errorf(codes.Internal, "failed to convert response into %v: %v", md.Output().FullName(), err)
+		}
+		if err := stream.SendMsg(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *serverInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case req := <-s.requests:
+		return req.msg, func(ctx context.Context, err error) error {
+			select {
+			case req.res <- err:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-s.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (s *serverInput) Close(ctx context.Context) error {
+	s.shutSig.TriggerSoftStop()
+	defer s.shutSig.TriggerHardStop()
+
+	s.mut.Lock()
+	server := s.server
+	s.mut.Unlock()
+	if server == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+	return nil
+}
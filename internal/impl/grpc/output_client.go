@@ -0,0 +1,329 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/protobuf"
+)
+
+const (
+	gcoFieldAddress           = "address"
+	gcoFieldMethod            = "method"
+	gcoFieldTLS               = "tls"
+	gcoFieldMetadata          = "metadata"
+	gcoFieldPropagateMetadata = "propagate_metadata"
+	gcoFieldPropagateResponse = "propagate_response"
+	gcoFieldTimeout           = "timeout"
+)
+
+func clientOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Calls a gRPC method for each message, where the request is described either by protobuf definitions or by server reflection.").
+		Description(`
+The contents of each message are converted into the input type of `+"`"+gcoFieldMethod+"`"+`. The descriptors of the method are resolved from the .proto files found in `+"`"+fieldImportPaths+"`"+` or, when no import paths are specified, from the server using the https://github.com/grpc/grpc/blob/master/doc/server-reflection.md[gRPC server reflection protocol^].
+
+Both unary and server streaming methods are supported, client and bidirectional streaming methods are not. When `+"`"+gcoFieldPropagateResponse+"`"+` is enabled the response of a unary method, or each response of a server streaming method, is returned as a xref:guides:sync_responses.adoc[synchronous response] with the response headers added as metadata.`).
+		Fields(
+			service.NewStringField(gcoFieldAddress).
+				Description("The address of the server, in any form supported by https://github.com/grpc/grpc/blob/master/doc/naming.md[gRPC name resolution^].").
+				Example("localhost:50051").
+				Example("dns:///grpc.example.com:443"),
+			service.NewStringField(gcoFieldMethod).
+				Description("The fully qualified method to call.").
+				Example("helloworld.Greeter/SayHello"),
+			importPathsField(),
+		).
+		Fields(formatFields()...).
+		Fields(
+			service.NewTLSToggledField(gcoFieldTLS),
+			service.NewInterpolatedStringMapField(gcoFieldMetadata).
+				Description("A map of metadata to add to each call.").
+				Example(map[string]any{"authorization": `Bearer ${! env("TOKEN") }`}).
+				Default(map[string]any{}),
+			service.NewMetadataFilterField(gcoFieldPropagateMetadata).
+				Description("Specify criteria for which metadata values of messages are added to calls.").
+				Advanced(),
+			service.NewBoolField(gcoFieldPropagateResponse).
+				Description("Whether responses should be returned as xref:guides:sync_responses.adoc[synchronous responses].").
+				Default(false).
+				Advanced(),
+			service.NewDurationField(gcoFieldTimeout).
+				Description("The maximum period to wait for a call to complete.").
+				Default("30s").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+		).
+		Example(
+			"Reflection",
+			"Call a method of a server that supports reflection, without requiring any .proto files.",
+			`
+output:
+  grpc_client:
+    address: localhost:50051
+    method: helloworld.Greeter/SayHello
+    metadata:
+      x-request-id: ${! @id }
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterOutput("grpc_client", clientOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Output, int, error) {
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, 0, err
+			}
+			o, err := newClientOutputFromConfig(conf, mgr)
+			return o, maxInFlight, err
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type clientOutput struct {
+	log *service.Logger
+
+	address           string
+	serviceName       string
+	methodName        string
+	tlsConf           *tls.Config
+	metadata          map[string]*service.InterpolatedString
+	propagateMetadata *service.MetadataFilter
+	propagateResponse bool
+	timeout           time.Duration
+	codec             messageCodec
+	files             *protoregistry.Files
+
+	mut    sync.RWMutex
+	conn   *grpc.ClientConn
+	method protoreflect.MethodDescriptor
+}
+
+func newClientOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*clientOutput, error) {
+	c := &clientOutput{log: mgr.Logger()}
+
+	var err error
+	if c.address, err = conf.FieldString(gcoFieldAddress); err != nil {
+		return nil, err
+	}
+	method, err := conf.FieldString(gcoFieldMethod)
+	if err != nil {
+		return nil, err
+	}
+	if c.serviceName, c.methodName, err = splitMethod(method); err != nil {
+		return nil, err
+	}
+
+	var tlsEnabled bool
+	if c.tlsConf, tlsEnabled, err = conf.FieldTLSToggled(gcoFieldTLS); err != nil {
+		return nil, err
+	}
+	if !tlsEnabled {
+		c.tlsConf = nil
+	}
+	if c.metadata, err = conf.FieldInterpolatedStringMap(gcoFieldMetadata); err != nil {
+		return nil, err
+	}
+	if c.propagateMetadata, err = conf.FieldMetadataFilter(gcoFieldPropagateMetadata); err != nil {
+		return nil, err
+	}
+	if c.propagateResponse, err = conf.FieldBool(gcoFieldPropagateResponse); err != nil {
+		return nil, err
+	}
+	if c.timeout, err = conf.FieldDuration(gcoFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	importPaths, err := conf.FieldStringList(fieldImportPaths)
+	if err != nil {
+		return nil, err
+	}
+	var types *protoregistry.Types
+	if len(importPaths) > 0 {
+		if c.files, types, err = protobuf.RegistriesFromImportPaths(mgr.FS(), importPaths); err != nil {
+			return nil, err
+		}
+		d, err := c.files.FindDescriptorByName(protoreflect.FullName(c.serviceName))
+		if err != nil {
+			return nil, fmt.Errorf("unable to find service '%v' definition within '%v'", c.serviceName, importPaths)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("descriptor '%v' is not a service", c.serviceName)
+		}
+		if c.method, err = findMethod(sd, c.methodName); err != nil {
+			return nil, err
+		}
+	}
+	if c.codec, err = messageCodecFromParsed(conf, types); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *clientOutput) Connect(ctx context.Context) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.conn != nil {
+		return nil
+	}
+
+	creds := insecure.NewCredentials()
+	if c.tlsConf != nil {
+		creds = credentials.NewTLS(c.tlsConf)
+	}
+	conn, err := grpc.NewClient(c.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+
+	if c.files == nil {
+		refClient := grpcreflect.NewClientAuto(ctx, conn)
+		sd, err := refClient.ResolveService(c.serviceName)
+		refClient.Reset()
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("failed to resolve service %v via reflection: %w", c.serviceName, err)
+		}
+		if c.method, err = findMethod(sd.UnwrapService(), c.methodName); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+
+	c.conn = conn
+	return nil
+}
+
+func (c *clientOutput) Write(ctx context.Context, msg *service.Message) error {
+	c.mut.RLock()
+	conn, md := c.conn, c.method
+	c.mut.RUnlock()
+	if conn == nil {
+		return service.ErrNotConnected
+	}
+
+	msgBytes, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	req := dynamicpb.NewMessage(md.Input())
+	if err := c.codec.unmarshal(msgBytes, req); err != nil {
+		return fmt.Errorf("failed to convert message into %v: %w", md.Input().FullName(), err)
+	}
+
+	outgoing := metadata.MD{}
+	_ = c.propagateMetadata.Walk(msg, func(k, v string) error {
+		outgoing.Append(k, v)
+		return nil
+	})
+	for k, v := range c.metadata {
+		value, err := v.TryString(msg)
+		if err != nil {
+			return fmt.Errorf("failed to interpolate metadata %v: %w", k, err)
+		}
+		outgoing.Set(k, value)
+	}
+
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(ctx, outgoing), c.timeout)
+	defer cancel()
+
+	fullMethod := fmt.Sprintf("/%v/%v", c.serviceName, c.methodName)
+	var header metadata.MD
+	var responses []*dynamicpb.Message
+	if !md.IsStreamingServer() {
+		res := dynamicpb.NewMessage(md.Output())
+		if err := conn.Invoke(ctx, fullMethod, req, res, grpc.Header(&header)); err != nil {
+			return err
+		}
+		responses = append(responses, res)
+	} else {
+		stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod, grpc.Header(&header))
+		if err != nil {
+			return err
+		}
+		if err := stream.SendMsg(req); err != nil {
+			return err
+		}
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+		for {
+			res := dynamicpb.NewMessage(md.Output())
+			if err := stream.RecvMsg(res); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			if c.propagateResponse {
+				responses = append(responses, res)
+			}
+		}
+	}
+
+	if !c.propagateResponse {
+		return nil
+	}
+
+	resBatch := make(service.MessageBatch, 0, len(responses))
+	for _, res := range responses {
+		resBytes, err := c.codec.marshal(res)
+		if err != nil {
+			return fmt.Errorf("failed to encode response: %w", err)
+		}
+		resMsg := msg.Copy()
+		resMsg.SetBytes(resBytes)
+		metadataToMessage(header, resMsg)
+		resBatch = append(resBatch, resMsg)
+	}
+	return resBatch.AddSyncResponse()
+}
+
+func (c *clientOutput) Close(context.Context) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
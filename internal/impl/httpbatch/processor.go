@@ -0,0 +1,404 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpbatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/Jeffail/gabs/v2"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	hbpFieldURL             = "url"
+	hbpFieldVerb            = "verb"
+	hbpFieldHeaders         = "headers"
+	hbpFieldRequestMapping  = "request_mapping"
+	hbpFieldResponseMapping = "response_mapping"
+	hbpFieldTargetPath      = "target_path"
+	hbpFieldMaxBatchSize    = "max_batch_size"
+	hbpFieldDeduplicate     = "deduplicate"
+	hbpFieldTimeout         = "timeout"
+	hbpFieldTLS             = "tls"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Integration").
+		Summary("Enriches a batch of messages with a single request to a bulk HTTP API, splitting the response into a result for each message.").
+		Description(`
+The contents of the messages of a batch are collected into an array, where messages that aren't valid JSON are added as strings, and the `+"`"+hbpFieldRequestMapping+"`"+` is executed on this array in order to assemble the body of the request. The `+"`"+hbpFieldResponseMapping+"`"+` is then executed on the body of the response, and must return an array with a result for each message of the batch in the same order. Each result is set at the `+"`"+hbpFieldTargetPath+"`"+` of its message.
+
+The URL and headers are interpolated with the first message of the batch. Batches larger than `+"`"+hbpFieldMaxBatchSize+"`"+` are split into several requests.
+
+When the request fails, the response has a status code other than 2XX, or the response mapping fails, all messages of the request are flagged with an error, which can be handled with xref:configuration:error_handling.adoc[error handling patterns].
+
+== Deduplication
+
+When `+"`"+hbpFieldDeduplicate+"`"+` is enabled, requests with the same verb, URL, headers and body as a request that is already in flight, such as those of the same lookups made by parallel pipeline threads, wait for the response of that request rather than being sent again. This should only be enabled for requests without side effects.
+
+== Metadata
+
+The response mapping can access the following metadata fields of the response:
+
+`+"```text"+`
+- http_status_code
+- All headers of the response
+`+"```"+``).
+		Fields(
+			service.NewInterpolatedStringField(hbpFieldURL).
+				Description("The URL of the bulk API.").
+				Example("https://api.example.com/v1/customers/bulk"),
+			service.NewStringField(hbpFieldVerb).
+				Description("The HTTP verb to use for requests.").
+				Default("POST"),
+			service.NewInterpolatedStringMapField(hbpFieldHeaders).
+				Description("A map of headers to add to each request.").
+				Example(map[string]any{"Content-Type": "application/json"}).
+				Default(map[string]any{"Content-Type": "application/json"}),
+			service.NewBloblangField(hbpFieldRequestMapping).
+				Description("A mapping executed on an array of the contents of the messages of a batch which returns the body of the request.").
+				Examples(`root.ids = this.map_each(m -> m.customer_id)`, `root = this.map_each(m -> {"sku": m.sku})`).
+				Default("root = this"),
+			service.NewBloblangField(hbpFieldResponseMapping).
+				Description("A mapping executed on the body of the response which returns an array with a result for each message of the batch.").
+				Examples(`root = this.results`).
+				Default("root = this"),
+			service.NewStringField(hbpFieldTargetPath).
+				Description("The dot separated path of each message to set its result at. When empty the result replaces the entire message.").
+				Example("customer").
+				Default(""),
+			service.NewIntField(hbpFieldMaxBatchSize).
+				Description("The maximum number of messages to include within a single request, where zero means unlimited.").
+				Default(0),
+			service.NewBoolField(hbpFieldDeduplicate).
+				Description("Whether requests that are identical to a request that is already in flight wait for the response of that request instead of being sent again.").
+				Default(false).
+				Advanced(),
+			service.NewDurationField(hbpFieldTimeout).
+				Description("The maximum period of time to wait for a response.").
+				Default("30s").
+				Advanced(),
+			service.NewTLSToggledField(hbpFieldTLS),
+		).
+		Example(
+			"Bulk customer lookups",
+			"Look up the customers of a batch of orders with a single request, where the API responds with the customers in the order of the requested IDs.",
+			`
+pipeline:
+  processors:
+    - http_batch:
+        url: https://api.example.com/v1/customers/bulk
+        request_mapping: 'root.ids = this.map_each(order -> order.customer_id)'
+        response_mapping: 'root = this.customers'
+        target_path: customer
+        max_batch_size: 100
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor("http_batch", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newProcessorFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+type processor struct {
+	log *service.Logger
+
+	url             *service.InterpolatedString
+	verb            string
+	headers         map[string]*service.InterpolatedString
+	requestMapping  *bloblang.Executor
+	responseMapping *bloblang.Executor
+	targetPath      string
+	maxBatchSize    int
+	deduplicate     bool
+	client          *http.Client
+
+	inFlight singleflight.Group
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	p := &processor{log: mgr.Logger()}
+
+	var err error
+	if p.url, err = conf.FieldInterpolatedString(hbpFieldURL); err != nil {
+		return nil, err
+	}
+	if p.verb, err = conf.FieldString(hbpFieldVerb); err != nil {
+		return nil, err
+	}
+	if p.headers, err = conf.FieldInterpolatedStringMap(hbpFieldHeaders); err != nil {
+		return nil, err
+	}
+	if p.requestMapping, err = conf.FieldBloblang(hbpFieldRequestMapping); err != nil {
+		return nil, err
+	}
+	if p.responseMapping, err = conf.FieldBloblang(hbpFieldResponseMapping); err != nil {
+		return nil, err
+	}
+	if p.targetPath, err = conf.FieldString(hbpFieldTargetPath); err != nil {
+		return nil, err
+	}
+	if p.maxBatchSize, err = conf.FieldInt(hbpFieldMaxBatchSize); err != nil {
+		return nil, err
+	}
+	if p.maxBatchSize < 0 {
+		return nil, fmt.Errorf("field %v must not be negative", hbpFieldMaxBatchSize)
+	}
+	if p.deduplicate, err = conf.FieldBool(hbpFieldDeduplicate); err != nil {
+		return nil, err
+	}
+
+	timeout, err := conf.FieldDuration(hbpFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var tlsConf *tls.Config
+	var tlsEnabled bool
+	if tlsConf, tlsEnabled, err = conf.FieldTLSToggled(hbpFieldTLS); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	p.client = &http.Client{Transport: transport, Timeout: timeout}
+	return p, nil
+}
+
+func (p *processor) buildRequest(ctx context.Context, batch service.MessageBatch) (*http.Request, error) {
+	contents := make([]any, len(batch))
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			b, err := msg.AsBytes()
+			if err != nil {
+				return nil, err
+			}
+			v = string(b)
+		}
+		contents[i] = v
+	}
+
+	collected := service.NewMessage(nil)
+	collected.SetStructured(contents)
+	bodyMsg, err := collected.BloblangQuery(p.requestMapping)
+	if err != nil {
+		return nil, fmt.Errorf("request mapping failed: %w", err)
+	}
+	var body []byte
+	if bodyMsg != nil {
+		if body, err = bodyMsg.AsBytes(); err != nil {
+			return nil, err
+		}
+	}
+
+	urlStr, err := batch.TryInterpolatedString(0, p.url)
+	if err != nil {
+		return nil, fmt.Errorf("url interpolation error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, p.verb, urlStr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.headers {
+		value, err := batch.TryInterpolatedString(0, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate header %v: %w", k, err)
+		}
+		req.Header.Set(k, value)
+	}
+	return req, nil
+}
+
+// requestKey identifies requests that are identical to each other for the
+// purpose of deduplication.
+func requestKey(req *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, req.Method+"\n"+req.URL.String()+"\n")
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			_, _ = io.WriteString(h, k+": "+v+"\n")
+		}
+	}
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (p *processor) send(req *http.Request) (*response, error) {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	r := &response{status: res.StatusCode, header: res.Header}
+	if r.body, err = io.ReadAll(res.Body); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (p *processor) do(req *http.Request) (*response, error) {
+	if !p.deduplicate {
+		return p.send(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	res, err, _ := p.inFlight.Do(requestKey(req, body), func() (any, error) {
+		return p.send(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*response), nil
+}
+
+func (p *processor) results(res *response, n int) ([]any, error) {
+	if res.status < 200 || res.status > 299 {
+		return nil, fmt.Errorf("request failed with status code: %v", res.status)
+	}
+
+	resMsg := service.NewMessage(res.body)
+	resMsg.MetaSetMut("http_status_code", res.status)
+	for k, v := range res.header {
+		if len(v) > 0 {
+			resMsg.MetaSetMut(k, v[0])
+		}
+	}
+
+	mapped, err := resMsg.BloblangQuery(p.responseMapping)
+	if err != nil {
+		return nil, fmt.Errorf("response mapping failed: %w", err)
+	}
+	if mapped == nil {
+		return nil, errors.New("response mapping did not return an array")
+	}
+	v, err := mapped.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("response mapping failed: %w", err)
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("response mapping must return an array, got %T", v)
+	}
+	if len(arr) != n {
+		return nil, fmt.Errorf("response mapping returned %v results for %v messages", len(arr), n)
+	}
+	return arr, nil
+}
+
+func (p *processor) setResult(msg *service.Message, result any) error {
+	if p.targetPath == "" {
+		msg.SetStructuredMut(result)
+		return nil
+	}
+
+	structured, err := msg.AsStructuredMut()
+	if err != nil {
+		return err
+	}
+	gObj := gabs.Wrap(structured)
+	if _, err := gObj.SetP(result, p.targetPath); err != nil {
+		return err
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return nil
+}
+
+func (p *processor) processChunk(ctx context.Context, batch service.MessageBatch) {
+	setErr := func(err error) {
+		p.log.Debugf("Batch request failed: %v", err)
+		for _, msg := range batch {
+			msg.SetError(err)
+		}
+	}
+
+	req, err := p.buildRequest(ctx, batch)
+	if err != nil {
+		setErr(err)
+		return
+	}
+	res, err := p.do(req)
+	if err != nil {
+		setErr(err)
+		return
+	}
+	results, err := p.results(res, len(batch))
+	if err != nil {
+		setErr(err)
+		return
+	}
+	for i, msg := range batch {
+		if err := p.setResult(msg, results[i]); err != nil {
+			msg.SetError(fmt.Errorf("failed to set result: %w", err))
+		}
+	}
+}
+
+func (p *processor) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	newBatch := batch.Copy()
+
+	size := len(newBatch)
+	if p.maxBatchSize > 0 {
+		size = p.maxBatchSize
+	}
+	for start := 0; start < len(newBatch); start += size {
+		end := min(start+size, len(newBatch))
+		p.processChunk(ctx, newBatch[start:end])
+	}
+	return []service.MessageBatch{newBatch}, nil
+}
+
+func (p *processor) Close(context.Context) error {
+	p.client.CloseIdleConnections()
+	return nil
+}
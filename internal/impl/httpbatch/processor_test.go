@@ -0,0 +1,190 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpbatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestProcessor(t *testing.T, conf string) *processor {
+	t.Helper()
+
+	pConf, err := processorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(t.Context()))
+	})
+	return p
+}
+
+func batchOf(docs ...string) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, d := range docs {
+		batch = append(batch, service.NewMessage([]byte(d)))
+	}
+	return batch
+}
+
+// customersHandler responds with a customer for each requested ID.
+func customersHandler(requests *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		customers := make([]map[string]any, len(req.IDs))
+		for i, id := range req.IDs {
+			customers[i] = map[string]any{"id": id, "name": "customer " + id}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"customers": customers})
+	}
+}
+
+func TestProcessorBulkRequests(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(customersHandler(&requests))
+	t.Cleanup(srv.Close)
+
+	p := newTestProcessor(t, `
+url: `+srv.URL+`
+request_mapping: 'root.ids = this.map_each(o -> o.customer_id)'
+response_mapping: 'root = this.customers.map_each(c -> c.name)'
+target_path: customer
+max_batch_size: 2
+`)
+
+	res, err := p.ProcessBatch(t.Context(), batchOf(
+		`{"customer_id":"a"}`,
+		`{"customer_id":"b"}`,
+		`{"customer_id":"c"}`,
+	))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Len(t, res[0], 3)
+
+	for i, exp := range []string{
+		`{"customer_id":"a","customer":"customer a"}`,
+		`{"customer_id":"b","customer":"customer b"}`,
+		`{"customer_id":"c","customer":"customer c"}`,
+	} {
+		require.NoError(t, res[0][i].GetError())
+		b, err := res[0][i].AsBytes()
+		require.NoError(t, err)
+		assert.JSONEq(t, exp, string(b))
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestProcessorErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"results":[1]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name   string
+		path   string
+		errStr string
+	}{
+		{name: "status code", path: "/fail", errStr: "request failed with status code: 500"},
+		{name: "result count", path: "/ok", errStr: "response mapping returned 1 results for 2 messages"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newTestProcessor(t, `
+url: `+srv.URL+test.path+`
+response_mapping: 'root = this.results'
+`)
+			res, err := p.ProcessBatch(t.Context(), batchOf(`{"id":1}`, `{"id":2}`))
+			require.NoError(t, err)
+			require.Len(t, res[0], 2)
+			for _, msg := range res[0] {
+				require.ErrorContains(t, msg.GetError(), test.errStr)
+			}
+		})
+	}
+}
+
+func TestProcessorDeduplicate(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-release
+		fmt.Fprint(w, `["enriched"]`)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newTestProcessor(t, `
+url: `+srv.URL+`
+deduplicate: true
+`)
+
+	var wg sync.WaitGroup
+	results := make([]service.MessageBatch, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := p.ProcessBatch(t.Context(), batchOf(`{"id":1}`))
+			assert.NoError(t, err)
+			results[i] = res[0]
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return requests.Load() == 1
+	}, time.Second, time.Millisecond*10)
+
+	// Give the remaining batches time to join the request in flight.
+	time.Sleep(time.Millisecond * 100)
+	close(release)
+	wg.Wait()
+
+	for _, batch := range results {
+		require.Len(t, batch, 1)
+		require.NoError(t, batch[0].GetError())
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, `"enriched"`, string(b))
+	}
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,460 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+
+	// Register the supported catalog types.
+	_ "github.com/apache/iceberg-go/catalog/glue"
+	_ "github.com/apache/iceberg-go/catalog/rest"
+	_ "github.com/apache/iceberg-go/catalog/sql"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ioFieldCatalog           = "catalog"
+	ioFieldCatalogType       = "type"
+	ioFieldCatalogURI        = "uri"
+	ioFieldCatalogWarehouse  = "warehouse"
+	ioFieldCatalogCredential = "credential"
+	ioFieldCatalogToken      = "token"
+	ioFieldCatalogProperties = "properties"
+	ioFieldNamespace         = "namespace"
+	ioFieldTable             = "table"
+	ioFieldSchemaEvolution   = "schema_evolution"
+	ioFieldCommitInterval    = "commit_interval"
+	ioFieldMaxInFlight       = "max_in_flight"
+	ioFieldBatching          = "batching"
+
+	commitAttempts = 3
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Writes messages to an Apache Iceberg table.").
+		Description(`
+Each message must be a JSON object, which is written as a row of the table where object fields are matched to the columns of the table by name. Rows are written as Parquet data files to the location of the table, and the data files are then committed to the table as a new snapshot through the configured catalog.
+
+The `+"`rest`"+` catalog type works with any catalog implementing the Iceberg REST specification, such as Apache Polaris, Nessie, Unity Catalog and AWS S3 Tables. Credentials for the storage of the table are either vended by the catalog or can be provided with `+"`"+ioFieldCatalog+"."+ioFieldCatalogProperties+"`"+`, e.g. `+"`s3.region`, `s3.access-key-id` and `s3.secret-access-key`"+`.
+
+The table must already exist in the catalog.
+
+== Partitioning
+
+Rows are grouped by the partition spec of the table, so that each data file contains rows of a single partition. The `+"`identity`, `year`, `month`, `day`, `hour` and `truncate`"+` transforms are supported, tables partitioned with a `+"`bucket` or `void`"+` transform cannot be written to.
+
+== Schema evolution
+
+When `+"`"+ioFieldSchemaEvolution+"`"+` is enabled, fields of messages that do not match a column of the table are added as new optional columns before the rows are written, with the type of the column inferred from the first non-null value of the field. Otherwise, fields that do not match a column are ignored.
+
+== Commits
+
+Committing a snapshot for each batch of messages can result in a large number of small data files and snapshots, therefore the data files written within each `+"`"+ioFieldCommitInterval+"`"+` are committed together in a single snapshot. Messages are only acknowledged once the snapshot containing them has been committed, and so `+"`"+ioFieldMaxInFlight+"`"+` should be large enough to allow the batches of a full interval to be in flight at the same time.
+`).
+		Fields(
+			service.NewObjectField(ioFieldCatalog,
+				service.NewStringAnnotatedEnumField(ioFieldCatalogType, map[string]string{
+					"rest": "A catalog implementing the Iceberg REST specification.",
+					"glue": "The AWS Glue data catalog, configured with `glue.` properties such as `glue.region`, otherwise the default AWS credentials chain is used.",
+					"sql":  "A catalog stored in a SQL database, configured with the `sql.driver` and `sql.dialect` properties, where `uri` is the connection string of the database.",
+				}).
+					Description("The type of catalog.").
+					Default("rest"),
+				service.NewStringField(ioFieldCatalogURI).
+					Description("The URI of the catalog.").
+					Example("http://localhost:8181").
+					Default(""),
+				service.NewStringField(ioFieldCatalogWarehouse).
+					Description("The warehouse to use, the meaning of which depends on the catalog.").
+					Default(""),
+				service.NewStringField(ioFieldCatalogCredential).
+					Description("A credential to exchange for a token with the OAuth2 client credentials flow of a REST catalog, in the form `client_id:client_secret`.").
+					Default("").
+					Secret(),
+				service.NewStringField(ioFieldCatalogToken).
+					Description("A bearer token to authenticate with a REST catalog.").
+					Default("").
+					Secret(),
+				service.NewStringMapField(ioFieldCatalogProperties).
+					Description("Additional properties of the catalog and the file IO of the table.").
+					Example(map[string]any{"s3.region": "us-east-1"}).
+					Default(map[string]any{}).
+					Advanced(),
+			).Description("The catalog of the table."),
+			service.NewStringField(ioFieldNamespace).
+				Description("The namespace of the table, where levels of a nested namespace are separated by dots.").
+				Example("analytics.events"),
+			service.NewStringField(ioFieldTable).
+				Description("The name of the table to write to."),
+			service.NewBoolField(ioFieldSchemaEvolution).
+				Description("Whether to add columns to the table for fields of messages that are not present in the schema of the table.").
+				Default(false),
+			service.NewDurationField(ioFieldCommitInterval).
+				Description("The period of time over which written data files are committed together as a single snapshot. When set to zero, a snapshot is committed for each batch.").
+				Default("10s"),
+			service.NewOutputMaxInFlightField().Default(64),
+			service.NewBatchPolicyField(ioFieldBatching),
+		).
+		Example("Streaming into a lakehouse", "Write events from a Kafka topic to an Iceberg table through a REST catalog, adding columns to the table as new fields appear.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: iceberg
+
+output:
+  iceberg:
+    catalog:
+      uri: http://localhost:8181
+      warehouse: s3://warehouse/
+      properties:
+        s3.region: us-east-1
+    namespace: analytics
+    table: events
+    schema_evolution: true
+    batching:
+      count: 10000
+      period: 10s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("iceberg", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ioFieldBatching); err != nil {
+				return
+			}
+			out, err = newIcebergOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+type commitRequest struct {
+	paths []string
+	done  chan error
+}
+
+type icebergOutput struct {
+	log *service.Logger
+
+	catalogProps    iceberg.Properties
+	ident           table.Identifier
+	schemaEvolution bool
+	commitInterval  time.Duration
+
+	// Guards the catalog and the latest known state of the table, which is
+	// replaced after each commit.
+	tblMut sync.Mutex
+	cat    catalog.Catalog
+	tbl    *table.Table
+
+	commitChan chan commitRequest
+	shutSig    *shutdown.Signaller
+}
+
+func newIcebergOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*icebergOutput, error) {
+	o := &icebergOutput{
+		log:        mgr.Logger(),
+		commitChan: make(chan commitRequest),
+		shutSig:    shutdown.NewSignaller(),
+	}
+
+	catConf := conf.Namespace(ioFieldCatalog)
+	props, err := catConf.FieldStringMap(ioFieldCatalogProperties)
+	if err != nil {
+		return nil, err
+	}
+	o.catalogProps = iceberg.Properties(props)
+	for k, field := range map[string]string{
+		"type":       ioFieldCatalogType,
+		"uri":        ioFieldCatalogURI,
+		"warehouse":  ioFieldCatalogWarehouse,
+		"credential": ioFieldCatalogCredential,
+		"token":      ioFieldCatalogToken,
+	} {
+		v, err := catConf.FieldString(field)
+		if err != nil {
+			return nil, err
+		}
+		if v != "" {
+			o.catalogProps[k] = v
+		}
+	}
+
+	namespace, err := conf.FieldString(ioFieldNamespace)
+	if err != nil {
+		return nil, err
+	}
+	tableName, err := conf.FieldString(ioFieldTable)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" || tableName == "" {
+		return nil, fmt.Errorf("both %v and %v must be set", ioFieldNamespace, ioFieldTable)
+	}
+	o.ident = append(strings.Split(namespace, "."), tableName)
+
+	if o.schemaEvolution, err = conf.FieldBool(ioFieldSchemaEvolution); err != nil {
+		return nil, err
+	}
+	if o.commitInterval, err = conf.FieldDuration(ioFieldCommitInterval); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *icebergOutput) Connect(ctx context.Context) error {
+	o.tblMut.Lock()
+	defer o.tblMut.Unlock()
+
+	if o.tbl != nil {
+		return nil
+	}
+
+	cat, err := catalog.Load(ctx, "", o.catalogProps)
+	if err != nil {
+		return fmt.Errorf("failed to load catalog: %w", err)
+	}
+	tbl, err := cat.LoadTable(ctx, o.ident, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load table %v: %w", strings.Join(o.ident, "."), err)
+	}
+
+	spec := tbl.Spec()
+	if err := checkPartitionSpec(&spec); err != nil {
+		return err
+	}
+
+	o.cat, o.tbl = cat, tbl
+	if o.commitInterval > 0 {
+		go o.commitLoop()
+	}
+	return nil
+}
+
+func (o *icebergOutput) currentTable() *table.Table {
+	o.tblMut.Lock()
+	defer o.tblMut.Unlock()
+	return o.tbl
+}
+
+func (o *icebergOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	if o.currentTable() == nil {
+		return service.ErrNotConnected
+	}
+
+	rows := make([]map[string]any, len(batch))
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return fmt.Errorf("failed to parse message as JSON: %w", err)
+		}
+		row, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected message to be a JSON object, got %T", v)
+		}
+		rows[i] = row
+	}
+
+	tbl := o.currentTable()
+	if o.schemaEvolution {
+		var err error
+		if tbl, err = o.evolveSchema(ctx, rows); err != nil {
+			return err
+		}
+	}
+
+	paths, err := writeDataFiles(ctx, tbl, rows)
+	if err != nil {
+		return fmt.Errorf("failed to write data files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	if o.commitInterval <= 0 {
+		return o.commitFiles(ctx, paths)
+	}
+
+	req := commitRequest{paths: paths, done: make(chan error, 1)}
+	select {
+	case o.commitChan <- req:
+	case <-o.shutSig.SoftStopChan():
+		return service.ErrNotConnected
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// evolveSchema adds columns to the table for any fields of the rows that are
+// not yet present in its schema and returns the resulting table.
+func (o *icebergOutput) evolveSchema(ctx context.Context, rows []map[string]any) (*table.Table, error) {
+	o.tblMut.Lock()
+	defer o.tblMut.Unlock()
+
+	current := o.tbl.Schema()
+	lastColumnID := o.tbl.Metadata().LastColumnID()
+	newFields := newColumns(current, rows, &lastColumnID)
+	if len(newFields) == 0 {
+		return o.tbl, nil
+	}
+
+	schema := iceberg.NewSchemaWithIdentifiers(current.ID+1, current.IdentifierFieldIDs, append(current.Fields(), newFields...)...)
+	mapping, err := json.Marshal(schema.NameMapping())
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(newFields))
+	for i, f := range newFields {
+		names[i] = f.Name
+	}
+	o.log.Infof("Adding columns %v to table %v", names, strings.Join(o.ident, "."))
+
+	// The name mapping is used to read the columns of added data files, and
+	// therefore must be kept in line with the schema.
+	if _, _, err := o.cat.CommitTable(ctx, o.tbl,
+		[]table.Requirement{table.AssertCurrentSchemaID(current.ID)},
+		[]table.Update{
+			table.NewAddSchemaUpdate(schema, lastColumnID, false),
+			table.NewSetCurrentSchemaUpdate(-1),
+			table.NewSetPropertiesUpdate(iceberg.Properties{table.DefaultNameMappingKey: string(mapping)}),
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failed to add columns %v: %w", names, err)
+	}
+
+	tbl, err := o.cat.LoadTable(ctx, o.ident, nil)
+	if err != nil {
+		return nil, err
+	}
+	o.tbl = tbl
+	return tbl, nil
+}
+
+// commitFiles commits a new snapshot to the table containing the data files,
+// the table is reloaded and the commit retried when it has been modified
+// concurrently.
+func (o *icebergOutput) commitFiles(ctx context.Context, paths []string) error {
+	o.tblMut.Lock()
+	defer o.tblMut.Unlock()
+
+	var err error
+	for attempt := range commitAttempts {
+		if attempt > 0 {
+			o.log.Debugf("Retrying commit after failure: %v", err)
+
+			var tbl *table.Table
+			if tbl, err = o.cat.LoadTable(ctx, o.ident, nil); err != nil {
+				continue
+			}
+			o.tbl = tbl
+		}
+
+		tx := o.tbl.NewTransaction()
+		if err = tx.AddFiles(ctx, paths, nil, true); err != nil {
+			return fmt.Errorf("failed to add data files: %w", err)
+		}
+
+		var tbl *table.Table
+		if tbl, err = tx.Commit(ctx); err == nil {
+			o.tbl = tbl
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to commit snapshot: %w", err)
+}
+
+// commitLoop commits the data files of all requests received within each
+// commit interval together.
+func (o *icebergOutput) commitLoop() {
+	defer o.shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(o.commitInterval)
+	defer ticker.Stop()
+
+	ctx, done := o.shutSig.HardStopCtx(context.Background())
+	defer done()
+
+	var pending []commitRequest
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		var paths []string
+		for _, req := range pending {
+			paths = append(paths, req.paths...)
+		}
+		err := o.commitFiles(ctx, paths)
+		if err != nil {
+			o.log.Errorf("Failed to commit %v data files: %v", len(paths), err)
+		}
+		for _, req := range pending {
+			req.done <- err
+		}
+		pending = nil
+	}
+
+	for {
+		select {
+		case req := <-o.commitChan:
+			pending = append(pending, req)
+		case <-ticker.C:
+			flush()
+		case <-o.shutSig.SoftStopChan():
+			flush()
+			return
+		}
+	}
+}
+
+func (o *icebergOutput) Close(ctx context.Context) error {
+	o.shutSig.TriggerSoftStop()
+	if o.currentTable() == nil || o.commitInterval <= 0 {
+		return nil
+	}
+	select {
+	case <-o.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		o.shutSig.TriggerHardStop()
+		return errors.Join(ctx.Err(), errors.New("timed out waiting for pending commits"))
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func setupSQLCatalog(t *testing.T, spec *iceberg.PartitionSpec) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	catalogConf := fmt.Sprintf(`
+catalog:
+  type: sql
+  uri: file:%v
+  warehouse: file://%v
+  properties:
+    sql.driver: sqlite
+    sql.dialect: sqlite
+`, filepath.Join(dir, "catalog.db"), filepath.Join(dir, "warehouse"))
+
+	ctx := t.Context()
+	cat, err := catalog.Load(ctx, "", iceberg.Properties{
+		"type":        "sql",
+		"uri":         "file:" + filepath.Join(dir, "catalog.db"),
+		"warehouse":   "file://" + filepath.Join(dir, "warehouse"),
+		"sql.driver":  "sqlite",
+		"sql.dialect": "sqlite",
+	})
+	require.NoError(t, err)
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"analytics"}, nil))
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "category", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 3, Name: "ts", Type: iceberg.PrimitiveTypes.TimestampTz},
+	)
+	_, err = cat.CreateTable(ctx, table.Identifier{"analytics", "events"}, schema, catalog.WithPartitionSpec(spec))
+	require.NoError(t, err)
+
+	return catalogConf
+}
+
+func newTestOutput(t *testing.T, conf string) *icebergOutput {
+	t.Helper()
+
+	parsed, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	out, err := newIcebergOutputFromConfig(parsed, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = out.Close(context.Background())
+	})
+	return out
+}
+
+func scanRows(t *testing.T, out *icebergOutput) []map[string]any {
+	t.Helper()
+
+	tbl, err := out.cat.LoadTable(t.Context(), out.ident, nil)
+	require.NoError(t, err)
+
+	arrTbl, err := tbl.Scan().ToArrowTable(t.Context())
+	require.NoError(t, err)
+	defer arrTbl.Release()
+
+	var rows []map[string]any
+	tr := array.NewTableReader(arrTbl, -1)
+	defer tr.Release()
+	for tr.Next() {
+		rec := tr.Record()
+		for i := range int(rec.NumRows()) {
+			row := map[string]any{}
+			for j, f := range rec.Schema().Fields() {
+				if col := rec.Column(j); col.IsValid(i) {
+					row[f.Name] = col.GetOneForMarshal(i)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func TestIcebergOutputPartitioned(t *testing.T) {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 2, FieldID: 1000, Name: "category", Transform: iceberg.IdentityTransform{}},
+		iceberg.PartitionField{SourceID: 3, FieldID: 1001, Name: "ts_day", Transform: iceberg.DayTransform{}},
+	)
+	out := newTestOutput(t, setupSQLCatalog(t, &spec)+`
+namespace: analytics
+table: events
+commit_interval: 0s
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"category":"a","ts":"2025-01-01T10:00:00Z"}`)),
+		service.NewMessage([]byte(`{"id":2,"category":"b","ts":"2025-01-01T11:00:00Z"}`)),
+		service.NewMessage([]byte(`{"id":3,"category":"a","ts":"2025-01-02T10:00:00.5Z","unknown":true}`)),
+		service.NewMessage([]byte(`{"id":4,"category":"a","ts":"2025-01-01T12:00:00Z"}`)),
+	}))
+
+	snapshot := out.currentTable().CurrentSnapshot()
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "3", snapshot.Summary.Properties["added-data-files"])
+
+	rows := scanRows(t, out)
+	assert.Len(t, rows, 4)
+	assert.Len(t, out.currentTable().Schema().Fields(), 3)
+}
+
+func TestIcebergOutputSchemaEvolution(t *testing.T) {
+	out := newTestOutput(t, setupSQLCatalog(t, iceberg.UnpartitionedSpec)+`
+namespace: analytics
+table: events
+schema_evolution: true
+commit_interval: 0s
+`)
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"category":"a"}`)),
+	}))
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":2,"category":"b","count":10,"tags":["x","y"],"empty":null}`)),
+	}))
+
+	schema := out.currentTable().Schema()
+	count, ok := schema.FindFieldByName("count")
+	require.True(t, ok)
+	assert.Equal(t, iceberg.PrimitiveTypes.Int64, count.Type)
+	tags, ok := schema.FindFieldByName("tags")
+	require.True(t, ok)
+	assert.Equal(t, "list<string>", tags.Type.String())
+	_, ok = schema.FindFieldByName("empty")
+	assert.False(t, ok)
+
+	rows := scanRows(t, out)
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		if row["id"] == int64(2) {
+			assert.Equal(t, int64(10), row["count"])
+		} else {
+			assert.NotContains(t, row, "count")
+		}
+	}
+}
+
+func TestIcebergOutputCommitInterval(t *testing.T) {
+	out := newTestOutput(t, setupSQLCatalog(t, iceberg.UnpartitionedSpec)+`
+namespace: analytics
+table: events
+commit_interval: 1s
+`)
+
+	errs := make(chan error)
+	for i := range 3 {
+		go func() {
+			errs <- out.WriteBatch(t.Context(), service.MessageBatch{
+				service.NewMessage(fmt.Appendf(nil, `{"id":%v}`, i)),
+			})
+		}()
+	}
+	for range 3 {
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+		case <-time.After(time.Second * 10):
+			t.Fatal("timed out waiting for commit")
+		}
+	}
+
+	assert.Len(t, scanRows(t, out), 3)
+	assert.Len(t, out.currentTable().Metadata().Snapshots(), 1)
+}
+
+func TestIcebergOutputUnsupportedPartitionTransform(t *testing.T) {
+	spec := iceberg.NewPartitionSpec(
+		iceberg.PartitionField{SourceID: 1, FieldID: 1000, Name: "id_bucket", Transform: iceberg.BucketTransform{NumBuckets: 8}},
+	)
+	parsed, err := outputSpec().ParseYAML(setupSQLCatalog(t, &spec)+`
+namespace: analytics
+table: events
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newIcebergOutputFromConfig(parsed, service.MockResources())
+	require.NoError(t, err)
+	require.ErrorContains(t, out.Connect(t.Context()), "bucket")
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/apache/iceberg-go"
+)
+
+// inferType returns the column type of a value of a structured message, or
+// false if a type cannot be inferred from the value.
+func inferType(v any, lastColumnID *int) (iceberg.Type, bool) {
+	switch t := v.(type) {
+	case bool:
+		return iceberg.PrimitiveTypes.Bool, true
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return iceberg.PrimitiveTypes.Int64, true
+		}
+		return iceberg.PrimitiveTypes.Float64, true
+	case int, int32, int64:
+		return iceberg.PrimitiveTypes.Int64, true
+	case float32, float64:
+		return iceberg.PrimitiveTypes.Float64, true
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return iceberg.PrimitiveTypes.TimestampTz, true
+		}
+		return iceberg.PrimitiveTypes.String, true
+	case time.Time:
+		return iceberg.PrimitiveTypes.TimestampTz, true
+	case []byte:
+		return iceberg.PrimitiveTypes.Binary, true
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		var fields []iceberg.NestedField
+		for _, k := range keys {
+			if f, ok := inferField(k, t[k], lastColumnID); ok {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) == 0 {
+			return nil, false
+		}
+		return &iceberg.StructType{FieldList: fields}, true
+	case []any:
+		for _, e := range t {
+			if e == nil {
+				continue
+			}
+			id := *lastColumnID + 1
+			*lastColumnID = id
+			elemType, ok := inferType(e, lastColumnID)
+			if !ok {
+				*lastColumnID = id - 1
+				return nil, false
+			}
+			return &iceberg.ListType{ElementID: id, Element: elemType, ElementRequired: false}, true
+		}
+	}
+	return nil, false
+}
+
+// inferField returns an optional column for a field of a structured message,
+// assigning it the next column ID.
+func inferField(name string, v any, lastColumnID *int) (iceberg.NestedField, bool) {
+	id := *lastColumnID + 1
+	*lastColumnID = id
+
+	typ, ok := inferType(v, lastColumnID)
+	if !ok {
+		*lastColumnID = id - 1
+		return iceberg.NestedField{}, false
+	}
+	return iceberg.NestedField{ID: id, Name: name, Type: typ}, true
+}
+
+// newColumns returns the columns to add to a schema for the top level fields
+// of rows that aren't present in it. Fields with only null values are skipped
+// as their type cannot be inferred.
+func newColumns(schema *iceberg.Schema, rows []map[string]any, lastColumnID *int) []iceberg.NestedField {
+	var fields []iceberg.NestedField
+	added := map[string]struct{}{}
+	for _, row := range rows {
+		keys := make([]string, 0, len(row))
+		for k := range row {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			if _, exists := added[k]; exists {
+				continue
+			}
+			if _, exists := schema.FindFieldByName(k); exists {
+				continue
+			}
+			if f, ok := inferField(k, row[k], lastColumnID); ok {
+				fields = append(fields, f)
+				added[k] = struct{}{}
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/iceberg-go"
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+)
+
+// checkPartitionSpec returns an error if the partition values of data files
+// written for the spec cannot be derived from their column statistics, which
+// is only possible for transforms that preserve the order of values.
+func checkPartitionSpec(spec *iceberg.PartitionSpec) error {
+	for field := range spec.Fields() {
+		if !field.Transform.PreservesOrder() {
+			return fmt.Errorf("partition field %v uses the %v transform, which is not supported", field.Name, field.Transform)
+		}
+	}
+	return nil
+}
+
+// toLiteral converts a value of a structured message to a literal of the
+// given type.
+func toLiteral(v any, typ iceberg.Type) (iceberg.Literal, error) {
+	var lit iceberg.Literal
+	switch t := v.(type) {
+	case string:
+		switch typ.(type) {
+		case iceberg.TimestampType, iceberg.TimestampTzType:
+			// Literals only parse timestamps without fractional seconds, so
+			// parse them here to be consistent with the data files.
+			ts, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return nil, err
+			}
+			lit = iceberg.NewLiteral(iceberg.Timestamp(ts.UnixMicro()))
+		default:
+			lit = iceberg.NewLiteral(t)
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			lit = iceberg.NewLiteral(i)
+		} else {
+			f, err := t.Float64()
+			if err != nil {
+				return nil, err
+			}
+			lit = iceberg.NewLiteral(f)
+		}
+	case bool:
+		lit = iceberg.NewLiteral(t)
+	case int:
+		lit = iceberg.NewLiteral(int64(t))
+	case int64:
+		lit = iceberg.NewLiteral(t)
+	case float64:
+		lit = iceberg.NewLiteral(t)
+	case time.Time:
+		lit = iceberg.NewLiteral(iceberg.Timestamp(t.UnixMicro()))
+	default:
+		return nil, fmt.Errorf("unsupported partition value type %T", v)
+	}
+	return lit.To(typ)
+}
+
+// rowValue returns the value at a dot separated column path of a row.
+func rowValue(row map[string]any, path string) any {
+	var current any = row
+	for seg := range strings.SplitSeq(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[seg]
+	}
+	return current
+}
+
+// partitionKey returns a key identifying the partition that a row belongs to
+// according to a partition spec.
+func partitionKey(row map[string]any, schema *iceberg.Schema, spec *iceberg.PartitionSpec) (string, error) {
+	var key strings.Builder
+	for field := range spec.Fields() {
+		source, ok := schema.FindFieldByID(field.SourceID)
+		if !ok {
+			return "", fmt.Errorf("source column of partition field %v not found", field.Name)
+		}
+		name, _ := schema.FindColumnName(field.SourceID)
+
+		key.WriteString(field.Name)
+		key.WriteByte('=')
+		if v := rowValue(row, name); v != nil {
+			lit, err := toLiteral(v, source.Type)
+			if err != nil {
+				return "", fmt.Errorf("failed to convert value of column %v: %w", name, err)
+			}
+			if res := field.Transform.Apply(iceberg.Optional[iceberg.Literal]{Val: lit, Valid: true}); res.Valid {
+				key.WriteString(res.Val.String())
+			}
+		}
+		key.WriteByte('/')
+	}
+	return key.String(), nil
+}
+
+// writeDataFiles writes rows to Parquet data files within the location of a
+// table and returns the paths of the files, where a data file is written for
+// each partition of the rows.
+func writeDataFiles(ctx context.Context, tbl *table.Table, rows []map[string]any) ([]string, error) {
+	fs, ok := tbl.FS().(icebergio.WriteFileIO)
+	if !ok {
+		return nil, errors.New("the file IO of the table does not support writing")
+	}
+	locations, err := tbl.LocationProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	schema, spec := tbl.Schema(), tbl.Spec()
+	if err := checkPartitionSpec(&spec); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	partitions := map[string][]map[string]any{}
+	for _, row := range rows {
+		key, err := partitionKey(row, schema, &spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := partitions[key]; !exists {
+			keys = append(keys, key)
+		}
+		partitions[key] = append(partitions[key], row)
+	}
+
+	var paths []string
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := locations.NewDataLocation(uuid.NewString() + ".parquet")
+		if err := writeDataFile(fs, path, schema, partitions[key]); err != nil {
+			return nil, fmt.Errorf("failed to write data file %v: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeDataFile(fs icebergio.WriteFileIO, path string, schema *iceberg.Schema, rows []map[string]any) error {
+	// Data files are added to the table by mapping column names to the schema,
+	// which requires that the files do not contain field IDs.
+	arrowSchema, err := table.SchemaToArrowSchema(schema, nil, false, false)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+
+	rdr := array.NewJSONReader(&buf, arrowSchema, array.WithChunk(-1))
+	defer rdr.Release()
+	if !rdr.Next() {
+		if err := rdr.Err(); err != nil {
+			return err
+		}
+		return errors.New("no rows to write")
+	}
+	rec := rdr.Record()
+
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w, err := pqarrow.NewFileWriter(arrowSchema, f,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd)),
+		pqarrow.DefaultWriterProps())
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Write(rec); err != nil {
+		_ = w.Close()
+		return err
+	}
+	// Closing the writer also closes the underlying file.
+	return w.Close()
+}
@@ -0,0 +1,458 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ioFieldURL              = "url"
+	ioFieldAPI              = "api"
+	ioFieldOrg              = "org"
+	ioFieldBucket           = "bucket"
+	ioFieldToken            = "token"
+	ioFieldPrecision        = "precision"
+	ioFieldMeasurement      = "measurement"
+	ioFieldTagsMapping      = "tags_mapping"
+	ioFieldFieldsMapping    = "fields_mapping"
+	ioFieldTimestampMapping = "timestamp_mapping"
+	ioFieldTLS              = "tls"
+	ioFieldTimeout          = "timeout"
+	ioFieldBatching         = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Writes messages as points to InfluxDB 2 or InfluxDB 3 using line protocol.").
+		Description(`
+Each message is converted into a point of line protocol, where the measurement, tags, fields and timestamp of the point are extracted from the message, and each batch of messages is written with a single request.
+
+The `+"`v2`"+` API writes to the `+"`/api/v2/write`"+` endpoint, which is supported by InfluxDB 2 and by InfluxDB 3 for compatibility, and the `+"`v3`"+` API writes to the `+"`/api/v3/write_lp`"+` endpoint of InfluxDB 3.
+
+Fields that are integers are written as integer fields, other numbers are written as float fields, and booleans and strings are written as boolean and string fields respectively.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewURLField(ioFieldURL).
+				Description("The URL of the InfluxDB server.").
+				Example("http://localhost:8181"),
+			service.NewStringAnnotatedEnumField(ioFieldAPI, map[string]string{
+				"v2": "Write to the `/api/v2/write` endpoint.",
+				"v3": "Write to the `/api/v3/write_lp` endpoint.",
+			}).
+				Description("The write API to use.").
+				Default("v2"),
+			service.NewStringField(ioFieldOrg).
+				Description("The organization of the bucket, which is only used by the `v2` API.").
+				Default(""),
+			service.NewStringField(ioFieldBucket).
+				Description("The bucket to write to, which is the name of the database for InfluxDB 3."),
+			service.NewStringField(ioFieldToken).
+				Description("The token to authenticate with.").
+				Secret().
+				Default(""),
+			service.NewStringEnumField(ioFieldPrecision, "ns", "us", "ms", "s").
+				Description("The precision of timestamps.").
+				Default("ns"),
+			service.NewInterpolatedStringField(ioFieldMeasurement).
+				Description("The measurement of each point.").
+				Example("cpu").
+				Example(`${! meta("kafka_topic") }`),
+			service.NewBloblangField(ioFieldTagsMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to an object of tags, where values are converted into strings and empty values are omitted.").
+				Example(`root = { "host": this.host, "region": this.region }`).
+				Optional(),
+			service.NewBloblangField(ioFieldFieldsMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to an object of at least one field, where null values are omitted.").
+				Example(`root = { "usage": this.usage, "cores": this.cores }`).
+				Default("root = this"),
+			service.NewBloblangField(ioFieldTimestampMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to the timestamp of each point. When omitted points are given the time at which they're written by the server.").
+				Example(`root = this.timestamp`).
+				Example(`root = meta("kafka_timestamp_ms").number().ts_unix_milli()`).
+				Optional(),
+			service.NewTLSToggledField(ioFieldTLS).
+				Advanced(),
+			service.NewDurationField(ioFieldTimeout).
+				Description("The maximum period of time to wait for each write request.").
+				Default("10s").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ioFieldBatching),
+		).
+		Example("Metrics from Kafka", "Writes metrics consumed from a Kafka topic to an InfluxDB 3 database, tagging each point by host.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ metrics ]
+    consumer_group: influxdb
+
+output:
+  influxdb:
+    url: http://localhost:8181
+    api: v3
+    bucket: metrics
+    token: ${INFLUXDB_TOKEN}
+    measurement: ${! this.name }
+    tags_mapping: 'root = { "host": this.host }'
+    fields_mapping: 'root = { "value": this.value }'
+    timestamp_mapping: 'root = this.timestamp'
+    precision: ms
+    batching:
+      count: 1000
+      period: 1s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"influxdb", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ioFieldBatching); err != nil {
+				return
+			}
+			out, err = newInfluxDBOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+type influxDBOutput struct {
+	writeURL  string
+	authValue string
+	precision time.Duration
+
+	measurement      *service.InterpolatedString
+	tagsMapping      *bloblang.Executor
+	fieldsMapping    *bloblang.Executor
+	timestampMapping *bloblang.Executor
+
+	client *http.Client
+}
+
+func newInfluxDBOutputFromParsed(conf *service.ParsedConfig, _ *service.Resources) (*influxDBOutput, error) {
+	o := &influxDBOutput{}
+
+	baseURL, err := conf.FieldURL(ioFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	api, err := conf.FieldString(ioFieldAPI)
+	if err != nil {
+		return nil, err
+	}
+	org, err := conf.FieldString(ioFieldOrg)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := conf.FieldString(ioFieldBucket)
+	if err != nil {
+		return nil, err
+	}
+	token, err := conf.FieldString(ioFieldToken)
+	if err != nil {
+		return nil, err
+	}
+	precision, err := conf.FieldString(ioFieldPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	switch api {
+	case "v3":
+		baseURL = baseURL.JoinPath("/api/v3/write_lp")
+		query.Set("db", bucket)
+		query.Set("precision", map[string]string{
+			"ns": "nanosecond",
+			"us": "microsecond",
+			"ms": "millisecond",
+			"s":  "second",
+		}[precision])
+		if token != "" {
+			o.authValue = "Bearer " + token
+		}
+	default:
+		baseURL = baseURL.JoinPath("/api/v2/write")
+		if org != "" {
+			query.Set("org", org)
+		}
+		query.Set("bucket", bucket)
+		query.Set("precision", precision)
+		if token != "" {
+			o.authValue = "Token " + token
+		}
+	}
+	baseURL.RawQuery = query.Encode()
+	o.writeURL = baseURL.String()
+
+	o.precision = map[string]time.Duration{
+		"ns": time.Nanosecond,
+		"us": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+	}[precision]
+
+	if o.measurement, err = conf.FieldInterpolatedString(ioFieldMeasurement); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ioFieldTagsMapping) {
+		if o.tagsMapping, err = conf.FieldBloblang(ioFieldTagsMapping); err != nil {
+			return nil, err
+		}
+	}
+	if o.fieldsMapping, err = conf.FieldBloblang(ioFieldFieldsMapping); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ioFieldTimestampMapping) {
+		if o.timestampMapping, err = conf.FieldBloblang(ioFieldTimestampMapping); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(ioFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(ioFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{Transport: transport, Timeout: timeout}
+	return o, nil
+}
+
+func (*influxDBOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *influxDBOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	var buf bytes.Buffer
+	if err := o.encodeBatch(&buf, batch); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.writeURL, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if o.authValue != "" {
+		req.Header.Set("Authorization", o.authValue)
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return fmt.Errorf("write failed with status %v: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (o *influxDBOutput) encodeBatch(w *bytes.Buffer, batch service.MessageBatch) error {
+	var tagsExec, timestampExec *service.MessageBatchBloblangExecutor
+	if o.tagsMapping != nil {
+		tagsExec = batch.BloblangExecutor(o.tagsMapping)
+	}
+	if o.timestampMapping != nil {
+		timestampExec = batch.BloblangExecutor(o.timestampMapping)
+	}
+	fieldsExec := batch.BloblangExecutor(o.fieldsMapping)
+	measurementExec := batch.InterpolationExecutor(o.measurement)
+
+	for i := range batch {
+		measurement, err := measurementExec.TryString(i)
+		if err != nil {
+			return fmt.Errorf("measurement interpolation error: %w", err)
+		}
+		if measurement == "" {
+			return fmt.Errorf("message %v has an empty measurement", i)
+		}
+
+		var tags map[string]any
+		if tagsExec != nil {
+			if tags, err = queryObject(tagsExec, i); err != nil {
+				return fmt.Errorf("tags mapping failed: %w", err)
+			}
+		}
+
+		fields, err := queryObject(fieldsExec, i)
+		if err != nil {
+			return fmt.Errorf("fields mapping failed: %w", err)
+		}
+
+		var ts *time.Time
+		if timestampExec != nil {
+			msg, err := timestampExec.Query(i)
+			if err != nil {
+				return fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			// Mappings resulting in strings produce raw message contents.
+			v, err := msg.AsStructured()
+			if err != nil {
+				b, _ := msg.AsBytes()
+				v = string(b)
+			}
+			t, err := bloblang.ValueAsTimestamp(v)
+			if err != nil {
+				return fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			ts = &t
+		}
+
+		if err := writeLine(w, measurement, tags, fields, ts, o.precision); err != nil {
+			return fmt.Errorf("message %v: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func queryObject(exec *service.MessageBatchBloblangExecutor, i int) (map[string]any, error) {
+	msg, err := exec.Query(i)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", v)
+	}
+	return obj, nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+	stringEscaper      = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+// writeLine writes a point of line protocol, with tags sorted by key as
+// recommended for write performance.
+func writeLine(w *bytes.Buffer, measurement string, tags, fields map[string]any, ts *time.Time, precision time.Duration) error {
+	_, _ = measurementEscaper.WriteString(w, measurement)
+
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		v := bloblang.ValueToString(tags[k])
+		if tags[k] == nil || v == "" {
+			continue
+		}
+		w.WriteByte(',')
+		_, _ = keyEscaper.WriteString(w, k)
+		w.WriteByte('=')
+		_, _ = keyEscaper.WriteString(w, v)
+	}
+
+	written := 0
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		if fields[k] == nil {
+			continue
+		}
+		if written == 0 {
+			w.WriteByte(' ')
+		} else {
+			w.WriteByte(',')
+		}
+		written++
+		_, _ = keyEscaper.WriteString(w, k)
+		w.WriteByte('=')
+		if err := writeFieldValue(w, fields[k]); err != nil {
+			return fmt.Errorf("field %v: %w", k, err)
+		}
+	}
+	if written == 0 {
+		return errors.New("points must have at least one field")
+	}
+
+	if ts != nil {
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatInt(ts.UnixNano()/int64(precision), 10))
+	}
+	w.WriteByte('\n')
+	return nil
+}
+
+func writeFieldValue(w *bytes.Buffer, v any) error {
+	switch t := v.(type) {
+	case bool:
+		w.WriteString(strconv.FormatBool(t))
+	case string:
+		w.WriteByte('"')
+		_, _ = stringEscaper.WriteString(w, t)
+		w.WriteByte('"')
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		i, err := bloblang.ValueAsInt64(t)
+		if err != nil {
+			return err
+		}
+		w.WriteString(strconv.FormatInt(i, 10))
+		w.WriteByte('i')
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			w.WriteString(strconv.FormatInt(i, 10))
+			w.WriteByte('i')
+			return nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return err
+		}
+		w.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	case float32, float64:
+		f, _ := bloblang.ValueAsFloat64(t)
+		w.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	default:
+		return fmt.Errorf("unsupported field type %T", v)
+	}
+	return nil
+}
+
+func (o *influxDBOutput) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
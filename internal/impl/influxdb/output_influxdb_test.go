@@ -0,0 +1,118 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdb
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestInfluxDBOutput(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		conf  string
+		path  string
+		query string
+		auth  string
+	}{
+		{
+			name:  "v2",
+			conf:  "org: acme\nprecision: s",
+			path:  "/api/v2/write",
+			query: "bucket=metrics&org=acme&precision=s",
+			auth:  "Token foo",
+		},
+		{
+			name:  "v3",
+			conf:  "api: v3\nprecision: s",
+			path:  "/api/v3/write_lp",
+			query: "db=metrics&precision=second",
+			auth:  "Bearer foo",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, test.path, r.URL.Path)
+				assert.Equal(t, test.query, r.URL.RawQuery)
+				assert.Equal(t, test.auth, r.Header.Get("Authorization"))
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				body = string(b)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			conf, err := outputSpec().ParseYAML(`
+url: `+srv.URL+`
+bucket: metrics
+token: foo
+measurement: ${! this.name }
+tags_mapping: 'root = { "host": this.host, "region": this.region }'
+fields_mapping: 'root = this.values'
+timestamp_mapping: 'root = this.ts'
+`+test.conf, nil)
+			require.NoError(t, err)
+
+			o, err := newInfluxDBOutputFromParsed(conf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, o.Connect(t.Context()))
+
+			require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+				service.NewMessage([]byte(`{"name":"cpu","host":"a b","region":null,"values":{"usage":0.5,"cores":4,"up":true,"model":"x \"86\""},"ts":1700000000}`)),
+				service.NewMessage([]byte(`{"name":"disk io","host":"c,d","values":{"reads":10,"note":null},"ts":"2023-11-14T22:13:21Z"}`)),
+			}))
+			assert.Equal(t, `cpu,host=a\ b cores=4i,model="x \"86\"",up=true,usage=0.5 1700000000
+disk\ io,host=c\,d reads=10i 1700000001
+`, body)
+			require.NoError(t, o.Close(t.Context()))
+		})
+	}
+}
+
+func TestInfluxDBOutputErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"invalid","message":"partial write"}`))
+	}))
+	defer srv.Close()
+
+	conf, err := outputSpec().ParseYAML(`
+url: `+srv.URL+`
+bucket: metrics
+measurement: cpu
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newInfluxDBOutputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+
+	err = o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"usage":1}`)),
+	})
+	require.ErrorContains(t, err, "write failed with status 400")
+
+	err = o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"usage":null}`)),
+	})
+	require.ErrorContains(t, err, "points must have at least one field")
+}
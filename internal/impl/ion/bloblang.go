@@ -0,0 +1,117 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ion
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func init() {
+	ionParseSpec := bloblang.NewPluginSpec().
+		Category("Parsing").
+		Version("4.62.0").
+		Description("Parses a text or binary https://amazon-ion.github.io/ion-docs/[Amazon Ion^] document containing a single value into a structured document. Annotations are discarded.").
+		Example("",
+			`root = content().parse_ion()`,
+			[2]string{
+				`order::{id: 1, total: 10.50, tags: [a, b]}`,
+				`{"id":1,"tags":["a","b"],"total":10.5}`,
+			}).
+		Example("",
+			`root = this.encoded.decode("base64").parse_ion()`,
+			[2]string{
+				`{"encoded":"4AEA6umBg9aHtINmb2/VioNiYXI="}`,
+				`{"foo":"bar"}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"parse_ion", ionParseSpec,
+		func(*bloblang.ParsedParams) (bloblang.Method, error) {
+			return func(v any) (any, error) {
+				b, err := bloblang.ValueAsBytes(v)
+				if err != nil {
+					return nil, err
+				}
+				values, err := decodeIon(b)
+				if err != nil {
+					return nil, err
+				}
+				if len(values) != 1 {
+					return nil, fmt.Errorf("expected a single Ion value, got %v", len(values))
+				}
+				return values[0].value, nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	ionFormatSpec := bloblang.NewPluginSpec().
+		Category("Parsing").
+		Version("4.62.0").
+		Description("Formats data as an https://amazon-ion.github.io/ion-docs/[Amazon Ion^] document in bytes format.").
+		Param(bloblang.NewStringParam("format").
+			Description("The Ion format to produce, either `binary` or `text`.").
+			Default("binary")).
+		Param(bloblang.NewAnyParam("annotations").
+			Description("An optional array of annotations to add to the value.").
+			Optional()).
+		Example("",
+			`root = this.format_ion(format: "text").string()`,
+			[2]string{
+				`{"foo":"bar","nums":[1,2.5]}`,
+				`{foo:"bar",nums:[1,2.5]}`,
+			}).
+		Example("",
+			`root.encoded = this.format_ion().encode("base64")`,
+			[2]string{
+				`{"foo":"bar"}`,
+				`{"encoded":"4AEA6umBg9aHtINmb2/VioNiYXI="}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"format_ion", ionFormatSpec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			format, err := args.GetString("format")
+			if err != nil {
+				return nil, err
+			}
+			if format != "binary" && format != "text" {
+				return nil, fmt.Errorf("unrecognised format: %v", format)
+			}
+			var annotations []string
+			if rawAnnotations, _ := args.Get("annotations"); rawAnnotations != nil {
+				arr, ok := rawAnnotations.([]any)
+				if !ok {
+					return nil, fmt.Errorf("expected annotations to be an array, got %T", rawAnnotations)
+				}
+				for _, a := range arr {
+					s, ok := a.(string)
+					if !ok {
+						return nil, fmt.Errorf("expected annotations to be strings, got %T", a)
+					}
+					annotations = append(annotations, s)
+				}
+			}
+			return func(v any) (any, error) {
+				return encodeIon(v, annotations, format == "binary")
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/amazon-ion/ion-go/ion"
+)
+
+// ionValue is a top level value of an Ion stream along with its annotations.
+type ionValue struct {
+	value       any
+	annotations []string
+}
+
+// decodeIon decodes all top level values of a text or binary Ion stream.
+// Annotations of nested values are discarded.
+func decodeIon(b []byte) ([]ionValue, error) {
+	r := ion.NewReaderBytes(b)
+
+	var values []ionValue
+	for r.Next() {
+		annotations, err := readAnnotations(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, ionValue{value: v, annotations: annotations})
+	}
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func readAnnotations(r ion.Reader) ([]string, error) {
+	tokens, err := r.Annotations()
+	if err != nil {
+		return nil, err
+	}
+	var annotations []string
+	for _, t := range tokens {
+		if t.Text != nil {
+			annotations = append(annotations, *t.Text)
+		}
+	}
+	return annotations, nil
+}
+
+func symbolText(t *ion.SymbolToken) string {
+	if t == nil {
+		return ""
+	}
+	if t.Text != nil {
+		return *t.Text
+	}
+	return fmt.Sprintf("$%d", t.LocalSID)
+}
+
+func readValue(r ion.Reader) (any, error) {
+	if r.IsNull() {
+		return nil, nil
+	}
+
+	switch r.Type() {
+	case ion.BoolType:
+		v, err := r.BoolValue()
+		if err != nil {
+			return nil, err
+		}
+		return *v, nil
+	case ion.IntType:
+		size, err := r.IntSize()
+		if err != nil {
+			return nil, err
+		}
+		if size == ion.BigInt {
+			v, err := r.BigIntValue()
+			if err != nil {
+				return nil, err
+			}
+			return json.Number(v.String()), nil
+		}
+		v, err := r.Int64Value()
+		if err != nil {
+			return nil, err
+		}
+		return *v, nil
+	case ion.FloatType:
+		v, err := r.FloatValue()
+		if err != nil {
+			return nil, err
+		}
+		return *v, nil
+	case ion.DecimalType:
+		v, err := r.DecimalValue()
+		if err != nil {
+			return nil, err
+		}
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(b), nil
+	case ion.TimestampType:
+		v, err := r.TimestampValue()
+		if err != nil {
+			return nil, err
+		}
+		return v.GetDateTime(), nil
+	case ion.StringType:
+		v, err := r.StringValue()
+		if err != nil {
+			return nil, err
+		}
+		return *v, nil
+	case ion.SymbolType:
+		v, err := r.SymbolValue()
+		if err != nil {
+			return nil, err
+		}
+		return symbolText(v), nil
+	case ion.BlobType, ion.ClobType:
+		return r.ByteValue()
+	case ion.ListType, ion.SexpType:
+		if err := r.StepIn(); err != nil {
+			return nil, err
+		}
+		arr := []any{}
+		for r.Next() {
+			v, err := readValue(r)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		return arr, r.StepOut()
+	case ion.StructType:
+		if err := r.StepIn(); err != nil {
+			return nil, err
+		}
+		obj := map[string]any{}
+		for r.Next() {
+			name, err := r.FieldName()
+			if err != nil {
+				return nil, err
+			}
+			v, err := readValue(r)
+			if err != nil {
+				return nil, err
+			}
+			obj[symbolText(name)] = v
+		}
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		return obj, r.StepOut()
+	}
+	return nil, fmt.Errorf("unsupported Ion type: %v", r.Type())
+}
+
+// encodeIon encodes a value as text or binary Ion, with optional annotations
+// of the value.
+func encodeIon(v any, annotations []string, binary bool) ([]byte, error) {
+	var buf bytes.Buffer
+	var w ion.Writer
+	if binary {
+		w = ion.NewBinaryWriter(&buf)
+	} else {
+		w = ion.NewTextWriter(&buf)
+	}
+
+	if len(annotations) > 0 {
+		tokens := make([]ion.SymbolToken, len(annotations))
+		for i, a := range annotations {
+			tokens[i] = ion.NewSymbolTokenFromString(a)
+		}
+		if err := w.Annotations(tokens...); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeValue(w, v); err != nil {
+		return nil, err
+	}
+	if err := w.Finish(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+func writeNumber(w ion.Writer, n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		return w.WriteInt(i)
+	}
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return w.WriteBigInt(i)
+	}
+	var d ion.Decimal
+	if err := d.UnmarshalJSON([]byte(n)); err != nil {
+		return err
+	}
+	return w.WriteDecimal(&d)
+}
+
+func timestamp(t time.Time) ion.Timestamp {
+	t = t.UTC()
+	if t.Nanosecond() == 0 {
+		return ion.NewTimestamp(t, ion.TimestampPrecisionSecond, ion.TimezoneUTC)
+	}
+	return ion.NewTimestampWithFractionalSeconds(t, ion.TimestampPrecisionNanosecond, ion.TimezoneUTC, 9)
+}
+
+func writeValue(w ion.Writer, v any) error {
+	switch t := v.(type) {
+	case nil:
+		return w.WriteNull()
+	case bool:
+		return w.WriteBool(t)
+	case int:
+		return w.WriteInt(int64(t))
+	case int8:
+		return w.WriteInt(int64(t))
+	case int16:
+		return w.WriteInt(int64(t))
+	case int32:
+		return w.WriteInt(int64(t))
+	case int64:
+		return w.WriteInt(t)
+	case uint:
+		return w.WriteUint(uint64(t))
+	case uint8:
+		return w.WriteUint(uint64(t))
+	case uint16:
+		return w.WriteUint(uint64(t))
+	case uint32:
+		return w.WriteUint(uint64(t))
+	case uint64:
+		return w.WriteUint(t)
+	case float32:
+		return w.WriteFloat(float64(t))
+	case float64:
+		return w.WriteFloat(t)
+	case json.Number:
+		return writeNumber(w, t)
+	case string:
+		return w.WriteString(t)
+	case []byte:
+		return w.WriteBlob(t)
+	case time.Time:
+		return w.WriteTimestamp(timestamp(t))
+	case []any:
+		if err := w.BeginList(); err != nil {
+			return err
+		}
+		for _, e := range t {
+			if err := writeValue(w, e); err != nil {
+				return err
+			}
+		}
+		return w.EndList()
+	case map[string]any:
+		if err := w.BeginStruct(); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if err := w.FieldName(ion.NewSymbolTokenFromString(k)); err != nil {
+				return err
+			}
+			if err := writeValue(w, t[k]); err != nil {
+				return err
+			}
+		}
+		return w.EndStruct()
+	}
+	return fmt.Errorf("unsupported value type for Ion: %T", v)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ipFieldOperator = "operator"
+	ipFieldFormat   = "format"
+
+	annotationsMetaKey = "ion_annotations"
+)
+
+func processorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Summary("Converts messages to or from the https://amazon-ion.github.io/ion-docs/[Amazon Ion^] format.").
+		Description(`
+The `+"`to_json`"+` operator accepts both the text and binary Ion formats. Each top level value of an Ion stream results in a separate message, and the annotations of a top level value, such as `+"`order::{id: 1}`"+`, are added to its message as the metadata field `+"`"+annotationsMetaKey+"`"+` containing an array of strings. Annotations of nested values are discarded.
+
+The `+"`from_json`"+` operator annotates the encoded value with the metadata field `+"`"+annotationsMetaKey+"`"+` when present, which may be either an array of strings or a comma separated string, and therefore annotations survive a round trip through both operators.
+
+== Type mapping
+
+Ion decimals and integers that do not fit within 64 bits are converted into JSON numbers without a loss of precision, timestamps into RFC 3339 strings, symbols into strings, blobs and clobs into bytes, and s-expressions into arrays. When converting from JSON, integers are encoded as Ion integers and all other numbers as Ion decimals.
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(ipFieldOperator, map[string]string{
+				"to_json":   "Convert Ion messages to JSON format",
+				"from_json": "Convert JSON messages to Ion format",
+			}).Description("The operation to perform on messages."),
+			service.NewStringAnnotatedEnumField(ipFieldFormat, map[string]string{
+				"binary": "The binary Ion format.",
+				"text":   "The text Ion format.",
+			}).Description("The Ion format produced by the `from_json` operator.").
+				Default("binary"),
+		).
+		Version("4.62.0")
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"ion", processorConfig(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+			return newProcessorFromConfig(conf)
+		})
+}
+
+type ionOperator func(m *service.Message) (service.MessageBatch, error)
+
+func metaAnnotations(m *service.Message) ([]string, error) {
+	v, exists := m.MetaGetMut(annotationsMetaKey)
+	if !exists {
+		return nil, nil
+	}
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil, nil
+		}
+		return strings.Split(t, ","), nil
+	case []string:
+		return t, nil
+	case []any:
+		annotations := make([]string, len(t))
+		for i, a := range t {
+			s, ok := a.(string)
+			if !ok {
+				return nil, fmt.Errorf("metadata field %v must contain strings, got %T", annotationsMetaKey, a)
+			}
+			annotations[i] = s
+		}
+		return annotations, nil
+	}
+	return nil, fmt.Errorf("metadata field %v must be an array of strings or a string, got %T", annotationsMetaKey, v)
+}
+
+func strToIonOperator(opStr string, binary bool) (ionOperator, error) {
+	switch opStr {
+	case "to_json":
+		return func(m *service.Message) (service.MessageBatch, error) {
+			mBytes, err := m.AsBytes()
+			if err != nil {
+				return nil, err
+			}
+
+			values, err := decodeIon(mBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert Ion document to JSON: %v", err)
+			}
+			if len(values) == 0 {
+				return nil, errors.New("failed to convert Ion document to JSON: document contains no values")
+			}
+
+			batch := make(service.MessageBatch, len(values))
+			for i, v := range values {
+				part := m
+				if i > 0 {
+					part = m.Copy()
+				}
+				part.MetaDelete(annotationsMetaKey)
+				if len(v.annotations) > 0 {
+					annotations := make([]any, len(v.annotations))
+					for j, a := range v.annotations {
+						annotations[j] = a
+					}
+					part.MetaSetMut(annotationsMetaKey, annotations)
+				}
+				part.SetStructuredMut(v.value)
+				batch[i] = part
+			}
+			return batch, nil
+		}, nil
+	case "from_json":
+		return func(m *service.Message) (service.MessageBatch, error) {
+			jObj, err := m.AsStructured()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse message as JSON: %v", err)
+			}
+
+			annotations, err := metaAnnotations(m)
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := encodeIon(jObj, annotations, binary)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert JSON to Ion: %v", err)
+			}
+
+			m.SetBytes(b)
+			return service.MessageBatch{m}, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("operator not recognised: %v", opStr)
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	operator ionOperator
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig) (*processor, error) {
+	operatorStr, err := conf.FieldString(ipFieldOperator)
+	if err != nil {
+		return nil, err
+	}
+	format, err := conf.FieldString(ipFieldFormat)
+	if err != nil {
+		return nil, err
+	}
+	return newProcessor(operatorStr, format == "binary")
+}
+
+func newProcessor(operatorStr string, binary bool) (*processor, error) {
+	operator, err := strToIonOperator(operatorStr, binary)
+	if err != nil {
+		return nil, err
+	}
+	return &processor{
+		operator: operator,
+	}, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	return p.operator(msg)
+}
+
+func (*processor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ion
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestIonToJSON(t *testing.T) {
+	proc, err := newProcessor("to_json", true)
+	require.NoError(t, err)
+
+	msgs, err := proc.Process(t.Context(), service.NewMessage([]byte(`
+order::{id: 1, total: 10.50, big: 123456789012345678901234567890, tags: [a, "b"], at: 2024-01-02T03:04:05Z}
+{id: 2, data: {{aGVsbG8=}}, expr: (+ 1 2), missing: null.int}
+`)))
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+
+	act, err := msgs[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id":    int64(1),
+		"total": json.Number("10.5"),
+		"big":   json.Number("123456789012345678901234567890"),
+		"tags":  []any{"a", "b"},
+		"at":    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, act)
+
+	annotations, exists := msgs[0].MetaGetMut(annotationsMetaKey)
+	require.True(t, exists)
+	assert.Equal(t, []any{"order"}, annotations)
+
+	act, err = msgs[1].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id":      int64(2),
+		"data":    []byte("hello"),
+		"expr":    []any{"+", int64(1), int64(2)},
+		"missing": nil,
+	}, act)
+
+	_, exists = msgs[1].MetaGetMut(annotationsMetaKey)
+	assert.False(t, exists)
+}
+
+func TestIonToJSONErrors(t *testing.T) {
+	proc, err := newProcessor("to_json", true)
+	require.NoError(t, err)
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`{id: `)))
+	require.Error(t, err)
+
+	_, err = proc.Process(t.Context(), service.NewMessage(nil))
+	require.ErrorContains(t, err, "no values")
+}
+
+func TestIonRoundTrip(t *testing.T) {
+	for _, binary := range []bool{true, false} {
+		fromJSON, err := newProcessor("from_json", binary)
+		require.NoError(t, err)
+
+		toJSON, err := newProcessor("to_json", binary)
+		require.NoError(t, err)
+
+		input := service.NewMessage([]byte(`{"id":1,"price":10.5,"big":123456789012345678901234567890,"name":"foo","tags":["a",null,true]}`))
+		input.MetaSetMut(annotationsMetaKey, []any{"order", "v1"})
+
+		msgs, err := fromJSON.Process(t.Context(), input)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+
+		b, err := msgs[0].AsBytes()
+		require.NoError(t, err)
+		if !binary {
+			assert.Equal(t, `order::v1::{big:123456789012345678901234567890,id:1,name:"foo",price:10.5,tags:["a",null,true]}`, string(b))
+		}
+
+		msgs, err = toJSON.Process(t.Context(), msgs[0])
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+
+		act, err := msgs[0].AsBytes()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"price":10.5,"big":123456789012345678901234567890,"name":"foo","tags":["a",null,true]}`, string(act))
+
+		annotations, exists := msgs[0].MetaGetMut(annotationsMetaKey)
+		require.True(t, exists)
+		assert.Equal(t, []any{"order", "v1"}, annotations)
+	}
+}
+
+func TestIonFromJSONStringAnnotations(t *testing.T) {
+	proc, err := newProcessor("from_json", false)
+	require.NoError(t, err)
+
+	input := service.NewMessage([]byte(`{"id":1}`))
+	input.MetaSetMut(annotationsMetaKey, "a,b")
+
+	msgs, err := proc.Process(t.Context(), input)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	b, err := msgs[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `a::b::{id:1}`, string(b))
+}
+
+func TestIonBloblang(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.format_ion(format: "text", annotations: ["doc"]).parse_ion()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(map[string]any{"foo": "bar", "n": int64(5)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"foo": "bar", "n": int64(5)}, res)
+
+	exec, err = bloblang.Parse(`root = content().parse_ion()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte(`1 2`))
+	require.ErrorContains(t, err, "expected a single Ion value")
+}
@@ -0,0 +1,395 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package join
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sjFieldKey    = "key"
+	sjFieldSide   = "side"
+	sjFieldWindow = "window"
+	sjFieldType   = "type"
+
+	sjFieldMaxRetained = "max_retained"
+
+	sjTypeInner = "inner"
+	sjTypeLeft  = "left"
+	sjTypeOuter = "outer"
+
+	sjSideLeft  = "left"
+	sjSideRight = "right"
+)
+
+func streamJoinBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Windowing").
+		Version("4.62.0").
+		Summary("Joins messages from two streams that share a key within a window of time.").
+		Description(`
+Each message written to this buffer belongs to either the left or the right side of the join, as resolved by the field `+"`"+sjFieldSide+"`"+`, and is retained for the duration of the `+"`"+sjFieldWindow+"`"+` from the moment it is added. When a message is added and the opposite side holds retained messages with the same key a joined message is emitted for each of them, and therefore a message can be joined with many messages of the opposite side throughout its window.
+
+Joined messages are objects of the form `+"`{\"left\":<left message>,\"right\":<right message>}`"+`, where the contents of each message are parsed as structured data when possible and otherwise included as a string. Joined messages carry the metadata of both messages, where the metadata of the right message takes precedence.
+
+Messages are typically sent to this buffer from a `+"`broker`"+` input consuming both streams, where the side of each message can be derived from metadata set by the input, or added with a processor of each child input.
+
+== Join types
+
+The field `+"`"+sjFieldType+"`"+` determines what happens to messages that expire from the window without being joined with any messages of the opposite side:
+
+- `+"`"+sjTypeInner+"`"+`: Unmatched messages are dropped.
+- `+"`"+sjTypeLeft+"`"+`: Unmatched messages of the left side are emitted with a `+"`right`"+` value of `+"`null`"+`, and unmatched messages of the right side are dropped.
+- `+"`"+sjTypeOuter+"`"+`: Unmatched messages of either side are emitted with a `+"`null`"+` value for the opposite side.
+
+When the input ends all retained messages are expired immediately.
+
+The number of messages retained at once can be limited with the field `+"`"+sjFieldMaxRetained+"`"+`, in which case adding a message beyond the limit expires the oldest retained message early, which is emitted according to the join type if it wasn't joined. This bounds the memory used by the buffer when many messages are never joined, at the cost of joins of messages that are evicted before their window passes.
+
+== Delivery guarantees
+
+Messages are acknowledged at the input level as soon as they are added to the buffer, and retained messages are lost if the service is shut down. Therefore this buffer does not preserve at-least-once delivery guarantees. Joined messages that are rejected by the output are returned to the buffer and delivered again.`).
+		Fields(
+			service.NewInterpolatedStringField(sjFieldKey).
+				Description("An interpolated string yielding the key to join messages by.").
+				Example(`${! this.order_id }`).
+				Example(`${! @kafka_key }`),
+			service.NewInterpolatedStringField(sjFieldSide).
+				Description("An interpolated string yielding the side of the join that a message belongs to, which must resolve to either `left` or `right`.").
+				Example(`${! if @kafka_topic == "orders" { "left" } else { "right" } }`),
+			service.NewDurationField(sjFieldWindow).
+				Description("The duration that messages are retained for joining after they are added.").
+				Example("30s").
+				Example("10m"),
+			service.NewStringAnnotatedEnumField(sjFieldType, map[string]string{
+				sjTypeInner: "Only emit messages that were joined.",
+				sjTypeLeft:  "Also emit messages of the left side that were not joined.",
+				sjTypeOuter: "Also emit messages of either side that were not joined.",
+			}).
+				Description("The type of join to perform.").
+				Default(sjTypeInner),
+			service.NewIntField(sjFieldMaxRetained).
+				Description("The maximum number of messages of both sides retained at once, where adding a message beyond the limit expires the oldest retained message early. Set to zero to retain any number of messages.").
+				Default(0).
+				Advanced(),
+		).
+		Example("Join orders with payments", "Join order events with the payment events of the order that arrive within ten minutes, emitting orders that were not paid for with a `right` value of `null`.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ orders, payments ]
+    consumer_group: order_payments
+
+buffer:
+  stream_join:
+    key: ${! this.order_id }
+    side: ${! if @kafka_topic == "orders" { "left" } else { "right" } }
+    window: 10m
+    type: left
+
+pipeline:
+  processors:
+    - mapping: |
+        root = this.left
+        root.paid = this.right != null
+`)
+}
+
+func init() {
+	service.MustRegisterBatchBuffer(
+		"stream_join", streamJoinBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newStreamJoinBufferFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type sjEntry struct {
+	msg     *service.Message
+	side    int
+	key     string
+	expires time.Time
+	matched bool
+}
+
+type streamJoinBuffer struct {
+	log *service.Logger
+
+	key         *service.InterpolatedString
+	side        *service.InterpolatedString
+	window      time.Duration
+	joinType    string
+	maxRetained int
+	clock       func() time.Time
+
+	mut sync.Mutex
+	// Retained messages of the left and right sides by key.
+	retained [2]map[string][]*sjEntry
+	// Retained messages in the order that they expire.
+	expiry  []*sjEntry
+	pending service.MessageBatch
+	// The number of batches read that haven't been acknowledged yet.
+	inFlight int
+
+	notifyChan     chan struct{}
+	endOfInputChan chan struct{}
+	endOfInputOnce sync.Once
+}
+
+func newStreamJoinBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*streamJoinBuffer, error) {
+	b := &streamJoinBuffer{
+		log:            mgr.Logger(),
+		clock:          time.Now,
+		retained:       [2]map[string][]*sjEntry{{}, {}},
+		notifyChan:     make(chan struct{}, 1),
+		endOfInputChan: make(chan struct{}),
+	}
+
+	var err error
+	if b.key, err = conf.FieldInterpolatedString(sjFieldKey); err != nil {
+		return nil, err
+	}
+	if b.side, err = conf.FieldInterpolatedString(sjFieldSide); err != nil {
+		return nil, err
+	}
+	if b.window, err = conf.FieldDuration(sjFieldWindow); err != nil {
+		return nil, err
+	}
+	if b.window <= 0 {
+		return nil, errors.New("window must be greater than zero")
+	}
+	if b.joinType, err = conf.FieldString(sjFieldType); err != nil {
+		return nil, err
+	}
+	if b.maxRetained, err = conf.FieldInt(sjFieldMaxRetained); err != nil {
+		return nil, err
+	}
+	if b.maxRetained < 0 {
+		return nil, errors.New("max_retained must not be negative")
+	}
+	return b, nil
+}
+
+func structuredOrString(msg *service.Message) any {
+	if v, err := msg.AsStructured(); err == nil {
+		return v
+	}
+	mBytes, _ := msg.AsBytes()
+	return string(mBytes)
+}
+
+// joinMessages creates a joined message from a left and right message, either
+// of which can be nil.
+func joinMessages(left, right *service.Message) *service.Message {
+	out := service.NewMessage(nil)
+	obj := map[string]any{sjSideLeft: nil, sjSideRight: nil}
+	for i, m := range []*service.Message{left, right} {
+		if m == nil {
+			continue
+		}
+		_ = m.MetaWalkMut(func(k string, v any) error {
+			out.MetaSetMut(k, v)
+			return nil
+		})
+		if i == 0 {
+			obj[sjSideLeft] = structuredOrString(m)
+		} else {
+			obj[sjSideRight] = structuredOrString(m)
+		}
+	}
+	out.SetStructuredMut(obj)
+	return out
+}
+
+func (b *streamJoinBuffer) emitUnmatched(e *sjEntry) {
+	if e.matched {
+		return
+	}
+	switch {
+	case e.side == 0 && (b.joinType == sjTypeLeft || b.joinType == sjTypeOuter):
+		b.pending = append(b.pending, joinMessages(e.msg, nil))
+	case e.side == 1 && b.joinType == sjTypeOuter:
+		b.pending = append(b.pending, joinMessages(nil, e.msg))
+	}
+}
+
+// expire removes retained messages that have expired by a given time, or all
+// of them when the time is zero. Must be called whilst holding the lock.
+func (b *streamJoinBuffer) expire(now time.Time) {
+	for len(b.expiry) > 0 && (now.IsZero() || !b.expiry[0].expires.After(now)) {
+		b.expireOldest()
+	}
+}
+
+// expireOldest removes the retained message that expires first. Must be called
+// whilst holding the lock.
+func (b *streamJoinBuffer) expireOldest() {
+	e := b.expiry[0]
+	b.expiry[0] = nil
+	b.expiry = b.expiry[1:]
+	b.emitUnmatched(e)
+
+	// Entries of a key expire in the order they were added.
+	entries := b.retained[e.side][e.key]
+	if len(entries) <= 1 {
+		delete(b.retained[e.side], e.key)
+	} else {
+		b.retained[e.side][e.key] = entries[1:]
+	}
+}
+
+func (b *streamJoinBuffer) notify() {
+	select {
+	case b.notifyChan <- struct{}{}:
+	default:
+	}
+}
+
+func (b *streamJoinBuffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	type keyedMsg struct {
+		msg  *service.Message
+		key  string
+		side int
+	}
+	msgs := make([]keyedMsg, 0, len(batch))
+	for i, msg := range batch {
+		key, err := batch.TryInterpolatedString(i, b.key)
+		if err != nil {
+			return fmt.Errorf("key interpolation error: %w", err)
+		}
+		sideStr, err := batch.TryInterpolatedString(i, b.side)
+		if err != nil {
+			return fmt.Errorf("side interpolation error: %w", err)
+		}
+		var side int
+		switch sideStr {
+		case sjSideLeft:
+		case sjSideRight:
+			side = 1
+		default:
+			return fmt.Errorf("side must resolve to either %v or %v, got: %v", sjSideLeft, sjSideRight, sideStr)
+		}
+		msgs = append(msgs, keyedMsg{msg: msg, key: key, side: side})
+	}
+
+	b.mut.Lock()
+	now := b.clock()
+	b.expire(now)
+	for _, m := range msgs {
+		e := &sjEntry{
+			msg:     m.msg,
+			side:    m.side,
+			key:     m.key,
+			expires: now.Add(b.window),
+		}
+		for _, other := range b.retained[1-m.side][m.key] {
+			if m.side == 0 {
+				b.pending = append(b.pending, joinMessages(m.msg, other.msg))
+			} else {
+				b.pending = append(b.pending, joinMessages(other.msg, m.msg))
+			}
+			other.matched = true
+			e.matched = true
+		}
+		b.retained[m.side][m.key] = append(b.retained[m.side][m.key], e)
+		b.expiry = append(b.expiry, e)
+		if b.maxRetained > 0 && len(b.expiry) > b.maxRetained {
+			b.expireOldest()
+		}
+	}
+	b.mut.Unlock()
+
+	b.notify()
+	return aFn(ctx, nil)
+}
+
+func (b *streamJoinBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		var endOfInput bool
+		select {
+		case <-b.endOfInputChan:
+			endOfInput = true
+		default:
+		}
+
+		b.mut.Lock()
+		if endOfInput {
+			b.expire(time.Time{})
+		} else {
+			b.expire(b.clock())
+		}
+		if len(b.pending) > 0 {
+			batch := b.pending
+			b.pending = nil
+			b.inFlight++
+			b.mut.Unlock()
+			return batch, func(_ context.Context, err error) error {
+				b.mut.Lock()
+				b.inFlight--
+				if err != nil {
+					// Rejected batches are delivered again ahead of newer
+					// joins.
+					b.pending = append(batch, b.pending...)
+				}
+				b.mut.Unlock()
+				b.notify()
+				return nil
+			}, nil
+		}
+		var nextExpiry <-chan time.Time
+		if len(b.expiry) > 0 {
+			nextExpiry = time.After(b.expiry[0].expires.Sub(b.clock()))
+		}
+		inFlight := b.inFlight
+		b.mut.Unlock()
+
+		// Batches that are still in flight might be rejected and delivered
+		// again, and so the buffer only ends once they're acknowledged.
+		if endOfInput && inFlight == 0 {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		endOfInputChan := b.endOfInputChan
+		if endOfInput {
+			// Only an acknowledgement can change anything now.
+			endOfInputChan = nil
+		}
+		select {
+		case <-b.notifyChan:
+		case <-nextExpiry:
+		case <-endOfInputChan:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+func (b *streamJoinBuffer) EndOfInput() {
+	b.endOfInputOnce.Do(func() {
+		close(b.endOfInputChan)
+	})
+}
+
+func (*streamJoinBuffer) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package join
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type testClock struct {
+	mut sync.Mutex
+	t   time.Time
+}
+
+func (c *testClock) now() time.Time {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.t
+}
+
+func (c *testClock) add(d time.Duration) {
+	c.mut.Lock()
+	c.t = c.t.Add(d)
+	c.mut.Unlock()
+}
+
+func testStreamJoinBuffer(t *testing.T, joinType string) (*streamJoinBuffer, *testClock) {
+	t.Helper()
+
+	pConf, err := streamJoinBufferConfig().ParseYAML(`
+key: ${! this.id }
+side: ${! @side }
+window: 1m
+type: `+joinType+`
+`, nil)
+	require.NoError(t, err)
+
+	b, err := newStreamJoinBufferFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	clock := &testClock{t: time.Unix(1000, 0)}
+	b.clock = clock.now
+	return b, clock
+}
+
+func writeSide(t *testing.T, b *streamJoinBuffer, side string, contents ...string) {
+	t.Helper()
+
+	var batch service.MessageBatch
+	for _, c := range contents {
+		msg := service.NewMessage([]byte(c))
+		msg.MetaSetMut("side", side)
+		batch = append(batch, msg)
+	}
+	var acked bool
+	require.NoError(t, b.WriteBatch(t.Context(), batch, func(context.Context, error) error {
+		acked = true
+		return nil
+	}))
+	assert.True(t, acked)
+}
+
+func readJoined(t *testing.T, b *streamJoinBuffer) (outputs []string) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), time.Millisecond*100)
+	defer done()
+
+	batch, aFn, err := b.ReadBatch(ctx)
+	if err != nil {
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		return nil
+	}
+	require.NoError(t, aFn(t.Context(), nil))
+	for _, msg := range batch {
+		mBytes, err := msg.AsBytes()
+		require.NoError(t, err)
+		outputs = append(outputs, string(mBytes))
+	}
+	return
+}
+
+func TestStreamJoinInner(t *testing.T) {
+	b, clock := testStreamJoinBuffer(t, "inner")
+
+	writeSide(t, b, "left", `{"id":1,"l":"a"}`, `{"id":2,"l":"b"}`)
+	assert.Empty(t, readJoined(t, b))
+
+	writeSide(t, b, "right", `{"id":1,"r":"c"}`, `{"id":3,"r":"d"}`)
+	assert.Equal(t, []string{
+		`{"left":{"id":1,"l":"a"},"right":{"id":1,"r":"c"}}`,
+	}, readJoined(t, b))
+
+	// Messages are joined with all retained messages of the opposite side.
+	writeSide(t, b, "left", `{"id":1,"l":"e"}`)
+	assert.Equal(t, []string{
+		`{"left":{"id":1,"l":"e"},"right":{"id":1,"r":"c"}}`,
+	}, readJoined(t, b))
+
+	clock.add(time.Minute)
+	writeSide(t, b, "right", `{"id":1,"r":"f"}`, `{"id":2,"r":"g"}`)
+	assert.Empty(t, readJoined(t, b))
+}
+
+func TestStreamJoinLeftAndOuter(t *testing.T) {
+	for joinType, exp := range map[string][]string{
+		"left": {
+			`{"left":{"id":2},"right":null}`,
+		},
+		"outer": {
+			`{"left":{"id":2},"right":null}`,
+			`{"left":null,"right":{"id":3}}`,
+		},
+	} {
+		t.Run(joinType, func(t *testing.T) {
+			b, clock := testStreamJoinBuffer(t, joinType)
+
+			writeSide(t, b, "left", `{"id":1}`, `{"id":2}`)
+			writeSide(t, b, "right", `{"id":1}`, `{"id":3}`)
+			assert.Equal(t, []string{
+				`{"left":{"id":1},"right":{"id":1}}`,
+			}, readJoined(t, b))
+
+			clock.add(time.Second * 30)
+			assert.Empty(t, readJoined(t, b))
+
+			clock.add(time.Second * 30)
+			assert.Equal(t, exp, readJoined(t, b))
+		})
+	}
+}
+
+func TestStreamJoinEndOfInput(t *testing.T) {
+	b, _ := testStreamJoinBuffer(t, "outer")
+
+	writeSide(t, b, "left", `{"id":1}`)
+	writeSide(t, b, "right", `{"id":2}`)
+	b.EndOfInput()
+
+	assert.Equal(t, []string{
+		`{"left":{"id":1},"right":null}`,
+		`{"left":null,"right":{"id":2}}`,
+	}, readJoined(t, b))
+
+	_, _, err := b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestStreamJoinBadSide(t *testing.T) {
+	b, _ := testStreamJoinBuffer(t, "inner")
+
+	msg := service.NewMessage([]byte(`{"id":1}`))
+	msg.MetaSetMut("side", "middle")
+	err := b.WriteBatch(t.Context(), service.MessageBatch{msg}, func(context.Context, error) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "side must resolve to either left or right")
+}
+
+func TestStreamJoinMetadata(t *testing.T) {
+	b, _ := testStreamJoinBuffer(t, "inner")
+
+	left := service.NewMessage([]byte(`{"id":1}`))
+	left.MetaSetMut("side", "left")
+	left.MetaSetMut("a", "left a")
+	left.MetaSetMut("b", "left b")
+	require.NoError(t, b.WriteBatch(t.Context(), service.MessageBatch{left}, func(context.Context, error) error { return nil }))
+
+	right := service.NewMessage([]byte(`{"id":1}`))
+	right.MetaSetMut("side", "right")
+	right.MetaSetMut("b", "right b")
+	require.NoError(t, b.WriteBatch(t.Context(), service.MessageBatch{right}, func(context.Context, error) error { return nil }))
+
+	batch, _, err := b.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	v, _ := batch[0].MetaGet("a")
+	assert.Equal(t, "left a", v)
+	v, _ = batch[0].MetaGet("b")
+	assert.Equal(t, "right b", v)
+	v, _ = batch[0].MetaGet("side")
+	assert.Equal(t, "right", v)
+}
+
+func TestStreamJoinNackRequeues(t *testing.T) {
+	b, _ := testStreamJoinBuffer(t, "inner")
+
+	writeSide(t, b, "left", `{"id":1}`)
+	writeSide(t, b, "right", `{"id":1}`)
+	b.EndOfInput()
+
+	batch, aFn, err := b.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	// The buffer doesn't end while a batch might still be rejected.
+	ctx, done := context.WithTimeout(t.Context(), time.Millisecond*50)
+	defer done()
+	_, _, err = b.ReadBatch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A rejected batch is delivered again.
+	require.NoError(t, aFn(t.Context(), errors.New("nope")))
+	assert.Equal(t, []string{
+		`{"left":{"id":1},"right":{"id":1}}`,
+	}, readJoined(t, b))
+
+	_, _, err = b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestStreamJoinMaxRetained(t *testing.T) {
+	pConf, err := streamJoinBufferConfig().ParseYAML(`
+key: ${! this.id }
+side: ${! @side }
+window: 1m
+type: left
+max_retained: 2
+`, nil)
+	require.NoError(t, err)
+
+	b, err := newStreamJoinBufferFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	// Retaining a third message evicts the oldest, which is emitted as an
+	// unmatched left message before its window passes.
+	writeSide(t, b, "left", `{"id":1}`, `{"id":2}`)
+	assert.Empty(t, readJoined(t, b))
+	writeSide(t, b, "left", `{"id":3}`)
+	assert.Equal(t, []string{
+		`{"left":{"id":1},"right":null}`,
+	}, readJoined(t, b))
+	assert.Len(t, b.expiry, 2)
+
+	// Evicted messages are no longer joined.
+	writeSide(t, b, "right", `{"id":1}`)
+	assert.Equal(t, []string{
+		`{"left":{"id":2},"right":null}`,
+	}, readJoined(t, b))
+	assert.Len(t, b.retained[0], 1)
+	assert.Len(t, b.retained[1], 1)
+}
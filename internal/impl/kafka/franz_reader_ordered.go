@@ -80,6 +80,7 @@ type FranzReaderOrdered struct {
 	readBackOff           backoff.BackOff
 	topicLagRefreshPeriod time.Duration
 	batchMaxSize          uint64
+	traceContext          *franzTraceContext
 
 	res     *service.Resources
 	log     *service.Logger
@@ -120,6 +121,10 @@ func NewFranzReaderOrderedFromConfig(conf *service.ParsedConfig, res *service.Re
 		return nil, err
 	}
 
+	if f.traceContext, err = franzTraceContextFromParsed(conf); err != nil {
+		return nil, err
+	}
+
 	return &f, nil
 }
 
@@ -134,11 +139,11 @@ type batchWithRecords struct {
 	size uint64
 }
 
-func recordsToBatch(records []*kgo.Record, consumerLag *ConsumerLag) (batch batchWithRecords) {
+func recordsToBatch(records []*kgo.Record, consumerLag *ConsumerLag, traceContext *franzTraceContext) (batch batchWithRecords) {
 	batch.b = make([]*messageWithRecord, len(records))
 
 	for i, r := range records {
-		msg := FranzRecordToMessageV1(r)
+		msg := traceContext.extract(r, FranzRecordToMessageV1(r))
 		if consumerLag != nil {
 			lag := consumerLag.Load(r.Topic, r.Partition)
 			msg.MetaSetMut("kafka_lag", lag)
@@ -540,7 +545,7 @@ func (f *FranzReaderOrdered) Connect(ctx context.Context) error {
 					return
 				}
 
-				batch := recordsToBatch(p.Records, consumerLag)
+				batch := recordsToBatch(p.Records, consumerLag, f.traceContext)
 				if len(batch.b) == 0 {
 					return
 				}
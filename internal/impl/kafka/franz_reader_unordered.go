@@ -92,6 +92,7 @@ type FranzReaderUnordered struct {
 	multiHeader           bool
 	batchPolicy           service.BatchPolicy
 	topicLagRefreshPeriod time.Duration
+	traceContext          *franzTraceContext
 
 	batchChan atomic.Value
 	res       *service.Resources
@@ -141,6 +142,10 @@ func NewFranzReaderUnorderedFromConfig(conf *service.ParsedConfig, res *service.
 		return nil, err
 	}
 
+	if f.traceContext, err = franzTraceContextFromParsed(conf); err != nil {
+		return nil, err
+	}
+
 	return &f, nil
 }
 
@@ -150,7 +155,7 @@ type msgWithRecord struct {
 }
 
 func (f *FranzReaderUnordered) recordToMessage(record *kgo.Record, consumerLag *ConsumerLag) *msgWithRecord {
-	msg := FranzRecordToMessageV0(record, f.multiHeader)
+	msg := f.traceContext.extract(record, FranzRecordToMessageV0(record, f.multiHeader))
 	if consumerLag != nil {
 		lag := consumerLag.Load(record.Topic, record.Partition)
 		msg.MetaSetMut("kafka_lag", lag)
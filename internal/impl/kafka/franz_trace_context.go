@@ -0,0 +1,126 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kftcFieldTraceContext      = "trace_context"
+	kftcFieldEnabled           = "enabled"
+	kftcFieldTraceparentHeader = "traceparent_header"
+	kftcFieldTracestateHeader  = "tracestate_header"
+
+	// The keys of the W3C trace context propagator.
+	w3cTraceparent = "traceparent"
+	w3cTracestate  = "tracestate"
+)
+
+// FranzTraceContextField returns a field for propagating the W3C trace context
+// of messages via record headers.
+func FranzTraceContextField() *service.ConfigField {
+	return service.NewObjectField(kftcFieldTraceContext,
+		service.NewBoolField(kftcFieldEnabled).
+			Description("Whether to propagate trace context via record headers.").
+			Default(true),
+		service.NewStringField(kftcFieldTraceparentHeader).
+			Description("The record header containing the W3C `traceparent`.").
+			Default(w3cTraceparent),
+		service.NewStringField(kftcFieldTracestateHeader).
+			Description("The record header containing the W3C `tracestate`.").
+			Default(w3cTracestate),
+	).
+		Description("Propagate the https://www.w3.org/TR/trace-context/[W3C trace context^] of messages via record headers. When consuming, the tracing spans of each record continue the trace found within its headers, and when producing, the trace context of each message is written to the headers of its record, replacing any headers of the same name. This links the spans of a pipeline into the distributed traces of the services producing and consuming its data when a xref:components:tracers/about.adoc[tracer] is configured, and has no effect otherwise.").
+		Version("4.62.0").
+		Advanced()
+}
+
+// franzTraceContext propagates the W3C trace context of messages via record
+// headers. A nil franzTraceContext propagates nothing.
+type franzTraceContext struct {
+	traceparentHeader string
+	tracestateHeader  string
+}
+
+// franzTraceContextFromParsed extracts a franzTraceContext from a parsed
+// config, returning nil when the field is absent or propagation is disabled.
+func franzTraceContextFromParsed(conf *service.ParsedConfig) (*franzTraceContext, error) {
+	if !conf.Contains(kftcFieldTraceContext) {
+		return nil, nil
+	}
+	conf = conf.Namespace(kftcFieldTraceContext)
+
+	enabled, err := conf.FieldBool(kftcFieldEnabled)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	t := &franzTraceContext{}
+	if t.traceparentHeader, err = conf.FieldString(kftcFieldTraceparentHeader); err != nil {
+		return nil, err
+	}
+	if t.tracestateHeader, err = conf.FieldString(kftcFieldTracestateHeader); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// extract sets the trace context found within the headers of a record as the
+// parent of the tracing span that's created for the message when it's read.
+func (t *franzTraceContext) extract(record *kgo.Record, msg *service.Message) *service.Message {
+	if t == nil {
+		return msg
+	}
+	c := propagation.MapCarrier{}
+	for _, hdr := range record.Headers {
+		switch hdr.Key {
+		case t.traceparentHeader:
+			c[w3cTraceparent] = string(hdr.Value)
+		case t.tracestateHeader:
+			c[w3cTracestate] = string(hdr.Value)
+		}
+	}
+	if _, exists := c[w3cTraceparent]; !exists {
+		return msg
+	}
+	return msg.WithContext(propagation.TraceContext{}.Extract(msg.Context(), c))
+}
+
+// inject writes the trace context of a message to the headers of its record.
+func (t *franzTraceContext) inject(msg *service.Message, record *kgo.Record) {
+	if t == nil {
+		return
+	}
+	c := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(msg.Context(), c)
+	if _, exists := c[w3cTraceparent]; !exists {
+		return
+	}
+
+	headers := record.Headers[:0]
+	for _, hdr := range record.Headers {
+		if hdr.Key != t.traceparentHeader && hdr.Key != t.tracestateHeader {
+			headers = append(headers, hdr)
+		}
+	}
+	headers = append(headers, kgo.RecordHeader{Key: t.traceparentHeader, Value: []byte(c[w3cTraceparent])})
+	if state := c[w3cTracestate]; state != "" {
+		headers = append(headers, kgo.RecordHeader{Key: t.tracestateHeader, Value: []byte(state)})
+	}
+	record.Headers = headers
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func parseTestTraceContext(t *testing.T, conf string) *franzTraceContext {
+	t.Helper()
+
+	pConf, err := service.NewConfigSpec().Field(FranzTraceContextField()).ParseYAML(conf, nil)
+	require.NoError(t, err)
+	tc, err := franzTraceContextFromParsed(pConf)
+	require.NoError(t, err)
+	return tc
+}
+
+func TestFranzTraceContextRoundTrip(t *testing.T) {
+	tc := parseTestTraceContext(t, `
+trace_context:
+  traceparent_header: x-traceparent
+`)
+
+	in := &kgo.Record{Headers: []kgo.RecordHeader{
+		{Key: "x-traceparent", Value: []byte(testTraceparent)},
+		{Key: "tracestate", Value: []byte("foo=bar")},
+	}}
+	msg := tc.extract(in, service.NewMessage([]byte("hello")))
+
+	sc := trace.SpanContextFromContext(msg.Context())
+	require.True(t, sc.IsValid())
+	assert.True(t, sc.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	assert.Equal(t, "foo=bar", sc.TraceState().String())
+
+	// Existing headers of the same name are replaced.
+	out := &kgo.Record{Headers: []kgo.RecordHeader{
+		{Key: "x-traceparent", Value: []byte("stale")},
+		{Key: "baz", Value: []byte("buz")},
+	}}
+	tc.inject(msg, out)
+	assert.Equal(t, []kgo.RecordHeader{
+		{Key: "baz", Value: []byte("buz")},
+		{Key: "x-traceparent", Value: []byte(testTraceparent)},
+		{Key: "tracestate", Value: []byte("foo=bar")},
+	}, out.Headers)
+}
+
+func TestFranzTraceContextWithoutTrace(t *testing.T) {
+	tc := parseTestTraceContext(t, `{}`)
+
+	msg := tc.extract(&kgo.Record{}, service.NewMessage([]byte("hello")))
+	assert.False(t, trace.SpanContextFromContext(msg.Context()).IsValid())
+
+	out := &kgo.Record{}
+	tc.inject(msg, out)
+	assert.Empty(t, out.Headers)
+}
+
+func TestFranzTraceContextDisabled(t *testing.T) {
+	assert.Nil(t, parseTestTraceContext(t, `
+trace_context:
+  enabled: false
+`))
+
+	var tc *franzTraceContext
+	in := &kgo.Record{Headers: []kgo.RecordHeader{
+		{Key: "traceparent", Value: []byte(testTraceparent)},
+	}}
+	msg := tc.extract(in, service.NewMessage([]byte("hello")))
+	assert.False(t, trace.SpanContextFromContext(msg.Context()).IsValid())
+}
@@ -292,6 +292,7 @@ type FranzWriter struct {
 	IsTimestampMs bool
 	MetaFilter    *service.MetadataFilter
 	hooks         franzWriterHooks
+	traceContext  *franzTraceContext
 	// OnWrite is executed for each record before it is written to the broker.
 	OnWrite func(ctx context.Context, client *kgo.Client, records []*kgo.Record) error
 }
@@ -343,6 +344,10 @@ func NewFranzWriterFromConfig(conf *service.ParsedConfig, hooks franzWriterHooks
 		w.IsTimestampMs = true
 	}
 
+	if w.traceContext, err = franzTraceContextFromParsed(conf); err != nil {
+		return nil, err
+	}
+
 	return &w, nil
 }
 
@@ -399,6 +404,7 @@ func (w *FranzWriter) BatchToRecords(_ context.Context, b service.MessageBatch)
 			})
 			return nil
 		})
+		w.traceContext.inject(msg, record)
 		if timestampExecutor != nil {
 			if tsStr, err := timestampExecutor.TryString(i); err != nil {
 				return nil, fmt.Errorf("timestamp interpolation error: %w", err)
@@ -59,6 +59,7 @@ func FranzKafkaInputConfigFields() []*service.ConfigField {
 		FranzConsumerFields(),
 		FranzReaderUnorderedConfigFields(),
 		[]*service.ConfigField{
+			FranzTraceContextField(),
 			service.NewAutoRetryNacksToggleField(),
 		},
 	)
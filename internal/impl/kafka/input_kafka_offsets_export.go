@@ -0,0 +1,303 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	koeFieldTopics            = "topics"
+	koeFieldRegexpTopics      = "regexp_topics"
+	koeFieldConsumerGroups    = "consumer_groups"
+	koeFieldFormat            = "format"
+	koeFieldIncludeTimestamps = "include_timestamps"
+)
+
+func kafkaOffsetsExportInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Exports the committed offsets of consumer groups as a single portable artifact.").
+		Description(`
+This input fetches the committed offsets of consumer groups once, emits a single message containing them in either JSON or CSV form and then shuts down. The artifact can be written to a file and carried to another cluster, where it is applied with the `+"`kafka_offsets_import`"+` output. This enables migrations where the source and destination clusters are never reachable at the same time.
+
+When `+"`include_timestamps`"+` is enabled the timestamp of the record each committed offset points to is also recorded, which allows the import to translate offsets onto a cluster where the same records live at different offsets.
+
+== Metadata
+
+This input adds the following metadata fields to the message:
+
+`+"```text"+`
+- kafka_offsets_format
+- kafka_offsets_count
+`+"```"+`
+`).
+		Fields(FranzConnectionFields()...).
+		Fields(
+			service.NewStringListField(koeFieldTopics).
+				Description("An optional list of topics to export offsets for. Multiple comma separated topics can be listed in a single element. When empty the offsets of all topics are exported.").
+				Example([]string{"foo", "bar"}).
+				Default([]any{}),
+			service.NewBoolField(koeFieldRegexpTopics).
+				Description("Whether listed topics should be interpreted as regular expression patterns for matching multiple topics.").
+				Default(false),
+			service.NewStringListField(koeFieldConsumerGroups).
+				Description("An optional list of consumer groups to export. When empty all consumer groups are exported.").
+				Default([]any{}),
+			service.NewStringEnumField(koeFieldFormat, offsetsArtifactFormatJSON, offsetsArtifactFormatCSV).
+				Description("The format of the exported artifact.").
+				Default(offsetsArtifactFormatJSON),
+			service.NewBoolField(koeFieldIncludeTimestamps).
+				Description("Whether to look up and record the timestamp of the record each committed offset points to, enabling timestamp based translation on import.").
+				Default(true),
+		).
+		Example("Export to a file", "Write the offsets of all consumer groups to a local JSON file.", `
+input:
+  kafka_offsets_export:
+    seed_brokers: [ source:9092 ]
+
+output:
+  file:
+    path: ./offsets.json
+    codec: all-bytes
+`)
+}
+
+func init() {
+	service.MustRegisterInput("kafka_offsets_export", kafkaOffsetsExportInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			return newKafkaOffsetsExportInputFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type kafkaOffsetsExportInput struct {
+	clientOpts        []kgo.Opt
+	topics            []string
+	topicPatterns     []*regexp.Regexp
+	groups            []string
+	format            string
+	includeTimestamps bool
+
+	mut    sync.Mutex
+	client *kgo.Client
+	done   bool
+
+	log *service.Logger
+}
+
+func newKafkaOffsetsExportInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*kafkaOffsetsExportInput, error) {
+	i := kafkaOffsetsExportInput{log: mgr.Logger()}
+
+	var err error
+	if i.clientOpts, err = FranzConnectionOptsFromConfig(conf, mgr.Logger()); err != nil {
+		return nil, err
+	}
+
+	topicList, err := conf.FieldStringList(koeFieldTopics)
+	if err != nil {
+		return nil, err
+	}
+	if i.topics, _, err = ParseTopics(topicList, -1, false); err != nil {
+		return nil, err
+	}
+
+	if regexpTopics, err := conf.FieldBool(koeFieldRegexpTopics); err != nil {
+		return nil, err
+	} else if regexpTopics {
+		for _, topic := range i.topics {
+			tp, err := regexp.Compile(topic)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile topic regex %q: %s", topic, err)
+			}
+			i.topicPatterns = append(i.topicPatterns, tp)
+		}
+	}
+
+	if i.groups, err = conf.FieldStringList(koeFieldConsumerGroups); err != nil {
+		return nil, err
+	}
+	if i.format, err = conf.FieldString(koeFieldFormat); err != nil {
+		return nil, err
+	}
+	if i.includeTimestamps, err = conf.FieldBool(koeFieldIncludeTimestamps); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (i *kafkaOffsetsExportInput) matchesTopic(topic string) bool {
+	if len(i.topicPatterns) > 0 {
+		return slices.ContainsFunc(i.topicPatterns, func(tp *regexp.Regexp) bool {
+			return tp.MatchString(topic)
+		})
+	}
+	if len(i.topics) == 0 {
+		return true
+	}
+	return slices.Contains(i.topics, topic)
+}
+
+func (i *kafkaOffsetsExportInput) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil || i.done {
+		return nil
+	}
+
+	var err error
+	if i.client, err = NewFranzClient(ctx, i.clientOpts...); err != nil {
+		return fmt.Errorf("failed to connect: %s", err)
+	}
+	return nil
+}
+
+func (i *kafkaOffsetsExportInput) fetchRecords(ctx context.Context) ([]offsetsArtifactRecord, error) {
+	adm := kadm.NewClient(i.client)
+
+	groups := i.groups
+	if len(groups) == 0 {
+		listed, err := adm.ListGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list consumer groups: %s", err)
+		}
+		groups = listed.Groups()
+	}
+
+	resp := adm.FetchManyOffsets(ctx, groups...)
+	if err := resp.Error(); err != nil {
+		return nil, fmt.Errorf("failed to fetch consumer group offsets: %s", err)
+	}
+
+	var records []offsetsArtifactRecord
+	for group, offsetResp := range resp {
+		offsetResp.Fetched.Each(func(offset kadm.OffsetResponse) {
+			if !i.matchesTopic(offset.Topic) || offset.At < 0 {
+				return
+			}
+			records = append(records, offsetsArtifactRecord{
+				Group:     group,
+				Topic:     offset.Topic,
+				Partition: offset.Partition,
+				Offset:    offset.At,
+				Metadata:  offset.Metadata,
+			})
+		})
+	}
+	sortOffsetsArtifactRecords(records)
+
+	if !i.includeTimestamps || len(records) == 0 {
+		return records, nil
+	}
+
+	// Multiple groups might have committed different offsets for the same
+	// topic partition, so we resolve timestamps one distinct offset at a time.
+	pending := records
+	for len(pending) > 0 {
+		tsRequests := make(timestampRequests)
+		var deferred []offsetsArtifactRecord
+		for _, r := range pending {
+			if _, ok := tsRequests[r.Topic]; !ok {
+				tsRequests[r.Topic] = make(map[int32]int64)
+			}
+			if o, exists := tsRequests[r.Topic][r.Partition]; exists && o != r.Offset {
+				deferred = append(deferred, r)
+				continue
+			}
+			tsRequests[r.Topic][r.Partition] = r.Offset
+		}
+
+		tsReader := &redpandaMigratorOffsetsInput{
+			client:    i.client,
+			admClient: adm,
+			log:       i.log,
+		}
+		tsResults, err := tsReader.getTimestampsForCommittedOffsets(ctx, tsRequests)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timestamps for committed offsets: %s", err)
+		}
+
+		for j := range records {
+			r := &records[j]
+			if r.Timestamp != nil || tsRequests[r.Topic][r.Partition] != r.Offset {
+				continue
+			}
+			if res, ok := tsResults[r.Topic][r.Partition]; ok {
+				ts := res.timestamp
+				r.Timestamp = &ts
+				r.IsHighWatermark = res.isHighWatermark
+			} else {
+				i.log.Warnf("Unable to read the timestamp for group %q topic %q partition %d offset %d, the topic may have been truncated", r.Group, r.Topic, r.Partition, r.Offset)
+			}
+		}
+		pending = deferred
+	}
+	return records, nil
+}
+
+func (i *kafkaOffsetsExportInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.done {
+		return nil, nil, service.ErrEndOfInput
+	}
+	if i.client == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	records, err := i.fetchRecords(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := encodeOffsetsArtifact(i.format, records)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode offsets artifact: %w", err)
+	}
+
+	i.done = true
+	i.client.Close()
+	i.client = nil
+
+	msg := service.NewMessage(data)
+	msg.MetaSetMut("kafka_offsets_format", i.format)
+	msg.MetaSetMut("kafka_offsets_count", len(records))
+	return msg, func(context.Context, error) error { return nil }, nil
+}
+
+func (i *kafkaOffsetsExportInput) Close(context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.client != nil {
+		i.client.Close()
+		i.client = nil
+	}
+	return nil
+}
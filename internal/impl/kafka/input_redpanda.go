@@ -15,6 +15,7 @@
 package kafka
 
 import (
+	"errors"
 	"slices"
 
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -91,6 +92,8 @@ func redpandaInputConfigFields() []*service.ConfigField {
 		FranzConsumerFields(),
 		FranzReaderOrderedConfigFields(),
 		[]*service.ConfigField{
+			redpandaTopicReloadField(),
+			FranzTraceContextField(),
 			service.NewAutoRetryNacksToggleField(),
 		},
 	)
@@ -99,24 +102,58 @@ func redpandaInputConfigFields() []*service.ConfigField {
 func init() {
 	service.MustRegisterBatchInput("redpanda", redpandaInputConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
-			tmpOpts, err := FranzConnectionOptsFromConfig(conf, mgr.Logger())
+			rdr, err := newRedpandaReaderFromConfig(conf, mgr)
 			if err != nil {
 				return nil, err
 			}
-			clientOpts := slices.Clone(tmpOpts)
-
-			if tmpOpts, err = FranzConsumerOptsFromConfig(conf); err != nil {
-				return nil, err
-			}
-			clientOpts = append(clientOpts, tmpOpts...)
-
-			rdr, err := NewFranzReaderOrderedFromConfig(conf, mgr, func() ([]kgo.Opt, error) {
-				return clientOpts, nil
-			})
-			if err != nil {
-				return nil, err
-			}
-
 			return service.AutoRetryNacksBatchedToggled(conf, rdr)
 		})
 }
+
+// newRedpandaReaderFromConfig creates the reader of a redpanda input, which is
+// wrapped by a topic reloader when reloading is enabled.
+func newRedpandaReaderFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+	tmpOpts, err := FranzConnectionOptsFromConfig(conf, mgr.Logger())
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := slices.Clone(tmpOpts)
+
+	details, err := FranzConsumerDetailsFromConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	reload := conf.Contains(rtrFieldTopicReload)
+	initialTopics, regex := details.Topics, details.RegexPattern
+	if reload {
+		if len(details.TopicPartitions) > 0 {
+			return nil, errors.New("explicit partitions cannot be consumed when topic_reload is enabled")
+		}
+		if regex {
+			// Regular expressions are resolved by the reloader, so the client
+			// begins without any topics.
+			details.Topics, details.RegexPattern = nil, false
+		}
+	}
+	clientOpts = append(clientOpts, details.FranzOpts()...)
+
+	var reloader *redpandaTopicReloader
+	rdr, err := NewFranzReaderOrderedFromConfig(conf, mgr, func() ([]kgo.Opt, error) {
+		if reloader != nil {
+			return reloader.clientOpts(clientOpts), nil
+		}
+		return clientOpts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !reload {
+		return rdr, nil
+	}
+
+	if reloader, err = newRedpandaTopicReloader(conf.Namespace(rtrFieldTopicReload), rdr, initialTopics, regex, mgr); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	rtrFieldTopicReload = "topic_reload"
+	rtrFieldPath        = "path"
+	rtrFieldURL         = "url"
+	rtrFieldCheckPeriod = "check_period"
+)
+
+func redpandaTopicReloadField() *service.ConfigField {
+	return service.NewObjectField(rtrFieldTopicReload,
+		service.NewStringField(rtrFieldPath).
+			Description("The path of a local file containing the topics to consume.").
+			Example("./topics.yaml").
+			Optional(),
+		service.NewURLField(rtrFieldURL).
+			Description("An HTTP endpoint returning the topics to consume, such as a management API.").
+			Example("http://localhost:8080/subscriptions/foo").
+			Optional(),
+		service.NewDurationField(rtrFieldCheckPeriod).
+			Description("The period of time between each check for topic changes. When `regexp_topics` is enabled this is also the period between each discovery of newly created topics matching the patterns.").
+			Default("10s"),
+	).
+		Description(`Reload the list of topics to consume at runtime without restarting the input. The source must contain a YAML or JSON document with a ` + "`topics`" + ` list, following the same format as the ` + "`topics`" + ` field, e.g. ` + "`{\"topics\":[\"foo\",\"bar.*\"]}`" + `. Topics added to the list are subscribed to and topics removed from the list are unsubscribed from, whilst the consumer group membership and committed offsets are preserved. If the source cannot be read the previous list of topics remains in use, and when it has never been read the ` + "`topics`" + ` field is used. Explicit partitions are not supported when reloading is enabled.`).
+		Version("4.62.0").
+		Optional().
+		Advanced()
+}
+
+//------------------------------------------------------------------------------
+
+type topicReloadSourceDoc struct {
+	Topics []string `yaml:"topics"`
+}
+
+// redpandaTopicReloader wraps a FranzReaderOrdered and keeps the set of
+// consumed topics in sync with an external source. The client of the reader is
+// only accessed while holding the lock, as it's replaced when reconnecting.
+type redpandaTopicReloader struct {
+	*FranzReaderOrdered
+
+	path        string
+	url         string
+	checkPeriod time.Duration
+	regex       bool
+	httpClient  *http.Client
+
+	mut           sync.Mutex
+	patterns      []string
+	consumed      []string
+	loopStarted   bool
+	reloadShutSig *shutdown.Signaller
+	log           *service.Logger
+}
+
+func newRedpandaTopicReloader(conf *service.ParsedConfig, rdr *FranzReaderOrdered, initialTopics []string, regex bool, mgr *service.Resources) (*redpandaTopicReloader, error) {
+	r := &redpandaTopicReloader{
+		FranzReaderOrdered: rdr,
+		regex:              regex,
+		patterns:           initialTopics,
+		httpClient:         &http.Client{Timeout: 30 * time.Second},
+		reloadShutSig:      shutdown.NewSignaller(),
+		log:                mgr.Logger(),
+	}
+	if !regex {
+		// Without regular expressions the client is created consuming the
+		// configured topics.
+		r.consumed = slices.Clone(initialTopics)
+	}
+
+	var err error
+	if conf.Contains(rtrFieldPath) {
+		if r.path, err = conf.FieldString(rtrFieldPath); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(rtrFieldURL) {
+		if r.url, err = conf.FieldString(rtrFieldURL); err != nil {
+			return nil, err
+		}
+	}
+	if (r.path == "") == (r.url == "") {
+		return nil, fmt.Errorf("exactly one of %s.%s or %s.%s must be specified", rtrFieldTopicReload, rtrFieldPath, rtrFieldTopicReload, rtrFieldURL)
+	}
+	if r.checkPeriod, err = conf.FieldDuration(rtrFieldCheckPeriod); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *redpandaTopicReloader) readSource(ctx context.Context) ([]byte, error) {
+	if r.path != "" {
+		return os.ReadFile(r.path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func parseTopicReloadDoc(data []byte) ([]string, error) {
+	var doc topicReloadSourceDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse topics document: %w", err)
+	}
+	topics, topicPartitions, err := ParseTopics(doc.Topics, -1, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(topicPartitions) > 0 {
+		return nil, errors.New("explicit partitions are not supported when reloading topics")
+	}
+	return topics, nil
+}
+
+// resolve returns the topics that should be consumed given the current
+// patterns, listing the topics of the cluster when patterns are regular
+// expressions.
+func (r *redpandaTopicReloader) resolve(ctx context.Context, patterns []string) ([]string, error) {
+	if !r.regex {
+		return patterns, nil
+	}
+
+	exprs := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile topic regex %q: %w", p, err)
+		}
+		exprs = append(exprs, re)
+	}
+
+	details, err := kadm.NewClient(r.Client).ListTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	var topics []string
+	for _, d := range details.Sorted() {
+		if d.IsInternal || d.Err != nil {
+			continue
+		}
+		if slices.ContainsFunc(exprs, func(re *regexp.Regexp) bool { return re.MatchString(d.Topic) }) {
+			topics = append(topics, d.Topic)
+		}
+	}
+	return topics, nil
+}
+
+// sync reads the topic source and updates the consumed topics of the client.
+func (r *redpandaTopicReloader) sync(ctx context.Context) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if data, err := r.readSource(ctx); err != nil {
+		r.log.Errorf("Failed to read topic reload source, keeping the current topics: %v", err)
+	} else if patterns, err := parseTopicReloadDoc(data); err != nil {
+		r.log.Errorf("Failed to parse topic reload source, keeping the current topics: %v", err)
+	} else if !slices.Equal(patterns, r.patterns) {
+		r.log.Infof("Topic subscription changed from %v to %v", r.patterns, patterns)
+		r.patterns = patterns
+	}
+
+	desired, err := r.resolve(ctx, r.patterns)
+	if err != nil {
+		r.log.Errorf("Failed to resolve topics: %v", err)
+		return
+	}
+
+	var added, removed []string
+	for _, t := range desired {
+		if !slices.Contains(r.consumed, t) {
+			added = append(added, t)
+		}
+	}
+	for _, t := range r.consumed {
+		if !slices.Contains(desired, t) {
+			removed = append(removed, t)
+		}
+	}
+
+	if len(added) > 0 {
+		r.log.Infof("Adding topics to consume: %v", added)
+		r.Client.AddConsumeTopics(added...)
+	}
+	if len(removed) > 0 {
+		r.log.Infof("Removing topics from consumption: %v", removed)
+		r.Client.PurgeTopicsFromConsuming(removed...)
+	}
+	r.consumed = slices.Clone(desired)
+}
+
+// clientOpts returns the options of a new client of the reader, which consumes
+// the topics most recently resolved rather than those configured, such that a
+// reconnect doesn't undo changes to the subscription. It's called by the reader
+// when connecting, during which the lock is held.
+func (r *redpandaTopicReloader) clientOpts(base []kgo.Opt) []kgo.Opt {
+	return append(slices.Clone(base), kgo.ConsumeTopics(r.consumed...))
+}
+
+// Connect connects the underlying reader and begins watching the topic source.
+func (r *redpandaTopicReloader) Connect(ctx context.Context) error {
+	r.mut.Lock()
+	prevClient := r.Client
+	if err := r.FranzReaderOrdered.Connect(ctx); err != nil {
+		r.mut.Unlock()
+		return err
+	}
+	if r.Client == prevClient {
+		r.mut.Unlock()
+		return nil
+	}
+	startLoop := !r.loopStarted
+	r.loopStarted = true
+	r.mut.Unlock()
+
+	// A new client consumes the topics most recently resolved, which are
+	// synced immediately in case the source changed in the meantime.
+	r.sync(ctx)
+	if !startLoop {
+		return nil
+	}
+
+	go func() {
+		defer r.reloadShutSig.TriggerHasStopped()
+
+		closeCtx, done := r.reloadShutSig.SoftStopCtx(context.Background())
+		defer done()
+
+		ticker := time.NewTicker(r.checkPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.sync(closeCtx)
+			case <-closeCtx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops watching the topic source and closes the underlying reader.
+func (r *redpandaTopicReloader) Close(ctx context.Context) error {
+	r.reloadShutSig.TriggerSoftStop()
+
+	r.mut.Lock()
+	started := r.loopStarted
+	r.mut.Unlock()
+
+	if started {
+		select {
+		case <-r.reloadShutSig.HasStoppedChan():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return r.FranzReaderOrdered.Close(ctx)
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestParseTopicReloadDoc(t *testing.T) {
+	topics, err := parseTopicReloadDoc([]byte(`topics: [ "foo, bar", baz ]`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "baz"}, topics)
+
+	topics, err = parseTopicReloadDoc([]byte(`{"topics":["foo"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, topics)
+
+	_, err = parseTopicReloadDoc([]byte(`topics: [ "foo:0" ]`))
+	require.Error(t, err)
+}
+
+func TestRedpandaInputTopicReload(t *testing.T) {
+	for _, regex := range []bool{false, true} {
+		t.Run(fmt.Sprintf("regex_%v", regex), func(t *testing.T) {
+			cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "foo", "bar"))
+			require.NoError(t, err)
+			t.Cleanup(cluster.Close)
+
+			client, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+			require.NoError(t, err)
+			t.Cleanup(client.Close)
+
+			produce := func(topic, value string) {
+				ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+				defer cancel()
+				require.NoError(t, client.ProduceSync(ctx, &kgo.Record{Topic: topic, Value: []byte(value)}).FirstErr())
+			}
+
+			topicsPath := filepath.Join(t.TempDir(), "topics.yaml")
+			writeTopics := func(topic string) {
+				if regex {
+					topic = "^" + topic[:2] + ".$"
+				}
+				require.NoError(t, os.WriteFile(topicsPath, fmt.Appendf(nil, "topics: [ %q ]", topic), 0o644))
+			}
+			writeTopics("foo")
+
+			sb := service.NewStreamBuilder()
+			require.NoError(t, sb.AddInputYAML(fmt.Sprintf(`
+redpanda:
+  seed_brokers: %v
+  topics: [ does_not_exist ]
+  regexp_topics: %v
+  consumer_group: cg
+  topic_reload:
+    path: %s
+    check_period: 50ms
+`, cluster.ListenAddrs(), regex, topicsPath)))
+			require.NoError(t, sb.SetLoggerYAML(`level: OFF`))
+
+			msgChan := make(chan string, 10)
+			require.NoError(t, sb.AddConsumerFunc(func(_ context.Context, msg *service.Message) error {
+				topic, _ := msg.MetaGet("kafka_topic")
+				b, _ := msg.AsBytes()
+				msgChan <- topic + ":" + string(b)
+				return nil
+			}))
+
+			stream, err := sb.Build()
+			require.NoError(t, err)
+			go func() {
+				_ = stream.Run(t.Context())
+			}()
+			t.Cleanup(func() {
+				require.NoError(t, stream.StopWithin(5*time.Second))
+			})
+
+			expect := func(expected string) {
+				select {
+				case got := <-msgChan:
+					assert.Equal(t, expected, got)
+				case <-time.After(30 * time.Second):
+					require.Fail(t, "timed out waiting for message", expected)
+				}
+			}
+
+			produce("foo", "1")
+			produce("bar", "1")
+			expect("foo:1")
+
+			writeTopics("bar")
+			expect("bar:1")
+
+			produce("foo", "2")
+			produce("bar", "2")
+			expect("bar:2")
+
+			select {
+			case got := <-msgChan:
+				require.Fail(t, "unexpected message from unsubscribed topic", got)
+			case <-time.After(500 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestRedpandaInputTopicReloadReconnect(t *testing.T) {
+	for _, regex := range []bool{false, true} {
+		t.Run(fmt.Sprintf("regex_%v", regex), func(t *testing.T) {
+			cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "foo", "bar"))
+			require.NoError(t, err)
+			t.Cleanup(cluster.Close)
+
+			client, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+			require.NoError(t, err)
+			t.Cleanup(client.Close)
+
+			produce := func(topic, value string) {
+				ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+				defer cancel()
+				require.NoError(t, client.ProduceSync(ctx, &kgo.Record{Topic: topic, Value: []byte(value)}).FirstErr())
+			}
+
+			topicsPath := filepath.Join(t.TempDir(), "topics.yaml")
+			writeTopics := func(topic string) {
+				if regex {
+					topic = "^" + topic[:2] + ".$"
+				}
+				require.NoError(t, os.WriteFile(topicsPath, fmt.Appendf(nil, "topics: [ %q ]", topic), 0o644))
+			}
+			writeTopics("foo")
+
+			conf, err := redpandaInputConfig().ParseYAML(fmt.Sprintf(`
+seed_brokers: %v
+topics: [ foo ]
+regexp_topics: %v
+consumer_group: cg
+topic_reload:
+  path: %s
+  check_period: 50ms
+`, cluster.ListenAddrs(), regex, topicsPath), nil)
+			require.NoError(t, err)
+
+			mgr := service.MockResources()
+			rdr, err := newRedpandaReaderFromConfig(conf, mgr)
+			require.NoError(t, err)
+			r := rdr.(*redpandaTopicReloader)
+			t.Cleanup(func() {
+				require.NoError(t, r.Close(context.Background()))
+			})
+
+			// readUntil reads messages until the expected one, failing on any
+			// message of a topic that isn't consumed.
+			readUntil := func(expected, unexpectedTopic string) {
+				ctx, cancel := context.WithTimeout(t.Context(), 30*time.Second)
+				defer cancel()
+				for {
+					batch, ackFn, err := r.ReadBatch(ctx)
+					require.NoError(t, err)
+					found := false
+					for _, msg := range batch {
+						topic, _ := msg.MetaGet("kafka_topic")
+						b, _ := msg.AsBytes()
+						require.NotEqual(t, unexpectedTopic, topic, "unexpected message %s", b)
+						found = found || topic+":"+string(b) == expected
+					}
+					require.NoError(t, ackFn(ctx, nil))
+					if found {
+						return
+					}
+				}
+			}
+
+			require.NoError(t, r.Connect(t.Context()))
+			produce("foo", "1")
+			readUntil("foo:1", "bar")
+
+			writeTopics("bar")
+			produce("bar", "1")
+			readUntil("bar:1", "")
+
+			// Reconnecting creates a new client, which must continue to
+			// consume the reloaded topics rather than the configured ones.
+			r.mut.Lock()
+			prev := r.FranzReaderOrdered
+			r.FranzReaderOrdered, err = NewFranzReaderOrderedFromConfig(conf, mgr, prev.clientOpts)
+			r.mut.Unlock()
+			require.NoError(t, err)
+			require.NoError(t, prev.Close(t.Context()))
+
+			require.NoError(t, r.Connect(t.Context()))
+			r.mut.Lock()
+			assert.Equal(t, []string{"bar"}, r.Client.GetConsumeTopics())
+			r.mut.Unlock()
+
+			produce("foo", "2")
+			produce("bar", "2")
+			readUntil("bar:2", "foo")
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	offsetsArtifactFormatJSON = "json"
+	offsetsArtifactFormatCSV  = "csv"
+
+	offsetsArtifactVersion = 1
+)
+
+var offsetsArtifactCSVHeader = []string{"group", "topic", "partition", "offset", "timestamp", "is_high_watermark", "metadata"}
+
+// offsetsArtifactRecord describes the committed offset of a consumer group for
+// a single topic partition.
+type offsetsArtifactRecord struct {
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Metadata  string `json:"metadata,omitempty"`
+
+	// Timestamp is the timestamp in milliseconds of the record the committed
+	// offset points to, and is used for translating offsets onto a cluster
+	// where they differ. A nil value means the timestamp is unknown.
+	Timestamp *int64 `json:"timestamp,omitempty"`
+
+	// IsHighWatermark indicates whether the committed offset pointed to the
+	// high watermark of the partition at the time of the export.
+	IsHighWatermark bool `json:"is_high_watermark"`
+}
+
+// offsetsArtifact is a portable snapshot of consumer group offsets which can be
+// moved between clusters that are never simultaneously reachable.
+type offsetsArtifact struct {
+	Version int                     `json:"version"`
+	Offsets []offsetsArtifactRecord `json:"offsets"`
+}
+
+func sortOffsetsArtifactRecords(records []offsetsArtifactRecord) {
+	slices.SortFunc(records, func(a, b offsetsArtifactRecord) int {
+		if c := strings.Compare(a.Group, b.Group); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Topic, b.Topic); c != 0 {
+			return c
+		}
+		return int(a.Partition - b.Partition)
+	})
+}
+
+func encodeOffsetsArtifact(format string, records []offsetsArtifactRecord) ([]byte, error) {
+	switch format {
+	case offsetsArtifactFormatJSON:
+		return json.MarshalIndent(offsetsArtifact{
+			Version: offsetsArtifactVersion,
+			Offsets: records,
+		}, "", "  ")
+	case offsetsArtifactFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(offsetsArtifactCSVHeader); err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			var ts string
+			if r.Timestamp != nil {
+				ts = strconv.FormatInt(*r.Timestamp, 10)
+			}
+			if err := w.Write([]string{
+				r.Group,
+				r.Topic,
+				strconv.FormatInt(int64(r.Partition), 10),
+				strconv.FormatInt(r.Offset, 10),
+				ts,
+				strconv.FormatBool(r.IsHighWatermark),
+				r.Metadata,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported offsets artifact format: %v", format)
+}
+
+func decodeOffsetsArtifact(format string, data []byte) ([]offsetsArtifactRecord, error) {
+	switch format {
+	case offsetsArtifactFormatJSON:
+		var a offsetsArtifact
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, fmt.Errorf("parsing offsets artifact: %w", err)
+		}
+		if a.Version != offsetsArtifactVersion {
+			return nil, fmt.Errorf("unsupported offsets artifact version: %v", a.Version)
+		}
+		return a.Offsets, nil
+	case offsetsArtifactFormatCSV:
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("parsing offsets artifact: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, errors.New("offsets artifact is missing a header row")
+		}
+		if !slices.Equal(rows[0], offsetsArtifactCSVHeader) {
+			return nil, fmt.Errorf("unexpected offsets artifact header: %v", rows[0])
+		}
+		records := make([]offsetsArtifactRecord, 0, len(rows)-1)
+		for i, row := range rows[1:] {
+			r, err := offsetsArtifactRecordFromCSV(row)
+			if err != nil {
+				return nil, fmt.Errorf("row %v: %w", i+1, err)
+			}
+			records = append(records, r)
+		}
+		return records, nil
+	}
+	return nil, fmt.Errorf("unsupported offsets artifact format: %v", format)
+}
+
+func offsetsArtifactRecordFromCSV(row []string) (r offsetsArtifactRecord, err error) {
+	r.Group, r.Topic, r.Metadata = row[0], row[1], row[6]
+
+	var partition int64
+	if partition, err = strconv.ParseInt(row[2], 10, 32); err != nil {
+		return r, fmt.Errorf("parsing partition: %w", err)
+	}
+	r.Partition = int32(partition)
+
+	if r.Offset, err = strconv.ParseInt(row[3], 10, 64); err != nil {
+		return r, fmt.Errorf("parsing offset: %w", err)
+	}
+	if row[4] != "" {
+		ts, err := strconv.ParseInt(row[4], 10, 64)
+		if err != nil {
+			return r, fmt.Errorf("parsing timestamp: %w", err)
+		}
+		r.Timestamp = &ts
+	}
+	if r.IsHighWatermark, err = strconv.ParseBool(row[5]); err != nil {
+		return r, fmt.Errorf("parsing is_high_watermark: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestOffsetsArtifactRoundTrip(t *testing.T) {
+	ts := int64(1700000000000)
+	records := []offsetsArtifactRecord{
+		{Group: "a", Topic: "foo", Partition: 0, Offset: 10, Timestamp: &ts},
+		{Group: "b", Topic: "bar", Partition: 3, Offset: 42, Metadata: "with,comma", IsHighWatermark: true},
+	}
+
+	for _, format := range []string{offsetsArtifactFormatJSON, offsetsArtifactFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeOffsetsArtifact(format, records)
+			require.NoError(t, err)
+
+			decoded, err := decodeOffsetsArtifact(format, data)
+			require.NoError(t, err)
+			assert.Equal(t, records, decoded)
+		})
+	}
+}
+
+func TestOffsetsArtifactCSV(t *testing.T) {
+	ts := int64(5)
+	data, err := encodeOffsetsArtifact(offsetsArtifactFormatCSV, []offsetsArtifactRecord{
+		{Group: "a", Topic: "foo", Partition: 1, Offset: 2, Timestamp: &ts},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "group,topic,partition,offset,timestamp,is_high_watermark,metadata\na,foo,1,2,5,false,\n", string(data))
+
+	_, err = decodeOffsetsArtifact(offsetsArtifactFormatCSV, []byte("nope\n"))
+	require.ErrorContains(t, err, "unexpected offsets artifact header")
+
+	_, err = decodeOffsetsArtifact(offsetsArtifactFormatJSON, []byte(`{"version":2,"offsets":[]}`))
+	require.ErrorContains(t, err, "unsupported offsets artifact version")
+}
+
+func TestKafkaOffsetsExportImport(t *testing.T) {
+	topic, group := "foobar", "foobar_cg"
+
+	newCluster := func() (*kfake.Cluster, *kgo.Client) {
+		cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(2, topic))
+		require.NoError(t, err)
+		t.Cleanup(cluster.Close)
+
+		client, err := kgo.NewClient(
+			kgo.SeedBrokers(cluster.ListenAddrs()...),
+			kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		return cluster, client
+	}
+
+	produce := func(client *kgo.Client, partition int32, timestamps ...int64) {
+		for _, ts := range timestamps {
+			ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+			require.NoError(t, client.ProduceSync(ctx, &kgo.Record{
+				Topic:     topic,
+				Partition: partition,
+				Value:     []byte(strconv.FormatInt(ts, 10)),
+				Timestamp: time.UnixMilli(ts),
+			}).FirstErr())
+			cancel()
+		}
+	}
+
+	src, srcClient := newCluster()
+	produce(srcClient, 0, 1, 2, 3, 4)
+	produce(srcClient, 1, 1, 2)
+	createUpdateConsumerGroup(t, kadm.NewClient(srcClient), group, kadm.Offset{Topic: topic, Partition: 0, At: 2, Metadata: "meta"})
+	createUpdateConsumerGroup(t, kadm.NewClient(srcClient), group, kadm.Offset{Topic: topic, Partition: 1, At: 2})
+
+	// The destination is missing the first record of partition 0, and so the
+	// offsets of the remaining records are shifted.
+	dst, dstClient := newCluster()
+	produce(dstClient, 0, 2, 3, 4)
+	produce(dstClient, 1, 1, 2)
+
+	exportConf, err := kafkaOffsetsExportInputConfig().ParseYAML(fmt.Sprintf(`
+seed_brokers: %v
+format: csv
+`, src.ListenAddrs()), nil)
+	require.NoError(t, err)
+
+	in, err := newKafkaOffsetsExportInputFromConfig(exportConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(t.Context()))
+
+	msg, _, err := in.Read(t.Context())
+	require.NoError(t, err)
+
+	_, _, err = in.Read(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+	require.NoError(t, in.Close(t.Context()))
+
+	data, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `group,topic,partition,offset,timestamp,is_high_watermark,metadata
+foobar_cg,foobar,0,2,3,false,meta
+foobar_cg,foobar,1,2,2,true,
+`, string(data))
+
+	importConf, err := kafkaOffsetsImportOutputConfig().ParseYAML(fmt.Sprintf(`
+seed_brokers: %v
+format: csv
+`, dst.ListenAddrs()), nil)
+	require.NoError(t, err)
+
+	out, err := newKafkaOffsetsImportOutputFromConfig(importConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() { _ = out.Close(context.Background()) })
+
+	require.NoError(t, out.Write(t.Context(), msg))
+
+	offsets, err := kadm.NewClient(dstClient).FetchOffsets(t.Context(), group)
+	require.NoError(t, err)
+
+	p0, ok := offsets.Lookup(topic, 0)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), p0.At)
+	assert.Equal(t, "meta", p0.Metadata)
+
+	p1, ok := offsets.Lookup(topic, 1)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), p1.At)
+}
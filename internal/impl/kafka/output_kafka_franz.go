@@ -57,6 +57,7 @@ func FranzKafkaOutputConfigFields() []*service.ConfigField {
 				Description("The maximum number of batches to be sending in parallel at any given time.").
 				Default(10),
 			service.NewBatchPolicyField(kfoFieldBatching),
+			FranzTraceContextField(),
 
 			// Deprecated
 			service.NewStringField(kfoFieldRackID).Deprecated(),
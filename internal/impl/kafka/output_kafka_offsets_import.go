@@ -0,0 +1,261 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	koiFieldFormat      = "format"
+	koiFieldTranslation = "translation"
+	koiFieldTopicPrefix = "topic_prefix"
+	koiFieldAllowRewind = "allow_rewind"
+
+	offsetsTranslationNone      = "none"
+	offsetsTranslationTimestamp = "timestamp"
+)
+
+func kafkaOffsetsImportOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Commits consumer group offsets from an artifact produced by the `kafka_offsets_export` input.").
+		Description(`
+Each message written to this output must contain a complete offsets artifact, as produced by the `+"`kafka_offsets_export`"+` input. The offsets are committed to the consumer groups of the target cluster, optionally translating them using the timestamps recorded during the export.
+
+Offsets are never moved backwards for a consumer group that already has a committed offset ahead of the imported one, unless `+"`allow_rewind`"+` is enabled.`).
+		Fields(FranzConnectionFields()...).
+		Fields(
+			service.NewStringEnumField(koiFieldFormat, offsetsArtifactFormatJSON, offsetsArtifactFormatCSV).
+				Description("The format of the artifact.").
+				Default(offsetsArtifactFormatJSON),
+			service.NewStringAnnotatedEnumField(koiFieldTranslation, map[string]string{
+				offsetsTranslationNone:      "Commit the exported offsets verbatim, which is appropriate when the target topics contain identical offsets, such as when restoring onto the same cluster.",
+				offsetsTranslationTimestamp: "Translate each offset to the earliest offset of the target partition with a timestamp equal to or greater than the recorded timestamp. Offsets recorded at the high watermark are translated to the high watermark of the target partition.",
+			}).
+				Description("How exported offsets are mapped onto the target cluster.").
+				Default(offsetsTranslationTimestamp),
+			service.NewStringField(koiFieldTopicPrefix).
+				Description("An optional prefix added to topic names before committing offsets.").
+				Default("").
+				Advanced(),
+			service.NewBoolField(koiFieldAllowRewind).
+				Description("Whether to commit offsets that are behind the existing committed offsets of a consumer group.").
+				Default(false).
+				Advanced(),
+		).
+		Example("Import from a file", "Apply an offsets artifact to a destination cluster.", `
+input:
+  file:
+    paths: [ ./offsets.json ]
+    scanner:
+      to_the_end: {}
+
+output:
+  kafka_offsets_import:
+    seed_brokers: [ destination:9092 ]
+`)
+}
+
+func init() {
+	service.MustRegisterOutput("kafka_offsets_import", kafkaOffsetsImportOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.Output, maxInFlight int, err error) {
+			maxInFlight = 1
+			out, err = newKafkaOffsetsImportOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type kafkaOffsetsImportOutput struct {
+	clientOpts  []kgo.Opt
+	format      string
+	translation string
+	topicPrefix string
+	allowRewind bool
+
+	connMut sync.Mutex
+	client  *kadm.Client
+
+	log *service.Logger
+}
+
+func newKafkaOffsetsImportOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*kafkaOffsetsImportOutput, error) {
+	o := kafkaOffsetsImportOutput{log: mgr.Logger()}
+
+	var err error
+	if o.clientOpts, err = FranzConnectionOptsFromConfig(conf, mgr.Logger()); err != nil {
+		return nil, err
+	}
+	if o.format, err = conf.FieldString(koiFieldFormat); err != nil {
+		return nil, err
+	}
+	if o.translation, err = conf.FieldString(koiFieldTranslation); err != nil {
+		return nil, err
+	}
+	if o.topicPrefix, err = conf.FieldString(koiFieldTopicPrefix); err != nil {
+		return nil, err
+	}
+	if o.allowRewind, err = conf.FieldBool(koiFieldAllowRewind); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (o *kafkaOffsetsImportOutput) Connect(ctx context.Context) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	if o.client != nil {
+		return nil
+	}
+
+	client, err := NewFranzClient(ctx, o.clientOpts...)
+	if err != nil {
+		return err
+	}
+	o.client = kadm.NewClient(client)
+	return nil
+}
+
+// translateOffset determines the offset on the target cluster that corresponds
+// to an exported record.
+func (o *kafkaOffsetsImportOutput) translateOffset(ctx context.Context, r offsetsArtifactRecord, topic string) (int64, error) {
+	if o.translation == offsetsTranslationNone {
+		return r.Offset, nil
+	}
+
+	if r.IsHighWatermark {
+		ends, err := o.client.ListEndOffsets(ctx, topic)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list the high watermark for topic %q: %s", topic, err)
+		}
+		end, ok := ends.Lookup(topic, r.Partition)
+		if !ok {
+			return 0, fmt.Errorf("topic %q partition %d not found", topic, r.Partition)
+		}
+		if end.Err != nil {
+			return 0, end.Err
+		}
+		return end.Offset, nil
+	}
+
+	if r.Timestamp == nil {
+		return 0, errors.New("offset has no recorded timestamp, it must be exported with include_timestamps enabled")
+	}
+
+	listed, err := o.client.ListOffsetsAfterMilli(ctx, *r.Timestamp, topic)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list offsets for topic %q and timestamp %d: %s", topic, *r.Timestamp, err)
+	}
+	listedOffset, ok := listed.Lookup(topic, r.Partition)
+	if !ok {
+		return 0, fmt.Errorf("topic %q partition %d not found", topic, r.Partition)
+	}
+	if listedOffset.Err != nil {
+		return 0, listedOffset.Err
+	}
+	return listedOffset.Offset, nil
+}
+
+func (o *kafkaOffsetsImportOutput) Write(ctx context.Context, msg *service.Message) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	if o.client == nil {
+		return service.ErrNotConnected
+	}
+
+	data, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	records, err := decodeOffsetsArtifact(o.format, data)
+	if err != nil {
+		return err
+	}
+
+	toCommit := map[string]kadm.Offsets{}
+	for _, r := range records {
+		topic := o.topicPrefix + r.Topic
+		at, err := o.translateOffset(ctx, r, topic)
+		if err != nil {
+			return fmt.Errorf("failed to translate offset for group %q topic %q partition %d: %w", r.Group, topic, r.Partition, err)
+		}
+		offsets := toCommit[r.Group]
+		offsets.Add(kadm.Offset{
+			Topic:       topic,
+			Partition:   r.Partition,
+			At:          at,
+			LeaderEpoch: -1,
+			Metadata:    r.Metadata,
+		})
+		toCommit[r.Group] = offsets
+	}
+
+	for group, offsets := range toCommit {
+		if !o.allowRewind {
+			// A group that does not exist yet has no offsets to protect.
+			current, err := o.client.FetchOffsets(ctx, group)
+			if err != nil && !errors.Is(err, kerr.GroupIDNotFound) {
+				return fmt.Errorf("failed to fetch the consumer group %q offsets: %s", group, err)
+			}
+			if err := current.Error(); err != nil {
+				return fmt.Errorf("consumer group offsets %q could not be read: %s", group, err)
+			}
+			offsets.DeleteFunc(func(offset kadm.Offset) bool {
+				existing, ok := current.Lookup(offset.Topic, offset.Partition)
+				if ok && existing.At > offset.At {
+					o.log.Warnf("Skipping offset import for group %q topic %q partition %d because the existing offset %d is ahead of the imported offset %d", group, offset.Topic, offset.Partition, existing.At, offset.At)
+					return true
+				}
+				return false
+			})
+		}
+
+		responses, err := o.client.CommitOffsets(ctx, group, offsets)
+		if err != nil {
+			return fmt.Errorf("failed to commit offsets for group %q: %s", group, err)
+		}
+		if err := responses.Error(); err != nil {
+			return fmt.Errorf("committed offsets returned an error for group %q: %s", group, err)
+		}
+		o.log.Debugf("Imported offsets for consumer group %q", group)
+	}
+	return nil
+}
+
+func (o *kafkaOffsetsImportOutput) Close(context.Context) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	if o.client != nil {
+		o.client.Close()
+		o.client = nil
+	}
+	return nil
+}
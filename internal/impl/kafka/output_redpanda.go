@@ -48,6 +48,7 @@ func redpandaOutputConfigFields() []*service.ConfigField {
 			service.NewIntField(roFieldMaxInFlight).
 				Description("The maximum number of batches to be sending in parallel at any given time.").
 				Default(256),
+			FranzTraceContextField(),
 		},
 		FranzProducerFields(),
 	)
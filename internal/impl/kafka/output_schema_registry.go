@@ -43,6 +43,7 @@ const (
 	sroFieldRemoveMetadata       = "remove_metadata"
 	sroFieldRemoveRuleSet        = "remove_rule_set"
 	sroFieldInputResource        = "input_resource"
+	sroFieldPolicy               = "policy"
 	sroFieldTLS                  = "tls"
 
 	sroResourceDefaultLabel = "schema_registry_output"
@@ -92,6 +93,10 @@ func schemaRegistryOutputConfigFields() []*service.ConfigField {
 			Description("The label of the schema_registry input from which to read source schemas.").
 			Default(sriResourceDefaultLabel).
 			Advanced(),
+		service.NewObjectField(sroFieldPolicy, schemaRegistryPolicyFields()...).
+			Description("Limits that schemas must satisfy before they are registered, allowing platform teams to gate what enters a shared registry. Policies also apply to backfilled references and previous versions.").
+			Version("4.62.0").
+			Advanced(),
 		service.NewTLSToggledField(sroFieldTLS),
 		service.NewOutputMaxInFlightField(),
 	},
@@ -119,18 +124,24 @@ type schemaRegistryOutput struct {
 	removeMetadata       bool
 	removeRuleSet        bool
 	inputResource        srResourceKey
+	policy               schemaPolicy
 
 	client      *sr.Client
 	inputClient *sr.Client
 	connected   atomic.Bool
 	mgr         *service.Resources
+
+	policyViolations *service.MetricCounter
+	// Stores <Subject, Version> as key and the reference depth as value.
+	referenceDepthCache sync.Map
 	// Stores <SchemaID, SchemaVersionID, Subject> as key and destination SchemaID as value.
 	schemaLineageCache sync.Map
 }
 
 func outputFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (o *schemaRegistryOutput, err error) {
 	o = &schemaRegistryOutput{
-		mgr: mgr,
+		mgr:              mgr,
+		policyViolations: mgr.Metrics().NewCounter("schema_registry_policy_violations", "policy"),
 	}
 
 	var srURLStr string
@@ -174,6 +185,10 @@ func outputFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (o *s
 		o.inputResource = srResourceKey(res)
 	}
 
+	if o.policy, err = schemaPolicyFromParsed(pConf.Namespace(sroFieldPolicy)); err != nil {
+		return nil, err
+	}
+
 	var reqSigner func(f fs.FS, req *http.Request) error
 	if reqSigner, err = pConf.HTTPRequestAuthSignerFromParsed(); err != nil {
 		return nil, err
@@ -399,6 +414,10 @@ func (o *schemaRegistryOutput) createSchema(ctx context.Context, key schemaLinea
 		return destinationID.(int), nil
 	}
 
+	if err := o.enforcePolicy(ctx, ss); err != nil {
+		return -1, err
+	}
+
 	if o.removeMetadata {
 		ss.SchemaMetadata = nil
 	}
@@ -452,3 +471,68 @@ func (o *schemaRegistryOutput) createSchema(ctx context.Context, key schemaLinea
 
 	return destinationID, nil
 }
+
+// enforcePolicy checks the provided schema against the configured policy and
+// either returns a *SchemaPolicyError or flags the violation.
+func (o *schemaRegistryOutput) enforcePolicy(ctx context.Context, ss franz_sr.SubjectSchema) error {
+	if !o.policy.enabled() {
+		return nil
+	}
+
+	var depth int
+	if o.policy.maxReferenceDepth > 0 {
+		var err error
+		if depth, err = o.referenceDepth(ctx, ss.References); err != nil {
+			return fmt.Errorf("failed to calculate reference depth for schema with subject %q and version %d: %s", ss.Subject, ss.Version, err)
+		}
+	}
+
+	err := o.policy.check(ss, depth)
+	if err == nil {
+		return nil
+	}
+
+	policyErr := err.(*SchemaPolicyError)
+	for _, v := range policyErr.Violations {
+		o.policyViolations.Incr(1, v.Policy)
+	}
+	if o.policy.action == srpActionFlag {
+		o.mgr.Logger().Warnf("Registering schema despite policy violation: %s", err)
+		return nil
+	}
+	return err
+}
+
+// referenceDepth returns the maximum depth of the provided schema references,
+// resolving them from the source Schema Registry when available and otherwise
+// from the destination.
+func (o *schemaRegistryOutput) referenceDepth(ctx context.Context, refs []franz_sr.SchemaReference) (int, error) {
+	client := o.inputClient
+	if client == nil {
+		client = o.client
+	}
+
+	var maxDepth int
+	for _, ref := range refs {
+		key := schemaLineageCacheKey{versionID: ref.Version, subject: ref.Subject}
+		if depth, ok := o.referenceDepthCache.Load(key); ok {
+			maxDepth = max(maxDepth, depth.(int))
+			continue
+		}
+
+		schema, err := client.GetSchemaBySubjectAndVersion(ctx, ref.Subject, &ref.Version, false)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get schema for subject %q with version %d: %s", ref.Subject, ref.Version, err)
+		}
+
+		depth, err := o.referenceDepth(ctx, schema.References)
+		if err != nil {
+			return 0, err
+		}
+		depth++
+
+		o.referenceDepthCache.Store(key, depth)
+		maxDepth = max(maxDepth, depth)
+	}
+	return maxDepth, nil
+}
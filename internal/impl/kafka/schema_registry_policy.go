@@ -0,0 +1,204 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	franz_sr "github.com/twmb/franz-go/pkg/sr"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	srpFieldMaxSchemaBytes    = "max_schema_bytes"
+	srpFieldMaxFields         = "max_fields"
+	srpFieldMaxReferenceDepth = "max_reference_depth"
+	srpFieldAction            = "action"
+
+	srpActionReject = "reject"
+	srpActionFlag   = "flag"
+
+	srpPolicyMaxSchemaBytes    = "max_schema_bytes"
+	srpPolicyMaxFields         = "max_fields"
+	srpPolicyMaxReferenceDepth = "max_reference_depth"
+)
+
+func schemaRegistryPolicyFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewIntField(srpFieldMaxSchemaBytes).
+			Description("The maximum size in bytes of a schema definition. Set to `0` to disable this check.").
+			Default(0),
+		service.NewIntField(srpFieldMaxFields).
+			Description("The maximum number of fields declared by a schema, including fields of nested records, messages and objects. Set to `0` to disable this check.").
+			Default(0),
+		service.NewIntField(srpFieldMaxReferenceDepth).
+			Description("The maximum depth of the schema reference graph, where a schema without references has a depth of `0`. Set to `0` to disable this check.").
+			Default(0),
+		service.NewStringAnnotatedEnumField(srpFieldAction, map[string]string{
+			srpActionReject: "Fail the write of a violating schema with an error describing each violation.",
+			srpActionFlag:   "Log a warning and increment the `schema_registry_policy_violations` metric for a violating schema, but register it anyway.",
+		}).
+			Description("The action to take when a schema violates a policy.").
+			Default(srpActionReject),
+	}
+}
+
+// schemaPolicy describes limits that schemas must satisfy before they are
+// registered.
+type schemaPolicy struct {
+	maxSchemaBytes    int
+	maxFields         int
+	maxReferenceDepth int
+	action            string
+}
+
+func schemaPolicyFromParsed(pConf *service.ParsedConfig) (p schemaPolicy, err error) {
+	if p.maxSchemaBytes, err = pConf.FieldInt(srpFieldMaxSchemaBytes); err != nil {
+		return
+	}
+	if p.maxFields, err = pConf.FieldInt(srpFieldMaxFields); err != nil {
+		return
+	}
+	if p.maxReferenceDepth, err = pConf.FieldInt(srpFieldMaxReferenceDepth); err != nil {
+		return
+	}
+	p.action, err = pConf.FieldString(srpFieldAction)
+	return
+}
+
+func (p schemaPolicy) enabled() bool {
+	return p.maxSchemaBytes > 0 || p.maxFields > 0 || p.maxReferenceDepth > 0
+}
+
+// SchemaPolicyViolation describes a single limit exceeded by a schema.
+type SchemaPolicyViolation struct {
+	Policy string `json:"policy"`
+	Limit  int    `json:"limit"`
+	Actual int    `json:"actual"`
+}
+
+// SchemaPolicyError is returned when a schema violates one or more of the
+// configured policies.
+type SchemaPolicyError struct {
+	Subject    string                  `json:"subject"`
+	Version    int                     `json:"version"`
+	Violations []SchemaPolicyViolation `json:"violations"`
+}
+
+func (e *SchemaPolicyError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		violations = append(violations, fmt.Sprintf("%s: %d exceeds limit %d", v.Policy, v.Actual, v.Limit))
+	}
+	return fmt.Sprintf("schema for subject %q version %d violates policy (%s)", e.Subject, e.Version, strings.Join(violations, ", "))
+}
+
+// check returns a *SchemaPolicyError if the schema violates the policy, the
+// reference depth of the schema must be calculated by the caller.
+func (p schemaPolicy) check(ss franz_sr.SubjectSchema, referenceDepth int) error {
+	var violations []SchemaPolicyViolation
+	if p.maxSchemaBytes > 0 {
+		if size := len(ss.Schema.Schema); size > p.maxSchemaBytes {
+			violations = append(violations, SchemaPolicyViolation{Policy: srpPolicyMaxSchemaBytes, Limit: p.maxSchemaBytes, Actual: size})
+		}
+	}
+	if p.maxFields > 0 {
+		if fields := countSchemaFields(ss.Schema); fields > p.maxFields {
+			violations = append(violations, SchemaPolicyViolation{Policy: srpPolicyMaxFields, Limit: p.maxFields, Actual: fields})
+		}
+	}
+	if p.maxReferenceDepth > 0 && referenceDepth > p.maxReferenceDepth {
+		violations = append(violations, SchemaPolicyViolation{Policy: srpPolicyMaxReferenceDepth, Limit: p.maxReferenceDepth, Actual: referenceDepth})
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &SchemaPolicyError{
+		Subject:    ss.Subject,
+		Version:    ss.Version,
+		Violations: violations,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var protobufFieldRegexp = regexp.MustCompile(`(?m)^\s*(?:(?:optional|required|repeated)\s+)?(?:map\s*<[^>]+>|[A-Za-z_][\w.]*)\s+[A-Za-z_]\w*\s*=\s*\d+`)
+
+// countSchemaFields returns a best effort count of the fields declared by a
+// schema. Schemas which cannot be parsed are considered to have no fields.
+func countSchemaFields(schema franz_sr.Schema) int {
+	switch schema.Type {
+	case franz_sr.TypeProtobuf:
+		return len(protobufFieldRegexp.FindAllStringIndex(schema.Schema, -1))
+	case franz_sr.TypeJSON:
+		var v any
+		if err := json.Unmarshal([]byte(schema.Schema), &v); err != nil {
+			return 0
+		}
+		return countJSONSchemaFields(v)
+	default:
+		var v any
+		if err := json.Unmarshal([]byte(schema.Schema), &v); err != nil {
+			return 0
+		}
+		return countAvroFields(v)
+	}
+}
+
+func countAvroFields(v any) (count int) {
+	switch t := v.(type) {
+	case map[string]any:
+		if fields, ok := t["fields"].([]any); ok {
+			count += len(fields)
+			for _, f := range fields {
+				if fm, ok := f.(map[string]any); ok {
+					count += countAvroFields(fm["type"])
+				}
+			}
+		}
+		count += countAvroFields(t["items"])
+		count += countAvroFields(t["values"])
+	case []any:
+		for _, e := range t {
+			count += countAvroFields(e)
+		}
+	}
+	return
+}
+
+func countJSONSchemaFields(v any) (count int) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			if props, ok := e.(map[string]any); ok && k == "properties" {
+				count += len(props)
+				for _, p := range props {
+					count += countJSONSchemaFields(p)
+				}
+				continue
+			}
+			count += countJSONSchemaFields(e)
+		}
+	case []any:
+		for _, e := range t {
+			count += countJSONSchemaFields(e)
+		}
+	}
+	return
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/sr"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestCountSchemaFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   sr.Schema
+		expected int
+	}{
+		{
+			name:     "avro primitive",
+			schema:   sr.Schema{Schema: `"string"`},
+			expected: 0,
+		},
+		{
+			name: "avro nested records",
+			schema: sr.Schema{Schema: `{"type":"record","name":"a","fields":[
+				{"name":"x","type":"int"},
+				{"name":"y","type":["null",{"type":"record","name":"b","fields":[{"name":"z","type":"string"}]}]},
+				{"name":"list","type":{"type":"array","items":{"type":"record","name":"c","fields":[{"name":"w","type":"long"}]}}}
+			]}`},
+			expected: 5,
+		},
+		{
+			name: "json schema",
+			schema: sr.Schema{Type: sr.TypeJSON, Schema: `{"type":"object","properties":{
+				"a":{"type":"string"},
+				"b":{"type":"object","properties":{"properties":{"type":"string"}}},
+				"c":{"type":"array","items":{"type":"object","properties":{"d":{"type":"integer"}}}}
+			}}`},
+			expected: 5,
+		},
+		{
+			name: "protobuf",
+			schema: sr.Schema{Type: sr.TypeProtobuf, Schema: `syntax = "proto3";
+option java_package = "foo";
+message A {
+  string a = 1;
+  repeated int32 b = 2;
+  map<string, int64> c = 3;
+  message B {
+    optional foo.Bar d = 4;
+  }
+}
+enum E {
+  E_UNKNOWN = 0;
+}`},
+			expected: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, countSchemaFields(test.schema))
+		})
+	}
+}
+
+func TestSchemaPolicyCheck(t *testing.T) {
+	ss := sr.SubjectSchema{
+		Subject: "foo",
+		Version: 2,
+		Schema:  sr.Schema{Schema: `{"type":"record","name":"a","fields":[{"name":"x","type":"int"},{"name":"y","type":"int"}]}`},
+	}
+
+	policy := schemaPolicy{maxSchemaBytes: 1000, maxFields: 2, maxReferenceDepth: 3}
+	require.NoError(t, policy.check(ss, 3))
+
+	policy = schemaPolicy{maxSchemaBytes: 10, maxFields: 1, maxReferenceDepth: 2}
+	err := policy.check(ss, 3)
+
+	var policyErr *SchemaPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, "foo", policyErr.Subject)
+	assert.Equal(t, 2, policyErr.Version)
+	assert.Equal(t, []SchemaPolicyViolation{
+		{Policy: srpPolicyMaxSchemaBytes, Limit: 10, Actual: len(ss.Schema.Schema)},
+		{Policy: srpPolicyMaxFields, Limit: 1, Actual: 2},
+		{Policy: srpPolicyMaxReferenceDepth, Limit: 2, Actual: 3},
+	}, policyErr.Violations)
+}
+
+func TestSchemaRegistryOutputPolicy(t *testing.T) {
+	schemas := map[string]sr.SubjectSchema{
+		"/subjects/a/versions/1": {Subject: "a", Version: 1, ID: 1, Schema: sr.Schema{Schema: `"string"`}},
+		"/subjects/b/versions/1": {Subject: "b", Version: 1, ID: 2, Schema: sr.Schema{
+			Schema:     `{"type":"record","name":"b","fields":[{"name":"a","type":"a"}]}`,
+			References: []sr.SchemaReference{{Name: "a", Subject: "a", Version: 1}},
+		}},
+	}
+
+	var created int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var output any
+		switch path := r.URL.EscapedPath(); path {
+		case "/mode":
+			output = map[string]string{"mode": "READWRITE"}
+		case "/subjects/c/versions":
+			created++
+			output = map[string]int{"id": 3}
+		case "/schemas/ids/3/versions":
+			output = []map[string]any{{"subject": "c", "version": 1}}
+		case "/subjects/c/versions/1":
+			output = sr.SubjectSchema{Subject: "c", Version: 1, ID: 3}
+		default:
+			ss, ok := schemas[path]
+			if !ok {
+				http.Error(w, "path not found: "+path, http.StatusNotFound)
+				return
+			}
+			output = ss
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(output))
+	}))
+	t.Cleanup(ts.Close)
+
+	msg := service.NewMessage([]byte(`{"schema":"{\"type\":\"record\",\"name\":\"c\",\"fields\":[{\"name\":\"b\",\"type\":\"b\"}]}","references":[{"name":"b","subject":"b","version":1}]}`))
+
+	for _, action := range []string{srpActionReject, srpActionFlag} {
+		t.Run(action, func(t *testing.T) {
+			conf, err := schemaRegistryOutputSpec().ParseYAML(fmt.Sprintf(`
+url: %s
+subject: c
+backfill_dependencies: false
+translate_ids: true
+policy:
+  max_reference_depth: 1
+  action: %s
+`, ts.URL, action), nil)
+			require.NoError(t, err)
+
+			out, err := outputFromParsed(conf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, out.Connect(t.Context()))
+
+			created = 0
+			err = out.Write(t.Context(), msg)
+			if action == srpActionFlag {
+				require.NoError(t, err)
+				assert.Equal(t, 1, created)
+				return
+			}
+
+			var policyErr *SchemaPolicyError
+			require.ErrorAs(t, err, &policyErr)
+			assert.Equal(t, []SchemaPolicyViolation{
+				{Policy: srpPolicyMaxReferenceDepth, Limit: 1, Actual: 2},
+			}, policyErr.Violations)
+			assert.Equal(t, 0, created)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kcFieldKubeconfig = "kubeconfig"
+	kcFieldContext    = "context"
+)
+
+func clientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(kcFieldKubeconfig).
+			Description("An optional path of a kubeconfig file. When empty the service account of the pod is used when running within a cluster, and otherwise the default kubeconfig file.").
+			Default("").
+			Advanced(),
+		service.NewStringField(kcFieldContext).
+			Description("An optional kubeconfig context to use instead of the current context.").
+			Default("").
+			Advanced(),
+	}
+}
+
+// clientConfig resolves the config used to connect to a cluster.
+type clientConfig struct {
+	kubeconfig  string
+	kubeContext string
+}
+
+func clientConfigFromParsed(conf *service.ParsedConfig) (*clientConfig, error) {
+	c := &clientConfig{}
+	var err error
+	if c.kubeconfig, err = conf.FieldString(kcFieldKubeconfig); err != nil {
+		return nil, err
+	}
+	if c.kubeContext, err = conf.FieldString(kcFieldContext); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// rest returns the in cluster config when running within a cluster and no
+// kubeconfig is set, and otherwise the config of the kubeconfig context.
+func (c *clientConfig) rest() (*rest.Config, error) {
+	if c.kubeconfig == "" && c.kubeContext == "" {
+		if cfg, err := rest.InClusterConfig(); err == nil {
+			return cfg, nil
+		}
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules, &clientcmd.ConfigOverrides{CurrentContext: c.kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+	return cfg, nil
+}
+
+func (c *clientConfig) dynamic() (dynamic.Interface, error) {
+	cfg, err := c.rest()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(cfg)
+}
+
+// dynamicWithMapper returns a dynamic client along with a mapper that
+// resolves the resources of kinds from the discovery API of the cluster.
+func (c *clientConfig) dynamicWithMapper() (dynamic.Interface, meta.ResettableRESTMapper, error) {
+	cfg, err := c.rest()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	disco, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco)), nil
+}
@@ -0,0 +1,529 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/resume"
+)
+
+const (
+	kiFieldAPIVersion      = "api_version"
+	kiFieldResource        = "resource"
+	kiFieldNamespaces      = "namespaces"
+	kiFieldLabelSelector   = "label_selector"
+	kiFieldFieldSelector   = "field_selector"
+	kiFieldIncludeExisting = "include_existing"
+	kiFieldResourceVersion = "resource_version"
+	kiFieldCache           = "cache"
+	kiFieldCacheKey        = "cache_key"
+)
+
+func inputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Watches Kubernetes resources or cluster events and consumes each change as a message.").
+		Description(`
+Watches a kind of resource with the https://kubernetes.io/docs/reference/using-api/api-concepts/#efficient-detection-of-changes[watch API^] of a Kubernetes cluster, consuming each object that is added, modified or deleted as a message. By default cluster events are watched, and any other kind of resource, including custom resources, can be watched by setting the `+"`api_version` and `resource`"+` fields.
+
+When running within a cluster the service account of the pod is used to authenticate, otherwise the default kubeconfig file or the one set with `+"`kubeconfig`"+` is used. The service account or user must be allowed to `+"`list` and `watch`"+` the resource.
+
+== Resuming
+
+Watches start from the `+"`resource_version`"+`, or from the latest resource version when it is empty. When a `+"`cache`"+` is configured the resource version of the latest acknowledged change of each namespace is stored within it, and watches resume from the stored resource version when restarted. Kubernetes only retains changes for a limited period, and when a watch can't resume from an expired resource version it starts again from the latest version, which can cause changes to be missed.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- kubernetes_event_type
+- kubernetes_api_version
+- kubernetes_kind
+- kubernetes_namespace
+- kubernetes_name
+- kubernetes_uid
+- kubernetes_resource_version
+`+"```"+`
+
+The event type is one of `+"`ADDED`, `MODIFIED` or `DELETED`"+`.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewStringField(kiFieldAPIVersion).
+				Description("The API group and version of the resource to watch.").
+				Examples("v1", "apps/v1", "events.k8s.io/v1").
+				Default("v1"),
+			service.NewStringField(kiFieldResource).
+				Description("The plural name of the resource to watch.").
+				Examples("events", "pods", "deployments").
+				Default("events"),
+			service.NewStringListField(kiFieldNamespaces).
+				Description("The namespaces to watch. When empty resources of all namespaces, or cluster scoped resources, are watched.").
+				Example([]string{"default", "kube-system"}).
+				Default([]string{}),
+			service.NewStringField(kiFieldLabelSelector).
+				Description("An optional https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#label-selectors[label selector^] of the resources to watch.").
+				Example("app=checkout,tier!=frontend").
+				Default(""),
+			service.NewStringField(kiFieldFieldSelector).
+				Description("An optional https://kubernetes.io/docs/concepts/overview/working-with-objects/field-selectors/[field selector^] of the resources to watch.").
+				Example("type=Warning").
+				Example("status.phase=Running").
+				Default(""),
+			service.NewBoolField(kiFieldIncludeExisting).
+				Description("Whether to consume the existing resources as `ADDED` changes before watching, when there's no resource version to resume from.").
+				Default(false),
+			service.NewStringField(kiFieldResourceVersion).
+				Description("An optional resource version to start watching from when there's no resource version stored in the cache.").
+				Default("").
+				Advanced(),
+			service.NewStringField(kiFieldCache).
+				Description("An optional xref:components:caches/about.adoc[cache] to store the resource version of the latest acknowledged change of each namespace within.").
+				Optional(),
+			service.NewStringField(kiFieldCacheKey).
+				Description("The prefix of the keys that resource versions are stored under, which is followed by the namespace.").
+				Default("kubernetes_resource_version").
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Warning events", "Consume warning events of all namespaces into Kafka, resuming from the latest delivered event when restarted.", `
+input:
+  kubernetes:
+    field_selector: type=Warning
+    cache: resource_versions
+
+cache_resources:
+  - label: resource_versions
+    redis:
+      url: redis://localhost:6379
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: kubernetes_events
+    key: ${! @kubernetes_namespace }/${! @kubernetes_name }
+`).
+		Example("Deployment changes", "Consume the changes of deployments within the production namespace, starting with the existing deployments.", `
+input:
+  kubernetes:
+    api_version: apps/v1
+    resource: deployments
+    namespaces: [ production ]
+    label_selector: team=payments
+    include_existing: true
+`)
+}
+
+func init() {
+	service.MustRegisterInput("kubernetes", inputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newInputFromParsed(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type watchMessage struct {
+	msg   *service.Message
+	ackFn service.AckFunc
+}
+
+type input struct {
+	gvr             schema.GroupVersionResource
+	namespaces      []string
+	labelSelector   string
+	fieldSelector   string
+	includeExisting bool
+	resourceVersion string
+	cache           string
+	cacheKey        string
+	replayNacks     bool
+
+	client  *clientConfig
+	mgr     *service.Resources
+	log     *service.Logger
+	newFn   func() (dynamic.Interface, error)
+	msgChan chan watchMessage
+	errChan chan error
+
+	mut     sync.Mutex
+	shutSig *shutdown.Signaller
+}
+
+func newInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*input, error) {
+	i := &input{
+		mgr: mgr,
+		log: mgr.Logger(),
+	}
+
+	var err error
+	if i.client, err = clientConfigFromParsed(conf); err != nil {
+		return nil, err
+	}
+
+	apiVersion, err := conf.FieldString(kiFieldAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", kiFieldAPIVersion, err)
+	}
+	resource, err := conf.FieldString(kiFieldResource)
+	if err != nil {
+		return nil, err
+	}
+	i.gvr = gv.WithResource(strings.ToLower(resource))
+
+	if i.namespaces, err = conf.FieldStringList(kiFieldNamespaces); err != nil {
+		return nil, err
+	}
+	if len(i.namespaces) == 0 {
+		i.namespaces = []string{metav1.NamespaceAll}
+	}
+	if i.labelSelector, err = conf.FieldString(kiFieldLabelSelector); err != nil {
+		return nil, err
+	}
+	if i.fieldSelector, err = conf.FieldString(kiFieldFieldSelector); err != nil {
+		return nil, err
+	}
+	if i.includeExisting, err = conf.FieldBool(kiFieldIncludeExisting); err != nil {
+		return nil, err
+	}
+	if i.resourceVersion, err = conf.FieldString(kiFieldResourceVersion); err != nil {
+		return nil, err
+	}
+	if conf.Contains(kiFieldCache) {
+		if i.cache, err = conf.FieldString(kiFieldCache); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(i.cache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", i.cache)
+		}
+	}
+	if i.cacheKey, err = conf.FieldString(kiFieldCacheKey); err != nil {
+		return nil, err
+	}
+	if i.replayNacks, err = conf.FieldBool(service.AutoRetryNacksToggleFieldName); err != nil {
+		return nil, err
+	}
+
+	i.newFn = i.client.dynamic
+	return i, nil
+}
+
+func (i *input) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.shutSig != nil {
+		return nil
+	}
+
+	client, err := i.newFn()
+	if err != nil {
+		return err
+	}
+
+	// Check that the resource can be listed before watching it, so that
+	// misconfigurations are reported as connection errors.
+	if _, err := i.resourceClient(client, i.namespaces[0]).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return fmt.Errorf("failed to list %v: %w", i.gvr.String(), err)
+	}
+
+	shutSig := shutdown.NewSignaller()
+	msgChan := make(chan watchMessage)
+	errChan := make(chan error, len(i.namespaces))
+
+	var wg sync.WaitGroup
+	for _, ns := range i.namespaces {
+		wg.Add(1)
+		go func(ns string) {
+			defer wg.Done()
+			if err := i.watchNamespace(shutSig, client, ns, msgChan); err != nil {
+				errChan <- err
+			}
+		}(ns)
+	}
+	go func() {
+		wg.Wait()
+		shutSig.TriggerHasStopped()
+	}()
+
+	i.shutSig = shutSig
+	i.msgChan = msgChan
+	i.errChan = errChan
+	return nil
+}
+
+func (i *input) resourceClient(client dynamic.Interface, ns string) dynamic.ResourceInterface {
+	if ns == metav1.NamespaceAll {
+		return client.Resource(i.gvr)
+	}
+	return client.Resource(i.gvr).Namespace(ns)
+}
+
+func (i *input) cacheKeyFor(ns string) string {
+	if ns == metav1.NamespaceAll {
+		return i.cacheKey + "_all"
+	}
+	return i.cacheKey + "_" + ns
+}
+
+// storeFor returns the store of the resource version of a namespace, which
+// is nil when no cache is configured.
+func (i *input) storeFor(ns string) *resume.Store {
+	if i.cache == "" {
+		return nil
+	}
+	return resume.NewStore(i.mgr, i.cache, i.cacheKeyFor(ns))
+}
+
+func (i *input) readResourceVersion(ctx context.Context, ns string) (string, error) {
+	rv, err := i.storeFor(ns).Load(ctx)
+	if err != nil {
+		return "", err
+	}
+	if rv == nil {
+		return i.resourceVersion, nil
+	}
+	return string(rv), nil
+}
+
+// watchNamespace consumes the changes of a namespace until the input is
+// closed, watching again from the latest resource version whenever a watch
+// ends.
+func (i *input) watchNamespace(shutSig *shutdown.Signaller, client dynamic.Interface, ns string, msgChan chan<- watchMessage) error {
+	ctx, done := shutSig.SoftStopCtx(context.Background())
+	defer done()
+
+	rv, err := i.readResourceVersion(ctx, ns)
+	if err != nil {
+		return fmt.Errorf("failed to read resource version from cache: %w", err)
+	}
+
+	// Resource versions are acknowledged in order so that the stored version
+	// never passes changes that haven't been delivered, unless they're rejected
+	// without being replayed.
+	tracker := resume.NewTracker(i.storeFor(ns), 1024, func(rv string) ([]byte, error) {
+		return []byte(rv), nil
+	}, resume.TrackerOptReleaseNacks(!i.replayNacks))
+	send := func(eventType string, obj *unstructured.Unstructured) error {
+		msg, err := newMessage(eventType, obj)
+		if err != nil {
+			return err
+		}
+		ackFn, err := tracker.Track(ctx, obj.GetResourceVersion())
+		if err != nil {
+			return err
+		}
+		select {
+		case msgChan <- watchMessage{msg: msg, ackFn: ackFn}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	if rv == "" && i.includeExisting {
+		if rv, err = i.listExisting(ctx, client, ns, send); err != nil {
+			return err
+		}
+	}
+
+	for {
+		w, err := i.resourceClient(client, ns).Watch(ctx, metav1.ListOptions{
+			LabelSelector:       i.labelSelector,
+			FieldSelector:       i.fieldSelector,
+			ResourceVersion:     rv,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				i.log.Warnf("Resource version %v of namespace '%v' has expired, watching from the latest version", rv, ns)
+				rv = ""
+				continue
+			}
+			i.log.Errorf("Failed to watch %v: %v", i.gvr.String(), err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return nil
+			}
+			continue
+		}
+
+		if rv, err = i.consumeWatch(ctx, w, rv, send); err != nil {
+			w.Stop()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		w.Stop()
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (i *input) listExisting(ctx context.Context, client dynamic.Interface, ns string, send func(string, *unstructured.Unstructured) error) (string, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: i.labelSelector,
+		FieldSelector: i.fieldSelector,
+		Limit:         500,
+	}
+	for {
+		list, err := i.resourceClient(client, ns).List(ctx, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to list %v: %w", i.gvr.String(), err)
+		}
+		for idx := range list.Items {
+			if err := send(string(watch.Added), &list.Items[idx]); err != nil {
+				return "", err
+			}
+		}
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			return list.GetResourceVersion(), nil
+		}
+	}
+}
+
+// consumeWatch sends the changes of a watch until it ends, returning the
+// resource version to watch from next.
+func (i *input) consumeWatch(ctx context.Context, w watch.Interface, rv string, send func(string, *unstructured.Unstructured) error) (string, error) {
+	for {
+		var event watch.Event
+		var open bool
+		select {
+		case event, open = <-w.ResultChan():
+		case <-ctx.Done():
+			return rv, ctx.Err()
+		}
+		if !open {
+			return rv, nil
+		}
+
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			if err := send(string(event.Type), obj); err != nil {
+				return rv, err
+			}
+			rv = obj.GetResourceVersion()
+		case watch.Bookmark:
+			if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+				rv = obj.GetResourceVersion()
+			}
+		case watch.Error:
+			err := apierrors.FromObject(event.Object)
+			var status apierrors.APIStatus
+			if errors.As(err, &status) && status.Status().Code == http.StatusGone {
+				i.log.Warnf("Resource version %v has expired, watching from the latest version", rv)
+				return "", nil
+			}
+			i.log.Errorf("Watch of %v failed: %v", i.gvr.String(), err)
+			return rv, nil
+		}
+	}
+}
+
+func newMessage(eventType string, obj *unstructured.Unstructured) (*service.Message, error) {
+	b, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	msg := service.NewMessage(b)
+	msg.MetaSetMut("kubernetes_event_type", eventType)
+	msg.MetaSetMut("kubernetes_api_version", obj.GetAPIVersion())
+	msg.MetaSetMut("kubernetes_kind", obj.GetKind())
+	msg.MetaSetMut("kubernetes_namespace", obj.GetNamespace())
+	msg.MetaSetMut("kubernetes_name", obj.GetName())
+	msg.MetaSetMut("kubernetes_uid", string(obj.GetUID()))
+	msg.MetaSetMut("kubernetes_resource_version", obj.GetResourceVersion())
+	return msg, nil
+}
+
+func (i *input) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	i.mut.Lock()
+	shutSig, msgChan, errChan := i.shutSig, i.msgChan, i.errChan
+	i.mut.Unlock()
+	if shutSig == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case m := <-msgChan:
+		return m.msg, m.ackFn, nil
+	case err := <-errChan:
+		i.mut.Lock()
+		i.shutSig = nil
+		i.mut.Unlock()
+		shutSig.TriggerHardStop()
+		return nil, nil, fmt.Errorf("%w: %v", service.ErrNotConnected, err)
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (i *input) Close(ctx context.Context) error {
+	i.mut.Lock()
+	shutSig := i.shutSig
+	i.shutSig = nil
+	i.mut.Unlock()
+	if shutSig == nil {
+		return nil
+	}
+
+	shutSig.TriggerHardStop()
+	select {
+	case <-shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testPod(ns, name, rv string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace(ns)
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetResourceVersion(rv)
+	return obj
+}
+
+func testInput(t *testing.T, conf string, mgr *service.Resources, objs ...runtime.Object) *input {
+	t.Helper()
+
+	pConf, err := inputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	i, err := newInputFromParsed(pConf, mgr)
+	require.NoError(t, err)
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	i.newFn = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	return i
+}
+
+func TestInputConfig(t *testing.T) {
+	i := testInput(t, `
+api_version: apps/v1
+resource: Deployments
+namespaces: [ foo, bar ]
+`, service.MockResources())
+	assert.Equal(t, "apps", i.gvr.Group)
+	assert.Equal(t, "v1", i.gvr.Version)
+	assert.Equal(t, "deployments", i.gvr.Resource)
+	assert.Equal(t, []string{"foo", "bar"}, i.namespaces)
+
+	i = testInput(t, ``, service.MockResources())
+	assert.Equal(t, "events", i.gvr.Resource)
+	assert.Equal(t, []string{metav1.NamespaceAll}, i.namespaces)
+
+	pConf, err := inputSpec().ParseYAML(`cache: nope`, nil)
+	require.NoError(t, err)
+	_, err = newInputFromParsed(pConf, service.MockResources())
+	require.Error(t, err)
+}
+
+func TestInputIncludeExisting(t *testing.T) {
+	i := testInput(t, `
+resource: pods
+namespaces: [ foo ]
+include_existing: true
+`, service.MockResources(),
+		testPod("foo", "a", "10"),
+		testPod("bar", "b", "11"),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*10)
+	defer cancel()
+
+	require.NoError(t, i.Connect(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, i.Close(context.Background()))
+	})
+
+	msg, ackFn, err := i.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, nil))
+
+	structured, err := msg.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, "a", structured.(map[string]any)["metadata"].(map[string]any)["name"])
+
+	for k, v := range map[string]string{
+		"kubernetes_event_type":       "ADDED",
+		"kubernetes_api_version":      "v1",
+		"kubernetes_kind":             "Pod",
+		"kubernetes_namespace":        "foo",
+		"kubernetes_name":             "a",
+		"kubernetes_uid":              "uid-a",
+		"kubernetes_resource_version": "10",
+	} {
+		actual, _ := msg.MetaGet(k)
+		assert.Equal(t, v, actual, k)
+	}
+}
+
+func TestInputConsumeWatch(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("foocache"))
+	i := testInput(t, `
+cache: foocache
+cache_key: rv
+`, mgr)
+
+	w := watch.NewFakeWithChanSize(10, false)
+	w.Add(testPod("foo", "a", "1"))
+	w.Modify(testPod("foo", "a", "2"))
+	w.Action(watch.Bookmark, testPod("", "", "5"))
+	w.Delete(testPod("foo", "a", "6"))
+	w.Stop()
+
+	var sent []*service.Message
+	rv, err := i.consumeWatch(t.Context(), w, "0", func(eventType string, obj *unstructured.Unstructured) error {
+		msg, err := newMessage(eventType, obj)
+		if err != nil {
+			return err
+		}
+		sent = append(sent, msg)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "6", rv)
+
+	require.Len(t, sent, 3)
+	for idx, exp := range []string{"ADDED", "MODIFIED", "DELETED"} {
+		actual, _ := sent[idx].MetaGet("kubernetes_event_type")
+		assert.Equal(t, exp, actual)
+	}
+
+	// An expired resource version restarts the watch from the latest version.
+	w = watch.NewFakeWithChanSize(10, false)
+	w.Error(&metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusGone,
+		Reason: metav1.StatusReasonExpired,
+	})
+	rv, err = i.consumeWatch(t.Context(), w, "6", func(string, *unstructured.Unstructured) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, rv)
+
+	// Resource versions are stored per namespace and read back on resume.
+	require.NoError(t, i.storeFor("foo").Save(t.Context(), []byte("6")))
+	rv, err = i.readResourceVersion(t.Context(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "6", rv)
+
+	rv, err = i.readResourceVersion(t.Context(), "bar")
+	require.NoError(t, err)
+	assert.Empty(t, rv)
+}
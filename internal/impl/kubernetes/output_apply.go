@@ -0,0 +1,235 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kaoFieldFieldManager   = "field_manager"
+	kaoFieldForceConflicts = "force_conflicts"
+	kaoFieldDryRun         = "dry_run"
+	kaoFieldNamespace      = "namespace"
+	kaoFieldMaxInFlight    = "max_in_flight"
+)
+
+func applyOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Applies messages as Kubernetes manifests with server-side apply.").
+		Description(`
+Each message must be a structured Kubernetes object, such as a manifest parsed from YAML with the `+"xref:guides:bloblang/methods.adoc#parse_yaml[`parse_yaml`]"+` method, or an array of objects. Objects are applied with https://kubernetes.io/docs/reference/using-api/server-side-apply/[server-side apply^], which creates them when they don't exist and otherwise updates the fields they set, claiming ownership of those fields for the `+"`field_manager`"+`.
+
+The resource of each object is resolved from its `+"`apiVersion` and `kind`"+` with the discovery API of the cluster, and so custom resources can be applied as well as the built-in ones. Namespaced objects without a namespace are applied to the `+"`namespace`"+` field.
+
+When running within a cluster the service account of the pod is used to authenticate, otherwise the default kubeconfig file or the one set with `+"`kubeconfig`"+` is used. The service account or user must be allowed to `+"`get`, `create` and `patch`"+` the resources that are applied.`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewStringField(kaoFieldFieldManager).
+				Description("The name of the manager that owns the fields set by applied objects.").
+				Default("redpanda-connect"),
+			service.NewBoolField(kaoFieldForceConflicts).
+				Description("Whether to take ownership of fields that are owned by other managers. When disabled applying an object that changes the value of a field owned by another manager fails with a conflict.").
+				Default(false),
+			service.NewBoolField(kaoFieldDryRun).
+				Description("Whether to validate objects with the API server without persisting them.").
+				Default(false),
+			service.NewInterpolatedStringField(kaoFieldNamespace).
+				Description("The namespace to apply namespaced objects to when they don't set one.").
+				Default("default"),
+			service.NewOutputMaxInFlightField(),
+		).
+		Example("GitOps from a topic", "Apply the YAML manifests consumed from a topic, keyed by the name of the object.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ manifests ]
+    consumer_group: kubernetes_apply
+
+pipeline:
+  processors:
+    - mapping: root = content().string().parse_yaml()
+
+output:
+  kubernetes_apply:
+    field_manager: gitops
+    force_conflicts: true
+`)
+}
+
+func init() {
+	service.MustRegisterOutput("kubernetes_apply", applyOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.Output, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out, err = newApplyOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type applyOutput struct {
+	fieldManager   string
+	forceConflicts bool
+	dryRun         bool
+	namespace      *service.InterpolatedString
+
+	log   *service.Logger
+	newFn func() (dynamic.Interface, meta.ResettableRESTMapper, error)
+
+	mut    sync.RWMutex
+	client dynamic.Interface
+	mapper meta.ResettableRESTMapper
+}
+
+func newApplyOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*applyOutput, error) {
+	o := &applyOutput{log: mgr.Logger()}
+
+	client, err := clientConfigFromParsed(conf)
+	if err != nil {
+		return nil, err
+	}
+	o.newFn = client.dynamicWithMapper
+
+	if o.fieldManager, err = conf.FieldString(kaoFieldFieldManager); err != nil {
+		return nil, err
+	}
+	if o.forceConflicts, err = conf.FieldBool(kaoFieldForceConflicts); err != nil {
+		return nil, err
+	}
+	if o.dryRun, err = conf.FieldBool(kaoFieldDryRun); err != nil {
+		return nil, err
+	}
+	if o.namespace, err = conf.FieldInterpolatedString(kaoFieldNamespace); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *applyOutput) Connect(context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.client != nil {
+		return nil
+	}
+
+	client, mapper, err := o.newFn()
+	if err != nil {
+		return err
+	}
+	o.client, o.mapper = client, mapper
+	return nil
+}
+
+func (o *applyOutput) Write(ctx context.Context, msg *service.Message) error {
+	o.mut.RLock()
+	client, mapper := o.client, o.mapper
+	o.mut.RUnlock()
+	if client == nil {
+		return service.ErrNotConnected
+	}
+
+	structured, err := msg.AsStructured()
+	if err != nil {
+		return fmt.Errorf("failed to parse message as an object: %w", err)
+	}
+
+	var objs []any
+	if arr, ok := structured.([]any); ok {
+		objs = arr
+	} else {
+		objs = []any{structured}
+	}
+
+	namespace, err := o.namespace.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate %v: %w", kaoFieldNamespace, err)
+	}
+
+	for idx, v := range objs {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected object %v to be an object, got %T", idx, v)
+		}
+		if err := o.apply(ctx, client, mapper, &unstructured.Unstructured{Object: m}, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *applyOutput) apply(ctx context.Context, client dynamic.Interface, mapper meta.ResettableRESTMapper, obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return errors.New("object must set apiVersion and kind")
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("%v object must set metadata.name", gvk.Kind)
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// The kind may be a custom resource that was defined after the
+		// discovery API was last read.
+		mapper.Reset()
+		mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve resource of %v: %w", gvk.String(), err)
+	}
+
+	var resource dynamic.ResourceInterface = client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		resource = client.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	}
+
+	opts := metav1.ApplyOptions{
+		FieldManager: o.fieldManager,
+		Force:        o.forceConflicts,
+	}
+	if o.dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err := resource.Apply(ctx, obj.GetName(), obj, opts); err != nil {
+		return fmt.Errorf("failed to apply %v %v: %w", gvk.Kind, obj.GetName(), err)
+	}
+	return nil
+}
+
+func (o *applyOutput) Close(context.Context) error {
+	o.mut.Lock()
+	o.client, o.mapper = nil, nil
+	o.mut.Unlock()
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type staticMapper struct {
+	*meta.DefaultRESTMapper
+	resets int
+}
+
+func (m *staticMapper) Reset() {
+	m.resets++
+}
+
+type appliedObject struct {
+	resource  string
+	namespace string
+	name      string
+	obj       map[string]any
+}
+
+func testApplyOutput(t *testing.T, conf string) (*applyOutput, *staticMapper, func() []appliedObject) {
+	t.Helper()
+
+	pConf, err := applyOutputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newApplyOutputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	mapper := &staticMapper{DefaultRESTMapper: meta.NewDefaultRESTMapper(nil)}
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	var mut sync.Mutex
+	var applied []appliedObject
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(patch.GetPatch()))
+
+		mut.Lock()
+		applied = append(applied, appliedObject{
+			resource:  patch.GetResource().Resource,
+			namespace: patch.GetNamespace(),
+			name:      patch.GetName(),
+			obj:       obj.Object,
+		})
+		mut.Unlock()
+		return true, obj, nil
+	})
+
+	o.newFn = func() (dynamic.Interface, meta.ResettableRESTMapper, error) {
+		return client, mapper, nil
+	}
+	return o, mapper, func() []appliedObject {
+		mut.Lock()
+		defer mut.Unlock()
+		return applied
+	}
+}
+
+func TestApplyOutput(t *testing.T) {
+	o, _, applied := testApplyOutput(t, `
+namespace: ${! @namespace }
+`)
+
+	ctx := t.Context()
+	require.NoError(t, o.Connect(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, o.Close(context.Background()))
+	})
+
+	msg := service.NewMessage([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo"},"data":{"a":"b"}}`))
+	msg.MetaSetMut("namespace", "team")
+	require.NoError(t, o.Write(ctx, msg))
+
+	msg = service.NewMessage([]byte(`[
+  {"apiVersion":"v1","kind":"Namespace","metadata":{"name":"prod"}},
+  {"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"bar","namespace":"prod"}}
+]`))
+	msg.MetaSetMut("namespace", "team")
+	require.NoError(t, o.Write(ctx, msg))
+
+	got := applied()
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "configmaps", got[0].resource)
+	assert.Equal(t, "team", got[0].namespace)
+	assert.Equal(t, "foo", got[0].name)
+	assert.Equal(t, map[string]any{"a": "b"}, got[0].obj["data"])
+
+	assert.Equal(t, "namespaces", got[1].resource)
+	assert.Empty(t, got[1].namespace)
+	assert.Equal(t, "prod", got[1].name)
+
+	assert.Equal(t, "configmaps", got[2].resource)
+	assert.Equal(t, "prod", got[2].namespace)
+	assert.Equal(t, "bar", got[2].name)
+}
+
+func TestApplyOutputErrors(t *testing.T) {
+	o, mapper, applied := testApplyOutput(t, ``)
+
+	ctx := t.Context()
+	require.ErrorIs(t, o.Write(ctx, service.NewMessage([]byte(`{}`))), service.ErrNotConnected)
+	require.NoError(t, o.Connect(ctx))
+
+	for _, tc := range []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{name: "not structured", content: `nope`, errContains: "failed to parse message"},
+		{name: "not an object", content: `[ 10 ]`, errContains: "expected object 0"},
+		{name: "no kind", content: `{"apiVersion":"v1","metadata":{"name":"foo"}}`, errContains: "apiVersion and kind"},
+		{name: "no name", content: `{"apiVersion":"v1","kind":"ConfigMap"}`, errContains: "metadata.name"},
+		{name: "unknown kind", content: `{"apiVersion":"v1","kind":"Nope","metadata":{"name":"foo"}}`, errContains: "failed to resolve resource"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := o.Write(ctx, service.NewMessage([]byte(tc.content)))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+
+	assert.Equal(t, 1, mapper.resets)
+	assert.Empty(t, applied())
+}
+
+func TestApplyOutputOptions(t *testing.T) {
+	o, _, _ := testApplyOutput(t, `
+field_manager: gitops
+force_conflicts: true
+dry_run: true
+`)
+
+	var opts metav1.ApplyOptions
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("patch", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+	_ = client
+
+	assert.Equal(t, "gitops", o.fieldManager)
+	assert.True(t, o.forceConflicts)
+	assert.True(t, o.dryRun)
+	assert.Empty(t, opts.DryRun)
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency
+
+import (
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const fieldTimestamp = "timestamp"
+
+func timestampField() *service.ConfigField {
+	return service.NewBloblangField(fieldTimestamp).
+		Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that yields the event time of each message, either as a timestamp, an RFC 3339 string, or a number of seconds since the unix epoch. Messages for which the mapping fails or yields `null` aren't measured. The default uses the timestamp of records consumed from Kafka.").
+		Default(`root = @kafka_timestamp_ms / 1000`).
+		Examples(`root = this.created_at`, `root = this.created_at.ts_parse("2006-01-02 15:04:05")`)
+}
+
+// eventTimer records the time elapsed since the event time of messages.
+type eventTimer struct {
+	log       *service.Logger
+	timestamp *bloblang.Executor
+	record    func(ns int64)
+	now       func() time.Time
+}
+
+func eventTimerFromParsed(conf *service.ParsedConfig, mgr *service.Resources, metricName string) (*eventTimer, error) {
+	timestamp, err := conf.FieldBloblang(fieldTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	timer := mgr.Metrics().NewTimer(metricName)
+	return &eventTimer{
+		log:       mgr.Logger(),
+		timestamp: timestamp,
+		record: func(ns int64) {
+			timer.Timing(ns)
+		},
+		now: time.Now,
+	}, nil
+}
+
+// observeBatch records the time elapsed since the event time of each message
+// of a batch.
+func (e *eventTimer) observeBatch(batch service.MessageBatch) {
+	now := e.now()
+	for _, msg := range batch {
+		ts, ok, err := e.eventTime(msg)
+		if err != nil {
+			e.log.Tracef("Failed to resolve event time: %v", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		// Clock skew between producers and this instance can place event
+		// times in the future, which are recorded as zero.
+		e.record(max(now.Sub(ts).Nanoseconds(), 0))
+	}
+}
+
+func (e *eventTimer) eventTime(msg *service.Message) (time.Time, bool, error) {
+	res, err := msg.BloblangQuery(e.timestamp)
+	if err != nil || res == nil {
+		return time.Time{}, false, err
+	}
+	// Strings yielded by a mapping are stored as raw bytes rather than as a
+	// structured value.
+	v, err := res.AsStructured()
+	if err != nil {
+		if v, err = res.AsBytes(); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	if v == nil {
+		return time.Time{}, false, nil
+	}
+	ts, err := bloblang.ValueAsTimestamp(v)
+	return ts, err == nil, err
+}
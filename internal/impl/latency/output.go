@@ -0,0 +1,99 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const elFieldOutput = "output"
+
+func eventLatencyOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Writes messages to a child output and records the end-to-end latency of each delivered message, the time elapsed between its event time and its delivery, as a metric.").
+		Description(`
+Once a batch has been written successfully to the child `+"`"+elFieldOutput+"`"+`, which is when it's acknowledged, the time elapsed since the event time of each message of the batch is recorded by the timer metric `+"`event_latency_ns`"+`. The metric is labelled with the `+"`label`"+` and `+"`path`"+` of this output like other component metrics, and so the latency of each output can be told apart. Messages that fail to be written aren't measured until they're delivered.
+
+This differs from the `+"`output_latency_ns`"+` metric of outputs, which measures the time elapsed since a message was read by the input, in that it includes the time the event took to reach the input, such as time spent within a Kafka topic, which is what service level objectives usually track.
+
+The age of messages at other points within the pipeline, such as when they're consumed, can be measured with the xref:components:processors/message_age.adoc[`+"`message_age`"+` processor].`).
+		Fields(
+			service.NewOutputField(elFieldOutput).
+				Description("The child output to write messages to."),
+			timestampField(),
+		).
+		Example("Kafka to Kafka latency", "Measure the latency between records being produced to a source topic and delivered to a sink topic.", `
+output:
+  label: sink
+  event_latency:
+    output:
+      kafka_franz:
+        seed_brokers: [ localhost:9092 ]
+        topic: events_enriched
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"event_latency", eventLatencyOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newEventLatencyOutputFromConfig(conf, mgr)
+			maxInFlight = 64
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type eventLatencyOutput struct {
+	output wrapper.BatchWriter
+	timer  *eventTimer
+}
+
+func newEventLatencyOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*eventLatencyOutput, error) {
+	o := &eventLatencyOutput{}
+
+	var err error
+	if o.timer, err = eventTimerFromParsed(conf, mgr, "event_latency_ns"); err != nil {
+		return nil, err
+	}
+	if o.output, err = conf.FieldOutput(elFieldOutput); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (*eventLatencyOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *eventLatencyOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	if err := o.output.WriteBatch(ctx, batch); err != nil {
+		return err
+	}
+	o.timer.observeBatch(batch)
+	return nil
+}
+
+func (o *eventLatencyOutput) Close(ctx context.Context) error {
+	return o.output.Close(ctx)
+}
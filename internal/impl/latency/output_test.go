@@ -0,0 +1,115 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeWriter struct {
+	err error
+}
+
+func (f *fakeWriter) WriteBatch(context.Context, service.MessageBatch) error {
+	return f.err
+}
+
+func (*fakeWriter) Close(context.Context) error {
+	return nil
+}
+
+var testNow = time.Unix(1000, 0)
+
+// captureTimings replaces the metric of an event timer with a slice of the
+// recorded durations.
+func captureTimings(e *eventTimer) *[]time.Duration {
+	var timings []time.Duration
+	e.now = func() time.Time { return testNow }
+	e.record = func(ns int64) {
+		timings = append(timings, time.Duration(ns))
+	}
+	return &timings
+}
+
+func kafkaMessage(tsMs int64) *service.Message {
+	msg := service.NewMessage([]byte("foo"))
+	msg.MetaSetMut("kafka_timestamp_ms", tsMs)
+	return msg
+}
+
+func TestEventLatencyOutput(t *testing.T) {
+	pConf, err := eventLatencyOutputConfig().ParseYAML(`
+output:
+  drop: {}
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newEventLatencyOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = o.Close(context.Background())
+	})
+
+	writer := &fakeWriter{}
+	o.output = writer
+	timings := captureTimings(o.timer)
+
+	batch := service.MessageBatch{
+		kafkaMessage(testNow.Add(-1500 * time.Millisecond).UnixMilli()),
+		service.NewMessage([]byte("no timestamp")),
+		kafkaMessage(testNow.Add(time.Second).UnixMilli()),
+	}
+
+	// Failed writes aren't measured.
+	writer.err = errors.New("nope")
+	require.ErrorContains(t, o.WriteBatch(t.Context(), batch), "nope")
+	assert.Empty(t, *timings)
+
+	writer.err = nil
+	require.NoError(t, o.WriteBatch(t.Context(), batch))
+	assert.Equal(t, []time.Duration{1500 * time.Millisecond, 0}, *timings)
+}
+
+func TestMessageAgeProcessorTimestampMapping(t *testing.T) {
+	pConf, err := messageAgeProcessorConfig().ParseYAML(`
+timestamp: 'root = this.created_at'
+`, nil)
+	require.NoError(t, err)
+
+	p, err := newMessageAgeProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	timings := captureTimings(p.timer)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"created_at":"` + testNow.Add(-time.Minute).Format(time.RFC3339) + `"}`)),
+		service.NewMessage([]byte(`{"created_at":null}`)),
+		service.NewMessage([]byte(`{"created_at":"not a timestamp"}`)),
+		service.NewMessage([]byte(`{"created_at":` + "990" + `}`)),
+	}
+	out, err := p.ProcessBatch(t.Context(), batch)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Len(t, out[0], 4)
+	assert.Equal(t, []time.Duration{time.Minute, 10 * time.Second}, *timings)
+}
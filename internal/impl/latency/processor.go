@@ -0,0 +1,73 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func messageAgeProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Records the age of messages, the time elapsed since their event time, as a metric without modifying them.").
+		Description(`
+The age of each message is recorded by the timer metric `+"`message_age_ns`"+` at the point within the pipeline where this processor runs, which is labelled with the `+"`label`"+` and `+"`path`"+` of the processor like other component metrics. Placing this processor within the processors of an input measures how far behind the event stream the input is, which is useful for alerting on consumer lag in terms of time rather than offsets.
+
+In order to measure the age of messages once they're delivered use the xref:components:outputs/event_latency.adoc[`+"`event_latency`"+` output].`).
+		Field(timestampField()).
+		Example("Kafka consumer age", "Measure the age of records as they're consumed from Kafka.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: example
+  processors:
+    - label: consumed
+      message_age: {}
+`)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor(
+		"message_age", messageAgeProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newMessageAgeProcessorFromConfig(conf, mgr)
+		})
+}
+
+type messageAgeProcessor struct {
+	timer *eventTimer
+}
+
+func newMessageAgeProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*messageAgeProcessor, error) {
+	timer, err := eventTimerFromParsed(conf, mgr, "message_age_ns")
+	if err != nil {
+		return nil, err
+	}
+	return &messageAgeProcessor{timer: timer}, nil
+}
+
+func (p *messageAgeProcessor) ProcessBatch(_ context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	p.timer.observeBatch(batch)
+	return []service.MessageBatch{batch}, nil
+}
+
+func (*messageAgeProcessor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,410 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	leFieldInput           = "input"
+	leFieldCache           = "cache"
+	leFieldCacheKey        = "cache_key"
+	leFieldKubernetesLease = "kubernetes_lease"
+	leFieldLeaseName       = "name"
+	leFieldLeaseNamespace  = "namespace"
+	leFieldKubeconfig      = "kubeconfig"
+	leFieldContext         = "context"
+	leFieldIdentity        = "identity"
+	leFieldLeaseDuration   = "lease_duration"
+	leFieldRenewDeadline   = "renew_deadline"
+	leFieldRetryPeriod     = "retry_period"
+)
+
+func leaderInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Runs a child input only while this instance is the elected leader amongst the instances sharing a lock, for active/passive deployments of inputs that can't be consumed by several instances at once.").
+		Description(`
+Inputs such as change data capture or file watchers can't be partitioned amongst several instances, and running more than one instance of them would consume each change several times. This input allows several replicas of a pipeline to be deployed for high availability, where the replicas elect a leader with a lock and only the leader runs the child `+"`"+leFieldInput+"`"+`. The child input is only created once leadership is acquired, and is closed when leadership is lost, at which point another replica acquires the lock and takes over.
+
+The lock is either a key of a xref:components:caches/about.adoc[cache resource] set with `+"`"+leFieldCache+"`"+`, or a Kubernetes https://kubernetes.io/docs/concepts/architecture/leases/[Lease^] set with `+"`"+leFieldKubernetesLease+"`"+`. A cache lock must be a cache shared by all replicas that supports TTLs, such as `+"`redis`"+`, and holds the identity of the leader under the key for the `+"`"+leFieldLeaseDuration+"`"+`. When running within a cluster the service account of the pod must be allowed to `+"`get`, `create` and `update`"+` leases.
+
+The leader attempts to renew the lock every `+"`"+leFieldRetryPeriod+"`"+`, and steps down when it fails to renew the lock within the `+"`"+leFieldRenewDeadline+"`"+`, which is shorter than the `+"`"+leFieldLeaseDuration+"`"+` so that the leader stops consuming before the lock can be acquired by another replica. Other replicas attempt to acquire the lock every `+"`"+leFieldRetryPeriod+"`"+`, and the lock is released when the leader shuts down gracefully, which means that failover takes at most the `+"`"+leFieldLeaseDuration+"`"+` when the leader fails, and at most the `+"`"+leFieldRetryPeriod+"`"+` when the leader shuts down.
+
+Messages that were consumed by a leader that lost leadership and weren't yet acknowledged may be consumed again by the next leader, and so the child input must support at-least-once delivery by resuming from a position that is stored outside of the replicas, such as a cache or the source itself.
+
+== Metrics
+
+The gauge `+"`leader_elected`"+` is 1 while this instance is the leader, and 0 otherwise.`).
+		Fields(
+			service.NewInputField(leFieldInput).
+				Description("The child input to run while this instance is the leader."),
+			service.NewStringField(leFieldCache).
+				Description("A cache resource to use as the lock.").
+				Optional(),
+			service.NewStringField(leFieldCacheKey).
+				Description("The key of the lock within the cache.").
+				Default("connect_leader"),
+			service.NewObjectField(leFieldKubernetesLease,
+				service.NewStringField(leFieldLeaseName).
+					Description("The name of the lease."),
+				service.NewStringField(leFieldLeaseNamespace).
+					Description("The namespace of the lease.").
+					Default("default"),
+				service.NewStringField(leFieldKubeconfig).
+					Description("An optional path of a kubeconfig file. When empty the service account of the pod is used when running within a cluster, and otherwise the default kubeconfig file.").
+					Default("").
+					Advanced(),
+				service.NewStringField(leFieldContext).
+					Description("An optional kubeconfig context to use instead of the current context.").
+					Default("").
+					Advanced(),
+			).
+				Description("A Kubernetes lease to use as the lock.").
+				Optional(),
+			service.NewStringField(leFieldIdentity).
+				Description("The unique identity of this instance. When empty the hostname followed by a random suffix is used, which is unique per process.").
+				Default("").
+				Advanced(),
+			service.NewDurationField(leFieldLeaseDuration).
+				Description("The period for which a lock is held by the leader without being renewed, after which other instances may acquire it.").
+				Default("15s"),
+			service.NewDurationField(leFieldRenewDeadline).
+				Description("The period within which the leader must renew the lock, after which it steps down. Must be shorter than the `lease_duration`.").
+				Default("10s").
+				Advanced(),
+			service.NewDurationField(leFieldRetryPeriod).
+				Description("The interval at which the leader renews the lock and other instances attempt to acquire it. Must be shorter than the `renew_deadline`.").
+				Default("2s").
+				Advanced(),
+		).
+		LintRule(`root = if this.exists("cache") == this.exists("kubernetes_lease") { [ "exactly one of cache or kubernetes_lease must be set" ] }`).
+		Example("CDC with a Kubernetes lease", "Run several replicas of a Postgres CDC pipeline, where only the replica holding the lease consumes changes.", `
+input:
+  leader_elected:
+    kubernetes_lease:
+      name: orders-cdc
+      namespace: pipelines
+    input:
+      postgres_cdc:
+        dsn: postgres://user:pass@db:5432/orders
+        slot_name: connect_orders
+        schema: public
+        tables: [ orders ]
+`).
+		Example("SFTP with a Redis lock", "Watch an SFTP directory from only one of several replicas, using a Redis key as the lock.", `
+input:
+  leader_elected:
+    cache: locks
+    cache_key: sftp_invoices_leader
+    input:
+      sftp:
+        address: sftp.example.com:22
+        paths: [ /invoices/*.csv ]
+        watcher:
+          enabled: true
+
+cache_resources:
+  - label: locks
+    redis:
+      url: redis://redis:6379
+`)
+}
+
+func init() {
+	service.MustRegisterBatchInput(
+		"leader_elected", leaderInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return newLeaderInputFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+// lock is a lock shared amongst instances, of which at most one instance
+// holds the lock at a time.
+type lock interface {
+	// TryAcquire attempts to acquire the lock, or to renew it when it's
+	// already held, and returns whether the lock is held by this instance.
+	TryAcquire(ctx context.Context) (bool, error)
+
+	// Release releases the lock when it's held by this instance.
+	Release(ctx context.Context) error
+}
+
+// batchReader is the subset of an owned input used by this input.
+type batchReader interface {
+	ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error)
+	Close(ctx context.Context) error
+}
+
+type leaderInput struct {
+	log      *service.Logger
+	mLeader  *service.MetricGauge
+	newChild func() (batchReader, error)
+
+	lock          lock
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	startOnce sync.Once
+	stopCtx   context.Context
+	stopFn    context.CancelFunc
+	loopDone  chan struct{}
+
+	// child is the child input, which is only set while this instance is the
+	// leader. The changed channel is closed and replaced whenever it changes.
+	mut     sync.Mutex
+	child   batchReader
+	changed chan struct{}
+}
+
+func newLeaderInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*leaderInput, error) {
+	l := &leaderInput{
+		log:      mgr.Logger(),
+		mLeader:  mgr.Metrics().NewGauge("leader_elected"),
+		loopDone: make(chan struct{}),
+		changed:  make(chan struct{}),
+	}
+	l.newChild = func() (batchReader, error) {
+		return conf.FieldInput(leFieldInput)
+	}
+	l.stopCtx, l.stopFn = context.WithCancel(context.Background())
+
+	var err error
+	if l.leaseDuration, err = conf.FieldDuration(leFieldLeaseDuration); err != nil {
+		return nil, err
+	}
+	if l.renewDeadline, err = conf.FieldDuration(leFieldRenewDeadline); err != nil {
+		return nil, err
+	}
+	if l.retryPeriod, err = conf.FieldDuration(leFieldRetryPeriod); err != nil {
+		return nil, err
+	}
+	if l.retryPeriod <= 0 || l.renewDeadline <= l.retryPeriod || l.leaseDuration <= l.renewDeadline {
+		return nil, errors.New("the lease_duration must be greater than the renew_deadline, which must be greater than the retry_period")
+	}
+
+	identity, err := conf.FieldString(leFieldIdentity)
+	if err != nil {
+		return nil, err
+	}
+	if identity == "" {
+		hostname, _ := os.Hostname()
+		identity = hostname + "-" + xid.New().String()
+	}
+
+	hasCache, hasLease := conf.Contains(leFieldCache), conf.Contains(leFieldKubernetesLease)
+	if hasCache == hasLease {
+		return nil, fmt.Errorf("exactly one of %v or %v must be set", leFieldCache, leFieldKubernetesLease)
+	}
+	if hasCache {
+		c := &cacheLock{
+			mgr:           mgr,
+			identity:      identity,
+			leaseDuration: l.leaseDuration,
+		}
+		if c.cache, err = conf.FieldString(leFieldCache); err != nil {
+			return nil, err
+		}
+		if c.key, err = conf.FieldString(leFieldCacheKey); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(c.cache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", c.cache)
+		}
+		l.lock = c
+		return l, nil
+	}
+
+	if l.leaseDuration < time.Second {
+		return nil, errors.New("the lease_duration of a kubernetes lease must be at least one second")
+	}
+	leaseConf := conf.Namespace(leFieldKubernetesLease)
+	name, err := leaseConf.FieldString(leFieldLeaseName)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := leaseConf.FieldString(leFieldLeaseNamespace)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, err := leaseConf.FieldString(leFieldKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, err := leaseConf.FieldString(leFieldContext)
+	if err != nil {
+		return nil, err
+	}
+	client, err := leasesClient(kubeconfig, kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	l.lock = newLeaseLock(client, namespace, name, identity, l.leaseDuration)
+	return l, nil
+}
+
+func (l *leaderInput) Connect(context.Context) error {
+	l.startOnce.Do(func() {
+		go l.electionLoop()
+	})
+	return nil
+}
+
+func (l *leaderInput) current() (batchReader, <-chan struct{}) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	return l.child, l.changed
+}
+
+func (l *leaderInput) setChild(child batchReader) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	l.child = child
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func (l *leaderInput) electionLoop() {
+	defer close(l.loopDone)
+
+	var lastRenewal time.Time
+	for {
+		ctx, done := context.WithTimeout(l.stopCtx, l.retryPeriod)
+		held, err := l.lock.TryAcquire(ctx)
+		done()
+		if l.stopCtx.Err() != nil {
+			// Leadership is released when the input is closed.
+			return
+		}
+
+		child, _ := l.current()
+		switch {
+		case err == nil && held:
+			lastRenewal = time.Now()
+			if child == nil {
+				l.becomeLeader()
+			}
+		case child != nil && err != nil && time.Since(lastRenewal) < l.renewDeadline:
+			l.log.Warnf("Failed to renew leadership lock, retrying: %v", err)
+		default:
+			if err != nil {
+				l.log.Warnf("Failed to acquire leadership lock: %v", err)
+			}
+			if child != nil {
+				l.log.Warn("Lost leadership, closing child input")
+				l.stepDown(child)
+			}
+		}
+
+		select {
+		case <-time.After(l.retryPeriod):
+		case <-l.stopCtx.Done():
+			return
+		}
+	}
+}
+
+func (l *leaderInput) becomeLeader() {
+	child, err := l.newChild()
+	if err != nil {
+		l.log.Errorf("Failed to create child input, releasing leadership: %v", err)
+		ctx, done := context.WithTimeout(l.stopCtx, l.retryPeriod)
+		defer done()
+		if err := l.lock.Release(ctx); err != nil {
+			l.log.Warnf("Failed to release leadership lock: %v", err)
+		}
+		return
+	}
+	l.log.Info("Acquired leadership, starting child input")
+	l.setChild(child)
+	l.mLeader.Set(1)
+}
+
+func (l *leaderInput) stepDown(child batchReader) {
+	l.setChild(nil)
+	l.mLeader.Set(0)
+
+	// Give in flight messages until the lease would expire to be
+	// acknowledged.
+	ctx, done := context.WithTimeout(context.Background(), l.leaseDuration)
+	defer done()
+	if err := child.Close(ctx); err != nil {
+		l.log.Warnf("Failed to close child input: %v", err)
+	}
+}
+
+func (l *leaderInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		child, changed := l.current()
+		if child == nil {
+			select {
+			case <-changed:
+				continue
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+
+		batch, ackFn, err := child.ReadBatch(ctx)
+		if errors.Is(err, service.ErrEndOfInput) {
+			// The child input is closed when leadership is lost, in which
+			// case we wait to become the leader again.
+			if current, _ := l.current(); current != child {
+				continue
+			}
+		}
+		return batch, ackFn, err
+	}
+}
+
+func (l *leaderInput) Close(ctx context.Context) error {
+	l.stopFn()
+	l.startOnce.Do(func() {
+		// The election loop was never started.
+		close(l.loopDone)
+	})
+	select {
+	case <-l.loopDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var errs []error
+	if child, _ := l.current(); child != nil {
+		l.setChild(nil)
+		l.mLeader.Set(0)
+		errs = append(errs, child.Close(ctx))
+
+		// Releasing the lock allows another instance to take over without
+		// waiting for the lease to expire.
+		errs = append(errs, l.lock.Release(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// fakeReader yields its name as a message until it's closed.
+type fakeReader struct {
+	name   string
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeReader(name string) *fakeReader {
+	return &fakeReader{name: name, closed: make(chan struct{})}
+}
+
+func (f *fakeReader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	select {
+	case <-f.closed:
+		return nil, nil, service.ErrEndOfInput
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-time.After(time.Millisecond):
+	}
+	return service.MessageBatch{service.NewMessage([]byte(f.name))}, func(context.Context, error) error { return nil }, nil
+}
+
+func (f *fakeReader) Close(context.Context) error {
+	f.once.Do(func() { close(f.closed) })
+	return nil
+}
+
+func newTestInput(t *testing.T, mgr *service.Resources, name string) (*leaderInput, *[]*fakeReader) {
+	t.Helper()
+
+	pConf, err := leaderInputConfig().ParseYAML(`
+cache: locks
+identity: `+name+`
+lease_duration: 300ms
+renew_deadline: 200ms
+retry_period: 20ms
+input:
+  generate:
+    mapping: 'root = "unused"'
+`, nil)
+	require.NoError(t, err)
+
+	l, err := newLeaderInputFromConfig(pConf, mgr)
+	require.NoError(t, err)
+
+	var mut sync.Mutex
+	var children []*fakeReader
+	l.newChild = func() (batchReader, error) {
+		mut.Lock()
+		defer mut.Unlock()
+		c := newFakeReader(name)
+		children = append(children, c)
+		return c, nil
+	}
+	return l, &children
+}
+
+func readName(t *testing.T, l *leaderInput, timeout time.Duration) string {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), timeout)
+	defer done()
+
+	batch, _, err := l.ReadBatch(ctx)
+	if err != nil {
+		return ""
+	}
+	content, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestLeaderInputFailover(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("locks"))
+
+	a, _ := newTestInput(t, mgr, "a")
+	require.NoError(t, a.Connect(t.Context()))
+	assert.Equal(t, "a", readName(t, a, time.Second))
+
+	b, _ := newTestInput(t, mgr, "b")
+	require.NoError(t, b.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = b.Close(context.Background())
+	})
+
+	// Only the leader consumes.
+	assert.Empty(t, readName(t, b, 100*time.Millisecond))
+
+	// Closing the leader releases the lock, and the other instance takes over.
+	require.NoError(t, a.Close(t.Context()))
+	assert.Equal(t, "b", readName(t, b, time.Second))
+}
+
+type flakyLock struct {
+	mut  sync.Mutex
+	fail bool
+}
+
+func (f *flakyLock) TryAcquire(context.Context) (bool, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return !f.fail, nil
+}
+
+func (*flakyLock) Release(context.Context) error {
+	return nil
+}
+
+func TestLeaderInputStepsDown(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("locks"))
+
+	l, children := newTestInput(t, mgr, "a")
+	lock := &flakyLock{}
+	l.lock = lock
+	require.NoError(t, l.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = l.Close(context.Background())
+	})
+	assert.Equal(t, "a", readName(t, l, time.Second))
+
+	lock.mut.Lock()
+	lock.fail = true
+	lock.mut.Unlock()
+
+	require.Eventually(t, func() bool {
+		select {
+		case <-(*children)[0].closed:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, readName(t, l, 100*time.Millisecond))
+
+	// A new child input is created when leadership is acquired again.
+	lock.mut.Lock()
+	lock.fail = false
+	lock.mut.Unlock()
+	assert.Equal(t, "a", readName(t, l, time.Second))
+	assert.Len(t, *children, 2)
+}
+
+func TestLeaderInputConfigErrors(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("locks"))
+	for _, conf := range []string{
+		`input: { generate: { mapping: 'root = ""' } }`,
+		`
+cache: nope
+input: { generate: { mapping: 'root = ""' } }
+`,
+		`
+cache: locks
+renew_deadline: 20s
+input: { generate: { mapping: 'root = ""' } }
+`,
+	} {
+		pConf, err := leaderInputConfig().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = newLeaderInputFromConfig(pConf, mgr)
+		require.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// cacheLock is a lock held by storing the identity of the holder under a key
+// of a cache resource with a TTL of the lease duration.
+type cacheLock struct {
+	mgr           *service.Resources
+	cache         string
+	key           string
+	identity      string
+	leaseDuration time.Duration
+}
+
+func (c *cacheLock) TryAcquire(ctx context.Context) (held bool, err error) {
+	if cerr := c.mgr.AccessCache(ctx, c.cache, func(cache service.Cache) {
+		var holder []byte
+		holder, err = cache.Get(ctx, c.key)
+		switch {
+		case err == nil && string(holder) == c.identity:
+			err = cache.Set(ctx, c.key, []byte(c.identity), &c.leaseDuration)
+			held = err == nil
+		case err == nil:
+			// Held by another instance.
+		case errors.Is(err, service.ErrKeyNotFound):
+			err = cache.Add(ctx, c.key, []byte(c.identity), &c.leaseDuration)
+			if errors.Is(err, service.ErrKeyAlreadyExists) {
+				err = nil
+				return
+			}
+			held = err == nil
+		}
+	}); cerr != nil {
+		return false, cerr
+	}
+	return
+}
+
+func (c *cacheLock) Release(ctx context.Context) (err error) {
+	if cerr := c.mgr.AccessCache(ctx, c.cache, func(cache service.Cache) {
+		var holder []byte
+		if holder, err = cache.Get(ctx, c.key); err != nil {
+			if errors.Is(err, service.ErrKeyNotFound) {
+				err = nil
+			}
+			return
+		}
+		if string(holder) == c.identity {
+			err = cache.Delete(ctx, c.key)
+		}
+	}); cerr != nil {
+		return cerr
+	}
+	return
+}
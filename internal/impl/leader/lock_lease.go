@@ -0,0 +1,151 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// leaseLock is a lock held by owning a Kubernetes Lease.
+//
+// As with the leader election of client-go, the expiry of a lease held by
+// another instance is measured from when this instance observed the latest
+// renewal of the lease, rather than from the renew time recorded within it,
+// such that the clocks of instances don't need to be synchronised.
+type leaseLock struct {
+	lock          *resourcelock.LeaseLock
+	identity      string
+	leaseDuration time.Duration
+	now           func() time.Time
+
+	observedRaw  []byte
+	observedTime time.Time
+}
+
+func newLeaseLock(client coordinationv1.LeasesGetter, namespace, name, identity string, leaseDuration time.Duration) *leaseLock {
+	return &leaseLock{
+		lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Client:     client,
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		now:           time.Now,
+	}
+}
+
+func leasesClient(kubeconfig, kubeContext string) (coordinationv1.LeasesGetter, error) {
+	var cfg *rest.Config
+	if kubeconfig == "" && kubeContext == "" {
+		cfg, _ = rest.InClusterConfig()
+	}
+	if cfg == nil {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = kubeconfig
+		var err error
+		if cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			rules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+		).ClientConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+		}
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.CoordinationV1(), nil
+}
+
+func (l *leaseLock) TryAcquire(ctx context.Context) (bool, error) {
+	now := metav1.NewTime(l.now())
+	record := resourcelock.LeaderElectionRecord{
+		HolderIdentity:       l.identity,
+		LeaseDurationSeconds: int(l.leaseDuration / time.Second),
+		AcquireTime:          now,
+		RenewTime:            now,
+	}
+
+	existing, raw, err := l.lock.Get(ctx)
+	if apierrors.IsNotFound(err) {
+		if err := l.lock.Create(ctx, record); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		l.observedRaw, l.observedTime = nil, now.Time
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !bytes.Equal(raw, l.observedRaw) {
+		l.observedRaw, l.observedTime = raw, now.Time
+	}
+
+	switch {
+	case existing.HolderIdentity == l.identity:
+		record.AcquireTime = existing.AcquireTime
+		record.LeaderTransitions = existing.LeaderTransitions
+	case existing.HolderIdentity != "" && l.observedTime.Add(l.leaseDuration).After(now.Time):
+		return false, nil
+	default:
+		record.LeaderTransitions = existing.LeaderTransitions + 1
+	}
+
+	if err := l.lock.Update(ctx, record); err != nil {
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *leaseLock) Release(ctx context.Context) error {
+	existing, _, err := l.lock.Get(ctx)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.HolderIdentity != l.identity {
+		return nil
+	}
+
+	// As with client-go, a released lease has no holder and a duration of a
+	// second, which allows other instances to acquire it immediately.
+	now := metav1.NewTime(l.now())
+	return l.lock.Update(ctx, resourcelock.LeaderElectionRecord{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    existing.LeaderTransitions,
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLeaseLock(t *testing.T) {
+	client := fake.NewClientset().CoordinationV1()
+
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+
+	a := newLeaseLock(client, "default", "leader", "a", 15*time.Second)
+	b := newLeaseLock(client, "default", "leader", "b", 15*time.Second)
+	a.now, b.now = clock, clock
+
+	held, err := a.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = b.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	// Renewals by the holder keep the lease from expiring.
+	now = now.Add(10 * time.Second)
+	held, err = a.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	now = now.Add(10 * time.Second)
+	held, err = b.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	// Without renewals the lease expires once the lease duration has passed
+	// since the latest renewal was observed.
+	now = now.Add(16 * time.Second)
+	held, err = b.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = a.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	// A released lease can be acquired immediately.
+	require.NoError(t, b.Release(t.Context()))
+	held, err = a.TryAcquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+}
@@ -0,0 +1,535 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	loFieldURL                       = "url"
+	loFieldTenantID                  = "tenant_id"
+	loFieldHeaders                   = "headers"
+	loFieldLabelsMapping             = "labels_mapping"
+	loFieldLineMapping               = "line_mapping"
+	loFieldStructuredMetadataMapping = "structured_metadata_mapping"
+	loFieldTimestampMapping          = "timestamp_mapping"
+	loFieldMaxLabelNames             = "max_label_names"
+	loFieldMaxLabelValueLength       = "max_label_value_length"
+	loFieldMaxStreams                = "max_streams"
+	loFieldGzip                      = "gzip"
+	loFieldTLS                       = "tls"
+	loFieldTimeout                   = "timeout"
+	loFieldBatching                  = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Pushes messages as log lines to Grafana Loki.").
+		Description(`
+Each message is pushed as a log line to the stream identified by the labels extracted with `+"`labels_mapping`"+`, where each batch is pushed with a request per tenant containing the lines of each stream in order of their timestamps.
+
+== Cardinality
+
+Each unique set of labels creates a stream within Loki, and therefore labels should only be extracted from fields with a small number of possible values, such as the service or environment that produced a log, whereas high cardinality values such as request IDs are better suited to structured metadata. In order to protect Loki from accidentally high cardinality the labels of each message are limited by `+"`max_label_names` and `max_label_value_length`"+`, and batches that contain more streams than `+"`max_streams`"+` are rejected.
+
+Label names that contain characters other than letters, digits and underscores have those characters replaced with underscores, and labels with empty values are omitted.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewURLField(loFieldURL).
+				Description("The URL of the push API of Loki.").
+				Example("http://localhost:3100/loki/api/v1/push"),
+			service.NewInterpolatedStringField(loFieldTenantID).
+				Description("An optional tenant ID of each message, which is sent as the `X-Scope-OrgID` header of multi-tenant deployments.").
+				Example("acme").
+				Example(`${! @tenant }`).
+				Optional(),
+			service.NewStringMapField(loFieldHeaders).
+				Description("A map of headers to add to each request.").
+				Default(map[string]any{}).
+				Advanced(),
+		).
+		Fields(service.NewHTTPRequestAuthSignerFields()...).
+		Fields(
+			service.NewBloblangField(loFieldLabelsMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to an object of the stream labels of each message.").
+				Example(`root = { "service_name": this.service, "env": "prod", "level": this.level }`).
+				Default(`root = { "service_name": "redpanda-connect" }`),
+			service.NewBloblangField(loFieldLineMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to the log line of each message. When omitted the contents of messages are used as log lines.").
+				Example(`root = this.message`).
+				Optional(),
+			service.NewBloblangField(loFieldStructuredMetadataMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to an object of the https://grafana.com/docs/loki/latest/get-started/labels/structured-metadata/[structured metadata^] of each log line, which requires Loki 3.0 or newer.").
+				Example(`root = { "trace_id": this.trace_id, "partition": @kafka_partition }`).
+				Optional(),
+			service.NewBloblangField(loFieldTimestampMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to the timestamp of each log line. When omitted log lines are given the time at which they're pushed.").
+				Example(`root = this.timestamp`).
+				Optional(),
+			service.NewIntField(loFieldMaxLabelNames).
+				Description("The maximum number of labels of each message, where messages with more labels are rejected.").
+				Default(15).
+				Advanced(),
+			service.NewIntField(loFieldMaxLabelValueLength).
+				Description("The maximum length of label values, where longer values are truncated.").
+				Default(1024).
+				Advanced(),
+			service.NewIntField(loFieldMaxStreams).
+				Description("The maximum number of streams within each batch, where batches with more streams are rejected. Set to zero in order to disable the limit.").
+				Default(1000).
+				Advanced(),
+			service.NewBoolField(loFieldGzip).
+				Description("Whether to compress requests with gzip.").
+				Default(true).
+				Advanced(),
+			service.NewTLSToggledField(loFieldTLS).
+				Advanced(),
+			service.NewDurationField(loFieldTimeout).
+				Description("The maximum period of time to wait for each push request.").
+				Default("10s").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(loFieldBatching),
+		).
+		Example("Kafka logs", "Pushes JSON logs consumed from Kafka to Loki, with a tenant per team and trace IDs as structured metadata.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ logs ]
+    consumer_group: loki
+
+output:
+  loki:
+    url: http://localhost:3100/loki/api/v1/push
+    tenant_id: ${! this.team }
+    labels_mapping: 'root = { "service_name": this.service, "level": this.level }'
+    line_mapping: 'root = this.message'
+    structured_metadata_mapping: 'root = { "trace_id": this.trace_id }'
+    timestamp_mapping: 'root = this.time'
+    batching:
+      count: 1000
+      period: 1s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"loki", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(loFieldBatching); err != nil {
+				return
+			}
+			out, err = newOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+type output struct {
+	url      string
+	tenantID *service.InterpolatedString
+	headers  map[string]string
+	signer   func(fs.FS, *http.Request) error
+	fs       fs.FS
+
+	labelsMapping             *bloblang.Executor
+	lineMapping               *bloblang.Executor
+	structuredMetadataMapping *bloblang.Executor
+	timestampMapping          *bloblang.Executor
+
+	maxLabelNames       int
+	maxLabelValueLength int
+	maxStreams          int
+	gzip                bool
+
+	client *http.Client
+	nowFn  func() time.Time
+}
+
+func newOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{
+		fs:    mgr.FS(),
+		nowFn: time.Now,
+	}
+
+	u, err := conf.FieldURL(loFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	o.url = u.String()
+
+	if conf.Contains(loFieldTenantID) {
+		if o.tenantID, err = conf.FieldInterpolatedString(loFieldTenantID); err != nil {
+			return nil, err
+		}
+	}
+	if o.headers, err = conf.FieldStringMap(loFieldHeaders); err != nil {
+		return nil, err
+	}
+	if o.signer, err = conf.HTTPRequestAuthSignerFromParsed(); err != nil {
+		return nil, err
+	}
+
+	if o.labelsMapping, err = conf.FieldBloblang(loFieldLabelsMapping); err != nil {
+		return nil, err
+	}
+	for name, target := range map[string]**bloblang.Executor{
+		loFieldLineMapping:               &o.lineMapping,
+		loFieldStructuredMetadataMapping: &o.structuredMetadataMapping,
+		loFieldTimestampMapping:          &o.timestampMapping,
+	} {
+		if conf.Contains(name) {
+			if *target, err = conf.FieldBloblang(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if o.maxLabelNames, err = conf.FieldInt(loFieldMaxLabelNames); err != nil {
+		return nil, err
+	}
+	if o.maxLabelValueLength, err = conf.FieldInt(loFieldMaxLabelValueLength); err != nil {
+		return nil, err
+	}
+	if o.maxStreams, err = conf.FieldInt(loFieldMaxStreams); err != nil {
+		return nil, err
+	}
+	if o.gzip, err = conf.FieldBool(loFieldGzip); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(loFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(loFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{Transport: transport, Timeout: timeout}
+	return o, nil
+}
+
+func (*output) Connect(context.Context) error {
+	return nil
+}
+
+// queryStructured executes a mapping, where results that are strings are
+// returned as strings rather than parsed.
+func queryStructured(exec *service.MessageBatchBloblangExecutor, i int) (any, error) {
+	msg, err := exec.Query(i)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	v, err := msg.AsStructured()
+	if err != nil {
+		b, _ := msg.AsBytes()
+		return string(b), nil
+	}
+	return v, nil
+}
+
+func queryObject(exec *service.MessageBatchBloblangExecutor, i int) (map[string]string, error) {
+	v, err := queryStructured(exec, i)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", v)
+	}
+	res := make(map[string]string, len(obj))
+	for k, e := range obj {
+		if e == nil {
+			continue
+		}
+		if s := bloblang.ValueToString(e); s != "" {
+			res[k] = s
+		}
+	}
+	return res, nil
+}
+
+// sanitiseLabelName replaces the characters of a label name that are not
+// allowed by Loki with underscores.
+func sanitiseLabelName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		isAlpha := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !isAlpha && !(i > 0 && c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+type entry struct {
+	timestamp int64
+	line      string
+	metadata  map[string]string
+}
+
+type stream struct {
+	labels  map[string]string
+	entries []entry
+}
+
+type tenant struct {
+	id      string
+	streams []*stream
+}
+
+func (o *output) labels(exec *service.MessageBatchBloblangExecutor, i int) (map[string]string, string, error) {
+	raw, err := queryObject(exec, i)
+	if err != nil {
+		return nil, "", fmt.Errorf("labels mapping failed: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, "", fmt.Errorf("message %v has no labels", i)
+	}
+	if len(raw) > o.maxLabelNames {
+		return nil, "", fmt.Errorf("message %v has %v labels, which exceeds the maximum of %v", i, len(raw), o.maxLabelNames)
+	}
+
+	labels := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if len(v) > o.maxLabelValueLength {
+			v = v[:o.maxLabelValueLength]
+		}
+		labels[sanitiseLabelName(k)] = v
+	}
+
+	var key strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		key.WriteString(k)
+		key.WriteByte(0)
+		key.WriteString(labels[k])
+		key.WriteByte(0)
+	}
+	return labels, key.String(), nil
+}
+
+func (o *output) batchToTenants(batch service.MessageBatch) ([]*tenant, error) {
+	labelsExec := batch.BloblangExecutor(o.labelsMapping)
+	var lineExec, metadataExec, timestampExec *service.MessageBatchBloblangExecutor
+	if o.lineMapping != nil {
+		lineExec = batch.BloblangExecutor(o.lineMapping)
+	}
+	if o.structuredMetadataMapping != nil {
+		metadataExec = batch.BloblangExecutor(o.structuredMetadataMapping)
+	}
+	if o.timestampMapping != nil {
+		timestampExec = batch.BloblangExecutor(o.timestampMapping)
+	}
+	var tenantExec *service.MessageBatchInterpolationExecutor
+	if o.tenantID != nil {
+		tenantExec = batch.InterpolationExecutor(o.tenantID)
+	}
+	now := o.nowFn().UnixNano()
+
+	var tenants []*tenant
+	tenantIndexes := map[string]int{}
+	streamIndexes := map[string]int{}
+	var totalStreams int
+	for i, msg := range batch {
+		var tenantID string
+		if tenantExec != nil {
+			var err error
+			if tenantID, err = tenantExec.TryString(i); err != nil {
+				return nil, fmt.Errorf("tenant ID interpolation error: %w", err)
+			}
+		}
+
+		labels, key, err := o.labels(labelsExec, i)
+		if err != nil {
+			return nil, err
+		}
+
+		e := entry{timestamp: now}
+		if lineExec != nil {
+			v, err := queryStructured(lineExec, i)
+			if err != nil {
+				return nil, fmt.Errorf("line mapping failed: %w", err)
+			}
+			e.line = bloblang.ValueToString(v)
+		} else {
+			b, err := msg.AsBytes()
+			if err != nil {
+				return nil, err
+			}
+			e.line = string(b)
+		}
+		if metadataExec != nil {
+			if e.metadata, err = queryObject(metadataExec, i); err != nil {
+				return nil, fmt.Errorf("structured metadata mapping failed: %w", err)
+			}
+		}
+		if timestampExec != nil {
+			v, err := queryStructured(timestampExec, i)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			t, err := bloblang.ValueAsTimestamp(v)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			e.timestamp = t.UnixNano()
+		}
+
+		ti, exists := tenantIndexes[tenantID]
+		if !exists {
+			ti = len(tenants)
+			tenantIndexes[tenantID] = ti
+			tenants = append(tenants, &tenant{id: tenantID})
+		}
+		t := tenants[ti]
+
+		streamKey := tenantID + "\x00" + key
+		si, exists := streamIndexes[streamKey]
+		if !exists {
+			if totalStreams++; o.maxStreams > 0 && totalStreams > o.maxStreams {
+				return nil, fmt.Errorf("batch has more than the maximum of %v streams, which suggests that labels have a high cardinality", o.maxStreams)
+			}
+			si = len(t.streams)
+			streamIndexes[streamKey] = si
+			t.streams = append(t.streams, &stream{labels: labels})
+		}
+		t.streams[si].entries = append(t.streams[si].entries, e)
+	}
+
+	for _, t := range tenants {
+		for _, s := range t.streams {
+			slices.SortStableFunc(s.entries, func(a, b entry) int { return cmp.Compare(a.timestamp, b.timestamp) })
+		}
+	}
+	return tenants, nil
+}
+
+func pushRequestBody(streams []*stream) ([]byte, error) {
+	type jsonStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][]any           `json:"values"`
+	}
+	req := struct {
+		Streams []jsonStream `json:"streams"`
+	}{Streams: make([]jsonStream, 0, len(streams))}
+
+	for _, s := range streams {
+		js := jsonStream{Stream: s.labels, Values: make([][]any, 0, len(s.entries))}
+		for _, e := range s.entries {
+			value := []any{strconv.FormatInt(e.timestamp, 10), e.line}
+			if len(e.metadata) > 0 {
+				value = append(value, e.metadata)
+			}
+			js.Values = append(js.Values, value)
+		}
+		req.Streams = append(req.Streams, js)
+	}
+	return json.Marshal(req)
+}
+
+func (o *output) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	tenants, err := o.batchToTenants(batch)
+	if err != nil {
+		return err
+	}
+	for _, t := range tenants {
+		if err := o.push(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *output) push(ctx context.Context, t *tenant) error {
+	body, err := pushRequestBody(t.streams)
+	if err != nil {
+		return err
+	}
+	if o.gzip {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(body); err != nil {
+			return err
+		}
+		if err := gw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+	if t.id != "" {
+		req.Header.Set("X-Scope-OrgID", t.id)
+	}
+	if err := o.signer(o.fs, req); err != nil {
+		return err
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return fmt.Errorf("push failed with status %v: %s", res.StatusCode, bytes.TrimSpace(resBody))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (o *output) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, conf string) *output {
+	t.Helper()
+
+	pConf, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOutputFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOutputPush(t *testing.T) {
+	var mut sync.Mutex
+	bodies := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+
+		gr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		b, err := io.ReadAll(gr)
+		require.NoError(t, err)
+
+		mut.Lock()
+		bodies[r.Header.Get("X-Scope-OrgID")] = string(b)
+		mut.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/loki/api/v1/push
+tenant_id: ${! this.team }
+labels_mapping: 'root = { "service.name": this.service, "level": this.level, "empty": "" }'
+line_mapping: 'root = this.message'
+structured_metadata_mapping: 'root = { "trace_id": this.trace_id }'
+timestamp_mapping: 'root = this.ts'
+`)
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"team":"a","service":"api","level":"info","message":"second","trace_id":"t2","ts":1700000002}`)),
+		service.NewMessage([]byte(`{"team":"a","service":"api","level":"error","message":"failed","ts":1700000001}`)),
+		service.NewMessage([]byte(`{"team":"b","service":"web","level":"info","message":"hello","ts":1700000003}`)),
+		service.NewMessage([]byte(`{"team":"a","service":"api","level":"info","message":"first","trace_id":"t1","ts":1700000000}`)),
+	}))
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{"streams":[
+  {"stream":{"service_name":"api","level":"info"},"values":[
+    ["1700000000000000000","first",{"trace_id":"t1"}],
+    ["1700000002000000000","second",{"trace_id":"t2"}]
+  ]},
+  {"stream":{"service_name":"api","level":"error"},"values":[
+    ["1700000001000000000","failed"]
+  ]}
+]}`, bodies["a"])
+	assert.JSONEq(t, `{"streams":[
+  {"stream":{"service_name":"web","level":"info"},"values":[
+    ["1700000003000000000","hello"]
+  ]}
+]}`, bodies["b"])
+}
+
+func TestOutputDefaults(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("X-Scope-OrgID"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`
+gzip: false
+`)
+	o.nowFn = func() time.Time { return time.Unix(0, 1234) }
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`plain log line`)),
+	}))
+	assert.JSONEq(t, `{"streams":[{"stream":{"service_name":"redpanda-connect"},"values":[["1234","plain log line"]]}]}`, body)
+}
+
+func TestOutputCardinalityGuardrails(t *testing.T) {
+	var batch service.MessageBatch
+	for i := range 5 {
+		batch = append(batch, service.NewMessage([]byte(`{"id":"`+strconv.Itoa(i)+`"}`)))
+	}
+
+	o := testOutput(t, `
+url: http://localhost:3100/loki/api/v1/push
+labels_mapping: 'root = { "request_id": this.id }'
+max_streams: 4
+`)
+	_, err := o.batchToTenants(batch)
+	require.ErrorContains(t, err, "batch has more than the maximum of 4 streams")
+
+	o = testOutput(t, `
+url: http://localhost:3100/loki/api/v1/push
+labels_mapping: 'root = { "a": 1, "b": 2, "c": 3 }'
+max_label_names: 2
+`)
+	_, err = o.batchToTenants(batch)
+	require.ErrorContains(t, err, "message 0 has 3 labels, which exceeds the maximum of 2")
+
+	o = testOutput(t, `
+url: http://localhost:3100/loki/api/v1/push
+labels_mapping: 'root = { "1st-label": "abcdef" }'
+max_label_value_length: 3
+`)
+	tenants, err := o.batchToTenants(batch[:1])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"_st_label": "abc"}, tenants[0].streams[0].labels)
+}
+
+func TestOutputPushError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("entry too far behind\n"))
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `url: `+srv.URL)
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`hello`))})
+	require.ErrorContains(t, err, "push failed with status 400: entry too far behind")
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// tables are the lookup_table resources by label, which are accessed by the
+// lookup function.
+var tables sync.Map
+
+func registerTable(t *lookupTable) {
+	if t.label != "" {
+		tables.Store(t.label, t)
+	}
+}
+
+func unregisterTable(t *lookupTable) {
+	if t.label != "" {
+		tables.CompareAndDelete(t.label, t)
+	}
+}
+
+func init() {
+	if err := bloblang.RegisterFunctionV2("lookup",
+		bloblang.NewPluginSpec().
+			Beta().
+			Category("Environment").
+			Description("Returns the row of a key from a xref:components:caches/lookup_table.adoc[`lookup_table`] cache resource, or `null` when the table has no row with the key.").
+			Param(bloblang.NewStringParam("table").Description("The label of a `lookup_table` cache resource.")).
+			Param(bloblang.NewAnyParam("key").Description("The key of the row, which is converted to a string.")).
+			ExampleNotTested("", `root.product = lookup("products", this.sku).or({})`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			table, err := args.GetString("table")
+			if err != nil {
+				return nil, err
+			}
+			key, err := args.Get("key")
+			if err != nil {
+				return nil, err
+			}
+			keyStr, isStr := key.(string)
+			if !isStr {
+				keyStr = fmt.Sprintf("%v", key)
+			}
+			return func() (any, error) {
+				t, exists := tables.Load(table)
+				if !exists {
+					return nil, fmt.Errorf("lookup_table resource %v was not found", table)
+				}
+				row, _ := t.(*lookupTable).row(keyStr)
+				return copyValue(row), nil
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
+
+// copyValue returns a deep copy of a row so that mappings modifying the result
+// of a lookup don't modify the table.
+func copyValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for i, e := range t {
+			s[i] = copyValue(e)
+		}
+		return s
+	}
+	return v
+}
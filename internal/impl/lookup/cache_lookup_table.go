@@ -0,0 +1,477 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	rpaws "github.com/redpanda-data/connect/v4/internal/impl/aws"
+	awsconfig "github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+)
+
+const (
+	ltFieldURL             = "url"
+	ltFieldFormat          = "format"
+	ltFieldKey             = "key"
+	ltFieldHeaders         = "headers"
+	ltFieldRefreshInterval = "refresh_interval"
+	ltFieldTimeout         = "timeout"
+	ltFieldAWS             = "aws"
+
+	ltFormatCSV  = "csv"
+	ltFormatJSON = "json"
+)
+
+func lookupTableCacheSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("A read-only cache of reference data loaded from a file, an HTTP URL or an S3 object, which is reloaded periodically.").
+		Description(`
+Loads a table of rows from a CSV or JSON document into memory, where each row is stored under the value of its key. The document is reloaded every `+"`refresh_interval`"+` when it has changed, which is detected with the modification time of files and the ETag of HTTP resources and S3 objects, so that unchanged documents aren't downloaded again. When reloading fails the previous table continues to be used.
+
+Rows can be obtained with the `+"xref:components:processors/cache.adoc[`cache` processor]"+`, where they're returned as JSON, or with the `+"`lookup`"+` Bloblang function, which takes the label of a `+"`lookup_table`"+` resource and a key and returns the row of the key, or `+"`null`"+` when there is no such row:
+
+`+"```coffeescript"+`
+root.country = lookup("countries", this.country_code).or({"name": "Unknown"})
+`+"```"+`
+
+Resources are created when they're first accessed by a component, and so the `+"`lookup`"+` function only finds tables that have been accessed, otherwise it fails. Tables can be accessed before any mappings are executed with a `+"`cache`"+` processor within a `+"xref:components:processors/branch.adoc[`branch` processor]"+`, as shown in the examples.
+
+Writing to this cache is not supported.
+
+== Formats
+
+CSV documents must have a header row, and each row is an object of the columns of the header row to the values of the row as strings. Rows are keyed by the column set with `+"`key`"+`, or the first column when it's empty.
+
+JSON documents are either an object, where each field is a row keyed by its name, or an array of objects keyed by the field set with `+"`key`"+`.`).
+		Fields(
+			service.NewStringField(ltFieldURL).
+				Description("The location of the document, which is either the path of a file, an HTTP URL or an S3 URL in the form `s3://<bucket>/<key>`.").
+				Examples("./countries.csv", "https://example.com/products.json", "s3://reference-data/customers.csv"),
+			service.NewStringEnumField(ltFieldFormat, ltFormatCSV, ltFormatJSON).
+				Description("The format of the document.").
+				Default(ltFormatCSV),
+			service.NewStringField(ltFieldKey).
+				Description("The column of CSV documents, or the field of the objects of JSON arrays, that rows are keyed by.").
+				Example("id").
+				Default(""),
+			service.NewStringMapField(ltFieldHeaders).
+				Description("A map of headers to add to requests of HTTP URLs.").
+				Example(map[string]any{"Authorization": "Bearer ${REFERENCE_DATA_TOKEN}"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewDurationField(ltFieldRefreshInterval).
+				Description("How often to reload the document when it has changed. Set to `0s` in order to never reload it.").
+				Default("5m"),
+			service.NewDurationField(ltFieldTimeout).
+				Description("The maximum period of time to wait for the document to be loaded.").
+				Default("30s").
+				Advanced(),
+			service.NewObjectField(ltFieldAWS, awsconfig.SessionFields()...).
+				Description("The AWS session used to load S3 objects.").
+				Advanced(),
+		).
+		Example("Enrich with a dimension table", "Join orders with product data stored in S3, which is refreshed every minute.", `
+cache_resources:
+  - label: products
+    lookup_table:
+      url: s3://reference-data/products.csv
+      key: sku
+      refresh_interval: 1m
+
+pipeline:
+  processors:
+    - branch:
+        processors:
+          - cache:
+              resource: products
+              operator: exists
+              key: ""
+    - mapping: |
+        root = this
+        root.product = lookup("products", this.sku)
+`)
+}
+
+func init() {
+	service.MustRegisterCache("lookup_table", lookupTableCacheSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
+			return newLookupTableFromParsed(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+// tableSource fetches a document when it has changed since the version it
+// was last fetched at, returning nil data when it hasn't.
+type tableSource interface {
+	fetch(ctx context.Context, version string) (data []byte, newVersion string, err error)
+}
+
+type lookupTable struct {
+	label           string
+	source          tableSource
+	format          string
+	key             string
+	refreshInterval time.Duration
+	timeout         time.Duration
+
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	mut     sync.RWMutex
+	rows    map[string]any
+	version string
+}
+
+func newLookupTableFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*lookupTable, error) {
+	t := &lookupTable{
+		label:   mgr.Label(),
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	rawURL, err := conf.FieldString(ltFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	if t.format, err = conf.FieldString(ltFieldFormat); err != nil {
+		return nil, err
+	}
+	if t.key, err = conf.FieldString(ltFieldKey); err != nil {
+		return nil, err
+	}
+	headers, err := conf.FieldStringMap(ltFieldHeaders)
+	if err != nil {
+		return nil, err
+	}
+	if t.refreshInterval, err = conf.FieldDuration(ltFieldRefreshInterval); err != nil {
+		return nil, err
+	}
+	if t.timeout, err = conf.FieldDuration(ltFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(rawURL, "http://"), strings.HasPrefix(rawURL, "https://"):
+		t.source = &httpSource{url: rawURL, headers: headers, client: &http.Client{}}
+	case strings.HasPrefix(rawURL, "s3://"):
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", ltFieldURL, err)
+		}
+		sess, err := rpaws.GetSession(context.Background(), conf.Namespace(ltFieldAWS))
+		if err != nil {
+			return nil, err
+		}
+		t.source = &s3Source{
+			client: s3.NewFromConfig(sess),
+			bucket: u.Host,
+			key:    strings.TrimPrefix(u.Path, "/"),
+		}
+	default:
+		t.source = &fileSource{path: rawURL}
+	}
+
+	// The table is loaded before the cache is used so that lookups never
+	// observe an empty table.
+	ctx, done := context.WithTimeout(context.Background(), t.timeout)
+	defer done()
+	if err := t.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load table: %w", err)
+	}
+
+	if t.refreshInterval > 0 {
+		go t.loop()
+	} else {
+		t.shutSig.TriggerHasStopped()
+	}
+	registerTable(t)
+	return t, nil
+}
+
+func (t *lookupTable) loop() {
+	defer t.shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(t.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-t.shutSig.SoftStopChan():
+			return
+		}
+		ctx, done := t.shutSig.SoftStopCtx(context.Background())
+		ctx, cancel := context.WithTimeout(ctx, t.timeout)
+		if err := t.refresh(ctx); err != nil && ctx.Err() == nil {
+			t.log.Errorf("Failed to reload table: %v", err)
+		}
+		cancel()
+		done()
+	}
+}
+
+// refresh loads the document when it has changed since it was last loaded.
+func (t *lookupTable) refresh(ctx context.Context) error {
+	t.mut.RLock()
+	version := t.version
+	t.mut.RUnlock()
+
+	data, newVersion, err := t.source.fetch(ctx, version)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	rows, err := parseRows(data, t.format, t.key)
+	if err != nil {
+		return err
+	}
+
+	t.mut.Lock()
+	t.rows, t.version = rows, newVersion
+	t.mut.Unlock()
+	t.log.Debugf("Loaded %v rows", len(rows))
+	return nil
+}
+
+func parseRows(data []byte, format, key string) (map[string]any, error) {
+	switch format {
+	case ltFormatCSV:
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, errors.New("CSV document must have a header row")
+		}
+
+		header, keyIndex := records[0], 0
+		if key != "" {
+			keyIndex = -1
+			for i, col := range header {
+				if col == key {
+					keyIndex = i
+				}
+			}
+			if keyIndex < 0 {
+				return nil, fmt.Errorf("key column %v was not found in the header row", key)
+			}
+		}
+
+		rows := make(map[string]any, len(records)-1)
+		for _, record := range records[1:] {
+			if keyIndex >= len(record) {
+				continue
+			}
+			row := make(map[string]any, len(header))
+			for i, col := range header {
+				if i < len(record) {
+					row[col] = record[i]
+				}
+			}
+			rows[record[keyIndex]] = row
+		}
+		return rows, nil
+
+	case ltFormatJSON:
+		var doc any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		switch v := doc.(type) {
+		case map[string]any:
+			return v, nil
+		case []any:
+			if key == "" {
+				return nil, fmt.Errorf("field %v must be set for JSON arrays", ltFieldKey)
+			}
+			rows := make(map[string]any, len(v))
+			for i, e := range v {
+				obj, ok := e.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("expected element %v to be an object, got %T", i, e)
+				}
+				if k, exists := obj[key]; exists && k != nil {
+					rows[fmt.Sprintf("%v", k)] = obj
+				}
+			}
+			return rows, nil
+		default:
+			return nil, fmt.Errorf("expected JSON document to be an object or array, got %T", doc)
+		}
+	}
+	return nil, fmt.Errorf("unknown format: %v", format)
+}
+
+// row returns the row of a key.
+func (t *lookupTable) row(key string) (any, bool) {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	v, exists := t.rows[key]
+	return v, exists
+}
+
+var errReadOnly = errors.New("lookup_table caches are read-only")
+
+func (t *lookupTable) Get(_ context.Context, key string) ([]byte, error) {
+	v, exists := t.row(key)
+	if !exists {
+		return nil, service.ErrKeyNotFound
+	}
+	return json.Marshal(v)
+}
+
+func (*lookupTable) Set(context.Context, string, []byte, *time.Duration) error {
+	return errReadOnly
+}
+
+func (*lookupTable) Add(context.Context, string, []byte, *time.Duration) error {
+	return errReadOnly
+}
+
+func (*lookupTable) Delete(context.Context, string) error {
+	return errReadOnly
+}
+
+func (t *lookupTable) Close(ctx context.Context) error {
+	unregisterTable(t)
+	t.shutSig.TriggerHardStop()
+	select {
+	case <-t.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type fileSource struct {
+	path string
+}
+
+func (f *fileSource) fetch(_ context.Context, version string) ([]byte, string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, "", err
+	}
+	newVersion := fmt.Sprintf("%v-%v", info.ModTime().UnixNano(), info.Size())
+	if newVersion == version {
+		return nil, version, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, newVersion, nil
+}
+
+type httpSource struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (h *httpSource) fetch(ctx context.Context, version string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, http.NoBody)
+	if err != nil {
+		return nil, "", err
+	}
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	if version != "" {
+		req.Header.Set("If-None-Match", version)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return nil, version, nil
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, "", fmt.Errorf("request failed with status %v: %s", res.StatusCode, data)
+	}
+	return data, res.Header.Get("ETag"), nil
+}
+
+type s3Source struct {
+	client *s3.Client
+	bucket string
+	key    string
+}
+
+func (s *s3Source) fetch(ctx context.Context, version string) ([]byte, string, error) {
+	if version != "" {
+		head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: &s.bucket,
+			Key:    &s.key,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if aws.ToString(head.ETag) == version {
+			return nil, version, nil
+		}
+	}
+
+	obj, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &s.key,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer obj.Body.Close()
+	data, err := io.ReadAll(obj.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(obj.ETag), nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func testLookupTable(t *testing.T, conf string) *lookupTable {
+	t.Helper()
+
+	pConf, err := lookupTableCacheSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	table, err := newLookupTableFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, table.Close(context.Background()))
+	})
+	return table
+}
+
+func TestLookupTableCSVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "countries.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name,code\nUnited Kingdom,GB\nFrance,FR\n"), 0o644))
+
+	table := testLookupTable(t, `
+url: `+path+`
+key: code
+refresh_interval: 0s
+`)
+
+	ctx := t.Context()
+	b, err := table.Get(ctx, "GB")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"United Kingdom","code":"GB"}`, string(b))
+
+	_, err = table.Get(ctx, "DE")
+	require.ErrorIs(t, err, service.ErrKeyNotFound)
+
+	require.ErrorIs(t, table.Set(ctx, "DE", []byte(`{}`), nil), errReadOnly)
+	require.ErrorIs(t, table.Add(ctx, "DE", []byte(`{}`), nil), errReadOnly)
+	require.ErrorIs(t, table.Delete(ctx, "GB"), errReadOnly)
+
+	// Changed files are loaded again.
+	require.NoError(t, os.WriteFile(path, []byte("name,code\nGermany,DE\n"), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, table.refresh(ctx))
+
+	b, err = table.Get(ctx, "DE")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Germany","code":"DE"}`, string(b))
+	_, err = table.Get(ctx, "GB")
+	require.ErrorIs(t, err, service.ErrKeyNotFound)
+}
+
+func TestLookupTableHTTPETag(t *testing.T) {
+	var mut sync.Mutex
+	doc, etag := `[{"sku":1,"name":"foo"},{"sku":2,"name":"bar"}]`, `"v1"`
+	var served atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer nope", r.Header.Get("Authorization"))
+		mut.Lock()
+		defer mut.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		served.Add(1)
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(doc))
+	}))
+	t.Cleanup(srv.Close)
+
+	table := testLookupTable(t, `
+url: `+srv.URL+`
+format: json
+key: sku
+headers:
+  Authorization: Bearer nope
+refresh_interval: 0s
+`)
+
+	ctx := t.Context()
+	b, err := table.Get(ctx, "2")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sku":2,"name":"bar"}`, string(b))
+
+	require.NoError(t, table.refresh(ctx))
+	assert.Equal(t, int64(1), served.Load())
+
+	mut.Lock()
+	doc, etag = `[{"sku":3,"name":"baz"}]`, `"v2"`
+	mut.Unlock()
+
+	require.NoError(t, table.refresh(ctx))
+	assert.Equal(t, int64(2), served.Load())
+	b, err = table.Get(ctx, "3")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sku":3,"name":"baz"}`, string(b))
+}
+
+func TestLookupTableParseRows(t *testing.T) {
+	rows, err := parseRows([]byte(`{"a":{"x":1},"b":"y"}`), ltFormatJSON, "")
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "y", rows["b"])
+
+	rows, err = parseRows([]byte("id,name\n1,foo\n2\n"), ltFormatCSV, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"1": map[string]any{"id": "1", "name": "foo"},
+		"2": map[string]any{"id": "2"},
+	}, rows)
+
+	_, err = parseRows([]byte(`[{"id":1}]`), ltFormatJSON, "")
+	require.ErrorContains(t, err, "must be set for JSON arrays")
+
+	_, err = parseRows([]byte("id,name\n"), ltFormatCSV, "nope")
+	require.ErrorContains(t, err, "was not found")
+
+	_, err = parseRows([]byte(`10`), ltFormatJSON, "")
+	require.Error(t, err)
+}
+
+func TestLookupFunction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "countries.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"GB":{"name":"United Kingdom"},"FR":{"name":"France"}}`), 0o644))
+
+	sb := service.NewStreamBuilder()
+	require.NoError(t, sb.AddInputYAML(`
+generate:
+  count: 2
+  interval: ""
+  mapping: |
+    root.code = if count("lookup_test") == 1 { "GB" } else { "DE" }
+`))
+	require.NoError(t, sb.AddProcessorYAML(`
+branch:
+  processors:
+    - cache:
+        resource: countries
+        operator: exists
+        key: ""
+`))
+	require.NoError(t, sb.AddProcessorYAML(`
+mapping: |
+  root = this
+  root.country = lookup("countries", this.code).or({"name": "Unknown"})
+  root.country.code = this.code
+`))
+	require.NoError(t, sb.AddResourcesYAML(`
+cache_resources:
+  - label: countries
+    lookup_table:
+      url: `+path+`
+      format: json
+`))
+
+	var mut sync.Mutex
+	var results []string
+	require.NoError(t, sb.AddConsumerFunc(func(_ context.Context, msg *service.Message) error {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		mut.Lock()
+		results = append(results, string(b))
+		mut.Unlock()
+		return nil
+	}))
+
+	stream, err := sb.Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*30)
+	defer cancel()
+	require.NoError(t, stream.Run(ctx))
+
+	require.Len(t, results, 2)
+	assert.JSONEq(t, `{"code":"GB","country":{"name":"United Kingdom","code":"GB"}}`, results[0])
+	assert.JSONEq(t, `{"code":"DE","country":{"name":"Unknown","code":"DE"}}`, results[1])
+
+	// Modifying the result of a lookup doesn't modify the table.
+	_, exists := tables.Load("countries")
+	assert.False(t, exists)
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/oschwald/geoip2-golang"
 
@@ -66,6 +67,39 @@ func registerMaxmindMethodSpec(name, entity string, fn func(*geoip2.Reader, net.
 }
 
 func init() {
+	if err := bloblang.RegisterMethodV2("geoip",
+		bloblang.NewPluginSpec().
+			Experimental().
+			Category("GeoIP").
+			Description("Looks up an IP address against a https://www.maxmind.com/en/home[MaxMind^] city or country database and an ASN database and returns an object of its location and autonomous system, in the same format as the xref:components:processors/geoip.adoc[`geoip` processor]. Fields that aren't known for the address are omitted. The database files are opened again when they are changed, which is checked at most once a minute.").
+			Param(bloblang.NewStringParam("city_database").Description("The path of a city or country database file.").Default("")).
+			Param(bloblang.NewStringParam("asn_database").Description("The path of an ASN database file.").Default("")).
+			Param(bloblang.NewStringParam("locale").Description("The locale of the names of places.").Default("en")).
+			ExampleNotTested("", `root.geo = this.ip.geoip(city_database: "/usr/share/GeoIP/GeoLite2-City.mmdb", asn_database: "/usr/share/GeoIP/GeoLite2-ASN.mmdb")`),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			cityPath, err := args.GetString("city_database")
+			if err != nil {
+				return nil, err
+			}
+			asnPath, err := args.GetString("asn_database")
+			if err != nil {
+				return nil, err
+			}
+			locale, err := args.GetString("locale")
+			if err != nil {
+				return nil, err
+			}
+			e, err := newEnricher(cityPath, asnPath, locale, time.Minute)
+			if err != nil {
+				return nil, err
+			}
+			return bloblang.StringMethod(func(s string) (any, error) {
+				return e.lookup(s)
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+
 	registerMaxmindMethodSpec("geoip_city", "city", func(db *geoip2.Reader, ip net.IP) (any, error) {
 		return db.City(ip)
 	})
@@ -0,0 +1,223 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxmind
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// reloadingDB is a database file that is opened again when it is changed,
+// checking for changes at most once per interval.
+type reloadingDB struct {
+	path     string
+	interval time.Duration
+
+	mut       sync.RWMutex
+	db        *geoip2.Reader
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+func openReloadingDB(path string, interval time.Duration) (*reloadingDB, error) {
+	r := &reloadingDB{path: path, interval: interval}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file and replaces the current database with it. Must be
+// called with the write lock held, or before the database is shared.
+func (r *reloadingDB) open() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	db, err := geoip2.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open database %v: %w", r.path, err)
+	}
+	if r.db != nil {
+		_ = r.db.Close()
+	}
+	r.db, r.modTime, r.size, r.lastCheck = db, info.ModTime(), info.Size(), time.Now()
+	return nil
+}
+
+// reloadIfChanged opens the file again when its modification time or size
+// has changed since it was last opened. When the file can't be opened the
+// current database continues to be used.
+func (r *reloadingDB) reloadIfChanged() error {
+	r.mut.RLock()
+	due := r.interval > 0 && time.Since(r.lastCheck) >= r.interval
+	r.mut.RUnlock()
+	if !due {
+		return nil
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if time.Since(r.lastCheck) < r.interval {
+		return nil
+	}
+	r.lastCheck = time.Now()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return nil
+	}
+	return r.open()
+}
+
+// read calls fn with the current database, which must not be used after fn
+// returns.
+func (r *reloadingDB) read(fn func(db *geoip2.Reader) error) error {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return fn(r.db)
+}
+
+func (r *reloadingDB) Close() error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.db == nil {
+		return nil
+	}
+	err := r.db.Close()
+	r.db = nil
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// enricher looks up IP addresses against a location database, which is
+// either a city or a country database, and an ASN database.
+type enricher struct {
+	location    *reloadingDB
+	asn         *reloadingDB
+	locale      string
+	onReloadErr func(path string, err error)
+}
+
+// lookup returns an object of the location and ASN of an IP address, which is
+// empty when the address isn't found in the databases.
+func (e *enricher) lookup(s string) (map[string]any, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil, fmt.Errorf("value %v does not appear to be a valid v4 or v6 IP address", s)
+	}
+
+	res := map[string]any{}
+	setStr := func(k, v string) {
+		if v != "" {
+			res[k] = v
+		}
+	}
+
+	if e.location != nil {
+		e.reload(e.location)
+		if err := e.location.read(func(db *geoip2.Reader) error {
+			if !strings.Contains(db.Metadata().DatabaseType, "City") {
+				country, err := db.Country(ip)
+				if err != nil {
+					return err
+				}
+				setStr("country", country.Country.Names[e.locale])
+				setStr("country_code", country.Country.IsoCode)
+				setStr("continent", country.Continent.Names[e.locale])
+				setStr("continent_code", country.Continent.Code)
+				return nil
+			}
+
+			city, err := db.City(ip)
+			if err != nil {
+				return err
+			}
+			setStr("city", city.City.Names[e.locale])
+			setStr("country", city.Country.Names[e.locale])
+			setStr("country_code", city.Country.IsoCode)
+			setStr("continent", city.Continent.Names[e.locale])
+			setStr("continent_code", city.Continent.Code)
+			if len(city.Subdivisions) > 0 {
+				setStr("subdivision", city.Subdivisions[0].Names[e.locale])
+				setStr("subdivision_code", city.Subdivisions[0].IsoCode)
+			}
+			setStr("postal_code", city.Postal.Code)
+			if city.Location.Latitude != 0 || city.Location.Longitude != 0 {
+				location := map[string]any{
+					"lat": city.Location.Latitude,
+					"lon": city.Location.Longitude,
+				}
+				if city.Location.AccuracyRadius > 0 {
+					location["accuracy_radius"] = int64(city.Location.AccuracyRadius)
+				}
+				if city.Location.TimeZone != "" {
+					location["time_zone"] = city.Location.TimeZone
+				}
+				res["location"] = location
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.asn != nil {
+		e.reload(e.asn)
+		if err := e.asn.read(func(db *geoip2.Reader) error {
+			asn, err := db.ASN(ip)
+			if err != nil {
+				return err
+			}
+			if asn.AutonomousSystemNumber > 0 {
+				res["asn"] = int64(asn.AutonomousSystemNumber)
+			}
+			setStr("as_org", asn.AutonomousSystemOrganization)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (e *enricher) reload(r *reloadingDB) {
+	err := r.reloadIfChanged()
+	if e.onReloadErr != nil && err != nil {
+		e.onReloadErr(r.path, err)
+	}
+}
+
+func (e *enricher) Close() error {
+	var errs []error
+	for _, r := range []*reloadingDB{e.location, e.asn} {
+		if r != nil {
+			errs = append(errs, r.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxmind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gpFieldIP             = "ip"
+	gpFieldCityDatabase   = "city_database"
+	gpFieldASNDatabase    = "asn_database"
+	gpFieldTargetPath     = "target_path"
+	gpFieldLocale         = "locale"
+	gpFieldReloadInterval = "reload_interval"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Enriches messages with the location and autonomous system of an IP address from local MaxMind databases.").
+		Description(`
+Looks up the IP address obtained with the `+"`ip`"+` query against a https://www.maxmind.com/en/home[MaxMind^] city or country database and an ASN database, such as the free GeoLite2 databases, and sets an object with the results at the `+"`target_path`"+` of the message:
+
+`+"```json"+`
+{
+  "city": "London",
+  "country": "United Kingdom",
+  "country_code": "GB",
+  "continent": "Europe",
+  "continent_code": "EU",
+  "subdivision": "England",
+  "subdivision_code": "ENG",
+  "postal_code": "SW1A",
+  "location": { "lat": 51.5142, "lon": -0.0931, "accuracy_radius": 10, "time_zone": "Europe/London" },
+  "asn": 1234,
+  "as_org": "Example Networks"
+}
+`+"```"+`
+
+Fields that aren't known for an address are omitted, and messages with an address that isn't found in any of the databases are left unchanged. Messages with a value that isn't an IP address are flagged as failed, and can be handled with xref:configuration:error_handling.adoc[error handling].
+
+The database files are checked for changes every `+"`reload_interval`"+`, and are opened again when they have been changed, so that databases that are updated periodically with a tool such as https://github.com/maxmind/geoipupdate[`+"`geoipupdate`"+`^] are used without restarting.
+
+The same lookup can be performed within Bloblang with the xref:guides:bloblang/methods.adoc#geoip[`+"`geoip`"+` method].`).
+		Fields(
+			service.NewBloblangField(gpFieldIP).
+				Description("A Bloblang query that returns the IP address to look up.").
+				Examples("this.client_ip", `meta("http_server_remote_ip")`),
+			service.NewStringField(gpFieldCityDatabase).
+				Description("The path of a city or country database file.").
+				Example("/usr/share/GeoIP/GeoLite2-City.mmdb").
+				Optional(),
+			service.NewStringField(gpFieldASNDatabase).
+				Description("The path of an ASN database file.").
+				Example("/usr/share/GeoIP/GeoLite2-ASN.mmdb").
+				Optional(),
+			service.NewStringField(gpFieldTargetPath).
+				Description("The dot separated path of the message to set the results at.").
+				Example("client.geo").
+				Default("geoip"),
+			service.NewStringField(gpFieldLocale).
+				Description("The locale of the names of places.").
+				Examples("de", "ja", "pt-BR").
+				Default("en").
+				Advanced(),
+			service.NewDurationField(gpFieldReloadInterval).
+				Description("How often to check the database files for changes. Set to `0s` in order to never reload them.").
+				Default("1m").
+				Advanced(),
+		).
+		LintRule(`root = if !this.exists("city_database") && !this.exists("asn_database") { [ "at least one of city_database and asn_database must be set" ] }`).
+		Example("Enrich access logs", "Add the location and network of the client of each access log.", `
+pipeline:
+  processors:
+    - geoip:
+        ip: this.remote_addr
+        city_database: /usr/share/GeoIP/GeoLite2-City.mmdb
+        asn_database: /usr/share/GeoIP/GeoLite2-ASN.mmdb
+        target_path: client.geo
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor("geoip", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newProcessorFromParsed(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	ip         *bloblang.Executor
+	targetPath string
+	enricher   *enricher
+}
+
+func newProcessorFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	p := &processor{}
+
+	var err error
+	if p.ip, err = conf.FieldBloblang(gpFieldIP); err != nil {
+		return nil, err
+	}
+	if p.targetPath, err = conf.FieldString(gpFieldTargetPath); err != nil {
+		return nil, err
+	}
+
+	var cityPath, asnPath string
+	if conf.Contains(gpFieldCityDatabase) {
+		if cityPath, err = conf.FieldString(gpFieldCityDatabase); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(gpFieldASNDatabase) {
+		if asnPath, err = conf.FieldString(gpFieldASNDatabase); err != nil {
+			return nil, err
+		}
+	}
+	locale, err := conf.FieldString(gpFieldLocale)
+	if err != nil {
+		return nil, err
+	}
+	reloadInterval, err := conf.FieldDuration(gpFieldReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	log := mgr.Logger()
+	if p.enricher, err = newEnricher(cityPath, asnPath, locale, reloadInterval); err != nil {
+		return nil, err
+	}
+	p.enricher.onReloadErr = func(path string, err error) {
+		log.Errorf("Failed to reload database %v: %v", path, err)
+	}
+	return p, nil
+}
+
+func newEnricher(cityPath, asnPath, locale string, reloadInterval time.Duration) (*enricher, error) {
+	if cityPath == "" && asnPath == "" {
+		return nil, errors.New("at least one of city_database and asn_database must be set")
+	}
+
+	e := &enricher{locale: locale}
+	var err error
+	if cityPath != "" {
+		if e.location, err = openReloadingDB(cityPath, reloadInterval); err != nil {
+			return nil, err
+		}
+	}
+	if asnPath != "" {
+		if e.asn, err = openReloadingDB(asnPath, reloadInterval); err != nil {
+			_ = e.Close()
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	ipMsg, err := msg.BloblangQuery(p.ip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %v query: %w", gpFieldIP, err)
+	}
+	ipBytes, err := ipMsg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.enricher.lookup(string(ipBytes))
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return service.MessageBatch{msg}, nil
+	}
+
+	structured, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as an object: %w", err)
+	}
+	gObj := gabs.Wrap(structured)
+	if _, err := gObj.SetP(res, p.targetPath); err != nil {
+		return nil, fmt.Errorf("failed to set %v: %w", gpFieldTargetPath, err)
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *processor) Close(context.Context) error {
+	return p.enricher.Close()
+}
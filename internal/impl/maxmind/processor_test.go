@@ -0,0 +1,176 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxmind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testProcessor(t *testing.T, conf string) *processor {
+	t.Helper()
+
+	pConf, err := processorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newProcessorFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	return p
+}
+
+func processJSON(t *testing.T, p *processor, content string) (any, error) {
+	t.Helper()
+
+	batch, err := p.Process(t.Context(), service.NewMessage([]byte(content)))
+	if err != nil {
+		return nil, err
+	}
+	require.Len(t, batch, 1)
+	return batch[0].AsStructured()
+}
+
+func TestGeoIPProcessor(t *testing.T) {
+	p := testProcessor(t, `
+ip: this.addr
+city_database: ./testdata/GeoIP2-City-Test.mmdb
+asn_database: ./testdata/GeoLite2-ASN-Test.mmdb
+target_path: client.geo
+`)
+
+	res, err := processJSON(t, p, `{"addr":"81.2.69.192","client":{"id":"foo"}}`)
+	require.NoError(t, err)
+
+	geo := res.(map[string]any)["client"].(map[string]any)["geo"].(map[string]any)
+	assert.Equal(t, "London", geo["city"])
+	assert.Equal(t, "United Kingdom", geo["country"])
+	assert.Equal(t, "GB", geo["country_code"])
+	assert.Equal(t, "EU", geo["continent_code"])
+	assert.Equal(t, "ENG", geo["subdivision_code"])
+	location := geo["location"].(map[string]any)
+	assert.InDelta(t, 51.5142, location["lat"], 0.001)
+	assert.Equal(t, "Europe/London", location["time_zone"])
+	assert.Equal(t, "foo", res.(map[string]any)["client"].(map[string]any)["id"])
+
+	res, err = processJSON(t, p, `{"addr":"214.0.0.0"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"addr": "214.0.0.0",
+		"client": map[string]any{
+			"geo": map[string]any{
+				"asn":    int64(721),
+				"as_org": "DoD Network Information Center",
+			},
+		},
+	}, res)
+
+	// Addresses that aren't found are left unchanged.
+	res, err = processJSON(t, p, `{"addr":"127.0.0.1"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"addr": "127.0.0.1"}, res)
+
+	_, err = processJSON(t, p, `{"addr":"nope"}`)
+	require.ErrorContains(t, err, "valid v4 or v6 IP address")
+}
+
+func TestGeoIPProcessorCountry(t *testing.T) {
+	p := testProcessor(t, `
+ip: this.addr
+city_database: ./testdata/GeoIP2-Country-Test.mmdb
+locale: de
+`)
+
+	res, err := processJSON(t, p, `{"addr":"2001:220::80"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"country":        "Republik Korea",
+		"country_code":   "KR",
+		"continent":      "Asien",
+		"continent_code": "AS",
+	}, res.(map[string]any)["geoip"])
+}
+
+func TestGeoIPProcessorConfig(t *testing.T) {
+	pConf, err := processorSpec().ParseYAML(`ip: this.addr`, nil)
+	require.NoError(t, err)
+	_, err = newProcessorFromParsed(pConf, service.MockResources())
+	require.ErrorContains(t, err, "at least one of")
+
+	pConf, err = processorSpec().ParseYAML(`
+ip: this.addr
+asn_database: ./testdata/nope.mmdb
+`, nil)
+	require.NoError(t, err)
+	_, err = newProcessorFromParsed(pConf, service.MockResources())
+	require.Error(t, err)
+}
+
+func TestGeoIPProcessorReload(t *testing.T) {
+	copyDB := func(from, to string) {
+		b, err := os.ReadFile(from)
+		require.NoError(t, err)
+		tmp := to + ".tmp"
+		require.NoError(t, os.WriteFile(tmp, b, 0o644))
+		require.NoError(t, os.Rename(tmp, to))
+	}
+
+	path := filepath.Join(t.TempDir(), "geo.mmdb")
+	copyDB("./testdata/GeoIP2-Country-Test.mmdb", path)
+
+	p := testProcessor(t, `
+ip: this.addr
+city_database: `+path+`
+reload_interval: 1ms
+`)
+
+	res, err := processJSON(t, p, `{"addr":"81.2.69.192"}`)
+	require.NoError(t, err)
+	assert.NotContains(t, res.(map[string]any)["geoip"], "city")
+
+	copyDB("./testdata/GeoIP2-City-Test.mmdb", path)
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	time.Sleep(time.Millisecond * 5)
+
+	res, err = processJSON(t, p, `{"addr":"81.2.69.192"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "London", res.(map[string]any)["geoip"].(map[string]any)["city"])
+}
+
+func TestGeoIPMethod(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.geoip(city_database: "./testdata/GeoIP2-City-Test.mmdb", asn_database: "./testdata/GeoLite2-ASN-Test.mmdb")`)
+	require.NoError(t, err)
+
+	res, err := exec.Query("81.2.69.192")
+	require.NoError(t, err)
+	assert.Equal(t, "London", res.(map[string]any)["city"])
+
+	res, err = exec.Query("214.0.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(721), res.(map[string]any)["asn"])
+
+	_, err = bloblang.Parse(`root = this.geoip()`)
+	require.Error(t, err)
+}
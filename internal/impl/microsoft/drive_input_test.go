@@ -0,0 +1,234 @@
+/*
+ * Copyright 2025 Redpanda Data, Inc.
+ *
+ * Licensed as a Redpanda Enterprise file under the Redpanda Community
+ * License (the "License"); you may not use this file except in compliance with
+ * the License. You may obtain a copy of the License at
+ *
+ * https://github.com/redpanda-data/redpanda/blob/master/licenses/rcl.md
+ */
+
+package microsoft
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/license"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func TestDriveInputLinting(t *testing.T) {
+	linter := service.NewEnvironment().NewComponentConfigLinter()
+
+	for _, test := range []struct {
+		name    string
+		conf    string
+		lintErr string
+	}{
+		{
+			name: "site",
+			conf: `
+microsoft_drive:
+  site_id: foo
+`,
+		},
+		{
+			name: "no target",
+			conf: `
+microsoft_drive:
+  client_id: foo
+`,
+			lintErr: "(3,1) exactly one of drive_id, site_id or user_id must be set",
+		},
+		{
+			name: "multiple targets",
+			conf: `
+microsoft_drive:
+  drive_id: foo
+  user_id: bar
+`,
+			lintErr: "(3,1) exactly one of drive_id, site_id or user_id must be set",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lints, err := linter.LintInputYAML([]byte(test.conf))
+			require.NoError(t, err)
+			if test.lintErr == "" {
+				assert.Empty(t, lints)
+				return
+			}
+			require.Len(t, lints, 1)
+			assert.Equal(t, test.lintErr, lints[0].Error())
+		})
+	}
+}
+
+func TestDriveInputSync(t *testing.T) {
+	var srvURL string
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	throttled := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sites/{site}/drive", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer foo", r.Header.Get("Authorization"))
+		assert.Equal(t, "contoso", r.PathValue("site"))
+		writeJSON(w, map[string]any{"id": "d1"})
+	})
+	mux.HandleFunc("GET /drives/d1/root/delta", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("token") {
+		case "":
+			writeJSON(w, map[string]any{
+				"@odata.nextLink": srvURL + "/drives/d1/root/delta?token=p2",
+				"value": []any{
+					map[string]any{"id": "root", "name": "root", "folder": map[string]any{}},
+					map[string]any{
+						"id":             "a",
+						"name":           "a.pdf",
+						"size":           9,
+						"file":           map[string]any{"mimeType": "application/pdf"},
+						"lastModifiedBy": map[string]any{"user": map[string]any{"displayName": "Alex", "email": "alex@contoso.com"}},
+					},
+				},
+			})
+		case "p2":
+			if !throttled {
+				throttled = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			writeJSON(w, map[string]any{
+				"@odata.deltaLink": srvURL + "/drives/d1/root/delta?token=d1",
+				"value": []any{
+					map[string]any{"id": "b", "name": "b.txt", "file": map[string]any{"mimeType": "text/plain"}},
+				},
+			})
+		case "d1":
+			writeJSON(w, map[string]any{
+				"@odata.deltaLink": srvURL + "/drives/d1/root/delta?token=d2",
+				"value": []any{
+					map[string]any{"id": "a", "name": "a.pdf", "deleted": map[string]any{"state": "deleted"}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	})
+	mux.HandleFunc("GET /drives/d1/items/{id}/content", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "b" {
+			http.Redirect(w, r, "/download/b", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("A content"))
+	})
+	mux.HandleFunc("GET /download/b", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("B content"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	srvURL = srv.URL
+
+	conf, err := driveInputConfig().ParseYAML(`
+site_id: contoso
+include_deleted: true
+poll_interval: 10ms
+cache: links
+endpoint: `+srv.URL+`
+`, nil)
+	require.NoError(t, err)
+
+	resBuilder := service.NewResourceBuilder()
+	require.NoError(t, resBuilder.AddCacheYAML(`
+label: links
+memory: {}
+`))
+	mgr, stop, err := resBuilder.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stop(t.Context()) })
+	license.InjectTestService(mgr)
+
+	in, err := newDriveInput(conf, mgr)
+	require.NoError(t, err)
+	in.tokenFn = func(context.Context) (string, error) { return "foo", nil }
+	require.NoError(t, in.Connect(t.Context()))
+
+	storedLink := func() string {
+		var b []byte
+		require.NoError(t, mgr.AccessCache(t.Context(), "links", func(c service.Cache) {
+			b, _ = c.Get(t.Context(), in.cacheKey)
+		}))
+		return string(b)
+	}
+
+	readNext := func() (*service.Message, service.AckFunc) {
+		t.Helper()
+		msg, ackFn, err := in.Read(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, msg.GetError())
+		return msg, ackFn
+	}
+
+	msg, ackA := readNext()
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "A content", string(b))
+	v, _ := msg.MetaGetMut("microsoft_drive_item_id")
+	assert.Equal(t, "a", v)
+	v, _ = msg.MetaGetMut("microsoft_drive_mime_type")
+	assert.Equal(t, "application/pdf", v)
+	v, _ = msg.MetaGetMut("microsoft_drive_size")
+	assert.Equal(t, int64(9), v)
+	v, _ = msg.MetaGetMut("microsoft_drive_modified_by")
+	assert.Equal(t, "alex@contoso.com", v)
+	v, _ = msg.MetaGetMut("microsoft_drive_id")
+	assert.Equal(t, "d1", v)
+
+	msg, ackB := readNext()
+	b, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "B content", string(b))
+	assert.True(t, throttled)
+
+	// Nothing is stored until the first page is acknowledged.
+	assert.Empty(t, storedLink())
+	require.NoError(t, ackB(t.Context(), nil))
+	assert.Empty(t, storedLink())
+	require.NoError(t, ackA(t.Context(), nil))
+	assert.Equal(t, srv.URL+"/drives/d1/root/delta?token=p2", storedLink())
+
+	msg, ackDel := readNext()
+	v, _ = msg.MetaGetMut("microsoft_drive_deleted")
+	assert.Equal(t, true, v)
+	b, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Empty(t, b)
+	assert.Equal(t, srv.URL+"/drives/d1/root/delta?token=d1", storedLink())
+	require.NoError(t, ackDel(t.Context(), nil))
+
+	// The next delta link has expired and so the drive is enumerated again.
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	_, _, err = in.Read(ctx)
+	require.ErrorIs(t, err, errResyncRequired)
+	assert.Equal(t, srv.URL+"/drives/d1/root/delta?token=d2", storedLink())
+
+	msg, _ = readNext()
+	v, _ = msg.MetaGetMut("microsoft_drive_item_id")
+	assert.Equal(t, "a", v)
+
+	require.NoError(t, in.Close(t.Context()))
+}
@@ -42,6 +42,7 @@ const (
 	fieldClientPassword      = "password"
 	fieldClientAppName       = "app_name"
 	fieldCollections         = "collections"
+	fieldOperations          = "operations"
 	fieldStreamSnapshot      = "stream_snapshot"
 	fieldSnapshotParallelism = "snapshot_parallelism"
 	fieldBucketSharding      = "snapshot_auto_bucket_sharding"
@@ -58,6 +59,10 @@ const (
 	marshalModeRelaxed   string = "relaxed"
 )
 
+// changeStreamOperations are the change stream operation types that are
+// converted into messages.
+var changeStreamOperations = []string{"insert", "update", "replace", "delete"}
+
 func spec() *service.ConfigSpec {
 	return service.NewConfigSpec().
 		Summary(`Streams changes from a MongoDB replica set.`).
@@ -87,7 +92,15 @@ Each message omitted by this plugin has the following metadata:
 				Default("").
 				Secret(),
 			service.NewStringListField(fieldCollections).
-				Description("The collections to stream changes from."),
+				Description("The collections to stream changes from. When empty the changes of all collections in the database are streamed, and the snapshot includes all collections that exist when the input starts.").
+				Default([]any{}),
+			service.NewStringListField(fieldOperations).
+				Description("The operation types to stream changes for. The filter is applied by the MongoDB server, so that changes of other operation types are never sent to Redpanda Connect. Documents from the initial snapshot are always emitted.").
+				Example([]string{"insert"}).
+				Example([]string{"insert", "replace", "update"}).
+				Default(slices.Clone(changeStreamOperations)).
+				Advanced().
+				Version("4.62.0"),
 			service.NewStringField(fieldCheckpointKey).
 				Description("Checkpoint cache key name.").
 				Default("mongodb_cdc_checkpoint"),
@@ -190,8 +203,16 @@ func newMongoCDC(conf *service.ParsedConfig, res *service.Resources) (i service.
 	if cdc.collections, err = conf.FieldStringList(fieldCollections); err != nil {
 		return
 	}
-	if len(cdc.collections) == 0 {
-		return nil, errors.New("at least one collection must be specified")
+	if cdc.operations, err = conf.FieldStringList(fieldOperations); err != nil {
+		return
+	}
+	if len(cdc.operations) == 0 {
+		return nil, fmt.Errorf("at least one operation must be specified in `%s`", fieldOperations)
+	}
+	for _, op := range cdc.operations {
+		if !slices.Contains(changeStreamOperations, op) {
+			return nil, fmt.Errorf("unknown operation in `%s`: %s, expected one of: %s", fieldOperations, op, strings.Join(changeStreamOperations, ", "))
+		}
 	}
 	var snapshotEnabled bool
 	if snapshotEnabled, err = conf.FieldBool(fieldStreamSnapshot); err != nil {
@@ -319,6 +340,7 @@ type mongoCDC struct {
 	client      *mongo.Client
 	db          *mongo.Database
 	collections []string
+	operations  []string
 	logger      *service.Logger
 
 	shutsig   *shutdown.Signaller
@@ -437,8 +459,16 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 		}()
 		cp := checkpoint.NewCapped[bson.Raw](int64(m.checkpointLimit))
 		if m.resumeToken == nil {
+			collections, err := m.snapshotCollections(ctx)
+			if err != nil {
+				select {
+				case m.errorChan <- fmt.Errorf("error listing MongoDB collections: %w", err):
+				default:
+				}
+				return
+			}
 			g, gctx := errgroup.WithContext(ctx)
-			for _, name := range m.collections {
+			for _, name := range collections {
 				coll := m.db.Collection(name)
 				g.Go(func() error { return m.readSnapshot(gctx, coll, ts, cp) })
 			}
@@ -473,6 +503,34 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 	return nil
 }
 
+// snapshotCollections returns the collections to read during the snapshot,
+// which is every collection of the database if none were configured.
+func (m *mongoCDC) snapshotCollections(ctx context.Context) ([]string, error) {
+	if len(m.collections) > 0 {
+		return m.collections, nil
+	}
+	names, err := m.db.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(names, func(name string) bool {
+		return strings.HasPrefix(name, "system.")
+	}), nil
+}
+
+// changeStreamFilter returns the pipeline that filters the change stream
+// to the configured collections and operation types. Invalidate events are
+// always included so that the stream can be restarted.
+func (m *mongoCDC) changeStreamFilter() []bson.M {
+	match := bson.M{
+		"operationType": bson.M{"$in": append(slices.Clone(m.operations), "invalidate")},
+	}
+	if len(m.collections) > 0 {
+		match["ns.coll"] = bson.M{"$in": slices.Clone(m.collections)}
+	}
+	return []bson.M{{"$match": match}}
+}
+
 func (m *mongoCDC) readSnapshot(
 	ctx context.Context,
 	coll *mongo.Collection,
@@ -670,10 +728,7 @@ func (m *mongoCDC) readSnapshotRange(
 }
 
 func (m *mongoCDC) readFromStream(ctx context.Context, cp *checkpoint.Capped[bson.Raw], opts *options.ChangeStreamOptionsBuilder) error {
-	filter := []bson.M{{"$match": bson.M{
-		"ns.coll": bson.M{"$in": slices.Clone(m.collections)},
-	}}}
-	stream, err := m.db.Watch(ctx, filter, opts)
+	stream, err := m.db.Watch(ctx, m.changeStreamFilter(), opts)
 	if err != nil {
 		return fmt.Errorf("error opening change stream: %w", err)
 	}
@@ -647,6 +647,49 @@ mongodb_cdc:
 	}, metas[3:6])
 }
 
+func TestIntegrationMongoCDCAllCollectionsFilteredOperations(t *testing.T) {
+	stream, db, output := setup(t, `
+mongodb_cdc:
+  url: '$URI'
+  database: '$DATABASE'
+  stream_snapshot: true
+  checkpoint_cache: '$CACHE'
+  json_marshal_mode: relaxed
+  operations:
+    - 'insert'
+`)
+	db.CreateCollection(t, "foo")
+	db.CreateCollection(t, "bar")
+	db.InsertOne(t, "foo", bson.M{"_id": 1, "data": "hello"})
+	db.InsertOne(t, "bar", bson.M{"_id": 2, "data": "world"})
+	wait := stream.RunAsync(t)
+	time.Sleep(time.Second)
+	db.UpdateOne(t, "foo", 1, bson.M{"$set": bson.M{"data": "updated"}})
+	db.DeleteByID(t, "bar", 2)
+	db.InsertOne(t, "foo", bson.M{"_id": 3, "data": "hello"})
+	db.InsertOne(t, "qux", bson.M{"_id": 4, "data": "!"})
+	time.Sleep(time.Second)
+	stream.Stop(t)
+	wait()
+	msgs := output.Messages(t)
+	metas := output.Metadata(t)
+	require.Len(t, msgs, 4)
+	require.Len(t, metas, 4)
+	// Snapshots can be processed in any order
+	require.ElementsMatch(t, []map[string]any{
+		{"operation": "read", "collection": "foo", "operation_time": "$timestamp"},
+		{"operation": "read", "collection": "bar", "operation_time": "$timestamp"},
+	}, metas[0:2])
+	require.Equal(t, []any{
+		map[string]any{"_id": json.Number("3"), "data": "hello"},
+		map[string]any{"_id": json.Number("4"), "data": "!"},
+	}, msgs[2:4])
+	require.Equal(t, []map[string]any{
+		{"operation": "insert", "collection": "foo", "operation_time": "$timestamp"},
+		{"operation": "insert", "collection": "qux", "operation_time": "$timestamp"},
+	}, metas[2:4])
+}
+
 func TestIntegrationMongoPartialUpdates(t *testing.T) {
 	stream, db, output := setup(t, `
 mongodb_cdc:
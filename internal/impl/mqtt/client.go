@@ -42,6 +42,12 @@ const (
 	msFieldClientPassword          = "password"
 	msFieldClientKeepAlive         = "keepalive"
 	msFieldClientTLS               = "tls"
+	msFieldClientProtocolVersion   = "protocol_version"
+)
+
+const (
+	protocolVersion311 = "3.1.1"
+	protocolVersion5   = "5"
 )
 
 func clientFields() []*service.ConfigField {
@@ -97,6 +103,14 @@ func clientFields() []*service.ConfigField {
 			Default(30).
 			Advanced(),
 		service.NewTLSToggledField(msFieldClientTLS),
+		service.NewStringAnnotatedEnumField(msFieldClientProtocolVersion, map[string]string{
+			protocolVersion311: "MQTT 3.1.1.",
+			protocolVersion5:   "MQTT 5, which enables shared subscriptions, user properties, message expiry and topic aliases.",
+		}).
+			Description("The version of the MQTT protocol to connect with.").
+			Default(protocolVersion311).
+			Advanced().
+			Version("4.62.0"),
 	}
 }
 
@@ -110,6 +124,8 @@ type clientOptsBuilder struct {
 	tlsEnabled     bool
 	tlsConf        *tls.Config
 	will           willOpt
+
+	protocolVersion string
 }
 
 func clientOptsFromParsed(conf *service.ParsedConfig) (opts clientOptsBuilder, err error) {
@@ -156,6 +172,9 @@ func clientOptsFromParsed(conf *service.ParsedConfig) (opts clientOptsBuilder, e
 	if opts.tlsConf, opts.tlsEnabled, err = conf.FieldTLSToggled(msFieldClientTLS); err != nil {
 		return
 	}
+	if opts.protocolVersion, err = conf.FieldString(msFieldClientProtocolVersion); err != nil {
+		return
+	}
 	return
 }
 
@@ -0,0 +1,118 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// autopahoConfig creates an MQTT 5 client config from the common client
+// options. Reconnects are attempted by the client itself for as long as the
+// connection manager is running.
+func (b *clientOptsBuilder) autopahoConfig(log *service.Logger) autopaho.ClientConfig {
+	conf := autopaho.ClientConfig{
+		ServerUrls:       b.urls,
+		KeepAlive:        uint16(b.keepAlive),
+		ConnectTimeout:   b.connectTimeout,
+		ReconnectBackoff: autopaho.NewExponentialBackoff(time.Second, time.Minute, 2*time.Second, 2),
+		OnConnectError: func(err error) {
+			log.Errorf("Failed to connect: %v", err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: b.clientID,
+			OnClientError: func(err error) {
+				log.Errorf("Connection lost due to: %v", err)
+			},
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				if d.Properties != nil && d.Properties.ReasonString != "" {
+					log.Errorf("Disconnected by server: %v", d.Properties.ReasonString)
+				} else {
+					log.Errorf("Disconnected by server with reason code: %v", d.ReasonCode)
+				}
+			},
+		},
+	}
+	if b.tlsEnabled {
+		conf.TlsCfg = b.tlsConf
+	}
+	if b.username != "" {
+		conf.ConnectUsername = b.username
+	}
+	if b.password != "" {
+		conf.ConnectPassword = []byte(b.password)
+	}
+	if b.will.Enabled {
+		conf.WillMessage = &paho.WillMessage{
+			Retain:  b.will.Retained,
+			QoS:     b.will.QoS,
+			Topic:   b.will.Topic,
+			Payload: []byte(b.will.Payload),
+		}
+	}
+	return conf
+}
+
+// connectV5 starts a connection manager and waits for the initial connection
+// to be established.
+func (b *clientOptsBuilder) connectV5(ctx context.Context, conf autopaho.ClientConfig) (*autopaho.ConnectionManager, error) {
+	cm, err := autopaho.NewConnection(context.Background(), conf)
+	if err != nil {
+		return nil, err
+	}
+
+	awaitCtx, done := context.WithTimeout(ctx, b.connectTimeout)
+	defer done()
+
+	if err := cm.AwaitConnection(awaitCtx); err != nil {
+		_ = cm.Disconnect(context.Background())
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out waiting for connection to %v", b.urls)
+		}
+		return nil, err
+	}
+	return cm, nil
+}
+
+// addPublishMetadata adds the MQTT 5 properties of a publish packet to a
+// message, user properties are added with their key as is.
+func addPublishMetadata(msg *service.Message, props *paho.PublishProperties) {
+	if props == nil {
+		return
+	}
+	for _, p := range props.User {
+		msg.MetaSetMut(p.Key, p.Value)
+	}
+	if props.ContentType != "" {
+		msg.MetaSetMut("mqtt_content_type", props.ContentType)
+	}
+	if props.ResponseTopic != "" {
+		msg.MetaSetMut("mqtt_response_topic", props.ResponseTopic)
+	}
+	if len(props.CorrelationData) > 0 {
+		msg.MetaSetMut("mqtt_correlation_data", string(props.CorrelationData))
+	}
+	if props.MessageExpiry != nil {
+		msg.MetaSetMut("mqtt_message_expiry", strconv.FormatUint(uint64(*props.MessageExpiry), 10))
+	}
+}
@@ -46,11 +46,24 @@ This input adds the following metadata fields to each message:
 - mqtt_topic
 - mqtt_message_id
 
-You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+When `+"`protocol_version`"+` is `+"`5`"+` the following metadata fields are also added when the respective properties are present, along with all user properties of the message:
+
+- mqtt_content_type
+- mqtt_response_topic
+- mqtt_correlation_data
+- mqtt_message_expiry
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+== Shared subscriptions
+
+With MQTT 5 the messages of a topic can be load balanced across a group of consumers by subscribing to a shared subscription of the form `+"`$share/<group>/<topic filter>`"+`, where each message is delivered to only one member of the group.`).
 		Fields(clientFields()...).
 		Fields(
 			service.NewStringListField(miFieldTopics).
-				Description("A list of topics to consume from."),
+				Description("A list of topics to consume from.").
+				Example([]string{"sensors/+/temperature"}).
+				Example([]string{"$share/connect/sensors/#"}),
 			service.NewIntField(miFieldQoS).
 				Description("The level of delivery guarantee to enforce. Has options 0, 1, 2.").
 				Advanced().
@@ -65,7 +78,17 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 
 func init() {
 	service.MustRegisterInput("mqtt", inputConfigSpec(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
-		rdr, err := newMQTTReaderFromParsed(conf, mgr)
+		version, err := conf.FieldString(msFieldClientProtocolVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		var rdr service.Input
+		if version == protocolVersion5 {
+			rdr, err = newMQTTV5ReaderFromParsed(conf, mgr)
+		} else {
+			rdr, err = newMQTTReaderFromParsed(conf, mgr)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,185 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type mqttV5Reader struct {
+	clientBuilder clientOptsBuilder
+	topics        []string
+	qos           uint8
+	cleanSession  bool
+
+	cm      *autopaho.ConnectionManager
+	msgChan chan paho.PublishReceived
+	cMut    sync.Mutex
+
+	interruptChan chan struct{}
+	interruptOnce sync.Once
+
+	log *service.Logger
+}
+
+func newMQTTV5ReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*mqttV5Reader, error) {
+	m := &mqttV5Reader{
+		interruptChan: make(chan struct{}),
+		log:           mgr.Logger(),
+	}
+
+	var err error
+	if m.clientBuilder, err = clientOptsFromParsed(conf); err != nil {
+		return nil, err
+	}
+
+	if m.topics, err = conf.FieldStringList(miFieldTopics); err != nil {
+		return nil, err
+	}
+	var tmpQoS int
+	if tmpQoS, err = conf.FieldInt(miFieldQoS); err != nil {
+		return nil, err
+	}
+	m.qos = uint8(tmpQoS)
+	if m.cleanSession, err = conf.FieldBool(miFieldCleanSession); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *mqttV5Reader) Connect(ctx context.Context) error {
+	m.cMut.Lock()
+	defer m.cMut.Unlock()
+
+	if m.cm != nil {
+		return nil
+	}
+
+	msgChan := make(chan paho.PublishReceived)
+
+	conf := m.clientBuilder.autopahoConfig(m.log)
+	conf.CleanStartOnInitialConnection = m.cleanSession
+	if !m.cleanSession {
+		// A session expiry of zero would end the session along with the
+		// network connection, so keep it for as long as possible instead.
+		conf.SessionExpiryInterval = math.MaxUint32
+	}
+	conf.EnableManualAcknowledgment = true
+	conf.OnPublishReceived = []func(paho.PublishReceived) (bool, error){
+		func(pr paho.PublishReceived) (bool, error) {
+			select {
+			case msgChan <- pr:
+			case <-m.interruptChan:
+			}
+			return true, nil
+		},
+	}
+	conf.OnConnectionUp = func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+		sub := &paho.Subscribe{}
+		for _, topic := range m.topics {
+			sub.Subscriptions = append(sub.Subscriptions, paho.SubscribeOptions{
+				Topic: topic,
+				QoS:   m.qos,
+			})
+		}
+
+		ctx, done := context.WithTimeout(context.Background(), m.clientBuilder.connectTimeout)
+		defer done()
+
+		suback, err := cm.Subscribe(ctx, sub)
+		if err != nil {
+			m.log.Errorf("Failed to subscribe to topics '%v': %v", m.topics, err)
+			return
+		}
+		for i, code := range suback.Reasons {
+			if code >= 0x80 && i < len(m.topics) {
+				m.log.Errorf("Failed to subscribe to topic '%v': reason code %v", m.topics[i], code)
+			}
+		}
+	}
+
+	cm, err := m.clientBuilder.connectV5(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	m.cm = cm
+	m.msgChan = msgChan
+	return nil
+}
+
+func (m *mqttV5Reader) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	m.cMut.Lock()
+	cm, msgChan := m.cm, m.msgChan
+	m.cMut.Unlock()
+
+	if msgChan == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case pr := <-msgChan:
+		pub := pr.Packet
+
+		message := service.NewMessage(pub.Payload)
+
+		addPublishMetadata(message, pub.Properties)
+		message.MetaSetMut("mqtt_duplicate", pub.Duplicate())
+		message.MetaSetMut("mqtt_qos", int(pub.QoS))
+		message.MetaSetMut("mqtt_retained", pub.Retain)
+		message.MetaSetMut("mqtt_topic", pub.Topic)
+		message.MetaSetMut("mqtt_message_id", int(pub.PacketID))
+
+		return message, func(_ context.Context, res error) error {
+			if res == nil {
+				return pr.Client.Ack(pub)
+			}
+			return nil
+		}, nil
+	case <-cm.Done():
+		m.cMut.Lock()
+		m.msgChan = nil
+		m.cm = nil
+		m.cMut.Unlock()
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-m.interruptChan:
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (m *mqttV5Reader) Close(ctx context.Context) (err error) {
+	m.cMut.Lock()
+	defer m.cMut.Unlock()
+
+	m.interruptOnce.Do(func() {
+		close(m.interruptChan)
+	})
+	if m.cm != nil {
+		err = m.cm.Disconnect(ctx)
+		m.cm = nil
+		m.msgChan = nil
+	}
+	return
+}
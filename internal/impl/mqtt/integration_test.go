@@ -109,3 +109,83 @@ input:
 		)
 	})
 }
+
+func TestIntegrationMQTTV5(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "eclipse-mosquitto",
+		Tag:        "2",
+		Cmd:        []string{"mosquitto", "-c", "/mosquitto-no-auth.conf"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		inConf := mqtt.NewClientOptions().SetClientID("UNIT_TEST")
+		inConf = inConf.AddBroker(fmt.Sprintf("tcp://localhost:%v", resource.GetPort("1883/tcp")))
+
+		mIn := mqtt.NewClient(inConf)
+		tok := mIn.Connect()
+		tok.Wait()
+		if cErr := tok.Error(); cErr != nil {
+			return cErr
+		}
+		mIn.Disconnect(0)
+		return nil
+	}))
+
+	template := `
+output:
+  mqtt:
+    urls: [ tcp://localhost:$PORT ]
+    protocol_version: "5"
+    qos: 1
+    topic: topic-$ID
+    client_id: client-output-$ID
+    max_in_flight: $MAX_IN_FLIGHT
+    topic_alias_maximum: 10
+    message_expiry: 1m
+    user_properties:
+      source: integration-test
+
+input:
+  mqtt:
+    urls: [ tcp://localhost:$PORT ]
+    protocol_version: "5"
+    topics: [ $VAR1topic-$ID ]
+    client_id: client-input-$ID
+    clean_session: false
+`
+	suite := integration.StreamTests(
+		integration.StreamTestOpenClose(),
+		integration.StreamTestSendBatch(10),
+		integration.StreamTestStreamParallel(1000),
+	)
+	suite.Run(
+		t, template,
+		integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+		integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+		integration.StreamTestOptPort(resource.GetPort("1883/tcp")),
+		integration.StreamTestOptVarSet("VAR1", ""),
+	)
+	t.Run("with shared subscription", func(t *testing.T) {
+		t.Parallel()
+		suite.Run(
+			t, template,
+			integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+			integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+			integration.StreamTestOptPort(resource.GetPort("1883/tcp")),
+			integration.StreamTestOptMaxInFlight(10),
+			integration.StreamTestOptVarSet("VAR1", "$share/group/"),
+		)
+	})
+}
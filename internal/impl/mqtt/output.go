@@ -32,6 +32,10 @@ const (
 	moFieldWriteTimeout         = "write_timeout"
 	moFieldRetained             = "retained"
 	moFieldRetainedInterpolated = "retained_interpolated"
+	moFieldUserProperties       = "user_properties"
+	moFieldContentType          = "content_type"
+	moFieldMessageExpiry        = "message_expiry"
+	moFieldTopicAliasMaximum    = "topic_alias_maximum"
 )
 
 func outputConfigSpec() *service.ConfigSpec {
@@ -40,7 +44,9 @@ func outputConfigSpec() *service.ConfigSpec {
 		Categories("Services").
 		Summary("Pushes messages to an MQTT broker.").
 		Description(`
-The `+"`topic`"+` field can be dynamically set using function interpolations described xref:configuration:interpolation.adoc#bloblang-queries[here]. When sending batched messages these interpolations are performed per message part.`+service.OutputPerformanceDocs(true, false)).
+The `+"`topic`"+` field can be dynamically set using function interpolations described xref:configuration:interpolation.adoc#bloblang-queries[here]. When sending batched messages these interpolations are performed per message part.
+
+The fields `+"`user_properties`, `content_type`, `message_expiry` and `topic_alias_maximum`"+` require `+"`protocol_version`"+` to be set to `+"`5`"+`.`+service.OutputPerformanceDocs(true, false)).
 		Fields(clientFields()...).
 		Fields(
 			service.NewInterpolatedStringField(moFieldTopic).
@@ -61,8 +67,32 @@ The `+"`topic`"+` field can be dynamically set using function interpolations des
 				Advanced().
 				Optional().
 				Version("3.59.0"),
+			service.NewInterpolatedStringMapField(moFieldUserProperties).
+				Description("A map of MQTT 5 user properties to add to each message.").
+				Example(map[string]any{"source": "redpanda-connect", "kafka_key": "${! @kafka_key }"}).
+				Default(map[string]any{}).
+				Advanced().
+				Version("4.62.0"),
+			service.NewInterpolatedStringField(moFieldContentType).
+				Description("An MQTT 5 content type to set on each message.").
+				Example("application/json").
+				Optional().
+				Advanced().
+				Version("4.62.0"),
+			service.NewDurationField(moFieldMessageExpiry).
+				Description("An MQTT 5 message expiry interval, after which the broker discards the message if it has not yet been delivered to a subscriber. Must be at least one second.").
+				Example("1m").
+				Optional().
+				Advanced().
+				Version("4.62.0"),
+			service.NewIntField(moFieldTopicAliasMaximum).
+				Description("The maximum number of MQTT 5 topic aliases to establish per connection, limited further by the maximum the broker accepts. Topic aliases reduce the size of messages sent to the same topics repeatedly. Set to `0` to disable topic aliases.").
+				Default(0).
+				Advanced().
+				Version("4.62.0"),
 			service.NewOutputMaxInFlightField(),
-		)
+		).
+		LintRule(`root = if this.protocol_version.or("3.1.1") != "5" && (this.user_properties.or({}).length() > 0 || this.exists("content_type") || this.exists("message_expiry") || this.topic_alias_maximum.or(0) > 0) { [ "user_properties, content_type, message_expiry and topic_alias_maximum require protocol_version 5" ] }`)
 }
 
 func init() {
@@ -70,7 +100,15 @@ func init() {
 		if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
 			return
 		}
-		out, err = newMQTTWriterFromParsed(conf, mgr)
+		var version string
+		if version, err = conf.FieldString(msFieldClientProtocolVersion); err != nil {
+			return
+		}
+		if version == protocolVersion5 {
+			out, err = newMQTTV5WriterFromParsed(conf, mgr)
+		} else {
+			out, err = newMQTTWriterFromParsed(conf, mgr)
+		}
 		return
 	})
 }
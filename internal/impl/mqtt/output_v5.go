@@ -0,0 +1,245 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// topicAliases tracks the topic aliases assigned during a single connection,
+// aliases are assigned on first use until the maximum is reached.
+type topicAliases struct {
+	mut     sync.Mutex
+	max     uint16
+	aliases map[string]uint16
+}
+
+func (t *topicAliases) reset(max uint16) {
+	t.mut.Lock()
+	t.max = max
+	t.aliases = map[string]uint16{}
+	t.mut.Unlock()
+}
+
+// apply sets the topic alias of a publish, omitting the topic when an alias
+// has already been established for it.
+func (t *topicAliases) apply(p *paho.Publish) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if alias, exists := t.aliases[p.Topic]; exists {
+		p.Properties.TopicAlias = paho.Uint16(alias)
+		p.Topic = ""
+		return
+	}
+	if len(t.aliases) < int(t.max) {
+		alias := uint16(len(t.aliases) + 1)
+		t.aliases[p.Topic] = alias
+		p.Properties.TopicAlias = paho.Uint16(alias)
+	}
+}
+
+type mqttV5Writer struct {
+	log *service.Logger
+
+	clientBuilder clientOptsBuilder
+
+	writeTimeout      time.Duration
+	topic             *service.InterpolatedString
+	retained          bool
+	retainedInterp    *service.InterpolatedString
+	qos               uint8
+	userProperties    map[string]*service.InterpolatedString
+	contentType       *service.InterpolatedString
+	messageExpiry     *uint32
+	topicAliasMaximum uint16
+
+	aliases topicAliases
+
+	cm      *autopaho.ConnectionManager
+	connMut sync.RWMutex
+}
+
+func newMQTTV5WriterFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*mqttV5Writer, error) {
+	m := &mqttV5Writer{
+		log: mgr.Logger(),
+	}
+
+	var err error
+	if m.clientBuilder, err = clientOptsFromParsed(conf); err != nil {
+		return nil, err
+	}
+
+	if m.writeTimeout, err = conf.FieldDuration(moFieldWriteTimeout); err != nil {
+		return nil, err
+	}
+	if m.topic, err = conf.FieldInterpolatedString(moFieldTopic); err != nil {
+		return nil, err
+	}
+	if m.retained, err = conf.FieldBool(moFieldRetained); err != nil {
+		return nil, err
+	}
+	if iStrp, _ := conf.FieldString(moFieldRetainedInterpolated); iStrp != "" {
+		if m.retainedInterp, err = conf.FieldInterpolatedString(moFieldRetainedInterpolated); err != nil {
+			return nil, err
+		}
+	}
+	var tmpQoS int
+	if tmpQoS, err = conf.FieldInt(moFieldQoS); err != nil {
+		return nil, err
+	}
+	m.qos = uint8(tmpQoS)
+
+	if m.userProperties, err = conf.FieldInterpolatedStringMap(moFieldUserProperties); err != nil {
+		return nil, err
+	}
+	if conf.Contains(moFieldContentType) {
+		if m.contentType, err = conf.FieldInterpolatedString(moFieldContentType); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(moFieldMessageExpiry) {
+		expiry, err := conf.FieldDuration(moFieldMessageExpiry)
+		if err != nil {
+			return nil, err
+		}
+		if expiry < time.Second {
+			return nil, fmt.Errorf("%v must be at least one second", moFieldMessageExpiry)
+		}
+		m.messageExpiry = paho.Uint32(uint32(expiry / time.Second))
+	}
+	var tmpAliasMax int
+	if tmpAliasMax, err = conf.FieldInt(moFieldTopicAliasMaximum); err != nil {
+		return nil, err
+	}
+	if tmpAliasMax < 0 || tmpAliasMax > 65535 {
+		return nil, fmt.Errorf("%v must be between 0 and 65535", moFieldTopicAliasMaximum)
+	}
+	m.topicAliasMaximum = uint16(tmpAliasMax)
+	return m, nil
+}
+
+func (m *mqttV5Writer) Connect(ctx context.Context) error {
+	m.connMut.Lock()
+	defer m.connMut.Unlock()
+
+	if m.cm != nil {
+		return nil
+	}
+
+	conf := m.clientBuilder.autopahoConfig(m.log)
+	conf.OnConnectionUp = func(_ *autopaho.ConnectionManager, connack *paho.Connack) {
+		// Topic aliases are scoped to a network connection and therefore
+		// reset on every (re)connect, limited by what the server accepts.
+		var serverMax uint16
+		if connack.Properties != nil && connack.Properties.TopicAliasMaximum != nil {
+			serverMax = *connack.Properties.TopicAliasMaximum
+		}
+		m.aliases.reset(min(m.topicAliasMaximum, serverMax))
+	}
+
+	cm, err := m.clientBuilder.connectV5(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	m.cm = cm
+	return nil
+}
+
+func (m *mqttV5Writer) Write(ctx context.Context, msg *service.Message) error {
+	m.connMut.RLock()
+	cm := m.cm
+	m.connMut.RUnlock()
+
+	if cm == nil {
+		return service.ErrNotConnected
+	}
+
+	retained := m.retained
+	if m.retainedInterp != nil {
+		retainedStr, parseErr := m.retainedInterp.TryString(msg)
+		if parseErr != nil {
+			m.log.Errorf("Retained interpolation error: %v", parseErr)
+		} else if retained, parseErr = strconv.ParseBool(retainedStr); parseErr != nil {
+			m.log.Errorf("Error parsing boolean value from retained flag: %v \n", parseErr)
+		}
+	}
+
+	topicStr, err := m.topic.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("topic interpolation error: %w", err)
+	}
+
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	props := &paho.PublishProperties{
+		MessageExpiry: m.messageExpiry,
+	}
+	if m.contentType != nil {
+		if props.ContentType, err = m.contentType.TryString(msg); err != nil {
+			return fmt.Errorf("content type interpolation error: %w", err)
+		}
+	}
+	for k, v := range m.userProperties {
+		value, err := v.TryString(msg)
+		if err != nil {
+			return fmt.Errorf("user property %v interpolation error: %w", k, err)
+		}
+		props.User.Add(k, value)
+	}
+
+	pub := &paho.Publish{
+		QoS:        m.qos,
+		Retain:     retained,
+		Topic:      topicStr,
+		Payload:    mBytes,
+		Properties: props,
+	}
+	m.aliases.apply(pub)
+
+	ctx, done := context.WithTimeout(ctx, m.writeTimeout)
+	defer done()
+
+	_, err = cm.Publish(ctx, pub)
+	if errors.Is(err, autopaho.ConnectionDownError) {
+		return service.ErrNotConnected
+	}
+	return err
+}
+
+func (m *mqttV5Writer) Close(ctx context.Context) (err error) {
+	m.connMut.Lock()
+	defer m.connMut.Unlock()
+
+	if m.cm != nil {
+		err = m.cm.Disconnect(ctx)
+		m.cm = nil
+	}
+	return
+}
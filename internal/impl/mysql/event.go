@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-mysql-org/go-mysql/mysql"
 )
@@ -35,9 +36,67 @@ const (
 // MessageEvent represents a message from mysql cdc plugin
 type MessageEvent struct {
 	Row       map[string]any   `json:"row"`
+	Before    map[string]any   `json:"before"`
 	Table     string           `json:"table"`
 	Operation MessageOperation `json:"operation"`
 	Position  *position        `json:"position"`
+	// GTIDSet is the set of transactions fully committed before this event,
+	// it is only populated when checkpointing with GTIDs.
+	GTIDSet string `json:"gtid_set"`
+	// GTID is the identifier of the transaction this event belongs to.
+	GTID      string    `json:"gtid"`
+	ServerID  uint32    `json:"server_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var debeziumOperations = map[MessageOperation]string{
+	MessageOperationRead:   "r",
+	MessageOperationInsert: "c",
+	MessageOperationUpdate: "u",
+	MessageOperationDelete: "d",
+}
+
+// debeziumEnvelope converts an event into the value of a Debezium MySQL
+// connector change event, with the schema omitted.
+func debeziumEnvelope(me MessageEvent, db string, now time.Time) map[string]any {
+	var before, after any
+	switch me.Operation {
+	case MessageOperationDelete:
+		before = me.Row
+	case MessageOperationUpdate:
+		before, after = me.Before, me.Row
+	default:
+		after = me.Row
+	}
+
+	source := map[string]any{
+		"connector": "mysql",
+		"ts_ms":     me.Timestamp.UnixMilli(),
+		"snapshot":  "false",
+		"db":        db,
+		"table":     me.Table,
+		"server_id": me.ServerID,
+		"file":      "",
+		"pos":       0,
+	}
+	if me.Operation == MessageOperationRead {
+		source["snapshot"] = "true"
+	}
+	if me.Position != nil {
+		source["file"] = me.Position.Name
+		source["pos"] = me.Position.Pos
+	}
+	if me.GTID != "" {
+		source["gtid"] = me.GTID
+	}
+
+	return map[string]any{
+		"before": before,
+		"after":  after,
+		"source": source,
+		"op":     debeziumOperations[me.Operation],
+		"ts_ms":  now.UnixMilli(),
+	}
 }
 
 func binlogPositionToString(pos position) string {
@@ -12,6 +12,7 @@ import (
 	"math"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -39,3 +40,60 @@ func TestBinlogString(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestDebeziumEnvelope(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	now := time.Unix(1700000001, 0)
+
+	update := debeziumEnvelope(MessageEvent{
+		Row:       map[string]any{"id": 1, "name": "bar"},
+		Before:    map[string]any{"id": 1, "name": "foo"},
+		Table:     "users",
+		Operation: MessageOperationUpdate,
+		Position:  &position{Name: "mysql-bin.000003", Pos: 154},
+		GTID:      "3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
+		ServerID:  1,
+		Timestamp: ts,
+	}, "inventory", now)
+	require.Equal(t, map[string]any{
+		"before": map[string]any{"id": 1, "name": "foo"},
+		"after":  map[string]any{"id": 1, "name": "bar"},
+		"source": map[string]any{
+			"connector": "mysql",
+			"ts_ms":     int64(1700000000000),
+			"snapshot":  "false",
+			"db":        "inventory",
+			"table":     "users",
+			"server_id": uint32(1),
+			"file":      "mysql-bin.000003",
+			"pos":       uint32(154),
+			"gtid":      "3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
+		},
+		"op":    "u",
+		"ts_ms": int64(1700000001000),
+	}, update)
+
+	del := debeziumEnvelope(MessageEvent{
+		Row:       map[string]any{"id": 1},
+		Table:     "users",
+		Operation: MessageOperationDelete,
+		Position:  &position{Name: "mysql-bin.000003", Pos: 200},
+		Timestamp: ts,
+	}, "inventory", now)
+	require.Equal(t, map[string]any{"id": 1}, del["before"])
+	require.Nil(t, del["after"])
+	require.Equal(t, "d", del["op"])
+
+	read := debeziumEnvelope(MessageEvent{
+		Row:       map[string]any{"id": 1},
+		Table:     "users",
+		Operation: MessageOperationRead,
+		Timestamp: ts,
+	}, "inventory", now)
+	require.Nil(t, read["before"])
+	require.Equal(t, map[string]any{"id": 1}, read["after"])
+	require.Equal(t, "r", read["op"])
+	source := read["source"].(map[string]any)
+	require.Equal(t, "true", source["snapshot"])
+	require.NotContains(t, source, "gtid")
+}
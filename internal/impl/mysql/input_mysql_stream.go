@@ -43,6 +43,14 @@ const (
 	fieldCheckpointKey        = "checkpoint_key"
 	fieldCheckpointCache      = "checkpoint_cache"
 	fieldCheckpointLimit      = "checkpoint_limit"
+	fieldCheckpointMode       = "checkpoint_mode"
+	fieldMessageFormat        = "message_format"
+
+	checkpointModeBinlogPosition = "binlog_position"
+	checkpointModeGTID           = "gtid"
+
+	messageFormatRow      = "row"
+	messageFormatDebezium = "debezium"
 
 	shutdownTimeout = 5 * time.Second
 )
@@ -60,6 +68,15 @@ This input adds the following metadata fields to each message:
 - operation
 - table
 - binlog_position
+- gtid_set (when `+"`"+fieldCheckpointMode+"`"+` is `+"`gtid`"+`)
+
+== Schema tracking
+
+The schema of each table is tracked as DDL statements are read from the binlog, so that rows are always decoded using the columns of the table at the time the change was made.
+
+== Debezium envelopes
+
+When `+"`"+fieldMessageFormat+"`"+` is `+"`debezium`"+` each message is the value of a Debezium MySQL connector change event (without the schema), containing the fields `+"`before`, `after`, `source`, `op` and `ts_ms`"+`. This allows existing consumers of Debezium topics to process the output of this input without changes.
 `).
 	Fields(
 		service.NewStringAnnotatedEnumField(fieldMySQLFlavor, map[string]string{
@@ -90,6 +107,21 @@ This input adds the following metadata fields to each message:
 			Description("The maximum number of messages that can be processed at a given time. Increasing this limit enables parallel processing and batching at the output level. Any given BinLog Position will not be acknowledged unless all messages under that offset are delivered in order to preserve at least once delivery guarantees.").
 			Default(1024),
 		service.NewBatchPolicyField(fieldBatching),
+		service.NewStringAnnotatedEnumField(fieldCheckpointMode, map[string]string{
+			checkpointModeBinlogPosition: "Checkpoint the binlog file name and offset. Positions are specific to a single server and are invalidated by a failover.",
+			checkpointModeGTID:           "Checkpoint the set of executed global transaction identifiers, which allows the stream to resume from any server of a replication topology after a failover. Requires GTIDs to be enabled on the server.",
+		}).
+			Description("The type of position stored in `"+fieldCheckpointCache+"` to resume streaming from. Changing this field requires a new `"+fieldCheckpointKey+"` as the stored positions are not compatible.").
+			Advanced().
+			Default(checkpointModeBinlogPosition).
+			Version("4.62.0"),
+		service.NewStringAnnotatedEnumField(fieldMessageFormat, map[string]string{
+			messageFormatRow:      "Emit the columns of the changed row as a JSON object, for updates this is the row after the change.",
+			messageFormatDebezium: "Emit a Debezium compatible change event envelope with the row before and after the change.",
+		}).
+			Description("The format of the messages emitted by this input.").
+			Default(messageFormatRow).
+			Version("4.62.0"),
 	)
 
 type asyncMessage struct {
@@ -108,6 +140,10 @@ type mysqlStreamInput struct {
 	binLogCache       string
 	binLogCacheKey    string
 	currentBinlogName string
+	currentGTIDSet    string
+	currentGTID       string
+	checkpointMode    string
+	messageFormat     string
 
 	dsn            string
 	tables         []string
@@ -123,7 +159,7 @@ type mysqlStreamInput struct {
 
 	rawMessageEvents chan MessageEvent
 	msgChan          chan asyncMessage
-	cp               *checkpoint.Capped[*position]
+	cp               *checkpoint.Capped[*string]
 
 	shutSig *shutdown.Signaller
 }
@@ -186,7 +222,14 @@ func newMySQLStreamInput(conf *service.ParsedConfig, res *service.Resources) (s
 		return nil, err
 	}
 
-	i.cp = checkpoint.NewCapped[*position](int64(i.checkPointLimit))
+	if i.checkpointMode, err = conf.FieldString(fieldCheckpointMode); err != nil {
+		return nil, err
+	}
+	if i.messageFormat, err = conf.FieldString(fieldMessageFormat); err != nil {
+		return nil, err
+	}
+
+	i.cp = checkpoint.NewCapped[*string](int64(i.checkPointLimit))
 
 	for _, table := range i.tables {
 		if err = validateTableName(table); err != nil {
@@ -254,18 +297,38 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 
 	i.canal = c
 
-	pos, err := i.getCachedBinlogPosition(ctx)
+	cached, err := i.getCachedCheckpoint(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to get cached binlog position: %s", err)
+		return fmt.Errorf("unable to get cached checkpoint: %s", err)
+	}
+	var (
+		pos     *position
+		gtidSet gomysql.GTIDSet
+	)
+	if cached != nil {
+		if i.checkpointMode == checkpointModeGTID {
+			if gtidSet, err = gomysql.ParseGTIDSet(i.flavor, *cached); err != nil {
+				return fmt.Errorf("unable to parse cached gtid set: %w", err)
+			}
+		} else {
+			p, err := parseBinlogPosition(*cached)
+			if err != nil {
+				return err
+			}
+			pos = &p
+		}
 	}
 	// create snapshot instance if we were requested and haven't finished it before.
 	var snapshot *Snapshot
-	if i.streamSnapshot && pos == nil {
+	if i.streamSnapshot && cached == nil {
 		db, err := sql.Open("mysql", i.dsn)
 		if err != nil {
 			return fmt.Errorf("failed to connect to MySQL server: %s", err)
 		}
 		snapshot = NewSnapshot(i.logger, db)
+		if i.checkpointMode == checkpointModeGTID {
+			snapshot.gtidFlavor = i.flavor
+		}
 	}
 
 	// Reset the shutSig
@@ -280,7 +343,12 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 			return nil
 		})
 		wg.Go(func() error { return i.readMessages(ctx) })
-		wg.Go(func() error { return i.startMySQLSync(ctx, pos, snapshot) })
+		wg.Go(func() error {
+			if i.checkpointMode == checkpointModeGTID {
+				return i.startMySQLSyncFromGTID(ctx, gtidSet, snapshot)
+			}
+			return i.startMySQLSync(ctx, pos, snapshot)
+		})
 		if err := wg.Wait(); err != nil && !errors.Is(err, context.Canceled) {
 			i.logger.Errorf("error during MySQL CDC: %s", err)
 		} else {
@@ -292,24 +360,32 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 	return nil
 }
 
+func (i *mysqlStreamInput) runSnapshot(ctx context.Context, snapshot *Snapshot) (*position, error) {
+	startPos, err := snapshot.prepareSnapshot(ctx, i.tables)
+	if err != nil {
+		_ = snapshot.close()
+		return nil, fmt.Errorf("unable to prepare snapshot: %w", err)
+	}
+	if err = i.readSnapshot(ctx, snapshot); err != nil {
+		_ = snapshot.close()
+		return nil, fmt.Errorf("failed reading snapshot: %w", err)
+	}
+	if err = snapshot.releaseSnapshot(ctx); err != nil {
+		_ = snapshot.close()
+		return nil, fmt.Errorf("unable to release snapshot: %w", err)
+	}
+	if err = snapshot.close(); err != nil {
+		return nil, fmt.Errorf("unable to close snapshot: %w", err)
+	}
+	return startPos, nil
+}
+
 func (i *mysqlStreamInput) startMySQLSync(ctx context.Context, pos *position, snapshot *Snapshot) error {
 	// If we are given a snapshot, then we need to read it.
 	if snapshot != nil {
-		startPos, err := snapshot.prepareSnapshot(ctx, i.tables)
+		startPos, err := i.runSnapshot(ctx, snapshot)
 		if err != nil {
-			_ = snapshot.close()
-			return fmt.Errorf("unable to prepare snapshot: %w", err)
-		}
-		if err = i.readSnapshot(ctx, snapshot); err != nil {
-			_ = snapshot.close()
-			return fmt.Errorf("failed reading snapshot: %w", err)
-		}
-		if err = snapshot.releaseSnapshot(ctx); err != nil {
-			_ = snapshot.close()
-			return fmt.Errorf("unable to release snapshot: %w", err)
-		}
-		if err = snapshot.close(); err != nil {
-			return fmt.Errorf("unable to close snapshot: %w", err)
+			return err
 		}
 		pos = startPos
 	} else if pos == nil {
@@ -328,6 +404,29 @@ func (i *mysqlStreamInput) startMySQLSync(ctx context.Context, pos *position, sn
 	return nil
 }
 
+func (i *mysqlStreamInput) startMySQLSyncFromGTID(ctx context.Context, gtidSet gomysql.GTIDSet, snapshot *Snapshot) error {
+	var err error
+	if snapshot != nil {
+		if _, err = i.runSnapshot(ctx, snapshot); err != nil {
+			return err
+		}
+		if gtidSet, err = gomysql.ParseGTIDSet(i.flavor, snapshot.gtidSet); err != nil {
+			return fmt.Errorf("unable to parse snapshot gtid set: %w", err)
+		}
+	} else if gtidSet == nil {
+		if gtidSet, err = i.canal.GetMasterGTIDSet(); err != nil {
+			return fmt.Errorf("unable to get start gtid set: %w", err)
+		}
+	}
+	i.logger.Infof("starting MySQL CDC stream from gtid set %s", gtidSet.String())
+	i.currentGTIDSet = gtidSet.String()
+	i.canal.SetEventHandler(i)
+	if err := i.canal.StartFromGTID(gtidSet); err != nil {
+		return fmt.Errorf("failed to start streaming: %w", err)
+	}
+	return nil
+}
+
 func (i *mysqlStreamInput) readSnapshot(ctx context.Context, snapshot *Snapshot) error {
 	// TODO(cdc): Process tables in parallel
 	for _, table := range i.tables {
@@ -392,6 +491,7 @@ func (i *mysqlStreamInput) readSnapshot(ctx context.Context, snapshot *Snapshot)
 					Operation: MessageOperationRead,
 					Table:     table,
 					Position:  nil,
+					Timestamp: time.Now(),
 				}:
 				case <-ctx.Done():
 					return ctx.Err()
@@ -518,7 +618,11 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 				return fmt.Errorf("failed to flush periodic batch: %w", err)
 			}
 		case me := <-i.rawMessageEvents:
-			row, err := json.Marshal(me.Row)
+			var value any = me.Row
+			if i.messageFormat == messageFormatDebezium {
+				value = debeziumEnvelope(me, i.mysqlConfig.DBName, time.Now())
+			}
+			row, err := json.Marshal(value)
 			if err != nil {
 				return fmt.Errorf("failed to serialize row: %w", err)
 			}
@@ -529,6 +633,9 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 			if me.Position != nil {
 				mb.MetaSet("binlog_position", binlogPositionToString(*me.Position))
 			}
+			if me.GTIDSet != "" {
+				mb.MetaSet("gtid_set", me.GTIDSet)
+			}
 
 			if i.batchPolicy.Add(mb) {
 				nextTimedBatchChan = nil
@@ -551,25 +658,25 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 
 func (i *mysqlStreamInput) flushBatch(
 	ctx context.Context,
-	checkpointer *checkpoint.Capped[*position],
+	checkpointer *checkpoint.Capped[*string],
 	batch service.MessageBatch,
 ) error {
 	if len(batch) == 0 {
 		return nil
 	}
 
+	checkpointMetaKey := "binlog_position"
+	if i.checkpointMode == checkpointModeGTID {
+		checkpointMetaKey = "gtid_set"
+	}
+
 	lastMsg := batch[len(batch)-1]
-	strPosition, ok := lastMsg.MetaGet("binlog_position")
-	var binLogPos *position
-	if ok {
-		pos, err := parseBinlogPosition(strPosition)
-		if err != nil {
-			return err
-		}
-		binLogPos = &pos
+	var cpValue *string
+	if v, ok := lastMsg.MetaGet(checkpointMetaKey); ok {
+		cpValue = &v
 	}
 
-	resolveFn, err := checkpointer.Track(ctx, binLogPos, int64(len(batch)))
+	resolveFn, err := checkpointer.Track(ctx, cpValue, int64(len(batch)))
 	if err != nil {
 		return fmt.Errorf("failed to track checkpoint for batch: %w", err)
 	}
@@ -588,7 +695,7 @@ func (i *mysqlStreamInput) flushBatch(
 			if offset == nil {
 				return nil
 			}
-			return i.setCachedBinlogPosition(ctx, *offset)
+			return i.setCachedCheckpoint(ctx, *offset)
 		},
 	}
 	select {
@@ -634,7 +741,7 @@ func (i *mysqlStreamInput) Close(ctx context.Context) error {
 
 // ---- cache methods start ----
 
-func (i *mysqlStreamInput) getCachedBinlogPosition(ctx context.Context) (*position, error) {
+func (i *mysqlStreamInput) getCachedCheckpoint(ctx context.Context) (*string, error) {
 	var (
 		cacheVal []byte
 		cErr     error
@@ -651,17 +758,17 @@ func (i *mysqlStreamInput) getCachedBinlogPosition(ctx context.Context) (*positi
 	} else if cacheVal == nil {
 		return nil, nil
 	}
-	pos, err := parseBinlogPosition(string(cacheVal))
-	return &pos, err
+	cp := string(cacheVal)
+	return &cp, nil
 }
 
-func (i *mysqlStreamInput) setCachedBinlogPosition(ctx context.Context, binLogPos position) error {
+func (i *mysqlStreamInput) setCachedCheckpoint(ctx context.Context, cp string) error {
 	var cErr error
 	if err := i.res.AccessCache(ctx, i.binLogCache, func(c service.Cache) {
 		cErr = c.Set(
 			ctx,
 			i.binLogCacheKey,
-			[]byte(binlogPositionToString(binLogPos)),
+			[]byte(cp),
 			nil,
 		)
 	}); err != nil {
@@ -682,6 +789,25 @@ func (i *mysqlStreamInput) OnRotate(_ *replication.EventHeader, re *replication.
 	return nil
 }
 
+func (i *mysqlStreamInput) OnGTID(_ *replication.EventHeader, e gomysql.BinlogGTIDEvent) error {
+	next, err := e.GTIDNext()
+	if err != nil {
+		return err
+	}
+	i.currentGTID = next.String()
+	return nil
+}
+
+func (i *mysqlStreamInput) OnPosSynced(_ *replication.EventHeader, _ gomysql.Position, set gomysql.GTIDSet, _ bool) error {
+	// The synced set only includes transactions that have been committed,
+	// therefore events of a partially delivered transaction are replayed
+	// after a restart.
+	if i.checkpointMode == checkpointModeGTID && set != nil {
+		i.currentGTIDSet = set.String()
+	}
+	return nil
+}
+
 func (i *mysqlStreamInput) OnRow(e *canal.RowsEvent) error {
 	switch e.Action {
 	case canal.InsertAction:
@@ -689,7 +815,8 @@ func (i *mysqlStreamInput) OnRow(e *canal.RowsEvent) error {
 	case canal.DeleteAction:
 		return i.onMessage(e, 0, 1)
 	case canal.UpdateAction:
-		// Updates send both the new and old data - we only emit the new data.
+		// Updates send both the old and new data, the new data is emitted with
+		// the old data as the before image.
 		return i.onMessage(e, 1, 2)
 	default:
 		return errors.New("invalid rows action")
@@ -698,25 +825,47 @@ func (i *mysqlStreamInput) OnRow(e *canal.RowsEvent) error {
 
 func (i *mysqlStreamInput) onMessage(e *canal.RowsEvent, initValue, incrementValue int) error {
 	for pi := initValue; pi < len(e.Rows); pi += incrementValue {
-		message := map[string]any{}
-		for i, v := range e.Rows[pi] {
-			col := e.Table.Columns[i]
-			v, err := mapMessageColumn(v, col)
-			if err != nil {
+		message, err := mapMessageRow(e.Rows[pi], e.Table.Columns)
+		if err != nil {
+			return err
+		}
+		var before map[string]any
+		if e.Action == canal.UpdateAction {
+			if before, err = mapMessageRow(e.Rows[pi-1], e.Table.Columns); err != nil {
 				return err
 			}
-			message[col.Name] = v
 		}
-		i.rawMessageEvents <- MessageEvent{
+		me := MessageEvent{
 			Row:       message,
+			Before:    before,
 			Operation: MessageOperation(e.Action),
 			Table:     e.Table.Name,
 			Position:  &position{Name: i.currentBinlogName, Pos: e.Header.LogPos},
+			GTID:      i.currentGTID,
+			ServerID:  e.Header.ServerID,
+			Timestamp: time.Unix(int64(e.Header.Timestamp), 0),
+		}
+		if i.checkpointMode == checkpointModeGTID {
+			me.GTIDSet = i.currentGTIDSet
 		}
+		i.rawMessageEvents <- me
 	}
 	return nil
 }
 
+func mapMessageRow(row []any, cols []schema.TableColumn) (map[string]any, error) {
+	message := map[string]any{}
+	for i, v := range row {
+		col := cols[i]
+		v, err := mapMessageColumn(v, col)
+		if err != nil {
+			return nil, err
+		}
+		message[col.Name] = v
+	}
+	return message, nil
+}
+
 func mapMessageColumn(v any, col schema.TableColumn) (any, error) {
 	if v == nil {
 		return v, nil
@@ -11,6 +11,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -46,7 +47,7 @@ func (db *testDB) Exec(query string, args ...any) {
 	require.NoError(db.t, err)
 }
 
-func setupTestWithMySQLVersion(t *testing.T, version string) (string, *testDB) {
+func setupTestWithMySQLVersion(t *testing.T, version string, extraArgs ...string) (string, *testDB) {
 	t.Parallel()
 	integration.CheckSkip(t)
 	pool, err := dockertest.NewPool("")
@@ -62,13 +63,13 @@ func setupTestWithMySQLVersion(t *testing.T, version string) (string, *testDB) {
 			"MYSQL_ROOT_PASSWORD=password",
 			"MYSQL_DATABASE=testdb",
 		},
-		Cmd: []string{
+		Cmd: append([]string{
 			"--server-id=1",
 			"--log-bin=mysql-bin",
 			"--binlog-format=ROW",
 			"--binlog-row-image=FULL",
 			"--log-slave-updates=ON",
-		},
+		}, extraArgs...),
 		ExposedPorts: []string{"3306/tcp"},
 	}, func(config *docker.HostConfig) {
 		// set AutoRemove to true so that stopped container goes away by itself
@@ -214,6 +215,108 @@ file:
 	}
 }
 
+func TestIntegrationMySQLCDCGTIDDebezium(t *testing.T) {
+	dsn, db := setupTestWithMySQLVersion(t, "8.0", "--gtid-mode=ON", "--enforce-gtid-consistency=ON")
+	db.Exec(`
+    CREATE TABLE IF NOT EXISTS foo (
+        a INT PRIMARY KEY,
+        b VARCHAR(32)
+    )
+`)
+	for i := range 10 {
+		db.Exec("INSERT INTO foo VALUES (?, ?)", i, "snapshot")
+	}
+
+	template := fmt.Sprintf(`
+mysql_cdc:
+  dsn: %s
+  stream_snapshot: true
+  checkpoint_cache: foocache
+  checkpoint_mode: gtid
+  message_format: debezium
+  tables:
+    - foo
+`, dsn)
+
+	cacheConf := fmt.Sprintf(`
+label: foocache
+file:
+  directory: %s`, t.TempDir())
+
+	var outMsgs []map[string]any
+	var outMut sync.Mutex
+	runStream := func() *service.Stream {
+		streamOutBuilder := service.NewStreamBuilder()
+		require.NoError(t, streamOutBuilder.SetLoggerYAML(`level: INFO`))
+		require.NoError(t, streamOutBuilder.AddCacheYAML(cacheConf))
+		require.NoError(t, streamOutBuilder.AddInputYAML(template))
+		require.NoError(t, streamOutBuilder.AddBatchConsumerFunc(func(_ context.Context, mb service.MessageBatch) error {
+			for _, msg := range mb {
+				_, hasGTIDSet := msg.MetaGet("gtid_set")
+				operation, _ := msg.MetaGet("operation")
+				assert.Equal(t, operation != "read", hasGTIDSet)
+
+				structured, err := msg.AsStructured()
+				require.NoError(t, err)
+				outMut.Lock()
+				outMsgs = append(outMsgs, structured.(map[string]any))
+				outMut.Unlock()
+			}
+			return nil
+		}))
+
+		streamOut, err := streamOutBuilder.Build()
+		require.NoError(t, err)
+		license.InjectTestService(streamOut.Resources())
+
+		go func() {
+			err := streamOut.Run(t.Context())
+			require.NoError(t, err)
+		}()
+		return streamOut
+	}
+
+	countMsgs := func(n int) func() bool {
+		return func() bool {
+			outMut.Lock()
+			defer outMut.Unlock()
+			return len(outMsgs) == n
+		}
+	}
+
+	streamOut := runStream()
+	assert.Eventually(t, countMsgs(10), time.Minute, time.Millisecond*100)
+	db.Exec("UPDATE foo SET b = ? WHERE a = ?", "updated", 1)
+	db.Exec("DELETE FROM foo WHERE a = ?", 2)
+	assert.Eventually(t, countMsgs(12), time.Minute, time.Millisecond*100)
+
+	outMut.Lock()
+	assert.Equal(t, "r", outMsgs[0]["op"])
+	update := outMsgs[10]
+	assert.Equal(t, "u", update["op"])
+	assert.Equal(t, map[string]any{"a": json.Number("1"), "b": "snapshot"}, update["before"])
+	assert.Equal(t, map[string]any{"a": json.Number("1"), "b": "updated"}, update["after"])
+	assert.Contains(t, update["source"], "gtid")
+	assert.Equal(t, "d", outMsgs[11]["op"])
+	assert.Nil(t, outMsgs[11]["after"])
+	outMsgs = nil
+	outMut.Unlock()
+
+	require.NoError(t, streamOut.StopWithin(time.Second*10))
+
+	// Changes made while the stream is stopped are resumed from the
+	// checkpointed gtid set without taking another snapshot.
+	db.Exec("INSERT INTO foo VALUES (?, ?)", 100, "resumed")
+
+	streamOut = runStream()
+	assert.Eventually(t, countMsgs(1), time.Minute, time.Millisecond*100)
+	outMut.Lock()
+	assert.Equal(t, "c", outMsgs[0]["op"])
+	assert.Equal(t, map[string]any{"a": json.Number("100"), "b": "resumed"}, outMsgs[0]["after"])
+	outMut.Unlock()
+	require.NoError(t, streamOut.StopWithin(time.Second*10))
+}
+
 func TestIntegrationMySQLSnapshotAndCDC(t *testing.T) {
 	dsn, db := setupTestWithMySQLVersion(t, "8.0")
 	// Create table
@@ -15,6 +15,8 @@ import (
 	"fmt"
 	"strings"
 
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
@@ -27,6 +29,11 @@ type Snapshot struct {
 	lockConn     *sql.Conn
 	snapshotConn *sql.Conn
 
+	// gtidFlavor is set when the executed GTID set at the point of the
+	// snapshot should be captured into gtidSet along with the binlog position.
+	gtidFlavor string
+	gtidSet    string
+
 	logger *service.Logger
 }
 
@@ -121,6 +128,15 @@ func (s *Snapshot) prepareSnapshot(ctx context.Context, tables []string) (*posit
 			s.tx.Rollback())
 	}
 
+	if s.gtidFlavor != "" {
+		if s.gtidSet, err = s.getCurrentGTIDSet(ctx); err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("get executed gtid set: %w", err),
+				unlockTables(),
+				s.tx.Rollback())
+		}
+	}
+
 	// Release the table locks immediately after getting the binlog position
 	if _, err := s.lockConn.ExecContext(ctx, "UNLOCK TABLES"); err != nil {
 		return nil, errors.Join(
@@ -239,6 +255,19 @@ func (s *Snapshot) getCurrentBinlogPosition(ctx context.Context) (position, erro
 	}, nil
 }
 
+func (s *Snapshot) getCurrentGTIDSet(ctx context.Context) (string, error) {
+	query := "SELECT @@GLOBAL.gtid_executed"
+	if s.gtidFlavor == gomysql.MariaDBFlavor {
+		query = "SELECT @@GLOBAL.gtid_binlog_pos"
+	}
+
+	var gtidSet string
+	if err := s.snapshotConn.QueryRowContext(ctx, query).Scan(&gtidSet); err != nil {
+		return "", err
+	}
+	return gtidSet, nil
+}
+
 func (s *Snapshot) releaseSnapshot(_ context.Context) error {
 	if s.tx != nil {
 		if err := s.tx.Commit(); err != nil {
@@ -19,7 +19,8 @@ import (
 )
 
 const (
-	kvFieldBucket = "bucket"
+	kvFieldBucket          = "bucket"
+	objectStoreFieldBucket = "bucket"
 )
 
 const (
@@ -60,3 +61,15 @@ func kvDocs(extraFields ...*service.ConfigField) []*service.ConfigField {
 
 	return fields
 }
+
+func objectStoreDocs(extraFields ...*service.ConfigField) []*service.ConfigField {
+	fields := append(
+		connectionHeadFields(),
+		service.NewStringField(objectStoreFieldBucket).
+			Description("The name of the object store bucket.").Example("my_object_store"),
+	)
+	fields = append(fields, extraFields...)
+	fields = append(fields, connectionTailFields()...)
+
+	return fields
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	osiFieldIgnoreDeletes = "ignore_deletes"
+	osiFieldUpdatesOnly   = "updates_only"
+	osiFieldMetaOnly      = "meta_only"
+)
+
+func natsObjectStoreInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Watches for updates in a NATS object store bucket and reads the updated objects.").
+		Description(`
+Each object added to or updated within the bucket is emitted as a message containing the contents of the object. Deleted objects are emitted as empty messages with the metadata field ` + "`nats_object_store_deleted`" + ` set to ` + "`true`" + `, unless ` + "`ignore_deletes`" + ` is enabled.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+` + objectStoreMetadataDescription() + `
+
+` + connectionNameDescription() + authDescription()).
+		Fields(objectStoreDocs(
+			service.NewAutoRetryNacksToggleField(),
+			service.NewBoolField(osiFieldIgnoreDeletes).
+				Description("Do not send delete markers as messages.").
+				Default(false).
+				Advanced(),
+			service.NewBoolField(osiFieldUpdatesOnly).
+				Description("Only emit objects updated after the input connects, rather than first emitting all existing objects.").
+				Default(false).
+				Advanced(),
+			service.NewBoolField(osiFieldMetaOnly).
+				Description("Emit only the metadata of objects, leaving the message contents empty.").
+				Default(false).
+				Advanced(),
+		)...)
+}
+
+func init() {
+	service.MustRegisterInput(
+		"nats_object_store", natsObjectStoreInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			reader, err := newObjectStoreReader(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, reader)
+		},
+	)
+}
+
+type objectStoreReader struct {
+	connDetails   connectionDetails
+	bucket        string
+	ignoreDeletes bool
+	updatesOnly   bool
+	metaOnly      bool
+
+	log *service.Logger
+
+	shutSig *shutdown.Signaller
+
+	connMut     sync.Mutex
+	natsConn    *nats.Conn
+	objectStore jetstream.ObjectStore
+	watcher     jetstream.ObjectWatcher
+}
+
+func newObjectStoreReader(conf *service.ParsedConfig, mgr *service.Resources) (*objectStoreReader, error) {
+	r := &objectStoreReader{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+	if r.connDetails, err = connectionDetailsFromParsed(conf, mgr); err != nil {
+		return nil, err
+	}
+
+	if r.bucket, err = conf.FieldString(objectStoreFieldBucket); err != nil {
+		return nil, err
+	}
+
+	if r.ignoreDeletes, err = conf.FieldBool(osiFieldIgnoreDeletes); err != nil {
+		return nil, err
+	}
+
+	if r.updatesOnly, err = conf.FieldBool(osiFieldUpdatesOnly); err != nil {
+		return nil, err
+	}
+
+	if r.metaOnly, err = conf.FieldBool(osiFieldMetaOnly); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *objectStoreReader) Connect(ctx context.Context) (err error) {
+	r.connMut.Lock()
+	defer r.connMut.Unlock()
+
+	if r.natsConn != nil {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			if r.watcher != nil {
+				_ = r.watcher.Stop()
+				r.watcher = nil
+			}
+			if r.natsConn != nil {
+				r.natsConn.Close()
+				r.natsConn = nil
+			}
+		}
+	}()
+
+	if r.natsConn, err = r.connDetails.get(ctx); err != nil {
+		return err
+	}
+
+	js, err := jetstream.New(r.natsConn)
+	if err != nil {
+		return err
+	}
+
+	if r.objectStore, err = js.ObjectStore(ctx, r.bucket); err != nil {
+		return err
+	}
+
+	var watchOpts []jetstream.WatchOpt
+	if r.ignoreDeletes {
+		watchOpts = append(watchOpts, jetstream.IgnoreDeletes())
+	}
+	if r.updatesOnly {
+		watchOpts = append(watchOpts, jetstream.UpdatesOnly())
+	}
+
+	// The watcher is bound to the lifetime of the connection rather than the
+	// context of this call.
+	r.watcher, err = r.objectStore.Watch(context.Background(), watchOpts...)
+	return err
+}
+
+func (r *objectStoreReader) disconnect() {
+	r.connMut.Lock()
+	defer r.connMut.Unlock()
+
+	if r.watcher != nil {
+		_ = r.watcher.Stop()
+		r.watcher = nil
+	}
+	if r.natsConn != nil {
+		r.natsConn.Close()
+		r.natsConn = nil
+	}
+	r.objectStore = nil
+}
+
+func (r *objectStoreReader) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	r.connMut.Lock()
+	watcher, objectStore := r.watcher, r.objectStore
+	r.connMut.Unlock()
+
+	if watcher == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	for {
+		var info *jetstream.ObjectInfo
+		var open bool
+		select {
+		case info, open = <-watcher.Updates():
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+
+		if !open {
+			r.disconnect()
+			return nil, nil, service.ErrNotConnected
+		}
+
+		// A nil entry marks the end of the initial objects.
+		if info == nil {
+			continue
+		}
+
+		r.log.With(
+			metaObjectStoreBucket, info.Bucket,
+			metaObjectStoreName, info.Name,
+			metaObjectStoreDeleted, info.Deleted,
+		).Debugf("Received object store update")
+
+		var data []byte
+		if !info.Deleted && !r.metaOnly {
+			var err error
+			if data, err = objectStore.GetBytes(ctx, info.Name); err != nil {
+				if errors.Is(err, jetstream.ErrObjectNotFound) {
+					// The object was deleted in the meantime, which is
+					// followed by an update of its own.
+					continue
+				}
+				return nil, nil, err
+			}
+		}
+
+		return newMessageFromObjectInfo(info, data), func(context.Context, error) error {
+			return nil
+		}, nil
+	}
+}
+
+func (r *objectStoreReader) Close(ctx context.Context) error {
+	go func() {
+		r.disconnect()
+		r.shutSig.TriggerHasStopped()
+	}()
+	select {
+	case <-r.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
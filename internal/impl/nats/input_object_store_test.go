@@ -0,0 +1,72 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestInputObjectStoreParse(t *testing.T) {
+	spec := natsObjectStoreInputConfig()
+	env := service.NewEnvironment()
+
+	conf, err := spec.ParseYAML(`
+urls: [ url1, url2 ]
+bucket: testbucket
+ignore_deletes: true
+updates_only: true
+meta_only: true
+`, env)
+	require.NoError(t, err)
+
+	e, err := newObjectStoreReader(conf, service.MockResources())
+	require.NoError(t, err)
+
+	assert.Equal(t, "url1,url2", e.connDetails.urls)
+	assert.Equal(t, "testbucket", e.bucket)
+	assert.True(t, e.ignoreDeletes)
+	assert.True(t, e.updatesOnly)
+	assert.True(t, e.metaOnly)
+}
+
+func TestOutputObjectStoreParse(t *testing.T) {
+	spec := natsObjectStoreOutputConfig()
+	env := service.NewEnvironment()
+
+	conf, err := spec.ParseYAML(`
+urls: [ url1 ]
+bucket: testbucket
+object_name: ${! @key }.json
+headers:
+  Content-Type: application/json
+`, env)
+	require.NoError(t, err)
+
+	o, err := newObjectStoreOutput(conf, service.MockResources())
+	require.NoError(t, err)
+
+	msg := service.NewMessage(nil)
+	msg.MetaSetMut("key", "foo")
+
+	name, err := o.objectName.TryString(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "foo.json", name)
+	assert.Contains(t, o.headers, "Content-Type")
+}
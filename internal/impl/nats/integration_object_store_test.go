@@ -0,0 +1,146 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/redpanda-data/benthos/v4/public/service/integration"
+)
+
+func TestIntegrationNatsObjectStore(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "nats",
+		Tag:        "latest",
+		Cmd:        []string{"--js", "--trace"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	var natsConn *nats.Conn
+	_ = resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		natsConn, err = nats.Connect(fmt.Sprintf("tcp://localhost:%v", resource.GetPort("4222/tcp")))
+		return err
+	}))
+	t.Cleanup(func() {
+		natsConn.Close()
+	})
+
+	template := `
+output:
+  label: object_store_output
+  nats_object_store:
+    urls: [ tcp://localhost:$PORT ]
+    bucket: bucket-$ID
+    object_name: ${! uuid_v4() }
+
+input:
+  label: object_store_input
+  nats_object_store:
+    urls: [ tcp://localhost:$PORT ]
+    bucket: bucket-$ID
+`
+	suite := integration.StreamTests(
+		integration.StreamTestOpenClose(),
+		integration.StreamTestSendBatch(10),
+		integration.StreamTestStreamSequential(100),
+		integration.StreamTestStreamParallel(100),
+	)
+	suite.Run(
+		t, template,
+		integration.StreamTestOptPreTest(func(t testing.TB, _ context.Context, vars *integration.StreamTestConfigVars) {
+			js, err := jetstream.New(natsConn)
+			require.NoError(t, err)
+
+			_, err = js.CreateObjectStore(t.Context(), jetstream.ObjectStoreConfig{
+				Bucket: "bucket-" + vars.ID,
+			})
+			require.NoError(t, err)
+		}),
+		integration.StreamTestOptSleepAfterInput(100*time.Millisecond),
+		integration.StreamTestOptSleepAfterOutput(100*time.Millisecond),
+		integration.StreamTestOptPort(resource.GetPort("4222/tcp")),
+	)
+
+	t.Run("processor", func(t *testing.T) {
+		js, err := jetstream.New(natsConn)
+		require.NoError(t, err)
+
+		store, err := js.CreateObjectStore(t.Context(), jetstream.ObjectStoreConfig{
+			Bucket: "processor-bucket",
+		})
+		require.NoError(t, err)
+
+		process := func(operation string, msg *service.Message) (service.MessageBatch, error) {
+			parsed, err := natsObjectStoreProcessorConfig().ParseYAML(fmt.Sprintf(`
+urls: [ tcp://localhost:%v ]
+bucket: processor-bucket
+operation: %v
+object_name: blob
+`, resource.GetPort("4222/tcp"), operation), nil)
+			require.NoError(t, err)
+
+			p, err := newObjectStoreProcessor(parsed, service.MockResources())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = p.Close(context.Background()) })
+
+			return p.Process(t.Context(), msg)
+		}
+
+		_, err = process("put", service.NewMessage([]byte("lawblog")))
+		require.NoError(t, err)
+
+		data, err := store.GetBytes(t.Context(), "blob")
+		require.NoError(t, err)
+		assert.Equal(t, "lawblog", string(data))
+
+		result, err := process("get", service.NewMessage(nil))
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+
+		b, err := result[0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "lawblog", string(b))
+
+		name, _ := result[0].MetaGet(metaObjectStoreName)
+		assert.Equal(t, "blob", name)
+
+		_, err = process("delete", service.NewMessage(nil))
+		require.NoError(t, err)
+
+		_, err = store.GetBytes(t.Context(), "blob")
+		require.ErrorIs(t, err, jetstream.ErrObjectNotFound)
+	})
+}
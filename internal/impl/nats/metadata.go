@@ -29,6 +29,29 @@ const (
 	metaKVCreated   = "nats_kv_created"
 )
 
+const (
+	metaObjectStoreName    = "nats_object_store_name"
+	metaObjectStoreBucket  = "nats_object_store_bucket"
+	metaObjectStoreNUID    = "nats_object_store_nuid"
+	metaObjectStoreSize    = "nats_object_store_size"
+	metaObjectStoreModTime = "nats_object_store_mtime"
+	metaObjectStoreDigest  = "nats_object_store_digest"
+	metaObjectStoreDeleted = "nats_object_store_deleted"
+)
+
+func objectStoreMetadataDescription() string {
+	return "``` text" + `
+- nats_object_store_name
+- nats_object_store_bucket
+- nats_object_store_nuid
+- nats_object_store_size
+- nats_object_store_mtime
+- nats_object_store_digest
+- nats_object_store_deleted
+- All object headers (only first values are taken)
+` + "```"
+}
+
 func newMessageFromKVEntry(entry jetstream.KeyValueEntry) *service.Message {
 	msg := service.NewMessage(entry.Value())
 	msg.MetaSetMut(metaKVKey, entry.Key())
@@ -40,3 +63,21 @@ func newMessageFromKVEntry(entry jetstream.KeyValueEntry) *service.Message {
 
 	return msg
 }
+
+func newMessageFromObjectInfo(info *jetstream.ObjectInfo, data []byte) *service.Message {
+	msg := service.NewMessage(data)
+	for k, v := range info.Headers {
+		if len(v) > 0 {
+			msg.MetaSetMut(k, v[0])
+		}
+	}
+	msg.MetaSetMut(metaObjectStoreName, info.Name)
+	msg.MetaSetMut(metaObjectStoreBucket, info.Bucket)
+	msg.MetaSetMut(metaObjectStoreNUID, info.NUID)
+	msg.MetaSetMut(metaObjectStoreSize, info.Size)
+	msg.MetaSetMut(metaObjectStoreModTime, info.ModTime)
+	msg.MetaSetMut(metaObjectStoreDigest, info.Digest)
+	msg.MetaSetMut(metaObjectStoreDeleted, info.Deleted)
+
+	return msg
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ospFieldOperation  = "operation"
+	ospFieldObjectName = "object_name"
+	ospFieldTimeout    = "timeout"
+)
+
+type ospOperationType string
+
+const (
+	ospOperationGet    ospOperationType = "get"
+	ospOperationPut    ospOperationType = "put"
+	ospOperationDelete ospOperationType = "delete"
+)
+
+var ospOperations = map[string]string{
+	string(ospOperationGet):    "Replaces the message contents with the contents of the object `object_name`.",
+	string(ospOperationPut):    "Places the message contents into the store as the object `object_name`.",
+	string(ospOperationDelete): "Deletes the object `object_name`.",
+}
+
+func natsObjectStoreProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Perform operations on a NATS object store bucket.").
+		Description(`
+== Metadata
+
+This processor adds the following metadata fields to each message:
+
+` + objectStoreMetadataDescription() + `
+
+` + connectionNameDescription() + authDescription()).
+		Fields(objectStoreDocs(
+			service.NewStringAnnotatedEnumField(ospFieldOperation, ospOperations).
+				Description("The operation to perform on the object store bucket."),
+			service.NewInterpolatedStringField(ospFieldObjectName).
+				Description("The name of the object for each message.").
+				Example("foo.json").
+				Example(`${! @nats_object_store_name }`).
+				LintRule(`if this == "" {[ "'object_name' must be set to a non-empty string" ]}`),
+			service.NewDurationField(ospFieldTimeout).
+				Description("The maximum period to wait on an operation before aborting and returning an error.").
+				Advanced().Default("5s"),
+		)...)
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"nats_object_store", natsObjectStoreProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newObjectStoreProcessor(conf, mgr)
+		},
+	)
+}
+
+type objectStoreProcessor struct {
+	connDetails connectionDetails
+	bucket      string
+	operation   ospOperationType
+	objectName  *service.InterpolatedString
+	timeout     time.Duration
+
+	log *service.Logger
+
+	shutSig *shutdown.Signaller
+
+	connMut     sync.Mutex
+	natsConn    *nats.Conn
+	objectStore jetstream.ObjectStore
+}
+
+func newObjectStoreProcessor(conf *service.ParsedConfig, mgr *service.Resources) (*objectStoreProcessor, error) {
+	p := &objectStoreProcessor{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+	if p.connDetails, err = connectionDetailsFromParsed(conf, mgr); err != nil {
+		return nil, err
+	}
+
+	if p.bucket, err = conf.FieldString(objectStoreFieldBucket); err != nil {
+		return nil, err
+	}
+
+	if operation, err := conf.FieldString(ospFieldOperation); err != nil {
+		return nil, err
+	} else {
+		p.operation = ospOperationType(operation)
+	}
+
+	if p.objectName, err = conf.FieldInterpolatedString(ospFieldObjectName); err != nil {
+		return nil, err
+	}
+
+	if p.timeout, err = conf.FieldDuration(ospFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	err = p.Connect(context.Background())
+	return p, err
+}
+
+func (p *objectStoreProcessor) disconnect() {
+	p.connMut.Lock()
+	defer p.connMut.Unlock()
+
+	if p.natsConn != nil {
+		p.natsConn.Close()
+		p.natsConn = nil
+	}
+	p.objectStore = nil
+}
+
+func (p *objectStoreProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	p.connMut.Lock()
+	objectStore := p.objectStore
+	p.connMut.Unlock()
+
+	name, err := p.objectName.TryString(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, done := context.WithTimeout(ctx, p.timeout)
+	defer done()
+
+	switch p.operation {
+
+	case ospOperationGet:
+		data, err := objectStore.GetBytes(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		info, err := objectStore.GetInfo(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return service.MessageBatch{newMessageFromObjectInfo(info, data)}, nil
+
+	case ospOperationPut:
+		data, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		info, err := objectStore.Put(ctx, jetstream.ObjectMeta{Name: name}, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		m := msg.Copy()
+		p.addMetadata(m, info)
+		return service.MessageBatch{m}, nil
+
+	case ospOperationDelete:
+		if err := objectStore.Delete(ctx, name); err != nil {
+			return nil, err
+		}
+
+		m := msg.Copy()
+		m.MetaSetMut(metaObjectStoreName, name)
+		m.MetaSetMut(metaObjectStoreBucket, p.bucket)
+		m.MetaSetMut(metaObjectStoreDeleted, true)
+		return service.MessageBatch{m}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid object store operation: %s", p.operation)
+	}
+}
+
+func (p *objectStoreProcessor) addMetadata(msg *service.Message, info *jetstream.ObjectInfo) {
+	msg.MetaSetMut(metaObjectStoreName, info.Name)
+	msg.MetaSetMut(metaObjectStoreBucket, info.Bucket)
+	msg.MetaSetMut(metaObjectStoreNUID, info.NUID)
+	msg.MetaSetMut(metaObjectStoreSize, info.Size)
+	msg.MetaSetMut(metaObjectStoreModTime, info.ModTime)
+	msg.MetaSetMut(metaObjectStoreDigest, info.Digest)
+	msg.MetaSetMut(metaObjectStoreDeleted, info.Deleted)
+}
+
+func (p *objectStoreProcessor) Connect(ctx context.Context) (err error) {
+	p.connMut.Lock()
+	defer p.connMut.Unlock()
+
+	if p.natsConn != nil {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			if p.natsConn != nil {
+				p.natsConn.Close()
+			}
+		}
+	}()
+
+	if p.natsConn, err = p.connDetails.get(ctx); err != nil {
+		return err
+	}
+
+	js, err := jetstream.New(p.natsConn)
+	if err != nil {
+		return err
+	}
+
+	p.objectStore, err = js.ObjectStore(ctx, p.bucket)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *objectStoreProcessor) Close(ctx context.Context) error {
+	go func() {
+		p.disconnect()
+		p.shutSig.TriggerHasStopped()
+	}()
+	select {
+	case <-p.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	otFieldTokenURL       = "token_url"
+	otFieldGrantType      = "grant_type"
+	otFieldClientID       = "client_id"
+	otFieldClientSecret   = "client_secret"
+	otFieldRefreshToken   = "refresh_token"
+	otFieldCache          = "cache"
+	otFieldCacheKey       = "cache_key"
+	otFieldScopes         = "scopes"
+	otFieldEndpointParams = "endpoint_params"
+	otFieldRefreshBefore  = "refresh_before"
+	otFieldMetadataKey    = "metadata_key"
+	otFieldTimeout        = "timeout"
+	otFieldTLS            = "tls"
+
+	grantClientCredentials = "client_credentials"
+	grantRefreshToken      = "refresh_token"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Obtains an OAuth2 access token with the client credentials or refresh token grant, caching it until shortly before it expires, and adds it to the metadata of messages.").
+		Description(`
+The access token is stored within the metadata key `+"`"+otFieldMetadataKey+"`"+`, which allows components without built-in support for a grant, such as an `+"`http_client`"+` output, to authenticate with a header interpolating the metadata key.
+
+A token is obtained when the first batch is processed and reused for subsequent batches until it's within `+"`"+otFieldRefreshBefore+"`"+` of its expiry, at which point a new token is obtained before the batch is processed. When a token can't be obtained the messages of the batch are flagged as errored.
+
+With the `+"`"+grantRefreshToken+"`"+` grant the refresh token returned by the authorization server replaces the configured one, which supports servers that rotate refresh tokens. A rotated refresh token is only held in memory unless a `+"`"+otFieldCache+"`"+` resource is configured, in which case it's written to the cache and read back in place of `+"`"+otFieldRefreshToken+"`"+` on startup. Without a cache the configured refresh token is likely to be rejected after a restart by servers that rotate refresh tokens.
+
+The metadata key travels with the message like any other, and outputs that write metadata, such as the headers of a `+"`kafka`"+` output, or processors such as `+"`tap`"+` would pass the access token downstream. Remove the key once it has been used, for example with a `+"`mapping`"+` of `+"`meta oauth2_access_token = deleted()`"+` after the component that uses it, or exclude it from the metadata written by outputs.
+
+== Metrics
+
+Tokens obtained from the authorization server are counted by the counter `+"`oauth2_token_refreshed`"+`, failures to obtain one by the counter `+"`oauth2_token_refresh_error`"+`, and the lifetime in seconds of the most recently obtained token is exposed as the gauge `+"`oauth2_token_lifetime_seconds`"+`.`).
+		Fields(
+			service.NewURLField(otFieldTokenURL).
+				Description("The URL of the token endpoint of the authorization server."),
+			service.NewStringEnumField(otFieldGrantType, grantClientCredentials, grantRefreshToken).
+				Description("The grant used to obtain access tokens.").
+				Default(grantClientCredentials),
+			service.NewStringField(otFieldClientID).
+				Description("The client ID of the application."),
+			service.NewStringField(otFieldClientSecret).
+				Description("The client secret of the application.").
+				Secret().
+				Default(""),
+			service.NewStringField(otFieldRefreshToken).
+				Description("The refresh token used to obtain access tokens, required by the `"+grantRefreshToken+"` grant.").
+				Secret().
+				Default(""),
+			service.NewStringField(otFieldCache).
+				Description("An optional cache resource in which refresh tokens rotated by the authorization server are stored. When the cache holds a refresh token it's used in place of `"+otFieldRefreshToken+"`, which allows rotated tokens to survive restarts.").
+				Optional().
+				Advanced(),
+			service.NewStringField(otFieldCacheKey).
+				Description("The key within `"+otFieldCache+"` at which the refresh token is stored.").
+				Default("oauth2_refresh_token").
+				Advanced(),
+			service.NewStringListField(otFieldScopes).
+				Description("A list of scopes to request.").
+				Default([]any{}),
+			service.NewStringMapField(otFieldEndpointParams).
+				Description("Additional parameters to send to the token endpoint, such as an `audience`.").
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewDurationField(otFieldRefreshBefore).
+				Description("The period before a token expires at which a new token is obtained.").
+				Default("1m"),
+			service.NewStringField(otFieldMetadataKey).
+				Description("The metadata key to store the access token within. The key should be removed once the token has been used so that it isn't written by outputs.").
+				Default("oauth2_access_token"),
+			service.NewDurationField(otFieldTimeout).
+				Description("The maximum period to wait for a token from the authorization server.").
+				Default("10s").
+				Advanced(),
+			service.NewTLSToggledField(otFieldTLS),
+		).
+		Example("Refresh token for an HTTP API", "Send messages to an API that issues short lived access tokens in exchange for a long lived refresh token.", `
+pipeline:
+  processors:
+    - oauth2_token:
+        token_url: https://auth.example.com/oauth/token
+        grant_type: refresh_token
+        client_id: ${CLIENT_ID}
+        client_secret: ${CLIENT_SECRET}
+        refresh_token: ${REFRESH_TOKEN}
+
+output:
+  http_client:
+    url: https://api.example.com/ingest
+    verb: POST
+    headers:
+      Authorization: Bearer ${! @oauth2_access_token }
+`).
+		Example("Persisting rotated refresh tokens", "Store refresh tokens rotated by the authorization server within a cache resource so that they survive restarts, and remove the access token from messages before they're written to Kafka.", `
+pipeline:
+  processors:
+    - oauth2_token:
+        token_url: https://auth.example.com/oauth/token
+        grant_type: refresh_token
+        client_id: ${CLIENT_ID}
+        refresh_token: ${REFRESH_TOKEN}
+        cache: tokens
+    - branch:
+        request_map: 'root = this'
+        processors:
+          - http:
+              url: https://api.example.com/enrich
+              verb: POST
+              headers:
+                Authorization: Bearer ${! @oauth2_access_token }
+        result_map: 'root.enrichment = this'
+    - mapping: 'meta oauth2_access_token = deleted()'
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: enriched
+
+cache_resources:
+  - label: tokens
+    redis:
+      url: redis://localhost:6379
+`)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor("oauth2_token", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newProcessorFromParsed(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	log         *service.Logger
+	mgr         *service.Resources
+	mRefreshed  *service.MetricCounter
+	mRefreshErr *service.MetricCounter
+	mLifetime   *service.MetricGauge
+
+	grantType     string
+	clientCreds   clientcredentials.Config
+	oauthConf     oauth2.Config
+	refreshBefore time.Duration
+	metadataKey   string
+	timeout       time.Duration
+	cache         string
+	cacheKey      string
+	client        *http.Client
+	clock         func() time.Time
+
+	mut          sync.Mutex
+	token        *oauth2.Token
+	refreshToken string
+	cacheLoaded  bool
+}
+
+func newProcessorFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	p := &processor{
+		log:         mgr.Logger(),
+		mgr:         mgr,
+		mRefreshed:  mgr.Metrics().NewCounter("oauth2_token_refreshed"),
+		mRefreshErr: mgr.Metrics().NewCounter("oauth2_token_refresh_error"),
+		mLifetime:   mgr.Metrics().NewGauge("oauth2_token_lifetime_seconds"),
+		clock:       time.Now,
+	}
+
+	tokenURL, err := conf.FieldURL(otFieldTokenURL)
+	if err != nil {
+		return nil, err
+	}
+	if p.grantType, err = conf.FieldString(otFieldGrantType); err != nil {
+		return nil, err
+	}
+	clientID, err := conf.FieldString(otFieldClientID)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := conf.FieldString(otFieldClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if p.refreshToken, err = conf.FieldString(otFieldRefreshToken); err != nil {
+		return nil, err
+	}
+	if p.grantType == grantRefreshToken && p.refreshToken == "" {
+		return nil, errors.New("a refresh_token is required by the refresh_token grant")
+	}
+	if conf.Contains(otFieldCache) {
+		if p.cache, err = conf.FieldString(otFieldCache); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(p.cache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", p.cache)
+		}
+	}
+	if p.cacheKey, err = conf.FieldString(otFieldCacheKey); err != nil {
+		return nil, err
+	}
+	scopes, err := conf.FieldStringList(otFieldScopes)
+	if err != nil {
+		return nil, err
+	}
+	endpointParams, err := conf.FieldStringMap(otFieldEndpointParams)
+	if err != nil {
+		return nil, err
+	}
+	if p.refreshBefore, err = conf.FieldDuration(otFieldRefreshBefore); err != nil {
+		return nil, err
+	}
+	if p.metadataKey, err = conf.FieldString(otFieldMetadataKey); err != nil {
+		return nil, err
+	}
+	if p.timeout, err = conf.FieldDuration(otFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(otFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	p.client = &http.Client{Transport: transport}
+
+	params := url.Values{}
+	for k, v := range endpointParams {
+		params.Set(k, v)
+	}
+	p.clientCreds = clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       tokenURL.String(),
+		Scopes:         scopes,
+		EndpointParams: params,
+	}
+	p.oauthConf = oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: tokenURL.String()},
+		Scopes:       scopes,
+	}
+	return p, nil
+}
+
+// fetch obtains a new token from the authorization server.
+func (p *processor) fetch(ctx context.Context) (*oauth2.Token, error) {
+	ctx, done := context.WithTimeout(ctx, p.timeout)
+	defer done()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	if p.grantType == grantClientCredentials {
+		return p.clientCreds.Token(ctx)
+	}
+
+	if p.cache != "" && !p.cacheLoaded {
+		if err := p.loadRefreshToken(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read refresh token from cache: %w", err)
+		}
+		p.cacheLoaded = true
+	}
+
+	// A token without an access token is always refreshed, and the
+	// refreshed token retains the refresh token when the server doesn't
+	// return a new one.
+	token, err := p.oauthConf.TokenSource(ctx, &oauth2.Token{RefreshToken: p.refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken != "" && token.RefreshToken != p.refreshToken {
+		p.log.Debug("Authorization server rotated the refresh token")
+		p.refreshToken = token.RefreshToken
+		if p.cache != "" {
+			if err := p.storeRefreshToken(ctx); err != nil {
+				p.log.Errorf("Failed to store rotated refresh token in cache: %v", err)
+			}
+		}
+	}
+	return token, nil
+}
+
+// loadRefreshToken replaces the configured refresh token with the one held by
+// the cache, if any.
+func (p *processor) loadRefreshToken(ctx context.Context) error {
+	var cacheErr error
+	if err := p.mgr.AccessCache(ctx, p.cache, func(c service.Cache) {
+		var b []byte
+		if b, cacheErr = c.Get(ctx, p.cacheKey); cacheErr == nil && len(b) > 0 {
+			p.refreshToken = string(b)
+		}
+	}); err != nil {
+		return err
+	}
+	if errors.Is(cacheErr, service.ErrKeyNotFound) {
+		return nil
+	}
+	return cacheErr
+}
+
+// storeRefreshToken writes the current refresh token to the cache.
+func (p *processor) storeRefreshToken(ctx context.Context) error {
+	var cacheErr error
+	if err := p.mgr.AccessCache(ctx, p.cache, func(c service.Cache) {
+		cacheErr = c.Set(ctx, p.cacheKey, []byte(p.refreshToken), nil)
+	}); err != nil {
+		return err
+	}
+	return cacheErr
+}
+
+// accessToken returns the cached access token, obtaining a new one when the
+// cached token is within the refresh period of its expiry.
+func (p *processor) accessToken(ctx context.Context) (string, error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	now := p.clock()
+	if p.token != nil && (p.token.Expiry.IsZero() || now.Add(p.refreshBefore).Before(p.token.Expiry)) {
+		return p.token.AccessToken, nil
+	}
+
+	token, err := p.fetch(ctx)
+	if err != nil {
+		p.mRefreshErr.Incr(1)
+		return "", fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	p.mRefreshed.Incr(1)
+	if !token.Expiry.IsZero() {
+		p.mLifetime.Set(int64(token.Expiry.Sub(now).Seconds()))
+	}
+	p.token = token
+	return token.AccessToken, nil
+}
+
+func (p *processor) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range batch {
+		msg.MetaSetMut(p.metadataKey, token)
+	}
+	return []service.MessageBatch{batch}, nil
+}
+
+func (p *processor) Close(context.Context) error {
+	p.client.CloseIdleConnections()
+	return nil
+}
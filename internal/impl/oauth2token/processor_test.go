@@ -0,0 +1,221 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2token
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type tokenServer struct {
+	mut           sync.Mutex
+	issued        int
+	fail          bool
+	refreshTokens []string
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.fail {
+		http.Error(w, `{"error":"server_error"}`, http.StatusInternalServerError)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.issued++
+	w.Header().Set("Content-Type", "application/json")
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		_, _ = fmt.Fprintf(w, `{"access_token":"access-%v","token_type":"bearer","expires_in":120}`, s.issued)
+	case "refresh_token":
+		s.refreshTokens = append(s.refreshTokens, r.PostForm.Get("refresh_token"))
+		_, _ = fmt.Fprintf(w, `{"access_token":"access-%v","token_type":"bearer","expires_in":120,"refresh_token":"refresh-%v"}`, s.issued, s.issued)
+	default:
+		http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+	}
+}
+
+func testProcessor(t *testing.T, conf string) *processor {
+	t.Helper()
+
+	pConf, err := processorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newProcessorFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	return p
+}
+
+func processToken(t *testing.T, p *processor) (string, error) {
+	t.Helper()
+
+	batches, err := p.ProcessBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("hello"))})
+	if err != nil {
+		return "", err
+	}
+	require.Len(t, batches, 1)
+	token, _ := batches[0][0].MetaGet("oauth2_access_token")
+	return token, nil
+}
+
+func TestProcessorClientCredentials(t *testing.T) {
+	srv := &tokenServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	p := testProcessor(t, `
+token_url: `+ts.URL+`
+client_id: foo
+client_secret: bar
+refresh_before: 30s
+`)
+	now := time.Now()
+	p.clock = func() time.Time { return now }
+
+	token, err := processToken(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+
+	// The token is reused until it's within the refresh period of its expiry.
+	now = now.Add(80 * time.Second)
+	token, err = processToken(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+
+	now = now.Add(20 * time.Second)
+	token, err = processToken(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token)
+
+	// A failure to obtain a token fails the batch.
+	now = now.Add(120 * time.Second)
+	srv.mut.Lock()
+	srv.fail = true
+	srv.mut.Unlock()
+	_, err = processToken(t, p)
+	require.ErrorContains(t, err, "failed to obtain access token")
+}
+
+func TestProcessorRefreshToken(t *testing.T) {
+	srv := &tokenServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	p := testProcessor(t, `
+token_url: `+ts.URL+`
+grant_type: refresh_token
+client_id: foo
+refresh_token: initial
+`)
+	now := time.Now()
+	p.clock = func() time.Time { return now }
+
+	token, err := processToken(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+
+	now = now.Add(100 * time.Second)
+	token, err = processToken(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token)
+
+	// Rotated refresh tokens replace the configured one.
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	assert.Equal(t, []string{"initial", "refresh-1"}, srv.refreshTokens)
+}
+
+func TestProcessorRefreshTokenRequired(t *testing.T) {
+	pConf, err := processorSpec().ParseYAML(`
+token_url: http://localhost/token
+grant_type: refresh_token
+client_id: foo
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newProcessorFromParsed(pConf, service.MockResources())
+	require.ErrorContains(t, err, "refresh_token is required")
+}
+
+func TestProcessorRefreshTokenCache(t *testing.T) {
+	srv := &tokenServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	res := service.MockResources(service.MockResourcesOptAddCache("tokens"))
+	newProc := func() *processor {
+		pConf, err := processorSpec().ParseYAML(`
+token_url: `+ts.URL+`
+grant_type: refresh_token
+client_id: foo
+refresh_token: initial
+cache: tokens
+`, nil)
+		require.NoError(t, err)
+
+		p, err := newProcessorFromParsed(pConf, res)
+		require.NoError(t, err)
+		return p
+	}
+
+	token, err := processToken(t, newProc())
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+
+	// A new processor, as after a restart, uses the rotated refresh token
+	// stored within the cache rather than the configured one.
+	token, err = processToken(t, newProc())
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token)
+
+	var cached []byte
+	require.NoError(t, res.AccessCache(t.Context(), "tokens", func(c service.Cache) {
+		cached, err = c.Get(t.Context(), "oauth2_refresh_token")
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-2", string(cached))
+
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	assert.Equal(t, []string{"initial", "refresh-1"}, srv.refreshTokens)
+}
+
+func TestProcessorCacheNotFound(t *testing.T) {
+	pConf, err := processorSpec().ParseYAML(`
+token_url: http://localhost/token
+grant_type: refresh_token
+client_id: foo
+refresh_token: foo
+cache: nope
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newProcessorFromParsed(pConf, service.MockResources())
+	require.ErrorContains(t, err, "cache resource 'nope' was not found")
+}
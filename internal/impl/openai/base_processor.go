@@ -10,6 +10,7 @@ package openai
 
 import (
 	"context"
+	"fmt"
 
 	oai "github.com/sashabaranov/go-openai"
 
@@ -17,9 +18,16 @@ import (
 )
 
 const (
-	opFieldServerAddress = "server_address"
-	opFieldAPIKey        = "api_key"
-	opFieldModel         = "model"
+	opFieldServerAddress   = "server_address"
+	opFieldBaseURL         = "base_url"
+	opFieldAPIType         = "api_type"
+	opFieldAPIVersion      = "api_version"
+	opFieldAzureDeployment = "azure_deployment"
+	opFieldAPIKey          = "api_key"
+	opFieldModel           = "model"
+
+	apiTypeOpenAI = "openai"
+	apiTypeAzure  = "azure"
 )
 
 func baseConfigFieldsWithModels(modelExamples ...any) []*service.ConfigField {
@@ -27,6 +35,32 @@ func baseConfigFieldsWithModels(modelExamples ...any) []*service.ConfigField {
 		service.NewStringField(opFieldServerAddress).
 			Description("The Open API endpoint that the processor sends requests to. Update the default value to use another OpenAI compatible service.").
 			Default("https://api.openai.com/v1"),
+		service.NewStringField(opFieldBaseURL).
+			Description("An optional base URL that overrides `server_address`, allowing requests to be sent to self-hosted OpenAI compatible gateways such as vLLM or LiteLLM. When `api_type` is `azure` this should be the endpoint of the Azure OpenAI resource.").
+			Example("http://localhost:8000/v1").
+			Example("https://my-resource.openai.azure.com").
+			Optional().
+			Advanced().
+			Version("4.62.0"),
+		service.NewStringAnnotatedEnumField(opFieldAPIType, map[string]string{
+			apiTypeOpenAI: "Use the OpenAI API, or a compatible API.",
+			apiTypeAzure:  "Use the Azure OpenAI service, where requests are routed to a model deployment and authenticated with the `api-key` header.",
+		}).
+			Description("The flavor of API to send requests to.").
+			Default(apiTypeOpenAI).
+			Advanced().
+			Version("4.62.0"),
+		service.NewStringField(opFieldAPIVersion).
+			Description("The API version to request, sent as the `api-version` query parameter. This is required when `api_type` is `azure`.").
+			Example("2024-10-21").
+			Default("").
+			Advanced().
+			Version("4.62.0"),
+		service.NewStringField(opFieldAzureDeployment).
+			Description("The name of the Azure OpenAI deployment to send requests to. When empty the name of the `model` is used as the deployment name.").
+			Default("").
+			Advanced().
+			Version("4.62.0"),
 		service.NewStringField(opFieldAPIKey).
 			Secret().
 			Description("The API key for OpenAI API."),
@@ -46,16 +80,10 @@ func (*baseProcessor) Close(context.Context) error {
 }
 
 func newBaseProcessor(conf *service.ParsedConfig) (*baseProcessor, error) {
-	sa, err := conf.FieldString(opFieldServerAddress)
+	cfg, err := clientConfigFromParsed(conf)
 	if err != nil {
 		return nil, err
 	}
-	k, err := conf.FieldString(opFieldAPIKey)
-	if err != nil {
-		return nil, err
-	}
-	cfg := oai.DefaultConfig(k)
-	cfg.BaseURL = sa
 	c := oai.NewClientWithConfig(cfg)
 	m, err := conf.FieldString(opFieldModel)
 	if err != nil {
@@ -63,3 +91,54 @@ func newBaseProcessor(conf *service.ParsedConfig) (*baseProcessor, error) {
 	}
 	return &baseProcessor{c, m}, nil
 }
+
+func clientConfigFromParsed(conf *service.ParsedConfig) (oai.ClientConfig, error) {
+	sa, err := conf.FieldString(opFieldServerAddress)
+	if err != nil {
+		return oai.ClientConfig{}, err
+	}
+	if conf.Contains(opFieldBaseURL) {
+		if sa, err = conf.FieldString(opFieldBaseURL); err != nil {
+			return oai.ClientConfig{}, err
+		}
+	}
+	k, err := conf.FieldString(opFieldAPIKey)
+	if err != nil {
+		return oai.ClientConfig{}, err
+	}
+	t, err := conf.FieldString(opFieldAPIType)
+	if err != nil {
+		return oai.ClientConfig{}, err
+	}
+	v, err := conf.FieldString(opFieldAPIVersion)
+	if err != nil {
+		return oai.ClientConfig{}, err
+	}
+	if t != apiTypeAzure {
+		cfg := oai.DefaultConfig(k)
+		cfg.BaseURL = sa
+		if v != "" {
+			cfg.APIVersion = v
+		}
+		return cfg, nil
+	}
+
+	if !conf.Contains(opFieldBaseURL) {
+		return oai.ClientConfig{}, fmt.Errorf("field %v is required when %v is %v", opFieldBaseURL, opFieldAPIType, apiTypeAzure)
+	}
+	if v == "" {
+		return oai.ClientConfig{}, fmt.Errorf("field %v is required when %v is %v", opFieldAPIVersion, opFieldAPIType, apiTypeAzure)
+	}
+	d, err := conf.FieldString(opFieldAzureDeployment)
+	if err != nil {
+		return oai.ClientConfig{}, err
+	}
+	cfg := oai.DefaultAzureConfig(k, sa)
+	cfg.APIVersion = v
+	if d != "" {
+		cfg.AzureModelMapperFunc = func(string) string {
+			return d
+		}
+	}
+	return cfg, nil
+}
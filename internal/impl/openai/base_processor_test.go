@@ -0,0 +1,88 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package openai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	oai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func embeddingsServer(t *testing.T, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.5]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func baseProcessorFromYAML(t *testing.T, yamlStr string) *baseProcessor {
+	t.Helper()
+	conf, err := service.NewConfigSpec().Fields(baseConfigFieldsWithModels()...).ParseYAML(yamlStr, nil)
+	require.NoError(t, err)
+	b, err := newBaseProcessor(conf)
+	require.NoError(t, err)
+	return b
+}
+
+func TestBaseProcessorBaseURL(t *testing.T) {
+	srv := embeddingsServer(t, func(r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer foo", r.Header.Get("Authorization"))
+	})
+
+	b := baseProcessorFromYAML(t, `
+server_address: http://localhost:1234/not-used
+base_url: `+srv.URL+`/v1
+api_key: foo
+model: bar
+`)
+	_, err := b.client.CreateEmbeddings(t.Context(), oai.EmbeddingRequestStrings{Input: []string{"hello"}, Model: oai.EmbeddingModel(b.model)})
+	require.NoError(t, err)
+}
+
+func TestBaseProcessorAzure(t *testing.T) {
+	srv := embeddingsServer(t, func(r *http.Request) {
+		assert.Equal(t, "/openai/deployments/my-deployment/embeddings", r.URL.Path)
+		assert.Equal(t, "2024-10-21", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "foo", r.Header.Get("api-key"))
+	})
+
+	b := baseProcessorFromYAML(t, `
+base_url: `+srv.URL+`
+api_type: azure
+api_version: 2024-10-21
+azure_deployment: my-deployment
+api_key: foo
+model: text-embedding-3-small
+`)
+	_, err := b.client.CreateEmbeddings(t.Context(), oai.EmbeddingRequestStrings{Input: []string{"hello"}, Model: oai.EmbeddingModel(b.model)})
+	require.NoError(t, err)
+}
+
+func TestBaseProcessorAzureValidation(t *testing.T) {
+	conf, err := service.NewConfigSpec().Fields(baseConfigFieldsWithModels()...).ParseYAML(`
+api_type: azure
+api_key: foo
+model: bar
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newBaseProcessor(conf)
+	require.ErrorContains(t, err, "field base_url is required")
+}
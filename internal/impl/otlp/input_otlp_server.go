@@ -0,0 +1,487 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/dustin/go-humanize"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	osiFieldGRPCAddress = "grpc_address"
+	osiFieldHTTPAddress = "http_address"
+	osiFieldCertFile    = "cert_file"
+	osiFieldKeyFile     = "key_file"
+	osiFieldMaxBodySize = "max_body_size"
+)
+
+func serverInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Receives OpenTelemetry logs, traces and metrics exported over OTLP with gRPC or HTTP.").
+		Description(`
+Runs an https://opentelemetry.io/docs/specs/otlp/[OTLP^] endpoint that SDKs and collectors can export telemetry to, with gRPC served at `+"`grpc_address`"+` and HTTP served at `+"`http_address`"+` at the paths `+"`/v1/logs`, `/v1/traces` and `/v1/metrics`"+`, where HTTP requests can be either binary protobuf or JSON encoded and optionally gzip compressed.
+
+Each export request is consumed as a batch where every log record, span or metric data point is a message, and the export only succeeds once the batch has been delivered. When the batch is rejected the export fails with the status `+"`UNAVAILABLE`"+`, or `+"`503`"+` over HTTP, so that exporters retry it.
+
+Messages have the same structure as the fields consumed by the xref:components:outputs/otlp.adoc[`+"`otlp`"+` output], and therefore telemetry received with this input can be forwarded with that output. Metric data points are given a `+"`type`"+` of `+"`gauge`, `sum`, `histogram`, `exponential_histogram` or `summary`"+`, where histogram and summary data points have the fields of their distributions rather than a `+"`value`"+`.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- otlp_signal
+- otlp_resource_attributes
+- otlp_scope_name
+- otlp_scope_version
+- otlp_scope_attributes
+`+"```"+`
+
+The signal is one of `+"`logs`, `traces` or `metrics`"+`, and attributes are objects, which can be accessed with queries such as `+"`@otlp_resource_attributes.\"service.name\"`"+`.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(
+			service.NewStringField(osiFieldGRPCAddress).
+				Description("The address to serve gRPC exports on. Set to an empty string in order to disable gRPC.").
+				Default("0.0.0.0:4317"),
+			service.NewStringField(osiFieldHTTPAddress).
+				Description("The address to serve HTTP exports on. Set to an empty string in order to disable HTTP.").
+				Default("0.0.0.0:4318"),
+			service.NewStringField(osiFieldCertFile).
+				Description("An optional certificate file for enabling TLS.").
+				Default("").
+				Advanced(),
+			service.NewStringField(osiFieldKeyFile).
+				Description("An optional key file for enabling TLS.").
+				Default("").
+				Advanced(),
+			service.NewStringField(osiFieldMaxBodySize).
+				Description("The maximum size of each export request, after decompression.").
+				Default("16MiB").
+				Advanced(),
+		).
+		Example(
+			"Telemetry to Kafka",
+			"Receive telemetry from OpenTelemetry SDKs and collectors and write each signal to its own topic.",
+			`
+input:
+  otlp_server: {}
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: otlp_${! @otlp_signal }
+    key: ${! @otlp_resource_attributes."service.name" }
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchInput("otlp_server", serverInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return newServerInputFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type serverRequest struct {
+	batch service.MessageBatch
+	res   chan error
+}
+
+type serverInput struct {
+	log *service.Logger
+
+	grpcAddress string
+	httpAddress string
+	certFile    string
+	keyFile     string
+	maxBodySize int64
+
+	requests chan serverRequest
+	shutSig  *shutdown.Signaller
+
+	mut          sync.Mutex
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	httpServer   *http.Server
+	httpListener net.Listener
+}
+
+func newServerInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*serverInput, error) {
+	s := &serverInput{
+		log:      mgr.Logger(),
+		requests: make(chan serverRequest),
+		shutSig:  shutdown.NewSignaller(),
+	}
+
+	var err error
+	if s.grpcAddress, err = conf.FieldString(osiFieldGRPCAddress); err != nil {
+		return nil, err
+	}
+	if s.httpAddress, err = conf.FieldString(osiFieldHTTPAddress); err != nil {
+		return nil, err
+	}
+	if s.grpcAddress == "" && s.httpAddress == "" {
+		return nil, errors.New("at least one of grpc_address and http_address must be specified")
+	}
+	if s.certFile, err = conf.FieldString(osiFieldCertFile); err != nil {
+		return nil, err
+	}
+	if s.keyFile, err = conf.FieldString(osiFieldKeyFile); err != nil {
+		return nil, err
+	}
+	if (s.certFile == "") != (s.keyFile == "") {
+		return nil, errors.New("both a cert_file and key_file must be specified for TLS")
+	}
+
+	maxBodySizeStr, err := conf.FieldString(osiFieldMaxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	maxBodySize, err := humanize.ParseBytes(maxBodySizeStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse max_body_size: %w", err)
+	}
+	s.maxBodySize = int64(maxBodySize)
+	return s, nil
+}
+
+func (s *serverInput) Connect(context.Context) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.grpcServer != nil || s.httpServer != nil {
+		return nil
+	}
+
+	if s.grpcAddress != "" {
+		opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(int(s.maxBodySize))}
+		if s.certFile != "" {
+			creds, err := credentials.NewServerTLSFromFile(s.certFile, s.keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load TLS credentials: %w", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+
+		listener, err := net.Listen("tcp", s.grpcAddress)
+		if err != nil {
+			return err
+		}
+
+		server := grpc.NewServer(opts...)
+		collogspb.RegisterLogsServiceServer(server, &logsServer{s: s})
+		coltracepb.RegisterTraceServiceServer(server, &traceServer{s: s})
+		colmetricspb.RegisterMetricsServiceServer(server, &metricsServer{s: s})
+
+		s.grpcServer = server
+		s.grpcListener = listener
+		go func() {
+			s.log.Infof("Receiving OTLP gRPC exports at: %v", listener.Addr())
+			if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				s.log.Errorf("gRPC server error: %v", err)
+			}
+		}()
+	}
+
+	if s.httpAddress != "" {
+		listener, err := net.Listen("tcp", s.httpAddress)
+		if err != nil {
+			if s.grpcServer != nil {
+				s.grpcServer.Stop()
+				s.grpcServer = nil
+			}
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/logs", s.httpHandler(func() proto.Message { return &collogspb.ExportLogsServiceRequest{} }))
+		mux.HandleFunc("/v1/traces", s.httpHandler(func() proto.Message { return &coltracepb.ExportTraceServiceRequest{} }))
+		mux.HandleFunc("/v1/metrics", s.httpHandler(func() proto.Message { return &colmetricspb.ExportMetricsServiceRequest{} }))
+
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: time.Second * 10}
+		s.httpServer = server
+		s.httpListener = listener
+		go func() {
+			s.log.Infof("Receiving OTLP HTTP exports at: %v", listener.Addr())
+			var err error
+			if s.certFile != "" {
+				err = server.ServeTLS(listener, s.certFile, s.keyFile)
+			} else {
+				err = server.Serve(listener)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.log.Errorf("HTTP server error: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// errServerClosing is returned to exporters when the input is shutting down.
+var errServerClosing = errors.New("server closing")
+
+// consume delivers a batch to the pipeline and waits for it to be
+// acknowledged.
+func (s *serverInput) consume(ctx context.Context, batch service.MessageBatch) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	resChan := make(chan error, 1)
+	select {
+	case s.requests <- serverRequest{batch: batch, res: resChan}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.shutSig.SoftStopChan():
+		return errServerClosing
+	}
+
+	select {
+	case err := <-resChan:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.shutSig.HardStopChan():
+		return errServerClosing
+	}
+}
+
+func grpcConsumeError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+type logsServer struct {
+	collogspb.UnimplementedLogsServiceServer
+	s *serverInput
+}
+
+func (l *logsServer) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if err := l.s.consume(ctx, logsBatch(req)); err != nil {
+		return nil, grpcConsumeError(err)
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+type traceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	s *serverInput
+}
+
+func (t *traceServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if err := t.s.consume(ctx, tracesBatch(req)); err != nil {
+		return nil, grpcConsumeError(err)
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+type metricsServer struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	s *serverInput
+}
+
+func (m *metricsServer) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	if err := m.s.consume(ctx, metricsBatch(req)); err != nil {
+		return nil, grpcConsumeError(err)
+	}
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *serverInput) httpHandler(newReq func() proto.Message) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		isJSON := mediaType == "application/json"
+		if !isJSON && mediaType != "application/x-protobuf" {
+			http.Error(w, "unsupported content type: "+r.Header.Get("Content-Type"), http.StatusUnsupportedMediaType)
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeHTTPStatus(w, isJSON, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		var body io.Reader = r.Body
+		switch r.Header.Get("Content-Encoding") {
+		case "", "identity":
+		case "gzip":
+			gr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeHTTPStatus(w, isJSON, http.StatusBadRequest, "invalid gzip body: "+err.Error())
+				return
+			}
+			defer gr.Close()
+			body = gr
+		default:
+			writeHTTPStatus(w, isJSON, http.StatusUnsupportedMediaType, "unsupported content encoding: "+r.Header.Get("Content-Encoding"))
+			return
+		}
+
+		reqBytes, err := io.ReadAll(io.LimitReader(body, s.maxBodySize+1))
+		if err != nil {
+			writeHTTPStatus(w, isJSON, http.StatusBadRequest, "failed to read body: "+err.Error())
+			return
+		}
+		if int64(len(reqBytes)) > s.maxBodySize {
+			writeHTTPStatus(w, isJSON, http.StatusRequestEntityTooLarge, "request body exceeds the maximum size")
+			return
+		}
+
+		req := newReq()
+		if isJSON {
+			err = unmarshalOTLPJSON(reqBytes, req)
+		} else {
+			err = proto.Unmarshal(reqBytes, req)
+		}
+		if err != nil {
+			writeHTTPStatus(w, isJSON, http.StatusBadRequest, "failed to parse request: "+err.Error())
+			return
+		}
+
+		var batch service.MessageBatch
+		var res proto.Message
+		switch t := req.(type) {
+		case *collogspb.ExportLogsServiceRequest:
+			batch, res = logsBatch(t), &collogspb.ExportLogsServiceResponse{}
+		case *coltracepb.ExportTraceServiceRequest:
+			batch, res = tracesBatch(t), &coltracepb.ExportTraceServiceResponse{}
+		case *colmetricspb.ExportMetricsServiceRequest:
+			batch, res = metricsBatch(t), &colmetricspb.ExportMetricsServiceResponse{}
+		}
+		if err := s.consume(r.Context(), batch); err != nil {
+			writeHTTPStatus(w, isJSON, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeHTTPMessage(w, isJSON, http.StatusOK, res)
+	}
+}
+
+// unmarshalOTLPJSON parses an OTLP/JSON request, where unlike protobuf JSON
+// trace and span identifiers are hex encoded.
+func unmarshalOTLPJSON(b []byte, req proto.Message) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	hexIDsToBase64(v)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, req)
+}
+
+func writeHTTPStatus(w http.ResponseWriter, isJSON bool, code int, msg string) {
+	if code == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	writeHTTPMessage(w, isJSON, code, &statuspb.Status{Code: int32(codes.Unknown), Message: msg})
+}
+
+func writeHTTPMessage(w http.ResponseWriter, isJSON bool, code int, msg proto.Message) {
+	var b []byte
+	if isJSON {
+		b, _ = protojson.Marshal(msg)
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		b, _ = proto.Marshal(msg)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+//------------------------------------------------------------------------------
+
+func (s *serverInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	select {
+	case req := <-s.requests:
+		return req.batch, func(ctx context.Context, err error) error {
+			select {
+			case req.res <- err:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-s.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (s *serverInput) Close(ctx context.Context) error {
+	s.shutSig.TriggerSoftStop()
+	defer s.shutSig.TriggerHardStop()
+
+	s.mut.Lock()
+	grpcServer, httpServer := s.grpcServer, s.httpServer
+	s.mut.Unlock()
+
+	var httpErr error
+	if httpServer != nil {
+		if httpErr = httpServer.Shutdown(ctx); httpErr != nil {
+			_ = httpServer.Close()
+		}
+	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+	return httpErr
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func goValue(v *commonpb.AnyValue) any {
+	switch t := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return t.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return t.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return t.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return t.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return t.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, 0, len(t.ArrayValue.GetValues()))
+		for _, e := range t.ArrayValue.GetValues() {
+			values = append(values, goValue(e))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		return goMap(t.KvlistValue.GetValues())
+	}
+	return nil
+}
+
+func goMap(kvs []*commonpb.KeyValue) map[string]any {
+	obj := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		obj[kv.GetKey()] = goValue(kv.GetValue())
+	}
+	return obj
+}
+
+// fieldSetter builds the fields of a message, omitting timestamps,
+// identifiers, strings and attributes that are not set.
+type fieldSetter map[string]any
+
+func (f fieldSetter) timestamp(key string, nanos uint64) {
+	if nanos > 0 {
+		f[key] = time.Unix(0, int64(nanos)).UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func (f fieldSetter) id(key string, id []byte) {
+	if len(id) > 0 {
+		f[key] = hex.EncodeToString(id)
+	}
+}
+
+func (f fieldSetter) str(key, s string) {
+	if s != "" {
+		f[key] = s
+	}
+}
+
+func (f fieldSetter) attributes(key string, kvs []*commonpb.KeyValue) {
+	if len(kvs) > 0 {
+		f[key] = goMap(kvs)
+	}
+}
+
+// otlpScope is the resource and instrumentation scope of telemetry, which is
+// added to each message as metadata.
+type otlpScope struct {
+	resource *resourcepb.Resource
+	scope    *commonpb.InstrumentationScope
+}
+
+func (s otlpScope) newMessage(signal string, fields map[string]any) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(fields)
+	msg.MetaSetMut("otlp_signal", signal)
+	msg.MetaSetMut("otlp_resource_attributes", goMap(s.resource.GetAttributes()))
+	msg.MetaSetMut("otlp_scope_name", s.scope.GetName())
+	msg.MetaSetMut("otlp_scope_version", s.scope.GetVersion())
+	msg.MetaSetMut("otlp_scope_attributes", goMap(s.scope.GetAttributes()))
+	return msg
+}
+
+func logsBatch(req *collogspb.ExportLogsServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			scope := otlpScope{resource: rl.GetResource(), scope: sl.GetScope()}
+			for _, rec := range sl.GetLogRecords() {
+				f := fieldSetter{}
+				f.timestamp("timestamp", rec.GetTimeUnixNano())
+				f.timestamp("observed_timestamp", rec.GetObservedTimeUnixNano())
+				f.str("severity_text", rec.GetSeverityText())
+				if n := rec.GetSeverityNumber(); n != 0 {
+					f["severity_number"] = int64(n)
+				}
+				if rec.GetBody() != nil {
+					f["body"] = goValue(rec.GetBody())
+				}
+				f.attributes("attributes", rec.GetAttributes())
+				f.id("trace_id", rec.GetTraceId())
+				f.id("span_id", rec.GetSpanId())
+				f.str("event_name", rec.GetEventName())
+				batch = append(batch, scope.newMessage("logs", f))
+			}
+		}
+	}
+	return batch
+}
+
+func tracesBatch(req *coltracepb.ExportTraceServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			scope := otlpScope{resource: rs.GetResource(), scope: ss.GetScope()}
+			for _, s := range ss.GetSpans() {
+				f := fieldSetter{}
+				f.id("trace_id", s.GetTraceId())
+				f.id("span_id", s.GetSpanId())
+				f.id("parent_span_id", s.GetParentSpanId())
+				f.str("trace_state", s.GetTraceState())
+				f.str("name", s.GetName())
+				if s.GetKind() != 0 {
+					f["kind"] = strings.ToLower(strings.TrimPrefix(s.GetKind().String(), "SPAN_KIND_"))
+				}
+				f.timestamp("start_time", s.GetStartTimeUnixNano())
+				f.timestamp("end_time", s.GetEndTimeUnixNano())
+				f.attributes("attributes", s.GetAttributes())
+				if status := s.GetStatus(); status != nil {
+					st := fieldSetter{"code": strings.ToLower(strings.TrimPrefix(status.GetCode().String(), "STATUS_CODE_"))}
+					st.str("message", status.GetMessage())
+					f["status"] = map[string]any(st)
+				}
+				if len(s.GetEvents()) > 0 {
+					events := make([]any, 0, len(s.GetEvents()))
+					for _, e := range s.GetEvents() {
+						ef := fieldSetter{}
+						ef.str("name", e.GetName())
+						ef.timestamp("timestamp", e.GetTimeUnixNano())
+						ef.attributes("attributes", e.GetAttributes())
+						events = append(events, map[string]any(ef))
+					}
+					f["events"] = events
+				}
+				if len(s.GetLinks()) > 0 {
+					links := make([]any, 0, len(s.GetLinks()))
+					for _, l := range s.GetLinks() {
+						lf := fieldSetter{}
+						lf.id("trace_id", l.GetTraceId())
+						lf.id("span_id", l.GetSpanId())
+						lf.str("trace_state", l.GetTraceState())
+						lf.attributes("attributes", l.GetAttributes())
+						links = append(links, map[string]any(lf))
+					}
+					f["links"] = links
+				}
+				batch = append(batch, scope.newMessage("traces", f))
+			}
+		}
+	}
+	return batch
+}
+
+func temporality(t metricspb.AggregationTemporality) string {
+	if t == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA {
+		return "delta"
+	}
+	return "cumulative"
+}
+
+func uint64sToAny(s []uint64) []any {
+	values := make([]any, 0, len(s))
+	for _, v := range s {
+		values = append(values, int64(v))
+	}
+	return values
+}
+
+func float64sToAny(s []float64) []any {
+	values := make([]any, 0, len(s))
+	for _, v := range s {
+		values = append(values, v)
+	}
+	return values
+}
+
+func metricsBatch(req *colmetricspb.ExportMetricsServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			scope := otlpScope{resource: rm.GetResource(), scope: sm.GetScope()}
+			for _, m := range sm.GetMetrics() {
+				newPoint := func(typ string, attrs []*commonpb.KeyValue, start, ts uint64) fieldSetter {
+					f := fieldSetter{"name": m.GetName(), "type": typ}
+					f.str("description", m.GetDescription())
+					f.str("unit", m.GetUnit())
+					f.attributes("attributes", attrs)
+					f.timestamp("start_time", start)
+					f.timestamp("timestamp", ts)
+					return f
+				}
+				numberValue := func(f fieldSetter, dp *metricspb.NumberDataPoint) {
+					switch v := dp.GetValue().(type) {
+					case *metricspb.NumberDataPoint_AsInt:
+						f["value"] = v.AsInt
+					case *metricspb.NumberDataPoint_AsDouble:
+						f["value"] = v.AsDouble
+					}
+				}
+
+				switch d := m.GetData().(type) {
+				case *metricspb.Metric_Gauge:
+					for _, dp := range d.Gauge.GetDataPoints() {
+						f := newPoint("gauge", dp.GetAttributes(), dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+						numberValue(f, dp)
+						batch = append(batch, scope.newMessage("metrics", f))
+					}
+				case *metricspb.Metric_Sum:
+					for _, dp := range d.Sum.GetDataPoints() {
+						f := newPoint("sum", dp.GetAttributes(), dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+						f["monotonic"] = d.Sum.GetIsMonotonic()
+						f["temporality"] = temporality(d.Sum.GetAggregationTemporality())
+						numberValue(f, dp)
+						batch = append(batch, scope.newMessage("metrics", f))
+					}
+				case *metricspb.Metric_Histogram:
+					for _, dp := range d.Histogram.GetDataPoints() {
+						f := newPoint("histogram", dp.GetAttributes(), dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+						f["temporality"] = temporality(d.Histogram.GetAggregationTemporality())
+						f["count"] = int64(dp.GetCount())
+						if dp.Sum != nil {
+							f["sum"] = dp.GetSum()
+						}
+						if dp.Min != nil {
+							f["min"] = dp.GetMin()
+						}
+						if dp.Max != nil {
+							f["max"] = dp.GetMax()
+						}
+						f["bucket_counts"] = uint64sToAny(dp.GetBucketCounts())
+						f["explicit_bounds"] = float64sToAny(dp.GetExplicitBounds())
+						batch = append(batch, scope.newMessage("metrics", f))
+					}
+				case *metricspb.Metric_ExponentialHistogram:
+					for _, dp := range d.ExponentialHistogram.GetDataPoints() {
+						f := newPoint("exponential_histogram", dp.GetAttributes(), dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+						f["temporality"] = temporality(d.ExponentialHistogram.GetAggregationTemporality())
+						f["count"] = int64(dp.GetCount())
+						if dp.Sum != nil {
+							f["sum"] = dp.GetSum()
+						}
+						if dp.Min != nil {
+							f["min"] = dp.GetMin()
+						}
+						if dp.Max != nil {
+							f["max"] = dp.GetMax()
+						}
+						f["scale"] = int64(dp.GetScale())
+						f["zero_count"] = int64(dp.GetZeroCount())
+						f["positive"] = map[string]any{
+							"offset":        int64(dp.GetPositive().GetOffset()),
+							"bucket_counts": uint64sToAny(dp.GetPositive().GetBucketCounts()),
+						}
+						f["negative"] = map[string]any{
+							"offset":        int64(dp.GetNegative().GetOffset()),
+							"bucket_counts": uint64sToAny(dp.GetNegative().GetBucketCounts()),
+						}
+						batch = append(batch, scope.newMessage("metrics", f))
+					}
+				case *metricspb.Metric_Summary:
+					for _, dp := range d.Summary.GetDataPoints() {
+						f := newPoint("summary", dp.GetAttributes(), dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+						f["count"] = int64(dp.GetCount())
+						f["sum"] = dp.GetSum()
+						quantiles := make([]any, 0, len(dp.GetQuantileValues()))
+						for _, q := range dp.GetQuantileValues() {
+							quantiles = append(quantiles, map[string]any{"quantile": q.GetQuantile(), "value": q.GetValue()})
+						}
+						f["quantiles"] = quantiles
+						batch = append(batch, scope.newMessage("metrics", f))
+					}
+				}
+			}
+		}
+	}
+	return batch
+}
+
+// otlpJSONIDKeys are the fields of OTLP/JSON requests that are hex encoded
+// rather than base64 encoded as protobuf JSON bytes otherwise are.
+var otlpJSONIDKeys = map[string]struct{}{
+	"traceId":      {},
+	"spanId":       {},
+	"parentSpanId": {},
+}
+
+// hexIDsToBase64 rewrites the hex encoded identifiers of a parsed OTLP/JSON
+// request so that the request can be parsed as protobuf JSON.
+func hexIDsToBase64(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			if _, isID := otlpJSONIDKeys[k]; isID {
+				if s, ok := e.(string); ok {
+					if b, err := hex.DecodeString(s); err == nil {
+						t[k] = base64.StdEncoding.EncodeToString(b)
+					}
+				}
+				continue
+			}
+			hexIDsToBase64(e)
+		}
+	case []any:
+		for _, e := range t {
+			hexIDsToBase64(e)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func startServerInput(t *testing.T, conf string) *serverInput {
+	t.Helper()
+
+	pConf, err := serverInputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	s, err := newServerInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, s.Connect(t.Context()))
+	t.Cleanup(func() {
+		ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+		defer done()
+		_ = s.Close(ctx)
+	})
+	return s
+}
+
+func structuredBatch(t *testing.T, batch service.MessageBatch) []any {
+	t.Helper()
+	var values []any
+	for _, msg := range batch {
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestOTLPServerInputGRPCRoundTrip(t *testing.T) {
+	s := startServerInput(t, `
+grpc_address: 127.0.0.1:0
+http_address: ""
+`)
+
+	outConf, err := outputSpec().ParseYAML(`
+signal: logs
+endpoint: `+s.grpcListener.Addr().String()+`
+service_name: checkout
+backoff:
+  max_elapsed_time: 1ms
+`, nil)
+	require.NoError(t, err)
+	o, err := newOTLPOutputFromParsed(outConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(t.Context()))
+	t.Cleanup(func() { _ = o.Close(context.Background()) })
+
+	for _, ackErr := range []error{nil, errors.New("nope")} {
+		writeErr := make(chan error, 1)
+		go func() {
+			writeErr <- o.WriteBatch(t.Context(), service.MessageBatch{
+				service.NewMessage([]byte(`{"body":"hello","severity_text":"INFO","timestamp":"2024-01-02T15:04:05Z","attributes":{"n":1},"trace_id":"5b8efff798038103d269b633813fc60c"}`)),
+				service.NewMessage([]byte(`{"body":{"nested":[true,1.5]}}`)),
+			})
+		}()
+
+		batch, ackFn, err := s.ReadBatch(t.Context())
+		require.NoError(t, err)
+		require.Len(t, batch, 2)
+
+		values := structuredBatch(t, batch)
+		obs := values[0].(map[string]any)["observed_timestamp"]
+		assert.Equal(t, map[string]any{
+			"body":               "hello",
+			"severity_text":      "INFO",
+			"severity_number":    int64(9),
+			"timestamp":          "2024-01-02T15:04:05Z",
+			"observed_timestamp": obs,
+			"attributes":         map[string]any{"n": int64(1)},
+			"trace_id":           "5b8efff798038103d269b633813fc60c",
+		}, values[0])
+		assert.Equal(t, map[string]any{"nested": []any{true, 1.5}}, values[1].(map[string]any)["body"])
+
+		signal, _ := batch[0].MetaGetMut("otlp_signal")
+		assert.Equal(t, "logs", signal)
+		attrs, _ := batch[0].MetaGetMut("otlp_resource_attributes")
+		assert.Equal(t, map[string]any{"service.name": "checkout"}, attrs)
+		scopeName, _ := batch[0].MetaGetMut("otlp_scope_name")
+		assert.Equal(t, "redpanda-connect", scopeName)
+
+		require.NoError(t, ackFn(t.Context(), ackErr))
+		if ackErr == nil {
+			require.NoError(t, <-writeErr)
+		} else {
+			require.ErrorContains(t, <-writeErr, "Unavailable")
+		}
+	}
+}
+
+func TestOTLPServerInputHTTPJSON(t *testing.T) {
+	s := startServerInput(t, `
+grpc_address: ""
+http_address: 127.0.0.1:0
+`)
+	url := "http://" + s.httpListener.Addr().String()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte(`{
+  "resourceSpans": [{
+    "resource": { "attributes": [{ "key": "service.name", "value": { "stringValue": "api" } }] },
+    "scopeSpans": [{
+      "scope": { "name": "tracer", "version": "1.0.0" },
+      "spans": [{
+        "traceId": "5b8efff798038103d269b633813fc60c",
+        "spanId": "eee19b7ec3c1b174",
+        "name": "GET /orders",
+        "kind": 2,
+        "startTimeUnixNano": "1704207845000000000",
+        "endTimeUnixNano": "1704207846000000000",
+        "attributes": [{ "key": "http.response.status_code", "value": { "intValue": "200" } }],
+        "events": [{ "name": "retry", "timeUnixNano": "1704207845500000000" }],
+        "status": { "code": 2, "message": "timed out" }
+      }]
+    }]
+  }]
+}`))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	type result struct {
+		res *http.Response
+		err error
+	}
+	resChan := make(chan result, 1)
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, url+"/v1/traces", &buf)
+		if err != nil {
+			resChan <- result{err: err}
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+		res, err := http.DefaultClient.Do(req)
+		resChan <- result{res: res, err: err}
+	}()
+
+	batch, ackFn, err := s.ReadBatch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{
+		"trace_id":   "5b8efff798038103d269b633813fc60c",
+		"span_id":    "eee19b7ec3c1b174",
+		"name":       "GET /orders",
+		"kind":       "server",
+		"start_time": "2024-01-02T15:04:05Z",
+		"end_time":   "2024-01-02T15:04:06Z",
+		"attributes": map[string]any{"http.response.status_code": int64(200)},
+		"events": []any{map[string]any{
+			"name":      "retry",
+			"timestamp": "2024-01-02T15:04:05.5Z",
+		}},
+		"status": map[string]any{"code": "error", "message": "timed out"},
+	}}, structuredBatch(t, batch))
+	scopeVersion, _ := batch[0].MetaGetMut("otlp_scope_version")
+	assert.Equal(t, "1.0.0", scopeVersion)
+	require.NoError(t, ackFn(t.Context(), nil))
+
+	r := <-resChan
+	require.NoError(t, r.err)
+	defer r.res.Body.Close()
+	assert.Equal(t, http.StatusOK, r.res.StatusCode)
+	assert.Equal(t, "application/json", r.res.Header.Get("Content-Type"))
+
+	res, err := http.Post(url+"/v1/logs", "text/plain", bytes.NewReader(nil))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+
+	res, err = http.Post(url+"/v1/logs", "application/x-protobuf", bytes.NewReader([]byte("not protobuf")))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestOTLPMetricsBatch(t *testing.T) {
+	sum := 12.5
+	batch := metricsBatch(&colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: keyValues(map[string]any{"host": "a"})},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope: &commonpb.InstrumentationScope{Name: "meter"},
+				Metrics: []*metricspb.Metric{
+					{
+						Name: "latency",
+						Unit: "ms",
+						Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+							DataPoints: []*metricspb.HistogramDataPoint{{
+								TimeUnixNano:   uint64(time.Unix(100, 0).UnixNano()),
+								Count:          3,
+								Sum:            &sum,
+								BucketCounts:   []uint64{1, 2},
+								ExplicitBounds: []float64{5},
+							}},
+						}},
+					},
+					{
+						Name: "requests",
+						Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+							IsMonotonic:            true,
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							DataPoints: []*metricspb.NumberDataPoint{
+								{Value: &metricspb.NumberDataPoint_AsInt{AsInt: 4}, Attributes: keyValues(map[string]any{"code": "200"})},
+								{Value: &metricspb.NumberDataPoint_AsInt{AsInt: 1}, Attributes: keyValues(map[string]any{"code": "500"})},
+							},
+						}},
+					},
+				},
+			}},
+		}},
+	})
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"name":            "latency",
+			"unit":            "ms",
+			"type":            "histogram",
+			"temporality":     "delta",
+			"timestamp":       "1970-01-01T00:01:40Z",
+			"count":           int64(3),
+			"sum":             12.5,
+			"bucket_counts":   []any{int64(1), int64(2)},
+			"explicit_bounds": []any{5.0},
+		},
+		map[string]any{
+			"name":        "requests",
+			"type":        "sum",
+			"monotonic":   true,
+			"temporality": "cumulative",
+			"value":       int64(4),
+			"attributes":  map[string]any{"code": "200"},
+		},
+		map[string]any{
+			"name":        "requests",
+			"type":        "sum",
+			"monotonic":   true,
+			"temporality": "cumulative",
+			"value":       int64(1),
+			"attributes":  map[string]any{"code": "500"},
+		},
+	}, structuredBatch(t, batch))
+}
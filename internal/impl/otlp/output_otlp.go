@@ -0,0 +1,546 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ooFieldSignal             = "signal"
+	ooFieldProtocol           = "protocol"
+	ooFieldEndpoint           = "endpoint"
+	ooFieldHeaders            = "headers"
+	ooFieldServiceName        = "service_name"
+	ooFieldResourceAttributes = "resource_attributes"
+	ooFieldMapping            = "mapping"
+	ooFieldTLS                = "tls"
+	ooFieldTimeout            = "timeout"
+	ooFieldBackoff            = "backoff"
+	ooFieldBatching           = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	retryDefaults := backoff.NewExponentialBackOff()
+	retryDefaults.InitialInterval = time.Second
+	retryDefaults.MaxInterval = time.Second * 30
+	retryDefaults.MaxElapsedTime = time.Minute * 5
+
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Exports messages as OpenTelemetry log records, spans or metrics to an OTLP endpoint over gRPC or HTTP.").
+		Description(`
+Each batch of messages is exported with a single request to an https://opentelemetry.io/docs/specs/otlp/[OTLP^] endpoint such as an OpenTelemetry collector or an observability backend, where each message is converted into a log record, span or metric data point depending on the `+"`signal`"+`. Requests that fail with a retryable error are attempted again according to the `+"`backoff`"+` field, after which the batch is rejected.
+
+Messages are expected to be objects with the fields of the signal described below, and a `+"`mapping`"+` can be provided in order to convert messages into this structure. Timestamps can be RFC 3339 strings or unix timestamps in seconds, and fields that are not set are omitted.
+
+=== Logs
+
+`+"```json"+`
+{
+  "timestamp": "2024-01-02T15:04:05Z",
+  "severity_text": "ERROR",
+  "severity_number": 17,
+  "body": "failed to process order",
+  "attributes": { "order_id": "abc123" },
+  "trace_id": "5b8efff798038103d269b633813fc60c",
+  "span_id": "eee19b7ec3c1b174",
+  "event_name": "order.failed"
+}
+`+"```"+`
+
+The timestamp defaults to the time of export, and the severity number is derived from the severity text when omitted. Messages that are not objects are exported with their contents as the body.
+
+=== Traces
+
+`+"```json"+`
+{
+  "trace_id": "5b8efff798038103d269b633813fc60c",
+  "span_id": "eee19b7ec3c1b174",
+  "parent_span_id": "eee19b7ec3c1b173",
+  "name": "GET /orders",
+  "kind": "server",
+  "start_time": "2024-01-02T15:04:05Z",
+  "end_time": "2024-01-02T15:04:06Z",
+  "attributes": { "http.response.status_code": 200 },
+  "status": { "code": "error", "message": "timed out" }
+}
+`+"```"+`
+
+The `+"`trace_id` and `span_id`"+` fields are required, the `+"`kind`"+` can be one of `+"`internal`, `server`, `client`, `producer` or `consumer`"+`, and the `+"`status.code`"+` can be one of `+"`unset`, `ok` or `error`"+`.
+
+=== Metrics
+
+`+"```json"+`
+{
+  "name": "orders_processed",
+  "description": "The number of orders processed.",
+  "unit": "1",
+  "type": "sum",
+  "monotonic": true,
+  "value": 42,
+  "timestamp": "2024-01-02T15:04:05Z",
+  "start_time": "2024-01-02T15:00:00Z",
+  "attributes": { "region": "eu" }
+}
+`+"```"+`
+
+The `+"`type`"+` can be either `+"`gauge`"+` (the default) or `+"`sum`"+`, where sums are cumulative. Data points of a batch that share a name, description, unit and type are exported as a single metric.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringEnumField(ooFieldSignal, "logs", "traces", "metrics").
+				Description("The type of telemetry that messages are exported as."),
+			service.NewStringEnumField(ooFieldProtocol, "grpc", "http").
+				Description("The protocol to export with, where `http` sends binary protobuf requests.").
+				Default("grpc"),
+			service.NewStringField(ooFieldEndpoint).
+				Description("The endpoint to export to. For the `grpc` protocol this is the address of the endpoint, and for the `http` protocol this is a URL where the default path of the signal, such as `/v1/logs`, is used when the URL has no path.").
+				Example("localhost:4317").
+				Example("http://localhost:4318"),
+			service.NewStringMapField(ooFieldHeaders).
+				Description("A map of headers, or gRPC metadata, to add to each request.").
+				Example(map[string]any{"Authorization": "Bearer ${OTLP_TOKEN}"}).
+				Default(map[string]any{}),
+			service.NewStringField(ooFieldServiceName).
+				Description("The name of the service that exported telemetry is attributed to.").
+				Default("redpanda-connect"),
+			service.NewStringMapField(ooFieldResourceAttributes).
+				Description("A map of attributes added to the resource of exported telemetry.").
+				Example(map[string]any{"deployment.environment": "production"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewBloblangField(ooFieldMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that converts each message into the structure of a log record, span or metric data point.").
+				Example(`root.body = this.message
+root.severity_text = this.level
+root.timestamp = this.time
+root.attributes = this.without("message", "level", "time")`).
+				Optional(),
+			service.NewTLSToggledField(ooFieldTLS),
+			service.NewDurationField(ooFieldTimeout).
+				Description("The maximum period of time to wait for each request.").
+				Default("10s").
+				Advanced(),
+			service.NewBackOffField(ooFieldBackoff, false, retryDefaults).
+				Description("The backoff applied between attempts to export a batch that failed with a retryable error, after which the batch is rejected.").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ooFieldBatching),
+		).
+		Example("Route application logs", "Exports JSON application logs consumed from Kafka as OpenTelemetry log records.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ app_logs ]
+    consumer_group: otlp
+
+output:
+  otlp:
+    signal: logs
+    endpoint: localhost:4317
+    service_name: checkout
+    mapping: |
+      root.body = this.msg
+      root.severity_text = this.level
+      root.timestamp = this.ts
+      root.attributes = this.without("msg", "level", "ts")
+    batching:
+      count: 500
+      period: 1s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"otlp", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ooFieldBatching); err != nil {
+				return
+			}
+			out, err = newOTLPOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+type otlpOutput struct {
+	signal     string
+	protocol   string
+	endpoint   string
+	headers    map[string]string
+	resource   *resourcepb.Resource
+	scope      *commonpb.InstrumentationScope
+	mapping    *bloblang.Executor
+	tlsConf    *tls.Config
+	tlsEnabled bool
+	timeout    time.Duration
+	backoff    *backoff.ExponentialBackOff
+	log        *service.Logger
+	nowFn      func() time.Time
+
+	mut        sync.Mutex
+	conn       *grpc.ClientConn
+	httpClient *http.Client
+}
+
+func newOTLPOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*otlpOutput, error) {
+	o := &otlpOutput{
+		log:   mgr.Logger(),
+		nowFn: time.Now,
+		scope: &commonpb.InstrumentationScope{
+			Name:    "redpanda-connect",
+			Version: conf.EngineVersion(),
+		},
+	}
+
+	var err error
+	if o.signal, err = conf.FieldString(ooFieldSignal); err != nil {
+		return nil, err
+	}
+	if o.protocol, err = conf.FieldString(ooFieldProtocol); err != nil {
+		return nil, err
+	}
+	if o.endpoint, err = conf.FieldString(ooFieldEndpoint); err != nil {
+		return nil, err
+	}
+	if o.headers, err = conf.FieldStringMap(ooFieldHeaders); err != nil {
+		return nil, err
+	}
+
+	serviceName, err := conf.FieldString(ooFieldServiceName)
+	if err != nil {
+		return nil, err
+	}
+	resourceAttrs, err := conf.FieldStringMap(ooFieldResourceAttributes)
+	if err != nil {
+		return nil, err
+	}
+	attrs := map[string]any{"service.name": serviceName}
+	for k, v := range resourceAttrs {
+		attrs[k] = v
+	}
+	o.resource = &resourcepb.Resource{Attributes: keyValues(attrs)}
+
+	if conf.Contains(ooFieldMapping) {
+		if o.mapping, err = conf.FieldBloblang(ooFieldMapping); err != nil {
+			return nil, err
+		}
+	}
+	if o.tlsConf, o.tlsEnabled, err = conf.FieldTLSToggled(ooFieldTLS); err != nil {
+		return nil, err
+	}
+	if o.timeout, err = conf.FieldDuration(ooFieldTimeout); err != nil {
+		return nil, err
+	}
+	if o.backoff, err = conf.FieldBackOff(ooFieldBackoff); err != nil {
+		return nil, err
+	}
+
+	if o.protocol == "http" {
+		u, err := url.Parse(o.endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse endpoint: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("endpoint %q must be a URL when the protocol is http", o.endpoint)
+		}
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/v1/" + o.signal
+		}
+		o.endpoint = u.String()
+	}
+	return o, nil
+}
+
+func (o *otlpOutput) Connect(context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.protocol == "http" {
+		if o.httpClient != nil {
+			return nil
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if o.tlsEnabled {
+			transport.TLSClientConfig = o.tlsConf
+		}
+		o.httpClient = &http.Client{Transport: transport, Timeout: o.timeout}
+		return nil
+	}
+
+	if o.conn != nil {
+		return nil
+	}
+	creds := insecure.NewCredentials()
+	if o.tlsEnabled {
+		creds = credentials.NewTLS(o.tlsConf)
+	}
+	conn, err := grpc.NewClient(o.endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	o.conn = conn
+	return nil
+}
+
+func (o *otlpOutput) exportRequest(batch service.MessageBatch) (proto.Message, error) {
+	var exec *service.MessageBatchBloblangExecutor
+	if o.mapping != nil {
+		exec = batch.BloblangExecutor(o.mapping)
+	}
+
+	records := make([]otlpRecord, len(batch))
+	for i, msg := range batch {
+		if exec != nil {
+			var err error
+			if msg, err = exec.Query(i); err != nil {
+				return nil, fmt.Errorf("mapping failed: %w", err)
+			}
+			if msg == nil {
+				return nil, fmt.Errorf("mapping of message %v resulted in deletion", i)
+			}
+		}
+		records[i].raw, _ = msg.AsBytes()
+		if v, err := msg.AsStructured(); err == nil {
+			records[i].fields, _ = v.(map[string]any)
+		}
+	}
+
+	now := o.nowFn()
+	switch o.signal {
+	case "traces":
+		return tracesRequest(o.resource, o.scope, records, now)
+	case "metrics":
+		return metricsRequest(o.resource, o.scope, records, now)
+	}
+	return logsRequest(o.resource, o.scope, records, now)
+}
+
+// retryableError is a failed export that may succeed when attempted again,
+// optionally after a delay requested by the endpoint.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *retryableError) Error() string {
+	return r.err.Error()
+}
+
+func (r *retryableError) Unwrap() error {
+	return r.err
+}
+
+func (o *otlpOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	req, err := o.exportRequest(batch)
+	if err != nil {
+		return err
+	}
+
+	boff := *o.backoff
+	boff.Reset()
+	for {
+		err := o.export(ctx, req)
+		var rErr *retryableError
+		if !errors.As(err, &rErr) {
+			return err
+		}
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		wait = max(wait, rErr.retryAfter)
+		o.log.Debugf("Retrying export after %v: %v", wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (o *otlpOutput) export(ctx context.Context, req proto.Message) error {
+	var res proto.Message
+	var err error
+	if o.protocol == "http" {
+		res, err = o.exportHTTP(ctx, req)
+	} else {
+		res, err = o.exportGRPC(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	var rejected int64
+	var msg string
+	switch t := res.(type) {
+	case *collogspb.ExportLogsServiceResponse:
+		rejected, msg = t.GetPartialSuccess().GetRejectedLogRecords(), t.GetPartialSuccess().GetErrorMessage()
+	case *coltracepb.ExportTraceServiceResponse:
+		rejected, msg = t.GetPartialSuccess().GetRejectedSpans(), t.GetPartialSuccess().GetErrorMessage()
+	case *colmetricspb.ExportMetricsServiceResponse:
+		rejected, msg = t.GetPartialSuccess().GetRejectedDataPoints(), t.GetPartialSuccess().GetErrorMessage()
+	}
+	if rejected > 0 {
+		o.log.Warnf("Endpoint rejected %v %v: %v", rejected, o.signal, msg)
+	}
+	return nil
+}
+
+func (o *otlpOutput) exportGRPC(ctx context.Context, req proto.Message) (proto.Message, error) {
+	o.mut.Lock()
+	conn := o.conn
+	o.mut.Unlock()
+	if conn == nil {
+		return nil, service.ErrNotConnected
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	for k, v := range o.headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+	}
+
+	var res proto.Message
+	var err error
+	switch t := req.(type) {
+	case *collogspb.ExportLogsServiceRequest:
+		res, err = collogspb.NewLogsServiceClient(conn).Export(ctx, t)
+	case *coltracepb.ExportTraceServiceRequest:
+		res, err = coltracepb.NewTraceServiceClient(conn).Export(ctx, t)
+	case *colmetricspb.ExportMetricsServiceRequest:
+		res, err = colmetricspb.NewMetricsServiceClient(conn).Export(ctx, t)
+	}
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.DataLoss:
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func (o *otlpOutput) exportHTTP(ctx context.Context, req proto.Message) (proto.Message, error) {
+	o.mut.Lock()
+	client := o.httpClient
+	o.mut.Unlock()
+	if client == nil {
+		return nil, service.ErrNotConnected
+	}
+
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hReq.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range o.headers {
+		hReq.Header.Set(k, v)
+	}
+
+	hRes, err := client.Do(hReq)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+	defer hRes.Body.Close()
+	resBody, err := io.ReadAll(io.LimitReader(hRes.Body, 1<<20))
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+
+	if hRes.StatusCode < 200 || hRes.StatusCode > 299 {
+		msg := string(bytes.TrimSpace(resBody))
+		var s statuspb.Status
+		if strings.HasPrefix(hRes.Header.Get("Content-Type"), "application/x-protobuf") && proto.Unmarshal(resBody, &s) == nil {
+			msg = s.GetMessage()
+		}
+		err := fmt.Errorf("export failed with status %v: %s", hRes.StatusCode, msg)
+		switch hRes.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			var retryAfter time.Duration
+			if secs, err := strconv.Atoi(hRes.Header.Get("Retry-After")); err == nil {
+				retryAfter = time.Duration(secs) * time.Second
+			}
+			return nil, &retryableError{err: err, retryAfter: retryAfter}
+		}
+		return nil, err
+	}
+
+	var res proto.Message
+	switch req.(type) {
+	case *collogspb.ExportLogsServiceRequest:
+		res = &collogspb.ExportLogsServiceResponse{}
+	case *coltracepb.ExportTraceServiceRequest:
+		res = &coltracepb.ExportTraceServiceResponse{}
+	case *colmetricspb.ExportMetricsServiceRequest:
+		res = &colmetricspb.ExportMetricsServiceResponse{}
+	}
+	if err := proto.Unmarshal(resBody, res); err != nil {
+		o.log.Debugf("Failed to parse export response: %v", err)
+	}
+	return res, nil
+}
+
+func (o *otlpOutput) Close(context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.httpClient != nil {
+		o.httpClient.CloseIdleConnections()
+		o.httpClient = nil
+	}
+	if o.conn != nil {
+		err := o.conn.Close()
+		o.conn = nil
+		return err
+	}
+	return nil
+}
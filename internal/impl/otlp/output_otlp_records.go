@@ -0,0 +1,426 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// otlpRecord is the structured form of a message along with the contents it
+// was parsed from.
+type otlpRecord struct {
+	fields map[string]any
+	raw    []byte
+}
+
+func (r otlpRecord) get(key string) (any, bool) {
+	v, exists := r.fields[key]
+	return v, exists && v != nil
+}
+
+func (r otlpRecord) str(key string) (string, error) {
+	v, exists := r.get(key)
+	if !exists {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("field %v must be a string, got %T", key, v)
+	}
+	return s, nil
+}
+
+func (r otlpRecord) timestamp(key string, def time.Time) (uint64, error) {
+	v, exists := r.get(key)
+	if !exists {
+		return uint64(def.UnixNano()), nil
+	}
+	t, err := bloblang.ValueAsTimestamp(v)
+	if err != nil {
+		return 0, fmt.Errorf("field %v: %w", key, err)
+	}
+	return uint64(t.UnixNano()), nil
+}
+
+func (r otlpRecord) id(key string, size int) ([]byte, error) {
+	s, err := r.str(key)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	id, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("field %v: %w", key, err)
+	}
+	if len(id) != size {
+		return nil, fmt.Errorf("field %v must be %v hex encoded bytes, got %v", key, size, len(id))
+	}
+	return id, nil
+}
+
+func (r otlpRecord) attributes(key string) ([]*commonpb.KeyValue, error) {
+	v, exists := r.get(key)
+	if !exists {
+		return nil, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("field %v must be an object, got %T", key, v)
+	}
+	return keyValues(obj), nil
+}
+
+func keyValues(obj map[string]any) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(obj))
+	for _, k := range slices.Sorted(maps.Keys(obj)) {
+		kvs = append(kvs, &commonpb.KeyValue{Key: k, Value: anyValue(obj[k])})
+	}
+	return kvs
+}
+
+func anyValue(v any) *commonpb.AnyValue {
+	switch t := v.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: t}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: t}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: t}}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		}
+		f, _ := t.Float64()
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case int, int32, int64, uint, uint32, uint64:
+		i, _ := bloblang.ValueAsInt64(t)
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+	case float32, float64:
+		f, _ := bloblang.ValueAsFloat64(t)
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case []any:
+		values := make([]*commonpb.AnyValue, 0, len(t))
+		for _, e := range t {
+			values = append(values, anyValue(e))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	case map[string]any:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: keyValues(t)}}}
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: bloblang.ValueToString(v)}}
+}
+
+//------------------------------------------------------------------------------
+
+var severityNumbers = map[string]logspb.SeverityNumber{
+	"TRACE":   logspb.SeverityNumber_SEVERITY_NUMBER_TRACE,
+	"DEBUG":   logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+	"INFO":    logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+	"WARN":    logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+	"WARNING": logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+	"ERROR":   logspb.SeverityNumber_SEVERITY_NUMBER_ERROR,
+	"FATAL":   logspb.SeverityNumber_SEVERITY_NUMBER_FATAL,
+}
+
+func logRecord(r otlpRecord, now time.Time) (*logspb.LogRecord, error) {
+	if r.fields == nil {
+		return &logspb.LogRecord{
+			TimeUnixNano:         uint64(now.UnixNano()),
+			ObservedTimeUnixNano: uint64(now.UnixNano()),
+			Body:                 anyValue(string(r.raw)),
+		}, nil
+	}
+
+	rec := &logspb.LogRecord{ObservedTimeUnixNano: uint64(now.UnixNano())}
+	var err error
+	if rec.TimeUnixNano, err = r.timestamp("timestamp", now); err != nil {
+		return nil, err
+	}
+	if rec.SeverityText, err = r.str("severity_text"); err != nil {
+		return nil, err
+	}
+	if v, exists := r.get("severity_number"); exists {
+		n, err := bloblang.ValueAsInt64(v)
+		if err != nil {
+			return nil, fmt.Errorf("field severity_number: %w", err)
+		}
+		rec.SeverityNumber = logspb.SeverityNumber(n)
+	} else {
+		rec.SeverityNumber = severityNumbers[strings.ToUpper(rec.SeverityText)]
+	}
+	if body, exists := r.get("body"); exists {
+		rec.Body = anyValue(body)
+	}
+	if rec.Attributes, err = r.attributes("attributes"); err != nil {
+		return nil, err
+	}
+	if rec.TraceId, err = r.id("trace_id", 16); err != nil {
+		return nil, err
+	}
+	if rec.SpanId, err = r.id("span_id", 8); err != nil {
+		return nil, err
+	}
+	if rec.EventName, err = r.str("event_name"); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func logsRequest(resource *resourcepb.Resource, scope *commonpb.InstrumentationScope, records []otlpRecord, now time.Time) (*collogspb.ExportLogsServiceRequest, error) {
+	logs := make([]*logspb.LogRecord, 0, len(records))
+	for i, r := range records {
+		rec, err := logRecord(r, now)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		logs = append(logs, rec)
+	}
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource:  resource,
+			ScopeLogs: []*logspb.ScopeLogs{{Scope: scope, LogRecords: logs}},
+		}},
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+var spanKinds = map[string]tracepb.Span_SpanKind{
+	"":         tracepb.Span_SPAN_KIND_UNSPECIFIED,
+	"internal": tracepb.Span_SPAN_KIND_INTERNAL,
+	"server":   tracepb.Span_SPAN_KIND_SERVER,
+	"client":   tracepb.Span_SPAN_KIND_CLIENT,
+	"producer": tracepb.Span_SPAN_KIND_PRODUCER,
+	"consumer": tracepb.Span_SPAN_KIND_CONSUMER,
+}
+
+var statusCodes = map[string]tracepb.Status_StatusCode{
+	"":      tracepb.Status_STATUS_CODE_UNSET,
+	"unset": tracepb.Status_STATUS_CODE_UNSET,
+	"ok":    tracepb.Status_STATUS_CODE_OK,
+	"error": tracepb.Status_STATUS_CODE_ERROR,
+}
+
+func span(r otlpRecord, now time.Time) (*tracepb.Span, error) {
+	if r.fields == nil {
+		return nil, fmt.Errorf("spans must be objects, got %q", r.raw)
+	}
+
+	s := &tracepb.Span{}
+	var err error
+	if s.TraceId, err = r.id("trace_id", 16); err != nil {
+		return nil, err
+	}
+	if s.SpanId, err = r.id("span_id", 8); err != nil {
+		return nil, err
+	}
+	if s.TraceId == nil || s.SpanId == nil {
+		return nil, errors.New("spans must have a trace_id and span_id")
+	}
+	if s.ParentSpanId, err = r.id("parent_span_id", 8); err != nil {
+		return nil, err
+	}
+	if s.TraceState, err = r.str("trace_state"); err != nil {
+		return nil, err
+	}
+	if s.Name, err = r.str("name"); err != nil {
+		return nil, err
+	}
+
+	kind, err := r.str("kind")
+	if err != nil {
+		return nil, err
+	}
+	var ok bool
+	if s.Kind, ok = spanKinds[strings.ToLower(kind)]; !ok {
+		return nil, fmt.Errorf("field kind has unrecognised value %q", kind)
+	}
+
+	if s.EndTimeUnixNano, err = r.timestamp("end_time", now); err != nil {
+		return nil, err
+	}
+	if s.StartTimeUnixNano, err = r.timestamp("start_time", time.Unix(0, int64(s.EndTimeUnixNano))); err != nil {
+		return nil, err
+	}
+	if s.Attributes, err = r.attributes("attributes"); err != nil {
+		return nil, err
+	}
+
+	if v, exists := r.get("status"); exists {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field status must be an object, got %T", v)
+		}
+		status := otlpRecord{fields: obj}
+		code, err := status.str("code")
+		if err != nil {
+			return nil, err
+		}
+		s.Status = &tracepb.Status{}
+		if s.Status.Code, ok = statusCodes[strings.ToLower(code)]; !ok {
+			return nil, fmt.Errorf("field code has unrecognised value %q", code)
+		}
+		if s.Status.Message, err = status.str("message"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func tracesRequest(resource *resourcepb.Resource, scope *commonpb.InstrumentationScope, records []otlpRecord, now time.Time) (*coltracepb.ExportTraceServiceRequest, error) {
+	spans := make([]*tracepb.Span, 0, len(records))
+	for i, r := range records {
+		s, err := span(r, now)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		spans = append(spans, s)
+	}
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource:   resource,
+			ScopeSpans: []*tracepb.ScopeSpans{{Scope: scope, Spans: spans}},
+		}},
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// metricKey identifies the metric that a data point belongs to.
+type metricKey struct {
+	name, description, unit, typ string
+	monotonic                    bool
+}
+
+func numberDataPoint(r otlpRecord, now time.Time) (*metricspb.NumberDataPoint, error) {
+	dp := &metricspb.NumberDataPoint{}
+	var err error
+	if dp.TimeUnixNano, err = r.timestamp("timestamp", now); err != nil {
+		return nil, err
+	}
+	if _, exists := r.get("start_time"); exists {
+		if dp.StartTimeUnixNano, err = r.timestamp("start_time", now); err != nil {
+			return nil, err
+		}
+	}
+	if dp.Attributes, err = r.attributes("attributes"); err != nil {
+		return nil, err
+	}
+
+	v, exists := r.get("value")
+	if !exists {
+		return nil, errors.New("metrics must have a value")
+	}
+	switch t := anyValue(v).Value.(type) {
+	case *commonpb.AnyValue_IntValue:
+		dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: t.IntValue}
+	case *commonpb.AnyValue_DoubleValue:
+		dp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: t.DoubleValue}
+	default:
+		return nil, fmt.Errorf("field value must be a number, got %T", v)
+	}
+	return dp, nil
+}
+
+func metricsRequest(resource *resourcepb.Resource, scope *commonpb.InstrumentationScope, records []otlpRecord, now time.Time) (*colmetricspb.ExportMetricsServiceRequest, error) {
+	var metrics []*metricspb.Metric
+	metricIndexes := map[metricKey]int{}
+	for i, r := range records {
+		if r.fields == nil {
+			return nil, fmt.Errorf("message %v: metrics must be objects, got %q", i, r.raw)
+		}
+
+		var key metricKey
+		var err error
+		if key.name, err = r.str("name"); err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if key.name == "" {
+			return nil, fmt.Errorf("message %v: metrics must have a name", i)
+		}
+		if key.description, err = r.str("description"); err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if key.unit, err = r.str("unit"); err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if key.typ, err = r.str("type"); err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if key.typ == "" {
+			key.typ = "gauge"
+		}
+		if v, exists := r.get("monotonic"); exists {
+			if key.monotonic, err = bloblang.ValueAsBool(v); err != nil {
+				return nil, fmt.Errorf("message %v: field monotonic: %w", i, err)
+			}
+		}
+
+		dp, err := numberDataPoint(r, now)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+
+		mi, exists := metricIndexes[key]
+		if !exists {
+			m := &metricspb.Metric{Name: key.name, Description: key.description, Unit: key.unit}
+			switch key.typ {
+			case "gauge":
+				m.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{}}
+			case "sum":
+				m.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					IsMonotonic:            key.monotonic,
+				}}
+			default:
+				return nil, fmt.Errorf("message %v: field type has unrecognised value %q", i, key.typ)
+			}
+			mi = len(metrics)
+			metricIndexes[key] = mi
+			metrics = append(metrics, m)
+		}
+		switch d := metrics[mi].Data.(type) {
+		case *metricspb.Metric_Gauge:
+			d.Gauge.DataPoints = append(d.Gauge.DataPoints, dp)
+		case *metricspb.Metric_Sum:
+			d.Sum.DataPoints = append(d.Sum.DataPoints, dp)
+		}
+	}
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource:     resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{Scope: scope, Metrics: metrics}},
+		}},
+	}, nil
+}
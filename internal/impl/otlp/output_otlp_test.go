@@ -0,0 +1,252 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeLogsService struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	reqs     chan *collogspb.ExportLogsServiceRequest
+	metadata chan metadata.MD
+}
+
+func (f *fakeLogsService) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.metadata <- md
+	f.reqs <- req
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func TestOTLPOutputLogsGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	svc := &fakeLogsService{
+		reqs:     make(chan *collogspb.ExportLogsServiceRequest, 1),
+		metadata: make(chan metadata.MD, 1),
+	}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, svc)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conf, err := outputSpec().ParseYAML(`
+signal: logs
+endpoint: `+lis.Addr().String()+`
+service_name: checkout
+headers:
+  x-tenant: acme
+resource_attributes:
+  deployment.environment: test
+mapping: |
+  root = match this.catch(null) {
+    this == null => content().string()
+    _ => {
+      "body": this.msg,
+      "severity_text": this.level,
+      "timestamp": this.ts,
+      "attributes": this.without("msg", "level", "ts"),
+    }
+  }
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newOTLPOutputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+	o.nowFn = func() time.Time { return time.Unix(10, 0) }
+	require.NoError(t, o.Connect(t.Context()))
+	t.Cleanup(func() { _ = o.Close(context.Background()) })
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"msg":"order failed","level":"error","ts":1700000000,"order_id":"abc","retries":2}`)),
+		service.NewMessage([]byte(`plain log line`)),
+	}))
+
+	assert.Equal(t, []string{"acme"}, (<-svc.metadata).Get("x-tenant"))
+
+	req := <-svc.reqs
+	require.Len(t, req.ResourceLogs, 1)
+	assert.Equal(t, []*commonpb.KeyValue{
+		{Key: "deployment.environment", Value: anyValue("test")},
+		{Key: "service.name", Value: anyValue("checkout")},
+	}, req.ResourceLogs[0].Resource.Attributes)
+
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+	assert.True(t, proto.Equal(&logspb.LogRecord{
+		TimeUnixNano:         uint64(time.Unix(1700000000, 0).UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Unix(10, 0).UnixNano()),
+		SeverityText:         "error",
+		SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_ERROR,
+		Body:                 anyValue("order failed"),
+		Attributes: []*commonpb.KeyValue{
+			{Key: "order_id", Value: anyValue("abc")},
+			{Key: "retries", Value: anyValue(int64(2))},
+		},
+	}, records[0]), records[0].String())
+	assert.Equal(t, "plain log line", records[1].Body.GetStringValue())
+	assert.Equal(t, uint64(time.Unix(10, 0).UnixNano()), records[1].TimeUnixNano)
+}
+
+func TestOTLPOutputTracesHTTP(t *testing.T) {
+	var attempts int
+	reqs := make(chan *coltracepb.ExportTraceServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer foo", r.Header.Get("Authorization"))
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req coltracepb.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(b, &req))
+		reqs <- &req
+
+		res, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	conf, err := outputSpec().ParseYAML(`
+signal: traces
+protocol: http
+endpoint: `+srv.URL+`
+headers:
+  Authorization: Bearer foo
+backoff:
+  initial_interval: 1ms
+  max_interval: 1ms
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newOTLPOutputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(t.Context()))
+	t.Cleanup(func() { _ = o.Close(context.Background()) })
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{
+  "trace_id": "5b8efff798038103d269b633813fc60c",
+  "span_id": "eee19b7ec3c1b174",
+  "parent_span_id": "eee19b7ec3c1b173",
+  "name": "GET /orders",
+  "kind": "server",
+  "start_time": "2024-01-02T15:04:05Z",
+  "end_time": "2024-01-02T15:04:06Z",
+  "attributes": { "http.response.status_code": 200 },
+  "status": { "code": "error", "message": "timed out" }
+}`)),
+	}))
+	assert.Equal(t, 2, attempts)
+
+	req := <-reqs
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	assert.True(t, proto.Equal(&tracepb.Span{
+		TraceId:           []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c},
+		SpanId:            []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74},
+		ParentSpanId:      []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x73},
+		Name:              "GET /orders",
+		Kind:              tracepb.Span_SPAN_KIND_SERVER,
+		StartTimeUnixNano: uint64(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC).UnixNano()),
+		EndTimeUnixNano:   uint64(time.Date(2024, 1, 2, 15, 4, 6, 0, time.UTC).UnixNano()),
+		Attributes: []*commonpb.KeyValue{
+			{Key: "http.response.status_code", Value: anyValue(int64(200))},
+		},
+		Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "timed out"},
+	}, spans[0]), spans[0].String())
+}
+
+func TestOTLPMetricsRequest(t *testing.T) {
+	now := time.Unix(100, 0)
+	records := []otlpRecord{
+		{fields: map[string]any{"name": "temperature", "value": 21.5, "attributes": map[string]any{"room": "a"}}},
+		{fields: map[string]any{"name": "orders", "type": "sum", "monotonic": true, "value": int64(3), "timestamp": int64(50)}},
+		{fields: map[string]any{"name": "temperature", "value": 19.0, "attributes": map[string]any{"room": "b"}}},
+	}
+
+	req, err := metricsRequest(nil, nil, records, now)
+	require.NoError(t, err)
+
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "temperature", metrics[0].Name)
+	points := metrics[0].GetGauge().GetDataPoints()
+	require.Len(t, points, 2)
+	assert.InDelta(t, 21.5, points[0].GetAsDouble(), 0.0001)
+	assert.InDelta(t, 19.0, points[1].GetAsDouble(), 0.0001)
+	assert.Equal(t, uint64(now.UnixNano()), points[0].TimeUnixNano)
+
+	assert.Equal(t, "orders", metrics[1].Name)
+	sum := metrics[1].GetSum()
+	require.NotNil(t, sum)
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	assert.Equal(t, int64(3), sum.DataPoints[0].GetAsInt())
+	assert.Equal(t, uint64(time.Unix(50, 0).UnixNano()), sum.DataPoints[0].TimeUnixNano)
+}
+
+func TestOTLPRequestErrors(t *testing.T) {
+	now := time.Now()
+
+	_, err := tracesRequest(nil, nil, []otlpRecord{{fields: map[string]any{"name": "foo"}}}, now)
+	require.ErrorContains(t, err, "spans must have a trace_id and span_id")
+
+	_, err = tracesRequest(nil, nil, []otlpRecord{{fields: map[string]any{"trace_id": "abc", "span_id": "eee19b7ec3c1b174"}}}, now)
+	require.ErrorContains(t, err, "field trace_id")
+
+	_, err = tracesRequest(nil, nil, []otlpRecord{{fields: map[string]any{"trace_id": "5b8efff798038103d269b633813fc60c", "span_id": "eee19b7ec3c1b174", "kind": "nope"}}}, now)
+	require.ErrorContains(t, err, `field kind has unrecognised value "nope"`)
+
+	_, err = metricsRequest(nil, nil, []otlpRecord{{fields: map[string]any{"name": "foo", "value": "bar"}}}, now)
+	require.ErrorContains(t, err, "field value must be a number")
+
+	_, err = metricsRequest(nil, nil, []otlpRecord{{fields: map[string]any{"name": "foo", "value": 1, "type": "histogram"}}}, now)
+	require.ErrorContains(t, err, `field type has unrecognised value "histogram"`)
+
+	_, err = logsRequest(nil, nil, []otlpRecord{{fields: map[string]any{"attributes": "nope"}}}, now)
+	require.ErrorContains(t, err, "field attributes must be an object")
+}
@@ -0,0 +1,447 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	hpiFieldURL                = "url"
+	hpiFieldVerb               = "verb"
+	hpiFieldHeaders            = "headers"
+	hpiFieldBody               = "body"
+	hpiFieldRecords            = "records"
+	hpiFieldPagination         = "pagination"
+	hpiFieldPaginationMode     = "mode"
+	hpiFieldPaginationCursor   = "cursor_mapping"
+	hpiFieldPaginationParam    = "param"
+	hpiFieldPaginationStart    = "start"
+	hpiFieldPaginationLimitKey = "limit_param"
+	hpiFieldPaginationLimit    = "limit"
+	hpiFieldMaxPages           = "max_pages"
+	hpiFieldRateLimit          = "rate_limit_headers"
+	hpiFieldRateLimitRemaining = "remaining"
+	hpiFieldRateLimitReset     = "reset"
+	hpiFieldRetryDelay         = "retry_delay"
+	hpiFieldTLS                = "tls"
+)
+
+func inputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Drains a paginated HTTP API by following cursors, `Link` headers, page numbers or offsets until the last page has been read.").
+		Description(`
+Each page is requested in turn and split into records, where each record becomes a message and the records of a page are emitted as a batch. When `+"`"+hpiFieldRecords+"`"+` is not set a page containing a JSON array is split into its elements, and any other page is emitted as a single message. Once the last page has been consumed the input ends, which gracefully terminates the pipeline.
+
+== Pagination modes
+
+- `+"`cursor`"+`: The `+"`"+hpiFieldPaginationCursor+"`"+` is executed on each page in order to obtain the cursor of the next page, which is added to the URL as the query parameter `+"`"+hpiFieldPaginationParam+"`"+`. When the cursor is an absolute URL it is requested as is instead. Pagination stops once the mapping returns an empty string, `+"`null`"+` or deletes the root.
+- `+"`link_header`"+`: The target of the `+"`next`"+` relation of the https://datatracker.ietf.org/doc/html/rfc8288[`+"`Link`"+` header^] is requested until a page without one is returned.
+- `+"`page`"+`: The query parameter `+"`"+hpiFieldPaginationParam+"`"+` is set to `+"`"+hpiFieldPaginationStart+"`"+` and incremented for each page until a page without records is returned.
+- `+"`offset`"+`: The query parameter `+"`"+hpiFieldPaginationParam+"`"+` is advanced by the number of records of each page until a page with fewer than `+"`"+hpiFieldPaginationLimit+"`"+` records is returned.
+
+== Rate limits
+
+When a response carries the headers configured in `+"`"+hpiFieldRateLimit+"`"+` the remaining requests are spread evenly over the time left until the quota resets, and once the quota is exhausted the input waits for the reset before requesting the next page. The reset header may hold either a number of seconds or a unix timestamp. Responses with the status code 429 are retried after the delay given by the `+"`Retry-After`"+` header, or `+"`"+hpiFieldRetryDelay+"`"+` when absent.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- http_page
+- http_url
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(
+			service.NewURLField(hpiFieldURL).
+				Description("The URL of the first page.").
+				Example("https://api.example.com/v1/items"),
+			service.NewStringField(hpiFieldVerb).
+				Description("The HTTP verb to use for requests.").
+				Default("GET").
+				Advanced(),
+			service.NewInterpolatedStringMapField(hpiFieldHeaders).
+				Description("A map of headers to add to each request.").
+				Example(map[string]any{"Authorization": "Bearer ${! env(\"TOKEN\") }"}).
+				Default(map[string]any{}),
+			service.NewStringField(hpiFieldBody).
+				Description("An optional body to send with each request.").
+				Optional().
+				Advanced(),
+			service.NewBloblangField(hpiFieldRecords).
+				Description("An optional mapping executed on each page which must return an array of records, each of which is emitted as a message.").
+				Example("root = this.data").
+				Optional(),
+			service.NewObjectField(hpiFieldPagination,
+				service.NewStringEnumField(hpiFieldPaginationMode, "none", "cursor", "link_header", "page", "offset").
+					Description("The pagination mode of the API.").
+					Default("none"),
+				service.NewBloblangField(hpiFieldPaginationCursor).
+					Description("A mapping executed on each page which returns the cursor of the next page, required by the `cursor` mode.").
+					Example("root = this.next_cursor").
+					Example("root = this.links.next").
+					Optional(),
+				service.NewStringField(hpiFieldPaginationParam).
+					Description("The query parameter holding the cursor, page number or offset. Defaults to `cursor`, `page` or `offset` respectively when empty.").
+					Default(""),
+				service.NewIntField(hpiFieldPaginationStart).
+					Description("The number of the first page in the `page` mode.").
+					Default(1),
+				service.NewStringField(hpiFieldPaginationLimitKey).
+					Description("An optional query parameter set to `"+hpiFieldPaginationLimit+"` in the `offset` mode.").
+					Example("limit").
+					Default(""),
+				service.NewIntField(hpiFieldPaginationLimit).
+					Description("The number of records per page in the `offset` mode.").
+					Default(100),
+			).Description("Determines how the next page is requested."),
+			service.NewIntField(hpiFieldMaxPages).
+				Description("The maximum number of pages to consume, where zero means unlimited.").
+				Default(0).
+				Advanced(),
+			service.NewObjectField(hpiFieldRateLimit,
+				service.NewStringField(hpiFieldRateLimitRemaining).
+					Description("The header holding the number of requests remaining within the current window.").
+					Default("X-RateLimit-Remaining"),
+				service.NewStringField(hpiFieldRateLimitReset).
+					Description("The header holding the number of seconds until, or the unix timestamp of, the reset of the current window.").
+					Default("X-RateLimit-Reset"),
+			).Description("The response headers used to pace requests according to the rate limit of the API.").Advanced(),
+			service.NewDurationField(hpiFieldRetryDelay).
+				Description("The period to wait before retrying a request that failed or was rate limited without a `Retry-After` header.").
+				Default("1s").
+				Advanced(),
+			service.NewTLSToggledField(hpiFieldTLS),
+		).
+		Example(
+			"Cursor pagination",
+			"Drain an API that returns the cursor of the next page within the body.",
+			`
+input:
+  http_paginated:
+    url: https://api.example.com/v1/items?limit=100
+    headers:
+      Authorization: Bearer ${! env("API_TOKEN") }
+    records: root = this.data
+    pagination:
+      mode: cursor
+      cursor_mapping: root = this.meta.next_cursor
+      param: cursor
+`,
+		).
+		Example(
+			"GitHub issues",
+			"Drain the issues of a GitHub repository by following `Link` headers.",
+			`
+input:
+  http_paginated:
+    url: https://api.github.com/repos/redpanda-data/connect/issues?per_page=100
+    pagination:
+      mode: link_header
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchInput("http_paginated", inputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			i, err := newInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatched(i), nil
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type input struct {
+	log *service.Logger
+
+	verb       string
+	headers    map[string]*service.InterpolatedString
+	body       []byte
+	records    *bloblang.Executor
+	pager      paginator
+	maxPages   int
+	rateLimit  *rateLimitHeaders
+	retryDelay time.Duration
+	client     *http.Client
+
+	mut       sync.Mutex
+	nextURL   *url.URL
+	pages     int
+	waitUntil time.Time
+}
+
+func paginatorFromConfig(conf *service.ParsedConfig) (paginator, error) {
+	mode, err := conf.FieldString(hpiFieldPaginationMode)
+	if err != nil {
+		return nil, err
+	}
+	param, err := conf.FieldString(hpiFieldPaginationParam)
+	if err != nil {
+		return nil, err
+	}
+	if param == "" && mode != "none" && mode != "link_header" {
+		param = mode
+	}
+
+	switch mode {
+	case "none":
+		return noPaginator{}, nil
+	case "cursor":
+		if !conf.Contains(hpiFieldPaginationCursor) {
+			return nil, fmt.Errorf("field %v is required by the cursor mode", hpiFieldPaginationCursor)
+		}
+		mapping, err := conf.FieldBloblang(hpiFieldPaginationCursor)
+		if err != nil {
+			return nil, err
+		}
+		return &cursorPaginator{mapping: mapping, param: param}, nil
+	case "link_header":
+		return linkHeaderPaginator{}, nil
+	case "page":
+		start, err := conf.FieldInt(hpiFieldPaginationStart)
+		if err != nil {
+			return nil, err
+		}
+		return &pageNumberPaginator{param: param, start: start}, nil
+	case "offset":
+		o := &offsetPaginator{param: param}
+		if o.limitParam, err = conf.FieldString(hpiFieldPaginationLimitKey); err != nil {
+			return nil, err
+		}
+		if o.limit, err = conf.FieldInt(hpiFieldPaginationLimit); err != nil {
+			return nil, err
+		}
+		if o.limit <= 0 {
+			return nil, fmt.Errorf("field %v must be greater than zero", hpiFieldPaginationLimit)
+		}
+		return o, nil
+	}
+	return nil, fmt.Errorf("unrecognised pagination mode: %v", mode)
+}
+
+func newInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*input, error) {
+	i := &input{log: mgr.Logger()}
+
+	var err error
+	if i.nextURL, err = conf.FieldURL(hpiFieldURL); err != nil {
+		return nil, err
+	}
+	if i.verb, err = conf.FieldString(hpiFieldVerb); err != nil {
+		return nil, err
+	}
+	if i.headers, err = conf.FieldInterpolatedStringMap(hpiFieldHeaders); err != nil {
+		return nil, err
+	}
+	if conf.Contains(hpiFieldBody) {
+		body, err := conf.FieldString(hpiFieldBody)
+		if err != nil {
+			return nil, err
+		}
+		i.body = []byte(body)
+	}
+	if conf.Contains(hpiFieldRecords) {
+		if i.records, err = conf.FieldBloblang(hpiFieldRecords); err != nil {
+			return nil, err
+		}
+	}
+	if i.pager, err = paginatorFromConfig(conf.Namespace(hpiFieldPagination)); err != nil {
+		return nil, err
+	}
+	i.nextURL = i.pager.first(i.nextURL)
+	if i.maxPages, err = conf.FieldInt(hpiFieldMaxPages); err != nil {
+		return nil, err
+	}
+
+	i.rateLimit = &rateLimitHeaders{nowFn: time.Now}
+	rlConf := conf.Namespace(hpiFieldRateLimit)
+	if i.rateLimit.remaining, err = rlConf.FieldString(hpiFieldRateLimitRemaining); err != nil {
+		return nil, err
+	}
+	if i.rateLimit.reset, err = rlConf.FieldString(hpiFieldRateLimitReset); err != nil {
+		return nil, err
+	}
+	if i.retryDelay, err = conf.FieldDuration(hpiFieldRetryDelay); err != nil {
+		return nil, err
+	}
+
+	var tlsConf *tls.Config
+	var tlsEnabled bool
+	if tlsConf, tlsEnabled, err = conf.FieldTLSToggled(hpiFieldTLS); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	i.client = &http.Client{Transport: transport}
+	return i, nil
+}
+
+func (*input) Connect(context.Context) error {
+	return nil
+}
+
+func (i *input) fetch(ctx context.Context, u *url.URL) (*page, error) {
+	var body io.Reader = http.NoBody
+	if len(i.body) > 0 {
+		body = bytes.NewReader(i.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, i.verb, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	emptyMsg := service.NewMessage(nil)
+	for k, v := range i.headers {
+		value, err := v.TryString(emptyMsg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate header %v: %w", k, err)
+		}
+		req.Header.Set(k, value)
+	}
+
+	res, err := i.client.Do(req)
+	if err != nil {
+		i.waitUntil = time.Now().Add(i.retryDelay)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		i.waitUntil = time.Now().Add(i.rateLimit.retryAfter(res.Header, i.retryDelay))
+		return nil, errors.New("request was rate limited")
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		i.waitUntil = time.Now().Add(i.retryDelay)
+		return nil, fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	}
+
+	p := &page{url: u, header: res.Header}
+	if p.body, err = io.ReadAll(res.Body); err != nil {
+		return nil, err
+	}
+	i.waitUntil = time.Now().Add(i.rateLimit.delay(res.Header))
+
+	if p.records, err = i.extractRecords(p.body); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// extractRecords splits a page into its records, where records of raw pages
+// are returned as byte slices.
+func (i *input) extractRecords(body []byte) ([]any, error) {
+	if i.records == nil {
+		var arr []any
+		if err := json.Unmarshal(body, &arr); err == nil {
+			return arr, nil
+		}
+		return []any{body}, nil
+	}
+
+	res, err := service.NewMessage(body).BloblangQuery(i.records)
+	if err != nil {
+		return nil, fmt.Errorf("records mapping failed: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	v, err := res.AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("records mapping failed: %w", err)
+	}
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return t, nil
+	}
+	return nil, fmt.Errorf("records mapping must return an array, got %T", v)
+}
+
+func (i *input) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	for {
+		if i.nextURL == nil || (i.maxPages > 0 && i.pages >= i.maxPages) {
+			return nil, nil, service.ErrEndOfInput
+		}
+
+		if wait := time.Until(i.waitUntil); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+
+		p, err := i.fetch(ctx, i.nextURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		next, err := i.pager.next(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		i.nextURL = next
+		i.pages++
+
+		if len(p.records) == 0 {
+			continue
+		}
+
+		pageURL := p.url.String()
+		batch := make(service.MessageBatch, 0, len(p.records))
+		for _, r := range p.records {
+			var msg *service.Message
+			if b, ok := r.([]byte); ok {
+				msg = service.NewMessage(b)
+			} else {
+				msg = service.NewMessage(nil)
+				msg.SetStructuredMut(r)
+			}
+			msg.MetaSetMut("http_page", i.pages)
+			msg.MetaSetMut("http_url", pageURL)
+			batch = append(batch, msg)
+		}
+		return batch, func(context.Context, error) error { return nil }, nil
+	}
+}
+
+func (*input) Close(context.Context) error {
+	return nil
+}
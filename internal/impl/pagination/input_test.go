@@ -0,0 +1,236 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func readAll(t *testing.T, conf string) (records []string, pages []int) {
+	t.Helper()
+
+	pConf, err := inputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	in, err := newInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(t.Context()))
+
+	for {
+		batch, _, err := in.ReadBatch(t.Context())
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+		require.NoError(t, err)
+		for _, msg := range batch {
+			b, err := msg.AsBytes()
+			require.NoError(t, err)
+			records = append(records, string(b))
+			v, ok := msg.MetaGetMut("http_page")
+			require.True(t, ok)
+			pages = append(pages, v.(int))
+		}
+	}
+	require.NoError(t, in.Close(t.Context()))
+	return
+}
+
+func TestInputCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"data":[1,2],"next":"abc"}`)
+		case "abc":
+			fmt.Fprint(w, `{"data":[3],"next":null}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	records, pages := readAll(t, `
+url: `+srv.URL+`
+records: root = this.data
+pagination:
+  mode: cursor
+  cursor_mapping: root = this.next
+`)
+	assert.Equal(t, []string{"1", "2", "3"}, records)
+	assert.Equal(t, []int{1, 1, 2}, pages)
+}
+
+func TestInputLinkHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		if n < 2 {
+			w.Header().Set("Link", fmt.Sprintf(`</items?p=0>; rel="first", </items?p=%v>; rel="next"`, n+1))
+		}
+		fmt.Fprintf(w, `[{"n":%v}]`, n)
+	}))
+	t.Cleanup(srv.Close)
+
+	records, _ := readAll(t, `
+url: `+srv.URL+`/items
+pagination:
+  mode: link_header
+`)
+	assert.Equal(t, []string{`{"n":0}`, `{"n":1}`, `{"n":2}`}, records)
+}
+
+func TestInputPageNumber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `["a","b"]`)
+		case "2":
+			fmt.Fprint(w, `["c"]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	records, pages := readAll(t, `
+url: `+srv.URL+`
+pagination:
+  mode: page
+`)
+	assert.Equal(t, []string{`"a"`, `"b"`, `"c"`}, records)
+	assert.Equal(t, []int{1, 1, 2}, pages)
+}
+
+func TestInputOffset(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+limit, len(items))
+		fmt.Fprint(w, `{"items":[`)
+		for i := offset; i < end; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, "%q", items[i])
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	records, _ := readAll(t, `
+url: `+srv.URL+`
+records: root = this.items
+pagination:
+  mode: offset
+  param: skip
+  limit_param: limit
+  limit: 2
+`)
+	assert.Equal(t, []string{`"a"`, `"b"`, `"c"`, `"d"`, `"e"`}, records)
+}
+
+func TestInputMaxPagesRawBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "page "+r.URL.Query().Get("page"))
+	}))
+	t.Cleanup(srv.Close)
+
+	records, _ := readAll(t, `
+url: `+srv.URL+`
+max_pages: 2
+pagination:
+  mode: page
+  start: 0
+`)
+	assert.Equal(t, []string{"page 0", "page 1"}, records)
+}
+
+func TestInputRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0.05")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `["ok"]`)
+	}))
+	t.Cleanup(srv.Close)
+
+	pConf, err := inputSpec().ParseYAML(`url: `+srv.URL, nil)
+	require.NoError(t, err)
+	in, err := newInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	_, _, err = in.ReadBatch(t.Context())
+	require.ErrorContains(t, err, "rate limited")
+
+	start := time.Now()
+	batch, _, err := in.ReadBatch(t.Context())
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	rl := &rateLimitHeaders{
+		remaining: "X-RateLimit-Remaining",
+		reset:     "X-RateLimit-Reset",
+		nowFn:     func() time.Time { return now },
+	}
+
+	for _, test := range []struct {
+		name      string
+		remaining string
+		reset     string
+		expected  time.Duration
+	}{
+		{name: "no headers", expected: 0},
+		{name: "seconds", remaining: "9", reset: "10", expected: time.Second},
+		{name: "epoch", remaining: "4", reset: "1700000050", expected: 10 * time.Second},
+		{name: "exhausted", remaining: "0", reset: "30", expected: 30 * time.Second},
+		{name: "reset passed", remaining: "0", reset: "1699999990", expected: 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h := http.Header{}
+			if test.remaining != "" {
+				h.Set("X-RateLimit-Remaining", test.remaining)
+				h.Set("X-RateLimit-Reset", test.reset)
+			}
+			assert.Equal(t, test.expected, rl.delay(h))
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	target, ok := nextLink(`<https://a.com/?page=1>; rel="prev", <https://a.com/?page=3>; rel="next last"`)
+	require.True(t, ok)
+	assert.Equal(t, "https://a.com/?page=3", target)
+
+	_, ok = nextLink(`<https://a.com/?page=1>; rel="prev"`)
+	assert.False(t, ok)
+}
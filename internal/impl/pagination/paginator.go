@@ -0,0 +1,222 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// page is a response of the API along with the records extracted from it.
+type page struct {
+	url     *url.URL
+	header  http.Header
+	body    []byte
+	records []any
+}
+
+// paginator determines the URL of the page that follows a page, or returns
+// nil once the last page has been reached.
+type paginator interface {
+	// first returns the URL of the first page given the configured URL.
+	first(u *url.URL) *url.URL
+	next(p *page) (*url.URL, error)
+}
+
+func withQueryParam(u *url.URL, key, value string) *url.URL {
+	next := *u
+	q := next.Query()
+	q.Set(key, value)
+	next.RawQuery = q.Encode()
+	return &next
+}
+
+//------------------------------------------------------------------------------
+
+type noPaginator struct{}
+
+func (noPaginator) first(u *url.URL) *url.URL {
+	return u
+}
+
+func (noPaginator) next(*page) (*url.URL, error) {
+	return nil, nil
+}
+
+//------------------------------------------------------------------------------
+
+// cursorPaginator executes a mapping on each page in order to obtain a cursor,
+// which is either added to the URL as a query parameter or, when it is an
+// absolute URL, requested as is.
+type cursorPaginator struct {
+	mapping *bloblang.Executor
+	param   string
+}
+
+func (*cursorPaginator) first(u *url.URL) *url.URL {
+	return u
+}
+
+func (c *cursorPaginator) next(p *page) (*url.URL, error) {
+	msg := service.NewMessage(p.body)
+	res, err := msg.BloblangQuery(c.mapping)
+	if err != nil {
+		return nil, fmt.Errorf("cursor mapping failed: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	v, err := res.AsStructured()
+	if err != nil {
+		// Mappings may also produce raw strings.
+		b, bErr := res.AsBytes()
+		if bErr != nil {
+			return nil, err
+		}
+		v = string(b)
+	}
+
+	var cursor string
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		cursor = t
+	default:
+		cursor = fmt.Sprint(t)
+	}
+	if cursor == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(cursor, "http://") || strings.HasPrefix(cursor, "https://") {
+		return url.Parse(cursor)
+	}
+	if c.param == "" {
+		return nil, errors.New("cursor is not an absolute URL and no query parameter is configured")
+	}
+	return withQueryParam(p.url, c.param, cursor), nil
+}
+
+//------------------------------------------------------------------------------
+
+// linkHeaderPaginator follows the next relation of RFC 8288 Link headers.
+type linkHeaderPaginator struct{}
+
+func (linkHeaderPaginator) first(u *url.URL) *url.URL {
+	return u
+}
+
+func (linkHeaderPaginator) next(p *page) (*url.URL, error) {
+	for _, header := range p.header.Values("Link") {
+		if target, ok := nextLink(header); ok {
+			return p.url.Parse(target)
+		}
+	}
+	return nil, nil
+}
+
+// nextLink returns the target of the link with the relation type next within
+// a Link header value.
+func nextLink(header string) (string, bool) {
+	for _, link := range strings.Split(header, ",") {
+		target, params, found := strings.Cut(strings.TrimSpace(link), ";")
+		if !found {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(key, "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+				if strings.EqualFold(rel, "next") {
+					return target[1 : len(target)-1], true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+//------------------------------------------------------------------------------
+
+// pageNumberPaginator increments a page number query parameter until a page
+// without records is returned.
+type pageNumberPaginator struct {
+	param string
+	start int
+}
+
+func (n *pageNumberPaginator) first(u *url.URL) *url.URL {
+	if u.Query().Has(n.param) {
+		return u
+	}
+	return withQueryParam(u, n.param, strconv.Itoa(n.start))
+}
+
+func (n *pageNumberPaginator) next(p *page) (*url.URL, error) {
+	if len(p.records) == 0 {
+		return nil, nil
+	}
+	current, err := strconv.Atoi(p.url.Query().Get(n.param))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page number: %w", err)
+	}
+	return withQueryParam(p.url, n.param, strconv.Itoa(current+1)), nil
+}
+
+//------------------------------------------------------------------------------
+
+// offsetPaginator advances an offset query parameter by the number of records
+// of each page until a page with fewer records than the limit is returned.
+type offsetPaginator struct {
+	param      string
+	limitParam string
+	limit      int
+}
+
+func (o *offsetPaginator) first(u *url.URL) *url.URL {
+	if o.limitParam != "" && !u.Query().Has(o.limitParam) {
+		u = withQueryParam(u, o.limitParam, strconv.Itoa(o.limit))
+	}
+	if u.Query().Has(o.param) {
+		return u
+	}
+	return withQueryParam(u, o.param, "0")
+}
+
+func (o *offsetPaginator) next(p *page) (*url.URL, error) {
+	if len(p.records) == 0 || len(p.records) < o.limit {
+		return nil, nil
+	}
+	current, err := strconv.Atoi(p.url.Query().Get(o.param))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse offset: %w", err)
+	}
+	return withQueryParam(p.url, o.param, strconv.Itoa(current+len(p.records))), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochThreshold distinguishes reset headers containing a unix timestamp from
+// those containing a number of seconds, as both conventions are common.
+const epochThreshold = 1_000_000_000
+
+// rateLimitHeaders reads the remaining request quota of an API from response
+// headers.
+type rateLimitHeaders struct {
+	remaining string
+	reset     string
+	nowFn     func() time.Time
+}
+
+func (r *rateLimitHeaders) parseReset(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		if n >= epochThreshold {
+			return time.Unix(int64(n), 0).Sub(r.nowFn()), true
+		}
+		return time.Duration(n * float64(time.Second)), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(r.nowFn()), true
+	}
+	return 0, false
+}
+
+// delay returns the period to wait before the next request, which spreads the
+// remaining quota evenly across the time left until the quota resets.
+func (r *rateLimitHeaders) delay(h http.Header) time.Duration {
+	remainingStr := h.Get(r.remaining)
+	if remainingStr == "" {
+		return 0
+	}
+	remaining, err := strconv.Atoi(strings.TrimSpace(remainingStr))
+	if err != nil {
+		return 0
+	}
+	untilReset, ok := r.parseReset(h.Get(r.reset))
+	if !ok || untilReset <= 0 {
+		return 0
+	}
+	if remaining <= 0 {
+		return untilReset
+	}
+	return untilReset / time.Duration(remaining+1)
+}
+
+// retryAfter returns the delay requested by a Retry-After header, falling back
+// to the reset of the quota.
+func (r *rateLimitHeaders) retryAfter(h http.Header, fallback time.Duration) time.Duration {
+	if d, ok := r.parseReset(h.Get("Retry-After")); ok && d > 0 {
+		return d
+	}
+	if d, ok := r.parseReset(h.Get(r.reset)); ok && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,562 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	poFieldPath                = "path"
+	poFieldFilePrefix          = "file_prefix"
+	poFieldPartitionBy         = "partition_by"
+	poFieldPartitionName       = "name"
+	poFieldPartitionMapping    = "mapping"
+	poFieldRolling             = "rolling"
+	poFieldRollingMaxBytes     = "max_bytes"
+	poFieldRollingMaxRows      = "max_rows"
+	poFieldRollingInterval     = "interval"
+	poFieldNotificationOutput  = "notification_output"
+	poFieldBatching            = "batching"
+	hiveDefaultPartitionString = "__HIVE_DEFAULT_PARTITION__"
+)
+
+func parquetOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Local").
+		Version("4.62.0").
+		Summary("Writes structured messages to rolling https://parquet.apache.org/docs/[Parquet files^] on the local filesystem, optionally within Hive style partition directories.").
+		Description(`
+Messages are appended to an open file until one of the `+"`"+poFieldRolling+"`"+` limits is reached, at which point the file is closed and a new one is opened in its place. A batch of messages is only acknowledged once every file that its messages were written to has been closed successfully, as a Parquet file cannot be read until its footer has been written. If a file fails to be written then it is removed and every batch written to it is rejected. This means that `+"`max_in_flight`"+` limits the number of batches that can be written to the open files at any given time, and should be large enough to fill files before the `+"`"+poFieldRolling+"`.`"+poFieldRollingInterval+"`"+` elapses.
+
+== Partitioning
+
+When `+"`"+poFieldPartitionBy+"`"+` is set each message is written to a file within a directory derived from its partition values, where each partition is a directory of the form `+"`name=value`"+`, e.g. `+"`dt=2024-06-01/hour=13/`"+`. Each partition directory has its own open file and rolling limits. Values are escaped in the same way as Hive, and null or empty values are written to the partition `+"`"+hiveDefaultPartitionString+"`"+`.
+
+== Notifications
+
+When a `+"`"+poFieldNotificationOutput+"`"+` is set a message is written to the xref:components:outputs/about.adoc[output resource] of that name each time a file is closed successfully. The message is a JSON object describing the file:
+
+`+"```json"+`
+{
+  "path": "/data/events/dt=2024-06-01/hour=13/part-1717246800000-0f8c4b4e.parquet",
+  "partition": { "dt": "2024-06-01", "hour": "13" },
+  "rows": 10000,
+  "bytes": 1048576,
+  "opened_at": "2024-06-01T13:00:00Z",
+  "closed_at": "2024-06-01T13:05:00Z"
+}
+`+"```"+`
+
+Failing to write a notification is logged but does not cause the messages written to the file to be rejected.
+
+This output uses https://github.com/parquet-go/parquet-go[https://github.com/parquet-go/parquet-go^], which is itself experimental. Therefore changes could be made into how this output functions outside of major version releases.
+`).
+		Fields(
+			service.NewStringField(poFieldPath).
+				Description("The directory to write files to, which is created if it does not exist.").
+				Example("/data/events"),
+			service.NewStringField(poFieldFilePrefix).
+				Description("A prefix for the names of written files, which are suffixed with the time the file was opened and a random identifier.").
+				Default("part").
+				Advanced(),
+			service.NewObjectListField(poFieldPartitionBy,
+				service.NewStringField(poFieldPartitionName).
+					Description("The name of the partition column."),
+				service.NewBloblangField(poFieldPartitionMapping).
+					Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that results in the value of the partition for each message."),
+			).
+				Description("A list of partition columns, in the order in which their directories are nested.").
+				Example([]any{
+					map[string]any{"name": "dt", "mapping": `root = this.timestamp.ts_parse("2006-01-02T15:04:05Z07:00").ts_format("2006-01-02")`},
+					map[string]any{"name": "hour", "mapping": `root = this.timestamp.ts_parse("2006-01-02T15:04:05Z07:00").ts_format("15")`},
+				}).
+				Default([]any{}),
+			parquetSchemaConfig(),
+			parquetDefaultCompressionConfig(),
+			parquetDefaultEncodingConfig(),
+			service.NewObjectField(poFieldRolling,
+				service.NewIntField(poFieldRollingMaxBytes).
+					Description("The maximum size of the messages written to a file before it is closed, measured as the raw size of the messages before encoding. Set to zero to disable.").
+					Default(134217728),
+				service.NewIntField(poFieldRollingMaxRows).
+					Description("The maximum number of rows written to a file before it is closed. Set to zero to disable.").
+					Default(0),
+				service.NewDurationField(poFieldRollingInterval).
+					Description("The maximum duration a file is open for before it is closed.").
+					Default("1m"),
+			).
+				Description("Limits that cause an open file to be closed, whichever is reached first."),
+			service.NewStringField(poFieldNotificationOutput).
+				Description("The name of an output resource to write a notification message to for each file that is closed.").
+				Optional().
+				Advanced(),
+			service.NewOutputMaxInFlightField().
+				Default(64),
+			service.NewBatchPolicyField(poFieldBatching),
+		).
+		Example("Hourly partitioned files", "Write the messages of a Kafka topic into files partitioned by day and hour, and send a notification to another topic for each completed file.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: parquet
+
+output:
+  parquet:
+    path: /data/events
+    partition_by:
+      - name: dt
+        mapping: 'root = @kafka_timestamp_unix.ts_format("2006-01-02", "UTC")'
+      - name: hour
+        mapping: 'root = @kafka_timestamp_unix.ts_format("15", "UTC")'
+    schema:
+      - name: id
+        type: INT64
+      - name: payload
+        type: UTF8
+    default_compression: zstd
+    rolling:
+      max_bytes: 268435456
+      interval: 5m
+    notification_output: file_events
+    max_in_flight: 256
+    batching:
+      count: 1000
+      period: 1s
+
+output_resources:
+  - label: file_events
+    kafka_franz:
+      seed_brokers: [ localhost:9092 ]
+      topic: parquet_files
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"parquet", parquetOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(poFieldBatching); err != nil {
+				return
+			}
+			out, err = newParquetOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type partitionColumn struct {
+	name    string
+	mapping *bloblang.Executor
+}
+
+type partitionValue struct {
+	name  string
+	value string
+}
+
+// rollingFile is an open Parquet file that rows are appended to until it is
+// closed, at which point every batch that was written to it is notified of
+// the result.
+type rollingFile struct {
+	path      string
+	partition []partitionValue
+	openedAt  time.Time
+
+	file   writeCloser
+	writer *parquet.GenericWriter[any]
+	rows   int
+	bytes  int
+	timer  *time.Timer
+
+	done chan struct{}
+	err  error
+}
+
+type writeCloser interface {
+	io.Writer
+	io.Closer
+}
+
+type parquetOutput struct {
+	log *service.Logger
+	mgr *service.Resources
+
+	path               string
+	filePrefix         string
+	partitionBy        []partitionColumn
+	schema             *parquet.Schema
+	compression        compress.Codec
+	maxBytes           int
+	maxRows            int
+	interval           time.Duration
+	notificationOutput string
+
+	mut   sync.Mutex
+	files map[string]*rollingFile
+}
+
+func newParquetOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*parquetOutput, error) {
+	o := &parquetOutput{
+		log:   mgr.Logger(),
+		mgr:   mgr,
+		files: map[string]*rollingFile{},
+	}
+
+	var err error
+	if o.path, err = conf.FieldString(poFieldPath); err != nil {
+		return nil, err
+	}
+	if o.filePrefix, err = conf.FieldString(poFieldFilePrefix); err != nil {
+		return nil, err
+	}
+
+	partConfs, err := conf.FieldObjectList(poFieldPartitionBy)
+	if err != nil {
+		return nil, err
+	}
+	for _, pConf := range partConfs {
+		var p partitionColumn
+		if p.name, err = pConf.FieldString(poFieldPartitionName); err != nil {
+			return nil, err
+		}
+		if p.name == "" {
+			return nil, errors.New("partition names must not be empty")
+		}
+		if p.mapping, err = pConf.FieldBloblang(poFieldPartitionMapping); err != nil {
+			return nil, err
+		}
+		o.partitionBy = append(o.partitionBy, p)
+	}
+
+	if o.schema, o.compression, err = parquetSchemaAndCompressionFromConfig(conf); err != nil {
+		return nil, err
+	}
+
+	rollConf := conf.Namespace(poFieldRolling)
+	if o.maxBytes, err = rollConf.FieldInt(poFieldRollingMaxBytes); err != nil {
+		return nil, err
+	}
+	if o.maxRows, err = rollConf.FieldInt(poFieldRollingMaxRows); err != nil {
+		return nil, err
+	}
+	if o.interval, err = rollConf.FieldDuration(poFieldRollingInterval); err != nil {
+		return nil, err
+	}
+	if o.interval <= 0 {
+		return nil, errors.New("a rolling interval must be set")
+	}
+
+	if conf.Contains(poFieldNotificationOutput) {
+		if o.notificationOutput, err = conf.FieldString(poFieldNotificationOutput); err != nil {
+			return nil, err
+		}
+		if o.notificationOutput != "" && !mgr.HasOutput(o.notificationOutput) {
+			return nil, fmt.Errorf("output resource %v not found", o.notificationOutput)
+		}
+	}
+	return o, nil
+}
+
+func (o *parquetOutput) Connect(context.Context) error {
+	return o.mgr.FS().MkdirAll(o.path, 0o755)
+}
+
+// escapePartitionValue escapes a partition value in the same way as Hive.
+func escapePartitionValue(v string) string {
+	if v == "" {
+		return hiveDefaultPartitionString
+	}
+	var sb strings.Builder
+	for _, r := range v {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune("\"#%'*/:=?\\{[]^", r) {
+			fmt.Fprintf(&sb, "%%%02X", r)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func (o *parquetOutput) partitionValues(batch service.MessageBatch, i int) ([]partitionValue, error) {
+	values := make([]partitionValue, len(o.partitionBy))
+	for j, p := range o.partitionBy {
+		res, err := batch.BloblangQuery(i, p.mapping)
+		if err != nil {
+			return nil, fmt.Errorf("partition %v: %w", p.name, err)
+		}
+		values[j].name = p.name
+		if res == nil {
+			continue
+		}
+		v, err := res.AsStructured()
+		if err != nil {
+			b, berr := res.AsBytes()
+			if berr != nil {
+				return nil, fmt.Errorf("partition %v: %w", p.name, err)
+			}
+			v = string(b)
+		}
+		switch t := v.(type) {
+		case nil:
+		case string:
+			values[j].value = t
+		default:
+			values[j].value = fmt.Sprintf("%v", t)
+		}
+	}
+	return values, nil
+}
+
+func (o *parquetOutput) openFile(dir string, partition []partitionValue) (*rollingFile, error) {
+	dir = path.Join(o.path, dir)
+	if err := o.mgr.FS().MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%v-%v-%v.parquet", o.filePrefix, now.UnixMilli(), uuid.NewString()[:8])
+	f := &rollingFile{
+		path:      path.Join(dir, name),
+		partition: partition,
+		openedAt:  now,
+		done:      make(chan struct{}),
+	}
+
+	file, err := o.mgr.FS().OpenFile(f.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := file.(writeCloser)
+	if !ok {
+		_ = file.Close()
+		return nil, errors.New("the filesystem does not support writing files")
+	}
+	f.file = w
+	f.writer = parquet.NewGenericWriter[any](w, o.schema, parquet.Compression(o.compression))
+	return f, nil
+}
+
+// detach removes a file from the set of open files, after which nothing else
+// is written to it. Returns false if the file has already been detached.
+//
+// The output mutex must be held by the caller.
+func (o *parquetOutput) detach(key string, f *rollingFile) bool {
+	if o.files[key] != f {
+		return false
+	}
+	delete(o.files, key)
+	f.timer.Stop()
+	return true
+}
+
+// finish closes a detached file, writes a notification for it, and notifies
+// the batches written to it of the result.
+func (o *parquetOutput) finish(ctx context.Context, f *rollingFile, writeErr error) {
+	defer close(f.done)
+
+	err := writeErr
+	if err == nil {
+		err = closeWithoutPanic(f.writer)
+	}
+	if cErr := f.file.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		o.log.Errorf("Failed to write file %v: %v", f.path, err)
+		if rErr := o.mgr.FS().Remove(f.path); rErr != nil && !errors.Is(rErr, os.ErrNotExist) {
+			o.log.Errorf("Failed to remove file %v: %v", f.path, rErr)
+		}
+		f.err = err
+		return
+	}
+	o.log.Debugf("Closed file %v with %v rows", f.path, f.rows)
+
+	if o.notificationOutput == "" {
+		return
+	}
+	if err := o.notify(ctx, f); err != nil {
+		o.log.Errorf("Failed to write notification for file %v: %v", f.path, err)
+	}
+}
+
+func (o *parquetOutput) notify(ctx context.Context, f *rollingFile) error {
+	var size int64
+	if info, err := o.mgr.FS().Stat(f.path); err == nil {
+		size = info.Size()
+	}
+	partition := make(map[string]any, len(f.partition))
+	for _, p := range f.partition {
+		partition[p.name] = p.value
+	}
+	b, err := json.Marshal(map[string]any{
+		"path":      f.path,
+		"partition": partition,
+		"rows":      f.rows,
+		"bytes":     size,
+		"opened_at": f.openedAt.UTC().Format(time.RFC3339Nano),
+		"closed_at": time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return err
+	}
+
+	var writeErr error
+	if err := o.mgr.AccessOutput(ctx, o.notificationOutput, func(out *service.ResourceOutput) {
+		writeErr = out.Write(ctx, service.NewMessage(b))
+	}); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+func (o *parquetOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	type group struct {
+		partition []partitionValue
+		rows      []any
+		bytes     int
+	}
+
+	var keys []string
+	groups := map[string]*group{}
+	for i, msg := range batch.Copy() {
+		values, err := o.partitionValues(batch, i)
+		if err != nil {
+			return err
+		}
+		dirs := make([]string, len(values))
+		for j, v := range values {
+			dirs[j] = escapePartitionValue(v.name) + "=" + escapePartitionValue(v.value)
+		}
+		key := strings.Join(dirs, "/")
+
+		g, exists := groups[key]
+		if !exists {
+			g = &group{partition: values}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		b, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+		row, err := messageToRow(msg, o.schema)
+		if err != nil {
+			return err
+		}
+		g.rows = append(g.rows, row)
+		g.bytes += len(b)
+	}
+
+	var written, full []*rollingFile
+	var failed []error
+
+	o.mut.Lock()
+	for _, key := range keys {
+		g := groups[key]
+
+		f, exists := o.files[key]
+		if !exists {
+			var err error
+			if f, err = o.openFile(key, g.partition); err != nil {
+				o.mut.Unlock()
+				return fmt.Errorf("failed to open file: %w", err)
+			}
+			o.files[key] = f
+			f.timer = time.AfterFunc(o.interval, func() {
+				o.mut.Lock()
+				detached := o.detach(key, f)
+				o.mut.Unlock()
+				if detached {
+					o.finish(context.Background(), f, nil)
+				}
+			})
+		}
+
+		if err := writeWithoutPanic(f.writer, g.rows); err != nil {
+			// The file can no longer be trusted, and so it is removed and all
+			// batches written to it are rejected.
+			o.detach(key, f)
+			full = append(full, f)
+			failed = append(failed, err)
+			written = append(written, f)
+			continue
+		}
+		f.rows += len(g.rows)
+		f.bytes += g.bytes
+		written = append(written, f)
+
+		if (o.maxRows > 0 && f.rows >= o.maxRows) || (o.maxBytes > 0 && f.bytes >= o.maxBytes) {
+			o.detach(key, f)
+			full = append(full, f)
+			failed = append(failed, nil)
+		}
+	}
+	o.mut.Unlock()
+
+	for i, f := range full {
+		o.finish(ctx, f, failed[i])
+	}
+
+	for _, f := range written {
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if f.err != nil {
+			return f.err
+		}
+	}
+	return nil
+}
+
+func (o *parquetOutput) Close(ctx context.Context) error {
+	o.mut.Lock()
+	var files []*rollingFile
+	for key, f := range o.files {
+		if o.detach(key, f) {
+			files = append(files, f)
+		}
+	}
+	o.mut.Unlock()
+
+	for _, f := range files {
+		o.finish(ctx, f, nil)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/io"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestParquetOutput(t *testing.T, conf string, mgr *service.Resources) *parquetOutput {
+	t.Helper()
+
+	parsed, err := parquetOutputConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	out, err := newParquetOutputFromConfig(parsed, mgr)
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	return out
+}
+
+// readParquetFiles returns the number of rows of each Parquet file in a
+// directory, keyed by their relative path.
+func readParquetFiles(t *testing.T, dir string) map[string]int {
+	t.Helper()
+
+	files := map[string]int{}
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".parquet") {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		pFile, err := parquet.OpenFile(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = int(pFile.NumRows())
+		return nil
+	}))
+	return files
+}
+
+func TestParquetOutputRollingRows(t *testing.T) {
+	dir := t.TempDir()
+	out := newTestParquetOutput(t, `
+path: `+dir+`
+schema:
+  - { name: id, type: INT64 }
+rolling:
+  max_rows: 2
+  interval: 1h
+`, service.MockResources())
+
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+		service.NewMessage([]byte(`{"id":2}`)),
+	}))
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":3}`)),
+		service.NewMessage([]byte(`{"id":4}`)),
+		service.NewMessage([]byte(`{"id":5}`)),
+	}))
+
+	files := readParquetFiles(t, dir)
+	require.Len(t, files, 2)
+
+	var counts []int
+	for name, rows := range files {
+		assert.Regexp(t, `^part-\d+-[0-9a-f]{8}\.parquet$`, name)
+		counts = append(counts, rows)
+	}
+	sort.Ints(counts)
+	assert.Equal(t, []int{2, 3}, counts)
+	require.NoError(t, out.Close(t.Context()))
+}
+
+func TestParquetOutputPartitionedInterval(t *testing.T) {
+	dir := t.TempDir()
+
+	resBuilder := service.NewResourceBuilder()
+	notifyPath := filepath.Join(t.TempDir(), "notifications.jsonl")
+	require.NoError(t, resBuilder.AddOutputYAML(`
+label: notifications
+file:
+  path: `+notifyPath+`
+  codec: lines
+`))
+	mgr, stop, err := resBuilder.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stop(t.Context())
+	})
+
+	out := newTestParquetOutput(t, `
+path: `+dir+`
+partition_by:
+  - name: dt
+    mapping: root = this.ts.slice(0, 10)
+  - name: kind
+    mapping: root = this.kind
+schema:
+  - { name: ts, type: UTF8 }
+  - { name: kind, type: UTF8, optional: true }
+rolling:
+  interval: 50ms
+notification_output: notifications
+`, mgr)
+
+	start := time.Now()
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"ts":"2024-06-01T13:00:00Z","kind":"a/b"}`)),
+		service.NewMessage([]byte(`{"ts":"2024-06-02T13:00:00Z","kind":"c"}`)),
+		service.NewMessage([]byte(`{"ts":"2024-06-01T14:00:00Z","kind":"a/b"}`)),
+		service.NewMessage([]byte(`{"ts":"2024-06-01T14:00:00Z"}`)),
+	}))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	counts := map[string]int{}
+	for name, rows := range readParquetFiles(t, dir) {
+		counts[filepath.Dir(name)] += rows
+	}
+	assert.Equal(t, map[string]int{
+		"dt=2024-06-01/kind=a%2Fb":                      2,
+		"dt=2024-06-02/kind=c":                          1,
+		"dt=2024-06-01/kind=__HIVE_DEFAULT_PARTITION__": 1,
+	}, counts)
+
+	require.Eventually(t, func() bool {
+		b, err := os.ReadFile(notifyPath)
+		return err == nil && strings.Count(string(b), "\n") == 3
+	}, time.Second, 10*time.Millisecond)
+
+	b, err := os.ReadFile(notifyPath)
+	require.NoError(t, err)
+	partitions := map[string]float64{}
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var n struct {
+			Path      string            `json:"path"`
+			Partition map[string]string `json:"partition"`
+			Rows      float64           `json:"rows"`
+			Bytes     float64           `json:"bytes"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &n))
+		assert.FileExists(t, n.Path)
+		assert.Positive(t, n.Bytes)
+		partitions[n.Partition["dt"]+"/"+n.Partition["kind"]] = n.Rows
+	}
+	assert.Equal(t, map[string]float64{
+		"2024-06-01/a/b": 2,
+		"2024-06-02/c":   1,
+		"2024-06-01/":    1,
+	}, partitions)
+}
+
+func TestParquetOutputFailedFile(t *testing.T) {
+	dir := t.TempDir()
+	out := newTestParquetOutput(t, `
+path: `+dir+`
+schema:
+  - { name: id, type: FLOAT }
+rolling:
+  interval: 1h
+`, service.MockResources())
+
+	err := out.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":12}`)),
+	})
+	require.Error(t, err)
+	assert.Empty(t, readParquetFiles(t, dir))
+}
+
+func TestEscapePartitionValue(t *testing.T) {
+	assert.Equal(t, "foo", escapePartitionValue("foo"))
+	assert.Equal(t, "a%2Fb%3Dc", escapePartitionValue("a/b=c"))
+	assert.Equal(t, hiveDefaultPartitionString, escapePartitionValue(""))
+}
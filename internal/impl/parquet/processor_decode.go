@@ -114,14 +114,14 @@ type parquetDecodeProcessor struct {
 	visitor decodingCoercionVisitor
 }
 
-func newReaderWithoutPanic(r io.ReaderAt) (pRdr *parquet.GenericReader[any], err error) {
+func newReaderWithoutPanic(r io.ReaderAt, options ...parquet.ReaderOption) (pRdr *parquet.GenericReader[any], err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("parquet read panic: %v", r)
 		}
 	}()
 
-	pRdr = parquet.NewGenericReader[any](r)
+	pRdr = parquet.NewGenericReader[any](r, options...)
 	return
 }
 
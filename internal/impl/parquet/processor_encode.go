@@ -31,18 +31,8 @@ func parquetEncodeProcessorConfig() *service.ConfigSpec {
 		Categories("Parsing").
 		Summary("Encodes https://parquet.apache.org/docs/[Parquet files^] from a batch of structured messages.").
 		Field(parquetSchemaConfig()).
-		Field(service.NewStringEnumField("default_compression",
-			"uncompressed", "snappy", "gzip", "brotli", "zstd", "lz4raw",
-		).
-			Description("The default compression type to use for fields.").
-			Default("uncompressed")).
-		Field(service.NewStringEnumField("default_encoding",
-			"DELTA_LENGTH_BYTE_ARRAY", "PLAIN",
-		).
-			Description("The default encoding type to use for fields. A custom default encoding is only necessary when consuming data with libraries that do not support `DELTA_LENGTH_BYTE_ARRAY` and is therefore best left unset where possible.").
-			Default("DELTA_LENGTH_BYTE_ARRAY").
-			Advanced().
-			Version("4.11.0")).
+		Field(parquetDefaultCompressionConfig()).
+		Field(parquetDefaultEncodingConfig()).
 		Description(`
 This processor uses https://github.com/parquet-go/parquet-go[https://github.com/parquet-go/parquet-go^], which is itself experimental. Therefore changes could be made into how this processor functions outside of major version releases.
 `).
@@ -101,6 +91,24 @@ func parquetSchemaConfig() *service.ConfigField {
 	).Description("Parquet schema.")
 }
 
+func parquetDefaultCompressionConfig() *service.ConfigField {
+	return service.NewStringEnumField("default_compression",
+		"uncompressed", "snappy", "gzip", "brotli", "zstd", "lz4raw",
+	).
+		Description("The default compression type to use for fields.").
+		Default("uncompressed")
+}
+
+func parquetDefaultEncodingConfig() *service.ConfigField {
+	return service.NewStringEnumField("default_encoding",
+		"DELTA_LENGTH_BYTE_ARRAY", "PLAIN",
+	).
+		Description("The default encoding type to use for fields. A custom default encoding is only necessary when consuming data with libraries that do not support `DELTA_LENGTH_BYTE_ARRAY` and is therefore best left unset where possible.").
+		Default("DELTA_LENGTH_BYTE_ARRAY").
+		Advanced().
+		Version("4.11.0")
+}
+
 type encodingFn func(n parquet.Node) parquet.Node
 
 var defaultEncodingFn encodingFn = func(n parquet.Node) parquet.Node {
@@ -185,14 +193,24 @@ func parquetGroupFromConfig(columnConfs []*service.ParsedConfig, encodingFn enco
 //------------------------------------------------------------------------------
 
 func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*parquetEncodeProcessor, error) {
-	schemaConfs, err := conf.FieldObjectList("schema")
+	schema, compressDefault, err := parquetSchemaAndCompressionFromConfig(conf)
 	if err != nil {
 		return nil, err
 	}
+	return newParquetEncodeProcessor(logger, schema, compressDefault)
+}
+
+// parquetSchemaAndCompressionFromConfig parses the schema, default encoding
+// and default compression fields shared by components that encode Parquet.
+func parquetSchemaAndCompressionFromConfig(conf *service.ParsedConfig) (*parquet.Schema, compress.Codec, error) {
+	schemaConfs, err := conf.FieldObjectList("schema")
+	if err != nil {
+		return nil, nil, err
+	}
 
 	customEncoding, err := conf.FieldString("default_encoding")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var encoding encodingFn
 	switch customEncoding {
@@ -204,13 +222,13 @@ func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig, logger *ser
 
 	node, err := parquetGroupFromConfig(schemaConfs, encoding)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	schema := parquet.NewSchema("", node)
 	compressStr, err := conf.FieldString("default_compression")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var compressDefault compress.Codec
@@ -228,9 +246,9 @@ func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig, logger *ser
 	case "lz4raw":
 		compressDefault = &parquet.Lz4Raw
 	default:
-		return nil, fmt.Errorf("default_compression type %v not recognised", compressStr)
+		return nil, nil, fmt.Errorf("default_compression type %v not recognised", compressStr)
 	}
-	return newParquetEncodeProcessor(logger, schema, compressDefault)
+	return schema, compressDefault, nil
 }
 
 type parquetEncodeProcessor struct {
@@ -270,6 +288,26 @@ func closeWithoutPanic(pWtr *parquet.GenericWriter[any]) (err error) {
 	return
 }
 
+// messageToRow converts a structured message into a row that can be written
+// with the given schema. The message is mutated and should therefore be a copy.
+func messageToRow(m *service.Message, schema *parquet.Schema) (any, error) {
+	ms, err := m.AsStructuredMut()
+	if err != nil {
+		return nil, err
+	}
+
+	row, isObj := scrubJSONNumbers(ms).(map[string]any)
+	if !isObj {
+		return nil, fmt.Errorf("unable to encode message type %T as parquet row", ms)
+	}
+
+	res, err := visitWithSchema(encodingCoercionVisitor{}, row, schema)
+	if err != nil {
+		return nil, fmt.Errorf("coercing logical types: %w", err)
+	}
+	return res, nil
+}
+
 func (s *parquetEncodeProcessor) ProcessBatch(_ context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
 	if len(batch) == 0 {
 		return nil, nil
@@ -281,20 +319,10 @@ func (s *parquetEncodeProcessor) ProcessBatch(_ context.Context, batch service.M
 	batch = batch.Copy()
 	rows := make([]any, len(batch))
 	for i, m := range batch {
-		ms, err := m.AsStructuredMut()
-		if err != nil {
+		var err error
+		if rows[i], err = messageToRow(m, s.schema); err != nil {
 			return nil, err
 		}
-
-		var isObj bool
-		if rows[i], isObj = scrubJSONNumbers(ms).(map[string]any); !isObj {
-			return nil, fmt.Errorf("unable to encode message type %T as parquet row", ms)
-		}
-
-		rows[i], err = visitWithSchema(encodingCoercionVisitor{}, rows[i], s.schema)
-		if err != nil {
-			return nil, fmt.Errorf("coercing logical types: %w", err)
-		}
 	}
 
 	if err := writeWithoutPanic(pWtr, rows); err != nil {
@@ -0,0 +1,202 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	psFieldColumns    = "columns"
+	psFieldBatchCount = "batch_count"
+)
+
+func parquetScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Summary("Consume the rows of a https://parquet.apache.org/docs/[Parquet file^] as structured messages.").
+		Description(`
+Rows are decoded one page at a time, and therefore files of any size can be consumed without being held in memory. The metadata of a Parquet file is located at the end of the file, and so when the source of the file does not support random access, such as objects streamed from S3 or GCS, the file is first written to a temporary file within the directory of `+"`os.TempDir()`"+`, which is removed once the file has been consumed.
+
+When the field `+"`"+psFieldColumns+"`"+` is set only the listed columns are decoded, and the data of all other columns is skipped without being read from the file.
+
+Values are extracted in the same way as the `+"xref:components:inputs/parquet.adoc[`parquet` input]"+`, where any BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY value is extracted as a byte slice unless the logical type is UTF8.
+`).
+		Fields(
+			service.NewStringListField(psFieldColumns).
+				Description("An optional list of top level columns to decode, all columns are decoded by default.").
+				Example([]string{"id", "created_at", "payload"}).
+				Optional(),
+			service.NewIntField(psFieldBatchCount).
+				Description("The maximum number of rows to yield in each batch. When the end of the file is reached the remaining rows are yielded as a (potentially smaller) batch.").
+				Default(1).
+				Advanced(),
+		)
+}
+
+func init() {
+	service.MustRegisterBatchScannerCreator("parquet", parquetScannerSpec(),
+		func(conf *service.ParsedConfig, _ *service.Resources) (service.BatchScannerCreator, error) {
+			return parquetScannerFromParsed(conf)
+		})
+}
+
+func parquetScannerFromParsed(conf *service.ParsedConfig) (c *parquetScannerCreator, err error) {
+	c = &parquetScannerCreator{}
+	if conf.Contains(psFieldColumns) {
+		if c.columns, err = conf.FieldStringList(psFieldColumns); err != nil {
+			return nil, err
+		}
+	}
+	if c.batchCount, err = conf.FieldInt(psFieldBatchCount); err != nil {
+		return nil, err
+	}
+	if c.batchCount < 1 {
+		return nil, fmt.Errorf("%v must be >0, got %v", psFieldBatchCount, c.batchCount)
+	}
+	return
+}
+
+type parquetScannerCreator struct {
+	columns    []string
+	batchCount int
+}
+
+// randomAccessFile is implemented by sources that can be read from directly,
+// such as files of the local filesystem.
+type randomAccessFile interface {
+	io.ReaderAt
+	Stat() (fs.FileInfo, error)
+}
+
+func (c *parquetScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, _ *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	s := &parquetScanner{r: rdr, batchCount: c.batchCount}
+
+	var ra io.ReaderAt
+	var size int64
+	if f, ok := rdr.(randomAccessFile); ok {
+		info, err := f.Stat()
+		if err != nil {
+			_ = rdr.Close()
+			return nil, err
+		}
+		ra, size = f, info.Size()
+	} else {
+		tmp, err := os.CreateTemp("", "parquet-scanner-*")
+		if err != nil {
+			_ = rdr.Close()
+			return nil, err
+		}
+		s.tmp = tmp
+		if size, err = io.Copy(tmp, rdr); err != nil {
+			_ = s.Close(context.Background())
+			return nil, fmt.Errorf("failed to buffer parquet file: %w", err)
+		}
+		ra = tmp
+	}
+
+	pFile, err := parquet.OpenFile(ra, size)
+	if err != nil {
+		_ = s.Close(context.Background())
+		return nil, err
+	}
+
+	var opts []parquet.ReaderOption
+	if len(c.columns) > 0 {
+		schema, err := projectSchema(pFile.Schema(), c.columns)
+		if err != nil {
+			_ = s.Close(context.Background())
+			return nil, err
+		}
+		opts = append(opts, schema)
+	}
+
+	if s.pRdr, err = newReaderWithoutPanic(pFile, opts...); err != nil {
+		_ = s.Close(context.Background())
+		return nil, err
+	}
+	return service.AutoAggregateBatchScannerAcks(s, aFn), nil
+}
+
+// projectSchema returns a schema containing only the given top level columns
+// of a schema.
+func projectSchema(schema *parquet.Schema, columns []string) (*parquet.Schema, error) {
+	fields := map[string]parquet.Field{}
+	for _, f := range schema.Fields() {
+		fields[f.Name()] = f
+	}
+
+	group := parquet.Group{}
+	for _, c := range columns {
+		f, exists := fields[c]
+		if !exists {
+			return nil, fmt.Errorf("column %v does not exist in the parquet schema", c)
+		}
+		group[c] = f
+	}
+	return parquet.NewSchema(schema.Name(), group), nil
+}
+
+func (*parquetScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type parquetScanner struct {
+	r          io.ReadCloser
+	tmp        *os.File
+	pRdr       *parquet.GenericReader[any]
+	batchCount int
+}
+
+func (s *parquetScanner) NextBatch(context.Context) (service.MessageBatch, error) {
+	rowBuf := make([]any, s.batchCount)
+	n, err := readWithoutPanic(s.pRdr, rowBuf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, io.EOF
+	}
+
+	batch := make(service.MessageBatch, n)
+	for i := range n {
+		msg := service.NewMessage(nil)
+		msg.SetStructuredMut(rowBuf[i])
+		batch[i] = msg
+	}
+	return batch, nil
+}
+
+func (s *parquetScanner) Close(context.Context) error {
+	var errs []error
+	if s.pRdr != nil {
+		errs = append(errs, s.pRdr.Close())
+	}
+	if s.tmp != nil {
+		errs = append(errs, s.tmp.Close(), os.Remove(s.tmp.Name()))
+	}
+	errs = append(errs, s.r.Close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type scannerData struct {
+	ID    int64
+	Value string
+	Score float64
+}
+
+func testParquetFile(t *testing.T, rows int) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	pWtr := parquet.NewWriter(buf, parquet.SchemaOf(scannerData{}))
+	for i := range rows {
+		require.NoError(t, pWtr.Write(scannerData{ID: int64(i), Value: "foo", Score: float64(i) / 2}))
+	}
+	require.NoError(t, pWtr.Close())
+	return buf.Bytes()
+}
+
+func scanAll(t *testing.T, conf string, rdr io.ReadCloser) (batches [][]string) {
+	t.Helper()
+
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	creator, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	var acked bool
+	strm, err := creator.Create(rdr, func(context.Context, error) error {
+		acked = true
+		return nil
+	}, service.NewScannerSourceDetails())
+	require.NoError(t, err)
+
+	for {
+		batch, aFn, err := strm.NextBatch(t.Context())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, aFn(t.Context(), nil))
+
+		var strs []string
+		for _, msg := range batch {
+			mBytes, err := msg.AsBytes()
+			require.NoError(t, err)
+			strs = append(strs, string(mBytes))
+		}
+		batches = append(batches, strs)
+	}
+	require.NoError(t, strm.Close(t.Context()))
+	assert.True(t, acked)
+	return
+}
+
+func TestParquetScannerStream(t *testing.T) {
+	b := testParquetFile(t, 3)
+
+	assert.Equal(t, [][]string{
+		{`{"ID":0,"Score":0,"Value":"foo"}`, `{"ID":1,"Score":0.5,"Value":"foo"}`},
+		{`{"ID":2,"Score":1,"Value":"foo"}`},
+	}, scanAll(t, `
+test:
+  parquet:
+    batch_count: 2
+`, io.NopCloser(bytes.NewReader(b))))
+}
+
+func TestParquetScannerFileProjection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	require.NoError(t, os.WriteFile(path, testParquetFile(t, 2), 0o644))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{`{"ID":0,"Score":0}`},
+		{`{"ID":1,"Score":0.5}`},
+	}, scanAll(t, `
+test:
+  parquet:
+    columns: [ Score, ID ]
+`, f))
+}
+
+func TestParquetScannerUnknownColumn(t *testing.T) {
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  parquet:
+    columns: [ Nope ]
+`, nil)
+	require.NoError(t, err)
+
+	creator, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	_, err = creator.Create(io.NopCloser(bytes.NewReader(testParquetFile(t, 1))), func(context.Context, error) error {
+		return nil
+	}, service.NewScannerSourceDetails())
+	require.ErrorContains(t, err, "column Nope does not exist")
+}
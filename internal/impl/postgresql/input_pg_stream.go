@@ -43,6 +43,9 @@ const (
 	fieldMaxParallelSnapshotTables = "max_parallel_snapshot_tables"
 	fieldUnchangedToastValue       = "unchanged_toast_value"
 	fieldHeartbeatInterval         = "heartbeat_interval"
+	fieldIncludeBeforeImage        = "include_before_image"
+	fieldCheckpointCache           = "checkpoint_cache"
+	fieldCheckpointKey             = "checkpoint_key"
 
 	shutdownTimeout = 5 * time.Second
 )
@@ -67,6 +70,10 @@ This input adds the following metadata fields to each message:
 - table (Name of the table that the message originated from)
 - operation (Type of operation that generated the message: "read", "insert", "update", or "delete". "read" is from messages that are read in the initial snapshot phase. This will also be "begin" and "commit" if ` + "`" + fieldIncludeTxnMarkers + "`" + ` is enabled)
 - lsn (the log sequence number in postgres)
+
+== Before and after images
+
+When ` + "`" + fieldIncludeBeforeImage + "`" + ` is set to true, the payload of each change message is an object with the fields ` + "`before`" + ` and ` + "`after`" + `, containing the row before and after the change respectively. Inserts and snapshot reads have a null ` + "`before`" + ` image and deletes have a null ` + "`after`" + ` image. The ` + "`before`" + ` image of an update contains the entire previous row only when the table has ` + "`REPLICA IDENTITY FULL`" + `, when the table uses its default replica identity it only contains the previous key columns if they were changed, and is null otherwise.
 		`).
 		Field(service.NewStringField(fieldDSN).
 			Description("The Data Source Name for the PostgreSQL database in the form of `postgres://[user[:password]@][netloc][:port][/dbname][?param1=value1&...]`. Please note that Postgres enforces SSL by default, you can override this with the parameter `sslmode=disable` if required.").
@@ -125,6 +132,21 @@ This input adds the following metadata fields to each message:
 			Example("0s").
 			Example("24h").
 			Advanced()).
+		Field(service.NewBoolField(fieldIncludeBeforeImage).
+			Description("When set to true, change messages contain both the `before` and `after` images of the row as described above, rather than only the row after the change.").
+			Default(false).
+			Advanced().
+			Version("4.62.0")).
+		Field(service.NewStringField(fieldCheckpointCache).
+			Description("An optional https://www.docs.redpanda.com/redpanda-connect/components/caches/about[cache resource^] to store the latest LSN that has been successfully delivered in, in addition to acknowledging it to the replication slot. When the replication slot already exists and the stored LSN is ahead of the position confirmed by the slot, for instance when the acknowledgement could not be sent to PostgreSQL before shutdown, streaming resumes from the stored LSN instead.").
+			Optional().
+			Advanced().
+			Version("4.62.0")).
+		Field(service.NewStringField(fieldCheckpointKey).
+			Description("The key to use to store the LSN in `" + fieldCheckpointCache + "`. An alternative key can be provided if multiple CDC inputs share the same cache.").
+			Default("postgres_cdc_lsn").
+			Advanced().
+			Version("4.62.0")).
 		Field(service.NewAutoRetryNacksToggleField()).
 		Field(service.NewBatchPolicyField(fieldBatching))
 }
@@ -146,6 +168,9 @@ func newPgStreamInput(conf *service.ParsedConfig, mgr *service.Resources) (s ser
 		batching                  service.BatchPolicy
 		unchangedToastValue       any
 		heartbeatInterval         time.Duration
+		includeBeforeImage        bool
+		checkpointCache           string
+		checkpointKey             string
 	)
 
 	if err := license.CheckRunningEnterprise(mgr); err != nil {
@@ -221,6 +246,23 @@ func newPgStreamInput(conf *service.ParsedConfig, mgr *service.Resources) (s ser
 		return nil, err
 	}
 
+	if includeBeforeImage, err = conf.FieldBool(fieldIncludeBeforeImage); err != nil {
+		return nil, err
+	}
+
+	if conf.Contains(fieldCheckpointCache) {
+		if checkpointCache, err = conf.FieldString(fieldCheckpointCache); err != nil {
+			return nil, err
+		}
+		if !mgr.HasCache(checkpointCache) {
+			return nil, fmt.Errorf("unknown cache resource: %s", checkpointCache)
+		}
+	}
+
+	if checkpointKey, err = conf.FieldString(fieldCheckpointKey); err != nil {
+		return nil, err
+	}
+
 	pgConnConfig, err := pgconn.ParseConfigWithOptions(dsn, pgconn.ParseConfigOptions{
 		// Don't support dynamic reading of password
 		GetSSLPassword: func(context.Context) string { return "" },
@@ -254,9 +296,12 @@ func newPgStreamInput(conf *service.ParsedConfig, mgr *service.Resources) (s ser
 			UnchangedToastValue:      unchangedToastValue,
 			HeartbeatInterval:        heartbeatInterval,
 		},
-		batching:        batching,
-		checkpointLimit: checkpointLimit,
-		msgChan:         make(chan asyncMessage),
+		batching:           batching,
+		checkpointLimit:    checkpointLimit,
+		includeBeforeImage: includeBeforeImage,
+		checkpointCache:    checkpointCache,
+		checkpointKey:      checkpointKey,
+		msgChan:            make(chan asyncMessage),
 
 		mgr:             mgr,
 		logger:          mgr.Logger(),
@@ -304,12 +349,23 @@ type pgStreamInput struct {
 	batching        service.BatchPolicy
 	checkpointLimit int
 
+	includeBeforeImage bool
+	checkpointCache    string
+	checkpointKey      string
+
 	snapshotMetrics *service.MetricGauge
 	replicationLag  *service.MetricGauge
 	stopSig         *shutdown.Signaller
 }
 
 func (p *pgStreamInput) Connect(ctx context.Context) error {
+	if p.checkpointCache != "" {
+		startLSN, err := p.getCachedLSN(ctx)
+		if err != nil {
+			return err
+		}
+		p.streamConfig.StartLSN = startLSN
+	}
 	pgStream, err := pglogicalstream.NewPgStream(ctx, p.streamConfig)
 	if err != nil {
 		return fmt.Errorf("unable to create replication stream: %w", err)
@@ -374,7 +430,7 @@ func (p *pgStreamInput) processStream(pgStream *pglogicalstream.Stream, batcher
 				err   error
 			)
 			for _, msg := range batch {
-				if mb, err = json.Marshal(msg.Data); err != nil {
+				if mb, err = json.Marshal(p.messagePayload(msg)); err != nil {
 					p.logger.Errorf("failure to marshal message: %s", err)
 					break
 				}
@@ -448,6 +504,11 @@ func (p *pgStreamInput) flushBatch(
 		if err = pgStream.AckLSN(ctx, *maxLSN); err != nil {
 			return fmt.Errorf("unable to ack LSN to postgres: %w", err)
 		}
+		if p.checkpointCache != "" {
+			if err = p.setCachedLSN(ctx, *maxLSN); err != nil {
+				return fmt.Errorf("unable to store LSN in cache: %w", err)
+			}
+		}
 		return nil
 	}
 	select {
@@ -485,3 +546,53 @@ func (p *pgStreamInput) Close(ctx context.Context) error {
 	}
 	return nil
 }
+
+// messagePayload returns the structured payload of a change message, which
+// is an envelope of the before and after images of the row when enabled.
+func (p *pgStreamInput) messagePayload(msg pglogicalstream.StreamMessage) any {
+	if !p.includeBeforeImage {
+		return msg.Data
+	}
+	switch msg.Operation {
+	case pglogicalstream.ReadOpType, pglogicalstream.InsertOpType:
+		return map[string]any{"before": nil, "after": msg.Data}
+	case pglogicalstream.UpdateOpType:
+		return map[string]any{"before": msg.Before, "after": msg.Data}
+	case pglogicalstream.DeleteOpType:
+		return map[string]any{"before": msg.Data, "after": nil}
+	default:
+		return msg.Data
+	}
+}
+
+func (p *pgStreamInput) getCachedLSN(ctx context.Context) (pglogicalstream.LSN, error) {
+	var (
+		cacheVal []byte
+		cErr     error
+	)
+	if err := p.mgr.AccessCache(ctx, p.checkpointCache, func(c service.Cache) {
+		cacheVal, cErr = c.Get(ctx, p.checkpointKey)
+	}); err != nil {
+		return 0, fmt.Errorf("unable to access cache for reading: %w", err)
+	}
+	if errors.Is(cErr, service.ErrKeyNotFound) {
+		return 0, nil
+	} else if cErr != nil {
+		return 0, fmt.Errorf("unable read checkpoint from cache: %w", cErr)
+	}
+	lsn, err := pglogicalstream.ParseLSN(string(cacheVal))
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse checkpointed LSN %q: %w", cacheVal, err)
+	}
+	return lsn, nil
+}
+
+func (p *pgStreamInput) setCachedLSN(ctx context.Context, lsn string) error {
+	var cErr error
+	if err := p.mgr.AccessCache(ctx, p.checkpointCache, func(c service.Cache) {
+		cErr = c.Set(ctx, p.checkpointKey, []byte(lsn), nil)
+	}); err != nil {
+		return fmt.Errorf("unable to access cache for writing: %w", err)
+	}
+	return cErr
+}
@@ -1143,3 +1143,85 @@ postgres_cdc:
 	}, 5*time.Second, 500*time.Millisecond)
 	require.NoError(t, streamOut.StopWithin(time.Second*10))
 }
+
+func TestIntegrationPostgresBeforeImage(t *testing.T) {
+	t.Parallel()
+	integration.CheckSkip(t)
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	resource, db, err := ResourceWithPostgreSQLVersion(t, pool, "16")
+	require.NoError(t, err)
+	require.NoError(t, resource.Expire(120))
+
+	_, err = db.Exec(`ALTER TABLE large_values REPLICA IDENTITY FULL`)
+	require.NoError(t, err)
+
+	hostAndPort := resource.GetHostPort("5432/tcp")
+	hostAndPortSplited := strings.Split(hostAndPort, ":")
+	password := "l]YLSc|4[i56%{gY"
+
+	databaseURL := fmt.Sprintf("user=user_name password=%s dbname=dbname sslmode=disable host=%s port=%s", password, hostAndPortSplited[0], hostAndPortSplited[1])
+	streamOutBuilder := service.NewStreamBuilder()
+	require.NoError(t, streamOutBuilder.AddCacheYAML(`
+label: checkpoints
+memory: {}
+`))
+	require.NoError(t, streamOutBuilder.AddInputYAML(fmt.Sprintf(`
+postgres_cdc:
+    dsn: %s
+    slot_name: test_slot_before_image
+    schema: public
+    tables: [ large_values ]
+    include_before_image: true
+    checkpoint_cache: checkpoints
+`, databaseURL)))
+
+	var outMessages []string
+	var outMut sync.Mutex
+	require.NoError(t, streamOutBuilder.AddConsumerFunc(func(_ context.Context, msg *service.Message) error {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		outMut.Lock()
+		outMessages = append(outMessages, string(b))
+		outMut.Unlock()
+		return nil
+	}))
+
+	streamOut, err := streamOutBuilder.Build()
+	require.NoError(t, err)
+	license.InjectTestService(streamOut.Resources())
+
+	go func() {
+		_ = streamOut.Run(t.Context())
+	}()
+
+	time.Sleep(time.Second * 5)
+	_, err = db.Exec(`INSERT INTO large_values (id, value) VALUES (1, 'foo');`)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE large_values SET value = 'bar' WHERE id = 1;`)
+	require.NoError(t, err)
+	_, err = db.Exec(`DELETE FROM large_values WHERE id = 1;`)
+	require.NoError(t, err)
+
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		outMut.Lock()
+		defer outMut.Unlock()
+		assert.Equal(c, []string{
+			`{"after":{"id":1,"value":"foo"},"before":null}`,
+			`{"after":{"id":1,"value":"bar"},"before":{"id":1,"value":"foo"}}`,
+			`{"after":null,"before":{"id":1,"value":"bar"}}`,
+		}, outMessages)
+	}, time.Second*25, time.Millisecond*100)
+
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		var lsn []byte
+		require.NoError(c, streamOut.Resources().AccessCache(t.Context(), "checkpoints", func(cache service.Cache) {
+			lsn, err = cache.Get(t.Context(), "postgres_cdc_lsn")
+		}))
+		assert.NoError(c, err)
+		assert.NotEmpty(c, lsn)
+	}, time.Second*25, time.Millisecond*100)
+
+	require.NoError(t, streamOut.StopWithin(time.Second*10))
+}
@@ -47,4 +47,7 @@ type Config struct {
 	UnchangedToastValue any
 	// The interval to send logical messages
 	HeartbeatInterval time.Duration
+	// StartLSN is an optional position to resume an existing replication slot
+	// from, which is only used when ahead of the confirmed position of the slot
+	StartLSN LSN
 }
@@ -200,6 +200,10 @@ func NewPgStream(ctx context.Context, config *Config) (*Stream, error) {
 		if outputPlugin != decodingPlugin {
 			return nil, fmt.Errorf("replication slot %s already exists with different output plugin: %s", config.ReplicationSlotName, outputPlugin)
 		}
+		if config.StartLSN > confirmedLSNFromDB {
+			stream.logger.Debugf("resuming from checkpointed LSN %s ahead of confirmed LSN %s", config.StartLSN.String(), confirmedLSNFromDB.String())
+			confirmedLSNFromDB = config.StartLSN
+		}
 		if confirmedLSNFromDB > 0 {
 			stream.ackedLSNMu.Lock()
 			stream.ackedLSN = confirmedLSNFromDB
@@ -131,6 +131,13 @@ func toStreamMessage(logicalMsg Message, relations map[uint32]*RelationMessage,
 			}
 		}
 		message.Data = values
+		if logicalMsg.OldTuple != nil {
+			before, err := decodeOldTuple(logicalMsg.OldTuple, rel, typeMap, unchangedToastValue)
+			if err != nil {
+				return nil, err
+			}
+			message.Before = before
+		}
 	case *DeleteMessage:
 		rel, ok := relations[logicalMsg.RelationID]
 		if !ok {
@@ -169,6 +176,33 @@ func toStreamMessage(logicalMsg Message, relations map[uint32]*RelationMessage,
 	return message, nil
 }
 
+// decodeOldTuple decodes the old tuple of an update, which only contains the
+// key columns unless the replica identity of the table is full.
+func decodeOldTuple(tuple *TupleData, rel *RelationMessage, typeMap *pgtype.Map, unchangedToastValue any) (map[string]any, error) {
+	values := map[string]any{}
+	for idx, col := range tuple.Columns {
+		if idx >= len(rel.Columns) {
+			break
+		}
+		colName := rel.Columns[idx].Name
+		switch col.DataType {
+		case 'n': // null
+			values[colName] = nil
+		case 'u': // unchanged toast
+			values[colName] = unchangedToastValue
+		case 't': // text
+			val, err := decodeTextColumnData(typeMap, col.Data, rel.Columns[idx].DataType)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode column data: %w", err)
+			}
+			values[colName] = val
+		default:
+			return nil, fmt.Errorf("unable to decode column data, unknown data type: %d", col.DataType)
+		}
+	}
+	return values, nil
+}
+
 func decodeTextColumnData(mi *pgtype.Map, data []byte, dataType uint32) (any, error) {
 	if data == nil {
 		return nil, nil
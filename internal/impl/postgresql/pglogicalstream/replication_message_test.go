@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -834,3 +835,41 @@ func (s *logicalDecodingMessageSuite) Test() {
 
 	s.Equal(expected, logicalDecodingMsg)
 }
+
+func TestUpdateMessageBeforeImage(t *testing.T) {
+	relations := map[uint32]*RelationMessage{
+		1: {
+			RelationID:   1,
+			Namespace:    "public",
+			RelationName: "users",
+			Columns: []*RelationMessageColumn{
+				{Flags: 1, Name: "id", DataType: pgtype.Int4OID},
+				{Name: "name", DataType: pgtype.TextOID},
+			},
+		},
+	}
+	update := &UpdateMessage{
+		RelationID:   1,
+		OldTupleType: UpdateMessageTupleTypeOld,
+		OldTuple: &TupleData{Columns: []*TupleDataColumn{
+			{DataType: 't', Data: []byte("1")},
+			{DataType: 't', Data: []byte("foo")},
+		}},
+		NewTuple: &TupleData{Columns: []*TupleDataColumn{
+			{DataType: 't', Data: []byte("1")},
+			{DataType: 't', Data: []byte("bar")},
+		}},
+	}
+
+	msg, err := toStreamMessage(update, relations, pgtype.NewMap(), nil)
+	require.NoError(t, err)
+	require.Equal(t, UpdateOpType, msg.Operation)
+	require.Equal(t, map[string]any{"id": int32(1), "name": "bar"}, msg.Data)
+	require.Equal(t, map[string]any{"id": int32(1), "name": "foo"}, msg.Before)
+
+	update.OldTupleType = UpdateMessageTupleTypeNone
+	update.OldTuple = nil
+	msg, err = toStreamMessage(update, relations, pgtype.NewMap(), nil)
+	require.NoError(t, err)
+	require.Nil(t, msg.Before)
+}
@@ -44,4 +44,7 @@ type StreamMessage struct {
 	Table     string  `json:"table"`
 	// For deleted messages - there will be old changes if replica identity set to full or empty changes
 	Data any `json:"data"`
+	// For updated messages - the old values of the row if replica identity is
+	// set to full, or the old key values if the key changed, otherwise nil
+	Before any `json:"before,omitempty"`
 }
@@ -0,0 +1,349 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	pbFieldPriority  = "priority"
+	pbFieldMaxWait   = "max_wait"
+	pbFieldLimit     = "limit"
+	pbFieldBatchSize = "batch_size"
+)
+
+func priorityBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.62.0").
+		Summary("Stores messages in memory and emits them in the order of a priority computed for each message, so that urgent messages overtake bulk traffic sharing a pipeline.").
+		Description(`
+The `+"`"+pbFieldPriority+"`"+` mapping is executed on each message written to this buffer and must return a number, where messages with a greater priority are emitted first and messages with the same priority are emitted in the order that they were written. Messages for which the mapping fails are given a priority of zero.
+
+In order to prevent messages with a low priority from waiting indefinitely while messages with a higher priority keep arriving, messages that have waited for longer than `+"`"+pbFieldMaxWait+"`"+` are emitted before any other messages, in the order that they were written.
+
+Batches written to this buffer are split into individual messages, which are emitted in batches of up to `+"`"+pbFieldBatchSize+"`"+` messages. When a batch is rejected downstream its messages return to the buffer with their original priority and age.
+
+== Delivery guarantees
+
+Messages are acknowledged at the input level as soon as they're added to the buffer, and therefore messages that are pending within the buffer are lost when the service shuts down unexpectedly. Writes are blocked while the buffer holds `+"`"+pbFieldLimit+"`"+` messages, which applies back pressure to the input.`).
+		Fields(
+			service.NewBloblangField(pbFieldPriority).
+				Description("A mapping that returns the priority of a message as a number, where greater numbers are emitted first.").
+				Examples(`root = if meta("kafka_topic") == "control" { 10 } else { 0 }`, `root = this.priority.or(0)`),
+			service.NewDurationField(pbFieldMaxWait).
+				Description("The maximum period of time a message waits before it's emitted ahead of messages with a higher priority. Set to `0s` in order to always emit messages strictly by priority.").
+				Default("30s"),
+			service.NewIntField(pbFieldLimit).
+				Description("The maximum number of messages held by the buffer, beyond which writes are blocked.").
+				Default(10000),
+			service.NewIntField(pbFieldBatchSize).
+				Description("The maximum number of messages to emit within a batch.").
+				Default(1),
+		).
+		Example("Control messages first", "Consume control messages and backfill traffic from two topics, where control messages overtake any pending backfill messages.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ control, backfill ]
+    consumer_group: worker
+
+buffer:
+  priority:
+    priority: 'root = if meta("kafka_topic") == "control" { 1 } else { 0 }'
+    max_wait: 1m
+    batch_size: 50
+`)
+}
+
+func init() {
+	service.MustRegisterBatchBuffer(
+		"priority", priorityBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newPriorityBufferFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type pbEntry struct {
+	msg      *service.Message
+	priority float64
+	seq      uint64
+	added    time.Time
+	taken    bool
+}
+
+// entryHeap is a heap of entries ordered by a less function, from which taken
+// entries are removed lazily.
+type entryHeap struct {
+	entries []*pbEntry
+	less    func(a, b *pbEntry) bool
+}
+
+func (h *entryHeap) Len() int           { return len(h.entries) }
+func (h *entryHeap) Less(i, j int) bool { return h.less(h.entries[i], h.entries[j]) }
+func (h *entryHeap) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *entryHeap) Push(x any)         { h.entries = append(h.entries, x.(*pbEntry)) }
+
+func (h *entryHeap) Pop() any {
+	e := h.entries[len(h.entries)-1]
+	h.entries[len(h.entries)-1] = nil
+	h.entries = h.entries[:len(h.entries)-1]
+	return e
+}
+
+// peek returns the first entry that hasn't been taken, or nil.
+func (h *entryHeap) peek() *pbEntry {
+	for len(h.entries) > 0 {
+		if e := h.entries[0]; !e.taken {
+			return e
+		}
+		heap.Pop(h)
+	}
+	return nil
+}
+
+type priorityBuffer struct {
+	log *service.Logger
+
+	priority  *bloblang.Executor
+	maxWait   time.Duration
+	limit     int
+	batchSize int
+	clock     func() time.Time
+
+	mut        sync.Mutex
+	byPriority *entryHeap
+	byAge      *entryHeap
+	pending    int
+	nextSeq    uint64
+	endOfInput bool
+	closed     bool
+
+	// changed is closed and replaced whenever the state of the buffer changes
+	// in order to wake up blocked reads and writes.
+	changed chan struct{}
+}
+
+func newPriorityBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*priorityBuffer, error) {
+	b := &priorityBuffer{
+		log:   mgr.Logger(),
+		clock: time.Now,
+		byPriority: &entryHeap{less: func(a, b *pbEntry) bool {
+			if a.priority != b.priority {
+				return a.priority > b.priority
+			}
+			return a.seq < b.seq
+		}},
+		byAge: &entryHeap{less: func(a, b *pbEntry) bool {
+			return a.seq < b.seq
+		}},
+		changed: make(chan struct{}),
+	}
+
+	var err error
+	if b.priority, err = conf.FieldBloblang(pbFieldPriority); err != nil {
+		return nil, err
+	}
+	if b.maxWait, err = conf.FieldDuration(pbFieldMaxWait); err != nil {
+		return nil, err
+	}
+	if b.limit, err = conf.FieldInt(pbFieldLimit); err != nil {
+		return nil, err
+	}
+	if b.limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+	if b.batchSize, err = conf.FieldInt(pbFieldBatchSize); err != nil {
+		return nil, err
+	}
+	if b.batchSize <= 0 {
+		return nil, errors.New("batch_size must be greater than zero")
+	}
+	return b, nil
+}
+
+// notify wakes up blocked reads and writes, the lock must be held.
+func (b *priorityBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *priorityBuffer) push(e *pbEntry) {
+	heap.Push(b.byPriority, e)
+	heap.Push(b.byAge, e)
+	b.pending++
+}
+
+// take removes the next entry to emit, which is the oldest entry when it has
+// waited for longer than the max wait, and otherwise the entry with the
+// greatest priority.
+func (b *priorityBuffer) take(now time.Time) *pbEntry {
+	e := b.byPriority.peek()
+	if e == nil {
+		return nil
+	}
+	if b.maxWait > 0 {
+		if oldest := b.byAge.peek(); oldest != nil && now.Sub(oldest.added) >= b.maxWait {
+			e = oldest
+		}
+	}
+	e.taken = true
+	b.pending--
+	return e
+}
+
+func (b *priorityBuffer) messagePriority(exec *service.MessageBatchBloblangExecutor, i int) float64 {
+	res, err := exec.Query(i)
+	if err == nil && res != nil {
+		var v any
+		if v, err = res.AsStructured(); err == nil {
+			var p float64
+			if p, err = bloblang.ValueAsFloat64(v); err == nil {
+				return p
+			}
+		}
+	}
+	if err == nil {
+		err = errors.New("mapping returned no value")
+	}
+	b.log.Debugf("Failed to compute priority of message %v, using zero: %v", i, err)
+	return 0
+}
+
+func (b *priorityBuffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	exec := batch.BloblangExecutor(b.priority)
+	priorities := make([]float64, len(batch))
+	for i := range batch {
+		priorities[i] = b.messagePriority(exec, i)
+	}
+
+	b.mut.Lock()
+	for b.pending > 0 && b.pending+len(batch) > b.limit {
+		if b.closed {
+			b.mut.Unlock()
+			return service.ErrEndOfBuffer
+		}
+		changed := b.changed
+		b.mut.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		b.mut.Lock()
+	}
+	if b.closed {
+		b.mut.Unlock()
+		return service.ErrEndOfBuffer
+	}
+
+	now := b.clock()
+	for i, msg := range batch {
+		b.push(&pbEntry{
+			msg:      msg,
+			priority: priorities[i],
+			seq:      b.nextSeq,
+			added:    now,
+		})
+		b.nextSeq++
+	}
+	b.notify()
+	b.mut.Unlock()
+
+	return aFn(ctx, nil)
+}
+
+func (b *priorityBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	b.mut.Lock()
+	for b.pending == 0 {
+		if b.closed || b.endOfInput {
+			b.mut.Unlock()
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		changed := b.changed
+		b.mut.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		b.mut.Lock()
+	}
+
+	now := b.clock()
+	entries := make([]*pbEntry, 0, min(b.batchSize, b.pending))
+	for len(entries) < b.batchSize {
+		e := b.take(now)
+		if e == nil {
+			break
+		}
+		entries = append(entries, e)
+	}
+	b.notify()
+	b.mut.Unlock()
+
+	batch := make(service.MessageBatch, len(entries))
+	for i, e := range entries {
+		batch[i] = e.msg.Copy()
+	}
+	return batch, func(_ context.Context, err error) error {
+		if err == nil {
+			return nil
+		}
+
+		b.mut.Lock()
+		defer b.mut.Unlock()
+		if b.closed {
+			return nil
+		}
+		for _, e := range entries {
+			// Taken entries may still be referenced by the heaps, and so
+			// rejected messages are added as new entries.
+			b.push(&pbEntry{msg: e.msg, priority: e.priority, seq: e.seq, added: e.added})
+		}
+		b.notify()
+		return nil
+	}, nil
+}
+
+func (b *priorityBuffer) EndOfInput() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.endOfInput = true
+	b.notify()
+}
+
+func (b *priorityBuffer) Close(context.Context) error {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.closed = true
+	b.notify()
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestBuffer(t *testing.T, conf string) *priorityBuffer {
+	t.Helper()
+
+	pConf, err := priorityBufferConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	b, err := newPriorityBufferFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	return b
+}
+
+func writeDocs(t *testing.T, b *priorityBuffer, docs ...string) {
+	t.Helper()
+
+	var batch service.MessageBatch
+	for _, d := range docs {
+		batch = append(batch, service.NewMessage([]byte(d)))
+	}
+	require.NoError(t, b.WriteBatch(t.Context(), batch, func(context.Context, error) error {
+		return nil
+	}))
+}
+
+func readDocs(t *testing.T, b *priorityBuffer) ([]string, service.AckFunc) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), time.Second*5)
+	defer done()
+
+	batch, aFn, err := b.ReadBatch(ctx)
+	require.NoError(t, err)
+
+	docs := make([]string, len(batch))
+	for i, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		docs[i] = string(b)
+	}
+	return docs, aFn
+}
+
+func TestPriorityBufferOrdering(t *testing.T) {
+	b := newTestBuffer(t, `
+priority: root = this.p
+batch_size: 2
+`)
+
+	writeDocs(t, b, `{"id":"a","p":0}`, `{"id":"b","p":5}`, `{"id":"c","p":"nope"}`)
+	writeDocs(t, b, `{"id":"d","p":5}`, `{"id":"e","p":1.5}`)
+	b.EndOfInput()
+
+	var ids []string
+	for {
+		batch, _, err := b.ReadBatch(t.Context())
+		if errors.Is(err, service.ErrEndOfBuffer) {
+			break
+		}
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(batch), 2)
+		for _, msg := range batch {
+			v, err := msg.AsStructured()
+			require.NoError(t, err)
+			ids = append(ids, v.(map[string]any)["id"].(string))
+		}
+	}
+	assert.Equal(t, []string{"b", "d", "e", "a", "c"}, ids)
+}
+
+func TestPriorityBufferMaxWait(t *testing.T) {
+	b := newTestBuffer(t, `
+priority: root = this.p
+max_wait: 10s
+`)
+	now := time.Unix(1000, 0)
+	b.clock = func() time.Time { return now }
+
+	writeDocs(t, b, `{"p":0}`)
+	now = now.Add(time.Second * 5)
+	writeDocs(t, b, `{"p":1}`, `{"p":2}`)
+
+	docs, _ := readDocs(t, b)
+	assert.Equal(t, []string{`{"p":2}`}, docs)
+
+	// The low priority message has now waited for longer than the max wait.
+	now = now.Add(time.Second * 5)
+	docs, _ = readDocs(t, b)
+	assert.Equal(t, []string{`{"p":0}`}, docs)
+
+	docs, _ = readDocs(t, b)
+	assert.Equal(t, []string{`{"p":1}`}, docs)
+}
+
+func TestPriorityBufferNackRequeue(t *testing.T) {
+	b := newTestBuffer(t, `
+priority: root = this.p
+`)
+
+	writeDocs(t, b, `{"p":1}`, `{"p":2}`)
+
+	docs, aFn := readDocs(t, b)
+	assert.Equal(t, []string{`{"p":2}`}, docs)
+	require.NoError(t, aFn(t.Context(), errors.New("nope")))
+
+	docs, aFn = readDocs(t, b)
+	assert.Equal(t, []string{`{"p":2}`}, docs)
+	require.NoError(t, aFn(t.Context(), nil))
+
+	docs, _ = readDocs(t, b)
+	assert.Equal(t, []string{`{"p":1}`}, docs)
+
+	b.EndOfInput()
+	_, _, err := b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestPriorityBufferLimit(t *testing.T) {
+	b := newTestBuffer(t, `
+priority: root = 0
+limit: 2
+`)
+
+	writeDocs(t, b, `a`, `b`)
+
+	ctx, done := context.WithTimeout(t.Context(), time.Millisecond*50)
+	defer done()
+	err := b.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("c"))}, func(context.Context, error) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	written := make(chan error, 1)
+	go func() {
+		written <- b.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("c"))}, func(context.Context, error) error {
+			return nil
+		})
+	}()
+
+	docs, _ := readDocs(t, b)
+	assert.Equal(t, []string{"a"}, docs)
+
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write was not unblocked")
+	}
+	require.NoError(t, b.Close(t.Context()))
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package profile
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+const threadCPUTimeSupported = true
+
+// measureThreadCPU calls fn with the calling goroutine locked to its thread
+// and returns the CPU time consumed by the thread in the meantime, which
+// excludes the work of any goroutines started by fn.
+func measureThreadCPU(fn func()) (ns int64, ok bool) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var before, after unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &before); err != nil {
+		fn()
+		return 0, false
+	}
+	fn()
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &after); err != nil {
+		return 0, false
+	}
+	return rusageNanos(&after) - rusageNanos(&before), true
+}
+
+func rusageNanos(r *unix.Rusage) int64 {
+	return unix.TimevalToNsec(r.Utime) + unix.TimevalToNsec(r.Stime)
+}
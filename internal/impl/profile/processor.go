@@ -0,0 +1,137 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ppFieldName       = "name"
+	ppFieldProcessors = "processors"
+
+	// LabelKey is the pprof label under which the name of the profile
+	// processor executing a goroutine is recorded.
+	LabelKey = "component"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Executes a list of child processors and attributes the resources they consume to a name, for finding the processors of a config that consume the most CPU.").
+		Description(`
+The CPU time consumed executing the child processors is recorded by the counter metric `+"`component_cpu_ns`"+`, which is labelled with the `+"`label`"+` and `+"`path`"+` of this processor like other component metrics. Comparing the rates of this metric between profile processors shows which parts of a config are burning resources. CPU time is measured by the operating system for the thread executing the child processors, which is only supported on Linux, and excludes work that the child processors hand off to other goroutines such as within the `+"`parallel`"+` and `+"`workflow`"+` processors.
+
+The child processors are also executed with the https://pkg.go.dev/runtime/pprof#Labels[pprof label^] `+"`"+LabelKey+"`"+` set to the name of this processor, and so CPU profiles captured from the running process, including those from goroutines spawned by the child processors, can be broken down by it. When the `+"`--profiling-address`"+` flag is set the CPU time of each name can be captured on demand from the `+"`/debug/profile/components`"+` endpoint, and otherwise profiles can be filtered with `+"`go tool pprof -tagfocus "+LabelKey+"=<name>`"+`.
+
+Memory allocations can't be attributed to goroutines by the Go runtime, and so are not broken down by name.`).
+		Fields(
+			service.NewStringField(ppFieldName).
+				Description("The name to attribute resources to. Defaults to the label of the processor.").
+				Default(""),
+			service.NewProcessorListField(ppFieldProcessors).
+				Description("The processors to execute."),
+		).
+		Example(
+			"Compare enrichment steps",
+			"Wrap the steps of a pipeline in order to find out which of them consumes the most CPU.",
+			`
+pipeline:
+  processors:
+    - label: parse
+      profile:
+        processors:
+          - mapping: 'root = content().parse_json()'
+    - label: enrich
+      profile:
+        processors:
+          - branch:
+              request_map: 'root.id = this.customer_id'
+              processors:
+                - cache:
+                    resource: customers
+                    operator: get
+                    key: ${! this.id }
+              result_map: 'root.customer = this'
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor("profile", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newProcessorFromConfig(conf, mgr)
+		})
+}
+
+type processor struct {
+	labels     pprof.LabelSet
+	children   []*service.OwnedProcessor
+	cpuCounter *service.MetricCounter
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	name, err := conf.FieldString(ppFieldName)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = mgr.Label()
+	}
+	if name == "" {
+		return nil, errors.New("either a name or a label must be set")
+	}
+
+	children, err := conf.FieldProcessorList(ppFieldProcessors)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &processor{
+		labels:   pprof.Labels(LabelKey, name),
+		children: children,
+	}
+	if threadCPUTimeSupported {
+		p.cpuCounter = mgr.Metrics().NewCounter("component_cpu_ns")
+	}
+	return p, nil
+}
+
+func (p *processor) ProcessBatch(ctx context.Context, batch service.MessageBatch) (batches []service.MessageBatch, err error) {
+	pprof.Do(ctx, p.labels, func(ctx context.Context) {
+		ns, ok := measureThreadCPU(func() {
+			batches, err = service.ExecuteProcessors(ctx, p.children, batch)
+		})
+		if ok {
+			p.cpuCounter.Incr(ns)
+		}
+	})
+	return
+}
+
+func (p *processor) Close(ctx context.Context) error {
+	for _, c := range p.children {
+		if err := c.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
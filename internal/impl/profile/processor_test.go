@@ -0,0 +1,85 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type labelProbe struct {
+	labels *[]string
+}
+
+func (l labelProbe) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	v, _ := pprof.Label(ctx, LabelKey)
+	*l.labels = append(*l.labels, v)
+	return service.MessageBatch{msg}, nil
+}
+
+func (labelProbe) Close(context.Context) error {
+	return nil
+}
+
+func TestProfileProcessorLabels(t *testing.T) {
+	var labels []string
+
+	env := service.NewEmptyEnvironment()
+	require.NoError(t, env.RegisterProcessor("label_probe", service.NewConfigSpec(),
+		func(*service.ParsedConfig, *service.Resources) (service.Processor, error) {
+			return labelProbe{labels: &labels}, nil
+		}))
+
+	pConf, err := processorSpec().ParseYAML(`
+name: enrich
+processors:
+  - label_probe: {}
+`, env)
+	require.NoError(t, err)
+	p, err := newProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("foo")),
+		service.NewMessage([]byte("bar")),
+	}
+	batches, err := p.ProcessBatch(t.Context(), batch)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, []string{"enrich", "enrich"}, labels)
+
+	// Labels aren't leaked to the caller.
+	_, exists := pprof.Label(t.Context(), LabelKey)
+	assert.False(t, exists)
+}
+
+func TestProfileProcessorRequiresName(t *testing.T) {
+	pConf, err := processorSpec().ParseYAML(`
+processors: []
+`, nil)
+	require.NoError(t, err)
+	_, err = newProcessorFromConfig(pConf, service.MockResources())
+	require.ErrorContains(t, err, "name or a label")
+}
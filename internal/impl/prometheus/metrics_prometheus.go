@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -46,6 +47,10 @@ const (
 	pmFieldPushInterval                = "push_interval"
 	pmFieldPushJobName                 = "push_job_name"
 	pmFieldFileOutputPath              = "file_output_path"
+	pmFieldNativeHistogramBucketFactor = "native_histogram_bucket_factor"
+	pmFieldNativeHistogramMaxBuckets   = "native_histogram_max_buckets"
+	pmFieldMaxLabelValues              = "max_label_values"
+	pmFieldLabelOverflowValue          = "label_overflow_value"
 )
 
 func configSpec() *service.ConfigSpec {
@@ -71,6 +76,17 @@ If the Push Gateway requires HTTP Basic Authentication it can be configured with
 				Advanced().
 				Version("3.63.0").
 				Default([]any{}),
+			service.NewFloatField(pmFieldNativeHistogramBucketFactor).
+				Description("When greater than `1` timing histograms are also exported as https://prometheus.io/docs/specs/native_histograms/[native histograms^] with exponential buckets, where the upper bound of each bucket is at most this factor of the upper bound of the previous bucket. Native histograms are only scraped by Prometheus when the protobuf exposition format is negotiated, and are exported alongside the buckets of `histogram_buckets` so that other scrapers remain unaffected. Applicable when `use_histogram_timing` is set to `true`.").
+				Example(1.1).
+				Advanced().
+				Version("4.62.0").
+				Default(0.0),
+			service.NewIntField(pmFieldNativeHistogramMaxBuckets).
+				Description("The maximum number of buckets of each native histogram, beyond which the resolution of the histogram is reduced.").
+				Advanced().
+				Version("4.62.0").
+				Default(160),
 			service.NewObjectListField(pmFieldSummaryQuantilesObj,
 				service.NewFloatField(pmFieldSummaryQuantilesObjQuantile).
 					Description("Quantile value.").
@@ -92,6 +108,16 @@ If the Push Gateway requires HTTP Basic Authentication it can be configured with
 					map[string]any{"quantile": 0.9, "error": 0.01},
 					map[string]any{"quantile": 0.99, "error": 0.001},
 				}),
+			service.NewIntField(pmFieldMaxLabelValues).
+				Description("The maximum number of distinct values of each label of a metric, set to `0` for no limit. Values seen after the limit is reached are replaced with `label_overflow_value`, which protects the exporter and scrapers from unbounded cardinality, such as from labels of the `metric` processor that are interpolated from message contents.").
+				Advanced().
+				Version("4.62.0").
+				Default(0),
+			service.NewStringField(pmFieldLabelOverflowValue).
+				Description("The value of labels with more distinct values than `max_label_values`.").
+				Advanced().
+				Version("4.62.0").
+				Default("__overflow__"),
 			service.NewBoolField(pmFieldAddProcessMetrics).
 				Description("Whether to export process metrics such as CPU and memory usage in addition to Redpanda Connect metrics.").
 				Advanced().
@@ -195,50 +221,120 @@ func (p *promTiming) Timing(val int64) {
 //------------------------------------------------------------------------------
 
 type promCounterVec struct {
-	ctr   *prometheus.CounterVec
-	count int
+	ctr    *prometheus.CounterVec
+	count  int
+	limits *labelLimits
 }
 
 func (p *promCounterVec) With(labelValues ...string) service.MetricsExporterCounter {
 	return &promCounter{
-		ctr: p.ctr.WithLabelValues(labelValues...),
+		ctr: p.ctr.WithLabelValues(p.limits.apply(labelValues)...),
 	}
 }
 
 type promTimingVec struct {
-	sum   *prometheus.SummaryVec
-	count int
+	sum    *prometheus.SummaryVec
+	count  int
+	limits *labelLimits
 }
 
 func (p *promTimingVec) With(labelValues ...string) service.MetricsExporterTimer {
 	return &promTiming{
-		sum: p.sum.WithLabelValues(labelValues...),
+		sum: p.sum.WithLabelValues(p.limits.apply(labelValues)...),
 	}
 }
 
 type promTimingHistVec struct {
-	sum   *prometheus.HistogramVec
-	count int
+	sum    *prometheus.HistogramVec
+	count  int
+	limits *labelLimits
 }
 
 func (p *promTimingHistVec) With(labelValues ...string) service.MetricsExporterTimer {
 	return &promTiming{
 		asSeconds: true,
-		sum:       p.sum.WithLabelValues(labelValues...),
+		sum:       p.sum.WithLabelValues(p.limits.apply(labelValues)...),
 	}
 }
 
 type promGaugeVec struct {
-	ctr   *prometheus.GaugeVec
-	count int
+	ctr    *prometheus.GaugeVec
+	count  int
+	limits *labelLimits
 }
 
 func (p *promGaugeVec) With(labelValues ...string) service.MetricsExporterGauge {
 	return &promGauge{
-		ctr: p.ctr.WithLabelValues(labelValues...),
+		ctr: p.ctr.WithLabelValues(p.limits.apply(labelValues)...),
 	}
 }
 
+// labelLimits caps the number of distinct values of each label of a metric,
+// replacing values seen after the limit is reached with an overflow value. A
+// nil labelLimits applies no limits.
+type labelLimits struct {
+	log      *service.Logger
+	path     string
+	names    []string
+	max      int
+	overflow string
+
+	mut    sync.Mutex
+	seen   []map[string]struct{}
+	warned []bool
+}
+
+func (p *metrics) newLabelLimits(path string, labelNames []string) *labelLimits {
+	if p.maxLabelValues <= 0 || len(labelNames) == 0 {
+		return nil
+	}
+	l := &labelLimits{
+		log:      p.log,
+		path:     path,
+		names:    labelNames,
+		max:      p.maxLabelValues,
+		overflow: p.labelOverflowValue,
+		seen:     make([]map[string]struct{}, len(labelNames)),
+		warned:   make([]bool, len(labelNames)),
+	}
+	for i := range l.seen {
+		l.seen[i] = map[string]struct{}{}
+	}
+	return l
+}
+
+func (l *labelLimits) apply(values []string) []string {
+	if l == nil {
+		return values
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	var limited []string
+	for i, v := range values[:min(len(values), len(l.seen))] {
+		if _, exists := l.seen[i][v]; exists {
+			continue
+		}
+		if len(l.seen[i]) < l.max {
+			l.seen[i][v] = struct{}{}
+			continue
+		}
+		if limited == nil {
+			limited = slices.Clone(values)
+		}
+		limited[i] = l.overflow
+		if !l.warned[i] {
+			l.warned[i] = true
+			l.log.Warnf("Label '%v' of metric '%v' exceeded %v distinct values, further values are replaced with '%v'", l.names[i], l.path, l.max, l.overflow)
+		}
+	}
+	if limited != nil {
+		return limited
+	}
+	return values
+}
+
 //------------------------------------------------------------------------------
 
 type metrics struct {
@@ -248,9 +344,13 @@ type metrics struct {
 
 	fileOutputPath string
 
-	useHistogramTiming bool
-	histogramBuckets   []float64
-	summaryQuantiles   map[float64]float64
+	useHistogramTiming          bool
+	histogramBuckets            []float64
+	nativeHistogramBucketFactor float64
+	nativeHistogramMaxBuckets   uint32
+	summaryQuantiles            map[float64]float64
+	maxLabelValues              int
+	labelOverflowValue          string
 
 	pusher *push.Pusher
 	reg    *prometheus.Registry
@@ -302,6 +402,28 @@ func fromParsed(conf *service.ParsedConfig, log *service.Logger) (p *metrics, er
 		p.histogramBuckets = prometheus.DefBuckets
 	}
 
+	if p.nativeHistogramBucketFactor, err = conf.FieldFloat(pmFieldNativeHistogramBucketFactor); err != nil {
+		return
+	}
+	if p.nativeHistogramBucketFactor != 0 && p.nativeHistogramBucketFactor <= 1 {
+		return nil, fmt.Errorf("%v must be greater than 1", pmFieldNativeHistogramBucketFactor)
+	}
+	var maxBuckets int
+	if maxBuckets, err = conf.FieldInt(pmFieldNativeHistogramMaxBuckets); err != nil {
+		return
+	}
+	if maxBuckets < 0 {
+		return nil, fmt.Errorf("%v must not be negative", pmFieldNativeHistogramMaxBuckets)
+	}
+	p.nativeHistogramMaxBuckets = uint32(maxBuckets)
+
+	if p.maxLabelValues, err = conf.FieldInt(pmFieldMaxLabelValues); err != nil {
+		return
+	}
+	if p.labelOverflowValue, err = conf.FieldString(pmFieldLabelOverflowValue); err != nil {
+		return
+	}
+
 	if quantilesParsedList, _ := conf.FieldObjectList(pmFieldSummaryQuantilesObj); len(quantilesParsedList) > 0 {
 		if p.summaryQuantiles, err = quantilesAsFloatMapFromParsed(quantilesParsedList); err != nil {
 			return
@@ -389,8 +511,9 @@ func (p *metrics) NewCounterCtor(path string, labelNames ...string) service.Metr
 		p.reg.MustRegister(ctr)
 
 		pv = &promCounterVec{
-			ctr:   ctr,
-			count: len(labelNames),
+			ctr:    ctr,
+			count:  len(labelNames),
+			limits: p.newLabelLimits(path, labelNames),
 		}
 		p.counters[path] = pv
 	}
@@ -432,8 +555,9 @@ func (p *metrics) NewTimerCtor(path string, labelNames ...string) service.Metric
 		p.reg.MustRegister(tmr)
 
 		pv = &promTimingVec{
-			sum:   tmr,
-			count: len(labelNames),
+			sum:    tmr,
+			count:  len(labelNames),
+			limits: p.newLabelLimits(path, labelNames),
 		}
 		p.timers[path] = pv
 	}
@@ -456,16 +580,23 @@ func (p *metrics) getTimerHistVec(path string, labelNames ...string) service.Met
 	p.mut.Lock()
 	var exists bool
 	if pv, exists = p.timersHist[path]; !exists {
-		tmr := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		opts := prometheus.HistogramOpts{
 			Name:    path,
 			Help:    "Benthos Timing metric",
 			Buckets: p.histogramBuckets,
-		}, labelNames)
+		}
+		if p.nativeHistogramBucketFactor > 1 {
+			opts.NativeHistogramBucketFactor = p.nativeHistogramBucketFactor
+			opts.NativeHistogramMaxBucketNumber = p.nativeHistogramMaxBuckets
+			opts.NativeHistogramMinResetDuration = time.Hour
+		}
+		tmr := prometheus.NewHistogramVec(opts, labelNames)
 		p.reg.MustRegister(tmr)
 
 		pv = &promTimingHistVec{
-			sum:   tmr,
-			count: len(labelNames),
+			sum:    tmr,
+			count:  len(labelNames),
+			limits: p.newLabelLimits(path, labelNames),
 		}
 		p.timersHist[path] = pv
 	}
@@ -502,8 +633,9 @@ func (p *metrics) NewGaugeCtor(path string, labelNames ...string) service.Metric
 		p.reg.MustRegister(ctr)
 
 		pv = &promGaugeVec{
-			ctr:   ctr,
-			count: len(labelNames),
+			ctr:    ctr,
+			count:  len(labelNames),
+			limits: p.newLabelLimits(path, labelNames),
 		}
 		p.gauges[path] = pv
 	}
@@ -236,3 +236,67 @@ func assertContainsTestMetrics(t *testing.T, body string) {
 	assert.Contains(t, body, "\ncountertwo{label1=\"value2\"} 11")
 	assert.Contains(t, body, "\ngaugetwo{label2=\"value3\"} 12")
 }
+
+func TestPrometheusNativeHistograms(t *testing.T) {
+	nm := promFromYAML(t, `
+use_histogram_timing: true
+native_histogram_bucket_factor: 1.1
+`)
+
+	tmr := nm.NewTimerCtor("timerone", "label1")("value1")
+	tmr.Timing(13)
+	tmr.Timing(1_000_000)
+
+	families, err := nm.reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].Metric, 1)
+
+	hist := families[0].Metric[0].GetHistogram()
+	require.NotNil(t, hist)
+	assert.Equal(t, uint64(2), hist.GetSampleCount())
+	assert.NotNil(t, hist.Schema, "native histogram schema")
+	assert.NotEmpty(t, hist.GetPositiveSpan())
+
+	// Classic buckets are still exported.
+	assert.Contains(t, getPage(t, nm.HandlerFunc()), "\ntimerone_bucket{label1=\"value1\",le=\"+Inf\"} 2")
+}
+
+func TestPrometheusNativeHistogramsBadFactor(t *testing.T) {
+	pConf, err := configSpec().ParseYAML(`
+use_histogram_timing: true
+native_histogram_bucket_factor: 0.5
+`, nil)
+	require.NoError(t, err)
+
+	_, err = fromParsed(pConf, nil)
+	require.ErrorContains(t, err, "must be greater than 1")
+}
+
+func TestPrometheusLabelLimits(t *testing.T) {
+	nm := promFromYAML(t, `
+max_label_values: 2
+`)
+
+	ctr := nm.NewCounterCtor("counterone", "topic", "partition")
+	ctr("a", "0").Incr(1)
+	ctr("b", "0").Incr(1)
+	ctr("c", "0").Incr(1)
+	ctr("d", "1").Incr(1)
+	ctr("a", "2").Incr(1)
+
+	gge := nm.NewGaugeCtor("gaugeone", "key")
+	for _, k := range []string{"x", "y", "z"} {
+		gge(k).Set(1)
+	}
+
+	body := getPage(t, nm.HandlerFunc())
+	assert.Contains(t, body, "\ncounterone{partition=\"0\",topic=\"a\"} 1")
+	assert.Contains(t, body, "\ncounterone{partition=\"0\",topic=\"b\"} 1")
+	assert.Contains(t, body, "\ncounterone{partition=\"0\",topic=\"__overflow__\"} 1")
+	assert.Contains(t, body, "\ncounterone{partition=\"1\",topic=\"__overflow__\"} 1")
+	assert.Contains(t, body, "\ncounterone{partition=\"__overflow__\",topic=\"a\"} 1")
+	assert.Contains(t, body, "\ngaugeone{key=\"__overflow__\"} 1")
+	assert.NotContains(t, body, "topic=\"c\"")
+	assert.NotContains(t, body, "key=\"z\"")
+}
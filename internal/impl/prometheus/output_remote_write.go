@@ -0,0 +1,408 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	rwFieldURL              = "url"
+	rwFieldHeaders          = "headers"
+	rwFieldMetricName       = "metric_name"
+	rwFieldLabelsMapping    = "labels_mapping"
+	rwFieldValueMapping     = "value_mapping"
+	rwFieldTimestampMapping = "timestamp_mapping"
+	rwFieldTLS              = "tls"
+	rwFieldTimeout          = "timeout"
+	rwFieldBatching         = "batching"
+)
+
+func remoteWriteOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Writes messages as samples to a Prometheus remote write endpoint.").
+		Description(`
+Each message is converted into a sample of a time series, where the metric name, labels, value and timestamp of the sample are extracted from the message. Each batch of messages is written with a single https://prometheus.io/docs/specs/prw/remote_write_spec/[remote write request^], which is supported by Prometheus as well as compatible databases such as Grafana Mimir, Cortex, Thanos and VictoriaMetrics.
+
+Samples of the same series within a batch are sent in order of their timestamps. Most databases reject samples that are older than the latest sample of their series, and therefore messages of each series should be written in order.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewURLField(rwFieldURL).
+				Description("The URL of the remote write endpoint.").
+				Example("http://localhost:9090/api/v1/write"),
+			service.NewStringMapField(rwFieldHeaders).
+				Description("A map of headers to add to each request.").
+				Example(map[string]any{"X-Scope-OrgID": "tenant-1"}).
+				Default(map[string]any{}).
+				Advanced(),
+		).
+		Fields(service.NewHTTPRequestAuthSignerFields()...).
+		Fields(
+			service.NewInterpolatedStringField(rwFieldMetricName).
+				Description("The metric name of each sample.").
+				Example("http_requests_total").
+				Example(`${! this.name }`),
+			service.NewBloblangField(rwFieldLabelsMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to an object of labels, where values are converted into strings and empty values are omitted.").
+				Example(`root = { "service": this.service, "status": this.status.string() }`).
+				Optional(),
+			service.NewBloblangField(rwFieldValueMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to the numeric value of each sample.").
+				Default("root = this.value"),
+			service.NewBloblangField(rwFieldTimestampMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that should evaluate to the timestamp of each sample. When omitted samples are given the time at which they're written.").
+				Example(`root = this.timestamp`).
+				Optional(),
+			service.NewTLSToggledField(rwFieldTLS).
+				Advanced(),
+			service.NewDurationField(rwFieldTimeout).
+				Description("The maximum period of time to wait for each write request.").
+				Default("10s").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(rwFieldBatching),
+		).
+		Example("Metrics from Kafka", "Writes metrics consumed from a Kafka topic to Grafana Mimir.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ metrics ]
+    consumer_group: mimir
+
+output:
+  prometheus_remote_write:
+    url: http://localhost:9009/api/v1/push
+    headers:
+      X-Scope-OrgID: tenant-1
+    metric_name: ${! this.name }
+    labels_mapping: 'root = this.labels'
+    value_mapping: 'root = this.value'
+    timestamp_mapping: 'root = this.timestamp'
+    batching:
+      count: 1000
+      period: 1s
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"prometheus_remote_write", remoteWriteOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(rwFieldBatching); err != nil {
+				return
+			}
+			out, err = newRemoteWriteOutputFromParsed(conf, mgr)
+			return
+		})
+}
+
+type remoteWriteOutput struct {
+	url     string
+	headers map[string]string
+	signer  func(fs.FS, *http.Request) error
+	fs      fs.FS
+
+	metricName       *service.InterpolatedString
+	labelsMapping    *bloblang.Executor
+	valueMapping     *bloblang.Executor
+	timestampMapping *bloblang.Executor
+
+	client *http.Client
+	nowFn  func() time.Time
+}
+
+func newRemoteWriteOutputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*remoteWriteOutput, error) {
+	o := &remoteWriteOutput{
+		fs:    mgr.FS(),
+		nowFn: time.Now,
+	}
+
+	u, err := conf.FieldURL(rwFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	o.url = u.String()
+
+	if o.headers, err = conf.FieldStringMap(rwFieldHeaders); err != nil {
+		return nil, err
+	}
+	if o.signer, err = conf.HTTPRequestAuthSignerFromParsed(); err != nil {
+		return nil, err
+	}
+
+	if o.metricName, err = conf.FieldInterpolatedString(rwFieldMetricName); err != nil {
+		return nil, err
+	}
+	if conf.Contains(rwFieldLabelsMapping) {
+		if o.labelsMapping, err = conf.FieldBloblang(rwFieldLabelsMapping); err != nil {
+			return nil, err
+		}
+	}
+	if o.valueMapping, err = conf.FieldBloblang(rwFieldValueMapping); err != nil {
+		return nil, err
+	}
+	if conf.Contains(rwFieldTimestampMapping) {
+		if o.timestampMapping, err = conf.FieldBloblang(rwFieldTimestampMapping); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(rwFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(rwFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{Transport: transport, Timeout: timeout}
+	return o, nil
+}
+
+func (*remoteWriteOutput) Connect(context.Context) error {
+	return nil
+}
+
+type rwLabel struct {
+	name, value string
+}
+
+type rwSample struct {
+	value     float64
+	timestamp int64
+}
+
+type rwSeries struct {
+	labels  []rwLabel
+	samples []rwSample
+}
+
+var (
+	metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegex  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+func (o *remoteWriteOutput) batchToSeries(batch service.MessageBatch) ([]*rwSeries, error) {
+	var labelsExec, timestampExec *service.MessageBatchBloblangExecutor
+	if o.labelsMapping != nil {
+		labelsExec = batch.BloblangExecutor(o.labelsMapping)
+	}
+	if o.timestampMapping != nil {
+		timestampExec = batch.BloblangExecutor(o.timestampMapping)
+	}
+	valueExec := batch.BloblangExecutor(o.valueMapping)
+	nameExec := batch.InterpolationExecutor(o.metricName)
+	now := o.nowFn().UnixMilli()
+
+	var series []*rwSeries
+	seriesIndexes := map[string]int{}
+	for i := range batch {
+		name, err := nameExec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("metric name interpolation error: %w", err)
+		}
+		if !metricNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("message %v has an invalid metric name: %q", i, name)
+		}
+
+		labels := []rwLabel{{name: "__name__", value: name}}
+		if labelsExec != nil {
+			v, err := queryStructured(labelsExec, i)
+			if err != nil {
+				return nil, fmt.Errorf("labels mapping failed: %w", err)
+			}
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("labels mapping returned non-object result: %T", v)
+			}
+			for _, k := range slices.Sorted(maps.Keys(obj)) {
+				if obj[k] == nil {
+					continue
+				}
+				value := bloblang.ValueToString(obj[k])
+				if value == "" {
+					continue
+				}
+				if k == "__name__" || !labelNameRegex.MatchString(k) {
+					return nil, fmt.Errorf("message %v has an invalid label name: %q", i, k)
+				}
+				labels = append(labels, rwLabel{name: k, value: value})
+			}
+		}
+		slices.SortFunc(labels, func(a, b rwLabel) int { return cmp.Compare(a.name, b.name) })
+
+		v, err := queryStructured(valueExec, i)
+		if err != nil {
+			return nil, fmt.Errorf("value mapping failed: %w", err)
+		}
+		var value float64
+		if b, isBool := v.(bool); isBool {
+			if b {
+				value = 1
+			}
+		} else if value, err = bloblang.ValueAsFloat64(v); err != nil {
+			return nil, fmt.Errorf("value mapping failed: %w", err)
+		}
+
+		timestamp := now
+		if timestampExec != nil {
+			v, err := queryStructured(timestampExec, i)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			t, err := bloblang.ValueAsTimestamp(v)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp mapping failed: %w", err)
+			}
+			timestamp = t.UnixMilli()
+		}
+
+		var key strings.Builder
+		for _, l := range labels {
+			key.WriteString(l.name)
+			key.WriteByte(0)
+			key.WriteString(l.value)
+			key.WriteByte(0)
+		}
+		si, exists := seriesIndexes[key.String()]
+		if !exists {
+			si = len(series)
+			seriesIndexes[key.String()] = si
+			series = append(series, &rwSeries{labels: labels})
+		}
+		series[si].samples = append(series[si].samples, rwSample{value: value, timestamp: timestamp})
+	}
+
+	for _, s := range series {
+		slices.SortStableFunc(s.samples, func(a, b rwSample) int { return cmp.Compare(a.timestamp, b.timestamp) })
+	}
+	return series, nil
+}
+
+// queryStructured executes a mapping, where results that are strings are
+// returned as strings rather than parsed.
+func queryStructured(exec *service.MessageBatchBloblangExecutor, i int) (any, error) {
+	msg, err := exec.Query(i)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	v, err := msg.AsStructured()
+	if err != nil {
+		b, _ := msg.AsBytes()
+		return string(b), nil
+	}
+	return v, nil
+}
+
+// encodeWriteRequest encodes series as a prometheus.WriteRequest protobuf
+// message.
+func encodeWriteRequest(series []*rwSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.value)
+
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, smp := range s.samples {
+			var sample []byte
+			sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+			sample = protowire.AppendFixed64(sample, math.Float64bits(smp.value))
+			sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+			sample = protowire.AppendVarint(sample, uint64(smp.timestamp))
+
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, sample)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return req
+}
+
+func (o *remoteWriteOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	series, err := o.batchToSeries(batch)
+	if err != nil {
+		return err
+	}
+	body := s2.EncodeSnappy(nil, encodeWriteRequest(series))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+	if err := o.signer(o.fs, req); err != nil {
+		return err
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return fmt.Errorf("remote write failed with status %v: %s", res.StatusCode, bytes.TrimSpace(resBody))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (o *remoteWriteOutput) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// decodeFields parses a protobuf message into the raw values of its fields.
+func decodeFields(t *testing.T, b []byte) (fields []protowire.Number, values []any) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			values = append(values, v)
+			b = b[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			require.GreaterOrEqual(t, n, 0)
+			values = append(values, math.Float64frombits(v))
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			values = append(values, int64(v))
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type: %v", typ)
+		}
+		fields = append(fields, num)
+	}
+	return
+}
+
+// decodeWriteRequest renders each series of a write request as a string.
+func decodeWriteRequest(t *testing.T, b []byte) []string {
+	t.Helper()
+	var series []string
+	_, tss := decodeFields(t, b)
+	for _, ts := range tss {
+		var labels, samples []string
+		fields, values := decodeFields(t, ts.([]byte))
+		for i, f := range fields {
+			_, v := decodeFields(t, values[i].([]byte))
+			switch f {
+			case 1:
+				labels = append(labels, string(v[0].([]byte))+"="+string(v[1].([]byte)))
+			case 2:
+				samples = append(samples, strconv.FormatFloat(v[0].(float64), 'g', -1, 64)+"@"+strconv.FormatInt(v[1].(int64), 10))
+			}
+		}
+		series = append(series, "{"+strings.Join(labels, ",")+"} "+strings.Join(samples, " "))
+	}
+	return series
+}
+
+func TestRemoteWriteOutput(t *testing.T) {
+	var series []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/write", r.URL.Path)
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+		assert.Equal(t, "tenant-1", r.Header.Get("X-Scope-OrgID"))
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		b, err = s2.Decode(nil, b)
+		require.NoError(t, err)
+		series = decodeWriteRequest(t, b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	conf, err := remoteWriteOutputSpec().ParseYAML(`
+url: `+srv.URL+`/api/v1/write
+headers:
+  X-Scope-OrgID: tenant-1
+metric_name: ${! this.name }
+labels_mapping: 'root = this.labels'
+value_mapping: 'root = this.value'
+timestamp_mapping: 'root = this.ts'
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newRemoteWriteOutputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(t.Context()))
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"name":"requests_total","labels":{"status":200,"method":"GET"},"value":5,"ts":1700000002}`)),
+		service.NewMessage([]byte(`{"name":"up","labels":{"job":"api","zone":""},"value":true,"ts":"2023-11-14T22:13:20Z"}`)),
+		service.NewMessage([]byte(`{"name":"requests_total","labels":{"method":"GET","status":200},"value":3,"ts":1700000001}`)),
+		service.NewMessage([]byte(`{"name":"requests_total","labels":{"method":"POST","status":201},"value":"1.5","ts":1700000001}`)),
+	}))
+	assert.Equal(t, []string{
+		"{__name__=requests_total,method=GET,status=200} 3@1700000001000 5@1700000002000",
+		"{__name__=up,job=api} 1@1700000000000",
+		"{__name__=requests_total,method=POST,status=201} 1.5@1700000001000",
+	}, series)
+	require.NoError(t, o.Close(t.Context()))
+}
+
+func TestRemoteWriteOutputDefaultTimestamp(t *testing.T) {
+	var series []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		b, err = s2.Decode(nil, b)
+		require.NoError(t, err)
+		series = decodeWriteRequest(t, b)
+	}))
+	defer srv.Close()
+
+	conf, err := remoteWriteOutputSpec().ParseYAML(`
+url: `+srv.URL+`
+metric_name: temperature
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newRemoteWriteOutputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+	o.nowFn = func() time.Time { return time.UnixMilli(1234) }
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte(`{"value":21.5}`)),
+	}))
+	assert.Equal(t, []string{"{__name__=temperature} 21.5@1234"}, series)
+}
+
+func TestRemoteWriteOutputErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("out of order sample\n"))
+	}))
+	defer srv.Close()
+
+	for _, test := range []struct {
+		name string
+		conf string
+		msg  string
+		err  string
+	}{
+		{
+			name: "server error",
+			conf: `metric_name: up`,
+			msg:  `{"value":1}`,
+			err:  "remote write failed with status 400: out of order sample",
+		},
+		{
+			name: "invalid metric name",
+			conf: `metric_name: ${! this.name }`,
+			msg:  `{"name":"http-requests","value":1}`,
+			err:  `invalid metric name: "http-requests"`,
+		},
+		{
+			name: "invalid label name",
+			conf: "metric_name: up\nlabels_mapping: 'root = { \"1st\": \"a\" }'",
+			msg:  `{"value":1}`,
+			err:  `invalid label name: "1st"`,
+		},
+		{
+			name: "non numeric value",
+			conf: `metric_name: up`,
+			msg:  `{"value":"nope"}`,
+			err:  "value mapping failed",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := remoteWriteOutputSpec().ParseYAML("url: "+srv.URL+"\n"+test.conf, nil)
+			require.NoError(t, err)
+
+			o, err := newRemoteWriteOutputFromParsed(conf, service.MockResources())
+			require.NoError(t, err)
+
+			err = o.WriteBatch(t.Context(), service.MessageBatch{
+				service.NewMessage([]byte(test.msg)),
+			})
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
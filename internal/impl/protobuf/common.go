@@ -15,11 +15,25 @@
 package protobuf
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/dynamicpb"
 
+	// Register the well-known types with the global registry.
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+
 	//nolint:staticcheck // Ignore SA1019 "github.com/jhump/protoreflect/desc/protoparse" is deprecated warning
 	"github.com/jhump/protoreflect/desc/protoparse"
 )
@@ -47,16 +61,98 @@ func RegistriesFromMap(filesMap map[string]string) (*protoregistry.Files, *proto
 		if err := files.RegisterFile(v.UnwrapFile()); err != nil {
 			return nil, nil, fmt.Errorf("failed to register file '%v': %w", v.GetName(), err)
 		}
-		for _, t := range v.GetMessageTypes() {
-			if err := types.RegisterMessage(dynamicpb.NewMessageType(t.UnwrapMessage())); err != nil {
-				return nil, nil, fmt.Errorf("failed to register type '%v': %w", t.GetName(), err)
+		if err := registerMessageTypes(types, v.UnwrapFile().Messages()); err != nil {
+			return nil, nil, err
+		}
+	}
+	return files, types, nil
+}
+
+// registerMessageTypes registers message types along with all of their nested
+// message types, at any depth.
+func registerMessageTypes(types *protoregistry.Types, msgs protoreflect.MessageDescriptors) error {
+	for i := range msgs.Len() {
+		md := msgs.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+			return fmt.Errorf("failed to register type '%v': %w", md.FullName(), err)
+		}
+		if err := registerMessageTypes(types, md.Messages()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolver resolves the message and extension types referenced by messages,
+// such as the contents of google.protobuf.Any fields.
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+type wellKnownTypesResolver struct {
+	types *protoregistry.Types
+}
+
+// ResolverWithWellKnownTypes returns a resolver of the types within a registry
+// that falls back to the well-known types, such as google.protobuf.Timestamp,
+// which are not part of registries parsed from .proto files that import them.
+func ResolverWithWellKnownTypes(types *protoregistry.Types) Resolver {
+	return wellKnownTypesResolver{types: types}
+}
+
+func (r wellKnownTypesResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	mt, err := r.types.FindMessageByName(name)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalTypes.FindMessageByName(name)
+	}
+	return mt, err
+}
+
+func (r wellKnownTypesResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := r.types.FindMessageByURL(url)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalTypes.FindMessageByURL(url)
+	}
+	return mt, err
+}
+
+func (r wellKnownTypesResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return r.types.FindExtensionByName(field)
+}
+
+func (r wellKnownTypesResolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return r.types.FindExtensionByNumber(message, field)
+}
+
+// RegistriesFromImportPaths walks a list of directories and parses all .proto
+// files found within them into a registry of protobuf files and protobuf
+// types, see RegistriesFromMap.
+func RegistriesFromImportPaths(f fs.FS, importPaths []string) (*protoregistry.Files, *protoregistry.Types, error) {
+	files := map[string]string{}
+	for _, importPath := range importPaths {
+		if err := fs.WalkDir(f, importPath, func(path string, info fs.DirEntry, ferr error) error {
+			if ferr != nil || info.IsDir() {
+				return ferr
 			}
-			for _, nt := range t.GetNestedMessageTypes() {
-				if err := types.RegisterMessage(dynamicpb.NewMessageType(nt.UnwrapMessage())); err != nil {
-					return nil, nil, fmt.Errorf("failed to register type '%v': %w", nt.GetFullyQualifiedName(), err)
+			if filepath.Ext(info.Name()) == ".proto" {
+				rPath, ferr := filepath.Rel(importPath, path)
+				if ferr != nil {
+					return fmt.Errorf("failed to get relative path: %v", ferr)
+				}
+				content, ferr := os.ReadFile(path)
+				if ferr != nil {
+					return fmt.Errorf("failed to read import %v: %v", path, ferr)
 				}
+				files[rPath] = string(content)
 			}
+			return nil
+		}); err != nil {
+			return nil, nil, err
 		}
 	}
-	return files, types, nil
+	return RegistriesFromMap(files)
 }
@@ -18,9 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"sync"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 
@@ -60,6 +58,10 @@ Converts protobuf messages into a generic JSON structure. This makes it easier t
 === `+"`from_json`"+`
 
 Attempts to create a target protobuf message from a generic JSON structure.
+
+== Well-known types
+
+The well-known types of the `+"`google.protobuf`"+` package, such as `+"`Timestamp`"+`, `+"`Duration`"+`, `+"`Struct`"+` and the wrapper types, are converted using their special JSON representations. Fields of the type `+"`google.protobuf.Any`"+` are resolved against both the well-known types and all messages found within the schema, including messages nested within other messages.
 `).Fields(
 		service.NewStringEnumField(fieldOperator, "to_json", "from_json").
 			Description("The <<operators, operator>> to execute"),
@@ -77,6 +79,7 @@ Attempts to create a target protobuf message from a generic JSON structure.
 		service.NewBoolField(fieldUseEnumNumbers).
 			Description("If `true`, the `to_json` operator deserializes enums as numerical values instead of string names.").
 			Default(false),
+		schemaRegistryField(),
 	).Example(
 		"JSON to Protobuf", `
 If we have the following protobuf definition within a directory called `+"`testing/schema`"+`:
@@ -167,19 +170,10 @@ func init() {
 
 type protobufOperator func(part *service.Message) error
 
-func newProtobufToJSONOperator(f fs.FS, msg string, importPaths []string, useProtoNames, useEnumNumbers bool) (protobufOperator, error) {
-	if msg == "" {
-		return nil, errors.New("message field must not be empty")
-	}
-
-	descriptors, types, err := loadDescriptors(f, importPaths)
-	if err != nil {
-		return nil, err
-	}
-
+func newProtobufToJSONOperator(descriptors *protoregistry.Files, types *protoregistry.Types, source, msg string, useProtoNames, useEnumNumbers bool) (protobufOperator, error) {
 	d, err := descriptors.FindDescriptorByName(protoreflect.FullName(msg))
 	if err != nil {
-		return nil, fmt.Errorf("unable to find message '%v' definition within '%v'", msg, importPaths)
+		return nil, fmt.Errorf("unable to find message '%v' definition within %v", msg, source)
 	}
 
 	md, ok := d.(protoreflect.MessageDescriptor)
@@ -199,7 +193,7 @@ func newProtobufToJSONOperator(f fs.FS, msg string, importPaths []string, usePro
 		}
 
 		opts := protojson.MarshalOptions{
-			Resolver:       types,
+			Resolver:       ResolverWithWellKnownTypes(types),
 			UseProtoNames:  useProtoNames,
 			UseEnumNumbers: useEnumNumbers,
 		}
@@ -213,23 +207,10 @@ func newProtobufToJSONOperator(f fs.FS, msg string, importPaths []string, usePro
 	}, nil
 }
 
-func newProtobufFromJSONOperator(f fs.FS, msg string, importPaths []string, discardUnknown bool) (protobufOperator, error) {
-	if msg == "" {
-		return nil, errors.New("message field must not be empty")
-	}
-
-	_, types, err := loadDescriptors(f, importPaths)
-	if err != nil {
-		return nil, err
-	}
-
-	types.RangeMessages(func(protoreflect.MessageType) bool {
-		return true
-	})
-
+func newProtobufFromJSONOperator(types *protoregistry.Types, source, msg string, discardUnknown bool) (protobufOperator, error) {
 	md, err := types.FindMessageByName(protoreflect.FullName(msg))
 	if err != nil {
-		return nil, fmt.Errorf("unable to find message '%v' definition within '%v'", msg, importPaths)
+		return nil, fmt.Errorf("unable to find message '%v' definition within %v", msg, source)
 	}
 
 	return func(part *service.Message) error {
@@ -241,7 +222,7 @@ func newProtobufFromJSONOperator(f fs.FS, msg string, importPaths []string, disc
 		dynMsg := dynamicpb.NewMessage(md.Descriptor())
 
 		opts := protojson.UnmarshalOptions{
-			Resolver:       types,
+			Resolver:       ResolverWithWellKnownTypes(types),
 			DiscardUnknown: discardUnknown,
 		}
 		if err := opts.Unmarshal(msgBytes, dynMsg); err != nil {
@@ -258,47 +239,26 @@ func newProtobufFromJSONOperator(f fs.FS, msg string, importPaths []string, disc
 	}, nil
 }
 
-func strToProtobufOperator(f fs.FS, opStr, message string, importPaths []string, discardUnknown, useProtoNames, useEnumNumbers bool) (protobufOperator, error) {
+func strToProtobufOperator(descriptors *protoregistry.Files, types *protoregistry.Types, source, opStr, message string, discardUnknown, useProtoNames, useEnumNumbers bool) (protobufOperator, error) {
 	switch opStr {
 	case "to_json":
-		return newProtobufToJSONOperator(f, message, importPaths, useProtoNames, useEnumNumbers)
+		return newProtobufToJSONOperator(descriptors, types, source, message, useProtoNames, useEnumNumbers)
 	case "from_json":
-		return newProtobufFromJSONOperator(f, message, importPaths, discardUnknown)
+		return newProtobufFromJSONOperator(types, source, message, discardUnknown)
 	}
 	return nil, fmt.Errorf("operator not recognised: %v", opStr)
 }
 
-func loadDescriptors(f fs.FS, importPaths []string) (*protoregistry.Files, *protoregistry.Types, error) {
-	files := map[string]string{}
-	for _, importPath := range importPaths {
-		if err := fs.WalkDir(f, importPath, func(path string, info fs.DirEntry, ferr error) error {
-			if ferr != nil || info.IsDir() {
-				return ferr
-			}
-			if filepath.Ext(info.Name()) == ".proto" {
-				rPath, ferr := filepath.Rel(importPath, path)
-				if ferr != nil {
-					return fmt.Errorf("failed to get relative path: %v", ferr)
-				}
-				content, ferr := os.ReadFile(path)
-				if ferr != nil {
-					return fmt.Errorf("failed to read import %v: %v", path, ferr)
-				}
-				files[rPath] = string(content)
-			}
-			return nil
-		}); err != nil {
-			return nil, nil, err
-		}
-	}
-	return RegistriesFromMap(files)
-}
-
 //------------------------------------------------------------------------------
 
 type protobufProc struct {
+	log *service.Logger
+
+	// The operator is loaded lazily when its schema is fetched from a schema
+	// registry, in which case a failed load is retried with the next message.
+	opMut    sync.Mutex
 	operator protobufOperator
-	log      *service.Logger
+	loadOp   func(ctx context.Context) (protobufOperator, error)
 }
 
 func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufProc, error) {
@@ -315,6 +275,9 @@ func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufP
 	if message, err = conf.FieldString(fieldMessage); err != nil {
 		return nil, err
 	}
+	if message == "" {
+		return nil, errors.New("message field must not be empty")
+	}
 
 	var importPaths []string
 	if importPaths, err = conf.FieldStringList(fieldImportPaths); err != nil {
@@ -336,14 +299,58 @@ func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufP
 		return nil, err
 	}
 
-	if p.operator, err = strToProtobufOperator(mgr.FS(), operatorStr, message, importPaths, discardUnknown, useProtoNames, useEnumNumbers); err != nil {
+	if conf.Contains(fieldSchemaRegistry) {
+		if len(importPaths) > 0 {
+			return nil, fmt.Errorf("fields %v and %v cannot both be set", fieldImportPaths, fieldSchemaRegistry)
+		}
+		if operatorStr != "to_json" && operatorStr != "from_json" {
+			return nil, fmt.Errorf("operator not recognised: %v", operatorStr)
+		}
+		src, err := schemaRegistrySourceFromParsed(conf.Namespace(fieldSchemaRegistry), mgr)
+		if err != nil {
+			return nil, err
+		}
+		p.loadOp = func(ctx context.Context) (protobufOperator, error) {
+			descriptors, types, err := src.registries(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return strToProtobufOperator(descriptors, types, src.String(), operatorStr, message, discardUnknown, useProtoNames, useEnumNumbers)
+		}
+		return p, nil
+	}
+
+	descriptors, types, err := RegistriesFromImportPaths(mgr.FS(), importPaths)
+	if err != nil {
+		return nil, err
+	}
+	if p.operator, err = strToProtobufOperator(descriptors, types, fmt.Sprintf("'%v'", importPaths), operatorStr, message, discardUnknown, useProtoNames, useEnumNumbers); err != nil {
 		return nil, err
 	}
 	return p, nil
 }
 
-func (p *protobufProc) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
-	if err := p.operator(msg); err != nil {
+func (p *protobufProc) getOperator(ctx context.Context) (protobufOperator, error) {
+	p.opMut.Lock()
+	defer p.opMut.Unlock()
+
+	if p.operator == nil {
+		op, err := p.loadOp(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.operator = op
+	}
+	return p.operator, nil
+}
+
+func (p *protobufProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	operator, err := p.getOperator(ctx)
+	if err != nil {
+		p.log.Errorf("Failed to load protobuf schema: %v", err)
+		return nil, err
+	}
+	if err := operator(msg); err != nil {
 		p.log.Debugf("Operator failed: %v", err)
 		return nil, err
 	}
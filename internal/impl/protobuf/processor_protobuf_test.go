@@ -15,8 +15,12 @@
 package protobuf
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -284,3 +288,123 @@ import_paths: [ %v ]
 		})
 	}
 }
+
+func TestProtobufWellKnownTypesRoundTrip(t *testing.T) {
+	for _, input := range []string{
+		`{"id":1,"content":{"@type":"type.googleapis.com/google.protobuf.Timestamp","value":"2024-01-02T03:04:05Z"}}`,
+		`{"id":2,"content":{"@type":"type.googleapis.com/google.protobuf.Duration","value":"1.500s"}}`,
+		`{"id":3,"content":{"@type":"type.googleapis.com/google.protobuf.StringValue","value":"foo"}}`,
+		`{"id":4,"content":{"@type":"type.googleapis.com/google.protobuf.Int64Value","value":"42"}}`,
+		`{"id":5,"content":{"@type":"type.googleapis.com/testing.House.Mailbox","color":"red"}}`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			fromConf, err := protobufProcessorSpec().ParseYAML(`
+operator: from_json
+message: testing.Envelope
+import_paths: [ ../../../config/test/protobuf/schema ]
+`, nil)
+			require.NoError(t, err)
+
+			fromProc, err := newProtobuf(fromConf, service.MockResources())
+			require.NoError(t, err)
+
+			toConf, err := protobufProcessorSpec().ParseYAML(`
+operator: to_json
+message: testing.Envelope
+import_paths: [ ../../../config/test/protobuf/schema ]
+`, nil)
+			require.NoError(t, err)
+
+			toProc, err := newProtobuf(toConf, service.MockResources())
+			require.NoError(t, err)
+
+			msgs, err := fromProc.Process(t.Context(), service.NewMessage([]byte(input)))
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+
+			msgs, err = toProc.Process(t.Context(), msgs[0])
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+
+			mBytes, err := msgs[0].AsBytes()
+			require.NoError(t, err)
+			assert.JSONEq(t, input, string(mBytes))
+		})
+	}
+}
+
+func TestProtobufSchemaRegistry(t *testing.T) {
+	personSchema, err := json.Marshal(`syntax = "proto3";
+package testing;
+
+import "google/protobuf/timestamp.proto";
+
+message Person {
+  string first_name = 1;
+  google.protobuf.Timestamp last_updated = 2;
+}`)
+	require.NoError(t, err)
+
+	envelopeSchema, err := json.Marshal(`syntax = "proto3";
+package testing;
+
+import "google/protobuf/any.proto";
+import "person.proto";
+
+message Envelope {
+  testing.Person sender = 1;
+  google.protobuf.Any content = 2;
+}`)
+	require.NoError(t, err)
+
+	var reqs atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs.Add(1)
+		switch r.URL.Path {
+		case "/subjects/envelope-value/versions/latest":
+			_, _ = fmt.Fprintf(w, `{"subject":"envelope-value","version":2,"id":2,"schemaType":"PROTOBUF","schema":%s,"references":[{"name":"person.proto","subject":"person-value","version":1}]}`, envelopeSchema)
+		case "/subjects/person-value/versions/1":
+			_, _ = fmt.Fprintf(w, `{"subject":"person-value","version":1,"id":1,"schemaType":"PROTOBUF","schema":%s}`, personSchema)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	conf, err := protobufProcessorSpec().ParseYAML(fmt.Sprintf(`
+operator: from_json
+message: testing.Envelope
+schema_registry:
+  url: %v
+  subject: envelope-value
+`, ts.URL), nil)
+	require.NoError(t, err)
+
+	proc, err := newProtobuf(conf, service.MockResources())
+	require.NoError(t, err)
+	assert.Zero(t, reqs.Load())
+
+	for range 2 {
+		msgs, err := proc.Process(t.Context(), service.NewMessage([]byte(
+			`{"sender":{"firstName":"bob","lastUpdated":"2024-01-02T03:04:05Z"},"content":{"@type":"type.googleapis.com/testing.Person","firstName":"alice"}}`,
+		)))
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+	}
+	assert.Equal(t, int32(2), reqs.Load())
+
+	conf, err = protobufProcessorSpec().ParseYAML(fmt.Sprintf(`
+operator: from_json
+message: testing.Nope
+schema_registry:
+  url: %v
+  subject: envelope-value
+`, ts.URL), nil)
+	require.NoError(t, err)
+
+	proc, err = newProtobuf(conf, service.MockResources())
+	require.NoError(t, err)
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`{}`)))
+	require.ErrorContains(t, err, "unable to find message 'testing.Nope' definition within schema registry subject 'envelope-value'")
+}
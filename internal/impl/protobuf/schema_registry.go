@@ -0,0 +1,124 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"context"
+	"fmt"
+
+	franz_sr "github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/confluent/sr"
+)
+
+const (
+	fieldSchemaRegistry = "schema_registry"
+	fieldSRURL          = "url"
+	fieldSRSubject      = "subject"
+	fieldSRVersion      = "version"
+	fieldSRTLS          = "tls"
+)
+
+func schemaRegistryField() *service.ConfigField {
+	fields := []*service.ConfigField{
+		service.NewURLField(fieldSRURL).
+			Description("The base URL of the schema registry service."),
+		service.NewStringField(fieldSRSubject).
+			Description("The subject of the protobuf schema containing the message definition.").
+			Example("people-value"),
+		service.NewIntField(fieldSRVersion).
+			Description("The version of the schema to use, the latest version is used by default.").
+			Optional(),
+	}
+	fields = append(fields, service.NewHTTPRequestAuthSignerFields()...)
+	fields = append(fields, service.NewTLSField(fieldSRTLS))
+
+	return service.NewObjectField(fieldSchemaRegistry, fields...).
+		Description("Obtain the message definitions from a protobuf schema registered under a subject of a schema registry, along with all of the schemas it references, instead of from `" + fieldImportPaths + "`. The schema is fetched when the first message is processed.").
+		Version("4.62.0").
+		Optional()
+}
+
+type schemaRegistrySource struct {
+	client  *sr.Client
+	subject string
+	version *int
+}
+
+func schemaRegistrySourceFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*schemaRegistrySource, error) {
+	urlStr, err := conf.FieldString(fieldSRURL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, err := conf.FieldTLS(fieldSRTLS)
+	if err != nil {
+		return nil, err
+	}
+	authSigner, err := conf.HTTPRequestAuthSignerFromParsed()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schemaRegistrySource{}
+	if s.subject, err = conf.FieldString(fieldSRSubject); err != nil {
+		return nil, err
+	}
+	if conf.Contains(fieldSRVersion) {
+		version, err := conf.FieldInt(fieldSRVersion)
+		if err != nil {
+			return nil, err
+		}
+		s.version = &version
+	}
+	if s.client, err = sr.NewClient(urlStr, authSigner, tlsConf, mgr); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *schemaRegistrySource) String() string {
+	return fmt.Sprintf("schema registry subject '%v'", s.subject)
+}
+
+// registries fetches the schema of the subject along with its references and
+// parses them, see RegistriesFromMap.
+func (s *schemaRegistrySource) registries(ctx context.Context) (*protoregistry.Files, *protoregistry.Types, error) {
+	schema, err := s.client.GetSchemaBySubjectAndVersion(ctx, s.subject, s.version, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch schema of subject '%v': %w", s.subject, err)
+	}
+	if schema.Type != franz_sr.TypeProtobuf {
+		return nil, nil, fmt.Errorf("schema of subject '%v' is of type %v, expected %v", s.subject, schema.Type, franz_sr.TypeProtobuf)
+	}
+
+	regMap := map[string]string{
+		".": schema.Schema.Schema,
+	}
+	if err := s.client.WalkReferences(ctx, schema.References, func(_ context.Context, name string, si franz_sr.Schema) error {
+		regMap[name] = si.Schema
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	files, types, err := RegistriesFromMap(regMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse proto schema: %w", err)
+	}
+	return files, types, nil
+}
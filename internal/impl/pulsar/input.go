@@ -16,6 +16,7 @@ package pulsar
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"strconv"
@@ -60,11 +61,20 @@ This input adds the following metadata fields to each message:
 - pulsar_topic
 - pulsar_producer_name
 - pulsar_redelivery_count
+- pulsar_schema_version
 - All properties of the message
 ` + "```" + `
 
 You can access these metadata fields using
 xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+== Dead letter topics
+
+Messages that are rejected downstream are negatively acknowledged and redelivered after the ` + "`nack_redelivery_delay`" + `. When a ` + "`dead_letter_policy`" + ` is configured, messages that have been delivered ` + "`max_deliveries`" + ` times are instead published to a dead letter topic and acknowledged. Dead letter policies are only supported with ` + "`shared`" + ` and ` + "`key_shared`" + ` subscriptions.
+
+== Schemas
+
+When a ` + "`schema`" + ` is configured the consumer is registered with it, which the broker checks for compatibility with the schema of the topic. Messages are then decoded into structured data using the schema version they were produced with, which is obtained from the schema registry of the broker.
 `).
 		Field(service.NewURLField("url").
 			Description("A URL to connect to.").
@@ -85,6 +95,21 @@ xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
 		Field(service.NewStringEnumField("subscription_initial_position", "latest", "earliest").
 			Description("Specify the subscription initial position for this consumer.").
 			Default(defaultSubscriptionInitialPosition)).
+		Field(service.NewDurationField("nack_redelivery_delay").
+			Description("The delay after which negatively acknowledged messages are redelivered.").
+			Default("1m").
+			Version("4.62.0").
+			Advanced()).
+		Field(service.NewObjectField("dead_letter_policy",
+			service.NewIntField("max_deliveries").
+				Description("The maximum number of times a message is delivered before it is sent to the dead letter topic."),
+			service.NewStringField("dead_letter_topic").
+				Description("The topic to send messages to once they exceed the maximum deliveries. Defaults to `<topic>-<subscription_name>-DLQ` when empty.").
+				Default("")).
+			Description("Optionally send messages that repeatedly fail to be processed to a dead letter topic.").
+			Version("4.62.0").
+			Optional()).
+		Field(schemaField("An optional schema to consume messages with, decoding them into structured data.")).
 		Field(service.NewObjectField("tls",
 			service.NewStringField("root_cas_file").
 				Description("An optional path of a root certificate authority file to use. This is a file, often with a .pem extension, containing a certificate chain from the parent trusted root certificate, to possible intermediate signing certificates, to the host certificate.").
@@ -111,6 +136,9 @@ type pulsarReader struct {
 	subType       string
 	subInitial    string
 	rootCasFile   string
+	nackDelay     time.Duration
+	dlqPolicy     *pulsar.DLQPolicy
+	schema        *schemaConfig
 }
 
 func newPulsarReaderFromParsed(conf *service.ParsedConfig, log *service.Logger) (p *pulsarReader, err error) {
@@ -142,6 +170,26 @@ func newPulsarReaderFromParsed(conf *service.ParsedConfig, log *service.Logger)
 	if p.rootCasFile, err = conf.FieldString("tls", "root_cas_file"); err != nil {
 		return
 	}
+	if p.nackDelay, err = conf.FieldDuration("nack_redelivery_delay"); err != nil {
+		return
+	}
+	if conf.Contains("dead_letter_policy") {
+		var maxDeliveries int
+		if maxDeliveries, err = conf.FieldInt("dead_letter_policy", "max_deliveries"); err != nil {
+			return
+		}
+		if maxDeliveries <= 0 {
+			err = errors.New("field dead_letter_policy.max_deliveries must be greater than zero")
+			return
+		}
+		p.dlqPolicy = &pulsar.DLQPolicy{MaxDeliveries: uint32(maxDeliveries)}
+		if p.dlqPolicy.DeadLetterTopic, err = conf.FieldString("dead_letter_policy", "dead_letter_topic"); err != nil {
+			return
+		}
+	}
+	if p.schema, err = schemaFromParsed(conf); err != nil {
+		return
+	}
 
 	if p.url == "" {
 		err = errors.New("field url must not be empty")
@@ -170,6 +218,16 @@ func newPulsarReaderFromParsed(conf *service.ParsedConfig, log *service.Logger)
 		err = fmt.Errorf("field subscription_initial_position is invalid: %v", err)
 		return
 	}
+	if p.dlqPolicy != nil && p.subType != "shared" && p.subType != "key_shared" {
+		err = errors.New("field dead_letter_policy is only supported with shared and key_shared subscription types")
+		return
+	}
+	if p.schema != nil {
+		if _, err = p.schema.Schema(); err != nil {
+			err = fmt.Errorf("field schema is invalid: %v", err)
+			return
+		}
+	}
 	if err = p.authConf.Validate(); err != nil {
 		err = fmt.Errorf("field auth is invalid: %v", err)
 	}
@@ -253,6 +311,14 @@ func (p *pulsarReader) Connect(context.Context) error {
 		KeySharedPolicy: &pulsar.KeySharedPolicy{
 			AllowOutOfOrderDelivery: true,
 		},
+		NackRedeliveryDelay: p.nackDelay,
+		DLQ:                 p.dlqPolicy,
+	}
+	if p.schema != nil {
+		if options.Schema, err = p.schema.Schema(); err != nil {
+			client.Close()
+			return err
+		}
 	}
 	if consumer, err = client.Subscribe(options); err != nil {
 		client.Close()
@@ -304,6 +370,14 @@ func (p *pulsarReader) Read(ctx context.Context) (*service.Message, service.AckF
 	}
 
 	msg := service.NewMessage(pulMsg.Payload())
+	if p.schema != nil {
+		var v any
+		if err := pulMsg.GetSchemaValue(&v); err != nil {
+			msg.SetError(fmt.Errorf("failed to decode message with schema: %w", err))
+		} else {
+			msg.SetStructuredMut(v)
+		}
+	}
 
 	msg.MetaSet("pulsar_message_id", string(pulMsg.ID().Serialize()))
 	msg.MetaSet("pulsar_topic", pulMsg.Topic())
@@ -321,6 +395,9 @@ func (p *pulsarReader) Read(ctx context.Context) (*service.Message, service.AckF
 	if producerName := pulMsg.ProducerName(); producerName != "" {
 		msg.MetaSet("pulsar_producer_name", producerName)
 	}
+	if version := pulMsg.SchemaVersion(); len(version) == 8 {
+		msg.MetaSet("pulsar_schema_version", strconv.FormatUint(binary.BigEndian.Uint64(version), 10))
+	}
 	for k, v := range pulMsg.Properties() {
 		msg.MetaSet(k, v)
 	}
@@ -72,3 +72,73 @@ subscription_name: "sub"
 		})
 	}
 }
+
+func TestParseInputDeadLetterAndSchema(t *testing.T) {
+	tests := []struct {
+		name, config string
+		errStr       string
+	}{
+		{
+			name: "dead letter policy",
+			config: `
+dead_letter_policy:
+  max_deliveries: 3
+  dead_letter_topic: my_dlq
+`,
+		},
+		{
+			name: "dead letter policy with zero deliveries fails",
+			config: `
+dead_letter_policy:
+  max_deliveries: 0
+`,
+			errStr: "field dead_letter_policy.max_deliveries must be greater than zero",
+		},
+		{
+			name: "dead letter policy with exclusive subscription fails",
+			config: `
+subscription_type: exclusive
+dead_letter_policy:
+  max_deliveries: 3
+`,
+			errStr: "field dead_letter_policy is only supported with shared and key_shared subscription types",
+		},
+		{
+			name: "avro schema",
+			config: `
+schema:
+  type: avro
+  definition: '{"type":"record","name":"Example","fields":[{"name":"id","type":"long"}]}'
+`,
+		},
+		{
+			name: "invalid avro schema fails",
+			config: `
+schema:
+  type: avro
+  definition: '{"type":"nope"}'
+`,
+			errStr: "field schema is invalid",
+		},
+	}
+
+	baseConfig := `
+url: pulsar://localhost:6650/
+subscription_name: "sub"
+topics: ["my_cool_topic"]
+`
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := inputConfigSpec().ParseYAML(baseConfig+test.config, service.NewEnvironment())
+			require.NoError(t, err, "parse config")
+
+			reader, err := newPulsarReaderFromParsed(parsed, service.MockResources().Logger())
+			if test.errStr != "" {
+				require.ErrorContains(t, err, test.errStr)
+			} else {
+				require.NoError(t, err, "new reader from parsed")
+				require.NoError(t, reader.Close(t.Context()))
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ package pulsar
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -46,6 +48,15 @@ func outputConfigSpec() *service.ConfigSpec {
 		Version("3.43.0").
 		Categories("Services").
 		Summary("Write messages to an Apache Pulsar server.").
+		Description(`
+== Batching
+
+Messages are batched by the Pulsar producer before being sent to the broker, which can be tuned with the ` + "`producer_batching`" + ` fields. Since each message is only acknowledged once its batch has been persisted it is recommended to increase ` + "`max_in_flight`" + ` alongside the batch size.
+
+== Schemas
+
+When a ` + "`schema`" + ` is configured the producer is registered with it, and the broker rejects the producer when the schema is incompatible with the schema of the topic. Messages must already be encoded in the format of the schema, for example by using the ` + "`avro`" + ` processor for Avro schemas or by writing JSON documents for JSON schemas.
+`).
 		Field(service.NewURLField("url").
 			Description("A URL to connect to.").
 			Example("pulsar://localhost:6650").
@@ -68,6 +79,23 @@ func outputConfigSpec() *service.ConfigSpec {
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of messages to have in flight at a given time. Increase this to improve throughput.").
 			Default(64)).
+		Field(service.NewObjectField("producer_batching",
+			service.NewBoolField("enabled").
+				Description("Whether messages are batched by the producer.").
+				Default(true),
+			service.NewIntField("max_messages").
+				Description("The maximum number of messages in a batch.").
+				Default(1000),
+			service.NewIntField("max_size").
+				Description("The maximum size of a batch in bytes.").
+				Default(131072),
+			service.NewDurationField("max_publish_delay").
+				Description("The maximum period of time that messages are batched for before being sent.").
+				Default("10ms")).
+			Description("Configures the batching of messages by the Pulsar producer.").
+			Version("4.62.0").
+			Advanced()).
+		Field(schemaField("An optional schema to register the producer with.")).
 		Field(authField())
 }
 
@@ -86,6 +114,15 @@ type pulsarWriter struct {
 	rootCasFile string
 	key         *service.InterpolatedString
 	orderingKey *service.InterpolatedString
+	batching    producerBatchingConfig
+	schema      *schemaConfig
+}
+
+type producerBatchingConfig struct {
+	Enabled         bool
+	MaxMessages     int
+	MaxSize         int
+	MaxPublishDelay time.Duration
 }
 
 func newPulsarWriterFromParsed(conf *service.ParsedConfig, log *service.Logger) (p *pulsarWriter, err error) {
@@ -112,6 +149,31 @@ func newPulsarWriterFromParsed(conf *service.ParsedConfig, log *service.Logger)
 	if p.orderingKey, err = conf.FieldInterpolatedString("ordering_key"); err != nil {
 		return
 	}
+	if p.batching.Enabled, err = conf.FieldBool("producer_batching", "enabled"); err != nil {
+		return
+	}
+	if p.batching.MaxMessages, err = conf.FieldInt("producer_batching", "max_messages"); err != nil {
+		return
+	}
+	if p.batching.MaxSize, err = conf.FieldInt("producer_batching", "max_size"); err != nil {
+		return
+	}
+	if p.batching.MaxPublishDelay, err = conf.FieldDuration("producer_batching", "max_publish_delay"); err != nil {
+		return
+	}
+	if p.batching.MaxMessages < 0 || p.batching.MaxSize < 0 {
+		err = errors.New("field producer_batching limits must not be negative")
+		return
+	}
+	if p.schema, err = schemaFromParsed(conf); err != nil {
+		return
+	}
+	if p.schema != nil {
+		if _, err = p.schema.Schema(); err != nil {
+			err = fmt.Errorf("field schema is invalid: %v", err)
+			return
+		}
+	}
 	return
 }
 
@@ -148,9 +210,21 @@ func (p *pulsarWriter) Connect(context.Context) error {
 		return err
 	}
 
-	if producer, err = client.CreateProducer(pulsar.ProducerOptions{
-		Topic: p.topic,
-	}); err != nil {
+	producerOpts := pulsar.ProducerOptions{
+		Topic:                   p.topic,
+		DisableBatching:         !p.batching.Enabled,
+		BatchingMaxMessages:     uint(p.batching.MaxMessages),
+		BatchingMaxSize:         uint(p.batching.MaxSize),
+		BatchingMaxPublishDelay: p.batching.MaxPublishDelay,
+	}
+	if p.schema != nil {
+		if producerOpts.Schema, err = p.schema.Schema(); err != nil {
+			client.Close()
+			return err
+		}
+	}
+
+	if producer, err = client.CreateProducer(producerOpts); err != nil {
 		client.Close()
 		return err
 	}
@@ -0,0 +1,75 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func schemaField(description string) *service.ConfigField {
+	return service.NewObjectField("schema",
+		service.NewStringAnnotatedEnumField("type", map[string]string{
+			"avro": "Messages are encoded with an Avro schema.",
+			"json": "Messages are JSON documents described by an Avro schema.",
+		}).
+			Description("The type of the schema."),
+		service.NewStringField("definition").
+			Description("The Avro schema definition, in JSON format.").
+			Example(`{"type":"record","name":"Example","fields":[{"name":"id","type":"long"}]}`),
+	).Description(description).
+		Version("4.62.0").
+		Advanced().
+		Optional()
+}
+
+type schemaConfig struct {
+	Type       string
+	Definition string
+}
+
+func schemaFromParsed(p *service.ParsedConfig) (c *schemaConfig, err error) {
+	if !p.Contains("schema") {
+		return
+	}
+	p = p.Namespace("schema")
+
+	c = &schemaConfig{}
+	if c.Type, err = p.FieldString("type"); err != nil {
+		return
+	}
+	if c.Definition, err = p.FieldString("definition"); err != nil {
+		return
+	}
+	if c.Definition == "" {
+		err = errors.New("schema definition is empty")
+	}
+	return
+}
+
+// Schema creates a Pulsar schema from the config.
+func (c *schemaConfig) Schema() (pulsar.Schema, error) {
+	switch c.Type {
+	case "avro":
+		return pulsar.NewAvroSchemaWithValidation(c.Definition, nil)
+	case "json":
+		return pulsar.NewJSONSchemaWithValidation(c.Definition, nil)
+	}
+	return nil, fmt.Errorf("unsupported schema type: %s", c.Type)
+}
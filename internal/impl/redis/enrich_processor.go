@@ -0,0 +1,364 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	epFieldKeyMapping  = "key_mapping"
+	epFieldCommand     = "command"
+	epFieldTargetPath  = "target_path"
+	epFieldParseJSON   = "parse_json"
+	epFieldMissPolicy  = "miss_policy"
+	epFieldDefault     = "default_value"
+	epFieldRetries     = "retries"
+	epFieldRetryPeriod = "retry_period"
+
+	epCommandGet     = "get"
+	epCommandHGetAll = "hgetall"
+
+	epMissSkip    = "skip"
+	epMissError   = "error"
+	epMissDefault = "default"
+)
+
+func redisEnrichProcConfig() *service.ConfigSpec {
+	spec := service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Integration").
+		Summary("Enriches messages with values obtained from Redis, performing the lookups of an entire batch in a single round trip.").
+		Description(`
+The key of each message is obtained with the ` + "`key_mapping`" + `, and the values of all keys of a batch are obtained with a single request, which is either an ` + "`MGET`" + ` command or a pipeline of ` + "`HGETALL`" + ` commands. Keys that appear within several messages of a batch are only looked up once. When connected to a cluster the ` + "`get`" + ` command is performed with a pipeline of ` + "`GET`" + ` commands instead, as the keys of an ` + "`MGET`" + ` command must belong to the same hash slot.
+
+The value of each key is set at the ` + "`target_path`" + ` of its message, leaving the rest of the message unchanged. This is much faster than obtaining values for each message with a ` + "xref:components:processors/cache.adoc[`cache` processor]" + ` within a ` + "xref:components:processors/branch.adoc[`branch` processor]" + `, especially for large batches.
+
+== Misses
+
+The ` + "`miss_policy`" + ` determines what happens to messages with a key that doesn't exist, or a hash without fields:
+
+- ` + "`skip`" + ` leaves the message unchanged.
+- ` + "`error`" + ` flags the message with an error, which can be handled with xref:configuration:error_handling.adoc[error handling patterns].
+- ` + "`default`" + ` sets the ` + "`default_value`" + ` at the ` + "`target_path`" + ` of the message.`)
+
+	for _, f := range clientFields() {
+		spec = spec.Field(f)
+	}
+
+	return spec.
+		Fields(
+			service.NewBloblangField(epFieldKeyMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] which should evaluate to the key to look up for each message.").
+				Examples(`root = "customer:" + this.customer_id`, `root = meta("kafka_key")`),
+			service.NewStringAnnotatedEnumField(epFieldCommand, map[string]string{
+				epCommandGet:     "Obtains the string values of keys.",
+				epCommandHGetAll: "Obtains all of the fields of hashes as objects.",
+			}).
+				Description("The command used to obtain the values of keys.").
+				Default(epCommandGet),
+			service.NewStringField(epFieldTargetPath).
+				Description("The dot separated path of the message to set values at. When empty the value replaces the entire message.").
+				Example("customer").
+				Default(""),
+			service.NewBoolField(epFieldParseJSON).
+				Description("Whether to parse the values obtained with the `get` command as JSON documents. Values that aren't valid JSON are set as strings.").
+				Default(true),
+			service.NewStringAnnotatedEnumField(epFieldMissPolicy, map[string]string{
+				epMissSkip:    "Leave messages with a missing key unchanged.",
+				epMissError:   "Flag messages with a missing key with an error.",
+				epMissDefault: "Set the `default_value` at the `target_path` of messages with a missing key.",
+			}).
+				Description("What to do with messages with a key that doesn't exist.").
+				Default(epMissSkip),
+			service.NewAnyField(epFieldDefault).
+				Description("The value to set for messages with a missing key when the `miss_policy` is `default`.").
+				Example(map[string]any{"tier": "free"}).
+				Optional(),
+			service.NewIntField(epFieldRetries).
+				Description("The maximum number of retries before abandoning a request.").
+				Default(3).
+				Advanced(),
+			service.NewDurationField(epFieldRetryPeriod).
+				Description("The time to wait before consecutive retry attempts.").
+				Default("500ms").
+				Advanced(),
+		).
+		LintRule(`root = if this.miss_policy.or("") == "default" && !this.exists("default_value") { [ "a default_value must be set when the miss_policy is default" ] }`).
+		Example("Enrich with customer profiles",
+			"Add the profile of the customer of each order, which is stored as a hash, and a default profile for unknown customers.",
+			`
+pipeline:
+  processors:
+    - redis_enrich:
+        url: redis://localhost:6379
+        key_mapping: 'root = "customer:" + this.customer_id'
+        command: hgetall
+        target_path: customer
+        miss_policy: default
+        default_value:
+          tier: free
+`)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor(
+		"redis_enrich", redisEnrichProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newRedisEnrichProcFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type redisEnrichProc struct {
+	log *service.Logger
+
+	keyMapping   *bloblang.Executor
+	command      string
+	targetPath   string
+	parseJSON    bool
+	missPolicy   string
+	defaultValue []byte
+
+	client      redis.UniversalClient
+	retries     int
+	retryPeriod time.Duration
+}
+
+func newRedisEnrichProcFromConfig(conf *service.ParsedConfig, res *service.Resources) (*redisEnrichProc, error) {
+	r := &redisEnrichProc{log: res.Logger()}
+
+	var err error
+	if r.keyMapping, err = conf.FieldBloblang(epFieldKeyMapping); err != nil {
+		return nil, err
+	}
+	if r.command, err = conf.FieldString(epFieldCommand); err != nil {
+		return nil, err
+	}
+	if r.targetPath, err = conf.FieldString(epFieldTargetPath); err != nil {
+		return nil, err
+	}
+	if r.parseJSON, err = conf.FieldBool(epFieldParseJSON); err != nil {
+		return nil, err
+	}
+	if r.missPolicy, err = conf.FieldString(epFieldMissPolicy); err != nil {
+		return nil, err
+	}
+	if conf.Contains(epFieldDefault) {
+		v, err := conf.FieldAny(epFieldDefault)
+		if err != nil {
+			return nil, err
+		}
+		// The default value is stored serialised so that each message obtains
+		// its own copy of it.
+		if r.defaultValue, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	} else if r.missPolicy == epMissDefault {
+		return nil, errors.New("a default_value must be set when the miss_policy is default")
+	}
+	if r.retries, err = conf.FieldInt(epFieldRetries); err != nil {
+		return nil, err
+	}
+	if r.retryPeriod, err = conf.FieldDuration(epFieldRetryPeriod); err != nil {
+		return nil, err
+	}
+
+	if r.client, err = getClient(conf); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fetch obtains the values of keys within a single round trip, where the
+// values of missing keys are nil.
+func (r *redisEnrichProc) fetch(ctx context.Context, keys []string) ([]any, error) {
+	if r.command == epCommandGet {
+		if _, isCluster := r.client.(*redis.ClusterClient); !isCluster {
+			return r.client.MGet(ctx, keys...).Result()
+		}
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]redis.Cmder, len(keys))
+	for i, k := range keys {
+		if r.command == epCommandHGetAll {
+			cmds[i] = pipe.HGetAll(ctx, k)
+		} else {
+			cmds[i] = pipe.Get(ctx, k)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	values := make([]any, len(keys))
+	for i, cmd := range cmds {
+		switch c := cmd.(type) {
+		case *redis.StringCmd:
+			if v, err := c.Result(); err == nil {
+				values[i] = v
+			}
+		case *redis.MapStringStringCmd:
+			if v := c.Val(); len(v) > 0 {
+				values[i] = v
+			}
+		}
+	}
+	return values, nil
+}
+
+func (r *redisEnrichProc) toValue(v any) any {
+	switch t := v.(type) {
+	case string:
+		if r.parseJSON {
+			var parsed any
+			if err := json.Unmarshal([]byte(t), &parsed); err == nil {
+				return parsed
+			}
+		}
+		return t
+	case map[string]string:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = e
+		}
+		return m
+	}
+	return v
+}
+
+func (r *redisEnrichProc) setValue(msg *service.Message, value any) error {
+	if r.targetPath == "" {
+		msg.SetStructuredMut(value)
+		return nil
+	}
+
+	structured, err := msg.AsStructuredMut()
+	if err != nil {
+		return err
+	}
+	gObj := gabs.Wrap(structured)
+	if _, err := gObj.SetP(value, r.targetPath); err != nil {
+		return err
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return nil
+}
+
+func queryKey(exec *service.MessageBatchBloblangExecutor, index int) (string, error) {
+	res, err := exec.Query(index)
+	if err != nil {
+		return "", err
+	}
+	if res == nil {
+		return "", errors.New("mapping did not return a key")
+	}
+	keyBytes, err := res.AsBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(keyBytes), nil
+}
+
+func (r *redisEnrichProc) ProcessBatch(ctx context.Context, inBatch service.MessageBatch) ([]service.MessageBatch, error) {
+	newBatch := inBatch.Copy()
+	keyExec := inBatch.BloblangExecutor(r.keyMapping)
+
+	msgKeys := make([]string, len(newBatch))
+	hasKey := make([]bool, len(newBatch))
+	keyIndexes := map[string]int{}
+	var keys []string
+	for i, msg := range newBatch {
+		key, err := queryKey(keyExec, i)
+		if err != nil {
+			r.log.Debugf("Key mapping failed: %v", err)
+			msg.SetError(fmt.Errorf("key mapping failed: %w", err))
+			continue
+		}
+		msgKeys[i], hasKey[i] = key, true
+		if _, exists := keyIndexes[key]; !exists {
+			keyIndexes[key] = len(keys)
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return []service.MessageBatch{newBatch}, nil
+	}
+
+	values, err := r.fetch(ctx, keys)
+	for i := 0; i < r.retries && err != nil; i++ {
+		r.log.Errorf("Enrichment lookup failed: %v", err)
+		select {
+		case <-time.After(r.retryPeriod):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		values, err = r.fetch(ctx, keys)
+	}
+	if err != nil {
+		r.log.Debugf("Enrichment lookup failed: %v", err)
+		for i, msg := range newBatch {
+			if hasKey[i] {
+				msg.SetError(fmt.Errorf("redis lookup failed: %w", err))
+			}
+		}
+		return []service.MessageBatch{newBatch}, nil
+	}
+
+	for i, msg := range newBatch {
+		if !hasKey[i] {
+			continue
+		}
+
+		value := values[keyIndexes[msgKeys[i]]]
+		if value == nil {
+			switch r.missPolicy {
+			case epMissSkip:
+				continue
+			case epMissError:
+				msg.SetError(fmt.Errorf("key %v was not found", msgKeys[i]))
+				continue
+			}
+			if err := json.Unmarshal(r.defaultValue, &value); err != nil {
+				msg.SetError(fmt.Errorf("failed to set default value: %w", err))
+				continue
+			}
+		} else {
+			value = r.toValue(value)
+		}
+
+		if err := r.setValue(msg, value); err != nil {
+			msg.SetError(fmt.Errorf("failed to set value: %w", err))
+		}
+	}
+	return []service.MessageBatch{newBatch}, nil
+}
+
+func (r *redisEnrichProc) Close(context.Context) error {
+	return r.client.Close()
+}
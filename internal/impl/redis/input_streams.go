@@ -38,13 +38,26 @@ const (
 	siFieldStartFromOldest = "start_from_oldest"
 	siFieldCommitPeriod    = "commit_period"
 	siFieldTimeout         = "timeout"
+	siFieldNoAck           = "no_ack"
+	siFieldAutoClaim       = "auto_claim"
+	siFieldACEnabled       = "enabled"
+	siFieldACMinIdle       = "min_idle"
+	siFieldACInterval      = "interval"
 )
 
 func redisStreamsInputConfig() *service.ConfigSpec {
 	return service.NewConfigSpec().
 		Stable().
 		Summary(`Pulls messages from Redis (v5.0+) streams with the XREADGROUP command. The `+"`client_id`"+` should be unique for each consumer of a group.`).
-		Description(`Redis stream entries are key/value pairs, as such it is necessary to specify the key that contains the body of the message. All other keys/value pairs are saved as metadata fields.`).
+		Description(`Redis stream entries are key/value pairs, as such it is necessary to specify the key that contains the body of the message. All other keys/value pairs are saved as metadata fields.
+
+== Recovering pending entries
+
+Entries read by a consumer of a group remain pending until they are acknowledged. When a consumer fails permanently its pending entries are never delivered again unless they are claimed by another consumer of the group. Enabling `+"`auto_claim`"+` periodically claims entries of the group that have been pending for longer than `+"`auto_claim.min_idle`"+` with the XAUTOCLAIM command (Redis v6.2+), and consumes them as regular messages.
+
+== Fire and forget
+
+When `+"`no_ack`"+` is set to `+"`true`"+` entries are read with the NOACK option, in which case they are considered acknowledged as soon as they are read. This reduces the load on Redis at the cost of losing entries that are read but not delivered when the process stops.`).
 		Categories("Services").
 		Fields(clientFields()...).
 		Fields(
@@ -79,6 +92,24 @@ func redisStreamsInputConfig() *service.ConfigSpec {
 				Description("The length of time to poll for new messages before reattempting.").
 				Advanced().
 				Default("1s"),
+			service.NewBoolField(siFieldNoAck).
+				Description("Read entries with the NOACK option, which removes the need to acknowledge them but means that entries that fail to be delivered are lost when the process stops.").
+				Version("4.62.0").
+				Advanced().
+				Default(false),
+			service.NewObjectField(siFieldAutoClaim,
+				service.NewBoolField(siFieldACEnabled).
+					Description("Whether to claim entries pending within other consumers of the group.").
+					Default(false),
+				service.NewDurationField(siFieldACMinIdle).
+					Description("The minimum period of time that an entry must have been pending for before it is claimed.").
+					Default("1m"),
+				service.NewDurationField(siFieldACInterval).
+					Description("The period of time between each attempt to claim pending entries.").
+					Default("30s"),
+			).
+				Description("Claim and consume entries that have been pending within consumers of the group for too long, which recovers the entries of failed consumers.").
+				Version("4.62.0"),
 		)
 }
 
@@ -117,6 +148,13 @@ type redisStreamsReader struct {
 	startFromOldest bool
 	commitPeriod    time.Duration
 	timeout         time.Duration
+	noAck           bool
+
+	autoClaim         bool
+	autoClaimMinIdle  time.Duration
+	autoClaimInterval time.Duration
+	nextAutoClaim     time.Time
+	claimCursors      map[string]string
 
 	backlogs map[string]string
 
@@ -179,6 +217,30 @@ func newRedisStreamsReader(conf *service.ParsedConfig, mgr *service.Resources) (
 	if r.timeout, err = conf.FieldDuration(siFieldTimeout); err != nil {
 		return
 	}
+	if r.noAck, err = conf.FieldBool(siFieldNoAck); err != nil {
+		return
+	}
+	if conf.Contains(siFieldAutoClaim) {
+		acConf := conf.Namespace(siFieldAutoClaim)
+		if r.autoClaim, err = acConf.FieldBool(siFieldACEnabled); err != nil {
+			return
+		}
+		if r.autoClaimMinIdle, err = acConf.FieldDuration(siFieldACMinIdle); err != nil {
+			return
+		}
+		if r.autoClaimInterval, err = acConf.FieldDuration(siFieldACInterval); err != nil {
+			return
+		}
+	}
+	if r.autoClaim && r.noAck {
+		err = errors.New("auto_claim cannot be enabled when no_ack is set as entries are never pending")
+		return
+	}
+
+	r.claimCursors = make(map[string]string, len(r.streams))
+	for _, str := range r.streams {
+		r.claimCursors[str] = "0-0"
+	}
 
 	r.ackSend = make(map[string][]string, len(r.streams))
 	r.backlogs = make(map[string]string, len(r.streams))
@@ -323,12 +385,20 @@ func (r *redisStreamsReader) read(ctx context.Context) (pendingRedisStreamMsg, e
 		}
 	}
 
+	if r.autoClaim && !time.Now().Before(r.nextAutoClaim) {
+		if pendingMsgs := r.claimPending(ctx, client); len(pendingMsgs) > 0 {
+			r.pendingMsgs = pendingMsgs[1:]
+			return pendingMsgs[0], nil
+		}
+	}
+
 	res, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
 		Block:    r.timeout,
 		Consumer: r.clientID,
 		Group:    r.consumerGroup,
 		Streams:  strs,
 		Count:    r.limit,
+		NoAck:    r.noAck,
 	}).Result()
 
 	if err != nil && err != redis.Nil {
@@ -355,49 +425,83 @@ func (r *redisStreamsReader) read(ctx context.Context) (pendingRedisStreamMsg, e
 				delete(r.backlogs, strRes.Stream)
 			}
 		}
-		for _, xmsg := range strRes.Messages {
-			body, exists := xmsg.Values[r.bodyKey]
-			if !exists {
-				continue
-			}
-			delete(xmsg.Values, r.bodyKey)
-
-			var bodyBytes []byte
-			switch t := body.(type) {
-			case string:
-				bodyBytes = []byte(t)
-			case []byte:
-				bodyBytes = t
-			}
-			if bodyBytes == nil {
-				continue
-			}
+		pendingMsgs = r.appendStreamMsgs(pendingMsgs, strRes.Stream, strRes.Messages)
+	}
 
-			part := service.NewMessage(bodyBytes)
-			part.MetaSetMut("redis_stream", xmsg.ID)
-			for k, v := range xmsg.Values {
-				part.MetaSetMut(k, v)
-			}
+	if len(pendingMsgs) == 0 {
+		r.pendingMsgs = nil
+		return msg, context.Canceled
+	}
+	r.pendingMsgs = pendingMsgs[1:]
+	return pendingMsgs[0], nil
+}
 
-			nextMsg := pendingRedisStreamMsg{
-				payload: service.MessageBatch{},
-				stream:  strRes.Stream,
-				id:      xmsg.ID,
-			}
-			nextMsg.payload = append(nextMsg.payload, part)
-			if msg.payload == nil {
-				msg = nextMsg
-			} else {
-				pendingMsgs = append(pendingMsgs, nextMsg)
-			}
+// claimPending claims entries of the group that have been pending for longer
+// than the minimum idle period with XAUTOCLAIM. Claiming resumes from the last
+// cursor of each stream until all pending entries have been scanned, after
+// which the next attempt is delayed until the next interval.
+func (r *redisStreamsReader) claimPending(ctx context.Context, client redis.UniversalClient) (pendingMsgs []pendingRedisStreamMsg) {
+	scanned := true
+	for _, str := range r.streams {
+		msgs, cursor, err := client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   str,
+			Group:    r.consumerGroup,
+			Consumer: r.clientID,
+			MinIdle:  r.autoClaimMinIdle,
+			Start:    r.claimCursors[str],
+			Count:    r.limit,
+		}).Result()
+		if err != nil {
+			r.log.Errorf("Failed to claim pending entries of stream %v: %v\n", str, err)
+			continue
+		}
+		if len(msgs) > 0 {
+			r.log.Debugf("Claimed %v pending entries of stream %v\n", len(msgs), str)
 		}
+		r.claimCursors[str] = cursor
+		if cursor != "0-0" {
+			scanned = false
+		}
+		pendingMsgs = r.appendStreamMsgs(pendingMsgs, str, msgs)
+	}
+	if scanned {
+		r.nextAutoClaim = time.Now().Add(r.autoClaimInterval)
 	}
+	return
+}
 
-	r.pendingMsgs = pendingMsgs
-	if msg.payload == nil {
-		return msg, context.Canceled
+func (r *redisStreamsReader) appendStreamMsgs(pendingMsgs []pendingRedisStreamMsg, stream string, xmsgs []redis.XMessage) []pendingRedisStreamMsg {
+	for _, xmsg := range xmsgs {
+		body, exists := xmsg.Values[r.bodyKey]
+		if !exists {
+			continue
+		}
+		delete(xmsg.Values, r.bodyKey)
+
+		var bodyBytes []byte
+		switch t := body.(type) {
+		case string:
+			bodyBytes = []byte(t)
+		case []byte:
+			bodyBytes = t
+		}
+		if bodyBytes == nil {
+			continue
+		}
+
+		part := service.NewMessage(bodyBytes)
+		part.MetaSetMut("redis_stream", xmsg.ID)
+		for k, v := range xmsg.Values {
+			part.MetaSetMut(k, v)
+		}
+
+		pendingMsgs = append(pendingMsgs, pendingRedisStreamMsg{
+			payload: service.MessageBatch{part},
+			stream:  stream,
+			id:      xmsg.ID,
+		})
 	}
-	return msg, nil
+	return pendingMsgs
 }
 
 func (r *redisStreamsReader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
@@ -420,7 +524,7 @@ func (r *redisStreamsReader) ReadBatch(ctx context.Context) (service.MessageBatc
 			r.pendingMsgsMut.Lock()
 			r.pendingMsgs = append(r.pendingMsgs, msg)
 			r.pendingMsgsMut.Unlock()
-		} else {
+		} else if !r.noAck {
 			r.addAsyncAcks(msg.stream, msg.id)
 		}
 		return nil
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
 	"github.com/redpanda-data/benthos/v4/public/service/integration"
 )
 
@@ -117,6 +118,74 @@ input:
 		})
 	})
 
+	t.Run("streams auto claim", func(t *testing.T) {
+		t.Parallel()
+
+		outConf, err := redisStreamsOutputConfig().ParseYAML(fmt.Sprintf(`
+url: tcp://localhost:%v
+stream: claim-stream
+consumer_groups: [ claim-group ]
+`, resource.GetPort("6379/tcp")), nil)
+		require.NoError(t, err)
+
+		out, err := newRedisStreamsWriter(outConf, service.MockResources())
+		require.NoError(t, err)
+		require.NoError(t, out.Connect(t.Context()))
+		t.Cleanup(func() {
+			_ = out.Close(context.Background())
+		})
+
+		require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+			service.NewMessage([]byte("foo")),
+			service.NewMessage([]byte("bar")),
+		}))
+
+		// Read the entries as a consumer that never acknowledges them.
+		res, err := client.XReadGroup(t.Context(), &redis.XReadGroupArgs{
+			Group:    "claim-group",
+			Consumer: "dead-consumer",
+			Streams:  []string{"claim-stream", ">"},
+		}).Result()
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Messages, 2)
+
+		inConf, err := redisStreamsInputConfig().ParseYAML(fmt.Sprintf(`
+url: tcp://localhost:%v
+streams: [ claim-stream ]
+client_id: live-consumer
+consumer_group: claim-group
+timeout: 100ms
+auto_claim:
+  enabled: true
+  min_idle: 100ms
+  interval: 100ms
+`, resource.GetPort("6379/tcp")), nil)
+		require.NoError(t, err)
+
+		in, err := newRedisStreamsReader(inConf, service.MockResources())
+		require.NoError(t, err)
+		require.NoError(t, in.Connect(t.Context()))
+		t.Cleanup(func() {
+			_ = in.Close(context.Background())
+		})
+
+		var bodies []string
+		assert.Eventually(t, func() bool {
+			batch, aFn, err := in.ReadBatch(t.Context())
+			if err != nil {
+				return false
+			}
+			for _, m := range batch {
+				b, _ := m.AsBytes()
+				bodies = append(bodies, string(b))
+			}
+			require.NoError(t, aFn(t.Context(), nil))
+			return len(bodies) == 2
+		}, time.Second*10, time.Millisecond*50)
+		assert.ElementsMatch(t, []string{"foo", "bar"}, bodies)
+	})
+
 	t.Run("pubsub", func(t *testing.T) {
 		t.Parallel()
 		template := `
@@ -17,6 +17,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/redis/go-redis/v9"
@@ -29,6 +30,7 @@ const (
 	soFieldBodyKey      = "body_key"
 	soFieldMaxLenApprox = "max_length"
 	soFieldMetadata     = "metadata"
+	soFieldGroups       = "consumer_groups"
 	soFieldBatching     = "batching"
 )
 
@@ -39,7 +41,9 @@ func redisStreamsOutputConfig() *service.ConfigSpec {
 		Description(`
 It's possible to specify a maximum length of the target stream by setting it to a value greater than 0, in which case this cap is applied only when Redis is able to remove a whole macro node, for efficiency.
 
-Redis stream entries are key/value pairs, as such it is necessary to specify the key to be set to the body of the message. All metadata fields of the message will also be set as key/value pairs, if there is a key collision between a metadata item and the body then the body takes precedence.`+service.OutputPerformanceDocs(true, true)).
+Redis stream entries are key/value pairs, as such it is necessary to specify the key to be set to the body of the message. All metadata fields of the message will also be set as key/value pairs, if there is a key collision between a metadata item and the body then the body takes precedence.
+
+Consumer groups only receive entries added after the group was created. When `+"`consumer_groups`"+` are listed they are created on each stream (with the XGROUP CREATE command) before the first entry is added to it by this output, which ensures that every entry written is delivered to those groups even when their consumers have not yet started.`+service.OutputPerformanceDocs(true, true)).
 		Categories("Services").
 		Fields(clientFields()...).
 		Fields(
@@ -54,6 +58,12 @@ Redis stream entries are key/value pairs, as such it is necessary to specify the
 			service.NewOutputMaxInFlightField(),
 			service.NewMetadataExcludeFilterField(soFieldMetadata).
 				Description("Specify criteria for which metadata values are included in the message body."),
+			service.NewStringListField(soFieldGroups).
+				Description("A list of consumer groups to create on each stream before adding entries to it, if they do not already exist.").
+				Example([]string{"foo_group"}).
+				Version("4.62.0").
+				Advanced().
+				Default([]string{}),
 			service.NewBatchPolicyField(soFieldBatching),
 		)
 }
@@ -81,10 +91,15 @@ type redisStreamsWriter struct {
 	bodyKey    string
 	maxLen     int
 	metaFilter *service.MetadataExcludeFilter
+	groups     []string
 
 	clientCtor func() (redis.UniversalClient, error)
 	client     redis.UniversalClient
 	connMut    sync.RWMutex
+
+	// Streams that the consumer groups are known to exist on.
+	groupStreams    map[string]struct{}
+	groupStreamsMut sync.Mutex
 }
 
 func newRedisStreamsWriter(conf *service.ParsedConfig, mgr *service.Resources) (r *redisStreamsWriter, err error) {
@@ -110,6 +125,9 @@ func newRedisStreamsWriter(conf *service.ParsedConfig, mgr *service.Resources) (
 	if r.metaFilter, err = conf.FieldMetadataExcludeFilter(soFieldMetadata); err != nil {
 		return
 	}
+	if r.groups, err = conf.FieldStringList(soFieldGroups); err != nil {
+		return
+	}
 
 	if _, err := getClient(conf); err != nil {
 		return nil, err
@@ -129,6 +147,35 @@ func (r *redisStreamsWriter) Connect(ctx context.Context) error {
 		return err
 	}
 	r.client = client
+
+	r.groupStreamsMut.Lock()
+	r.groupStreams = map[string]struct{}{}
+	r.groupStreamsMut.Unlock()
+	return nil
+}
+
+// ensureGroups creates the consumer groups on streams that they have not yet
+// been created on.
+func (r *redisStreamsWriter) ensureGroups(ctx context.Context, client redis.UniversalClient, streams ...string) error {
+	if len(r.groups) == 0 {
+		return nil
+	}
+
+	r.groupStreamsMut.Lock()
+	defer r.groupStreamsMut.Unlock()
+
+	for _, stream := range streams {
+		if _, exists := r.groupStreams[stream]; exists {
+			continue
+		}
+		for _, group := range r.groups {
+			err := client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				return fmt.Errorf("failed to create group %v for stream %v: %w", group, stream, err)
+			}
+		}
+		r.groupStreams[stream] = struct{}{}
+	}
 	return nil
 }
 
@@ -162,6 +209,9 @@ func (r *redisStreamsWriter) WriteBatch(ctx context.Context, batch service.Messa
 			return err
 		}
 
+		if err := r.ensureGroups(ctx, client, stream); err != nil {
+			return err
+		}
 		if err := client.XAdd(ctx, &redis.XAddArgs{
 			ID:     "*",
 			Stream: stream,
@@ -176,13 +226,19 @@ func (r *redisStreamsWriter) WriteBatch(ctx context.Context, batch service.Messa
 		return nil
 	}
 
-	pipe := client.Pipeline()
+	streams := make([]string, len(batch))
 	for i := range batch {
-		stream, err := batch.TryInterpolatedString(i, r.stream)
-		if err != nil {
+		var err error
+		if streams[i], err = batch.TryInterpolatedString(i, r.stream); err != nil {
 			return fmt.Errorf("stream interpolation error: %w", err)
 		}
+	}
+	if err := r.ensureGroups(ctx, client, streams...); err != nil {
+		return err
+	}
 
+	pipe := client.Pipeline()
+	for i := range batch {
 		values, err := partToMap(batch[i])
 		if err != nil {
 			return err
@@ -190,7 +246,7 @@ func (r *redisStreamsWriter) WriteBatch(ctx context.Context, batch service.Messa
 
 		_ = pipe.XAdd(ctx, &redis.XAddArgs{
 			ID:     "*",
-			Stream: stream,
+			Stream: streams[i],
 			MaxLen: int64(r.maxLen),
 			Approx: true,
 			Values: values,
@@ -111,6 +111,91 @@ func TestIntegrationRedisProcessor(t *testing.T) {
 	t.Run("testRedisHGetAll", func(t *testing.T) {
 		testRedisHGetAll(t, urlStr)
 	})
+
+	require.NoError(t, client.FlushAll(ctx).Err())
+	t.Run("testRedisEnrichGet", func(t *testing.T) {
+		testRedisEnrichGet(t, client, urlStr)
+	})
+	t.Run("testRedisEnrichHGetAll", func(t *testing.T) {
+		testRedisEnrichHGetAll(t, client, urlStr)
+	})
+}
+
+func testRedisEnrichGet(t *testing.T, client *redis.Client, url string) {
+	conf, err := redisEnrichProcConfig().ParseYAML(fmt.Sprintf(`
+url: %v
+key_mapping: 'root = "product:" + this.sku'
+target_path: product
+miss_policy: error
+`, url), nil)
+	require.NoError(t, err)
+
+	r, err := newRedisEnrichProcFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	require.NoError(t, client.Set(ctx, "product:a", `{"name":"apple"}`, 0).Err())
+	require.NoError(t, client.Set(ctx, "product:b", "banana", 0).Err())
+
+	resMsgs, err := r.ProcessBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte(`{"sku":"a"}`)),
+		service.NewMessage([]byte(`{"sku":"b"}`)),
+		service.NewMessage([]byte(`{"sku":"c"}`)),
+		service.NewMessage([]byte(`{"sku":"a"}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, resMsgs, 1)
+	require.Len(t, resMsgs[0], 4)
+
+	for i, exp := range []string{
+		`{"product":{"name":"apple"},"sku":"a"}`,
+		`{"product":"banana","sku":"b"}`,
+		`{"sku":"c"}`,
+		`{"product":{"name":"apple"},"sku":"a"}`,
+	} {
+		b, err := resMsgs[0][i].AsBytes()
+		require.NoError(t, err)
+		assert.JSONEq(t, exp, string(b), i)
+	}
+	require.NoError(t, resMsgs[0][0].GetError())
+	require.ErrorContains(t, resMsgs[0][2].GetError(), "key product:c was not found")
+}
+
+func testRedisEnrichHGetAll(t *testing.T, client *redis.Client, url string) {
+	conf, err := redisEnrichProcConfig().ParseYAML(fmt.Sprintf(`
+url: %v
+key_mapping: 'root = "customer:" + this.id'
+command: hgetall
+target_path: customer
+miss_policy: default
+default_value:
+  tier: free
+`, url), nil)
+	require.NoError(t, err)
+
+	r, err := newRedisEnrichProcFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	require.NoError(t, client.HSet(ctx, "customer:1", "name", "ash", "tier", "gold").Err())
+
+	resMsgs, err := r.ProcessBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"1"}`)),
+		service.NewMessage([]byte(`{"id":"2"}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, resMsgs, 1)
+	require.Len(t, resMsgs[0], 2)
+
+	for i, exp := range []string{
+		`{"customer":{"name":"ash","tier":"gold"},"id":"1"}`,
+		`{"customer":{"tier":"free"},"id":"2"}`,
+	} {
+		require.NoError(t, resMsgs[0][i].GetError())
+		b, err := resMsgs[0][i].AsBytes()
+		require.NoError(t, err)
+		assert.JSONEq(t, exp, string(b), i)
+	}
 }
 
 func testRedisScript(t *testing.T, url string) {
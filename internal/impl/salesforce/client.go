@@ -0,0 +1,232 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sfFieldOrgURL       = "org_url"
+	sfFieldClientID     = "client_id"
+	sfFieldClientSecret = "client_secret"
+	sfFieldAPIVersion   = "api_version"
+	sfFieldTimeout      = "timeout"
+)
+
+func clientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewURLField(sfFieldOrgURL).
+			Description("The My Domain URL of the Salesforce org, which is used to obtain access tokens.").
+			Example("https://acme.my.salesforce.com"),
+		service.NewStringField(sfFieldClientID).
+			Description("The consumer key of a connected app with the OAuth 2.0 client credentials flow enabled."),
+		service.NewStringField(sfFieldClientSecret).
+			Description("The consumer secret of the connected app.").
+			Secret(),
+		service.NewStringField(sfFieldAPIVersion).
+			Description("The Salesforce API version to use.").
+			Default("62.0").
+			Advanced(),
+		service.NewDurationField(sfFieldTimeout).
+			Description("The maximum period of time to wait for each request, excluding long polls for streaming events.").
+			Default("30s").
+			Advanced(),
+	}
+}
+
+// sfClient performs authenticated requests against the REST APIs of an org
+// using access tokens obtained with the client credentials flow.
+type sfClient struct {
+	orgURL       string
+	clientID     string
+	clientSecret string
+	apiVersion   string
+	timeout      time.Duration
+	http         *http.Client
+
+	mut         sync.Mutex
+	token       string
+	instanceURL string
+}
+
+func sfClientFromParsed(conf *service.ParsedConfig) (*sfClient, error) {
+	c := &sfClient{}
+	var err error
+	if c.orgURL, err = conf.FieldString(sfFieldOrgURL); err != nil {
+		return nil, err
+	}
+	c.orgURL = strings.TrimSuffix(c.orgURL, "/")
+	if c.clientID, err = conf.FieldString(sfFieldClientID); err != nil {
+		return nil, err
+	}
+	if c.clientSecret, err = conf.FieldString(sfFieldClientSecret); err != nil {
+		return nil, err
+	}
+	if c.apiVersion, err = conf.FieldString(sfFieldAPIVersion); err != nil {
+		return nil, err
+	}
+	if c.timeout, err = conf.FieldDuration(sfFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	// The streaming API relies on cookies for load balancing sessions.
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	c.http = &http.Client{Jar: jar}
+	return c, nil
+}
+
+// authenticate obtains a new access token.
+func (c *sfClient) authenticate(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.orgURL+"/services/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to obtain access token: status %v: %s", res.StatusCode, body)
+	}
+
+	var tokenRes struct {
+		AccessToken string `json:"access_token"`
+		InstanceURL string `json:"instance_url"`
+	}
+	if err := json.Unmarshal(body, &tokenRes); err != nil {
+		return fmt.Errorf("failed to parse access token response: %w", err)
+	}
+
+	c.mut.Lock()
+	c.token = tokenRes.AccessToken
+	c.instanceURL = strings.TrimSuffix(tokenRes.InstanceURL, "/")
+	if c.instanceURL == "" {
+		c.instanceURL = c.orgURL
+	}
+	c.mut.Unlock()
+	return nil
+}
+
+// errUnauthorized is returned when the access token has expired or been
+// revoked.
+var errUnauthorized = errors.New("unauthorized")
+
+// sfResponse is a successful response with its body read.
+type sfResponse struct {
+	header http.Header
+	body   []byte
+}
+
+// do performs a request relative to the instance URL, authenticating when no
+// access token has been obtained yet and again if it is rejected. A zero
+// timeout disables the default timeout.
+func (c *sfClient) do(ctx context.Context, method, path string, body any, timeout time.Duration) (*sfResponse, error) {
+	c.mut.Lock()
+	hasToken := c.token != ""
+	c.mut.Unlock()
+	if !hasToken {
+		if err := c.authenticate(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.doOnce(ctx, method, path, body, timeout)
+	if errors.Is(err, errUnauthorized) {
+		if err = c.authenticate(ctx); err != nil {
+			return nil, err
+		}
+		res, err = c.doOnce(ctx, method, path, body, timeout)
+	}
+	return res, err
+}
+
+func (c *sfClient) doOnce(ctx context.Context, method, path string, body any, timeout time.Duration) (*sfResponse, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var bodyReader io.Reader = http.NoBody
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	c.mut.Lock()
+	token, instanceURL := c.token, c.instanceURL
+	c.mut.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, method, instanceURL+path, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		return nil, errUnauthorized
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("request to %v failed with status %v: %s", path, res.StatusCode, resBody)
+	}
+	return &sfResponse{header: res.Header, body: resBody}, nil
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sbiFieldQuery        = "query"
+	sbiFieldQueryAll     = "query_all"
+	sbiFieldMaxRecords   = "max_records"
+	sbiFieldPollInterval = "poll_interval"
+)
+
+func bulkInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Executes a SOQL query with the Salesforce Bulk API 2.0 and emits the resulting records.").
+		Description(`
+Creates a bulk query job, waits for it to complete and then emits each page of results as a batch of messages, one for each record. Once all records have been consumed the input shuts down, which makes it suitable for backfilling data before switching to the `+"xref:components:inputs/salesforce_cdc.adoc[`salesforce_cdc` input]"+` within a `+"xref:components:inputs/sequence.adoc[`sequence`]"+`.
+
+Records are emitted as JSON objects where fields of related objects, such as `+"`Account.Name`"+`, are nested. The Bulk API does not distinguish empty strings from null values, and therefore empty fields are emitted as null.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+- salesforce_job_id
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewStringField(sbiFieldQuery).
+				Description("The SOQL query to execute.").
+				Example("SELECT Id, Name, Account.Name FROM Contact"),
+			service.NewBoolField(sbiFieldQueryAll).
+				Description("Whether to include deleted and archived records in the results.").
+				Default(false),
+			service.NewIntField(sbiFieldMaxRecords).
+				Description("The maximum number of records within each page of results, which is emitted as a batch. Salesforce chooses the page size when set to zero.").
+				Default(10000).
+				Advanced(),
+			service.NewDurationField(sbiFieldPollInterval).
+				Description("The interval at which to check whether the query job has completed.").
+				Default("5s").
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Backfill and stream", "Consumes all existing accounts and then streams changes to them.", `
+input:
+  sequence:
+    inputs:
+      - salesforce_bulk:
+          org_url: https://acme.my.salesforce.com
+          client_id: ${SALESFORCE_CLIENT_ID}
+          client_secret: ${SALESFORCE_CLIENT_SECRET}
+          query: SELECT Id, Name, Industry FROM Account
+      - salesforce_cdc:
+          org_url: https://acme.my.salesforce.com
+          client_id: ${SALESFORCE_CLIENT_ID}
+          client_secret: ${SALESFORCE_CLIENT_SECRET}
+          topics: [ /data/AccountChangeEvent ]
+          replay_preset: earliest
+`)
+}
+
+func init() {
+	service.MustRegisterBatchInput("salesforce_bulk", bulkInputSpec(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+		i, err := newBulkInputFromParsed(conf, mgr)
+		if err != nil {
+			return nil, err
+		}
+		return service.AutoRetryNacksBatchedToggled(conf, i)
+	})
+}
+
+type bulkInput struct {
+	log *service.Logger
+
+	client       *sfClient
+	query        string
+	queryAll     bool
+	maxRecords   int
+	pollInterval time.Duration
+
+	jobID    string
+	complete bool
+	locator  string
+	done     bool
+}
+
+func newBulkInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*bulkInput, error) {
+	i := &bulkInput{log: mgr.Logger()}
+
+	var err error
+	if i.client, err = sfClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.query, err = conf.FieldString(sbiFieldQuery); err != nil {
+		return nil, err
+	}
+	if i.queryAll, err = conf.FieldBool(sbiFieldQueryAll); err != nil {
+		return nil, err
+	}
+	if i.maxRecords, err = conf.FieldInt(sbiFieldMaxRecords); err != nil {
+		return nil, err
+	}
+	if i.maxRecords < 0 {
+		return nil, fmt.Errorf("%v must not be negative", sbiFieldMaxRecords)
+	}
+	if i.pollInterval, err = conf.FieldDuration(sbiFieldPollInterval); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (i *bulkInput) jobsPath() string {
+	return "/services/data/v" + i.client.apiVersion + "/jobs/query"
+}
+
+func (i *bulkInput) Connect(ctx context.Context) error {
+	if i.jobID != "" {
+		return nil
+	}
+
+	operation := "query"
+	if i.queryAll {
+		operation = "queryAll"
+	}
+	res, err := i.client.do(ctx, http.MethodPost, i.jobsPath(), map[string]any{
+		"operation": operation,
+		"query":     i.query,
+	}, i.client.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to create query job: %w", err)
+	}
+
+	var job struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(res.body, &job); err != nil {
+		return fmt.Errorf("failed to parse query job: %w", err)
+	}
+	if job.ID == "" {
+		return errors.New("query job was created without an ID")
+	}
+	i.jobID = job.ID
+	i.log.Debugf("Created bulk query job %v", i.jobID)
+	return nil
+}
+
+func (i *bulkInput) awaitJob(ctx context.Context) error {
+	for {
+		res, err := i.client.do(ctx, http.MethodGet, i.jobsPath()+"/"+i.jobID, nil, i.client.timeout)
+		if err != nil {
+			return fmt.Errorf("failed to check query job: %w", err)
+		}
+		var job struct {
+			State        string `json:"state"`
+			ErrorMessage string `json:"errorMessage"`
+		}
+		if err := json.Unmarshal(res.body, &job); err != nil {
+			return fmt.Errorf("failed to parse query job: %w", err)
+		}
+
+		switch job.State {
+		case "JobComplete":
+			return nil
+		case "Failed", "Aborted":
+			return fmt.Errorf("query job %v finished with state %v: %v", i.jobID, job.State, job.ErrorMessage)
+		}
+
+		select {
+		case <-time.After(i.pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (i *bulkInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	if i.done {
+		return nil, nil, service.ErrEndOfInput
+	}
+	if i.jobID == "" {
+		return nil, nil, service.ErrNotConnected
+	}
+	if !i.complete {
+		if err := i.awaitJob(ctx); err != nil {
+			return nil, nil, err
+		}
+		i.complete = true
+	}
+
+	query := url.Values{}
+	if i.maxRecords > 0 {
+		query.Set("maxRecords", strconv.Itoa(i.maxRecords))
+	}
+	if i.locator != "" {
+		query.Set("locator", i.locator)
+	}
+	path := i.jobsPath() + "/" + i.jobID + "/results"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	res, err := i.client.do(ctx, http.MethodGet, path, nil, i.client.timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read query results: %w", err)
+	}
+	records, err := parseBulkCSV(res.body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse query results: %w", err)
+	}
+
+	if i.locator = res.header.Get("Sforce-Locator"); i.locator == "" || i.locator == "null" {
+		i.done = true
+	}
+
+	batch := make(service.MessageBatch, 0, len(records))
+	for _, r := range records {
+		msg := service.NewMessage(nil)
+		msg.SetStructuredMut(r)
+		msg.MetaSetMut("salesforce_job_id", i.jobID)
+		batch = append(batch, msg)
+	}
+	if len(batch) == 0 {
+		if i.done {
+			return nil, nil, service.ErrEndOfInput
+		}
+		return i.ReadBatch(ctx)
+	}
+	return batch, func(context.Context, error) error { return nil }, nil
+}
+
+// parseBulkCSV converts query results into objects, nesting the fields of
+// related objects by splitting column names on dots.
+func parseBulkCSV(body []byte) ([]map[string]any, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	paths := make([][]string, len(header))
+	for j, h := range header {
+		paths[j] = strings.Split(h, ".")
+	}
+
+	var records []map[string]any
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record := map[string]any{}
+		for j, v := range row {
+			var value any
+			if v != "" {
+				value = v
+			}
+			setPath(record, paths[j], value)
+		}
+		records = append(records, record)
+	}
+}
+
+func setPath(obj map[string]any, path []string, value any) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := obj[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			obj[p] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = value
+}
+
+func (*bulkInput) Close(context.Context) error {
+	return nil
+}
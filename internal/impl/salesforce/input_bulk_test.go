@@ -0,0 +1,116 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestBulkInput(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	var srv *httptest.Server
+	var polls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/oauth2/token", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token",
+			"instance_url": srv.URL,
+		})
+	})
+	mux.HandleFunc("POST /services/data/v62.0/jobs/query", func(w http.ResponseWriter, r *http.Request) {
+		var job map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&job))
+		assert.Equal(t, map[string]any{
+			"operation": "queryAll",
+			"query":     "SELECT Id, Name, Account.Name FROM Contact",
+		}, job)
+		_, _ = w.Write([]byte(`{"id":"750"}`))
+	})
+	mux.HandleFunc("GET /services/data/v62.0/jobs/query/750", func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		state := "InProgress"
+		if polls > 1 {
+			state = "JobComplete"
+		}
+		_, _ = w.Write([]byte(`{"id":"750","state":"` + state + `"}`))
+	})
+	mux.HandleFunc("GET /services/data/v62.0/jobs/query/750/results", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("maxRecords"))
+		if r.URL.Query().Get("locator") == "" {
+			w.Header().Set("Sforce-Locator", "next")
+			_, _ = w.Write([]byte("\"Id\",\"Name\",\"Account.Name\"\n\"003A\",\"Ada\",\"Acme\"\n\"003B\",\"Bob\",\"\"\n"))
+			return
+		}
+		assert.Equal(t, "next", r.URL.Query().Get("locator"))
+		w.Header().Set("Sforce-Locator", "null")
+		_, _ = w.Write([]byte("\"Id\",\"Name\",\"Account.Name\"\n\"003C\",\"Cy\",\"Initech\"\n"))
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	conf, err := bulkInputSpec().ParseYAML(`
+org_url: `+srv.URL+`
+client_id: foo
+client_secret: bar
+query: SELECT Id, Name, Account.Name FROM Contact
+query_all: true
+max_records: 2
+poll_interval: 1ms
+`, nil)
+	require.NoError(t, err)
+
+	i, err := newBulkInputFromParsed(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, i.Connect(ctx))
+
+	var records []any
+	for {
+		batch, ackFn, err := i.ReadBatch(ctx)
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+		require.NoError(t, err)
+		for _, msg := range batch {
+			v, err := msg.AsStructured()
+			require.NoError(t, err)
+			records = append(records, v)
+
+			id, _ := msg.MetaGetMut("salesforce_job_id")
+			assert.Equal(t, "750", id)
+		}
+		require.NoError(t, ackFn(ctx, nil))
+	}
+	require.NoError(t, i.Close(ctx))
+
+	assert.Equal(t, []any{
+		map[string]any{"Id": "003A", "Name": "Ada", "Account": map[string]any{"Name": "Acme"}},
+		map[string]any{"Id": "003B", "Name": "Bob", "Account": map[string]any{"Name": nil}},
+		map[string]any{"Id": "003C", "Name": "Cy", "Account": map[string]any{"Name": "Initech"}},
+	}, records)
+	assert.Equal(t, 2, polls)
+}
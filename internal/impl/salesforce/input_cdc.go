@@ -0,0 +1,456 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/checkpoint"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sciFieldTopics         = "topics"
+	sciFieldReplayPreset   = "replay_preset"
+	sciFieldCache          = "cache"
+	sciFieldCacheKeyPrefix = "cache_key_prefix"
+
+	// Replay IDs that subscribe to new events only and to all retained events.
+	replayLatest   = -1
+	replayEarliest = -2
+)
+
+func cdcInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Services").
+		Summary("Consumes Change Data Capture events and Platform Events from Salesforce with the Streaming API.").
+		Description(`
+Subscribes to one or more channels over https://developer.salesforce.com/docs/atlas.en-us.api_streaming.meta/api_streaming/using_streaming_api_durability.htm[^CometD], where each event is emitted as a message containing its payload.
+
+The replay ID of the latest acknowledged event of each channel is stored in a `+"`"+sciFieldCache+"`"+` resource, and when the input connects it resumes each subscription after the stored replay ID. Channels without a stored replay ID begin according to `+"`"+sciFieldReplayPreset+"`"+`. Salesforce retains events for 72 hours, and when a stored replay ID is no longer retained the subscription begins from the earliest retained event instead.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+- salesforce_topic
+- salesforce_replay_id
+- salesforce_entity_name (Change Data Capture events only)
+- salesforce_change_type (Change Data Capture events only)
+- salesforce_record_ids (Change Data Capture events only)
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(clientFields()...).
+		Fields(
+			service.NewStringListField(sciFieldTopics).
+				Description("The channels to subscribe to, which can be Change Data Capture channels, Platform Event channels or custom channels.").
+				Example([]string{"/data/ChangeEvents"}).
+				Example([]string{"/data/AccountChangeEvent", "/data/ContactChangeEvent"}).
+				Example([]string{"/event/Order_Placed__e"}),
+			service.NewStringAnnotatedEnumField(sciFieldReplayPreset, map[string]string{
+				"latest":   "Consume only events published after subscribing.",
+				"earliest": "Consume all events retained by Salesforce.",
+			}).
+				Description("Where to begin consuming channels that have no stored replay ID.").
+				Default("latest"),
+			service.NewStringField(sciFieldCache).
+				Description("A xref:components:caches/about.adoc[cache resource] for storing the replay ID of the latest acknowledged event of each channel.").
+				Default(""),
+			service.NewStringField(sciFieldCacheKeyPrefix).
+				Description("A prefix for the cache keys of replay IDs, which are suffixed with the channel name.").
+				Default("salesforce_replay_id_").
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Account changes", "Consumes changes to accounts and contacts, resuming from the latest acknowledged change after restarts.", `
+input:
+  salesforce_cdc:
+    org_url: https://acme.my.salesforce.com
+    client_id: ${SALESFORCE_CLIENT_ID}
+    client_secret: ${SALESFORCE_CLIENT_SECRET}
+    topics:
+      - /data/AccountChangeEvent
+      - /data/ContactChangeEvent
+    cache: replay_ids
+
+cache_resources:
+  - label: replay_ids
+    redis:
+      url: redis://localhost:6379
+`)
+}
+
+func init() {
+	service.MustRegisterInput("salesforce_cdc", cdcInputSpec(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+		i, err := newCDCInputFromParsed(conf, mgr)
+		if err != nil {
+			return nil, err
+		}
+		return service.AutoRetryNacksToggled(conf, i)
+	})
+}
+
+type bayeuxAdvice struct {
+	Reconnect string `json:"reconnect"`
+}
+
+type bayeuxMessage struct {
+	Channel      string          `json:"channel"`
+	ClientID     string          `json:"clientId"`
+	Successful   bool            `json:"successful"`
+	Error        string          `json:"error"`
+	Subscription string          `json:"subscription"`
+	Advice       *bayeuxAdvice   `json:"advice"`
+	Data         json.RawMessage `json:"data"`
+}
+
+type cdcEvent struct {
+	topic    string
+	replayID int64
+	payload  json.RawMessage
+}
+
+type cdcInput struct {
+	log *service.Logger
+	mgr *service.Resources
+
+	client         *sfClient
+	topics         []string
+	replayPreset   int64
+	cache          string
+	cacheKeyPrefix string
+
+	checkpointers map[string]*checkpoint.Capped[int64]
+
+	mut      sync.Mutex
+	clientID string
+	acked    map[string]int64
+	pending  []cdcEvent
+}
+
+func newCDCInputFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*cdcInput, error) {
+	i := &cdcInput{
+		log:           mgr.Logger(),
+		mgr:           mgr,
+		checkpointers: map[string]*checkpoint.Capped[int64]{},
+		acked:         map[string]int64{},
+	}
+
+	var err error
+	if i.client, err = sfClientFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if i.topics, err = conf.FieldStringList(sciFieldTopics); err != nil {
+		return nil, err
+	}
+	if len(i.topics) == 0 {
+		return nil, errors.New("at least one topic must be specified")
+	}
+	for _, t := range i.topics {
+		i.checkpointers[t] = checkpoint.NewCapped[int64](1024)
+	}
+
+	var preset string
+	if preset, err = conf.FieldString(sciFieldReplayPreset); err != nil {
+		return nil, err
+	}
+	i.replayPreset = replayLatest
+	if preset == "earliest" {
+		i.replayPreset = replayEarliest
+	}
+
+	if i.cache, err = conf.FieldString(sciFieldCache); err != nil {
+		return nil, err
+	}
+	if i.cacheKeyPrefix, err = conf.FieldString(sciFieldCacheKeyPrefix); err != nil {
+		return nil, err
+	}
+	if i.cache != "" && !mgr.HasCache(i.cache) {
+		return nil, fmt.Errorf("cache resource %q was not found", i.cache)
+	}
+	return i, nil
+}
+
+func (i *cdcInput) cometdPath() string {
+	return "/cometd/" + i.client.apiVersion
+}
+
+// exchange sends bayeux messages and returns the responses. A zero timeout is
+// used for long polls, which Salesforce holds open for up to two minutes.
+func (i *cdcInput) exchange(ctx context.Context, timeout time.Duration, msgs ...map[string]any) ([]bayeuxMessage, error) {
+	res, err := i.client.do(ctx, http.MethodPost, i.cometdPath(), msgs, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var replies []bayeuxMessage
+	if err := json.Unmarshal(res.body, &replies); err != nil {
+		return nil, fmt.Errorf("failed to parse bayeux response: %w", err)
+	}
+	return replies, nil
+}
+
+func (i *cdcInput) storedReplayID(ctx context.Context, topic string) (int64, error) {
+	i.mut.Lock()
+	id, exists := i.acked[topic]
+	i.mut.Unlock()
+	if exists || i.cache == "" {
+		if !exists {
+			id = i.replayPreset
+		}
+		return id, nil
+	}
+
+	id = i.replayPreset
+	var cacheErr error
+	if err := i.mgr.AccessCache(ctx, i.cache, func(c service.Cache) {
+		var b []byte
+		if b, cacheErr = c.Get(ctx, i.cacheKeyPrefix+topic); cacheErr != nil {
+			if errors.Is(cacheErr, service.ErrKeyNotFound) {
+				cacheErr = nil
+			}
+			return
+		}
+		id, cacheErr = strconv.ParseInt(string(b), 10, 64)
+	}); err != nil {
+		return 0, err
+	}
+	if cacheErr != nil {
+		return 0, fmt.Errorf("failed to obtain stored replay ID of %v: %w", topic, cacheErr)
+	}
+	return id, nil
+}
+
+func (i *cdcInput) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	connected := i.clientID != ""
+	i.mut.Unlock()
+	if connected {
+		return nil
+	}
+
+	replies, err := i.exchange(ctx, i.client.timeout, map[string]any{
+		"channel":                  "/meta/handshake",
+		"version":                  "1.0",
+		"minimumVersion":           "1.0",
+		"supportedConnectionTypes": []string{"long-polling"},
+		"ext":                      map[string]any{"replay": true},
+	})
+	if err != nil {
+		return fmt.Errorf("handshake failed: %w", err)
+	}
+	if len(replies) == 0 || !replies[0].Successful {
+		return fmt.Errorf("handshake failed: %v", replyError(replies))
+	}
+	clientID := replies[0].ClientID
+
+	for _, topic := range i.topics {
+		replayID, err := i.storedReplayID(ctx, topic)
+		if err != nil {
+			return err
+		}
+		if err := i.subscribe(ctx, clientID, topic, replayID); err != nil {
+			if replayID < 0 || !strings.Contains(err.Error(), "replayId") {
+				return err
+			}
+			i.log.Warnf("Replay ID %v of %v is no longer retained, consuming from the earliest retained event: %v", replayID, topic, err)
+			if err := i.subscribe(ctx, clientID, topic, replayEarliest); err != nil {
+				return err
+			}
+		}
+	}
+
+	i.mut.Lock()
+	i.clientID = clientID
+	i.pending = nil
+	i.mut.Unlock()
+	return nil
+}
+
+func (i *cdcInput) subscribe(ctx context.Context, clientID, topic string, replayID int64) error {
+	replies, err := i.exchange(ctx, i.client.timeout, map[string]any{
+		"channel":      "/meta/subscribe",
+		"clientId":     clientID,
+		"subscription": topic,
+		"ext":          map[string]any{"replay": map[string]int64{topic: replayID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %v: %w", topic, err)
+	}
+	if len(replies) == 0 || !replies[0].Successful {
+		return fmt.Errorf("failed to subscribe to %v: %v", topic, replyError(replies))
+	}
+	return nil
+}
+
+func replyError(replies []bayeuxMessage) string {
+	if len(replies) == 0 || replies[0].Error == "" {
+		return "unsuccessful response"
+	}
+	return replies[0].Error
+}
+
+func (i *cdcInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	for {
+		i.mut.Lock()
+		clientID := i.clientID
+		var event *cdcEvent
+		if len(i.pending) > 0 {
+			event = &i.pending[0]
+			i.pending = i.pending[1:]
+		}
+		i.mut.Unlock()
+		if clientID == "" {
+			return nil, nil, service.ErrNotConnected
+		}
+		if event != nil {
+			return i.eventToMessage(ctx, *event)
+		}
+
+		replies, err := i.exchange(ctx, 0, map[string]any{
+			"channel":        "/meta/connect",
+			"clientId":       clientID,
+			"connectionType": "long-polling",
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				i.disconnected()
+				return nil, nil, service.ErrNotConnected
+			}
+			return nil, nil, err
+		}
+
+		var events []cdcEvent
+		for _, r := range replies {
+			if r.Channel == "/meta/connect" {
+				if !r.Successful {
+					i.log.Warnf("Streaming connection was rejected, reconnecting: %v", r.Error)
+					i.disconnected()
+					return nil, nil, service.ErrNotConnected
+				}
+				continue
+			}
+			if strings.HasPrefix(r.Channel, "/meta/") || len(r.Data) == 0 {
+				continue
+			}
+			var data struct {
+				Event struct {
+					ReplayID int64 `json:"replayId"`
+				} `json:"event"`
+				Payload json.RawMessage `json:"payload"`
+				SObject json.RawMessage `json:"sobject"`
+			}
+			if err := json.Unmarshal(r.Data, &data); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse event: %w", err)
+			}
+			payload := data.Payload
+			if len(payload) == 0 {
+				// PushTopic events carry the record as an sobject.
+				payload = data.SObject
+			}
+			events = append(events, cdcEvent{topic: r.Channel, replayID: data.Event.ReplayID, payload: payload})
+		}
+
+		i.mut.Lock()
+		i.pending = append(i.pending, events...)
+		i.mut.Unlock()
+	}
+}
+
+func (i *cdcInput) disconnected() {
+	i.mut.Lock()
+	i.clientID = ""
+	i.pending = nil
+	i.mut.Unlock()
+}
+
+func (i *cdcInput) eventToMessage(ctx context.Context, event cdcEvent) (*service.Message, service.AckFunc, error) {
+	msg := service.NewMessage(event.payload)
+	msg.MetaSetMut("salesforce_topic", event.topic)
+	msg.MetaSetMut("salesforce_replay_id", event.replayID)
+
+	var header struct {
+		ChangeEventHeader *struct {
+			EntityName string   `json:"entityName"`
+			ChangeType string   `json:"changeType"`
+			RecordIDs  []string `json:"recordIds"`
+		} `json:"ChangeEventHeader"`
+	}
+	if err := json.Unmarshal(event.payload, &header); err == nil && header.ChangeEventHeader != nil {
+		msg.MetaSetMut("salesforce_entity_name", header.ChangeEventHeader.EntityName)
+		msg.MetaSetMut("salesforce_change_type", header.ChangeEventHeader.ChangeType)
+		recordIDs := make([]any, 0, len(header.ChangeEventHeader.RecordIDs))
+		for _, id := range header.ChangeEventHeader.RecordIDs {
+			recordIDs = append(recordIDs, id)
+		}
+		msg.MetaSetMut("salesforce_record_ids", recordIDs)
+	}
+
+	checkpointer, exists := i.checkpointers[event.topic]
+	if !exists {
+		// Wildcard channels such as /data/ChangeEvents deliver events on the
+		// channel they were subscribed to.
+		return msg, func(context.Context, error) error { return nil }, nil
+	}
+	release, err := checkpointer.Track(ctx, event.replayID, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, func(ctx context.Context, _ error) error {
+		replayID := release()
+		if replayID == nil {
+			return nil
+		}
+		i.mut.Lock()
+		i.acked[event.topic] = *replayID
+		i.mut.Unlock()
+		if i.cache == "" {
+			return nil
+		}
+		var setErr error
+		if err := i.mgr.AccessCache(ctx, i.cache, func(c service.Cache) {
+			setErr = c.Set(ctx, i.cacheKeyPrefix+event.topic, []byte(strconv.FormatInt(*replayID, 10)), nil)
+		}); err != nil {
+			return err
+		}
+		return setErr
+	}, nil
+}
+
+func (i *cdcInput) Close(ctx context.Context) error {
+	i.mut.Lock()
+	clientID := i.clientID
+	i.mut.Unlock()
+	if clientID == "" {
+		return nil
+	}
+	i.disconnected()
+	_, err := i.exchange(ctx, i.client.timeout, map[string]any{
+		"channel":  "/meta/disconnect",
+		"clientId": clientID,
+	})
+	return err
+}
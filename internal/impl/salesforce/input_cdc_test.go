@@ -0,0 +1,220 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeCometD struct {
+	t *testing.T
+
+	mut        sync.Mutex
+	handshakes int
+	subscribed map[string]int64
+	events     [][]map[string]any
+	rejectNext bool
+}
+
+func (f *fakeCometD) handler(srvURL func() string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(f.t, "client_credentials", r.FormValue("grant_type"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token",
+			"instance_url": srvURL(),
+		})
+	})
+	mux.HandleFunc("/cometd/62.0", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(f.t, "Bearer token", r.Header.Get("Authorization"))
+
+		var msgs []map[string]any
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&msgs))
+		require.Len(f.t, msgs, 1)
+
+		f.mut.Lock()
+		defer f.mut.Unlock()
+
+		var replies []map[string]any
+		switch channel := msgs[0]["channel"]; channel {
+		case "/meta/handshake":
+			f.handshakes++
+			replies = append(replies, map[string]any{"channel": channel, "successful": true, "clientId": "client"})
+		case "/meta/subscribe":
+			topic := msgs[0]["subscription"].(string)
+			replay := msgs[0]["ext"].(map[string]any)["replay"].(map[string]any)
+			f.subscribed[topic] = int64(replay[topic].(float64))
+			replies = append(replies, map[string]any{"channel": channel, "successful": true, "subscription": topic})
+		case "/meta/connect":
+			if f.rejectNext {
+				f.rejectNext = false
+				replies = append(replies, map[string]any{
+					"channel":    channel,
+					"successful": false,
+					"error":      "403::Unknown client",
+					"advice":     map[string]any{"reconnect": "handshake"},
+				})
+				break
+			}
+			if len(f.events) > 0 {
+				replies = append(replies, f.events[0]...)
+				f.events = f.events[1:]
+			} else {
+				time.Sleep(10 * time.Millisecond)
+			}
+			replies = append(replies, map[string]any{"channel": channel, "successful": true})
+		case "/meta/disconnect":
+			replies = append(replies, map[string]any{"channel": channel, "successful": true})
+		default:
+			f.t.Errorf("unexpected channel: %v", channel)
+		}
+		_ = json.NewEncoder(w).Encode(replies)
+	})
+	return mux
+}
+
+func cdcEventReply(topic string, replayID int64, payload map[string]any) map[string]any {
+	return map[string]any{
+		"channel": topic,
+		"data": map[string]any{
+			"event":   map[string]any{"replayId": replayID},
+			"payload": payload,
+		},
+	}
+}
+
+func newTestCDCInput(t *testing.T, srvURL string, res *service.Resources) *cdcInput {
+	t.Helper()
+
+	conf, err := cdcInputSpec().ParseYAML(`
+org_url: `+srvURL+`
+client_id: foo
+client_secret: bar
+topics: [ /data/AccountChangeEvent, /event/Order__e ]
+cache: replay
+`, nil)
+	require.NoError(t, err)
+
+	i, err := newCDCInputFromParsed(conf, res)
+	require.NoError(t, err)
+	return i
+}
+
+func TestCDCInputReplay(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	fake := &fakeCometD{t: t, subscribed: map[string]int64{}}
+	var srv *httptest.Server
+	srv = httptest.NewServer(fake.handler(func() string { return srv.URL }))
+	defer srv.Close()
+
+	fake.events = [][]map[string]any{{
+		cdcEventReply("/data/AccountChangeEvent", 11, map[string]any{
+			"ChangeEventHeader": map[string]any{
+				"entityName": "Account",
+				"changeType": "UPDATE",
+				"recordIds":  []string{"001"},
+			},
+			"Name": "Acme",
+		}),
+		cdcEventReply("/event/Order__e", 5, map[string]any{"Amount__c": 10}),
+	}}
+
+	res := service.MockResources(service.MockResourcesOptAddCache("replay"))
+
+	i := newTestCDCInput(t, srv.URL, res)
+	require.NoError(t, i.Connect(ctx))
+	assert.Equal(t, map[string]int64{
+		"/data/AccountChangeEvent": replayLatest,
+		"/event/Order__e":          replayLatest,
+	}, fake.subscribed)
+
+	msg, ackFn, err := i.Read(ctx)
+	require.NoError(t, err)
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ChangeEventHeader":{"entityName":"Account","changeType":"UPDATE","recordIds":["001"]},"Name":"Acme"}`, string(b))
+
+	v, _ := msg.MetaGetMut("salesforce_topic")
+	assert.Equal(t, "/data/AccountChangeEvent", v)
+	v, _ = msg.MetaGetMut("salesforce_replay_id")
+	assert.Equal(t, int64(11), v)
+	v, _ = msg.MetaGetMut("salesforce_entity_name")
+	assert.Equal(t, "Account", v)
+	v, _ = msg.MetaGetMut("salesforce_change_type")
+	assert.Equal(t, "UPDATE", v)
+	v, _ = msg.MetaGetMut("salesforce_record_ids")
+	assert.Equal(t, []any{"001"}, v)
+	require.NoError(t, ackFn(ctx, nil))
+
+	msg, ackFn, err = i.Read(ctx)
+	require.NoError(t, err)
+	_, exists := msg.MetaGetMut("salesforce_entity_name")
+	assert.False(t, exists)
+	require.NoError(t, ackFn(ctx, nil))
+	require.NoError(t, i.Close(ctx))
+
+	// A new input resumes from the replay IDs stored in the cache.
+	i = newTestCDCInput(t, srv.URL, res)
+	require.NoError(t, i.Connect(ctx))
+	assert.Equal(t, map[string]int64{
+		"/data/AccountChangeEvent": 11,
+		"/event/Order__e":          5,
+	}, fake.subscribed)
+	require.NoError(t, i.Close(ctx))
+}
+
+func TestCDCInputRehandshake(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	fake := &fakeCometD{t: t, subscribed: map[string]int64{}, rejectNext: true}
+	var srv *httptest.Server
+	srv = httptest.NewServer(fake.handler(func() string { return srv.URL }))
+	defer srv.Close()
+
+	fake.events = [][]map[string]any{{
+		cdcEventReply("/event/Order__e", 7, map[string]any{"Amount__c": 10}),
+	}}
+
+	i := newTestCDCInput(t, srv.URL, service.MockResources(service.MockResourcesOptAddCache("replay")))
+	require.NoError(t, i.Connect(ctx))
+
+	_, _, err := i.Read(ctx)
+	require.ErrorIs(t, err, service.ErrNotConnected)
+
+	require.NoError(t, i.Connect(ctx))
+	assert.Equal(t, 2, fake.handshakes)
+
+	msg, ackFn, err := i.Read(ctx)
+	require.NoError(t, err)
+	v, _ := msg.MetaGetMut("salesforce_replay_id")
+	assert.Equal(t, int64(7), v)
+	require.NoError(t, ackFn(ctx, nil))
+	require.NoError(t, i.Close(ctx))
+}
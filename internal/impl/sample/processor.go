@@ -0,0 +1,282 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sample
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	spFieldMode        = "mode"
+	spFieldRatio       = "ratio"
+	spFieldKey         = "key"
+	spFieldTargetRate  = "target_rate"
+	spFieldInterval    = "interval"
+	spFieldAction      = "action"
+	spFieldMetadataKey = "metadata_key"
+
+	spModeRatio    = "ratio"
+	spModeHash     = "hash"
+	spModeAdaptive = "adaptive"
+
+	spActionDrop = "drop"
+	spActionTag  = "tag"
+)
+
+func sampleProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.62.0").
+		Summary("Selects a subset of messages, either at random, deterministically by a key, or adaptively in order to meet a target rate, and drops or tags the remaining messages.").
+		Description(`
+This is useful for feeding expensive sinks, such as AI models or analytics platforms, with a controlled subset of a stream. The messages selected depend on the `+"`"+spFieldMode+"`"+`:
+
+- `+"`"+spModeRatio+"`"+`: Each message is selected at random with the probability `+"`"+spFieldRatio+"`"+`.
+- `+"`"+spModeHash+"`"+`: Each message is selected when the hash of its `+"`"+spFieldKey+"`"+` falls within the `+"`"+spFieldRatio+"`"+` of the hash space. Messages with the same key are therefore either all selected or all dropped, which keeps related messages such as the events of a session together, and the same keys are selected by every instance of a pipeline.
+- `+"`"+spModeAdaptive+"`"+`: Messages are selected at random with a probability that's adjusted every `+"`"+spFieldInterval+"`"+` such that roughly `+"`"+spFieldTargetRate+"`"+` messages per second are selected, based on the rate of messages observed during the previous interval. The number of messages selected within an interval never exceeds the target, which caps the selected rate during sudden bursts.
+
+When the `+"`"+spFieldAction+"`"+` is `+"`"+spActionTag+"`"+` no messages are dropped, and instead each message is given the boolean metadata field `+"`"+spFieldMetadataKey+"`"+`, which is `+"`true`"+` for selected messages. This allows selected messages to be routed with a `+"`switch`"+` output while the full stream is delivered elsewhere.`).
+		Fields(
+			service.NewStringAnnotatedEnumField(spFieldMode, map[string]string{
+				spModeRatio:    "Select messages at random with a fixed probability.",
+				spModeHash:     "Select messages deterministically by the hash of a key.",
+				spModeAdaptive: "Select messages at random in order to meet a target rate.",
+			}).
+				Description("The mode used to select messages.").
+				Default(spModeRatio),
+			service.NewFloatField(spFieldRatio).
+				Description("The proportion of messages to select, between 0 and 1, with the `ratio` and `hash` modes.").
+				Default(0.1),
+			service.NewInterpolatedStringField(spFieldKey).
+				Description("The key to select messages by with the `hash` mode.").
+				Examples(`${! meta("kafka_key") }`, `${! this.session_id }`).
+				Optional(),
+			service.NewFloatField(spFieldTargetRate).
+				Description("The number of messages per second to select with the `adaptive` mode.").
+				Example(100).
+				Optional(),
+			service.NewDurationField(spFieldInterval).
+				Description("The interval at which the probability of selecting messages is adjusted with the `adaptive` mode.").
+				Default("1s").
+				Advanced(),
+			service.NewStringAnnotatedEnumField(spFieldAction, map[string]string{
+				spActionDrop: "Drop messages that aren't selected.",
+				spActionTag:  "Keep all messages, and set a metadata field indicating whether each message was selected.",
+			}).
+				Description("What to do with messages that aren't selected.").
+				Default(spActionDrop),
+			service.NewStringField(spFieldMetadataKey).
+				Description("The metadata key indicating whether a message was selected with the `tag` action.").
+				Default("sampled").
+				Advanced(),
+		).
+		LintRule(`root = match {
+  this.mode.or("") == "hash" && !this.exists("key") => [ "a key must be set with the hash mode" ],
+  this.mode.or("") == "adaptive" && !this.exists("target_rate") => [ "a target_rate must be set with the adaptive mode" ],
+}`).
+		Example("Sample sessions for an LLM", "Send the events of roughly five percent of sessions to an expensive model, keeping all events of a selected session.", `
+pipeline:
+  processors:
+    - sample:
+        mode: hash
+        ratio: 0.05
+        key: ${! this.session_id }
+`).
+		Example("Cap the rate of analytics events", "Tag roughly 50 messages per second, regardless of the rate of the stream, and deliver tagged messages to an analytics sink in addition to archiving all messages.", `
+pipeline:
+  processors:
+    - sample:
+        mode: adaptive
+        target_rate: 50
+        action: tag
+
+output:
+  broker:
+    pattern: fan_out
+    outputs:
+      - aws_s3:
+          bucket: archive
+          path: ${! timestamp_unix_nano() }.json
+      - switch:
+          cases:
+            - check: '@sampled'
+              output:
+                http_client:
+                  url: http://analytics:8080/ingest
+            - output:
+                drop: {}
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"sample", sampleProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newSampleProcessorFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type sampleProcessor struct {
+	log *service.Logger
+
+	mode        string
+	ratio       float64
+	key         *service.InterpolatedString
+	targetRate  float64
+	interval    time.Duration
+	tag         bool
+	metadataKey string
+
+	randFloat func() float64
+	clock     func() time.Time
+
+	// The state of the adaptive mode, where the probability is derived from
+	// the number of messages seen during the previous interval.
+	mut           sync.Mutex
+	intervalStart time.Time
+	seen          int
+	taken         int
+	probability   float64
+}
+
+func newSampleProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sampleProcessor, error) {
+	s := &sampleProcessor{
+		log:         mgr.Logger(),
+		randFloat:   rand.Float64,
+		clock:       time.Now,
+		probability: 1,
+	}
+
+	var err error
+	if s.mode, err = conf.FieldString(spFieldMode); err != nil {
+		return nil, err
+	}
+	if s.ratio, err = conf.FieldFloat(spFieldRatio); err != nil {
+		return nil, err
+	}
+	if s.ratio < 0 || s.ratio > 1 {
+		return nil, errors.New("ratio must be between zero and one")
+	}
+	if conf.Contains(spFieldKey) {
+		if s.key, err = conf.FieldInterpolatedString(spFieldKey); err != nil {
+			return nil, err
+		}
+	} else if s.mode == spModeHash {
+		return nil, errors.New("a key must be set with the hash mode")
+	}
+	if conf.Contains(spFieldTargetRate) {
+		if s.targetRate, err = conf.FieldFloat(spFieldTargetRate); err != nil {
+			return nil, err
+		}
+	}
+	if s.mode == spModeAdaptive && s.targetRate <= 0 {
+		return nil, errors.New("a target_rate greater than zero must be set with the adaptive mode")
+	}
+	if s.interval, err = conf.FieldDuration(spFieldInterval); err != nil {
+		return nil, err
+	}
+	if s.interval <= 0 {
+		return nil, errors.New("interval must be greater than zero")
+	}
+
+	action, err := conf.FieldString(spFieldAction)
+	if err != nil {
+		return nil, err
+	}
+	s.tag = action == spActionTag
+	if s.metadataKey, err = conf.FieldString(spFieldMetadataKey); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// hashSelected returns whether the hash of a key falls within the ratio of the
+// hash space.
+func (s *sampleProcessor) hashSelected(key string) bool {
+	u := float64(xxhash.Sum64String(key)>>11) / (1 << 53)
+	return u < s.ratio
+}
+
+func (s *sampleProcessor) adaptiveSelected() bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	now := s.clock()
+	if s.intervalStart.IsZero() {
+		s.intervalStart = now
+	}
+	if elapsed := now.Sub(s.intervalStart); elapsed >= s.interval {
+		// An interval without messages means the rate is unknown, in which
+		// case every message is selected until the cap is reached.
+		s.probability = 1
+		if s.seen > 0 && elapsed < 2*s.interval {
+			observedRate := float64(s.seen) / elapsed.Seconds()
+			s.probability = min(1, s.targetRate/observedRate)
+		}
+		s.intervalStart, s.seen, s.taken = now, 0, 0
+	}
+
+	s.seen++
+	if float64(s.taken) >= s.targetRate*s.interval.Seconds() {
+		return false
+	}
+	if s.probability < 1 && s.randFloat() >= s.probability {
+		return false
+	}
+	s.taken++
+	return true
+}
+
+func (s *sampleProcessor) selected(msg *service.Message) bool {
+	switch s.mode {
+	case spModeHash:
+		key, err := s.key.TryString(msg)
+		if err != nil {
+			s.log.Debugf("Failed to resolve sampling key, not selecting message: %v", err)
+			return false
+		}
+		return s.hashSelected(key)
+	case spModeAdaptive:
+		return s.adaptiveSelected()
+	}
+	return s.randFloat() < s.ratio
+}
+
+func (s *sampleProcessor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	selected := s.selected(msg)
+	if s.tag {
+		msg.MetaSetMut(s.metadataKey, selected)
+		return service.MessageBatch{msg}, nil
+	}
+	if !selected {
+		return nil, nil
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (*sampleProcessor) Close(context.Context) error {
+	return nil
+}
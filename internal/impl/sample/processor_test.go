@@ -0,0 +1,176 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sample
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestProcessor(t *testing.T, conf string) *sampleProcessor {
+	t.Helper()
+
+	pConf, err := sampleProcessorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	s, err := newSampleProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	return s
+}
+
+// countSelected processes n messages and returns the number of messages that
+// are kept.
+func countSelected(t *testing.T, s *sampleProcessor, n int, content func(i int) string) int {
+	t.Helper()
+
+	var kept int
+	for i := range n {
+		batch, err := s.Process(t.Context(), service.NewMessage([]byte(content(i))))
+		require.NoError(t, err)
+		kept += len(batch)
+	}
+	return kept
+}
+
+func TestSampleRatio(t *testing.T) {
+	s := newTestProcessor(t, `ratio: 0.5`)
+
+	values := []float64{0.1, 0.9, 0.4, 0.5}
+	s.randFloat = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	var kept []string
+	for _, c := range []string{"a", "b", "c", "d"} {
+		batch, err := s.Process(t.Context(), service.NewMessage([]byte(c)))
+		require.NoError(t, err)
+		for _, msg := range batch {
+			content, _ := msg.AsBytes()
+			kept = append(kept, string(content))
+		}
+	}
+	assert.Equal(t, []string{"a", "c"}, kept)
+}
+
+func TestSampleHash(t *testing.T) {
+	s := newTestProcessor(t, `
+mode: hash
+ratio: 0.2
+key: ${! json("session") }
+`)
+
+	content := func(i int) string {
+		return fmt.Sprintf(`{"session":"s%v","event":%v}`, i%1000, i/1000)
+	}
+
+	// Every event of a session has the same outcome, and so each pass over the
+	// sessions keeps the same number of messages.
+	first := countSelected(t, s, 1000, content)
+	assert.InDelta(t, 200, first, 50)
+
+	selected := map[string]bool{}
+	for i := range 3000 {
+		msg := service.NewMessage([]byte(content(i)))
+		batch, err := s.Process(t.Context(), msg)
+		require.NoError(t, err)
+
+		session := fmt.Sprintf("s%v", i%1000)
+		if prev, exists := selected[session]; exists {
+			require.Equal(t, prev, len(batch) == 1, session)
+		}
+		selected[session] = len(batch) == 1
+	}
+}
+
+func TestSampleAdaptive(t *testing.T) {
+	s := newTestProcessor(t, `
+mode: adaptive
+target_rate: 10
+`)
+
+	now := time.Unix(0, 0)
+	s.clock = func() time.Time { return now }
+
+	// The rate of the first interval is unknown, and so messages are selected
+	// until the target is reached.
+	content := func(int) string { return "hello" }
+	assert.Equal(t, 10, countSelected(t, s, 100, content))
+
+	// The following interval selects a tenth of the messages based on the rate
+	// observed during the previous interval.
+	now = now.Add(time.Second)
+	values := []float64{0.05, 0.5, 0.09, 0.2}
+	s.randFloat = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+	assert.Equal(t, 2, countSelected(t, s, 4, content))
+
+	// A gap without messages resets the probability.
+	now = now.Add(time.Minute)
+	assert.Equal(t, 10, countSelected(t, s, 20, content))
+}
+
+func TestSampleTag(t *testing.T) {
+	s := newTestProcessor(t, `
+ratio: 0.5
+action: tag
+metadata_key: keep
+`)
+
+	values := []float64{0.1, 0.9}
+	s.randFloat = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	for _, exp := range []bool{true, false} {
+		batch, err := s.Process(t.Context(), service.NewMessage([]byte("hello")))
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		v, exists := batch[0].MetaGetMut("keep")
+		require.True(t, exists)
+		assert.Equal(t, exp, v)
+	}
+}
+
+func TestSampleConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`ratio: 1.5`,
+		`mode: hash`,
+		`mode: adaptive`,
+		`
+mode: adaptive
+target_rate: 0
+`,
+	} {
+		pConf, err := sampleProcessorConfig().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = newSampleProcessorFromConfig(pConf, service.MockResources())
+		require.Error(t, err, conf)
+	}
+}
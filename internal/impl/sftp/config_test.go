@@ -139,6 +139,20 @@ sftp:
 `,
 			lintErr: `(5,1) both private_key and private_key_file can't be set simultaneously`,
 		},
+		{
+			name: "conflicting post actions",
+			conf: `
+sftp:
+  address: localhost:22
+  credentials:
+    username: blobfish
+    password: secret
+  paths: [ /upload/*.txt ]
+  delete_on_finish: true
+  rename_suffix: .done
+`,
+			lintErr: `(3,1) delete_on_finish can't be set alongside move_on_finish or rename_suffix`,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"sync"
 	"time"
 
@@ -35,11 +36,14 @@ const (
 	siFieldMaxSFTPSessions     = "max_sftp_sessions"
 	siFieldPaths               = "paths"
 	siFieldDeleteOnFinish      = "delete_on_finish"
+	siFieldMoveOnFinish        = "move_on_finish"
+	siFieldRenameSuffix        = "rename_suffix"
 	siFieldWatcher             = "watcher"
 	siFieldWatcherEnabled      = "enabled"
 	siFieldWatcherMinimumAge   = "minimum_age"
 	siFieldWatcherPollInterval = "poll_interval"
 	siFieldWatcherCache        = "cache"
+	siFieldWatcherStablePeriod = "stable_period"
 )
 
 func sftpInputSpec() *service.ConfigSpec {
@@ -75,6 +79,18 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 				Description("Whether to delete files from the server once they are processed.").
 				Advanced().
 				Default(false),
+			service.NewStringField(siFieldMoveOnFinish).
+				Description("An optional directory to move files to once they are processed, which must exist on the server.").
+				Example("/processed").
+				Advanced().
+				Version("4.62.0").
+				Default(""),
+			service.NewStringField(siFieldRenameSuffix).
+				Description("An optional suffix to append to the names of files once they are processed, which can be combined with `"+siFieldMoveOnFinish+"`. When files are renamed in place make sure the suffix is not matched by the target `"+siFieldPaths+"`.").
+				Example(".done").
+				Advanced().
+				Version("4.62.0").
+				Default(""),
 			service.NewObjectField(siFieldWatcher,
 				service.NewBoolField(siFieldWatcherEnabled).
 					Description("Whether file watching is enabled.").
@@ -83,6 +99,11 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 					Description("The minimum period of time since a file was last updated before attempting to consume it. Increasing this period decreases the likelihood that a file will be consumed whilst it is still being written to.").
 					Default("1s").
 					Examples("10s", "1m", "10m"),
+				service.NewDurationField(siFieldWatcherStablePeriod).
+					Description("The period of time for which the size and modification time of a file must remain unchanged between polls before attempting to consume it. Unlike `"+siFieldWatcherMinimumAge+"` this does not depend on the clock of the server, which makes it better suited for detecting uploads that are still in progress. Set to `0s` in order to disable this check.").
+					Default("0s").
+					Examples("30s", "5m").
+					Version("4.62.0"),
 				service.NewDurationField(siFieldWatcherPollInterval).
 					Description("The interval between each attempt to scan the target paths for new files.").
 					Default("1s").
@@ -92,7 +113,31 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 					Default(""),
 			).Description("An experimental mode whereby the input will periodically scan the target paths for new files and consume them, when all files are consumed the input will continue polling for new files.").
 				Version("3.42.0"),
-		)
+		).
+		LintRule(`root = if this.delete_on_finish.or(false) && (this.move_on_finish.or("") != "" || this.rename_suffix.or("") != "") { "delete_on_finish can't be set alongside move_on_finish or rename_suffix" }`).
+		Example("Partner drop ingestion", "Watch a directory that partners upload files to, consuming each file once it has stopped changing for a minute, moving it to an archive directory afterwards and remembering which files were consumed across restarts.", `
+input:
+  sftp:
+    address: sftp.partner.example.com:22
+    credentials:
+      username: acme
+      private_key_file: ./id_ed25519
+    paths: [ /incoming/*.csv ]
+    scanner:
+      csv: {}
+    move_on_finish: /archive
+    watcher:
+      enabled: true
+      poll_interval: 10s
+      stable_period: 1m
+      cache: processed_files
+
+cache_resources:
+  - label: processed_files
+    redis:
+      url: redis://localhost:6379
+      prefix: sftp_partner_
+`)
 }
 
 func init() {
@@ -121,11 +166,14 @@ type sftpReader struct {
 	sshConfig      *ssh.ClientConfig
 	scannerCtor    codec.DeprecatedFallbackCodec
 	deleteOnFinish bool
+	moveOnFinish   string
+	renameSuffix   string
 
 	watcherEnabled      bool
 	watcherCache        string
 	watcherPollInterval time.Duration
 	watcherMinAge       time.Duration
+	watcherStablePeriod time.Duration
 
 	stateLock       sync.Mutex
 	scanner         codec.DeprecatedFallbackStream
@@ -162,6 +210,15 @@ func newSFTPReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources)
 	if s.deleteOnFinish, err = conf.FieldBool(siFieldDeleteOnFinish); err != nil {
 		return
 	}
+	if s.moveOnFinish, err = conf.FieldString(siFieldMoveOnFinish); err != nil {
+		return
+	}
+	if s.renameSuffix, err = conf.FieldString(siFieldRenameSuffix); err != nil {
+		return
+	}
+	if s.deleteOnFinish && (s.moveOnFinish != "" || s.renameSuffix != "") {
+		return nil, fmt.Errorf("field %v cannot be set alongside %v or %v", siFieldDeleteOnFinish, siFieldMoveOnFinish, siFieldRenameSuffix)
+	}
 
 	{
 		wConf := conf.Namespace(siFieldWatcher)
@@ -175,6 +232,9 @@ func newSFTPReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources)
 			if s.watcherMinAge, err = wConf.FieldDuration(siFieldWatcherMinimumAge); err != nil {
 				return
 			}
+			if s.watcherStablePeriod, err = wConf.FieldDuration(siFieldWatcherStablePeriod); err != nil {
+				return
+			}
 			if !mgr.HasCache(s.watcherCache) {
 				return nil, fmt.Errorf("cache resource %q was not found", s.watcherCache)
 			}
@@ -227,6 +287,10 @@ func (s *sftpReader) Connect(ctx context.Context) error {
 			pollInterval: s.watcherPollInterval,
 			minAge:       s.watcherMinAge,
 			targetPaths:  s.paths,
+			stability: stabilityTracker{
+				period: s.watcherStablePeriod,
+				nowFn:  time.Now,
+			},
 		}
 
 		return nil
@@ -454,25 +518,32 @@ func (s *sftpReader) initScanner(ctx context.Context) (codec.DeprecatedFallbackS
 	}
 }
 
-func (s *sftpReader) newCodecAckFn(client *sftp.Client, path string) service.AckFunc {
+func (s *sftpReader) newCodecAckFn(client *sftp.Client, filePath string) service.AckFunc {
 	return func(ctx context.Context, aErr error) error {
-		if err := s.pathProvider.Ack(ctx, path, aErr); err != nil {
-			s.log.With("error", err).Warnf("Failed to acknowledge path: %s", path)
+		if err := s.pathProvider.Ack(ctx, filePath, aErr); err != nil {
+			s.log.With("error", err).Warnf("Failed to acknowledge path: %s", filePath)
 		}
-		if aErr != nil {
+		if aErr != nil || s.sshClient == nil {
 			return nil
 		}
 
 		if s.deleteOnFinish {
-			if s.sshClient == nil {
-				return nil
+			if err := client.Remove(filePath); err != nil {
+				return fmt.Errorf("failed to remove file %q: %w", filePath, err)
 			}
+			return nil
+		}
 
-			if err := client.Remove(path); err != nil {
-				return fmt.Errorf("failed to remove file %q: %w", path, err)
+		if s.moveOnFinish != "" || s.renameSuffix != "" {
+			dir, name := path.Split(filePath)
+			if s.moveOnFinish != "" {
+				dir = s.moveOnFinish
+			}
+			target := path.Join(dir, name+s.renameSuffix)
+			if err := client.PosixRename(filePath, target); err != nil {
+				return fmt.Errorf("failed to move file %q to %q: %w", filePath, target, err)
 			}
 		}
-
 		return nil
 	}
 }
@@ -506,6 +577,7 @@ type watcherPathProvider struct {
 	pollInterval time.Duration
 	minAge       time.Duration
 	targetPaths  []string
+	stability    stabilityTracker
 
 	expandedPaths []string
 	nextPoll      time.Time
@@ -544,6 +616,14 @@ func (w *watcherPathProvider) findNewPaths(ctx context.Context) error {
 			return
 		}
 		defer w.clientPool.Release(client)
+
+		seen := map[string]struct{}{}
+		defer func() {
+			if ctx.Err() == nil {
+				w.stability.prune(seen)
+			}
+		}()
+
 		for _, p := range w.targetPaths {
 			select {
 			case <-ctx.Done():
@@ -572,6 +652,10 @@ func (w *watcherPathProvider) findNewPaths(ctx context.Context) error {
 				if time.Since(info.ModTime()) < w.minAge {
 					continue
 				}
+				seen[path] = struct{}{}
+				if !w.stability.observe(path, info.Size(), info.ModTime()) {
+					continue
+				}
 
 				// We process it if the marker is a pending symbol (!) and we're
 				// polling for the first time, or if the path isn't found in the
@@ -208,6 +208,80 @@ cache_resources:
 	}, time.Second*10, time.Millisecond*100)
 }
 
+func TestIntegrationSFTPMoveOnFinish(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	emulator := runEmulator(t)
+
+	require.NoError(t, emulator.client.MkdirAll("/upload"))
+	require.NoError(t, emulator.client.MkdirAll("/archive"))
+
+	writeSFTPFile(t, emulator.client, "/upload/1.txt", "data-1")
+	writeSFTPFile(t, emulator.client, "/upload/2.txt", "data-2")
+
+	config := `
+output:
+  drop: {}
+
+input:
+  sftp:
+    address: $VAR1
+    paths:
+      - /upload/*.txt
+    credentials:
+      username: $VAR2
+      password: $VAR3
+      host_public_key: $VAR4
+    scanner:
+      to_the_end: {}
+    move_on_finish: /archive
+    rename_suffix: .done
+    watcher:
+      enabled: true
+      poll_interval: 100ms
+      stable_period: 200ms
+      cache: files_memory
+
+cache_resources:
+  - label: files_memory
+    memory:
+      default_ttl: 900s
+`
+	config = strings.NewReplacer(
+		"$VAR1", emulator.address,
+		"$VAR2", sftpUsername,
+		"$VAR3", sftpPassword,
+		"$VAR4", emulator.hostKey,
+	).Replace(config)
+
+	builder := service.NewStreamBuilder()
+	require.NoError(t, builder.SetYAML(config))
+	stream, err := builder.Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	runErr := make(chan error)
+	go func() { runErr <- stream.Run(ctx) }()
+	defer func() {
+		cancel()
+		err := <-runErr
+		if err != context.Canceled {
+			require.NoError(t, err, "stream.Run() failed")
+		}
+	}()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		files, err := emulator.client.Glob("/upload/*")
+		assert.NoError(c, err)
+		assert.Empty(c, files)
+
+		files, err = emulator.client.Glob("/archive/*")
+		assert.NoError(c, err)
+		assert.ElementsMatch(c, []string{"/archive/1.txt.done", "/archive/2.txt.done"}, files)
+	}, time.Second*10, time.Millisecond*100)
+}
+
 type emulator struct {
 	client  *sftp.Client
 	address string
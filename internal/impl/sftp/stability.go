@@ -0,0 +1,64 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftp
+
+import (
+	"time"
+)
+
+type fileObservation struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+}
+
+// stabilityTracker determines whether files have stopped changing by
+// comparing their size and modification time across polls, which avoids
+// consuming files that are still being uploaded without relying on the clock
+// of the server.
+type stabilityTracker struct {
+	period time.Duration
+	nowFn  func() time.Time
+	files  map[string]fileObservation
+}
+
+// observe records the current size and modification time of a file and
+// returns true if neither has changed for at least the configured period.
+func (s *stabilityTracker) observe(path string, size int64, modTime time.Time) bool {
+	if s.period <= 0 {
+		return true
+	}
+	if s.files == nil {
+		s.files = map[string]fileObservation{}
+	}
+
+	now := s.nowFn()
+	obs, exists := s.files[path]
+	if !exists || obs.size != size || !obs.modTime.Equal(modTime) {
+		s.files[path] = fileObservation{size: size, modTime: modTime, since: now}
+		return false
+	}
+	return now.Sub(obs.since) >= s.period
+}
+
+// prune forgets about files that were not seen during the latest poll so that
+// the tracker does not grow unbounded as files are consumed and removed.
+func (s *stabilityTracker) prune(seen map[string]struct{}) {
+	for path := range s.files {
+		if _, exists := seen[path]; !exists {
+			delete(s.files, path)
+		}
+	}
+}
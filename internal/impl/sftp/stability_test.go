@@ -0,0 +1,54 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStabilityTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	modTime := time.Unix(500, 0)
+	s := stabilityTracker{
+		period: time.Minute,
+		nowFn:  func() time.Time { return now },
+	}
+
+	assert.False(t, s.observe("/a.csv", 10, modTime), "first sighting")
+
+	now = now.Add(30 * time.Second)
+	assert.False(t, s.observe("/a.csv", 10, modTime), "within the period")
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, s.observe("/a.csv", 10, modTime), "unchanged for the period")
+
+	now = now.Add(time.Second)
+	assert.False(t, s.observe("/a.csv", 20, modTime), "size changed")
+
+	now = now.Add(time.Minute)
+	assert.True(t, s.observe("/a.csv", 20, modTime))
+
+	now = now.Add(time.Second)
+	assert.False(t, s.observe("/a.csv", 20, modTime.Add(time.Second)), "mod time changed")
+
+	s.prune(map[string]struct{}{})
+	assert.Empty(t, s.files)
+
+	disabled := stabilityTracker{nowFn: time.Now}
+	assert.True(t, disabled.observe("/a.csv", 10, modTime))
+}
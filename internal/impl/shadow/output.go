@@ -0,0 +1,249 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadow
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const (
+	soFieldOutput      = "output"
+	soFieldShadow      = "shadow"
+	soFieldSampleRatio = "sample_ratio"
+	soFieldMaxInFlight = "shadow_max_in_flight"
+	soFieldTimeout     = "shadow_timeout"
+
+	resultMatch       = "match"
+	resultPrimaryOnly = "primary_only"
+	resultShadowOnly  = "shadow_only"
+	resultBothFailed  = "both_failed"
+	resultDropped     = "dropped"
+)
+
+func shadowOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Writes messages to a primary output and mirrors them asynchronously to a shadow output, without the shadow output affecting the delivery of messages to the primary output.").
+		Description(`
+Each batch is written to the primary `+"`"+soFieldOutput+"`"+`, and a copy of the batch is written to the `+"`"+soFieldShadow+"`"+` output in the background. The outcome of a write only depends on the primary output: errors of the shadow output are never propagated, and writes to the primary output never wait for the shadow output. When `+"`"+soFieldMaxInFlight+"`"+` writes to the shadow output are already pending further batches aren't mirrored, which prevents a slow shadow output from accumulating an unbounded backlog.
+
+This makes it possible to validate a new sink with production traffic before cutting over to it.
+
+== Metrics
+
+The outcome of each mirrored batch is counted by the metric `+"`shadow_batches`"+` with the label `+"`result`"+`, which is one of:
+
+- `+"`"+resultMatch+"`"+`: Both outputs succeeded.
+- `+"`"+resultPrimaryOnly+"`"+`: The primary output succeeded and the shadow output failed.
+- `+"`"+resultShadowOnly+"`"+`: The shadow output succeeded and the primary output failed.
+- `+"`"+resultBothFailed+"`"+`: Both outputs failed.
+- `+"`"+resultDropped+"`"+`: The batch wasn't mirrored as too many writes to the shadow output were pending.
+
+The latency of writes is measured by the metric `+"`shadow_latency_ns`"+` with the label `+"`output`"+`, which is either `+"`primary`"+` or `+"`shadow`"+`.`).
+		Fields(
+			service.NewOutputField(soFieldOutput).
+				Description("The primary output, which determines the outcome of each write."),
+			service.NewOutputField(soFieldShadow).
+				Description("The shadow output to mirror batches to."),
+			service.NewFloatField(soFieldSampleRatio).
+				Description("The proportion of batches, between 0 and 1, to mirror to the shadow output.").
+				Default(1.0),
+			service.NewIntField(soFieldMaxInFlight).
+				Description("The maximum number of pending writes to the shadow output, beyond which batches aren't mirrored.").
+				Default(64).
+				Advanced(),
+			service.NewDurationField(soFieldTimeout).
+				Description("The maximum period to wait for a write to the shadow output.").
+				Default("30s").
+				Advanced(),
+		).
+		Example("Validate a new cluster", "Mirror a tenth of the traffic to a new Kafka cluster while the existing cluster continues to receive all messages.", `
+output:
+  shadow:
+    sample_ratio: 0.1
+    output:
+      kafka_franz:
+        seed_brokers: [ old-cluster:9092 ]
+        topic: events
+    shadow:
+      kafka_franz:
+        seed_brokers: [ new-cluster:9092 ]
+        topic: events
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"shadow", shadowOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newShadowOutputFromConfig(conf, mgr)
+			maxInFlight = 64
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type shadowOutput struct {
+	log      *service.Logger
+	mResults *service.MetricCounter
+	mLatency *service.MetricTimer
+
+	primary     wrapper.BatchWriter
+	shadow      wrapper.BatchWriter
+	sampleRatio float64
+	timeout     time.Duration
+	randFloat   func() float64
+
+	// slots holds a token for each pending write to the shadow output.
+	slots        chan struct{}
+	pending      sync.WaitGroup
+	shadowCtx    context.Context
+	shadowCancel context.CancelFunc
+}
+
+func newShadowOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*shadowOutput, error) {
+	o := &shadowOutput{
+		log:       mgr.Logger(),
+		mResults:  mgr.Metrics().NewCounter("shadow_batches", "result"),
+		mLatency:  mgr.Metrics().NewTimer("shadow_latency_ns", "output"),
+		randFloat: rand.Float64,
+	}
+
+	var err error
+	if o.sampleRatio, err = conf.FieldFloat(soFieldSampleRatio); err != nil {
+		return nil, err
+	}
+	if o.sampleRatio < 0 || o.sampleRatio > 1 {
+		return nil, errors.New("sample_ratio must be between zero and one")
+	}
+	maxInFlight, err := conf.FieldInt(soFieldMaxInFlight)
+	if err != nil {
+		return nil, err
+	}
+	if maxInFlight <= 0 {
+		return nil, errors.New("shadow_max_in_flight must be greater than zero")
+	}
+	o.slots = make(chan struct{}, maxInFlight)
+	if o.timeout, err = conf.FieldDuration(soFieldTimeout); err != nil {
+		return nil, err
+	}
+	if o.primary, err = conf.FieldOutput(soFieldOutput); err != nil {
+		return nil, err
+	}
+	if o.shadow, err = conf.FieldOutput(soFieldShadow); err != nil {
+		return nil, err
+	}
+
+	o.shadowCtx, o.shadowCancel = context.WithCancel(context.Background())
+	return o, nil
+}
+
+func (o *shadowOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *shadowOutput) sampled() bool {
+	if o.sampleRatio >= 1 {
+		return true
+	}
+	return o.randFloat() < o.sampleRatio
+}
+
+func (o *shadowOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	var primaryRes chan error
+	if o.sampled() {
+		select {
+		case o.slots <- struct{}{}:
+			primaryRes = make(chan error, 1)
+			o.pending.Add(1)
+			go o.writeShadow(batch.Copy(), primaryRes)
+		default:
+			o.mResults.Incr(1, resultDropped)
+		}
+	}
+
+	start := time.Now()
+	err := o.primary.WriteBatch(ctx, batch)
+	o.mLatency.Timing(time.Since(start).Nanoseconds(), "primary")
+
+	if primaryRes != nil {
+		primaryRes <- err
+	}
+	return err
+}
+
+func (o *shadowOutput) writeShadow(batch service.MessageBatch, primaryRes <-chan error) {
+	defer func() {
+		<-o.slots
+		o.pending.Done()
+	}()
+
+	ctx, done := context.WithTimeout(o.shadowCtx, o.timeout)
+	defer done()
+
+	start := time.Now()
+	shadowErr := o.shadow.WriteBatch(ctx, batch)
+	o.mLatency.Timing(time.Since(start).Nanoseconds(), "shadow")
+
+	primaryErr := <-primaryRes
+
+	var result string
+	switch {
+	case primaryErr == nil && shadowErr == nil:
+		result = resultMatch
+	case primaryErr == nil:
+		result = resultPrimaryOnly
+	case shadowErr == nil:
+		result = resultShadowOnly
+	default:
+		result = resultBothFailed
+	}
+	if shadowErr != nil {
+		o.log.Debugf("Failed to write batch to shadow output: %v", shadowErr)
+	}
+	o.mResults.Incr(1, result)
+}
+
+func (o *shadowOutput) Close(ctx context.Context) error {
+	err := o.primary.Close(ctx)
+
+	// Pending writes to the shadow output are given until the deadline of the
+	// context to finish, after which they're abandoned.
+	waitDone := make(chan struct{})
+	go func() {
+		o.pending.Wait()
+		close(waitDone)
+	}()
+	select {
+	case <-waitDone:
+	case <-ctx.Done():
+		o.shadowCancel()
+		<-waitDone
+	}
+	o.shadowCancel()
+
+	return errors.Join(err, o.shadow.Close(ctx))
+}
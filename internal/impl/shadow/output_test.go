@@ -0,0 +1,154 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeWriter struct {
+	mut      sync.Mutex
+	err      error
+	block    chan struct{}
+	contents []string
+}
+
+func (f *fakeWriter) WriteBatch(ctx context.Context, b service.MessageBatch) error {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	for _, msg := range b {
+		content, _ := msg.AsBytes()
+		f.contents = append(f.contents, string(content))
+	}
+	return nil
+}
+
+func (f *fakeWriter) written() []string {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return append([]string(nil), f.contents...)
+}
+
+func (*fakeWriter) Close(context.Context) error {
+	return nil
+}
+
+func newTestOutput(t *testing.T, conf string, primary, shadow *fakeWriter) *shadowOutput {
+	t.Helper()
+
+	pConf, err := shadowOutputConfig().ParseYAML(conf+`
+output:
+  drop: {}
+shadow:
+  drop: {}
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newShadowOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	o.primary, o.shadow = primary, shadow
+	return o
+}
+
+func TestShadowOutputIsolation(t *testing.T) {
+	primary := &fakeWriter{}
+	shadow := &fakeWriter{err: errors.New("nope"), block: make(chan struct{})}
+	o := newTestOutput(t, `shadow_max_in_flight: 1`, primary, shadow)
+
+	// The primary write neither waits for nor fails due to the shadow output.
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("a"))}))
+
+	// The shadow write of the first batch is still pending, and so the second
+	// batch isn't mirrored.
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("b"))}))
+	assert.Equal(t, []string{"a", "b"}, primary.written())
+
+	close(shadow.block)
+	require.NoError(t, o.Close(t.Context()))
+	assert.Empty(t, shadow.written())
+}
+
+func TestShadowOutputMirrors(t *testing.T) {
+	primary, shadow := &fakeWriter{}, &fakeWriter{}
+	o := newTestOutput(t, ``, primary, shadow)
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{
+		service.NewMessage([]byte("a")),
+		service.NewMessage([]byte("b")),
+	}))
+
+	primary.err = errors.New("primary failed")
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("c"))})
+	require.ErrorContains(t, err, "primary failed")
+
+	require.NoError(t, o.Close(t.Context()))
+	assert.Equal(t, []string{"a", "b"}, primary.written())
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, shadow.written())
+}
+
+func TestShadowOutputSampling(t *testing.T) {
+	primary, shadow := &fakeWriter{}, &fakeWriter{}
+	o := newTestOutput(t, `sample_ratio: 0.5`, primary, shadow)
+
+	values := []float64{0.1, 0.9, 0.4}
+	o.randFloat = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	for _, c := range []string{"a", "b", "c"} {
+		require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(c))}))
+	}
+
+	require.NoError(t, o.Close(t.Context()))
+	assert.Equal(t, []string{"a", "b", "c"}, primary.written())
+	assert.ElementsMatch(t, []string{"a", "c"}, shadow.written())
+}
+
+func TestShadowOutputCloseAbandonsPending(t *testing.T) {
+	primary := &fakeWriter{}
+	shadow := &fakeWriter{block: make(chan struct{})}
+	o := newTestOutput(t, ``, primary, shadow)
+
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte("a"))}))
+
+	ctx, done := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer done()
+	require.NoError(t, o.Close(ctx))
+	assert.Empty(t, shadow.written())
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/shard"
+)
+
+const (
+	spFieldKey = "key"
+)
+
+func shardProcessorConfig() *service.ConfigSpec {
+	fields := shard.GroupFields()
+	// The key is documented after the cache and group it's partitioned by.
+	fields = slices.Insert(fields, 2, service.NewInterpolatedStringField(spFieldKey).
+		Description("An interpolated string yielding the key of the work unit of each message, where all messages with the same key are kept by the same instance.").
+		Examples(`${! meta("s3_key") }`, `${! this.customer_id }`))
+
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.62.0").
+		Summary("Partitions work amongst the instances of a group by the key of each message, dropping the messages of keys that are owned by other instances.").
+		Description(`
+Inputs such as S3 prefix scans, SQL queries or schema registry subjects don't balance work amongst several instances the way consumer groups do, and so each instance consumes the same work. When this processor is placed within the processors of such an input, each instance only keeps the messages of the keys it owns and drops the rest, which allows the processing and delivery of work to be scaled horizontally.
+
+The instances sharing a `+"`group`"+` register themselves as members within a xref:components:caches/about.adoc[cache resource], which must be shared by all instances and support TTLs and atomic adds, such as `+"`redis`"+`. Each instance renews its membership every `+"`heartbeat_interval`"+`, and instances that stop renewing are removed from the group once their `+"`member_ttl`"+` passes. Each key is owned by a single member chosen by rendezvous hashing, and so when members join or leave only the keys of the changed members move.
+
+Messages are processed once the first heartbeat succeeds. When heartbeats fail for longer than the `+"`member_ttl`"+` the ownership of keys is unknown, and messages are flagged as failed rather than dropped, which allows them to be handled with xref:configuration:error_handling.adoc[error handling].
+
+== Delivery guarantees
+
+Every instance still reads all of the work from the input, and dropped messages are acknowledged. Inputs must therefore be configured so that acknowledging a message doesn't remove it for the other instances, for example an `+"`aws_s3`"+` input must not delete objects. The `+"`aws_s3`"+` input also supports partitioning the objects of a bucket scan with its own `+"`shard`"+` field, which skips the objects owned by other instances before they're downloaded.
+
+The members of a group are observed by each instance at its own heartbeat, and so after the members change instances may disagree on the owner of the keys that moved until all of them observe the change. For twice the `+"`heartbeat_interval`"+` after an instance observes a change, messages of keys it doesn't own are flagged as failed rather than dropped, and so they can be rejected with a `+"`reject_errored`"+` output in order to be delivered again once the members settle, rather than being lost. Messages of keys that moved may still be kept by two instances during this period.
+
+When an instance leaves the group the keys it owned are dropped by the other instances until they observe that it left, which is up to a `+"`heartbeat_interval`"+` when it shuts down cleanly and up to its `+"`member_ttl`"+` otherwise. This processor is therefore best suited to inputs that repeat their work, such as periodic scans, in combination with deduplication of the results.`).
+		Fields(fields...).
+		Example("Scale an S3 scan", "Process the objects of a bucket with several instances, where each object is processed by one instance.", `
+input:
+  aws_s3:
+    bucket: invoices
+    prefix: 2025/
+    scanner:
+      to_the_end: {}
+  processors:
+    - shard:
+        cache: members
+        group: invoices
+        key: ${! meta("s3_key") }
+
+output:
+  reject_errored:
+    aws_s3:
+      bucket: processed
+      path: ${! meta("s3_key") }
+
+cache_resources:
+  - label: members
+    redis:
+      url: redis://redis:6379
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"shard", shardProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newShardProcessorFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type shardProcessor struct {
+	key   *service.InterpolatedString
+	group *shard.Group
+}
+
+func newShardProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*shardProcessor, error) {
+	key, err := conf.FieldInterpolatedString(spFieldKey)
+	if err != nil {
+		return nil, err
+	}
+	group, err := shard.NewGroupFromParsed(conf, mgr)
+	if err != nil {
+		return nil, err
+	}
+	return &shardProcessor{key: key, group: group}, nil
+}
+
+func (s *shardProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	key, err := s.key.TryString(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key: %w", err)
+	}
+	owned, settled, err := s.group.Owns(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		if !settled {
+			return nil, fmt.Errorf("owner of key %v is unknown while the members of group %v are changing", key, s.group.Name())
+		}
+		return nil, nil
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (s *shardProcessor) Close(ctx context.Context) error {
+	return s.group.Close(ctx)
+}
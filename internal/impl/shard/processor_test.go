@@ -0,0 +1,168 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestProcessor(t *testing.T, mgr *service.Resources, identity string) *shardProcessor {
+	t.Helper()
+
+	pConf, err := shardProcessorConfig().ParseYAML(`
+cache: members
+group: test
+key: ${! content() }
+identity: `+identity+`
+heartbeat_interval: 20ms
+member_ttl: 1s
+`, nil)
+	require.NoError(t, err)
+
+	s, err := newShardProcessorFromConfig(pConf, mgr)
+	require.NoError(t, err)
+	return s
+}
+
+func kept(t *testing.T, s *shardProcessor, n int) map[string]bool {
+	t.Helper()
+
+	keys := map[string]bool{}
+	for i := range n {
+		batch, err := s.Process(t.Context(), service.NewMessage(fmt.Appendf(nil, "key%v", i)))
+		require.NoError(t, err)
+		for _, msg := range batch {
+			content, _ := msg.AsBytes()
+			keys[string(content)] = true
+		}
+	}
+	return keys
+}
+
+// waitSettled waits until each processor observes n members and considers
+// them settled.
+func waitSettled(t *testing.T, procs []*shardProcessor, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		for _, p := range procs {
+			members, err := p.group.Members()
+			if err != nil || len(members) != n {
+				return false
+			}
+			if _, settled, err := p.group.Owns(t.Context(), ""); err != nil || !settled {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestShardProcessorPartitions(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("members"))
+
+	procs := []*shardProcessor{
+		newTestProcessor(t, mgr, "a"),
+		newTestProcessor(t, mgr, "b"),
+		newTestProcessor(t, mgr, "c"),
+	}
+	for _, p := range procs[1:] {
+		t.Cleanup(func() {
+			_ = p.Close(context.Background())
+		})
+	}
+
+	waitSettled(t, procs, 3)
+
+	// Each key is kept by exactly one member.
+	owners := map[string]int{}
+	for _, p := range procs {
+		keys := kept(t, p, 300)
+		assert.InDelta(t, 100, len(keys), 40)
+		for k := range keys {
+			owners[k]++
+		}
+	}
+	assert.Len(t, owners, 300)
+	for k, n := range owners {
+		assert.Equal(t, 1, n, k)
+	}
+
+	// The keys of a member that leaves are taken over by the others.
+	before := kept(t, procs[1], 300)
+	require.NoError(t, procs[0].Close(t.Context()))
+	waitSettled(t, procs[1:], 2)
+
+	after := kept(t, procs[1], 300)
+	assert.Greater(t, len(after), len(before))
+	for k := range before {
+		assert.True(t, after[k], k)
+	}
+}
+
+func TestShardProcessorUnsettled(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("members"))
+
+	a := newTestProcessor(t, mgr, "a")
+	t.Cleanup(func() {
+		_ = a.Close(context.Background())
+	})
+	waitSettled(t, []*shardProcessor{a}, 1)
+
+	b := newTestProcessor(t, mgr, "b")
+	t.Cleanup(func() {
+		_ = b.Close(context.Background())
+	})
+
+	// A member that just joined flags the messages of keys it doesn't own as
+	// failed, as the other members may not have observed it yet.
+	require.Eventually(t, func() bool {
+		members, err := b.group.Members()
+		return err == nil && len(members) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var errored, kept int
+	for i := range 100 {
+		batch, err := b.Process(t.Context(), service.NewMessage(fmt.Appendf(nil, "key%v", i)))
+		if err != nil {
+			assert.ErrorContains(t, err, "members of group test are changing")
+			errored++
+			continue
+		}
+		kept += len(batch)
+	}
+	assert.Positive(t, errored)
+	assert.Equal(t, 100, errored+kept)
+
+	// Once settled the messages of keys owned by other members are dropped.
+	waitSettled(t, []*shardProcessor{a, b}, 2)
+	dropped := 0
+	for i := range 100 {
+		batch, err := b.Process(t.Context(), service.NewMessage(fmt.Appendf(nil, "key%v", i)))
+		require.NoError(t, err)
+		if len(batch) == 0 {
+			dropped++
+		}
+	}
+	assert.Equal(t, errored, dropped)
+}
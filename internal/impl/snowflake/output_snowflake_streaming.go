@@ -20,6 +20,8 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 
@@ -157,9 +159,10 @@ NOTE: There is a limit of 10,000 streams per table - if using more than 10k stre
 			service.NewInterpolatedStringField(ssoFieldChannelName).
 				Description(`The channel name to use.
 Duplicate channel names will result in errors and prevent multiple instances of Redpanda Connect from writing at the same time.
-Note that batches are assumed to all contain messages for the same channel, so this interpolation is only executed on the first
-message in each batch. It's recommended to batch at the input level to ensure that batches contain messages for the same channel
-if using an input that is partitioned (such as an Apache Kafka topic).
+This interpolation is executed for each message, and batches containing messages for multiple channels are split so that each channel
+receives its own rows, with the channels of a batch being written to in parallel. When combined with an `+"`"+ssoFieldOffsetToken+"`"+` this allows
+each partition of a partitioned input (such as an Apache Kafka topic) to be written to its own channel with its own offset, giving exactly-once
+delivery without requiring batches to be formed at the input level.
 
 This option is mutually exclusive with `+"`"+ssoFieldChannelPrefix+"`"+`.
 
@@ -1008,10 +1011,27 @@ func (*snowpipeIndexedOutput) Connect(context.Context) error {
 }
 
 func (o *snowpipeIndexedOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
-	channelName, err := batch.TryInterpolatedString(0, o.channelName)
+	channelBatches, err := splitBatchByChannel(batch, o.channelName)
 	if err != nil {
-		return fmt.Errorf("error executing %s: %w", ssoFieldChannelName, err)
+		return err
+	}
+	if len(channelBatches) == 1 {
+		return o.writeChannelBatch(ctx, channelBatches[0].name, channelBatches[0].batch)
+	}
+	// Each channel has its own offset token, so the channels can be written to
+	// in parallel without affecting the ordering guarantees within a channel.
+	// If any fail then the whole batch is retried, at which point the offset
+	// tokens of the channels that succeeded filter out their messages.
+	wg, ctx := errgroup.WithContext(ctx)
+	for _, cb := range channelBatches {
+		wg.Go(func() error {
+			return o.writeChannelBatch(ctx, cb.name, cb.batch)
+		})
 	}
+	return wg.Wait()
+}
+
+func (o *snowpipeIndexedOutput) writeChannelBatch(ctx context.Context, channelName string, batch service.MessageBatch) error {
 	channel, err := o.channelPool.Acquire(ctx, channelName)
 	if err != nil {
 		return fmt.Errorf("unable to open snowflake streaming channel: %w", err)
@@ -1080,6 +1100,34 @@ func (o *snowpipeIndexedOutput) Close(context.Context) error {
 	return nil
 }
 
+type channelBatch struct {
+	name  string
+	batch service.MessageBatch
+}
+
+// splitBatchByChannel groups the messages of a batch by their channel name,
+// preserving the order of messages within each channel as well as the order
+// in which the channels first appear in the batch.
+func splitBatchByChannel(batch service.MessageBatch, channelName *service.InterpolatedString) ([]channelBatch, error) {
+	exec := batch.InterpolationExecutor(channelName)
+	var batches []channelBatch
+	indexes := map[string]int{}
+	for i, msg := range batch {
+		name, err := exec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("error executing %s: %w", ssoFieldChannelName, err)
+		}
+		idx, ok := indexes[name]
+		if !ok {
+			idx = len(batches)
+			indexes[name] = idx
+			batches = append(batches, channelBatch{name: name})
+		}
+		batches[idx].batch = append(batches[idx].batch, msg)
+	}
+	return batches, nil
+}
+
 func preprocessForExactlyOnce(
 	channel *streaming.SnowflakeIngestionChannel,
 	offsetTokenMapping *service.InterpolatedString,
@@ -11,9 +11,12 @@
 package snowflake
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
 func TestValidColumnTypeRegex(t *testing.T) {
@@ -50,3 +53,35 @@ func TestValidColumnTypeRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitBatchByChannel(t *testing.T) {
+	channelName, err := service.NewInterpolatedString(`partition-${!@kafka_partition}`)
+	require.NoError(t, err)
+
+	var batch service.MessageBatch
+	for i, partition := range []string{"1", "0", "1", "2", "0"} {
+		msg := service.NewMessage([]byte(strconv.Itoa(i)))
+		msg.MetaSetMut("kafka_partition", partition)
+		batch = append(batch, msg)
+	}
+
+	batches, err := splitBatchByChannel(batch, channelName)
+	require.NoError(t, err)
+
+	contents := map[string][]string{}
+	var names []string
+	for _, cb := range batches {
+		names = append(names, cb.name)
+		for _, msg := range cb.batch {
+			b, err := msg.AsBytes()
+			require.NoError(t, err)
+			contents[cb.name] = append(contents[cb.name], string(b))
+		}
+	}
+	require.Equal(t, []string{"partition-1", "partition-0", "partition-2"}, names)
+	require.Equal(t, map[string][]string{
+		"partition-0": {"1", "4"},
+		"partition-1": {"0", "2"},
+		"partition-2": {"3"},
+	}, contents)
+}
@@ -14,11 +14,18 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 
 	"github.com/redpanda-data/connect/v4/internal/license"
@@ -32,6 +39,13 @@ const (
 	soFieldEventSource     = "event_source"
 	soFieldEventSourceType = "event_sourcetype"
 	soFieldEventIndex      = "event_index"
+	soFieldMode            = "mode"
+	soFieldMetaMapping     = "event_metadata_mapping"
+	soFieldAck             = "indexer_ack"
+	soFieldAckEnabled      = "enabled"
+	soFieldAckChannel      = "channel"
+	soFieldAckPollInterval = "poll_interval"
+	soFieldAckTimeout      = "timeout"
 	soFieldTLS             = "tls"
 	soFieldBatching        = "batching"
 
@@ -51,7 +65,14 @@ func outputSpec() *service.ConfigSpec {
 		Version("4.30.0").
 		Categories("Services").
 		Summary(`Publishes messages to a Splunk HTTP Endpoint Collector (HEC).`).
-		Description(service.OutputPerformanceDocs(true, true)).
+		Description(`
+== Modes
+
+In the `+"`event`"+` mode messages are sent to the event endpoint, where messages that are objects with an `+"`event`"+` field are sent as they are and all other messages are sent as the `+"`event`"+` of a new object. In the `+"`raw`"+` mode the contents of messages are sent as lines to the raw endpoint, and messages of a batch with differing metadata are sent with a request each, and when some of those requests fail only the messages of the failed requests are retried.
+
+== Indexer acknowledgements
+
+When `+"`indexer_ack.enabled`"+` is `+"`true`"+` each write waits until Splunk has acknowledged that the events of the batch have been indexed, which guarantees delivery in the event of an indexer failure. The HEC token must have indexer acknowledgement enabled, and a batch that isn't acknowledged within the `+"`indexer_ack.timeout`"+` is failed and therefore sent again. In the `+"`raw`"+` mode only the messages of the requests that weren't acknowledged are sent again.`+service.OutputPerformanceDocs(true, true)).
 		Fields(
 			service.NewStringField(soFieldURL).Description("Full HTTP Endpoint Collector (HEC) URL.").Example("https://foobar.splunkcloud.com/services/collector/event"),
 			service.NewStringField(soFieldToken).Description("A bot token used for authentication.").Secret(),
@@ -60,6 +81,36 @@ func outputSpec() *service.ConfigSpec {
 			service.NewStringField(soFieldEventSource).Description("Set the source value to assign to the event data. Overrides existing source field if present.").Optional(),
 			service.NewStringField(soFieldEventSourceType).Description("Set the sourcetype value to assign to the event data. Overrides existing sourcetype field if present.").Optional(),
 			service.NewStringField(soFieldEventIndex).Description("Set the index value to assign to the event data. Overrides existing index field if present.").Optional(),
+			service.NewStringEnumField(soFieldMode, "event", "raw").
+				Description("Whether to send messages to the event or raw endpoint, which must match the path of the `url`.").
+				Default("event").
+				Version("4.62.0"),
+			service.NewBloblangField(soFieldMetaMapping).
+				Description("An optional xref:guides:bloblang/about.adoc[Bloblang mapping] that evaluates to an object of the metadata of each event, with any of the fields `host`, `source`, `sourcetype` and `index`, which take precedence over the static event fields. In the `event` mode the fields `time` and `fields` are also supported.").
+				Example(`root.index = @kafka_topic
+root.sourcetype = "_json"
+root.time = this.timestamp.ts_unix()`).
+				Optional().
+				Version("4.62.0"),
+			service.NewObjectField(soFieldAck,
+				service.NewBoolField(soFieldAckEnabled).
+					Description("Whether to wait for the indexer acknowledgement of each batch.").
+					Default(false),
+				service.NewStringField(soFieldAckChannel).
+					Description("The channel that requests are sent with, which must be a GUID. When empty a random channel is used.").
+					Default("").
+					Advanced(),
+				service.NewDurationField(soFieldAckPollInterval).
+					Description("The period of time between requests for the acknowledgement status of batches.").
+					Default("1s").
+					Advanced(),
+				service.NewDurationField(soFieldAckTimeout).
+					Description("The maximum period of time to wait for a batch to be acknowledged, after which it is failed.").
+					Default("5m").
+					Advanced(),
+			).
+				Description("Waits for Splunk indexers to acknowledge that batches have been indexed.").
+				Version("4.62.0"),
 			service.NewTLSToggledField(soFieldTLS),
 			service.NewOutputMaxInFlightField(),
 			service.NewBatchPolicyField(soFieldBatching),
@@ -115,12 +166,20 @@ func init() {
 
 type output struct {
 	url                string
+	ackURL             string
 	token              string
 	useGzipCompression bool
 	eventHost          string
 	eventSource        string
 	eventSourceType    string
 	eventIndex         string
+	raw                bool
+	metaMapping        *bloblang.Executor
+
+	ackEnabled      bool
+	ackChannel      string
+	ackPollInterval time.Duration
+	ackTimeout      time.Duration
 
 	client http.Client
 	log    *service.Logger
@@ -143,21 +202,66 @@ func outputFromParsed(pConf *service.ParsedConfig, log *service.Logger) (o *outp
 		return
 	}
 
-	if o.eventHost, err = pConf.FieldString(soFieldEventHost); err != nil {
-		return
+	if pConf.Contains(soFieldEventHost) {
+		if o.eventHost, err = pConf.FieldString(soFieldEventHost); err != nil {
+			return
+		}
 	}
 
-	if o.eventSource, err = pConf.FieldString(soFieldEventSource); err != nil {
-		return
+	if pConf.Contains(soFieldEventSource) {
+		if o.eventSource, err = pConf.FieldString(soFieldEventSource); err != nil {
+			return
+		}
+	}
+
+	if pConf.Contains(soFieldEventSourceType) {
+		if o.eventSourceType, err = pConf.FieldString(soFieldEventSourceType); err != nil {
+			return
+		}
+	}
+
+	if pConf.Contains(soFieldEventIndex) {
+		if o.eventIndex, err = pConf.FieldString(soFieldEventIndex); err != nil {
+			return
+		}
 	}
 
-	if o.eventSourceType, err = pConf.FieldString(soFieldEventSourceType); err != nil {
+	var mode string
+	if mode, err = pConf.FieldString(soFieldMode); err != nil {
 		return
 	}
+	o.raw = mode == "raw"
 
-	if o.eventIndex, err = pConf.FieldString(soFieldEventIndex); err != nil {
+	if pConf.Contains(soFieldMetaMapping) {
+		if o.metaMapping, err = pConf.FieldBloblang(soFieldMetaMapping); err != nil {
+			return
+		}
+	}
+
+	ackConf := pConf.Namespace(soFieldAck)
+	if o.ackEnabled, err = ackConf.FieldBool(soFieldAckEnabled); err != nil {
+		return
+	}
+	if o.ackChannel, err = ackConf.FieldString(soFieldAckChannel); err != nil {
+		return
+	}
+	if o.ackChannel == "" {
+		o.ackChannel = uuid.NewString()
+	} else if _, err = uuid.Parse(o.ackChannel); err != nil {
+		err = fmt.Errorf("invalid %v.%v: %w", soFieldAck, soFieldAckChannel, err)
+		return
+	}
+	if o.ackPollInterval, err = ackConf.FieldDuration(soFieldAckPollInterval); err != nil {
 		return
 	}
+	if o.ackTimeout, err = ackConf.FieldDuration(soFieldAckTimeout); err != nil {
+		return
+	}
+	if o.ackEnabled {
+		if o.ackURL, err = ackURLFromURL(o.url); err != nil {
+			return
+		}
+	}
 
 	var tlsConf *tls.Config
 	var tlsEnabled bool
@@ -181,32 +285,138 @@ func outputFromParsed(pConf *service.ParsedConfig, log *service.Logger) (o *outp
 	return
 }
 
+// ackURLFromURL returns the URL of the acknowledgement endpoint of the
+// collector that an event or raw endpoint URL belongs to.
+func ackURLFromURL(s string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse url: %w", err)
+	}
+	prefix := "/services/collector"
+	if i := strings.Index(u.Path, prefix); i >= 0 {
+		prefix = u.Path[:i] + prefix
+	}
+	u.Path = prefix + "/ack"
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
 //------------------------------------------------------------------------------
 
 func (*output) Connect(context.Context) error { return nil }
 
+// eventMeta is the metadata of an event.
+type eventMeta struct {
+	host       string
+	source     string
+	sourceType string
+	index      string
+	time       any
+	fields     any
+}
+
+func (o *output) eventMeta(exec *service.MessageBatchBloblangExecutor, i int) (eventMeta, error) {
+	meta := eventMeta{
+		host:       o.eventHost,
+		source:     o.eventSource,
+		sourceType: o.eventSourceType,
+		index:      o.eventIndex,
+	}
+	if exec == nil {
+		return meta, nil
+	}
+
+	res, err := exec.Query(i)
+	if err != nil {
+		return meta, fmt.Errorf("event metadata mapping failed: %w", err)
+	}
+	if res == nil {
+		return meta, nil
+	}
+	v, err := res.AsStructured()
+	if err != nil {
+		return meta, fmt.Errorf("event metadata mapping failed: %w", err)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return meta, fmt.Errorf("event metadata mapping returned non-object result: %T", v)
+	}
+
+	for k, target := range map[string]*string{
+		"host":       &meta.host,
+		"source":     &meta.source,
+		"sourcetype": &meta.sourceType,
+		"index":      &meta.index,
+	} {
+		if s, exists := obj[k]; exists && s != nil {
+			*target = bloblang.ValueToString(s)
+		}
+	}
+	meta.time = obj["time"]
+	meta.fields = obj["fields"]
+	return meta, nil
+}
+
 func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err error) {
-	header := make(http.Header)
-	header.Set("Content-Type", "application/json")
-	header.Set("Authorization", "Splunk "+o.token)
+	var exec *service.MessageBatchBloblangExecutor
+	if o.metaMapping != nil {
+		exec = b.BloblangExecutor(o.metaMapping)
+	}
 
-	var payload bytes.Buffer
-	var payloadWriter io.Writer = &payload
-	var gzipFlusher func() error
-	if o.useGzipCompression {
-		header.Set("Content-Encoding", "gzip")
-		gzipper := gzip.NewWriter(&payload)
-		payloadWriter = gzipper
-		gzipFlusher = gzipper.Close
+	var sent []sentRequest
+	if o.raw {
+		sent, err = o.writeRaw(ctx, b, exec)
+	} else {
+		sent, err = o.writeEvents(ctx, b, exec)
+	}
+
+	// A batch error means that some of the requests succeeded, and so their
+	// acknowledgements are awaited before the failed messages are returned.
+	var batchErr *service.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
 	}
-	encoder := json.NewEncoder(payloadWriter)
+	if o.ackEnabled && len(sent) > 0 {
+		unacked, ackErr := o.waitForAcks(ctx, sent)
+		if ackErr == nil {
+			return err
+		}
+		if !o.raw {
+			return ackErr
+		}
 
-	for _, msg := range b {
+		// Only the messages of requests that weren't acknowledged are marked
+		// so that the acknowledged requests aren't sent again.
+		if batchErr == nil {
+			batchErr = service.NewBatchError(b, ackErr)
+		}
+		for _, r := range unacked {
+			for _, i := range r.indexes {
+				batchErr.Failed(i, ackErr)
+			}
+		}
+		return batchErr
+	}
+	return err
+}
+
+// sentRequest is a request that was accepted by the collector, along with the
+// indexes of the messages of the batch it contained.
+type sentRequest struct {
+	ackID   int64
+	indexes []int
+}
+
+func (o *output) writeEvents(ctx context.Context, b service.MessageBatch, exec *service.MessageBatchBloblangExecutor) ([]sentRequest, error) {
+	var payload bytes.Buffer
+	encoder := json.NewEncoder(&payload)
+
+	for i, msg := range b {
 		data, err := msg.AsStructuredMut()
 		if err != nil {
 			rawData, err := msg.AsBytes()
 			if err != nil {
-				return fmt.Errorf("failed to get message bytes: %s", err)
+				return nil, fmt.Errorf("failed to get message bytes: %s", err)
 			}
 			data = map[string]any{"event": string(rawData)}
 		}
@@ -219,55 +429,250 @@ func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err er
 			dataObj = map[string]any{"event": data}
 		}
 
-		if o.eventHost != "" {
-			dataObj["host"] = o.eventHost
+		meta, err := o.eventMeta(exec, i)
+		if err != nil {
+			return nil, err
+		}
+		if meta.host != "" {
+			dataObj["host"] = meta.host
+		}
+		if meta.source != "" {
+			dataObj["source"] = meta.source
+		}
+		if meta.sourceType != "" {
+			dataObj["sourcetype"] = meta.sourceType
 		}
-		if o.eventSource != "" {
-			dataObj["source"] = o.eventSource
+		if meta.index != "" {
+			dataObj["index"] = meta.index
 		}
-		if o.eventSourceType != "" {
-			dataObj["sourcetype"] = o.eventSourceType
+		if meta.time != nil {
+			dataObj["time"] = meta.time
 		}
-		if o.eventIndex != "" {
-			dataObj["index"] = o.eventIndex
+		if meta.fields != nil {
+			dataObj["fields"] = meta.fields
 		}
 
 		err = encoder.Encode(dataObj)
 		if err != nil {
-			return fmt.Errorf("failed to marshal message to json: %s", err)
+			return nil, fmt.Errorf("failed to marshal message to json: %s", err)
+		}
+	}
+
+	ackID, err := o.send(ctx, o.url, payload.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return []sentRequest{{ackID: ackID}}, nil
+}
+
+func (o *output) writeRaw(ctx context.Context, b service.MessageBatch, exec *service.MessageBatchBloblangExecutor) ([]sentRequest, error) {
+	// Metadata of the raw endpoint is set per request, and therefore messages
+	// are grouped by their metadata.
+	type rawGroup struct {
+		meta    eventMeta
+		indexes []int
+		payload bytes.Buffer
+	}
+	var groups []*rawGroup
+	groupIndexes := map[eventMeta]int{}
+
+	for i, msg := range b {
+		meta, err := o.eventMeta(exec, i)
+		if err != nil {
+			return nil, err
+		}
+		meta.time, meta.fields = nil, nil
+
+		gi, exists := groupIndexes[meta]
+		if !exists {
+			gi = len(groups)
+			groupIndexes[meta] = gi
+			groups = append(groups, &rawGroup{meta: meta})
+		}
+
+		rawData, err := msg.AsBytes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message bytes: %s", err)
+		}
+		groups[gi].indexes = append(groups[gi].indexes, i)
+		groups[gi].payload.Write(rawData)
+		groups[gi].payload.WriteByte('\n')
+	}
+
+	sent := make([]sentRequest, 0, len(groups))
+	var batchErr *service.BatchError
+	for _, g := range groups {
+		target := o.url
+		query := url.Values{}
+		for k, v := range map[string]string{
+			"host":       g.meta.host,
+			"source":     g.meta.source,
+			"sourcetype": g.meta.sourceType,
+			"index":      g.meta.index,
+		} {
+			if v != "" {
+				query.Set(k, v)
+			}
 		}
+		if len(query) > 0 {
+			sep := "?"
+			if strings.Contains(target, "?") {
+				sep = "&"
+			}
+			target += sep + query.Encode()
+		}
+
+		ackID, err := o.send(ctx, target, g.payload.Bytes())
+		if err != nil {
+			// Only the messages of the failed request are marked so that
+			// the requests that succeeded aren't sent again.
+			if batchErr == nil {
+				batchErr = service.NewBatchError(b, err)
+			}
+			for _, i := range g.indexes {
+				batchErr.Failed(i, err)
+			}
+			continue
+		}
+		sent = append(sent, sentRequest{ackID: ackID, indexes: g.indexes})
+	}
+	if batchErr != nil {
+		return sent, batchErr
+	}
+	return sent, nil
+}
+
+// send posts a payload to the collector, returning the acknowledgement ID of
+// the request when indexer acknowledgement is enabled.
+func (o *output) send(ctx context.Context, target string, data []byte) (int64, error) {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if o.raw {
+		header.Set("Content-Type", "text/plain")
+	}
+	header.Set("Authorization", "Splunk "+o.token)
+	if o.ackEnabled {
+		header.Set("X-Splunk-Request-Channel", o.ackChannel)
 	}
 
+	var payload bytes.Buffer
 	if o.useGzipCompression {
-		if err := gzipFlusher(); err != nil {
-			return fmt.Errorf("failed to compress messages: %s", err)
+		header.Set("Content-Encoding", "gzip")
+		gzipper := gzip.NewWriter(&payload)
+		if _, err := gzipper.Write(data); err != nil {
+			return 0, fmt.Errorf("failed to compress messages: %s", err)
+		}
+		if err := gzipper.Close(); err != nil {
+			return 0, fmt.Errorf("failed to compress messages: %s", err)
 		}
+	} else {
+		payload.Write(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, &payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, &payload)
 	if err != nil {
-		return fmt.Errorf("failed to construct HTTP request: %s", err)
+		return 0, fmt.Errorf("failed to construct HTTP request: %s", err)
 	}
 	req.Header = header
 	req.ContentLength = int64(payload.Len())
 
 	resp, err := o.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute http request: %s", err)
+		return 0, fmt.Errorf("failed to execute http request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		if respData, err := httputil.DumpResponse(resp, true); err != nil {
-			return fmt.Errorf("failed to read response: %s", err)
+			return 0, fmt.Errorf("failed to read response: %s", err)
 		} else {
 			o.log.Debugf("Failed to push data to Splunk with status %d: %s", resp.StatusCode, string(respData))
 		}
 
-		return fmt.Errorf("HTTP request returned status: %d", resp.StatusCode)
+		return 0, fmt.Errorf("HTTP request returned status: %d", resp.StatusCode)
+	}
+	if !o.ackEnabled {
+		return 0, nil
 	}
 
-	return
+	var res struct {
+		AckID *int64 `json:"ackId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %s", err)
+	}
+	if res.AckID == nil {
+		return 0, errors.New("response is missing an ackId, indexer acknowledgement may be disabled for the token")
+	}
+	return *res.AckID, nil
+}
+
+// waitForAcks polls the acknowledgement endpoint until all of the given
+// requests have been indexed. When the requests aren't all acknowledged in
+// time those that weren't are returned along with the error.
+func (o *output) waitForAcks(ctx context.Context, sent []sentRequest) ([]sentRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.ackTimeout)
+	defer cancel()
+
+	pending := sent
+	for {
+		select {
+		case <-time.After(o.ackPollInterval):
+		case <-ctx.Done():
+			return pending, fmt.Errorf("timed out waiting for indexer acknowledgement of %v requests: %w", len(pending), ctx.Err())
+		}
+
+		ackIDs := make([]int64, len(pending))
+		for i, r := range pending {
+			ackIDs[i] = r.ackID
+		}
+		acked, err := o.pollAcks(ctx, ackIDs)
+		if err != nil {
+			o.log.Warnf("Failed to poll indexer acknowledgements: %v", err)
+			continue
+		}
+		var remaining []sentRequest
+		for _, r := range pending {
+			if !acked[strconv.FormatInt(r.ackID, 10)] {
+				remaining = append(remaining, r)
+			}
+		}
+		if pending = remaining; len(pending) == 0 {
+			return nil, nil
+		}
+	}
+}
+
+func (o *output) pollAcks(ctx context.Context, ackIDs []int64) (map[string]bool, error) {
+	body, err := json.Marshal(map[string]any{"acks": ackIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.ackURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+o.token)
+	req.Header.Set("X-Splunk-Request-Channel", o.ackChannel)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP request returned status: %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Acks map[string]bool `json:"acks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %s", err)
+	}
+	return res.Acks, nil
 }
 
 func (*output) Close(context.Context) error { return nil }
@@ -0,0 +1,294 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package splunk
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, conf string) *output {
+	t.Helper()
+
+	pConf, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := outputFromParsed(pConf, service.MockResources().Logger())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOutputEventsWithIndexerAck(t *testing.T) {
+	const channel = "8f5b1b6e-8f0e-4c1c-9d5c-0b5a4a0d6e1f"
+
+	var mut sync.Mutex
+	var events []string
+	var ackPolls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		assert.Equal(t, "Splunk foo", r.Header.Get("Authorization"))
+		assert.Equal(t, channel, r.Header.Get("X-Splunk-Request-Channel"))
+
+		switch r.URL.Path {
+		case "/services/collector/event":
+			assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+			gr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			dec := json.NewDecoder(gr)
+			for dec.More() {
+				var v json.RawMessage
+				require.NoError(t, dec.Decode(&v))
+				events = append(events, string(v))
+			}
+			_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":7}`))
+		case "/services/collector/ack":
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"acks":[7]}`, string(b))
+			ackPolls++
+			if ackPolls == 1 {
+				_, _ = w.Write([]byte(`{"acks":{"7":false}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"acks":{"7":true}}`))
+		default:
+			t.Errorf("unexpected path: %v", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/services/collector/event
+token: foo
+gzip: true
+event_sourcetype: _json
+event_metadata_mapping: |
+  root.index = @topic
+  root.time = this.catch({}).ts
+  root.fields = { "region": "eu" }
+indexer_ack:
+  enabled: true
+  channel: `+channel+`
+  poll_interval: 1ms
+`)
+
+	msgA := service.NewMessage([]byte(`{"ts":1700000000,"msg":"a"}`))
+	msgA.MetaSetMut("topic", "orders")
+	msgB := service.NewMessage([]byte(`not json`))
+	msgB.MetaSetMut("topic", "logs")
+	require.NoError(t, o.WriteBatch(t.Context(), service.MessageBatch{msgA, msgB}))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, 2, ackPolls)
+	require.Len(t, events, 2)
+	assert.JSONEq(t, `{"event":{"ts":1700000000,"msg":"a"},"sourcetype":"_json","index":"orders","time":1700000000,"fields":{"region":"eu"}}`, events[0])
+	assert.JSONEq(t, `{"event":"not json","sourcetype":"_json","index":"logs","fields":{"region":"eu"}}`, events[1])
+}
+
+func TestOutputRaw(t *testing.T) {
+	var mut sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		assert.Equal(t, "/services/collector/raw", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.URL.RawQuery+" "+string(b))
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/services/collector/raw
+token: foo
+mode: raw
+event_host: web-1
+event_metadata_mapping: 'root.index = @index'
+`)
+
+	var batch service.MessageBatch
+	for _, m := range []struct{ content, index string }{
+		{"first line", "a"},
+		{"second line", "b"},
+		{"third line", "a"},
+	} {
+		msg := service.NewMessage([]byte(m.content))
+		msg.MetaSetMut("index", m.index)
+		batch = append(batch, msg)
+	}
+	require.NoError(t, o.WriteBatch(t.Context(), batch))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, []string{
+		"host=web-1&index=a first line\nthird line\n",
+		"host=web-1&index=b second line\n",
+	}, requests)
+}
+
+func TestOutputRawPartialFailure(t *testing.T) {
+	var mut sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.URL.RawQuery+" "+string(b))
+		if r.URL.Query().Get("index") == "b" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/services/collector/raw
+token: foo
+mode: raw
+event_metadata_mapping: 'root.index = @index'
+`)
+
+	var batch service.MessageBatch
+	for _, m := range []struct{ content, index string }{
+		{"first line", "b"},
+		{"second line", "a"},
+		{"third line", "b"},
+	} {
+		msg := service.NewMessage([]byte(m.content))
+		msg.MetaSetMut("index", m.index)
+		batch = append(batch, msg)
+	}
+	indexer := batch.Index()
+
+	err := o.WriteBatch(t.Context(), batch)
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+
+	var failed []int
+	batchErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{0, 2}, failed)
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, []string{
+		"index=b first line\nthird line\n",
+		"index=a second line\n",
+	}, requests)
+}
+
+func TestOutputIndexerAckTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/collector/ack" {
+			_, _ = w.Write([]byte(`{"acks":{"1":false}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":1}`))
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/services/collector/event
+token: foo
+indexer_ack:
+  enabled: true
+  poll_interval: 1ms
+  timeout: 20ms
+`)
+
+	err := o.WriteBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`{"event":"a"}`))})
+	require.ErrorContains(t, err, "timed out waiting for indexer acknowledgement")
+}
+
+func TestOutputRawIndexerAckPartialTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/collector/ack" {
+			_, _ = w.Write([]byte(`{"acks":{"1":true,"2":false}}`))
+			return
+		}
+		ackID := "1"
+		if r.URL.Query().Get("index") == "b" {
+			ackID = "2"
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":` + ackID + `}`))
+	}))
+	defer srv.Close()
+
+	o := testOutput(t, `
+url: `+srv.URL+`/services/collector/raw
+token: foo
+mode: raw
+event_metadata_mapping: 'root.index = @index'
+indexer_ack:
+  enabled: true
+  poll_interval: 1ms
+  timeout: 20ms
+`)
+
+	var batch service.MessageBatch
+	for _, m := range []struct{ content, index string }{
+		{"first line", "a"},
+		{"second line", "b"},
+		{"third line", "a"},
+	} {
+		msg := service.NewMessage([]byte(m.content))
+		msg.MetaSetMut("index", m.index)
+		batch = append(batch, msg)
+	}
+	indexer := batch.Index()
+
+	err := o.WriteBatch(t.Context(), batch)
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.ErrorContains(t, err, "timed out waiting for indexer acknowledgement")
+
+	var failed []int
+	batchErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{1}, failed)
+}
+
+func TestAckURLFromURL(t *testing.T) {
+	for in, exp := range map[string]string{
+		"https://splunk:8088/services/collector/event":                "https://splunk:8088/services/collector/ack",
+		"https://splunk:8088/services/collector/raw?channel=foo":      "https://splunk:8088/services/collector/ack",
+		"https://example.com/proxy/services/collector/event/1.0":      "https://example.com/proxy/services/collector/ack",
+		"https://http-inputs-acme.splunkcloud.com/services/collector": "https://http-inputs-acme.splunkcloud.com/services/collector/ack",
+	} {
+		u, err := ackURLFromURL(in)
+		require.NoError(t, err)
+		assert.Equal(t, exp, u, in)
+	}
+}
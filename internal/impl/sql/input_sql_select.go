@@ -17,8 +17,10 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 
@@ -26,6 +28,8 @@ import (
 
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/resume"
 )
 
 func sqlSelectInputConfig() *service.ConfigSpec {
@@ -33,7 +37,13 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 		Beta().
 		Categories("Services").
 		Summary("Executes a select query and creates a message for each row received.").
-		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a xref:components:inputs/sequence.adoc[sequence] to execute).`).
+		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a xref:components:inputs/sequence.adoc[sequence] to execute).
+
+== Incremental mode
+
+When the ` + "`incremental`" + ` field is set this input instead polls the table for new rows indefinitely. Rows are selected in ascending order of a cursor column, such as a monotonically increasing ID or an update timestamp, and each poll selects rows where the cursor column is greater than or equal to the value of the last row read. The comparison is inclusive so that rows sharing the cursor value of the last row read are not missed, which means those rows are emitted again by the next poll and consumers should tolerate duplicates.
+
+The cursor value of the latest acknowledged row is stored in a cache resource, and when the input restarts it resumes from the stored value, which guarantees that each row is emitted at least once.`).
 		Field(driverField).
 		Field(dsnField).
 		Field(service.NewStringField("table").
@@ -60,6 +70,27 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 			Description("An optional suffix to append to the select query.").
 			Optional().
 			Advanced()).
+		Field(service.NewObjectField("incremental",
+			service.NewStringField("column").
+				Description("The cursor column, which must be included in the selected columns and whose values should only increase as rows are inserted or updated.").
+				Example("id").
+				Example("updated_at"),
+			service.NewStringField("cache").
+				Description("A xref:components:caches/about.adoc[cache resource] for storing the cursor value of the latest acknowledged row."),
+			service.NewStringField("cache_key").
+				Description("The key under which the cursor value is stored. Defaults to the name of the table when empty.").
+				Default(""),
+			service.NewStringField("initial_value").
+				Description("The cursor value to begin from when no cursor value has been stored. When omitted all rows of the table are consumed.").
+				Example("2024-01-01T00:00:00Z").
+				Optional(),
+			service.NewDurationField("poll_interval").
+				Description("The period of time to wait after consuming all new rows before polling the table again.").
+				Default("10s"),
+		).
+			Description("Enables incremental mode, where the table is polled for new rows indefinitely.").
+			Optional().
+			Version("4.62.0")).
 		Field(service.NewAutoRetryNacksToggleField())
 
 	for _, f := range connFields() {
@@ -83,6 +114,27 @@ input:
       root = [
         now().ts_unix() - 3600
       ]
+`,
+		).
+		Example("Stream a Table Incrementally (Snowflake)",
+			`
+Here we poll a table for rows that were inserted or updated since the last poll, storing the latest consumed update timestamp in a Redis cache so that the pipeline resumes where it left off after restarts:`,
+			`
+input:
+  sql_select:
+    driver: snowflake
+    dsn: username:${SNOWFLAKE_PASSWORD}@account/db/schema?warehouse=wh
+    table: orders
+    columns: [ '*' ]
+    incremental:
+      column: updated_at
+      cache: cursors
+      poll_interval: 1m
+
+cache_resources:
+  - label: cursors
+    redis:
+      url: redis://localhost:6379
 `,
 		)
 	return spec
@@ -113,14 +165,64 @@ type sqlSelectInput struct {
 	where       string
 	argsMapping *bloblang.Executor
 
+	incremental  *sqlSelectIncremental
+	cursor       any
+	cursorLoaded bool
+
 	connSettings *connSettings
 
+	mgr     *service.Resources
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
+type sqlSelectIncremental struct {
+	column       string
+	store        *resume.Store
+	initialValue *string
+	pollInterval time.Duration
+	tracker      *resume.Tracker[any]
+}
+
+func sqlSelectIncrementalFromParsed(conf *service.ParsedConfig, table string, replayNacks bool, mgr *service.Resources) (inc *sqlSelectIncremental, err error) {
+	inc = &sqlSelectIncremental{}
+	if inc.column, err = conf.FieldString("column"); err != nil {
+		return nil, err
+	}
+	cache, err := conf.FieldString("cache")
+	if err != nil {
+		return nil, err
+	}
+	if !mgr.HasCache(cache) {
+		return nil, fmt.Errorf("cache resource %q was not found", cache)
+	}
+	cacheKey, err := conf.FieldString("cache_key")
+	if err != nil {
+		return nil, err
+	}
+	if cacheKey == "" {
+		cacheKey = table
+	}
+	inc.store = resume.NewStore(mgr, cache, cacheKey)
+	// Rows that are rejected without being replayed are never delivered again,
+	// and so they must not hold back the stored cursor.
+	inc.tracker = resume.NewTracker(inc.store, 1024, encodeSQLCursor, resume.TrackerOptReleaseNacks(!replayNacks))
+	if conf.Contains("initial_value") {
+		initialValue, err := conf.FieldString("initial_value")
+		if err != nil {
+			return nil, err
+		}
+		inc.initialValue = &initialValue
+	}
+	if inc.pollInterval, err = conf.FieldDuration("poll_interval"); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
 func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlSelectInput, error) {
 	s := &sqlSelectInput{
+		mgr:     mgr,
 		logger:  mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
@@ -157,6 +259,16 @@ func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr *service.Resour
 		}
 	}
 
+	if conf.Contains("incremental") {
+		replayNacks, err := conf.FieldBool(service.AutoRetryNacksToggleFieldName)
+		if err != nil {
+			return nil, err
+		}
+		if s.incremental, err = sqlSelectIncrementalFromParsed(conf.Namespace("incremental"), tableStr, replayNacks, mgr); err != nil {
+			return nil, err
+		}
+	}
+
 	s.builder = squirrel.Select(columns...).From(tableStr)
 	switch s.driver {
 	case "postgres", "clickhouse":
@@ -207,29 +319,15 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 
 	s.connSettings.apply(ctx, db, s.logger)
 
-	var args []any
-	if s.argsMapping != nil {
-		var iargs any
-		if iargs, err = s.argsMapping.Query(nil); err != nil {
-			return
-		}
-
-		var ok bool
-		if args, ok = iargs.([]any); !ok {
-			err = fmt.Errorf("mapping returned non-array result: %T", iargs)
+	if s.incremental != nil && !s.cursorLoaded {
+		if err = s.loadCursor(ctx); err != nil {
 			return
 		}
 	}
 
-	queryBuilder := s.builder
-	if s.where != "" {
-		queryBuilder = queryBuilder.Where(s.where, args...)
-	}
 	var rows *sql.Rows
-	if rows, err = queryBuilder.RunWith(db).Query(); err != nil {
+	if rows, err = s.query(db); err != nil {
 		return
-	} else if err = rows.Err(); err != nil {
-		s.logger.With("err", err).Warn("unexpected error while execute raw select")
 	}
 
 	s.db = db
@@ -253,7 +351,95 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	return nil
 }
 
-func (s *sqlSelectInput) Read(context.Context) (*service.Message, service.AckFunc, error) {
+func (s *sqlSelectInput) query(db *sql.DB) (*sql.Rows, error) {
+	var args []any
+	if s.argsMapping != nil {
+		iargs, err := s.argsMapping.Query(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if args, ok = iargs.([]any); !ok {
+			return nil, fmt.Errorf("mapping returned non-array result: %T", iargs)
+		}
+	}
+
+	queryBuilder := s.builder
+	if s.where != "" {
+		queryBuilder = queryBuilder.Where(s.where, args...)
+	}
+	if s.incremental != nil {
+		if s.cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.GtOrEq{s.incremental.column: s.cursor})
+		}
+		queryBuilder = queryBuilder.OrderBy(s.incremental.column)
+	}
+
+	rows, err := queryBuilder.RunWith(db).Query()
+	if err != nil {
+		return nil, err
+	} else if err = rows.Err(); err != nil {
+		s.logger.With("err", err).Warn("unexpected error while execute raw select")
+	}
+	return rows, nil
+}
+
+func (s *sqlSelectInput) loadCursor(ctx context.Context) error {
+	cursorBytes, err := s.incremental.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to obtain stored cursor: %w", err)
+	}
+
+	if cursorBytes != nil {
+		cursor, err := decodeSQLCursor(cursorBytes)
+		if err != nil {
+			return fmt.Errorf("failed to decode stored cursor: %w", err)
+		}
+		s.cursor = cursor
+	} else if s.incremental.initialValue != nil {
+		s.cursor = *s.incremental.initialValue
+	}
+	s.cursorLoaded = true
+	return nil
+}
+
+// nextRow advances to the next row, polling the table again once the rows of
+// the current query are exhausted when in incremental mode.
+func (s *sqlSelectInput) nextRow(ctx context.Context) error {
+	for {
+		if s.rows != nil {
+			if s.rows.Next() {
+				return nil
+			}
+			err := s.rows.Err()
+			_ = s.rows.Close()
+			s.rows = nil
+			if err != nil {
+				return err
+			}
+		}
+		if s.incremental == nil || s.db == nil {
+			return service.ErrEndOfInput
+		}
+
+		select {
+		case <-time.After(s.incremental.pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.shutSig.HardStopChan():
+			return service.ErrEndOfInput
+		}
+
+		rows, err := s.query(s.db)
+		if err != nil {
+			return err
+		}
+		s.rows = rows
+	}
+}
+
+func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
 	s.dbMut.Lock()
 	defer s.dbMut.Unlock()
 
@@ -261,17 +447,7 @@ func (s *sqlSelectInput) Read(context.Context) (*service.Message, service.AckFun
 		return nil, nil, service.ErrNotConnected
 	}
 
-	if s.rows == nil {
-		return nil, nil, service.ErrEndOfInput
-	}
-
-	if !s.rows.Next() {
-		err := s.rows.Err()
-		if err == nil {
-			err = service.ErrEndOfInput
-		}
-		_ = s.rows.Close()
-		s.rows = nil
+	if err := s.nextRow(ctx); err != nil {
 		return nil, nil, err
 	}
 
@@ -284,11 +460,31 @@ func (s *sqlSelectInput) Read(context.Context) (*service.Message, service.AckFun
 
 	msg := service.NewMessage(nil)
 	msg.SetStructuredMut(obj)
-	return msg, func(context.Context, error) error {
-		// Nacks are handled by AutoRetryNacks because we don't have an explicit
-		// ack mechanism right now.
-		return nil
-	}, nil
+
+	if s.incremental == nil {
+		return msg, func(context.Context, error) error {
+			// Nacks are handled by AutoRetryNacks because we don't have an explicit
+			// ack mechanism right now.
+			return nil
+		}, nil
+	}
+
+	cursor, exists := obj[s.incremental.column]
+	if !exists {
+		return nil, nil, fmt.Errorf("cursor column %v was not selected", s.incremental.column)
+	}
+	if cursor == nil {
+		// Rows without a cursor value can't be checkpointed and are never
+		// selected again.
+		return msg, func(context.Context, error) error { return nil }, nil
+	}
+	s.cursor = cursor
+
+	ackFn, err := s.incremental.tracker.Track(ctx, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, ackFn, nil
 }
 
 func (s *sqlSelectInput) Close(ctx context.Context) error {
@@ -306,3 +502,63 @@ func (s *sqlSelectInput) Close(ctx context.Context) error {
 	}
 	return nil
 }
+
+//------------------------------------------------------------------------------
+
+// sqlCursor is the stored form of a cursor value, which retains its type so
+// that it is compared against the cursor column as the same type when
+// restored.
+type sqlCursor struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func encodeSQLCursor(v any) ([]byte, error) {
+	var c sqlCursor
+	switch t := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		c.Type = "int"
+	case float32, float64:
+		c.Type = "float"
+	case time.Time:
+		c.Type = "time"
+		v = t.Format(time.RFC3339Nano)
+	case string:
+		c.Type = "string"
+	default:
+		return nil, fmt.Errorf("cursor values of type %T are not supported", v)
+	}
+	var err error
+	if c.Value, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(c)
+}
+
+func decodeSQLCursor(b []byte) (any, error) {
+	var c sqlCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case "int":
+		var i int64
+		err := json.Unmarshal(c.Value, &i)
+		return i, err
+	case "float":
+		var f float64
+		err := json.Unmarshal(c.Value, &f)
+		return f, err
+	case "time":
+		var ts string
+		if err := json.Unmarshal(c.Value, &ts); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, ts)
+	case "string":
+		var str string
+		err := json.Unmarshal(c.Value, &str)
+		return str, err
+	}
+	return nil, fmt.Errorf("unknown cursor type: %v", c.Type)
+}
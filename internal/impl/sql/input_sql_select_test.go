@@ -15,11 +15,19 @@
 package sql
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "modernc.org/sqlite"
 )
 
 func TestSQLSelectInputEmptyShutdown(t *testing.T) {
@@ -42,3 +50,152 @@ args_mapping: 'root = [ this.id ]'
 	require.NoError(t, err)
 	require.NoError(t, selectInput.Close(t.Context()))
 }
+
+func TestSQLSelectInputIncremental(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "foo.db")
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Rows of the input remain open while rows are inserted.
+	_, err = db.Exec(`PRAGMA journal_mode=WAL`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE footable (id INTEGER PRIMARY KEY, name TEXT)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO footable (id, name) VALUES (3, 'c'), (1, 'a'), (2, 'b')`)
+	require.NoError(t, err)
+
+	conf, err := sqlSelectInputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+table: footable
+columns: [ id, name ]
+incremental:
+  column: id
+  cache: cursors
+  initial_value: "2"
+  poll_interval: 10ms
+`, nil)
+	require.NoError(t, err)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("cursors"))
+
+	readNames := func(i *sqlSelectInput, n int) (names []any) {
+		t.Helper()
+		for range n {
+			msg, ackFn, err := i.Read(ctx)
+			require.NoError(t, err)
+			v, err := msg.AsStructured()
+			require.NoError(t, err)
+			names = append(names, v.(map[string]any)["name"])
+			require.NoError(t, ackFn(ctx, nil))
+		}
+		return
+	}
+
+	i, err := newSQLSelectInputFromConfig(conf, res)
+	require.NoError(t, err)
+	require.NoError(t, i.Connect(ctx))
+
+	// Rows are consumed from the initial value inclusively.
+	assert.Equal(t, []any{"b", "c"}, readNames(i, 2))
+
+	_, err = db.Exec(`INSERT INTO footable (id, name) VALUES (4, 'd')`)
+	require.NoError(t, err)
+
+	// The row sharing the cursor value of the last row is read again.
+	assert.Equal(t, []any{"c", "d"}, readNames(i, 2))
+	require.NoError(t, i.Close(ctx))
+
+	require.NoError(t, res.AccessCache(ctx, "cursors", func(c service.Cache) {
+		v, err := c.Get(ctx, "footable")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"int","value":4}`, string(v))
+	}))
+
+	// A new input resumes from the stored cursor.
+	_, err = db.Exec(`INSERT INTO footable (id, name) VALUES (5, 'e')`)
+	require.NoError(t, err)
+
+	i, err = newSQLSelectInputFromConfig(conf, res)
+	require.NoError(t, err)
+	require.NoError(t, i.Connect(ctx))
+	assert.Equal(t, []any{"d", "e"}, readNames(i, 2))
+	require.NoError(t, i.Close(ctx))
+}
+
+func TestSQLSelectInputIncrementalNacksWithoutReplay(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "foo.db")
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE footable (id INTEGER PRIMARY KEY, name TEXT)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO footable (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+	require.NoError(t, err)
+
+	conf, err := sqlSelectInputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+table: footable
+columns: [ id, name ]
+auto_replay_nacks: false
+incremental:
+  column: id
+  cache: cursors
+  poll_interval: 10ms
+`, nil)
+	require.NoError(t, err)
+
+	res := service.MockResources(service.MockResourcesOptAddCache("cursors"))
+
+	i, err := newSQLSelectInputFromConfig(conf, res)
+	require.NoError(t, err)
+	require.NoError(t, i.Connect(ctx))
+
+	// Rows rejected without being replayed don't hold back the cursor.
+	for n := range 3 {
+		_, ackFn, err := i.Read(ctx)
+		require.NoError(t, err)
+		if n == 0 {
+			require.NoError(t, ackFn(ctx, errors.New("nope")))
+		} else {
+			require.NoError(t, ackFn(ctx, nil))
+		}
+	}
+	require.NoError(t, i.Close(ctx))
+
+	require.NoError(t, res.AccessCache(ctx, "cursors", func(c service.Cache) {
+		v, err := c.Get(ctx, "footable")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"int","value":3}`, string(v))
+	}))
+}
+
+func TestSQLCursorEncoding(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	for _, test := range []struct {
+		in, out any
+	}{
+		{in: int32(5), out: int64(5)},
+		{in: 1.5, out: 1.5},
+		{in: ts, out: ts},
+		{in: "2024-01-01", out: "2024-01-01"},
+	} {
+		b, err := encodeSQLCursor(test.in)
+		require.NoError(t, err)
+		v, err := decodeSQLCursor(b)
+		require.NoError(t, err)
+		assert.Equal(t, test.out, v)
+	}
+
+	_, err := encodeSQLCursor(true)
+	require.Error(t, err)
+}
@@ -60,6 +60,8 @@ func sqlInsertOutputConfig() *service.ConfigSpec {
 			Optional().
 			Advanced().
 			Example([]string{"DELAYED", "IGNORE"})).
+		Field(stmtCacheSizeField()).
+		Field(transactionField("Whether to execute the insert of each batch within an explicit transaction. The `clickhouse` and `oracle` drivers always insert each batch within a transaction.")).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of inserts to run in parallel.").
 			Default(64))
@@ -116,6 +118,9 @@ type sqlInsertOutput struct {
 	dbMut   sync.RWMutex
 
 	useTxStmt     bool
+	stmts         *stmtCache
+	txEnabled     bool
+	txOpts        *sql.TxOptions
 	argsMapping   *bloblang.Executor
 	argsConverter argsConverter
 
@@ -209,6 +214,20 @@ func newSQLInsertOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resou
 		s.builder = s.builder.Options(options...)
 	}
 
+	stmtCacheSize, err := conf.FieldInt("prepared_statement_cache_size")
+	if err != nil {
+		return nil, err
+	}
+	// The clickhouse driver sends the rows of a prepared statement once the
+	// transaction is committed, and therefore statements can't be reused.
+	if s.driver != "clickhouse" {
+		s.stmts = newStmtCache(stmtCacheSize)
+	}
+
+	if s.txEnabled, s.txOpts, err = transactionFromParsed(conf); err != nil {
+		return nil, err
+	}
+
 	if s.connSettings, err = connSettingsFromParsed(conf, mgr); err != nil {
 		return nil, err
 	}
@@ -234,6 +253,7 @@ func (s *sqlInsertOutput) Connect(ctx context.Context) error {
 		<-s.shutSig.HardStopChan()
 
 		s.dbMut.Lock()
+		s.stmts.close()
 		_ = s.db.Close()
 		s.dbMut.Unlock()
 
@@ -252,14 +272,23 @@ func (s *sqlInsertOutput) WriteBatch(ctx context.Context, batch service.MessageB
 	var stmt *sql.Stmt
 	if s.useTxStmt {
 		var err error
-		if tx, err = s.db.Begin(); err != nil {
+		if tx, err = s.db.BeginTx(ctx, s.txOpts); err != nil {
 			return err
 		}
 		sqlStr, _, err := insertBuilder.ToSql()
 		if err != nil {
+			_ = tx.Rollback()
 			return err
 		}
-		if stmt, err = tx.Prepare(sqlStr); err != nil {
+		if s.stmts != nil {
+			cached, release, err := s.stmts.get(ctx, s.db, sqlStr)
+			if err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			defer release()
+			stmt = tx.StmtContext(ctx, cached)
+		} else if stmt, err = tx.Prepare(sqlStr); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
@@ -297,13 +326,26 @@ func (s *sqlInsertOutput) WriteBatch(ctx context.Context, batch service.MessageB
 		}
 	}
 
-	var err error
-	if tx == nil {
-		_, err = insertBuilder.RunWith(s.db).ExecContext(ctx)
-	} else {
-		err = tx.Commit()
+	if tx != nil {
+		return tx.Commit()
+	}
+
+	sqlStr, args, err := insertBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+	if !s.txEnabled {
+		return execContext(ctx, s.stmts, s.db, nil, sqlStr, args...)
+	}
+
+	if tx, err = s.db.BeginTx(ctx, s.txOpts); err != nil {
+		return err
+	}
+	if err := execContext(ctx, s.stmts, s.db, tx, sqlStr, args...); err != nil {
+		_ = tx.Rollback()
+		return err
 	}
-	return err
+	return tx.Commit()
 }
 
 func (s *sqlInsertOutput) Close(ctx context.Context) error {
@@ -52,6 +52,8 @@ func sqlRawOutputConfig() *service.ConfigSpec {
 		).
 			Description("A list of statements to run in addition to `query`. When specifying multiple statements, they are all executed within a transaction.").
 			Optional()).
+		Field(stmtCacheSizeField()).
+		Field(transactionField("Whether to execute the statements of all messages of a batch within a single transaction, where the failure of any statement rolls back the entire batch. When disabled the statements of each message are executed within their own transaction only when multiple statements are configured.")).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of statements to execute in parallel.").
 			Default(64)).
@@ -129,6 +131,10 @@ type sqlRawOutput struct {
 	dbMut  sync.RWMutex
 
 	queries []rawQueryStatement
+	stmts   *stmtCache
+
+	txEnabled bool
+	txOpts    *sql.TxOptions
 
 	argsConverter argsConverter
 
@@ -205,7 +211,20 @@ func newSQLRawOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resource
 		argsConverter = func(v []any) []any { return v }
 	}
 
-	return newSQLRawOutput(mgr.Logger(), driverStr, dsnStr, queries, argsConverter, connSettings), nil
+	stmtCacheSize, err := conf.FieldInt("prepared_statement_cache_size")
+	if err != nil {
+		return nil, err
+	}
+
+	txEnabled, txOpts, err := transactionFromParsed(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSQLRawOutput(mgr.Logger(), driverStr, dsnStr, queries, argsConverter, connSettings)
+	s.stmts = newStmtCache(stmtCacheSize)
+	s.txEnabled, s.txOpts = txEnabled, txOpts
+	return s, nil
 }
 
 func newSQLRawOutput(
@@ -245,6 +264,7 @@ func (s *sqlRawOutput) Connect(ctx context.Context) error {
 		<-s.shutSig.HardStopChan()
 
 		s.dbMut.Lock()
+		s.stmts.close()
 		_ = s.db.Close()
 		s.dbMut.Unlock()
 
@@ -269,10 +289,26 @@ func (s *sqlRawOutput) WriteBatch(ctx context.Context, batch service.MessageBatc
 			dynQueries[i] = batch.InterpolationExecutor(q.dynamic)
 		}
 	}
+	if s.txEnabled {
+		tx, err := s.db.BeginTx(ctx, s.txOpts)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := s.execMessage(ctx, tx, i, argsExec, dynQueries); err != nil {
+				if rerr := tx.Rollback(); rerr != nil {
+					s.logger.Debugf("Failed to rollback transaction: %v", rerr)
+				}
+				return err
+			}
+		}
+		return tx.Commit()
+	}
+
 	return batch.WalkWithBatchedErrors(func(i int, _ *service.Message) (err error) {
 		var tx *sql.Tx
 		if len(s.queries) > 1 {
-			tx, err = s.db.BeginTx(ctx, nil)
+			tx, err = s.db.BeginTx(ctx, s.txOpts)
 			if err != nil {
 				return err
 			}
@@ -290,46 +326,52 @@ func (s *sqlRawOutput) WriteBatch(ctx context.Context, batch service.MessageBatc
 				}
 			}()
 		}
-		for j, query := range s.queries {
-			var args []any
-			if argsExec[j] != nil {
-				var resMsg *service.Message
-				resMsg, err = argsExec[j].Query(i)
-				if err != nil {
-					return fmt.Errorf("arguments mapping failed: %w", err)
-				}
-
-				var iargs any
-				iargs, err = resMsg.AsStructured()
-				if err != nil {
-					return fmt.Errorf("mapping returned non-structured result: %w", err)
-				}
+		return s.execMessage(ctx, tx, i, argsExec, dynQueries)
+	})
+}
 
-				var ok bool
-				if args, ok = iargs.([]any); !ok {
-					return fmt.Errorf("mapping returned non-array result: %T", iargs)
-				}
-				args = s.argsConverter(args)
+// execMessage executes each query for a message of a batch, within the
+// transaction if one is provided.
+func (s *sqlRawOutput) execMessage(
+	ctx context.Context,
+	tx *sql.Tx,
+	i int,
+	argsExec []*service.MessageBatchBloblangExecutor,
+	dynQueries []*service.MessageBatchInterpolationExecutor,
+) error {
+	for j, query := range s.queries {
+		var args []any
+		if argsExec[j] != nil {
+			resMsg, err := argsExec[j].Query(i)
+			if err != nil {
+				return fmt.Errorf("arguments mapping failed: %w", err)
 			}
 
-			queryStr := query.static
-			if query.dynamic != nil {
-				if queryStr, err = dynQueries[j].TryString(i); err != nil {
-					return fmt.Errorf("query interpolation error: %w", err)
-				}
+			iargs, err := resMsg.AsStructured()
+			if err != nil {
+				return fmt.Errorf("mapping returned non-structured result: %w", err)
 			}
 
-			if tx == nil {
-				_, err = s.db.ExecContext(ctx, queryStr, args...)
-			} else {
-				_, err = tx.ExecContext(ctx, queryStr, args...)
+			var ok bool
+			if args, ok = iargs.([]any); !ok {
+				return fmt.Errorf("mapping returned non-array result: %T", iargs)
 			}
-			if err != nil {
-				return fmt.Errorf("failed to run query: %w", err)
+			args = s.argsConverter(args)
+		}
+
+		queryStr := query.static
+		if query.dynamic != nil {
+			var err error
+			if queryStr, err = dynQueries[j].TryString(i); err != nil {
+				return fmt.Errorf("query interpolation error: %w", err)
 			}
 		}
-		return nil
-	})
+
+		if err := execContext(ctx, s.stmts, s.db, tx, queryStr, args...); err != nil {
+			return fmt.Errorf("failed to run query: %w", err)
+		}
+	}
+	return nil
 }
 
 func (s *sqlRawOutput) Close(ctx context.Context) error {
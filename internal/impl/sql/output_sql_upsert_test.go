@@ -0,0 +1,195 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLUpsertQueries(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		driver  string
+		updates string
+		query   string
+	}{
+		{
+			name:   "postgres",
+			driver: "postgres",
+			query:  "INSERT INTO foo (id, tenant, name) VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT (id, tenant) DO UPDATE SET name = EXCLUDED.name",
+		},
+		{
+			name:    "postgres no updates",
+			driver:  "postgres",
+			updates: "on_conflict: ignore",
+			query:   "INSERT INTO foo (id, tenant, name) VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT (id, tenant) DO NOTHING",
+		},
+		{
+			name:   "mysql",
+			driver: "mysql",
+			query:  "INSERT INTO foo (id, tenant, name) VALUES (?, ?, ?), (?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)",
+		},
+		{
+			name:    "mysql no updates",
+			driver:  "mysql",
+			updates: "on_conflict: ignore",
+			query:   "INSERT INTO foo (id, tenant, name) VALUES (?, ?, ?), (?, ?, ?) ON DUPLICATE KEY UPDATE id = id",
+		},
+		{
+			name:   "mssql",
+			driver: "mssql",
+			query:  "MERGE INTO foo AS target USING (VALUES (?, ?, ?), (?, ?, ?)) AS source (id, tenant, name) ON target.id = source.id AND target.tenant = source.tenant WHEN MATCHED THEN UPDATE SET name = source.name WHEN NOT MATCHED THEN INSERT (id, tenant, name) VALUES (source.id, source.tenant, source.name);",
+		},
+		{
+			name:   "oracle",
+			driver: "oracle",
+			query:  "MERGE INTO foo target USING (SELECT :1 id, :2 tenant, :3 name FROM dual UNION ALL SELECT :4 id, :5 tenant, :6 name FROM dual) source ON (target.id = source.id AND target.tenant = source.tenant) WHEN MATCHED THEN UPDATE SET name = source.name WHEN NOT MATCHED THEN INSERT (id, tenant, name) VALUES (source.id, source.tenant, source.name)",
+		},
+		{
+			name:    "oracle no updates",
+			driver:  "oracle",
+			updates: "on_conflict: ignore",
+			query:   "MERGE INTO foo target USING (SELECT :1 id, :2 tenant, :3 name FROM dual UNION ALL SELECT :4 id, :5 tenant, :6 name FROM dual) source ON (target.id = source.id AND target.tenant = source.tenant) WHEN NOT MATCHED THEN INSERT (id, tenant, name) VALUES (source.id, source.tenant, source.name)",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := sqlUpsertOutputConfig().ParseYAML(`
+driver: `+test.driver+`
+dsn: foo
+table: foo
+columns: [ id, tenant, name ]
+key_columns: [ id, tenant ]
+args_mapping: 'root = [ this.id, this.tenant, this.name ]'
+`+test.updates+`
+`, nil)
+			require.NoError(t, err)
+
+			s, err := newSQLUpsertOutputFromConfig(conf, service.MockResources())
+			require.NoError(t, err)
+
+			query, err := s.upsertQuery(2)
+			require.NoError(t, err)
+			assert.Equal(t, test.query, query)
+		})
+	}
+}
+
+func TestSQLUpsertConfigErrors(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "unsupported driver",
+			config: "driver: trino\ncolumns: [ id ]\nkey_columns: [ id ]",
+			err:    "driver trino does not support upserts",
+		},
+		{
+			name:   "unknown key column",
+			config: "driver: postgres\ncolumns: [ id ]\nkey_columns: [ uuid ]",
+			err:    "key column uuid is not within columns",
+		},
+		{
+			name:   "key update column",
+			config: "driver: postgres\ncolumns: [ id, name ]\nkey_columns: [ id ]\nupdate_columns: [ id ]",
+			err:    "update column id must not be a key column",
+		},
+		{
+			name:   "ignored update columns",
+			config: "driver: postgres\ncolumns: [ id, name ]\nkey_columns: [ id ]\nupdate_columns: [ name ]\non_conflict: ignore",
+			err:    "update columns must not be set when conflicts are ignored",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := sqlUpsertOutputConfig().ParseYAML(test.config+`
+dsn: foo
+table: foo
+args_mapping: 'root = []'
+`, nil)
+			require.NoError(t, err)
+
+			_, err = newSQLUpsertOutputFromConfig(conf, service.MockResources())
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestSQLUpsertSQLite(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "foo.db")
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE footable (id INTEGER PRIMARY KEY, name TEXT, age INTEGER)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO footable (id, name, age) VALUES (1, 'a', 10), (2, 'b', 20)`)
+	require.NoError(t, err)
+
+	conf, err := sqlUpsertOutputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+table: footable
+columns: [ id, name, age ]
+key_columns: [ id ]
+update_columns: [ name ]
+args_mapping: 'root = [ this.id, this.name, this.age ]'
+`, nil)
+	require.NoError(t, err)
+
+	s, err := newSQLUpsertOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, s.Connect(ctx))
+	t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+	require.NoError(t, s.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte(`{"id":2,"name":"b2","age":21}`)),
+		service.NewMessage([]byte(`{"id":3,"name":"c","age":30}`)),
+		service.NewMessage([]byte(`{"id":3,"name":"c2","age":31}`)),
+	}))
+
+	rows, err := db.QueryContext(ctx, `SELECT id, name, age FROM footable ORDER BY id`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var results []any
+	for rows.Next() {
+		var id, age int
+		var name string
+		require.NoError(t, rows.Scan(&id, &name, &age))
+		results = append(results, []any{id, name, age})
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, []any{
+		[]any{1, "a", 10},
+		[]any{2, "b2", 20},
+		[]any{3, "c2", 31},
+	}, results)
+}
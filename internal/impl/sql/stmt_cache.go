@@ -0,0 +1,179 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read_uncommitted": sql.LevelReadUncommitted,
+	"read_committed":   sql.LevelReadCommitted,
+	"write_committed":  sql.LevelWriteCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"snapshot":         sql.LevelSnapshot,
+	"serializable":     sql.LevelSerializable,
+	"linearizable":     sql.LevelLinearizable,
+}
+
+func stmtCacheSizeField() *service.ConfigField {
+	return service.NewIntField("prepared_statement_cache_size").
+		Description("The maximum number of prepared statements to keep for reuse across batches, keyed by their query. Queries are prepared the first time they are executed, and once the limit is reached the least recently used statement is closed. When set to zero queries are sent to the database without being prepared beforehand.").
+		Default(0).
+		Advanced().
+		Version("4.62.0")
+}
+
+func transactionField(enabledDescription string) *service.ConfigField {
+	return service.NewObjectField("transaction",
+		service.NewBoolField("enabled").
+			Description(enabledDescription).
+			Default(false),
+		service.NewStringEnumField("isolation_level", "default", "read_uncommitted", "read_committed", "write_committed", "repeatable_read", "snapshot", "serializable", "linearizable").
+			Description("The isolation level of transactions opened by this output, where `default` uses the default level of the database. Not all levels are supported by every database.").
+			Default("default"),
+	).
+		Description("Options for the transactions used for writing batches.").
+		Advanced().
+		Version("4.62.0")
+}
+
+// transactionFromParsed returns whether transactions are enabled and the
+// options to begin them with.
+func transactionFromParsed(conf *service.ParsedConfig) (enabled bool, opts *sql.TxOptions, err error) {
+	conf = conf.Namespace("transaction")
+	if enabled, err = conf.FieldBool("enabled"); err != nil {
+		return
+	}
+	var levelStr string
+	if levelStr, err = conf.FieldString("isolation_level"); err != nil {
+		return
+	}
+	opts = &sql.TxOptions{Isolation: isolationLevels[levelStr]}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// stmtCache keeps a bounded number of prepared statements keyed by query,
+// closing the least recently used statement once it is no longer in use.
+type stmtCache struct {
+	mut   sync.Mutex
+	size  int
+	stmts map[string]*list.Element
+	lru   *list.List
+}
+
+func newStmtCache(size int) *stmtCache {
+	if size <= 0 {
+		return nil
+	}
+	return &stmtCache{
+		size:  size,
+		stmts: map[string]*list.Element{},
+		lru:   list.New(),
+	}
+}
+
+// get returns a prepared statement for a query along with a func that must be
+// called once the statement is no longer being used.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, func(), error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var cs *cachedStmt
+	if e, exists := c.stmts[query]; exists {
+		c.lru.MoveToFront(e)
+		cs = e.Value.(*cachedStmt)
+	} else {
+		stmt, err := db.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		cs = &cachedStmt{query: query, stmt: stmt}
+		c.stmts[query] = c.lru.PushFront(cs)
+		for c.lru.Len() > c.size {
+			c.evict(c.lru.Back())
+		}
+	}
+
+	cs.refs++
+	return cs.stmt, func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		if cs.refs--; cs.refs == 0 && cs.evicted {
+			_ = cs.stmt.Close()
+		}
+	}, nil
+}
+
+func (c *stmtCache) evict(e *list.Element) {
+	cs := c.lru.Remove(e).(*cachedStmt)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		_ = cs.stmt.Close()
+	}
+}
+
+// close closes all statements of the cache.
+func (c *stmtCache) close() {
+	if c == nil {
+		return
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back())
+	}
+}
+
+// execContext executes a query within an optional transaction, using a cached
+// prepared statement when a cache is provided.
+func execContext(ctx context.Context, c *stmtCache, db *sql.DB, tx *sql.Tx, query string, args ...any) error {
+	if c == nil {
+		var err error
+		if tx == nil {
+			_, err = db.ExecContext(ctx, query, args...)
+		} else {
+			_, err = tx.ExecContext(ctx, query, args...)
+		}
+		return err
+	}
+
+	stmt, release, err := c.get(ctx, db, query)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if tx != nil {
+		stmt = tx.StmtContext(ctx, stmt)
+	}
+	_, err = stmt.ExecContext(ctx, args...)
+	return err
+}
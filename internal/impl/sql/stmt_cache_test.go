@@ -0,0 +1,171 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestSQLiteDB(t *testing.T) (string, *sql.DB) {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "foo.db")
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE footable (id INTEGER PRIMARY KEY, name TEXT)`)
+	require.NoError(t, err)
+	return dsn, db
+}
+
+func TestStmtCacheEviction(t *testing.T) {
+	ctx := t.Context()
+	_, db := newTestSQLiteDB(t)
+
+	c := newStmtCache(2)
+
+	stmtA, releaseA, err := c.get(ctx, db, "SELECT 1")
+	require.NoError(t, err)
+
+	stmtB, releaseB, err := c.get(ctx, db, "SELECT 2")
+	require.NoError(t, err)
+	releaseB()
+
+	stmtA2, releaseA2, err := c.get(ctx, db, "SELECT 1")
+	require.NoError(t, err)
+	assert.Same(t, stmtA, stmtA2)
+	releaseA2()
+
+	// Preparing a third statement evicts the least recently used one, which
+	// is closed as it's no longer in use.
+	_, releaseC, err := c.get(ctx, db, "SELECT 3")
+	require.NoError(t, err)
+	releaseC()
+	assert.Len(t, c.stmts, 2)
+	require.Error(t, stmtB.QueryRowContext(ctx).Scan(new(int)))
+
+	// Statements in use remain open until they are released.
+	c.close()
+	var v int
+	require.NoError(t, stmtA.QueryRowContext(ctx).Scan(&v))
+	assert.Equal(t, 1, v)
+	releaseA()
+	require.Error(t, stmtA.QueryRowContext(ctx).Scan(&v))
+
+	assert.Nil(t, newStmtCache(0))
+}
+
+func countRows(t *testing.T, db *sql.DB) (n int) {
+	t.Helper()
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM footable`).Scan(&n))
+	return
+}
+
+func TestSQLRawOutputTransaction(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	for _, test := range []struct {
+		name        string
+		transaction bool
+		rows        int
+	}{
+		{name: "without transaction", rows: 1},
+		{name: "with transaction", transaction: true, rows: 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dsn, db := newTestSQLiteDB(t)
+
+			conf, err := sqlRawOutputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+query: INSERT INTO footable (id, name) VALUES (?, ?)
+args_mapping: 'root = [ this.id, this.name ]'
+prepared_statement_cache_size: 10
+transaction:
+  enabled: `+strconv.FormatBool(test.transaction)+`
+`, nil)
+			require.NoError(t, err)
+
+			s, err := newSQLRawOutputFromConfig(conf, service.MockResources())
+			require.NoError(t, err)
+			require.NoError(t, s.Connect(ctx))
+			t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+			// The second message collides with the first and fails.
+			require.Error(t, s.WriteBatch(ctx, service.MessageBatch{
+				service.NewMessage([]byte(`{"id":1,"name":"a"}`)),
+				service.NewMessage([]byte(`{"id":1,"name":"b"}`)),
+			}))
+			assert.Equal(t, test.rows, countRows(t, db))
+
+			require.NoError(t, s.WriteBatch(ctx, service.MessageBatch{
+				service.NewMessage([]byte(`{"id":2,"name":"c"}`)),
+				service.NewMessage([]byte(`{"id":3,"name":"d"}`)),
+			}))
+			assert.Equal(t, test.rows+2, countRows(t, db))
+			assert.Len(t, s.stmts.stmts, 1)
+		})
+	}
+}
+
+func TestSQLInsertOutputStmtCache(t *testing.T) {
+	ctx, done := context.WithTimeout(t.Context(), 10*time.Second)
+	defer done()
+
+	dsn, db := newTestSQLiteDB(t)
+
+	conf, err := sqlInsertOutputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+table: footable
+columns: [ id, name ]
+args_mapping: 'root = [ this.id, this.name ]'
+prepared_statement_cache_size: 10
+transaction:
+  enabled: true
+`, nil)
+	require.NoError(t, err)
+
+	s, err := newSQLInsertOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, s.Connect(ctx))
+	t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+	for i := range 3 {
+		require.NoError(t, s.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage(fmt.Appendf(nil, `{"id":%v,"name":"a"}`, i*2)),
+			service.NewMessage(fmt.Appendf(nil, `{"id":%v,"name":"b"}`, i*2+1)),
+		}))
+	}
+	assert.Equal(t, 6, countRows(t, db))
+
+	// Batches of the same size share a statement.
+	assert.Len(t, s.stmts.stmts, 1)
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	siFieldURL            = "url"
+	siFieldHeaders        = "headers"
+	siFieldLastEventID    = "last_event_id"
+	siFieldReconnectDelay = "reconnect_delay"
+	siFieldTLS            = "tls"
+)
+
+func inputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Consumes a https://html.spec.whatwg.org/multipage/server-sent-events.html[Server-Sent Events^] stream.").
+		Description(`
+Each event received from the stream is emitted as a message with the event data as its contents, where the data of events spanning multiple `+"`data`"+` lines is joined with line breaks.
+
+When the stream ends or the connection is lost the input reconnects after `+"`"+siFieldReconnectDelay+"`"+`, or the delay requested by the server with a `+"`retry`"+` field, and sends the ID of the last event received in the `+"`Last-Event-ID`"+` header so that the server can resume the stream. Since the position within the stream is only held in memory a restart of the pipeline resumes from `+"`"+siFieldLastEventID+"`"+`.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- sse_event
+- sse_id
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+		Fields(
+			service.NewURLField(siFieldURL).
+				Description("The URL of the event stream.").
+				Example("https://example.com/events"),
+			service.NewInterpolatedStringMapField(siFieldHeaders).
+				Description("A map of headers to add to the request.").
+				Example(map[string]any{"Authorization": "Bearer ${! env(\"TOKEN\") }"}).
+				Default(map[string]any{}),
+			service.NewStringField(siFieldLastEventID).
+				Description("An optional event ID to resume the stream from when first connecting.").
+				Default("").
+				Advanced(),
+			service.NewDurationField(siFieldReconnectDelay).
+				Description("The period to wait before reconnecting after the stream ends, unless the server requests a different delay.").
+				Default("3s").
+				Advanced(),
+			service.NewTLSToggledField(siFieldTLS),
+		).
+		Example(
+			"Wikimedia recent changes",
+			"Consume the public stream of edits made to Wikimedia projects.",
+			`
+input:
+  sse:
+    url: https://stream.wikimedia.org/v2/stream/recentchange
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterInput("sse", inputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacks(i), nil
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type event struct {
+	id   string
+	name string
+	data string
+}
+
+// eventReader parses events from a text/event-stream body.
+type eventReader struct {
+	scanner *bufio.Scanner
+	lastID  string
+	retry   time.Duration
+}
+
+func newEventReader(r io.Reader, lastID string) *eventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	scanner.Split(scanLines)
+	return &eventReader{scanner: scanner, lastID: lastID}
+}
+
+// scanLines splits on any of the line endings allowed by the specification:
+// CRLF, LF or CR.
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			// Wait for more data to determine whether this is a CRLF.
+			return 0, nil, nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// next returns the next event of the stream. Events without any data are
+// skipped, but their id and retry fields are still applied.
+func (e *eventReader) next() (*event, error) {
+	var data strings.Builder
+	var hasData bool
+	ev := &event{}
+	for e.scanner.Scan() {
+		line := e.scanner.Text()
+		if line == "" {
+			if !hasData {
+				ev.name = ""
+				continue
+			}
+			ev.id = e.lastID
+			ev.data = strings.TrimSuffix(data.String(), "\n")
+			if ev.name == "" {
+				ev.name = "message"
+			}
+			return ev, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.name = value
+		case "data":
+			hasData = true
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				e.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 64); err == nil {
+				e.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := e.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+//------------------------------------------------------------------------------
+
+type input struct {
+	log *service.Logger
+
+	url            string
+	headers        map[string]*service.InterpolatedString
+	reconnectDelay time.Duration
+	client         *http.Client
+
+	mut       sync.Mutex
+	lastID    string
+	connected bool
+	body      io.ReadCloser
+	events    *eventReader
+}
+
+func newInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*input, error) {
+	i := &input{log: mgr.Logger()}
+
+	var err error
+	if i.url, err = conf.FieldString(siFieldURL); err != nil {
+		return nil, err
+	}
+	if i.headers, err = conf.FieldInterpolatedStringMap(siFieldHeaders); err != nil {
+		return nil, err
+	}
+	if i.lastID, err = conf.FieldString(siFieldLastEventID); err != nil {
+		return nil, err
+	}
+	if i.reconnectDelay, err = conf.FieldDuration(siFieldReconnectDelay); err != nil {
+		return nil, err
+	}
+
+	var tlsConf *tls.Config
+	var tlsEnabled bool
+	if tlsConf, tlsEnabled, err = conf.FieldTLSToggled(siFieldTLS); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	i.client = &http.Client{Transport: transport}
+	return i, nil
+}
+
+func (i *input) Connect(ctx context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+
+	if i.body != nil {
+		return nil
+	}
+
+	if i.connected {
+		select {
+		case <-time.After(i.reconnectDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, i.url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	emptyMsg := service.NewMessage(nil)
+	for k, v := range i.headers {
+		value, err := v.TryString(emptyMsg)
+		if err != nil {
+			return fmt.Errorf("failed to interpolate header %v: %w", k, err)
+		}
+		req.Header.Set(k, value)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if i.lastID != "" {
+		req.Header.Set("Last-Event-ID", i.lastID)
+	}
+
+	res, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		res.Body.Close()
+		return fmt.Errorf("unexpected content type: %v", ct)
+	}
+
+	i.connected = true
+	i.body = res.Body
+	i.events = newEventReader(res.Body, i.lastID)
+	return nil
+}
+
+func (i *input) Read(context.Context) (*service.Message, service.AckFunc, error) {
+	i.mut.Lock()
+	events := i.events
+	i.mut.Unlock()
+	if events == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	ev, err := events.next()
+
+	i.mut.Lock()
+	defer i.mut.Unlock()
+	i.lastID = events.lastID
+	if events.retry > 0 {
+		i.reconnectDelay = events.retry
+	}
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			i.log.Warnf("Event stream interrupted: %v", err)
+		}
+		i.closeBody()
+		return nil, nil, service.ErrNotConnected
+	}
+
+	msg := service.NewMessage([]byte(ev.data))
+	msg.MetaSetMut("sse_event", ev.name)
+	msg.MetaSetMut("sse_id", ev.id)
+	return msg, func(context.Context, error) error { return nil }, nil
+}
+
+func (i *input) closeBody() {
+	if i.body != nil {
+		_ = i.body.Close()
+		i.body = nil
+		i.events = nil
+	}
+}
+
+func (i *input) Close(context.Context) error {
+	i.mut.Lock()
+	defer i.mut.Unlock()
+	i.closeBody()
+	return nil
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	soFieldAddress           = "address"
+	soFieldPath              = "path"
+	soFieldEvent             = "event"
+	soFieldID                = "id"
+	soFieldHistorySize       = "history_size"
+	soFieldHeartbeatInterval = "heartbeat_interval"
+	soFieldSendBuffer        = "send_buffer"
+	soFieldAllowedOrigins    = "allowed_origins"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Runs an HTTP server that streams messages to connected clients as https://html.spec.whatwg.org/multipage/server-sent-events.html[Server-Sent Events^].").
+		Description(`
+Every message is sent as an event to all clients connected to `+"`"+soFieldPath+"`"+` at the time it is written. Delivery is best effort: messages are acknowledged once they have been queued for all connected clients, and clients that fall behind by more than `+"`"+soFieldSendBuffer+"`"+` events are disconnected rather than applying back pressure to the pipeline.
+
+Each event is given an ID, which is either the result of the `+"`"+soFieldID+"`"+` field or otherwise a sequence number. When `+"`"+soFieldHistorySize+"`"+` is greater than zero the most recent events are retained, and clients that reconnect with a `+"`Last-Event-ID`"+` header first receive the retained events that followed it.`).
+		Fields(
+			service.NewStringField(soFieldAddress).
+				Description("The address to listen on.").
+				Default("0.0.0.0:4196"),
+			service.NewStringField(soFieldPath).
+				Description("The path at which clients can open event streams.").
+				Default("/events"),
+			service.NewInterpolatedStringField(soFieldEvent).
+				Description("An optional event type to set on each event, clients receive events without a type as `message` events.").
+				Example(`${! @kafka_topic }`).
+				Default(""),
+			service.NewInterpolatedStringField(soFieldID).
+				Description("An optional ID to set on each event. When empty a sequence number is used.").
+				Example(`${! @kafka_partition }-${! @kafka_offset }`).
+				Default(""),
+			service.NewIntField(soFieldHistorySize).
+				Description("The number of recent events to retain for clients resuming a stream.").
+				Default(0).
+				Advanced(),
+			service.NewDurationField(soFieldHeartbeatInterval).
+				Description("The interval at which comments are sent to idle clients in order to keep connections open through proxies. Set to `0s` to disable.").
+				Default("15s").
+				Advanced(),
+			service.NewIntField(soFieldSendBuffer).
+				Description("The maximum number of events queued for each client.").
+				Default(256).
+				Advanced(),
+			service.NewStringListField(soFieldAllowedOrigins).
+				Description("A list of origins allowed to make cross-origin requests, `*` allows any origin.").
+				Default([]any{}).
+				Advanced(),
+		).
+		Example(
+			"Topic to browser",
+			"Stream the records of a Kafka topic to web frontends, with the topic as the event type.",
+			`
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ prices ]
+    consumer_group: sse_bridge
+
+output:
+  sse:
+    address: 0.0.0.0:8080
+    path: /prices
+    event: ${! @kafka_topic }
+    history_size: 100
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterOutput("sse", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Output, int, error) {
+			o, err := newOutputFromConfig(conf, mgr)
+			return o, 1, err
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type sentEvent struct {
+	id      string
+	encoded []byte
+}
+
+// encodeEvent formats an event in the text/event-stream format, splitting the
+// data into a field per line.
+func encodeEvent(id, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	if id != "" {
+		buf.WriteString("id: " + id + "\n")
+	}
+	if name != "" {
+		buf.WriteString("event: " + name + "\n")
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+type client struct {
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *client) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+type output struct {
+	log *service.Logger
+
+	address           string
+	path              string
+	event             *service.InterpolatedString
+	id                *service.InterpolatedString
+	historySize       int
+	heartbeatInterval time.Duration
+	sendBuffer        int
+	allowedOrigins    []string
+
+	mut     sync.Mutex
+	seq     uint64
+	history []sentEvent
+	clients map[*client]struct{}
+
+	server   *http.Server
+	listener net.Listener
+}
+
+func newOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{
+		log:     mgr.Logger(),
+		clients: map[*client]struct{}{},
+	}
+
+	var err error
+	if o.address, err = conf.FieldString(soFieldAddress); err != nil {
+		return nil, err
+	}
+	if o.path, err = conf.FieldString(soFieldPath); err != nil {
+		return nil, err
+	}
+	if o.event, err = conf.FieldInterpolatedString(soFieldEvent); err != nil {
+		return nil, err
+	}
+	if o.id, err = conf.FieldInterpolatedString(soFieldID); err != nil {
+		return nil, err
+	}
+	if o.historySize, err = conf.FieldInt(soFieldHistorySize); err != nil {
+		return nil, err
+	}
+	if o.heartbeatInterval, err = conf.FieldDuration(soFieldHeartbeatInterval); err != nil {
+		return nil, err
+	}
+	if o.sendBuffer, err = conf.FieldInt(soFieldSendBuffer); err != nil {
+		return nil, err
+	}
+	if o.sendBuffer < 1 {
+		return nil, fmt.Errorf("%v must be greater than zero", soFieldSendBuffer)
+	}
+	if o.allowedOrigins, err = conf.FieldStringList(soFieldAllowedOrigins); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *output) Connect(context.Context) error {
+	if o.server != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", o.address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(o.path, o.handleStream)
+
+	o.listener = listener
+	o.server = &http.Server{Handler: mux}
+	go func() {
+		o.log.Infof("Serving Server-Sent Events at: http://%v%v", listener.Addr(), o.path)
+		if err := o.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			o.log.Errorf("Server-Sent Events server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (o *output) handleStream(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if slices.Contains(o.allowedOrigins, "*") || slices.Contains(o.allowedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	c := &client{
+		send: make(chan []byte, o.sendBuffer),
+		done: make(chan struct{}),
+	}
+
+	// Register the client and queue any missed events under the same lock so
+	// that no events are lost or duplicated in between.
+	o.mut.Lock()
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if idx := slices.IndexFunc(o.history, func(e sentEvent) bool { return e.id == lastID }); idx >= 0 {
+			for _, e := range o.history[idx+1:] {
+				select {
+				case c.send <- e.encoded:
+				default:
+				}
+			}
+		}
+	}
+	o.clients[c] = struct{}{}
+	o.mut.Unlock()
+
+	defer func() {
+		o.mut.Lock()
+		delete(o.clients, c)
+		o.mut.Unlock()
+		c.close()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if o.heartbeatInterval > 0 {
+		ticker := time.NewTicker(o.heartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case data := <-c.send:
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat:
+			if _, err := w.Write([]byte(":\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-c.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (o *output) Write(_ context.Context, msg *service.Message) error {
+	name, err := o.event.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate event: %w", err)
+	}
+	if strings.ContainsAny(name, "\r\n") {
+		return errors.New("event type must not contain line breaks")
+	}
+	id, err := o.id.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate id: %w", err)
+	}
+	if strings.ContainsAny(id, "\r\n\x00") {
+		return errors.New("event id must not contain line breaks or null characters")
+	}
+
+	data, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	o.seq++
+	if id == "" {
+		id = strconv.FormatUint(o.seq, 10)
+	}
+	e := sentEvent{id: id, encoded: encodeEvent(id, name, data)}
+	if o.historySize > 0 {
+		if len(o.history) >= o.historySize {
+			o.history = slices.Delete(o.history, 0, len(o.history)-o.historySize+1)
+		}
+		o.history = append(o.history, e)
+	}
+
+	for c := range o.clients {
+		select {
+		case c.send <- e.encoded:
+		default:
+			o.log.Warn("Disconnecting Server-Sent Events client as its send buffer is full")
+			delete(o.clients, c)
+			c.close()
+		}
+	}
+	return nil
+}
+
+func (o *output) Close(ctx context.Context) error {
+	o.mut.Lock()
+	for c := range o.clients {
+		c.close()
+		delete(o.clients, c)
+	}
+	o.mut.Unlock()
+
+	if o.server == nil {
+		return nil
+	}
+	return o.server.Shutdown(ctx)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestEventReader(t *testing.T) {
+	stream := ": a comment\r\n" +
+		"retry: 2500\r\n" +
+		"\r\n" +
+		"data: first\n" +
+		"data:  second\n" +
+		"id: 1\n" +
+		"\n" +
+		"event: update\r" +
+		"data: {\"a\":1}\r" +
+		"\r" +
+		"id: 2\n" +
+		"\n" +
+		"data: third\n" +
+		"\n" +
+		"data: unterminated"
+
+	r := newEventReader(strings.NewReader(stream), "")
+
+	var events []event
+	for {
+		ev, err := r.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, *ev)
+	}
+
+	assert.Equal(t, []event{
+		{id: "1", name: "message", data: "first\n second"},
+		{id: "1", name: "update", data: `{"a":1}`},
+		{id: "2", name: "message", data: "third"},
+	}, events)
+	assert.Equal(t, 2500*time.Millisecond, r.retry)
+}
+
+func TestEncodeEvent(t *testing.T) {
+	assert.Equal(t, "id: 5\nevent: foo\ndata: a\ndata: b\ndata: c\n\n", string(encodeEvent("5", "foo", []byte("a\r\nb\rc"))))
+	assert.Equal(t, "data: \n\n", string(encodeEvent("", "", nil)))
+}
+
+func TestSSEOutputToInput(t *testing.T) {
+	outConf, err := outputSpec().ParseYAML(`
+address: 127.0.0.1:0
+event: ${! @type }
+history_size: 10
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newOutputFromConfig(outConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() { _ = out.Close(t.Context()) })
+
+	inConf, err := inputSpec().ParseYAML(`
+url: http://`+out.listener.Addr().String()+`/events
+reconnect_delay: 10ms
+`, nil)
+	require.NoError(t, err)
+
+	in, err := newInputFromConfig(inConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = in.Close(t.Context()) })
+
+	connectAndWait := func() {
+		require.NoError(t, in.Connect(t.Context()))
+		require.Eventually(t, func() bool {
+			out.mut.Lock()
+			defer out.mut.Unlock()
+			return len(out.clients) == 1
+		}, time.Second*5, time.Millisecond*10)
+	}
+
+	write := func(eventType, data string) {
+		msg := service.NewMessage([]byte(data))
+		msg.MetaSetMut("type", eventType)
+		require.NoError(t, out.Write(t.Context(), msg))
+	}
+
+	read := func(eventType, id, data string) {
+		msg, _, err := in.Read(t.Context())
+		require.NoError(t, err)
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, data, string(b))
+		v, _ := msg.MetaGet("sse_event")
+		assert.Equal(t, eventType, v)
+		v, _ = msg.MetaGet("sse_id")
+		assert.Equal(t, id, v)
+	}
+
+	connectAndWait()
+	write("", "hello")
+	write("greeting", "multi\nline")
+	read("message", "1", "hello")
+	read("greeting", "2", "multi\nline")
+
+	// Disconnect the client and write events while it is away.
+	out.mut.Lock()
+	for c := range out.clients {
+		c.close()
+	}
+	out.mut.Unlock()
+	require.Eventually(t, func() bool {
+		out.mut.Lock()
+		defer out.mut.Unlock()
+		return len(out.clients) == 0
+	}, time.Second*5, time.Millisecond*10)
+
+	write("", "missed")
+	_, _, err = in.Read(t.Context())
+	require.ErrorIs(t, err, service.ErrNotConnected)
+
+	// Reconnecting resumes from the last event received.
+	connectAndWait()
+	write("", "after")
+	read("message", "3", "missed")
+	read("message", "4", "after")
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateful
+
+import (
+	"context"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	smFieldMapping = "mapping"
+)
+
+func statefulMappingProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Mapping").
+		Version("4.62.0").
+		Summary("Executes a Bloblang mapping with additional functions for keeping per-key statistics within cache resources.").
+		Description(`
+This processor behaves like the `+"xref:components:processors/mapping.adoc[`mapping` processor]"+`, where the result of the mapping replaces each message, with the addition of the following functions that store their state in a xref:components:caches/about.adoc[cache resource] identified by the argument `+"`name`"+`:
+
+=== `+"`counter(name, key, delta: 1)`"+`
+
+Adds `+"`delta`"+` to the integer counter stored at `+"`key`"+` and returns the new value. Counters that do not yet exist start at zero.
+
+=== `+"`rolling_sum(name, key, value, window)`"+`
+
+Adds a number `+"`value`"+` to the rolling sum stored at `+"`key`"+` and returns the sum of values added within the duration `+"`window`"+`, such as `+"`\"1m\"`"+`.
+
+=== `+"`rate(name, key, window)`"+`
+
+Counts an event at `+"`key`"+` and returns the average number of events per second within the duration `+"`window`"+`.
+
+== Accuracy
+
+Rolling sums and rates are tracked with 60 buckets spanning the window, and therefore values expire from the sum in steps of one sixtieth of the window. Their state is written to the cache with a TTL of the window, which is honoured by caches that support TTLs.
+
+Updates are performed by reading and then writing the state within the cache. Concurrent updates of a key are serialized within an instance of this processor, but updates from separate processors or instances sharing a cache might overwrite one another. Where exact counts across instances are required, partition messages between instances by the key.`).
+		Field(service.NewStringField(smFieldMapping).
+			Description("The xref:guides:bloblang/about.adoc[Bloblang mapping] to execute, which can use the functions listed above.").
+			Examples(`root = this
+root.user_events = counter("stats", this.user_id)`)).
+		Example("Per-user spend", "Annotate purchases with the number of purchases made by the user and the amount spent by them within the last hour.", `
+pipeline:
+  processors:
+    - stateful_mapping:
+        mapping: |
+          root = this
+          root.purchase_count = counter("stats", "count:" + this.user_id)
+          root.hourly_spend = rolling_sum("stats", "spend:" + this.user_id, this.amount, "1h")
+
+cache_resources:
+  - label: stats
+    redis:
+      url: redis://localhost:6379
+`)
+}
+
+func init() {
+	service.MustRegisterBatchProcessor(
+		"stateful_mapping", statefulMappingProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newStatefulMappingFromConfig(conf, mgr)
+		})
+}
+
+type statefulMapping struct {
+	exec *bloblang.Executor
+}
+
+func newStatefulMappingFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*statefulMapping, error) {
+	mapping, err := conf.FieldString(smFieldMapping)
+	if err != nil {
+		return nil, err
+	}
+	return newStatefulMapping(mapping, mgr, time.Now)
+}
+
+func newStatefulMapping(mapping string, mgr *service.Resources, clock func() time.Time) (*statefulMapping, error) {
+	env := bloblang.GlobalEnvironment().Clone()
+	if err := registerStateFunctions(env, newStateStore(mgr, clock)); err != nil {
+		return nil, err
+	}
+
+	exec, err := env.Parse(mapping)
+	if err != nil {
+		return nil, err
+	}
+	return &statefulMapping{exec: exec}, nil
+}
+
+func (s *statefulMapping) ProcessBatch(_ context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	exec := batch.BloblangExecutor(s.exec)
+
+	newBatch := make(service.MessageBatch, 0, len(batch))
+	for i, msg := range batch {
+		res, err := exec.Query(i)
+		if err != nil {
+			msg.SetError(err)
+			newBatch = append(newBatch, msg)
+			continue
+		}
+		if res != nil {
+			newBatch = append(newBatch, res)
+		}
+	}
+	if len(newBatch) == 0 {
+		return nil, nil
+	}
+	return []service.MessageBatch{newBatch}, nil
+}
+
+func (*statefulMapping) Close(context.Context) error {
+	return nil
+}
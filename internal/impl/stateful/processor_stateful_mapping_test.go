@@ -0,0 +1,101 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateful
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func processAll(t *testing.T, proc *statefulMapping, inputs ...string) (outputs []string) {
+	t.Helper()
+
+	var batch service.MessageBatch
+	for _, in := range inputs {
+		batch = append(batch, service.NewMessage([]byte(in)))
+	}
+	batches, err := proc.ProcessBatch(t.Context(), batch)
+	require.NoError(t, err)
+	for _, b := range batches {
+		for _, msg := range b {
+			require.NoError(t, msg.GetError())
+			mBytes, err := msg.AsBytes()
+			require.NoError(t, err)
+			outputs = append(outputs, string(mBytes))
+		}
+	}
+	return
+}
+
+func TestStatefulMappingCounter(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("stats"))
+	proc, err := newStatefulMapping(`
+root.user = this.user
+root.count = counter("stats", this.user)
+root.total = counter(name: "stats", key: "total", delta: 10)
+`, res, time.Now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`{"count":1,"total":10,"user":"a"}`,
+		`{"count":1,"total":20,"user":"b"}`,
+		`{"count":2,"total":30,"user":"a"}`,
+	}, processAll(t, proc, `{"user":"a"}`, `{"user":"b"}`, `{"user":"a"}`))
+}
+
+func TestStatefulMappingRolling(t *testing.T) {
+	now := time.Unix(1000, 0)
+	res := service.MockResources(service.MockResourcesOptAddCache("stats"))
+	proc, err := newStatefulMapping(`
+root.sum = rolling_sum("stats", "sum", this.v, "1m")
+root.rate = rate("stats", "rate", "10s")
+`, res, func() time.Time { return now })
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`{"rate":0.1,"sum":5}`,
+		`{"rate":0.2,"sum":7.5}`,
+	}, processAll(t, proc, `{"v":5}`, `{"v":2.5}`))
+
+	now = now.Add(time.Second * 30)
+	assert.Equal(t, []string{
+		`{"rate":0.1,"sum":8.5}`,
+	}, processAll(t, proc, `{"v":1}`))
+
+	now = now.Add(time.Second * 30)
+	assert.Equal(t, []string{
+		`{"rate":0.1,"sum":2}`,
+	}, processAll(t, proc, `{"v":1}`))
+}
+
+func TestStatefulMappingErrors(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("stats"))
+
+	_, err := newStatefulMapping(`root = rolling_sum("stats", "foo", 1, "nope")`, res, time.Now)
+	require.ErrorContains(t, err, "failed to parse window")
+
+	proc, err := newStatefulMapping(`root = counter("missing", "foo")`, res, time.Now)
+	require.NoError(t, err)
+
+	batches, err := proc.ProcessBatch(t.Context(), service.MessageBatch{service.NewMessage([]byte(`{}`))})
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Error(t, batches[0][0].GetError())
+}
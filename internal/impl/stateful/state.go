@@ -0,0 +1,244 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// The number of buckets that rolling windows are divided into.
+const rollingBuckets = 60
+
+// stateStore performs read-modify-write updates of state within cache
+// resources, where updates of the same key are serialized.
+type stateStore struct {
+	res   *service.Resources
+	clock func() time.Time
+	locks [64]sync.Mutex
+}
+
+func newStateStore(res *service.Resources, clock func() time.Time) *stateStore {
+	return &stateStore{res: res, clock: clock}
+}
+
+// update reads the current value of a key, which is nil when the key does not
+// exist, and stores the value returned by fn.
+func (s *stateStore) update(name, key string, ttl *time.Duration, fn func(current []byte) ([]byte, error)) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	lock := &s.locks[h.Sum32()%uint32(len(s.locks))]
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx := context.Background()
+
+	var err error
+	if cerr := s.res.AccessCache(ctx, name, func(c service.Cache) {
+		var current []byte
+		if current, err = c.Get(ctx, key); err != nil {
+			if !errors.Is(err, service.ErrKeyNotFound) {
+				return
+			}
+			current = nil
+		}
+		var updated []byte
+		if updated, err = fn(current); err != nil {
+			return
+		}
+		err = c.Set(ctx, key, updated, ttl)
+	}); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+func (s *stateStore) counter(name, key string, delta int64) (count int64, err error) {
+	err = s.update(name, key, nil, func(current []byte) ([]byte, error) {
+		if current != nil {
+			var perr error
+			if count, perr = strconv.ParseInt(string(current), 10, 64); perr != nil {
+				return nil, fmt.Errorf("failed to parse counter value: %w", perr)
+			}
+		}
+		count += delta
+		return strconv.AppendInt(nil, count, 10), nil
+	})
+	return
+}
+
+// rollingState is the stored state of a rolling sum, where each bucket is a
+// pair of the bucket index and the sum of values added within it.
+type rollingState struct {
+	Width   int64        `json:"width"`
+	Buckets [][2]float64 `json:"buckets"`
+}
+
+func (s *stateStore) rollingSum(name, key string, value float64, window time.Duration) (sum float64, err error) {
+	width := int64(window) / rollingBuckets
+	if width <= 0 {
+		return 0, errors.New("window must be greater than zero")
+	}
+	current := s.clock().UnixNano() / width
+
+	err = s.update(name, key, &window, func(existing []byte) ([]byte, error) {
+		var state rollingState
+		if existing != nil {
+			if jerr := json.Unmarshal(existing, &state); jerr != nil {
+				return nil, fmt.Errorf("failed to parse rolling sum state: %w", jerr)
+			}
+		}
+		if state.Width != width {
+			// The window of the key has changed.
+			state = rollingState{Width: width}
+		}
+
+		buckets := state.Buckets[:0]
+		for _, b := range state.Buckets {
+			if int64(b[0]) > current-rollingBuckets {
+				buckets = append(buckets, b)
+			}
+		}
+		if l := len(buckets); l > 0 && int64(buckets[l-1][0]) == current {
+			buckets[l-1][1] += value
+		} else {
+			buckets = append(buckets, [2]float64{float64(current), value})
+		}
+		state.Buckets = buckets
+
+		sum = 0
+		for _, b := range buckets {
+			sum += b[1]
+		}
+		return json.Marshal(state)
+	})
+	return
+}
+
+func parseWindow(args *bloblang.ParsedParams) (time.Duration, error) {
+	windowStr, err := args.GetString("window")
+	if err != nil {
+		return 0, err
+	}
+	window, err := time.ParseDuration(windowStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse window: %w", err)
+	}
+	if window <= 0 {
+		return 0, errors.New("window must be greater than zero")
+	}
+	return window, nil
+}
+
+func registerStateFunctions(env *bloblang.Environment, store *stateStore) error {
+	counterSpec := bloblang.NewPluginSpec().
+		Impure().
+		Description("Adds to a counter stored in a cache resource and returns the new value.").
+		Param(bloblang.NewStringParam("name").Description("The name of the cache resource.")).
+		Param(bloblang.NewStringParam("key").Description("The key of the counter.")).
+		Param(bloblang.NewInt64Param("delta").Description("The amount to add to the counter.").Default(int64(1)))
+
+	if err := env.RegisterFunctionV2("counter", counterSpec, func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+		name, err := args.GetString("name")
+		if err != nil {
+			return nil, err
+		}
+		key, err := args.GetString("key")
+		if err != nil {
+			return nil, err
+		}
+		delta, err := args.GetInt64("delta")
+		if err != nil {
+			return nil, err
+		}
+		return func() (any, error) {
+			return store.counter(name, key, delta)
+		}, nil
+	}); err != nil {
+		return err
+	}
+
+	rollingSumSpec := bloblang.NewPluginSpec().
+		Impure().
+		Description("Adds a value to a rolling sum stored in a cache resource and returns the sum of values added within a window.").
+		Param(bloblang.NewStringParam("name").Description("The name of the cache resource.")).
+		Param(bloblang.NewStringParam("key").Description("The key of the rolling sum.")).
+		Param(bloblang.NewFloat64Param("value").Description("The value to add.")).
+		Param(bloblang.NewStringParam("window").Description("The duration of the window."))
+
+	if err := env.RegisterFunctionV2("rolling_sum", rollingSumSpec, func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+		name, err := args.GetString("name")
+		if err != nil {
+			return nil, err
+		}
+		key, err := args.GetString("key")
+		if err != nil {
+			return nil, err
+		}
+		value, err := args.GetFloat64("value")
+		if err != nil {
+			return nil, err
+		}
+		window, err := parseWindow(args)
+		if err != nil {
+			return nil, err
+		}
+		return func() (any, error) {
+			return store.rollingSum(name, key, value, window)
+		}, nil
+	}); err != nil {
+		return err
+	}
+
+	rateSpec := bloblang.NewPluginSpec().
+		Impure().
+		Description("Counts an event within a cache resource and returns the average number of events per second within a window.").
+		Param(bloblang.NewStringParam("name").Description("The name of the cache resource.")).
+		Param(bloblang.NewStringParam("key").Description("The key of the rate.")).
+		Param(bloblang.NewStringParam("window").Description("The duration of the window."))
+
+	return env.RegisterFunctionV2("rate", rateSpec, func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+		name, err := args.GetString("name")
+		if err != nil {
+			return nil, err
+		}
+		key, err := args.GetString("key")
+		if err != nil {
+			return nil, err
+		}
+		window, err := parseWindow(args)
+		if err != nil {
+			return nil, err
+		}
+		return func() (any, error) {
+			count, err := store.rollingSum(name, key, 1, window)
+			if err != nil {
+				return nil, err
+			}
+			return count / window.Seconds(), nil
+		}, nil
+	})
+}
@@ -0,0 +1,453 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sloFieldURLs            = "urls"
+	sloFieldDatabase        = "database"
+	sloFieldTable           = "table"
+	sloFieldUsername        = "username"
+	sloFieldPassword        = "password"
+	sloFieldFormat          = "format"
+	sloFieldColumnSeparator = "column_separator"
+	sloFieldColumns         = "columns"
+	sloFieldLabel           = "label"
+	sloFieldLabelPrefix     = "label_prefix"
+	sloFieldHeaders         = "headers"
+	sloFieldTimeout         = "timeout"
+	sloFieldBusyBackoff     = "busy_backoff"
+	sloFieldTLS             = "tls"
+	sloFieldMaxInFlight     = "max_in_flight"
+	sloFieldBatching        = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	busyDefaults := backoff.NewExponentialBackOff()
+	busyDefaults.InitialInterval = time.Second
+	busyDefaults.MaxInterval = time.Second * 30
+	busyDefaults.MaxElapsedTime = time.Minute * 5
+
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.62.0").
+		Summary("Loads batches of messages into an Apache Doris or StarRocks table with Stream Load.").
+		Description(`
+Each batch of messages is loaded into the table with a single https://doris.apache.org/docs/data-operate/import/import-way/stream-load-manual[Stream Load^] request to the frontend, which redirects the request to a backend node. With the `+"`json`"+` format the messages of a batch are sent as a JSON array, and with the `+"`csv`"+` format messages are sent as lines of delimited values.
+
+== Exactly once delivery
+
+Each load is identified by a label, and a label can only be loaded once. When a load is retried after a failure, such as a timeout where it is unknown whether the load succeeded, a load with the same label that has already finished is treated as a success rather than loading the batch again.
+
+By default the label is derived from the table and the content of the batch, so that retries of the same batch always use the same label. This means that identical batches loaded within the label retention period of the cluster are deduplicated, and so it is recommended to set `+"`"+sloFieldLabel+"`"+` to a value that uniquely identifies a batch when the input provides one, such as the partition and offset of a Kafka message.
+
+== Backpressure
+
+When the cluster rejects a load because too many loads are already running, or responds with a 429 or 503 status code, the load is retried with the same label according to `+"`"+sloFieldBusyBackoff+"`"+`.
+`).
+		Fields(
+			service.NewURLListField(sloFieldURLs).
+				Description("A list of frontend URLs, which are tried in turn when a load fails due to a connection error.").
+				Example([]string{"http://localhost:8030"}),
+			service.NewStringField(sloFieldDatabase).
+				Description("The database of the table."),
+			service.NewStringField(sloFieldTable).
+				Description("The table to load messages into."),
+			service.NewStringField(sloFieldUsername).
+				Description("The user to authenticate as.").
+				Default("root"),
+			service.NewStringField(sloFieldPassword).
+				Description("The password of the user.").
+				Default("").
+				Secret(),
+			service.NewStringEnumField(sloFieldFormat, "json", "csv").
+				Description("The format of loaded data. With `csv` each message must be a single line of delimited values.").
+				Default("json"),
+			service.NewStringField(sloFieldColumnSeparator).
+				Description("The column separator of the `csv` format.").
+				Default(",").
+				Advanced(),
+			service.NewStringListField(sloFieldColumns).
+				Description("An optional list of the columns of the table that the loaded data is mapped to, which may include derived columns such as `dt=to_date(ts)`.").
+				Example([]string{"id", "name", "ts", "dt=to_date(ts)"}).
+				Optional(),
+			service.NewInterpolatedStringField(sloFieldLabel).
+				Description("An optional label that uniquely identifies each batch, resolved from the first message of the batch. Labels may only contain letters, digits, `-`, `_` and `:`, other characters are replaced with `_`.").
+				Example(`${! @kafka_topic }-${! @kafka_partition }-${! @kafka_offset }`).
+				Optional(),
+			service.NewStringField(sloFieldLabelPrefix).
+				Description("The prefix of labels derived from the content of batches, when `label` is not set.").
+				Default("connect").
+				Advanced(),
+			service.NewStringMapField(sloFieldHeaders).
+				Description("Additional Stream Load properties to send as headers.").
+				Example(map[string]any{"max_filter_ratio": "0.1", "partial_columns": "true"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewDurationField(sloFieldTimeout).
+				Description("The maximum period of time to wait for a load to complete.").
+				Default("5m").
+				Advanced(),
+			service.NewBackOffField(sloFieldBusyBackoff, false, busyDefaults).
+				Description("The backoff applied when the cluster is too busy to accept a load.").
+				Advanced(),
+			service.NewTLSToggledField(sloFieldTLS),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(sloFieldBatching),
+		).
+		Example("Exactly once from Kafka", "Load messages from a Kafka topic into Doris, labelling each batch with the offset of its first message so that redelivered batches are not loaded twice.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: doris
+    batching:
+      count: 10000
+      period: 5s
+
+output:
+  stream_load:
+    urls: [ http://localhost:8030 ]
+    database: analytics
+    table: events
+    username: root
+    password: ${DORIS_PASSWORD}
+    label: events-${! @kafka_partition }-${! @kafka_offset }
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput("stream_load", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(sloFieldBatching); err != nil {
+				return
+			}
+			out, err = newStreamLoadOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+type streamLoadOutput struct {
+	log *service.Logger
+
+	urls            []*url.URL
+	database, table string
+	username        string
+	password        string
+	format          string
+	columnSeparator string
+	columns         []string
+	label           *service.InterpolatedString
+	labelPrefix     string
+	headers         map[string]string
+	busyBackoff     *backoff.ExponentialBackOff
+
+	client  *http.Client
+	nextURL atomic.Uint32
+}
+
+func newStreamLoadOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*streamLoadOutput, error) {
+	o := &streamLoadOutput{log: mgr.Logger()}
+
+	var err error
+	if o.urls, err = conf.FieldURLList(sloFieldURLs); err != nil {
+		return nil, err
+	}
+	if len(o.urls) == 0 {
+		return nil, errors.New("at least one url must be specified")
+	}
+	if o.database, err = conf.FieldString(sloFieldDatabase); err != nil {
+		return nil, err
+	}
+	if o.table, err = conf.FieldString(sloFieldTable); err != nil {
+		return nil, err
+	}
+	if o.username, err = conf.FieldString(sloFieldUsername); err != nil {
+		return nil, err
+	}
+	if o.password, err = conf.FieldString(sloFieldPassword); err != nil {
+		return nil, err
+	}
+	if o.format, err = conf.FieldString(sloFieldFormat); err != nil {
+		return nil, err
+	}
+	if o.columnSeparator, err = conf.FieldString(sloFieldColumnSeparator); err != nil {
+		return nil, err
+	}
+	if conf.Contains(sloFieldColumns) {
+		if o.columns, err = conf.FieldStringList(sloFieldColumns); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(sloFieldLabel) {
+		if o.label, err = conf.FieldInterpolatedString(sloFieldLabel); err != nil {
+			return nil, err
+		}
+	}
+	if o.labelPrefix, err = conf.FieldString(sloFieldLabelPrefix); err != nil {
+		return nil, err
+	}
+	if o.headers, err = conf.FieldStringMap(sloFieldHeaders); err != nil {
+		return nil, err
+	}
+	if o.busyBackoff, err = conf.FieldBackOff(sloFieldBusyBackoff); err != nil {
+		return nil, err
+	}
+
+	timeout, err := conf.FieldDuration(sloFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(sloFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			// Frontends redirect loads to a backend on a different host, for
+			// which credentials are otherwise dropped.
+			req.SetBasicAuth(o.username, o.password)
+			return nil
+		},
+	}
+	return o, nil
+}
+
+func (*streamLoadOutput) Connect(context.Context) error {
+	return nil
+}
+
+// loadResult is the response to a Stream Load request, which is the same for
+// both Doris and StarRocks.
+type loadResult struct {
+	Label             string `json:"Label"`
+	Status            string `json:"Status"`
+	ExistingJobStatus string `json:"ExistingJobStatus"`
+	Message           string `json:"Message"`
+	NumberLoadedRows  int64  `json:"NumberLoadedRows"`
+	ErrorURL          string `json:"ErrorURL"`
+}
+
+var (
+	errBusy         = errors.New("cluster is too busy to accept the load")
+	invalidLabelRex = regexp.MustCompile(`[^a-zA-Z0-9_:-]`)
+)
+
+func (o *streamLoadOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	var body bytes.Buffer
+	if o.format == "json" {
+		body.WriteByte('[')
+	}
+	for i, msg := range batch {
+		b, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if o.format == "json" {
+				body.WriteByte(',')
+			} else {
+				body.WriteByte('\n')
+			}
+		}
+		if o.format == "json" && !json.Valid(b) {
+			return fmt.Errorf("message %v is not valid JSON", i)
+		}
+		body.Write(bytes.TrimRight(b, "\r\n"))
+	}
+	if o.format == "json" {
+		body.WriteByte(']')
+	}
+
+	label, err := o.batchLabel(batch, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	boff := *o.busyBackoff
+	boff.Reset()
+	for {
+		err := o.load(ctx, label, body.Bytes())
+		if !errors.Is(err, errBusy) {
+			return err
+		}
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		o.log.Debugf("Retrying load %v in %v: %v", label, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (o *streamLoadOutput) batchLabel(batch service.MessageBatch, body []byte) (string, error) {
+	var label string
+	if o.label != nil {
+		var err error
+		if label, err = batch.TryInterpolatedString(0, o.label); err != nil {
+			return "", fmt.Errorf("failed to resolve label: %w", err)
+		}
+	}
+	if label == "" {
+		h := sha256.New()
+		_, _ = h.Write([]byte(o.database + "." + o.table + "\n"))
+		_, _ = h.Write(body)
+		label = o.labelPrefix + "_" + hex.EncodeToString(h.Sum(nil))[:40]
+	}
+	label = invalidLabelRex.ReplaceAllString(label, "_")
+	if len(label) > 128 {
+		label = label[:128]
+	}
+	return label, nil
+}
+
+// load performs a Stream Load request, trying each frontend in turn until one
+// can be reached.
+func (o *streamLoadOutput) load(ctx context.Context, label string, body []byte) error {
+	var err error
+	start := o.nextURL.Load()
+	for i := range uint32(len(o.urls)) {
+		idx := (start + i) % uint32(len(o.urls))
+		var res *loadResult
+		if res, err = o.loadTo(ctx, o.urls[idx], label, body); err != nil {
+			var netErr *url.Error
+			if errors.As(err, &netErr) && ctx.Err() == nil {
+				o.nextURL.Store(idx + 1)
+				continue
+			}
+			return err
+		}
+		return o.checkResult(res)
+	}
+	return err
+}
+
+func (o *streamLoadOutput) loadTo(ctx context.Context, base *url.URL, label string, body []byte) (*loadResult, error) {
+	u := base.JoinPath("api", o.database, o.table, "_stream_load")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(o.username, o.password)
+	req.Header.Set("Expect", "100-continue")
+	req.Header.Set("label", label)
+	req.Header.Set("format", o.format)
+	if o.format == "json" {
+		req.Header.Set("strip_outer_array", "true")
+	} else {
+		req.Header.Set("column_separator", o.columnSeparator)
+	}
+	if len(o.columns) > 0 {
+		req.Header.Set("columns", strings.Join(o.columns, ","))
+	}
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return nil, fmt.Errorf("%w: status %v: %s", errBusy, resp.StatusCode, resBody)
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("load %v failed with status %v: %s", label, resp.StatusCode, resBody)
+	}
+
+	var res loadResult
+	if err := json.Unmarshal(resBody, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse load response: %w", err)
+	}
+	if res.Label == "" {
+		res.Label = label
+	}
+	return &res, nil
+}
+
+func (o *streamLoadOutput) checkResult(res *loadResult) error {
+	switch res.Status {
+	case "Success", "Publish Timeout":
+		o.log.Tracef("Loaded %v rows with label %v", res.NumberLoadedRows, res.Label)
+		return nil
+	case "Label Already Exists":
+		switch res.ExistingJobStatus {
+		case "FINISHED", "VISIBLE", "COMMITTED":
+			o.log.Debugf("Load with label %v has already finished", res.Label)
+			return nil
+		case "RUNNING", "PREPARE", "PREPARED":
+			return fmt.Errorf("%w: load with label %v is still running", errBusy, res.Label)
+		}
+		return fmt.Errorf("load with label %v already exists with status %v", res.Label, res.ExistingJobStatus)
+	}
+
+	if msg := strings.ToLower(res.Message); strings.Contains(msg, "too_many_tasks") || strings.Contains(msg, "too many running") {
+		return fmt.Errorf("%w: %v", errBusy, res.Message)
+	}
+	if res.ErrorURL != "" {
+		return fmt.Errorf("load %v failed: %v, see %v for details", res.Label, res.Message, res.ErrorURL)
+	}
+	return fmt.Errorf("load %v failed: %v", res.Label, res.Message)
+}
+
+func (o *streamLoadOutput) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
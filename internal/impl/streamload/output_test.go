@@ -0,0 +1,230 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamload
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type loadRequest struct {
+	path    string
+	label   string
+	format  string
+	columns string
+	body    string
+}
+
+type testCluster struct {
+	mut       sync.Mutex
+	loads     []loadRequest
+	responses []string
+
+	frontend *httptest.Server
+	backend  *httptest.Server
+}
+
+// newTestCluster starts a frontend that redirects loads to a backend, which
+// responds with the given responses in order.
+func newTestCluster(t *testing.T, responses ...string) *testCluster {
+	t.Helper()
+
+	c := &testCluster{responses: responses}
+	c.backend = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "root" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		c.loads = append(c.loads, loadRequest{
+			path:    r.URL.Path,
+			label:   r.Header.Get("label"),
+			format:  r.Header.Get("format"),
+			columns: r.Header.Get("columns"),
+			body:    string(body),
+		})
+		res := c.responses[0]
+		if len(c.responses) > 1 {
+			c.responses = c.responses[1:]
+		}
+		_, _ = w.Write([]byte(res))
+	}))
+	t.Cleanup(c.backend.Close)
+
+	c.frontend = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, c.backend.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(c.frontend.Close)
+	return c
+}
+
+func newTestOutput(t *testing.T, conf string) *streamLoadOutput {
+	t.Helper()
+
+	parsed, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	out, err := newStreamLoadOutputFromConfig(parsed, service.MockResources())
+	require.NoError(t, err)
+	return out
+}
+
+func TestStreamLoadJSON(t *testing.T) {
+	c := newTestCluster(t, `{"Status":"Success","NumberLoadedRows":2}`)
+	out := newTestOutput(t, `
+urls: [ `+c.frontend.URL+` ]
+database: analytics
+table: events
+password: secret
+label: events-${! @offset }
+`)
+
+	msg1 := service.NewMessage([]byte(`{"id":1}`))
+	msg1.MetaSetMut("offset", 10)
+	require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+		msg1,
+		service.NewMessage([]byte(`{"id":2}`)),
+	}))
+
+	require.Len(t, c.loads, 1)
+	assert.Equal(t, loadRequest{
+		path:   "/api/analytics/events/_stream_load",
+		label:  "events-10",
+		format: "json",
+		body:   `[{"id":1},{"id":2}]`,
+	}, c.loads[0])
+}
+
+func TestStreamLoadCSVDerivedLabel(t *testing.T) {
+	c := newTestCluster(t, `{"Status":"Success"}`)
+	out := newTestOutput(t, `
+urls: [ `+c.frontend.URL+` ]
+database: analytics
+table: events
+password: secret
+format: csv
+columns: [ id, name ]
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("1,foo\n")),
+		service.NewMessage([]byte("2,bar")),
+	}
+	require.NoError(t, out.WriteBatch(t.Context(), batch))
+	require.NoError(t, out.WriteBatch(t.Context(), batch))
+
+	require.Len(t, c.loads, 2)
+	assert.Equal(t, "1,foo\n2,bar", c.loads[0].body)
+	assert.Equal(t, "id,name", c.loads[0].columns)
+	assert.Regexp(t, `^connect_[0-9a-f]{40}$`, c.loads[0].label)
+	assert.Equal(t, c.loads[0].label, c.loads[1].label)
+}
+
+func TestStreamLoadResults(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		loads     int
+		errMsg    string
+	}{
+		{
+			name:      "label already finished",
+			responses: []string{`{"Status":"Label Already Exists","ExistingJobStatus":"FINISHED"}`},
+			loads:     1,
+		},
+		{
+			name: "too many tasks",
+			responses: []string{
+				`{"Status":"Fail","Message":"[TOO_MANY_TASKS]too many running load tasks"}`,
+				`{"Status":"Fail","Message":"too many running txns in db"}`,
+				`{"Status":"Success"}`,
+			},
+			loads: 3,
+		},
+		{
+			name:      "failed load",
+			responses: []string{`{"Status":"Fail","Message":"too many filtered rows","ErrorURL":"http://be/error"}`},
+			loads:     1,
+			errMsg:    "http://be/error",
+		},
+		{
+			name:      "busy timeout",
+			responses: []string{`{"Status":"Label Already Exists","ExistingJobStatus":"RUNNING"}`},
+			loads:     3,
+			errMsg:    "still running",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newTestCluster(t, test.responses...)
+			out := newTestOutput(t, `
+urls: [ `+c.frontend.URL+` ]
+database: analytics
+table: events
+password: secret
+busy_backoff:
+  initial_interval: 1ms
+  max_interval: 1ms
+  max_elapsed_time: 100ms
+`)
+			out.busyBackoff.RandomizationFactor = 0
+
+			err := out.WriteBatch(t.Context(), service.MessageBatch{
+				service.NewMessage([]byte(`{"id":1}`)),
+			})
+			if test.errMsg != "" {
+				require.ErrorContains(t, err, test.errMsg)
+				assert.GreaterOrEqual(t, len(c.loads), test.loads)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, c.loads, test.loads)
+		})
+	}
+}
+
+func TestStreamLoadFailover(t *testing.T) {
+	c := newTestCluster(t, `{"Status":"Success"}`)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	out := newTestOutput(t, `
+urls: [ `+down.URL+`, `+c.frontend.URL+` ]
+database: analytics
+table: events
+password: secret
+`)
+
+	for range 2 {
+		require.NoError(t, out.WriteBatch(t.Context(), service.MessageBatch{
+			service.NewMessage([]byte(`{"id":1}`)),
+		}))
+	}
+	assert.Len(t, c.loads, 2)
+	assert.Equal(t, uint32(1), out.nextURL.Load())
+}
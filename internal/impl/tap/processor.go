@@ -0,0 +1,199 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	tpFieldName    = "name"
+	tpFieldAddress = "address"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Marks a point within a pipeline where operators can tail live messages over HTTP, similar to `kubectl logs` for data.").
+		Description(`
+Messages pass through this processor unchanged. While no clients are attached a tap costs next to nothing, which means taps can be left within production configs and attached to when something needs investigating, without restarting or reconfiguring the pipeline.
+
+Clients tail a tap by opening a streaming `+"`GET /taps/<name>`"+` request to `+"`"+tpFieldAddress+"`"+`, and receive a https://github.com/ndjson/ndjson-spec[newline delimited JSON^] object for each sampled message containing the tap name, the time it was sampled, its metadata, its content as a string, and its error if it has one. The taps served at an address can be listed with `+"`GET /taps`"+`. Taps of all processors and streams configured with the same address share a server, and so tap names must be unique per address.
+
+The following query parameters control what is sent to each client:
+
+`+"`filter`"+`:: A xref:guides:bloblang/about.adoc[Bloblang query] that must yield `+"`true`"+` for a message to be sent, messages for which the query fails are skipped.
+`+"`rate`"+`:: The maximum number of messages per second to send, defaults to 10. Set to `+"`0`"+` for no limit.
+`+"`limit`"+`:: The number of messages after which the stream ends, by default the stream continues until the client disconnects.
+
+Tailing is best effort and never applies back pressure to the pipeline: messages beyond the rate of a client and messages sent to clients that have fallen behind are dropped.
+
+Tap servers aren't authenticated, and so the default address only accepts connections from the local host. Take care before exposing taps more widely as they reveal the contents of messages.`).
+		Fields(
+			service.NewStringField(tpFieldName).
+				Description("The name by which clients attach to the tap.").
+				Examples("post_input", "pre_output"),
+			service.NewStringField(tpFieldAddress).
+				Description("The address on which the tap is served.").
+				Default("localhost:4197").
+				Advanced(),
+		).
+		Example(
+			"Tap inputs and outputs",
+			"Place taps after an input and before an output, and then attach to them with `curl -N 'http://localhost:4197/taps/pre_output?filter=this.amount > 100&rate=1'`.",
+			`
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ orders ]
+    consumer_group: enrichment
+  processors:
+    - tap:
+        name: post_input
+
+pipeline:
+  processors:
+    - mapping: 'root = this.merge({"amount": this.price * this.quantity})'
+
+output:
+  aws_s3:
+    bucket: orders
+    path: ${! @kafka_partition }/${! @kafka_offset }.json
+  processors:
+    - tap:
+        name: pre_output
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterProcessor("tap", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newProcessorFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+// tap samples the messages passing through a point of a pipeline to the
+// subscribers attached to it.
+type tap struct {
+	name string
+
+	mut         sync.Mutex
+	subscribers map[*subscriber]struct{}
+	active      atomic.Int32
+}
+
+func (t *tap) subscribe(sub *subscriber) {
+	t.mut.Lock()
+	t.subscribers[sub] = struct{}{}
+	t.active.Store(int32(len(t.subscribers)))
+	t.mut.Unlock()
+}
+
+func (t *tap) unsubscribe(sub *subscriber) {
+	t.mut.Lock()
+	delete(t.subscribers, sub)
+	t.active.Store(int32(len(t.subscribers)))
+	t.mut.Unlock()
+	sub.close()
+}
+
+func (t *tap) closeSubscribers() {
+	t.mut.Lock()
+	for sub := range t.subscribers {
+		sub.close()
+		delete(t.subscribers, sub)
+	}
+	t.active.Store(0)
+	t.mut.Unlock()
+}
+
+// publish a message to the subscribers of the tap that accept it.
+func (t *tap) publish(msg *service.Message) error {
+	if t.active.Load() == 0 {
+		return nil
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	var data []byte
+	for sub := range t.subscribers {
+		if !sub.matches(msg) || !sub.limiter.Allow() {
+			continue
+		}
+		if data == nil {
+			var err error
+			if data, err = encodeMessage(t.name, msg, time.Now()); err != nil {
+				return err
+			}
+		}
+		sub.offer(data)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	log    *service.Logger
+	tap    *tap
+	server *server
+}
+
+func newProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*processor, error) {
+	name, err := conf.FieldString(tpFieldName)
+	if err != nil {
+		return nil, err
+	}
+	address, err := conf.FieldString(tpFieldAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tap{
+		name:        name,
+		subscribers: map[*subscriber]struct{}{},
+	}
+	s, err := register(address, t, mgr.Logger())
+	if err != nil {
+		return nil, err
+	}
+	return &processor{
+		log:    mgr.Logger(),
+		tap:    t,
+		server: s,
+	}, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	if err := p.tap.publish(msg); err != nil {
+		p.log.Debugf("Failed to sample message for tap %v: %v", p.tap.name, err)
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *processor) Close(ctx context.Context) error {
+	return p.server.deregister(ctx, p.tap)
+}
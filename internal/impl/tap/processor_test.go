@@ -0,0 +1,128 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestProcessor(t *testing.T, conf string) *processor {
+	t.Helper()
+
+	pConf, err := processorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+	p, err := newProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	return p
+}
+
+func testTapURL(p *processor, path string) string {
+	return "http://" + p.server.listener.Addr().String() + path
+}
+
+func TestTapListAndTail(t *testing.T) {
+	first := newTestProcessor(t, `
+name: post_input
+address: 127.0.0.1:0
+`)
+	second := newTestProcessor(t, `
+name: pre_output
+address: 127.0.0.1:0
+`)
+	require.Same(t, first.server, second.server)
+
+	res, err := http.Get(testTapURL(first, "/taps"))
+	require.NoError(t, err)
+	var names []string
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&names))
+	res.Body.Close()
+	assert.Equal(t, []string{"post_input", "pre_output"}, names)
+
+	// Messages are passed through regardless of whether anyone is tailing.
+	msg := service.NewMessage([]byte(`{"id":0}`))
+	batch, err := second.Process(t.Context(), msg)
+	require.NoError(t, err)
+	assert.Equal(t, service.MessageBatch{msg}, batch)
+
+	query := url.Values{"filter": {"this.id % 2 == 1"}, "rate": {"0"}, "limit": {"2"}}
+	res, err = http.Get(testTapURL(second, "/taps/pre_output?"+query.Encode()))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	for i := range 5 {
+		msg := service.NewMessage([]byte(`{"id":` + strconv.Itoa(i) + `}`))
+		msg.MetaSetMut("key", "value")
+		_, err := second.Process(t.Context(), msg)
+		require.NoError(t, err)
+	}
+
+	var tapped []tappedMessage
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		var m tappedMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m))
+		tapped = append(tapped, m)
+	}
+	require.Len(t, tapped, 2)
+	for i, m := range tapped {
+		assert.Equal(t, "pre_output", m.Tap)
+		assert.Equal(t, map[string]string{"key": "value"}, m.Metadata)
+		assert.Equal(t, `{"id":`+strconv.Itoa(1+2*i)+`}`, m.Content)
+	}
+}
+
+func TestTapErrors(t *testing.T) {
+	p := newTestProcessor(t, `
+name: foo
+address: 127.0.0.1:0
+`)
+
+	pConf, err := processorSpec().ParseYAML(`
+name: foo
+address: 127.0.0.1:0
+`, nil)
+	require.NoError(t, err)
+	_, err = newProcessorFromConfig(pConf, service.MockResources())
+	require.ErrorContains(t, err, "already served")
+
+	for path, status := range map[string]int{
+		"/taps/bar":                 http.StatusNotFound,
+		"/taps/foo?rate=nope":       http.StatusBadRequest,
+		"/taps/foo?limit=-1":        http.StatusBadRequest,
+		"/taps/foo?filter=" + "%7B": http.StatusBadRequest,
+	} {
+		res, err := http.Get(testTapURL(p, path))
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		assert.Equal(t, status, res.StatusCode, path)
+	}
+}
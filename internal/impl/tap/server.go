@@ -0,0 +1,301 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	defaultRate = 10
+	sendBuffer  = 64
+)
+
+// servers holds the tap servers of this process by address, which allows the
+// taps of multiple processors and streams to share a listener.
+var servers = struct {
+	mut sync.Mutex
+	m   map[string]*server
+}{m: map[string]*server{}}
+
+// server serves the live messages passing through the taps registered with it
+// to HTTP clients.
+type server struct {
+	address  string
+	log      *service.Logger
+	listener net.Listener
+	http     *http.Server
+
+	mut  sync.Mutex
+	taps map[string]*tap
+}
+
+// register a tap with the server listening on an address, starting the server
+// if it isn't already running.
+func register(address string, t *tap, log *service.Logger) (*server, error) {
+	servers.mut.Lock()
+	defer servers.mut.Unlock()
+
+	s, exists := servers.m[address]
+	if !exists {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		s = &server{
+			address:  address,
+			log:      log,
+			listener: listener,
+			taps:     map[string]*tap{},
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /taps", s.handleList)
+		mux.HandleFunc("GET /taps/{name}", s.handleTail)
+		s.http = &http.Server{Handler: mux}
+		go func() {
+			log.Infof("Serving message taps at: http://%v/taps", listener.Addr())
+			if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Message tap server error: %v", err)
+			}
+		}()
+		servers.m[address] = s
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if _, exists := s.taps[t.name]; exists {
+		return nil, fmt.Errorf("a tap named %v is already served at %v", t.name, address)
+	}
+	s.taps[t.name] = t
+	return s, nil
+}
+
+// deregister a tap from the server, which is shut down once it serves no taps.
+func (s *server) deregister(ctx context.Context, t *tap) error {
+	servers.mut.Lock()
+	defer servers.mut.Unlock()
+
+	s.mut.Lock()
+	if s.taps[t.name] == t {
+		delete(s.taps, t.name)
+	}
+	remaining := len(s.taps)
+	s.mut.Unlock()
+	t.closeSubscribers()
+
+	if remaining > 0 || servers.m[s.address] != s {
+		return nil
+	}
+	delete(servers.m, s.address)
+	return s.http.Shutdown(ctx)
+}
+
+func (s *server) handleList(w http.ResponseWriter, _ *http.Request) {
+	s.mut.Lock()
+	names := make([]string, 0, len(s.taps))
+	for name := range s.taps {
+		names = append(names, name)
+	}
+	s.mut.Unlock()
+	slices.Sort(names)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(names)
+}
+
+func (s *server) handleTail(w http.ResponseWriter, r *http.Request) {
+	s.mut.Lock()
+	t, exists := s.taps[r.PathValue("name")]
+	s.mut.Unlock()
+	if !exists {
+		http.Error(w, "Tap not found", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, err := subscriberFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t.subscribe(sub)
+	defer t.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var sent int
+	for {
+		select {
+		case data := <-sub.send:
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			flusher.Flush()
+			if sent++; sub.limit > 0 && sent >= sub.limit {
+				return
+			}
+		case <-sub.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// subscriber is a client tailing the messages of a tap.
+type subscriber struct {
+	filter  *bloblang.Executor
+	limiter *rate.Limiter
+	limit   int
+
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+// subscriberFromQuery creates a subscriber from the query parameters of a tail
+// request:
+//
+//   - filter: a Bloblang query that must yield true for a message to be sent.
+//   - rate: the maximum number of messages per second, 0 for no limit.
+//   - limit: the number of messages after which the stream ends, 0 for no limit.
+func subscriberFromQuery(r *http.Request) (*subscriber, error) {
+	q := r.URL.Query()
+	sub := &subscriber{
+		limiter: rate.NewLimiter(defaultRate, 1),
+		send:    make(chan []byte, sendBuffer),
+		done:    make(chan struct{}),
+	}
+
+	if filter := q.Get("filter"); filter != "" {
+		exec, err := bloblang.Parse(filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse filter: %w", err)
+		}
+		sub.filter = exec
+	}
+	if rateStr := q.Get("rate"); rateStr != "" {
+		perSecond, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || perSecond < 0 || math.IsNaN(perSecond) {
+			return nil, fmt.Errorf("invalid rate: %v", rateStr)
+		}
+		if perSecond == 0 {
+			sub.limiter = rate.NewLimiter(rate.Inf, 1)
+		} else {
+			sub.limiter = rate.NewLimiter(rate.Limit(perSecond), max(1, int(perSecond)))
+		}
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit: %v", limitStr)
+		}
+		sub.limit = limit
+	}
+	return sub, nil
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// matches returns whether a message passes the filter of the subscriber.
+// Messages for which the filter fails are skipped.
+func (s *subscriber) matches(msg *service.Message) bool {
+	if s.filter == nil {
+		return true
+	}
+	res, err := msg.BloblangQuery(s.filter)
+	if err != nil || res == nil {
+		return false
+	}
+	v, err := res.AsStructured()
+	if err != nil {
+		return false
+	}
+	b, _ := v.(bool)
+	return b
+}
+
+// offer a sampled message to the subscriber without blocking, the message is
+// dropped when the subscriber has fallen behind.
+func (s *subscriber) offer(data []byte) {
+	select {
+	case s.send <- data:
+	default:
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// tappedMessage is the JSON representation of each message sent to clients.
+type tappedMessage struct {
+	Tap       string            `json:"tap"`
+	Timestamp time.Time         `json:"timestamp"`
+	Metadata  map[string]string `json:"metadata"`
+	Content   string            `json:"content"`
+	Error     string            `json:"error,omitempty"`
+}
+
+func encodeMessage(name string, msg *service.Message, now time.Time) ([]byte, error) {
+	content, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	m := tappedMessage{
+		Tap:       name,
+		Timestamp: now,
+		Metadata:  map[string]string{},
+		Content:   string(content),
+	}
+	_ = msg.MetaWalk(func(k, v string) error {
+		m.Metadata[k] = v
+		return nil
+	})
+	if err := msg.GetError(); err != nil {
+		m.Error = err.Error()
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+var _ service.Processor = (*dissectProcessor)(nil)
+
+func init() {
+	service.MustRegisterProcessor(
+		"dissect",
+		newDissectProcessorSpec(),
+		newDissectProcessor,
+	)
+}
+
+const (
+	dpFieldPattern         = "pattern"
+	dpFieldTextMapping     = "text_mapping"
+	dpFieldTargetPath      = "target_path"
+	dpFieldAppendSeparator = "append_separator"
+)
+
+func newDissectProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("Parsing").
+		Version("4.62.0").
+		Summary("Splits unstructured text into fields with a pattern of delimiters.").
+		Description(`
+Dissect patterns describe text as fields separated by literal delimiters, for example the pattern `+"`%{ip} - - [%{ts}] \"%{method} %{path} %{}\" %{status}`"+` splits an access log line into the fields `+"`ip`, `ts`, `method`, `path` and `status`"+`. Unlike the `+"xref:components:processors/grok.adoc[`grok` processor]"+` no regular expressions are evaluated, which makes dissect much cheaper for text with a consistent layout.
+
+Each field is set as a string at the `+"`target_path`"+` of the message, or merged into the root of the message when the path is empty. Messages that aren't structured, such as raw log lines, are replaced with an object of the fields. Text following the last delimiter of a pattern is ignored, and messages that don't match the pattern are flagged as failed, and can be handled with xref:configuration:error_handling.adoc[error handling].
+
+== Key modifiers
+
+The name of a field can be prefixed or suffixed with modifiers that change how its value is used:
+
+|===
+| Modifier | Example | Description
+
+| Skip
+| `+"`%{}` or `%{?name}`"+`
+| The value is matched and discarded.
+
+| Append
+| `+"`%{+name}` or `%{+name/2}`"+`
+| The value is appended to the field of the same name, joined by the `+"`append_separator`"+`, optionally in the order of the number following a slash.
+
+| Reference
+| `+"`%{*name}` and `%{&name}`"+`
+| The value of the `+"`*`"+` field is used as the name of a field with the value of the `+"`&`"+` field of the same name.
+
+| Right padding
+| `+"`%{name->}`"+`
+| Repetitions of the delimiter following the field are skipped, which is useful for text aligned with padding.
+|===`).
+		Fields(
+			service.NewStringField(dpFieldPattern).
+				Description("The dissect pattern to split text with.").
+				Examples(
+					`%{ts} %{+ts} %{level->} %{logger}: %{message}`,
+					`%{ip} - %{user} [%{ts}] "%{method} %{path} %{}" %{status} %{size}`,
+				),
+			service.NewBloblangField(dpFieldTextMapping).
+				Description("The text to split. By default, the processor uses the entire payload as a string.").
+				Example("this.message").
+				Optional(),
+			service.NewStringField(dpFieldTargetPath).
+				Description("The dot separated path of the message to set the fields at. When empty the fields are merged into the root of the message.").
+				Example("parsed").
+				Default(""),
+			service.NewStringField(dpFieldAppendSeparator).
+				Description("The separator joining the values appended to a field.").
+				Default(" ").
+				Advanced(),
+		).
+		Example(
+			"Application logs",
+			"Split application log lines with padded levels into structured fields.",
+			`
+pipeline:
+  processors:
+    - dissect:
+        pattern: '%{ts} %{+ts} %{level->} %{logger}: %{message}'
+`,
+		)
+}
+
+func newDissectProcessor(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+	p := &dissectProcessor{}
+
+	pattern, err := conf.FieldString(dpFieldPattern)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Contains(dpFieldTextMapping) {
+		if p.text, err = conf.FieldBloblang(dpFieldTextMapping); err != nil {
+			return nil, err
+		}
+	}
+	if p.targetPath, err = conf.FieldString(dpFieldTargetPath); err != nil {
+		return nil, err
+	}
+	appendSeparator, err := conf.FieldString(dpFieldAppendSeparator)
+	if err != nil {
+		return nil, err
+	}
+	if p.pattern, err = parseDissectPattern(pattern, appendSeparator); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dpFieldPattern, err)
+	}
+	return p, nil
+}
+
+//------------------------------------------------------------------------------
+
+type dissectModifier int
+
+const (
+	dissectModNone dissectModifier = iota
+	dissectModSkip
+	dissectModAppend
+	dissectModRefName
+	dissectModRefValue
+)
+
+// dissectKey is a field of a pattern along with the delimiter that follows it,
+// which is empty for a field at the end of the pattern.
+type dissectKey struct {
+	name        string
+	modifier    dissectModifier
+	order       int
+	rightPad    bool
+	delimiter   string
+	appendIndex int
+}
+
+type dissectPattern struct {
+	prefix          string
+	keys            []dissectKey
+	appendSeparator string
+}
+
+func parseDissectPattern(pattern, appendSeparator string) (*dissectPattern, error) {
+	p := &dissectPattern{appendSeparator: appendSeparator}
+
+	rest := pattern
+	start := strings.Index(rest, "%{")
+	if start < 0 {
+		return nil, errors.New("pattern must contain at least one field")
+	}
+	p.prefix, rest = rest[:start], rest[start:]
+
+	appendCounts := map[string]int{}
+	for rest != "" {
+		end := strings.Index(rest, "}")
+		if end < 0 {
+			return nil, fmt.Errorf("field %q is not closed", rest)
+		}
+		key, err := parseDissectKey(rest[2:end])
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[end+1:]
+
+		next := strings.Index(rest, "%{")
+		if next < 0 {
+			next = len(rest)
+		}
+		key.delimiter, rest = rest[:next], rest[next:]
+		if key.delimiter == "" && rest != "" {
+			return nil, fmt.Errorf("field %q must be followed by a delimiter", key.name)
+		}
+
+		if key.modifier == dissectModAppend || key.modifier == dissectModNone {
+			key.appendIndex = appendCounts[key.name]
+			appendCounts[key.name]++
+		}
+		p.keys = append(p.keys, key)
+	}
+
+	refs := map[string]int{}
+	for _, k := range p.keys {
+		switch k.modifier {
+		case dissectModRefName:
+			refs[k.name]++
+		case dissectModRefValue:
+			refs[k.name]--
+		}
+	}
+	for name, n := range refs {
+		if n != 0 {
+			return nil, fmt.Errorf("reference field %q must have exactly one * and one & field", name)
+		}
+	}
+	return p, nil
+}
+
+func parseDissectKey(s string) (dissectKey, error) {
+	var k dissectKey
+	if before, found := strings.CutSuffix(s, "->"); found {
+		k.rightPad = true
+		s = before
+	}
+
+	switch {
+	case s == "":
+		k.modifier = dissectModSkip
+		return k, nil
+	case strings.HasPrefix(s, "?"):
+		k.modifier = dissectModSkip
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		k.modifier = dissectModAppend
+		s = s[1:]
+		if name, order, found := strings.Cut(s, "/"); found {
+			n, err := strconv.Atoi(order)
+			if err != nil {
+				return k, fmt.Errorf("invalid append order of field %q: %w", name, err)
+			}
+			s, k.order = name, n
+		}
+	case strings.HasPrefix(s, "*"):
+		k.modifier = dissectModRefName
+		s = s[1:]
+	case strings.HasPrefix(s, "&"):
+		k.modifier = dissectModRefValue
+		s = s[1:]
+	}
+	if s == "" && k.modifier != dissectModSkip {
+		return k, errors.New("fields with a modifier must have a name")
+	}
+	k.name = s
+	return k, nil
+}
+
+type dissectAppend struct {
+	order int
+	value string
+}
+
+// dissect splits text into fields, returning an error when the text doesn't
+// match the pattern.
+func (p *dissectPattern) dissect(text string) (map[string]any, error) {
+	if !strings.HasPrefix(text, p.prefix) {
+		return nil, fmt.Errorf("text does not begin with %q", p.prefix)
+	}
+	pos := len(p.prefix)
+
+	appends := map[string][]dissectAppend{}
+	var appendNames []string
+	refNames := map[string]string{}
+	refValues := map[string]string{}
+
+	for _, k := range p.keys {
+		var value string
+		if k.delimiter == "" {
+			value, pos = text[pos:], len(text)
+		} else {
+			idx := strings.Index(text[pos:], k.delimiter)
+			if idx < 0 {
+				return nil, fmt.Errorf("delimiter %q was not found", k.delimiter)
+			}
+			value = text[pos : pos+idx]
+			pos += idx + len(k.delimiter)
+			if k.rightPad {
+				for strings.HasPrefix(text[pos:], k.delimiter) {
+					pos += len(k.delimiter)
+				}
+			}
+		}
+
+		switch k.modifier {
+		case dissectModSkip:
+		case dissectModRefName:
+			refNames[k.name] = value
+		case dissectModRefValue:
+			refValues[k.name] = value
+		default:
+			if _, exists := appends[k.name]; !exists {
+				appendNames = append(appendNames, k.name)
+			}
+			order := k.order
+			if k.modifier == dissectModNone || order == 0 {
+				order = k.appendIndex
+			}
+			appends[k.name] = append(appends[k.name], dissectAppend{order: order, value: value})
+		}
+	}
+
+	fields := make(map[string]any, len(appendNames)+len(refNames))
+	for _, name := range appendNames {
+		parts := appends[name]
+		sort.SliceStable(parts, func(i, j int) bool {
+			return parts[i].order < parts[j].order
+		})
+		values := make([]string, len(parts))
+		for i, part := range parts {
+			values[i] = part.value
+		}
+		fields[name] = strings.Join(values, p.appendSeparator)
+	}
+	for ref, name := range refNames {
+		fields[name] = refValues[ref]
+	}
+	return fields, nil
+}
+
+//------------------------------------------------------------------------------
+
+type dissectProcessor struct {
+	pattern    *dissectPattern
+	text       *bloblang.Executor
+	targetPath string
+}
+
+// Process implements service.Processor.
+func (p *dissectProcessor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	var b []byte
+	var err error
+	if p.text != nil {
+		res, err := msg.BloblangQuery(p.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s execution error: %w", dpFieldTextMapping, err)
+		}
+		if b, err = res.AsBytes(); err != nil {
+			return nil, err
+		}
+	} else if b, err = msg.AsBytes(); err != nil {
+		return nil, err
+	}
+
+	fields, err := p.pattern.dissect(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages that aren't objects, such as raw log lines, are replaced.
+	var root map[string]any
+	if structured, err := msg.AsStructuredMut(); err == nil {
+		root, _ = structured.(map[string]any)
+	}
+	if root == nil {
+		root = map[string]any{}
+	}
+
+	if p.targetPath == "" {
+		for k, v := range fields {
+			root[k] = v
+		}
+	} else {
+		gObj := gabs.Wrap(root)
+		if _, err := gObj.SetP(fields, p.targetPath); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", dpFieldTargetPath, err)
+		}
+	}
+	msg.SetStructuredMut(root)
+	return service.MessageBatch{msg}, nil
+}
+
+// Close implements service.Processor.
+func (*dissectProcessor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestDissectPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		exp     map[string]any
+	}{
+		{
+			name:    "access log",
+			pattern: `%{ip} - %{user} [%{ts}] "%{method} %{path} %{}" %{status} %{size}`,
+			text:    `10.0.0.1 - alice [10/Oct/2024:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 2326`,
+			exp: map[string]any{
+				"ip":     "10.0.0.1",
+				"user":   "alice",
+				"ts":     "10/Oct/2024:13:55:36 +0000",
+				"method": "GET",
+				"path":   "/index.html",
+				"status": "200",
+				"size":   "2326",
+			},
+		},
+		{
+			name:    "append and right padding",
+			pattern: `%{ts} %{+ts} %{level->} %{logger}: %{message}`,
+			text:    `2024-10-10 13:55:36,123 INFO    com.example.App: Started in 1.2 seconds`,
+			exp: map[string]any{
+				"ts":      "2024-10-10 13:55:36,123",
+				"level":   "INFO",
+				"logger":  "com.example.App",
+				"message": "Started in 1.2 seconds",
+			},
+		},
+		{
+			name:    "append order",
+			pattern: `%{+name/2} %{+name/1} %{?ignored}`,
+			text:    `world hello !`,
+			exp: map[string]any{
+				"name": "hello world",
+			},
+		},
+		{
+			name:    "reference",
+			pattern: `[%{ts}] %{*field}=%{&field}`,
+			text:    `[noon] user=bob`,
+			exp: map[string]any{
+				"ts":   "noon",
+				"user": "bob",
+			},
+		},
+		{
+			name:    "prefix and suffix",
+			pattern: `<%{pri}>%{msg};`,
+			text:    `<13>hello; ignored`,
+			exp: map[string]any{
+				"pri": "13",
+				"msg": "hello",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := parseDissectPattern(test.pattern, " ")
+			require.NoError(t, err)
+
+			res, err := p.dissect(test.text)
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestDissectPatternErrors(t *testing.T) {
+	for _, pattern := range []string{
+		`no fields`,
+		`%{a`,
+		`%{a}%{b}`,
+		`%{+a/x} %{b}`,
+		`%{*a} %{b}`,
+		`%{+} %{b}`,
+	} {
+		_, err := parseDissectPattern(pattern, " ")
+		require.Error(t, err, pattern)
+	}
+
+	p, err := parseDissectPattern(`%{a} [%{b}]`, " ")
+	require.NoError(t, err)
+	_, err = p.dissect(`foo bar`)
+	require.ErrorContains(t, err, "was not found")
+
+	p, err = parseDissectPattern(`<%{a}>`, " ")
+	require.NoError(t, err)
+	_, err = p.dissect(`foo`)
+	require.ErrorContains(t, err, "does not begin with")
+}
+
+func newTestDissectProcessor(t *testing.T, yamlStr string) service.Processor {
+	t.Helper()
+	conf, err := newDissectProcessorSpec().ParseYAML(yamlStr, nil)
+	require.NoError(t, err)
+	proc, err := newDissectProcessor(conf, service.MockResources())
+	require.NoError(t, err)
+	return proc
+}
+
+func processDissect(t *testing.T, proc service.Processor, input string) any {
+	t.Helper()
+	batch, err := proc.Process(t.Context(), service.NewMessage([]byte(input)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	res, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	return res
+}
+
+func TestDissectProcessor(t *testing.T) {
+	proc := newTestDissectProcessor(t, `
+pattern: '%{level}: %{message}'
+`)
+	assert.Equal(t, map[string]any{
+		"level":   "WARN",
+		"message": "disk almost full",
+	}, processDissect(t, proc, `WARN: disk almost full`))
+
+	proc = newTestDissectProcessor(t, `
+pattern: '%{level}: %{message}'
+text_mapping: 'root = this.line'
+target_path: parsed.line
+`)
+	assert.Equal(t, map[string]any{
+		"line": "ERROR: boom",
+		"host": "foo",
+		"parsed": map[string]any{
+			"line": map[string]any{
+				"level":   "ERROR",
+				"message": "boom",
+			},
+		},
+	}, processDissect(t, proc, `{"line":"ERROR: boom","host":"foo"}`))
+
+	_, err := proc.Process(t.Context(), service.NewMessage([]byte(`{"line":"nope"}`)))
+	require.Error(t, err)
+
+	conf, err := newDissectProcessorSpec().ParseYAML(`pattern: '%{a}%{b}'`, nil)
+	require.NoError(t, err)
+	_, err = newDissectProcessor(conf, service.MockResources())
+	require.ErrorContains(t, err, "must be followed by a delimiter")
+}
@@ -0,0 +1,358 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// textBlock is a paragraph, heading or list item extracted from a document.
+type textBlock struct {
+	// The page or slide the block belongs to starting from 1, or 0 for
+	// formats without pages.
+	page int
+	// The level of a heading from 1 to 6, or 0 for body text.
+	heading  int
+	listItem bool
+	text     string
+}
+
+func extractPDF(b []byte) (blocks []textBlock, pages int, err error) {
+	// The PDF reader panics on some malformed documents.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed pdf: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, 0, err
+	}
+	pages = r.NumPage()
+	for i := 1; i <= pages; i++ {
+		p := r.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		text, err := p.GetPlainText(nil)
+		if err != nil {
+			return nil, 0, fmt.Errorf("page %v: %w", i, err)
+		}
+		for _, para := range splitParagraphs(text) {
+			blocks = append(blocks, textBlock{page: i, text: para})
+		}
+	}
+	return blocks, pages, nil
+}
+
+func extractDOCX(zr *zip.Reader) ([]textBlock, error) {
+	f, err := zr.Open("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		blocks  []textBlock
+		current textBlock
+		text    strings.Builder
+		inPara  bool
+	)
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				inPara = true
+				current = textBlock{}
+				text.Reset()
+			case "pStyle":
+				current.heading = docxHeadingLevel(xmlAttr(t, "val"))
+			case "numPr":
+				current.listItem = true
+			case "tab":
+				if inPara {
+					text.WriteByte('\t')
+				}
+			case "br", "cr":
+				if inPara {
+					text.WriteByte('\n')
+				}
+			case "t":
+				var s string
+				if err := dec.DecodeElement(&s, &t); err != nil {
+					return nil, err
+				}
+				if inPara {
+					text.WriteString(s)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" && inPara {
+				inPara = false
+				if current.text = strings.TrimSpace(text.String()); current.text != "" {
+					if current.heading > 0 {
+						current.listItem = false
+					}
+					blocks = append(blocks, current)
+				}
+			}
+		}
+	}
+}
+
+func docxHeadingLevel(style string) int {
+	style = strings.ToLower(style)
+	if style == "title" {
+		return 1
+	}
+	if level, err := strconv.Atoi(strings.TrimPrefix(style, "heading")); err == nil && strings.HasPrefix(style, "heading") {
+		return min(max(level, 1), 6)
+	}
+	return 0
+}
+
+var pptxSlidePattern = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+func extractPPTX(zr *zip.Reader) (blocks []textBlock, slides int, err error) {
+	type slideFile struct {
+		num  int
+		file *zip.File
+	}
+	var files []slideFile
+	for _, f := range zr.File {
+		if m := pptxSlidePattern.FindStringSubmatch(f.Name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			files = append(files, slideFile{num: n, file: f})
+		}
+	}
+	slices.SortFunc(files, func(a, b slideFile) int { return a.num - b.num })
+
+	for i, sf := range files {
+		sBlocks, err := extractPPTXSlide(sf.file, i+1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%v: %w", path.Base(sf.file.Name), err)
+		}
+		blocks = append(blocks, sBlocks...)
+	}
+	return blocks, len(files), nil
+}
+
+func extractPPTXSlide(zf *zip.File, page int) ([]textBlock, error) {
+	f, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		blocks  []textBlock
+		current textBlock
+		text    strings.Builder
+		isTitle bool
+		inPara  bool
+	)
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "sp":
+				isTitle = false
+			case "ph":
+				phType := xmlAttr(t, "type")
+				isTitle = phType == "title" || phType == "ctrTitle"
+			case "p":
+				inPara = true
+				current = textBlock{page: page}
+				text.Reset()
+			case "buChar", "buAutoNum":
+				current.listItem = true
+			case "br":
+				if inPara {
+					text.WriteByte('\n')
+				}
+			case "t":
+				var s string
+				if err := dec.DecodeElement(&s, &t); err != nil {
+					return nil, err
+				}
+				if inPara {
+					text.WriteString(s)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" && inPara {
+				inPara = false
+				if current.text = strings.TrimSpace(text.String()); current.text != "" {
+					if isTitle {
+						current.heading = 1
+						current.listItem = false
+					}
+					blocks = append(blocks, current)
+				}
+			}
+		}
+	}
+}
+
+func xmlAttr(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func extractHTML(b []byte) ([]textBlock, error) {
+	doc, err := html.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		blocks []textBlock
+		text   strings.Builder
+	)
+	flush := func(heading int, listItem bool) {
+		if s := strings.TrimSpace(text.String()); s != "" {
+			blocks = append(blocks, textBlock{heading: heading, listItem: listItem, text: s})
+		}
+		text.Reset()
+	}
+
+	writeSpace := func() {
+		if str := text.String(); str != "" && !isSpace(rune(str[len(str)-1])) {
+			text.WriteByte(' ')
+		}
+	}
+
+	var walk func(n *html.Node, pre bool)
+	walk = func(n *html.Node, pre bool) {
+		switch n.Type {
+		case html.TextNode:
+			if pre {
+				text.WriteString(n.Data)
+			} else {
+				// Collapse whitespace whilst retaining a single space
+				// between adjacent inline elements.
+				s := strings.Join(strings.Fields(n.Data), " ")
+				if s == "" || isSpace(rune(n.Data[0])) {
+					writeSpace()
+				}
+				text.WriteString(s)
+				if s != "" && isSpace(rune(n.Data[len(n.Data)-1])) {
+					writeSpace()
+				}
+			}
+			return
+		case html.ElementNode:
+			switch n.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Head, atom.Svg:
+				return
+			case atom.Br:
+				text.WriteByte('\n')
+				return
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				flush(0, false)
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					walk(c, pre)
+				}
+				flush(int(n.Data[1]-'0'), false)
+				return
+			case atom.Li:
+				flush(0, false)
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					walk(c, pre)
+				}
+				flush(0, true)
+				return
+			case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer,
+				atom.Nav, atom.Aside, atom.Blockquote, atom.Pre, atom.Ul, atom.Ol, atom.Table, atom.Tr,
+				atom.Dl, atom.Dt, atom.Dd, atom.Figure, atom.Figcaption, atom.Hr:
+				flush(0, false)
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					walk(c, pre || n.DataAtom == atom.Pre)
+				}
+				flush(0, false)
+				return
+			case atom.Td, atom.Th:
+				if text.Len() > 0 {
+					text.WriteString(" | ")
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, pre)
+		}
+	}
+	walk(doc, false)
+	flush(0, false)
+	return blocks, nil
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+func extractPlainText(b []byte) []textBlock {
+	var blocks []textBlock
+	for _, para := range splitParagraphs(string(b)) {
+		blocks = append(blocks, textBlock{text: para})
+	}
+	return blocks
+}
+
+// splitParagraphs splits text on blank lines, trimming surrounding space.
+func splitParagraphs(s string) []string {
+	var paras []string
+	for _, p := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paras = append(paras, p)
+		}
+	}
+	return paras
+}
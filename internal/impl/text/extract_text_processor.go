@@ -0,0 +1,323 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	etpFieldFormat       = "format"
+	etpFieldOutputFormat = "output_format"
+	etpFieldSplit        = "split"
+)
+
+func init() {
+	service.MustRegisterProcessor(
+		"extract_text",
+		newExtractTextSpec(),
+		newExtractText,
+	)
+}
+
+func newExtractTextSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("AI").
+		Version("4.62.0").
+		Summary("Extracts text from PDF, DOCX, PPTX and HTML documents as Markdown or plain text.").
+		Description(`
+Converts documents into text suitable for chunking and embedding, where headings, list items and paragraphs are preserved as Markdown. Text is extracted locally and so scanned documents without a text layer produce no text, and PDF documents have no heading structure.
+
+Documents can optionally be split into a message per page, where pages are PDF pages or PPTX slides, or a message per section, where a section begins at each heading.
+
+== Metadata
+
+This processor adds the following metadata fields to each message:
+
+- extract_text_format: The format of the document.
+- extract_text_page_count: The number of pages or slides within the document, only set for PDF and PPTX documents.
+- extract_text_page: The page or slide number of the message, starting from 1. Only set when splitting by page, or when splitting PDF and PPTX documents by heading in which case it is the page that the section begins on.
+- extract_text_heading: The text of the heading that begins the section, only set when splitting by heading.
+- extract_text_heading_level: The level of the heading that begins the section from 1 to 6, only set when splitting by heading.
+- extract_text_heading_path: An array of the headings leading to and including the section heading, only set when splitting by heading.
+
+Sections that precede the first heading have an empty heading with a level of 0.
+`).
+		Fields(
+			service.NewStringAnnotatedEnumField(etpFieldFormat, map[string]string{
+				"auto": "Detect the format from the contents of each message.",
+				"pdf":  "Portable Document Format.",
+				"docx": "Microsoft Word documents.",
+				"pptx": "Microsoft PowerPoint presentations.",
+				"html": "HTML documents, where scripts, styles and other non-visible elements are removed.",
+				"text": "Plain text, where paragraphs are separated by blank lines.",
+			}).
+				Description("The format of documents.").
+				Default("auto"),
+			service.NewStringAnnotatedEnumField(etpFieldOutputFormat, map[string]string{
+				"markdown": "Headings and list items are written as Markdown.",
+				"text":     "Headings and list items are written as plain paragraphs.",
+			}).
+				Description("The format of extracted text.").
+				Default("markdown"),
+			service.NewStringAnnotatedEnumField(etpFieldSplit, map[string]string{
+				"none":    "Emit a single message for each document.",
+				"page":    "Emit a message for each page or slide of a document. Documents without pages are emitted as a single page.",
+				"heading": "Emit a message for each section of a document, where a section begins at each heading.",
+			}).
+				Description("Whether to split documents into multiple messages.").
+				Default("none"),
+		).
+		Example("RAG ingestion", "Extract the text of documents downloaded from a bucket into a message per section, which are then chunked for embedding with the heading path retained for context.", `
+input:
+  aws_s3:
+    bucket: documents
+    scanner:
+      to_the_end: {}
+pipeline:
+  processors:
+    - extract_text:
+        split: heading
+    - text_chunker:
+        strategy: markdown
+        chunk_size: 1024
+    - mapping: |
+        root.text = content().string()
+        root.source = @s3_key
+        root.section = @extract_text_heading_path.join(" > ")
+`)
+}
+
+type extractText struct {
+	format   string
+	markdown bool
+	split    string
+}
+
+func newExtractText(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+	e := &extractText{}
+	var err error
+	if e.format, err = conf.FieldString(etpFieldFormat); err != nil {
+		return nil, err
+	}
+	var outputFormat string
+	if outputFormat, err = conf.FieldString(etpFieldOutputFormat); err != nil {
+		return nil, err
+	}
+	e.markdown = outputFormat == "markdown"
+	if e.split, err = conf.FieldString(etpFieldSplit); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+var errUnknownDocumentFormat = errors.New("unable to detect document format")
+
+// detectDocumentFormat detects the format of a document from its contents.
+func detectDocumentFormat(b []byte) (string, error) {
+	if bytes.HasPrefix(b, []byte("%PDF-")) {
+		return "pdf", nil
+	}
+	if bytes.HasPrefix(b, []byte("PK\x03\x04")) {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return "", err
+		}
+		for _, f := range zr.File {
+			switch f.Name {
+			case "word/document.xml":
+				return "docx", nil
+			case "ppt/presentation.xml":
+				return "pptx", nil
+			}
+		}
+		return "", fmt.Errorf("%w: unrecognised zip archive", errUnknownDocumentFormat)
+	}
+	if strings.HasPrefix(http.DetectContentType(b), "text/html") {
+		return "html", nil
+	}
+	if utf8.Valid(b) {
+		return "text", nil
+	}
+	return "", errUnknownDocumentFormat
+}
+
+// extractBlocks returns the blocks of a document and its number of pages,
+// which is zero for formats without pages.
+func extractBlocks(format string, b []byte) ([]textBlock, int, error) {
+	switch format {
+	case "pdf":
+		return extractPDF(b)
+	case "docx", "pptx":
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, 0, err
+		}
+		if format == "pptx" {
+			return extractPPTX(zr)
+		}
+		blocks, err := extractDOCX(zr)
+		return blocks, 0, err
+	case "html":
+		blocks, err := extractHTML(b)
+		return blocks, 0, err
+	case "text":
+		return extractPlainText(b), 0, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported format: %v", format)
+}
+
+func renderBlocks(blocks []textBlock, markdown bool) string {
+	var sb strings.Builder
+	for i, b := range blocks {
+		if i > 0 {
+			if markdown && b.listItem && blocks[i-1].listItem {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		if markdown {
+			if b.heading > 0 {
+				sb.WriteString(strings.Repeat("#", b.heading))
+				sb.WriteByte(' ')
+			} else if b.listItem {
+				sb.WriteString("- ")
+			}
+		}
+		sb.WriteString(b.text)
+	}
+	return sb.String()
+}
+
+type textSection struct {
+	page        int
+	heading     string
+	level       int
+	headingPath []any
+	blocks      []textBlock
+}
+
+// sectionsByHeading splits blocks into sections that begin at each heading.
+func sectionsByHeading(blocks []textBlock) []textSection {
+	var (
+		sections []textSection
+		current  = textSection{headingPath: []any{}}
+		// The headings leading to the current section indexed by level.
+		path [6]string
+	)
+	for _, b := range blocks {
+		if b.heading > 0 {
+			if len(current.blocks) > 0 {
+				sections = append(sections, current)
+			}
+			path[b.heading-1] = b.text
+			clear(path[b.heading:])
+
+			current = textSection{page: b.page, heading: b.text, level: b.heading, headingPath: []any{}}
+			for _, h := range path[:b.heading] {
+				if h != "" {
+					current.headingPath = append(current.headingPath, h)
+				}
+			}
+		} else if len(current.blocks) == 0 {
+			current.page = b.page
+		}
+		current.blocks = append(current.blocks, b)
+	}
+	if len(current.blocks) > 0 || len(sections) == 0 {
+		sections = append(sections, current)
+	}
+	return sections
+}
+
+func (e *extractText) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	b, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	format := e.format
+	if format == "auto" {
+		if format, err = detectDocumentFormat(b); err != nil {
+			return nil, err
+		}
+	}
+
+	blocks, pages, err := extractBlocks(format, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from %v document: %w", format, err)
+	}
+
+	newPart := func(blocks []textBlock) *service.Message {
+		part := msg.Copy()
+		part.SetBytes([]byte(renderBlocks(blocks, e.markdown)))
+		part.MetaSetMut("extract_text_format", format)
+		if pages > 0 {
+			part.MetaSetMut("extract_text_page_count", pages)
+		}
+		return part
+	}
+
+	switch e.split {
+	case "page":
+		if pages == 0 {
+			part := newPart(blocks)
+			part.MetaSetMut("extract_text_page", 1)
+			return service.MessageBatch{part}, nil
+		}
+		batch := make(service.MessageBatch, 0, pages)
+		for page := 1; page <= pages; page++ {
+			var pageBlocks []textBlock
+			for _, b := range blocks {
+				if b.page == page {
+					pageBlocks = append(pageBlocks, b)
+				}
+			}
+			part := newPart(pageBlocks)
+			part.MetaSetMut("extract_text_page", page)
+			batch = append(batch, part)
+		}
+		return batch, nil
+	case "heading":
+		sections := sectionsByHeading(blocks)
+		batch := make(service.MessageBatch, 0, len(sections))
+		for _, s := range sections {
+			part := newPart(s.blocks)
+			if s.page > 0 {
+				part.MetaSetMut("extract_text_page", s.page)
+			}
+			part.MetaSetMut("extract_text_heading", s.heading)
+			part.MetaSetMut("extract_text_heading_level", s.level)
+			part.MetaSetMut("extract_text_heading_path", s.headingPath)
+			batch = append(batch, part)
+		}
+		return batch, nil
+	}
+	return service.MessageBatch{newPart(blocks)}, nil
+}
+
+func (*extractText) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func zipDocument(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func testDOCX(t *testing.T) []byte {
+	para := func(style, text string) string {
+		var pPr string
+		if style != "" {
+			pPr = `<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`
+		}
+		return `<w:p>` + pPr + `<w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	return zipDocument(t, map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			para("", "Preamble") +
+			para("Title", "Report") +
+			para("", "Intro text") +
+			para("Heading2", "Details") +
+			`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>first</w:t></w:r></w:p>` +
+			`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">sec</w:t></w:r><w:r><w:t>ond</w:t></w:r></w:p>` +
+			para("Heading1", "Summary") +
+			`<w:p><w:r><w:t>a</w:t><w:tab/><w:t>b</w:t><w:br/><w:t>c</w:t></w:r></w:p>` +
+			`<w:p></w:p>` +
+			`</w:body></w:document>`,
+	})
+}
+
+func testPPTX(t *testing.T) []byte {
+	slide := func(title string, bullets ...string) string {
+		s := `<?xml version="1.0" encoding="UTF-8"?>
+<p:sld xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"><p:cSld><p:spTree>` +
+			`<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + title + `</a:t></a:r></a:p></p:txBody></p:sp>` +
+			`<p:sp><p:nvSpPr><p:nvPr><p:ph idx="1"/></p:nvPr></p:nvSpPr><p:txBody>`
+		for _, b := range bullets {
+			s += `<a:p><a:pPr><a:buChar char="•"/></a:pPr><a:r><a:t>` + b + `</a:t></a:r></a:p>`
+		}
+		return s + `</p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+	}
+	return zipDocument(t, map[string]string{
+		"ppt/presentation.xml":   `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"/>`,
+		"ppt/slides/slide1.xml":  slide("Welcome", "hello"),
+		"ppt/slides/slide2.xml":  slide("Agenda", "one", "two"),
+		"ppt/slides/slide10.xml": slide("Questions"),
+	})
+}
+
+// testPDF builds a minimal PDF document with a line of text on each page.
+func testPDF(pages ...string) []byte {
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+i*2))
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	for i, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+const testHTML = `<!DOCTYPE html>
+<html>
+<head><title>Ignored</title><style>p { color: red; }</style></head>
+<body>
+  <nav>Home</nav>
+  <h1>Guide</h1>
+  <p>Some   <b>bold</b> and <a href="#">linked</a>
+    text.</p>
+  <ul>
+    <li>one</li>
+    <li>two</li>
+  </ul>
+  <h2>Table</h2>
+  <table><tr><th>a</th><th>b</th></tr><tr><td>1</td><td>2</td></tr></table>
+  <pre>keep
+  spacing</pre>
+  <p>line<br>break</p>
+  <script>alert("nope")</script>
+</body>
+</html>`
+
+func TestDetectDocumentFormat(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{name: "pdf", input: testPDF("hello"), expected: "pdf"},
+		{name: "docx", input: testDOCX(t), expected: "docx"},
+		{name: "pptx", input: testPPTX(t), expected: "pptx"},
+		{name: "html", input: []byte(testHTML), expected: "html"},
+		{name: "text", input: []byte("just some text"), expected: "text"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			format, err := detectDocumentFormat(test.input)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, format)
+		})
+	}
+
+	_, err := detectDocumentFormat(zipDocument(t, map[string]string{"foo.txt": "bar"}))
+	require.ErrorIs(t, err, errUnknownDocumentFormat)
+
+	_, err = detectDocumentFormat([]byte{0xff, 0xfe, 0x00, 0x81})
+	require.ErrorIs(t, err, errUnknownDocumentFormat)
+}
+
+type extractedPart struct {
+	content string
+	meta    map[string]any
+}
+
+func runExtractText(t *testing.T, conf string, input []byte) []extractedPart {
+	t.Helper()
+
+	pConf, err := newExtractTextSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+	proc, err := newExtractText(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	batch, err := proc.Process(t.Context(), service.NewMessage(input))
+	require.NoError(t, err)
+
+	var parts []extractedPart
+	for _, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		p := extractedPart{content: string(b), meta: map[string]any{}}
+		require.NoError(t, msg.MetaWalkMut(func(k string, v any) error {
+			p.meta[k] = v
+			return nil
+		}))
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+func TestExtractTextHTML(t *testing.T) {
+	parts := runExtractText(t, ``, []byte(testHTML))
+	require.Len(t, parts, 1)
+	assert.Equal(t, "Home\n\n# Guide\n\nSome bold and linked text.\n\n- one\n- two\n\n## Table\n\na | b\n\n1 | 2\n\nkeep\n  spacing\n\nline\nbreak", parts[0].content)
+	assert.Equal(t, map[string]any{"extract_text_format": "html"}, parts[0].meta)
+
+	parts = runExtractText(t, `output_format: text`, []byte(testHTML))
+	require.Len(t, parts, 1)
+	assert.Equal(t, "Home\n\nGuide\n\nSome bold and linked text.\n\none\n\ntwo\n\nTable\n\na | b\n\n1 | 2\n\nkeep\n  spacing\n\nline\nbreak", parts[0].content)
+}
+
+func TestExtractTextDOCXByHeading(t *testing.T) {
+	parts := runExtractText(t, `split: heading`, testDOCX(t))
+	require.Len(t, parts, 4)
+
+	assert.Equal(t, extractedPart{
+		content: "Preamble",
+		meta: map[string]any{
+			"extract_text_format":        "docx",
+			"extract_text_heading":       "",
+			"extract_text_heading_level": 0,
+			"extract_text_heading_path":  []any{},
+		},
+	}, parts[0])
+	assert.Equal(t, "# Report\n\nIntro text", parts[1].content)
+	assert.Equal(t, []any{"Report"}, parts[1].meta["extract_text_heading_path"])
+
+	assert.Equal(t, "## Details\n\n- first\n- second", parts[2].content)
+	assert.Equal(t, "Details", parts[2].meta["extract_text_heading"])
+	assert.Equal(t, 2, parts[2].meta["extract_text_heading_level"])
+	assert.Equal(t, []any{"Report", "Details"}, parts[2].meta["extract_text_heading_path"])
+
+	assert.Equal(t, "# Summary\n\na\tb\nc", parts[3].content)
+	assert.Equal(t, []any{"Summary"}, parts[3].meta["extract_text_heading_path"])
+}
+
+func TestExtractTextPPTXByPage(t *testing.T) {
+	parts := runExtractText(t, `split: page`, testPPTX(t))
+	require.Len(t, parts, 3)
+
+	assert.Equal(t, extractedPart{
+		content: "# Welcome\n\n- hello",
+		meta: map[string]any{
+			"extract_text_format":     "pptx",
+			"extract_text_page_count": 3,
+			"extract_text_page":       1,
+		},
+	}, parts[0])
+	assert.Equal(t, "# Agenda\n\n- one\n- two", parts[1].content)
+	assert.Equal(t, 2, parts[1].meta["extract_text_page"])
+	assert.Equal(t, "# Questions", parts[2].content)
+	assert.Equal(t, 3, parts[2].meta["extract_text_page"])
+}
+
+func TestExtractTextPDF(t *testing.T) {
+	doc := testPDF("First page", "Second page")
+
+	parts := runExtractText(t, `format: pdf`, doc)
+	require.Len(t, parts, 1)
+	assert.Equal(t, "First page\n\nSecond page", parts[0].content)
+	assert.Equal(t, 2, parts[0].meta["extract_text_page_count"])
+
+	parts = runExtractText(t, `split: page`, doc)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Second page", parts[1].content)
+	assert.Equal(t, 2, parts[1].meta["extract_text_page"])
+
+	pConf, err := newExtractTextSpec().ParseYAML(`format: pdf`, nil)
+	require.NoError(t, err)
+	proc, err := newExtractText(pConf, service.MockResources())
+	require.NoError(t, err)
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte("%PDF-1.4 garbage")))
+	require.Error(t, err)
+}
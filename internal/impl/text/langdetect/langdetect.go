@@ -0,0 +1,173 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package langdetect implements a small, dependency free language detector
+// based on unicode scripts and common words. It favours being cheap and
+// predictable over being exhaustive, and is intended for routing decisions
+// rather than linguistic analysis.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Undetermined is the code returned when the language of a text cannot be
+// detected.
+const Undetermined = "und"
+
+// Result is the outcome of a language detection.
+type Result struct {
+	// Language is the ISO 639-1 code of the detected language, or
+	// Undetermined.
+	Language string
+	// Confidence is a score between 0 and 1.
+	Confidence float64
+}
+
+type scriptLanguage struct {
+	table *unicode.RangeTable
+	lang  string
+}
+
+// Scripts which, within the scope of this detector, identify a single
+// language.
+var scriptLanguages = []scriptLanguage{
+	{unicode.Hangul, "ko"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Georgian, "ka"},
+	{unicode.Armenian, "hy"},
+}
+
+var commonWords = map[string][]string{
+	"en": {"the", "and", "is", "are", "was", "of", "to", "in", "that", "it", "with", "for", "this", "you", "not", "have", "be", "on", "they", "what"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "es", "en", "un", "una", "por", "con", "para", "del", "se", "no", "como", "pero", "muy"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "que", "qui", "dans", "pour", "pas", "sur", "au", "avec", "ce", "je", "nous"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "von", "auf", "ich", "sie", "es", "dem", "des", "auch", "wir"},
+	"it": {"il", "la", "di", "che", "e", "è", "un", "una", "per", "non", "con", "sono", "del", "della", "gli", "le", "si", "ma", "come", "questo"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "é", "um", "uma", "não", "em", "para", "com", "do", "da", "se", "por", "mais", "mas"},
+	"nl": {"de", "het", "een", "en", "is", "van", "niet", "dat", "in", "op", "te", "zijn", "met", "voor", "ik", "je", "er", "maar", "ook", "wat"},
+	"sv": {"och", "att", "det", "är", "en", "som", "på", "för", "med", "inte", "jag", "av", "har", "till", "den", "var", "om", "ett", "men", "vi"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "dari", "dalam", "akan", "ada", "saya", "kami", "juga", "adalah", "ke", "bisa", "atau", "karena"},
+	"tr": {"ve", "bir", "bu", "da", "de", "için", "ile", "çok", "ne", "ama", "gibi", "daha", "olan", "var", "ben", "sen", "mi", "değil", "kadar", "her"},
+}
+
+// Letters that distinguish Ukrainian from Russian text.
+const ukrainianLetters = "іїєґІЇЄҐ"
+
+// Detect returns the most likely language of a text.
+func Detect(text string) Result {
+	var letters, latin, han, kana, cyrillic, ukrainian int
+	scriptCounts := make([]int, len(scriptLanguages))
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if strings.ContainsRune(ukrainianLetters, r) {
+				ukrainian++
+			}
+		default:
+			for i, sl := range scriptLanguages {
+				if unicode.Is(sl.table, r) {
+					scriptCounts[i]++
+					break
+				}
+			}
+		}
+	}
+	if letters == 0 {
+		return Result{Language: Undetermined}
+	}
+
+	// Find the dominant script of the text.
+	best, bestCount := "", 0
+	consider := func(lang string, count int) {
+		if count > bestCount {
+			best, bestCount = lang, count
+		}
+	}
+	consider("latin", latin)
+	if kana > 0 {
+		// Japanese text commonly mixes kanji and kana.
+		consider("ja", han+kana)
+	} else {
+		consider("zh", han)
+	}
+	if ukrainian > 0 {
+		consider("uk", cyrillic)
+	} else {
+		consider("ru", cyrillic)
+	}
+	for i, sl := range scriptLanguages {
+		consider(sl.lang, scriptCounts[i])
+	}
+
+	scriptShare := float64(bestCount) / float64(letters)
+	if best != "latin" {
+		return Result{Language: best, Confidence: scriptShare}
+	}
+
+	lang, share := detectLatin(text)
+	if lang == "" {
+		return Result{Language: Undetermined}
+	}
+	return Result{Language: lang, Confidence: share * scriptShare}
+}
+
+// detectLatin scores a text written in the latin script against lists of
+// common words of each language, returning the best match and its share of
+// all matches.
+func detectLatin(text string) (string, float64) {
+	scores := map[string]int{}
+	var total int
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for lang, words := range commonWords {
+			for _, w := range words {
+				if w == word {
+					scores[lang]++
+					total++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestScore := "", 0
+	for lang, score := range scores {
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+	if bestScore == 0 {
+		return "", 0
+	}
+	return best, float64(bestScore) / float64(total)
+}
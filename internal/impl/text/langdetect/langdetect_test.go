@@ -0,0 +1,58 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"The quick brown fox jumps over the lazy dog and it is not tired.", "en"},
+		{"El perro come la comida que está en la mesa de la cocina.", "es"},
+		{"Le chat est sur la table et il ne veut pas descendre.", "fr"},
+		{"Der Hund ist nicht im Garten, sondern auf dem Sofa.", "de"},
+		{"Il gatto è sulla sedia e non vuole scendere.", "it"},
+		{"O gato não quer sair da casa porque está chovendo.", "pt"},
+		{"De hond is niet in de tuin maar op de bank.", "nl"},
+		{"今日はとても良い天気ですね。", "ja"},
+		{"今天天气很好。", "zh"},
+		{"오늘 날씨가 정말 좋네요.", "ko"},
+		{"Сегодня очень хорошая погода.", "ru"},
+		{"Сьогодні дуже гарна погода, ї є.", "uk"},
+		{"الطقس جميل اليوم", "ar"},
+		{"Ο καιρός είναι ωραίος σήμερα.", "el"},
+		{"12345 !!! ???", Undetermined},
+		{"xyzzy plugh", Undetermined},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			res := Detect(test.text)
+			assert.Equal(t, test.expected, res.Language)
+			if test.expected == Undetermined {
+				assert.Zero(t, res.Confidence)
+			} else {
+				assert.Greater(t, res.Confidence, 0.0)
+				assert.LessOrEqual(t, res.Confidence, 1.0)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/text/langdetect"
+)
+
+var _ service.Processor = (*languageProcessor)(nil)
+
+func init() {
+	service.MustRegisterProcessor(
+		"text_language",
+		newLanguageProcessorSpec(),
+		newLanguageProcessor,
+	)
+}
+
+const (
+	tlpFieldTextMapping    = "text_mapping"
+	tlpFieldProvider       = "provider"
+	tlpFieldURL            = "url"
+	tlpFieldAPIKey         = "api_key"
+	tlpFieldTargetLanguage = "target_language"
+	tlpFieldTimeout        = "timeout"
+
+	tlpProviderBuiltin        = "builtin"
+	tlpProviderLibreTranslate = "libretranslate"
+	tlpProviderGoogle         = "google"
+
+	tlpGoogleDefaultURL = "https://translation.googleapis.com"
+)
+
+func newLanguageProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("AI").
+		Version("4.62.0").
+		Summary("Detects the language of messages and optionally translates them.").
+		Description(`
+The detected language is added to each message as the metadata field `+"`language`"+` in the form of an ISO 639-1 code, or `+"`und`"+` when the language cannot be determined. The confidence of the detection, a number between 0 and 1, is added as the metadata field `+"`translation_confidence`"+`.
+
+When `+"`"+tlpFieldTargetLanguage+"`"+` is set the payload of each message is replaced with the text translated into the target language. Messages that are already in the target language, or whose language cannot be determined, have their payload replaced with the untranslated text.
+
+The `+"`builtin`"+` provider runs locally and recognises a small set of common languages based on their script and common words. It is cheap and requires no network access, but does not support translation.`).
+		Fields(
+			service.NewBloblangField(tlpFieldTextMapping).
+				Description("The text to detect the language of. By default, the processor uses the entire payload as a string.").
+				Optional(),
+			service.NewStringAnnotatedEnumField(tlpFieldProvider, map[string]string{
+				tlpProviderBuiltin:        "A local detector with no external dependencies. Translation is not supported.",
+				tlpProviderLibreTranslate: "A https://libretranslate.com[LibreTranslate^] server, which can be self hosted.",
+				tlpProviderGoogle:         "The https://cloud.google.com/translate/docs/reference/rest/v2/translate[Google Cloud Translation API^] (basic edition).",
+			}).
+				Description("The backend used for detection and translation.").
+				Default(tlpProviderBuiltin),
+			service.NewURLField(tlpFieldURL).
+				Description("The base URL of the provider API. Required for the `libretranslate` provider.").
+				Example("http://localhost:5000").
+				Optional(),
+			service.NewStringField(tlpFieldAPIKey).
+				Description("The API key of the provider, if required.").
+				Secret().
+				Default(""),
+			service.NewStringField(tlpFieldTargetLanguage).
+				Description("An optional language to translate messages into, as an ISO 639-1 code.").
+				Example("en").
+				Optional(),
+			service.NewDurationField(tlpFieldTimeout).
+				Description("The maximum period to wait for a response from the provider.").
+				Default("30s").
+				Advanced(),
+		).
+		Example(
+			"Translate support tickets",
+			"Detect the language of incoming support tickets and translate their body into English.",
+			`
+pipeline:
+  processors:
+    - branch:
+        request_map: 'root = this.body'
+        processors:
+          - text_language:
+              provider: libretranslate
+              url: http://localhost:5000
+              target_language: en
+        result_map: |
+          root.body_en = content().string()
+          root.language = @language
+`,
+		)
+}
+
+func newLanguageProcessor(conf *service.ParsedConfig, _ *service.Resources) (service.Processor, error) {
+	p := &languageProcessor{}
+
+	var err error
+	if conf.Contains(tlpFieldTextMapping) {
+		if p.text, err = conf.FieldBloblang(tlpFieldTextMapping); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(tlpFieldTargetLanguage) {
+		if p.targetLanguage, err = conf.FieldString(tlpFieldTargetLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	providerName, err := conf.FieldString(tlpFieldProvider)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := conf.FieldString(tlpFieldAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	var baseURL string
+	if conf.Contains(tlpFieldURL) {
+		if baseURL, err = conf.FieldString(tlpFieldURL); err != nil {
+			return nil, err
+		}
+	}
+	timeout, err := conf.FieldDuration(tlpFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch providerName {
+	case tlpProviderBuiltin:
+		if p.targetLanguage != "" {
+			return nil, fmt.Errorf("the %s provider does not support translation", tlpProviderBuiltin)
+		}
+		p.provider = builtinLanguageProvider{}
+	case tlpProviderLibreTranslate:
+		if baseURL == "" {
+			return nil, fmt.Errorf("field %s is required for the %s provider", tlpFieldURL, tlpProviderLibreTranslate)
+		}
+		p.provider = &libreTranslateProvider{client: client, baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey}
+	case tlpProviderGoogle:
+		if baseURL == "" {
+			baseURL = tlpGoogleDefaultURL
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("field %s is required for the %s provider", tlpFieldAPIKey, tlpProviderGoogle)
+		}
+		p.provider = &googleTranslateProvider{client: client, baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey}
+	default:
+		return nil, fmt.Errorf("unknown %s: %v", tlpFieldProvider, providerName)
+	}
+	return p, nil
+}
+
+//------------------------------------------------------------------------------
+
+type languageProvider interface {
+	Detect(ctx context.Context, text string) (langdetect.Result, error)
+	Translate(ctx context.Context, text, source, target string) (string, error)
+}
+
+type languageProcessor struct {
+	text           *bloblang.Executor
+	targetLanguage string
+	provider       languageProvider
+}
+
+// Process implements service.Processor.
+func (p *languageProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	var b []byte
+	var err error
+	if p.text != nil {
+		res, err := msg.BloblangQuery(p.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s execution error: %w", tlpFieldTextMapping, err)
+		}
+		if b, err = res.AsBytes(); err != nil {
+			return nil, fmt.Errorf("%s extraction error: %w", tlpFieldTextMapping, err)
+		}
+	} else if b, err = msg.AsBytes(); err != nil {
+		return nil, err
+	}
+	text := string(b)
+
+	detected, err := p.provider.Detect(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect language: %w", err)
+	}
+
+	msg = msg.Copy()
+	msg.MetaSetMut("language", detected.Language)
+	msg.MetaSetMut("translation_confidence", detected.Confidence)
+
+	if p.targetLanguage == "" {
+		return service.MessageBatch{msg}, nil
+	}
+
+	if detected.Language != langdetect.Undetermined && detected.Language != p.targetLanguage {
+		if text, err = p.provider.Translate(ctx, text, detected.Language, p.targetLanguage); err != nil {
+			return nil, fmt.Errorf("failed to translate from %s to %s: %w", detected.Language, p.targetLanguage, err)
+		}
+	}
+	msg.SetBytes([]byte(text))
+	return service.MessageBatch{msg}, nil
+}
+
+// Close implements service.Processor.
+func (*languageProcessor) Close(context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type builtinLanguageProvider struct{}
+
+func (builtinLanguageProvider) Detect(_ context.Context, text string) (langdetect.Result, error) {
+	return langdetect.Detect(text), nil
+}
+
+func (builtinLanguageProvider) Translate(context.Context, string, string, string) (string, error) {
+	return "", errors.New("translation is not supported")
+}
+
+//------------------------------------------------------------------------------
+
+func postJSON(ctx context.Context, client *http.Client, url string, body, out any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("request returned status %d: %s", res.StatusCode, bytes.TrimSpace(resBody))
+	}
+	return json.Unmarshal(resBody, out)
+}
+
+type libreTranslateProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func (l *libreTranslateProvider) Detect(ctx context.Context, text string) (langdetect.Result, error) {
+	var res []struct {
+		Confidence float64 `json:"confidence"`
+		Language   string  `json:"language"`
+	}
+	if err := postJSON(ctx, l.client, l.baseURL+"/detect", map[string]string{
+		"q":       text,
+		"api_key": l.apiKey,
+	}, &res); err != nil {
+		return langdetect.Result{}, err
+	}
+	if len(res) == 0 {
+		return langdetect.Result{Language: langdetect.Undetermined}, nil
+	}
+	// LibreTranslate reports confidence as a percentage.
+	return langdetect.Result{Language: res[0].Language, Confidence: res[0].Confidence / 100}, nil
+}
+
+func (l *libreTranslateProvider) Translate(ctx context.Context, text, source, target string) (string, error) {
+	var res struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := postJSON(ctx, l.client, l.baseURL+"/translate", map[string]string{
+		"q":       text,
+		"source":  source,
+		"target":  target,
+		"format":  "text",
+		"api_key": l.apiKey,
+	}, &res); err != nil {
+		return "", err
+	}
+	return res.TranslatedText, nil
+}
+
+type googleTranslateProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func (g *googleTranslateProvider) endpoint(path string) string {
+	return g.baseURL + "/language/translate/v2" + path + "?key=" + url.QueryEscape(g.apiKey)
+}
+
+func (g *googleTranslateProvider) Detect(ctx context.Context, text string) (langdetect.Result, error) {
+	var res struct {
+		Data struct {
+			Detections [][]struct {
+				Language   string  `json:"language"`
+				Confidence float64 `json:"confidence"`
+			} `json:"detections"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, g.client, g.endpoint("/detect"), map[string]any{"q": []string{text}}, &res); err != nil {
+		return langdetect.Result{}, err
+	}
+	if len(res.Data.Detections) == 0 || len(res.Data.Detections[0]) == 0 || res.Data.Detections[0][0].Language == "und" {
+		return langdetect.Result{Language: langdetect.Undetermined}, nil
+	}
+	d := res.Data.Detections[0][0]
+	return langdetect.Result{Language: d.Language, Confidence: d.Confidence}, nil
+}
+
+func (g *googleTranslateProvider) Translate(ctx context.Context, text, source, target string) (string, error) {
+	var res struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, g.client, g.endpoint(""), map[string]any{
+		"q":      []string{text},
+		"source": source,
+		"target": target,
+		"format": "text",
+	}, &res); err != nil {
+		return "", err
+	}
+	if len(res.Data.Translations) == 0 {
+		return "", errors.New("no translation returned")
+	}
+	return res.Data.Translations[0].TranslatedText, nil
+}
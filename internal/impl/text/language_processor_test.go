@@ -0,0 +1,136 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestLanguageProcessor(t *testing.T, yamlStr string) service.Processor {
+	t.Helper()
+	conf, err := newLanguageProcessorSpec().ParseYAML(yamlStr, nil)
+	require.NoError(t, err)
+	proc, err := newLanguageProcessor(conf, service.MockResources())
+	require.NoError(t, err)
+	return proc
+}
+
+func processLanguage(t *testing.T, proc service.Processor, input string) (lang string, confidence float64, content string) {
+	t.Helper()
+	batch, err := proc.Process(t.Context(), service.NewMessage([]byte(input)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	l, _ := batch[0].MetaGetMut("language")
+	c, _ := batch[0].MetaGetMut("translation_confidence")
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	return l.(string), c.(float64), string(b)
+}
+
+func TestLanguageProcessorBuiltin(t *testing.T) {
+	proc := newTestLanguageProcessor(t, `
+text_mapping: 'root = this.body'
+`)
+	input := `{"body":"Le chat est sur la table et il ne veut pas descendre."}`
+	lang, confidence, content := processLanguage(t, proc, input)
+	assert.Equal(t, "fr", lang)
+	assert.Greater(t, confidence, 0.0)
+	assert.Equal(t, input, content)
+
+	conf, err := newLanguageProcessorSpec().ParseYAML(`target_language: en`, nil)
+	require.NoError(t, err)
+	_, err = newLanguageProcessor(conf, service.MockResources())
+	require.ErrorContains(t, err, "does not support translation")
+}
+
+func TestLanguageProcessorLibreTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "secret", body["api_key"])
+
+		switch r.URL.Path {
+		case "/detect":
+			lang := "es"
+			if body["q"] == "hello" {
+				lang = "en"
+			}
+			_, _ = w.Write([]byte(`[{"confidence":90.0,"language":"` + lang + `"}]`))
+		case "/translate":
+			assert.Equal(t, "es", body["source"])
+			assert.Equal(t, "en", body["target"])
+			_, _ = w.Write([]byte(`{"translatedText":"the cat"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	proc := newTestLanguageProcessor(t, `
+provider: libretranslate
+url: `+srv.URL+`
+api_key: secret
+target_language: en
+`)
+
+	lang, confidence, content := processLanguage(t, proc, "el gato")
+	assert.Equal(t, "es", lang)
+	assert.InDelta(t, 0.9, confidence, 0.0001)
+	assert.Equal(t, "the cat", content)
+
+	lang, _, content = processLanguage(t, proc, "hello")
+	assert.Equal(t, "en", lang)
+	assert.Equal(t, "hello", content)
+}
+
+func TestLanguageProcessorGoogle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+		switch r.URL.Path {
+		case "/language/translate/v2/detect":
+			_, _ = w.Write([]byte(`{"data":{"detections":[[{"language":"de","confidence":0.75,"isReliable":false}]]}}`))
+		case "/language/translate/v2":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []any{"der Hund"}, body["q"])
+			assert.Equal(t, "en", body["target"])
+			_, _ = w.Write([]byte(`{"data":{"translations":[{"translatedText":"the dog"}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	proc := newTestLanguageProcessor(t, `
+provider: google
+url: `+srv.URL+`
+api_key: secret
+target_language: en
+`)
+
+	lang, confidence, content := processLanguage(t, proc, "der Hund")
+	assert.Equal(t, "de", lang)
+	assert.InDelta(t, 0.75, confidence, 0.0001)
+	assert.Equal(t, "the dog", content)
+}
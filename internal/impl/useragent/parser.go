@@ -0,0 +1,101 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package useragent
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ua-parser/uap-go/uaparser"
+)
+
+const otherFamily = "Other"
+
+// parser parses user agents with the embedded uap-core regexes, preferring
+// the results of optional override regexes when they match.
+type parser struct {
+	defaults  *uaparser.Parser
+	overrides *uaparser.Parser
+}
+
+func newParser(overridesPath string) (*parser, error) {
+	p := &parser{defaults: uaparser.NewFromSaved()}
+	if overridesPath == "" {
+		return p, nil
+	}
+
+	b, err := os.ReadFile(overridesPath)
+	if err != nil {
+		return nil, err
+	}
+	if p.overrides, err = uaparser.NewFromBytes(b); err != nil {
+		return nil, fmt.Errorf("failed to parse regexes file %v: %w", overridesPath, err)
+	}
+	return p, nil
+}
+
+// parse returns an object of the browser, operating system and device of a
+// user agent, where the family of each is "Other" when it isn't recognised.
+func (p *parser) parse(s string) map[string]any {
+	client := p.defaults.Parse(s)
+	if p.overrides != nil {
+		override := p.overrides.Parse(s)
+		if override.UserAgent.Family != otherFamily {
+			client.UserAgent = override.UserAgent
+		}
+		if override.Os.Family != otherFamily {
+			client.Os = override.Os
+		}
+		if override.Device.Family != otherFamily {
+			client.Device = override.Device
+		}
+	}
+
+	browser := versionedObject(client.UserAgent.Family, client.UserAgent.Major, client.UserAgent.Minor, client.UserAgent.Patch)
+	opSys := versionedObject(client.Os.Family, client.Os.Major, client.Os.Minor, client.Os.Patch, client.Os.PatchMinor)
+
+	device := map[string]any{"family": client.Device.Family}
+	if client.Device.Brand != "" {
+		device["brand"] = client.Device.Brand
+	}
+	if client.Device.Model != "" {
+		device["model"] = client.Device.Model
+	}
+
+	return map[string]any{
+		"browser": browser,
+		"os":      opSys,
+		"device":  device,
+	}
+}
+
+// versionedObject returns an object of a family and the parts of its version
+// that are known, along with the version as a dot separated string.
+func versionedObject(family string, parts ...string) map[string]any {
+	obj := map[string]any{"family": family}
+	var known []string
+	for i, k := range []string{"major", "minor", "patch", "patch_minor"}[:len(parts)] {
+		if parts[i] == "" {
+			break
+		}
+		obj[k] = parts[i]
+		known = append(known, parts[i])
+	}
+	if len(known) > 0 {
+		obj["version"] = strings.Join(known, ".")
+	}
+	return obj
+}
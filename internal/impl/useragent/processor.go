@@ -0,0 +1,171 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package useragent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Jeffail/gabs/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	uapFieldUserAgent   = "user_agent"
+	uapFieldTargetPath  = "target_path"
+	uapFieldRegexesFile = "regexes_file"
+)
+
+func processorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Parses user agent strings into the family and version of their browser, operating system and device.").
+		Description(`
+Parses the user agent string obtained with the `+"`user_agent`"+` query with the regexes of the https://github.com/ua-parser/uap-core[uap-core^] project, which are embedded within the binary, and sets an object with the results at the `+"`target_path`"+` of the message:
+
+`+"```json"+`
+{
+  "browser": { "family": "Chrome", "version": "120.0.6099", "major": "120", "minor": "0", "patch": "6099" },
+  "os": { "family": "Mac OS X", "version": "10.15.7", "major": "10", "minor": "15", "patch": "7" },
+  "device": { "family": "Mac", "brand": "Apple", "model": "Mac" }
+}
+`+"```"+`
+
+The family of a browser, operating system or device that isn't recognised is `+"`Other`"+`, and the parts of versions that aren't known are omitted.
+
+User agents that aren't recognised by the embedded regexes, such as those of internal clients, can be parsed by adding regexes to a file in the https://github.com/ua-parser/uap-core/blob/master/docs/specification.md[uap-core format^] set with `+"`regexes_file`"+`. The results of these regexes take precedence over the embedded regexes whenever they match.
+
+The same parsing can be performed within Bloblang with the xref:guides:bloblang/methods.adoc#parse_user_agent[`+"`parse_user_agent`"+` method].`).
+		Fields(
+			service.NewBloblangField(uapFieldUserAgent).
+				Description("A Bloblang query that returns the user agent string to parse.").
+				Examples("this.user_agent", `meta("User-Agent")`),
+			service.NewStringField(uapFieldTargetPath).
+				Description("The dot separated path of the message to set the results at.").
+				Example("client.agent").
+				Default("user_agent"),
+			service.NewStringField(uapFieldRegexesFile).
+				Description("An optional path of a file of regexes in the uap-core format that take precedence over the embedded regexes.").
+				Example("./regexes.yaml").
+				Optional().
+				Advanced(),
+		).
+		Example("Enrich access logs", "Parse the user agent of each access log received over HTTP.", `
+input:
+  http_server:
+    path: /logs
+
+pipeline:
+  processors:
+    - user_agent_parse:
+        user_agent: meta("User-Agent")
+        target_path: client.agent
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor("user_agent_parse", processorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newProcessorFromParsed(conf)
+		})
+
+	if err := bloblang.RegisterMethodV2("parse_user_agent",
+		bloblang.NewPluginSpec().
+			Category("Parsing").
+			Description("Parses a user agent string into an object of the family and version of its browser, operating system and device, in the same format as the xref:components:processors/user_agent_parse.adoc[`user_agent_parse` processor].").
+			Param(bloblang.NewStringParam("regexes_file").Description("An optional path of a file of regexes in the uap-core format that take precedence over the embedded regexes.").Default("")).
+			Example("", `root = this.ua.parse_user_agent().browser`,
+				[2]string{
+					`{"ua":"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Safari/537.36"}`,
+					`{"family":"Chrome","major":"120","minor":"0","patch":"6099","version":"120.0.6099"}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			regexesFile, err := args.GetString("regexes_file")
+			if err != nil {
+				return nil, err
+			}
+			p, err := newParser(regexesFile)
+			if err != nil {
+				return nil, err
+			}
+			return bloblang.StringMethod(func(s string) (any, error) {
+				return p.parse(s), nil
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type processor struct {
+	userAgent  *bloblang.Executor
+	targetPath string
+	parser     *parser
+}
+
+func newProcessorFromParsed(conf *service.ParsedConfig) (*processor, error) {
+	p := &processor{}
+
+	var err error
+	if p.userAgent, err = conf.FieldBloblang(uapFieldUserAgent); err != nil {
+		return nil, err
+	}
+	if p.targetPath, err = conf.FieldString(uapFieldTargetPath); err != nil {
+		return nil, err
+	}
+
+	var regexesFile string
+	if conf.Contains(uapFieldRegexesFile) {
+		if regexesFile, err = conf.FieldString(uapFieldRegexesFile); err != nil {
+			return nil, err
+		}
+	}
+	if p.parser, err = newParser(regexesFile); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *processor) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	uaMsg, err := msg.BloblangQuery(p.userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %v query: %w", uapFieldUserAgent, err)
+	}
+	uaBytes, err := uaMsg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	structured, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as an object: %w", err)
+	}
+	gObj := gabs.Wrap(structured)
+	if _, err := gObj.SetP(p.parser.parse(string(uaBytes)), p.targetPath); err != nil {
+		return nil, fmt.Errorf("failed to set %v: %w", uapFieldTargetPath, err)
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return service.MessageBatch{msg}, nil
+}
+
+func (*processor) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package useragent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	chromeMacUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Safari/537.36"
+	iPhoneUA    = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1"
+	internalUA  = "acme-sync/4.2.1 (acmeos)"
+)
+
+func testProcess(t *testing.T, conf, content string) any {
+	t.Helper()
+
+	pConf, err := processorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newProcessorFromParsed(pConf)
+	require.NoError(t, err)
+
+	batch, err := p.Process(t.Context(), service.NewMessage([]byte(content)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	res, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	return res
+}
+
+func TestUserAgentParseProcessor(t *testing.T) {
+	res := testProcess(t, `
+user_agent: this.ua
+target_path: client.agent
+`, `{"ua":"`+chromeMacUA+`"}`)
+
+	agent := res.(map[string]any)["client"].(map[string]any)["agent"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"family":  "Chrome",
+		"version": "120.0.6099",
+		"major":   "120",
+		"minor":   "0",
+		"patch":   "6099",
+	}, agent["browser"])
+	assert.Equal(t, "Mac OS X", agent["os"].(map[string]any)["family"])
+	assert.Equal(t, "10.15.7", agent["os"].(map[string]any)["version"])
+	assert.Equal(t, map[string]any{
+		"family": "Mac",
+		"brand":  "Apple",
+		"model":  "Mac",
+	}, agent["device"])
+
+	res = testProcess(t, `user_agent: this.ua`, `{"ua":"`+iPhoneUA+`"}`)
+	agent = res.(map[string]any)["user_agent"].(map[string]any)
+	assert.Equal(t, "Mobile Safari", agent["browser"].(map[string]any)["family"])
+	assert.Equal(t, "iOS", agent["os"].(map[string]any)["family"])
+	assert.Equal(t, "iPhone", agent["device"].(map[string]any)["family"])
+
+	res = testProcess(t, `user_agent: this.ua`, `{"ua":"`+internalUA+`"}`)
+	assert.Equal(t, map[string]any{
+		"browser": map[string]any{"family": "Other"},
+		"os":      map[string]any{"family": "Other"},
+		"device":  map[string]any{"family": "Other"},
+	}, res.(map[string]any)["user_agent"])
+}
+
+func TestUserAgentParseOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regexes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+user_agent_parsers:
+  - regex: '(acme-sync)/(\d+)\.(\d+)\.(\d+)'
+    family_replacement: 'Acme Sync'
+os_parsers:
+  - regex: '\((acmeos)\)'
+    os_replacement: 'Acme OS'
+device_parsers: []
+`), 0o644))
+
+	res := testProcess(t, `
+user_agent: this.ua
+regexes_file: `+path+`
+`, `{"ua":"`+internalUA+`"}`)
+	agent := res.(map[string]any)["user_agent"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"family":  "Acme Sync",
+		"version": "4.2.1",
+		"major":   "4",
+		"minor":   "2",
+		"patch":   "1",
+	}, agent["browser"])
+	assert.Equal(t, map[string]any{"family": "Acme OS"}, agent["os"])
+	assert.Equal(t, map[string]any{"family": "Other"}, agent["device"])
+
+	// User agents not matched by the overrides use the embedded regexes.
+	res = testProcess(t, `
+user_agent: this.ua
+regexes_file: `+path+`
+`, `{"ua":"`+chromeMacUA+`"}`)
+	agent = res.(map[string]any)["user_agent"].(map[string]any)
+	assert.Equal(t, "Chrome", agent["browser"].(map[string]any)["family"])
+
+	pConf, err := processorSpec().ParseYAML(`
+user_agent: this.ua
+regexes_file: ./nope.yaml
+`, nil)
+	require.NoError(t, err)
+	_, err = newProcessorFromParsed(pConf)
+	require.Error(t, err)
+}
+
+func TestParseUserAgentMethod(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_user_agent()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(iPhoneUA)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"family":  "iOS",
+		"version": "17.1",
+		"major":   "17",
+		"minor":   "1",
+	}, res.(map[string]any)["os"])
+}
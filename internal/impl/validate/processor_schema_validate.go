@@ -0,0 +1,333 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	cuejson "cuelang.org/go/encoding/json"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	svFieldJSONSchema     = "json_schema"
+	svFieldJSONSchemaPath = "json_schema_path"
+	svFieldCUE            = "cue"
+	svFieldCUEPath        = "cue_path"
+	svFieldCUEDefinition  = "cue_definition"
+	svFieldOnFailure      = "on_failure"
+
+	svOnFailureReject = "reject"
+	svOnFailureTag    = "tag"
+	svOnFailureDrop   = "drop"
+
+	svMetaValid  = "schema_valid"
+	svMetaErrors = "schema_validation_errors"
+)
+
+func schemaValidateProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Mapping").
+		Version("4.62.0").
+		Summary("Validates messages against a JSON Schema or a CUE schema.").
+		Description(`
+Exactly one of the fields `+"`"+svFieldJSONSchema+"`, `"+svFieldJSONSchemaPath+"`, `"+svFieldCUE+"` or `"+svFieldCUEPath+"`"+` must be set.
+
+JSON Schemas of all drafts from draft 4 up to and including draft 2020-12 are supported, where the draft is determined by the `+"`$schema`"+` keyword of the schema and defaults to draft 2020-12, including support for `+"`$dynamicRef`"+`. References to other schemas are resolved relative to the schema file when the schema is loaded with `+"`"+svFieldJSONSchemaPath+"`"+`.
+
+CUE schemas are unified with the contents of each message, which is valid when the result is concrete and free of conflicts. The field `+"`"+svFieldCUEDefinition+"`"+` selects a definition of the schema to validate against, otherwise the whole schema is used.
+
+== Failures
+
+The action taken when a message fails validation is determined by the field `+"`"+svFieldOnFailure+"`"+`. Messages rejected with the action `+"`"+svOnFailureReject+"`"+` are flagged as having failed and can be routed with xref:configuration:error_handling.adoc[error handling] patterns such as a `+"`switch`"+` output checking `+"`errored()`"+`. Messages that are not valid JSON are considered invalid.
+
+== Metadata
+
+When `+"`"+svFieldOnFailure+"`"+` is `+"`"+svOnFailureTag+"`"+` the following metadata fields are added to each message:
+
+- `+svMetaValid+`: Whether the message is valid, as a boolean.
+- `+svMetaErrors+`: A description of the validation errors, only added to invalid messages.
+`).
+		Fields(
+			service.NewStringField(svFieldJSONSchema).
+				Description("An inline JSON Schema to validate messages against.").
+				Example(`{"type":"object","required":["id"],"properties":{"id":{"type":"integer"}}}`).
+				Optional(),
+			service.NewStringField(svFieldJSONSchemaPath).
+				Description("The path of a file containing a JSON Schema to validate messages against.").
+				Example("./schemas/event.json").
+				Optional(),
+			service.NewStringField(svFieldCUE).
+				Description("An inline CUE schema to validate messages against.").
+				Example(`id: int & >0
+name: string`).
+				Optional(),
+			service.NewStringField(svFieldCUEPath).
+				Description("The path of a file containing a CUE schema to validate messages against.").
+				Example("./schemas/event.cue").
+				Optional(),
+			service.NewStringField(svFieldCUEDefinition).
+				Description("An optional path of a definition within the CUE schema to validate messages against.").
+				Example("#Event").
+				Optional().
+				Advanced(),
+			service.NewStringAnnotatedEnumField(svFieldOnFailure, map[string]string{
+				svOnFailureReject: "Flag invalid messages as having failed with the validation errors.",
+				svOnFailureTag:    "Pass all messages through with metadata describing whether they are valid.",
+				svOnFailureDrop:   "Drop invalid messages.",
+			}).
+				Description("The action to take on messages that fail validation.").
+				Default(svOnFailureReject),
+		).
+		Example("Dead letter invalid events", "Validate events against a JSON Schema and send invalid events to a dead letter topic.", `
+pipeline:
+  processors:
+    - schema_validate:
+        json_schema_path: ./schemas/event.json
+
+output:
+  switch:
+    cases:
+      - check: errored()
+        output:
+          kafka_franz:
+            seed_brokers: [ localhost:9092 ]
+            topic: events_dlq
+          processors:
+            - mutation: meta validation_error = error()
+      - output:
+          kafka_franz:
+            seed_brokers: [ localhost:9092 ]
+            topic: events
+`)
+}
+
+func init() {
+	service.MustRegisterProcessor(
+		"schema_validate", schemaValidateProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newSchemaValidateFromConfig(conf, mgr)
+		})
+}
+
+// validator validates the JSON contents of a message.
+type validator interface {
+	validate(doc []byte) error
+}
+
+type schemaValidate struct {
+	validator validator
+	onFailure string
+}
+
+func newSchemaValidateFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*schemaValidate, error) {
+	var set []string
+	for _, f := range []string{svFieldJSONSchema, svFieldJSONSchemaPath, svFieldCUE, svFieldCUEPath} {
+		if conf.Contains(f) {
+			set = append(set, f)
+		}
+	}
+	if len(set) != 1 {
+		return nil, fmt.Errorf("exactly one of the fields %v, %v, %v or %v must be set", svFieldJSONSchema, svFieldJSONSchemaPath, svFieldCUE, svFieldCUEPath)
+	}
+
+	p := &schemaValidate{}
+
+	var err error
+	if p.onFailure, err = conf.FieldString(svFieldOnFailure); err != nil {
+		return nil, err
+	}
+
+	str, err := conf.FieldString(set[0])
+	if err != nil {
+		return nil, err
+	}
+	switch set[0] {
+	case svFieldJSONSchema:
+		p.validator, err = newJSONSchemaValidator(str, "")
+	case svFieldJSONSchemaPath:
+		p.validator, err = newJSONSchemaValidator("", str)
+	case svFieldCUE, svFieldCUEPath:
+		if set[0] == svFieldCUEPath {
+			var b []byte
+			if b, err = service.ReadFile(mgr.FS(), str); err != nil {
+				return nil, fmt.Errorf("failed to read CUE schema: %w", err)
+			}
+			str = string(b)
+		}
+		var def string
+		if conf.Contains(svFieldCUEDefinition) {
+			if def, err = conf.FieldString(svFieldCUEDefinition); err != nil {
+				return nil, err
+			}
+		}
+		p.validator, err = newCUEValidator(str, def)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *schemaValidate) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	doc, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	vErr := p.validator.validate(doc)
+	switch p.onFailure {
+	case svOnFailureTag:
+		msg.MetaSetMut(svMetaValid, vErr == nil)
+		if vErr != nil {
+			msg.MetaSetMut(svMetaErrors, vErr.Error())
+		}
+	case svOnFailureDrop:
+		if vErr != nil {
+			return nil, nil
+		}
+	default:
+		if vErr != nil {
+			return nil, vErr
+		}
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (*schemaValidate) Close(context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type jsonSchemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+func newJSONSchemaValidator(inline, path string) (*jsonSchemaValidator, error) {
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.UseLoader(jsonschema.SchemeURLLoader{"file": jsonschema.FileLoader{}})
+
+	loc := path
+	if path == "" {
+		doc, err := jsonschema.UnmarshalJSON(strings.NewReader(inline))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON Schema: %w", err)
+		}
+		loc = "inline.json"
+		if err := c.AddResource(loc, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := c.Compile(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile JSON Schema: %w", err)
+	}
+	return &jsonSchemaValidator{schema: schema}, nil
+}
+
+func (j *jsonSchemaValidator) validate(doc []byte) error {
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(doc))
+	if err != nil {
+		return fmt.Errorf("failed to parse message as JSON: %w", err)
+	}
+	if err := j.schema.Validate(v); err != nil {
+		var vErr *jsonschema.ValidationError
+		if errors.As(err, &vErr) {
+			return errors.New(jsonSchemaErrorString(vErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// jsonSchemaErrorString describes the leaf errors of a validation error on a
+// single line.
+func jsonSchemaErrorString(err *jsonschema.ValidationError) string {
+	var descs []string
+	var walk func(u *jsonschema.OutputUnit)
+	walk = func(u *jsonschema.OutputUnit) {
+		if len(u.Errors) == 0 && u.Error != nil {
+			loc := u.InstanceLocation
+			if loc == "" {
+				loc = "/"
+			}
+			descs = append(descs, fmt.Sprintf("%v: %v", loc, u.Error.String()))
+		}
+		for i := range u.Errors {
+			walk(&u.Errors[i])
+		}
+	}
+	walk(err.BasicOutput())
+	if len(descs) == 0 {
+		return err.Error()
+	}
+	return strings.Join(descs, "; ")
+}
+
+//------------------------------------------------------------------------------
+
+type cueValidator struct {
+	// Values of a CUE context are not safe for concurrent use.
+	mut    sync.Mutex
+	ctx    *cue.Context
+	schema cue.Value
+}
+
+func newCUEValidator(src, definition string) (*cueValidator, error) {
+	ctx := cuecontext.New()
+	schema := ctx.CompileString(src)
+	if err := schema.Err(); err != nil {
+		return nil, fmt.Errorf("failed to compile CUE schema: %w", err)
+	}
+	if definition != "" {
+		if schema = schema.LookupPath(cue.ParsePath(definition)); !schema.Exists() {
+			return nil, fmt.Errorf("CUE definition %v was not found", definition)
+		}
+	}
+	return &cueValidator{ctx: ctx, schema: schema}, nil
+}
+
+func (c *cueValidator) validate(doc []byte) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	expr, err := cuejson.Extract("message", doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse message as JSON: %w", err)
+	}
+	v := c.ctx.BuildExpr(expr)
+	if err := c.schema.Unify(v).Validate(cue.Concrete(true)); err != nil {
+		var descs []string
+		for _, e := range cueerrors.Errors(err) {
+			descs = append(descs, e.Error())
+		}
+		return errors.New(strings.Join(descs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testSchemaValidate(t *testing.T, conf string) *schemaValidate {
+	t.Helper()
+
+	pConf, err := schemaValidateProcessorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	proc, err := newSchemaValidateFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	return proc
+}
+
+func TestSchemaValidateJSONSchemaDynamicRef(t *testing.T) {
+	// A generic list schema where the item type is bound by a dynamic anchor
+	// in the referencing schema.
+	proc := testSchemaValidate(t, `
+json_schema: |
+  {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$id": "https://example.com/string-list",
+    "$ref": "list",
+    "$defs": {
+      "item": { "$dynamicAnchor": "item", "type": "string" },
+      "list": {
+        "$id": "list",
+        "type": "array",
+        "items": { "$dynamicRef": "#item" },
+        "$defs": {
+          "item": { "$dynamicAnchor": "item" }
+        }
+      }
+    }
+  }
+`)
+
+	res, err := proc.Process(t.Context(), service.NewMessage([]byte(`["a","b"]`)))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`["a",1]`)))
+	require.ErrorContains(t, err, "/1")
+}
+
+func TestSchemaValidateJSONSchemaPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "id.json"), []byte(`{"type":"integer","minimum":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "event.json"), []byte(`{
+  "type": "object",
+  "required": ["id"],
+  "properties": { "id": { "$ref": "id.json" } }
+}`), 0o644))
+
+	proc := testSchemaValidate(t, `
+json_schema_path: `+filepath.Join(dir, "event.json")+`
+`)
+
+	res, err := proc.Process(t.Context(), service.NewMessage([]byte(`{"id":5}`)))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`{"id":0}`)))
+	require.ErrorContains(t, err, "/id")
+}
+
+func TestSchemaValidateOnFailure(t *testing.T) {
+	schema := `json_schema: '{"type":"object","required":["id"]}'`
+
+	tag := testSchemaValidate(t, schema+"\non_failure: tag")
+	for _, test := range []struct {
+		input string
+		valid bool
+	}{
+		{input: `{"id":1}`, valid: true},
+		{input: `{}`, valid: false},
+		{input: `not json`, valid: false},
+	} {
+		res, err := tag.Process(t.Context(), service.NewMessage([]byte(test.input)))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		v, ok := res[0].MetaGetMut(svMetaValid)
+		require.True(t, ok)
+		assert.Equal(t, test.valid, v, test.input)
+		_, ok = res[0].MetaGetMut(svMetaErrors)
+		assert.Equal(t, !test.valid, ok, test.input)
+	}
+
+	drop := testSchemaValidate(t, schema+"\non_failure: drop")
+	res, err := drop.Process(t.Context(), service.NewMessage([]byte(`{}`)))
+	require.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestSchemaValidateCUE(t *testing.T) {
+	proc := testSchemaValidate(t, `
+cue: |
+  #Event: {
+    id:   int & >0
+    kind: "created" | "deleted"
+  }
+cue_definition: "#Event"
+`)
+
+	res, err := proc.Process(t.Context(), service.NewMessage([]byte(`{"id":1,"kind":"created"}`)))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	for _, input := range []string{
+		`{"id":0,"kind":"created"}`,
+		`{"id":1,"kind":"updated"}`,
+		`{"id":1}`,
+		`{"id":1,"kind":"created","extra":true}`,
+		`not json`,
+	} {
+		_, err = proc.Process(t.Context(), service.NewMessage([]byte(input)))
+		require.Error(t, err, input)
+	}
+}
+
+func TestSchemaValidateConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`on_failure: tag`,
+		"json_schema: '{}'\ncue: 'a: int'",
+	} {
+		pConf, err := schemaValidateProcessorConfig().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = newSchemaValidateFromConfig(pConf, service.MockResources())
+		require.ErrorContains(t, err, "exactly one of the fields")
+	}
+}
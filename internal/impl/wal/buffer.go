@@ -0,0 +1,744 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/dustin/go-humanize"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	wbFieldPath         = "path"
+	wbFieldSegmentSize  = "segment_size"
+	wbFieldMaxSize      = "max_size"
+	wbFieldWhenFull     = "when_full"
+	wbFieldSync         = "sync"
+	wbFieldSyncInterval = "sync_interval"
+
+	wbWhenFullBackpressure = "backpressure"
+	wbWhenFullDropOldest   = "drop_oldest"
+
+	wbSyncAlways   = "always"
+	wbSyncInterval = "interval"
+	wbSyncNever    = "never"
+
+	checkpointFile = "checkpoint"
+)
+
+func bufferSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Stores messages in a write-ahead log of segment files on disk and acknowledges them at the input level, so that output outages and restarts don't lose data.").
+		Description(`
+Batches are appended as records to the current segment file within the directory `+"`"+wbFieldPath+"`"+`, and a new segment is started once it reaches the `+"`"+wbFieldSegmentSize+"`"+`. Records are consumed in order and segments are deleted once all of their records have been delivered. Each record carries a CRC checksum, and records that were only partially written before a crash are discarded when the buffer starts.
+
+The sequence number of the oldest record that hasn't been delivered is stored within a checkpoint file, and when the buffer starts it resumes consuming from this record. Records that were delivered after this record but before a restart are delivered again.
+
+== Delivery guarantees
+
+Messages are acknowledged at the input level once they're written to the log. When `+"`"+wbFieldSync+"`"+` is `+"`"+wbSyncAlways+"`"+` each write is flushed to disk with fsync before it's acknowledged, which preserves at-least-once delivery guarantees when the machine crashes. The `+"`"+wbSyncInterval+"`"+` and `+"`"+wbSyncNever+"`"+` policies are faster, but writes that weren't flushed yet can be lost when the machine, rather than only the service, crashes.
+
+== Retention
+
+When `+"`"+wbFieldMaxSize+"`"+` is set, the total size of the segments is limited, which is enforced at the granularity of segments. Once the log is full, the `+"`"+wbFieldWhenFull+"`"+` policy either applies back pressure to the input until records have been delivered, or deletes the oldest segment, dropping its records whether they've been delivered or not.
+
+== Batching
+
+Messages that are logically batched at the point where they are added to the buffer will continue to be associated with that batch when they are consumed. Writing larger batches is much more efficient, especially with the `+"`"+wbSyncAlways+"`"+` policy, and therefore it is recommended to use batching at the input level in high-throughput use cases.`).
+		Fields(
+			service.NewStringField(wbFieldPath).
+				Description("The directory of the log, which is created if it doesn't exist.").
+				Example("./data/wal"),
+			service.NewStringField(wbFieldSegmentSize).
+				Description("The size at which a new segment file is started.").
+				Default("64MiB").
+				Advanced(),
+			service.NewStringField(wbFieldMaxSize).
+				Description("The maximum total size of the log, where zero means unlimited.").
+				Examples("10GiB", "500MB").
+				Default("0"),
+			service.NewStringAnnotatedEnumField(wbFieldWhenFull, map[string]string{
+				wbWhenFullBackpressure: "Block writes until records have been delivered and their segments deleted.",
+				wbWhenFullDropOldest:   "Delete the oldest segment, dropping records that weren't delivered.",
+			}).
+				Description("What to do when writing a batch would exceed the `"+wbFieldMaxSize+"`.").
+				Default(wbWhenFullBackpressure),
+			service.NewStringAnnotatedEnumField(wbFieldSync, map[string]string{
+				wbSyncAlways:   "Flush each write and checkpoint to disk before acknowledging it.",
+				wbSyncInterval: "Flush writes and checkpoints to disk every `" + wbFieldSyncInterval + "`.",
+				wbSyncNever:    "Leave flushing writes to disk to the operating system.",
+			}).
+				Description("The policy of flushing writes to disk with fsync.").
+				Default(wbSyncAlways),
+			service.NewDurationField(wbFieldSyncInterval).
+				Description("The period between flushes when the `"+wbFieldSync+"` policy is `"+wbSyncInterval+"`.").
+				Default("1s").
+				Advanced(),
+		).
+		Example("Surviving output outages", "Buffer up to 20GiB of messages on disk while the output is unavailable, dropping the oldest messages once the disk budget is exhausted.", `
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ events ]
+    consumer_group: archiver
+
+buffer:
+  wal:
+    path: /var/lib/redpanda-connect/wal
+    max_size: 20GiB
+    when_full: drop_oldest
+    sync: interval
+
+output:
+  http_client:
+    url: https://collector.example.com/events
+`)
+}
+
+func init() {
+	service.MustRegisterBatchBuffer("wal", bufferSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newBufferFromConfig(conf, mgr)
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type bufferConfig struct {
+	dir          string
+	segmentSize  int64
+	maxSize      int64
+	dropOldest   bool
+	syncPolicy   string
+	syncInterval time.Duration
+}
+
+func sizeField(conf *service.ParsedConfig, name string) (int64, error) {
+	str, err := conf.FieldString(name)
+	if err != nil {
+		return 0, err
+	}
+	size, err := humanize.ParseBytes(str)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %v: %w", name, err)
+	}
+	return int64(size), nil
+}
+
+func newBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*buffer, error) {
+	var c bufferConfig
+
+	var err error
+	if c.dir, err = conf.FieldString(wbFieldPath); err != nil {
+		return nil, err
+	}
+	if c.segmentSize, err = sizeField(conf, wbFieldSegmentSize); err != nil {
+		return nil, err
+	}
+	if c.segmentSize <= 0 {
+		return nil, fmt.Errorf("%v must be greater than zero", wbFieldSegmentSize)
+	}
+	if c.maxSize, err = sizeField(conf, wbFieldMaxSize); err != nil {
+		return nil, err
+	}
+	if c.maxSize > 0 && c.maxSize < c.segmentSize {
+		return nil, fmt.Errorf("%v must not be smaller than %v", wbFieldMaxSize, wbFieldSegmentSize)
+	}
+
+	whenFull, err := conf.FieldString(wbFieldWhenFull)
+	if err != nil {
+		return nil, err
+	}
+	c.dropOldest = whenFull == wbWhenFullDropOldest
+
+	if c.syncPolicy, err = conf.FieldString(wbFieldSync); err != nil {
+		return nil, err
+	}
+	if c.syncInterval, err = conf.FieldDuration(wbFieldSyncInterval); err != nil {
+		return nil, err
+	}
+	if c.syncPolicy == wbSyncInterval && c.syncInterval <= 0 {
+		return nil, fmt.Errorf("%v must be greater than zero", wbFieldSyncInterval)
+	}
+	return newBuffer(c, mgr.Logger())
+}
+
+//------------------------------------------------------------------------------
+
+type pendingRecord struct {
+	seq   uint64
+	batch service.MessageBatch
+}
+
+type buffer struct {
+	conf bufferConfig
+	log  *service.Logger
+
+	cond *sync.Cond
+
+	// The segments of the log ordered by sequence number, where the last
+	// segment is the one being written to.
+	segments   []*segment
+	active     *os.File
+	totalSize  int64
+	nextSeq    uint64
+	unsynced   bool
+	checkpoint *os.File
+	persisted  uint64
+
+	// The reader consumes the records of readSeg from readOff, where readSeq
+	// is the sequence number following the last record read.
+	readSeg  *segment
+	readFile *os.File
+	readOff  int64
+	readSeq  uint64
+
+	// Records that have been read but not yet delivered, and those that were
+	// rejected downstream and are therefore read again.
+	inFlight map[uint64]struct{}
+	requeued []pendingRecord
+
+	endOfInput bool
+	closed     bool
+
+	shutSig *shutdown.Signaller
+}
+
+func newBuffer(conf bufferConfig, log *service.Logger) (*buffer, error) {
+	if err := os.MkdirAll(conf.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	b := &buffer{
+		conf:     conf,
+		log:      log,
+		cond:     sync.NewCond(&sync.Mutex{}),
+		inFlight: map[uint64]struct{}{},
+		shutSig:  shutdown.NewSignaller(),
+	}
+	if err := b.open(); err != nil {
+		b.closeFiles()
+		return nil, err
+	}
+
+	if conf.syncPolicy == wbSyncInterval {
+		go b.syncLoop()
+	} else {
+		b.shutSig.TriggerHasStopped()
+	}
+	return b, nil
+}
+
+// open recovers the segments and checkpoint of the log directory.
+func (b *buffer) open() error {
+	var err error
+	if b.checkpoint, err = os.OpenFile(filepath.Join(b.conf.dir, checkpointFile), os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return err
+	}
+	var cpBytes [8]byte
+	if _, err := b.checkpoint.ReadAt(cpBytes[:], 0); err == nil {
+		b.persisted = binary.BigEndian.Uint64(cpBytes[:])
+	} else if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	segs, err := listSegments(b.conf.dir)
+	if err != nil {
+		return err
+	}
+
+	b.nextSeq = b.persisted
+	for _, s := range segs {
+		truncated, err := s.recover()
+		if err != nil {
+			return fmt.Errorf("failed to recover segment %v: %w", s.path, err)
+		}
+		if truncated > 0 {
+			b.log.Warnf("Truncated %v bytes of partially written records from segment %v", truncated, s.path)
+		}
+		if s.nextSeq <= b.persisted && s.size > 0 {
+			// All records of this segment were delivered.
+			if err := os.Remove(s.path); err != nil {
+				return err
+			}
+			continue
+		}
+		if s.size == 0 && s != segs[len(segs)-1] {
+			if err := os.Remove(s.path); err != nil {
+				return err
+			}
+			continue
+		}
+		b.segments = append(b.segments, s)
+		b.totalSize += s.size
+		b.nextSeq = max(b.nextSeq, s.nextSeq)
+	}
+
+	if len(b.segments) == 0 {
+		s := &segment{path: segmentPath(b.conf.dir, b.nextSeq), firstSeq: b.nextSeq, nextSeq: b.nextSeq}
+		b.segments = append(b.segments, s)
+	}
+
+	last := b.segments[len(b.segments)-1]
+	if b.active, err = os.OpenFile(last.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644); err != nil {
+		return err
+	}
+
+	b.readSeg = b.segments[0]
+	b.readSeq = max(b.readSeg.firstSeq, b.persisted)
+	return nil
+}
+
+func (b *buffer) closeFiles() {
+	for _, f := range []*os.File{b.active, b.readFile, b.checkpoint} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	b.active, b.readFile, b.checkpoint = nil, nil, nil
+}
+
+func (b *buffer) syncLoop() {
+	defer b.shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(b.conf.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.shutSig.SoftStopChan():
+			return
+		}
+
+		b.cond.L.Lock()
+		if !b.closed {
+			if err := b.sync(); err != nil {
+				b.log.Errorf("Failed to flush log to disk: %v", err)
+			}
+		}
+		b.cond.L.Unlock()
+	}
+}
+
+// sync flushes writes to the active segment and checkpoint to disk.
+func (b *buffer) sync() error {
+	if !b.unsynced {
+		return nil
+	}
+	if err := b.active.Sync(); err != nil {
+		return err
+	}
+	if err := b.checkpoint.Sync(); err != nil {
+		return err
+	}
+	b.unsynced = false
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// watermark returns the sequence number of the oldest record that hasn't been
+// delivered.
+func (b *buffer) watermark() uint64 {
+	w := b.readSeq
+	for seq := range b.inFlight {
+		w = min(w, seq)
+	}
+	return w
+}
+
+func (b *buffer) persistCheckpoint() error {
+	w := b.watermark()
+	if w == b.persisted {
+		return nil
+	}
+
+	var cpBytes [8]byte
+	binary.BigEndian.PutUint64(cpBytes[:], w)
+	if _, err := b.checkpoint.WriteAt(cpBytes[:], 0); err != nil {
+		return err
+	}
+	if b.conf.syncPolicy == wbSyncAlways {
+		if err := b.checkpoint.Sync(); err != nil {
+			return err
+		}
+	} else {
+		b.unsynced = true
+	}
+	b.persisted = w
+	return nil
+}
+
+// roll starts a new segment, removing the active segment when it's empty.
+func (b *buffer) roll() error {
+	if err := b.active.Sync(); err != nil {
+		return err
+	}
+	if err := b.active.Close(); err != nil {
+		return err
+	}
+
+	s := &segment{path: segmentPath(b.conf.dir, b.nextSeq), firstSeq: b.nextSeq, nextSeq: b.nextSeq}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	b.active = f
+
+	if last := b.segments[len(b.segments)-1]; last.size == 0 {
+		b.segments = b.segments[:len(b.segments)-1]
+		if b.readSeg == last {
+			b.moveReader(s)
+		}
+		if err := os.Remove(last.path); err != nil {
+			return err
+		}
+	}
+	b.segments = append(b.segments, s)
+	return nil
+}
+
+func (b *buffer) moveReader(s *segment) {
+	if b.readFile != nil {
+		_ = b.readFile.Close()
+		b.readFile = nil
+	}
+	b.readSeg = s
+	b.readOff = 0
+	b.readSeq = max(b.readSeq, s.firstSeq)
+}
+
+// removeOldest deletes the oldest segment, which must not be the active one.
+func (b *buffer) removeOldest() error {
+	s := b.segments[0]
+	b.segments = b.segments[1:]
+	if b.readSeg == s {
+		b.moveReader(b.segments[0])
+	}
+	b.totalSize -= s.size
+	return os.Remove(s.path)
+}
+
+// release deletes the segments of which all records have been delivered.
+func (b *buffer) release() error {
+	w := b.watermark()
+	for len(b.segments) > 1 && b.segments[0].nextSeq <= w {
+		if err := b.removeOldest(); err != nil {
+			return err
+		}
+	}
+	return b.persistCheckpoint()
+}
+
+// dropOldest deletes the oldest segment in order to free space, dropping any
+// of its records that weren't delivered. Returns false when there is nothing
+// to drop.
+func (b *buffer) dropOldest() (bool, error) {
+	if len(b.segments) == 1 {
+		if b.segments[0].size == 0 {
+			return false, nil
+		}
+		if err := b.roll(); err != nil {
+			return false, err
+		}
+	}
+
+	s := b.segments[0]
+	dropped := 0
+	if s.nextSeq > b.readSeq {
+		dropped = int(s.nextSeq - max(b.readSeq, s.firstSeq))
+	}
+	for seq := range b.inFlight {
+		if seq < s.nextSeq {
+			delete(b.inFlight, seq)
+			dropped++
+		}
+	}
+	requeued := b.requeued[:0]
+	for _, r := range b.requeued {
+		if r.seq >= s.nextSeq {
+			requeued = append(requeued, r)
+		}
+	}
+	b.requeued = requeued
+
+	if err := b.removeOldest(); err != nil {
+		return false, err
+	}
+	if dropped > 0 {
+		b.log.Warnf("Log exceeded %v, dropped %v undelivered batches", humanize.IBytes(uint64(b.conf.maxSize)), dropped)
+	}
+	return true, b.persistCheckpoint()
+}
+
+// waitWithContext waits for the condition to be signalled or the context to
+// be cancelled, the lock of the condition must be held.
+func (b *buffer) waitWithContext(ctx context.Context) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		b.cond.L.Lock()
+		b.cond.Broadcast()
+		b.cond.L.Unlock()
+	}()
+	b.cond.Wait()
+}
+
+// WriteBatch appends a batch to the log.
+func (b *buffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	b.cond.L.Lock()
+
+	if b.closed {
+		b.cond.L.Unlock()
+		return service.ErrEndOfBuffer
+	}
+
+	rec, err := encodeRecord(b.nextSeq, batch)
+	if err != nil {
+		b.cond.L.Unlock()
+		return err
+	}
+	recLen := int64(len(rec))
+	if b.conf.maxSize > 0 && recLen > b.conf.maxSize {
+		b.cond.L.Unlock()
+		return fmt.Errorf("batch of %v exceeds the %v of the log", humanize.IBytes(uint64(recLen)), wbFieldMaxSize)
+	}
+
+	for b.conf.maxSize > 0 && b.totalSize+recLen > b.conf.maxSize {
+		if b.conf.dropOldest {
+			dropped, err := b.dropOldest()
+			if err != nil {
+				b.cond.L.Unlock()
+				return err
+			}
+			if !dropped {
+				break
+			}
+			continue
+		}
+
+		if active := b.segments[len(b.segments)-1]; active.size > 0 && b.watermark() == b.nextSeq {
+			// Every record has been delivered, and so the active segment is
+			// replaced in order to delete it.
+			if err := b.roll(); err == nil {
+				err = b.removeOldest()
+			}
+			if err != nil {
+				b.cond.L.Unlock()
+				return err
+			}
+			continue
+		}
+
+		b.waitWithContext(ctx)
+		if b.closed {
+			b.cond.L.Unlock()
+			return service.ErrEndOfBuffer
+		}
+		if err := ctx.Err(); err != nil {
+			b.cond.L.Unlock()
+			return err
+		}
+	}
+
+	active := b.segments[len(b.segments)-1]
+	if active.size > 0 && active.size+recLen > b.conf.segmentSize {
+		if err := b.roll(); err != nil {
+			b.cond.L.Unlock()
+			return err
+		}
+		active = b.segments[len(b.segments)-1]
+	}
+
+	if _, err := b.active.Write(rec); err != nil {
+		b.cond.L.Unlock()
+		return err
+	}
+	if b.conf.syncPolicy == wbSyncAlways {
+		if err := b.active.Sync(); err != nil {
+			b.cond.L.Unlock()
+			return err
+		}
+	} else {
+		b.unsynced = true
+	}
+
+	b.nextSeq++
+	active.nextSeq = b.nextSeq
+	active.size += recLen
+	b.totalSize += recLen
+
+	b.cond.Broadcast()
+	b.cond.L.Unlock()
+
+	return aFn(ctx, nil)
+}
+
+// readNext reads the next record of the log, returning false when the reader
+// has reached the end of the log.
+func (b *buffer) readNext() (pendingRecord, bool, error) {
+	for {
+		if b.readOff >= b.readSeg.size {
+			i := 0
+			for i < len(b.segments) && b.segments[i] != b.readSeg {
+				i++
+			}
+			if i+1 >= len(b.segments) {
+				return pendingRecord{}, false, nil
+			}
+			b.moveReader(b.segments[i+1])
+			continue
+		}
+
+		if b.readFile == nil {
+			var err error
+			if b.readFile, err = os.Open(b.readSeg.path); err != nil {
+				return pendingRecord{}, false, err
+			}
+		}
+
+		var r pendingRecord
+		seq, n, err := readRecordAt(b.readFile, b.readOff, b.readSeg.size, &r.batch)
+		if err != nil {
+			if errors.Is(err, errCorruptRecord) {
+				b.log.Errorf("Skipping the remaining records of segment %v: %v", b.readSeg.path, err)
+				b.readOff = b.readSeg.size
+				b.readSeq = max(b.readSeq, b.readSeg.nextSeq)
+				continue
+			}
+			return pendingRecord{}, false, err
+		}
+		b.readOff += n
+		if seq < b.readSeq {
+			// Delivered before the checkpoint was persisted.
+			continue
+		}
+		b.readSeq = seq + 1
+		r.seq = seq
+		return r, true, nil
+	}
+}
+
+func (b *buffer) ackFn(r pendingRecord) service.AckFunc {
+	return func(_ context.Context, err error) error {
+		b.cond.L.Lock()
+		defer b.cond.L.Unlock()
+
+		if _, exists := b.inFlight[r.seq]; !exists || b.closed {
+			// The record was dropped.
+			return nil
+		}
+		if err != nil {
+			b.requeued = append(b.requeued, r)
+			b.cond.Broadcast()
+			return nil
+		}
+
+		delete(b.inFlight, r.seq)
+		err = b.release()
+		b.cond.Broadcast()
+		return err
+	}
+}
+
+// ReadBatch reads the oldest batch that hasn't been read from the log.
+func (b *buffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	for {
+		if b.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		if len(b.requeued) > 0 {
+			r := b.requeued[0]
+			b.requeued = b.requeued[1:]
+			return r.batch.Copy(), b.ackFn(r), nil
+		}
+
+		r, ok, err := b.readNext()
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			b.inFlight[r.seq] = struct{}{}
+			return r.batch.Copy(), b.ackFn(r), nil
+		}
+		if b.endOfInput {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		b.waitWithContext(ctx)
+	}
+}
+
+// EndOfInput signals to the buffer that the input is finished and therefore
+// once the log is drained it should close.
+func (b *buffer) EndOfInput() {
+	go func() {
+		b.cond.L.Lock()
+		defer b.cond.L.Unlock()
+
+		b.endOfInput = true
+		b.cond.Broadcast()
+	}()
+}
+
+// Close flushes the log to disk and closes its files.
+func (b *buffer) Close(ctx context.Context) error {
+	b.shutSig.TriggerSoftStop()
+	select {
+	case <-b.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.cond.Broadcast()
+
+	err := b.persistCheckpoint()
+	if serr := b.sync(); err == nil {
+		err = serr
+	}
+	b.closeFiles()
+	return err
+}
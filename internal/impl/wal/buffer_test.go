@@ -0,0 +1,256 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestBuffer(t *testing.T, dir, extra string) *buffer {
+	t.Helper()
+
+	conf, err := bufferSpec().ParseYAML("path: "+dir+"\n"+extra, nil)
+	require.NoError(t, err)
+
+	b, err := newBufferFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	return b
+}
+
+func noopAck(context.Context, error) error {
+	return nil
+}
+
+func writeMessages(t *testing.T, b *buffer, contents ...string) {
+	t.Helper()
+
+	for _, c := range contents {
+		msg := service.NewMessage([]byte(c))
+		msg.MetaSetMut("content", c)
+		require.NoError(t, b.WriteBatch(t.Context(), service.MessageBatch{msg}, noopAck))
+	}
+}
+
+func readMessage(t *testing.T, b *buffer) (string, service.AckFunc) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(t.Context(), time.Second*5)
+	defer done()
+
+	batch, aFn, err := b.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	content, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	v, ok := batch[0].MetaGetMut("content")
+	require.True(t, ok)
+	assert.Equal(t, string(content), v)
+	return string(content), aFn
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestBufferReadWrite(t *testing.T) {
+	dir := t.TempDir()
+	b := newTestBuffer(t, dir, "segment_size: 100B")
+
+	writeMessages(t, b, "foo", "bar", "baz")
+	assert.Greater(t, len(segmentFiles(t, dir)), 1)
+
+	for _, exp := range []string{"foo", "bar", "baz"} {
+		content, aFn := readMessage(t, b)
+		assert.Equal(t, exp, content)
+		require.NoError(t, aFn(t.Context(), nil))
+	}
+	assert.Len(t, segmentFiles(t, dir), 1)
+
+	b.EndOfInput()
+	_, _, err := b.ReadBatch(t.Context())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+	require.NoError(t, b.Close(t.Context()))
+}
+
+func TestBufferNackRedelivery(t *testing.T) {
+	b := newTestBuffer(t, t.TempDir(), "")
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+
+	writeMessages(t, b, "foo", "bar")
+
+	content, aFn := readMessage(t, b)
+	assert.Equal(t, "foo", content)
+	require.NoError(t, aFn(t.Context(), errors.New("nope")))
+
+	content, aFn = readMessage(t, b)
+	assert.Equal(t, "foo", content)
+	require.NoError(t, aFn(t.Context(), nil))
+
+	content, _ = readMessage(t, b)
+	assert.Equal(t, "bar", content)
+}
+
+func TestBufferReplayAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	b := newTestBuffer(t, dir, "segment_size: 100B")
+	writeMessages(t, b, "foo", "bar", "baz", "qux")
+
+	_, fooAck := readMessage(t, b)
+	_, barAck := readMessage(t, b)
+	_, bazAck := readMessage(t, b)
+	require.NoError(t, fooAck(t.Context(), nil))
+	require.NoError(t, bazAck(t.Context(), nil))
+	_ = barAck
+	require.NoError(t, b.Close(t.Context()))
+
+	// The delivery of bar wasn't acknowledged, and therefore bar and all
+	// records following it are delivered again.
+	b = newTestBuffer(t, dir, "segment_size: 100B")
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+	for _, exp := range []string{"bar", "baz", "qux"} {
+		content, aFn := readMessage(t, b)
+		assert.Equal(t, exp, content)
+		require.NoError(t, aFn(t.Context(), nil))
+	}
+}
+
+func TestBufferTruncatesPartialRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	b := newTestBuffer(t, dir, "")
+	writeMessages(t, b, "foo", "bar")
+	require.NoError(t, b.Close(t.Context()))
+
+	segs := segmentFiles(t, dir)
+	require.Len(t, segs, 1)
+	info, err := os.Stat(segs[0])
+	require.NoError(t, err)
+
+	// Simulate a crash during the write of a record.
+	f, err := os.OpenFile(segs[0], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 100, 1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	b = newTestBuffer(t, dir, "")
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+
+	info2, err := os.Stat(segs[0])
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), info2.Size())
+
+	writeMessages(t, b, "baz")
+	for _, exp := range []string{"foo", "bar", "baz"} {
+		content, _ := readMessage(t, b)
+		assert.Equal(t, exp, content)
+	}
+}
+
+func TestBufferDropOldest(t *testing.T) {
+	dir := t.TempDir()
+	b := newTestBuffer(t, dir, `
+segment_size: 100B
+max_size: 200B
+when_full: drop_oldest
+sync: never
+`)
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+
+	var contents []string
+	for i := range 10 {
+		contents = append(contents, "message "+strconv.Itoa(i))
+	}
+	writeMessages(t, b, contents...)
+	assert.LessOrEqual(t, b.totalSize, int64(200))
+
+	content, _ := readMessage(t, b)
+	assert.NotEqual(t, "message 0", content)
+
+	var last string
+	for last != "message 9" {
+		last, _ = readMessage(t, b)
+	}
+}
+
+func TestBufferBackpressure(t *testing.T) {
+	b := newTestBuffer(t, t.TempDir(), `
+segment_size: 100B
+max_size: 200B
+sync: interval
+sync_interval: 10ms
+`)
+	t.Cleanup(func() {
+		require.NoError(t, b.Close(context.Background()))
+	})
+
+	var contents []string
+	for i := range 3 {
+		contents = append(contents, "message "+strconv.Itoa(i))
+	}
+	writeMessages(t, b, contents...)
+
+	ctx, done := context.WithTimeout(t.Context(), time.Millisecond*50)
+	defer done()
+	err := b.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("message 3"))}, noopAck)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	written := make(chan error, 1)
+	go func() {
+		msg := service.NewMessage([]byte("message 3"))
+		msg.MetaSetMut("content", "message 3")
+		written <- b.WriteBatch(t.Context(), service.MessageBatch{msg}, noopAck)
+	}()
+
+	for _, exp := range contents {
+		content, aFn := readMessage(t, b)
+		assert.Equal(t, exp, content)
+		require.NoError(t, aFn(t.Context(), nil))
+	}
+
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write was not unblocked")
+	}
+	content, _ := readMessage(t, b)
+	assert.Equal(t, "message 3", content)
+}
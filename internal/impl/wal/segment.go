@@ -0,0 +1,263 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	segmentExt = ".wal"
+
+	// Each record starts with the length of its payload followed by the CRC
+	// of its payload.
+	recordHeaderLen = 8
+
+	// The payload of each record starts with its sequence number followed by
+	// the encoded batch.
+	recordSeqLen = 8
+)
+
+var (
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errCorruptRecord = errors.New("corrupt record")
+)
+
+// segment is a file of records with consecutive sequence numbers, where the
+// file is named after the sequence number of its first record.
+type segment struct {
+	path     string
+	firstSeq uint64
+	nextSeq  uint64
+	size     int64
+}
+
+func segmentPath(dir string, firstSeq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", firstSeq, segmentExt))
+}
+
+// listSegments returns the segments of a directory ordered by their first
+// sequence number, without reading their records.
+func listSegments(dir string) ([]*segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segs []*segment
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		firstSeq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segs = append(segs, &segment{
+			path:     filepath.Join(dir, name),
+			firstSeq: firstSeq,
+			nextSeq:  firstSeq,
+		})
+	}
+	sort.Slice(segs, func(i, j int) bool {
+		return segs[i].firstSeq < segs[j].firstSeq
+	})
+	return segs, nil
+}
+
+// recover reads the records of a segment in order to determine its size and
+// the sequence number following its last record. A corrupt or partially
+// written record, which is expected at the end of a segment written during a
+// crash, is truncated along with any records following it, and the number of
+// truncated bytes is returned.
+func (s *segment) recover() (truncated int64, err error) {
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	for {
+		seq, n, err := readRecordAt(f, offset, info.Size(), nil)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if errors.Is(err, errCorruptRecord) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return 0, err
+		}
+		offset += n
+		s.nextSeq = seq + 1
+	}
+
+	if truncated = info.Size() - offset; truncated > 0 {
+		if err := f.Truncate(offset); err != nil {
+			return 0, err
+		}
+	}
+	s.size = offset
+	return truncated, nil
+}
+
+// readRecordAt reads the record at an offset of a segment file of a given
+// size, returning its sequence number and the number of bytes it occupies. The
+// batch of the record is only decoded when a non-nil batch pointer is
+// provided. io.EOF is returned when the offset is at the end of the file, and
+// io.ErrUnexpectedEOF when the record is only partially written.
+func readRecordAt(r io.ReaderAt, offset, size int64, batch *service.MessageBatch) (seq uint64, n int64, err error) {
+	if offset >= size {
+		return 0, 0, io.EOF
+	}
+	if offset+recordHeaderLen > size {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+
+	var header [recordHeaderLen]byte
+	if _, err = r.ReadAt(header[:], offset); err != nil {
+		return 0, 0, err
+	}
+
+	payloadLen := int64(binary.BigEndian.Uint32(header[:4]))
+	if payloadLen < recordSeqLen {
+		return 0, 0, errCorruptRecord
+	}
+	if offset+recordHeaderLen+payloadLen > size {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+
+	payload := make([]byte, payloadLen)
+	if _, err = r.ReadAt(payload, offset+recordHeaderLen); err != nil {
+		return 0, 0, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return 0, 0, errCorruptRecord
+	}
+
+	seq = binary.BigEndian.Uint64(payload[:recordSeqLen])
+	if batch != nil {
+		if *batch, err = decodeBatch(payload[recordSeqLen:]); err != nil {
+			return 0, 0, fmt.Errorf("%w: %v", errCorruptRecord, err)
+		}
+	}
+	return seq, recordHeaderLen + payloadLen, nil
+}
+
+// encodeRecord returns a record of a batch with a sequence number.
+func encodeRecord(seq uint64, batch service.MessageBatch) ([]byte, error) {
+	rec := make([]byte, recordHeaderLen+recordSeqLen)
+	binary.BigEndian.PutUint64(rec[recordHeaderLen:], seq)
+
+	rec, err := appendBatch(rec, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := rec[recordHeaderLen:]
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.Checksum(payload, crcTable))
+	return rec, nil
+}
+
+//------------------------------------------------------------------------------
+
+func appendBatch(b []byte, batch service.MessageBatch) ([]byte, error) {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(batch)))
+	for _, msg := range batch {
+		metaObj := map[string]any{}
+		_ = msg.MetaWalkMut(func(key string, value any) error {
+			metaObj[key] = value
+			return nil
+		})
+		metaBytes, err := msgpack.Marshal(metaObj)
+		if err != nil {
+			return nil, err
+		}
+		content, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		b = binary.BigEndian.AppendUint32(b, uint32(len(metaBytes)))
+		b = append(b, metaBytes...)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(content)))
+		b = append(b, content...)
+	}
+	return b, nil
+}
+
+func readChunk(b []byte) (chunk, remaining []byte, err error) {
+	if len(b) < 4 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	l := binary.BigEndian.Uint32(b)
+	if uint64(len(b)-4) < uint64(l) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	return b[4 : 4+l], b[4+l:], nil
+}
+
+func decodeBatch(b []byte) (service.MessageBatch, error) {
+	if len(b) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	count := binary.BigEndian.Uint32(b)
+	b = b[4:]
+
+	batch := make(service.MessageBatch, 0, count)
+	for range count {
+		var metaBytes, content []byte
+		var err error
+		if metaBytes, b, err = readChunk(b); err != nil {
+			return nil, err
+		}
+		if content, b, err = readChunk(b); err != nil {
+			return nil, err
+		}
+
+		metaObj := map[string]any{}
+		if err := msgpack.Unmarshal(metaBytes, &metaObj); err != nil {
+			return nil, err
+		}
+		msg := service.NewMessage(content)
+		for k, v := range metaObj {
+			msg.MetaSetMut(k, v)
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
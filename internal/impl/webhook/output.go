@@ -0,0 +1,349 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	woFieldURL               = "url"
+	woFieldVerb              = "verb"
+	woFieldHeaders           = "headers"
+	woFieldSignature         = "signature"
+	woFieldSignatureSecret   = "secret"
+	woFieldSignatureScheme   = "scheme"
+	woFieldSignatureHeader   = "header"
+	woFieldSignaturePrefix   = "prefix"
+	woFieldSignatureEncoding = "encoding"
+	woFieldIdempotencyHeader = "idempotency_header"
+	woFieldIdempotencyKey    = "idempotency_key"
+	woFieldTimeout           = "timeout"
+	woFieldBackoff           = "backoff"
+	woFieldTLS               = "tls"
+)
+
+func outputSpec() *service.ConfigSpec {
+	retryDefaults := backoff.NewExponentialBackOff()
+	retryDefaults.InitialInterval = time.Millisecond * 500
+	retryDefaults.MaxInterval = time.Second * 30
+	retryDefaults.MaxElapsedTime = time.Minute * 5
+
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Delivers messages to a webhook endpoint as signed HTTP requests, retrying failed deliveries.").
+		Description(`
+Each message is sent as the body of a request, which is signed with an HMAC-SHA256 signature of the body when `+"`"+woFieldSignature+"."+woFieldSignatureSecret+"`"+` is set:
+
+- `+"`github`"+`: The hex encoded signature is sent in the `+"`X-Hub-Signature-256`"+` header as `+"`sha256=<signature>`"+`.
+- `+"`stripe`"+`: The signature of `+"`<timestamp>.<body>`"+` is sent in the `+"`Stripe-Signature`"+` header as `+"`t=<timestamp>,v1=<signature>`"+`, allowing receivers to reject replayed requests.
+- `+"`custom`"+`: The signature is sent in the header `+"`"+woFieldSignatureHeader+"`"+`, encoded with `+"`"+woFieldSignatureEncoding+"`"+` and preceded by `+"`"+woFieldSignaturePrefix+"`"+`.
+
+== Retries
+
+Requests that fail with a connection error, or with a 408, 429 or 5xx status code, are retried according to `+"`"+woFieldBackoff+"`"+`, where each interval is randomised in order to avoid retries of many messages arriving at once. When the endpoint responds with a `+"`Retry-After`"+` header the retry is delayed until at least the requested time. Other status codes fail the delivery immediately.
+
+Every attempt to deliver a message carries the same idempotency key, so that endpoints can discard deliveries they have already processed. By default the key is derived from the content of the message, which means that identical messages share a key, and so it is recommended to set `+"`"+woFieldIdempotencyKey+"`"+` to a value that uniquely identifies an event when one is available.`+service.OutputPerformanceDocs(true, false)).
+		Fields(
+			service.NewInterpolatedStringField(woFieldURL).
+				Description("The URL of the webhook endpoint.").
+				Example("https://example.com/webhooks/orders"),
+			service.NewStringField(woFieldVerb).
+				Description("The HTTP verb to use.").
+				Default("POST").
+				Advanced(),
+			service.NewInterpolatedStringMapField(woFieldHeaders).
+				Description("A map of headers to add to each request.").
+				Example(map[string]any{"Content-Type": "application/json"}).
+				Default(map[string]any{"Content-Type": "application/json"}),
+			service.NewObjectField(woFieldSignature,
+				service.NewStringField(woFieldSignatureSecret).
+					Description("The secret used to sign requests. Requests are not signed when empty.").
+					Default("").
+					Secret(),
+				service.NewStringEnumField(woFieldSignatureScheme, "github", "stripe", "custom").
+					Description("The scheme used to present the signature.").
+					Default("github"),
+				service.NewStringField(woFieldSignatureHeader).
+					Description("The header holding the signature with the `custom` scheme.").
+					Default("X-Signature").
+					Advanced(),
+				service.NewStringField(woFieldSignaturePrefix).
+					Description("A prefix added to the signature with the `custom` scheme.").
+					Example("sha256=").
+					Default("").
+					Advanced(),
+				service.NewStringEnumField(woFieldSignatureEncoding, "hex", "base64").
+					Description("The encoding of the signature with the `custom` scheme.").
+					Default("hex").
+					Advanced(),
+			).Description("Determines how requests are signed."),
+			service.NewStringField(woFieldIdempotencyHeader).
+				Description("The header holding the idempotency key of a delivery. Set to an empty string in order to omit the key.").
+				Default("Idempotency-Key").
+				Advanced(),
+			service.NewInterpolatedStringField(woFieldIdempotencyKey).
+				Description("An optional idempotency key for each message. When not set the key is the SHA-256 hash of the message contents.").
+				Example(`${! @kafka_topic }-${! @kafka_partition }-${! @kafka_offset }`).
+				Example(`${! this.event_id }`).
+				Optional(),
+			service.NewDurationField(woFieldTimeout).
+				Description("The maximum period to wait for a response to each attempt.").
+				Default("30s").
+				Advanced(),
+			service.NewBackOffField(woFieldBackoff, false, retryDefaults).
+				Description("The backoff applied between attempts to deliver a message, after which the delivery fails.").
+				Advanced(),
+			service.NewTLSToggledField(woFieldTLS),
+			service.NewOutputMaxInFlightField(),
+		).
+		Example("Signed order events", "Deliver order events to a partner endpoint with GitHub style signatures, using the order ID as the idempotency key.", `
+output:
+  webhook:
+    url: https://partner.example.com/hooks/orders
+    signature:
+      secret: ${WEBHOOK_SECRET}
+      scheme: github
+    idempotency_key: ${! this.order_id }-${! this.status }
+`)
+}
+
+func init() {
+	service.MustRegisterOutput("webhook", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.Output, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out, err = newOutputFromConfig(conf, mgr)
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type output struct {
+	log *service.Logger
+
+	url               *service.InterpolatedString
+	verb              string
+	headers           map[string]*service.InterpolatedString
+	signer            *signer
+	idempotencyHeader string
+	idempotencyKey    *service.InterpolatedString
+	backoff           *backoff.ExponentialBackOff
+	client            *http.Client
+	nowFn             func() time.Time
+}
+
+func newOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{log: mgr.Logger(), nowFn: time.Now}
+
+	var err error
+	if o.url, err = conf.FieldInterpolatedString(woFieldURL); err != nil {
+		return nil, err
+	}
+	if o.verb, err = conf.FieldString(woFieldVerb); err != nil {
+		return nil, err
+	}
+	if o.headers, err = conf.FieldInterpolatedStringMap(woFieldHeaders); err != nil {
+		return nil, err
+	}
+
+	sigConf := conf.Namespace(woFieldSignature)
+	secret, err := sigConf.FieldString(woFieldSignatureSecret)
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" {
+		o.signer = &signer{secret: []byte(secret)}
+		if o.signer.scheme, err = sigConf.FieldString(woFieldSignatureScheme); err != nil {
+			return nil, err
+		}
+		if o.signer.header, err = sigConf.FieldString(woFieldSignatureHeader); err != nil {
+			return nil, err
+		}
+		if o.signer.prefix, err = sigConf.FieldString(woFieldSignaturePrefix); err != nil {
+			return nil, err
+		}
+		if o.signer.encoding, err = sigConf.FieldString(woFieldSignatureEncoding); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.idempotencyHeader, err = conf.FieldString(woFieldIdempotencyHeader); err != nil {
+		return nil, err
+	}
+	if conf.Contains(woFieldIdempotencyKey) {
+		if o.idempotencyKey, err = conf.FieldInterpolatedString(woFieldIdempotencyKey); err != nil {
+			return nil, err
+		}
+	}
+	if o.backoff, err = conf.FieldBackOff(woFieldBackoff); err != nil {
+		return nil, err
+	}
+
+	timeout, err := conf.FieldDuration(woFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(woFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled {
+		transport.TLSClientConfig = tlsConf
+	}
+	o.client = &http.Client{Transport: transport, Timeout: timeout}
+	return o, nil
+}
+
+func (*output) Connect(context.Context) error {
+	return nil
+}
+
+// retryableError is a failed delivery that may succeed when attempted again,
+// optionally after a delay requested by the endpoint.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *retryableError) Error() string {
+	return r.err.Error()
+}
+
+func (r *retryableError) Unwrap() error {
+	return r.err
+}
+
+// parseRetryAfter reads a Retry-After header holding either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func (o *output) Write(ctx context.Context, msg *service.Message) error {
+	payload, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	target, err := o.url.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate url: %w", err)
+	}
+
+	header := http.Header{}
+	for k, v := range o.headers {
+		value, err := v.TryString(msg)
+		if err != nil {
+			return fmt.Errorf("failed to interpolate header %v: %w", k, err)
+		}
+		header.Set(k, value)
+	}
+	if o.idempotencyHeader != "" {
+		var key string
+		if o.idempotencyKey != nil {
+			if key, err = o.idempotencyKey.TryString(msg); err != nil {
+				return fmt.Errorf("failed to interpolate idempotency key: %w", err)
+			}
+		} else {
+			sum := sha256.Sum256(payload)
+			key = hex.EncodeToString(sum[:])
+		}
+		header.Set(o.idempotencyHeader, key)
+	}
+
+	boff := *o.backoff
+	boff.Reset()
+	for {
+		err := o.send(ctx, target, header, payload)
+		var rErr *retryableError
+		if !errors.As(err, &rErr) {
+			return err
+		}
+		wait := boff.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		wait = max(wait, rErr.retryAfter)
+		o.log.Debugf("Retrying delivery to %v in %v: %v", target, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (o *output) send(ctx context.Context, target string, header http.Header, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, o.verb, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	if o.signer != nil {
+		o.signer.sign(req.Header, payload, o.nowFn())
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &retryableError{err: err}
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<20))
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+	err = fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	if res.StatusCode == http.StatusRequestTimeout ||
+		res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode >= 500 {
+		return &retryableError{err: err, retryAfter: parseRetryAfter(res.Header.Get("Retry-After"), o.nowFn())}
+	}
+	return err
+}
+
+func (*output) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, conf string) *output {
+	t.Helper()
+
+	pConf, err := outputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	o.nowFn = func() time.Time { return time.Unix(1700000000, 0) }
+	require.NoError(t, o.Connect(t.Context()))
+	t.Cleanup(func() { _ = o.Close(t.Context()) })
+	return o
+}
+
+func hexMAC(secret, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestSigner(t *testing.T) {
+	payload := []byte(`{"id":1}`)
+	ts := time.Unix(1700000000, 0)
+
+	h := http.Header{}
+	(&signer{secret: []byte("s"), scheme: "github"}).sign(h, payload, ts)
+	assert.Equal(t, "sha256="+hexMAC("s", `{"id":1}`), h.Get("X-Hub-Signature-256"))
+
+	h = http.Header{}
+	(&signer{secret: []byte("s"), scheme: "stripe"}).sign(h, payload, ts)
+	assert.Equal(t, "t=1700000000,v1="+hexMAC("s", `1700000000.{"id":1}`), h.Get("Stripe-Signature"))
+
+	h = http.Header{}
+	(&signer{secret: []byte("s"), scheme: "custom", header: "X-Sig", prefix: "v1=", encoding: "base64"}).sign(h, payload, ts)
+	assert.Equal(t, "v1=eNibnlRa1w5lSpKR7ufOSmEQWUdZRLQC8pWFW6qmiEM=", h.Get("X-Sig"))
+}
+
+func TestOutputRetries(t *testing.T) {
+	var mut sync.Mutex
+	var keys []string
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "sha256="+hexMAC("secret", string(body)), r.Header.Get("X-Hub-Signature-256"))
+
+		mut.Lock()
+		defer mut.Unlock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	o := testOutput(t, `
+url: `+srv.URL+`
+signature:
+  secret: secret
+idempotency_key: ${! @id }
+backoff:
+  initial_interval: 1ms
+  max_interval: 5ms
+`)
+
+	msg := service.NewMessage([]byte(`{"id":1}`))
+	msg.MetaSetMut("id", "evt-1")
+	require.NoError(t, o.Write(t.Context(), msg))
+	assert.Equal(t, []string{"evt-1", "evt-1", "evt-1"}, keys)
+}
+
+func TestOutputPermanentFailure(t *testing.T) {
+	var mut sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mut.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	o := testOutput(t, `url: `+srv.URL)
+
+	err := o.Write(t.Context(), service.NewMessage([]byte("hello")))
+	require.ErrorContains(t, err, "unexpected status code: 400")
+
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, []string{hex.EncodeToString(sum[:])}, keys)
+}
+
+func TestOutputRetriesExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	o := testOutput(t, `
+url: `+srv.URL+`
+backoff:
+  initial_interval: 1ms
+  max_interval: 1ms
+  max_elapsed_time: 20ms
+`)
+	require.ErrorContains(t, o.Write(t.Context(), service.NewMessage([]byte("hello"))), "unexpected status code: 502")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Mon, 01 Jan 2024 00:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// signer adds an HMAC-SHA256 signature of a payload to request headers.
+type signer struct {
+	secret   []byte
+	scheme   string
+	header   string
+	prefix   string
+	encoding string
+}
+
+func (s *signer) mac(parts ...[]byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func (s *signer) encode(b []byte) string {
+	if s.encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// sign sets the signature headers of a request, where the timestamp is only
+// part of the signature for schemes that protect against replays.
+func (s *signer) sign(h http.Header, payload []byte, ts time.Time) {
+	switch s.scheme {
+	case "github":
+		h.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(s.mac(payload)))
+	case "stripe":
+		t := strconv.FormatInt(ts.Unix(), 10)
+		h.Set("Stripe-Signature", "t="+t+",v1="+hex.EncodeToString(s.mac([]byte(t), []byte("."), payload)))
+	default:
+		h.Set(s.header, s.prefix+s.encode(s.mac(payload)))
+	}
+}
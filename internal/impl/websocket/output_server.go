@@ -0,0 +1,385 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	wssoFieldAddress          = "address"
+	wssoFieldPath             = "path"
+	wssoFieldChannel          = "channel"
+	wssoFieldAllowedOrigins   = "allowed_origins"
+	wssoFieldSubscribeTimeout = "subscribe_timeout"
+	wssoFieldWriteTimeout     = "write_timeout"
+	wssoFieldSendBuffer       = "send_buffer"
+)
+
+func serverOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Network").
+		Summary("Runs a WebSocket server and sends messages to the connected clients that are subscribed to the channel of each message.").
+		Description(`
+Each client must send a JSON frame of the form `+"`"+`{"subscribe":["foo","bar.*"]}`+"`"+` after connecting, clients that do not subscribe within `+"`"+wssoFieldSubscribeTimeout+"`"+` are disconnected. Channel names may contain glob patterns, where `+"`*`"+` matches any sequence of characters except `+"`/`"+`. Clients can change their subscriptions at any time by sending further frames containing `+"`subscribe`"+` and `+"`unsubscribe`"+` lists.
+
+The channel of each message is calculated with the `+"`"+wssoFieldChannel+"`"+` mapping and the message is sent as a text frame to every client subscribed to it. Delivery is best effort: messages are acknowledged once they have been queued for all subscribed clients, messages without any subscribers are dropped, and clients that fall behind by more than `+"`"+wssoFieldSendBuffer+"`"+` messages are disconnected rather than applying back pressure to the pipeline.`).
+		Fields(
+			service.NewStringField(wssoFieldAddress).
+				Description("The address to listen on.").
+				Default("0.0.0.0:4196"),
+			service.NewStringField(wssoFieldPath).
+				Description("The path at which clients can open WebSocket connections.").
+				Default("/ws"),
+			service.NewBloblangField(wssoFieldChannel).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that calculates the channel of each message.").
+				Examples(`root = @kafka_topic`, `root = "orders." + this.region`),
+			service.NewStringListField(wssoFieldAllowedOrigins).
+				Description("A list of origins that are allowed to connect, `*` allows any origin. When empty only requests where the origin matches the host are accepted.").
+				Default([]any{}).
+				Advanced(),
+			service.NewDurationField(wssoFieldSubscribeTimeout).
+				Description("The maximum period to wait for the subscription frame of a newly connected client.").
+				Default("10s").
+				Advanced(),
+			service.NewDurationField(wssoFieldWriteTimeout).
+				Description("The maximum period to wait for a frame to be written to a client before it is disconnected.").
+				Default("5s").
+				Advanced(),
+			service.NewIntField(wssoFieldSendBuffer).
+				Description("The maximum number of messages queued for each client.").
+				Default(256).
+				Advanced(),
+		).
+		Example(
+			"Topic fan-out",
+			"Forward records consumed from Kafka to browsers subscribed to their topic.",
+			`
+input:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ orders, prices ]
+    consumer_group: websocket_fanout
+
+output:
+  websocket_server:
+    address: 0.0.0.0:8080
+    path: /stream
+    channel: root = @kafka_topic
+`,
+		)
+}
+
+func init() {
+	service.MustRegisterOutput("websocket_server", serverOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Output, int, error) {
+			o, err := newServerOutputFromConfig(conf, mgr)
+			return o, 1, err
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type subscriptionFrame struct {
+	Subscribe   []string `json:"subscribe"`
+	Unsubscribe []string `json:"unsubscribe"`
+}
+
+type serverClient struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	mut      sync.RWMutex
+	channels []string
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (c *serverClient) subscribed(channel string) bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	for _, pattern := range c.channels {
+		if matched, _ := path.Match(pattern, channel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *serverClient) update(frame subscriptionFrame) error {
+	for _, pattern := range append(slices.Clone(frame.Subscribe), frame.Unsubscribe...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid channel pattern %q: %w", pattern, err)
+		}
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.channels = slices.DeleteFunc(c.channels, func(ch string) bool {
+		return slices.Contains(frame.Unsubscribe, ch)
+	})
+	for _, ch := range frame.Subscribe {
+		if !slices.Contains(c.channels, ch) {
+			c.channels = append(c.channels, ch)
+		}
+	}
+	return nil
+}
+
+func (c *serverClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		_ = c.conn.Close()
+	})
+}
+
+type serverOutput struct {
+	log *service.Logger
+
+	address          string
+	path             string
+	channel          *bloblang.Executor
+	subscribeTimeout time.Duration
+	writeTimeout     time.Duration
+	sendBuffer       int
+	upgrader         websocket.Upgrader
+
+	clientsMut sync.RWMutex
+	clients    map[*serverClient]struct{}
+
+	server   *http.Server
+	listener net.Listener
+}
+
+func newServerOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*serverOutput, error) {
+	s := &serverOutput{
+		log:     mgr.Logger(),
+		clients: map[*serverClient]struct{}{},
+	}
+
+	var err error
+	if s.address, err = conf.FieldString(wssoFieldAddress); err != nil {
+		return nil, err
+	}
+	if s.path, err = conf.FieldString(wssoFieldPath); err != nil {
+		return nil, err
+	}
+	if s.channel, err = conf.FieldBloblang(wssoFieldChannel); err != nil {
+		return nil, err
+	}
+	if s.subscribeTimeout, err = conf.FieldDuration(wssoFieldSubscribeTimeout); err != nil {
+		return nil, err
+	}
+	if s.writeTimeout, err = conf.FieldDuration(wssoFieldWriteTimeout); err != nil {
+		return nil, err
+	}
+	if s.sendBuffer, err = conf.FieldInt(wssoFieldSendBuffer); err != nil {
+		return nil, err
+	}
+	if s.sendBuffer < 1 {
+		return nil, fmt.Errorf("%v must be greater than zero", wssoFieldSendBuffer)
+	}
+
+	origins, err := conf.FieldStringList(wssoFieldAllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	if len(origins) > 0 {
+		s.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || slices.Contains(origins, "*") || slices.Contains(origins, origin)
+		}
+	}
+	return s, nil
+}
+
+func (s *serverOutput) Connect(context.Context) error {
+	if s.server != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.path, s.handleConn)
+
+	s.listener = listener
+	s.server = &http.Server{Handler: mux}
+	go func() {
+		s.log.Infof("Serving WebSocket connections at: ws://%v%v", listener.Addr(), s.path)
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Errorf("WebSocket server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *serverOutput) handleConn(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debugf("Failed to upgrade WebSocket connection: %v", err)
+		return
+	}
+
+	c := &serverClient{
+		conn:   conn,
+		send:   make(chan []byte, s.sendBuffer),
+		closed: make(chan struct{}),
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(s.subscribeTimeout))
+	var frame subscriptionFrame
+	if err := conn.ReadJSON(&frame); err != nil {
+		s.rejectClient(c, fmt.Errorf("failed to read subscription frame: %w", err))
+		return
+	}
+	if err := c.update(frame); err != nil {
+		s.rejectClient(c, err)
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	s.clientsMut.Lock()
+	s.clients[c] = struct{}{}
+	s.clientsMut.Unlock()
+
+	go s.writeLoop(c)
+	s.readLoop(c)
+}
+
+func (s *serverOutput) rejectClient(c *serverClient, err error) {
+	s.log.Debugf("Rejecting WebSocket client %v: %v", c.conn.RemoteAddr(), err)
+	_ = c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()),
+		time.Now().Add(s.writeTimeout))
+	c.close()
+}
+
+func (s *serverOutput) removeClient(c *serverClient) {
+	s.clientsMut.Lock()
+	delete(s.clients, c)
+	s.clientsMut.Unlock()
+	c.close()
+}
+
+// readLoop processes subscription changes until the client disconnects.
+func (s *serverOutput) readLoop(c *serverClient) {
+	defer s.removeClient(c)
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var frame subscriptionFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			s.rejectClient(c, fmt.Errorf("failed to parse subscription frame: %w", err))
+			return
+		}
+		if err := c.update(frame); err != nil {
+			s.rejectClient(c, err)
+			return
+		}
+	}
+}
+
+func (s *serverOutput) writeLoop(c *serverClient) {
+	defer s.removeClient(c)
+	for {
+		select {
+		case data := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				s.log.Debugf("Failed to write to WebSocket client %v: %v", c.conn.RemoteAddr(), err)
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (s *serverOutput) Write(_ context.Context, msg *service.Message) error {
+	res, err := msg.BloblangQuery(s.channel)
+	if err != nil {
+		return fmt.Errorf("failed to execute channel mapping: %w", err)
+	}
+	if res == nil {
+		return errors.New("channel mapping resulted in a deleted message")
+	}
+	channelBytes, err := res.AsBytes()
+	if err != nil {
+		return err
+	}
+	channel := string(channelBytes)
+
+	data, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	var slow []*serverClient
+	s.clientsMut.RLock()
+	for c := range s.clients {
+		if !c.subscribed(channel) {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	s.clientsMut.RUnlock()
+
+	for _, c := range slow {
+		s.log.Warnf("Disconnecting WebSocket client %v as its send buffer is full", c.conn.RemoteAddr())
+		s.removeClient(c)
+	}
+	return nil
+}
+
+func (s *serverOutput) Close(ctx context.Context) error {
+	s.clientsMut.Lock()
+	for c := range s.clients {
+		c.close()
+		delete(s.clients, c)
+	}
+	s.clientsMut.Unlock()
+
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
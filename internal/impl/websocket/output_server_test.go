@@ -0,0 +1,104 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestServerOutputSubscriptions(t *testing.T) {
+	conf, err := serverOutputSpec().ParseYAML(`
+address: 127.0.0.1:0
+channel: root = this.topic
+subscribe_timeout: 100ms
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newServerOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, out.Connect(t.Context()))
+	t.Cleanup(func() {
+		_ = out.Close(t.Context())
+	})
+
+	wsURL := "ws://" + out.listener.Addr().String() + "/ws"
+	dial := func(subscription string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		if subscription != "" {
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(subscription)))
+		}
+		return conn
+	}
+
+	orders := dial(`{"subscribe":["orders"]}`)
+	all := dial(`{"subscribe":["*"]}`)
+	silent := dial("")
+
+	// Wait for the subscribed clients to be registered.
+	require.Eventually(t, func() bool {
+		out.clientsMut.RLock()
+		defer out.clientsMut.RUnlock()
+		return len(out.clients) == 2
+	}, time.Second*5, time.Millisecond*10)
+
+	for _, payload := range []string{
+		`{"topic":"orders","id":1}`,
+		`{"topic":"prices","id":2}`,
+	} {
+		require.NoError(t, out.Write(t.Context(), service.NewMessage([]byte(payload))))
+	}
+
+	read := func(conn *websocket.Conn) string {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, `{"topic":"orders","id":1}`, read(orders))
+	assert.Equal(t, `{"topic":"orders","id":1}`, read(all))
+	assert.Equal(t, `{"topic":"prices","id":2}`, read(all))
+
+	// Changing subscriptions applies to later messages.
+	require.NoError(t, orders.WriteMessage(websocket.TextMessage, []byte(`{"subscribe":["prices"],"unsubscribe":["orders"]}`)))
+	require.Eventually(t, func() bool {
+		out.clientsMut.RLock()
+		defer out.clientsMut.RUnlock()
+		for c := range out.clients {
+			if c.subscribed("prices") && !c.subscribed("orders") {
+				return true
+			}
+		}
+		return false
+	}, time.Second*5, time.Millisecond*10)
+
+	require.NoError(t, out.Write(t.Context(), service.NewMessage([]byte(`{"topic":"orders","id":3}`))))
+	require.NoError(t, out.Write(t.Context(), service.NewMessage([]byte(`{"topic":"prices","id":4}`))))
+	assert.Equal(t, `{"topic":"prices","id":4}`, read(orders))
+
+	// Clients that never subscribe are disconnected.
+	require.NoError(t, silent.SetReadDeadline(time.Now().Add(time.Second*5)))
+	_, _, err = silent.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weighted
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/wrapper"
+)
+
+const (
+	woFieldOutputs   = "outputs"
+	woFieldOutput    = "output"
+	woFieldWeight    = "weight"
+	woFieldStickyKey = "sticky_key"
+)
+
+func weightedOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.62.0").
+		Categories("Utility").
+		Summary("Routes each message to one of several child outputs, chosen at random in proportion to their weights or consistently by a key, which allows traffic to be shifted gradually between sinks.").
+		Description(`
+Each message is routed to a single output, where the chance of an output being chosen is its `+"`"+woFieldWeight+"`"+` divided by the sum of the weights of all outputs. Outputs with a weight of zero receive no messages, which makes it possible to prepare an output for a rollout before shifting any traffic to it.
+
+When a `+"`"+woFieldStickyKey+"`"+` is set the output of each message is chosen with weighted rendezvous hashing of its key instead, so that messages with the same key are always routed to the same output. Changing the weight of an output only moves the keys that are needed in order to honour the new weights, which means that increasing the weight of a canary output moves keys from the other outputs to the canary, and never between the other outputs.
+
+== Adjusting weights
+
+When running in xref:guides:streams_mode/about.adoc[streams mode] the weights can be adjusted at runtime by updating the stream with a `+"`PATCH /streams/{id}`"+` request of the streams API. Lists are replaced rather than merged by patches, and so the patch must contain the entire list of `+"`"+woFieldOutputs+"`"+` with their new weights. Since messages with a sticky key are routed by their key and the weights alone, keys remain with the same output across updates unless they're moved by a change of weights.
+
+When a batch is written, its messages are grouped by the output they're routed to and the groups are written concurrently. Messages of groups that fail are rejected, and may therefore be routed to a different output when retried upstream unless a `+"`"+woFieldStickyKey+"`"+` is set.`).
+		Fields(
+			service.NewObjectListField(woFieldOutputs,
+				service.NewOutputField(woFieldOutput).
+					Description("The child output."),
+				service.NewFloatField(woFieldWeight).
+					Description("The weight of the output, which must not be negative.").
+					Default(1.0),
+			).Description("A list of child outputs to route messages to."),
+			service.NewBloblangField(woFieldStickyKey).
+				Description("An optional mapping that returns the key of a message, where messages with the same key are routed to the same output.").
+				Examples(`root = meta("kafka_key")`, `root = this.user_id`).
+				Optional(),
+		).
+		Example("Canary rollout", "Route a tenth of the messages to a new cluster, where the events of each user are always routed to the same cluster.", `
+output:
+  weighted:
+    sticky_key: 'root = this.user_id'
+    outputs:
+      - weight: 9
+        output:
+          kafka_franz:
+            seed_brokers: [ old-cluster:9092 ]
+            topic: events
+      - weight: 1
+        output:
+          kafka_franz:
+            seed_brokers: [ new-cluster:9092 ]
+            topic: events
+`)
+}
+
+func init() {
+	service.MustRegisterBatchOutput(
+		"weighted", weightedOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			out, err = newWeightedOutputFromConfig(conf, mgr)
+			maxInFlight = 64
+			return
+		})
+}
+
+//------------------------------------------------------------------------------
+
+type weightedOutput struct {
+	log *service.Logger
+
+	outputs     []wrapper.BatchWriter
+	weights     []float64
+	totalWeight float64
+	stickyKey   *bloblang.Executor
+	randFloat   func() float64
+}
+
+func newWeightedOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*weightedOutput, error) {
+	o := &weightedOutput{
+		log:       mgr.Logger(),
+		randFloat: rand.Float64,
+	}
+
+	if conf.Contains(woFieldStickyKey) {
+		var err error
+		if o.stickyKey, err = conf.FieldBloblang(woFieldStickyKey); err != nil {
+			return nil, err
+		}
+	}
+
+	outConfs, err := conf.FieldObjectList(woFieldOutputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(outConfs) == 0 {
+		return nil, errors.New("at least one output must be specified")
+	}
+	for i, oc := range outConfs {
+		weight, err := oc.FieldFloat(woFieldWeight)
+		if err != nil {
+			return nil, err
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("output %v: weight must be a finite number that isn't negative", i)
+		}
+		out, err := oc.FieldOutput(woFieldOutput)
+		if err != nil {
+			return nil, fmt.Errorf("output %v: %w", i, err)
+		}
+		o.outputs = append(o.outputs, out)
+		o.weights = append(o.weights, weight)
+		o.totalWeight += weight
+	}
+	if o.totalWeight <= 0 {
+		return nil, errors.New("the weight of at least one output must be greater than zero")
+	}
+	return o, nil
+}
+
+func (o *weightedOutput) Connect(context.Context) error {
+	return nil
+}
+
+// pickRandom returns the index of an output chosen at random in proportion to
+// the weights.
+func (o *weightedOutput) pickRandom() int {
+	r := o.randFloat() * o.totalWeight
+	last := 0
+	for i, w := range o.weights {
+		if w <= 0 {
+			continue
+		}
+		if r < w {
+			return i
+		}
+		r -= w
+		last = i
+	}
+	// Rounding errors may leave a remainder, in which case the last output
+	// with a weight wins.
+	return last
+}
+
+// pickSticky returns the index of the output with the highest weighted
+// rendezvous score for a key.
+func (o *weightedOutput) pickSticky(key []byte) int {
+	best, bestScore := -1, math.Inf(-1)
+	buf := make([]byte, 0, len(key)+1)
+	for i, w := range o.weights {
+		if w <= 0 {
+			continue
+		}
+		buf = append(append(buf[:0], key...), byte(i))
+		h := xxhash.Sum64(buf)
+
+		// Map the hash onto (0, 1) and score it such that the chance of an
+		// output having the highest score is proportional to its weight.
+		u := (float64(h>>11) + 0.5) / (1 << 53)
+		if score := -w / math.Log(u); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+func (o *weightedOutput) route(batch service.MessageBatch) []int {
+	routes := make([]int, len(batch))
+	if o.stickyKey == nil {
+		for i := range batch {
+			routes[i] = o.pickRandom()
+		}
+		return routes
+	}
+
+	exec := batch.BloblangExecutor(o.stickyKey)
+	for i := range batch {
+		res, err := exec.Query(i)
+		if err == nil && res == nil {
+			err = errors.New("mapping returned no value")
+		}
+		var key []byte
+		if err == nil {
+			key, err = res.AsBytes()
+		}
+		if err != nil {
+			o.log.Debugf("Failed to compute sticky key of message %v, routing it at random: %v", i, err)
+			routes[i] = o.pickRandom()
+			continue
+		}
+		routes[i] = o.pickSticky(key)
+	}
+	return routes
+}
+
+func (o *weightedOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	routes := o.route(batch)
+
+	groups := make([][]int, len(o.outputs))
+	for i, r := range routes {
+		groups[r] = append(groups[r], i)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(o.outputs))
+	for oi, indexes := range groups {
+		if len(indexes) == 0 {
+			continue
+		}
+		group := make(service.MessageBatch, len(indexes))
+		for j, i := range indexes {
+			group[j] = batch[i]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[oi] = o.outputs[oi].WriteBatch(ctx, group)
+		}()
+	}
+	wg.Wait()
+
+	var bErr *service.BatchError
+	for oi, err := range errs {
+		if err == nil {
+			continue
+		}
+		if bErr == nil {
+			bErr = service.NewBatchError(batch, fmt.Errorf("output %v: %w", oi, err))
+		}
+		for _, i := range groups[oi] {
+			bErr.Failed(i, err)
+		}
+	}
+	if bErr != nil {
+		return bErr
+	}
+	return nil
+}
+
+func (o *weightedOutput) Close(ctx context.Context) error {
+	var errs []error
+	for _, out := range o.outputs {
+		errs = append(errs, out.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
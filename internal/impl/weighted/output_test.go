@@ -0,0 +1,209 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weighted
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeWriter struct {
+	mut      sync.Mutex
+	err      error
+	contents []string
+}
+
+func (f *fakeWriter) WriteBatch(_ context.Context, b service.MessageBatch) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	for _, msg := range b {
+		content, _ := msg.AsBytes()
+		f.contents = append(f.contents, string(content))
+	}
+	return nil
+}
+
+func (*fakeWriter) Close(context.Context) error {
+	return nil
+}
+
+// newTestOutput creates an output with the weights of a config, where the
+// child outputs are replaced with fakes.
+func newTestOutput(t *testing.T, conf string) (*weightedOutput, []*fakeWriter) {
+	t.Helper()
+
+	pConf, err := weightedOutputConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newWeightedOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	fakes := make([]*fakeWriter, len(o.outputs))
+	for i := range o.outputs {
+		fakes[i] = &fakeWriter{}
+		o.outputs[i] = fakes[i]
+	}
+	return o, fakes
+}
+
+func testBatch(n int) service.MessageBatch {
+	batch := make(service.MessageBatch, n)
+	for i := range batch {
+		batch[i] = service.NewMessage(fmt.Appendf(nil, `{"id":"%v"}`, i))
+	}
+	return batch
+}
+
+func TestWeightedOutputRandom(t *testing.T) {
+	o, fakes := newTestOutput(t, `
+outputs:
+  - output: { drop: {} }
+    weight: 3
+  - output: { drop: {} }
+    weight: 0
+  - output: { drop: {} }
+    weight: 1
+`)
+
+	values := []float64{0, 0.5, 0.74, 0.75, 0.99}
+	o.randFloat = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	require.NoError(t, o.WriteBatch(t.Context(), testBatch(5)))
+	assert.Equal(t, []string{`{"id":"0"}`, `{"id":"1"}`, `{"id":"2"}`}, fakes[0].contents)
+	assert.Empty(t, fakes[1].contents)
+	assert.Equal(t, []string{`{"id":"3"}`, `{"id":"4"}`}, fakes[2].contents)
+}
+
+func TestWeightedOutputSticky(t *testing.T) {
+	conf := func(w0, w1 float64) string {
+		return fmt.Sprintf(`
+sticky_key: root = this.id
+outputs:
+  - output: { drop: {} }
+    weight: %v
+  - output: { drop: {} }
+    weight: %v
+`, w0, w1)
+	}
+
+	routesFor := func(w0, w1 float64) map[string]int {
+		o, fakes := newTestOutput(t, conf(w0, w1))
+		require.NoError(t, o.WriteBatch(t.Context(), testBatch(2000)))
+		require.NoError(t, o.WriteBatch(t.Context(), testBatch(2000)))
+
+		routes := map[string]int{}
+		for i, f := range fakes {
+			for _, c := range f.contents {
+				if prev, exists := routes[c]; exists {
+					require.Equal(t, prev, i, "key %v routed to several outputs", c)
+				}
+				routes[c] = i
+			}
+		}
+		return routes
+	}
+
+	count := func(routes map[string]int, output int) (n int) {
+		for _, r := range routes {
+			if r == output {
+				n++
+			}
+		}
+		return
+	}
+
+	before := routesFor(9, 1)
+	assert.InDelta(t, 200, count(before, 1), 60)
+
+	// Raising the weight of the canary only moves keys onto the canary.
+	after := routesFor(7, 3)
+	assert.InDelta(t, 600, count(after, 1), 90)
+	for k, r := range before {
+		if r == 1 {
+			assert.Equal(t, 1, after[k], "key %v moved off the canary", k)
+		}
+	}
+}
+
+func TestWeightedOutputErrors(t *testing.T) {
+	o, fakes := newTestOutput(t, `
+sticky_key: root = this.id
+outputs:
+  - output: { drop: {} }
+  - output: { drop: {} }
+`)
+	fakes[1].err = errors.New("nope")
+
+	batch := testBatch(20)
+	indexer := batch.Index()
+	err := o.WriteBatch(t.Context(), batch)
+
+	var bErr *service.BatchError
+	require.ErrorAs(t, err, &bErr)
+
+	failed := map[int]bool{}
+	bErr.WalkMessagesIndexedBy(indexer, func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failed[i] = true
+		}
+		return true
+	})
+	assert.NotEmpty(t, failed)
+	assert.Len(t, failed, 20-len(fakes[0].contents))
+	for i := range batch {
+		content, _ := batch[i].AsBytes()
+		assert.Equal(t, !failed[i], slices.Contains(fakes[0].contents, string(content)), i)
+	}
+}
+
+func TestWeightedOutputConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`outputs: []`,
+		`
+outputs:
+  - output: { drop: {} }
+    weight: 0
+`,
+		`
+outputs:
+  - output: { drop: {} }
+    weight: -1
+`,
+	} {
+		pConf, err := weightedOutputConfig().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = newWeightedOutputFromConfig(pConf, service.MockResources())
+		require.Error(t, err, conf)
+	}
+}
@@ -22,8 +22,11 @@ import (
 )
 
 const (
-	pFieldOperator = "operator"
-	pFieldCast     = "cast"
+	pFieldOperator   = "operator"
+	pFieldCast       = "cast"
+	pFieldQueries    = "queries"
+	pFieldNamespaces = "namespaces"
+	pFieldXSDPath    = "xsd_path"
 )
 
 func xmlProcSpec() *service.ConfigSpec {
@@ -91,15 +94,60 @@ With cast set to true, the resulting JSON structure would look like this:
     ]
   }
 }
-`+"```").
+`+"```"+`
+
+=== `+"`xpath`"+`
+
+Evaluates each of the XPath 1.0 expressions of the field `+"`"+pFieldQueries+"`"+` against an XML document and replaces the message with an object containing the result of each expression under its key. Expressions that select nodes result in the string value of the selected node, an array of values when multiple nodes are selected, or `+"`null`"+` when no nodes are selected. Expressions that evaluate to a number, string or boolean, such as `+"`count(//item)`"+`, result in that value.
+
+Parsing is namespace aware, and elements and attributes of a namespace are selected with a prefix mapped to the namespace URI in the field `+"`"+pFieldNamespaces+"`"+`. This includes elements of a default namespace, which are selected with a prefix even though the document does not use one. Names without a prefix select elements and attributes of any namespace.
+
+With cast set to true, the values of selected nodes are converted to numbers and booleans where possible.
+
+=== `+"`validate`"+`
+
+Validates XML documents against the XML Schema (XSD) of the field `+"`"+pFieldXSDPath+"`"+`, messages that are invalid are flagged as having failed with a description of the validation errors and are otherwise left unchanged. Validation is backed by libxml2 and is only available in builds with CGO enabled and the build tag `+"`x_benthos_extra`"+`.`).
 		Fields(
-			service.NewStringEnumField(pFieldOperator, "to_json").
+			service.NewStringEnumField(pFieldOperator, "to_json", "xpath", "validate").
 				Description("An XML <<operators, operation>> to apply to messages.").
 				Default(""),
 			service.NewBoolField(pFieldCast).
 				Description("Whether to try to cast values that are numbers and booleans to the right type. Default: all values are strings.").
 				Default(false),
-		)
+			service.NewStringMapField(pFieldQueries).
+				Description("A map of keys to XPath 1.0 expressions evaluated by the `xpath` operator.").
+				Example(map[string]any{
+					"id":     "/doc:Document/doc:CstmrCdtTrfInitn/doc:GrpHdr/doc:MsgId",
+					"amount": "sum(//doc:InstdAmt)",
+				}).
+				Version("4.62.0").
+				Optional(),
+			service.NewStringMapField(pFieldNamespaces).
+				Description("A map of prefixes to namespace URIs used by the expressions of the `xpath` operator.").
+				Example(map[string]any{
+					"doc": "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09",
+				}).
+				Version("4.62.0").
+				Optional(),
+			service.NewStringField(pFieldXSDPath).
+				Description("The path of an XML Schema (XSD) file used by the `validate` operator. References to other schemas are resolved relative to this file.").
+				Example("./schemas/pain.001.001.09.xsd").
+				Version("4.62.0").
+				Optional(),
+		).
+		Example("Extract fields from SOAP responses", "Extracts fields from the body of SOAP responses with namespace prefixed XPath expressions.", `
+pipeline:
+  processors:
+    - xml:
+        operator: xpath
+        cast: true
+        namespaces:
+          soap: http://schemas.xmlsoap.org/soap/envelope/
+          m: http://www.example.org/stock
+        queries:
+          symbol: /soap:Envelope/soap:Body/m:GetStockPriceResponse/m:Symbol
+          price: /soap:Envelope/soap:Body/m:GetStockPriceResponse/m:Price
+`)
 }
 
 func init() {
@@ -111,8 +159,11 @@ func init() {
 }
 
 type xmlProc struct {
-	log  *service.Logger
-	cast bool
+	log      *service.Logger
+	operator string
+	cast     bool
+	queries  *xpathQueries
+	xsd      *xsdValidator
 }
 
 func xmlProcFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (*xmlProc, error) {
@@ -120,9 +171,6 @@ func xmlProcFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (*xm
 	if err != nil {
 		return nil, err
 	}
-	if operator != "to_json" {
-		return nil, fmt.Errorf("operator not recognised: %v", operator)
-	}
 
 	cast, err := pConf.FieldBool(pFieldCast)
 	if err != nil {
@@ -130,8 +178,47 @@ func xmlProcFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (*xm
 	}
 
 	j := &xmlProc{
-		log:  mgr.Logger(),
-		cast: cast,
+		log:      mgr.Logger(),
+		operator: operator,
+		cast:     cast,
+	}
+
+	switch operator {
+	case "to_json":
+	case "xpath":
+		queries, err := pConf.FieldStringMap(pFieldQueries)
+		if err != nil {
+			return nil, err
+		}
+		if len(queries) == 0 {
+			return nil, fmt.Errorf("field %v is required by the xpath operator", pFieldQueries)
+		}
+		var namespaces map[string]string
+		if pConf.Contains(pFieldNamespaces) {
+			if namespaces, err = pConf.FieldStringMap(pFieldNamespaces); err != nil {
+				return nil, err
+			}
+		}
+		if j.queries, err = newXPathQueries(queries, namespaces, cast); err != nil {
+			return nil, err
+		}
+	case "validate":
+		if !pConf.Contains(pFieldXSDPath) {
+			return nil, fmt.Errorf("field %v is required by the validate operator", pFieldXSDPath)
+		}
+		xsdPath, err := pConf.FieldString(pFieldXSDPath)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := service.ReadFile(mgr.FS(), xsdPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XSD schema: %w", err)
+		}
+		if j.xsd, err = newXSDValidator(schema, xsdPath); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("operator not recognised: %v", operator)
 	}
 	return j, nil
 }
@@ -142,15 +229,33 @@ func (p *xmlProc) Process(_ context.Context, msg *service.Message) (service.Mess
 		return nil, err
 	}
 
-	root, err := ToMap(mBytes, p.cast)
-	if err != nil {
-		p.log.Debugf("Failed to parse part as XML: %v", err)
-		return nil, err
+	switch p.operator {
+	case "xpath":
+		res, err := p.queries.evaluate(mBytes)
+		if err != nil {
+			p.log.Debugf("Failed to parse part as XML: %v", err)
+			return nil, err
+		}
+		msg.SetStructuredMut(res)
+	case "validate":
+		if err := p.xsd.validate(mBytes); err != nil {
+			p.log.Debugf("Failed to validate part: %v", err)
+			return nil, err
+		}
+	default:
+		root, err := ToMap(mBytes, p.cast)
+		if err != nil {
+			p.log.Debugf("Failed to parse part as XML: %v", err)
+			return nil, err
+		}
+		msg.SetStructuredMut(root)
 	}
-	msg.SetStructuredMut(root)
 	return service.MessageBatch{msg}, nil
 }
 
-func (*xmlProc) Close(context.Context) error {
+func (p *xmlProc) Close(context.Context) error {
+	if p.xsd != nil {
+		p.xsd.close()
+	}
 	return nil
 }
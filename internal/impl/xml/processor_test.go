@@ -146,3 +146,99 @@ cast: true
 
 	assert.Equal(t, `{"root":{"bool":true,"number":{"#text":123,"-id":99},"title":"This is a title"}}`, string(mBytes))
 }
+
+func TestXMLXPath(t *testing.T) {
+	pConf, err := xmlProcSpec().ParseYAML(`
+operator: xpath
+namespaces:
+  soap: http://schemas.xmlsoap.org/soap/envelope/
+  m: http://www.example.org/stock
+queries:
+  symbol: /soap:Envelope/soap:Body/m:GetStockPriceResponse/m:Symbol
+  price: /soap:Envelope/soap:Body/m:GetStockPriceResponse/m:Price
+  currency: /soap:Envelope/soap:Body/m:GetStockPriceResponse/m:Price/@currency
+  tags: //m:Tag
+  tag_count: count(//m:Tag)
+  has_error: boolean(//soap:Fault)
+  missing: //m:Missing
+  unprefixed: //Price
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := xmlProcFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	input := `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetStockPriceResponse xmlns="http://www.example.org/stock">
+      <Symbol>RPD</Symbol>
+      <Price currency="USD">34.5</Price>
+      <Tag>a</Tag>
+      <Tag>b</Tag>
+    </GetStockPriceResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+	msgsOut, err := proc.Process(t.Context(), service.NewMessage([]byte(input)))
+	require.NoError(t, err)
+	require.Len(t, msgsOut, 1)
+
+	mBytes, err := msgsOut[0].AsBytes()
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+  "symbol": "RPD",
+  "price": "34.5",
+  "currency": "USD",
+  "tags": ["a","b"],
+  "tag_count": 2,
+  "has_error": false,
+  "missing": null,
+  "unprefixed": "34.5"
+}`, string(mBytes))
+}
+
+func TestXMLXPathCast(t *testing.T) {
+	pConf, err := xmlProcSpec().ParseYAML(`
+operator: xpath
+cast: true
+queries:
+  number: /root/number
+  bool: /root/bool
+  title: /root/title
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := xmlProcFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	msgsOut, err := proc.Process(t.Context(), service.NewMessage([]byte(`<root><title>This is a title</title><number id="99">123</number><bool>True</bool></root>`)))
+	require.NoError(t, err)
+	require.Len(t, msgsOut, 1)
+
+	mBytes, err := msgsOut[0].AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"number":123,"bool":true,"title":"This is a title"}`, string(mBytes))
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`<root><unclosed></root>`)))
+	require.Error(t, err)
+}
+
+func TestXMLConfigErrors(t *testing.T) {
+	for _, conf := range []string{
+		`operator: xpath`,
+		`
+operator: xpath
+queries:
+  foo: '/root/['
+`,
+		`operator: validate`,
+	} {
+		pConf, err := xmlProcSpec().ParseYAML(conf, nil)
+		require.NoError(t, err)
+
+		_, err = xmlProcFromParsed(pConf, service.MockResources())
+		require.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// xpathQueries evaluates a set of named XPath 1.0 expressions against XML
+// documents.
+type xpathQueries struct {
+	exprs map[string]*xpath.Expr
+	cast  bool
+}
+
+func newXPathQueries(queries, namespaces map[string]string, cast bool) (*xpathQueries, error) {
+	x := &xpathQueries{
+		exprs: make(map[string]*xpath.Expr, len(queries)),
+		cast:  cast,
+	}
+	for k, q := range queries {
+		expr, err := xpath.CompileWithNS(q, namespaces)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile XPath expression of query %v: %w", k, err)
+		}
+		x.exprs[k] = expr
+	}
+	return x, nil
+}
+
+// evaluate parses an XML document and returns an object containing the result
+// of each query.
+func (x *xpathQueries) evaluate(doc []byte) (map[string]any, error) {
+	root, err := xmlquery.Parse(bytes.NewReader(doc))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]any, len(x.exprs))
+	for k, expr := range x.exprs {
+		switch v := expr.Evaluate(xmlquery.CreateXPathNavigator(root)).(type) {
+		case *xpath.NodeIterator:
+			var values []any
+			for v.MoveNext() {
+				values = append(values, x.nodeValue(v.Current().Value()))
+			}
+			switch len(values) {
+			case 0:
+				res[k] = nil
+			case 1:
+				res[k] = values[0]
+			default:
+				res[k] = values
+			}
+		default:
+			res[k] = v
+		}
+	}
+	return res, nil
+}
+
+func (x *xpathQueries) nodeValue(s string) any {
+	if !x.cast {
+		return s
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	return s
+}
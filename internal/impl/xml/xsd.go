@@ -0,0 +1,65 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build x_benthos_extra
+
+package xml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lestrrat-go/libxml2"
+	"github.com/lestrrat-go/libxml2/xsd"
+)
+
+type xsdValidator struct {
+	schema *xsd.Schema
+}
+
+// newXSDValidator parses an XML Schema, where relative references to other
+// schemas are resolved from path.
+func newXSDValidator(schema []byte, path string) (*xsdValidator, error) {
+	s, err := xsd.Parse(schema, xsd.WithPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XSD schema: %w", err)
+	}
+	return &xsdValidator{schema: s}, nil
+}
+
+func (x *xsdValidator) validate(doc []byte) error {
+	d, err := libxml2.Parse(doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse XML document: %w", err)
+	}
+	defer d.Free()
+
+	if err := x.schema.Validate(d); err != nil {
+		var sErr xsd.SchemaValidationError
+		if errors.As(err, &sErr) {
+			var descs []string
+			for _, e := range sErr.Errors() {
+				descs = append(descs, strings.TrimSpace(e.Error()))
+			}
+			return errors.New(strings.Join(descs, "; "))
+		}
+		return err
+	}
+	return nil
+}
+
+func (x *xsdValidator) close() {
+	x.schema.Free()
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !x_benthos_extra
+
+package xml
+
+import (
+	"errors"
+)
+
+// XSD validation is backed by libxml2 and therefore requires CGO.
+type xsdValidator struct{}
+
+func newXSDValidator([]byte, string) (*xsdValidator, error) {
+	return nil, errors.New("XSD validation requires a build with the x_benthos_extra tag")
+}
+
+func (*xsdValidator) validate([]byte) error {
+	return nil
+}
+
+func (*xsdValidator) close() {}
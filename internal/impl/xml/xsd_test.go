@@ -0,0 +1,76 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build x_benthos_extra
+
+package xml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestXMLValidate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.xsd"), []byte(`<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:example:order" xmlns="urn:example:order" elementFormDefault="qualified">
+  <xs:simpleType name="Quantity">
+    <xs:restriction base="xs:positiveInteger"/>
+  </xs:simpleType>
+</xs:schema>`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.xsd"), []byte(`<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:example:order" xmlns="urn:example:order" elementFormDefault="qualified">
+  <xs:include schemaLocation="types.xsd"/>
+  <xs:element name="Order">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="Item" type="xs:string"/>
+        <xs:element name="Quantity" type="Quantity"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`), 0o644))
+
+	pConf, err := xmlProcSpec().ParseYAML(`
+operator: validate
+xsd_path: `+filepath.Join(dir, "order.xsd"), nil)
+	require.NoError(t, err)
+
+	proc, err := xmlProcFromParsed(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, proc.Close(t.Context()))
+	})
+
+	valid := `<Order xmlns="urn:example:order"><Item>widget</Item><Quantity>3</Quantity></Order>`
+	msgsOut, err := proc.Process(t.Context(), service.NewMessage([]byte(valid)))
+	require.NoError(t, err)
+	require.Len(t, msgsOut, 1)
+
+	mBytes, err := msgsOut[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, valid, string(mBytes))
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`<Order xmlns="urn:example:order"><Item>widget</Item><Quantity>0</Quantity></Order>`)))
+	require.ErrorContains(t, err, "Quantity")
+
+	_, err = proc.Process(t.Context(), service.NewMessage([]byte(`<Order xmlns="urn:example:other"/>`)))
+	require.Error(t, err)
+}
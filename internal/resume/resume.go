@@ -0,0 +1,222 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resume provides a way for inputs that consume a finite or ordered
+// collection of work, such as the rows of a table or the objects of a bucket,
+// to persist their progress within a cache resource and resume from it after
+// a restart.
+package resume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Jeffail/checkpoint"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	rFieldCheckpoint = "checkpoint"
+	rFieldCache      = "cache"
+	rFieldKey        = "key"
+)
+
+// CheckpointField returns the common field for configuring a cache resource
+// within which an input stores its progress.
+func CheckpointField() *service.ConfigField {
+	return service.NewObjectField(rFieldCheckpoint,
+		service.NewStringField(rFieldCache).
+			Description("A xref:components:caches/about.adoc[cache resource] to store the progress of the input within."),
+		service.NewStringField(rFieldKey).
+			Description("The key within the cache to store the progress of the input under, which must be unique for each input sharing the cache. When empty a key is derived from the target of the input, such as the bucket and prefix of objects.").
+			Default("").
+			Advanced(),
+	).
+		Description("Store the progress of the input within a cache so that it resumes after the latest acknowledged message when restarted, rather than starting from scratch. Progress only advances once all prior messages have also been acknowledged.").
+		Optional().
+		Advanced()
+}
+
+// StoreFromParsed extracts a Store from the common checkpoint field of a
+// parsed config, returning a nil Store when the field is not set. The
+// defaultKey is used when the key field is empty.
+func StoreFromParsed(pConf *service.ParsedConfig, mgr *service.Resources, defaultKey string) (*Store, error) {
+	if !pConf.Contains(rFieldCheckpoint) {
+		return nil, nil
+	}
+	cConf := pConf.Namespace(rFieldCheckpoint)
+
+	cache, err := cConf.FieldString(rFieldCache)
+	if err != nil {
+		return nil, err
+	}
+	if !mgr.HasCache(cache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", cache)
+	}
+	key, err := cConf.FieldString(rFieldKey)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		key = defaultKey
+	}
+	return NewStore(mgr, cache, key), nil
+}
+
+//------------------------------------------------------------------------------
+
+// Store persists the progress of an input under a key of a cache resource.
+// All methods of a nil Store are no-ops, which allows inputs to use a Store
+// regardless of whether checkpointing is configured.
+type Store struct {
+	mgr   *service.Resources
+	cache string
+	key   string
+}
+
+// NewStore creates a Store of progress under a key of a cache resource.
+func NewStore(mgr *service.Resources, cache, key string) *Store {
+	return &Store{mgr: mgr, cache: cache, key: key}
+}
+
+// Load returns the stored progress, or nil when no progress has been stored.
+func (s *Store) Load(ctx context.Context) ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	var b []byte
+	var getErr error
+	if err := s.mgr.AccessCache(ctx, s.cache, func(c service.Cache) {
+		b, getErr = c.Get(ctx, s.key)
+	}); err != nil {
+		return nil, err
+	}
+	if errors.Is(getErr, service.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if getErr != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", getErr)
+	}
+	return b, nil
+}
+
+// Save stores progress, replacing any progress previously stored.
+func (s *Store) Save(ctx context.Context, b []byte) error {
+	if s == nil {
+		return nil
+	}
+	var setErr error
+	if err := s.mgr.AccessCache(ctx, s.cache, func(c service.Cache) {
+		setErr = c.Set(ctx, s.key, b, nil)
+	}); err != nil {
+		return err
+	}
+	if setErr != nil {
+		return fmt.Errorf("failed to store checkpoint: %w", setErr)
+	}
+	return nil
+}
+
+// Clear removes the stored progress, such that the input starts from scratch
+// the next time it's restarted.
+func (s *Store) Clear(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	var delErr error
+	if err := s.mgr.AccessCache(ctx, s.cache, func(c service.Cache) {
+		delErr = c.Delete(ctx, s.key)
+	}); err != nil {
+		return err
+	}
+	if delErr != nil && !errors.Is(delErr, service.ErrKeyNotFound) {
+		return fmt.Errorf("failed to clear checkpoint: %w", delErr)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Tracker tracks the positions of messages in the order they're consumed and
+// saves the latest position for which all prior messages have been
+// acknowledged, such that progress never passes messages that haven't been
+// delivered.
+type Tracker[T any] struct {
+	store        *Store
+	encode       func(T) ([]byte, error)
+	checkpointer *checkpoint.Capped[T]
+	releaseNacks bool
+}
+
+// TrackerOpt customises a Tracker.
+type TrackerOpt func(*trackerOpts)
+
+type trackerOpts struct {
+	releaseNacks bool
+}
+
+// TrackerOptReleaseNacks sets whether messages that are rejected with an error
+// release their position as if they were acknowledged. This must be enabled
+// for inputs that don't deliver rejected messages again, such as those with
+// auto_replay_nacks disabled, as otherwise progress would never advance past
+// a rejected message and Track would eventually block.
+func TrackerOptReleaseNacks(release bool) TrackerOpt {
+	return func(o *trackerOpts) {
+		o.releaseNacks = release
+	}
+}
+
+// NewTracker creates a Tracker that saves positions to a Store, encoded with
+// the provided function. The capacity limits the number of positions in
+// flight, where Track blocks until earlier positions are acknowledged.
+func NewTracker[T any](store *Store, capacity int64, encode func(T) ([]byte, error), opts ...TrackerOpt) *Tracker[T] {
+	var o trackerOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Tracker[T]{
+		store:        store,
+		encode:       encode,
+		checkpointer: checkpoint.NewCapped[T](capacity),
+		releaseNacks: o.releaseNacks,
+	}
+}
+
+// Track adds the position of a message and returns an acknowledgement
+// function for it. By default messages that are rejected with an error don't
+// release their position, and so progress doesn't advance past them until
+// they're delivered again and acknowledged, as with inputs wrapped with
+// AutoRetryNacks.
+func (t *Tracker[T]) Track(ctx context.Context, position T) (service.AckFunc, error) {
+	release, err := t.checkpointer.Track(ctx, position, 1)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, err error) error {
+		if err != nil && !t.releaseNacks {
+			return nil
+		}
+		highest := release()
+		if highest == nil {
+			return nil
+		}
+		b, err := t.encode(*highest)
+		if err != nil {
+			return err
+		}
+		return t.store.Save(ctx, b)
+	}, nil
+}
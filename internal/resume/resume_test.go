@@ -0,0 +1,141 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resume
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestStoreFromParsed(t *testing.T) {
+	spec := service.NewConfigSpec().Field(CheckpointField())
+	res := service.MockResources(service.MockResourcesOptAddCache("foo"))
+
+	pConf, err := spec.ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+	store, err := StoreFromParsed(pConf, res, "default")
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	pConf, err = spec.ParseYAML(`
+checkpoint:
+  cache: foo
+`, nil)
+	require.NoError(t, err)
+	store, err = StoreFromParsed(pConf, res, "default")
+	require.NoError(t, err)
+	assert.Equal(t, "default", store.key)
+
+	pConf, err = spec.ParseYAML(`
+checkpoint:
+  cache: bar
+  key: custom
+`, nil)
+	require.NoError(t, err)
+	_, err = StoreFromParsed(pConf, res, "default")
+	require.ErrorContains(t, err, "cache resource 'bar' was not found")
+}
+
+func TestStore(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("foo"))
+	store := NewStore(res, "foo", "bar")
+
+	b, err := store.Load(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	require.NoError(t, store.Save(t.Context(), []byte("baz")))
+	b, err = store.Load(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "baz", string(b))
+
+	require.NoError(t, store.Clear(t.Context()))
+	require.NoError(t, store.Clear(t.Context()))
+	b, err = store.Load(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+}
+
+func TestStoreNil(t *testing.T) {
+	var store *Store
+
+	b, err := store.Load(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+	require.NoError(t, store.Save(t.Context(), []byte("foo")))
+	require.NoError(t, store.Clear(t.Context()))
+}
+
+func TestTrackerOutOfOrder(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("foo"))
+	store := NewStore(res, "foo", "bar")
+	tracker := NewTracker(store, 10, func(i int) ([]byte, error) {
+		return []byte(strconv.Itoa(i)), nil
+	})
+
+	var acks []service.AckFunc
+	for i := range 3 {
+		ackFn, err := tracker.Track(t.Context(), i)
+		require.NoError(t, err)
+		acks = append(acks, ackFn)
+	}
+
+	loaded := func() string {
+		t.Helper()
+		b, err := store.Load(t.Context())
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	// Progress doesn't pass positions that haven't been acknowledged.
+	require.NoError(t, acks[1](t.Context(), nil))
+	assert.Empty(t, loaded())
+
+	// Rejected positions aren't released.
+	require.NoError(t, acks[0](t.Context(), errors.New("nope")))
+	assert.Empty(t, loaded())
+
+	require.NoError(t, acks[0](t.Context(), nil))
+	assert.Equal(t, "1", loaded())
+
+	require.NoError(t, acks[2](t.Context(), nil))
+	assert.Equal(t, "2", loaded())
+}
+
+func TestTrackerReleaseNacks(t *testing.T) {
+	res := service.MockResources(service.MockResourcesOptAddCache("foo"))
+	store := NewStore(res, "foo", "bar")
+	tracker := NewTracker(store, 1, func(i int) ([]byte, error) {
+		return []byte(strconv.Itoa(i)), nil
+	}, TrackerOptReleaseNacks(true))
+
+	// With a capacity of one each Track blocks until the prior position is
+	// released, including when it was rejected.
+	for i := range 3 {
+		ackFn, err := tracker.Track(t.Context(), i)
+		require.NoError(t, err)
+		require.NoError(t, ackFn(t.Context(), errors.New("nope")))
+
+		b, err := store.Load(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(i), string(b))
+	}
+}
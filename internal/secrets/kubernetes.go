@@ -0,0 +1,95 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/redpanda-data/common-go/secrets"
+)
+
+// kubernetesSecretsClient reads Kubernetes secrets from a namespace, returning
+// the data of a secret as a JSON object.
+type kubernetesSecretsClient struct {
+	logger  *slog.Logger
+	secrets corev1.SecretInterface
+}
+
+func (k *kubernetesSecretsClient) GetSecretValue(ctx context.Context, name string) (string, bool) {
+	secret, err := k.secrets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			k.logger.With("error", err, "key", name).Error("Failed to look up secret")
+		}
+		return "", false
+	}
+
+	data := make(map[string]string, len(secret.Data))
+	for key, v := range secret.Data {
+		data[key] = string(v)
+	}
+	value, err := json.Marshal(data)
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
+
+func (k *kubernetesSecretsClient) CheckSecretExists(ctx context.Context, name string) bool {
+	_, exists := k.GetSecretValue(ctx, name)
+	return exists
+}
+
+// newKubernetesSecretsLookup creates a lookup from a URN of the form
+// k8s://namespace/prefix. The in-cluster config is used when running within a
+// pod, and otherwise the default kubeconfig, where the context can be selected
+// with the context parameter.
+func newKubernetesSecretsLookup(_ context.Context, logger *slog.Logger, u *url.URL) (LookupFn, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("kubernetes secrets URN %v must specify a namespace", u.Redacted())
+	}
+
+	kubeContext := u.Query().Get("context")
+
+	var cfg *rest.Config
+	if kubeContext == "" {
+		cfg, _ = rest.InClusterConfig()
+	}
+	if cfg == nil {
+		var err error
+		if cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+		).ClientConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+		}
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &kubernetesSecretsClient{
+		logger:  logger,
+		secrets: client.CoreV1().Secrets(u.Host),
+	}
+	return lookupFn(secrets.NewSecretProvider, k, strings.TrimPrefix(u.Path, "/"), u.Query().Get(trimPrefixParam))
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/redpanda-data/common-go/secrets"
+)
+
+func TestKubernetesSecretsLookup(t *testing.T) {
+	client := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "connect-db", Namespace: "prod"},
+		Data: map[string][]byte{
+			"password": []byte("hunter2"),
+		},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "connect-other", Namespace: "staging"},
+		Data: map[string][]byte{
+			"password": []byte("nope"),
+		},
+	})
+
+	k := &kubernetesSecretsClient{
+		logger:  slog.Default(),
+		secrets: client.CoreV1().Secrets("prod"),
+	}
+	lookup, err := lookupFn(secrets.NewSecretProvider, k, "connect-", "")
+	require.NoError(t, err)
+
+	v, ok := lookup(t.Context(), "db.password")
+	require.True(t, ok)
+	assert.Equal(t, "hunter2", v)
+
+	v, ok = lookup(t.Context(), "db")
+	require.True(t, ok)
+	assert.JSONEq(t, `{"password":"hunter2"}`, v)
+
+	_, ok = lookup(t.Context(), "db.username")
+	assert.False(t, ok)
+
+	_, ok = lookup(t.Context(), "other.password")
+	assert.False(t, ok)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/common-go/secrets"
+)
+
+// vaultSecretsClient reads secrets from a HashiCorp Vault KV version 2
+// secrets engine, returning the key/value pairs of a secret as a JSON object.
+type vaultSecretsClient struct {
+	logger    *slog.Logger
+	client    *http.Client
+	baseURL   string
+	token     string
+	namespace string
+}
+
+func (v *vaultSecretsClient) GetSecretValue(ctx context.Context, name string) (string, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL+name, nil)
+	if err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to look up secret")
+		return "", false
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to look up secret")
+		return "", false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", false
+	}
+	if res.StatusCode != http.StatusOK {
+		v.logger.With("status", res.StatusCode, "key", name).Error("Failed to look up secret")
+		return "", false
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to parse secret")
+		return "", false
+	}
+	// Deleted and destroyed versions are returned without data.
+	if body.Data.Data == nil {
+		return "", false
+	}
+
+	value, err := json.Marshal(body.Data.Data)
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
+
+func (v *vaultSecretsClient) CheckSecretExists(ctx context.Context, name string) bool {
+	_, exists := v.GetSecretValue(ctx, name)
+	return exists
+}
+
+// newVaultSecretsLookup creates a lookup from a URN of the form
+// vault://host:port/mount/prefix, where the token is read from the VAULT_TOKEN
+// environment variable.
+func newVaultSecretsLookup(_ context.Context, logger *slog.Logger, u *url.URL) (LookupFn, error) {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.New("a vault token must be provided with the VAULT_TOKEN environment variable")
+	}
+
+	mount, prefix, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if mount == "" {
+		return nil, fmt.Errorf("vault secrets URN %v must specify the mount of a KV secrets engine", u.Redacted())
+	}
+
+	scheme := "https"
+	if u.Query().Get("tls") == "false" {
+		scheme = "http"
+	}
+
+	namespace := u.Query().Get("namespace")
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	v := &vaultSecretsClient{
+		logger:    logger,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		baseURL:   scheme + "://" + u.Host + "/v1/" + mount + "/data/",
+		token:     token,
+		namespace: namespace,
+	}
+	return lookupFn(secrets.NewSecretProvider, v, prefix, u.Query().Get(trimPrefixParam))
+}
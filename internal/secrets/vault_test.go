@@ -0,0 +1,74 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultSecretsLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "footoken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/prod/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"username":"admin","password":"hunter2"},"metadata":{"version":3}}}`))
+		case "/v1/secret/data/prod/deleted":
+			_, _ = w.Write([]byte(`{"data":{"data":null,"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("VAULT_TOKEN", "footoken")
+
+	urn := "vault://" + strings.TrimPrefix(server.URL, "http://") + "/secret/prod/?tls=false&trimPrefix=VAULT_"
+	lookup, err := ParseLookupURNs(t.Context(), slog.Default(), urn)
+	require.NoError(t, err)
+
+	v, ok := lookup(t.Context(), "VAULT_db.password")
+	require.True(t, ok)
+	assert.Equal(t, "hunter2", v)
+
+	v, ok = lookup(t.Context(), "VAULT_db")
+	require.True(t, ok)
+	assert.JSONEq(t, `{"username":"admin","password":"hunter2"}`, v)
+
+	_, ok = lookup(t.Context(), "VAULT_db.nope")
+	assert.False(t, ok)
+
+	_, ok = lookup(t.Context(), "VAULT_deleted")
+	assert.False(t, ok)
+
+	_, ok = lookup(t.Context(), "VAULT_missing")
+	assert.False(t, ok)
+
+	_, ok = lookup(t.Context(), "db.password")
+	assert.False(t, ok)
+}
+
+func TestVaultSecretsLookupErrors(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	_, err := ParseLookupURNs(t.Context(), slog.Default(), "vault://localhost:8200/secret")
+	require.ErrorContains(t, err, "VAULT_TOKEN")
+
+	t.Setenv("VAULT_TOKEN", "footoken")
+	_, err = ParseLookupURNs(t.Context(), slog.Default(), "vault://localhost:8200")
+	require.ErrorContains(t, err, "mount")
+}
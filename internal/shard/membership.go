@@ -0,0 +1,144 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// membership tracks the members of a group within a cache resource.
+//
+// Caches can't list their keys, and so each member claims one of a fixed
+// number of slots with an add operation, which is atomic for caches that
+// support it, and renews the slot by setting it again with a TTL. The members
+// of the group are found by reading every slot.
+type membership struct {
+	mgr      *service.Resources
+	cache    string
+	group    string
+	identity string
+	slots    int
+	ttl      time.Duration
+
+	slot int
+}
+
+func (m *membership) slotKey(i int) string {
+	return fmt.Sprintf("%v/members/%v", m.group, i)
+}
+
+// heartbeat claims or renews the slot of this member, and returns the sorted
+// identities of all members of the group.
+func (m *membership) heartbeat(ctx context.Context) (members []string, err error) {
+	if cerr := m.mgr.AccessCache(ctx, m.cache, func(c service.Cache) {
+		holders := make([]string, m.slots)
+		for i := range holders {
+			var v []byte
+			if v, err = c.Get(ctx, m.slotKey(i)); err == nil {
+				holders[i] = string(v)
+			} else if !errors.Is(err, service.ErrKeyNotFound) {
+				return
+			}
+		}
+		err = nil
+
+		if m.slot >= 0 && holders[m.slot] == m.identity {
+			if err = c.Set(ctx, m.slotKey(m.slot), []byte(m.identity), &m.ttl); err != nil {
+				return
+			}
+		} else if m.slot = slices.Index(holders, m.identity); m.slot >= 0 {
+			if err = c.Set(ctx, m.slotKey(m.slot), []byte(m.identity), &m.ttl); err != nil {
+				return
+			}
+		} else {
+			for i, h := range holders {
+				if h != "" {
+					continue
+				}
+				aerr := c.Add(ctx, m.slotKey(i), []byte(m.identity), &m.ttl)
+				if errors.Is(aerr, service.ErrKeyAlreadyExists) {
+					continue
+				}
+				if err = aerr; err != nil {
+					return
+				}
+				m.slot, holders[i] = i, m.identity
+				break
+			}
+			if m.slot < 0 {
+				err = fmt.Errorf("all %v member slots of group %v are taken", m.slots, m.group)
+				return
+			}
+		}
+
+		for _, h := range holders {
+			if h != "" {
+				members = append(members, h)
+			}
+		}
+		slices.Sort(members)
+	}); cerr != nil {
+		return nil, cerr
+	}
+	return
+}
+
+// leave releases the slot of this member.
+func (m *membership) leave(ctx context.Context) (err error) {
+	if m.slot < 0 {
+		return nil
+	}
+	if cerr := m.mgr.AccessCache(ctx, m.cache, func(c service.Cache) {
+		var v []byte
+		if v, err = c.Get(ctx, m.slotKey(m.slot)); err != nil {
+			if errors.Is(err, service.ErrKeyNotFound) {
+				err = nil
+			}
+			return
+		}
+		if string(v) == m.identity {
+			err = c.Delete(ctx, m.slotKey(m.slot))
+		}
+	}); cerr != nil {
+		return cerr
+	}
+	m.slot = -1
+	return
+}
+
+// owner returns the member that owns a key with rendezvous hashing, such that
+// only the keys owned by a member that leaves, or the keys taken by a member
+// that joins, change owner when the members change.
+func owner(members []string, key string) string {
+	best, bestScore := "", uint64(0)
+	for _, member := range members {
+		h := xxhash.New()
+		_, _ = h.WriteString(member)
+		_, _ = h.Write([]byte{0})
+		_, _ = h.WriteString(key)
+		if score := h.Sum64(); best == "" || score > bestScore {
+			best, bestScore = member, score
+		}
+	}
+	return best
+}
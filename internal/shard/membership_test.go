@@ -0,0 +1,50 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestMembershipMaxMembers(t *testing.T) {
+	m := &membership{
+		mgr:      service.MockResources(service.MockResourcesOptAddCache("members")),
+		cache:    "members",
+		group:    "test",
+		slots:    1,
+		ttl:      time.Minute,
+		slot:     -1,
+		identity: "a",
+	}
+	members, err := m.heartbeat(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, members)
+
+	other := *m
+	other.identity, other.slot = "b", -1
+	_, err = other.heartbeat(t.Context())
+	require.ErrorContains(t, err, "member slots")
+
+	require.NoError(t, m.leave(t.Context()))
+	members, err = other.heartbeat(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, members)
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shard provides a way for the instances of a group, tracked within a
+// cache resource, to partition work amongst themselves by key, such that each
+// key is owned by a single instance.
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gFieldCache             = "cache"
+	gFieldGroup             = "group"
+	gFieldIdentity          = "identity"
+	gFieldMaxMembers        = "max_members"
+	gFieldHeartbeatInterval = "heartbeat_interval"
+	gFieldMemberTTL         = "member_ttl"
+)
+
+// GroupFields returns the common fields for configuring the group that work is
+// partitioned amongst.
+func GroupFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(gFieldCache).
+			Description("The xref:components:caches/about.adoc[`cache` resource] used to track the members of the group."),
+		service.NewStringField(gFieldGroup).
+			Description("The name of the group of instances to partition work amongst, which is used as the prefix of keys within the cache."),
+		service.NewStringField(gFieldIdentity).
+			Description("The unique identity of this instance. When empty the hostname followed by a random suffix is used, which is unique per process.").
+			Default("").
+			Advanced(),
+		service.NewIntField(gFieldMaxMembers).
+			Description("The maximum number of members of the group.").
+			Default(64).
+			Advanced(),
+		service.NewDurationField(gFieldHeartbeatInterval).
+			Description("The interval at which each instance renews its membership and observes the members of the group.").
+			Default("5s").
+			Advanced(),
+		service.NewDurationField(gFieldMemberTTL).
+			Description("The period after which an instance that stopped renewing its membership is removed from the group. Must be greater than the `heartbeat_interval`.").
+			Default("15s").
+			Advanced(),
+	}
+}
+
+// Group is the membership of this instance within a group, which it renews
+// in the background until closed.
+type Group struct {
+	log      *service.Logger
+	mMembers *service.MetricGauge
+
+	membership *membership
+	interval   time.Duration
+
+	mut           sync.Mutex
+	members       []string
+	lastHeartbeat time.Time
+	changedAt     time.Time
+	ready         chan struct{}
+	readyOnce     sync.Once
+
+	stopCtx  context.Context
+	stopFn   context.CancelFunc
+	loopDone chan struct{}
+}
+
+// NewGroupFromParsed joins the group configured by the common group fields of
+// a parsed config.
+func NewGroupFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (*Group, error) {
+	g := &Group{
+		log:      mgr.Logger(),
+		mMembers: mgr.Metrics().NewGauge("shard_members"),
+		ready:    make(chan struct{}),
+		loopDone: make(chan struct{}),
+	}
+	m := &membership{mgr: mgr, slot: -1}
+
+	var err error
+	if m.cache, err = conf.FieldString(gFieldCache); err != nil {
+		return nil, err
+	}
+	if !mgr.HasCache(m.cache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", m.cache)
+	}
+	if m.group, err = conf.FieldString(gFieldGroup); err != nil {
+		return nil, err
+	}
+	if m.identity, err = conf.FieldString(gFieldIdentity); err != nil {
+		return nil, err
+	}
+	if m.identity == "" {
+		hostname, _ := os.Hostname()
+		m.identity = hostname + "-" + xid.New().String()
+	}
+	if m.slots, err = conf.FieldInt(gFieldMaxMembers); err != nil {
+		return nil, err
+	}
+	if m.slots <= 0 {
+		return nil, errors.New("max_members must be greater than zero")
+	}
+	if g.interval, err = conf.FieldDuration(gFieldHeartbeatInterval); err != nil {
+		return nil, err
+	}
+	if m.ttl, err = conf.FieldDuration(gFieldMemberTTL); err != nil {
+		return nil, err
+	}
+	if g.interval <= 0 || m.ttl <= g.interval {
+		return nil, errors.New("the member_ttl must be greater than the heartbeat_interval, which must be greater than zero")
+	}
+	g.membership = m
+
+	g.stopCtx, g.stopFn = context.WithCancel(context.Background())
+	go g.heartbeatLoop()
+	return g, nil
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string {
+	return g.membership.group
+}
+
+func (g *Group) heartbeatLoop() {
+	defer close(g.loopDone)
+
+	for {
+		ctx, done := context.WithTimeout(g.stopCtx, g.interval)
+		members, err := g.membership.heartbeat(ctx)
+		done()
+		if g.stopCtx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			g.log.Warnf("Failed to renew membership of group %v: %v", g.membership.group, err)
+		} else {
+			now := time.Now()
+			g.mut.Lock()
+			if !slices.Equal(members, g.members) {
+				if len(members) != len(g.members) {
+					g.log.Infof("Group %v has %v members", g.membership.group, len(members))
+				}
+				g.changedAt = now
+			}
+			g.members, g.lastHeartbeat = members, now
+			g.mut.Unlock()
+			g.mMembers.Set(int64(len(members)))
+			g.readyOnce.Do(func() { close(g.ready) })
+		}
+
+		select {
+		case <-time.After(g.interval):
+		case <-g.stopCtx.Done():
+			return
+		}
+	}
+}
+
+// settlePeriod is the period after this instance observes a change to the
+// members of the group during which other instances may not have observed it
+// yet, as each instance observes the members at its own heartbeat, which can
+// take up to an interval itself.
+func (g *Group) settlePeriod() time.Duration {
+	return 2 * g.interval
+}
+
+// Members returns the sorted identities of the members of the group, or an
+// error if the membership of this instance hasn't been renewed within the
+// member TTL, in which case the ownership of keys is unknown.
+func (g *Group) Members() ([]string, error) {
+	members, _, err := g.current()
+	return members, err
+}
+
+func (g *Group) current() (members []string, settled bool, err error) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if time.Since(g.lastHeartbeat) > g.membership.ttl {
+		return nil, false, fmt.Errorf("membership of group %v hasn't been renewed for %v", g.membership.group, time.Since(g.lastHeartbeat).Round(time.Second))
+	}
+	return g.members, time.Since(g.changedAt) >= g.settlePeriod(), nil
+}
+
+// Owns returns whether a key is owned by this instance, waiting for the first
+// heartbeat of the group. The members of the group are settled once they
+// haven't changed for long enough that all instances agree on them, and while
+// they aren't a key that isn't owned by this instance may also not be owned by
+// the instance that other instances believe owns it.
+func (g *Group) Owns(ctx context.Context, key string) (owned, settled bool, err error) {
+	select {
+	case <-g.ready:
+	case <-ctx.Done():
+		return false, false, ctx.Err()
+	}
+
+	members, settled, err := g.current()
+	if err != nil {
+		return false, false, err
+	}
+	return owner(members, key) == g.membership.identity, settled, nil
+}
+
+// OwnsSettled returns whether a key is owned by this instance, waiting for the
+// members of the group to settle before reporting that a key is owned by
+// another instance.
+func (g *Group) OwnsSettled(ctx context.Context, key string) (bool, error) {
+	for {
+		owned, settled, err := g.Owns(ctx, key)
+		if err != nil || owned || settled {
+			return owned, err
+		}
+		select {
+		case <-time.After(g.interval):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// Close stops renewing the membership of this instance and leaves the group,
+// which allows the remaining members to take over the keys of this instance
+// without waiting for the membership to expire.
+func (g *Group) Close(ctx context.Context) error {
+	g.stopFn()
+	select {
+	case <-g.loopDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return g.membership.leave(ctx)
+}
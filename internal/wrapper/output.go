@@ -0,0 +1,32 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wrapper contains types shared by outputs that wrap child outputs.
+package wrapper
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// BatchWriter is the subset of a *service.OwnedOutput used by outputs that
+// write to child outputs, which allows tests to replace the children with
+// fakes.
+type BatchWriter interface {
+	WriteBatch(ctx context.Context, b service.MessageBatch) error
+	Close(ctx context.Context) error
+}
+
+var _ BatchWriter = (*service.OwnedOutput)(nil)
@@ -23,6 +23,7 @@ import (
 	_ "github.com/redpanda-data/connect/v4/public/components/gcp/enterprise"
 	_ "github.com/redpanda-data/connect/v4/public/components/google"
 	_ "github.com/redpanda-data/connect/v4/public/components/kafka/enterprise"
+	_ "github.com/redpanda-data/connect/v4/public/components/microsoft"
 	_ "github.com/redpanda-data/connect/v4/public/components/mongodb/enterprise"
 	_ "github.com/redpanda-data/connect/v4/public/components/mysql"
 	_ "github.com/redpanda-data/connect/v4/public/components/ollama"
@@ -21,28 +21,57 @@ import (
 	// Import all public sub-categories.
 	_ "github.com/redpanda-data/connect/v4/public/components/amqp09"
 	_ "github.com/redpanda-data/connect/v4/public/components/amqp1"
+	_ "github.com/redpanda-data/connect/v4/public/components/audit"
 	_ "github.com/redpanda-data/connect/v4/public/components/avro"
 	_ "github.com/redpanda-data/connect/v4/public/components/aws"
 	_ "github.com/redpanda-data/connect/v4/public/components/azure"
 	_ "github.com/redpanda-data/connect/v4/public/components/beanstalkd"
+	_ "github.com/redpanda-data/connect/v4/public/components/breaker"
 	_ "github.com/redpanda-data/connect/v4/public/components/cassandra"
+	_ "github.com/redpanda-data/connect/v4/public/components/cbor"
 	_ "github.com/redpanda-data/connect/v4/public/components/changelog"
+	_ "github.com/redpanda-data/connect/v4/public/components/clickhouse"
 	_ "github.com/redpanda-data/connect/v4/public/components/cockroachdb"
+	_ "github.com/redpanda-data/connect/v4/public/components/compression"
 	_ "github.com/redpanda-data/connect/v4/public/components/confluent"
 	_ "github.com/redpanda-data/connect/v4/public/components/couchbase"
 	_ "github.com/redpanda-data/connect/v4/public/components/crypto"
+	_ "github.com/redpanda-data/connect/v4/public/components/csv"
 	_ "github.com/redpanda-data/connect/v4/public/components/cypher"
+	_ "github.com/redpanda-data/connect/v4/public/components/datadog"
+	_ "github.com/redpanda-data/connect/v4/public/components/debezium"
+	_ "github.com/redpanda-data/connect/v4/public/components/dedupe"
+	_ "github.com/redpanda-data/connect/v4/public/components/delay"
+	_ "github.com/redpanda-data/connect/v4/public/components/deltalake"
 	_ "github.com/redpanda-data/connect/v4/public/components/dgraph"
 	_ "github.com/redpanda-data/connect/v4/public/components/discord"
+	_ "github.com/redpanda-data/connect/v4/public/components/dlq"
+	_ "github.com/redpanda-data/connect/v4/public/components/docker"
+	_ "github.com/redpanda-data/connect/v4/public/components/duckdb"
+	_ "github.com/redpanda-data/connect/v4/public/components/elasticsearch/knn"
 	_ "github.com/redpanda-data/connect/v4/public/components/elasticsearch/v8"
+	_ "github.com/redpanda-data/connect/v4/public/components/email"
+	_ "github.com/redpanda-data/connect/v4/public/components/encryption"
+	_ "github.com/redpanda-data/connect/v4/public/components/etcd"
+	_ "github.com/redpanda-data/connect/v4/public/components/ftp"
 	_ "github.com/redpanda-data/connect/v4/public/components/gcp"
 	_ "github.com/redpanda-data/connect/v4/public/components/git"
+	_ "github.com/redpanda-data/connect/v4/public/components/grpc"
 	_ "github.com/redpanda-data/connect/v4/public/components/hdfs"
+	_ "github.com/redpanda-data/connect/v4/public/components/httpbatch"
+	_ "github.com/redpanda-data/connect/v4/public/components/iceberg"
 	_ "github.com/redpanda-data/connect/v4/public/components/influxdb"
 	_ "github.com/redpanda-data/connect/v4/public/components/io"
+	_ "github.com/redpanda-data/connect/v4/public/components/ion"
 	_ "github.com/redpanda-data/connect/v4/public/components/jaeger"
 	_ "github.com/redpanda-data/connect/v4/public/components/javascript"
+	_ "github.com/redpanda-data/connect/v4/public/components/join"
 	_ "github.com/redpanda-data/connect/v4/public/components/kafka"
+	_ "github.com/redpanda-data/connect/v4/public/components/kubernetes"
+	_ "github.com/redpanda-data/connect/v4/public/components/latency"
+	_ "github.com/redpanda-data/connect/v4/public/components/leader"
+	_ "github.com/redpanda-data/connect/v4/public/components/loki"
+	_ "github.com/redpanda-data/connect/v4/public/components/lookup"
 	_ "github.com/redpanda-data/connect/v4/public/components/maxmind"
 	_ "github.com/redpanda-data/connect/v4/public/components/memcached"
 	_ "github.com/redpanda-data/connect/v4/public/components/mongodb"
@@ -51,10 +80,14 @@ import (
 	_ "github.com/redpanda-data/connect/v4/public/components/nanomsg"
 	_ "github.com/redpanda-data/connect/v4/public/components/nats"
 	_ "github.com/redpanda-data/connect/v4/public/components/nsq"
+	_ "github.com/redpanda-data/connect/v4/public/components/oauth2token"
 	_ "github.com/redpanda-data/connect/v4/public/components/ockam"
 	_ "github.com/redpanda-data/connect/v4/public/components/opensearch"
 	_ "github.com/redpanda-data/connect/v4/public/components/otlp"
+	_ "github.com/redpanda-data/connect/v4/public/components/pagination"
 	_ "github.com/redpanda-data/connect/v4/public/components/pinecone"
+	_ "github.com/redpanda-data/connect/v4/public/components/priority"
+	_ "github.com/redpanda-data/connect/v4/public/components/profile"
 	_ "github.com/redpanda-data/connect/v4/public/components/prometheus"
 	_ "github.com/redpanda-data/connect/v4/public/components/pulsar"
 	_ "github.com/redpanda-data/connect/v4/public/components/pure"
@@ -64,14 +97,28 @@ import (
 	_ "github.com/redpanda-data/connect/v4/public/components/questdb"
 	_ "github.com/redpanda-data/connect/v4/public/components/redis"
 	_ "github.com/redpanda-data/connect/v4/public/components/redpanda"
+	_ "github.com/redpanda-data/connect/v4/public/components/salesforce"
+	_ "github.com/redpanda-data/connect/v4/public/components/sample"
 	_ "github.com/redpanda-data/connect/v4/public/components/sentry"
 	_ "github.com/redpanda-data/connect/v4/public/components/sftp"
+	_ "github.com/redpanda-data/connect/v4/public/components/shadow"
+	_ "github.com/redpanda-data/connect/v4/public/components/shard"
 	_ "github.com/redpanda-data/connect/v4/public/components/spicedb"
 	_ "github.com/redpanda-data/connect/v4/public/components/sql"
+	_ "github.com/redpanda-data/connect/v4/public/components/sse"
+	_ "github.com/redpanda-data/connect/v4/public/components/stateful"
 	_ "github.com/redpanda-data/connect/v4/public/components/statsd"
+	_ "github.com/redpanda-data/connect/v4/public/components/streamload"
+	_ "github.com/redpanda-data/connect/v4/public/components/tap"
 	_ "github.com/redpanda-data/connect/v4/public/components/text"
 	_ "github.com/redpanda-data/connect/v4/public/components/timeplus"
 	_ "github.com/redpanda-data/connect/v4/public/components/twitter"
+	_ "github.com/redpanda-data/connect/v4/public/components/useragent"
+	_ "github.com/redpanda-data/connect/v4/public/components/validate"
+	_ "github.com/redpanda-data/connect/v4/public/components/wal"
 	_ "github.com/redpanda-data/connect/v4/public/components/wasm"
+	_ "github.com/redpanda-data/connect/v4/public/components/webhook"
+	_ "github.com/redpanda-data/connect/v4/public/components/websocket"
+	_ "github.com/redpanda-data/connect/v4/public/components/weighted"
 	_ "github.com/redpanda-data/connect/v4/public/components/zeromq"
 )
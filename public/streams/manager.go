@@ -0,0 +1,396 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package streams provides a way to run a set of streams from YAML configs
+// within a Go program, and to apply changes to those configs by only
+// restarting the streams that actually changed.
+//
+// This is the programmatic equivalent of running the CLI in streams mode with
+// the `--watcher` flag.
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// Manager runs a set of streams identified by unique IDs.
+type Manager struct {
+	newBuilder   func() *service.StreamBuilder
+	drainTimeout time.Duration
+	log          *slog.Logger
+
+	mut     sync.Mutex
+	streams map[string]*runningStream
+
+	// idMuts serialise changes to each stream so that draining a stream
+	// doesn't block changes to other streams.
+	idMuts map[string]*idMutex
+}
+
+// idMutex is the lock of a stream ID, which is removed once no callers hold or
+// wait for it.
+type idMutex struct {
+	sync.Mutex
+	refs int
+}
+
+// OptFunc customises a Manager.
+type OptFunc func(m *Manager)
+
+// OptSetBuilderFunc sets the function used to create a stream builder for each
+// stream config, which allows customising the environment, schema and logger
+// of streams. The config of a stream is applied to the builder with SetYAML.
+func OptSetBuilderFunc(fn func() *service.StreamBuilder) OptFunc {
+	return func(m *Manager) {
+		m.newBuilder = fn
+	}
+}
+
+// OptSetDrainTimeout sets the maximum period to wait for a stream to drain
+// gracefully when it's stopped in order to be updated or removed. Once the
+// period elapses the stream is stopped forcefully, which may result in
+// messages that were in flight being delivered again by inputs that support
+// at-least-once delivery. The default is 30 seconds.
+func OptSetDrainTimeout(d time.Duration) OptFunc {
+	return func(m *Manager) {
+		m.drainTimeout = d
+	}
+}
+
+// OptSetLogger sets the logger used to report changes to streams.
+func OptSetLogger(l *slog.Logger) OptFunc {
+	return func(m *Manager) {
+		m.log = l
+	}
+}
+
+// NewManager creates a Manager without any streams.
+func NewManager(opts ...OptFunc) *Manager {
+	m := &Manager{
+		newBuilder:   service.NewStreamBuilder,
+		drainTimeout: 30 * time.Second,
+		log:          slog.Default(),
+		streams:      map[string]*runningStream{},
+		idMuts:       map[string]*idMutex{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+type runningStream struct {
+	conf   string
+	stream *service.Stream
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// failed returns true when the stream came to a stop by itself with an error.
+func (rs *runningStream) failed() bool {
+	select {
+	case <-rs.done:
+		return rs.err != nil && !errors.Is(rs.err, context.Canceled)
+	default:
+		return false
+	}
+}
+
+func (m *Manager) start(id, conf string, strm *service.Stream) *runningStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	rs := &runningStream{
+		conf:   conf,
+		stream: strm,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		rs.err = strm.Run(ctx)
+		close(rs.done)
+		switch {
+		case errors.Is(rs.err, context.Canceled):
+		case rs.err != nil:
+			m.log.Error("Stream exited with an error, it will be restarted by the next update", "stream", id, "error", rs.err)
+		default:
+			m.log.Info("Stream finished", "stream", id)
+		}
+	}()
+	return rs
+}
+
+// lockID locks changes to the stream with the ID, and returns a function that
+// unlocks them.
+func (m *Manager) lockID(id string) func() {
+	m.mut.Lock()
+	idMut, exists := m.idMuts[id]
+	if !exists {
+		idMut = &idMutex{}
+		m.idMuts[id] = idMut
+	}
+	idMut.refs++
+	m.mut.Unlock()
+
+	idMut.Lock()
+	return func() {
+		idMut.Unlock()
+
+		m.mut.Lock()
+		if idMut.refs--; idMut.refs == 0 {
+			delete(m.idMuts, id)
+		}
+		m.mut.Unlock()
+	}
+}
+
+func (m *Manager) stop(ctx context.Context, rs *runningStream) error {
+	// Cancelling the run context doesn't stop the stream, but once Run has
+	// returned we know that the stream was started and can therefore be
+	// stopped gracefully.
+	rs.cancel()
+	<-rs.done
+	if !errors.Is(rs.err, context.Canceled) {
+		// The stream had already come to a stop by itself.
+		return nil
+	}
+
+	ctx, done := context.WithTimeout(ctx, m.drainTimeout)
+	defer done()
+	return rs.stream.Stop(ctx)
+}
+
+// Set creates a stream from a YAML config, or updates the stream with the ID
+// if it already exists. Configs are compared after they've been parsed, and
+// so an existing stream is only restarted when the contents of its config
+// changed, in which case the existing stream is drained before the updated
+// stream starts, or when it exited with an error, in which case a new instance
+// of it is started. Returns true when a stream was created or restarted.
+//
+// The updated stream is built before the existing stream is stopped, which
+// means an invalid config results in an error and leaves the existing stream
+// running.
+func (m *Manager) Set(ctx context.Context, id, conf string) (bool, error) {
+	b := m.newBuilder()
+	if err := b.SetYAML(conf); err != nil {
+		return false, fmt.Errorf("stream %v: %w", id, err)
+	}
+	normalised, err := b.AsYAML()
+	if err != nil {
+		return false, fmt.Errorf("stream %v: %w", id, err)
+	}
+
+	defer m.lockID(id)()
+
+	m.mut.Lock()
+	existing, exists := m.streams[id]
+	m.mut.Unlock()
+	if exists && existing.conf == normalised && !existing.failed() {
+		return false, nil
+	}
+
+	strm, err := b.Build()
+	if err != nil {
+		return false, fmt.Errorf("stream %v: %w", id, err)
+	}
+	switch {
+	case exists && existing.failed():
+		m.log.Info("Restarting stream that exited with an error", "stream", id)
+		existing.cancel()
+	case exists:
+		m.log.Info("Stream config changed, draining stream before restarting it", "stream", id)
+		if err := m.stop(ctx, existing); err != nil {
+			m.log.Warn("Stream failed to drain gracefully", "stream", id, "error", err)
+		}
+	default:
+		m.log.Info("Starting stream", "stream", id)
+	}
+
+	rs := m.start(id, normalised, strm)
+	m.mut.Lock()
+	m.streams[id] = rs
+	m.mut.Unlock()
+	return true, nil
+}
+
+// Remove drains and removes the stream with the ID, if it exists.
+func (m *Manager) Remove(ctx context.Context, id string) error {
+	defer m.lockID(id)()
+
+	m.mut.Lock()
+	rs, exists := m.streams[id]
+	delete(m.streams, id)
+	m.mut.Unlock()
+	if !exists {
+		return nil
+	}
+
+	m.log.Info("Removing stream", "stream", id)
+	if err := m.stop(ctx, rs); err != nil {
+		return fmt.Errorf("stream %v: %w", id, err)
+	}
+	return nil
+}
+
+// Sync makes the set of streams match a map of stream IDs to YAML configs,
+// where streams that aren't in the map are removed, new streams are created,
+// and streams with a changed config are restarted. Streams with an unchanged
+// config continue running undisturbed.
+//
+// Errors of individual streams don't prevent the remaining streams from being
+// synced, and are returned together.
+func (m *Manager) Sync(ctx context.Context, confs map[string]string) error {
+	var errs []error
+	for _, id := range m.IDs() {
+		if _, exists := confs[id]; !exists {
+			errs = append(errs, m.Remove(ctx, id))
+		}
+	}
+
+	ids := make([]string, 0, len(confs))
+	for id := range confs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		_, err := m.Set(ctx, id, confs[id])
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// streamID returns the ID of a stream from the slash separated path of its
+// config file.
+func streamID(path string) string {
+	path = strings.TrimSuffix(strings.TrimSuffix(path, ".yaml"), ".yml")
+	return strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+}
+
+// confsByID returns the configs of a map of slash separated paths to configs
+// keyed by their stream IDs. Returns an error when multiple paths have the
+// same stream ID, such as `a/b.yaml` and `a_b.yaml`, rather than picking one of
+// the configs.
+func confsByID(files map[string]string) (map[string]string, error) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	confs := make(map[string]string, len(files))
+	pathsByID := make(map[string]string, len(files))
+	for _, p := range paths {
+		id := streamID(p)
+		if prev, exists := pathsByID[id]; exists {
+			return nil, fmt.Errorf("stream configs %v and %v both have the stream ID %v", prev, p, id)
+		}
+		pathsByID[id] = p
+		confs[id] = files[p]
+	}
+	return confs, nil
+}
+
+// SyncDir syncs the set of streams with the YAML files within a directory and
+// its sub-directories. The ID of each stream is the path of its file relative
+// to the directory without the extension, where path separators are replaced
+// with underscores, which matches the IDs of streams mode. Returns an error
+// without syncing any streams when multiple files have the same ID.
+func (m *Manager) SyncDir(ctx context.Context, dir string) error {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		confBytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(confBytes)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	confs, err := confsByID(files)
+	if err != nil {
+		return err
+	}
+	return m.Sync(ctx, confs)
+}
+
+// Watch syncs the set of streams with a directory, as with SyncDir, and then
+// again at each interval until the context is cancelled. Errors of each sync
+// are logged rather than returned, so that a stream config that's
+// temporarily invalid while being edited doesn't stop the remaining streams
+// from being watched.
+//
+// Streams continue running after Watch returns, and can be stopped with
+// StopAll.
+func (m *Manager) Watch(ctx context.Context, dir string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.SyncDir(ctx, dir); err != nil {
+			m.log.Error("Failed to sync streams with directory", "dir", dir, "error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// IDs returns the sorted IDs of all streams.
+func (m *Manager) IDs() []string {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	ids := make([]string, 0, len(m.streams))
+	for id := range m.streams {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// StopAll drains and removes all streams.
+func (m *Manager) StopAll(ctx context.Context) error {
+	var errs []error
+	for _, id := range m.IDs() {
+		errs = append(errs, m.Remove(ctx, id))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func testConf(content string) string {
+	return fmt.Sprintf(`
+input:
+  generate:
+    interval: 10ms
+    mapping: 'root = %q'
+output:
+  drop: {}
+`, content)
+}
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	m := NewManager(OptSetDrainTimeout(time.Second))
+	t.Cleanup(func() {
+		require.NoError(t, m.StopAll(context.Background()))
+	})
+	return m
+}
+
+func TestManagerSet(t *testing.T) {
+	m := newTestManager(t)
+
+	changed, err := m.Set(t.Context(), "foo", testConf("a"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	first := m.streams["foo"]
+
+	// Formatting and comments don't affect the parsed config.
+	changed, err = m.Set(t.Context(), "foo", "# A comment\n"+testConf("a"))
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Same(t, first, m.streams["foo"])
+
+	changed, err = m.Set(t.Context(), "foo", testConf("b"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotSame(t, first, m.streams["foo"])
+
+	// The previous stream was drained before the update started.
+	select {
+	case <-first.done:
+	default:
+		t.Fatal("previous stream is still running")
+	}
+
+	// An invalid config leaves the running stream undisturbed.
+	second := m.streams["foo"]
+	_, err = m.Set(t.Context(), "foo", `input: { nope: {} }`)
+	require.Error(t, err)
+	assert.Same(t, second, m.streams["foo"])
+
+	require.NoError(t, m.Remove(t.Context(), "foo"))
+	assert.Empty(t, m.IDs())
+}
+
+func TestManagerSyncDir(t *testing.T) {
+	m := newTestManager(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	writeConf := func(path, conf string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(conf), 0o644))
+	}
+
+	writeConf("foo.yaml", testConf("a"))
+	writeConf("sub/bar.yml", testConf("b"))
+	writeConf("notes.txt", "not a config")
+
+	require.NoError(t, m.SyncDir(t.Context(), dir))
+	assert.Equal(t, []string{"foo", "sub_bar"}, m.IDs())
+	foo, bar := m.streams["foo"], m.streams["sub_bar"]
+
+	// Only the changed stream is restarted.
+	writeConf("sub/bar.yml", testConf("c"))
+	require.NoError(t, m.SyncDir(t.Context(), dir))
+	assert.Same(t, foo, m.streams["foo"])
+	assert.NotSame(t, bar, m.streams["sub_bar"])
+
+	// Invalid configs don't prevent the remaining streams from being synced.
+	require.NoError(t, os.Remove(filepath.Join(dir, "foo.yaml")))
+	writeConf("baz.yaml", testConf("d"))
+	writeConf("broken.yaml", `input: { nope: {} }`)
+	require.Error(t, m.SyncDir(t.Context(), dir))
+	assert.Equal(t, []string{"baz", "sub_bar"}, m.IDs())
+}
+
+func TestManagerSyncDirDuplicateIDs(t *testing.T) {
+	for _, paths := range [][2]string{
+		{"a/b.yaml", "a_b.yaml"},
+		{"x.yaml", "x.yml"},
+	} {
+		m := newTestManager(t)
+
+		dir := t.TempDir()
+		for _, p := range paths {
+			p = filepath.Join(dir, filepath.FromSlash(p))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+			require.NoError(t, os.WriteFile(p, []byte(testConf("a")), 0o644))
+		}
+
+		err := m.SyncDir(t.Context(), dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), paths[0])
+		assert.Contains(t, err.Error(), paths[1])
+		assert.Empty(t, m.IDs())
+	}
+}
+
+func TestManagerStreamEndsByItself(t *testing.T) {
+	m := newTestManager(t)
+
+	_, err := m.Set(t.Context(), "foo", `
+input:
+  generate:
+    count: 1
+    mapping: 'root = "a"'
+output:
+  drop: {}
+`)
+	require.NoError(t, err)
+
+	select {
+	case <-m.streams["foo"].done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stream did not end")
+	}
+	require.NoError(t, m.Remove(t.Context(), "foo"))
+}
+
+func TestManagerStreamFailsByItself(t *testing.T) {
+	m := newTestManager(t)
+
+	// The cache resource is only resolved once the stream runs.
+	conf := `
+input:
+  generate:
+    interval: 10ms
+    mapping: 'root = "a"'
+pipeline:
+  processors:
+    - cache:
+        resource: nope
+        operator: get
+        key: foo
+output:
+  drop: {}
+`
+	changed, err := m.Set(t.Context(), "foo", conf)
+	require.NoError(t, err)
+	require.True(t, changed)
+	first := m.streams["foo"]
+
+	select {
+	case <-first.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stream did not end")
+	}
+	require.Error(t, first.err)
+
+	// A stream that exited with an error is restarted even though its config
+	// is unchanged.
+	changed, err = m.Set(t.Context(), "foo", conf)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotSame(t, first, m.streams["foo"])
+}
+
+func TestManagerDrainDoesNotBlockOtherStreams(t *testing.T) {
+	m := NewManager(OptSetDrainTimeout(time.Second))
+	t.Cleanup(func() {
+		_ = m.StopAll(context.Background())
+	})
+
+	// Messages are held by the sleep processor, and so the stream takes until
+	// the drain timeout to stop.
+	slowConf := func(content string) string {
+		return fmt.Sprintf(`
+input:
+  generate:
+    interval: 10ms
+    mapping: 'root = %q'
+pipeline:
+  processors:
+    - sleep:
+        duration: 1m
+output:
+  drop: {}
+`, content)
+	}
+	_, err := m.Set(t.Context(), "slow", slowConf("a"))
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	updated := make(chan error, 1)
+	go func() {
+		_, err := m.Set(t.Context(), "slow", slowConf("b"))
+		updated <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Other streams can be changed while the slow stream drains.
+	start := time.Now()
+	_, err = m.Set(t.Context(), "other", testConf("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other", "slow"}, m.IDs())
+	require.NoError(t, m.Remove(t.Context(), "other"))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	select {
+	case err := <-updated:
+		t.Fatalf("slow stream updated before it was drained: %v", err)
+	default:
+	}
+	require.NoError(t, <-updated)
+}
+
+func TestManagerRemovesIDLocks(t *testing.T) {
+	m := newTestManager(t)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("stream%v", i%2)
+			_, err := m.Set(t.Context(), id, testConf(strconv.Itoa(i)))
+			assert.NoError(t, err)
+			assert.NoError(t, m.Remove(t.Context(), id))
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, m.IDs())
+	m.mut.Lock()
+	assert.Empty(t, m.idMuts)
+	m.mut.Unlock()
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Source provides stream config files from a remote location, such as an
+// object storage prefix or a git repository, for distributing stream configs
+// to a fleet of instances.
+type Source interface {
+	// Fetch returns the files of the source keyed by their slash separated
+	// paths relative to the root of the source, along with a version that
+	// changes whenever the files change, such as a commit hash.
+	// Implementations should avoid downloading files that haven't changed
+	// since the previous fetch.
+	Fetch(ctx context.Context) (files map[string][]byte, version string, err error)
+}
+
+// sigSuffix is the suffix of files containing the signature of a config file.
+const sigSuffix = ".sig"
+
+type verifiedSource struct {
+	src  Source
+	keys []ed25519.PublicKey
+}
+
+// NewVerifiedSource wraps a Source such that each config file must be
+// accompanied by a signature file, which has the path of the config file with
+// the suffix `.sig` and contains the base64 encoded ed25519 signature of the
+// SignedMessage of the config file by one of the public keys. A fetch fails
+// entirely when any config file lacks a valid signature, and so unverified
+// configs are never applied.
+//
+// As the signed message includes the path of the config a signed config can't
+// be copied to another path in order to run it as a different stream. However,
+// the signatures carry no version, and so replacing a config and its signature
+// with an older signed revision of the same path isn't detected.
+func NewVerifiedSource(src Source, keys ...ed25519.PublicKey) Source {
+	return &verifiedSource{src: src, keys: keys}
+}
+
+// SignedMessage returns the message that is signed for a config file of a
+// verified source, which is the slash separated path of the config relative to
+// the root of the source followed by a null byte and the contents of the
+// config.
+func SignedMessage(path string, content []byte) []byte {
+	msg := make([]byte, 0, len(path)+1+len(content))
+	msg = append(msg, path...)
+	msg = append(msg, 0)
+	return append(msg, content...)
+}
+
+func (v *verifiedSource) verify(p string, content, sig []byte) bool {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil {
+		return false
+	}
+	msg := SignedMessage(p, content)
+	for _, k := range v.keys {
+		if ed25519.Verify(k, msg, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *verifiedSource) Fetch(ctx context.Context) (map[string][]byte, string, error) {
+	files, version, err := v.src.Fetch(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	verified := map[string][]byte{}
+	for p, content := range files {
+		if !isConfigPath(p) {
+			continue
+		}
+		sig, exists := files[p+sigSuffix]
+		if !exists {
+			return nil, "", fmt.Errorf("config %v has no signature", p)
+		}
+		if !v.verify(p, content, sig) {
+			return nil, "", fmt.Errorf("config %v has an invalid signature", p)
+		}
+		verified[p] = content
+	}
+	return verified, version, nil
+}
+
+func isConfigPath(p string) bool {
+	ext := path.Ext(p)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// versionOf returns a version derived from a set of keys and their
+// fingerprints, such as object keys and their ETags.
+func versionOf(fingerprints map[string]string) string {
+	keys := make([]string, 0, len(fingerprints))
+	for k := range fingerprints {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "%v\x00%v\x00", k, fingerprints[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalisePrefix returns an object key prefix that ends with a slash, such
+// that the prefix `foo` doesn't match keys within `foobar/`.
+func normalisePrefix(prefix string) string {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// syncFiles syncs the set of streams with the config files of a source.
+func (m *Manager) syncFiles(ctx context.Context, files map[string][]byte) error {
+	confFiles := map[string]string{}
+	for p, content := range files {
+		if isConfigPath(p) {
+			confFiles[p] = string(content)
+		}
+	}
+	confs, err := confsByID(confFiles)
+	if err != nil {
+		return err
+	}
+	return m.Sync(ctx, confs)
+}
+
+// SyncSource fetches the files of a Source and syncs the set of streams with
+// its config files, as with SyncDir. Returns the version of the fetched files.
+func (m *Manager) SyncSource(ctx context.Context, src Source) (string, error) {
+	files, version, err := src.Fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch stream configs: %w", err)
+	}
+	return version, m.syncFiles(ctx, files)
+}
+
+// WatchSource syncs the set of streams with a Source, as with SyncSource, and
+// then polls the source at each interval until the context is cancelled.
+// Streams are only synced when the version of the source changes, or when the
+// previous sync failed. Errors are logged rather than returned, so that an
+// unavailable source leaves the running streams undisturbed.
+func (m *Manager) WatchSource(ctx context.Context, src Source, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastVersion string
+	for {
+		files, version, err := src.Fetch(ctx)
+		if err != nil {
+			m.log.Error("Failed to fetch stream configs", "error", err)
+		} else if version != lastVersion {
+			if err := m.syncFiles(ctx, files); err != nil {
+				m.log.Error("Failed to sync streams with source", "version", version, "error", err)
+			} else {
+				m.log.Info("Synced streams with source", "version", version)
+				lastVersion = version
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
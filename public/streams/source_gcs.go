@@ -0,0 +1,96 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+type gcsSource struct {
+	bucket *storage.BucketHandle
+	prefix string
+
+	cache map[string]cachedObject
+}
+
+// NewGCSSource creates a Source of the objects within a prefix of a Google
+// Cloud Storage bucket. Changes are detected by the generations of objects,
+// and only objects with a changed generation are downloaded.
+func NewGCSSource(client *storage.Client, bucket, prefix string) Source {
+	return &gcsSource{
+		bucket: client.Bucket(bucket),
+		prefix: normalisePrefix(prefix),
+		cache:  map[string]cachedObject{},
+	}
+}
+
+func (g *gcsSource) Fetch(ctx context.Context) (map[string][]byte, string, error) {
+	generations := map[string]int64{}
+	fingerprints := map[string]string{}
+	it := g.bucket.Objects(ctx, &storage.Query{Prefix: g.prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list objects: %w", err)
+		}
+		if strings.HasSuffix(attrs.Name, "/") {
+			continue
+		}
+		generations[attrs.Name] = attrs.Generation
+		fingerprints[attrs.Name] = strconv.FormatInt(attrs.Generation, 10)
+	}
+
+	files := make(map[string][]byte, len(generations))
+	cache := make(map[string]cachedObject, len(generations))
+	for name, generation := range generations {
+		cached, exists := g.cache[name]
+		if !exists || cached.fingerprint != fingerprints[name] {
+			content, err := g.download(ctx, name, generation)
+			if err != nil {
+				return nil, "", err
+			}
+			cached = cachedObject{fingerprint: fingerprints[name], content: content}
+		}
+		cache[name] = cached
+		files[strings.TrimPrefix(name, g.prefix)] = cached.content
+	}
+	g.cache = cache
+	return files, versionOf(fingerprints), nil
+}
+
+func (g *gcsSource) download(ctx context.Context, name string, generation int64) ([]byte, error) {
+	r, err := g.bucket.Object(name).Generation(generation).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %v: %w", name, err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %v: %w", name, err)
+	}
+	return content, nil
+}
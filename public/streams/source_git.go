@@ -0,0 +1,152 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// GitSourceConfig describes a git repository to source stream configs from.
+type GitSourceConfig struct {
+	// URL is the URL of the repository.
+	URL string
+
+	// Branch is the branch to track, which defaults to `main`.
+	Branch string
+
+	// Dir is an optional directory within the repository that contains the
+	// stream configs.
+	Dir string
+
+	// Auth is an optional method of authenticating with the repository.
+	Auth transport.AuthMethod
+}
+
+type gitSource struct {
+	conf GitSourceConfig
+	ref  plumbing.ReferenceName
+
+	repo       *git.Repository
+	lastCommit plumbing.Hash
+	lastFiles  map[string][]byte
+}
+
+// NewGitSource creates a Source of the files within a branch of a git
+// repository. The head of the branch is checked on each poll and, when it has
+// changed, a shallow clone of the branch is made in memory. Changes are
+// detected by the commit hash at the head of the branch, which is the version
+// of the source.
+func NewGitSource(conf GitSourceConfig) Source {
+	if conf.Branch == "" {
+		conf.Branch = "main"
+	}
+	return &gitSource{
+		conf: conf,
+		ref:  plumbing.NewRemoteReferenceName("origin", conf.Branch),
+	}
+}
+
+// update resolves the head of the branch and, when it has moved, replaces the
+// repository with a fresh shallow clone of it. Objects of earlier commits are
+// therefore never retained, and the repository is only downloaded when the
+// branch changes.
+func (g *gitSource) update(ctx context.Context) error {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{g.conf.URL},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: g.conf.Auth})
+	if err != nil {
+		return fmt.Errorf("failed to list references of repository: %w", err)
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(g.conf.Branch)
+	i := slices.IndexFunc(refs, func(r *plumbing.Reference) bool {
+		return r.Name() == branchRef
+	})
+	if i == -1 {
+		return fmt.Errorf("branch %v was not found", g.conf.Branch)
+	}
+	if g.repo != nil && refs[i].Hash() == g.lastCommit {
+		return nil
+	}
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           g.conf.URL,
+		Auth:          g.conf.Auth,
+		ReferenceName: branchRef,
+		SingleBranch:  true,
+		NoCheckout:    true,
+		Depth:         1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+	g.repo = repo
+	return nil
+}
+
+func (g *gitSource) Fetch(ctx context.Context) (map[string][]byte, string, error) {
+	if err := g.update(ctx); err != nil {
+		return nil, "", err
+	}
+
+	ref, err := g.repo.Reference(g.ref, true)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve branch %v: %w", g.conf.Branch, err)
+	}
+	if ref.Hash() == g.lastCommit {
+		return g.lastFiles, ref.Hash().String(), nil
+	}
+
+	commit, err := g.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %v: %w", ref.Hash(), err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %v: %w", ref.Hash(), err)
+	}
+
+	dir := normalisePrefix(g.conf.Dir)
+	files := map[string][]byte{}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !strings.HasPrefix(f.Name, dir) {
+			return nil
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read file %v: %w", f.Name, err)
+		}
+		files[strings.TrimPrefix(f.Name, dir)] = []byte(content)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	g.lastCommit, g.lastFiles = ref.Hash(), files
+	return files, ref.Hash().String(), nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3API is the subset of the S3 client used by an S3 source, which is
+// satisfied by *s3.Client.
+type S3API interface {
+	s3.ListObjectsV2APIClient
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+type cachedObject struct {
+	fingerprint string
+	content     []byte
+}
+
+type s3Source struct {
+	client S3API
+	bucket string
+	prefix string
+
+	cache map[string]cachedObject
+}
+
+// NewS3Source creates a Source of the objects within a prefix of an S3
+// bucket. Changes are detected by the ETags of objects, and only objects with
+// a changed ETag are downloaded.
+func NewS3Source(client S3API, bucket, prefix string) Source {
+	return &s3Source{
+		client: client,
+		bucket: bucket,
+		prefix: normalisePrefix(prefix),
+		cache:  map[string]cachedObject{},
+	}
+}
+
+func (s *s3Source) Fetch(ctx context.Context) (map[string][]byte, string, error) {
+	etags := map[string]string{}
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			etags[key] = aws.ToString(obj.ETag)
+		}
+	}
+
+	files := make(map[string][]byte, len(etags))
+	cache := make(map[string]cachedObject, len(etags))
+	for key, etag := range etags {
+		cached, exists := s.cache[key]
+		if !exists || cached.fingerprint != etag {
+			content, err := s.download(ctx, key)
+			if err != nil {
+				return nil, "", err
+			}
+			cached = cachedObject{fingerprint: etag, content: content}
+		}
+		cache[key] = cached
+		files[strings.TrimPrefix(key, s.prefix)] = cached.content
+	}
+	s.cache = cache
+	return files, versionOf(etags), nil
+}
+
+func (s *s3Source) download(ctx context.Context, key string) ([]byte, error) {
+	obj, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %v: %w", key, err)
+	}
+	defer obj.Body.Close()
+
+	content, err := io.ReadAll(obj.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %v: %w", key, err)
+	}
+	return content, nil
+}
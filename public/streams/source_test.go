@@ -0,0 +1,211 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObject struct {
+	etag    string
+	content string
+}
+
+type fakeS3 struct {
+	objects   map[string]fakeObject
+	downloads []string
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, in *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	out := &s3.ListObjectsV2Output{}
+	for k, o := range f.objects {
+		if strings.HasPrefix(k, aws.ToString(in.Prefix)) {
+			out.Contents = append(out.Contents, types.Object{Key: aws.String(k), ETag: aws.String(o.etag)})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	key := aws.ToString(in.Key)
+	f.downloads = append(f.downloads, key)
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(f.objects[key].content))}, nil
+}
+
+func TestS3Source(t *testing.T) {
+	client := &fakeS3{objects: map[string]fakeObject{
+		"streams/foo.yaml":     {etag: "1", content: "foo"},
+		"streams/sub/bar.yaml": {etag: "1", content: "bar"},
+		"streamsother/a.yaml":  {etag: "1", content: "nope"},
+	}}
+	src := NewS3Source(client, "bucket", "streams")
+
+	files, v1, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"foo.yaml":     []byte("foo"),
+		"sub/bar.yaml": []byte("bar"),
+	}, files)
+	assert.ElementsMatch(t, []string{"streams/foo.yaml", "streams/sub/bar.yaml"}, client.downloads)
+
+	// Unchanged objects aren't downloaded again.
+	client.downloads = nil
+	_, v2, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, v1, v2)
+	assert.Empty(t, client.downloads)
+
+	client.objects["streams/foo.yaml"] = fakeObject{etag: "2", content: "foo2"}
+	files, v3, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, v1, v3)
+	assert.Equal(t, []byte("foo2"), files["foo.yaml"])
+	assert.Equal(t, []string{"streams/foo.yaml"}, client.downloads)
+}
+
+type staticSource struct {
+	files   map[string][]byte
+	version string
+}
+
+func (s *staticSource) Fetch(context.Context) (map[string][]byte, string, error) {
+	return s.files, s.version, nil
+}
+
+func TestVerifiedSource(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	sign := func(p, content string) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignedMessage(p, []byte(content)))) + "\n")
+	}
+
+	src := &staticSource{files: map[string][]byte{
+		"foo.yaml":     []byte("foo"),
+		"foo.yaml.sig": sign("foo.yaml", "foo"),
+		"README.md":    []byte("not a config"),
+	}}
+
+	files, _, err := NewVerifiedSource(src, otherPub, pub).Fetch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo.yaml": []byte("foo")}, files)
+
+	_, _, err = NewVerifiedSource(src, otherPub).Fetch(t.Context())
+	require.ErrorContains(t, err, "invalid signature")
+
+	// A signed config copied to another path isn't accepted.
+	moved := &staticSource{files: map[string][]byte{
+		"bar.yaml":     []byte("foo"),
+		"bar.yaml.sig": sign("foo.yaml", "foo"),
+	}}
+	_, _, err = NewVerifiedSource(moved, pub).Fetch(t.Context())
+	require.ErrorContains(t, err, "config bar.yaml has an invalid signature")
+
+	src.files["foo.yaml"] = []byte("tampered")
+	_, _, err = NewVerifiedSource(src, pub).Fetch(t.Context())
+	require.ErrorContains(t, err, "invalid signature")
+
+	src.files["bar.yml"] = []byte("bar")
+	delete(src.files, "foo.yaml")
+	_, _, err = NewVerifiedSource(src, pub).Fetch(t.Context())
+	require.ErrorContains(t, err, "no signature")
+}
+
+func TestGitSource(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(files map[string]string) {
+		for p, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, p), []byte(content), 0o644))
+			_, err := wt.Add(p)
+			require.NoError(t, err)
+		}
+		_, err := wt.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit(map[string]string{
+		"streams/foo.yaml": "foo",
+		"other/bar.yaml":   "bar",
+	})
+
+	src := NewGitSource(GitSourceConfig{URL: dir, Branch: "master", Dir: "streams"})
+	files, v1, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo.yaml": []byte("foo")}, files)
+
+	_, v2, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, v1, v2)
+
+	commit(map[string]string{"streams/foo.yaml": "foo2"})
+	files, v3, err := src.Fetch(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, v1, v3)
+	assert.Equal(t, map[string][]byte{"foo.yaml": []byte("foo2")}, files)
+
+	// Only the head commit is retained.
+	_, err = src.(*gitSource).repo.CommitObject(plumbing.NewHash(v1))
+	require.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+}
+
+func TestManagerWatchSource(t *testing.T) {
+	m := newTestManager(t)
+
+	src := &staticSource{version: "1", files: map[string][]byte{
+		"foo.yaml": []byte(testConf("a")),
+		"bar.yaml": []byte(testConf("b")),
+	}}
+
+	ctx, done := context.WithCancel(t.Context())
+	watchDone := make(chan error)
+	go func() {
+		watchDone <- m.WatchSource(ctx, src, 10*time.Millisecond)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return slices.Equal(m.IDs(), []string{"bar", "foo"})
+	}, 5*time.Second, 10*time.Millisecond)
+
+	done()
+	require.ErrorIs(t, <-watchDone, context.Canceled)
+}